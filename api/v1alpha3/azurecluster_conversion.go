@@ -72,9 +72,25 @@ func (src *AzureCluster) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.NetworkSpec.NodeOutboundLB = restored.Spec.NetworkSpec.NodeOutboundLB
 
 	dst.Spec.NetworkSpec.PrivateDNSZoneName = restored.Spec.NetworkSpec.PrivateDNSZoneName
+	dst.Spec.NetworkSpec.OutboundType = restored.Spec.NetworkSpec.OutboundType
+	dst.Spec.CloudProviderIdentity = restored.Spec.CloudProviderIdentity
+	dst.Spec.Addons = restored.Spec.Addons
+	dst.Spec.BootstrapDataStorage = restored.Spec.BootstrapDataStorage
+	dst.Spec.Proxy = restored.Spec.Proxy
+	dst.Spec.BootstrapExtension = restored.Spec.BootstrapExtension
+	dst.Spec.RegistryMirrors = restored.Spec.RegistryMirrors
+	dst.Spec.ExtendedLocation = restored.Spec.ExtendedLocation
+	dst.Spec.Paused = restored.Spec.Paused
 
 	dst.Spec.NetworkSpec.APIServerLB.FrontendIPsCount = restored.Spec.NetworkSpec.APIServerLB.FrontendIPsCount
 	dst.Spec.NetworkSpec.APIServerLB.IdleTimeoutInMinutes = restored.Spec.NetworkSpec.APIServerLB.IdleTimeoutInMinutes
+	dst.Spec.NetworkSpec.APIServerLB.BackendPoolType = restored.Spec.NetworkSpec.APIServerLB.BackendPoolType
+	dst.Spec.NetworkSpec.APIServerLB.EnableTCPReset = restored.Spec.NetworkSpec.APIServerLB.EnableTCPReset
+	dst.Spec.NetworkSpec.APIServerLB.LoadDistribution = restored.Spec.NetworkSpec.APIServerLB.LoadDistribution
+	dst.Spec.NetworkSpec.APIServerLB.External = restored.Spec.NetworkSpec.APIServerLB.External
+	dst.Spec.NetworkSpec.APIServerLB.FrontendPort = restored.Spec.NetworkSpec.APIServerLB.FrontendPort
+	dst.Spec.NetworkSpec.APIServerLB.AliasFrontends = restored.Spec.NetworkSpec.APIServerLB.AliasFrontends
+	restoreFrontendIPs(dst.Spec.NetworkSpec.APIServerLB.FrontendIPs, restored.Spec.NetworkSpec.APIServerLB.FrontendIPs)
 	dst.Spec.CloudProviderConfigOverrides = restored.Spec.CloudProviderConfigOverrides
 	dst.Spec.BastionSpec = restored.Spec.BastionSpec
 
@@ -99,6 +115,20 @@ func (src *AzureCluster) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
+	dst.Status.LastARMTemplateExportRequest = restored.Status.LastARMTemplateExportRequest
+	dst.Status.LastRoleDefinitionExportRequest = restored.Status.LastRoleDefinitionExportRequest
+	dst.Status.SSHPublicKey = restored.Status.SSHPublicKey
+	dst.Status.Resources = restored.Status.Resources
+	dst.Spec.ReconciliationBackend = restored.Spec.ReconciliationBackend
+	dst.Spec.Monitoring = restored.Spec.Monitoring
+	dst.Spec.SecurityProfile = restored.Spec.SecurityProfile
+	dst.Spec.AttachedACRs = restored.Spec.AttachedACRs
+	dst.Spec.CrossSubscriptionAccess = restored.Spec.CrossSubscriptionAccess
+	dst.Spec.ControlPlaneZoneSpreading = restored.Spec.ControlPlaneZoneSpreading
+	dst.Spec.NetworkSpec.GlobalAPIServerLB = restored.Spec.NetworkSpec.GlobalAPIServerLB
+	dst.Spec.NetworkSpec.DNSResolver = restored.Spec.NetworkSpec.DNSResolver
+	dst.Spec.Budget = restored.Spec.Budget
+	dst.Spec.PolicyExemptions = restored.Spec.PolicyExemptions
 
 	// Restore list of virtual network peerings
 	dst.Spec.NetworkSpec.Vnet.Peerings = restored.Spec.NetworkSpec.Vnet.Peerings
@@ -407,3 +437,22 @@ func Convert_v1beta1_FrontendIP_To_v1alpha3_FrontendIP(in *infrav1beta1.Frontend
 
 	return nil
 }
+
+// restoreFrontendIPs restores the fields of frontend IPs which don't exist in this version.
+func restoreFrontendIPs(dst, restored []infrav1beta1.FrontendIP) {
+	if len(dst) != len(restored) {
+		return
+	}
+	for i := range dst {
+		dst[i].GatewayLoadBalancerID = restored[i].GatewayLoadBalancerID
+		if dst[i].PublicIP != nil && restored[i].PublicIP != nil {
+			dst[i].PublicIP.Availability = restored[i].PublicIP.Availability
+			dst[i].PublicIP.Zone = restored[i].PublicIP.Zone
+		}
+	}
+}
+
+// Convert_v1beta1_PublicIPSpec_To_v1alpha3_PublicIPSpec is an autogenerated conversion function.
+func Convert_v1beta1_PublicIPSpec_To_v1alpha3_PublicIPSpec(in *infrav1beta1.PublicIPSpec, out *PublicIPSpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_PublicIPSpec_To_v1alpha3_PublicIPSpec(in, out, s)
+}
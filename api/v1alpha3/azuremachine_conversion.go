@@ -63,6 +63,32 @@ func (src *AzureMachine) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.AdditionalCapabilities = restored.Spec.AdditionalCapabilities
 	}
 
+	dst.Status.Image = restored.Status.Image
+	dst.Spec.OSDisk.Distro = restored.Spec.OSDisk.Distro
+	dst.Spec.UserData = restored.Spec.UserData
+	dst.Spec.DesiredPowerState = restored.Spec.DesiredPowerState
+	dst.Spec.MultiInstanceGPU = restored.Spec.MultiInstanceGPU
+	dst.Spec.LocalStorage = restored.Spec.LocalStorage
+	dst.Spec.FileStorage = restored.Spec.FileStorage
+	dst.Spec.CACertificates = restored.Spec.CACertificates
+	dst.Spec.OSDisk.WriteAcceleratorEnabled = restored.Spec.OSDisk.WriteAcceleratorEnabled
+	dst.Spec.OSDisk.SourceSnapshotID = restored.Spec.OSDisk.SourceSnapshotID
+	dst.Spec.DiskSnapshots = restored.Spec.DiskSnapshots
+	dst.Spec.BackupProtection = restored.Spec.BackupProtection
+	dst.Spec.DiskControllerType = restored.Spec.DiskControllerType
+	dst.Spec.EtcdDataDisk = restored.Spec.EtcdDataDisk
+	dst.Spec.BackendPoolDrainTimeout = restored.Spec.BackendPoolDrainTimeout
+	dst.Spec.TombstonePolicy = restored.Spec.TombstonePolicy
+	dst.Spec.DerivedResources = restored.Spec.DerivedResources
+	dst.Spec.AdditionalSSHPublicKeys = restored.Spec.AdditionalSSHPublicKeys
+	restoreDataDisks(dst.Spec.DataDisks, restored.Spec.DataDisks)
+	dst.Status.PowerState = restored.Status.PowerState
+	dst.Status.Placement = restored.Status.Placement
+	dst.Status.LastDiskSnapshotTime = restored.Status.LastDiskSnapshotTime
+	dst.Status.LastDiskSnapshotRequest = restored.Status.LastDiskSnapshotRequest
+	dst.Status.LastARMTemplateExportRequest = restored.Status.LastARMTemplateExportRequest
+	dst.Status.Resources = restored.Status.Resources
+
 	dst.Spec.SubnetName = restored.Spec.SubnetName
 
 	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
@@ -177,3 +203,20 @@ func Convert_v1alpha3_AzureMarketplaceImage_To_v1beta1_AzureMarketplaceImage(in
 func Convert_v1beta1_Image_To_v1alpha3_Image(in *v1beta1.Image, out *Image, s apiconversion.Scope) error {
 	return autoConvert_v1beta1_Image_To_v1alpha3_Image(in, out, s)
 }
+
+// Convert_v1beta1_DataDisk_To_v1alpha3_DataDisk converts from the Hub version (v1beta1) of the DataDisk to this version.
+func Convert_v1beta1_DataDisk_To_v1alpha3_DataDisk(in *v1beta1.DataDisk, out *DataDisk, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_DataDisk_To_v1alpha3_DataDisk(in, out, s)
+}
+
+// restoreDataDisks restores the fields of data disks which don't exist in this version.
+func restoreDataDisks(dst, restored []v1beta1.DataDisk) {
+	if len(dst) != len(restored) {
+		return
+	}
+	for i := range dst {
+		dst[i].WriteAcceleratorEnabled = restored[i].WriteAcceleratorEnabled
+		dst[i].ExistingDiskID = restored[i].ExistingDiskID
+		dst[i].DeletePolicy = restored[i].DeletePolicy
+	}
+}
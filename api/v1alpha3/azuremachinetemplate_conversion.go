@@ -63,6 +63,24 @@ func (src *AzureMachineTemplate) ConvertTo(dstRaw conversion.Hub) error {
 
 	dst.Spec.Template.Spec.SubnetName = restored.Spec.Template.Spec.SubnetName
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
+	dst.Spec.Template.Spec.OSDisk.Distro = restored.Spec.Template.Spec.OSDisk.Distro
+	dst.Spec.Template.Spec.UserData = restored.Spec.Template.Spec.UserData
+	dst.Spec.Template.Spec.DesiredPowerState = restored.Spec.Template.Spec.DesiredPowerState
+	dst.Spec.Template.Spec.MultiInstanceGPU = restored.Spec.Template.Spec.MultiInstanceGPU
+	dst.Spec.Template.Spec.LocalStorage = restored.Spec.Template.Spec.LocalStorage
+	dst.Spec.Template.Spec.FileStorage = restored.Spec.Template.Spec.FileStorage
+	dst.Spec.Template.Spec.CACertificates = restored.Spec.Template.Spec.CACertificates
+	dst.Spec.Template.Spec.OSDisk.WriteAcceleratorEnabled = restored.Spec.Template.Spec.OSDisk.WriteAcceleratorEnabled
+	dst.Spec.Template.Spec.OSDisk.SourceSnapshotID = restored.Spec.Template.Spec.OSDisk.SourceSnapshotID
+	dst.Spec.Template.Spec.DiskSnapshots = restored.Spec.Template.Spec.DiskSnapshots
+	dst.Spec.Template.Spec.BackupProtection = restored.Spec.Template.Spec.BackupProtection
+	dst.Spec.Template.Spec.DiskControllerType = restored.Spec.Template.Spec.DiskControllerType
+	dst.Spec.Template.Spec.EtcdDataDisk = restored.Spec.Template.Spec.EtcdDataDisk
+	dst.Spec.Template.Spec.BackendPoolDrainTimeout = restored.Spec.Template.Spec.BackendPoolDrainTimeout
+	dst.Spec.Template.Spec.TombstonePolicy = restored.Spec.Template.Spec.TombstonePolicy
+	dst.Spec.Template.Spec.DerivedResources = restored.Spec.Template.Spec.DerivedResources
+	dst.Spec.Template.Spec.AdditionalSSHPublicKeys = restored.Spec.Template.Spec.AdditionalSSHPublicKeys
+	restoreDataDisks(dst.Spec.Template.Spec.DataDisks, restored.Spec.Template.Spec.DataDisks)
 
 	return nil
 }
@@ -176,11 +176,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.DataDisk)(nil), (*DataDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_DataDisk_To_v1alpha3_DataDisk(a.(*v1beta1.DataDisk), b.(*DataDisk), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*DiffDiskSettings)(nil), (*v1beta1.DiffDiskSettings)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_DiffDiskSettings_To_v1beta1_DiffDiskSettings(a.(*DiffDiskSettings), b.(*v1beta1.DiffDiskSettings), scope)
 	}); err != nil {
@@ -211,11 +206,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.PublicIPSpec)(nil), (*PublicIPSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_PublicIPSpec_To_v1alpha3_PublicIPSpec(a.(*v1beta1.PublicIPSpec), b.(*PublicIPSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*RouteTable)(nil), (*v1beta1.RouteTable)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_RouteTable_To_v1beta1_RouteTable(a.(*RouteTable), b.(*v1beta1.RouteTable), scope)
 	}); err != nil {
@@ -376,6 +366,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.DataDisk)(nil), (*DataDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_DataDisk_To_v1alpha3_DataDisk(a.(*v1beta1.DataDisk), b.(*DataDisk), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta1.FrontendIP)(nil), (*FrontendIP)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_FrontendIP_To_v1alpha3_FrontendIP(a.(*v1beta1.FrontendIP), b.(*FrontendIP), scope)
 	}); err != nil {
@@ -411,6 +406,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.PublicIPSpec)(nil), (*PublicIPSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_PublicIPSpec_To_v1alpha3_PublicIPSpec(a.(*v1beta1.PublicIPSpec), b.(*PublicIPSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta1.SecurityGroup)(nil), (*SecurityGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_SecurityGroup_To_v1alpha3_SecurityGroup(a.(*v1beta1.SecurityGroup), b.(*SecurityGroup), scope)
 	}); err != nil {
@@ -579,6 +579,7 @@ func autoConvert_v1beta1_AzureClusterIdentitySpec_To_v1alpha3_AzureClusterIdenti
 	out.ClientSecret = in.ClientSecret
 	out.TenantID = in.TenantID
 	// WARNING: in.AllowedNamespaces requires manual conversion: inconvertible types (*sigs.k8s.io/cluster-api-provider-azure/api/v1beta1.AllowedNamespaces vs []string)
+	// WARNING: in.DeniedNamespaces requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -689,6 +690,15 @@ func autoConvert_v1beta1_AzureClusterSpec_To_v1alpha3_AzureClusterSpec(in *v1bet
 	if err := apiv1alpha3.Convert_v1beta1_APIEndpoint_To_v1alpha3_APIEndpoint(&in.ControlPlaneEndpoint, &out.ControlPlaneEndpoint, s); err != nil {
 		return err
 	}
+	// WARNING: in.Paused requires manual conversion: does not exist in peer-type
+	// WARNING: in.ReconciliationBackend requires manual conversion: does not exist in peer-type
+	// WARNING: in.Monitoring requires manual conversion: does not exist in peer-type
+	// WARNING: in.SecurityProfile requires manual conversion: does not exist in peer-type
+	// WARNING: in.AttachedACRs requires manual conversion: does not exist in peer-type
+	// WARNING: in.CrossSubscriptionAccess requires manual conversion: does not exist in peer-type
+	// WARNING: in.ControlPlaneZoneSpreading requires manual conversion: does not exist in peer-type
+	// WARNING: in.Budget requires manual conversion: does not exist in peer-type
+	// WARNING: in.PolicyExemptions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -748,6 +758,10 @@ func autoConvert_v1beta1_AzureClusterStatus_To_v1alpha3_AzureClusterStatus(in *v
 		out.Conditions = nil
 	}
 	// WARNING: in.LongRunningOperationStates requires manual conversion: does not exist in peer-type
+	// WARNING: in.Resources requires manual conversion: does not exist in peer-type
+	// WARNING: in.LastARMTemplateExportRequest requires manual conversion: does not exist in peer-type
+	// WARNING: in.LastRoleDefinitionExportRequest requires manual conversion: does not exist in peer-type
+	// WARNING: in.SSHPublicKey requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -897,7 +911,9 @@ func autoConvert_v1beta1_AzureMachineSpec_To_v1alpha3_AzureMachineSpec(in *v1bet
 	} else {
 		out.DataDisks = nil
 	}
+	// WARNING: in.DiskControllerType requires manual conversion: does not exist in peer-type
 	out.SSHPublicKey = in.SSHPublicKey
+	// WARNING: in.AdditionalSSHPublicKeys requires manual conversion: does not exist in peer-type
 	out.AdditionalTags = *(*Tags)(unsafe.Pointer(&in.AdditionalTags))
 	// WARNING: in.AdditionalCapabilities requires manual conversion: does not exist in peer-type
 	out.AllocatePublicIP = in.AllocatePublicIP
@@ -907,6 +923,18 @@ func autoConvert_v1beta1_AzureMachineSpec_To_v1alpha3_AzureMachineSpec(in *v1bet
 	out.SecurityProfile = (*SecurityProfile)(unsafe.Pointer(in.SecurityProfile))
 	// WARNING: in.SubnetName requires manual conversion: does not exist in peer-type
 	// WARNING: in.NetworkInterfaces requires manual conversion: does not exist in peer-type
+	// WARNING: in.UserData requires manual conversion: does not exist in peer-type
+	// WARNING: in.DesiredPowerState requires manual conversion: does not exist in peer-type
+	// WARNING: in.MultiInstanceGPU requires manual conversion: does not exist in peer-type
+	// WARNING: in.LocalStorage requires manual conversion: does not exist in peer-type
+	// WARNING: in.FileStorage requires manual conversion: does not exist in peer-type
+	// WARNING: in.CACertificates requires manual conversion: does not exist in peer-type
+	// WARNING: in.DiskSnapshots requires manual conversion: does not exist in peer-type
+	// WARNING: in.BackupProtection requires manual conversion: does not exist in peer-type
+	// WARNING: in.EtcdDataDisk requires manual conversion: does not exist in peer-type
+	// WARNING: in.BackendPoolDrainTimeout requires manual conversion: does not exist in peer-type
+	// WARNING: in.TombstonePolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.DerivedResources requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -934,6 +962,12 @@ func autoConvert_v1beta1_AzureMachineStatus_To_v1alpha3_AzureMachineStatus(in *v
 	out.Ready = in.Ready
 	out.Addresses = *(*[]v1.NodeAddress)(unsafe.Pointer(&in.Addresses))
 	out.VMState = (*VMState)(unsafe.Pointer(in.VMState))
+	// WARNING: in.PowerState requires manual conversion: does not exist in peer-type
+	// WARNING: in.Placement requires manual conversion: does not exist in peer-type
+	// WARNING: in.Image requires manual conversion: does not exist in peer-type
+	// WARNING: in.LastDiskSnapshotTime requires manual conversion: does not exist in peer-type
+	// WARNING: in.LastDiskSnapshotRequest requires manual conversion: does not exist in peer-type
+	// WARNING: in.LastARMTemplateExportRequest requires manual conversion: does not exist in peer-type
 	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
 	if in.Conditions != nil {
@@ -948,6 +982,7 @@ func autoConvert_v1beta1_AzureMachineStatus_To_v1alpha3_AzureMachineStatus(in *v
 		out.Conditions = nil
 	}
 	// WARNING: in.LongRunningOperationStates requires manual conversion: does not exist in peer-type
+	// WARNING: in.Resources requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1171,14 +1206,12 @@ func autoConvert_v1beta1_DataDisk_To_v1alpha3_DataDisk(in *v1beta1.DataDisk, out
 	}
 	out.Lun = (*int32)(unsafe.Pointer(in.Lun))
 	out.CachingType = in.CachingType
+	// WARNING: in.WriteAcceleratorEnabled requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExistingDiskID requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeletePolicy requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_DataDisk_To_v1alpha3_DataDisk is an autogenerated conversion function.
-func Convert_v1beta1_DataDisk_To_v1alpha3_DataDisk(in *v1beta1.DataDisk, out *DataDisk, s conversion.Scope) error {
-	return autoConvert_v1beta1_DataDisk_To_v1alpha3_DataDisk(in, out, s)
-}
-
 func autoConvert_v1alpha3_DiffDiskSettings_To_v1beta1_DiffDiskSettings(in *DiffDiskSettings, out *v1beta1.DiffDiskSettings, s conversion.Scope) error {
 	out.Option = in.Option
 	return nil
@@ -1222,13 +1255,30 @@ func Convert_v1beta1_DiskEncryptionSetParameters_To_v1alpha3_DiskEncryptionSetPa
 func autoConvert_v1alpha3_FrontendIP_To_v1beta1_FrontendIP(in *FrontendIP, out *v1beta1.FrontendIP, s conversion.Scope) error {
 	out.Name = in.Name
 	// WARNING: in.PrivateIPAddress requires manual conversion: does not exist in peer-type
-	out.PublicIP = (*v1beta1.PublicIPSpec)(unsafe.Pointer(in.PublicIP))
+	if in.PublicIP != nil {
+		in, out := &in.PublicIP, &out.PublicIP
+		*out = new(v1beta1.PublicIPSpec)
+		if err := Convert_v1alpha3_PublicIPSpec_To_v1beta1_PublicIPSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PublicIP = nil
+	}
 	return nil
 }
 
 func autoConvert_v1beta1_FrontendIP_To_v1alpha3_FrontendIP(in *v1beta1.FrontendIP, out *FrontendIP, s conversion.Scope) error {
 	out.Name = in.Name
-	out.PublicIP = (*PublicIPSpec)(unsafe.Pointer(in.PublicIP))
+	if in.PublicIP != nil {
+		in, out := &in.PublicIP, &out.PublicIP
+		*out = new(PublicIPSpec)
+		if err := Convert_v1beta1_PublicIPSpec_To_v1alpha3_PublicIPSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PublicIP = nil
+	}
+	// WARNING: in.GatewayLoadBalancerID requires manual conversion: does not exist in peer-type
 	// WARNING: in.FrontendIPClass requires manual conversion: does not exist in peer-type
 	return nil
 }
@@ -1336,6 +1386,9 @@ func autoConvert_v1beta1_LoadBalancerSpec_To_v1alpha3_LoadBalancerSpec(in *v1bet
 		out.FrontendIPs = nil
 	}
 	// WARNING: in.FrontendIPsCount requires manual conversion: does not exist in peer-type
+	// WARNING: in.External requires manual conversion: does not exist in peer-type
+	// WARNING: in.FrontendPort requires manual conversion: does not exist in peer-type
+	// WARNING: in.AliasFrontends requires manual conversion: does not exist in peer-type
 	// WARNING: in.LoadBalancerClassSpec requires manual conversion: does not exist in peer-type
 	return nil
 }
@@ -1381,6 +1434,8 @@ func autoConvert_v1beta1_NetworkSpec_To_v1alpha3_NetworkSpec(in *v1beta1.Network
 	}
 	// WARNING: in.NodeOutboundLB requires manual conversion: does not exist in peer-type
 	// WARNING: in.ControlPlaneOutboundLB requires manual conversion: does not exist in peer-type
+	// WARNING: in.GlobalAPIServerLB requires manual conversion: does not exist in peer-type
+	// WARNING: in.DNSResolver requires manual conversion: does not exist in peer-type
 	// WARNING: in.NetworkClassSpec requires manual conversion: does not exist in peer-type
 	return nil
 }
@@ -1399,14 +1454,11 @@ func Convert_v1alpha3_PublicIPSpec_To_v1beta1_PublicIPSpec(in *PublicIPSpec, out
 func autoConvert_v1beta1_PublicIPSpec_To_v1alpha3_PublicIPSpec(in *v1beta1.PublicIPSpec, out *PublicIPSpec, s conversion.Scope) error {
 	out.Name = in.Name
 	out.DNSName = in.DNSName
+	// WARNING: in.Availability requires manual conversion: does not exist in peer-type
+	// WARNING: in.Zone requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_PublicIPSpec_To_v1alpha3_PublicIPSpec is an autogenerated conversion function.
-func Convert_v1beta1_PublicIPSpec_To_v1alpha3_PublicIPSpec(in *v1beta1.PublicIPSpec, out *PublicIPSpec, s conversion.Scope) error {
-	return autoConvert_v1beta1_PublicIPSpec_To_v1alpha3_PublicIPSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_RouteTable_To_v1beta1_RouteTable(in *RouteTable, out *v1beta1.RouteTable, s conversion.Scope) error {
 	out.ID = in.ID
 	out.Name = in.Name
@@ -42,6 +42,50 @@ func (src *AzureCluster) ConvertTo(dstRaw conversion.Hub) error {
 	// Restore list of virtual network peerings
 	dst.Spec.NetworkSpec.Vnet.Peerings = restored.Spec.NetworkSpec.Vnet.Peerings
 
+	// Restore outbound type
+	dst.Spec.NetworkSpec.OutboundType = restored.Spec.NetworkSpec.OutboundType
+
+	dst.Status.LastARMTemplateExportRequest = restored.Status.LastARMTemplateExportRequest
+	dst.Status.LastRoleDefinitionExportRequest = restored.Status.LastRoleDefinitionExportRequest
+	dst.Status.SSHPublicKey = restored.Status.SSHPublicKey
+	dst.Status.Resources = restored.Status.Resources
+	dst.Spec.ReconciliationBackend = restored.Spec.ReconciliationBackend
+	dst.Spec.Monitoring = restored.Spec.Monitoring
+	dst.Spec.SecurityProfile = restored.Spec.SecurityProfile
+	dst.Spec.AttachedACRs = restored.Spec.AttachedACRs
+	dst.Spec.CrossSubscriptionAccess = restored.Spec.CrossSubscriptionAccess
+	dst.Spec.ControlPlaneZoneSpreading = restored.Spec.ControlPlaneZoneSpreading
+	dst.Spec.NetworkSpec.GlobalAPIServerLB = restored.Spec.NetworkSpec.GlobalAPIServerLB
+	dst.Spec.NetworkSpec.DNSResolver = restored.Spec.NetworkSpec.DNSResolver
+	dst.Spec.Budget = restored.Spec.Budget
+	dst.Spec.PolicyExemptions = restored.Spec.PolicyExemptions
+
+	// Restore load balancer backend pool types and gateway load balancers
+	restoreLoadBalancer(&dst.Spec.NetworkSpec.APIServerLB, &restored.Spec.NetworkSpec.APIServerLB)
+	restoreLoadBalancer(dst.Spec.NetworkSpec.NodeOutboundLB, restored.Spec.NetworkSpec.NodeOutboundLB)
+	restoreLoadBalancer(dst.Spec.NetworkSpec.ControlPlaneOutboundLB, restored.Spec.NetworkSpec.ControlPlaneOutboundLB)
+
+	// Restore the availability zones of the NAT gateway and bastion public IPs
+	if len(dst.Spec.NetworkSpec.Subnets) == len(restored.Spec.NetworkSpec.Subnets) {
+		for i := range dst.Spec.NetworkSpec.Subnets {
+			restorePublicIP(&dst.Spec.NetworkSpec.Subnets[i].NatGateway.NatGatewayIP, &restored.Spec.NetworkSpec.Subnets[i].NatGateway.NatGatewayIP)
+		}
+	}
+	if dst.Spec.BastionSpec.AzureBastion != nil && restored.Spec.BastionSpec.AzureBastion != nil {
+		restorePublicIP(&dst.Spec.BastionSpec.AzureBastion.PublicIP, &restored.Spec.BastionSpec.AzureBastion.PublicIP)
+		restorePublicIP(&dst.Spec.BastionSpec.AzureBastion.Subnet.NatGateway.NatGatewayIP, &restored.Spec.BastionSpec.AzureBastion.Subnet.NatGateway.NatGatewayIP)
+	}
+
+	// Restore cloud provider identity
+	dst.Spec.CloudProviderIdentity = restored.Spec.CloudProviderIdentity
+	dst.Spec.Addons = restored.Spec.Addons
+	dst.Spec.BootstrapDataStorage = restored.Spec.BootstrapDataStorage
+	dst.Spec.Proxy = restored.Spec.Proxy
+	dst.Spec.BootstrapExtension = restored.Spec.BootstrapExtension
+	dst.Spec.RegistryMirrors = restored.Spec.RegistryMirrors
+	dst.Spec.ExtendedLocation = restored.Spec.ExtendedLocation
+	dst.Spec.Paused = restored.Spec.Paused
+
 	return nil
 }
 
@@ -128,6 +172,11 @@ func Convert_v1beta1_AzureClusterSpec_To_v1alpha4_AzureClusterSpec(in *infrav1be
 	return nil
 }
 
+// Convert_v1beta1_AzureClusterStatus_To_v1alpha4_AzureClusterStatus is an autogenerated conversion function.
+func Convert_v1beta1_AzureClusterStatus_To_v1alpha4_AzureClusterStatus(in *infrav1beta1.AzureClusterStatus, out *AzureClusterStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_AzureClusterStatus_To_v1alpha4_AzureClusterStatus(in, out, s)
+}
+
 // Convert_v1alpha4_FrontendIP_To_v1beta1_FrontendIP is an autogenerated conversion function.
 func Convert_v1alpha4_FrontendIP_To_v1beta1_FrontendIP(in *FrontendIP, out *infrav1beta1.FrontendIP, s apiconversion.Scope) error { //nolint
 	if err := autoConvert_v1alpha4_FrontendIP_To_v1beta1_FrontendIP(in, out, s); err != nil {
@@ -299,3 +348,37 @@ func Convert_v1beta1_NatGateway_To_v1alpha4_NatGateway(in *infrav1beta1.NatGatew
 	out.Name = in.Name
 	return nil
 }
+
+// restoreLoadBalancer restores the fields of a load balancer which don't exist in this version.
+func restoreLoadBalancer(dst, restored *infrav1beta1.LoadBalancerSpec) {
+	if dst == nil || restored == nil {
+		return
+	}
+	dst.BackendPoolType = restored.BackendPoolType
+	dst.EnableTCPReset = restored.EnableTCPReset
+	dst.LoadDistribution = restored.LoadDistribution
+	dst.External = restored.External
+	dst.FrontendPort = restored.FrontendPort
+	dst.AliasFrontends = restored.AliasFrontends
+	if len(dst.FrontendIPs) != len(restored.FrontendIPs) {
+		return
+	}
+	for i := range dst.FrontendIPs {
+		dst.FrontendIPs[i].GatewayLoadBalancerID = restored.FrontendIPs[i].GatewayLoadBalancerID
+		restorePublicIP(dst.FrontendIPs[i].PublicIP, restored.FrontendIPs[i].PublicIP)
+	}
+}
+
+// restorePublicIP restores the fields of a public IP which don't exist in this version.
+func restorePublicIP(dst, restored *infrav1beta1.PublicIPSpec) {
+	if dst == nil || restored == nil {
+		return
+	}
+	dst.Availability = restored.Availability
+	dst.Zone = restored.Zone
+}
+
+// Convert_v1beta1_PublicIPSpec_To_v1alpha4_PublicIPSpec is an autogenerated conversion function.
+func Convert_v1beta1_PublicIPSpec_To_v1alpha4_PublicIPSpec(in *infrav1beta1.PublicIPSpec, out *PublicIPSpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_PublicIPSpec_To_v1alpha4_PublicIPSpec(in, out, s)
+}
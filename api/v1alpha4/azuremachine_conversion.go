@@ -48,6 +48,32 @@ func (src *AzureMachine) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.AdditionalCapabilities = restored.Spec.AdditionalCapabilities
 	}
 
+	dst.Status.Image = restored.Status.Image
+	dst.Spec.OSDisk.Distro = restored.Spec.OSDisk.Distro
+	dst.Spec.UserData = restored.Spec.UserData
+	dst.Spec.DesiredPowerState = restored.Spec.DesiredPowerState
+	dst.Spec.MultiInstanceGPU = restored.Spec.MultiInstanceGPU
+	dst.Spec.LocalStorage = restored.Spec.LocalStorage
+	dst.Spec.FileStorage = restored.Spec.FileStorage
+	dst.Spec.CACertificates = restored.Spec.CACertificates
+	dst.Spec.OSDisk.WriteAcceleratorEnabled = restored.Spec.OSDisk.WriteAcceleratorEnabled
+	dst.Spec.OSDisk.SourceSnapshotID = restored.Spec.OSDisk.SourceSnapshotID
+	dst.Spec.DiskSnapshots = restored.Spec.DiskSnapshots
+	dst.Spec.BackupProtection = restored.Spec.BackupProtection
+	dst.Spec.DiskControllerType = restored.Spec.DiskControllerType
+	dst.Spec.EtcdDataDisk = restored.Spec.EtcdDataDisk
+	dst.Spec.BackendPoolDrainTimeout = restored.Spec.BackendPoolDrainTimeout
+	dst.Spec.TombstonePolicy = restored.Spec.TombstonePolicy
+	dst.Spec.DerivedResources = restored.Spec.DerivedResources
+	dst.Spec.AdditionalSSHPublicKeys = restored.Spec.AdditionalSSHPublicKeys
+	restoreDataDisks(dst.Spec.DataDisks, restored.Spec.DataDisks)
+	dst.Status.PowerState = restored.Status.PowerState
+	dst.Status.Placement = restored.Status.Placement
+	dst.Status.LastDiskSnapshotTime = restored.Status.LastDiskSnapshotTime
+	dst.Status.LastDiskSnapshotRequest = restored.Status.LastDiskSnapshotRequest
+	dst.Status.LastARMTemplateExportRequest = restored.Status.LastARMTemplateExportRequest
+	dst.Status.Resources = restored.Status.Resources
+
 	return nil
 }
 
@@ -82,6 +108,16 @@ func Convert_v1beta1_AzureMachineSpec_To_v1alpha4_AzureMachineSpec(in *v1beta1.A
 	return autoConvert_v1beta1_AzureMachineSpec_To_v1alpha4_AzureMachineSpec(in, out, s)
 }
 
+// Convert_v1beta1_AzureMachineStatus_To_v1alpha4_AzureMachineStatus converts from the Hub version (v1beta1) of the AzureMachineStatus to this version.
+func Convert_v1beta1_AzureMachineStatus_To_v1alpha4_AzureMachineStatus(in *v1beta1.AzureMachineStatus, out *AzureMachineStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_AzureMachineStatus_To_v1alpha4_AzureMachineStatus(in, out, s)
+}
+
+// Convert_v1beta1_OSDisk_To_v1alpha4_OSDisk converts from the Hub version (v1beta1) of the OSDisk to this version.
+func Convert_v1beta1_OSDisk_To_v1alpha4_OSDisk(in *v1beta1.OSDisk, out *OSDisk, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_OSDisk_To_v1alpha4_OSDisk(in, out, s)
+}
+
 func Convert_v1beta1_AzureMarketplaceImage_To_v1alpha4_AzureMarketplaceImage(in *v1beta1.AzureMarketplaceImage, out *AzureMarketplaceImage, s apiconversion.Scope) error {
 	out.Offer = in.ImagePlan.Offer
 	out.Publisher = in.ImagePlan.Publisher
@@ -101,3 +137,20 @@ func Convert_v1alpha4_AzureMarketplaceImage_To_v1beta1_AzureMarketplaceImage(in
 func Convert_v1beta1_Image_To_v1alpha4_Image(in *v1beta1.Image, out *Image, s apiconversion.Scope) error {
 	return autoConvert_v1beta1_Image_To_v1alpha4_Image(in, out, s)
 }
+
+// Convert_v1beta1_DataDisk_To_v1alpha4_DataDisk converts from the Hub version (v1beta1) of the DataDisk to this version.
+func Convert_v1beta1_DataDisk_To_v1alpha4_DataDisk(in *v1beta1.DataDisk, out *DataDisk, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_DataDisk_To_v1alpha4_DataDisk(in, out, s)
+}
+
+// restoreDataDisks restores the fields of data disks which don't exist in this version.
+func restoreDataDisks(dst, restored []v1beta1.DataDisk) {
+	if len(dst) != len(restored) {
+		return
+	}
+	for i := range dst {
+		dst[i].WriteAcceleratorEnabled = restored[i].WriteAcceleratorEnabled
+		dst[i].ExistingDiskID = restored[i].ExistingDiskID
+		dst[i].DeletePolicy = restored[i].DeletePolicy
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ImageWarnings returns the admission warnings for the image of a virtual machine.
+func ImageWarnings(image *Image, fldPath *field.Path) []string {
+	if image == nil || image.SharedGallery == nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s is deprecated and will be removed in a future API version, use %s instead",
+		fldPath.Child("sharedGallery"), fldPath.Child("computeGallery"))}
+}
+
+// IdentityRefWarnings returns the admission warnings for the identity used to reconcile a cluster.
+func IdentityRefWarnings(identityRef *corev1.ObjectReference, fldPath *field.Path) []string {
+	if identityRef != nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s is not set: the cluster is reconciled with the credentials of the controller manager, which is deprecated and will be removed in a future release, reference an AzureClusterIdentity instead",
+		fldPath)}
+}
+
+// SpotControlPlaneWarnings returns the admission warnings for a control plane machine using Spot VMs.
+func SpotControlPlaneWarnings(isControlPlane bool, spotVMOptions *SpotVMOptions, fldPath *field.Path) []string {
+	if !isControlPlane || spotVMOptions == nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s is set on a control plane machine: Spot VMs can be evicted at any time, which can make the control plane lose etcd quorum",
+		fldPath)}
+}
@@ -19,6 +19,7 @@ package v1beta1
 import (
 	"fmt"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"k8s.io/utils/pointer"
 )
 
@@ -33,6 +34,12 @@ const (
 	DefaultNodeSubnetCIDRPattern = "10.%d.0.0/16"
 	// DefaultAzureBastionSubnetCIDR is the default Subnet CIDR for AzureBastion.
 	DefaultAzureBastionSubnetCIDR = "10.255.255.224/27"
+	// DefaultDNSResolverSubnetName is the default name of the subnet of the outbound endpoint of a DNS private resolver.
+	DefaultDNSResolverSubnetName = "dns-resolver-outbound-subnet"
+	// DefaultDNSResolverSubnetCIDR is the default CIDR of the subnet of the outbound endpoint of a DNS private resolver.
+	DefaultDNSResolverSubnetCIDR = "10.255.255.208/28"
+	// DefaultTargetDNSServerPort is the default port of the target DNS servers of DNS forwarding rules.
+	DefaultTargetDNSServerPort = 53
 	// DefaultAzureBastionSubnetName is the default Subnet Name for AzureBastion.
 	DefaultAzureBastionSubnetName = "AzureBastionSubnet"
 	// DefaultAzureBastionSubnetRole is the default Subnet role for AzureBastion.
@@ -49,6 +56,7 @@ func (c *AzureCluster) setDefaults() {
 	c.Spec.AzureClusterClassSpec.setDefaults()
 	c.setResourceGroupDefault()
 	c.setNetworkSpecDefaults()
+	c.setPolicyExemptionsDefaults()
 }
 
 func (c *AzureCluster) setNetworkSpecDefaults() {
@@ -59,6 +67,8 @@ func (c *AzureCluster) setNetworkSpecDefaults() {
 	c.setAPIServerLBDefaults()
 	c.SetNodeOutboundLBDefaults()
 	c.SetControlPlaneOutboundLBDefaults()
+	c.setGlobalAPIServerLBDefaults()
+	c.setDNSResolverDefaults()
 }
 
 func (c *AzureCluster) setResourceGroupDefault() {
@@ -67,6 +77,14 @@ func (c *AzureCluster) setResourceGroupDefault() {
 	}
 }
 
+func (c *AzureCluster) setPolicyExemptionsDefaults() {
+	for i := range c.Spec.PolicyExemptions {
+		if c.Spec.PolicyExemptions[i].Category == "" {
+			c.Spec.PolicyExemptions[i].Category = PolicyExemptionCategoryWaiver
+		}
+	}
+}
+
 func (c *AzureCluster) setAzureEnvironmentDefault() {
 	if c.Spec.AzureEnvironment == "" {
 		c.Spec.AzureEnvironment = DefaultAzureCloud
@@ -161,8 +179,22 @@ func (c *AzureCluster) setVnetPeeringDefaults() {
 func (c *AzureCluster) setAPIServerLBDefaults() {
 	lb := &c.Spec.NetworkSpec.APIServerLB
 
+	// Clusters without public IPs can only expose the API server privately.
+	if lb.Type == "" && c.Spec.NetworkSpec.IsUserDefinedRouting() {
+		lb.Type = Internal
+	}
 	lb.LoadBalancerClassSpec.setAPIServerLBDefaults()
 
+	// External load balancers already have their frontend IPs, which are not managed by the provider.
+	if lb.External != nil {
+		if lb.Name == "" {
+			if resource, err := arm.ParseResourceID(lb.External.ID); err == nil {
+				lb.Name = resource.Name
+			}
+		}
+		return
+	}
+
 	if lb.Type == Public {
 		if lb.Name == "" {
 			lb.Name = generatePublicLBName(c.ObjectMeta.Name)
@@ -192,6 +224,55 @@ func (c *AzureCluster) setAPIServerLBDefaults() {
 			}
 		}
 	}
+
+	for i := range lb.AliasFrontends {
+		if ip := lb.AliasFrontends[i].PublicIP; ip != nil && ip.Name == "" {
+			ip.Name = generateAliasPublicIPName(c.ObjectMeta.Name, lb.AliasFrontends[i].Name)
+		}
+	}
+}
+
+func (c *AzureCluster) setGlobalAPIServerLBDefaults() {
+	lb := c.Spec.NetworkSpec.GlobalAPIServerLB
+	if lb == nil {
+		return
+	}
+
+	if lb.Name == "" {
+		lb.Name = generateGlobalLBName(c.ObjectMeta.Name)
+	}
+	if lb.Location == "" {
+		lb.Location = c.Spec.Location
+	}
+	if lb.PublicIP == nil {
+		lb.PublicIP = &PublicIPSpec{
+			Name: generateGlobalPublicIPName(c.ObjectMeta.Name),
+		}
+	}
+}
+
+func (c *AzureCluster) setDNSResolverDefaults() {
+	resolver := c.Spec.NetworkSpec.DNSResolver
+	if resolver == nil || resolver.ForwardingRulesetID != "" {
+		return
+	}
+
+	if resolver.Name == "" {
+		resolver.Name = generateDNSResolverName(c.ObjectMeta.Name)
+	}
+	if resolver.OutboundSubnet.Name == "" {
+		resolver.OutboundSubnet.Name = DefaultDNSResolverSubnetName
+	}
+	if len(resolver.OutboundSubnet.CIDRBlocks) == 0 {
+		resolver.OutboundSubnet.CIDRBlocks = []string{DefaultDNSResolverSubnetCIDR}
+	}
+	for i := range resolver.ForwardingRules {
+		for j := range resolver.ForwardingRules[i].TargetDNSServers {
+			if resolver.ForwardingRules[i].TargetDNSServers[j].Port == nil {
+				resolver.ForwardingRules[i].TargetDNSServers[j].Port = pointer.Int32(DefaultTargetDNSServerPort)
+			}
+		}
+	}
 }
 
 func (c *AzureCluster) SetNodeOutboundLBDefaults() {
@@ -250,6 +331,10 @@ func (c *AzureCluster) SetControlPlaneOutboundLBDefaults() {
 // setOutboundLBFrontendIPs sets the frontend ips for the given load balancer.
 // The name of the frontend ip is generated using generatePublicIPName function.
 func (c *AzureCluster) setOutboundLBFrontendIPs(lb *LoadBalancerSpec, generatePublicIPName func(string) string) {
+	// Keep the Gateway Load Balancers the frontend IPs are chained to and the availability zones of their public IPs,
+	// as they can't be generated.
+	previousFrontendIPs := lb.FrontendIPs
+
 	switch *lb.FrontendIPsCount {
 	case 0:
 		lb.FrontendIPs = []FrontendIP{}
@@ -273,6 +358,17 @@ func (c *AzureCluster) setOutboundLBFrontendIPs(lb *LoadBalancerSpec, generatePu
 			}
 		}
 	}
+
+	for i := range lb.FrontendIPs {
+		if i >= len(previousFrontendIPs) {
+			break
+		}
+		lb.FrontendIPs[i].GatewayLoadBalancerID = previousFrontendIPs[i].GatewayLoadBalancerID
+		if previousPublicIP := previousFrontendIPs[i].PublicIP; previousPublicIP != nil {
+			lb.FrontendIPs[i].PublicIP.Availability = previousPublicIP.Availability
+			lb.FrontendIPs[i].PublicIP.Zone = previousPublicIP.Zone
+		}
+	}
 }
 
 func (c *AzureCluster) setBastionDefaults() {
@@ -404,6 +500,26 @@ func generatePublicIPName(clusterName string) string {
 	return fmt.Sprintf("pip-%s-apiserver", clusterName)
 }
 
+// generateDNSResolverName generates the name of the DNS private resolver of the virtual network of the cluster.
+func generateDNSResolverName(clusterName string) string {
+	return fmt.Sprintf("%s-%s", clusterName, "dns-resolver")
+}
+
+// generateGlobalLBName generates the name of the cross-region API server load balancer.
+func generateGlobalLBName(clusterName string) string {
+	return fmt.Sprintf("%s-%s", clusterName, "global-lb")
+}
+
+// generateGlobalPublicIPName generates the name of the public IP of the cross-region API server load balancer.
+func generateGlobalPublicIPName(clusterName string) string {
+	return fmt.Sprintf("pip-%s-apiserver-global", clusterName)
+}
+
+// generateAliasPublicIPName generates the name of the public IP of an alias frontend of the API server load balancer.
+func generateAliasPublicIPName(clusterName, aliasName string) string {
+	return fmt.Sprintf("pip-%s-apiserver-%s", clusterName, aliasName)
+}
+
 // generateFrontendIPConfigName generates a load balancer frontend IP config name.
 func generateFrontendIPConfigName(lbName string) string {
 	return fmt.Sprintf("%s-%s", lbName, "frontEnd")
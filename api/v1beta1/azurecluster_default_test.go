@@ -21,8 +21,9 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 )
 
 func TestResourceGroupDefault(t *testing.T) {
@@ -138,7 +139,7 @@ func TestVnetDefaults(t *testing.T) {
 							},
 						},
 						NodeOutboundLB: &LoadBalancerSpec{
-							FrontendIPsCount: to.Int32Ptr(1),
+							FrontendIPsCount: to.Ptr[int32](1),
 						},
 					},
 				},
@@ -626,10 +627,10 @@ func TestSubnetDefaults(t *testing.T) {
 												Description:      "allow port 50000",
 												Protocol:         "*",
 												Priority:         2202,
-												SourcePorts:      to.StringPtr("*"),
-												DestinationPorts: to.StringPtr("*"),
-												Source:           to.StringPtr("*"),
-												Destination:      to.StringPtr("*"),
+												SourcePorts:      to.Ptr("*"),
+												DestinationPorts: to.Ptr("*"),
+												Source:           to.Ptr("*"),
+												Destination:      to.Ptr("*"),
 											},
 										},
 									},
@@ -661,10 +662,10 @@ func TestSubnetDefaults(t *testing.T) {
 												Description:      "allow port 50000",
 												Protocol:         "*",
 												Priority:         2202,
-												SourcePorts:      to.StringPtr("*"),
-												DestinationPorts: to.StringPtr("*"),
-												Source:           to.StringPtr("*"),
-												Destination:      to.StringPtr("*"),
+												SourcePorts:      to.Ptr("*"),
+												DestinationPorts: to.Ptr("*"),
+												Source:           to.Ptr("*"),
+												Destination:      to.Ptr("*"),
 												Direction:        SecurityRuleDirectionInbound,
 											},
 										},
@@ -856,7 +857,7 @@ func TestAPIServerLBDefaults(t *testing.T) {
 							LoadBalancerClassSpec: LoadBalancerClassSpec{
 								SKU:                  SKUStandard,
 								Type:                 Public,
-								IdleTimeoutInMinutes: to.Int32Ptr(DefaultOutboundRuleIdleTimeoutInMinutes),
+								IdleTimeoutInMinutes: to.Ptr[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
 							},
 						},
 					},
@@ -897,7 +898,7 @@ func TestAPIServerLBDefaults(t *testing.T) {
 							LoadBalancerClassSpec: LoadBalancerClassSpec{
 								SKU:                  SKUStandard,
 								Type:                 Internal,
-								IdleTimeoutInMinutes: to.Int32Ptr(DefaultOutboundRuleIdleTimeoutInMinutes),
+								IdleTimeoutInMinutes: to.Ptr[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
 							},
 							Name: "cluster-test-internal-lb",
 						},
@@ -905,6 +906,152 @@ func TestAPIServerLBDefaults(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "user defined routing defaults to internal lb",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NetworkClassSpec: NetworkClassSpec{
+							OutboundType: OutboundTypeUserDefinedRouting,
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NetworkClassSpec: NetworkClassSpec{
+							OutboundType: OutboundTypeUserDefinedRouting,
+						},
+						APIServerLB: LoadBalancerSpec{
+							FrontendIPs: []FrontendIP{
+								{
+									Name: "cluster-test-internal-lb-frontEnd",
+									FrontendIPClass: FrontendIPClass{
+										PrivateIPAddress: DefaultInternalLBIPAddress,
+									},
+								},
+							},
+							LoadBalancerClassSpec: LoadBalancerClassSpec{
+								SKU:                  SKUStandard,
+								Type:                 Internal,
+								IdleTimeoutInMinutes: to.Ptr[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
+							},
+							Name: "cluster-test-internal-lb",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "external lb",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{
+							External: &ExternalLoadBalancer{
+								ID:              "/subscriptions/123/resourceGroups/network-rg/providers/Microsoft.Network/loadBalancers/shared-lb",
+								BackendPoolName: "cluster-test-pool",
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{
+							Name: "shared-lb",
+							External: &ExternalLoadBalancer{
+								ID:              "/subscriptions/123/resourceGroups/network-rg/providers/Microsoft.Network/loadBalancers/shared-lb",
+								BackendPoolName: "cluster-test-pool",
+							},
+							LoadBalancerClassSpec: LoadBalancerClassSpec{
+								SKU:                  SKUStandard,
+								Type:                 Public,
+								IdleTimeoutInMinutes: to.Ptr[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "alias frontends",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{
+							FrontendPort: to.Ptr[int32](443),
+							AliasFrontends: []APIServerAliasFrontend{
+								{
+									Name: "corp",
+									Port: to.Ptr[int32](8443),
+								},
+								{
+									Name:     "partner",
+									PublicIP: &PublicIPSpec{},
+								},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{
+							Name: "cluster-test-public-lb",
+							FrontendIPs: []FrontendIP{
+								{
+									Name: "cluster-test-public-lb-frontEnd",
+									PublicIP: &PublicIPSpec{
+										Name:    "pip-cluster-test-apiserver",
+										DNSName: "",
+									},
+								},
+							},
+							FrontendPort: to.Ptr[int32](443),
+							AliasFrontends: []APIServerAliasFrontend{
+								{
+									Name: "corp",
+									Port: to.Ptr[int32](8443),
+								},
+								{
+									Name: "partner",
+									PublicIP: &PublicIPSpec{
+										Name: "pip-cluster-test-apiserver-partner",
+									},
+								},
+							},
+							LoadBalancerClassSpec: LoadBalancerClassSpec{
+								SKU:                  SKUStandard,
+								Type:                 Public,
+								IdleTimeoutInMinutes: to.Ptr[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -1077,11 +1224,11 @@ func TestNodeOutboundLBDefaults(t *testing.T) {
 									Name: "pip-cluster-test-node-outbound",
 								},
 							}},
-							FrontendIPsCount: to.Int32Ptr(1),
+							FrontendIPsCount: to.Ptr[int32](1),
 							LoadBalancerClassSpec: LoadBalancerClassSpec{
 								SKU:                  SKUStandard,
 								Type:                 Public,
-								IdleTimeoutInMinutes: to.Int32Ptr(DefaultOutboundRuleIdleTimeoutInMinutes),
+								IdleTimeoutInMinutes: to.Ptr[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
 							},
 						},
 					},
@@ -1255,11 +1402,11 @@ func TestNodeOutboundLBDefaults(t *testing.T) {
 									Name: "pip-cluster-test-node-outbound",
 								},
 							}},
-							FrontendIPsCount: to.Int32Ptr(1),
+							FrontendIPsCount: to.Ptr[int32](1),
 							LoadBalancerClassSpec: LoadBalancerClassSpec{
 								SKU:                  SKUStandard,
 								Type:                 Public,
-								IdleTimeoutInMinutes: to.Int32Ptr(DefaultOutboundRuleIdleTimeoutInMinutes),
+								IdleTimeoutInMinutes: to.Ptr[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
 							},
 						},
 					},
@@ -1364,11 +1511,11 @@ func TestNodeOutboundLBDefaults(t *testing.T) {
 									Name: "pip-cluster-test-node-outbound",
 								},
 							}},
-							FrontendIPsCount: to.Int32Ptr(1),
+							FrontendIPsCount: to.Ptr[int32](1),
 							LoadBalancerClassSpec: LoadBalancerClassSpec{
 								SKU:                  SKUStandard,
 								Type:                 Public,
-								IdleTimeoutInMinutes: to.Int32Ptr(DefaultOutboundRuleIdleTimeoutInMinutes),
+								IdleTimeoutInMinutes: to.Ptr[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
 							},
 							Name: "cluster-test",
 						},
@@ -1544,9 +1691,9 @@ func TestNodeOutboundLBDefaults(t *testing.T) {
 					NetworkSpec: NetworkSpec{
 						APIServerLB: LoadBalancerSpec{LoadBalancerClassSpec: LoadBalancerClassSpec{Type: Public}},
 						NodeOutboundLB: &LoadBalancerSpec{
-							FrontendIPsCount: to.Int32Ptr(2),
+							FrontendIPsCount: to.Ptr[int32](2),
 							LoadBalancerClassSpec: LoadBalancerClassSpec{
-								IdleTimeoutInMinutes: to.Int32Ptr(15),
+								IdleTimeoutInMinutes: to.Ptr[int32](15),
 							},
 						},
 					},
@@ -1578,11 +1725,119 @@ func TestNodeOutboundLBDefaults(t *testing.T) {
 									},
 								},
 							},
-							FrontendIPsCount: to.Int32Ptr(2), // we expect the original value to be respected here
+							FrontendIPsCount: to.Ptr[int32](2), // we expect the original value to be respected here
 							LoadBalancerClassSpec: LoadBalancerClassSpec{
 								SKU:                  SKUStandard,
 								Type:                 Public,
-								IdleTimeoutInMinutes: to.Int32Ptr(15), // we expect the original value to be respected here
+								IdleTimeoutInMinutes: to.Ptr[int32](15), // we expect the original value to be respected here
+							},
+							Name: "cluster-test",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "NodeOutboundLB frontend IPs chained to a gateway LB",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{LoadBalancerClassSpec: LoadBalancerClassSpec{Type: Public}},
+						NodeOutboundLB: &LoadBalancerSpec{
+							FrontendIPs: []FrontendIP{
+								{
+									GatewayLoadBalancerID: "/subscriptions/123/resourceGroups/nva-rg/providers/Microsoft.Network/loadBalancers/my-gwlb/frontendIPConfigurations/my-gwlb-frontend",
+								},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{
+							LoadBalancerClassSpec: LoadBalancerClassSpec{
+								Type: Public,
+							},
+						},
+						NodeOutboundLB: &LoadBalancerSpec{
+							FrontendIPs: []FrontendIP{
+								{
+									Name: "cluster-test-frontEnd",
+									PublicIP: &PublicIPSpec{
+										Name: "pip-cluster-test-node-outbound",
+									},
+									GatewayLoadBalancerID: "/subscriptions/123/resourceGroups/nva-rg/providers/Microsoft.Network/loadBalancers/my-gwlb/frontendIPConfigurations/my-gwlb-frontend", // we expect the gateway LB to be kept here
+								},
+							},
+							FrontendIPsCount: to.Ptr[int32](1),
+							LoadBalancerClassSpec: LoadBalancerClassSpec{
+								SKU:                  SKUStandard,
+								Type:                 Public,
+								IdleTimeoutInMinutes: to.Ptr[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
+							},
+							Name: "cluster-test",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "NodeOutboundLB frontend IPs with zonal public IPs",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{LoadBalancerClassSpec: LoadBalancerClassSpec{Type: Public}},
+						NodeOutboundLB: &LoadBalancerSpec{
+							FrontendIPs: []FrontendIP{
+								{
+									PublicIP: &PublicIPSpec{
+										Availability: PublicIPAvailabilityZonal,
+										Zone:         "2",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						APIServerLB: LoadBalancerSpec{
+							LoadBalancerClassSpec: LoadBalancerClassSpec{
+								Type: Public,
+							},
+						},
+						NodeOutboundLB: &LoadBalancerSpec{
+							FrontendIPs: []FrontendIP{
+								{
+									Name: "cluster-test-frontEnd",
+									PublicIP: &PublicIPSpec{
+										Name:         "pip-cluster-test-node-outbound",
+										Availability: PublicIPAvailabilityZonal, // we expect the availability to be kept here
+										Zone:         "2",
+									},
+								},
+							},
+							FrontendIPsCount: to.Ptr[int32](1),
+							LoadBalancerClassSpec: LoadBalancerClassSpec{
+								SKU:                  SKUStandard,
+								Type:                 Public,
+								IdleTimeoutInMinutes: to.Ptr[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
 							},
 							Name: "cluster-test",
 						},
@@ -1676,9 +1931,9 @@ func TestControlPlaneOutboundLBDefaults(t *testing.T) {
 					NetworkSpec: NetworkSpec{
 						APIServerLB: LoadBalancerSpec{LoadBalancerClassSpec: LoadBalancerClassSpec{Type: Internal}},
 						ControlPlaneOutboundLB: &LoadBalancerSpec{
-							FrontendIPsCount: to.Int32Ptr(2),
+							FrontendIPsCount: to.Ptr[int32](2),
 							LoadBalancerClassSpec: LoadBalancerClassSpec{
-								IdleTimeoutInMinutes: to.Int32Ptr(15),
+								IdleTimeoutInMinutes: to.Ptr[int32](15),
 							},
 						},
 					},
@@ -1711,11 +1966,11 @@ func TestControlPlaneOutboundLBDefaults(t *testing.T) {
 									},
 								},
 							},
-							FrontendIPsCount: to.Int32Ptr(2),
+							FrontendIPsCount: to.Ptr[int32](2),
 							LoadBalancerClassSpec: LoadBalancerClassSpec{
 								SKU:                  SKUStandard,
 								Type:                 Public,
-								IdleTimeoutInMinutes: to.Int32Ptr(15),
+								IdleTimeoutInMinutes: to.Ptr[int32](15),
 							},
 						},
 					},
@@ -1738,6 +1993,285 @@ func TestControlPlaneOutboundLBDefaults(t *testing.T) {
 	}
 }
 
+func TestGlobalAPIServerLBDefaults(t *testing.T) {
+	cases := []struct {
+		name    string
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		{
+			name: "no cross-region lb",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+			},
+		},
+		{
+			name: "default cross-region lb",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					AzureClusterClassSpec: AzureClusterClassSpec{
+						Location: "westus",
+					},
+					NetworkSpec: NetworkSpec{
+						GlobalAPIServerLB: &GlobalLoadBalancerSpec{},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					AzureClusterClassSpec: AzureClusterClassSpec{
+						Location: "westus",
+					},
+					NetworkSpec: NetworkSpec{
+						GlobalAPIServerLB: &GlobalLoadBalancerSpec{
+							Name:     "cluster-test-global-lb",
+							Location: "westus",
+							PublicIP: &PublicIPSpec{
+								Name: "pip-cluster-test-apiserver-global",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "custom cross-region lb",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					AzureClusterClassSpec: AzureClusterClassSpec{
+						Location: "westus",
+					},
+					NetworkSpec: NetworkSpec{
+						GlobalAPIServerLB: &GlobalLoadBalancerSpec{
+							Name:     "my-global-lb",
+							Location: "eastus2",
+							PublicIP: &PublicIPSpec{
+								Name:    "my-global-pip",
+								DNSName: "my-cluster.eastus2.cloudapp.azure.com",
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					AzureClusterClassSpec: AzureClusterClassSpec{
+						Location: "westus",
+					},
+					NetworkSpec: NetworkSpec{
+						GlobalAPIServerLB: &GlobalLoadBalancerSpec{
+							Name:     "my-global-lb",
+							Location: "eastus2",
+							PublicIP: &PublicIPSpec{
+								Name:    "my-global-pip",
+								DNSName: "my-cluster.eastus2.cloudapp.azure.com",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		tc := c
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.cluster.setGlobalAPIServerLBDefaults()
+			if !reflect.DeepEqual(tc.cluster, tc.output) {
+				expected, _ := json.MarshalIndent(tc.output, "", "\t")
+				actual, _ := json.MarshalIndent(tc.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestDNSResolverDefaults(t *testing.T) {
+	cases := []struct {
+		name    string
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		{
+			name: "no DNS private resolver",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+			},
+		},
+		{
+			name: "default DNS private resolver",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						DNSResolver: &DNSResolverSpec{
+							ForwardingRules: []DNSForwardingRule{
+								{
+									Name:             "on-prem",
+									DomainName:       "corp.example.com",
+									TargetDNSServers: []TargetDNSServer{{IPAddress: "10.1.0.4"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						DNSResolver: &DNSResolverSpec{
+							Name: "cluster-test-dns-resolver",
+							OutboundSubnet: DNSResolverSubnet{
+								Name:       DefaultDNSResolverSubnetName,
+								CIDRBlocks: []string{DefaultDNSResolverSubnetCIDR},
+							},
+							ForwardingRules: []DNSForwardingRule{
+								{
+									Name:             "on-prem",
+									DomainName:       "corp.example.com",
+									TargetDNSServers: []TargetDNSServer{{IPAddress: "10.1.0.4", Port: pointer.Int32(53)}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "existing DNS forwarding ruleset",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						DNSResolver: &DNSResolverSpec{
+							ForwardingRulesetID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/dnsForwardingRulesets/hub-ruleset",
+						},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					NetworkSpec: NetworkSpec{
+						DNSResolver: &DNSResolverSpec{
+							ForwardingRulesetID: "/subscriptions/123/resourceGroups/hub-rg/providers/Microsoft.Network/dnsForwardingRulesets/hub-ruleset",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		tc := c
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.cluster.setDNSResolverDefaults()
+			if !reflect.DeepEqual(tc.cluster, tc.output) {
+				expected, _ := json.MarshalIndent(tc.output, "", "\t")
+				actual, _ := json.MarshalIndent(tc.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestPolicyExemptionsDefaults(t *testing.T) {
+	cases := []struct {
+		name    string
+		cluster *AzureCluster
+		output  *AzureCluster
+	}{
+		{
+			name: "no policy exemptions",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+			},
+		},
+		{
+			name: "default category",
+			cluster: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					PolicyExemptions: []PolicyExemption{
+						{Name: "allow-public-ip"},
+						{Name: "allow-missing-tags", Category: PolicyExemptionCategoryMitigated},
+					},
+				},
+			},
+			output: &AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-test",
+				},
+				Spec: AzureClusterSpec{
+					PolicyExemptions: []PolicyExemption{
+						{Name: "allow-public-ip", Category: PolicyExemptionCategoryWaiver},
+						{Name: "allow-missing-tags", Category: PolicyExemptionCategoryMitigated},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		tc := c
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.cluster.setPolicyExemptionsDefaults()
+			if !reflect.DeepEqual(tc.cluster, tc.output) {
+				expected, _ := json.MarshalIndent(tc.output, "", "\t")
+				actual, _ := json.MarshalIndent(tc.cluster, "", "\t")
+				t.Errorf("Expected %s, got %s", string(expected), string(actual))
+			}
+		})
+	}
+}
+
 func TestBastionDefault(t *testing.T) {
 	cases := map[string]struct {
 		cluster *AzureCluster
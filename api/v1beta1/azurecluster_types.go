@@ -28,6 +28,48 @@ const (
 
 	// ClusterLabelNamespace indicates the namespace of the cluster.
 	ClusterLabelNamespace = "azurecluster.infrastructure.cluster.x-k8s.io/cluster-namespace"
+
+	// ReconcileTimeoutAnnotation overrides the timeout of a single reconciliation of the AzureCluster, e.g. "3h".
+	ReconcileTimeoutAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/reconcile-timeout"
+
+	// SyncPeriodAnnotation overrides the interval at which the AzureCluster is reconciled when it doesn't change,
+	// e.g. "1h", instead of the sync period of the controller.
+	SyncPeriodAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/sync-period"
+
+	// ARMTemplateExportRequestAnnotation requests an export of the desired state of the Azure resources of the
+	// AzureCluster as an ARM template, in the ConfigMap named after the AzureCluster with the ARMTemplateSuffix.
+	// The template is exported each time it is set to a new value, e.g. the current time.
+	ARMTemplateExportRequestAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/arm-template-export-request"
+
+	// ARMTemplateSuffix is the suffix of the names of the ConfigMaps holding exported ARM templates.
+	ARMTemplateSuffix = "-arm-template"
+
+	// ARMTemplateKey is the key of the ARM template in the ConfigMaps holding exported ARM templates.
+	ARMTemplateKey = "template.json"
+
+	// RoleDefinitionExportRequestAnnotation requests an export of the least-privilege custom role definition of the
+	// cluster identity, with the actions the enabled services of the AzureCluster perform, in the ConfigMap named after
+	// the AzureCluster with the RoleDefinitionSuffix. The role definition is exported each time it is set to a new
+	// value, e.g. the current time.
+	RoleDefinitionExportRequestAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/role-definition-export-request"
+
+	// RoleDefinitionCreateAnnotation set to "true" also creates or updates the exported role definition in the
+	// subscription of the AzureCluster, which requires the Microsoft.Authorization/roleDefinitions/write permission.
+	RoleDefinitionCreateAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/role-definition-create"
+
+	// RoleDefinitionSuffix is the suffix of the names of the ConfigMaps holding exported role definitions.
+	RoleDefinitionSuffix = "-role-definition"
+
+	// RoleDefinitionKey is the key of the role definition in the ConfigMaps holding exported role definitions.
+	RoleDefinitionKey = "roleDefinition.json"
+
+	// IdentityAnnotation records the AzureClusterIdentity, as "namespace/name", the AzureCluster was last reconciled
+	// with. It is empty when the AzureCluster was reconciled with the credentials of the manager environment.
+	IdentityAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/identity"
+
+	// TokenAudienceAnnotation records the audience of the access tokens the AzureCluster was last reconciled with, the
+	// Azure Resource Manager endpoint of its cloud.
+	TokenAudienceAnnotation = "azurecluster.infrastructure.cluster.x-k8s.io/token-audience"
 )
 
 // AzureClusterSpec defines the desired state of AzureCluster.
@@ -49,6 +91,67 @@ type AzureClusterSpec struct {
 	// this when creating an AzureCluster as CAPZ will set this for you. However, if it is set, CAPZ will not change it.
 	// +optional
 	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint,omitempty"`
+
+	// Paused hibernates the cluster when set to Deallocated: the VMs of all its AzureMachines are deallocated, which
+	// keeps their disks and network interfaces but stops compute billing. Unsetting it resumes the cluster, starting
+	// the control plane VMs before the worker VMs.
+	// +kubebuilder:validation:Enum=Deallocated
+	// +optional
+	Paused PowerState `json:"paused,omitempty"`
+
+	// ReconciliationBackend is how the Azure resources of the cluster are reconciled: by calling the Azure API directly
+	// (ARM), or through Azure Service Operator resources (ASO), which makes their state visible as Kubernetes objects.
+	// Defaults to ARM. It can't be changed once set.
+	// ASO requires the ASOBackend feature flag to be enabled.
+	// +kubebuilder:validation:Enum=ARM;ASO
+	// +optional
+	ReconciliationBackend ReconciliationBackend `json:"reconciliationBackend,omitempty"`
+
+	// Monitoring onboards the cluster to Azure Monitor: the Azure Monitor agent is installed on its machines, and a
+	// data collection rule sends their performance counters and syslog to a Log Analytics workspace.
+	// +optional
+	Monitoring *AzureMonitoring `json:"monitoring,omitempty"`
+
+	// SecurityProfile specifies the security settings of the cluster. Enabling Defender installs the Azure security
+	// agent on the machines of the cluster, and requires Monitoring.
+	// +optional
+	SecurityProfile *ClusterSecurityProfile `json:"securityProfile,omitempty"`
+
+	// AttachedACRs are the resource IDs of the Azure Container Registries the machines of the cluster pull images from.
+	// The AcrPull role is granted on each registry to the system-assigned identity of the machines.
+	// +optional
+	AttachedACRs []string `json:"attachedACRs,omitempty"`
+
+	// CrossSubscriptionAccess sets how the access of the cluster identity to the Azure Compute Galleries and disk
+	// encryption sets of the machines which are in another subscription than the cluster is handled. Validate, the
+	// default, checks that the cluster identity has the permissions the machines need on them, and rejects the machines
+	// with the missing permission. Assign grants the Reader role on them to the cluster identity, which requires the
+	// cluster identity to be allowed to create role assignments in their subscription.
+	// +kubebuilder:validation:Enum=Validate;Assign
+	// +optional
+	CrossSubscriptionAccess CrossSubscriptionAccessPolicy `json:"crossSubscriptionAccess,omitempty"`
+
+	// ControlPlaneZoneSpreading sets how the control plane machines whose Machine and AzureMachine have no failure
+	// domain are placed. Explicit, the default, creates their VMs without an availability zone. Automatic places each of
+	// them in the control plane failure domain of the cluster with the fewest control plane machines, in the order of
+	// the failure domains on ties, and records it in the failureDomain of the AzureMachine before its VM is created.
+	// +kubebuilder:validation:Enum=Explicit;Automatic
+	// +optional
+	ControlPlaneZoneSpreading ControlPlaneZoneSpreadingPolicy `json:"controlPlaneZoneSpreading,omitempty"`
+
+	// Budget caps the total vCPUs or the estimated hourly cost of the virtual machines of the cluster. AzureMachines
+	// and machine pool scale-ups that would exceed it are rejected. Lowering it doesn't remove existing machines.
+	// +optional
+	Budget *BudgetGuardrail `json:"budget,omitempty"`
+
+	// PolicyExemptions are created on the resource group of the cluster before any other of its resources, to waive
+	// the policy assignments which would deny them, e.g. public IPs for the API server load balancer. Creating them
+	// requires the cluster identity to be allowed the Microsoft.Authorization/policyAssignments/exempt/action on the
+	// policy assignments.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	PolicyExemptions []PolicyExemption `json:"policyExemptions,omitempty"`
 }
 
 // AzureClusterStatus defines the observed state of AzureCluster.
@@ -74,6 +177,26 @@ type AzureClusterStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates Futures `json:"longRunningOperationStates,omitempty"`
+
+	// Resources records the Azure resources created or updated for this AzureCluster, one entry per ARM ID.
+	// +optional
+	Resources ResourceStatuses `json:"resources,omitempty"`
+
+	// LastARMTemplateExportRequest is the value of the ARMTemplateExportRequestAnnotation the last ARM template was
+	// exported for.
+	// +optional
+	LastARMTemplateExportRequest string `json:"lastARMTemplateExportRequest,omitempty"`
+
+	// LastRoleDefinitionExportRequest is the value of the RoleDefinitionExportRequestAnnotation the last role
+	// definition was exported for.
+	// +optional
+	LastRoleDefinitionExportRequest string `json:"lastRoleDefinitionExportRequest,omitempty"`
+
+	// SSHPublicKey is the SSH public key string base64 encoded of the SSH key pair generated for the cluster, whose
+	// private key is stored in the <cluster-name>-ssh Secret. It is added to the machines of the cluster which don't
+	// set an SSH public key.
+	// +optional
+	SSHPublicKey string `json:"sshPublicKey,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -127,6 +250,16 @@ func (c *AzureCluster) SetFutures(futures Futures) {
 	c.Status.LongRunningOperationStates = futures
 }
 
+// GetResourceStatuses returns the list of Azure resources recorded for an AzureCluster API object.
+func (c *AzureCluster) GetResourceStatuses() ResourceStatuses {
+	return c.Status.Resources
+}
+
+// SetResourceStatuses will set the given Azure resources on an AzureCluster object.
+func (c *AzureCluster) SetResourceStatuses(resources ResourceStatuses) {
+	c.Status.Resources = resources
+}
+
 func init() {
 	SchemeBuilder.Register(&AzureCluster{}, &AzureClusterList{})
 }
@@ -19,14 +19,22 @@ package v1beta1
 import (
 	"fmt"
 	"net"
+	"net/url"
+	"path"
 	"reflect"
 	"regexp"
+	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	valid "github.com/asaskevich/govalidator"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 )
 
 const (
@@ -41,6 +49,18 @@ const (
 	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules.
 	subnetRegex       = `^[-\w\._]+$`
 	loadBalancerRegex = `^[-\w\._]+$`
+	// frontendIPConfigurationIDRegex matches the resource ID of a load balancer frontend IP configuration.
+	frontendIPConfigurationIDRegex = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Network/loadBalancers/[^/]+/frontendIPConfigurations/[^/]+$`
+	// dnsForwardingRulesetIDRegex matches the resource ID of a DNS forwarding ruleset.
+	dnsForwardingRulesetIDRegex = `(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Network/dnsForwardingRulesets/[^/]+$`
+	// policyAssignmentIDRegex matches the resource ID of a policy assignment, at any scope.
+	policyAssignmentIDRegex = `(?i)^(/[^/]+/[^/]+)*/providers/Microsoft\.Authorization/policyAssignments/[^/]+$`
+	// policyExemptionNameRegex matches the name of a policy exemption, which can't use <>*%&:\?.+/ nor end with a space.
+	policyExemptionNameRegex = `^[^<>*%&:\\?.+/]*[^<>*%&:\\?.+/ ]$`
+	// minDNSResolverSubnetPrefix and maxDNSResolverSubnetPrefix are the prefix lengths of the smallest and largest
+	// subnets a DNS private resolver endpoint can be in.
+	minDNSResolverSubnetPrefix = 24
+	maxDNSResolverSubnetPrefix = 28
 	// MaxLoadBalancerOutboundIPs is the maximum number of outbound IPs in a Standard LoadBalancer frontend configuration.
 	MaxLoadBalancerOutboundIPs = 16
 	// MinLBIdleTimeoutInMinutes is the minimum number of minutes for the LB idle timeout.
@@ -51,12 +71,23 @@ const (
 	// https://docs.microsoft.com/en-us/azure/virtual-network/network-security-groups-overview#security-rules
 	minRulePriority = 100
 	maxRulePriority = 4096
+	// described in https://docs.microsoft.com/en-us/azure/azure-resource-manager/management/resource-name-rules#microsoftstorage.
+	storageAccountNameRegex = `^[a-z0-9]{3,24}$`
+	blobContainerNameRegex  = `^[a-z0-9](-?[a-z0-9])+$`
+	blobContainerMaxLength  = 63
+	// scriptFileNameRegex matches the name of a script file, which is run by the bootstrap extension.
+	scriptFileNameRegex = `^[\w-][\w.-]*$`
+	// proxyForbiddenChars are the characters that can't be used in the proxy configuration of a cluster.
+	proxyForbiddenChars = "'\"` \t\n\\$"
+	// registryHostRegex matches the host, and optional port, of a container image registry.
+	registryHostRegex = `^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*(:[0-9]{1,5})?$`
 )
 
 // validateCluster validates a cluster.
 func (c *AzureCluster) validateCluster(old *AzureCluster) error {
 	var allErrs field.ErrorList
 	allErrs = append(allErrs, c.validateClusterName()...)
+	allErrs = append(allErrs, c.validateClusterAnnotations()...)
 	allErrs = append(allErrs, c.validateClusterSpec(old)...)
 	if len(allErrs) == 0 {
 		return nil
@@ -83,6 +114,81 @@ func (c *AzureCluster) validateClusterSpec(old *AzureCluster) field.ErrorList {
 	allErrs = append(allErrs, validateCloudProviderConfigOverrides(c.Spec.CloudProviderConfigOverrides, oldCloudProviderConfigOverrides,
 		field.NewPath("spec").Child("cloudProviderConfigOverrides"))...)
 
+	allErrs = append(allErrs, validateCloudProviderIdentity(c.Spec.CloudProviderIdentity,
+		field.NewPath("spec").Child("cloudProviderIdentity"))...)
+
+	allErrs = append(allErrs, validateBootstrapDataStorage(c.Spec.BootstrapDataStorage,
+		field.NewPath("spec").Child("bootstrapDataStorage"))...)
+
+	allErrs = append(allErrs, validateProxyConfig(c.Spec.Proxy,
+		field.NewPath("spec").Child("proxy"))...)
+
+	allErrs = append(allErrs, validateBootstrapExtension(c.Spec.BootstrapExtension,
+		field.NewPath("spec").Child("bootstrapExtension"))...)
+
+	allErrs = append(allErrs, validateRegistryMirrors(c.Spec.RegistryMirrors,
+		field.NewPath("spec").Child("registryMirrors"))...)
+
+	allErrs = append(allErrs, ValidateMonitoring(c.Spec.Monitoring,
+		field.NewPath("spec").Child("monitoring"))...)
+
+	allErrs = append(allErrs, validateSecurityProfile(c.Spec.SecurityProfile, c.Spec.Monitoring,
+		field.NewPath("spec").Child("securityProfile"))...)
+
+	allErrs = append(allErrs, ValidateAttachedACRs(c.Spec.AttachedACRs,
+		field.NewPath("spec").Child("attachedACRs"))...)
+
+	allErrs = append(allErrs, validateBudget(c.Spec.Budget,
+		field.NewPath("spec").Child("budget"))...)
+
+	var oldPolicyExemptions []PolicyExemption
+	if old != nil {
+		oldPolicyExemptions = old.Spec.PolicyExemptions
+	}
+	allErrs = append(allErrs, validatePolicyExemptions(c.Spec.PolicyExemptions, oldPolicyExemptions,
+		field.NewPath("spec").Child("policyExemptions"))...)
+
+	if c.Spec.Addons != nil && !feature.Gates.Enabled(feature.ClusterAddons) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("addons"),
+			"can be set only if the ClusterAddons feature flag is enabled"))
+	}
+
+	if c.Spec.ReconciliationBackend == ReconciliationBackendASO && !feature.Gates.Enabled(feature.ASOBackend) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("reconciliationBackend"),
+			"can be set to ASO only if the ASOBackend feature flag is enabled"))
+	}
+
+	allErrs = append(allErrs, validateExtendedLocation(c.Spec.ExtendedLocation, c.Spec,
+		field.NewPath("spec").Child("extendedLocation"))...)
+
+	// The subscription is immutable, so it is only required of new clusters.
+	if old == nil {
+		allErrs = append(allErrs, ValidateSubscriptionID(c.Spec.SubscriptionID, c.Spec.IdentityRef,
+			field.NewPath("spec").Child("subscriptionID"))...)
+	}
+
+	if external := c.Spec.NetworkSpec.APIServerLB.External; external != nil {
+		// The external load balancer is read with the credentials of the cluster, and its frontend is only known to the user.
+		if resource, err := arm.ParseResourceID(external.ID); err == nil && c.Spec.SubscriptionID != "" && !strings.EqualFold(resource.SubscriptionID, c.Spec.SubscriptionID) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "networkSpec", "apiServerLB", "external", "id"), external.ID,
+				"the external load balancer must be in the subscription of the cluster"))
+		}
+		if c.Spec.ControlPlaneEndpoint.Host == "" {
+			allErrs = append(allErrs, field.Required(field.NewPath("spec", "controlPlaneEndpoint", "host"),
+				"the host of the frontend of the external API Server load balancer is required"))
+		}
+	}
+
+	if c.Spec.NetworkSpec.IsUserDefinedRouting() && c.Spec.BastionSpec.AzureBastion != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("bastionSpec").Child("azureBastion"),
+			"Azure Bastion requires a public IP and cannot be used when outboundType is UserDefinedRouting"))
+	}
+
+	if c.Spec.BastionSpec.AzureBastion != nil {
+		allErrs = append(allErrs, validatePublicIP(&c.Spec.BastionSpec.AzureBastion.PublicIP,
+			field.NewPath("spec").Child("bastionSpec", "azureBastion", "publicIP"))...)
+	}
+
 	return allErrs
 }
 
@@ -104,6 +210,22 @@ func (c *AzureCluster) validateClusterName() field.ErrorList {
 	return allErrs
 }
 
+// validateClusterAnnotations validates the annotations overriding how the cluster is reconciled.
+func (c *AzureCluster) validateClusterAnnotations() field.ErrorList {
+	var allErrs field.ErrorList
+	for _, annotation := range []string{ReconcileTimeoutAnnotation, SyncPeriodAnnotation} {
+		value, ok := c.Annotations[annotation]
+		if !ok {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "annotations").Key(annotation), value,
+				"must be a positive duration, e.g. 30m"))
+		}
+	}
+	return allErrs
+}
+
 // validateNetworkSpec validates a NetworkSpec.
 func validateNetworkSpec(networkSpec NetworkSpec, old NetworkSpec, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -146,8 +268,23 @@ func validateNetworkSpec(networkSpec NetworkSpec, old NetworkSpec, fldPath *fiel
 
 	allErrs = append(allErrs, validateControlPlaneOutboundLB(networkSpec.ControlPlaneOutboundLB, networkSpec.APIServerLB, fldPath.Child("controlPlaneOutboundLB"))...)
 
+	allErrs = append(allErrs, validateGlobalAPIServerLB(networkSpec.GlobalAPIServerLB, old.GlobalAPIServerLB, networkSpec.APIServerLB, fldPath.Child("globalAPIServerLB"))...)
+
+	allErrs = append(allErrs, validateDNSResolver(networkSpec.DNSResolver, old.DNSResolver, fldPath.Child("dnsResolver"))...)
+
 	allErrs = append(allErrs, validatePrivateDNSZoneName(networkSpec.PrivateDNSZoneName, networkSpec.APIServerLB.Type, fldPath.Child("privateDNSZoneName"))...)
 
+	var natGatewaySubnets []int
+	for i, subnet := range networkSpec.Subnets {
+		if subnet.IsNatGatewayEnabled() {
+			natGatewaySubnets = append(natGatewaySubnets, i)
+			allErrs = append(allErrs, validatePublicIP(&subnet.NatGateway.NatGatewayIP,
+				fldPath.Child("subnets").Index(i).Child("natGateway", "ip"))...)
+		}
+	}
+	allErrs = append(allErrs, validateOutboundType(networkSpec.OutboundType, networkSpec.APIServerLB.Type,
+		networkSpec.NodeOutboundLB != nil, networkSpec.ControlPlaneOutboundLB != nil, natGatewaySubnets, fldPath)...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -322,6 +459,19 @@ func validateAPIServerLB(lb LoadBalancerSpec, old LoadBalancerSpec, cidrs []stri
 	if old.Name != "" && old.Name != lb.Name {
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("name"), "API Server load balancer name should not be modified after AzureCluster creation."))
 	}
+	// External should be immutable.
+	if old.Name != "" && !reflect.DeepEqual(old.External, lb.External) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("external"), "API Server load balancer external reference should not be modified after AzureCluster creation."))
+	}
+
+	// FrontendPort should be immutable.
+	if old.Name != "" && !pointer.Int32Equal(old.FrontendPort, lb.FrontendPort) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendPort"), "API Server load balancer frontend port should not be modified after AzureCluster creation."))
+	}
+
+	if lb.External != nil {
+		return append(allErrs, validateExternalAPIServerLB(lb, fldPath)...)
+	}
 
 	// There should only be one IP config.
 	if len(lb.FrontendIPs) != 1 || pointer.Int32Deref(lb.FrontendIPsCount, 1) != 1 {
@@ -354,6 +504,109 @@ func validateAPIServerLB(lb LoadBalancerSpec, old LoadBalancerSpec, cidrs []stri
 		}
 	}
 
+	allErrs = append(allErrs, validateGatewayLoadBalancers(lb.FrontendIPs, lb.Type, fldPath.Child("frontendIPs"))...)
+	allErrs = append(allErrs, validateFrontendIPPublicIPs(lb.FrontendIPs, fldPath.Child("frontendIPs"))...)
+	allErrs = append(allErrs, validateAPIServerAliasFrontends(lb, old, cidrs, fldPath.Child("aliasFrontends"))...)
+
+	return allErrs
+}
+
+// validateAPIServerAliasFrontends validates the additional frontends of the API server load balancer. Every frontend
+// should listen on its own IP and port, and alias frontends can only be added after AzureCluster creation.
+func validateAPIServerAliasFrontends(lb LoadBalancerSpec, old LoadBalancerSpec, cidrs []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	var primaryName string
+	if len(lb.FrontendIPs) != 0 {
+		primaryName = lb.FrontendIPs[0].Name
+	}
+	frontendPort := pointer.Int32Deref(lb.FrontendPort, 0)
+	names := make(map[string]bool)
+	sharedPorts := make(map[int32]bool)
+	for i, alias := range lb.AliasFrontends {
+		idxPath := fldPath.Index(i)
+
+		if err := validateLoadBalancerName(alias.Name, idxPath.Child("name")); err != nil {
+			allErrs = append(allErrs, err)
+		} else if names[alias.Name] || alias.Name == primaryName {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), alias.Name))
+		}
+		names[alias.Name] = true
+
+		switch lb.Type {
+		case Public:
+			if alias.PrivateIPAddress != "" {
+				allErrs = append(allErrs, field.Forbidden(idxPath.Child("privateIP"), "Public Load Balancers cannot have a Private IP"))
+			}
+			allErrs = append(allErrs, validatePublicIP(alias.PublicIP, idxPath.Child("publicIP"))...)
+		case Internal:
+			if alias.PublicIP != nil {
+				allErrs = append(allErrs, field.Forbidden(idxPath.Child("publicIP"), "Internal Load Balancers cannot have a Public IP"))
+			}
+			if alias.PrivateIPAddress != "" {
+				if err := validateInternalLBIPAddress(alias.PrivateIPAddress, cidrs, idxPath.Child("privateIP")); err != nil {
+					allErrs = append(allErrs, err)
+				}
+			}
+		}
+
+		// Alias frontends without their own IP listen on the frontend IP of the load balancer, so they need their own port.
+		port := pointer.Int32Deref(alias.Port, frontendPort)
+		if !alias.HasOwnIP() && port == frontendPort {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("port"), port,
+				"alias frontends without their own IP should have another port than the frontend port of the load balancer"))
+		}
+		if !alias.HasOwnIP() {
+			if sharedPorts[port] {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("port"), port))
+			}
+			sharedPorts[port] = true
+		}
+	}
+
+	// Existing alias frontends should not be modified or removed.
+	if old.Name != "" {
+		if len(lb.AliasFrontends) < len(old.AliasFrontends) {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "API Server load balancer alias frontends cannot be removed after AzureCluster creation."))
+		} else {
+			for i, oldAlias := range old.AliasFrontends {
+				if !reflect.DeepEqual(oldAlias, lb.AliasFrontends[i]) {
+					allErrs = append(allErrs, field.Forbidden(fldPath.Index(i), "API Server load balancer alias frontends cannot be modified after AzureCluster creation."))
+				}
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateExternalAPIServerLB validates an API server load balancer that is managed outside of the provider, whose
+// frontend IPs are not managed by the provider either.
+func validateExternalAPIServerLB(lb LoadBalancerSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	resource, err := arm.ParseResourceID(lb.External.ID)
+	if err != nil || !strings.EqualFold(resource.ResourceType.Namespace, "Microsoft.Network") || !strings.EqualFold(resource.ResourceType.Type, "loadBalancers") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("external", "id"), lb.External.ID, "must be the resource ID of a load balancer"))
+	} else if lb.Name != resource.Name {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), lb.Name, "must be the name of the external load balancer"))
+	}
+	if lb.External.BackendPoolName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("external", "backendPoolName"), "the backend pool of the external load balancer is required"))
+	}
+	if len(lb.FrontendIPs) != 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPs"), "cannot be set for external load balancers"))
+	}
+	if lb.FrontendIPsCount != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendIPsCount"), "cannot be set for external load balancers"))
+	}
+	if lb.FrontendPort != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendPort"), "cannot be set for external load balancers"))
+	}
+	if len(lb.AliasFrontends) != 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("aliasFrontends"), "cannot be set for external load balancers"))
+	}
+
 	return allErrs
 }
 
@@ -375,6 +628,11 @@ func validateNodeOutboundLB(lb *LoadBalancerSpec, old *LoadBalancerSpec, apiserv
 		return allErrs
 	}
 
+	if lb.External != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("external"), "only the API Server load balancer can be external"))
+	}
+	allErrs = append(allErrs, validateAPIServerOnlyFrontends(*lb, fldPath)...)
+
 	if old != nil && old.ID != lb.ID {
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("id"), "Node outbound load balancer ID should not be modified after AzureCluster creation."))
 	}
@@ -404,6 +662,9 @@ func validateNodeOutboundLB(lb *LoadBalancerSpec, old *LoadBalancerSpec, apiserv
 			fmt.Sprintf("Max front end ips allowed is %d", MaxLoadBalancerOutboundIPs)))
 	}
 
+	allErrs = append(allErrs, validateGatewayLoadBalancers(lb.FrontendIPs, lb.Type, fldPath.Child("frontendIPs"))...)
+	allErrs = append(allErrs, validateFrontendIPPublicIPs(lb.FrontendIPs, fldPath.Child("frontendIPs"))...)
+
 	return allErrs
 }
 
@@ -418,11 +679,186 @@ func validateControlPlaneOutboundLB(lb *LoadBalancerSpec, apiserverLB LoadBalanc
 
 	allErrs = append(allErrs, validateClassSpecForControlPlaneOutboundLB(lbClassSpec, apiServerLBClassSpec, fldPath)...)
 
+	if lb != nil && lb.External != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("external"), "only the API Server load balancer can be external"))
+	}
+	if lb != nil {
+		allErrs = append(allErrs, validateAPIServerOnlyFrontends(*lb, fldPath)...)
+	}
+
 	if apiServerLBClassSpec.Type == Internal && lb != nil {
 		if lb.FrontendIPsCount != nil && *lb.FrontendIPsCount > MaxLoadBalancerOutboundIPs {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("frontendIPsCount"), *lb.FrontendIPsCount,
 				fmt.Sprintf("Max front end ips allowed is %d", MaxLoadBalancerOutboundIPs)))
 		}
+		allErrs = append(allErrs, validateGatewayLoadBalancers(lb.FrontendIPs, lb.Type, fldPath.Child("frontendIPs"))...)
+		allErrs = append(allErrs, validateFrontendIPPublicIPs(lb.FrontendIPs, fldPath.Child("frontendIPs"))...)
+	}
+
+	return allErrs
+}
+
+// validateAPIServerOnlyFrontends validates that an outbound load balancer doesn't set the frontends that only the API
+// server load balancer has.
+func validateAPIServerOnlyFrontends(lb LoadBalancerSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if lb.FrontendPort != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("frontendPort"), "only the API Server load balancer can have a frontend port"))
+	}
+	if len(lb.AliasFrontends) != 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("aliasFrontends"), "only the API Server load balancer can have alias frontends"))
+	}
+
+	return allErrs
+}
+
+// validateGlobalAPIServerLB validates the cross-region load balancer in front of the API server load balancer.
+func validateGlobalAPIServerLB(lb *GlobalLoadBalancerSpec, old *GlobalLoadBalancerSpec, apiserverLB LoadBalancerSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if lb == nil {
+		return allErrs
+	}
+	if apiserverLB.Type != Public {
+		allErrs = append(allErrs, field.Forbidden(fldPath,
+			"Cross-region load balancers can only be in front of Public API server load balancers"))
+	}
+	if apiserverLB.External != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath,
+			"Cross-region load balancers cannot be in front of external API server load balancers"))
+	}
+	if err := validateLoadBalancerName(lb.Name, fldPath.Child("name")); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if old != nil && old.Name != "" && old.Name != lb.Name {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("name"), "Global API Server load balancer name should not be modified after AzureCluster creation."))
+	}
+	if old != nil && old.Location != "" && old.Location != lb.Location {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("location"), "Global API Server load balancer location should not be modified after AzureCluster creation."))
+	}
+	if lb.PublicIP != nil && lb.PublicIP.Availability != "" && lb.PublicIP.Availability != PublicIPAvailabilityNoZone {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("publicIP", "availability"), lb.PublicIP.Availability,
+			"Global tier public IPs have no availability zones"))
+	}
+	allErrs = append(allErrs, validatePublicIP(lb.PublicIP, fldPath.Child("publicIP"))...)
+	for i, id := range lb.RegionalFrontendIPConfigurationIDs {
+		if success, _ := regexp.MatchString(frontendIPConfigurationIDRegex, id); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("regionalFrontendIPConfigurationIDs").Index(i), id,
+				"must be the resource ID of the frontend IP configuration of a regional load balancer"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateDNSResolver validates the DNS private resolver of the virtual network of the cluster.
+func validateDNSResolver(resolver *DNSResolverSpec, old *DNSResolverSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if old != nil && (resolver == nil || old.ForwardingRulesetID != resolver.ForwardingRulesetID || old.Name != resolver.Name) {
+		allErrs = append(allErrs, field.Forbidden(fldPath,
+			"The DNS private resolver and the DNS forwarding ruleset can't be changed after AzureCluster creation"))
+	}
+	if resolver == nil {
+		return allErrs
+	}
+
+	if resolver.ForwardingRulesetID != "" {
+		if success, _ := regexp.MatchString(dnsForwardingRulesetIDRegex, resolver.ForwardingRulesetID); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("forwardingRulesetID"), resolver.ForwardingRulesetID,
+				"must be the resource ID of a DNS forwarding ruleset"))
+		}
+		if len(resolver.ForwardingRules) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("forwardingRules"),
+				"forwarding rules can't be set when linking to an existing DNS forwarding ruleset"))
+		}
+		return allErrs
+	}
+
+	for i, cidr := range resolver.OutboundSubnet.CIDRBlocks {
+		_, subnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("outboundSubnet", "cidrBlocks").Index(i), cidr, "invalid CIDR format"))
+			continue
+		}
+		if prefix, _ := subnet.Mask.Size(); prefix < minDNSResolverSubnetPrefix || prefix > maxDNSResolverSubnetPrefix {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("outboundSubnet", "cidrBlocks").Index(i), cidr,
+				fmt.Sprintf("the subnet of a DNS private resolver endpoint must be between a /%d and a /%d", maxDNSResolverSubnetPrefix, minDNSResolverSubnetPrefix)))
+		}
+	}
+
+	ruleNames := make(map[string]bool, len(resolver.ForwardingRules))
+	for i, rule := range resolver.ForwardingRules {
+		rulePath := fldPath.Child("forwardingRules").Index(i)
+		if ruleNames[rule.Name] {
+			allErrs = append(allErrs, field.Duplicate(rulePath.Child("name"), rule.Name))
+		}
+		ruleNames[rule.Name] = true
+		if !valid.IsDNSName(strings.TrimSuffix(rule.DomainName, ".")) {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("domainName"), rule.DomainName, "must be a valid domain name"))
+		}
+		for j, server := range rule.TargetDNSServers {
+			if net.ParseIP(server.IPAddress) == nil {
+				allErrs = append(allErrs, field.Invalid(rulePath.Child("targetDNSServers").Index(j).Child("ipAddress"), server.IPAddress,
+					"must be a valid IP address"))
+			}
+			if server.Port != nil && (*server.Port < 1 || *server.Port > 65535) {
+				allErrs = append(allErrs, field.Invalid(rulePath.Child("targetDNSServers").Index(j).Child("port"), *server.Port,
+					"must be between 1 and 65535"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateGatewayLoadBalancers validates the Gateway Load Balancers the frontend IPs of a load balancer are chained to.
+func validateGatewayLoadBalancers(frontendIPs []FrontendIP, lbType LBType, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, frontendIP := range frontendIPs {
+		if frontendIP.GatewayLoadBalancerID == "" {
+			continue
+		}
+		if lbType != Public {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(i).Child("gatewayLoadBalancerID"),
+				"Only the frontend IPs of Public load balancers can be chained to a Gateway Load Balancer"))
+			continue
+		}
+		if success, _ := regexp.MatchString(frontendIPConfigurationIDRegex, frontendIP.GatewayLoadBalancerID); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("gatewayLoadBalancerID"), frontendIP.GatewayLoadBalancerID,
+				"must be the resource ID of the frontend IP configuration of a Gateway Load Balancer"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateFrontendIPPublicIPs validates the public IPs of the frontend IPs of a load balancer.
+func validateFrontendIPPublicIPs(frontendIPs []FrontendIP, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, frontendIP := range frontendIPs {
+		allErrs = append(allErrs, validatePublicIP(frontendIP.PublicIP, fldPath.Index(i).Child("publicIP"))...)
+	}
+
+	return allErrs
+}
+
+// validatePublicIP validates the availability zones of a public IP. Whether the location supports them is only known
+// when the public IP is reconciled.
+func validatePublicIP(ip *PublicIPSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if ip == nil {
+		return allErrs
+	}
+	if ip.Availability == PublicIPAvailabilityZonal && ip.Zone == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("zone"), "Zonal public IPs require a zone"))
+	}
+	if ip.Availability != PublicIPAvailabilityZonal && ip.Zone != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("zone"), "zone can only be set for Zonal public IPs"))
 	}
 
 	return allErrs
@@ -447,6 +883,182 @@ func validatePrivateDNSZoneName(privateDNSZoneName string, apiserverLBType LBTyp
 	return allErrs
 }
 
+// validateOutboundType validates that a cluster using user-defined routing for egress doesn't configure
+// any load balancer or NAT gateway that would require a public IP.
+func validateOutboundType(outboundType OutboundType, apiServerLBType LBType, hasNodeOutboundLB, hasControlPlaneOutboundLB bool, natGatewaySubnets []int, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if outboundType != OutboundTypeUserDefinedRouting {
+		return allErrs
+	}
+
+	if apiServerLBType != Internal {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("apiServerLB").Child("type"),
+			"API Server load balancer must be Internal when outboundType is UserDefinedRouting"))
+	}
+	if hasNodeOutboundLB {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("nodeOutboundLB"),
+			"Node outbound load balancer cannot be set when outboundType is UserDefinedRouting"))
+	}
+	if hasControlPlaneOutboundLB {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("controlPlaneOutboundLB"),
+			"Control plane outbound load balancer cannot be set when outboundType is UserDefinedRouting"))
+	}
+	for _, i := range natGatewaySubnets {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("subnets").Index(i).Child("natGateway"),
+			"NAT gateways managed by the Azure provider cannot be set when outboundType is UserDefinedRouting"))
+	}
+
+	return allErrs
+}
+
+// validateCloudProviderIdentity validates a CloudProviderIdentity.
+func validateCloudProviderIdentity(identity *CloudProviderIdentity, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if identity == nil {
+		return allErrs
+	}
+
+	if identity.Type == VMIdentityUserAssigned && identity.UserAssignedIdentityClientID == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("userAssignedIdentityClientID"),
+			"must be set when type is UserAssigned"))
+	}
+	if identity.Type != VMIdentityUserAssigned && identity.UserAssignedIdentityClientID != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("userAssignedIdentityClientID"),
+			"can only be set when type is UserAssigned"))
+	}
+	if identity.Type == CloudProviderWorkloadIdentity && identity.WorkloadIdentityClientID == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("workloadIdentityClientID"),
+			"must be set when type is WorkloadIdentity"))
+	}
+	if identity.Type != CloudProviderWorkloadIdentity && identity.WorkloadIdentityClientID != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("workloadIdentityClientID"),
+			"can only be set when type is WorkloadIdentity"))
+	}
+
+	return allErrs
+}
+
+// validateBootstrapDataStorage validates a BootstrapDataStorage.
+func validateBootstrapDataStorage(storage *BootstrapDataStorage, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if storage == nil {
+		return allErrs
+	}
+
+	if success, _ := regexp.MatchString(storageAccountNameRegex, storage.StorageAccountName); !success {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("storageAccountName"), storage.StorageAccountName,
+			fmt.Sprintf("storage account name doesn't match regex %s", storageAccountNameRegex)))
+	}
+	if storage.ResourceGroup != "" {
+		if err := validateResourceGroup(storage.ResourceGroup, fldPath.Child("resourceGroup")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	if storage.ContainerName != "" {
+		if success, _ := regexp.MatchString(blobContainerNameRegex, storage.ContainerName); !success || len(storage.ContainerName) > blobContainerMaxLength {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("containerName"), storage.ContainerName,
+				fmt.Sprintf("container name must be at most %d characters and match regex %s", blobContainerMaxLength, blobContainerNameRegex)))
+		}
+	}
+	if storage.SASDuration != nil && storage.SASDuration.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("sasDuration"), storage.SASDuration.Duration.String(),
+			"must be a positive duration"))
+	}
+
+	return allErrs
+}
+
+// validateProxyConfig validates a ProxyConfig.
+func validateProxyConfig(proxy *ProxyConfig, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if proxy == nil {
+		return allErrs
+	}
+
+	if proxy.HTTPProxy == "" && proxy.HTTPSProxy == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of httpProxy or httpsProxy must be set"))
+	}
+	if proxy.HTTPProxy != "" {
+		if err := validateProxyURL(proxy.HTTPProxy, fldPath.Child("httpProxy")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	if proxy.HTTPSProxy != "" {
+		if err := validateProxyURL(proxy.HTTPSProxy, fldPath.Child("httpsProxy")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	for i, host := range proxy.NoProxy {
+		if host == "" || strings.ContainsAny(host, proxyForbiddenChars+",") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("noProxy").Index(i), host,
+				"must be a single non-empty host name, domain, IP address or CIDR"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateProxyURL validates the URL of a proxy. The URL is written to shell commands and configuration files, so
+// quotes and whitespaces are not allowed.
+func validateProxyURL(proxyURL string, fldPath *field.Path) *field.Error {
+	u, err := url.Parse(proxyURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.ContainsAny(proxyURL, proxyForbiddenChars) {
+		return field.Invalid(fldPath, proxyURL, "must be an http or https URL without quotes or whitespaces")
+	}
+	return nil
+}
+
+// validateBootstrapExtension validates a BootstrapExtension.
+func validateBootstrapExtension(extension *BootstrapExtension, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if extension == nil {
+		return allErrs
+	}
+
+	if extension.Disabled && (extension.Linux != nil || extension.Windows != nil) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "linux and windows can't be set when the bootstrap extension is disabled"))
+	}
+	allErrs = append(allErrs, validateBootstrapExtensionSource(extension.Linux, fldPath.Child("linux"))...)
+	allErrs = append(allErrs, validateBootstrapExtensionSource(extension.Windows, fldPath.Child("windows"))...)
+
+	return allErrs
+}
+
+// validateBootstrapExtensionSource validates a BootstrapExtensionSource.
+func validateBootstrapExtensionSource(source *BootstrapExtensionSource, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if source == nil {
+		return allErrs
+	}
+
+	if source.Publisher == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("publisher"), "publisher is required"))
+	}
+	if source.Type == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("type"), "type is required"))
+	}
+	if source.Version == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("version"), "version is required"))
+	}
+	if source.ScriptURL != "" {
+		u, err := url.Parse(source.ScriptURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("scriptURL"), source.ScriptURL, "must be an http or https URL"))
+		} else if success, _ := regexp.MatchString(scriptFileNameRegex, path.Base(u.Path)); !success {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("scriptURL"), source.ScriptURL,
+				fmt.Sprintf("the name of the script file must match regex %s", scriptFileNameRegex)))
+		}
+	}
+
+	return allErrs
+}
+
 // validateCloudProviderConfigOverrides validates CloudProviderConfigOverrides.
 func validateCloudProviderConfigOverrides(oldConfig, newConfig *CloudProviderConfigOverrides, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -485,6 +1097,16 @@ func validateClassSpecForAPIServerLB(lb LoadBalancerClassSpec, old *LoadBalancer
 		allErrs = append(allErrs, field.Forbidden(apiServerLBPath.Child("idleTimeoutInMinutes"), "API Server load balancer idle timeout cannot be modified after AzureCluster creation."))
 	}
 
+	// IP backend pools can't be referenced by the outbound rule of public load balancers.
+	if lb.IsIPBackendPool() && lb.Type != Internal {
+		allErrs = append(allErrs, field.Forbidden(apiServerLBPath.Child("backendPoolType"), "IP backend pools are only supported by Internal API Server load balancers."))
+	}
+
+	// BackendPoolType should be immutable, including when it isn't set. The SKU is always set on existing load balancers.
+	if old != nil && old.SKU != "" && old.BackendPoolType != lb.BackendPoolType {
+		allErrs = append(allErrs, field.Forbidden(apiServerLBPath.Child("backendPoolType"), "API Server load balancer backend pool type cannot be modified after AzureCluster creation."))
+	}
+
 	if lb.IdleTimeoutInMinutes != nil && (*lb.IdleTimeoutInMinutes < MinLBIdleTimeoutInMinutes || *lb.IdleTimeoutInMinutes > MaxLBIdleTimeoutInMinutes) {
 		allErrs = append(allErrs, field.Invalid(apiServerLBPath.Child("idleTimeoutInMinutes"), *lb.IdleTimeoutInMinutes,
 			fmt.Sprintf("Node outbound idle timeout should be between %d and %d minutes", MinLBIdleTimeoutInMinutes, MaxLoadBalancerOutboundIPs)))
@@ -506,6 +1128,14 @@ func validateClassSpecForNodeOutboundLB(lb *LoadBalancerClassSpec, old *LoadBala
 		return allErrs
 	}
 
+	if lb.IsIPBackendPool() {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("backendPoolType"), "Node outbound load balancer cannot use an IP backend pool."))
+	}
+
+	if lb.LoadDistribution != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("loadDistribution"), "Node outbound load balancer has no load balancing rules."))
+	}
+
 	if old != nil && old.SKU != lb.SKU {
 		allErrs = append(allErrs, field.Forbidden(fldPath.Child("sku"), "Node outbound load balancer SKU should not be modified after AzureCluster creation."))
 	}
@@ -540,6 +1170,14 @@ func validateClassSpecForControlPlaneOutboundLB(lb *LoadBalancerClassSpec, apise
 			return nil
 		}
 
+		if lb.IsIPBackendPool() {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("backendPoolType"), "Control plane outbound load balancer cannot use an IP backend pool."))
+		}
+
+		if lb.LoadDistribution != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("loadDistribution"), "Control plane outbound load balancer has no load balancing rules."))
+		}
+
 		if lb.IdleTimeoutInMinutes != nil && (*lb.IdleTimeoutInMinutes < MinLBIdleTimeoutInMinutes || *lb.IdleTimeoutInMinutes > MaxLBIdleTimeoutInMinutes) {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("idleTimeoutInMinutes"), *lb.IdleTimeoutInMinutes,
 				fmt.Sprintf("Control plane outbound idle timeout should be between %d and %d minutes", MinLBIdleTimeoutInMinutes, MaxLoadBalancerOutboundIPs)))
@@ -548,3 +1186,264 @@ func validateClassSpecForControlPlaneOutboundLB(lb *LoadBalancerClassSpec, apise
 
 	return allErrs
 }
+
+// validateRegistryMirrors validates the RegistryMirrors of a cluster.
+func validateRegistryMirrors(mirrors []RegistryMirror, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	registries := make(map[string]struct{}, len(mirrors))
+	for i, mirror := range mirrors {
+		mirrorPath := fldPath.Index(i)
+		if success, _ := regexp.MatchString(registryHostRegex, mirror.Registry); !success && mirror.Registry != DefaultRegistryMirror {
+			allErrs = append(allErrs, field.Invalid(mirrorPath.Child("registry"), mirror.Registry,
+				fmt.Sprintf("registry must be %q or a host with an optional port matching regex %s", DefaultRegistryMirror, registryHostRegex)))
+		}
+		if _, ok := registries[mirror.Registry]; ok {
+			allErrs = append(allErrs, field.Duplicate(mirrorPath.Child("registry"), mirror.Registry))
+		}
+		registries[mirror.Registry] = struct{}{}
+
+		if len(mirror.Endpoints) == 0 {
+			allErrs = append(allErrs, field.Required(mirrorPath.Child("endpoints"), "at least one endpoint must be set"))
+		}
+		for j, endpoint := range mirror.Endpoints {
+			u, err := url.Parse(endpoint)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" ||
+				strings.ContainsAny(endpoint, proxyForbiddenChars) {
+				allErrs = append(allErrs, field.Invalid(mirrorPath.Child("endpoints").Index(j), endpoint,
+					"must be an http or https URL without query, fragment, quotes or whitespaces"))
+			}
+		}
+
+		if mirror.CredentialsSecretRef != nil && mirror.CredentialsSecretRef.Name == "" {
+			allErrs = append(allErrs, field.Required(mirrorPath.Child("credentialsSecretRef", "name"), "name must be set"))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateMonitoring validates the Azure Monitor onboarding of a cluster.
+func ValidateMonitoring(monitoring *AzureMonitoring, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if monitoring == nil || monitoring.LogAnalyticsWorkspaceID == "" {
+		return allErrs
+	}
+
+	allErrs = append(allErrs, ValidateLogAnalyticsWorkspaceID(monitoring.LogAnalyticsWorkspaceID, fldPath.Child("logAnalyticsWorkspaceID"))...)
+	if monitoring.RetentionInDays != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("retentionInDays"), "can't be set along with logAnalyticsWorkspaceID"))
+	}
+
+	return allErrs
+}
+
+// ValidateLogAnalyticsWorkspaceID validates the resource ID of a Log Analytics workspace.
+func ValidateLogAnalyticsWorkspaceID(id string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	resource, err := arm.ParseResourceID(id)
+	if err != nil || !strings.EqualFold(resource.ResourceType.Namespace, "Microsoft.OperationalInsights") || !strings.EqualFold(resource.ResourceType.Type, "workspaces") {
+		allErrs = append(allErrs, field.Invalid(fldPath, id, "must be the resource ID of a Log Analytics workspace"))
+	}
+
+	return allErrs
+}
+
+// ValidateSubscriptionID validates the subscription of a cluster is set when the cluster uses an identity, as the
+// subscription of the manager environment only goes with the credentials of the manager environment.
+func ValidateSubscriptionID(subscriptionID string, identityRef *corev1.ObjectReference, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if identityRef != nil && subscriptionID == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "must be set when identityRef is set"))
+	}
+
+	return allErrs
+}
+
+// ValidateAttachedACRs validates the resource IDs of the Azure Container Registries attached to a cluster.
+func ValidateAttachedACRs(acrs []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	seen := make(map[string]struct{}, len(acrs))
+	for i, id := range acrs {
+		resource, err := arm.ParseResourceID(id)
+		if err != nil || !strings.EqualFold(resource.ResourceType.Namespace, "Microsoft.ContainerRegistry") || !strings.EqualFold(resource.ResourceType.Type, "registries") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), id, "must be the resource ID of an Azure Container Registry"))
+			continue
+		}
+		if _, ok := seen[strings.ToLower(id)]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), id))
+		}
+		seen[strings.ToLower(id)] = struct{}{}
+	}
+
+	return allErrs
+}
+
+// validateBudget validates the budget guardrail of a cluster.
+func validateBudget(budget *BudgetGuardrail, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if budget == nil {
+		return allErrs
+	}
+
+	if budget.MaxVCPUs == nil && budget.MaxHourlyCost == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of maxVCPUs and maxHourlyCost must be set"))
+	}
+	allErrs = append(allErrs, validateNonNegativeQuantity(budget.MaxHourlyCost, fldPath.Child("maxHourlyCost"))...)
+	allErrs = append(allErrs, validateNonNegativeQuantity(budget.VCPUHourlyCost, fldPath.Child("vCPUHourlyCost"))...)
+
+	for i, size := range budget.VMSizes {
+		if size.VCPUs == nil {
+			if _, err := VMSizeVCPUs(size.Name); err != nil {
+				allErrs = append(allErrs, field.Required(fldPath.Child("vmSizes").Index(i).Child("vCPUs"),
+					fmt.Sprintf("the number of vCPUs of VM size %s can't be inferred from its name", size.Name)))
+			}
+		}
+		allErrs = append(allErrs, validateNonNegativeQuantity(size.HourlyCost, fldPath.Child("vmSizes").Index(i).Child("hourlyCost"))...)
+	}
+
+	return allErrs
+}
+
+// validatePolicyExemptions validates the policy exemptions of the resource group of a cluster. The policy assignment
+// of an existing exemption can't be changed.
+func validatePolicyExemptions(exemptions, old []PolicyExemption, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	oldAssignments := make(map[string]string, len(old))
+	for _, exemption := range old {
+		oldAssignments[exemption.Name] = exemption.PolicyAssignmentID
+	}
+
+	for i, exemption := range exemptions {
+		if !regexp.MustCompile(policyExemptionNameRegex).MatchString(exemption.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("name"), exemption.Name,
+				"must not contain <>*%&:\\?.+/ nor end with a space"))
+		}
+		if !regexp.MustCompile(policyAssignmentIDRegex).MatchString(exemption.PolicyAssignmentID) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("policyAssignmentID"), exemption.PolicyAssignmentID,
+				"must be the resource ID of a policy assignment"))
+		}
+		if oldID, ok := oldAssignments[exemption.Name]; ok && !strings.EqualFold(oldID, exemption.PolicyAssignmentID) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("policyAssignmentID"), exemption.PolicyAssignmentID,
+				"field is immutable"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateNonNegativeQuantity validates that an optional quantity isn't negative.
+func validateNonNegativeQuantity(q *resource.Quantity, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if q != nil && q.Sign() < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, q.String(), "must not be negative"))
+	}
+	return allErrs
+}
+
+// validateSecurityProfile validates the security settings of a self-managed cluster. The security agent reports to
+// the workspace of the Azure Monitor agent, so Defender requires monitoring.
+func validateSecurityProfile(securityProfile *ClusterSecurityProfile, monitoring *AzureMonitoring, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if securityProfile == nil {
+		return allErrs
+	}
+
+	if securityProfile.Defender != nil {
+		if monitoring == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("defender"), "requires spec.monitoring to be set"))
+		}
+		if securityProfile.Defender.LogAnalyticsWorkspaceID != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("defender", "logAnalyticsWorkspaceID"),
+				"is only supported by managed clusters, set spec.monitoring.logAnalyticsWorkspaceID instead"))
+		}
+	}
+
+	if securityProfile.JustInTimeAccess != nil {
+		allErrs = append(allErrs, validateJustInTimeAccess(*securityProfile.JustInTimeAccess, fldPath.Child("justInTimeAccess"))...)
+	}
+
+	return allErrs
+}
+
+// validateJustInTimeAccess validates the ports of a just-in-time network access policy.
+func validateJustInTimeAccess(jit JustInTimeAccess, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	numbers := make(map[int32]struct{}, len(jit.Ports))
+	for i, port := range jit.Ports {
+		portPath := fldPath.Child("ports").Index(i)
+		if _, ok := numbers[port.Number]; ok {
+			allErrs = append(allErrs, field.Duplicate(portPath.Child("number"), port.Number))
+		}
+		numbers[port.Number] = struct{}{}
+
+		for j, prefix := range port.AllowedSourceAddressPrefixes {
+			if prefix == "*" {
+				continue
+			}
+			if net.ParseIP(prefix) == nil {
+				if _, _, err := net.ParseCIDR(prefix); err != nil {
+					allErrs = append(allErrs, field.Invalid(portPath.Child("allowedSourceAddressPrefixes").Index(j), prefix,
+						"must be an IP address, a CIDR, or *"))
+				}
+			}
+		}
+
+		if port.MaxRequestAccessDuration != nil {
+			duration := port.MaxRequestAccessDuration.Duration
+			if duration < MinJustInTimeAccessMaxRequestAccessDuration || duration > MaxJustInTimeAccessMaxRequestAccessDuration {
+				allErrs = append(allErrs, field.Invalid(portPath.Child("maxRequestAccessDuration"), duration.String(),
+					fmt.Sprintf("must be between %s and %s", MinJustInTimeAccessMaxRequestAccessDuration, MaxJustInTimeAccessMaxRequestAccessDuration)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateExtendedLocation validates the extended location of a cluster, and rejects the resources of the cluster
+// that can't be created in an extended location.
+func validateExtendedLocation(extendedLocation *ExtendedLocationSpec, spec AzureClusterSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if extendedLocation == nil {
+		return allErrs
+	}
+
+	if !feature.Gates.Enabled(feature.EdgeZone) {
+		return append(allErrs, field.Forbidden(fldPath, "can be set only if the EdgeZone feature flag is enabled"))
+	}
+
+	if extendedLocation.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "must be set"))
+	}
+	if extendedLocation.Type != ExtendedLocationTypeEdgeZone {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), extendedLocation.Type, []string{string(ExtendedLocationTypeEdgeZone)}))
+	}
+
+	for i, subnet := range spec.NetworkSpec.Subnets {
+		if subnet.IsNatGatewayEnabled() {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "networkSpec", "subnets").Index(i).Child("natGateway"),
+				"NAT gateways are not supported in extended locations"))
+		}
+	}
+
+	if spec.BastionSpec.AzureBastion != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "bastionSpec", "azureBastion"),
+			"Azure Bastion is not supported in extended locations"))
+	}
+
+	if spec.ReconciliationBackend == ReconciliationBackendASO {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "reconciliationBackend"),
+			"the ASO backend doesn't support extended locations"))
+	}
+
+	return allErrs
+}
@@ -22,12 +22,16 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	webhookutil "sigs.k8s.io/cluster-api-provider-azure/util/webhook"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 // SetupWebhookWithManager sets up and registers the webhook with the manager.
 func (c *AzureCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if err := webhookutil.RegisterValidatingWebhookWithWarnings(mgr, c); err != nil {
+		return err
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
 		Complete()
@@ -75,6 +79,13 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
+	if !reflect.DeepEqual(c.Spec.ExtendedLocation, old.Spec.ExtendedLocation) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "ExtendedLocation"),
+				c.Spec.ExtendedLocation, "field is immutable"),
+		)
+	}
+
 	if old.Spec.ControlPlaneEndpoint.Host != "" && c.Spec.ControlPlaneEndpoint.Host != old.Spec.ControlPlaneEndpoint.Host {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "ControlPlaneEndpoint", "Host"),
@@ -113,6 +124,13 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
+	if !reflect.DeepEqual(c.Spec.NetworkSpec.OutboundType, old.Spec.NetworkSpec.OutboundType) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "networkSpec", "outboundType"),
+				c.Spec.NetworkSpec.OutboundType, "field is immutable"),
+		)
+	}
+
 	// Allow enabling azure bastion but avoid disabling it.
 	if old.Spec.BastionSpec.AzureBastion != nil && !reflect.DeepEqual(old.Spec.BastionSpec.AzureBastion, c.Spec.BastionSpec.AzureBastion) {
 		allErrs = append(allErrs,
@@ -121,6 +139,13 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
+	if !reflect.DeepEqual(c.Spec.ReconciliationBackend, old.Spec.ReconciliationBackend) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "reconciliationBackend"),
+				c.Spec.ReconciliationBackend, "field is immutable"),
+		)
+	}
+
 	if !reflect.DeepEqual(c.Spec.NetworkSpec.ControlPlaneOutboundLB, old.Spec.NetworkSpec.ControlPlaneOutboundLB) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "networkSpec", "controlPlaneOutboundLB"),
@@ -139,3 +164,20 @@ func (c *AzureCluster) ValidateUpdate(oldRaw runtime.Object) error {
 func (c *AzureCluster) ValidateDelete() error {
 	return nil
 }
+
+var _ webhookutil.Warner = &AzureCluster{}
+
+// WarningsOnCreate implements webhookutil.Warner so admission warnings are returned for the type.
+func (c *AzureCluster) WarningsOnCreate() []string {
+	return c.warnings()
+}
+
+// WarningsOnUpdate implements webhookutil.Warner so admission warnings are returned for the type.
+func (c *AzureCluster) WarningsOnUpdate(_ runtime.Object) []string {
+	return c.warnings()
+}
+
+// warnings returns the admission warnings for deprecated fields and risky configurations of the AzureCluster.
+func (c *AzureCluster) warnings() []string {
+	return IdentityRefWarnings(c.Spec.IdentityRef, field.NewPath("spec", "identityRef"))
+}
@@ -306,6 +306,20 @@ func TestAzureCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "reconciliation backend is immutable",
+			oldCluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					ReconciliationBackend: ReconciliationBackendARM,
+				},
+			},
+			cluster: &AzureCluster{
+				Spec: AzureClusterSpec{
+					ReconciliationBackend: ReconciliationBackendASO,
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
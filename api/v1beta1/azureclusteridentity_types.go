@@ -41,6 +41,20 @@ type AllowedNamespaces struct {
 	Selector *metav1.LabelSelector `json:"selector"`
 }
 
+// DeniedNamespaces defines the namespaces the clusters are never allowed to use the identity from, even if they are
+// allowed by AllowedNamespaces. A namespace is denied if it is either in the NamespaceList or matches the Selector.
+type DeniedNamespaces struct {
+	// NamespaceList is a list of namespaces that AzureCluster cannot use this Identity from.
+	//
+	// +optional
+	NamespaceList []string `json:"list,omitempty"`
+	// Selector is a selector of namespaces that AzureCluster cannot use this Identity from.
+	// This is a standard Kubernetes LabelSelector. A nil or empty selector denies no namespace.
+	//
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
 // AzureClusterIdentitySpec defines the parameters that are used to create an AzureIdentity.
 type AzureClusterIdentitySpec struct {
 	// Type is the type of Azure Identity used.
@@ -67,6 +81,12 @@ type AzureClusterIdentitySpec struct {
 	// +optional
 	// +nullable
 	AllowedNamespaces *AllowedNamespaces `json:"allowedNamespaces"`
+	// DeniedNamespaces is used to identify the namespaces the clusters are not allowed to use the identity from.
+	// It takes precedence over AllowedNamespaces, so that a team's namespace can be excluded from an identity
+	// otherwise shared with all the namespaces, or all the namespaces matching a label selector.
+	//
+	// +optional
+	DeniedNamespaces *DeniedNamespaces `json:"deniedNamespaces,omitempty"`
 }
 
 // AzureClusterIdentityStatus defines the observed state of AzureClusterIdentity.
@@ -31,6 +31,9 @@ func (c *AzureClusterTemplate) setNetworkTemplateSpecDefaults() {
 	c.setSubnetsTemplateDefaults()
 
 	apiServerLB := &c.Spec.Template.Spec.NetworkSpec.APIServerLB
+	if apiServerLB.Type == "" && c.Spec.Template.Spec.NetworkSpec.IsUserDefinedRouting() {
+		apiServerLB.Type = Internal
+	}
 	apiServerLB.setAPIServerLBDefaults()
 	c.setNodeOutboundLBDefaults()
 	c.setControlPlaneOutboundLBDefaults()
@@ -71,6 +71,11 @@ func (c *AzureClusterTemplate) validateClusterTemplateSpec() field.ErrorList {
 
 	allErrs = append(allErrs, c.validatePrivateDNSZoneName()...)
 
+	allErrs = append(allErrs, c.validateOutboundType()...)
+
+	allErrs = append(allErrs, validateCloudProviderIdentity(c.Spec.Template.Spec.CloudProviderIdentity,
+		field.NewPath("spec").Child("template").Child("spec").Child("cloudProviderIdentity"))...)
+
 	return allErrs
 }
 
@@ -151,3 +156,26 @@ func (c *AzureClusterTemplate) validatePrivateDNSZoneName() field.ErrorList {
 
 	return allErrs
 }
+
+func (c *AzureClusterTemplate) validateOutboundType() field.ErrorList {
+	var allErrs field.ErrorList
+
+	fldPath := field.NewPath("spec").Child("template").Child("spec").Child("networkSpec")
+	networkSpec := c.Spec.Template.Spec.NetworkSpec
+
+	var natGatewaySubnets []int
+	for i, subnet := range networkSpec.Subnets {
+		if subnet.IsNatGatewayEnabled() {
+			natGatewaySubnets = append(natGatewaySubnets, i)
+		}
+	}
+	allErrs = append(allErrs, validateOutboundType(networkSpec.OutboundType, networkSpec.APIServerLB.Type,
+		networkSpec.NodeOutboundLB != nil, networkSpec.ControlPlaneOutboundLB != nil, natGatewaySubnets, fldPath)...)
+
+	if networkSpec.IsUserDefinedRouting() && c.Spec.Template.Spec.BastionSpec.AzureBastion != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("template").Child("spec").Child("bastionSpec").Child("azureBastion"),
+			"Azure Bastion requires a public IP and cannot be used when outboundType is UserDefinedRouting"))
+	}
+
+	return allErrs
+}
@@ -19,7 +19,7 @@ package v1beta1
 import (
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -361,7 +361,7 @@ func TestValidateAPIServerLBTemplate(t *testing.T) {
 								APIServerLB: LoadBalancerClassSpec{
 									SKU:                  SKUStandard,
 									Type:                 Public,
-									IdleTimeoutInMinutes: to.Int32Ptr(DefaultOutboundRuleIdleTimeoutInMinutes),
+									IdleTimeoutInMinutes: to.Ptr[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
 								},
 							},
 						},
@@ -383,7 +383,7 @@ func TestValidateAPIServerLBTemplate(t *testing.T) {
 								APIServerLB: LoadBalancerClassSpec{
 									SKU:                  SKU("wrong"),
 									Type:                 Public,
-									IdleTimeoutInMinutes: to.Int32Ptr(DefaultOutboundRuleIdleTimeoutInMinutes),
+									IdleTimeoutInMinutes: to.Ptr[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
 								},
 							},
 						},
@@ -405,7 +405,7 @@ func TestValidateAPIServerLBTemplate(t *testing.T) {
 								APIServerLB: LoadBalancerClassSpec{
 									SKU:                  SKUStandard,
 									Type:                 LBType("wrong"),
-									IdleTimeoutInMinutes: to.Int32Ptr(DefaultOutboundRuleIdleTimeoutInMinutes),
+									IdleTimeoutInMinutes: to.Ptr[int32](DefaultOutboundRuleIdleTimeoutInMinutes),
 								},
 							},
 						},
@@ -495,7 +495,7 @@ func TestControlPlaneOutboundLBTemplate(t *testing.T) {
 									Type: Internal,
 								},
 								ControlPlaneOutboundLB: &LoadBalancerClassSpec{
-									IdleTimeoutInMinutes: to.Int32Ptr(2),
+									IdleTimeoutInMinutes: to.Ptr[int32](2),
 								},
 							},
 						},
@@ -518,7 +518,7 @@ func TestControlPlaneOutboundLBTemplate(t *testing.T) {
 									Type: Internal,
 								},
 								ControlPlaneOutboundLB: &LoadBalancerClassSpec{
-									IdleTimeoutInMinutes: to.Int32Ptr(60),
+									IdleTimeoutInMinutes: to.Ptr[int32](60),
 								},
 							},
 						},
@@ -625,7 +625,7 @@ func TestNodeOutboundLBTemplate(t *testing.T) {
 									Type: Public,
 								},
 								NodeOutboundLB: &LoadBalancerClassSpec{
-									IdleTimeoutInMinutes: to.Int32Ptr(2),
+									IdleTimeoutInMinutes: to.Ptr[int32](2),
 								},
 							},
 						},
@@ -648,7 +648,7 @@ func TestNodeOutboundLBTemplate(t *testing.T) {
 									Type: Public,
 								},
 								NodeOutboundLB: &LoadBalancerClassSpec{
-									IdleTimeoutInMinutes: to.Int32Ptr(60),
+									IdleTimeoutInMinutes: to.Ptr[int32](60),
 								},
 							},
 						},
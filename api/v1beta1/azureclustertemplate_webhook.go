@@ -22,6 +22,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	webhookutil "sigs.k8s.io/cluster-api-provider-azure/util/webhook"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -30,6 +31,9 @@ import (
 const AzureClusterTemplateImmutableMsg = "AzureClusterTemplate spec.template.spec field is immutable. Please create new resource instead. ref doc: https://cluster-api.sigs.k8s.io/tasks/experimental-features/cluster-class/change-clusterclass.html"
 
 func (c *AzureClusterTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if err := webhookutil.RegisterValidatingWebhookWithWarnings(mgr, c); err != nil {
+		return err
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
 		Complete()
@@ -72,3 +76,20 @@ func (c *AzureClusterTemplate) ValidateUpdate(oldRaw runtime.Object) error {
 func (c *AzureClusterTemplate) ValidateDelete() error {
 	return nil
 }
+
+var _ webhookutil.Warner = &AzureClusterTemplate{}
+
+// WarningsOnCreate implements webhookutil.Warner so admission warnings are returned for the type.
+func (c *AzureClusterTemplate) WarningsOnCreate() []string {
+	return c.warnings()
+}
+
+// WarningsOnUpdate implements webhookutil.Warner so admission warnings are returned for the type.
+func (c *AzureClusterTemplate) WarningsOnUpdate(_ runtime.Object) []string {
+	return c.warnings()
+}
+
+// warnings returns the admission warnings for deprecated fields and risky configurations of the AzureClusterTemplate.
+func (c *AzureClusterTemplate) warnings() []string {
+	return IdentityRefWarnings(c.Spec.Template.Spec.IdentityRef, field.NewPath("spec", "template", "spec", "identityRef"))
+}
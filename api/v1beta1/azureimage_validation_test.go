@@ -19,7 +19,7 @@ package v1beta1
 import (
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -74,15 +74,15 @@ func TestComputeImageGalleryValid(t *testing.T) {
 		},
 		"AzureComputeGalleryImage - fully specified private image": {
 			expectedErrors: 0,
-			image:          createTestComputeImage(to.StringPtr("SUB1234"), to.StringPtr("RG1234")),
+			image:          createTestComputeImage(to.Ptr("SUB1234"), to.Ptr("RG1234")),
 		},
 		"AzureComputeGalleryImage - private image with missing subscription": {
 			expectedErrors: 1,
-			image:          createTestComputeImage(nil, to.StringPtr("RG1234")),
+			image:          createTestComputeImage(nil, to.Ptr("RG1234")),
 		},
 		"AzureComputeGalleryImage - private image with missing resource group": {
 			expectedErrors: 1,
-			image:          createTestComputeImage(to.StringPtr("SUB1234"), nil),
+			image:          createTestComputeImage(to.Ptr("SUB1234"), nil),
 		},
 	}
 
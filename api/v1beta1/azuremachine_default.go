@@ -17,28 +17,10 @@ limitations under the License.
 package v1beta1
 
 import (
-	"encoding/base64"
-
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"golang.org/x/crypto/ssh"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"k8s.io/apimachinery/pkg/util/uuid"
-	utilSSH "sigs.k8s.io/cluster-api-provider-azure/util/ssh"
-	ctrl "sigs.k8s.io/controller-runtime"
 )
 
-// SetDefaultSSHPublicKey sets the default SSHPublicKey for an AzureMachine.
-func (s *AzureMachineSpec) SetDefaultSSHPublicKey() error {
-	if sshKeyData := s.SSHPublicKey; sshKeyData == "" {
-		_, publicRsaKey, err := utilSSH.GenerateSSHKey()
-		if err != nil {
-			return err
-		}
-
-		s.SSHPublicKey = base64.StdEncoding.EncodeToString(ssh.MarshalAuthorizedKey(publicRsaKey))
-	}
-	return nil
-}
-
 // SetDefaultCachingType sets the default cache type for an AzureMachine.
 func (s *AzureMachineSpec) SetDefaultCachingType() {
 	if s.OSDisk.CachingType == "" {
@@ -69,15 +51,31 @@ func (s *AzureMachineSpec) SetDataDisksDefaults() {
 		}
 		if disk.CachingType == "" {
 			if s.DataDisks[i].ManagedDisk != nil &&
-				s.DataDisks[i].ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) {
-				s.DataDisks[i].CachingType = string(compute.CachingTypesNone)
+				s.DataDisks[i].ManagedDisk.StorageAccountType == string(armcompute.StorageAccountTypesUltraSSDLRS) {
+				s.DataDisks[i].CachingType = string(armcompute.CachingTypesNone)
+			} else if s.DataDisks[i].WriteAcceleratorEnabled != nil && *s.DataDisks[i].WriteAcceleratorEnabled {
+				// write accelerator doesn't support write caching
+				s.DataDisks[i].CachingType = string(armcompute.CachingTypesNone)
 			} else {
-				s.DataDisks[i].CachingType = string(compute.CachingTypesReadWrite)
+				s.DataDisks[i].CachingType = string(armcompute.CachingTypesReadWrite)
 			}
 		}
 	}
 }
 
+// SetEtcdDataDiskDefaults adds the data disk dedicated to etcd to the data disks of an AzureMachine.
+func (s *AzureMachineSpec) SetEtcdDataDiskDefaults() {
+	if s.EtcdDataDisk == nil {
+		return
+	}
+	for _, disk := range s.DataDisks {
+		if disk.NameSuffix == EtcdDataDiskNameSuffix {
+			return
+		}
+	}
+	s.DataDisks = append(s.DataDisks, s.EtcdDataDisk.DataDisk())
+}
+
 // SetIdentityDefaults sets the defaults for VM Identity.
 func (s *AzureMachineSpec) SetIdentityDefaults() {
 	if s.Identity == VMIdentitySystemAssigned {
@@ -89,10 +87,8 @@ func (s *AzureMachineSpec) SetIdentityDefaults() {
 
 // SetDefaults sets to the defaults for the AzureMachineSpec.
 func (s *AzureMachineSpec) SetDefaults() {
-	if err := s.SetDefaultSSHPublicKey(); err != nil {
-		ctrl.Log.WithName("SetDefault").Error(err, "SetDefaultSshPublicKey failed")
-	}
 	s.SetDefaultCachingType()
+	s.SetEtcdDataDiskDefaults()
 	s.SetDataDisksDefaults()
 	s.SetIdentityDefaults()
 }
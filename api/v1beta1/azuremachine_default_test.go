@@ -21,31 +21,11 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/google/uuid"
 	. "github.com/onsi/gomega"
 )
 
-func TestAzureMachineSpec_SetDefaultSSHPublicKey(t *testing.T) {
-	g := NewWithT(t)
-
-	type test struct {
-		machine *AzureMachine
-	}
-
-	existingPublicKey := "testpublickey"
-	publicKeyExistTest := test{machine: createMachineWithSSHPublicKey(existingPublicKey)}
-	publicKeyNotExistTest := test{machine: createMachineWithSSHPublicKey("")}
-
-	err := publicKeyExistTest.machine.Spec.SetDefaultSSHPublicKey()
-	g.Expect(err).To(BeNil())
-	g.Expect(publicKeyExistTest.machine.Spec.SSHPublicKey).To(Equal(existingPublicKey))
-
-	err = publicKeyNotExistTest.machine.Spec.SetDefaultSSHPublicKey()
-	g.Expect(err).To(BeNil())
-	g.Expect(publicKeyNotExistTest.machine.Spec.SSHPublicKey).To(Not(BeEmpty()))
-}
-
 func TestAzureMachineSpec_SetIdentityDefaults(t *testing.T) {
 	g := NewWithT(t)
 
@@ -107,13 +87,13 @@ func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 				{
 					NameSuffix:  "testdisk1",
 					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(0),
+					Lun:         to.Ptr[int32](0),
 					CachingType: "ReadWrite",
 				},
 				{
 					NameSuffix:  "testdisk2",
 					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(1),
+					Lun:         to.Ptr[int32](1),
 					CachingType: "ReadWrite",
 				},
 			},
@@ -124,13 +104,13 @@ func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 				{
 					NameSuffix:  "testdisk1",
 					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(5),
+					Lun:         to.Ptr[int32](5),
 					CachingType: "ReadWrite",
 				},
 				{
 					NameSuffix:  "testdisk2",
 					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(3),
+					Lun:         to.Ptr[int32](3),
 					CachingType: "ReadWrite",
 				},
 			},
@@ -138,13 +118,13 @@ func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 				{
 					NameSuffix:  "testdisk1",
 					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(5),
+					Lun:         to.Ptr[int32](5),
 					CachingType: "ReadWrite",
 				},
 				{
 					NameSuffix:  "testdisk2",
 					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(3),
+					Lun:         to.Ptr[int32](3),
 					CachingType: "ReadWrite",
 				},
 			},
@@ -155,7 +135,7 @@ func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 				{
 					NameSuffix:  "testdisk1",
 					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(0),
+					Lun:         to.Ptr[int32](0),
 					CachingType: "ReadWrite",
 				},
 				{
@@ -166,7 +146,7 @@ func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 				{
 					NameSuffix:  "testdisk3",
 					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(1),
+					Lun:         to.Ptr[int32](1),
 					CachingType: "ReadWrite",
 				},
 				{
@@ -179,25 +159,25 @@ func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 				{
 					NameSuffix:  "testdisk1",
 					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(0),
+					Lun:         to.Ptr[int32](0),
 					CachingType: "ReadWrite",
 				},
 				{
 					NameSuffix:  "testdisk2",
 					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(2),
+					Lun:         to.Ptr[int32](2),
 					CachingType: "ReadWrite",
 				},
 				{
 					NameSuffix:  "testdisk3",
 					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(1),
+					Lun:         to.Ptr[int32](1),
 					CachingType: "ReadWrite",
 				},
 				{
 					NameSuffix:  "testdisk4",
 					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(3),
+					Lun:         to.Ptr[int32](3),
 					CachingType: "ReadWrite",
 				},
 			},
@@ -208,12 +188,12 @@ func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 				{
 					NameSuffix: "testdisk1",
 					DiskSizeGB: 30,
-					Lun:        to.Int32Ptr(0),
+					Lun:        to.Ptr[int32](0),
 				},
 				{
 					NameSuffix: "testdisk2",
 					DiskSizeGB: 30,
-					Lun:        to.Int32Ptr(2),
+					Lun:        to.Ptr[int32](2),
 				},
 				{
 					NameSuffix: "testdisk3",
@@ -221,31 +201,50 @@ func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 					ManagedDisk: &ManagedDiskParameters{
 						StorageAccountType: "UltraSSD_LRS",
 					},
-					Lun: to.Int32Ptr(3),
+					Lun: to.Ptr[int32](3),
+				},
+				{
+					NameSuffix: "testdisk4",
+					DiskSizeGB: 30,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+					Lun:                     to.Ptr[int32](4),
+					WriteAcceleratorEnabled: to.Ptr(true),
 				},
 			},
 			output: []DataDisk{
 				{
 					NameSuffix:  "testdisk1",
 					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(0),
+					Lun:         to.Ptr[int32](0),
 					CachingType: "ReadWrite",
 				},
 				{
 					NameSuffix:  "testdisk2",
 					DiskSizeGB:  30,
-					Lun:         to.Int32Ptr(2),
+					Lun:         to.Ptr[int32](2),
 					CachingType: "ReadWrite",
 				},
 				{
 					NameSuffix: "testdisk3",
 					DiskSizeGB: 30,
-					Lun:        to.Int32Ptr(3),
+					Lun:        to.Ptr[int32](3),
 					ManagedDisk: &ManagedDiskParameters{
 						StorageAccountType: "UltraSSD_LRS",
 					},
 					CachingType: "None",
 				},
+				{
+					NameSuffix: "testdisk4",
+					DiskSizeGB: 30,
+					Lun:        to.Ptr[int32](4),
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+					CachingType:             "None",
+					WriteAcceleratorEnabled: to.Ptr(true),
+				},
 			},
 		},
 	}
@@ -266,6 +265,48 @@ func TestAzureMachineSpec_SetDataDisksDefaults(t *testing.T) {
 	}
 }
 
+func TestAzureMachineSpec_SetEtcdDataDiskDefaults(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := AzureMachineSpec{
+		EtcdDataDisk: &EtcdDataDisk{},
+		DataDisks:    []DataDisk{{NameSuffix: "data", DiskSizeGB: 64, Lun: to.Ptr[int32](1)}},
+	}
+	spec.SetEtcdDataDiskDefaults()
+	g.Expect(spec.DataDisks).To(Equal([]DataDisk{
+		{NameSuffix: "data", DiskSizeGB: 64, Lun: to.Ptr[int32](1)},
+		{
+			NameSuffix:  "etcddisk",
+			DiskSizeGB:  256,
+			ManagedDisk: &ManagedDiskParameters{StorageAccountType: "Premium_LRS"},
+			Lun:         to.Ptr[int32](0),
+			CachingType: "None",
+		},
+	}))
+
+	// the etcd data disk is only added once
+	spec.SetEtcdDataDiskDefaults()
+	g.Expect(spec.DataDisks).To(HaveLen(2))
+
+	spec = AzureMachineSpec{
+		EtcdDataDisk: &EtcdDataDisk{DiskSizeGB: 512, StorageAccountType: "Premium_ZRS", Lun: to.Ptr[int32](2)},
+	}
+	spec.SetEtcdDataDiskDefaults()
+	g.Expect(spec.DataDisks).To(Equal([]DataDisk{
+		{
+			NameSuffix:  "etcddisk",
+			DiskSizeGB:  512,
+			ManagedDisk: &ManagedDiskParameters{StorageAccountType: "Premium_ZRS"},
+			Lun:         to.Ptr[int32](2),
+			CachingType: "None",
+		},
+	}))
+
+	spec = AzureMachineSpec{}
+	spec.SetEtcdDataDiskDefaults()
+	g.Expect(spec.DataDisks).To(BeEmpty())
+}
+
 func createMachineWithSSHPublicKey(sshPublicKey string) *AzureMachine {
 	machine := hardcodedAzureMachineWithSSHKey(sshPublicKey)
 	return machine
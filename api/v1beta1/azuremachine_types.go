@@ -28,6 +28,36 @@ const (
 	// MachineFinalizer allows ReconcileAzureMachine to clean up Azure resources associated with AzureMachine before
 	// removing it from the apiserver.
 	MachineFinalizer = "azuremachine.infrastructure.cluster.x-k8s.io"
+
+	// HibernatedAnnotation is set on an AzureMachine whose VM was deallocated because its cluster is hibernated, so
+	// that the VM is started again when the cluster resumes.
+	HibernatedAnnotation = "azuremachine.infrastructure.cluster.x-k8s.io/hibernated"
+
+	// DiskSnapshotRequestAnnotation requests snapshots of the disks of an AzureMachine. Snapshots are taken each time
+	// it is set to a new value, e.g. the current time, even when the AzureMachine doesn't schedule snapshots.
+	DiskSnapshotRequestAnnotation = "azuremachine.infrastructure.cluster.x-k8s.io/disk-snapshot-request"
+
+	// MachineARMTemplateExportRequestAnnotation requests an export of the desired state of the Azure resources of the
+	// AzureMachine as an ARM template, in the ConfigMap named after the AzureMachine with the ARMTemplateSuffix.
+	// The template is exported each time it is set to a new value, e.g. the current time.
+	MachineARMTemplateExportRequestAnnotation = "azuremachine.infrastructure.cluster.x-k8s.io/arm-template-export-request"
+
+	// DefaultDiskSnapshotRetain is the number of snapshots kept for each disk when not specified.
+	DefaultDiskSnapshotRetain int32 = 3
+
+	// DefaultBackupPolicyName is the backup policy virtual machines are enrolled in when not specified.
+	DefaultBackupPolicyName = "DefaultPolicy"
+
+	// EtcdDataDiskNameSuffix is the name suffix of the data disk dedicated to etcd.
+	EtcdDataDiskNameSuffix = "etcddisk"
+
+	// DefaultEtcdDataDiskSizeGB is the size of the etcd data disk when not specified. A 256 GB Premium SSD disk (P15)
+	// provides 1100 IOPS.
+	DefaultEtcdDataDiskSizeGB int32 = 256
+
+	// EtcdDiskMinIOPS is the sequential IOPS etcd recommends for heavily loaded clusters.
+	// See https://etcd.io/docs/v3.5/op-guide/hardware/#disks.
+	EtcdDiskMinIOPS int32 = 500
 )
 
 // AzureMachineSpec defines the desired state of AzureMachine.
@@ -78,7 +108,21 @@ type AzureMachineSpec struct {
 	// +optional
 	DataDisks []DataDisk `json:"dataDisks,omitempty"`
 
-	SSHPublicKey string `json:"sshPublicKey"`
+	// DiskControllerType specifies the disk controller used to attach the OS and data disks of the machine.
+	// NVMe requires a VM size that supports it and a HyperVGeneration V2 image, which is selected automatically
+	// for default images. If not specified, Azure uses the default controller of the VM size.
+	// +optional
+	DiskControllerType DiskControllerType `json:"diskControllerType,omitempty"`
+
+	// SSHPublicKey is the SSH public key string base64 encoded to add to the virtual machine. If omitted, the public key
+	// of the SSH key pair generated for the cluster is added, whose private key is stored in the <cluster-name>-ssh Secret.
+	// +optional
+	SSHPublicKey string `json:"sshPublicKey,omitempty"`
+
+	// AdditionalSSHPublicKeys are SSH public key strings base64 encoded to authorize on the virtual machine in addition
+	// to SSHPublicKey, e.g. the keys of the members of a team.
+	// +optional
+	AdditionalSSHPublicKeys []string `json:"additionalSSHPublicKeys,omitempty"`
 
 	// AdditionalTags is an optional set of tags to add to an instance, in addition to the ones added by default by the
 	// Azure provider. If both the AzureCluster and the AzureMachine specify the same tag name with different values, the
@@ -120,6 +164,223 @@ type AzureMachineSpec struct {
 	SubnetName string `json:"subnetName,omitempty"`
 
 	NetworkInterfaces []AzureNetworkInterface `json:"networkInterfaces,omitempty"`
+
+	// UserData is passed to the virtual machine through its userData property, separately from the bootstrap data
+	// passed as customData. It can be read from the Azure Instance Metadata Service and is not part of the bootstrap secret.
+	// +optional
+	UserData *UserData `json:"userData,omitempty"`
+
+	// DesiredPowerState is the power state the virtual machine should be kept in. When set to Running, a virtual
+	// machine found stopped, deallocated or hibernated, for example from the Azure portal, is started again. When set
+	// to Hibernated, the virtual machine is hibernated: it is deallocated with its memory state saved to its OS disk,
+	// and resumes with it when set back to Running. Hibernated requires additionalCapabilities.hibernationEnabled.
+	// +kubebuilder:validation:Enum=Running;Hibernated
+	// +optional
+	DesiredPowerState PowerState `json:"desiredPowerState,omitempty"`
+
+	// MultiInstanceGPU partitions the GPUs of the virtual machine into GPU instances every time it boots, through a
+	// systemd unit written by cloud-init merged with the bootstrap data. It requires a Linux VM size with MIG capable
+	// GPUs and the NVIDIA driver in the image.
+	// +optional
+	MultiInstanceGPU *MultiInstanceGPU `json:"multiInstanceGPU,omitempty"`
+
+	// LocalStorage formats and mounts the local temp or NVMe disks of the virtual machine before it is bootstrapped,
+	// through cloud-init merged with the bootstrap data. It is only supported on Linux machines bootstrapped with cloud-init.
+	// +optional
+	LocalStorage *LocalStorage `json:"localStorage,omitempty"`
+
+	// FileStorage installs the packages and loads the kernel modules the Azure Files and Azure Blob storage CSI drivers
+	// need to mount volumes, through cloud-init merged with the bootstrap data. It is only supported on Linux machines
+	// bootstrapped with cloud-init.
+	// +optional
+	FileStorage *FileStorage `json:"fileStorage,omitempty"`
+
+	// CACertificates are additional CA certificates added to the trust store of the virtual machine before it is
+	// bootstrapped, through cloud-init merged with the bootstrap data, e.g. for TLS-intercepting proxies or private
+	// registries. It is only supported on Linux machines bootstrapped with cloud-init.
+	// +optional
+	CACertificates *CACertificates `json:"caCertificates,omitempty"`
+
+	// DiskSnapshots takes snapshots of the disks of the virtual machine on a schedule. A machine can be recovered from a
+	// snapshot of the OS disk of another one with osDisk.sourceSnapshotID.
+	// +optional
+	DiskSnapshots *DiskSnapshots `json:"diskSnapshots,omitempty"`
+
+	// BackupProtection enrolls the virtual machine in a backup policy of a Recovery Services vault, e.g. to take
+	// VM-level backups of the etcd members of the control plane.
+	// +optional
+	BackupProtection *BackupProtection `json:"backupProtection,omitempty"`
+
+	// EtcdDataDisk creates a data disk dedicated to etcd, named <machineName>_etcddisk, on a control plane machine. The
+	// disk is added to the data disks of the machine, and its size and storage type are validated against the IOPS
+	// etcd needs.
+	// +optional
+	EtcdDataDisk *EtcdDataDisk `json:"etcdDataDisk,omitempty"`
+
+	// BackendPoolDrainTimeout is how long the network interfaces of the machine are kept out of the load balancer
+	// backend pools before the virtual machine is deleted, so that connections through the API server or node load
+	// balancers can drain rather than being reset, e.g. "30s". If omitted, the machine is deleted without draining.
+	// +optional
+	BackendPoolDrainTimeout *metav1.Duration `json:"backendPoolDrainTimeout,omitempty"`
+
+	// TombstonePolicy deletes the virtual machine in two phases: it is first tagged as tombstoned, and optionally
+	// deallocated, and only deleted once a grace period has passed, giving operators a window to recover from an
+	// accidental scale-down or deletion. If omitted, the virtual machine is deleted right away.
+	// +optional
+	TombstonePolicy *TombstonePolicy `json:"tombstonePolicy,omitempty"`
+
+	// DerivedResources overrides the names and tags of the resources created along with the virtual machine: its
+	// network interfaces, its OS disk and its public IP, e.g. to tag its disks for chargeback differently than the
+	// virtual machine. By default, they are named after the machine and tagged with the additional tags of the machine.
+	// +optional
+	DerivedResources *DerivedResources `json:"derivedResources,omitempty"`
+}
+
+// DerivedResources configures the names and tags of the resources created along with a virtual machine.
+type DerivedResources struct {
+	// NetworkInterface overrides the name and tags of the network interfaces of the machine. The index of the
+	// interface is still appended to the names of the interfaces of a machine with several networkInterfaces.
+	// +optional
+	NetworkInterface *DerivedResource `json:"networkInterface,omitempty"`
+
+	// OSDisk overrides the name and tags of the managed OS disk of the machine. An ephemeral OS disk is not a
+	// resource of its own, so it has no name nor tags.
+	// +optional
+	OSDisk *DerivedResource `json:"osDisk,omitempty"`
+
+	// PublicIP overrides the name and tags of the public IP of a machine with allocatePublicIP.
+	// +optional
+	PublicIP *DerivedResource `json:"publicIP,omitempty"`
+}
+
+// DerivedResource configures the name and tags of a resource created along with a virtual machine.
+type DerivedResource struct {
+	// NamePrefix replaces the default prefix of the name of the resource, which is made of the name of the machine
+	// between a prefix and a suffix. The defaults are "" and "-nic" for network interfaces, "" and "_OSDisk" for OS
+	// disks, and "pip-" and "" for public IPs. Setting either the prefix or the suffix replaces both defaults.
+	// +kubebuilder:validation:MaxLength=20
+	// +kubebuilder:validation:Pattern=`^[-\w\.]*$`
+	// +optional
+	NamePrefix string `json:"namePrefix,omitempty"`
+
+	// NameSuffix replaces the default suffix of the name of the resource.
+	// +kubebuilder:validation:MaxLength=20
+	// +kubebuilder:validation:Pattern=`^[-\w\.]*$`
+	// +optional
+	NameSuffix string `json:"nameSuffix,omitempty"`
+
+	// AdditionalTags is an optional set of tags to add to the resource, on top of the additional tags of the cluster
+	// and of the machine. A tag set here overrides a tag with the same key of the cluster or of the machine.
+	// +optional
+	AdditionalTags Tags `json:"additionalTags,omitempty"`
+}
+
+// TombstonePolicy configures the two-phase deletion of a virtual machine.
+type TombstonePolicy struct {
+	// GracePeriod is how long the virtual machine is kept after it is tombstoned, before it is deleted, e.g. "24h".
+	GracePeriod metav1.Duration `json:"gracePeriod"`
+
+	// Deallocate deallocates the virtual machine while it is tombstoned, so that it stops incurring compute charges.
+	// Its disks and network interfaces are kept.
+	// +optional
+	Deallocate bool `json:"deallocate,omitempty"`
+}
+
+// DiskSnapshots configures the snapshots of the disks of a virtual machine.
+type DiskSnapshots struct {
+	// Interval is the time between two scheduled snapshots, e.g. "24h". Snapshots are only taken when requested with
+	// the DiskSnapshotRequestAnnotation if not set.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// IncludeDataDisks takes snapshots of the data disks of the virtual machine along with its OS disk.
+	// +optional
+	IncludeDataDisks bool `json:"includeDataDisks,omitempty"`
+
+	// Retain is the number of snapshots kept for each disk, the oldest ones being deleted first. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Retain int32 `json:"retain,omitempty"`
+}
+
+// GetRetain returns the number of snapshots kept for each disk.
+func (s *DiskSnapshots) GetRetain() int32 {
+	if s == nil || s.Retain == 0 {
+		return DefaultDiskSnapshotRetain
+	}
+	return s.Retain
+}
+
+// BackupProtection configures the Azure Backup protection of a virtual machine.
+type BackupProtection struct {
+	// VaultID is the resource ID of the Recovery Services vault backing up the virtual machine. The vault must be in the
+	// same subscription and location as the virtual machine.
+	VaultID string `json:"vaultID"`
+
+	// PolicyName is the name of the backup policy of the vault the virtual machine is enrolled in. Defaults to
+	// DefaultPolicy, the policy created with every vault.
+	// +optional
+	PolicyName string `json:"policyName,omitempty"`
+}
+
+// GetPolicyName returns the name of the backup policy the virtual machine is enrolled in.
+func (b *BackupProtection) GetPolicyName() string {
+	if b.PolicyName == "" {
+		return DefaultBackupPolicyName
+	}
+	return b.PolicyName
+}
+
+// EtcdDataDisk configures the data disk dedicated to etcd.
+type EtcdDataDisk struct {
+	// DiskSizeGB is the size of the disk in GB. Defaults to 256. Premium SSD disks provide more IOPS as they grow, and
+	// must be at least 128 GB to provide the IOPS etcd needs.
+	// +optional
+	DiskSizeGB int32 `json:"diskSizeGB,omitempty"`
+
+	// StorageAccountType is the storage type of the disk. Defaults to Premium_LRS. Standard HDD disks can't meet the
+	// latency requirements of etcd and are not supported.
+	// +kubebuilder:validation:Enum=Premium_LRS;Premium_ZRS;StandardSSD_LRS;StandardSSD_ZRS
+	// +optional
+	StorageAccountType string `json:"storageAccountType,omitempty"`
+
+	// Lun is the logical unit number of the disk, which the bootstrap configuration uses to find it, e.g.
+	// /dev/disk/azure/scsi1/lun0. Defaults to 0.
+	// +optional
+	Lun *int32 `json:"lun,omitempty"`
+}
+
+// GetDiskSizeGB returns the size of the etcd data disk in GB.
+func (d *EtcdDataDisk) GetDiskSizeGB() int32 {
+	if d.DiskSizeGB == 0 {
+		return DefaultEtcdDataDiskSizeGB
+	}
+	return d.DiskSizeGB
+}
+
+// GetStorageAccountType returns the storage type of the etcd data disk.
+func (d *EtcdDataDisk) GetStorageAccountType() string {
+	if d.StorageAccountType == "" {
+		return "Premium_LRS"
+	}
+	return d.StorageAccountType
+}
+
+// DataDisk returns the data disk dedicated to etcd. Its host caching is disabled, as etcd syncs every write to disk.
+func (d *EtcdDataDisk) DataDisk() DataDisk {
+	lun := int32(0)
+	if d.Lun != nil {
+		lun = *d.Lun
+	}
+	return DataDisk{
+		NameSuffix: EtcdDataDiskNameSuffix,
+		DiskSizeGB: d.GetDiskSizeGB(),
+		ManagedDisk: &ManagedDiskParameters{
+			StorageAccountType: d.GetStorageAccountType(),
+		},
+		Lun:         &lun,
+		CachingType: "None",
+	}
 }
 
 // SpotVMOptions defines the options relevant to running the Machine on Spot VMs.
@@ -143,6 +404,33 @@ type AzureMachineStatus struct {
 	// +optional
 	VMState *ProvisioningState `json:"vmState,omitempty"`
 
+	// PowerState is the power state of the Azure virtual machine, as reported by its instance view.
+	// +optional
+	PowerState PowerState `json:"powerState,omitempty"`
+
+	// Placement is where Azure placed the virtual machine: its fault and update domains, as reported by its instance
+	// view, its availability zone, dedicated host and proximity placement group.
+	// +optional
+	Placement *VMPlacement `json:"placement,omitempty"`
+
+	// Image is the default reference image resolved for this machine when spec.image is not set.
+	// It is resolved from the Kubernetes version and OS of the machine only once, and reused afterwards.
+	// +optional
+	Image *Image `json:"image,omitempty"`
+
+	// LastDiskSnapshotTime is the time the last snapshots of the disks of the virtual machine were taken.
+	// +optional
+	LastDiskSnapshotTime *metav1.Time `json:"lastDiskSnapshotTime,omitempty"`
+
+	// LastDiskSnapshotRequest is the value of the DiskSnapshotRequestAnnotation the last requested snapshots were taken for.
+	// +optional
+	LastDiskSnapshotRequest string `json:"lastDiskSnapshotRequest,omitempty"`
+
+	// LastARMTemplateExportRequest is the value of the MachineARMTemplateExportRequestAnnotation the last ARM template
+	// was exported for.
+	// +optional
+	LastARMTemplateExportRequest string `json:"lastARMTemplateExportRequest,omitempty"`
+
 	// ErrorReason will be set in the event that there is a terminal problem
 	// reconciling the Machine and will contain a succinct value suitable
 	// for machine interpretation.
@@ -189,6 +477,10 @@ type AzureMachineStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates Futures `json:"longRunningOperationStates,omitempty"`
+
+	// Resources records the Azure resources created or updated for this AzureMachine, one entry per ARM ID.
+	// +optional
+	Resources ResourceStatuses `json:"resources,omitempty"`
 }
 
 // AdditionalCapabilities enables or disables a capability on the virtual machine.
@@ -198,6 +490,11 @@ type AdditionalCapabilities struct {
 	// otherwise it doesn't set the capability on the VM.
 	// +optional
 	UltraSSDEnabled *bool `json:"ultraSSDEnabled,omitempty"`
+
+	// HibernationEnabled enables or disables the hibernation capability of the virtual machines, which is required
+	// to hibernate them. It requires a VM size that supports hibernation.
+	// +optional
+	HibernationEnabled *bool `json:"hibernationEnabled,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -251,6 +548,16 @@ func (m *AzureMachine) SetFutures(futures Futures) {
 	m.Status.LongRunningOperationStates = futures
 }
 
+// GetResourceStatuses returns the list of Azure resources recorded for an AzureMachine API object.
+func (m *AzureMachine) GetResourceStatuses() ResourceStatuses {
+	return m.Status.Resources
+}
+
+// SetResourceStatuses will set the given Azure resources on an AzureMachine object.
+func (m *AzureMachine) SetResourceStatuses(resources ResourceStatuses) {
+	m.Status.Resources = resources
+}
+
 func init() {
 	SchemeBuilder.Register(&AzureMachine{}, &AzureMachineList{})
 }
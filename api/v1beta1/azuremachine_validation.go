@@ -19,13 +19,31 @@ package v1beta1
 import (
 	"encoding/base64"
 	"fmt"
-
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"net"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
+// minDiskSnapshotInterval is the minimum interval between two scheduled snapshots of the disks of a virtual machine.
+const minDiskSnapshotInterval = time.Hour
+
+// maxBackendPoolDrainTimeout is the longest a machine can be kept out of the load balancer backend pools before it is
+// deleted.
+const maxBackendPoolDrainTimeout = 30 * time.Minute
+
+// maxTombstoneGracePeriod is the longest a tombstoned virtual machine can be kept before it is deleted.
+const maxTombstoneGracePeriod = 30 * 24 * time.Hour
+
 // ValidateAzureMachineSpec check for validation errors of azuremachine.spec.
 func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 	var allErrs field.ErrorList
@@ -38,7 +56,14 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
-	if errs := ValidateSSHKey(spec.SSHPublicKey, field.NewPath("sshPublicKey")); len(errs) > 0 {
+	// Machines without an SSH public key use the public key of the cluster.
+	if spec.SSHPublicKey != "" {
+		if errs := ValidateSSHKey(spec.SSHPublicKey, field.NewPath("sshPublicKey")); len(errs) > 0 {
+			allErrs = append(allErrs, errs...)
+		}
+	}
+
+	if errs := ValidateAdditionalSSHKeys(spec.AdditionalSSHPublicKeys, field.NewPath("additionalSSHPublicKeys")); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
 
@@ -58,6 +83,346 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateUserData(spec.UserData, field.NewPath("userData")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateMultiInstanceGPU(spec.MultiInstanceGPU, spec.VMSize, spec.OSDisk, field.NewPath("multiInstanceGPU")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateLocalStorage(spec.LocalStorage, spec.OSDisk, field.NewPath("localStorage")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateFileStorage(spec.FileStorage, spec.OSDisk, field.NewPath("fileStorage")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateCACertificates(spec.CACertificates, spec.OSDisk, field.NewPath("caCertificates")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateDiskSnapshots(spec.DiskSnapshots, spec.OSDisk, field.NewPath("diskSnapshots")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateBackupProtection(spec.BackupProtection, field.NewPath("backupProtection")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateEtcdDataDisk(spec.EtcdDataDisk, spec.DataDisks, field.NewPath("etcdDataDisk")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateBackendPoolDrainTimeout(spec.BackendPoolDrainTimeout, field.NewPath("backendPoolDrainTimeout")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateTombstonePolicy(spec.TombstonePolicy, field.NewPath("tombstonePolicy")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateDerivedResources(spec.DerivedResources, spec.OSDisk, spec.AllocatePublicIP, field.NewPath("derivedResources")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateHibernation(spec.DesiredPowerState, spec.AdditionalCapabilities, spec.SpotVMOptions, field.NewPath("desiredPowerState")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	return allErrs
+}
+
+// ValidateHibernation validates that a machine is only hibernated when its hibernation capability is enabled, which
+// isn't supported by Spot VMs.
+func ValidateHibernation(desiredPowerState PowerState, capabilities *AdditionalCapabilities, spotVMOptions *SpotVMOptions, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	hibernationEnabled := capabilities != nil && capabilities.HibernationEnabled != nil && *capabilities.HibernationEnabled
+	if desiredPowerState == PowerStateHibernated && !hibernationEnabled {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "a machine can only be hibernated when additionalCapabilities.hibernationEnabled is true"))
+	}
+	if hibernationEnabled && spotVMOptions != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("additionalCapabilities", "hibernationEnabled"), "hibernation is not supported by Spot VMs"))
+	}
+	return allErrs
+}
+
+// ValidateMultiInstanceGPU validates the Multi-Instance GPU configuration of a virtual machine.
+func ValidateMultiInstanceGPU(mig *MultiInstanceGPU, vmSize string, osDisk OSDisk, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if mig == nil {
+		return allErrs
+	}
+
+	if !SupportsMultiInstanceGPU(vmSize) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("VM size %s does not have MIG capable GPUs", vmSize)))
+	}
+
+	if osDisk.OSType == "Windows" {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "MIG can only be configured on Linux machines"))
+	}
+
+	maxInstances := mig.MaxInstancesPerGPU()
+	if maxInstances == 0 {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("profile"), mig.Profile, []string{"1g.*", "2g.*", "3g.*", "4g.*", "7g.*"}))
+	} else if mig.InstancesPerGPU != nil && *mig.InstancesPerGPU > maxInstances {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("instancesPerGPU"), *mig.InstancesPerGPU,
+			fmt.Sprintf("at most %d instances of profile %s fit on a GPU", maxInstances, mig.Profile)))
+	}
+
+	return allErrs
+}
+
+// ValidateLocalStorage validates the local storage configuration of a virtual machine.
+func ValidateLocalStorage(localStorage *LocalStorage, osDisk OSDisk, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if localStorage == nil {
+		return allErrs
+	}
+
+	if osDisk.OSType == "Windows" {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "local storage can only be configured on Linux machines"))
+	}
+
+	if localStorage.MountPath == "/" || !path.IsAbs(localStorage.MountPath) || path.Clean(localStorage.MountPath) != localStorage.MountPath {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("mountPath"), localStorage.MountPath, "must be a clean absolute path other than /"))
+	}
+
+	return allErrs
+}
+
+// ValidateFileStorage validates the file storage configuration of a virtual machine.
+func ValidateFileStorage(fileStorage *FileStorage, osDisk OSDisk, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if fileStorage == nil {
+		return allErrs
+	}
+
+	if osDisk.OSType == "Windows" {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "file storage can only be configured on Linux machines"))
+	}
+
+	if len(fileStorage.Protocols) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("protocols"), "at least one protocol must be specified"))
+	}
+
+	seen := make(map[FileStorageProtocol]bool)
+	for i, protocol := range fileStorage.Protocols {
+		if seen[protocol] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("protocols").Index(i), protocol))
+		}
+		seen[protocol] = true
+	}
+
+	return allErrs
+}
+
+// ValidateCACertificates validates the additional CA certificates of a virtual machine.
+func ValidateCACertificates(caCertificates *CACertificates, osDisk OSDisk, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if caCertificates == nil {
+		return allErrs
+	}
+
+	if osDisk.OSType == "Windows" {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "CA certificates can only be configured on Linux machines"))
+	}
+
+	switch {
+	case caCertificates.ConfigMapRef == nil && caCertificates.SecretRef == nil:
+		allErrs = append(allErrs, field.Required(fldPath, "one of configMapRef or secretRef must be set"))
+	case caCertificates.ConfigMapRef != nil && caCertificates.SecretRef != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath, "only one of configMapRef or secretRef can be set"))
+	case caCertificates.ConfigMapRef != nil && caCertificates.ConfigMapRef.Name == "":
+		allErrs = append(allErrs, field.Required(fldPath.Child("configMapRef", "name"), "name must be set"))
+	case caCertificates.SecretRef != nil && caCertificates.SecretRef.Name == "":
+		allErrs = append(allErrs, field.Required(fldPath.Child("secretRef", "name"), "name must be set"))
+	}
+
+	return allErrs
+}
+
+// ValidateBackupProtection validates the backup protection configuration of a virtual machine.
+func ValidateBackupProtection(protection *BackupProtection, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if protection == nil {
+		return allErrs
+	}
+
+	resource, err := arm.ParseResourceID(protection.VaultID)
+	if err != nil || !strings.EqualFold(resource.ResourceType.Namespace, "Microsoft.RecoveryServices") || !strings.EqualFold(resource.ResourceType.Type, "vaults") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("vaultID"), protection.VaultID, "must be the resource ID of a Recovery Services vault"))
+	}
+
+	return allErrs
+}
+
+// ValidateEtcdDataDisk validates the data disk dedicated to etcd against the IOPS etcd needs, and checks it doesn't
+// conflict with another data disk.
+func ValidateEtcdDataDisk(etcdDisk *EtcdDataDisk, dataDisks []DataDisk, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if etcdDisk == nil {
+		return allErrs
+	}
+
+	storageType := etcdDisk.GetStorageAccountType()
+	sizeGB := etcdDisk.GetDiskSizeGB()
+	if iops := DiskIOPS(storageType, sizeGB); iops < EtcdDiskMinIOPS {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("diskSizeGB"), sizeGB,
+			fmt.Sprintf("a %d GB %s disk provides %d IOPS, below the %d IOPS etcd needs", sizeGB, storageType, iops, EtcdDiskMinIOPS)))
+	}
+
+	// The etcd data disk is added to the data disks when the machine is created, so a data disk with the same name must
+	// be that disk.
+	expected := etcdDisk.DataDisk()
+	for i, disk := range dataDisks {
+		if disk.NameSuffix != EtcdDataDiskNameSuffix {
+			continue
+		}
+		if disk.DiskSizeGB != expected.DiskSizeGB || disk.ManagedDisk == nil || disk.ManagedDisk.StorageAccountType != expected.ManagedDisk.StorageAccountType ||
+			disk.Lun == nil || *disk.Lun != *expected.Lun {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("dataDisks").Index(i), disk.NameSuffix, "conflicts with etcdDataDisk"))
+		}
+	}
+
+	return allErrs
+}
+
+// premiumSSDIOPS and standardSSDIOPS are the provisioned IOPS of the Premium SSD and Standard SSD disk tiers, by
+// maximum size in GB. A disk gets the IOPS of the smallest tier it fits in.
+// See https://learn.microsoft.com/azure/virtual-machines/disks-scalability-targets.
+var (
+	premiumSSDIOPS = []struct{ sizeGB, iops int32 }{
+		{32, 120}, {64, 240}, {128, 500}, {256, 1100}, {512, 2300}, {1024, 5000}, {4096, 7500}, {8192, 16000}, {16384, 18000}, {32767, 20000},
+	}
+	standardSSDIOPS = []struct{ sizeGB, iops int32 }{
+		{4096, 500}, {8192, 2000}, {16384, 4000}, {32767, 6000},
+	}
+)
+
+// DiskIOPS returns the provisioned IOPS of a managed disk of the given storage type and size, or 0 if it isn't known.
+func DiskIOPS(storageAccountType string, diskSizeGB int32) int32 {
+	var tiers []struct{ sizeGB, iops int32 }
+	switch armcompute.StorageAccountTypes(storageAccountType) {
+	case armcompute.StorageAccountTypesPremiumLRS, armcompute.StorageAccountTypesPremiumZRS:
+		tiers = premiumSSDIOPS
+	case armcompute.StorageAccountTypesStandardSSDLRS, armcompute.StorageAccountTypesStandardSSDZRS:
+		tiers = standardSSDIOPS
+	}
+	for _, tier := range tiers {
+		if diskSizeGB <= tier.sizeGB {
+			return tier.iops
+		}
+	}
+	return 0
+}
+
+// ValidateDiskSnapshots validates the disk snapshots configuration of a virtual machine.
+func ValidateDiskSnapshots(snapshots *DiskSnapshots, osDisk OSDisk, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if snapshots == nil {
+		return allErrs
+	}
+
+	if osDisk.DiffDiskSettings != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "snapshots are not supported on machines with an ephemeral OS disk"))
+	}
+
+	if snapshots.Interval != nil && snapshots.Interval.Duration < minDiskSnapshotInterval {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), snapshots.Interval.Duration.String(), fmt.Sprintf("must be at least %s", minDiskSnapshotInterval)))
+	}
+
+	if snapshots.Retain < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("retain"), snapshots.Retain, "must be at least 1"))
+	}
+
+	return allErrs
+}
+
+// ValidateBackendPoolDrainTimeout validates the time a machine is kept out of the load balancer backend pools before
+// it is deleted.
+func ValidateBackendPoolDrainTimeout(timeout *metav1.Duration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if timeout == nil {
+		return allErrs
+	}
+
+	if timeout.Duration < 0 || timeout.Duration > maxBackendPoolDrainTimeout {
+		allErrs = append(allErrs, field.Invalid(fldPath, timeout.Duration.String(), fmt.Sprintf("must be between 0s and %s", maxBackendPoolDrainTimeout)))
+	}
+
+	return allErrs
+}
+
+// ValidateTombstonePolicy validates the two-phase deletion configuration of a virtual machine.
+func ValidateTombstonePolicy(policy *TombstonePolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if policy == nil {
+		return allErrs
+	}
+
+	if policy.GracePeriod.Duration < 0 || policy.GracePeriod.Duration > maxTombstoneGracePeriod {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("gracePeriod"), policy.GracePeriod.Duration.String(), fmt.Sprintf("must be between 0s and %s", maxTombstoneGracePeriod)))
+	}
+
+	return allErrs
+}
+
+// ValidateDerivedResources validates the overrides of the names and tags of the resources created along with a
+// virtual machine.
+func ValidateDerivedResources(derived *DerivedResources, osDisk OSDisk, allocatePublicIP bool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if derived == nil {
+		return allErrs
+	}
+
+	if derived.OSDisk != nil && osDisk.DiffDiskSettings != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("osDisk"), "an ephemeral OS disk has no name nor tags"))
+	}
+
+	if derived.PublicIP != nil && !allocatePublicIP {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("publicIP"), "the machine has no public IP as allocatePublicIP is false"))
+	}
+
+	return allErrs
+}
+
+// SupportsMultiInstanceGPU returns true if the VM size has NVIDIA A100 or H100 GPUs, which support MIG.
+func SupportsMultiInstanceGPU(vmSize string) bool {
+	size := strings.ToLower(vmSize)
+	return strings.Contains(size, "a100") || strings.Contains(size, "h100") || size == "standard_nd96asr_v4"
+}
+
+// ValidateUserData validates the user data of a virtual machine.
+func ValidateUserData(userData *UserData, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if userData == nil {
+		return allErrs
+	}
+
+	for i, section := range userData.Sections {
+		sectionPath := fldPath.Child("sections").Index(i)
+		if (section.Content == "") == (section.SecretRef == nil) {
+			allErrs = append(allErrs, field.Invalid(sectionPath, section, "exactly one of content or secretRef must be set"))
+			continue
+		}
+		if section.SecretRef != nil && section.SecretRef.Name == "" {
+			allErrs = append(allErrs, field.Required(sectionPath.Child("secretRef", "name"), "the Secret name cannot be empty"))
+		}
+	}
+
 	return allErrs
 }
 
@@ -65,7 +430,26 @@ func ValidateNetwork(subnetName string, networkInterfaces []AzureNetworkInterfac
 	if (networkInterfaces != nil) && len(networkInterfaces) > 0 && subnetName != "" {
 		return field.ErrorList{field.Invalid(fldPath, networkInterfaces, "cannot set both NetworkInterfaces and machine SubnetName")}
 	}
-	return field.ErrorList{}
+
+	allErrs := field.ErrorList{}
+	for i, nic := range networkInterfaces {
+		nicPath := fldPath.Index(i)
+		if nic.ID != "" && (nic.InternalDNSNameLabel != "" || len(nic.DNSServers) > 0) {
+			allErrs = append(allErrs, field.Forbidden(nicPath, "DNS settings cannot be set on an already provisioned interface"))
+			continue
+		}
+		if nic.InternalDNSNameLabel != "" {
+			for _, msg := range validation.IsDNS1123Label(nic.InternalDNSNameLabel) {
+				allErrs = append(allErrs, field.Invalid(nicPath.Child("internalDNSNameLabel"), nic.InternalDNSNameLabel, msg))
+			}
+		}
+		for j, server := range nic.DNSServers {
+			if net.ParseIP(server) == nil {
+				allErrs = append(allErrs, field.Invalid(nicPath.Child("dnsServers").Index(j), server, "DNS server must be a valid IP address"))
+			}
+		}
+	}
+	return allErrs
 }
 
 // ValidateSSHKey validates an SSHKey.
@@ -86,6 +470,17 @@ func ValidateSSHKey(sshKey string, fldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+// ValidateAdditionalSSHKeys validates the SSH public keys authorized on a machine in addition to its SSH public key.
+func ValidateAdditionalSSHKeys(sshKeys []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, sshKey := range sshKeys {
+		allErrs = append(allErrs, ValidateSSHKey(sshKey, fldPath.Index(i))...)
+	}
+
+	return allErrs
+}
+
 // ValidateSystemAssignedIdentity validates the system-assigned identities list.
 func ValidateSystemAssignedIdentity(identityType VMIdentity, oldIdentity, newIdentity string, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -120,8 +515,10 @@ func ValidateDataDisks(dataDisks []DataDisk, fieldPath *field.Path) field.ErrorL
 	lunSet := make(map[int32]struct{})
 	nameSet := make(map[string]struct{})
 	for _, disk := range dataDisks {
-		// validate that the disk size is between 4 and 32767.
-		if disk.DiskSizeGB < 4 || disk.DiskSizeGB > 32767 {
+		if disk.ExistingDiskID != "" {
+			allErrs = append(allErrs, validateExistingDiskID(disk, fieldPath)...)
+		} else if disk.DiskSizeGB < 4 || disk.DiskSizeGB > 32767 {
+			// validate that the disk size is between 4 and 32767.
 			allErrs = append(allErrs, field.Invalid(fieldPath.Child("DiskSizeGB"), "", "the disk size should be a value between 4 and 32767"))
 		}
 
@@ -155,7 +552,25 @@ func ValidateDataDisks(dataDisks []DataDisk, fieldPath *field.Path) field.ErrorL
 
 		// validate cachingType
 		allErrs = append(allErrs, validateCachingType(disk.CachingType, fieldPath, disk.ManagedDisk)...)
+
+		allErrs = append(allErrs, ValidateWriteAccelerator(disk.WriteAcceleratorEnabled, disk.CachingType, disk.ManagedDisk, fieldPath)...)
+	}
+	return allErrs
+}
+
+// validateExistingDiskID validates a data disk attaching an existing managed disk.
+func validateExistingDiskID(disk DataDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	resource, err := arm.ParseResourceID(disk.ExistingDiskID)
+	if err != nil || !strings.EqualFold(resource.ResourceType.Namespace, "Microsoft.Compute") || !strings.EqualFold(resource.ResourceType.Type, "disks") {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("existingDiskID"), disk.ExistingDiskID, "must be the resource ID of a managed disk"))
 	}
+
+	if disk.ManagedDisk != nil {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("managedDisk"), "managed disk parameters can't be set for an existing disk"))
+	}
+
 	return allErrs
 }
 
@@ -173,14 +588,33 @@ func ValidateOSDisk(osDisk OSDisk, fieldPath *field.Path) field.ErrorList {
 		allErrs = append(allErrs, field.Required(fieldPath.Child("OSType"), "the OS type cannot be empty"))
 	}
 
+	if osDisk.Distro != "" && osDisk.OSType == "Windows" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("distro"), "distro can only be set for Linux machines"))
+	}
+
 	allErrs = append(allErrs, validateCachingType(osDisk.CachingType, fieldPath, osDisk.ManagedDisk)...)
 
+	allErrs = append(allErrs, ValidateWriteAccelerator(osDisk.WriteAcceleratorEnabled, osDisk.CachingType, osDisk.ManagedDisk, fieldPath)...)
+	if osDisk.DiffDiskSettings != nil && osDisk.WriteAcceleratorEnabled != nil && *osDisk.WriteAcceleratorEnabled {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("writeAcceleratorEnabled"), "write accelerator is not supported on ephemeral OS disks"))
+	}
+
 	if osDisk.ManagedDisk != nil {
 		if errs := validateManagedDisk(osDisk.ManagedDisk, fieldPath.Child("managedDisk"), true); len(errs) > 0 {
 			allErrs = append(allErrs, errs...)
 		}
 	}
 
+	if osDisk.SourceSnapshotID != "" {
+		resource, err := arm.ParseResourceID(osDisk.SourceSnapshotID)
+		if err != nil || !strings.EqualFold(resource.ResourceType.Namespace, "Microsoft.Compute") || !strings.EqualFold(resource.ResourceType.Type, "snapshots") {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("sourceSnapshotID"), osDisk.SourceSnapshotID, "must be the resource ID of a snapshot"))
+		}
+		if osDisk.DiffDiskSettings != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("sourceSnapshotID"), "ephemeral OS disks can't be created from a snapshot"))
+		}
+	}
+
 	if osDisk.DiffDiskSettings != nil && osDisk.ManagedDisk != nil && osDisk.ManagedDisk.DiskEncryptionSet != nil {
 		allErrs = append(allErrs, field.Invalid(
 			fieldPath.Child("managedDisks").Child("diskEncryptionSet"),
@@ -238,6 +672,14 @@ func ValidateDataDisksUpdate(oldDataDisks, newDataDisks []DataDisk, fieldPath *f
 			if newDisk.CachingType != oldDisk.CachingType {
 				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("cachingType"), newDataDisks, fieldErrMsg))
 			}
+
+			if !reflect.DeepEqual(newDisk.WriteAcceleratorEnabled, oldDisk.WriteAcceleratorEnabled) {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("writeAcceleratorEnabled"), newDataDisks, fieldErrMsg))
+			}
+
+			if newDisk.ExistingDiskID != oldDisk.ExistingDiskID {
+				allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("existingDiskID"), newDataDisks, fieldErrMsg))
+			}
 		} else {
 			allErrs = append(allErrs, field.Invalid(fieldPath.Index(i).Child("nameSuffix"), newDataDisks, diskErrMsg))
 		}
@@ -271,7 +713,7 @@ func validateManagedDisksUpdate(oldDiskParams, newDiskParams *ManagedDiskParamet
 func validateStorageAccountType(storageAccountType string, fieldPath *field.Path, isOSDisk bool) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	if isOSDisk && storageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) {
+	if isOSDisk && storageAccountType == string(armcompute.StorageAccountTypesUltraSSDLRS) {
 		allErrs = append(allErrs, field.Invalid(fieldPath.Child("managedDisks").Child("storageAccountType"), storageAccountType, "UltraSSD_LRS can only be used with data disks, it cannot be used with OS Disks"))
 	}
 
@@ -280,31 +722,63 @@ func validateStorageAccountType(storageAccountType string, fieldPath *field.Path
 		return allErrs
 	}
 
-	for _, possibleStorageAccountType := range compute.PossibleDiskStorageAccountTypesValues() {
+	for _, possibleStorageAccountType := range supportedDiskStorageAccountTypes() {
 		if string(possibleStorageAccountType) == storageAccountType {
 			return allErrs
 		}
 	}
-	allErrs = append(allErrs, field.Invalid(fieldPath, "", fmt.Sprintf("allowed values are %v", compute.PossibleDiskStorageAccountTypesValues())))
+	allErrs = append(allErrs, field.Invalid(fieldPath, "", fmt.Sprintf("allowed values are %v", supportedDiskStorageAccountTypes())))
 	return allErrs
 }
 
+// supportedDiskStorageAccountTypes returns the storage account types of the managed disks of a machine.
+// PremiumV2_LRS disks aren't supported yet, as they can't be used as OS disks nor with host caching.
+func supportedDiskStorageAccountTypes() []armcompute.DiskStorageAccountTypes {
+	var types []armcompute.DiskStorageAccountTypes
+	for _, storageAccountType := range armcompute.PossibleDiskStorageAccountTypesValues() {
+		if storageAccountType != armcompute.DiskStorageAccountTypesPremiumV2LRS {
+			types = append(types, storageAccountType)
+		}
+	}
+	return types
+}
+
 func validateCachingType(cachingType string, fieldPath *field.Path, managedDisk *ManagedDiskParameters) field.ErrorList {
 	allErrs := field.ErrorList{}
 	cachingTypeChildPath := fieldPath.Child("CachingType")
 
-	if managedDisk != nil && managedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) {
-		if cachingType != string(compute.CachingTypesNone) {
-			allErrs = append(allErrs, field.Invalid(cachingTypeChildPath, cachingType, fmt.Sprintf("cachingType '%s' is not supported when storageAccountType is '%s'. Allowed values are: '%s'", cachingType, compute.StorageAccountTypesUltraSSDLRS, compute.CachingTypesNone)))
+	if managedDisk != nil && managedDisk.StorageAccountType == string(armcompute.StorageAccountTypesUltraSSDLRS) {
+		if cachingType != string(armcompute.CachingTypesNone) {
+			allErrs = append(allErrs, field.Invalid(cachingTypeChildPath, cachingType, fmt.Sprintf("cachingType '%s' is not supported when storageAccountType is '%s'. Allowed values are: '%s'", cachingType, armcompute.StorageAccountTypesUltraSSDLRS, armcompute.CachingTypesNone)))
 		}
 	}
 
-	for _, possibleCachingType := range compute.PossibleCachingTypesValues() {
+	for _, possibleCachingType := range armcompute.PossibleCachingTypesValues() {
 		if string(possibleCachingType) == cachingType {
 			return allErrs
 		}
 	}
 
-	allErrs = append(allErrs, field.Invalid(cachingTypeChildPath, cachingType, fmt.Sprintf("allowed values are %v", compute.PossibleCachingTypesValues())))
+	allErrs = append(allErrs, field.Invalid(cachingTypeChildPath, cachingType, fmt.Sprintf("allowed values are %v", armcompute.PossibleCachingTypesValues())))
+	return allErrs
+}
+
+// ValidateWriteAccelerator validates that Write Accelerator is only enabled on Premium_LRS disks which are not cached for writes.
+// Whether the VM size supports Write Accelerator is checked against its SKU capabilities when the VM is created.
+func ValidateWriteAccelerator(writeAcceleratorEnabled *bool, cachingType string, managedDisk *ManagedDiskParameters, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if writeAcceleratorEnabled == nil || !*writeAcceleratorEnabled {
+		return allErrs
+	}
+
+	if managedDisk == nil || managedDisk.StorageAccountType != string(armcompute.StorageAccountTypesPremiumLRS) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("writeAcceleratorEnabled"), *writeAcceleratorEnabled, fmt.Sprintf("write accelerator requires a storageAccountType of '%s'", armcompute.StorageAccountTypesPremiumLRS)))
+	}
+
+	if cachingType == string(armcompute.CachingTypesReadWrite) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("writeAcceleratorEnabled"), *writeAcceleratorEnabled, fmt.Sprintf("write accelerator is not supported when cachingType is '%s'", armcompute.CachingTypesReadWrite)))
+	}
+
 	return allErrs
 }
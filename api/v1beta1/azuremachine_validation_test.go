@@ -22,12 +22,14 @@ import (
 	"encoding/base64"
 	"fmt"
 	"testing"
+	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/google/uuid"
 	. "github.com/onsi/gomega"
 	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -68,6 +70,43 @@ func TestAzureMachine_ValidateSSHKey(t *testing.T) {
 	}
 }
 
+func TestAzureMachine_ValidateAdditionalSSHKeys(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		sshKeys []string
+		wantErr bool
+	}{
+		{
+			name:    "no additional ssh keys",
+			wantErr: false,
+		},
+		{
+			name:    "valid additional ssh keys",
+			sshKeys: []string{generateSSHPublicKey(true), generateSSHPublicKey(true)},
+			wantErr: false,
+		},
+		{
+			name:    "invalid additional ssh key",
+			sshKeys: []string{generateSSHPublicKey(true), "invalid ssh key"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateAdditionalSSHKeys(tc.sshKeys, field.NewPath("additionalSSHPublicKeys"))
+			if tc.wantErr {
+				g.Expect(err).To(HaveLen(1))
+				g.Expect(err[0].Field).To(Equal("additionalSSHPublicKeys[1]"))
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
 func generateSSHPublicKey(b64Enconded bool) string {
 	privateKey, _ := rsa.GenerateKey(rand.Reader, 2048)
 	publicRsaKey, _ := ssh.NewPublicKey(&privateKey.PublicKey)
@@ -101,26 +140,46 @@ func TestAzureMachine_ValidateOSDisk(t *testing.T) {
 			name:    "valid ephemeral os disk spec",
 			wantErr: false,
 			osDisk: OSDisk{
-				DiskSizeGB:  to.Int32Ptr(30),
+				DiskSizeGB:  to.Ptr[int32](30),
 				CachingType: "None",
 				OSType:      "blah",
 				DiffDiskSettings: &DiffDiskSettings{
-					Option: string(compute.DiffDiskOptionsLocal),
+					Option: string(armcompute.DiffDiskOptionsLocal),
 				},
 				ManagedDisk: &ManagedDiskParameters{
 					StorageAccountType: "Standard_LRS",
 				},
 			},
 		},
+		{
+			name:    "valid Azure Linux os disk spec",
+			wantErr: false,
+			osDisk: OSDisk{
+				DiskSizeGB:  to.Ptr[int32](30),
+				CachingType: "None",
+				OSType:      "Linux",
+				Distro:      OSDistroAzureLinux,
+			},
+		},
+		{
+			name:    "distro set on a Windows os disk spec",
+			wantErr: true,
+			osDisk: OSDisk{
+				DiskSizeGB:  to.Ptr[int32](30),
+				CachingType: "None",
+				OSType:      "Windows",
+				Distro:      OSDistroAzureLinux,
+			},
+		},
 		{
 			name:    "byoc encryption with ephemeral os disk spec",
 			wantErr: true,
 			osDisk: OSDisk{
-				DiskSizeGB:  to.Int32Ptr(30),
+				DiskSizeGB:  to.Ptr[int32](30),
 				CachingType: "None",
 				OSType:      "blah",
 				DiffDiskSettings: &DiffDiskSettings{
-					Option: string(compute.DiffDiskOptionsLocal),
+					Option: string(armcompute.DiffDiskOptionsLocal),
 				},
 				ManagedDisk: &ManagedDiskParameters{
 					StorageAccountType: "Standard_LRS",
@@ -130,6 +189,36 @@ func TestAzureMachine_ValidateOSDisk(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "os disk created from a snapshot",
+			wantErr: false,
+			osDisk: OSDisk{
+				CachingType:      "None",
+				OSType:           "Linux",
+				SourceSnapshotID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot",
+			},
+		},
+		{
+			name:    "os disk created from a disk instead of a snapshot",
+			wantErr: true,
+			osDisk: OSDisk{
+				CachingType:      "None",
+				OSType:           "Linux",
+				SourceSnapshotID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-disk",
+			},
+		},
+		{
+			name:    "ephemeral os disk created from a snapshot",
+			wantErr: true,
+			osDisk: OSDisk{
+				CachingType:      "ReadOnly",
+				OSType:           "Linux",
+				SourceSnapshotID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot",
+				DiffDiskSettings: &DiffDiskSettings{
+					Option: string(armcompute.DiffDiskOptionsLocal),
+				},
+			},
+		},
 	}
 	testcases = append(testcases, generateNegativeTestCases()...)
 
@@ -152,48 +241,48 @@ func generateNegativeTestCases() []osDiskTestInput {
 	invalidDiskSpecs := []OSDisk{
 		{},
 		{
-			DiskSizeGB: to.Int32Ptr(0),
+			DiskSizeGB: to.Ptr[int32](0),
 			OSType:     "blah",
 		},
 		{
-			DiskSizeGB: to.Int32Ptr(-10),
+			DiskSizeGB: to.Ptr[int32](-10),
 			OSType:     "blah",
 		},
 		{
-			DiskSizeGB: to.Int32Ptr(2050),
+			DiskSizeGB: to.Ptr[int32](2050),
 			OSType:     "blah",
 		},
 		{
-			DiskSizeGB: to.Int32Ptr(20),
+			DiskSizeGB: to.Ptr[int32](20),
 			OSType:     "",
 		},
 		{
-			DiskSizeGB:  to.Int32Ptr(30),
+			DiskSizeGB:  to.Ptr[int32](30),
 			OSType:      "blah",
 			ManagedDisk: &ManagedDiskParameters{},
 		},
 		{
-			DiskSizeGB: to.Int32Ptr(30),
+			DiskSizeGB: to.Ptr[int32](30),
 			OSType:     "blah",
 			ManagedDisk: &ManagedDiskParameters{
 				StorageAccountType: "",
 			},
 		},
 		{
-			DiskSizeGB: to.Int32Ptr(30),
+			DiskSizeGB: to.Ptr[int32](30),
 			OSType:     "blah",
 			ManagedDisk: &ManagedDiskParameters{
 				StorageAccountType: "invalid_type",
 			},
 		},
 		{
-			DiskSizeGB: to.Int32Ptr(30),
+			DiskSizeGB: to.Ptr[int32](30),
 			OSType:     "blah",
 			ManagedDisk: &ManagedDiskParameters{
 				StorageAccountType: "Premium_LRS",
 			},
 			DiffDiskSettings: &DiffDiskSettings{
-				Option: string(compute.DiffDiskOptionsLocal),
+				Option: string(armcompute.DiffDiskOptionsLocal),
 			},
 		},
 	}
@@ -211,12 +300,12 @@ func generateNegativeTestCases() []osDiskTestInput {
 
 func generateValidOSDisk() OSDisk {
 	return OSDisk{
-		DiskSizeGB: to.Int32Ptr(30),
+		DiskSizeGB: to.Ptr[int32](30),
 		OSType:     "Linux",
 		ManagedDisk: &ManagedDiskParameters{
 			StorageAccountType: "Premium_LRS",
 		},
-		CachingType: string(compute.PossibleCachingTypesValues()[0]),
+		CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
 	}
 }
 
@@ -250,32 +339,71 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 				{
 					NameSuffix:  "my_disk",
 					DiskSizeGB:  64,
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
 				},
 				{
 					NameSuffix:  "my_other_disk",
 					DiskSizeGB:  64,
-					Lun:         to.Int32Ptr(1),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+					Lun:         to.Ptr[int32](1),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid existing disk",
+			disks: []DataDisk{
+				{
+					NameSuffix:     "my_disk",
+					ExistingDiskID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-disk",
+					Lun:            to.Ptr[int32](0),
+					CachingType:    string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "existing disk ID of another resource type",
+			disks: []DataDisk{
+				{
+					NameSuffix:     "my_disk",
+					ExistingDiskID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot",
+					Lun:            to.Ptr[int32](0),
+					CachingType:    string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "existing disk with managed disk parameters",
+			disks: []DataDisk{
+				{
+					NameSuffix:     "my_disk",
+					ExistingDiskID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-disk",
+					Lun:            to.Ptr[int32](0),
+					CachingType:    string(armcompute.PossibleCachingTypesValues()[0]),
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "duplicate names",
 			disks: []DataDisk{
 				{
 					NameSuffix:  "disk",
 					DiskSizeGB:  64,
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
 				},
 				{
 					NameSuffix:  "disk",
 					DiskSizeGB:  64,
-					Lun:         to.Int32Ptr(1),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+					Lun:         to.Ptr[int32](1),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
 				},
 			},
 			wantErr: true,
@@ -286,14 +414,14 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 				{
 					NameSuffix:  "my_disk",
 					DiskSizeGB:  64,
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
 				},
 				{
 					NameSuffix:  "my_other_disk",
 					DiskSizeGB:  64,
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
 				},
 			},
 			wantErr: true,
@@ -304,8 +432,8 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 				{
 					NameSuffix:  "my_disk",
 					DiskSizeGB:  0,
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
 				},
 			},
 			wantErr: true,
@@ -316,8 +444,8 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 				{
 					NameSuffix:  "",
 					DiskSizeGB:  0,
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
 				},
 			},
 			wantErr: true,
@@ -328,7 +456,7 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 				{
 					NameSuffix:  "my_disk",
 					DiskSizeGB:  64,
-					Lun:         to.Int32Ptr(0),
+					Lun:         to.Ptr[int32](0),
 					CachingType: "invalidCacheType",
 				},
 			},
@@ -340,8 +468,8 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 				{
 					NameSuffix:  "my_disk",
 					DiskSizeGB:  64,
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
 				},
 			},
 			wantErr: false,
@@ -355,8 +483,8 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 					ManagedDisk: &ManagedDiskParameters{
 						StorageAccountType: "Premium_LRS",
 					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
 				},
 				{
 					NameSuffix: "my_disk_2",
@@ -364,8 +492,8 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 					ManagedDisk: &ManagedDiskParameters{
 						StorageAccountType: "Standard_LRS",
 					},
-					Lun:         to.Int32Ptr(1),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+					Lun:         to.Ptr[int32](1),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
 				},
 			},
 			wantErr: false,
@@ -379,8 +507,23 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 					ManagedDisk: &ManagedDiskParameters{
 						StorageAccountType: "invalid storage account",
 					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported managed disk storage account type PremiumV2_LRS",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: string(armcompute.DiskStorageAccountTypesPremiumV2LRS),
+					},
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.CachingTypesNone),
 				},
 			},
 			wantErr: true,
@@ -392,10 +535,10 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 					NameSuffix: "my_disk_1",
 					DiskSizeGB: 64,
 					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: string(compute.StorageAccountTypesUltraSSDLRS),
+						StorageAccountType: string(armcompute.StorageAccountTypesUltraSSDLRS),
 					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.CachingTypesNone),
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.CachingTypesNone),
 				},
 			},
 			wantErr: false,
@@ -407,10 +550,10 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 					NameSuffix: "my_disk_1",
 					DiskSizeGB: 64,
 					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: string(compute.StorageAccountTypesUltraSSDLRS),
+						StorageAccountType: string(armcompute.StorageAccountTypesUltraSSDLRS),
 					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.CachingTypesReadWrite),
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.CachingTypesReadWrite),
 				},
 			},
 			wantErr: true,
@@ -422,10 +565,10 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 					NameSuffix: "my_disk_1",
 					DiskSizeGB: 64,
 					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: string(compute.StorageAccountTypesUltraSSDLRS),
+						StorageAccountType: string(armcompute.StorageAccountTypesUltraSSDLRS),
 					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.CachingTypesReadOnly),
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.CachingTypesReadOnly),
 				},
 			},
 			wantErr: true,
@@ -499,194 +642,962 @@ func TestAzureMachine_ValidateSystemAssignedIdentity(t *testing.T) {
 	}
 }
 
-func TestAzureMachine_ValidateDataDisksUpdate(t *testing.T) {
+func TestAzureMachine_ValidateUserData(t *testing.T) {
 	g := NewWithT(t)
 
 	tests := []struct {
 		name     string
-		disks    []DataDisk
-		oldDisks []DataDisk
+		userData *UserData
 		wantErr  bool
 	}{
 		{
-			name:     "valid nil data disks",
-			disks:    nil,
-			oldDisks: nil,
-			wantErr:  false,
-		},
-		{
-			name:     "valid empty data disks",
-			disks:    []DataDisk{},
-			oldDisks: []DataDisk{},
+			name:     "nil",
+			userData: nil,
 			wantErr:  false,
 		},
 		{
-			name: "valid data disk updates",
-			disks: []DataDisk{
-				{
-					NameSuffix: "my_disk",
-					DiskSizeGB: 64,
-					Lun:        to.Int32Ptr(0),
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-				{
-					NameSuffix: "my_other_disk",
-					DiskSizeGB: 64,
-					Lun:        to.Int32Ptr(1),
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-			},
-			oldDisks: []DataDisk{
-				{
-					NameSuffix: "my_disk",
-					DiskSizeGB: 64,
-					Lun:        to.Int32Ptr(0),
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-				{
-					NameSuffix: "my_other_disk",
-					DiskSizeGB: 64,
-					Lun:        to.Int32Ptr(1),
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+			name: "inline and secret sections",
+			userData: &UserData{
+				Sections: []UserDataSection{
+					{Content: "#cloud-config\nruncmd: []\n"},
+					{SecretRef: &UserDataSecretReference{Name: "agent", Key: "install.sh"}},
 				},
 			},
 			wantErr: false,
 		},
 		{
-			name: "cannot update data disk fields after machine creation",
-			disks: []DataDisk{
-				{
-					NameSuffix: "my_disk_1",
-					DiskSizeGB: 64,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-			},
-			oldDisks: []DataDisk{
-				{
-					NameSuffix: "my_disk_1",
-					DiskSizeGB: 128,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Premium_LRS",
-					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+			name: "section with both content and secret",
+			userData: &UserData{
+				Sections: []UserDataSection{
+					{Content: "#!/bin/bash", SecretRef: &UserDataSecretReference{Name: "agent"}},
 				},
 			},
 			wantErr: true,
 		},
 		{
-			name: "validate updates to optional fields",
-			disks: []DataDisk{
-				{
-					NameSuffix: "my_disk_1",
-					DiskSizeGB: 128,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					Lun: to.Int32Ptr(0),
-				},
+			name: "empty section",
+			userData: &UserData{
+				Sections: []UserDataSection{{}},
 			},
-			oldDisks: []DataDisk{
-				{
-					NameSuffix:  "my_disk_1",
-					DiskSizeGB:  128,
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
+			wantErr: true,
+		},
+		{
+			name: "secret without name",
+			userData: &UserData{
+				Sections: []UserDataSection{
+					{SecretRef: &UserDataSecretReference{Key: "value"}},
 				},
 			},
 			wantErr: true,
 		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateUserData(tc.userData, field.NewPath("userData"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateNetwork(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name              string
+		subnetName        string
+		networkInterfaces []AzureNetworkInterface
+		wantErr           bool
+	}{
 		{
-			name: "data disks cannot be added after machine creation",
-			disks: []DataDisk{
-				{
-					NameSuffix: "my_disk_1",
-					DiskSizeGB: 64,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
+			name:              "no network interfaces",
+			subnetName:        "subnet",
+			networkInterfaces: nil,
+			wantErr:           false,
+		},
+		{
+			name:              "network interfaces and machine subnet",
+			subnetName:        "subnet",
+			networkInterfaces: []AzureNetworkInterface{{SubnetName: "subnet"}},
+			wantErr:           true,
+		},
+		{
+			name: "valid DNS settings",
+			networkInterfaces: []AzureNetworkInterface{
+				{SubnetName: "subnet", InternalDNSNameLabel: "win-node-1", DNSServers: []string{"10.0.0.4", "fd00::4"}},
 			},
-			oldDisks: []DataDisk{
-				{
-					NameSuffix: "my_disk_1",
-					DiskSizeGB: 64,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Premium_LRS",
-					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-				{
-					NameSuffix: "my_disk_2",
-					DiskSizeGB: 64,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Premium_LRS",
-					},
-					Lun:         to.Int32Ptr(2),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
+			wantErr: false,
+		},
+		{
+			name: "invalid internal DNS name label",
+			networkInterfaces: []AzureNetworkInterface{
+				{SubnetName: "subnet", InternalDNSNameLabel: "Win_Node"},
 			},
 			wantErr: true,
 		},
 		{
-			name: "data disks cannot be removed after machine creation",
-			disks: []DataDisk{
-				{
-					NameSuffix: "my_disk_1",
-					DiskSizeGB: 64,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
-				{
-					NameSuffix: "my_disk_2",
-					DiskSizeGB: 64,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Premium_LRS",
-					},
-					Lun:         to.Int32Ptr(2),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
+			name: "invalid DNS server",
+			networkInterfaces: []AzureNetworkInterface{
+				{SubnetName: "subnet", DNSServers: []string{"dns.example.com"}},
 			},
-			oldDisks: []DataDisk{
-				{
-					NameSuffix: "my_disk_1",
-					DiskSizeGB: 64,
-					ManagedDisk: &ManagedDiskParameters{
-						StorageAccountType: "Standard_LRS",
-					},
-					Lun:         to.Int32Ptr(0),
-					CachingType: string(compute.PossibleCachingTypesValues()[0]),
-				},
+			wantErr: true,
+		},
+		{
+			name: "DNS settings on an existing interface",
+			networkInterfaces: []AzureNetworkInterface{
+				{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-nic", DNSServers: []string{"10.0.0.4"}},
 			},
 			wantErr: true,
 		},
 	}
 
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			err := ValidateDataDisksUpdate(test.oldDisks, test.disks, field.NewPath("dataDisks"))
-			if test.wantErr {
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateNetwork(tc.subnetName, tc.networkInterfaces, field.NewPath("networkInterfaces"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateMultiInstanceGPU(t *testing.T) {
+	g := NewWithT(t)
+
+	linuxOSDisk := OSDisk{OSType: "Linux"}
+	tests := []struct {
+		name    string
+		mig     *MultiInstanceGPU
+		vmSize  string
+		osDisk  OSDisk
+		wantErr bool
+	}{
+		{
+			name:    "nil",
+			mig:     nil,
+			vmSize:  "Standard_D2s_v3",
+			osDisk:  linuxOSDisk,
+			wantErr: false,
+		},
+		{
+			name:    "A100 VM size with default instances",
+			mig:     &MultiInstanceGPU{Profile: "1g.10gb"},
+			vmSize:  "Standard_NC24ads_A100_v4",
+			osDisk:  linuxOSDisk,
+			wantErr: false,
+		},
+		{
+			name:    "A100 VM size with as many instances as fit on a GPU",
+			mig:     &MultiInstanceGPU{Profile: "3g.40gb", InstancesPerGPU: to.Ptr[int32](2)},
+			vmSize:  "Standard_ND96asr_v4",
+			osDisk:  linuxOSDisk,
+			wantErr: false,
+		},
+		{
+			name:    "VM size without MIG capable GPUs",
+			mig:     &MultiInstanceGPU{Profile: "1g.10gb"},
+			vmSize:  "Standard_NC6s_v3",
+			osDisk:  linuxOSDisk,
+			wantErr: true,
+		},
+		{
+			name:    "too many instances",
+			mig:     &MultiInstanceGPU{Profile: "3g.40gb", InstancesPerGPU: to.Ptr[int32](3)},
+			vmSize:  "Standard_NC24ads_A100_v4",
+			osDisk:  linuxOSDisk,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported profile",
+			mig:     &MultiInstanceGPU{Profile: "5g.50gb"},
+			vmSize:  "Standard_NC24ads_A100_v4",
+			osDisk:  linuxOSDisk,
+			wantErr: true,
+		},
+		{
+			name:    "Windows machine",
+			mig:     &MultiInstanceGPU{Profile: "1g.10gb"},
+			vmSize:  "Standard_NC24ads_A100_v4",
+			osDisk:  OSDisk{OSType: "Windows"},
+			wantErr: true,
+		},
+		{
+			name:    "Azure Linux machine",
+			mig:     &MultiInstanceGPU{Profile: "1g.10gb"},
+			vmSize:  "Standard_NC24ads_A100_v4",
+			osDisk:  OSDisk{OSType: "Linux", Distro: OSDistroAzureLinux},
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateMultiInstanceGPU(tc.mig, tc.vmSize, tc.osDisk, field.NewPath("multiInstanceGPU"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateLocalStorage(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name         string
+		localStorage *LocalStorage
+		osDisk       OSDisk
+		wantErr      bool
+	}{
+		{
+			name:         "nil",
+			localStorage: nil,
+			osDisk:       OSDisk{OSType: "Linux"},
+			wantErr:      false,
+		},
+		{
+			name:         "NVMe disks on Linux",
+			localStorage: &LocalStorage{Source: LocalStorageNVMe, Filesystem: "xfs", MountPath: "/var/lib/etcd"},
+			osDisk:       OSDisk{OSType: "Linux"},
+			wantErr:      false,
+		},
+		{
+			name:         "Windows machine",
+			localStorage: &LocalStorage{Source: LocalStorageTempDisk, MountPath: "/var/lib/containerd"},
+			osDisk:       OSDisk{OSType: "Windows"},
+			wantErr:      true,
+		},
+		{
+			name:         "root mount path",
+			localStorage: &LocalStorage{Source: LocalStorageTempDisk, MountPath: "/"},
+			osDisk:       OSDisk{OSType: "Linux"},
+			wantErr:      true,
+		},
+		{
+			name:         "unclean mount path",
+			localStorage: &LocalStorage{Source: LocalStorageTempDisk, MountPath: "/var/lib/../etcd/"},
+			osDisk:       OSDisk{OSType: "Linux"},
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateLocalStorage(tc.localStorage, tc.osDisk, field.NewPath("localStorage"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateFileStorage(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		fileStorage *FileStorage
+		osDisk      OSDisk
+		wantErr     bool
+	}{
+		{
+			name:        "nil",
+			fileStorage: nil,
+			osDisk:      OSDisk{OSType: "Linux"},
+			wantErr:     false,
+		},
+		{
+			name:        "SMB and blobfuse on Linux",
+			fileStorage: &FileStorage{Protocols: []FileStorageProtocol{FileStorageSMB, FileStorageBlobfuse}},
+			osDisk:      OSDisk{OSType: "Linux"},
+			wantErr:     false,
+		},
+		{
+			name:        "Windows machine",
+			fileStorage: &FileStorage{Protocols: []FileStorageProtocol{FileStorageSMB}},
+			osDisk:      OSDisk{OSType: "Windows"},
+			wantErr:     true,
+		},
+		{
+			name:        "no protocol",
+			fileStorage: &FileStorage{},
+			osDisk:      OSDisk{OSType: "Linux"},
+			wantErr:     true,
+		},
+		{
+			name:        "duplicate protocol",
+			fileStorage: &FileStorage{Protocols: []FileStorageProtocol{FileStorageNFS, FileStorageNFS}},
+			osDisk:      OSDisk{OSType: "Linux"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateFileStorage(tc.fileStorage, tc.osDisk, field.NewPath("fileStorage"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateCACertificates(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name           string
+		caCertificates *CACertificates
+		osDisk         OSDisk
+		wantErr        bool
+	}{
+		{
+			name:           "nil",
+			caCertificates: nil,
+			osDisk:         OSDisk{OSType: "Linux"},
+			wantErr:        false,
+		},
+		{
+			name:           "ConfigMap on Linux",
+			caCertificates: &CACertificates{ConfigMapRef: &CACertificatesReference{Name: "trust"}},
+			osDisk:         OSDisk{OSType: "Linux"},
+			wantErr:        false,
+		},
+		{
+			name:           "Secret with a key on Linux",
+			caCertificates: &CACertificates{SecretRef: &CACertificatesReference{Name: "trust", Key: "bundle.pem"}},
+			osDisk:         OSDisk{OSType: "Linux"},
+			wantErr:        false,
+		},
+		{
+			name:           "Windows machine",
+			caCertificates: &CACertificates{ConfigMapRef: &CACertificatesReference{Name: "trust"}},
+			osDisk:         OSDisk{OSType: "Windows"},
+			wantErr:        true,
+		},
+		{
+			name:           "no reference",
+			caCertificates: &CACertificates{},
+			osDisk:         OSDisk{OSType: "Linux"},
+			wantErr:        true,
+		},
+		{
+			name: "both ConfigMap and Secret",
+			caCertificates: &CACertificates{
+				ConfigMapRef: &CACertificatesReference{Name: "trust"},
+				SecretRef:    &CACertificatesReference{Name: "trust"},
+			},
+			osDisk:  OSDisk{OSType: "Linux"},
+			wantErr: true,
+		},
+		{
+			name:           "reference without a name",
+			caCertificates: &CACertificates{SecretRef: &CACertificatesReference{Key: "ca.crt"}},
+			osDisk:         OSDisk{OSType: "Linux"},
+			wantErr:        true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateCACertificates(tc.caCertificates, tc.osDisk, field.NewPath("caCertificates"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateWriteAccelerator(t *testing.T) {
+	g := NewWithT(t)
+
+	premiumDisk := &ManagedDiskParameters{StorageAccountType: "Premium_LRS"}
+	tests := []struct {
+		name                    string
+		writeAcceleratorEnabled *bool
+		cachingType             string
+		managedDisk             *ManagedDiskParameters
+		wantErr                 bool
+	}{
+		{
+			name:                    "nil",
+			writeAcceleratorEnabled: nil,
+			cachingType:             "ReadWrite",
+			managedDisk:             nil,
+			wantErr:                 false,
+		},
+		{
+			name:                    "disabled",
+			writeAcceleratorEnabled: to.Ptr(false),
+			cachingType:             "ReadWrite",
+			managedDisk:             &ManagedDiskParameters{StorageAccountType: "Standard_LRS"},
+			wantErr:                 false,
+		},
+		{
+			name:                    "Premium_LRS disk without caching",
+			writeAcceleratorEnabled: to.Ptr(true),
+			cachingType:             "None",
+			managedDisk:             premiumDisk,
+			wantErr:                 false,
+		},
+		{
+			name:                    "Premium_LRS disk with read caching",
+			writeAcceleratorEnabled: to.Ptr(true),
+			cachingType:             "ReadOnly",
+			managedDisk:             premiumDisk,
+			wantErr:                 false,
+		},
+		{
+			name:                    "Premium_LRS disk with write caching",
+			writeAcceleratorEnabled: to.Ptr(true),
+			cachingType:             "ReadWrite",
+			managedDisk:             premiumDisk,
+			wantErr:                 true,
+		},
+		{
+			name:                    "StandardSSD_LRS disk",
+			writeAcceleratorEnabled: to.Ptr(true),
+			cachingType:             "None",
+			managedDisk:             &ManagedDiskParameters{StorageAccountType: "StandardSSD_LRS"},
+			wantErr:                 true,
+		},
+		{
+			name:                    "no managed disk parameters",
+			writeAcceleratorEnabled: to.Ptr(true),
+			cachingType:             "None",
+			managedDisk:             nil,
+			wantErr:                 true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateWriteAccelerator(tc.writeAcceleratorEnabled, tc.cachingType, tc.managedDisk, field.NewPath("osDisk"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateDataDisksUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name     string
+		disks    []DataDisk
+		oldDisks []DataDisk
+		wantErr  bool
+	}{
+		{
+			name:     "valid nil data disks",
+			disks:    nil,
+			oldDisks: nil,
+			wantErr:  false,
+		},
+		{
+			name:     "valid empty data disks",
+			disks:    []DataDisk{},
+			oldDisks: []DataDisk{},
+			wantErr:  false,
+		},
+		{
+			name: "valid data disk updates",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk",
+					DiskSizeGB: 64,
+					Lun:        to.Ptr[int32](0),
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+				{
+					NameSuffix: "my_other_disk",
+					DiskSizeGB: 64,
+					Lun:        to.Ptr[int32](1),
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix: "my_disk",
+					DiskSizeGB: 64,
+					Lun:        to.Ptr[int32](0),
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+				{
+					NameSuffix: "my_other_disk",
+					DiskSizeGB: 64,
+					Lun:        to.Ptr[int32](1),
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "cannot update data disk fields after machine creation",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 128,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validate updates to optional fields",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 128,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					Lun: to.Ptr[int32](0),
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix:  "my_disk_1",
+					DiskSizeGB:  128,
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "data disks cannot be added after machine creation",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+				{
+					NameSuffix: "my_disk_2",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+					Lun:         to.Ptr[int32](2),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "data disks cannot be removed after machine creation",
+			disks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+				{
+					NameSuffix: "my_disk_2",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+					Lun:         to.Ptr[int32](2),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			oldDisks: []DataDisk{
+				{
+					NameSuffix: "my_disk_1",
+					DiskSizeGB: 64,
+					ManagedDisk: &ManagedDiskParameters{
+						StorageAccountType: "Standard_LRS",
+					},
+					Lun:         to.Ptr[int32](0),
+					CachingType: string(armcompute.PossibleCachingTypesValues()[0]),
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateDataDisksUpdate(test.oldDisks, test.disks, field.NewPath("dataDisks"))
+			if test.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateDiskSnapshots(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name      string
+		snapshots *DiskSnapshots
+		osDisk    OSDisk
+		wantErr   bool
+	}{
+		{
+			name:      "nil",
+			snapshots: nil,
+			osDisk:    OSDisk{OSType: "Linux"},
+			wantErr:   false,
+		},
+		{
+			name:      "daily snapshots of all disks",
+			snapshots: &DiskSnapshots{Interval: &metav1.Duration{Duration: 24 * time.Hour}, IncludeDataDisks: true, Retain: 7},
+			osDisk:    OSDisk{OSType: "Linux"},
+			wantErr:   false,
+		},
+		{
+			name:      "interval shorter than an hour",
+			snapshots: &DiskSnapshots{Interval: &metav1.Duration{Duration: 30 * time.Minute}},
+			osDisk:    OSDisk{OSType: "Linux"},
+			wantErr:   true,
+		},
+		{
+			name:      "negative retain",
+			snapshots: &DiskSnapshots{Retain: -1},
+			osDisk:    OSDisk{OSType: "Linux"},
+			wantErr:   true,
+		},
+		{
+			name:      "ephemeral OS disk",
+			snapshots: &DiskSnapshots{},
+			osDisk:    OSDisk{OSType: "Linux", DiffDiskSettings: &DiffDiskSettings{Option: string(armcompute.DiffDiskOptionsLocal)}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDiskSnapshots(tc.snapshots, tc.osDisk, field.NewPath("diskSnapshots"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateEtcdDataDisk(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name      string
+		etcdDisk  *EtcdDataDisk
+		dataDisks []DataDisk
+		wantErr   bool
+	}{
+		{
+			name:     "nil",
+			etcdDisk: nil,
+			wantErr:  false,
+		},
+		{
+			name:      "default etcd data disk",
+			etcdDisk:  &EtcdDataDisk{},
+			dataDisks: []DataDisk{(&EtcdDataDisk{}).DataDisk()},
+			wantErr:   false,
+		},
+		{
+			name:     "128 GB Premium SSD disk",
+			etcdDisk: &EtcdDataDisk{DiskSizeGB: 128},
+			wantErr:  false,
+		},
+		{
+			name:     "64 GB Premium SSD disk",
+			etcdDisk: &EtcdDataDisk{DiskSizeGB: 64},
+			wantErr:  true,
+		},
+		{
+			name:     "64 GB Standard SSD disk",
+			etcdDisk: &EtcdDataDisk{DiskSizeGB: 64, StorageAccountType: "StandardSSD_LRS"},
+			wantErr:  false,
+		},
+		{
+			name:     "Standard HDD disk",
+			etcdDisk: &EtcdDataDisk{StorageAccountType: "Standard_LRS"},
+			wantErr:  true,
+		},
+		{
+			name:      "data disk conflicting with the etcd data disk",
+			etcdDisk:  &EtcdDataDisk{},
+			dataDisks: []DataDisk{{NameSuffix: "etcddisk", DiskSizeGB: 128, Lun: to.Ptr[int32](0)}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateEtcdDataDisk(tc.etcdDisk, tc.dataDisks, field.NewPath("etcdDataDisk"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestDiskIOPS(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(DiskIOPS("Premium_LRS", 100)).To(Equal(int32(500)))
+	g.Expect(DiskIOPS("Premium_LRS", 256)).To(Equal(int32(1100)))
+	g.Expect(DiskIOPS("Premium_ZRS", 2048)).To(Equal(int32(7500)))
+	g.Expect(DiskIOPS("StandardSSD_LRS", 32)).To(Equal(int32(500)))
+	g.Expect(DiskIOPS("StandardSSD_LRS", 8192)).To(Equal(int32(2000)))
+	g.Expect(DiskIOPS("Standard_LRS", 256)).To(Equal(int32(0)))
+}
+
+func TestAzureMachine_ValidateBackendPoolDrainTimeout(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		timeout *metav1.Duration
+		wantErr bool
+	}{
+		{
+			name:    "nil",
+			timeout: nil,
+			wantErr: false,
+		},
+		{
+			name:    "zero",
+			timeout: &metav1.Duration{},
+			wantErr: false,
+		},
+		{
+			name:    "five minutes",
+			timeout: &metav1.Duration{Duration: 5 * time.Minute},
+			wantErr: false,
+		},
+		{
+			name:    "negative",
+			timeout: &metav1.Duration{Duration: -time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "longer than 30 minutes",
+			timeout: &metav1.Duration{Duration: time.Hour},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateBackendPoolDrainTimeout(tc.timeout, field.NewPath("backendPoolDrainTimeout"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateTombstonePolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		policy  *TombstonePolicy
+		wantErr bool
+	}{
+		{
+			name:    "nil",
+			policy:  nil,
+			wantErr: false,
+		},
+		{
+			name:    "one day",
+			policy:  &TombstonePolicy{GracePeriod: metav1.Duration{Duration: 24 * time.Hour}, Deallocate: true},
+			wantErr: false,
+		},
+		{
+			name:    "negative",
+			policy:  &TombstonePolicy{GracePeriod: metav1.Duration{Duration: -time.Second}},
+			wantErr: true,
+		},
+		{
+			name:    "longer than 30 days",
+			policy:  &TombstonePolicy{GracePeriod: metav1.Duration{Duration: 31 * 24 * time.Hour}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTombstonePolicy(tc.policy, field.NewPath("tombstonePolicy"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateDerivedResources(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name             string
+		derived          *DerivedResources
+		osDisk           OSDisk
+		allocatePublicIP bool
+		wantErr          bool
+	}{
+		{
+			name:    "nil",
+			derived: nil,
+			wantErr: false,
+		},
+		{
+			name: "all overrides",
+			derived: &DerivedResources{
+				NetworkInterface: &DerivedResource{NamePrefix: "nic-", NameSuffix: ""},
+				OSDisk:           &DerivedResource{AdditionalTags: Tags{"costcenter": "storage"}},
+				PublicIP:         &DerivedResource{NameSuffix: "-pip"},
+			},
+			allocatePublicIP: true,
+			wantErr:          false,
+		},
+		{
+			name: "OS disk override with an ephemeral OS disk",
+			derived: &DerivedResources{
+				OSDisk: &DerivedResource{AdditionalTags: Tags{"costcenter": "storage"}},
+			},
+			osDisk:  OSDisk{DiffDiskSettings: &DiffDiskSettings{Option: "Local"}},
+			wantErr: true,
+		},
+		{
+			name: "public IP override without a public IP",
+			derived: &DerivedResources{
+				PublicIP: &DerivedResource{NameSuffix: "-pip"},
+			},
+			allocatePublicIP: false,
+			wantErr:          true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateDerivedResources(tc.derived, tc.osDisk, tc.allocatePublicIP, field.NewPath("derivedResources"))
+			if tc.wantErr {
+				g.Expect(err).NotTo(BeEmpty())
+			} else {
+				g.Expect(err).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateHibernation(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name              string
+		desiredPowerState PowerState
+		capabilities      *AdditionalCapabilities
+		spotVMOptions     *SpotVMOptions
+		wantErr           bool
+	}{
+		{
+			name:    "not hibernated",
+			wantErr: false,
+		},
+		{
+			name:              "hibernated with hibernation enabled",
+			desiredPowerState: PowerStateHibernated,
+			capabilities:      &AdditionalCapabilities{HibernationEnabled: to.Ptr(true)},
+			wantErr:           false,
+		},
+		{
+			name:              "hibernated without hibernation enabled",
+			desiredPowerState: PowerStateHibernated,
+			capabilities:      &AdditionalCapabilities{HibernationEnabled: to.Ptr(false)},
+			wantErr:           true,
+		},
+		{
+			name:          "hibernation enabled on a Spot VM",
+			capabilities:  &AdditionalCapabilities{HibernationEnabled: to.Ptr(true)},
+			spotVMOptions: &SpotVMOptions{},
+			wantErr:       true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateHibernation(tc.desiredPowerState, tc.capabilities, tc.spotVMOptions, field.NewPath("desiredPowerState"))
+			if tc.wantErr {
 				g.Expect(err).NotTo(BeEmpty())
 			} else {
 				g.Expect(err).To(BeEmpty())
@@ -22,12 +22,17 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	webhookutil "sigs.k8s.io/cluster-api-provider-azure/util/webhook"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 // SetupWebhookWithManager sets up and registers the webhook with the manager.
 func (m *AzureMachine) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if err := webhookutil.RegisterValidatingWebhookWithWarnings(mgr, m); err != nil {
+		return err
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(m).
 		Complete()
@@ -87,7 +92,8 @@ func (m *AzureMachine) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
-	if !reflect.DeepEqual(m.Spec.DataDisks, old.Spec.DataDisks) {
+	// The delete policy of data disks can be changed, e.g. to retain a disk before deleting its machine.
+	if !reflect.DeepEqual(withoutDeletePolicy(m.Spec.DataDisks), withoutDeletePolicy(old.Spec.DataDisks)) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "dataDisks"),
 				m.Spec.DataDisks, "field is immutable"),
@@ -101,6 +107,13 @@ func (m *AzureMachine) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
+	if !reflect.DeepEqual(m.Spec.AdditionalSSHPublicKeys, old.Spec.AdditionalSSHPublicKeys) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "additionalSSHPublicKeys"),
+				m.Spec.AdditionalSSHPublicKeys, "field is immutable"),
+		)
+	}
+
 	if !reflect.DeepEqual(m.Spec.AllocatePublicIP, old.Spec.AllocatePublicIP) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "allocatePublicIP"),
@@ -136,6 +149,80 @@ func (m *AzureMachine) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
+	if !reflect.DeepEqual(m.Spec.UserData, old.Spec.UserData) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "userData"),
+				m.Spec.UserData, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(m.Spec.MultiInstanceGPU, old.Spec.MultiInstanceGPU) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "multiInstanceGPU"),
+				m.Spec.MultiInstanceGPU, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(m.Spec.FileStorage, old.Spec.FileStorage) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "fileStorage"),
+				m.Spec.FileStorage, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(m.Spec.CACertificates, old.Spec.CACertificates) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "caCertificates"),
+				m.Spec.CACertificates, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(m.Spec.LocalStorage, old.Spec.LocalStorage) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "localStorage"),
+				m.Spec.LocalStorage, "field is immutable"),
+		)
+	}
+
+	if m.Spec.DiskControllerType != old.Spec.DiskControllerType {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "diskControllerType"),
+				m.Spec.DiskControllerType, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(m.Spec.EtcdDataDisk, old.Spec.EtcdDataDisk) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "etcdDataDisk"),
+				m.Spec.EtcdDataDisk, "field is immutable"),
+		)
+	}
+
+	allErrs = append(allErrs, ValidateDiskSnapshots(m.Spec.DiskSnapshots, m.Spec.OSDisk, field.NewPath("spec", "diskSnapshots"))...)
+
+	// A machine can be enrolled in a backup policy and moved to another policy of its vault, but it can't leave its
+	// vault as its recovery points stay there.
+	if old.Spec.BackupProtection != nil && (m.Spec.BackupProtection == nil || m.Spec.BackupProtection.VaultID != old.Spec.BackupProtection.VaultID) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "backupProtection", "vaultID"),
+				m.Spec.BackupProtection, "field is immutable"),
+		)
+	}
+	allErrs = append(allErrs, ValidateBackupProtection(m.Spec.BackupProtection, field.NewPath("spec", "backupProtection"))...)
+
+	allErrs = append(allErrs, ValidateBackendPoolDrainTimeout(m.Spec.BackendPoolDrainTimeout, field.NewPath("spec", "backendPoolDrainTimeout"))...)
+
+	allErrs = append(allErrs, ValidateTombstonePolicy(m.Spec.TombstonePolicy, field.NewPath("spec", "tombstonePolicy"))...)
+
+	// The additional tags of derived resources can be changed, but their names can't as the resources already exist.
+	if !reflect.DeepEqual(withoutAdditionalTags(m.Spec.DerivedResources), withoutAdditionalTags(old.Spec.DerivedResources)) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "derivedResources"),
+				m.Spec.DerivedResources, "names of derived resources are immutable"),
+		)
+	}
+	allErrs = append(allErrs, ValidateDerivedResources(m.Spec.DerivedResources, m.Spec.OSDisk, m.Spec.AllocatePublicIP, field.NewPath("spec", "derivedResources"))...)
+
 	if !reflect.DeepEqual(m.Spec.NetworkInterfaces, old.Spec.NetworkInterfaces) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "networkInterfaces"),
@@ -149,6 +236,33 @@ func (m *AzureMachine) ValidateUpdate(oldRaw runtime.Object) error {
 	return apierrors.NewInvalid(GroupVersion.WithKind("AzureMachine").GroupKind(), m.Name, allErrs)
 }
 
+// withoutDeletePolicy returns a copy of the data disks without their delete policy.
+func withoutDeletePolicy(dataDisks []DataDisk) []DataDisk {
+	if dataDisks == nil {
+		return nil
+	}
+	disks := make([]DataDisk, len(dataDisks))
+	for i, disk := range dataDisks {
+		disks[i] = *disk.DeepCopy()
+		disks[i].DeletePolicy = ""
+	}
+	return disks
+}
+
+// withoutAdditionalTags returns the names of the derived resources, without their additional tags.
+func withoutAdditionalTags(derived *DerivedResources) [3]DerivedResource {
+	var names [3]DerivedResource
+	if derived == nil {
+		return names
+	}
+	for i, override := range []*DerivedResource{derived.NetworkInterface, derived.OSDisk, derived.PublicIP} {
+		if override != nil {
+			names[i] = DerivedResource{NamePrefix: override.NamePrefix, NameSuffix: override.NameSuffix}
+		}
+	}
+	return names
+}
+
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
 func (m *AzureMachine) ValidateDelete() error {
 	return nil
@@ -158,3 +272,22 @@ func (m *AzureMachine) ValidateDelete() error {
 func (m *AzureMachine) Default() {
 	m.Spec.SetDefaults()
 }
+
+var _ webhookutil.Warner = &AzureMachine{}
+
+// WarningsOnCreate implements webhookutil.Warner so admission warnings are returned for the type.
+func (m *AzureMachine) WarningsOnCreate() []string {
+	return m.warnings()
+}
+
+// WarningsOnUpdate implements webhookutil.Warner so admission warnings are returned for the type.
+func (m *AzureMachine) WarningsOnUpdate(_ runtime.Object) []string {
+	return m.warnings()
+}
+
+// warnings returns the admission warnings for deprecated fields and risky configurations of the AzureMachine.
+func (m *AzureMachine) warnings() []string {
+	_, isControlPlane := m.Labels[clusterv1.MachineControlPlaneLabelName]
+	warnings := ImageWarnings(m.Spec.Image, field.NewPath("spec", "image"))
+	return append(warnings, SpotControlPlaneWarnings(isControlPlane, m.Spec.SpotVMOptions, field.NewPath("spec", "spotVMOptions"))...)
+}
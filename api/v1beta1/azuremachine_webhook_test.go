@@ -18,11 +18,14 @@ package v1beta1
 
 import (
 	"testing"
+	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 var (
@@ -76,7 +79,7 @@ func TestAzureMachine_ValidateCreate(t *testing.T) {
 		{
 			name:    "azuremachine without SSHPublicKey",
 			machine: createMachineWithSSHPublicKey(""),
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name:    "azuremachine with invalid SSHPublicKey",
@@ -95,7 +98,7 @@ func TestAzureMachine_ValidateCreate(t *testing.T) {
 		},
 		{
 			name:    "azuremachine with valid osDisk cache type",
-			machine: createMachineWithOsDiskCacheType(string(compute.PossibleCachingTypesValues()[1])),
+			machine: createMachineWithOsDiskCacheType(string(armcompute.PossibleCachingTypesValues()[1])),
 			wantErr: false,
 		},
 		{
@@ -348,6 +351,164 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "validTest: azuremachine.spec.DataDisks deletePolicy is mutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DataDisks: []DataDisk{
+						{
+							DiskSizeGB: 128,
+						},
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DataDisks: []DataDisk{
+						{
+							DiskSizeGB:   128,
+							DeletePolicy: DiskDeletePolicyDetach,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "validTest: azuremachine.spec.DiskSnapshots is mutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DiskSnapshots: &DiskSnapshots{
+						Interval: &metav1.Duration{Duration: 24 * time.Hour},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.DiskSnapshots interval is too short",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DiskSnapshots: &DiskSnapshots{
+						Interval: &metav1.Duration{Duration: time.Minute},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validTest: azuremachine.spec.BackupProtection can be set",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					BackupProtection: &BackupProtection{
+						VaultID: "/subscriptions/123/resourceGroups/backup-rg/providers/Microsoft.RecoveryServices/vaults/my-vault",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "validTest: azuremachine.spec.BackupProtection policyName is mutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					BackupProtection: &BackupProtection{
+						VaultID: "/subscriptions/123/resourceGroups/backup-rg/providers/Microsoft.RecoveryServices/vaults/my-vault",
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					BackupProtection: &BackupProtection{
+						VaultID:    "/subscriptions/123/resourceGroups/backup-rg/providers/Microsoft.RecoveryServices/vaults/my-vault",
+						PolicyName: "etcd",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.BackupProtection vaultID must be a Recovery Services vault",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					BackupProtection: &BackupProtection{
+						VaultID: "/subscriptions/123/resourceGroups/backup-rg/providers/Microsoft.DataProtection/backupVaults/my-vault",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.BackupProtection vaultID is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					BackupProtection: &BackupProtection{
+						VaultID: "/subscriptions/123/resourceGroups/backup-rg/providers/Microsoft.RecoveryServices/vaults/my-vault",
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					BackupProtection: &BackupProtection{
+						VaultID: "/subscriptions/123/resourceGroups/backup-rg/providers/Microsoft.RecoveryServices/vaults/other-vault",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.BackupProtection can't be removed",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					BackupProtection: &BackupProtection{
+						VaultID: "/subscriptions/123/resourceGroups/backup-rg/providers/Microsoft.RecoveryServices/vaults/my-vault",
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.EtcdDataDisk is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					EtcdDataDisk: &EtcdDataDisk{},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					EtcdDataDisk: &EtcdDataDisk{DiskSizeGB: 512},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.DiskControllerType is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DiskControllerType: DiskControllerTypeSCSI,
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DiskControllerType: DiskControllerTypeNVMe,
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalidTest: azuremachine.spec.SSHPublicKey is immutable",
 			oldMachine: &AzureMachine{
@@ -376,6 +537,20 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalidTest: azuremachine.spec.AdditionalSSHPublicKeys is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					AdditionalSSHPublicKeys: []string{"validKey"},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					AdditionalSSHPublicKeys: []string{"validKey", "otherKey"},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalidTest: azuremachine.spec.AllocatePublicIP is immutable",
 			oldMachine: &AzureMachine{
@@ -552,6 +727,43 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "validTest: azuremachine.spec.derivedResources additional tags can be changed",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DerivedResources: &DerivedResources{
+						OSDisk: &DerivedResource{NameSuffix: "-osdisk", AdditionalTags: Tags{"costcenter": "a"}},
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DerivedResources: &DerivedResources{
+						NetworkInterface: &DerivedResource{AdditionalTags: Tags{"costcenter": "b"}},
+						OSDisk:           &DerivedResource{NameSuffix: "-osdisk", AdditionalTags: Tags{"costcenter": "b"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.derivedResources names are immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DerivedResources: &DerivedResources{
+						OSDisk: &DerivedResource{NameSuffix: "-osdisk"},
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DerivedResources: &DerivedResources{
+						OSDisk: &DerivedResource{NameSuffix: "-disk"},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -565,6 +777,25 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 	}
 }
 
+func TestAzureMachine_Warnings(t *testing.T) {
+	g := NewWithT(t)
+
+	marketplace := createMachineWithMarketPlaceImage("PUB1234", "OFFER1234", "SKU1234", "1.0.0")
+	g.Expect(marketplace.WarningsOnCreate()).To(BeEmpty())
+
+	shared := createMachineWithSharedImage("SUB123", "RG123", "NAME123", "GALLERY1", "1.0.0")
+	g.Expect(shared.WarningsOnCreate()).To(ConsistOf(
+		"spec.image.sharedGallery is deprecated and will be removed in a future API version, use spec.image.computeGallery instead"))
+	g.Expect(shared.WarningsOnUpdate(shared.DeepCopy())).To(HaveLen(1))
+
+	// Spot VMs are only risky on control plane machines.
+	spot := createMachineWithMarketPlaceImage("PUB1234", "OFFER1234", "SKU1234", "1.0.0")
+	spot.Spec.SpotVMOptions = &SpotVMOptions{}
+	g.Expect(spot.WarningsOnCreate()).To(BeEmpty())
+	spot.Labels = map[string]string{clusterv1.MachineControlPlaneLabelName: ""}
+	g.Expect(spot.WarningsOnCreate()).To(ConsistOf(ContainSubstring("spec.spotVMOptions is set on a control plane machine")))
+}
+
 func TestAzureMachine_Default(t *testing.T) {
 	g := NewWithT(t)
 
@@ -579,14 +810,15 @@ func TestAzureMachine_Default(t *testing.T) {
 	publicKeyExistTest.machine.Default()
 	g.Expect(publicKeyExistTest.machine.Spec.SSHPublicKey).To(Equal(existingPublicKey))
 
+	// Machines without an SSH public key use the public key of the cluster.
 	publicKeyNotExistTest.machine.Default()
-	g.Expect(publicKeyNotExistTest.machine.Spec.SSHPublicKey).To(Not(BeEmpty()))
+	g.Expect(publicKeyNotExistTest.machine.Spec.SSHPublicKey).To(BeEmpty())
 
 	cacheTypeNotSpecifiedTest := test{machine: &AzureMachine{Spec: AzureMachineSpec{OSDisk: OSDisk{CachingType: ""}}}}
 	cacheTypeNotSpecifiedTest.machine.Default()
 	g.Expect(cacheTypeNotSpecifiedTest.machine.Spec.OSDisk.CachingType).To(Equal("None"))
 
-	for _, possibleCachingType := range compute.PossibleCachingTypesValues() {
+	for _, possibleCachingType := range armcompute.PossibleCachingTypesValues() {
 		cacheTypeSpecifiedTest := test{machine: &AzureMachine{Spec: AzureMachineSpec{OSDisk: OSDisk{CachingType: string(possibleCachingType)}}}}
 		cacheTypeSpecifiedTest.machine.Default()
 		g.Expect(cacheTypeSpecifiedTest.machine.Spec.OSDisk.CachingType).To(Equal(string(possibleCachingType)))
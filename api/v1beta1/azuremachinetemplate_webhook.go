@@ -22,6 +22,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	webhookutil "sigs.k8s.io/cluster-api-provider-azure/util/webhook"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -30,10 +31,16 @@ import (
 const (
 	AzureMachineTemplateImmutableMsg          = "AzureMachineTemplate spec.template.spec field is immutable. Please create new resource instead. ref doc: https://cluster-api.sigs.k8s.io/tasks/updating-machine-templates.html"
 	AzureMachineTemplateRoleAssignmentNameMsg = "AzureMachineTemplate spec.template.spec.roleAssignmentName field can't be set"
+	// AzureMachineTemplateInternalDNSNameLabelMsg is returned when a template sets an internal DNS name label, which must be
+	// unique within the virtual network and so can't be shared by all the machines of the template.
+	AzureMachineTemplateInternalDNSNameLabelMsg = "AzureMachineTemplate spec.template.spec.networkInterfaces internalDNSNameLabel field can't be set"
 )
 
 // SetupWebhookWithManager sets up and registers the webhook with the manager.
 func (r *AzureMachineTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if err := webhookutil.RegisterValidatingWebhookWithWarnings(mgr, r); err != nil {
+		return err
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -61,6 +68,14 @@ func (r *AzureMachineTemplate) ValidateCreate() error {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("AzureMachineTemplate", "spec", "template", "spec", "networkInterfaces"), r.Spec.Template.Spec.NetworkInterfaces, "cannot set both NetworkInterfaces and machine SubnetName"))
 	}
 
+	for i, nic := range r.Spec.Template.Spec.NetworkInterfaces {
+		if nic.InternalDNSNameLabel != "" {
+			allErrs = append(allErrs,
+				field.Forbidden(field.NewPath("AzureMachineTemplate", "spec", "template", "spec", "networkInterfaces").Index(i).Child("internalDNSNameLabel"), AzureMachineTemplateInternalDNSNameLabelMsg),
+			)
+		}
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -80,7 +95,7 @@ func (r *AzureMachineTemplate) ValidateUpdate(oldRaw runtime.Object) error {
 		// in object inequality. To workaround this, we set the v1beta1 defaults here so that the old object also gets
 		// the new defaults.
 
-		// We need to set ssh key explicitly, otherwise Default() will create a new one.
+		// Templates of older versions may have no ssh key while the new object has the one it was defaulted to.
 		if old.Spec.Template.Spec.SSHPublicKey == "" {
 			old.Spec.Template.Spec.SSHPublicKey = r.Spec.Template.Spec.SSHPublicKey
 		}
@@ -108,9 +123,23 @@ func (r *AzureMachineTemplate) ValidateDelete() error {
 
 // Default implements webhookutil.defaulter so a webhook will be registered for the type.
 func (r *AzureMachineTemplate) Default() {
-	if err := r.Spec.Template.Spec.SetDefaultSSHPublicKey(); err != nil {
-		ctrl.Log.WithName("SetDefault").Error(err, "SetDefaultSSHPublicKey failed")
-	}
 	r.Spec.Template.Spec.SetDefaultCachingType()
 	r.Spec.Template.Spec.SetDataDisksDefaults()
 }
+
+var _ webhookutil.Warner = &AzureMachineTemplate{}
+
+// WarningsOnCreate implements webhookutil.Warner so admission warnings are returned for the type.
+func (r *AzureMachineTemplate) WarningsOnCreate() []string {
+	return r.warnings()
+}
+
+// WarningsOnUpdate implements webhookutil.Warner so admission warnings are returned for the type.
+func (r *AzureMachineTemplate) WarningsOnUpdate(_ runtime.Object) []string {
+	return r.warnings()
+}
+
+// warnings returns the admission warnings for deprecated fields and risky configurations of the AzureMachineTemplate.
+func (r *AzureMachineTemplate) warnings() []string {
+	return ImageWarnings(r.Spec.Template.Spec.Image, field.NewPath("spec", "template", "spec", "image"))
+}
@@ -19,8 +19,8 @@ package v1beta1
 import (
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -87,7 +87,7 @@ func TestAzureMachineTemplate_ValidateCreate(t *testing.T) {
 			machineTemplate: createAzureMachineTemplateFromMachine(
 				createMachineWithSSHPublicKey(""),
 			),
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "azuremachinetemplate with invalid SSHPublicKey",
@@ -113,7 +113,7 @@ func TestAzureMachineTemplate_ValidateCreate(t *testing.T) {
 		{
 			name: "azuremachinetemplate with valid osDisk cache type",
 			machineTemplate: createAzureMachineTemplateFromMachine(
-				createMachineWithOsDiskCacheType(string(compute.PossibleCachingTypesValues()[1])),
+				createMachineWithOsDiskCacheType(string(armcompute.PossibleCachingTypesValues()[1])),
 			),
 			wantErr: false,
 		},
@@ -129,6 +129,20 @@ func TestAzureMachineTemplate_ValidateCreate(t *testing.T) {
 			machineTemplate: createAzureMachineTemplateFromMachine(createMachineWithRoleAssignmentName()),
 			wantErr:         true,
 		},
+		{
+			name: "azuremachinetemplate with DNS servers",
+			machineTemplate: createAzureMachineTemplateFromMachine(
+				createMachineWithNetworkConfig("", []AzureNetworkInterface{{SubnetName: "subnet", DNSServers: []string{"10.0.0.4"}}}),
+			),
+			wantErr: false,
+		},
+		{
+			name: "azuremachinetemplate with internal DNS name label",
+			machineTemplate: createAzureMachineTemplateFromMachine(
+				createMachineWithNetworkConfig("", []AzureNetworkInterface{{SubnetName: "subnet", InternalDNSNameLabel: "win-node"}}),
+			),
+			wantErr: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -163,7 +177,7 @@ func TestAzureMachineTemplate_ValidateUpdate(t *testing.T) {
 							FailureDomain: &failureDomain,
 							OSDisk: OSDisk{
 								OSType:     "type",
-								DiskSizeGB: to.Int32Ptr(11),
+								DiskSizeGB: to.Ptr[int32](11),
 							},
 							DataDisks:    []DataDisk{},
 							SSHPublicKey: "",
@@ -179,7 +193,7 @@ func TestAzureMachineTemplate_ValidateUpdate(t *testing.T) {
 							FailureDomain: &failureDomain,
 							OSDisk: OSDisk{
 								OSType:     "type",
-								DiskSizeGB: to.Int32Ptr(11),
+								DiskSizeGB: to.Ptr[int32](11),
 							},
 							DataDisks:    []DataDisk{},
 							SSHPublicKey: "fake ssh key",
@@ -199,7 +213,7 @@ func TestAzureMachineTemplate_ValidateUpdate(t *testing.T) {
 							FailureDomain: &failureDomain,
 							OSDisk: OSDisk{
 								OSType:     "type",
-								DiskSizeGB: to.Int32Ptr(11),
+								DiskSizeGB: to.Ptr[int32](11),
 							},
 							DataDisks:    []DataDisk{},
 							SSHPublicKey: "fake ssh key",
@@ -218,7 +232,7 @@ func TestAzureMachineTemplate_ValidateUpdate(t *testing.T) {
 							FailureDomain: &failureDomain,
 							OSDisk: OSDisk{
 								OSType:     "type",
-								DiskSizeGB: to.Int32Ptr(11),
+								DiskSizeGB: to.Ptr[int32](11),
 							},
 							DataDisks:    []DataDisk{},
 							SSHPublicKey: "fake ssh key",
@@ -241,7 +255,7 @@ func TestAzureMachineTemplate_ValidateUpdate(t *testing.T) {
 							FailureDomain: &failureDomain,
 							OSDisk: OSDisk{
 								OSType:      "type",
-								DiskSizeGB:  to.Int32Ptr(11),
+								DiskSizeGB:  to.Ptr[int32](11),
 								CachingType: "",
 							},
 							DataDisks:    []DataDisk{},
@@ -261,7 +275,7 @@ func TestAzureMachineTemplate_ValidateUpdate(t *testing.T) {
 							FailureDomain: &failureDomain,
 							OSDisk: OSDisk{
 								OSType:      "type",
-								DiskSizeGB:  to.Int32Ptr(11),
+								DiskSizeGB:  to.Ptr[int32](11),
 								CachingType: "None",
 							},
 							DataDisks:    []DataDisk{},
@@ -285,7 +299,7 @@ func TestAzureMachineTemplate_ValidateUpdate(t *testing.T) {
 							FailureDomain: &failureDomain,
 							OSDisk: OSDisk{
 								OSType:      "type",
-								DiskSizeGB:  to.Int32Ptr(11),
+								DiskSizeGB:  to.Ptr[int32](11),
 								CachingType: "None",
 							},
 							DataDisks:    []DataDisk{},
@@ -305,7 +319,7 @@ func TestAzureMachineTemplate_ValidateUpdate(t *testing.T) {
 							FailureDomain: &failureDomain,
 							OSDisk: OSDisk{
 								OSType:      "type",
-								DiskSizeGB:  to.Int32Ptr(11),
+								DiskSizeGB:  to.Ptr[int32](11),
 								CachingType: "None",
 							},
 							DataDisks:    []DataDisk{},
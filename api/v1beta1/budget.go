@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// vmSizeVCPUsRegex matches the number of vCPUs in the name of a VM size, e.g. "4" in "Standard_D4s_v3", and the number
+// of active vCPUs of constrained vCPU sizes, e.g. "16" in "Standard_E64-16ds_v4".
+var vmSizeVCPUsRegex = regexp.MustCompile(`^(?i)(?:standard|basic)_[a-z]+(\d+)(?:-(\d+))?`)
+
+// BudgetUsage is the compute resources of virtual machines counted against a budget guardrail.
+type BudgetUsage struct {
+	// VCPUs is the number of vCPUs.
+	VCPUs int64
+	// MicroHourlyCost is the estimated hourly cost, in millionths of the currency.
+	MicroHourlyCost int64
+}
+
+// Add returns the sum of the usages.
+func (u BudgetUsage) Add(other BudgetUsage) BudgetUsage {
+	return BudgetUsage{
+		VCPUs:           u.VCPUs + other.VCPUs,
+		MicroHourlyCost: u.MicroHourlyCost + other.MicroHourlyCost,
+	}
+}
+
+// VMSizeVCPUs returns the number of vCPUs of a VM size parsed from its name, e.g. 4 for Standard_D4s_v3.
+func VMSizeVCPUs(vmSize string) (int64, error) {
+	match := vmSizeVCPUsRegex.FindStringSubmatch(vmSize)
+	if match == nil {
+		return 0, fmt.Errorf("failed to parse the number of vCPUs of VM size %s", vmSize)
+	}
+	n := match[1]
+	if match[2] != "" {
+		n = match[2]
+	}
+	vCPUs, err := strconv.ParseInt(n, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the number of vCPUs of VM size %s: %w", vmSize, err)
+	}
+	// Shared core sizes like Standard_A0 have a single vCPU.
+	if vCPUs < 1 {
+		vCPUs = 1
+	}
+	return vCPUs, nil
+}
+
+// Usage returns the usage of count virtual machines of a VM size.
+func (b *BudgetGuardrail) Usage(vmSize string, count int64) (BudgetUsage, error) {
+	var size *BudgetVMSize
+	for i := range b.VMSizes {
+		if strings.EqualFold(b.VMSizes[i].Name, vmSize) {
+			size = &b.VMSizes[i]
+			break
+		}
+	}
+
+	var vCPUs int64
+	if size != nil && size.VCPUs != nil {
+		vCPUs = *size.VCPUs
+	} else {
+		var err error
+		if vCPUs, err = VMSizeVCPUs(vmSize); err != nil {
+			return BudgetUsage{}, fmt.Errorf("%w, set its vCPUs in spec.budget.vmSizes of the AzureCluster", err)
+		}
+	}
+	usage := BudgetUsage{VCPUs: vCPUs * count}
+
+	if b.MaxHourlyCost == nil {
+		return usage, nil
+	}
+	switch {
+	case size != nil && size.HourlyCost != nil:
+		usage.MicroHourlyCost = size.HourlyCost.ScaledValue(resource.Micro) * count
+	case b.VCPUHourlyCost != nil:
+		usage.MicroHourlyCost = b.VCPUHourlyCost.ScaledValue(resource.Micro) * vCPUs * count
+	default:
+		return BudgetUsage{}, fmt.Errorf("failed to estimate the hourly cost of VM size %s, set spec.budget.vCPUHourlyCost "+
+			"or its hourly cost in spec.budget.vmSizes of the AzureCluster", vmSize)
+	}
+	return usage, nil
+}
+
+// Check returns an error if the usage exceeds the budget guardrail.
+func (b *BudgetGuardrail) Check(usage BudgetUsage) error {
+	if b.MaxVCPUs != nil && usage.VCPUs > *b.MaxVCPUs {
+		return fmt.Errorf("the virtual machines of the cluster would have %d vCPUs, more than the maximum of %d vCPUs of its budget",
+			usage.VCPUs, *b.MaxVCPUs)
+	}
+	if b.MaxHourlyCost != nil && usage.MicroHourlyCost > b.MaxHourlyCost.ScaledValue(resource.Micro) {
+		return fmt.Errorf("the virtual machines of the cluster would have an estimated hourly cost of %s, more than the maximum of %s of its budget",
+			formatMicro(usage.MicroHourlyCost), formatMicro(b.MaxHourlyCost.ScaledValue(resource.Micro)))
+	}
+	return nil
+}
+
+// formatMicro formats an amount in millionths as a decimal number, e.g. "12.5" for 12500000.
+func formatMicro(micro int64) string {
+	return strconv.FormatFloat(float64(micro)/1e6, 'f', -1, 64)
+}
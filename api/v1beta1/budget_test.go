@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+)
+
+func TestVMSizeVCPUs(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		vmSize  string
+		want    int64
+		wantErr bool
+	}{
+		{vmSize: "Standard_D4s_v3", want: 4},
+		{vmSize: "Standard_B1ls", want: 1},
+		{vmSize: "Standard_NC24ads_A100_v4", want: 24},
+		{vmSize: "Standard_E64-16ds_v4", want: 16},
+		{vmSize: "standard_m416ms_v2", want: 416},
+		{vmSize: "Basic_A0", want: 1},
+		{vmSize: "custom", wantErr: true},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.vmSize, func(t *testing.T) {
+			got, err := VMSizeVCPUs(test.vmSize)
+			if test.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(test.want))
+		})
+	}
+}
+
+func TestBudgetGuardrailUsage(t *testing.T) {
+	g := NewWithT(t)
+
+	maxCost := resource.MustParse("10")
+	vCPUCost := resource.MustParse("0.05")
+	d4Cost := resource.MustParse("0.192")
+
+	tests := []struct {
+		name    string
+		budget  BudgetGuardrail
+		vmSize  string
+		count   int64
+		want    BudgetUsage
+		wantErr bool
+	}{
+		{
+			name:   "vCPUs only",
+			budget: BudgetGuardrail{MaxVCPUs: pointer.Int64(64)},
+			vmSize: "Standard_D4s_v3",
+			count:  3,
+			want:   BudgetUsage{VCPUs: 12},
+		},
+		{
+			name:   "vCPU hourly cost",
+			budget: BudgetGuardrail{MaxHourlyCost: &maxCost, VCPUHourlyCost: &vCPUCost},
+			vmSize: "Standard_D4s_v3",
+			count:  3,
+			want:   BudgetUsage{VCPUs: 12, MicroHourlyCost: 600000},
+		},
+		{
+			name: "VM size hourly cost",
+			budget: BudgetGuardrail{
+				MaxHourlyCost:  &maxCost,
+				VCPUHourlyCost: &vCPUCost,
+				VMSizes:        []BudgetVMSize{{Name: "standard_d4s_v3", HourlyCost: &d4Cost}},
+			},
+			vmSize: "Standard_D4s_v3",
+			count:  2,
+			want:   BudgetUsage{VCPUs: 8, MicroHourlyCost: 384000},
+		},
+		{
+			name: "VM size vCPUs",
+			budget: BudgetGuardrail{
+				MaxVCPUs: pointer.Int64(64),
+				VMSizes:  []BudgetVMSize{{Name: "custom", VCPUs: pointer.Int64(6)}},
+			},
+			vmSize: "custom",
+			count:  2,
+			want:   BudgetUsage{VCPUs: 12},
+		},
+		{
+			name:    "unknown hourly cost",
+			budget:  BudgetGuardrail{MaxHourlyCost: &maxCost},
+			vmSize:  "Standard_D4s_v3",
+			count:   1,
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.budget.Usage(test.vmSize, test.count)
+			if test.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(test.want))
+		})
+	}
+}
+
+func TestBudgetGuardrailCheck(t *testing.T) {
+	g := NewWithT(t)
+
+	maxCost := resource.MustParse("1.5")
+	budget := BudgetGuardrail{MaxVCPUs: pointer.Int64(16), MaxHourlyCost: &maxCost}
+
+	g.Expect(budget.Check(BudgetUsage{VCPUs: 16, MicroHourlyCost: 1500000})).To(Succeed())
+	g.Expect(budget.Check(BudgetUsage{VCPUs: 20, MicroHourlyCost: 1000000})).To(MatchError(ContainSubstring("20 vCPUs, more than the maximum of 16 vCPUs")))
+	g.Expect(budget.Check(BudgetUsage{VCPUs: 8, MicroHourlyCost: 1750000})).To(MatchError(ContainSubstring("estimated hourly cost of 1.75, more than the maximum of 1.5")))
+}
@@ -24,6 +24,36 @@ const (
 	NetworkInfrastructureReadyCondition clusterv1.ConditionType = "NetworkInfrastructureReady"
 	// NamespaceNotAllowedByIdentity used to indicate cluster in a namespace not allowed by identity.
 	NamespaceNotAllowedByIdentity = "NamespaceNotAllowedByIdentity"
+	// AddonsReadyCondition reports on the installation of the workload cluster addons.
+	AddonsReadyCondition clusterv1.ConditionType = "AddonsReady"
+	// WaitingForControlPlaneReason used when addons or connectivity checks are waiting for the control plane to be initialized.
+	WaitingForControlPlaneReason = "WaitingForControlPlane"
+	// AddonsInstallFailedReason used when one or more addons could not be installed.
+	AddonsInstallFailedReason = "AddonsInstallFailed"
+	// APIServerAvailableCondition reports whether the API server of the cluster answers through its load balancer.
+	APIServerAvailableCondition clusterv1.ConditionType = "APIServerAvailable"
+	// DNSResolutionAvailableCondition reports whether the nodes of the cluster resolve names through the DNS of the virtual network.
+	DNSResolutionAvailableCondition clusterv1.ConditionType = "DNSResolutionAvailable"
+	// EgressAvailableCondition reports whether the nodes of the cluster reach the internet through the outbound connectivity of the virtual network.
+	EgressAvailableCondition clusterv1.ConditionType = "EgressAvailable"
+	// APIServerUnavailableReason used when the API server of the cluster doesn't answer.
+	APIServerUnavailableReason = "APIServerUnavailable"
+	// ProbeRunningReason used when the connectivity probe is running in the cluster.
+	ProbeRunningReason = "ProbeRunning"
+	// ProbeFailedReason used when the connectivity probe didn't complete.
+	ProbeFailedReason = "ProbeFailed"
+	// DNSResolutionFailedReason used when the connectivity probe failed to resolve a name.
+	DNSResolutionFailedReason = "DNSResolutionFailed"
+	// EgressFailedReason used when the connectivity probe failed to reach the internet.
+	EgressFailedReason = "EgressFailed"
+	// IdentityPermissionsReadyCondition reports whether the cluster identity is allowed to perform the actions the
+	// services of the cluster need on its resource group.
+	IdentityPermissionsReadyCondition clusterv1.ConditionType = "IdentityPermissionsReady"
+	// MissingPermissionsReason used when the cluster identity isn't allowed to perform some of the actions the services
+	// of the cluster need.
+	MissingPermissionsReason = "MissingPermissions"
+	// PermissionsCheckFailedReason used when the permissions of the cluster identity couldn't be listed.
+	PermissionsCheckFailedReason = "PermissionsCheckFailed"
 )
 
 // AzureMachine Conditions and Reasons.
@@ -38,6 +68,14 @@ const (
 	VMDeletingReason = "VMDeleting"
 	// VMProvisionFailedReason used for failures during vm provisioning.
 	VMProvisionFailedReason = "VMProvisionFailed"
+	// VMStoppedReason used when the vm is provisioned but stopped or deallocated.
+	VMStoppedReason = "VMStopped"
+	// VMStartingReason used when a stopped vm is being started.
+	VMStartingReason = "VMStarting"
+	// VMHibernatedReason used when the vm is deallocated because its cluster is hibernated.
+	VMHibernatedReason = "VMHibernated"
+	// VMSuspendedReason used when the vm is hibernated with its memory state because of its desired power state.
+	VMSuspendedReason = "VMSuspended"
 	// WaitingForClusterInfrastructureReason used when machine is waiting for cluster infrastructure to be ready before proceeding.
 	WaitingForClusterInfrastructureReason = "WaitingForClusterInfrastructure"
 	// WaitingForBootstrapDataReason used when machine is waiting for bootstrap data to be ready before proceeding.
@@ -48,6 +86,14 @@ const (
 	BootstrapInProgressReason = "BootstrapInProgress"
 	// BootstrapFailedReason is used to indicate the bootstrap process ran into an error.
 	BootstrapFailedReason = "BootstrapFailed"
+	// EtcdDataDiskPerformanceCondition reports whether the etcd data disk and the VM size can meet the latency
+	// requirements of etcd. It is only set on machines with an etcd data disk.
+	EtcdDataDiskPerformanceCondition clusterv1.ConditionType = "EtcdDataDiskPerformance"
+	// EtcdDataDiskLatencyNotGuaranteedReason is used when the storage type of the etcd data disk doesn't guarantee
+	// its latency.
+	EtcdDataDiskLatencyNotGuaranteedReason = "EtcdDataDiskLatencyNotGuaranteed"
+	// EtcdDataDiskThrottledReason is used when the VM size limits the IOPS of the etcd data disk.
+	EtcdDataDiskThrottledReason = "EtcdDataDiskThrottled"
 )
 
 // AzureMachinePool Conditions and Reasons.
@@ -62,6 +108,9 @@ const (
 	ScaleSetDeletingReason = "ScaleSetDeleting"
 	// ScaleSetProvisionFailedReason used for failures during scale set provisioning.
 	ScaleSetProvisionFailedReason = "ScaleSetProvisionFailed"
+	// InvalidAzureDataReason used when the data of the Azure scale set, or of one of its VMs, can't be converted
+	// faithfully, which stops its reconciliation in strict conversion mode.
+	InvalidAzureDataReason = "InvalidAzureData"
 
 	// ScaleSetDesiredReplicasCondition reports on the scaling state of the machine pool.
 	ScaleSetDesiredReplicasCondition clusterv1.ConditionType = "ScaleSetDesiredReplicas"
@@ -74,6 +123,19 @@ const (
 	ScaleSetModelUpdatedCondition clusterv1.ConditionType = "ScaleSetModelUpdated"
 	// ScaleSetModelOutOfDateReason describes the machine pool model being out of date.
 	ScaleSetModelOutOfDateReason = "ScaleSetModelOutOfDate"
+
+	// AutoscaleSettingReadyCondition means the Azure Monitor autoscale setting of the scale set exists and is ready to be used.
+	AutoscaleSettingReadyCondition clusterv1.ConditionType = "AutoscaleSettingReady"
+
+	// ImageReplicatedCondition reports on the replication of the Azure Compute Gallery image version of the pool to its
+	// location, which gates creating or rolling the pool to the image version.
+	ImageReplicatedCondition clusterv1.ConditionType = "ImageReplicated"
+	// ImageReplicatingReason describes the image version still replicating to the location of the pool.
+	ImageReplicatingReason = "ImageReplicating"
+	// ImageReplicationFailedReason describes the image version failing to replicate to the location of the pool.
+	ImageReplicationFailedReason = "ImageReplicationFailed"
+	// ImageNotReplicatedReason describes the image version not targeting the location of the pool.
+	ImageNotReplicatedReason = "ImageNotReplicated"
 )
 
 // AzureManagedCluster Conditions and Reasons.
@@ -82,6 +144,8 @@ const (
 	ManagedClusterRunningCondition clusterv1.ConditionType = "ManagedClusterRunning"
 	// AgentPoolsReadyCondition means the AKS agent pools exist and are ready to be used.
 	AgentPoolsReadyCondition clusterv1.ConditionType = "AgentPoolsReady"
+	// FleetsMemberReadyCondition means the AKS cluster is a member of its Azure Kubernetes Fleet Manager hub.
+	FleetsMemberReadyCondition clusterv1.ConditionType = "FleetsMemberReady"
 )
 
 // Azure Services Conditions and Reasons.
@@ -92,6 +156,13 @@ const (
 	VNetReadyCondition clusterv1.ConditionType = "VNetReady"
 	// VnetPeeringReadyCondition means the virtual network peerings exist and are ready to be used.
 	VnetPeeringReadyCondition clusterv1.ConditionType = "VnetPeeringReady"
+	// DNSResolverReadyCondition means the DNS private resolver and its forwarding ruleset, or the link of the virtual
+	// network to an existing forwarding ruleset, exist and are ready to be used.
+	DNSResolverReadyCondition clusterv1.ConditionType = "DNSResolverReady"
+	// PolicyExemptionsReadyCondition means the policy exemptions of the resource group of the cluster exist.
+	PolicyExemptionsReadyCondition clusterv1.ConditionType = "PolicyExemptionsReady"
+	// EventSubscriptionReadyCondition means the Event Grid subscription for resource notifications exists and is ready to be used.
+	EventSubscriptionReadyCondition clusterv1.ConditionType = "EventSubscriptionReady"
 	// SecurityGroupsReadyCondition means the security groups exist and are ready to be used.
 	SecurityGroupsReadyCondition clusterv1.ConditionType = "SecurityGroupsReady"
 	// RouteTablesReadyCondition means the route tables exist and are ready to be used.
@@ -114,14 +185,37 @@ const (
 	BastionHostReadyCondition clusterv1.ConditionType = "BastionHostReady"
 	// InboundNATRulesReadyCondition means the inbound NAT rules exist and are ready to be used.
 	InboundNATRulesReadyCondition clusterv1.ConditionType = "InboundNATRulesReady"
+	// BackendAddressesReadyCondition means the IP addresses of the machine are members of the load balancer backend pools.
+	BackendAddressesReadyCondition clusterv1.ConditionType = "BackendAddressesReady"
+	// BackendPoolsDrainedCondition means the network interfaces of the machine were removed from the load balancer
+	// backend pools and the connections through them had time to drain before the machine is deleted.
+	BackendPoolsDrainedCondition clusterv1.ConditionType = "BackendPoolsDrained"
+	// TombstoneExpiredCondition means the virtual machine was tagged as tombstoned and the grace period of the
+	// tombstone policy has passed, so the machine can be deleted.
+	TombstoneExpiredCondition clusterv1.ConditionType = "TombstoneExpired"
 	// AvailabilitySetReadyCondition means the availability set exists and is ready to be used.
 	AvailabilitySetReadyCondition clusterv1.ConditionType = "AvailabilitySetReady"
 	// RoleAssignmentReadyCondition means the role assignment exists and is ready to be used.
 	RoleAssignmentReadyCondition clusterv1.ConditionType = "RoleAssignmentReady"
 	// DisksReadyCondition means the disks exist and are ready to be used.
 	DisksReadyCondition clusterv1.ConditionType = "DisksReady"
+	// DiskSnapshotsReadyCondition means the last scheduled or requested snapshots of the disks were taken.
+	DiskSnapshotsReadyCondition clusterv1.ConditionType = "DiskSnapshotsReady"
+	// BackupProtectionReadyCondition means the virtual machine is enrolled in its backup policy.
+	BackupProtectionReadyCondition clusterv1.ConditionType = "BackupProtectionReady"
+	// JITNetworkAccessPolicyReadyCondition means the management ports of the virtual machine are protected by its
+	// just-in-time network access policy.
+	JITNetworkAccessPolicyReadyCondition clusterv1.ConditionType = "JITNetworkAccessPolicyReady"
 	// NetworkInterfaceReadyCondition means the network interfaces exist and are ready to be used.
 	NetworkInterfaceReadyCondition clusterv1.ConditionType = "NetworkInterfacesReady"
+	// LogAnalyticsWorkspaceReadyCondition means the Log Analytics workspace receiving the metrics and the logs of the
+	// cluster exists and is ready to be used.
+	LogAnalyticsWorkspaceReadyCondition clusterv1.ConditionType = "LogAnalyticsWorkspaceReady"
+	// DataCollectionReadyCondition means the data collection endpoint and rule of the cluster exist and are ready to be used.
+	DataCollectionReadyCondition clusterv1.ConditionType = "DataCollectionReady"
+	// DataCollectionRuleAssociationReadyCondition means the machine is associated with the data collection rule and
+	// endpoint of the cluster.
+	DataCollectionRuleAssociationReadyCondition clusterv1.ConditionType = "DataCollectionRuleAssociationReady"
 
 	// CreatingReason means the resource is being created.
 	CreatingReason = "Creating"
@@ -135,4 +229,8 @@ const (
 	DeletionFailedReason = "DeletionFailed"
 	// UpdatingReason means the resource is being updated.
 	UpdatingReason = "Updating"
+	// DrainingReason means the connections through the resource are being drained.
+	DrainingReason = "Draining"
+	// TombstonedReason means the resource is tombstoned and is kept until the grace period of the tombstone policy passes.
+	TombstonedReason = "Tombstoned"
 )
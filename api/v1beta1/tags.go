@@ -116,6 +116,19 @@ const (
 	// dedicated to this cluster api provider implementation.
 	NameAzureClusterAPIRole = NameAzureProviderPrefix + "role"
 
+	// NameAzureSnapshotSourceDisk is the tag name we use to mark snapshots with the name of the disk they were taken
+	// from, to retain a number of snapshots for each disk.
+	NameAzureSnapshotSourceDisk = NameAzureProviderPrefix + "snapshot-source-disk"
+
+	// NameAzureTombstone is the tag name we use to mark resources as tombstoned, with the time they were tombstoned in
+	// RFC 3339 format as value. Tombstoned resources are deleted once the grace period of their tombstone policy passes.
+	NameAzureTombstone = NameAzureProviderPrefix + "tombstone"
+
+	// NameAzureBootstrapDataSASExpiry is the tag name we use to mark scale sets with the RFC 3339 expiry time of the
+	// SAS URL their custom data includes to fetch the bootstrap data from storage, so that renewing the URL updates the
+	// model of the scale set.
+	NameAzureBootstrapDataSASExpiry = NameAzureProviderPrefix + "bootstrap-data-sas-expiry"
+
 	// APIServerRole describes the value for the apiserver role.
 	APIServerRole = "apiserver"
 
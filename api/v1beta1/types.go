@@ -17,8 +17,13 @@ limitations under the License.
 package v1beta1
 
 import (
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -63,6 +68,39 @@ type Future struct {
 	Data string `json:"data"`
 }
 
+// ResourceOperation is the last operation CAPZ performed on an Azure resource.
+type ResourceOperation string
+
+const (
+	// ResourceOperationCreate means the resource was created.
+	ResourceOperationCreate ResourceOperation = "Create"
+	// ResourceOperationUpdate means the resource was updated after it was first recorded.
+	ResourceOperationUpdate ResourceOperation = "Update"
+)
+
+// ResourceStatuses is a slice of ResourceStatus.
+type ResourceStatuses []ResourceStatus
+
+// ResourceStatus records an Azure resource created or updated by CAPZ.
+type ResourceStatus struct {
+	// ID is the ARM ID of the resource.
+	ID string `json:"id"`
+
+	// Type is the ARM resource type, such as Microsoft.Network/virtualNetworks.
+	Type string `json:"type"`
+
+	// ServiceName is the name of the CAPZ service that manages the resource.
+	ServiceName string `json:"serviceName"`
+
+	// LastOperation is the last operation performed on the resource.
+	// +kubebuilder:validation:Enum=Create;Update
+	LastOperation ResourceOperation `json:"lastOperation"`
+
+	// LastOperationTime is the time the last operation completed.
+	// +optional
+	LastOperationTime *metav1.Time `json:"lastOperationTime,omitempty"`
+}
+
 // NetworkSpec specifies what the Azure networking resources should look like.
 type NetworkSpec struct {
 	// Vnet is the configuration for the Azure virtual network.
@@ -86,9 +124,79 @@ type NetworkSpec struct {
 	// +optional
 	ControlPlaneOutboundLB *LoadBalancerSpec `json:"controlPlaneOutboundLB,omitempty"`
 
+	// GlobalAPIServerLB is the configuration for a cross-region load balancer in front of the API server load balancer
+	// and, optionally, the API server load balancers of clusters in other regions, e.g. to fail over to a passive
+	// control plane in the paired region. It requires a Public APIServerLB.
+	// +optional
+	GlobalAPIServerLB *GlobalLoadBalancerSpec `json:"globalAPIServerLB,omitempty"`
+
+	// DNSResolver is the configuration of the Azure DNS Private Resolver the virtual network of the cluster uses to
+	// forward the DNS queries of some domains, e.g. on-premises ones, to other DNS servers.
+	// +optional
+	DNSResolver *DNSResolverSpec `json:"dnsResolver,omitempty"`
+
 	NetworkClassSpec `json:",inline"`
 }
 
+// DNSResolverSpec configures an Azure DNS Private Resolver. Either a DNS private resolver with an outbound endpoint and
+// a DNS forwarding ruleset is created in the virtual network of the cluster, or the existing DNS forwarding ruleset
+// of a DNS private resolver, e.g. in a peered hub virtual network, is linked to the virtual network of the cluster.
+type DNSResolverSpec struct {
+	// ForwardingRulesetID is the resource ID of an existing DNS forwarding ruleset to link to the virtual network of
+	// the cluster, in the same location. No DNS private resolver is created when it is set.
+	// +optional
+	ForwardingRulesetID string `json:"forwardingRulesetID,omitempty"`
+
+	// Name is the name of the DNS private resolver created in the virtual network of the cluster, of its outbound
+	// endpoint and of its DNS forwarding ruleset. Defaults to <cluster name>-dns-resolver.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// OutboundSubnet is the subnet of the outbound endpoint of the DNS private resolver, which is delegated to
+	// Microsoft.Network/dnsResolvers and can't be used by other resources.
+	// +optional
+	OutboundSubnet DNSResolverSubnet `json:"outboundSubnet,omitempty"`
+
+	// ForwardingRules are the rules of the DNS forwarding ruleset of the DNS private resolver.
+	// +optional
+	ForwardingRules []DNSForwardingRule `json:"forwardingRules,omitempty"`
+}
+
+// DNSResolverSubnet defines the subnet of the outbound endpoint of a DNS private resolver.
+type DNSResolverSubnet struct {
+	// Name is the name of the subnet. Defaults to dns-resolver-outbound-subnet.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// CIDRBlocks is the address space of the subnet, between a /28 and a /24. Defaults to 10.255.255.208/28.
+	// +optional
+	CIDRBlocks []string `json:"cidrBlocks,omitempty"`
+}
+
+// DNSForwardingRule forwards the DNS queries of a domain to target DNS servers.
+type DNSForwardingRule struct {
+	// Name is the name of the forwarding rule.
+	Name string `json:"name"`
+
+	// DomainName is the domain whose DNS queries are forwarded, e.g. corp.contoso.com.
+	DomainName string `json:"domainName"`
+
+	// TargetDNSServers are the DNS servers the queries are forwarded to, e.g. on-premises DNS servers.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=6
+	TargetDNSServers []TargetDNSServer `json:"targetDNSServers"`
+}
+
+// TargetDNSServer is a DNS server DNS queries are forwarded to.
+type TargetDNSServer struct {
+	// IPAddress is the IP address of the DNS server.
+	IPAddress string `json:"ipAddress"`
+
+	// Port is the port of the DNS server. Defaults to 53.
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+}
+
 // VnetSpec configures an Azure virtual network.
 type VnetSpec struct {
 	// ResourceGroup is the name of the resource group of the existing virtual network
@@ -243,10 +351,61 @@ type LoadBalancerSpec struct {
 	// FrontendIPsCount specifies the number of frontend IP addresses for the load balancer.
 	// +optional
 	FrontendIPsCount *int32 `json:"frontendIPsCount,omitempty"`
+	// External references an existing load balancer that is managed outside of the Azure provider, e.g. by a central
+	// networking team. The provider never creates, updates or deletes an external load balancer: it only verifies that
+	// the load balancer and its backend pool exist, and adds the control plane machines to the backend pool.
+	// It can only be set on the API server load balancer, and requires the host of the control plane endpoint to be set.
+	// It cannot be modified after AzureCluster creation.
+	// +optional
+	External *ExternalLoadBalancer `json:"external,omitempty"`
+	// FrontendPort is the port of the frontend IP of the API server load balancer, which forwards to the API server
+	// port of the control plane nodes, e.g. 443 when only HTTPS is allowed between the network of the clients and
+	// Azure. Defaults to the API server port. It can only be set on the API server load balancer, and cannot be
+	// modified after AzureCluster creation.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	FrontendPort *int32 `json:"frontendPort,omitempty"`
+	// AliasFrontends are additional frontends of the API server load balancer, which forward to the API server port
+	// of the control plane nodes as well. They can be added after AzureCluster creation, but not modified or removed.
+	// +optional
+	AliasFrontends []APIServerAliasFrontend `json:"aliasFrontends,omitempty"`
 
 	LoadBalancerClassSpec `json:",inline"`
 }
 
+// APIServerAliasFrontend is an additional frontend of the API server load balancer, on another port or IP than its
+// frontend IP, e.g. one that is allowed between another network of the clients and Azure.
+type APIServerAliasFrontend struct {
+	// Name is the name of the alias frontend, which names its load balancing rule, and its frontend IP configuration
+	// if it has its own IP.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Port is the port of the alias frontend. Defaults to the frontend port of the API server load balancer.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+	// PublicIP is the own public IP of an alias frontend of a Public load balancer. An alias frontend without its own
+	// IP uses the frontend IP of the load balancer, on another port.
+	// +optional
+	PublicIP *PublicIPSpec `json:"publicIP,omitempty"`
+	// PrivateIPAddress is the own private IP of an alias frontend of an Internal load balancer, in the control plane
+	// subnet.
+	// +optional
+	PrivateIPAddress string `json:"privateIP,omitempty"`
+}
+
+// ExternalLoadBalancer references a load balancer that is managed outside of the Azure provider.
+type ExternalLoadBalancer struct {
+	// ID is the resource ID of the load balancer. It must be in the subscription of the cluster, and the name of the
+	// load balancer spec defaults to its name.
+	ID string `json:"id"`
+	// BackendPoolName is the name of the existing backend pool of the load balancer that the control plane machines
+	// are added to.
+	BackendPoolName string `json:"backendPoolName"`
+}
+
 // SKU defines an Azure load balancer SKU.
 type SKU string
 
@@ -255,6 +414,42 @@ const (
 	SKUStandard = SKU("Standard")
 )
 
+// SKUTier defines the tier of an Azure load balancer or public IP of the Standard SKU.
+type SKUTier string
+
+const (
+	// SKUTierRegional is the tier of load balancers and public IPs deployed in a single region.
+	SKUTierRegional = SKUTier("Regional")
+	// SKUTierGlobal is the tier of cross-region load balancers and their public IPs.
+	SKUTierGlobal = SKUTier("Global")
+)
+
+// GlobalLoadBalancerSpec defines a cross-region load balancer, a Standard SKU load balancer of the Global tier whose
+// backends are the frontend IP configurations of regional public load balancers. Clients connect to its Global tier
+// public IP and are routed to the closest healthy regional load balancer.
+type GlobalLoadBalancerSpec struct {
+	// Name is the name of the cross-region load balancer. Defaults to <cluster name>-global-lb.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Location is the home region of the cross-region load balancer, which must be one of the regions that support
+	// cross-region load balancers. Defaults to the location of the AzureCluster.
+	// +optional
+	Location string `json:"location,omitempty"`
+
+	// PublicIP is the Global tier public IP of the frontend of the cross-region load balancer. Global tier public IPs
+	// have no availability zones. Defaults to a public IP named pip-<cluster name>-apiserver-global.
+	// +optional
+	PublicIP *PublicIPSpec `json:"publicIP,omitempty"`
+
+	// RegionalFrontendIPConfigurationIDs are the resource IDs of the frontend IP configurations of the regional API
+	// server load balancers of other clusters, e.g. of a passive control plane in the paired region, which are added
+	// to the backend pool of the cross-region load balancer. The frontend of the APIServerLB of the AzureCluster is
+	// always in the backend pool.
+	// +optional
+	RegionalFrontendIPConfigurationIDs []string `json:"regionalFrontendIPConfigurationIDs,omitempty"`
+}
+
 // LBType defines an Azure load balancer Type.
 type LBType string
 
@@ -265,12 +460,50 @@ const (
 	Public = LBType("Public")
 )
 
+// BackendPoolType defines how the members of an Azure load balancer backend pool are referenced.
+type BackendPoolType string
+
+const (
+	// BackendPoolTypeNIC references the IP configurations of the network interfaces of the pool members.
+	BackendPoolTypeNIC = BackendPoolType("NIC")
+	// BackendPoolTypeIP references the private IP addresses and virtual networks of the pool members.
+	BackendPoolTypeIP = BackendPoolType("IP")
+)
+
+// LoadDistribution defines the session persistence of an Azure load balancing rule.
+type LoadDistribution string
+
+const (
+	// LoadDistributionDefault distributes the connections by 5-tuple.
+	LoadDistributionDefault = LoadDistribution("Default")
+	// LoadDistributionSourceIP sends the connections of a client IP to the same backend.
+	LoadDistributionSourceIP = LoadDistribution("SourceIP")
+	// LoadDistributionSourceIPProtocol sends the connections of a client IP and protocol to the same backend.
+	LoadDistributionSourceIPProtocol = LoadDistribution("SourceIPProtocol")
+)
+
+// OutboundType defines how egress traffic leaves the cluster.
+type OutboundType string
+
+const (
+	// OutboundTypeLoadBalancer uses outbound load balancers and NAT gateways managed by the Azure provider for egress.
+	OutboundTypeLoadBalancer = OutboundType("LoadBalancer")
+	// OutboundTypeUserDefinedRouting relies on user-provided NAT gateways, firewalls or routes for egress.
+	// No public IPs or public load balancers are created for clusters using this outbound type.
+	OutboundTypeUserDefinedRouting = OutboundType("UserDefinedRouting")
+)
+
 // FrontendIP defines a load balancer frontend IP configuration.
 type FrontendIP struct {
 	// +kubebuilder:validation:MinLength=1
 	Name string `json:"name"`
 	// +optional
 	PublicIP *PublicIPSpec `json:"publicIP,omitempty"`
+	// GatewayLoadBalancerID is the resource ID of the frontend IP configuration of a Gateway Load Balancer the public
+	// frontend IP is chained to, so that its traffic is steered through the network virtual appliances behind the
+	// Gateway Load Balancer. Only the frontend IPs of Public load balancers can be chained.
+	// +optional
+	GatewayLoadBalancerID string `json:"gatewayLoadBalancerID,omitempty"`
 
 	FrontendIPClass `json:",inline"`
 }
@@ -280,8 +513,33 @@ type PublicIPSpec struct {
 	Name string `json:"name"`
 	// +optional
 	DNSName string `json:"dnsName,omitempty"`
+
+	// Availability defines the availability zones of the public IP. ZoneRedundant public IPs are served from all the
+	// availability zones of the location, Zonal public IPs from the availability zone set in Zone, and NoZone public IPs
+	// from no specific zone. If omitted, the public IP is zone-redundant in locations with availability zones, and has
+	// no zone otherwise. Public IPs are always of the Standard SKU and of the Regional tier, as required by Standard
+	// load balancers, except the public IP of a cross-region load balancer which is of the Global tier.
+	// +kubebuilder:validation:Enum=ZoneRedundant;Zonal;NoZone
+	// +optional
+	Availability PublicIPAvailability `json:"availability,omitempty"`
+
+	// Zone is the availability zone of a Zonal public IP.
+	// +optional
+	Zone string `json:"zone,omitempty"`
 }
 
+// PublicIPAvailability defines the availability zones of a public IP.
+type PublicIPAvailability string
+
+const (
+	// PublicIPAvailabilityZoneRedundant is a public IP served from all the availability zones of the location.
+	PublicIPAvailabilityZoneRedundant PublicIPAvailability = "ZoneRedundant"
+	// PublicIPAvailabilityZonal is a public IP pinned to a single availability zone.
+	PublicIPAvailabilityZonal PublicIPAvailability = "Zonal"
+	// PublicIPAvailabilityNoZone is a public IP not served from any specific availability zone.
+	PublicIPAvailabilityNoZone PublicIPAvailability = "NoZone"
+)
+
 // VMState describes the state of an Azure virtual machine.
 // Deprecated: use ProvisioningState.
 type VMState string
@@ -309,6 +567,352 @@ const (
 	Deleted ProvisioningState = "Deleted"
 )
 
+// ReconciliationBackend describes how the Azure resources of a cluster are reconciled.
+type ReconciliationBackend string
+
+const (
+	// ReconciliationBackendARM reconciles the Azure resources by calling the Azure Resource Manager API directly.
+	ReconciliationBackendARM ReconciliationBackend = "ARM"
+	// ReconciliationBackendASO reconciles the Azure resources through Azure Service Operator resources.
+	ReconciliationBackendASO ReconciliationBackend = "ASO"
+)
+
+// AzureMonitoring onboards a cluster to Azure Monitor, so that the metrics and the logs of its nodes flow to a Log
+// Analytics workspace as soon as the cluster is created.
+type AzureMonitoring struct {
+	// LogAnalyticsWorkspaceID is the resource ID of an existing Log Analytics workspace receiving the metrics and the
+	// logs of the cluster. If omitted, a workspace named after the cluster is created in the resource group of the
+	// cluster, and deleted with the cluster.
+	// +optional
+	LogAnalyticsWorkspaceID string `json:"logAnalyticsWorkspaceID,omitempty"`
+
+	// RetentionInDays is the number of days the Log Analytics workspace created for the cluster retains the data.
+	// Defaults to 30. It can't be set along with LogAnalyticsWorkspaceID.
+	// +kubebuilder:validation:Minimum=30
+	// +kubebuilder:validation:Maximum=730
+	// +optional
+	RetentionInDays *int32 `json:"retentionInDays,omitempty"`
+}
+
+// CrossSubscriptionAccessPolicy is how the access of the cluster identity to the resources of the machines which are in
+// another subscription than the cluster is handled.
+type CrossSubscriptionAccessPolicy string
+
+const (
+	// CrossSubscriptionAccessValidate checks that the cluster identity has the permissions the machines need on the
+	// resources in other subscriptions.
+	CrossSubscriptionAccessValidate CrossSubscriptionAccessPolicy = "Validate"
+	// CrossSubscriptionAccessAssign grants the Reader role on the resources in other subscriptions to the cluster
+	// identity.
+	CrossSubscriptionAccessAssign CrossSubscriptionAccessPolicy = "Assign"
+)
+
+// ControlPlaneZoneSpreadingPolicy is how the control plane machines without a failure domain are placed in the
+// availability zones of the cluster.
+type ControlPlaneZoneSpreadingPolicy string
+
+const (
+	// ControlPlaneZoneSpreadingExplicit places the control plane machines only in the failure domain of their Machine or
+	// AzureMachine, if any.
+	ControlPlaneZoneSpreadingExplicit ControlPlaneZoneSpreadingPolicy = "Explicit"
+	// ControlPlaneZoneSpreadingAutomatic places the control plane machines without a failure domain in the control plane
+	// failure domain of the cluster with the fewest control plane machines.
+	ControlPlaneZoneSpreadingAutomatic ControlPlaneZoneSpreadingPolicy = "Automatic"
+)
+
+// ClusterSecurityProfile specifies the security settings of a cluster.
+type ClusterSecurityProfile struct {
+	// Defender enables Microsoft Defender for Cloud on the cluster.
+	// +optional
+	Defender *DefenderProfile `json:"defender,omitempty"`
+
+	// JustInTimeAccess protects the management ports of the virtual machines of the cluster with Defender for Cloud
+	// just-in-time (JIT) network access policies: their network security groups deny access to the ports until it is
+	// requested and approved. Requires Defender for Servers Plan 2 on the subscription of the cluster. The virtual
+	// machines of machine pools aren't protected, as JIT doesn't support scale sets.
+	// +optional
+	JustInTimeAccess *JustInTimeAccess `json:"justInTimeAccess,omitempty"`
+}
+
+// DefenderProfile enables Microsoft Defender for Cloud on a cluster, so that it is covered as soon as it is created.
+type DefenderProfile struct {
+	// LogAnalyticsWorkspaceID is the resource ID of the Log Analytics workspace Defender sends its security events to.
+	// Defaults to the workspace of the monitoring of the cluster. Only supported by managed clusters: the security
+	// agent of self-managed clusters always reports to the workspace of their monitoring.
+	// +optional
+	LogAnalyticsWorkspaceID string `json:"logAnalyticsWorkspaceID,omitempty"`
+}
+
+// JustInTimeAccessProtocol is the protocol of a port protected by just-in-time network access.
+type JustInTimeAccessProtocol string
+
+const (
+	// JustInTimeAccessProtocolTCP protects the TCP port.
+	JustInTimeAccessProtocolTCP JustInTimeAccessProtocol = "TCP"
+	// JustInTimeAccessProtocolUDP protects the UDP port.
+	JustInTimeAccessProtocolUDP JustInTimeAccessProtocol = "UDP"
+	// JustInTimeAccessProtocolAll protects both the TCP and UDP ports.
+	JustInTimeAccessProtocolAll JustInTimeAccessProtocol = "*"
+)
+
+const (
+	// DefaultJustInTimeAccessPort is the port protected by just-in-time network access when none is specified: SSH.
+	DefaultJustInTimeAccessPort = 22
+	// DefaultJustInTimeAccessMaxRequestAccessDuration is the longest duration access to a port is granted for by default.
+	DefaultJustInTimeAccessMaxRequestAccessDuration = 3 * time.Hour
+	// MinJustInTimeAccessMaxRequestAccessDuration is the shortest maximum duration of the access to a port Azure accepts.
+	MinJustInTimeAccessMaxRequestAccessDuration = 5 * time.Minute
+	// MaxJustInTimeAccessMaxRequestAccessDuration is the longest maximum duration of the access to a port Azure accepts.
+	MaxJustInTimeAccessMaxRequestAccessDuration = 24 * time.Hour
+)
+
+// JustInTimeAccess is the just-in-time network access policy of the virtual machines of a cluster.
+type JustInTimeAccess struct {
+	// Ports are the ports of the virtual machines access to is requested through just-in-time network access.
+	// Defaults to SSH, TCP port 22.
+	// +optional
+	Ports []JustInTimeAccessPort `json:"ports,omitempty"`
+}
+
+// GetPorts returns the ports protected by just-in-time network access, defaulting to SSH.
+func (j JustInTimeAccess) GetPorts() []JustInTimeAccessPort {
+	if len(j.Ports) > 0 {
+		return j.Ports
+	}
+	return []JustInTimeAccessPort{{Number: DefaultJustInTimeAccessPort}}
+}
+
+// JustInTimeAccessPort is a port of the virtual machines protected by just-in-time network access.
+type JustInTimeAccessPort struct {
+	// Number is the port number.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Number int32 `json:"number"`
+
+	// Protocol is the protocol of the port: TCP, UDP, or * for both. Defaults to TCP.
+	// +kubebuilder:validation:Enum=TCP;UDP;*
+	// +optional
+	Protocol JustInTimeAccessProtocol `json:"protocol,omitempty"`
+
+	// AllowedSourceAddressPrefixes are the IP addresses or CIDRs access to the port can be requested from. Defaults to
+	// any source.
+	// +optional
+	AllowedSourceAddressPrefixes []string `json:"allowedSourceAddressPrefixes,omitempty"`
+
+	// MaxRequestAccessDuration is the longest duration access to the port is granted for, between 5 minutes and 24
+	// hours. Defaults to 3 hours.
+	// +optional
+	MaxRequestAccessDuration *metav1.Duration `json:"maxRequestAccessDuration,omitempty"`
+}
+
+// GetProtocol returns the protocol of the port, defaulting to TCP.
+func (p JustInTimeAccessPort) GetProtocol() JustInTimeAccessProtocol {
+	if p.Protocol != "" {
+		return p.Protocol
+	}
+	return JustInTimeAccessProtocolTCP
+}
+
+// GetMaxRequestAccessDuration returns the longest duration access to the port is granted for, defaulting to 3 hours.
+func (p JustInTimeAccessPort) GetMaxRequestAccessDuration() time.Duration {
+	if p.MaxRequestAccessDuration != nil {
+		return p.MaxRequestAccessDuration.Duration
+	}
+	return DefaultJustInTimeAccessMaxRequestAccessDuration
+}
+
+// BudgetGuardrail caps the compute resources of the virtual machines of a cluster. Creating an AzureMachine or
+// scaling up a machine pool is rejected when the cluster would exceed any of its maximums.
+type BudgetGuardrail struct {
+	// MaxVCPUs is the maximum total number of vCPUs of the virtual machines of the cluster.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxVCPUs *int64 `json:"maxVCPUs,omitempty"`
+
+	// MaxHourlyCost is the maximum estimated hourly cost of the virtual machines of the cluster, e.g. "12.5", in the
+	// currency of VCPUHourlyCost and the VMSizes hourly costs.
+	// +optional
+	MaxHourlyCost *resource.Quantity `json:"maxHourlyCost,omitempty"`
+
+	// VCPUHourlyCost is the estimated hourly cost of a vCPU, used for the VM sizes without an hourly cost in VMSizes.
+	// +optional
+	VCPUHourlyCost *resource.Quantity `json:"vCPUHourlyCost,omitempty"`
+
+	// VMSizes overrides the number of vCPUs or the hourly cost of VM sizes, e.g. with the prices negotiated for the
+	// subscription.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	VMSizes []BudgetVMSize `json:"vmSizes,omitempty"`
+}
+
+// BudgetVMSize describes a VM size for a budget guardrail.
+type BudgetVMSize struct {
+	// Name is the name of the VM size, e.g. "Standard_D4s_v3".
+	Name string `json:"name"`
+
+	// VCPUs is the number of vCPUs of the VM size. Defaults to the number in the name of the VM size, e.g. 4 for
+	// Standard_D4s_v3, or 16 for the constrained vCPU size Standard_E64-16ds_v4.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	VCPUs *int64 `json:"vCPUs,omitempty"`
+
+	// HourlyCost is the estimated hourly cost of a virtual machine of the VM size.
+	// +optional
+	HourlyCost *resource.Quantity `json:"hourlyCost,omitempty"`
+}
+
+// PolicyExemption exempts the resource group of a cluster from a policy assignment, e.g. one denying public IPs
+// which must be waived for the API server load balancer.
+type PolicyExemption struct {
+	// Name is the name of the policy exemption, unique in the resource group of the cluster.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=64
+	Name string `json:"name"`
+
+	// PolicyAssignmentID is the resource ID of the policy assignment the resource group is exempted from, e.g.
+	// "/providers/Microsoft.Management/managementGroups/my-mg/providers/Microsoft.Authorization/policyAssignments/deny-public-ip".
+	PolicyAssignmentID string `json:"policyAssignmentID"`
+
+	// PolicyDefinitionReferenceIDs restricts the exemption to these policy definitions of the policy set definition
+	// the policy assignment assigns. The exemption covers all of them when empty.
+	// +optional
+	PolicyDefinitionReferenceIDs []string `json:"policyDefinitionReferenceIDs,omitempty"`
+
+	// Category is the category of the policy exemption: Waiver, the default, when the resource group isn't compliant
+	// with the policy, or Mitigated when the intent of the policy is met some other way.
+	// +kubebuilder:validation:Enum=Waiver;Mitigated
+	// +optional
+	Category PolicyExemptionCategory `json:"category,omitempty"`
+
+	// Justification is the description of the policy exemption, explaining why the policy is waived.
+	// +kubebuilder:validation:MinLength=1
+	Justification string `json:"justification"`
+
+	// Metadata is additional metadata of the policy exemption justifying it, e.g. the ticket or the person who
+	// approved it.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// ExpiresOn is the time the policy exemption expires at. It never expires when unset.
+	// +optional
+	ExpiresOn *metav1.Time `json:"expiresOn,omitempty"`
+}
+
+// PolicyExemptionCategory is the category of a policy exemption.
+type PolicyExemptionCategory string
+
+const (
+	// PolicyExemptionCategoryWaiver exempts a resource group which isn't compliant with a policy.
+	PolicyExemptionCategoryWaiver PolicyExemptionCategory = "Waiver"
+	// PolicyExemptionCategoryMitigated exempts a resource group which meets the intent of a policy some other way.
+	PolicyExemptionCategoryMitigated PolicyExemptionCategory = "Mitigated"
+)
+
+// PowerState describes the power state of an Azure virtual machine.
+type PowerState string
+
+const (
+	// PowerStateStarting means the virtual machine is being started.
+	PowerStateStarting PowerState = "Starting"
+	// PowerStateRunning means the virtual machine is running.
+	PowerStateRunning PowerState = "Running"
+	// PowerStateStopping means the virtual machine is being stopped.
+	PowerStateStopping PowerState = "Stopping"
+	// PowerStateStopped means the virtual machine is stopped but still allocated, and still billed for compute.
+	PowerStateStopped PowerState = "Stopped"
+	// PowerStateDeallocating means the virtual machine is being deallocated.
+	PowerStateDeallocating PowerState = "Deallocating"
+	// PowerStateDeallocated means the virtual machine is stopped and its compute resources are released.
+	PowerStateDeallocated PowerState = "Deallocated"
+	// PowerStateHibernated means the virtual machine is deallocated with its memory state saved to its OS disk.
+	PowerStateHibernated PowerState = "Hibernated"
+	// PowerStateUnknown means the power state of the virtual machine is not known.
+	PowerStateUnknown PowerState = "Unknown"
+)
+
+// VMInstanceView is the health of an Azure virtual machine reported by its instance view, beyond its provisioning
+// state.
+type VMInstanceView struct {
+	// Extensions are the provisioning states of the extensions of the virtual machine.
+	// +optional
+	Extensions []VMExtensionStatus `json:"extensions,omitempty"`
+
+	// Disks are the provisioning states of the disks attached to the virtual machine.
+	// +optional
+	Disks []VMDiskStatus `json:"disks,omitempty"`
+
+	// BootDiagnostics is the availability of the boot diagnostics of the virtual machine, if they are enabled.
+	// +optional
+	BootDiagnostics *VMBootDiagnosticsStatus `json:"bootDiagnostics,omitempty"`
+}
+
+// VMExtensionStatus is the provisioning state of a virtual machine extension.
+type VMExtensionStatus struct {
+	// Name is the name of the extension.
+	Name string `json:"name"`
+
+	// Type is the type of the extension, e.g. "Microsoft.Azure.Extensions.CustomScript".
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// ProvisioningState is the provisioning state of the extension, e.g. Succeeded or Failed.
+	// +optional
+	ProvisioningState ProvisioningState `json:"provisioningState,omitempty"`
+
+	// Message is the message of the extension status, e.g. the reason of its failure.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// VMDiskStatus is the provisioning state of a disk attached to a virtual machine.
+type VMDiskStatus struct {
+	// Name is the name of the disk.
+	Name string `json:"name"`
+
+	// ProvisioningState is the provisioning state of the disk attachment, e.g. Succeeded, Updating or Failed.
+	// +optional
+	ProvisioningState ProvisioningState `json:"provisioningState,omitempty"`
+
+	// Message is the message of the disk status, e.g. the reason of its failure.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// VMBootDiagnosticsStatus is the availability of the boot diagnostics of a virtual machine.
+type VMBootDiagnosticsStatus struct {
+	// Available is true when the serial console log and the screenshot of the virtual machine can be retrieved.
+	Available bool `json:"available"`
+
+	// Message is the reason the boot diagnostics aren't available.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// VMPlacement is where Azure placed a virtual machine.
+type VMPlacement struct {
+	// FaultDomain is the platform fault domain of the virtual machine, sharing a power source and network switch.
+	// +optional
+	FaultDomain *int32 `json:"faultDomain,omitempty"`
+
+	// UpdateDomain is the platform update domain of the virtual machine, rebooted together during planned maintenance.
+	// +optional
+	UpdateDomain *int32 `json:"updateDomain,omitempty"`
+
+	// AvailabilityZone is the availability zone of the virtual machine.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// DedicatedHostID is the ID of the dedicated host the virtual machine runs on, including the host automatically
+	// assigned in its dedicated host group.
+	// +optional
+	DedicatedHostID string `json:"dedicatedHostID,omitempty"`
+
+	// ProximityPlacementGroupID is the ID of the proximity placement group of the virtual machine.
+	// +optional
+	ProximityPlacementGroupID string `json:"proximityPlacementGroupID,omitempty"`
+}
+
 // Image defines information about the image to use for VM creation.
 // There are three ways to specify an image: by ID, Marketplace Image or SharedImageGallery
 // One of ID, SharedImage or Marketplace should be set.
@@ -482,6 +1086,12 @@ const (
 // qualified import when generating outside of the GOPATH.
 type OSDisk struct {
 	OSType string `json:"osType"`
+	// Distro is the Linux distribution running on the machine. It is used to pick a default reference image
+	// when no image is specified, and to adapt bootstrapping to the distribution.
+	// Defaults to Ubuntu when not set. It cannot be set for Windows machines.
+	// +kubebuilder:validation:Enum=Ubuntu;AzureLinux
+	// +optional
+	Distro OSDistro `json:"distro,omitempty"`
 	// DiskSizeGB is the size in GB to assign to the OS disk.
 	// Will have a default of 30GB if not provided
 	// +optional
@@ -495,14 +1105,185 @@ type OSDisk struct {
 	// +optional
 	// +kubebuilder:validation:Enum=None;ReadOnly;ReadWrite
 	CachingType string `json:"cachingType,omitempty"`
+	// WriteAcceleratorEnabled enables Write Accelerator on the disk, which lowers the write latency of Premium_LRS
+	// disks on M-series VM sizes. It requires a cachingType of None or ReadOnly.
+	// See https://learn.microsoft.com/azure/virtual-machines/how-to-enable-write-accelerator.
+	// +optional
+	WriteAcceleratorEnabled *bool `json:"writeAcceleratorEnabled,omitempty"`
+	// SourceSnapshotID is the resource ID of a snapshot the OS disk is created from instead of the image, to recover a
+	// machine from a snapshot of the OS disk of another one. The virtual machine boots in the state of the snapshot,
+	// and its bootstrap data isn't applied as the OS disk is already provisioned.
+	// +optional
+	SourceSnapshotID string `json:"sourceSnapshotID,omitempty"`
+}
+
+// UserData defines the user data passed to a virtual machine through its userData property.
+type UserData struct {
+	// Sections of the user data. A single section is passed as-is, while multiple sections are merged,
+	// in order, into a MIME multi-part document which can be consumed by cloud-init.
+	// +kubebuilder:validation:MinItems=1
+	Sections []UserDataSection `json:"sections"`
 }
 
+// UserDataSection defines a section of the user data of a virtual machine.
+// Exactly one of Content or SecretRef must be set.
+type UserDataSection struct {
+	// Content is the inline content of the section.
+	// +optional
+	Content string `json:"content,omitempty"`
+
+	// SecretRef references a key of a Secret, in the namespace of the machine, holding the content of the section.
+	// +optional
+	SecretRef *UserDataSecretReference `json:"secretRef,omitempty"`
+}
+
+// UserDataSecretReference references a key of a Secret holding user data.
+type UserDataSecretReference struct {
+	// Name of the Secret.
+	Name string `json:"name"`
+
+	// Key of the Secret holding the content. Defaults to "value".
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// MultiInstanceGPU configures the NVIDIA Multi-Instance GPU (MIG) partitioning of the GPUs of a virtual machine.
+// MIG is only supported by some GPUs, like the NVIDIA A100 and H100 GPUs.
+// See https://docs.nvidia.com/datacenter/tesla/mig-user-guide/.
+type MultiInstanceGPU struct {
+	// Profile is the MIG profile of the GPU instances created on each GPU, made of the number of compute slices and
+	// the memory of an instance, e.g. "1g.10gb" or "3g.40gb" on an A100 80GB GPU.
+	// +kubebuilder:validation:Pattern=`^[1-7]g\.[0-9]+gb(\+me)?$`
+	Profile string `json:"profile"`
+
+	// InstancesPerGPU is the number of GPU instances of the profile created on each GPU. Defaults to the maximum
+	// number of instances of the profile that fit on a GPU.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=7
+	// +optional
+	InstancesPerGPU *int32 `json:"instancesPerGPU,omitempty"`
+}
+
+// multiInstanceGPUMaxInstances is the maximum number of GPU instances that fit on a GPU for each number of compute
+// slices of a MIG profile.
+var multiInstanceGPUMaxInstances = map[int32]int32{1: 7, 2: 3, 3: 2, 4: 1, 7: 1}
+
+// ComputeSlices returns the number of compute slices of a GPU instance of the MIG profile, or 0 if the profile is invalid.
+func (m MultiInstanceGPU) ComputeSlices() int32 {
+	parts := strings.SplitN(m.Profile, "g.", 2)
+	if len(parts) != 2 {
+		return 0
+	}
+	slices, err := strconv.ParseInt(parts[0], 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(slices)
+}
+
+// MaxInstancesPerGPU returns the maximum number of GPU instances of the MIG profile that fit on a GPU, or 0 if the
+// profile is not supported.
+func (m MultiInstanceGPU) MaxInstancesPerGPU() int32 {
+	return multiInstanceGPUMaxInstances[m.ComputeSlices()]
+}
+
+// GetInstancesPerGPU returns the number of GPU instances of the MIG profile created on each GPU.
+func (m MultiInstanceGPU) GetInstancesPerGPU() int32 {
+	if m.InstancesPerGPU != nil {
+		return *m.InstancesPerGPU
+	}
+	return m.MaxInstancesPerGPU()
+}
+
+// LocalStorageSource is the kind of local disks of a virtual machine.
+type LocalStorageSource string
+
+const (
+	// LocalStorageTempDisk is the temporary disk of the virtual machine, mounted on /mnt by default.
+	LocalStorageTempDisk LocalStorageSource = "TempDisk"
+	// LocalStorageNVMe are the local NVMe disks of the virtual machine, striped into a RAID 0 array when there are several.
+	LocalStorageNVMe LocalStorageSource = "NVMe"
+)
+
+// LocalStorage configures how the local disks of a virtual machine are formatted and mounted when it is provisioned.
+// The data of local disks is lost when the virtual machine is deallocated or moved to another host.
+type LocalStorage struct {
+	// Source is the kind of local disks to format and mount.
+	// +kubebuilder:validation:Enum=TempDisk;NVMe
+	Source LocalStorageSource `json:"source"`
+
+	// Filesystem is the filesystem the local disks are formatted with.
+	// +kubebuilder:validation:Enum=ext4;xfs
+	// +kubebuilder:default=ext4
+	// +optional
+	Filesystem string `json:"filesystem,omitempty"`
+
+	// MountPath is the absolute path the local disks are mounted on, e.g. /var/lib/etcd.
+	// +kubebuilder:validation:Pattern=`^/.+`
+	MountPath string `json:"mountPath"`
+}
+
+// FileStorageProtocol is a protocol used to mount Azure Files shares or Azure Blob storage containers.
+// +kubebuilder:validation:Enum=SMB;NFS;Blobfuse
+type FileStorageProtocol string
+
+const (
+	// FileStorageSMB is the SMB protocol used to mount Azure Files shares.
+	FileStorageSMB FileStorageProtocol = "SMB"
+	// FileStorageNFS is the NFS protocol used to mount Azure Files NFS shares and NFS enabled Azure Blob storage containers.
+	FileStorageNFS FileStorageProtocol = "NFS"
+	// FileStorageBlobfuse is the FUSE driver used to mount Azure Blob storage containers.
+	FileStorageBlobfuse FileStorageProtocol = "Blobfuse"
+)
+
+// FileStorage configures the prerequisites of the Azure Files and Azure Blob storage CSI drivers on a virtual machine.
+type FileStorage struct {
+	// Protocols are the protocols whose packages and kernel modules are installed and loaded when the virtual machine
+	// is provisioned.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Protocols []FileStorageProtocol `json:"protocols"`
+}
+
+// CACertificates references additional PEM encoded CA certificates trusted by a virtual machine.
+// Exactly one of ConfigMapRef or SecretRef must be set.
+type CACertificates struct {
+	// ConfigMapRef references a key of a ConfigMap, in the namespace of the machine, holding the CA certificates.
+	// +optional
+	ConfigMapRef *CACertificatesReference `json:"configMapRef,omitempty"`
+
+	// SecretRef references a key of a Secret, in the namespace of the machine, holding the CA certificates.
+	// +optional
+	SecretRef *CACertificatesReference `json:"secretRef,omitempty"`
+}
+
+// CACertificatesReference references a key of a ConfigMap or Secret holding CA certificates.
+type CACertificatesReference struct {
+	// Name of the ConfigMap or Secret.
+	Name string `json:"name"`
+
+	// Key of the ConfigMap or Secret holding the CA certificates. Defaults to "ca.crt".
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// OSDistro is the Linux distribution running on a machine.
+type OSDistro string
+
+const (
+	// OSDistroUbuntu is the Ubuntu Linux distribution.
+	OSDistroUbuntu OSDistro = "Ubuntu"
+	// OSDistroAzureLinux is the Azure Linux (formerly CBL-Mariner) distribution.
+	OSDistroAzureLinux OSDistro = "AzureLinux"
+)
+
 // DataDisk specifies the parameters that are used to add one or more data disks to the machine.
 type DataDisk struct {
 	// NameSuffix is the suffix to be appended to the machine name to generate the disk name.
 	// Each disk name will be in format <machineName>_<nameSuffix>.
 	NameSuffix string `json:"nameSuffix"`
-	// DiskSizeGB is the size in GB to assign to the data disk.
+	// DiskSizeGB is the size in GB to assign to the data disk. It is required unless ExistingDiskID is set.
+	// +optional
 	DiskSizeGB int32 `json:"diskSizeGB"`
 	// ManagedDisk specifies the Managed Disk parameters for the data disk.
 	// +optional
@@ -515,8 +1296,57 @@ type DataDisk struct {
 	// +optional
 	// +kubebuilder:validation:Enum=None;ReadOnly;ReadWrite
 	CachingType string `json:"cachingType,omitempty"`
+	// WriteAcceleratorEnabled enables Write Accelerator on the disk, which lowers the write latency of Premium_LRS
+	// disks on M-series VM sizes. It requires a cachingType of None or ReadOnly.
+	// See https://learn.microsoft.com/azure/virtual-machines/how-to-enable-write-accelerator.
+	// +optional
+	WriteAcceleratorEnabled *bool `json:"writeAcceleratorEnabled,omitempty"`
+	// ExistingDiskID is the resource ID of an existing managed disk to attach to the virtual machine instead of
+	// creating a new one, e.g. to keep the data of a stateful workload across machine replacements. The disk must
+	// be in the same location and availability zone as the virtual machine. DiskSizeGB and ManagedDisk are not used
+	// for existing disks.
+	// +optional
+	ExistingDiskID string `json:"existingDiskID,omitempty"`
+	// DeletePolicy specifies whether the disk is deleted, or detached and retained, when the machine is deleted.
+	// Defaults to Detach for existing disks and to Delete for disks created with the machine.
+	// +kubebuilder:validation:Enum=Delete;Detach
+	// +optional
+	DeletePolicy DiskDeletePolicy `json:"deletePolicy,omitempty"`
+}
+
+// GetDeletePolicy returns the delete policy of the data disk, defaulting to Detach for existing disks and to Delete
+// for disks created with the machine.
+func (d DataDisk) GetDeletePolicy() DiskDeletePolicy {
+	if d.DeletePolicy != "" {
+		return d.DeletePolicy
+	}
+	if d.ExistingDiskID != "" {
+		return DiskDeletePolicyDetach
+	}
+	return DiskDeletePolicyDelete
 }
 
+// DiskDeletePolicy specifies what happens to a data disk when its machine is deleted.
+type DiskDeletePolicy string
+
+const (
+	// DiskDeletePolicyDelete deletes the disk when the machine is deleted.
+	DiskDeletePolicyDelete DiskDeletePolicy = "Delete"
+	// DiskDeletePolicyDetach detaches the disk and retains it when the machine is deleted.
+	DiskDeletePolicyDetach DiskDeletePolicy = "Detach"
+)
+
+// DiskControllerType specifies the disk controller used to attach the disks of a virtual machine.
+// +kubebuilder:validation:Enum=NVMe;SCSI
+type DiskControllerType string
+
+const (
+	// DiskControllerTypeNVMe attaches the disks through an NVMe controller.
+	DiskControllerTypeNVMe DiskControllerType = "NVMe"
+	// DiskControllerTypeSCSI attaches the disks through a SCSI controller.
+	DiskControllerTypeSCSI DiskControllerType = "SCSI"
+)
+
 // ManagedDiskParameters defines the parameters of a managed disk.
 type ManagedDiskParameters struct {
 	// +optional
@@ -527,7 +1357,8 @@ type ManagedDiskParameters struct {
 
 // DiskEncryptionSetParameters defines disk encryption options.
 type DiskEncryptionSetParameters struct {
-	// ID defines resourceID for diskEncryptionSet resource. It must be in the same subscription
+	// ID defines resourceID for diskEncryptionSet resource. When it is in another subscription than the cluster, the
+	// cluster identity must be able to read it, see the crossSubscriptionAccess of the AzureCluster.
 	// +optional
 	ID string `json:"id,omitempty"`
 }
@@ -598,9 +1429,25 @@ type AzureNetworkInterface struct {
 	// +optional
 	AcceleratedNetworking *bool `json:"acceleratedNetworking,omitempty"`
 
+	// EnableIPForwarding enables IP forwarding on the interface, which is required for nodes routing traffic for other
+	// addresses, such as Calico in non-encapsulated mode or egress gateways. If omitted, the EnableIPForwarding of the
+	// machine is used.
+	// +optional
+	EnableIPForwarding *bool `json:"enableIPForwarding,omitempty"`
+
 	// Attach an already provisioned interface by ID.
 	// +optional
 	ID string `json:"id,omitempty"`
+
+	// InternalDNSNameLabel is the relative DNS name of the interface, used by the Azure-provided DNS for internal
+	// communication between VMs in the same virtual network. It must be a valid DNS label.
+	// +optional
+	InternalDNSNameLabel string `json:"internalDNSNameLabel,omitempty"`
+
+	// DNSServers is a list of DNS server IP addresses used by the interface instead of the DNS servers of the
+	// virtual network.
+	// +optional
+	DNSServers []string `json:"dnsServers,omitempty"`
 }
 
 // AzureIPConfig defines options to confiure a network interface.
@@ -648,6 +1495,17 @@ func (s SubnetSpec) IsNatGatewayEnabled() bool {
 	return s.NatGateway.Name != ""
 }
 
+// HasOwnIP returns whether the alias frontend has its own frontend IP rather than using the frontend IP of the load
+// balancer.
+func (f APIServerAliasFrontend) HasOwnIP() bool {
+	return f.PublicIP != nil || f.PrivateIPAddress != ""
+}
+
+// IsIPBackendPool returns whether the members of the backend pool of the load balancer are referenced by IP address.
+func (lb LoadBalancerClassSpec) IsIPBackendPool() bool {
+	return lb.BackendPoolType == BackendPoolTypeIP
+}
+
 // SecurityProfile specifies the Security profile settings for a
 // virtual machine or virtual machine scale set.
 type SecurityProfile struct {
@@ -16,7 +16,10 @@ limitations under the License.
 
 package v1beta1
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // AzureClusterClassSpec defines the AzureCluster properties that may be shared across several Azure clusters.
 type AzureClusterClassSpec struct {
@@ -25,6 +28,12 @@ type AzureClusterClassSpec struct {
 
 	Location string `json:"location"`
 
+	// ExtendedLocation is the extended location of the cluster, e.g. an Azure Public MEC edge zone of the location. The
+	// virtual network, load balancers, public IPs, network interfaces and VMs of the cluster are created in it.
+	// This requires the EdgeZone feature flag to be enabled.
+	// +optional
+	ExtendedLocation *ExtendedLocationSpec `json:"extendedLocation,omitempty"`
+
 	// AdditionalTags is an optional set of tags to add to Azure resources managed by the Azure provider, in addition to the
 	// ones added by default.
 	// +optional
@@ -37,7 +46,6 @@ type AzureClusterClassSpec struct {
 	// AzureEnvironment is the name of the AzureCloud to be used.
 	// The default value that would be used by most users is "AzurePublicCloud", other values are:
 	// - ChinaCloud: "AzureChinaCloud"
-	// - GermanCloud: "AzureGermanCloud"
 	// - PublicCloud: "AzurePublicCloud"
 	// - USGovernmentCloud: "AzureUSGovernmentCloud"
 	// +optional
@@ -50,13 +58,221 @@ type AzureClusterClassSpec struct {
 	// Note: All cloud provider config values can be customized by creating the secret beforehand. CloudProviderConfigOverrides is only used when the secret is managed by the Azure Provider.
 	// +optional
 	CloudProviderConfigOverrides *CloudProviderConfigOverrides `json:"cloudProviderConfigOverrides,omitempty"`
+
+	// CloudProviderIdentity configures the identity written to the cloud provider config secret that is generated for the cluster.
+	// If not set, the credentials of the cluster identity are used.
+	// +optional
+	CloudProviderIdentity *CloudProviderIdentity `json:"cloudProviderIdentity,omitempty"`
+
+	// Addons configures the workload cluster addons that are installed and kept up to date by the Azure provider.
+	// The version of each addon is chosen based on the Kubernetes version of the cluster.
+	// This requires the ClusterAddons feature flag to be enabled.
+	// +optional
+	Addons *AddonsSpec `json:"addons,omitempty"`
+
+	// BootstrapDataStorage configures the storage used to deliver the bootstrap data of machines that exceeds the
+	// maximum size of the VM custom data. When set, such bootstrap data is uploaded to a blob and the VM custom data
+	// only contains a cloud-init include of a short-lived SAS URL to that blob.
+	// +optional
+	BootstrapDataStorage *BootstrapDataStorage `json:"bootstrapDataStorage,omitempty"`
+
+	// Proxy configures the HTTP proxy the machines of the cluster reach the internet through, e.g. when they have no
+	// direct outbound connectivity. It is written to the environment, APT and containerd configuration of Linux
+	// machines through cloud-init, and set in the environment of the command of the bootstrap VM extension.
+	// +optional
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
+
+	// BootstrapExtension configures the VM extension reporting whether the machines of the cluster bootstrapped
+	// successfully, e.g. to use an extension available in an air-gapped environment without access to the default
+	// extension repositories, or to disable it and rely on cloud-init only.
+	// +optional
+	BootstrapExtension *BootstrapExtension `json:"bootstrapExtension,omitempty"`
+
+	// RegistryMirrors configure the mirrors containerd pulls the images of registries through on the Linux machines of
+	// the cluster, e.g. an internal mirror of docker.io. They are written to the containerd registry host configuration
+	// through cloud-init.
+	// +optional
+	RegistryMirrors []RegistryMirror `json:"registryMirrors,omitempty"`
+}
+
+// ExtendedLocationType is the type of an extended location.
+type ExtendedLocationType string
+
+const (
+	// ExtendedLocationTypeEdgeZone is an Azure Public MEC edge zone.
+	ExtendedLocationTypeEdgeZone ExtendedLocationType = "EdgeZone"
+)
+
+// ExtendedLocationSpec defines an extended location of Azure, e.g. an edge zone.
+type ExtendedLocationSpec struct {
+	// Name is the name of the extended location, e.g. attatlanta1.
+	Name string `json:"name"`
+
+	// Type is the type of the extended location.
+	// +kubebuilder:validation:Enum=EdgeZone
+	Type ExtendedLocationType `json:"type"`
 }
 
+// DefaultRegistryMirror is the registry of the mirrors used for all the registries without mirrors of their own.
+const DefaultRegistryMirror = "_default"
+
+// RegistryMirror defines the mirrors of a container image registry.
+type RegistryMirror struct {
+	// Registry is the host, and optional port, of the mirrored registry, e.g. "docker.io" or "registry.k8s.io", or
+	// "_default" to mirror all the registries without mirrors of their own.
+	Registry string `json:"registry"`
+
+	// Endpoints are the URLs of the mirrors, tried in order before the registry itself, e.g.
+	// "https://mirror.example.com". Endpoints with a path, e.g. "https://harbor.example.com/v2/docker-hub", are used
+	// as the full path of the registry API.
+	// +kubebuilder:validation:MinItems=1
+	Endpoints []string `json:"endpoints"`
+
+	// CredentialsSecretRef references a Secret, in the namespace of the cluster, holding the "username" and "password"
+	// authenticating to the mirrors, e.g. of type kubernetes.io/basic-auth.
+	// +optional
+	CredentialsSecretRef *RegistryCredentialsReference `json:"credentialsSecretRef,omitempty"`
+}
+
+// RegistryCredentialsReference references a Secret holding the credentials of registry mirrors.
+type RegistryCredentialsReference struct {
+	// Name of the Secret.
+	Name string `json:"name"`
+}
+
+// BootstrapExtension defines the VM extension checking the bootstrap of the machines of a cluster.
+type BootstrapExtension struct {
+	// Disabled disables the bootstrap VM extension. The machines are then provisioned by cloud-init only, and their
+	// bootstrap failures are not reported in the BootstrapSucceeded condition.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Linux is the extension used on Linux machines, instead of the CAPZ Linux bootstrapping extension, which is only
+	// available in the Azure public cloud.
+	// +optional
+	Linux *BootstrapExtensionSource `json:"linux,omitempty"`
+
+	// Windows is the extension used on Windows machines, instead of the CAPZ Windows bootstrapping extension, which is
+	// only available in the Azure public cloud.
+	// +optional
+	Windows *BootstrapExtensionSource `json:"windows,omitempty"`
+}
+
+// BootstrapExtensionSource defines a VM extension running a command that checks the bootstrap of a machine, such as the
+// Custom Script extension.
+type BootstrapExtensionSource struct {
+	// Publisher is the publisher of the extension, e.g. "Microsoft.Azure.Extensions".
+	Publisher string `json:"publisher"`
+
+	// Type is the type of the extension, e.g. "CustomScript".
+	Type string `json:"type"`
+
+	// Version is the version of the extension handler, e.g. "2.1".
+	Version string `json:"version"`
+
+	// ScriptURL is the URL of a script the extension downloads through its fileUris setting and runs instead of the
+	// default bootstrap check, e.g. from a storage account reachable by the machines. The script must exit with a
+	// non-zero code when the bootstrap of the machine failed.
+	// +optional
+	ScriptURL string `json:"scriptURL,omitempty"`
+}
+
+// ProxyConfig defines the HTTP proxy used by the machines of a cluster.
+type ProxyConfig struct {
+	// HTTPProxy is the URL of the proxy for HTTP requests, e.g. "http://proxy.example.com:3128".
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the URL of the proxy for HTTPS requests, e.g. "http://proxy.example.com:3128".
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy lists the host names, domains, IP addresses and CIDRs that are reached without the proxy, e.g.
+	// ".svc" or "10.96.0.0/12". localhost, the Azure Instance Metadata Service and wire server addresses, and the
+	// address space of the cluster virtual network are always reached without the proxy.
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+}
+
+// BootstrapDataStorage defines the storage account used to deliver large bootstrap data to machines.
+type BootstrapDataStorage struct {
+	// StorageAccountName is the name of an existing storage account the bootstrap data is uploaded to.
+	StorageAccountName string `json:"storageAccountName"`
+
+	// ResourceGroup is the resource group of the storage account. Defaults to the resource group of the cluster.
+	// +optional
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+
+	// ContainerName is the name of the blob container the bootstrap data is uploaded to. It is created if it doesn't exist.
+	// +kubebuilder:default=capz-bootstrap-data
+	// +optional
+	ContainerName string `json:"containerName,omitempty"`
+
+	// SASDuration is how long the SAS URL given to the machine to download its bootstrap data is valid for.
+	// +kubebuilder:default="1h"
+	// +optional
+	SASDuration *metav1.Duration `json:"sasDuration,omitempty"`
+}
+
+// AddonsSpec defines the workload cluster addons installed by the Azure provider.
+type AddonsSpec struct {
+	// CloudProviderAzure installs the external cloud provider for Azure (cloud-controller-manager and cloud-node-manager).
+	// +optional
+	CloudProviderAzure bool `json:"cloudProviderAzure,omitempty"`
+
+	// AzureDiskCSIDriver installs the Azure Disk CSI driver.
+	// +optional
+	AzureDiskCSIDriver bool `json:"azureDiskCSIDriver,omitempty"`
+
+	// AzureFileCSIDriver installs the Azure File CSI driver.
+	// +optional
+	AzureFileCSIDriver bool `json:"azureFileCSIDriver,omitempty"`
+}
+
+// CloudProviderIdentity defines the identity used by the cloud provider to authenticate against Azure.
+type CloudProviderIdentity struct {
+	// Type is the type of identity the cloud provider should use.
+	// None uses the service principal credentials of the cluster identity.
+	// WorkloadIdentity uses a service account token federated with an Azure AD application or managed identity.
+	// +kubebuilder:validation:Enum=None;SystemAssigned;UserAssigned;WorkloadIdentity
+	// +kubebuilder:default=None
+	// +optional
+	Type VMIdentity `json:"type,omitempty"`
+
+	// UserAssignedIdentityClientID is the client ID of the user-assigned identity attached to the cluster nodes.
+	// Only applicable when type is UserAssigned.
+	// +optional
+	UserAssignedIdentityClientID string `json:"userAssignedIdentityClientID,omitempty"`
+
+	// WorkloadIdentityClientID is the client ID of the Azure AD application or managed identity trusting the service
+	// account tokens of the cloud provider components. Only applicable when type is WorkloadIdentity.
+	// +optional
+	WorkloadIdentityClientID string `json:"workloadIdentityClientID,omitempty"`
+}
+
+// CloudProviderWorkloadIdentity is the CloudProviderIdentity type making the cloud provider authenticate with Azure AD
+// workload identity instead of a secret or an identity attached to the nodes.
+const CloudProviderWorkloadIdentity VMIdentity = "WorkloadIdentity"
+
 // NetworkClassSpec defines the NetworkSpec properties that may be shared across several Azure clusters.
 type NetworkClassSpec struct {
 	// PrivateDNSZoneName defines the zone name for the Azure Private DNS.
 	// +optional
 	PrivateDNSZoneName string `json:"privateDNSZoneName,omitempty"`
+
+	// OutboundType defines how egress traffic leaves the cluster's virtual network.
+	// "LoadBalancer" (the default) lets the Azure provider create outbound load balancers and NAT gateways
+	// with public IPs as needed. "UserDefinedRouting" asserts that egress is handled by a pre-existing
+	// NAT gateway, firewall or route table, and that no public IPs or public load balancers are created for the cluster.
+	// +kubebuilder:validation:Enum=LoadBalancer;UserDefinedRouting
+	// +optional
+	OutboundType OutboundType `json:"outboundType,omitempty"`
+}
+
+// IsUserDefinedRouting returns true if egress for the cluster is handled outside of the Azure provider,
+// in which case no public IPs or public load balancers may be created.
+func (n NetworkClassSpec) IsUserDefinedRouting() bool {
+	return n.OutboundType == OutboundTypeUserDefinedRouting
 }
 
 // VnetClassSpec defines the VnetSpec properties that may be shared across several Azure clusters.
@@ -90,6 +306,25 @@ type LoadBalancerClassSpec struct {
 	// IdleTimeoutInMinutes specifies the timeout for the TCP idle connection.
 	// +optional
 	IdleTimeoutInMinutes *int32 `json:"idleTimeoutInMinutes,omitempty"`
+	// BackendPoolType is the type of the backend pool of the load balancer. NIC pools reference the IP configurations
+	// of the network interfaces of the machines, while IP pools reference their private IP addresses and virtual
+	// networks, letting machines in peered virtual networks join the pool. Only Internal API server load balancers
+	// support IP pools. Defaults to NIC.
+	// +kubebuilder:validation:Enum=NIC;IP
+	// +optional
+	BackendPoolType BackendPoolType `json:"backendPoolType,omitempty"`
+	// EnableTCPReset sends bidirectional TCP resets when the connections of the rules of the load balancer are idle for
+	// longer than IdleTimeoutInMinutes, instead of silently dropping them, so that long-lived connections such as the
+	// ones of kubectl exec or watches are closed and reopened by the clients. Defaults to false.
+	// +optional
+	EnableTCPReset *bool `json:"enableTCPReset,omitempty"`
+	// LoadDistribution is the session persistence of the load balancing rules of the load balancer. Default
+	// distributes the connections by 5-tuple, SourceIP sends the connections of a client IP to the same backend and
+	// SourceIPProtocol the connections of a client IP and protocol. Only API server load balancers have load
+	// balancing rules. Defaults to Default.
+	// +kubebuilder:validation:Enum=Default;SourceIP;SourceIPProtocol
+	// +optional
+	LoadDistribution LoadDistribution `json:"loadDistribution,omitempty"`
 }
 
 // SecurityGroupClass defines the SecurityGroup properties that may be shared across several Azure clusters.
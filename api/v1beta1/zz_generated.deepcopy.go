@@ -29,6 +29,31 @@ import (
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerAliasFrontend) DeepCopyInto(out *APIServerAliasFrontend) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PublicIP != nil {
+		in, out := &in.PublicIP, &out.PublicIP
+		*out = new(PublicIPSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerAliasFrontend.
+func (in *APIServerAliasFrontend) DeepCopy() *APIServerAliasFrontend {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerAliasFrontend)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AdditionalCapabilities) DeepCopyInto(out *AdditionalCapabilities) {
 	*out = *in
@@ -37,6 +62,11 @@ func (in *AdditionalCapabilities) DeepCopyInto(out *AdditionalCapabilities) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.HibernationEnabled != nil {
+		in, out := &in.HibernationEnabled, &out.HibernationEnabled
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalCapabilities.
@@ -49,6 +79,21 @@ func (in *AdditionalCapabilities) DeepCopy() *AdditionalCapabilities {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonsSpec) DeepCopyInto(out *AddonsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonsSpec.
+func (in *AddonsSpec) DeepCopy() *AddonsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddressRecord) DeepCopyInto(out *AddressRecord) {
 	*out = *in
@@ -152,6 +197,11 @@ func (in *AzureCluster) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureClusterClassSpec) DeepCopyInto(out *AzureClusterClassSpec) {
 	*out = *in
+	if in.ExtendedLocation != nil {
+		in, out := &in.ExtendedLocation, &out.ExtendedLocation
+		*out = new(ExtendedLocationSpec)
+		**out = **in
+	}
 	if in.AdditionalTags != nil {
 		in, out := &in.AdditionalTags, &out.AdditionalTags
 		*out = make(Tags, len(*in))
@@ -169,6 +219,38 @@ func (in *AzureClusterClassSpec) DeepCopyInto(out *AzureClusterClassSpec) {
 		*out = new(CloudProviderConfigOverrides)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CloudProviderIdentity != nil {
+		in, out := &in.CloudProviderIdentity, &out.CloudProviderIdentity
+		*out = new(CloudProviderIdentity)
+		**out = **in
+	}
+	if in.Addons != nil {
+		in, out := &in.Addons, &out.Addons
+		*out = new(AddonsSpec)
+		**out = **in
+	}
+	if in.BootstrapDataStorage != nil {
+		in, out := &in.BootstrapDataStorage, &out.BootstrapDataStorage
+		*out = new(BootstrapDataStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ProxyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BootstrapExtension != nil {
+		in, out := &in.BootstrapExtension, &out.BootstrapExtension
+		*out = new(BootstrapExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make([]RegistryMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterClassSpec.
@@ -249,6 +331,11 @@ func (in *AzureClusterIdentitySpec) DeepCopyInto(out *AzureClusterIdentitySpec)
 		*out = new(AllowedNamespaces)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DeniedNamespaces != nil {
+		in, out := &in.DeniedNamespaces, &out.DeniedNamespaces
+		*out = new(DeniedNamespaces)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterIdentitySpec.
@@ -322,6 +409,33 @@ func (in *AzureClusterSpec) DeepCopyInto(out *AzureClusterSpec) {
 	in.NetworkSpec.DeepCopyInto(&out.NetworkSpec)
 	in.BastionSpec.DeepCopyInto(&out.BastionSpec)
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(AzureMonitoring)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityProfile != nil {
+		in, out := &in.SecurityProfile, &out.SecurityProfile
+		*out = new(ClusterSecurityProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AttachedACRs != nil {
+		in, out := &in.AttachedACRs, &out.AttachedACRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(BudgetGuardrail)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PolicyExemptions != nil {
+		in, out := &in.PolicyExemptions, &out.PolicyExemptions
+		*out = make([]PolicyExemption, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterSpec.
@@ -356,6 +470,13 @@ func (in *AzureClusterStatus) DeepCopyInto(out *AzureClusterStatus) {
 		*out = make(Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(ResourceStatuses, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureClusterStatus.
@@ -611,6 +732,11 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AdditionalSSHPublicKeys != nil {
+		in, out := &in.AdditionalSSHPublicKeys, &out.AdditionalSSHPublicKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.AdditionalTags != nil {
 		in, out := &in.AdditionalTags, &out.AdditionalTags
 		*out = make(Tags, len(*in))
@@ -645,6 +771,61 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.UserData != nil {
+		in, out := &in.UserData, &out.UserData
+		*out = new(UserData)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MultiInstanceGPU != nil {
+		in, out := &in.MultiInstanceGPU, &out.MultiInstanceGPU
+		*out = new(MultiInstanceGPU)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LocalStorage != nil {
+		in, out := &in.LocalStorage, &out.LocalStorage
+		*out = new(LocalStorage)
+		**out = **in
+	}
+	if in.FileStorage != nil {
+		in, out := &in.FileStorage, &out.FileStorage
+		*out = new(FileStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CACertificates != nil {
+		in, out := &in.CACertificates, &out.CACertificates
+		*out = new(CACertificates)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiskSnapshots != nil {
+		in, out := &in.DiskSnapshots, &out.DiskSnapshots
+		*out = new(DiskSnapshots)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupProtection != nil {
+		in, out := &in.BackupProtection, &out.BackupProtection
+		*out = new(BackupProtection)
+		**out = **in
+	}
+	if in.EtcdDataDisk != nil {
+		in, out := &in.EtcdDataDisk, &out.EtcdDataDisk
+		*out = new(EtcdDataDisk)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackendPoolDrainTimeout != nil {
+		in, out := &in.BackendPoolDrainTimeout, &out.BackendPoolDrainTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TombstonePolicy != nil {
+		in, out := &in.TombstonePolicy, &out.TombstonePolicy
+		*out = new(TombstonePolicy)
+		**out = **in
+	}
+	if in.DerivedResources != nil {
+		in, out := &in.DerivedResources, &out.DerivedResources
+		*out = new(DerivedResources)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineSpec.
@@ -670,6 +851,20 @@ func (in *AzureMachineStatus) DeepCopyInto(out *AzureMachineStatus) {
 		*out = new(ProvisioningState)
 		**out = **in
 	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(VMPlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(Image)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastDiskSnapshotTime != nil {
+		in, out := &in.LastDiskSnapshotTime, &out.LastDiskSnapshotTime
+		*out = (*in).DeepCopy()
+	}
 	if in.FailureReason != nil {
 		in, out := &in.FailureReason, &out.FailureReason
 		*out = new(errors.MachineStatusError)
@@ -692,6 +887,13 @@ func (in *AzureMachineStatus) DeepCopyInto(out *AzureMachineStatus) {
 		*out = make(Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(ResourceStatuses, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineStatus.
@@ -811,6 +1013,26 @@ func (in *AzureMarketplaceImage) DeepCopy() *AzureMarketplaceImage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMonitoring) DeepCopyInto(out *AzureMonitoring) {
+	*out = *in
+	if in.RetentionInDays != nil {
+		in, out := &in.RetentionInDays, &out.RetentionInDays
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMonitoring.
+func (in *AzureMonitoring) DeepCopy() *AzureMonitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMonitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureNetworkInterface) DeepCopyInto(out *AzureNetworkInterface) {
 	*out = *in
@@ -819,6 +1041,16 @@ func (in *AzureNetworkInterface) DeepCopyInto(out *AzureNetworkInterface) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.EnableIPForwarding != nil {
+		in, out := &in.EnableIPForwarding, &out.EnableIPForwarding
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DNSServers != nil {
+		in, out := &in.DNSServers, &out.DNSServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureNetworkInterface.
@@ -886,6 +1118,21 @@ func (in *BackOffConfig) DeepCopy() *BackOffConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupProtection) DeepCopyInto(out *BackupProtection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupProtection.
+func (in *BackupProtection) DeepCopy() *BackupProtection {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupProtection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BastionSpec) DeepCopyInto(out *BastionSpec) {
 	*out = *in
@@ -927,171 +1174,640 @@ func (in *BastionTemplateSpec) DeepCopy() *BastionTemplateSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BuildParams) DeepCopyInto(out *BuildParams) {
+func (in *BootstrapDataStorage) DeepCopyInto(out *BootstrapDataStorage) {
 	*out = *in
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
-		*out = new(string)
+	if in.SASDuration != nil {
+		in, out := &in.SASDuration, &out.SASDuration
+		*out = new(v1.Duration)
 		**out = **in
 	}
-	if in.Role != nil {
-		in, out := &in.Role, &out.Role
-		*out = new(string)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapDataStorage.
+func (in *BootstrapDataStorage) DeepCopy() *BootstrapDataStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapDataStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapExtension) DeepCopyInto(out *BootstrapExtension) {
+	*out = *in
+	if in.Linux != nil {
+		in, out := &in.Linux, &out.Linux
+		*out = new(BootstrapExtensionSource)
 		**out = **in
 	}
-	if in.Additional != nil {
-		in, out := &in.Additional, &out.Additional
-		*out = make(Tags, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = new(BootstrapExtensionSource)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildParams.
-func (in *BuildParams) DeepCopy() *BuildParams {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapExtension.
+func (in *BootstrapExtension) DeepCopy() *BootstrapExtension {
 	if in == nil {
 		return nil
 	}
-	out := new(BuildParams)
+	out := new(BootstrapExtension)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CloudProviderConfigOverrides) DeepCopyInto(out *CloudProviderConfigOverrides) {
+func (in *BootstrapExtensionSource) DeepCopyInto(out *BootstrapExtensionSource) {
 	*out = *in
-	if in.RateLimits != nil {
-		in, out := &in.RateLimits, &out.RateLimits
-		*out = make([]RateLimitSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	in.BackOffs.DeepCopyInto(&out.BackOffs)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudProviderConfigOverrides.
-func (in *CloudProviderConfigOverrides) DeepCopy() *CloudProviderConfigOverrides {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapExtensionSource.
+func (in *BootstrapExtensionSource) DeepCopy() *BootstrapExtensionSource {
 	if in == nil {
 		return nil
 	}
-	out := new(CloudProviderConfigOverrides)
+	out := new(BootstrapExtensionSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataDisk) DeepCopyInto(out *DataDisk) {
+func (in *BudgetGuardrail) DeepCopyInto(out *BudgetGuardrail) {
 	*out = *in
-	if in.ManagedDisk != nil {
-		in, out := &in.ManagedDisk, &out.ManagedDisk
-		*out = new(ManagedDiskParameters)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Lun != nil {
-		in, out := &in.Lun, &out.Lun
-		*out = new(int32)
+	if in.MaxVCPUs != nil {
+		in, out := &in.MaxVCPUs, &out.MaxVCPUs
+		*out = new(int64)
 		**out = **in
 	}
+	if in.MaxHourlyCost != nil {
+		in, out := &in.MaxHourlyCost, &out.MaxHourlyCost
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.VCPUHourlyCost != nil {
+		in, out := &in.VCPUHourlyCost, &out.VCPUHourlyCost
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.VMSizes != nil {
+		in, out := &in.VMSizes, &out.VMSizes
+		*out = make([]BudgetVMSize, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataDisk.
-func (in *DataDisk) DeepCopy() *DataDisk {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BudgetGuardrail.
+func (in *BudgetGuardrail) DeepCopy() *BudgetGuardrail {
 	if in == nil {
 		return nil
 	}
-	out := new(DataDisk)
+	out := new(BudgetGuardrail)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DiffDiskSettings) DeepCopyInto(out *DiffDiskSettings) {
+func (in *BudgetUsage) DeepCopyInto(out *BudgetUsage) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiffDiskSettings.
-func (in *DiffDiskSettings) DeepCopy() *DiffDiskSettings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BudgetUsage.
+func (in *BudgetUsage) DeepCopy() *BudgetUsage {
 	if in == nil {
 		return nil
 	}
-	out := new(DiffDiskSettings)
+	out := new(BudgetUsage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DiskEncryptionSetParameters) DeepCopyInto(out *DiskEncryptionSetParameters) {
+func (in *BudgetVMSize) DeepCopyInto(out *BudgetVMSize) {
 	*out = *in
+	if in.VCPUs != nil {
+		in, out := &in.VCPUs, &out.VCPUs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.HourlyCost != nil {
+		in, out := &in.HourlyCost, &out.HourlyCost
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskEncryptionSetParameters.
-func (in *DiskEncryptionSetParameters) DeepCopy() *DiskEncryptionSetParameters {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BudgetVMSize.
+func (in *BudgetVMSize) DeepCopy() *BudgetVMSize {
 	if in == nil {
 		return nil
 	}
-	out := new(DiskEncryptionSetParameters)
+	out := new(BudgetVMSize)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FrontendIP) DeepCopyInto(out *FrontendIP) {
+func (in *BuildParams) DeepCopyInto(out *BuildParams) {
 	*out = *in
-	if in.PublicIP != nil {
-		in, out := &in.PublicIP, &out.PublicIP
-		*out = new(PublicIPSpec)
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
 		**out = **in
 	}
-	out.FrontendIPClass = in.FrontendIPClass
+	if in.Role != nil {
+		in, out := &in.Role, &out.Role
+		*out = new(string)
+		**out = **in
+	}
+	if in.Additional != nil {
+		in, out := &in.Additional, &out.Additional
+		*out = make(Tags, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendIP.
-func (in *FrontendIP) DeepCopy() *FrontendIP {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildParams.
+func (in *BuildParams) DeepCopy() *BuildParams {
 	if in == nil {
 		return nil
 	}
-	out := new(FrontendIP)
+	out := new(BuildParams)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FrontendIPClass) DeepCopyInto(out *FrontendIPClass) {
+func (in *CACertificates) DeepCopyInto(out *CACertificates) {
 	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(CACertificatesReference)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(CACertificatesReference)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendIPClass.
-func (in *FrontendIPClass) DeepCopy() *FrontendIPClass {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CACertificates.
+func (in *CACertificates) DeepCopy() *CACertificates {
 	if in == nil {
 		return nil
 	}
-	out := new(FrontendIPClass)
+	out := new(CACertificates)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Future) DeepCopyInto(out *Future) {
+func (in *CACertificatesReference) DeepCopyInto(out *CACertificatesReference) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Future.
-func (in *Future) DeepCopy() *Future {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CACertificatesReference.
+func (in *CACertificatesReference) DeepCopy() *CACertificatesReference {
 	if in == nil {
 		return nil
 	}
-	out := new(Future)
+	out := new(CACertificatesReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in Futures) DeepCopyInto(out *Futures) {
-	{
-		in := &in
-		*out = make(Futures, len(*in))
+func (in *CloudProviderConfigOverrides) DeepCopyInto(out *CloudProviderConfigOverrides) {
+	*out = *in
+	if in.RateLimits != nil {
+		in, out := &in.RateLimits, &out.RateLimits
+		*out = make([]RateLimitSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.BackOffs.DeepCopyInto(&out.BackOffs)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudProviderConfigOverrides.
+func (in *CloudProviderConfigOverrides) DeepCopy() *CloudProviderConfigOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudProviderConfigOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudProviderIdentity) DeepCopyInto(out *CloudProviderIdentity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudProviderIdentity.
+func (in *CloudProviderIdentity) DeepCopy() *CloudProviderIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudProviderIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSecurityProfile) DeepCopyInto(out *ClusterSecurityProfile) {
+	*out = *in
+	if in.Defender != nil {
+		in, out := &in.Defender, &out.Defender
+		*out = new(DefenderProfile)
+		**out = **in
+	}
+	if in.JustInTimeAccess != nil {
+		in, out := &in.JustInTimeAccess, &out.JustInTimeAccess
+		*out = new(JustInTimeAccess)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSecurityProfile.
+func (in *ClusterSecurityProfile) DeepCopy() *ClusterSecurityProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSecurityProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSForwardingRule) DeepCopyInto(out *DNSForwardingRule) {
+	*out = *in
+	if in.TargetDNSServers != nil {
+		in, out := &in.TargetDNSServers, &out.TargetDNSServers
+		*out = make([]TargetDNSServer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSForwardingRule.
+func (in *DNSForwardingRule) DeepCopy() *DNSForwardingRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSForwardingRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSResolverSpec) DeepCopyInto(out *DNSResolverSpec) {
+	*out = *in
+	in.OutboundSubnet.DeepCopyInto(&out.OutboundSubnet)
+	if in.ForwardingRules != nil {
+		in, out := &in.ForwardingRules, &out.ForwardingRules
+		*out = make([]DNSForwardingRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSResolverSpec.
+func (in *DNSResolverSpec) DeepCopy() *DNSResolverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSResolverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSResolverSubnet) DeepCopyInto(out *DNSResolverSubnet) {
+	*out = *in
+	if in.CIDRBlocks != nil {
+		in, out := &in.CIDRBlocks, &out.CIDRBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSResolverSubnet.
+func (in *DNSResolverSubnet) DeepCopy() *DNSResolverSubnet {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSResolverSubnet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataDisk) DeepCopyInto(out *DataDisk) {
+	*out = *in
+	if in.ManagedDisk != nil {
+		in, out := &in.ManagedDisk, &out.ManagedDisk
+		*out = new(ManagedDiskParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lun != nil {
+		in, out := &in.Lun, &out.Lun
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WriteAcceleratorEnabled != nil {
+		in, out := &in.WriteAcceleratorEnabled, &out.WriteAcceleratorEnabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataDisk.
+func (in *DataDisk) DeepCopy() *DataDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(DataDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefenderProfile) DeepCopyInto(out *DefenderProfile) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefenderProfile.
+func (in *DefenderProfile) DeepCopy() *DefenderProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(DefenderProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeniedNamespaces) DeepCopyInto(out *DeniedNamespaces) {
+	*out = *in
+	if in.NamespaceList != nil {
+		in, out := &in.NamespaceList, &out.NamespaceList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeniedNamespaces.
+func (in *DeniedNamespaces) DeepCopy() *DeniedNamespaces {
+	if in == nil {
+		return nil
+	}
+	out := new(DeniedNamespaces)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DerivedResource) DeepCopyInto(out *DerivedResource) {
+	*out = *in
+	if in.AdditionalTags != nil {
+		in, out := &in.AdditionalTags, &out.AdditionalTags
+		*out = make(Tags, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DerivedResource.
+func (in *DerivedResource) DeepCopy() *DerivedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(DerivedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DerivedResources) DeepCopyInto(out *DerivedResources) {
+	*out = *in
+	if in.NetworkInterface != nil {
+		in, out := &in.NetworkInterface, &out.NetworkInterface
+		*out = new(DerivedResource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OSDisk != nil {
+		in, out := &in.OSDisk, &out.OSDisk
+		*out = new(DerivedResource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PublicIP != nil {
+		in, out := &in.PublicIP, &out.PublicIP
+		*out = new(DerivedResource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DerivedResources.
+func (in *DerivedResources) DeepCopy() *DerivedResources {
+	if in == nil {
+		return nil
+	}
+	out := new(DerivedResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiffDiskSettings) DeepCopyInto(out *DiffDiskSettings) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiffDiskSettings.
+func (in *DiffDiskSettings) DeepCopy() *DiffDiskSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(DiffDiskSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskEncryptionSetParameters) DeepCopyInto(out *DiskEncryptionSetParameters) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskEncryptionSetParameters.
+func (in *DiskEncryptionSetParameters) DeepCopy() *DiskEncryptionSetParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskEncryptionSetParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskSnapshots) DeepCopyInto(out *DiskSnapshots) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskSnapshots.
+func (in *DiskSnapshots) DeepCopy() *DiskSnapshots {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskSnapshots)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdDataDisk) DeepCopyInto(out *EtcdDataDisk) {
+	*out = *in
+	if in.Lun != nil {
+		in, out := &in.Lun, &out.Lun
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdDataDisk.
+func (in *EtcdDataDisk) DeepCopy() *EtcdDataDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdDataDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtendedLocationSpec) DeepCopyInto(out *ExtendedLocationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtendedLocationSpec.
+func (in *ExtendedLocationSpec) DeepCopy() *ExtendedLocationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtendedLocationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalLoadBalancer) DeepCopyInto(out *ExternalLoadBalancer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalLoadBalancer.
+func (in *ExternalLoadBalancer) DeepCopy() *ExternalLoadBalancer {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalLoadBalancer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileStorage) DeepCopyInto(out *FileStorage) {
+	*out = *in
+	if in.Protocols != nil {
+		in, out := &in.Protocols, &out.Protocols
+		*out = make([]FileStorageProtocol, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileStorage.
+func (in *FileStorage) DeepCopy() *FileStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(FileStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FrontendIP) DeepCopyInto(out *FrontendIP) {
+	*out = *in
+	if in.PublicIP != nil {
+		in, out := &in.PublicIP, &out.PublicIP
+		*out = new(PublicIPSpec)
+		**out = **in
+	}
+	out.FrontendIPClass = in.FrontendIPClass
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendIP.
+func (in *FrontendIP) DeepCopy() *FrontendIP {
+	if in == nil {
+		return nil
+	}
+	out := new(FrontendIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FrontendIPClass) DeepCopyInto(out *FrontendIPClass) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FrontendIPClass.
+func (in *FrontendIPClass) DeepCopy() *FrontendIPClass {
+	if in == nil {
+		return nil
+	}
+	out := new(FrontendIPClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Future) DeepCopyInto(out *Future) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Future.
+func (in *Future) DeepCopy() *Future {
+	if in == nil {
+		return nil
+	}
+	out := new(Future)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in Futures) DeepCopyInto(out *Futures) {
+	{
+		in := &in
+		*out = make(Futures, len(*in))
 		copy(*out, *in)
 	}
 }
@@ -1106,6 +1822,31 @@ func (in Futures) DeepCopy() Futures {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalLoadBalancerSpec) DeepCopyInto(out *GlobalLoadBalancerSpec) {
+	*out = *in
+	if in.PublicIP != nil {
+		in, out := &in.PublicIP, &out.PublicIP
+		*out = new(PublicIPSpec)
+		**out = **in
+	}
+	if in.RegionalFrontendIPConfigurationIDs != nil {
+		in, out := &in.RegionalFrontendIPConfigurationIDs, &out.RegionalFrontendIPConfigurationIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalLoadBalancerSpec.
+func (in *GlobalLoadBalancerSpec) DeepCopy() *GlobalLoadBalancerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalLoadBalancerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Image) DeepCopyInto(out *Image) {
 	*out = *in
@@ -1156,6 +1897,53 @@ func (in *ImagePlan) DeepCopy() *ImagePlan {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JustInTimeAccess) DeepCopyInto(out *JustInTimeAccess) {
+	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]JustInTimeAccessPort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JustInTimeAccess.
+func (in *JustInTimeAccess) DeepCopy() *JustInTimeAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(JustInTimeAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JustInTimeAccessPort) DeepCopyInto(out *JustInTimeAccessPort) {
+	*out = *in
+	if in.AllowedSourceAddressPrefixes != nil {
+		in, out := &in.AllowedSourceAddressPrefixes, &out.AllowedSourceAddressPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxRequestAccessDuration != nil {
+		in, out := &in.MaxRequestAccessDuration, &out.MaxRequestAccessDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JustInTimeAccessPort.
+func (in *JustInTimeAccessPort) DeepCopy() *JustInTimeAccessPort {
+	if in == nil {
+		return nil
+	}
+	out := new(JustInTimeAccessPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadBalancerClassSpec) DeepCopyInto(out *LoadBalancerClassSpec) {
 	*out = *in
@@ -1164,6 +1952,11 @@ func (in *LoadBalancerClassSpec) DeepCopyInto(out *LoadBalancerClassSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.EnableTCPReset != nil {
+		in, out := &in.EnableTCPReset, &out.EnableTCPReset
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerClassSpec.
@@ -1186,11 +1979,28 @@ func (in *LoadBalancerSpec) DeepCopyInto(out *LoadBalancerSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.FrontendIPsCount != nil {
-		in, out := &in.FrontendIPsCount, &out.FrontendIPsCount
-		*out = new(int32)
-		**out = **in
-	}
+	if in.FrontendIPsCount != nil {
+		in, out := &in.FrontendIPsCount, &out.FrontendIPsCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalLoadBalancer)
+		**out = **in
+	}
+	if in.FrontendPort != nil {
+		in, out := &in.FrontendPort, &out.FrontendPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AliasFrontends != nil {
+		in, out := &in.AliasFrontends, &out.AliasFrontends
+		*out = make([]APIServerAliasFrontend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.LoadBalancerClassSpec.DeepCopyInto(&out.LoadBalancerClassSpec)
 }
 
@@ -1204,6 +2014,21 @@ func (in *LoadBalancerSpec) DeepCopy() *LoadBalancerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalStorage) DeepCopyInto(out *LocalStorage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalStorage.
+func (in *LocalStorage) DeepCopy() *LocalStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedDiskParameters) DeepCopyInto(out *ManagedDiskParameters) {
 	*out = *in
@@ -1224,6 +2049,26 @@ func (in *ManagedDiskParameters) DeepCopy() *ManagedDiskParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultiInstanceGPU) DeepCopyInto(out *MultiInstanceGPU) {
+	*out = *in
+	if in.InstancesPerGPU != nil {
+		in, out := &in.InstancesPerGPU, &out.InstancesPerGPU
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultiInstanceGPU.
+func (in *MultiInstanceGPU) DeepCopy() *MultiInstanceGPU {
+	if in == nil {
+		return nil
+	}
+	out := new(MultiInstanceGPU)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NatGateway) DeepCopyInto(out *NatGateway) {
 	*out = *in
@@ -1293,6 +2138,16 @@ func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
 		*out = new(LoadBalancerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GlobalAPIServerLB != nil {
+		in, out := &in.GlobalAPIServerLB, &out.GlobalAPIServerLB
+		*out = new(GlobalLoadBalancerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DNSResolver != nil {
+		in, out := &in.DNSResolver, &out.DNSResolver
+		*out = new(DNSResolverSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	out.NetworkClassSpec = in.NetworkClassSpec
 }
 
@@ -1359,6 +2214,11 @@ func (in *OSDisk) DeepCopyInto(out *OSDisk) {
 		*out = new(DiffDiskSettings)
 		**out = **in
 	}
+	if in.WriteAcceleratorEnabled != nil {
+		in, out := &in.WriteAcceleratorEnabled, &out.WriteAcceleratorEnabled
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSDisk.
@@ -1371,6 +2231,57 @@ func (in *OSDisk) DeepCopy() *OSDisk {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyExemption) DeepCopyInto(out *PolicyExemption) {
+	*out = *in
+	if in.PolicyDefinitionReferenceIDs != nil {
+		in, out := &in.PolicyDefinitionReferenceIDs, &out.PolicyDefinitionReferenceIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExpiresOn != nil {
+		in, out := &in.ExpiresOn, &out.ExpiresOn
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyExemption.
+func (in *PolicyExemption) DeepCopy() *PolicyExemption {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyExemption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyConfig) DeepCopyInto(out *ProxyConfig) {
+	*out = *in
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyConfig.
+func (in *ProxyConfig) DeepCopy() *ProxyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PublicIPSpec) DeepCopyInto(out *PublicIPSpec) {
 	*out = *in
@@ -1427,6 +2338,86 @@ func (in *RateLimitSpec) DeepCopy() *RateLimitSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryCredentialsReference) DeepCopyInto(out *RegistryCredentialsReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryCredentialsReference.
+func (in *RegistryCredentialsReference) DeepCopy() *RegistryCredentialsReference {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryCredentialsReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryMirror) DeepCopyInto(out *RegistryMirror) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(RegistryCredentialsReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirror.
+func (in *RegistryMirror) DeepCopy() *RegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceStatus) DeepCopyInto(out *ResourceStatus) {
+	*out = *in
+	if in.LastOperationTime != nil {
+		in, out := &in.LastOperationTime, &out.LastOperationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceStatus.
+func (in *ResourceStatus) DeepCopy() *ResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ResourceStatuses) DeepCopyInto(out *ResourceStatuses) {
+	{
+		in := &in
+		*out = make(ResourceStatuses, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceStatuses.
+func (in ResourceStatuses) DeepCopy() ResourceStatuses {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceStatuses)
+	in.DeepCopyInto(out)
+	return *out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteTable) DeepCopyInto(out *RouteTable) {
 	*out = *in
@@ -1703,6 +2694,42 @@ func (in Tags) DeepCopy() Tags {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetDNSServer) DeepCopyInto(out *TargetDNSServer) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetDNSServer.
+func (in *TargetDNSServer) DeepCopy() *TargetDNSServer {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetDNSServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TombstonePolicy) DeepCopyInto(out *TombstonePolicy) {
+	*out = *in
+	out.GracePeriod = in.GracePeriod
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TombstonePolicy.
+func (in *TombstonePolicy) DeepCopy() *TombstonePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TombstonePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UserAssignedIdentity) DeepCopyInto(out *UserAssignedIdentity) {
 	*out = *in
@@ -1718,6 +2745,163 @@ func (in *UserAssignedIdentity) DeepCopy() *UserAssignedIdentity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserData) DeepCopyInto(out *UserData) {
+	*out = *in
+	if in.Sections != nil {
+		in, out := &in.Sections, &out.Sections
+		*out = make([]UserDataSection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserData.
+func (in *UserData) DeepCopy() *UserData {
+	if in == nil {
+		return nil
+	}
+	out := new(UserData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserDataSecretReference) DeepCopyInto(out *UserDataSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserDataSecretReference.
+func (in *UserDataSecretReference) DeepCopy() *UserDataSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(UserDataSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserDataSection) DeepCopyInto(out *UserDataSection) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(UserDataSecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserDataSection.
+func (in *UserDataSection) DeepCopy() *UserDataSection {
+	if in == nil {
+		return nil
+	}
+	out := new(UserDataSection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMBootDiagnosticsStatus) DeepCopyInto(out *VMBootDiagnosticsStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMBootDiagnosticsStatus.
+func (in *VMBootDiagnosticsStatus) DeepCopy() *VMBootDiagnosticsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMBootDiagnosticsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMDiskStatus) DeepCopyInto(out *VMDiskStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMDiskStatus.
+func (in *VMDiskStatus) DeepCopy() *VMDiskStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMDiskStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMExtensionStatus) DeepCopyInto(out *VMExtensionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMExtensionStatus.
+func (in *VMExtensionStatus) DeepCopy() *VMExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMInstanceView) DeepCopyInto(out *VMInstanceView) {
+	*out = *in
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]VMExtensionStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]VMDiskStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.BootDiagnostics != nil {
+		in, out := &in.BootDiagnostics, &out.BootDiagnostics
+		*out = new(VMBootDiagnosticsStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMInstanceView.
+func (in *VMInstanceView) DeepCopy() *VMInstanceView {
+	if in == nil {
+		return nil
+	}
+	out := new(VMInstanceView)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMPlacement) DeepCopyInto(out *VMPlacement) {
+	*out = *in
+	if in.FaultDomain != nil {
+		in, out := &in.FaultDomain, &out.FaultDomain
+		*out = new(int32)
+		**out = **in
+	}
+	if in.UpdateDomain != nil {
+		in, out := &in.UpdateDomain, &out.UpdateDomain
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMPlacement.
+func (in *VMPlacement) DeepCopy() *VMPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(VMPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VnetClassSpec) DeepCopyInto(out *VnetClassSpec) {
 	*out = *in
@@ -0,0 +1,340 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/errors"
+)
+
+// AzureMachineSpec defines the desired state of AzureMachine.
+type AzureMachineSpec struct {
+	// ProviderID is the unique identifier as specified by the cloud provider.
+	// +optional
+	ProviderID *string `json:"providerID,omitempty"`
+
+	VMSize string `json:"vmSize"`
+
+	// FailureDomain is the failure domain unique identifier this Machine should be attached to,
+	// as defined in Cluster API. This relates to an Azure Availability Zone
+	// +optional
+	FailureDomain *string `json:"failureDomain,omitempty"`
+
+	// Image is used to provide details of an image to use during VM creation.
+	// If image details are omitted the image will default the Azure Marketplace "capi" offer,
+	// which is based on Ubuntu.
+	// +kubebuilder:validation:nullable
+	// +optional
+	Image *Image `json:"image,omitempty"`
+
+	// Identity is the type of identity used for the virtual machine.
+	// The type 'SystemAssigned' is an implicitly created identity.
+	// The generated identity will be assigned a Subscription contributor role.
+	// The type 'UserAssigned' is a standalone Azure resource provided by the user
+	// and assigned to the VM
+	// +kubebuilder:default=None
+	// +optional
+	Identity infrav1.VMIdentity `json:"identity,omitempty"`
+
+	// UserAssignedIdentities is a list of standalone Azure identities provided by the user
+	// The lifecycle of a user-assigned identity is managed separately from the lifecycle of
+	// the AzureMachine.
+	// See https://docs.microsoft.com/en-us/azure/active-directory/managed-identities-azure-resources/how-to-manage-ua-identity-cli
+	// +optional
+	UserAssignedIdentities []infrav1.UserAssignedIdentity `json:"userAssignedIdentities,omitempty"`
+
+	// RoleAssignmentName is the name of the role assignment to create for a system assigned identity. It can be any valid GUID.
+	// If not specified, a random GUID will be generated.
+	// +optional
+	RoleAssignmentName string `json:"roleAssignmentName,omitempty"`
+
+	// OSDisk specifies the parameters for the operating system disk of the machine
+	OSDisk infrav1.OSDisk `json:"osDisk"`
+
+	// DataDisk specifies the parameters that are used to add one or more data disks to the machine
+	// +optional
+	DataDisks []infrav1.DataDisk `json:"dataDisks,omitempty"`
+
+	// DiskControllerType specifies the disk controller used to attach the OS and data disks of the machine.
+	// NVMe requires a VM size that supports it and a HyperVGeneration V2 image, which is selected automatically
+	// for default images. If not specified, Azure uses the default controller of the VM size.
+	// +optional
+	DiskControllerType infrav1.DiskControllerType `json:"diskControllerType,omitempty"`
+
+	// SSHPublicKey is the SSH public key string base64 encoded to add to the virtual machine. If omitted, the public key
+	// of the SSH key pair generated for the cluster is added, whose private key is stored in the <cluster-name>-ssh Secret.
+	// +optional
+	SSHPublicKey string `json:"sshPublicKey,omitempty"`
+
+	// AdditionalSSHPublicKeys are SSH public key strings base64 encoded to authorize on the virtual machine in addition
+	// to SSHPublicKey, e.g. the keys of the members of a team.
+	// +optional
+	AdditionalSSHPublicKeys []string `json:"additionalSSHPublicKeys,omitempty"`
+
+	// AdditionalTags is an optional set of tags to add to an instance, in addition to the ones added by default by the
+	// Azure provider. If both the AzureCluster and the AzureMachine specify the same tag name with different values, the
+	// AzureMachine's value takes precedence.
+	// +optional
+	AdditionalTags infrav1.Tags `json:"additionalTags,omitempty"`
+
+	// AdditionalCapabilities specifies additional capabilities enabled or disabled on the virtual machine.
+	// +optional
+	AdditionalCapabilities *infrav1.AdditionalCapabilities `json:"additionalCapabilities,omitempty"`
+
+	// AllocatePublicIP allows the ability to create dynamic public ips for machines where this value is true.
+	// +optional
+	AllocatePublicIP bool `json:"allocatePublicIP,omitempty"`
+
+	// EnableIPForwarding enables IP Forwarding in Azure which is required for some CNI's to send traffic from a pods on one machine
+	// to another. This is required for IpV6 with Calico in combination with User Defined Routes (set by the Azure Cloud Controller
+	// manager). Default is false for disabled.
+	// +optional
+	EnableIPForwarding bool `json:"enableIPForwarding,omitempty"`
+
+	// SpotVMOptions allows the ability to specify the Machine should use a Spot VM
+	// +optional
+	SpotVMOptions *infrav1.SpotVMOptions `json:"spotVMOptions,omitempty"`
+
+	// SecurityProfile specifies the Security profile settings for a virtual machine.
+	// +optional
+	SecurityProfile *infrav1.SecurityProfile `json:"securityProfile,omitempty"`
+
+	// NetworkInterfaces are the network interfaces of the virtual machine. The first interface is the primary one. If
+	// omitted, a single interface is created in the node or control plane subnet of the cluster.
+	// +optional
+	NetworkInterfaces []infrav1.AzureNetworkInterface `json:"networkInterfaces,omitempty"`
+
+	// UserData is passed to the virtual machine through its userData property, separately from the bootstrap data
+	// passed as customData. It can be read from the Azure Instance Metadata Service and is not part of the bootstrap secret.
+	// +optional
+	UserData *infrav1.UserData `json:"userData,omitempty"`
+
+	// DesiredPowerState is the power state the virtual machine should be kept in. When set to Running, a virtual
+	// machine found stopped, deallocated or hibernated, for example from the Azure portal, is started again. When set
+	// to Hibernated, the virtual machine is hibernated: it is deallocated with its memory state saved to its OS disk,
+	// and resumes with it when set back to Running. Hibernated requires additionalCapabilities.hibernationEnabled.
+	// +kubebuilder:validation:Enum=Running;Hibernated
+	// +optional
+	DesiredPowerState infrav1.PowerState `json:"desiredPowerState,omitempty"`
+
+	// MultiInstanceGPU partitions the GPUs of the virtual machine into GPU instances every time it boots, through a
+	// systemd unit written by cloud-init merged with the bootstrap data. It requires a Linux VM size with MIG capable
+	// GPUs and the NVIDIA driver in the image.
+	// +optional
+	MultiInstanceGPU *infrav1.MultiInstanceGPU `json:"multiInstanceGPU,omitempty"`
+
+	// LocalStorage formats and mounts the local temp or NVMe disks of the virtual machine before it is bootstrapped,
+	// through cloud-init merged with the bootstrap data. It is only supported on Linux machines bootstrapped with cloud-init.
+	// +optional
+	LocalStorage *infrav1.LocalStorage `json:"localStorage,omitempty"`
+
+	// FileStorage installs the packages and loads the kernel modules the Azure Files and Azure Blob storage CSI drivers
+	// need to mount volumes, through cloud-init merged with the bootstrap data. It is only supported on Linux machines
+	// bootstrapped with cloud-init.
+	// +optional
+	FileStorage *infrav1.FileStorage `json:"fileStorage,omitempty"`
+
+	// CACertificates are additional CA certificates added to the trust store of the virtual machine before it is
+	// bootstrapped, through cloud-init merged with the bootstrap data, e.g. for TLS-intercepting proxies or private
+	// registries. It is only supported on Linux machines bootstrapped with cloud-init.
+	// +optional
+	CACertificates *infrav1.CACertificates `json:"caCertificates,omitempty"`
+
+	// DiskSnapshots takes snapshots of the disks of the virtual machine on a schedule. A machine can be recovered from a
+	// snapshot of the OS disk of another one with osDisk.sourceSnapshotID.
+	// +optional
+	DiskSnapshots *infrav1.DiskSnapshots `json:"diskSnapshots,omitempty"`
+
+	// BackupProtection enrolls the virtual machine in a backup policy of a Recovery Services vault, e.g. to take
+	// VM-level backups of the etcd members of the control plane.
+	// +optional
+	BackupProtection *infrav1.BackupProtection `json:"backupProtection,omitempty"`
+
+	// EtcdDataDisk creates a data disk dedicated to etcd, named <machineName>_etcddisk, on a control plane machine. The
+	// disk is added to the data disks of the machine, and its size and storage type are validated against the IOPS
+	// etcd needs.
+	// +optional
+	EtcdDataDisk *infrav1.EtcdDataDisk `json:"etcdDataDisk,omitempty"`
+
+	// BackendPoolDrainTimeout is how long the network interfaces of the machine are kept out of the load balancer
+	// backend pools before the virtual machine is deleted, so that connections through the API server or node load
+	// balancers can drain rather than being reset, e.g. "30s". If omitted, the machine is deleted without draining.
+	// +optional
+	BackendPoolDrainTimeout *metav1.Duration `json:"backendPoolDrainTimeout,omitempty"`
+
+	// TombstonePolicy deletes the virtual machine in two phases: it is first tagged as tombstoned, and optionally
+	// deallocated, and only deleted once a grace period has passed, giving operators a window to recover from an
+	// accidental scale-down or deletion. If omitted, the virtual machine is deleted right away.
+	// +optional
+	TombstonePolicy *infrav1.TombstonePolicy `json:"tombstonePolicy,omitempty"`
+
+	// DerivedResources overrides the names and tags of the resources created along with the virtual machine: its
+	// network interfaces, its OS disk and its public IP, e.g. to tag its disks for chargeback differently than the
+	// virtual machine. By default, they are named after the machine and tagged with the additional tags of the machine.
+	// +optional
+	DerivedResources *infrav1.DerivedResources `json:"derivedResources,omitempty"`
+}
+
+// AzureMachineStatus defines the observed state of AzureMachine.
+type AzureMachineStatus struct {
+	// Ready is true when the provider resource is ready.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Addresses contains the Azure instance associated addresses.
+	// +optional
+	Addresses []corev1.NodeAddress `json:"addresses,omitempty"`
+
+	// VMState is the provisioning state of the Azure virtual machine.
+	// +optional
+	VMState *infrav1.ProvisioningState `json:"vmState,omitempty"`
+
+	// PowerState is the power state of the Azure virtual machine, as reported by its instance view.
+	// +optional
+	PowerState infrav1.PowerState `json:"powerState,omitempty"`
+
+	// Placement is where Azure placed the virtual machine: its fault and update domains, as reported by its instance
+	// view, its availability zone, dedicated host and proximity placement group.
+	// +optional
+	Placement *infrav1.VMPlacement `json:"placement,omitempty"`
+
+	// Image is the default reference image resolved for this machine when spec.image is not set.
+	// It is resolved from the Kubernetes version and OS of the machine only once, and reused afterwards.
+	// +optional
+	Image *Image `json:"image,omitempty"`
+
+	// LastDiskSnapshotTime is the time the last snapshots of the disks of the virtual machine were taken.
+	// +optional
+	LastDiskSnapshotTime *metav1.Time `json:"lastDiskSnapshotTime,omitempty"`
+
+	// LastDiskSnapshotRequest is the value of the DiskSnapshotRequestAnnotation the last requested snapshots were taken for.
+	// +optional
+	LastDiskSnapshotRequest string `json:"lastDiskSnapshotRequest,omitempty"`
+
+	// LastARMTemplateExportRequest is the value of the MachineARMTemplateExportRequestAnnotation the last ARM template
+	// was exported for.
+	// +optional
+	LastARMTemplateExportRequest string `json:"lastARMTemplateExportRequest,omitempty"`
+
+	// ErrorReason will be set in the event that there is a terminal problem
+	// reconciling the Machine and will contain a succinct value suitable
+	// for machine interpretation.
+	//
+	// This field should not be set for transitive errors that a controller
+	// faces that are expected to be fixed automatically over
+	// time (like service outages), but instead indicate that something is
+	// fundamentally wrong with the Machine's spec or the configuration of
+	// the controller, and that manual intervention is required. Examples
+	// of terminal errors would be invalid combinations of settings in the
+	// spec, values that are unsupported by the controller, or the
+	// responsible controller itself being critically misconfigured.
+	//
+	// Any transient errors that occur during the reconciliation of Machines
+	// can be added as events to the Machine object and/or logged in the
+	// controller's output.
+	// +optional
+	FailureReason *errors.MachineStatusError `json:"failureReason,omitempty"`
+
+	// ErrorMessage will be set in the event that there is a terminal problem
+	// reconciling the Machine and will contain a more verbose string suitable
+	// for logging and human consumption.
+	//
+	// This field should not be set for transitive errors that a controller
+	// faces that are expected to be fixed automatically over
+	// time (like service outages), but instead indicate that something is
+	// fundamentally wrong with the Machine's spec or the configuration of
+	// the controller, and that manual intervention is required. Examples
+	// of terminal errors would be invalid combinations of settings in the
+	// spec, values that are unsupported by the controller, or the
+	// responsible controller itself being critically misconfigured.
+	//
+	// Any transient errors that occur during the reconciliation of Machines
+	// can be added as events to the Machine object and/or logged in the
+	// controller's output.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Conditions defines current service state of the AzureMachine.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// LongRunningOperationStates saves the states for Azure long-running operations so they can be continued on the
+	// next reconciliation loop.
+	// +optional
+	LongRunningOperationStates infrav1.Futures `json:"longRunningOperationStates,omitempty"`
+
+	// Resources records the Azure resources created or updated for this AzureMachine, one entry per ARM ID.
+	// +optional
+	Resources infrav1.ResourceStatuses `json:"resources,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].reason"
+// +kubebuilder:printcolumn:name="Message",type="string",priority=1,JSONPath=".status.conditions[?(@.type=='Ready')].message"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.vmState",description="Azure VM provisioning state"
+// +kubebuilder:printcolumn:name="Cluster",type="string",priority=1,JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this AzureMachine belongs"
+// +kubebuilder:printcolumn:name="Machine",type="string",priority=1,JSONPath=".metadata.ownerReferences[?(@.kind==\"Machine\")].name",description="Machine object to which this AzureMachine belongs"
+// +kubebuilder:printcolumn:name="VM ID",type="string",priority=1,JSONPath=".spec.providerID",description="Azure VM ID"
+// +kubebuilder:printcolumn:name="VM Size",type="string",priority=1,JSONPath=".spec.vmSize",description="Azure VM Size"
+// +kubebuilder:resource:path=azuremachines,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+
+// AzureMachine is the Schema for the azuremachines API.
+type AzureMachine struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AzureMachineSpec   `json:"spec,omitempty"`
+	Status AzureMachineStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AzureMachineList contains a list of AzureMachine.
+type AzureMachineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AzureMachine `json:"items"`
+}
+
+// GetConditions returns the list of conditions for an AzureMachine API object.
+func (m *AzureMachine) GetConditions() clusterv1.Conditions {
+	return m.Status.Conditions
+}
+
+// SetConditions will set the given conditions on an AzureMachine object.
+func (m *AzureMachine) SetConditions(conditions clusterv1.Conditions) {
+	m.Status.Conditions = conditions
+}
+
+// GetFutures returns the list of long running operation states for an AzureMachine API object.
+func (m *AzureMachine) GetFutures() infrav1.Futures {
+	return m.Status.LongRunningOperationStates
+}
+
+// SetFutures will set the given long running operation states on an AzureMachine object.
+func (m *AzureMachine) SetFutures(futures infrav1.Futures) {
+	m.Status.LongRunningOperationStates = futures
+}
+
+func init() {
+	SchemeBuilder.Register(&AzureMachine{}, &AzureMachineList{})
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// Convert_v1beta1_AzureMachineSpec_To_v1beta2_AzureMachineSpec converts the machine SubnetName and
+// AcceleratedNetworking of v1beta1 to a single network interface.
+func Convert_v1beta1_AzureMachineSpec_To_v1beta2_AzureMachineSpec(in *infrav1.AzureMachineSpec, out *AzureMachineSpec, s apiconversion.Scope) error { // nolint
+	if err := autoConvert_v1beta1_AzureMachineSpec_To_v1beta2_AzureMachineSpec(in, out, s); err != nil {
+		return err
+	}
+
+	if len(out.NetworkInterfaces) == 0 && (in.SubnetName != "" || in.AcceleratedNetworking != nil) {
+		out.NetworkInterfaces = []infrav1.AzureNetworkInterface{
+			{
+				SubnetName:            in.SubnetName,
+				AcceleratedNetworking: in.AcceleratedNetworking,
+			},
+		}
+	}
+
+	return nil
+}
+
+// Convert_v1beta1_Image_To_v1beta2_Image converts a v1beta1 SharedGallery image to a private Azure Compute Gallery image.
+func Convert_v1beta1_Image_To_v1beta2_Image(in *infrav1.Image, out *Image, s apiconversion.Scope) error { // nolint
+	if err := autoConvert_v1beta1_Image_To_v1beta2_Image(in, out, s); err != nil {
+		return err
+	}
+
+	if out.ComputeGallery == nil && in.SharedGallery != nil {
+		out.ComputeGallery = sharedGalleryToComputeGallery(in.SharedGallery)
+	}
+
+	return nil
+}
+
+// sharedGalleryToComputeGallery returns the Azure Compute Gallery image referencing the same image version as a
+// Shared Image Gallery image.
+func sharedGalleryToComputeGallery(sharedGallery *infrav1.AzureSharedGalleryImage) *infrav1.AzureComputeGalleryImage {
+	subscriptionID, resourceGroup := sharedGallery.SubscriptionID, sharedGallery.ResourceGroup
+	computeGallery := &infrav1.AzureComputeGalleryImage{
+		Gallery:        sharedGallery.Gallery,
+		Name:           sharedGallery.Name,
+		Version:        sharedGallery.Version,
+		SubscriptionID: &subscriptionID,
+		ResourceGroup:  &resourceGroup,
+	}
+	if sharedGallery.Publisher != nil && sharedGallery.Offer != nil && sharedGallery.SKU != nil {
+		computeGallery.Plan = &infrav1.ImagePlan{
+			Publisher: *sharedGallery.Publisher,
+			Offer:     *sharedGallery.Offer,
+			SKU:       *sharedGallery.SKU,
+		}
+	}
+	return computeGallery
+}
+
+// restoreAzureMachineSpec restores the v1beta1 representation of the fields restructured in v1beta2 from the
+// hub it was converted from, as long as they weren't changed in v1beta2 since.
+func restoreAzureMachineSpec(src *AzureMachineSpec, dst, restored *infrav1.AzureMachineSpec) {
+	converted := &AzureMachineSpec{}
+	if err := Convert_v1beta1_AzureMachineSpec_To_v1beta2_AzureMachineSpec(restored, converted, nil); err != nil {
+		return
+	}
+
+	if apiequality.Semantic.DeepEqual(src.NetworkInterfaces, converted.NetworkInterfaces) {
+		dst.SubnetName = restored.SubnetName
+		dst.AcceleratedNetworking = restored.AcceleratedNetworking
+		dst.NetworkInterfaces = restored.NetworkInterfaces
+	}
+
+	restoreImage(src.Image, dst.Image, restored.Image)
+}
+
+// restoreImage restores the v1beta1 SharedGallery of an image, as long as the image wasn't changed in v1beta2 since.
+func restoreImage(src *Image, dst, restored *infrav1.Image) {
+	if src == nil || dst == nil || restored == nil {
+		return
+	}
+
+	converted := &Image{}
+	if err := Convert_v1beta1_Image_To_v1beta2_Image(restored, converted, nil); err != nil {
+		return
+	}
+
+	if apiequality.Semantic.DeepEqual(src, converted) {
+		dst.SharedGallery = restored.SharedGallery
+		dst.ComputeGallery = restored.ComputeGallery
+	}
+}
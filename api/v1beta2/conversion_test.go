@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestFuzzyConversion(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+
+	t.Run("for AzureMachine", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme: scheme,
+		Hub:    &infrav1.AzureMachine{},
+		Spoke:  &AzureMachine{},
+	}))
+
+	t.Run("for AzureMachineTemplate", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme: scheme,
+		Hub:    &infrav1.AzureMachineTemplate{},
+		Spoke:  &AzureMachineTemplate{},
+	}))
+}
+
+func TestConvertFrom(t *testing.T) {
+	tests := []struct {
+		name string
+		hub  infrav1.AzureMachineSpec
+		want AzureMachineSpec
+	}{
+		{
+			name: "machine subnet becomes the only network interface",
+			hub: infrav1.AzureMachineSpec{
+				SubnetName:            "node-subnet",
+				AcceleratedNetworking: pointer.BoolPtr(true),
+			},
+			want: AzureMachineSpec{
+				NetworkInterfaces: []infrav1.AzureNetworkInterface{
+					{SubnetName: "node-subnet", AcceleratedNetworking: pointer.BoolPtr(true)},
+				},
+			},
+		},
+		{
+			name: "network interfaces are kept",
+			hub: infrav1.AzureMachineSpec{
+				NetworkInterfaces: []infrav1.AzureNetworkInterface{{SubnetName: "subnet-1"}, {SubnetName: "subnet-2"}},
+			},
+			want: AzureMachineSpec{
+				NetworkInterfaces: []infrav1.AzureNetworkInterface{{SubnetName: "subnet-1"}, {SubnetName: "subnet-2"}},
+			},
+		},
+		{
+			name: "shared gallery image becomes a private compute gallery image",
+			hub: infrav1.AzureMachineSpec{
+				Image: &infrav1.Image{
+					SharedGallery: &infrav1.AzureSharedGalleryImage{
+						SubscriptionID: "00000000-0000-0000-0000-000000000000",
+						ResourceGroup:  "images",
+						Gallery:        "gallery",
+						Name:           "capi-ubuntu",
+						Version:        "1.0.0",
+						Publisher:      pointer.StringPtr("publisher"),
+						Offer:          pointer.StringPtr("offer"),
+						SKU:            pointer.StringPtr("sku"),
+					},
+				},
+			},
+			want: AzureMachineSpec{
+				Image: &Image{
+					ComputeGallery: &infrav1.AzureComputeGalleryImage{
+						SubscriptionID: pointer.StringPtr("00000000-0000-0000-0000-000000000000"),
+						ResourceGroup:  pointer.StringPtr("images"),
+						Gallery:        "gallery",
+						Name:           "capi-ubuntu",
+						Version:        "1.0.0",
+						Plan:           &infrav1.ImagePlan{Publisher: "publisher", Offer: "offer", SKU: "sku"},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			hub := &infrav1.AzureMachine{Spec: tc.hub}
+			spoke := &AzureMachine{}
+			g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			g.Expect(spoke.Spec).To(Equal(tc.want))
+
+			restored := &infrav1.AzureMachine{}
+			g.Expect(spoke.ConvertTo(restored)).To(Succeed())
+			g.Expect(restored.Spec).To(Equal(tc.hub))
+		})
+	}
+}
+
+func TestConvertToChangedNetworkInterfaces(t *testing.T) {
+	g := NewWithT(t)
+	hub := &infrav1.AzureMachine{Spec: infrav1.AzureMachineSpec{SubnetName: "node-subnet"}}
+	spoke := &AzureMachine{}
+	g.Expect(spoke.ConvertFrom(hub)).To(Succeed())
+
+	spoke.Spec.NetworkInterfaces = append(spoke.Spec.NetworkInterfaces, infrav1.AzureNetworkInterface{SubnetName: "storage-subnet"})
+
+	converted := &infrav1.AzureMachine{}
+	g.Expect(spoke.ConvertTo(converted)).To(Succeed())
+	g.Expect(converted.Spec.SubnetName).To(BeEmpty())
+	g.Expect(converted.Spec.NetworkInterfaces).To(Equal([]infrav1.AzureNetworkInterface{
+		{SubnetName: "node-subnet"},
+		{SubnetName: "storage-subnet"},
+	}))
+}
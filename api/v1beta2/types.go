@@ -0,0 +1,39 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// Image defines information about the image to use for VM creation.
+// There are three ways to specify an image: by ID, Marketplace Image or Azure Compute Gallery image.
+// One of ID, Marketplace or ComputeGallery should be set.
+type Image struct {
+	// ID specifies an image to use by ID
+	// +optional
+	ID *string `json:"id,omitempty"`
+
+	// Marketplace specifies an image to use from the Azure Marketplace
+	// +optional
+	Marketplace *infrav1.AzureMarketplaceImage `json:"marketplace,omitempty"`
+
+	// ComputeGallery specifies an image to use from the Azure Compute Gallery. Images of a private gallery are
+	// referenced with its subscriptionID and resourceGroup, images of a community gallery without them.
+	// +optional
+	ComputeGallery *infrav1.AzureComputeGalleryImage `json:"computeGallery,omitempty"`
+}
@@ -0,0 +1,501 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by conversion-gen-v0.23.1. DO NOT EDIT.
+
+package v1beta2
+
+import (
+	unsafe "unsafe"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	conversion "k8s.io/apimachinery/pkg/conversion"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	errors "sigs.k8s.io/cluster-api/errors"
+)
+
+func init() {
+	localSchemeBuilder.Register(RegisterConversions)
+}
+
+// RegisterConversions adds conversion functions to the given scheme.
+// Public to allow building arbitrary schemes.
+func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*AzureMachine)(nil), (*v1beta1.AzureMachine)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AzureMachine_To_v1beta1_AzureMachine(a.(*AzureMachine), b.(*v1beta1.AzureMachine), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.AzureMachine)(nil), (*AzureMachine)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AzureMachine_To_v1beta2_AzureMachine(a.(*v1beta1.AzureMachine), b.(*AzureMachine), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AzureMachineList)(nil), (*v1beta1.AzureMachineList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AzureMachineList_To_v1beta1_AzureMachineList(a.(*AzureMachineList), b.(*v1beta1.AzureMachineList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.AzureMachineList)(nil), (*AzureMachineList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AzureMachineList_To_v1beta2_AzureMachineList(a.(*v1beta1.AzureMachineList), b.(*AzureMachineList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AzureMachineSpec)(nil), (*v1beta1.AzureMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AzureMachineSpec_To_v1beta1_AzureMachineSpec(a.(*AzureMachineSpec), b.(*v1beta1.AzureMachineSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AzureMachineStatus)(nil), (*v1beta1.AzureMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AzureMachineStatus_To_v1beta1_AzureMachineStatus(a.(*AzureMachineStatus), b.(*v1beta1.AzureMachineStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.AzureMachineStatus)(nil), (*AzureMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AzureMachineStatus_To_v1beta2_AzureMachineStatus(a.(*v1beta1.AzureMachineStatus), b.(*AzureMachineStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AzureMachineTemplate)(nil), (*v1beta1.AzureMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AzureMachineTemplate_To_v1beta1_AzureMachineTemplate(a.(*AzureMachineTemplate), b.(*v1beta1.AzureMachineTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.AzureMachineTemplate)(nil), (*AzureMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AzureMachineTemplate_To_v1beta2_AzureMachineTemplate(a.(*v1beta1.AzureMachineTemplate), b.(*AzureMachineTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AzureMachineTemplateList)(nil), (*v1beta1.AzureMachineTemplateList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AzureMachineTemplateList_To_v1beta1_AzureMachineTemplateList(a.(*AzureMachineTemplateList), b.(*v1beta1.AzureMachineTemplateList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.AzureMachineTemplateList)(nil), (*AzureMachineTemplateList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AzureMachineTemplateList_To_v1beta2_AzureMachineTemplateList(a.(*v1beta1.AzureMachineTemplateList), b.(*AzureMachineTemplateList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AzureMachineTemplateResource)(nil), (*v1beta1.AzureMachineTemplateResource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AzureMachineTemplateResource_To_v1beta1_AzureMachineTemplateResource(a.(*AzureMachineTemplateResource), b.(*v1beta1.AzureMachineTemplateResource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.AzureMachineTemplateResource)(nil), (*AzureMachineTemplateResource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AzureMachineTemplateResource_To_v1beta2_AzureMachineTemplateResource(a.(*v1beta1.AzureMachineTemplateResource), b.(*AzureMachineTemplateResource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AzureMachineTemplateSpec)(nil), (*v1beta1.AzureMachineTemplateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_AzureMachineTemplateSpec_To_v1beta1_AzureMachineTemplateSpec(a.(*AzureMachineTemplateSpec), b.(*v1beta1.AzureMachineTemplateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.AzureMachineTemplateSpec)(nil), (*AzureMachineTemplateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AzureMachineTemplateSpec_To_v1beta2_AzureMachineTemplateSpec(a.(*v1beta1.AzureMachineTemplateSpec), b.(*AzureMachineTemplateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*Image)(nil), (*v1beta1.Image)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_Image_To_v1beta1_Image(a.(*Image), b.(*v1beta1.Image), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.AzureMachineSpec)(nil), (*AzureMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_AzureMachineSpec_To_v1beta2_AzureMachineSpec(a.(*v1beta1.AzureMachineSpec), b.(*AzureMachineSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.Image)(nil), (*Image)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Image_To_v1beta2_Image(a.(*v1beta1.Image), b.(*Image), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1beta2_AzureMachine_To_v1beta1_AzureMachine(in *AzureMachine, out *v1beta1.AzureMachine, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta2_AzureMachineSpec_To_v1beta1_AzureMachineSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta2_AzureMachineStatus_To_v1beta1_AzureMachineStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_AzureMachine_To_v1beta1_AzureMachine is an autogenerated conversion function.
+func Convert_v1beta2_AzureMachine_To_v1beta1_AzureMachine(in *AzureMachine, out *v1beta1.AzureMachine, s conversion.Scope) error {
+	return autoConvert_v1beta2_AzureMachine_To_v1beta1_AzureMachine(in, out, s)
+}
+
+func autoConvert_v1beta1_AzureMachine_To_v1beta2_AzureMachine(in *v1beta1.AzureMachine, out *AzureMachine, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_AzureMachineSpec_To_v1beta2_AzureMachineSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_AzureMachineStatus_To_v1beta2_AzureMachineStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_AzureMachine_To_v1beta2_AzureMachine is an autogenerated conversion function.
+func Convert_v1beta1_AzureMachine_To_v1beta2_AzureMachine(in *v1beta1.AzureMachine, out *AzureMachine, s conversion.Scope) error {
+	return autoConvert_v1beta1_AzureMachine_To_v1beta2_AzureMachine(in, out, s)
+}
+
+func autoConvert_v1beta2_AzureMachineList_To_v1beta1_AzureMachineList(in *AzureMachineList, out *v1beta1.AzureMachineList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1beta1.AzureMachine, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta2_AzureMachine_To_v1beta1_AzureMachine(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1beta2_AzureMachineList_To_v1beta1_AzureMachineList is an autogenerated conversion function.
+func Convert_v1beta2_AzureMachineList_To_v1beta1_AzureMachineList(in *AzureMachineList, out *v1beta1.AzureMachineList, s conversion.Scope) error {
+	return autoConvert_v1beta2_AzureMachineList_To_v1beta1_AzureMachineList(in, out, s)
+}
+
+func autoConvert_v1beta1_AzureMachineList_To_v1beta2_AzureMachineList(in *v1beta1.AzureMachineList, out *AzureMachineList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AzureMachine, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_AzureMachine_To_v1beta2_AzureMachine(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1beta1_AzureMachineList_To_v1beta2_AzureMachineList is an autogenerated conversion function.
+func Convert_v1beta1_AzureMachineList_To_v1beta2_AzureMachineList(in *v1beta1.AzureMachineList, out *AzureMachineList, s conversion.Scope) error {
+	return autoConvert_v1beta1_AzureMachineList_To_v1beta2_AzureMachineList(in, out, s)
+}
+
+func autoConvert_v1beta2_AzureMachineSpec_To_v1beta1_AzureMachineSpec(in *AzureMachineSpec, out *v1beta1.AzureMachineSpec, s conversion.Scope) error {
+	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
+	out.VMSize = in.VMSize
+	out.FailureDomain = (*string)(unsafe.Pointer(in.FailureDomain))
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(v1beta1.Image)
+		if err := Convert_v1beta2_Image_To_v1beta1_Image(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Image = nil
+	}
+	out.Identity = v1beta1.VMIdentity(in.Identity)
+	out.UserAssignedIdentities = *(*[]v1beta1.UserAssignedIdentity)(unsafe.Pointer(&in.UserAssignedIdentities))
+	out.RoleAssignmentName = in.RoleAssignmentName
+	out.OSDisk = in.OSDisk
+	out.DataDisks = *(*[]v1beta1.DataDisk)(unsafe.Pointer(&in.DataDisks))
+	out.DiskControllerType = v1beta1.DiskControllerType(in.DiskControllerType)
+	out.SSHPublicKey = in.SSHPublicKey
+	out.AdditionalSSHPublicKeys = *(*[]string)(unsafe.Pointer(&in.AdditionalSSHPublicKeys))
+	out.AdditionalTags = *(*v1beta1.Tags)(unsafe.Pointer(&in.AdditionalTags))
+	out.AdditionalCapabilities = (*v1beta1.AdditionalCapabilities)(unsafe.Pointer(in.AdditionalCapabilities))
+	out.AllocatePublicIP = in.AllocatePublicIP
+	out.EnableIPForwarding = in.EnableIPForwarding
+	out.SpotVMOptions = (*v1beta1.SpotVMOptions)(unsafe.Pointer(in.SpotVMOptions))
+	out.SecurityProfile = (*v1beta1.SecurityProfile)(unsafe.Pointer(in.SecurityProfile))
+	out.NetworkInterfaces = *(*[]v1beta1.AzureNetworkInterface)(unsafe.Pointer(&in.NetworkInterfaces))
+	out.UserData = (*v1beta1.UserData)(unsafe.Pointer(in.UserData))
+	out.DesiredPowerState = v1beta1.PowerState(in.DesiredPowerState)
+	out.MultiInstanceGPU = (*v1beta1.MultiInstanceGPU)(unsafe.Pointer(in.MultiInstanceGPU))
+	out.LocalStorage = (*v1beta1.LocalStorage)(unsafe.Pointer(in.LocalStorage))
+	out.FileStorage = (*v1beta1.FileStorage)(unsafe.Pointer(in.FileStorage))
+	out.CACertificates = (*v1beta1.CACertificates)(unsafe.Pointer(in.CACertificates))
+	out.DiskSnapshots = (*v1beta1.DiskSnapshots)(unsafe.Pointer(in.DiskSnapshots))
+	out.BackupProtection = (*v1beta1.BackupProtection)(unsafe.Pointer(in.BackupProtection))
+	out.EtcdDataDisk = (*v1beta1.EtcdDataDisk)(unsafe.Pointer(in.EtcdDataDisk))
+	out.BackendPoolDrainTimeout = (*v1.Duration)(unsafe.Pointer(in.BackendPoolDrainTimeout))
+	out.TombstonePolicy = (*v1beta1.TombstonePolicy)(unsafe.Pointer(in.TombstonePolicy))
+	out.DerivedResources = (*v1beta1.DerivedResources)(unsafe.Pointer(in.DerivedResources))
+	return nil
+}
+
+// Convert_v1beta2_AzureMachineSpec_To_v1beta1_AzureMachineSpec is an autogenerated conversion function.
+func Convert_v1beta2_AzureMachineSpec_To_v1beta1_AzureMachineSpec(in *AzureMachineSpec, out *v1beta1.AzureMachineSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_AzureMachineSpec_To_v1beta1_AzureMachineSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_AzureMachineSpec_To_v1beta2_AzureMachineSpec(in *v1beta1.AzureMachineSpec, out *AzureMachineSpec, s conversion.Scope) error {
+	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
+	out.VMSize = in.VMSize
+	out.FailureDomain = (*string)(unsafe.Pointer(in.FailureDomain))
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(Image)
+		if err := Convert_v1beta1_Image_To_v1beta2_Image(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Image = nil
+	}
+	out.Identity = v1beta1.VMIdentity(in.Identity)
+	out.UserAssignedIdentities = *(*[]v1beta1.UserAssignedIdentity)(unsafe.Pointer(&in.UserAssignedIdentities))
+	out.RoleAssignmentName = in.RoleAssignmentName
+	out.OSDisk = in.OSDisk
+	out.DataDisks = *(*[]v1beta1.DataDisk)(unsafe.Pointer(&in.DataDisks))
+	out.DiskControllerType = v1beta1.DiskControllerType(in.DiskControllerType)
+	out.SSHPublicKey = in.SSHPublicKey
+	out.AdditionalSSHPublicKeys = *(*[]string)(unsafe.Pointer(&in.AdditionalSSHPublicKeys))
+	out.AdditionalTags = *(*v1beta1.Tags)(unsafe.Pointer(&in.AdditionalTags))
+	out.AdditionalCapabilities = (*v1beta1.AdditionalCapabilities)(unsafe.Pointer(in.AdditionalCapabilities))
+	out.AllocatePublicIP = in.AllocatePublicIP
+	out.EnableIPForwarding = in.EnableIPForwarding
+	// WARNING: in.AcceleratedNetworking requires manual conversion: does not exist in peer-type
+	out.SpotVMOptions = (*v1beta1.SpotVMOptions)(unsafe.Pointer(in.SpotVMOptions))
+	out.SecurityProfile = (*v1beta1.SecurityProfile)(unsafe.Pointer(in.SecurityProfile))
+	// WARNING: in.SubnetName requires manual conversion: does not exist in peer-type
+	out.NetworkInterfaces = *(*[]v1beta1.AzureNetworkInterface)(unsafe.Pointer(&in.NetworkInterfaces))
+	out.UserData = (*v1beta1.UserData)(unsafe.Pointer(in.UserData))
+	out.DesiredPowerState = v1beta1.PowerState(in.DesiredPowerState)
+	out.MultiInstanceGPU = (*v1beta1.MultiInstanceGPU)(unsafe.Pointer(in.MultiInstanceGPU))
+	out.LocalStorage = (*v1beta1.LocalStorage)(unsafe.Pointer(in.LocalStorage))
+	out.FileStorage = (*v1beta1.FileStorage)(unsafe.Pointer(in.FileStorage))
+	out.CACertificates = (*v1beta1.CACertificates)(unsafe.Pointer(in.CACertificates))
+	out.DiskSnapshots = (*v1beta1.DiskSnapshots)(unsafe.Pointer(in.DiskSnapshots))
+	out.BackupProtection = (*v1beta1.BackupProtection)(unsafe.Pointer(in.BackupProtection))
+	out.EtcdDataDisk = (*v1beta1.EtcdDataDisk)(unsafe.Pointer(in.EtcdDataDisk))
+	out.BackendPoolDrainTimeout = (*v1.Duration)(unsafe.Pointer(in.BackendPoolDrainTimeout))
+	out.TombstonePolicy = (*v1beta1.TombstonePolicy)(unsafe.Pointer(in.TombstonePolicy))
+	out.DerivedResources = (*v1beta1.DerivedResources)(unsafe.Pointer(in.DerivedResources))
+	return nil
+}
+
+func autoConvert_v1beta2_AzureMachineStatus_To_v1beta1_AzureMachineStatus(in *AzureMachineStatus, out *v1beta1.AzureMachineStatus, s conversion.Scope) error {
+	out.Ready = in.Ready
+	out.Addresses = *(*[]corev1.NodeAddress)(unsafe.Pointer(&in.Addresses))
+	out.VMState = (*v1beta1.ProvisioningState)(unsafe.Pointer(in.VMState))
+	out.PowerState = v1beta1.PowerState(in.PowerState)
+	out.Placement = (*v1beta1.VMPlacement)(unsafe.Pointer(in.Placement))
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(v1beta1.Image)
+		if err := Convert_v1beta2_Image_To_v1beta1_Image(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Image = nil
+	}
+	out.LastDiskSnapshotTime = (*v1.Time)(unsafe.Pointer(in.LastDiskSnapshotTime))
+	out.LastDiskSnapshotRequest = in.LastDiskSnapshotRequest
+	out.LastARMTemplateExportRequest = in.LastARMTemplateExportRequest
+	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
+	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	out.Conditions = *(*apiv1beta1.Conditions)(unsafe.Pointer(&in.Conditions))
+	out.LongRunningOperationStates = *(*v1beta1.Futures)(unsafe.Pointer(&in.LongRunningOperationStates))
+	out.Resources = *(*v1beta1.ResourceStatuses)(unsafe.Pointer(&in.Resources))
+	return nil
+}
+
+// Convert_v1beta2_AzureMachineStatus_To_v1beta1_AzureMachineStatus is an autogenerated conversion function.
+func Convert_v1beta2_AzureMachineStatus_To_v1beta1_AzureMachineStatus(in *AzureMachineStatus, out *v1beta1.AzureMachineStatus, s conversion.Scope) error {
+	return autoConvert_v1beta2_AzureMachineStatus_To_v1beta1_AzureMachineStatus(in, out, s)
+}
+
+func autoConvert_v1beta1_AzureMachineStatus_To_v1beta2_AzureMachineStatus(in *v1beta1.AzureMachineStatus, out *AzureMachineStatus, s conversion.Scope) error {
+	out.Ready = in.Ready
+	out.Addresses = *(*[]corev1.NodeAddress)(unsafe.Pointer(&in.Addresses))
+	out.VMState = (*v1beta1.ProvisioningState)(unsafe.Pointer(in.VMState))
+	out.PowerState = v1beta1.PowerState(in.PowerState)
+	out.Placement = (*v1beta1.VMPlacement)(unsafe.Pointer(in.Placement))
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(Image)
+		if err := Convert_v1beta1_Image_To_v1beta2_Image(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Image = nil
+	}
+	out.LastDiskSnapshotTime = (*v1.Time)(unsafe.Pointer(in.LastDiskSnapshotTime))
+	out.LastDiskSnapshotRequest = in.LastDiskSnapshotRequest
+	out.LastARMTemplateExportRequest = in.LastARMTemplateExportRequest
+	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
+	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	out.Conditions = *(*apiv1beta1.Conditions)(unsafe.Pointer(&in.Conditions))
+	out.LongRunningOperationStates = *(*v1beta1.Futures)(unsafe.Pointer(&in.LongRunningOperationStates))
+	out.Resources = *(*v1beta1.ResourceStatuses)(unsafe.Pointer(&in.Resources))
+	return nil
+}
+
+// Convert_v1beta1_AzureMachineStatus_To_v1beta2_AzureMachineStatus is an autogenerated conversion function.
+func Convert_v1beta1_AzureMachineStatus_To_v1beta2_AzureMachineStatus(in *v1beta1.AzureMachineStatus, out *AzureMachineStatus, s conversion.Scope) error {
+	return autoConvert_v1beta1_AzureMachineStatus_To_v1beta2_AzureMachineStatus(in, out, s)
+}
+
+func autoConvert_v1beta2_AzureMachineTemplate_To_v1beta1_AzureMachineTemplate(in *AzureMachineTemplate, out *v1beta1.AzureMachineTemplate, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta2_AzureMachineTemplateSpec_To_v1beta1_AzureMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_AzureMachineTemplate_To_v1beta1_AzureMachineTemplate is an autogenerated conversion function.
+func Convert_v1beta2_AzureMachineTemplate_To_v1beta1_AzureMachineTemplate(in *AzureMachineTemplate, out *v1beta1.AzureMachineTemplate, s conversion.Scope) error {
+	return autoConvert_v1beta2_AzureMachineTemplate_To_v1beta1_AzureMachineTemplate(in, out, s)
+}
+
+func autoConvert_v1beta1_AzureMachineTemplate_To_v1beta2_AzureMachineTemplate(in *v1beta1.AzureMachineTemplate, out *AzureMachineTemplate, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_AzureMachineTemplateSpec_To_v1beta2_AzureMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_AzureMachineTemplate_To_v1beta2_AzureMachineTemplate is an autogenerated conversion function.
+func Convert_v1beta1_AzureMachineTemplate_To_v1beta2_AzureMachineTemplate(in *v1beta1.AzureMachineTemplate, out *AzureMachineTemplate, s conversion.Scope) error {
+	return autoConvert_v1beta1_AzureMachineTemplate_To_v1beta2_AzureMachineTemplate(in, out, s)
+}
+
+func autoConvert_v1beta2_AzureMachineTemplateList_To_v1beta1_AzureMachineTemplateList(in *AzureMachineTemplateList, out *v1beta1.AzureMachineTemplateList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1beta1.AzureMachineTemplate, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta2_AzureMachineTemplate_To_v1beta1_AzureMachineTemplate(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1beta2_AzureMachineTemplateList_To_v1beta1_AzureMachineTemplateList is an autogenerated conversion function.
+func Convert_v1beta2_AzureMachineTemplateList_To_v1beta1_AzureMachineTemplateList(in *AzureMachineTemplateList, out *v1beta1.AzureMachineTemplateList, s conversion.Scope) error {
+	return autoConvert_v1beta2_AzureMachineTemplateList_To_v1beta1_AzureMachineTemplateList(in, out, s)
+}
+
+func autoConvert_v1beta1_AzureMachineTemplateList_To_v1beta2_AzureMachineTemplateList(in *v1beta1.AzureMachineTemplateList, out *AzureMachineTemplateList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AzureMachineTemplate, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_AzureMachineTemplate_To_v1beta2_AzureMachineTemplate(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1beta1_AzureMachineTemplateList_To_v1beta2_AzureMachineTemplateList is an autogenerated conversion function.
+func Convert_v1beta1_AzureMachineTemplateList_To_v1beta2_AzureMachineTemplateList(in *v1beta1.AzureMachineTemplateList, out *AzureMachineTemplateList, s conversion.Scope) error {
+	return autoConvert_v1beta1_AzureMachineTemplateList_To_v1beta2_AzureMachineTemplateList(in, out, s)
+}
+
+func autoConvert_v1beta2_AzureMachineTemplateResource_To_v1beta1_AzureMachineTemplateResource(in *AzureMachineTemplateResource, out *v1beta1.AzureMachineTemplateResource, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta2_AzureMachineSpec_To_v1beta1_AzureMachineSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_AzureMachineTemplateResource_To_v1beta1_AzureMachineTemplateResource is an autogenerated conversion function.
+func Convert_v1beta2_AzureMachineTemplateResource_To_v1beta1_AzureMachineTemplateResource(in *AzureMachineTemplateResource, out *v1beta1.AzureMachineTemplateResource, s conversion.Scope) error {
+	return autoConvert_v1beta2_AzureMachineTemplateResource_To_v1beta1_AzureMachineTemplateResource(in, out, s)
+}
+
+func autoConvert_v1beta1_AzureMachineTemplateResource_To_v1beta2_AzureMachineTemplateResource(in *v1beta1.AzureMachineTemplateResource, out *AzureMachineTemplateResource, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_AzureMachineSpec_To_v1beta2_AzureMachineSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_AzureMachineTemplateResource_To_v1beta2_AzureMachineTemplateResource is an autogenerated conversion function.
+func Convert_v1beta1_AzureMachineTemplateResource_To_v1beta2_AzureMachineTemplateResource(in *v1beta1.AzureMachineTemplateResource, out *AzureMachineTemplateResource, s conversion.Scope) error {
+	return autoConvert_v1beta1_AzureMachineTemplateResource_To_v1beta2_AzureMachineTemplateResource(in, out, s)
+}
+
+func autoConvert_v1beta2_AzureMachineTemplateSpec_To_v1beta1_AzureMachineTemplateSpec(in *AzureMachineTemplateSpec, out *v1beta1.AzureMachineTemplateSpec, s conversion.Scope) error {
+	if err := Convert_v1beta2_AzureMachineTemplateResource_To_v1beta1_AzureMachineTemplateResource(&in.Template, &out.Template, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_AzureMachineTemplateSpec_To_v1beta1_AzureMachineTemplateSpec is an autogenerated conversion function.
+func Convert_v1beta2_AzureMachineTemplateSpec_To_v1beta1_AzureMachineTemplateSpec(in *AzureMachineTemplateSpec, out *v1beta1.AzureMachineTemplateSpec, s conversion.Scope) error {
+	return autoConvert_v1beta2_AzureMachineTemplateSpec_To_v1beta1_AzureMachineTemplateSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_AzureMachineTemplateSpec_To_v1beta2_AzureMachineTemplateSpec(in *v1beta1.AzureMachineTemplateSpec, out *AzureMachineTemplateSpec, s conversion.Scope) error {
+	if err := Convert_v1beta1_AzureMachineTemplateResource_To_v1beta2_AzureMachineTemplateResource(&in.Template, &out.Template, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_AzureMachineTemplateSpec_To_v1beta2_AzureMachineTemplateSpec is an autogenerated conversion function.
+func Convert_v1beta1_AzureMachineTemplateSpec_To_v1beta2_AzureMachineTemplateSpec(in *v1beta1.AzureMachineTemplateSpec, out *AzureMachineTemplateSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_AzureMachineTemplateSpec_To_v1beta2_AzureMachineTemplateSpec(in, out, s)
+}
+
+func autoConvert_v1beta2_Image_To_v1beta1_Image(in *Image, out *v1beta1.Image, s conversion.Scope) error {
+	out.ID = (*string)(unsafe.Pointer(in.ID))
+	out.Marketplace = (*v1beta1.AzureMarketplaceImage)(unsafe.Pointer(in.Marketplace))
+	out.ComputeGallery = (*v1beta1.AzureComputeGalleryImage)(unsafe.Pointer(in.ComputeGallery))
+	return nil
+}
+
+// Convert_v1beta2_Image_To_v1beta1_Image is an autogenerated conversion function.
+func Convert_v1beta2_Image_To_v1beta1_Image(in *Image, out *v1beta1.Image, s conversion.Scope) error {
+	return autoConvert_v1beta2_Image_To_v1beta1_Image(in, out, s)
+}
+
+func autoConvert_v1beta1_Image_To_v1beta2_Image(in *v1beta1.Image, out *Image, s conversion.Scope) error {
+	out.ID = (*string)(unsafe.Pointer(in.ID))
+	// WARNING: in.SharedGallery requires manual conversion: does not exist in peer-type
+	out.Marketplace = (*v1beta1.AzureMarketplaceImage)(unsafe.Pointer(in.Marketplace))
+	out.ComputeGallery = (*v1beta1.AzureComputeGalleryImage)(unsafe.Pointer(in.ComputeGallery))
+	return nil
+}
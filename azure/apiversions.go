@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/pkg/errors"
+)
+
+// supportedAPIVersions are the API versions of the resource types that can be used instead of the version of the
+// Azure SDK package of their service. The first version of each resource type is the one of the SDK package, and the
+// others are newer versions whose request and response models are a superset of those of the SDK package, so that
+// they can be sent and decoded with its models.
+var supportedAPIVersions = map[string][]string{
+	"Microsoft.Compute/availabilitySets":                        {"2022-11-01"},
+	"Microsoft.Compute/disks":                                   {"2022-07-02"},
+	"Microsoft.Compute/snapshots":                               {"2022-07-02"},
+	"Microsoft.Compute/virtualMachines":                         {"2022-11-01"},
+	"Microsoft.Compute/virtualMachines/extensions":              {"2022-11-01"},
+	"Microsoft.Compute/virtualMachineScaleSets":                 {"2022-11-01"},
+	"Microsoft.Compute/virtualMachineScaleSets/virtualMachines": {"2022-11-01"},
+	"Microsoft.Network/loadBalancers":                           {"2023-05-01", "2023-06-01", "2023-09-01", "2023-11-01"},
+	"Microsoft.Network/natGateways":                             {"2023-05-01", "2023-06-01", "2023-09-01", "2023-11-01"},
+	"Microsoft.Network/networkInterfaces":                       {"2023-05-01", "2023-06-01", "2023-09-01", "2023-11-01"},
+	"Microsoft.Network/networkSecurityGroups":                   {"2023-05-01", "2023-06-01", "2023-09-01", "2023-11-01"},
+	"Microsoft.Network/publicIPAddresses":                       {"2023-05-01", "2023-06-01", "2023-09-01", "2023-11-01"},
+	"Microsoft.Network/routeTables":                             {"2023-05-01", "2023-06-01", "2023-09-01", "2023-11-01"},
+	"Microsoft.Network/virtualNetworks":                         {"2023-05-01", "2023-06-01", "2023-09-01", "2023-11-01"},
+	"Microsoft.Network/virtualNetworks/subnets":                 {"2023-05-01", "2023-06-01", "2023-09-01", "2023-11-01"},
+}
+
+// apiVersions holds the API versions used instead of the versions of the Azure SDK packages, by lowercase resource type.
+var apiVersions = struct {
+	sync.RWMutex
+	byType map[string]string
+}{byType: map[string]string{}}
+
+// SetAPIVersions sets the API versions used for the requests on the given resource types, e.g.
+// "Microsoft.Network/loadBalancers" to "2023-11-01", instead of the versions of the Azure SDK packages of their
+// services. It returns an error if a resource type or a version isn't supported.
+func SetAPIVersions(versions map[string]string) error {
+	byType := make(map[string]string, len(versions))
+	for resourceType, version := range versions {
+		supported, ok := supportedAPIVersion(resourceType, version)
+		if !ok {
+			return errors.Errorf("API version %q of resource type %q is not supported, supported versions are %s",
+				version, resourceType, strings.Join(SupportedAPIVersions(resourceType), ", "))
+		}
+		byType[strings.ToLower(resourceType)] = supported
+	}
+
+	apiVersions.Lock()
+	defer apiVersions.Unlock()
+	apiVersions.byType = byType
+	return nil
+}
+
+// SupportedAPIVersions returns the API versions that can be set for a resource type, or nil if the API version of the
+// resource type can't be changed.
+func SupportedAPIVersions(resourceType string) []string {
+	for t, versions := range supportedAPIVersions {
+		if strings.EqualFold(t, resourceType) {
+			return versions
+		}
+	}
+	return nil
+}
+
+// SupportedAPIVersionResourceTypes returns the resource types whose API versions can be set, sorted.
+func SupportedAPIVersionResourceTypes() []string {
+	types := make([]string, 0, len(supportedAPIVersions))
+	for t := range supportedAPIVersions {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func supportedAPIVersion(resourceType, version string) (string, bool) {
+	for _, v := range SupportedAPIVersions(resourceType) {
+		if v == version {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// apiVersionOverride returns the API version set for a resource type, if any.
+func apiVersionOverride(resourceType string) (string, bool) {
+	apiVersions.RLock()
+	defer apiVersions.RUnlock()
+	version, ok := apiVersions.byType[strings.ToLower(resourceType)]
+	return version, ok
+}
+
+// apiVersionPolicy replaces the api-version query parameter of the requests on resource types whose API version is set.
+type apiVersionPolicy struct{}
+
+// Do replaces the api-version query parameter of the request if it is on a resource type whose API version is set.
+func (apiVersionPolicy) Do(req *policy.Request) (*http.Response, error) {
+	r := req.Raw()
+	query := r.URL.Query()
+	if query.Get("api-version") != "" {
+		if version, ok := apiVersionOverride(resourceTypeFromPath(r.URL.Path)); ok {
+			query.Set("api-version", version)
+			r.URL.RawQuery = query.Encode()
+		}
+	}
+	return req.Next()
+}
+
+// resourceTypeFromPath returns the resource type of an ARM request path, e.g. "Microsoft.Compute/virtualMachines" for
+// a VM or a list of VMs, or "Microsoft.Compute/virtualMachines/extensions" for a VM extension. It returns an empty
+// string if the path isn't a resource provider path.
+func resourceTypeFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	// The last providers segment is the one of the resource type, e.g. for extension resources.
+	providers := -1
+	for i, segment := range segments {
+		if strings.EqualFold(segment, "providers") {
+			providers = i
+		}
+	}
+	if providers < 0 || providers+2 >= len(segments) {
+		return ""
+	}
+
+	types := []string{segments[providers+1]}
+	for i := providers + 2; i < len(segments); i += 2 {
+		types = append(types, segments[i])
+	}
+	return strings.Join(types, "/")
+}
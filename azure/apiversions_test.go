@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	. "github.com/onsi/gomega"
+)
+
+func TestResourceTypeFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{
+			path: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm",
+			want: "Microsoft.Compute/virtualMachines",
+		},
+		{
+			path: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines",
+			want: "Microsoft.Compute/virtualMachines",
+		},
+		{
+			path: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm/extensions/my-ext",
+			want: "Microsoft.Compute/virtualMachines/extensions",
+		},
+		{
+			path: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss/virtualMachines/0",
+			want: "Microsoft.Compute/virtualMachineScaleSets/virtualMachines",
+		},
+		{
+			path: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm/providers/Microsoft.Authorization/roleAssignments/my-role",
+			want: "Microsoft.Authorization/roleAssignments",
+		},
+		{
+			path: "/subscriptions/123/resourceGroups/my-rg",
+			want: "",
+		},
+		{
+			path: "/subscriptions/123/providers/Microsoft.Compute",
+			want: "",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.path, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(resourceTypeFromPath(tc.path)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestSetAPIVersions(t *testing.T) {
+	g := NewWithT(t)
+	defer func() { g.Expect(SetAPIVersions(nil)).To(Succeed()) }()
+
+	g.Expect(SetAPIVersions(map[string]string{"Microsoft.Network/virtualNetworks": "2023-09-01"})).To(Succeed())
+	version, ok := apiVersionOverride("microsoft.network/VIRTUALNETWORKS")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(version).To(Equal("2023-09-01"))
+
+	// Setting the versions replaces the versions previously set.
+	g.Expect(SetAPIVersions(map[string]string{"microsoft.network/loadbalancers": "2023-11-01"})).To(Succeed())
+	_, ok = apiVersionOverride("Microsoft.Network/virtualNetworks")
+	g.Expect(ok).To(BeFalse())
+	version, ok = apiVersionOverride("Microsoft.Network/loadBalancers")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(version).To(Equal("2023-11-01"))
+
+	err := SetAPIVersions(map[string]string{"Microsoft.Network/loadBalancers": "2019-07-01"})
+	g.Expect(err).To(MatchError(ContainSubstring("supported versions are 2023-05-01, 2023-06-01")))
+	g.Expect(SetAPIVersions(map[string]string{"Microsoft.Storage/storageAccounts": "2021-09-01"})).NotTo(Succeed())
+}
+
+// sendAPIVersion returns the api-version query parameter of a request on the given path, sent with the given API
+// version by an ARM client.
+func sendAPIVersion(path, version string) (string, error) {
+	var apiVersion string
+	testSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiVersion = r.URL.Query().Get("api-version")
+	}))
+	defer testSrv.Close()
+
+	opts, err := ARMClientOptions("")
+	if err != nil {
+		return "", err
+	}
+	pl := runtime.NewPipeline("test", "v0.0.0", runtime.PipelineOptions{}, &opts.ClientOptions)
+	req, err := runtime.NewRequest(context.TODO(), http.MethodGet, testSrv.URL+path+"?api-version="+version)
+	if err != nil {
+		return "", err
+	}
+	if _, err := pl.Do(req); err != nil {
+		return "", err
+	}
+	return apiVersion, nil
+}
+
+// TestAPIVersionOverrideMatrix checks that each supported API version of each resource type is sent instead of the
+// version of the Azure SDK package, and that the requests on the other resource types are left untouched.
+func TestAPIVersionOverrideMatrix(t *testing.T) {
+	for _, resourceType := range SupportedAPIVersionResourceTypes() {
+		versions := SupportedAPIVersions(resourceType)
+		for _, version := range versions {
+			resourceType, version := resourceType, version
+			t.Run(resourceType+"@"+version, func(t *testing.T) {
+				g := NewWithT(t)
+				g.Expect(SetAPIVersions(map[string]string{resourceType: version})).To(Succeed())
+				defer func() { g.Expect(SetAPIVersions(nil)).To(Succeed()) }()
+
+				path := "/subscriptions/123/resourceGroups/my-rg/providers"
+				parts := strings.Split(resourceType, "/")
+				path += "/" + parts[0]
+				for _, part := range parts[1:] {
+					path += "/" + part + "/my-resource"
+				}
+				g.Expect(sendAPIVersion(path, versions[0])).To(Equal(version))
+				g.Expect(sendAPIVersion("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Storage/storageAccounts/my-account", "2021-08-01")).To(Equal("2021-08-01"))
+			})
+		}
+	}
+}
+
+func TestARMClientAPIVersionOverride(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(SetAPIVersions(map[string]string{"Microsoft.Network/loadBalancers": "2023-11-01"})).To(Succeed())
+	defer func() { g.Expect(SetAPIVersions(nil)).To(Succeed()) }()
+
+	var apiVersion string
+	testSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiVersion = r.URL.Query().Get("api-version")
+	}))
+	defer testSrv.Close()
+
+	opts, err := ARMClientOptions("")
+	g.Expect(err).NotTo(HaveOccurred())
+	pl := runtime.NewPipeline("test", "v0.0.0", runtime.PipelineOptions{}, &opts.ClientOptions)
+	req, err := runtime.NewRequest(context.TODO(), http.MethodGet, testSrv.URL+"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-lb?api-version=2023-05-01")
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = pl.Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(apiVersion).To(Equal("2023-11-01"))
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package armtemplate renders the desired state of Azure resources into Azure Resource Manager deployment templates.
+package armtemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Schema is the schema of the deployment templates targeting a resource group.
+	Schema = "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#"
+	// ContentVersion is the version of the rendered templates.
+	ContentVersion = "1.0.0.0"
+
+	// ComputeAPIVersion is the API version of the Microsoft.Compute resources.
+	ComputeAPIVersion = "2022-11-01"
+	// DisksAPIVersion is the API version of the Microsoft.Compute disks, which is versioned separately.
+	DisksAPIVersion = "2022-07-02"
+	// NetworkAPIVersion is the API version of the Microsoft.Network resources.
+	NetworkAPIVersion = "2023-05-01"
+	// PrivateDNSAPIVersion is the API version of the private DNS resources.
+	PrivateDNSAPIVersion = "2024-06-01"
+
+	deploymentsAPIVersion = "2021-04-01"
+)
+
+// resourceTypes maps the SDK types of the resource parameters to their resource type and API version.
+var resourceTypes = map[reflect.Type]struct {
+	resourceType string
+	apiVersion   string
+}{
+	reflect.TypeOf(armcompute.AvailabilitySet{}):         {"Microsoft.Compute/availabilitySets", ComputeAPIVersion},
+	reflect.TypeOf(armcompute.Disk{}):                    {"Microsoft.Compute/disks", DisksAPIVersion},
+	reflect.TypeOf(armcompute.VirtualMachine{}):          {"Microsoft.Compute/virtualMachines", ComputeAPIVersion},
+	reflect.TypeOf(armcompute.VirtualMachineExtension{}): {"Microsoft.Compute/virtualMachines/extensions", ComputeAPIVersion},
+	reflect.TypeOf(armnetwork.BastionHost{}):             {"Microsoft.Network/bastionHosts", NetworkAPIVersion},
+	reflect.TypeOf(armnetwork.Interface{}):               {"Microsoft.Network/networkInterfaces", NetworkAPIVersion},
+	reflect.TypeOf(armnetwork.LoadBalancer{}):            {"Microsoft.Network/loadBalancers", NetworkAPIVersion},
+	reflect.TypeOf(armnetwork.NatGateway{}):              {"Microsoft.Network/natGateways", NetworkAPIVersion},
+	reflect.TypeOf(armnetwork.PublicIPAddress{}):         {"Microsoft.Network/publicIPAddresses", NetworkAPIVersion},
+	reflect.TypeOf(armnetwork.RouteTable{}):              {"Microsoft.Network/routeTables", NetworkAPIVersion},
+	reflect.TypeOf(armnetwork.SecurityGroup{}):           {"Microsoft.Network/networkSecurityGroups", NetworkAPIVersion},
+	reflect.TypeOf(armnetwork.Subnet{}):                  {"Microsoft.Network/virtualNetworks/subnets", NetworkAPIVersion},
+	reflect.TypeOf(armnetwork.VirtualNetwork{}):          {"Microsoft.Network/virtualNetworks", NetworkAPIVersion},
+	reflect.TypeOf(armnetwork.VirtualNetworkPeering{}):   {"Microsoft.Network/virtualNetworks/virtualNetworkPeerings", NetworkAPIVersion},
+	reflect.TypeOf(armprivatedns.PrivateZone{}):          {"Microsoft.Network/privateDnsZones", PrivateDNSAPIVersion},
+	reflect.TypeOf(armprivatedns.VirtualNetworkLink{}):   {"Microsoft.Network/privateDnsZones/virtualNetworkLinks", PrivateDNSAPIVersion},
+	reflect.TypeOf(armprivatedns.RecordSet{}):            {"Microsoft.Network/privateDnsZones/A", PrivateDNSAPIVersion},
+}
+
+// secretProperties maps the properties holding secrets, which are replaced by template parameters, to the type of
+// these parameters.
+var secretProperties = map[string]string{
+	"adminPassword":     "securestring",
+	"customData":        "securestring",
+	"protectedSettings": "secureObject",
+}
+
+// parameterNameUnsafeChars matches the characters that can't be used in the name of a template parameter.
+var parameterNameUnsafeChars = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// ResourceSpec describes a resource added to a template. azure.ResourceSpecGetter implements it.
+type ResourceSpec interface {
+	// ResourceName returns the name of the resource.
+	ResourceName() string
+	// OwnerResourceName returns the name of the parent of a child resource.
+	OwnerResourceName() string
+	// ResourceGroupName returns the name of the resource group of the resource.
+	ResourceGroupName() string
+	// Parameters returns the desired parameters of the resource, as SDK types.
+	Parameters(existing interface{}) (params interface{}, err error)
+}
+
+// Template is a deployment template.
+// See https://docs.microsoft.com/azure/azure-resource-manager/templates/syntax.
+type Template struct {
+	// ResourceGroup is the resource group the template is deployed to. Resources in other resource groups are
+	// deployed by nested deployments.
+	ResourceGroup string `json:"-"`
+
+	Schema         string               `json:"$schema"`
+	ContentVersion string               `json:"contentVersion"`
+	Parameters     map[string]Parameter `json:"parameters,omitempty"`
+	Resources      []Resource           `json:"resources"`
+}
+
+// Parameter is a parameter of a deployment template.
+type Parameter struct {
+	Type     string            `json:"type"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Resource is a resource of a deployment template.
+type Resource map[string]interface{}
+
+// New returns an empty template deployed to the given resource group.
+func New(resourceGroup string) *Template {
+	return &Template{
+		ResourceGroup:  resourceGroup,
+		Schema:         Schema,
+		ContentVersion: ContentVersion,
+		Parameters:     map[string]Parameter{},
+		Resources:      []Resource{},
+	}
+}
+
+// AddSpec adds the resource with the desired parameters of a spec to the template. Specs without parameters to apply
+// are skipped.
+func (t *Template) AddSpec(spec ResourceSpec) error {
+	params, err := spec.Parameters(nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the parameters of %s", spec.ResourceName())
+	}
+	if params == nil || (reflect.ValueOf(params).Kind() == reflect.Ptr && reflect.ValueOf(params).IsNil()) {
+		return nil
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(params))
+	resourceType, ok := resourceTypes[value.Type()]
+	if !ok {
+		return errors.Errorf("unsupported resource parameters %T of %s", params, spec.ResourceName())
+	}
+
+	typeName := resourceType.resourceType
+	if recordSet, ok := value.Interface().(armprivatedns.RecordSet); ok && recordSet.Properties != nil && recordSet.Properties.AaaaRecords != nil {
+		typeName = "Microsoft.Network/privateDnsZones/AAAA"
+	}
+
+	name := spec.ResourceName()
+	if strings.Count(typeName, "/") > 1 {
+		name = spec.OwnerResourceName() + "/" + name
+	}
+
+	if resourceGroup := spec.ResourceGroupName(); resourceGroup != "" && !strings.EqualFold(resourceGroup, t.ResourceGroup) {
+		nested := New(resourceGroup)
+		if err := nested.AddResource(typeName, resourceType.apiVersion, name, value.Interface()); err != nil {
+			return err
+		}
+		return t.addDeployment(nested, strings.ReplaceAll(name, "/", "-"))
+	}
+	return t.AddResource(typeName, resourceType.apiVersion, name, value.Interface())
+}
+
+// addDeployment adds a nested deployment of a template to another resource group.
+func (t *Template) addDeployment(nested *Template, name string) error {
+	// Secure parameters are passed through to the nested template.
+	parameters := map[string]interface{}{}
+	for parameterName, parameter := range nested.Parameters {
+		t.Parameters[parameterName] = parameter
+		parameters[parameterName] = map[string]string{"value": fmt.Sprintf("[parameters('%s')]", parameterName)}
+	}
+
+	t.Resources = append(t.Resources, Resource{
+		"type":          "Microsoft.Resources/deployments",
+		"apiVersion":    deploymentsAPIVersion,
+		"name":          name,
+		"resourceGroup": nested.ResourceGroup,
+		"properties": map[string]interface{}{
+			"mode":                        "Incremental",
+			"expressionEvaluationOptions": map[string]string{"scope": "inner"},
+			"parameters":                  parameters,
+			"template":                    nested,
+		},
+	})
+	return nil
+}
+
+// AddResource adds a resource of the given type and name with the given parameters, as SDK types, to the template.
+// Properties holding secrets are replaced by secure template parameters.
+func (t *Template) AddResource(resourceType, apiVersion, name string, params interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the parameters of %s", name)
+	}
+	resource := Resource{}
+	if err := json.Unmarshal(data, &resource); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal the parameters of %s", name)
+	}
+
+	// The identity of the resource is set by the template.
+	for _, key := range []string{"id", "etag", "name", "type"} {
+		delete(resource, key)
+	}
+	resource["type"] = resourceType
+	resource["apiVersion"] = apiVersion
+	resource["name"] = name
+	t.parameterizeSecrets(resource, name)
+
+	t.Resources = append(t.Resources, resource)
+	return nil
+}
+
+// parameterizeSecrets replaces the properties of a resource holding secrets by template parameters.
+func (t *Template) parameterizeSecrets(value interface{}, resourceName string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		t.parameterizeSecretsOfObject(v, resourceName)
+	case Resource:
+		t.parameterizeSecretsOfObject(v, resourceName)
+	case []interface{}:
+		for _, item := range v {
+			t.parameterizeSecrets(item, resourceName)
+		}
+	}
+}
+
+func (t *Template) parameterizeSecretsOfObject(object map[string]interface{}, resourceName string) {
+	for key, value := range object {
+		parameterType, secret := secretProperties[key]
+		if !secret {
+			t.parameterizeSecrets(value, resourceName)
+			continue
+		}
+		parameterName := strings.Trim(parameterNameUnsafeChars.ReplaceAllString(resourceName, "_"), "_") + "_" + key
+		t.Parameters[parameterName] = Parameter{
+			Type:     parameterType,
+			Metadata: map[string]string{"description": fmt.Sprintf("%s of %s", key, resourceName)},
+		}
+		object[key] = fmt.Sprintf("[parameters('%s')]", parameterName)
+	}
+}
+
+// MarshalJSON renders the template, with the dependencies of each resource on the other resources of the template it
+// references.
+func (t *Template) MarshalJSON() ([]byte, error) {
+	ids := make([]string, len(t.Resources))
+	for i, resource := range t.Resources {
+		ids[i] = resourceIDSuffix(resource)
+	}
+
+	resources := make([]Resource, len(t.Resources))
+	for i, resource := range t.Resources {
+		data, err := json.Marshal(map[string]interface{}(resource))
+		if err != nil {
+			return nil, err
+		}
+		content := strings.ToLower(string(data))
+		parent := ""
+		if parts := strings.Split(ids[i], "/"); len(parts) > 5 {
+			parent = strings.Join(parts[:len(parts)-2], "/")
+		}
+
+		var dependsOn []string
+		for j, id := range ids {
+			if i == j {
+				continue
+			}
+			if id == parent || strings.Contains(content, id+`"`) || strings.Contains(content, id+`/`) {
+				dependsOn = append(dependsOn, resourceIDExpression(t.Resources[j]))
+			}
+		}
+
+		resources[i] = Resource{}
+		for key, value := range resource {
+			resources[i][key] = value
+		}
+		if len(dependsOn) > 0 {
+			resources[i]["dependsOn"] = dependsOn
+		}
+	}
+
+	type template Template
+	rendered := template(*t)
+	rendered.Resources = resources
+	return json.Marshal(rendered)
+}
+
+// resourceIDSuffix returns the lower case end of the ID of a resource starting at its provider, e.g.
+// "/providers/microsoft.network/virtualnetworks/vnet/subnets/subnet".
+func resourceIDSuffix(resource Resource) string {
+	typeParts := strings.Split(fmt.Sprint(resource["type"]), "/")
+	nameParts := strings.Split(fmt.Sprint(resource["name"]), "/")
+	id := "/providers/" + typeParts[0]
+	for i, part := range typeParts[1:] {
+		id += "/" + part
+		if i < len(nameParts) {
+			id += "/" + nameParts[i]
+		}
+	}
+	return strings.ToLower(id)
+}
+
+// resourceIDExpression returns the template expression of the ID of a resource.
+func resourceIDExpression(resource Resource) string {
+	args := []string{fmt.Sprintf("'%s'", resource["type"])}
+	for _, part := range strings.Split(fmt.Sprint(resource["name"]), "/") {
+		args = append(args, fmt.Sprintf("'%s'", part))
+	}
+	return fmt.Sprintf("[resourceId(%s)]", strings.Join(args, ", "))
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package armtemplate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+type fakeSpec struct {
+	name          string
+	owner         string
+	resourceGroup string
+	params        interface{}
+	err           error
+}
+
+func (s fakeSpec) ResourceName() string      { return s.name }
+func (s fakeSpec) OwnerResourceName() string { return s.owner }
+func (s fakeSpec) ResourceGroupName() string { return s.resourceGroup }
+func (s fakeSpec) Parameters(existing interface{}) (interface{}, error) {
+	return s.params, s.err
+}
+
+func render(g *WithT, template *Template) map[string]interface{} {
+	data, err := json.Marshal(template)
+	g.Expect(err).NotTo(HaveOccurred())
+	rendered := map[string]interface{}{}
+	g.Expect(json.Unmarshal(data, &rendered)).To(Succeed())
+	return rendered
+}
+
+func TestAddSpec(t *testing.T) {
+	g := NewWithT(t)
+
+	template := New("my-rg")
+	g.Expect(template.AddSpec(fakeSpec{
+		name:          "my-vnet",
+		resourceGroup: "my-rg",
+		params: armnetwork.VirtualNetwork{
+			ID:       to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"),
+			Name:     to.Ptr("my-vnet"),
+			Location: to.Ptr("westeurope"),
+		},
+	})).To(Succeed())
+	g.Expect(template.AddSpec(fakeSpec{
+		name:          "my-nsg",
+		resourceGroup: "my-rg",
+		params:        armnetwork.SecurityGroup{Location: to.Ptr("westeurope")},
+	})).To(Succeed())
+	g.Expect(template.AddSpec(fakeSpec{
+		name:          "my-subnet",
+		owner:         "my-vnet",
+		resourceGroup: "my-rg",
+		params: &armnetwork.Subnet{
+			Properties: &armnetwork.SubnetPropertiesFormat{
+				NetworkSecurityGroup: &armnetwork.SecurityGroup{
+					ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-nsg"),
+				},
+			},
+		},
+	})).To(Succeed())
+	g.Expect(template.AddSpec(fakeSpec{
+		name:          "my-record",
+		owner:         "my-zone",
+		resourceGroup: "my-rg",
+		params: armprivatedns.RecordSet{
+			Properties: &armprivatedns.RecordSetProperties{
+				AaaaRecords: []*armprivatedns.AaaaRecord{{IPv6Address: to.Ptr("2001:db8::1")}},
+			},
+		},
+	})).To(Succeed())
+
+	rendered := render(g, template)
+	g.Expect(rendered["$schema"]).To(Equal(Schema))
+	g.Expect(rendered["contentVersion"]).To(Equal(ContentVersion))
+	resources := rendered["resources"].([]interface{})
+	g.Expect(resources).To(HaveLen(4))
+
+	vnet := resources[0].(map[string]interface{})
+	g.Expect(vnet["type"]).To(Equal("Microsoft.Network/virtualNetworks"))
+	g.Expect(vnet["apiVersion"]).To(Equal(NetworkAPIVersion))
+	g.Expect(vnet["name"]).To(Equal("my-vnet"))
+	g.Expect(vnet["location"]).To(Equal("westeurope"))
+	g.Expect(vnet).NotTo(HaveKey("id"))
+	g.Expect(vnet).NotTo(HaveKey("dependsOn"))
+
+	subnet := resources[2].(map[string]interface{})
+	g.Expect(subnet["type"]).To(Equal("Microsoft.Network/virtualNetworks/subnets"))
+	g.Expect(subnet["name"]).To(Equal("my-vnet/my-subnet"))
+	g.Expect(subnet["dependsOn"]).To(ConsistOf(
+		"[resourceId('Microsoft.Network/virtualNetworks', 'my-vnet')]",
+		"[resourceId('Microsoft.Network/networkSecurityGroups', 'my-nsg')]",
+	))
+
+	record := resources[3].(map[string]interface{})
+	g.Expect(record["type"]).To(Equal("Microsoft.Network/privateDnsZones/AAAA"))
+	g.Expect(record["name"]).To(Equal("my-zone/my-record"))
+
+	// Rendering the template doesn't change it.
+	g.Expect(template.Resources[2]).NotTo(HaveKey("dependsOn"))
+}
+
+func TestAddSpecSecrets(t *testing.T) {
+	g := NewWithT(t)
+
+	template := New("my-rg")
+	g.Expect(template.AddSpec(fakeSpec{
+		name: "my-vm",
+		params: armcompute.VirtualMachine{
+			Properties: &armcompute.VirtualMachineProperties{
+				OSProfile: &armcompute.OSProfile{
+					CustomData:    to.Ptr("Y2xvdWQtaW5pdA=="),
+					AdminPassword: to.Ptr("s3cr3t"),
+				},
+			},
+		},
+	})).To(Succeed())
+	g.Expect(template.AddSpec(fakeSpec{
+		name:  "my-extension",
+		owner: "my-vm",
+		params: armcompute.VirtualMachineExtension{
+			Properties: &armcompute.VirtualMachineExtensionProperties{
+				ProtectedSettings: map[string]string{"token": "s3cr3t"},
+			},
+		},
+	})).To(Succeed())
+
+	data, err := json.Marshal(template)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).NotTo(ContainSubstring("s3cr3t"))
+	g.Expect(string(data)).NotTo(ContainSubstring("Y2xvdWQtaW5pdA=="))
+
+	g.Expect(template.Parameters).To(HaveLen(3))
+	g.Expect(template.Parameters["my_vm_customData"].Type).To(Equal("securestring"))
+	g.Expect(template.Parameters["my_vm_adminPassword"].Type).To(Equal("securestring"))
+	g.Expect(template.Parameters["my_vm_my_extension_protectedSettings"].Type).To(Equal("secureObject"))
+
+	osProfile := template.Resources[0]["properties"].(map[string]interface{})["osProfile"].(map[string]interface{})
+	g.Expect(osProfile["customData"]).To(Equal("[parameters('my_vm_customData')]"))
+
+	rendered := render(g, template)
+	extension := rendered["resources"].([]interface{})[1].(map[string]interface{})
+	g.Expect(extension["dependsOn"]).To(ConsistOf("[resourceId('Microsoft.Compute/virtualMachines', 'my-vm')]"))
+}
+
+func TestAddSpecOtherResourceGroup(t *testing.T) {
+	g := NewWithT(t)
+
+	template := New("my-rg")
+	g.Expect(template.AddSpec(fakeSpec{
+		name:          "my-vnet-to-remote-vnet",
+		owner:         "my-vnet",
+		resourceGroup: "my-rg",
+		params:        armnetwork.VirtualNetworkPeering{},
+	})).To(Succeed())
+	g.Expect(template.AddSpec(fakeSpec{
+		name:          "remote-vnet-to-my-vnet",
+		owner:         "remote-vnet",
+		resourceGroup: "remote-rg",
+		params:        armnetwork.VirtualNetworkPeering{},
+	})).To(Succeed())
+
+	g.Expect(template.Resources).To(HaveLen(2))
+	g.Expect(template.Resources[0]["type"]).To(Equal("Microsoft.Network/virtualNetworks/virtualNetworkPeerings"))
+	deployment := template.Resources[1]
+	g.Expect(deployment["type"]).To(Equal("Microsoft.Resources/deployments"))
+	g.Expect(deployment["name"]).To(Equal("remote-vnet-remote-vnet-to-my-vnet"))
+	g.Expect(deployment["resourceGroup"]).To(Equal("remote-rg"))
+
+	nested := deployment["properties"].(map[string]interface{})["template"].(*Template)
+	g.Expect(nested.ResourceGroup).To(Equal("remote-rg"))
+	g.Expect(nested.Resources).To(HaveLen(1))
+	g.Expect(nested.Resources[0]["name"]).To(Equal("remote-vnet/remote-vnet-to-my-vnet"))
+}
+
+func TestAddSpecErrors(t *testing.T) {
+	g := NewWithT(t)
+
+	template := New("my-rg")
+	g.Expect(template.AddSpec(fakeSpec{name: "up-to-date"})).To(Succeed())
+	g.Expect(template.AddSpec(fakeSpec{name: "up-to-date", params: (*armnetwork.Subnet)(nil)})).To(Succeed())
+	g.Expect(template.Resources).To(BeEmpty())
+
+	g.Expect(template.AddSpec(fakeSpec{name: "failing", err: errors.New("boom")})).To(MatchError(ContainSubstring("boom")))
+	g.Expect(template.AddSpec(fakeSpec{name: "unsupported", params: "unsupported"})).To(MatchError(ContainSubstring("unsupported resource parameters")))
+}
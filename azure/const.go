@@ -29,7 +29,54 @@ const (
 	// for annotation formatting rules.
 	RGTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-rg"
 
+	// NICTagsLastAppliedAnnotation is the prefix of the keys of the machine object annotations which track the
+	// additional tags of its network interfaces. The keys are suffixed by the index of the network interface.
+	NICTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-nic"
+
+	// OSDiskTagsLastAppliedAnnotation is the key for the machine object annotation
+	// which tracks the additional tags of the OS disk of the machine.
+	OSDiskTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-osdisk"
+
+	// NodeResourceGroupTagsLastAppliedAnnotation is the key for the AzureManagedControlPlane object annotation
+	// which tracks the NodeResourceGroupTags of the node resource group of the AKS cluster.
+	NodeResourceGroupTagsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-tags-node-rg"
+
+	// PolicyExemptionsLastAppliedAnnotation is the key for the Azure Cluster object annotation
+	// which tracks the policy exemptions created on its resource group, so that the ones removed from its spec are deleted.
+	PolicyExemptionsLastAppliedAnnotation = "sigs.k8s.io/cluster-api-provider-azure-last-applied-policy-exemptions"
+
 	// ReplicasManagedByAutoscalerAnnotation is the key for the AzureMachinePool Object annotation
 	// which signals that the underlying VMSS replicas are not controlled by CAPZ.
 	ReplicasManagedByAutoscalerAnnotation = "cluster.x-k8s.io/replicas-managed-by-autoscaler"
+
+	// AutoscalerMinSizeAnnotation is the key for the MachinePool object annotation holding the minimum size of its
+	// node group for the cluster-autoscaler.
+	AutoscalerMinSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size"
+
+	// AutoscalerMaxSizeAnnotation is the key for the MachinePool object annotation holding the maximum size of its
+	// node group for the cluster-autoscaler.
+	AutoscalerMaxSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size"
+
+	// ZonalCapacityBackoffAnnotation is the key for the MachinePool object annotation holding the zones its scale set
+	// recently failed to scale out in for lack of capacity, as a JSON object mapping each zone to the RFC 3339 time
+	// until which an autoscaler should prefer other node groups, e.g. {"2":"2022-06-15T09:05:00Z"}.
+	ZonalCapacityBackoffAnnotation = "sigs.k8s.io/cluster-api-provider-azure-zonal-capacity-backoff"
+
+	// BootstrapDataBlobAnnotation is the key for the AzureMachine and AzureMachinePool object annotation holding the
+	// name of the blob their bootstrap data was uploaded to, until that blob is deleted.
+	BootstrapDataBlobAnnotation = "sigs.k8s.io/cluster-api-provider-azure-bootstrap-data-blob"
+
+	// BootstrapDataGenerationAnnotation is the key for the AzureMachinePool object annotation holding the generations
+	// of the AzureMachinePool and its MachinePool when their bootstrap data was last uploaded, so that it's uploaded
+	// again when a change of either may create instances of the scale set.
+	BootstrapDataGenerationAnnotation = "sigs.k8s.io/cluster-api-provider-azure-bootstrap-data-generation"
+
+	// BootstrapDataHashAnnotation is the key for the AzureMachine and AzureMachinePool object annotation holding the
+	// SHA-256 hash of the bootstrap data uploaded to their blob, so that it's only uploaded again once it changes.
+	BootstrapDataHashAnnotation = "sigs.k8s.io/cluster-api-provider-azure-bootstrap-data-hash"
+
+	// BootstrapDataSASExpiryAnnotation is the key for the AzureMachine and AzureMachinePool object annotation holding
+	// the RFC 3339 expiry time of the SAS URL their custom data includes to fetch the bootstrap data from its blob, so
+	// that the same URL is used until it's close to expiry.
+	BootstrapDataSASExpiryAnnotation = "sigs.k8s.io/cluster-api-provider-azure-bootstrap-data-sas-expiry"
 )
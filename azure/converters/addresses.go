@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+// SDKNetworkInterfaceToNodeAddresses converts an Azure SDK network interface to the internal DNS name of the interface
+// and the private IPs of all its IP configurations, including the secondary ones.
+func SDKNetworkInterfaceToNodeAddresses(nic armnetwork.Interface) []corev1.NodeAddress {
+	var addresses []corev1.NodeAddress
+	if nic.Properties == nil {
+		return addresses
+	}
+
+	if nic.Properties.DNSSettings != nil && pointer.StringDeref(nic.Properties.DNSSettings.InternalFqdn, "") != "" {
+		addresses = append(addresses, corev1.NodeAddress{
+			Type:    corev1.NodeInternalDNS,
+			Address: *nic.Properties.DNSSettings.InternalFqdn,
+		})
+	}
+
+	for _, ipConfig := range nic.Properties.IPConfigurations {
+		if ipConfig == nil || ipConfig.Properties == nil || pointer.StringDeref(ipConfig.Properties.PrivateIPAddress, "") == "" {
+			continue
+		}
+		addresses = append(addresses, corev1.NodeAddress{
+			Type:    corev1.NodeInternalIP,
+			Address: *ipConfig.Properties.PrivateIPAddress,
+		})
+	}
+
+	return addresses
+}
+
+// SDKPublicIPToNodeAddresses converts an Azure SDK public IP to its IP and the FQDN of its DNS record, if any.
+func SDKPublicIPToNodeAddresses(publicIP armnetwork.PublicIPAddress) []corev1.NodeAddress {
+	var addresses []corev1.NodeAddress
+	if publicIP.Properties == nil {
+		return addresses
+	}
+
+	if pointer.StringDeref(publicIP.Properties.IPAddress, "") != "" {
+		addresses = append(addresses, corev1.NodeAddress{
+			Type:    corev1.NodeExternalIP,
+			Address: *publicIP.Properties.IPAddress,
+		})
+	}
+	if publicIP.Properties.DNSSettings != nil && pointer.StringDeref(publicIP.Properties.DNSSettings.Fqdn, "") != "" {
+		addresses = append(addresses, corev1.NodeAddress{
+			Type:    corev1.NodeExternalDNS,
+			Address: *publicIP.Properties.DNSSettings.Fqdn,
+		})
+	}
+
+	return addresses
+}
+
+// UniqueNodeAddresses returns the addresses without duplicates, keeping their order, as e.g. the internal DNS name of
+// the primary network interface of a VM can be reported more than once.
+func UniqueNodeAddresses(addresses []corev1.NodeAddress) []corev1.NodeAddress {
+	seen := make(map[corev1.NodeAddress]bool, len(addresses))
+	unique := make([]corev1.NodeAddress, 0, len(addresses))
+	for _, address := range addresses {
+		if seen[address] {
+			continue
+		}
+		seen[address] = true
+		unique = append(unique, address)
+	}
+	return unique
+}
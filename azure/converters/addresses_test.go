@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSDKNetworkInterfaceToNodeAddresses(t *testing.T) {
+	tests := []struct {
+		name string
+		nic  armnetwork.Interface
+		want []corev1.NodeAddress
+	}{
+		{
+			name: "nil properties network interface",
+			nic:  armnetwork.Interface{},
+		},
+		{
+			name: "network interface with internal DNS name and secondary IP configurations",
+			nic: armnetwork.Interface{
+				Properties: &armnetwork.InterfacePropertiesFormat{
+					DNSSettings: &armnetwork.InterfaceDNSSettings{
+						InternalFqdn: to.Ptr("my-vm.internal.cloudapp.net"),
+					},
+					IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+						{
+							Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+								PrivateIPAddress: to.Ptr("10.0.0.4"),
+							},
+						},
+						{
+							Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+								PrivateIPAddress: to.Ptr("2001:1234:5678:9abd::4"),
+							},
+						},
+						{
+							Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{},
+						},
+						{},
+					},
+				},
+			},
+			want: []corev1.NodeAddress{
+				{Type: corev1.NodeInternalDNS, Address: "my-vm.internal.cloudapp.net"},
+				{Type: corev1.NodeInternalIP, Address: "10.0.0.4"},
+				{Type: corev1.NodeInternalIP, Address: "2001:1234:5678:9abd::4"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+			g.Expect(SDKNetworkInterfaceToNodeAddresses(tt.nic)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestSDKPublicIPToNodeAddresses(t *testing.T) {
+	tests := []struct {
+		name     string
+		publicIP armnetwork.PublicIPAddress
+		want     []corev1.NodeAddress
+	}{
+		{
+			name:     "nil properties public IP",
+			publicIP: armnetwork.PublicIPAddress{},
+		},
+		{
+			name: "public IP without DNS name",
+			publicIP: armnetwork.PublicIPAddress{
+				Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+					IPAddress: to.Ptr("20.1.2.3"),
+				},
+			},
+			want: []corev1.NodeAddress{
+				{Type: corev1.NodeExternalIP, Address: "20.1.2.3"},
+			},
+		},
+		{
+			name: "public IP with DNS name",
+			publicIP: armnetwork.PublicIPAddress{
+				Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+					IPAddress: to.Ptr("20.1.2.3"),
+					DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
+						Fqdn: to.Ptr("my-vm.westus2.cloudapp.azure.com"),
+					},
+				},
+			},
+			want: []corev1.NodeAddress{
+				{Type: corev1.NodeExternalIP, Address: "20.1.2.3"},
+				{Type: corev1.NodeExternalDNS, Address: "my-vm.westus2.cloudapp.azure.com"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+			g.Expect(SDKPublicIPToNodeAddresses(tt.publicIP)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestUniqueNodeAddresses(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(UniqueNodeAddresses([]corev1.NodeAddress{
+		{Type: corev1.NodeInternalDNS, Address: "my-vm"},
+		{Type: corev1.NodeInternalIP, Address: "10.0.0.4"},
+		{Type: corev1.NodeInternalDNS, Address: "my-vm"},
+		{Type: corev1.NodeExternalIP, Address: "10.0.0.4"},
+	})).To(Equal([]corev1.NodeAddress{
+		{Type: corev1.NodeInternalDNS, Address: "my-vm"},
+		{Type: corev1.NodeInternalIP, Address: "10.0.0.4"},
+		{Type: corev1.NodeExternalIP, Address: "10.0.0.4"},
+	}))
+}
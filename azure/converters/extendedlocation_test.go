@@ -0,0 +1,44 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestExtendedLocationToSDK(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(ExtendedLocationToNetworkSDK(nil)).To(BeNil())
+	g.Expect(ExtendedLocationToComputeSDK(nil)).To(BeNil())
+
+	edgeZone := &infrav1.ExtendedLocationSpec{Name: "attatlanta1", Type: infrav1.ExtendedLocationTypeEdgeZone}
+	g.Expect(ExtendedLocationToNetworkSDK(edgeZone)).To(Equal(&armnetwork.ExtendedLocation{
+		Name: to.Ptr("attatlanta1"),
+		Type: to.Ptr(armnetwork.ExtendedLocationTypesEdgeZone),
+	}))
+	g.Expect(ExtendedLocationToComputeSDK(edgeZone)).To(Equal(&armcompute.ExtendedLocation{
+		Name: to.Ptr("attatlanta1"),
+		Type: to.Ptr(armcompute.ExtendedLocationTypesEdgeZone),
+	}))
+}
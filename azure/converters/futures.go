@@ -18,37 +18,39 @@ package converters
 
 import (
 	"encoding/base64"
+	"encoding/json"
 
-	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
-// SDKToFuture converts an SDK future to an infrav1.Future.
-func SDKToFuture(future azureautorest.FutureAPI, futureType, service, resourceName, rgName string) (*infrav1.Future, error) {
-	jsonData, err := future.MarshalJSON()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to marshal async future")
-	}
-
+// ResumeTokenToFuture converts the resume token of an SDK poller to an infrav1.Future.
+func ResumeTokenToFuture(resumeToken, futureType, service, resourceName, rgName string) *infrav1.Future {
 	return &infrav1.Future{
 		Type:          futureType,
 		ResourceGroup: rgName,
 		ServiceName:   service,
 		Name:          resourceName,
-		Data:          base64.URLEncoding.EncodeToString(jsonData),
-	}, nil
+		Data:          base64.URLEncoding.EncodeToString([]byte(resumeToken)),
+	}
 }
 
-// FutureToSDK converts an infrav1.Future to an SDK future.
-func FutureToSDK(future infrav1.Future) (azureautorest.FutureAPI, error) {
+// FutureToResumeToken converts an infrav1.Future to the resume token of an SDK poller.
+// Futures stored by previous versions of the Azure SDK aren't resume tokens, and are rejected.
+func FutureToResumeToken(future infrav1.Future) (string, error) {
 	futureData, err := base64.URLEncoding.DecodeString(future.Data)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to base64 decode future data")
+		return "", errors.Wrap(err, "failed to base64 decode future data")
+	}
+	var token struct {
+		Type  string          `json:"type"`
+		Token json.RawMessage `json:"token"`
+	}
+	if err := json.Unmarshal(futureData, &token); err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal future data")
 	}
-	var genericFuture azureautorest.Future
-	if err := genericFuture.UnmarshalJSON(futureData); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal future data")
+	if token.Type == "" || len(token.Token) == 0 {
+		return "", errors.New("future data is not a resume token")
 	}
-	return &genericFuture, nil
+	return string(futureData), nil
 }
@@ -17,24 +17,15 @@ limitations under the License.
 package converters
 
 import (
-	"net/http"
 	"testing"
 
-	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
 var (
-	sdkFuture, _ = azureautorest.NewFutureFromResponse(&http.Response{
-		Status:     "200 OK",
-		StatusCode: 200,
-		Request: &http.Request{
-			Method: http.MethodDelete,
-		},
-	})
-
-	validFuture = infrav1.Future{
+	// legacyFuture is a future stored by a previous version of the Azure SDK, which isn't a resume token.
+	legacyFuture = infrav1.Future{
 		Type:          infrav1.DeleteFuture,
 		ServiceName:   "test-service",
 		Name:          "test-group",
@@ -57,90 +48,53 @@ var (
 		ResourceGroup: "test-group",
 		Data:          "this is not b64 encoded",
 	}
+)
 
-	invalidFuture = infrav1.Future{
+func Test_ResumeTokenToFuture(t *testing.T) {
+	g := NewGomegaWithT(t)
+	f := ResumeTokenToFuture(`{"type":"DisksClientDeleteResponse","token":{"state":"InProgress"}}`, infrav1.DeleteFuture, "test-service", "test-resource", "test-group")
+	g.Expect(f).To(Equal(&infrav1.Future{
 		Type:          infrav1.DeleteFuture,
 		ServiceName:   "test-service",
-		Name:          "test-group",
+		Name:          "test-resource",
 		ResourceGroup: "test-group",
-		Data:          "ZmFrZSBiNjQgZnV0dXJlIGRhdGEK",
-	}
-)
-
-func Test_SDKToFuture(t *testing.T) {
-	cases := []struct {
-		name         string
-		future       azureautorest.FutureAPI
-		futureType   string
-		service      string
-		resourceName string
-		rgName       string
-		expect       func(*GomegaWithT, *infrav1.Future, error)
-	}{
-		{
-			name:         "valid future",
-			future:       &sdkFuture,
-			futureType:   infrav1.DeleteFuture,
-			service:      "test-service",
-			resourceName: "test-resource",
-			rgName:       "test-group",
-			expect: func(g *GomegaWithT, f *infrav1.Future, err error) {
-				g.Expect(err).Should(BeNil())
-				g.Expect(f).Should(BeEquivalentTo(&infrav1.Future{
-					Type:          infrav1.DeleteFuture,
-					ServiceName:   "test-service",
-					Name:          "test-resource",
-					ResourceGroup: "test-group",
-					Data:          "eyJtZXRob2QiOiJERUxFVEUiLCJwb2xsaW5nTWV0aG9kIjoiIiwicG9sbGluZ1VSSSI6IiIsImxyb1N0YXRlIjoiU3VjY2VlZGVkIiwicmVzdWx0VVJJIjoiIn0=",
-				}))
-			},
-		},
-	}
-
-	for _, c := range cases {
-		c := c
-		t.Run(c.name, func(t *testing.T) {
-			t.Parallel()
-			g := NewGomegaWithT(t)
-			result, err := SDKToFuture(c.future, c.futureType, c.service, c.resourceName, c.rgName)
-			c.expect(g, result, err)
-		})
-	}
+		Data:          "eyJ0eXBlIjoiRGlza3NDbGllbnREZWxldGVSZXNwb25zZSIsInRva2VuIjp7InN0YXRlIjoiSW5Qcm9ncmVzcyJ9fQ==",
+	}))
 }
 
-func Test_FutureToSDK(t *testing.T) {
+func Test_FutureToResumeToken(t *testing.T) {
 	cases := []struct {
 		name   string
 		future infrav1.Future
-		expect func(*GomegaWithT, azureautorest.FutureAPI, error)
+		expect func(*GomegaWithT, string, error)
 	}{
 		{
 			name:   "data is empty",
 			future: emptyDataFuture,
-			expect: func(g *GomegaWithT, f azureautorest.FutureAPI, err error) {
-				g.Expect(err.Error()).Should(ContainSubstring("failed to unmarshal future data"))
+			expect: func(g *GomegaWithT, token string, err error) {
+				g.Expect(err).To(MatchError(ContainSubstring("failed to unmarshal future data")))
 			},
 		},
 		{
 			name:   "data is not base64 encoded",
 			future: decodedDataFuture,
-			expect: func(g *GomegaWithT, f azureautorest.FutureAPI, err error) {
-				g.Expect(err.Error()).Should(ContainSubstring("failed to base64 decode future data"))
+			expect: func(g *GomegaWithT, token string, err error) {
+				g.Expect(err).To(MatchError(ContainSubstring("failed to base64 decode future data")))
 			},
 		},
 		{
-			name:   "base64 data is not a valid future",
-			future: invalidFuture,
-			expect: func(g *GomegaWithT, f azureautorest.FutureAPI, err error) {
-				g.Expect(err.Error()).Should(ContainSubstring("failed to unmarshal future data"))
+			name:   "data is a future of a previous Azure SDK",
+			future: legacyFuture,
+			expect: func(g *GomegaWithT, token string, err error) {
+				g.Expect(err).To(MatchError("future data is not a resume token"))
 			},
 		},
 		{
-			name:   "valid future data",
-			future: validFuture,
-			expect: func(g *GomegaWithT, f azureautorest.FutureAPI, err error) {
-				g.Expect(err).Should(BeNil())
-				g.Expect(f).Should(BeAssignableToTypeOf(&azureautorest.Future{}))
+			name:   "valid resume token",
+			future: *ResumeTokenToFuture(`{"type":"DisksClientDeleteResponse","token":{"state":"InProgress"}}`, infrav1.DeleteFuture, "test-service", "test-resource", "test-group"),
+			expect: func(g *GomegaWithT, token string, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(token).To(Equal(`{"type":"DisksClientDeleteResponse","token":{"state":"InProgress"}}`))
 			},
 		},
 	}
@@ -150,7 +104,7 @@ func Test_FutureToSDK(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			t.Parallel()
 			g := NewGomegaWithT(t)
-			result, err := FutureToSDK(c.future)
+			result, err := FutureToResumeToken(c.future)
 			c.expect(g, result, err)
 		})
 	}
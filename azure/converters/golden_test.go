@@ -0,0 +1,534 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters_test
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/fixtures"
+)
+
+// update regenerates the golden files from the output of the converters, e.g. with
+// "go test ./azure/converters/... -update". The changes of the golden files must be reviewed like code changes.
+var update = flag.Bool("update", false, "update the golden files of the converters")
+
+// expectGolden compares the JSON encoding of got with the golden file testdata/<name>.golden.json.
+func expectGolden(t *testing.T, name string, got interface{}) {
+	t.Helper()
+	g := NewWithT(t)
+
+	data, err := json.MarshalIndent(got, "", "  ")
+	g.Expect(err).NotTo(HaveOccurred())
+	data = append(data, '\n')
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if *update {
+		g.Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		g.Expect(os.WriteFile(path, data, 0o600)).To(Succeed())
+	}
+
+	want, err := os.ReadFile(path)
+	g.Expect(err).NotTo(HaveOccurred(), "missing golden file, run the test with -update to create it")
+	g.Expect(string(data)).To(Equal(string(want)))
+}
+
+// result holds the output of a converter returning an error, so that errors are recorded in the golden files too.
+type result struct {
+	Output interface{} `json:"output"`
+	Error  string      `json:"error,omitempty"`
+}
+
+func newResult(output interface{}, err error) result {
+	r := result{Output: output}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return r
+}
+
+func TestConvertersGolden(t *testing.T) {
+	agentPool := azure.AgentPoolSpec{
+		Name:              "pool1",
+		ResourceGroup:     fixtures.ResourceGroup,
+		Cluster:           fixtures.ClusterName,
+		Version:           to.Ptr("1.23.5"),
+		SKU:               "Standard_D4s_v3",
+		Replicas:          3,
+		OSDiskSizeGB:      128,
+		VnetSubnetID:      "/subscriptions/" + fixtures.SubscriptionID + "/resourceGroups/" + fixtures.ResourceGroup + "/providers/Microsoft.Network/virtualNetworks/my-cluster-vnet/subnets/node-subnet",
+		Mode:              "User",
+		MaxCount:          to.Ptr[int32](5),
+		MinCount:          to.Ptr[int32](1),
+		NodeLabels:        map[string]*string{"workload": to.Ptr("batch")},
+		NodeTaints:        []string{"workload=batch:NoSchedule"},
+		EnableAutoScaling: to.Ptr(true),
+		AvailabilityZones: []string{"1", "2", "3"},
+		MaxPods:           to.Ptr[int32](60),
+		OsDiskType:        to.Ptr("Ephemeral"),
+		EnableUltraSSD:    to.Ptr(false),
+		OSType:            to.Ptr("Linux"),
+	}
+	identities := []infrav1.UserAssignedIdentity{
+		{ProviderID: "azure:///subscriptions/" + fixtures.SubscriptionID + "/resourceGroups/" + fixtures.ResourceGroup + "/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-cluster-identity"},
+		{ProviderID: "/subscriptions/" + fixtures.SubscriptionID + "/resourceGroups/" + fixtures.ResourceGroup + "/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-cluster-kubelet"},
+	}
+	maxPrice := resource.MustParse("0.0425")
+	nic := fixtures.MultiIPConfigNIC()
+	publicIP := fixtures.PublicIP()
+
+	tests := []struct {
+		name    string
+		convert func() interface{}
+	}{
+		{
+			name: "SDKToVMSS_with_plan",
+			convert: func() interface{} {
+				return newResult(converters.SDKToVMSS(fixtures.VMSSWithPlan(), fixtures.VMSSVMs()))
+			},
+		},
+		{
+			name: "SDKToVMSSVM_without_properties",
+			convert: func() interface{} {
+				return newResult(converters.SDKToVMSSVM(armcompute.VirtualMachineScaleSetVM{
+					ID:         fixtures.VMSSVMs()[0].ID,
+					InstanceID: fixtures.VMSSVMs()[0].InstanceID,
+				}))
+			},
+		},
+		{
+			name: "SDKToVMSSVMInstanceView",
+			convert: func() interface{} {
+				return converters.SDKToVMSSVMInstanceView(fixtures.VMSSVMs()[0].Properties.InstanceView)
+			},
+		},
+		{
+			name: "SDKToVMSSVMInstanceView_boot_diagnostics_error",
+			convert: func() interface{} {
+				return converters.SDKToVMSSVMInstanceView(&armcompute.VirtualMachineScaleSetVMInstanceView{
+					BootDiagnostics: &armcompute.BootDiagnosticsInstanceView{
+						Status: &armcompute.InstanceViewStatus{
+							Code:    to.Ptr("BootDiagnosticsStorageAccountNotFound"),
+							Level:   to.Ptr(armcompute.StatusLevelTypesError),
+							Message: to.Ptr("The storage account of the boot diagnostics of the VM was not found."),
+						},
+					},
+					Disks: []*armcompute.DiskInstanceView{
+						{
+							Name: to.Ptr("my-cluster-mp-0_OsDisk_1_9f6a0c2e"),
+							Statuses: []*armcompute.InstanceViewStatus{
+								{Code: to.Ptr("ProvisioningState/updating"), Level: to.Ptr(armcompute.StatusLevelTypesInfo)},
+							},
+						},
+					},
+				})
+			},
+		},
+		{
+			name: "SDKToVMSSVMInstanceView_without_health",
+			convert: func() interface{} {
+				return converters.SDKToVMSSVMInstanceView(&armcompute.VirtualMachineScaleSetVMInstanceView{
+					Statuses: []*armcompute.InstanceViewStatus{
+						{Code: to.Ptr("PowerState/running"), Level: to.Ptr(armcompute.StatusLevelTypesInfo)},
+					},
+				})
+			},
+		},
+		{
+			name: "SDKToVM",
+			convert: func() interface{} {
+				return newResult(converters.SDKToVM(fixtures.VM()))
+			},
+		},
+		{
+			name: "SDKToVMPlacement",
+			convert: func() interface{} {
+				return converters.SDKToVMPlacement(fixtures.VM())
+			},
+		},
+		{
+			name: "SDKToVMPlacement_dedicated_host_group",
+			convert: func() interface{} {
+				vm := fixtures.VM()
+				vm.Properties.HostGroup = &armcompute.SubResource{
+					ID: to.Ptr("/subscriptions/" + fixtures.SubscriptionID + "/resourceGroups/" + fixtures.ResourceGroup + "/providers/Microsoft.Compute/hostGroups/my-host-group"),
+				}
+				vm.Properties.InstanceView.AssignedHost = to.Ptr("/subscriptions/" + fixtures.SubscriptionID + "/resourceGroups/" + fixtures.ResourceGroup + "/providers/Microsoft.Compute/hostGroups/my-host-group/hosts/my-host-1")
+				return converters.SDKToVMPlacement(vm)
+			},
+		},
+		{
+			name: "SDKToVMPlacement_without_instance_view",
+			convert: func() interface{} {
+				vm := fixtures.VM()
+				vm.Properties.InstanceView = nil
+				return converters.SDKToVMPlacement(vm)
+			},
+		},
+		{
+			name: "SDKToPowerState",
+			convert: func() interface{} {
+				status := func(code string) *armcompute.InstanceViewStatus {
+					return &armcompute.InstanceViewStatus{Code: to.Ptr(code), Level: to.Ptr(armcompute.StatusLevelTypesInfo)}
+				}
+				provisioned := status("ProvisioningState/succeeded")
+				return map[string]infrav1.PowerState{
+					"starting":     converters.SDKToPowerState([]*armcompute.InstanceViewStatus{provisioned, status("PowerState/starting")}),
+					"running":      converters.SDKToPowerState([]*armcompute.InstanceViewStatus{provisioned, status("PowerState/running")}),
+					"stopping":     converters.SDKToPowerState([]*armcompute.InstanceViewStatus{provisioned, status("PowerState/stopping")}),
+					"stopped":      converters.SDKToPowerState([]*armcompute.InstanceViewStatus{provisioned, status("PowerState/stopped")}),
+					"deallocating": converters.SDKToPowerState([]*armcompute.InstanceViewStatus{provisioned, status("PowerState/deallocating")}),
+					"deallocated":  converters.SDKToPowerState([]*armcompute.InstanceViewStatus{provisioned, status("PowerState/deallocated")}),
+					"hibernated":   converters.SDKToPowerState([]*armcompute.InstanceViewStatus{provisioned, status("PowerState/deallocated"), status("HibernationState/Hibernated")}),
+					"unknown":      converters.SDKToPowerState([]*armcompute.InstanceViewStatus{provisioned, status("PowerState/unknown")}),
+					"nil_status":   converters.SDKToPowerState([]*armcompute.InstanceViewStatus{nil, status("PowerState/running")}),
+					"no_statuses":  converters.SDKToPowerState(nil),
+				}
+			},
+		},
+		{
+			name: "SDKNetworkInterfaceToNodeAddresses_multi_ipconfig",
+			convert: func() interface{} {
+				return converters.SDKNetworkInterfaceToNodeAddresses(nic)
+			},
+		},
+		{
+			name: "SDKPublicIPToNodeAddresses",
+			convert: func() interface{} {
+				return converters.SDKPublicIPToNodeAddresses(publicIP)
+			},
+		},
+		{
+			name: "UniqueNodeAddresses",
+			convert: func() interface{} {
+				addresses := converters.SDKNetworkInterfaceToNodeAddresses(nic)
+				addresses = append(addresses, converters.SDKPublicIPToNodeAddresses(publicIP)...)
+				addresses = append(addresses, converters.SDKNetworkInterfaceToNodeAddresses(nic)...)
+				return converters.UniqueNodeAddresses(addresses)
+			},
+		},
+		{
+			name: "GetSubnetAddresses_dual_stack",
+			convert: func() interface{} {
+				return converters.GetSubnetAddresses(fixtures.DualStackSubnet())
+			},
+		},
+		{
+			name: "GetRecordType",
+			convert: func() interface{} {
+				return []interface{}{
+					converters.GetRecordType("10.0.0.4"),
+					converters.GetRecordType("2001:1234:5678:9abd::4"),
+				}
+			},
+		},
+		{
+			name: "SDKToManagedClusterStatus",
+			convert: func() interface{} {
+				return converters.SDKToManagedClusterStatus(fixtures.ManagedCluster())
+			},
+		},
+		{
+			name: "SDKToAgentPoolStatus",
+			convert: func() interface{} {
+				return converters.SDKToAgentPoolStatus(fixtures.AgentPool())
+			},
+		},
+		{
+			name: "AgentPoolToManagedClusterAgentPoolProfile",
+			convert: func() interface{} {
+				return converters.AgentPoolToManagedClusterAgentPoolProfile(agentPool)
+			},
+		},
+		{
+			name: "AgentPoolToContainerServiceAgentPool",
+			convert: func() interface{} {
+				return converters.AgentPoolToContainerServiceAgentPool(agentPool)
+			},
+		},
+		{
+			name: "SecurityRuleToSDK",
+			convert: func() interface{} {
+				return []interface{}{
+					converters.SecurityRuleToSDK(infrav1.SecurityRule{
+						Name:             "allow_apiserver",
+						Description:      "Allow K8s API Server",
+						Priority:         2201,
+						Protocol:         infrav1.SecurityGroupProtocolTCP,
+						Direction:        infrav1.SecurityRuleDirectionInbound,
+						Source:           to.Ptr("*"),
+						SourcePorts:      to.Ptr("*"),
+						Destination:      to.Ptr("*"),
+						DestinationPorts: to.Ptr("6443"),
+					}),
+					converters.SecurityRuleToSDK(infrav1.SecurityRule{
+						Name:             "deny_outbound_all",
+						Priority:         4096,
+						Protocol:         infrav1.SecurityGroupProtocolAll,
+						Direction:        infrav1.SecurityRuleDirectionOutbound,
+						Destination:      to.Ptr("Internet"),
+						DestinationPorts: to.Ptr("*"),
+					}),
+				}
+			},
+		},
+		{
+			name: "SKUToSDK",
+			convert: func() interface{} {
+				return []interface{}{
+					converters.SKUtoSDK(infrav1.SKUStandard),
+					converters.SKUTierToSDK(infrav1.SKUTierRegional),
+					converters.SKUTierToSDK(infrav1.SKUTierGlobal),
+				}
+			},
+		},
+		{
+			name: "GetSpotVMOptions",
+			convert: func() interface{} {
+				priority, evictionPolicy, billingProfile, err := converters.GetSpotVMOptions(&infrav1.SpotVMOptions{MaxPrice: &maxPrice})
+				return newResult([]interface{}{priority, evictionPolicy, billingProfile}, err)
+			},
+		},
+		{
+			name: "ExtendedLocationToSDK",
+			convert: func() interface{} {
+				extendedLocation := &infrav1.ExtendedLocationSpec{Name: "losangeles", Type: "EdgeZone"}
+				return []interface{}{
+					converters.ExtendedLocationToNetworkSDK(extendedLocation),
+					converters.ExtendedLocationToComputeSDK(extendedLocation),
+				}
+			},
+		},
+		{
+			name: "VMIdentityToVMSDK_user_assigned",
+			convert: func() interface{} {
+				return newResult(converters.VMIdentityToVMSDK(infrav1.VMIdentityUserAssigned, identities))
+			},
+		},
+		{
+			name: "VMIdentityToVMSDK_user_assigned_without_identities",
+			convert: func() interface{} {
+				return newResult(converters.VMIdentityToVMSDK(infrav1.VMIdentityUserAssigned, nil))
+			},
+		},
+		{
+			name: "UserAssignedIdentitiesToVMSDK",
+			convert: func() interface{} {
+				return newResult(converters.UserAssignedIdentitiesToVMSDK(identities))
+			},
+		},
+		{
+			name: "UserAssignedIdentitiesToVMSDK_without_identities",
+			convert: func() interface{} {
+				return newResult(converters.UserAssignedIdentitiesToVMSDK(nil))
+			},
+		},
+		{
+			name: "UserAssignedIdentitiesToVMSSSDK",
+			convert: func() interface{} {
+				return newResult(converters.UserAssignedIdentitiesToVMSSSDK(identities))
+			},
+		},
+		{
+			name: "MapToTags",
+			convert: func() interface{} {
+				return converters.MapToTags(fixtures.VM().Tags)
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			expectGolden(t, tc.name, tc.convert())
+		})
+	}
+}
+
+func TestSDKImageToImageGolden(t *testing.T) {
+	tests := []struct {
+		name              string
+		imageRef          *armcompute.ImageReference
+		isThirdPartyImage bool
+	}{
+		{
+			name:     "marketplace",
+			imageRef: fixtures.MarketplaceImageReference(),
+		},
+		{
+			name:              "third_party",
+			imageRef:          fixtures.ThirdPartyImageReference(),
+			isThirdPartyImage: true,
+		},
+		{
+			name:     "compute_gallery",
+			imageRef: fixtures.ComputeGalleryImageReference(),
+		},
+		{
+			name:     "community_gallery",
+			imageRef: fixtures.CommunityGalleryImageReference(),
+		},
+		{
+			name:     "shared_gallery",
+			imageRef: fixtures.SharedGalleryImageReference(),
+		},
+		{
+			name:     "empty_id",
+			imageRef: fixtures.EmptyIDImageReference(),
+		},
+		{
+			name:     "malformed_id",
+			imageRef: fixtures.MalformedIDImageReference(),
+		},
+		{
+			name:     "empty",
+			imageRef: &armcompute.ImageReference{},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			strictImage, strictErr := converters.SDKImageToImage(tc.imageRef, tc.isThirdPartyImage)
+			converters.SetStrictConversion(false)
+			defer converters.SetStrictConversion(true)
+			lenientImage, lenientErr := converters.SDKImageToImage(tc.imageRef, tc.isThirdPartyImage)
+
+			expectGolden(t, "SDKImageToImage_"+tc.name, struct {
+				Strict  result `json:"strict"`
+				Lenient result `json:"lenient"`
+			}{
+				Strict:  newResult(strictImage, strictErr),
+				Lenient: newResult(lenientImage, lenientErr),
+			})
+		})
+	}
+}
+
+// TestImageRoundTripGolden converts the images of the specs of AzureMachines to SDK image references, and back to the
+// images recorded in the status of the machine pools, which are compared to the images of the specs to detect new
+// models.
+func TestImageRoundTripGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		image infrav1.Image
+	}{
+		{
+			name: "marketplace",
+			image: infrav1.Image{
+				Marketplace: &infrav1.AzureMarketplaceImage{
+					ImagePlan: infrav1.ImagePlan{Publisher: "cncf-upstream", Offer: "capi", SKU: "ubuntu-2004-gen1"},
+					Version:   "latest",
+				},
+			},
+		},
+		{
+			name: "third_party",
+			image: infrav1.Image{
+				Marketplace: &infrav1.AzureMarketplaceImage{
+					ImagePlan:       infrav1.ImagePlan{Publisher: "kinvolk", Offer: "flatcar-container-linux-free", SKU: "stable-gen2"},
+					Version:         "3139.2.3",
+					ThirdPartyImage: true,
+				},
+			},
+		},
+		{
+			name:  "id",
+			image: infrav1.Image{ID: fixtures.ComputeGalleryImageReference().ID},
+		},
+		{
+			name:  "community_gallery_id",
+			image: infrav1.Image{ID: fixtures.CommunityGalleryImageReference().CommunityGalleryImageID},
+		},
+		{
+			name: "private_compute_gallery",
+			image: infrav1.Image{
+				ComputeGallery: &infrav1.AzureComputeGalleryImage{
+					Gallery:        "capzGallery",
+					Name:           "capi-ubuntu-2004",
+					Version:        "1.23.5",
+					SubscriptionID: to.Ptr(fixtures.SubscriptionID),
+					ResourceGroup:  to.Ptr(fixtures.ResourceGroup),
+				},
+			},
+		},
+		{
+			name: "community_compute_gallery",
+			image: infrav1.Image{
+				ComputeGallery: &infrav1.AzureComputeGalleryImage{
+					Gallery: "capzPublic-1b6e4c8a-2b52-4f1e-9e8f-7f4b0c2a1e3d",
+					Name:    "capi-ubuntu-2004",
+					Version: "1.23.5",
+					Plan: &infrav1.ImagePlan{
+						Publisher: "kinvolk",
+						Offer:     "flatcar-container-linux-free",
+						SKU:       "stable-gen2",
+					},
+				},
+			},
+		},
+		{
+			name: "shared_gallery",
+			image: infrav1.Image{
+				SharedGallery: &infrav1.AzureSharedGalleryImage{
+					SubscriptionID: fixtures.SubscriptionID,
+					ResourceGroup:  fixtures.ResourceGroup,
+					Gallery:        "capzGallery",
+					Name:           "capi-ubuntu-2004",
+					Version:        "1.23.5",
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			imageRef, err := converters.ImageToSDK(&tc.image)
+			g.Expect(err).NotTo(HaveOccurred())
+			plan := converters.ImageToPlan(&tc.image)
+			image, err := converters.SDKImageToImage(imageRef, plan != nil)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			expectGolden(t, "ImageRoundTrip_"+tc.name, struct {
+				ImageReference *armcompute.ImageReference `json:"imageReference"`
+				Plan           *armcompute.Plan           `json:"plan,omitempty"`
+				Image          infrav1.Image              `json:"image"`
+			}{
+				ImageReference: imageRef,
+				Plan:           plan,
+				Image:          image,
+			})
+		})
+	}
+}
+
+func TestTagsRoundTrip(t *testing.T) {
+	g := NewWithT(t)
+
+	tags := converters.MapToTags(fixtures.VMSSWithPlan().Tags)
+	g.Expect(converters.TagsToMap(tags)).To(Equal(fixtures.VMSSWithPlan().Tags))
+	g.Expect(converters.MapToTags(converters.TagsToMap(tags))).To(Equal(tags))
+}
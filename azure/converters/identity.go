@@ -19,7 +19,8 @@ package converters
 import (
 	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -29,10 +30,10 @@ import (
 var ErrUserAssignedIdentitiesNotFound = errors.New("the user-assigned identity provider ids must not be null or empty for 'UserAssigned' identity type")
 
 // VMIdentityToVMSDK converts CAPZ VM identity to Azure SDK identity.
-func VMIdentityToVMSDK(identity infrav1.VMIdentity, uami []infrav1.UserAssignedIdentity) (*compute.VirtualMachineIdentity, error) {
+func VMIdentityToVMSDK(identity infrav1.VMIdentity, uami []infrav1.UserAssignedIdentity) (*armcompute.VirtualMachineIdentity, error) {
 	if identity == infrav1.VMIdentitySystemAssigned {
-		return &compute.VirtualMachineIdentity{
-			Type: compute.ResourceIdentityTypeSystemAssigned,
+		return &armcompute.VirtualMachineIdentity{
+			Type: to.Ptr(armcompute.ResourceIdentityTypeSystemAssigned),
 		}, nil
 	}
 
@@ -42,8 +43,8 @@ func VMIdentityToVMSDK(identity infrav1.VMIdentity, uami []infrav1.UserAssignedI
 			return nil, errors.Wrap(err, "failed to assign VM identity")
 		}
 
-		return &compute.VirtualMachineIdentity{
-			Type:                   compute.ResourceIdentityTypeUserAssigned,
+		return &armcompute.VirtualMachineIdentity{
+			Type:                   to.Ptr(armcompute.ResourceIdentityTypeUserAssigned),
 			UserAssignedIdentities: userIdentitiesMap,
 		}, nil
 	}
@@ -54,14 +55,14 @@ func VMIdentityToVMSDK(identity infrav1.VMIdentity, uami []infrav1.UserAssignedI
 // UserAssignedIdentitiesToVMSDK converts CAPZ user assigned identities associated with the Virtual Machine to Azure SDK identities
 // The user identity dictionary key references will be ARM resource ids in the form:
 // '/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.ManagedIdentity/userAssignedIdentities/{identityName}'.
-func UserAssignedIdentitiesToVMSDK(identities []infrav1.UserAssignedIdentity) (map[string]*compute.VirtualMachineIdentityUserAssignedIdentitiesValue, error) {
+func UserAssignedIdentitiesToVMSDK(identities []infrav1.UserAssignedIdentity) (map[string]*armcompute.UserAssignedIdentitiesValue, error) {
 	if len(identities) == 0 {
 		return nil, ErrUserAssignedIdentitiesNotFound
 	}
-	userIdentitiesMap := make(map[string]*compute.VirtualMachineIdentityUserAssignedIdentitiesValue, len(identities))
+	userIdentitiesMap := make(map[string]*armcompute.UserAssignedIdentitiesValue, len(identities))
 	for _, id := range identities {
 		key := sanitized(id.ProviderID)
-		userIdentitiesMap[key] = &compute.VirtualMachineIdentityUserAssignedIdentitiesValue{}
+		userIdentitiesMap[key] = &armcompute.UserAssignedIdentitiesValue{}
 	}
 
 	return userIdentitiesMap, nil
@@ -69,14 +70,14 @@ func UserAssignedIdentitiesToVMSDK(identities []infrav1.UserAssignedIdentity) (m
 
 // UserAssignedIdentitiesToVMSSSDK converts CAPZ user assigned identities associated with the Virtual Machine Scale Set to Azure SDK identities
 // Similar to UserAssignedIdentitiesToVMSDK.
-func UserAssignedIdentitiesToVMSSSDK(identities []infrav1.UserAssignedIdentity) (map[string]*compute.VirtualMachineScaleSetIdentityUserAssignedIdentitiesValue, error) {
+func UserAssignedIdentitiesToVMSSSDK(identities []infrav1.UserAssignedIdentity) (map[string]*armcompute.UserAssignedIdentitiesValue, error) {
 	if len(identities) == 0 {
 		return nil, ErrUserAssignedIdentitiesNotFound
 	}
-	userIdentitiesMap := make(map[string]*compute.VirtualMachineScaleSetIdentityUserAssignedIdentitiesValue, len(identities))
+	userIdentitiesMap := make(map[string]*armcompute.UserAssignedIdentitiesValue, len(identities))
 	for _, id := range identities {
 		key := sanitized(id.ProviderID)
-		userIdentitiesMap[key] = &compute.VirtualMachineScaleSetIdentityUserAssignedIdentitiesValue{}
+		userIdentitiesMap[key] = &armcompute.UserAssignedIdentitiesValue{}
 	}
 
 	return userIdentitiesMap, nil
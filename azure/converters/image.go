@@ -18,15 +18,17 @@ package converters
 
 import (
 	"fmt"
+	"k8s.io/utils/pointer"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 )
 
 // ImageToSDK converts a CAPZ Image (as RawExtension) to a Azure SDK Image Reference.
-func ImageToSDK(image *infrav1.Image) (*compute.ImageReference, error) {
+func ImageToSDK(image *infrav1.Image) (*armcompute.ImageReference, error) {
 	if image.ID != nil {
 		return specificImageToSDK(image)
 	}
@@ -40,21 +42,21 @@ func ImageToSDK(image *infrav1.Image) (*compute.ImageReference, error) {
 	return nil, errors.New("unable to convert image as no options set")
 }
 
-func mpImageToSDK(image *infrav1.Image) (*compute.ImageReference, error) {
-	return &compute.ImageReference{
+func mpImageToSDK(image *infrav1.Image) (*armcompute.ImageReference, error) {
+	return &armcompute.ImageReference{
 		Publisher: &image.Marketplace.Publisher,
 		Offer:     &image.Marketplace.Offer,
-		Sku:       &image.Marketplace.SKU,
+		SKU:       &image.Marketplace.SKU,
 		Version:   &image.Marketplace.Version,
 	}, nil
 }
 
-func computeImageToSDK(image *infrav1.Image) (*compute.ImageReference, error) {
+func computeImageToSDK(image *infrav1.Image) (*armcompute.ImageReference, error) {
 	idTemplate := "/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/galleries/%s/images/%s/versions/%s"
 
 	if image.SharedGallery != nil {
-		return &compute.ImageReference{
-			ID: to.StringPtr(fmt.Sprintf(idTemplate,
+		return &armcompute.ImageReference{
+			ID: to.Ptr(fmt.Sprintf(idTemplate,
 				image.SharedGallery.SubscriptionID,
 				image.SharedGallery.ResourceGroup,
 				image.SharedGallery.Gallery,
@@ -67,10 +69,10 @@ func computeImageToSDK(image *infrav1.Image) (*compute.ImageReference, error) {
 	// For private Azure Compute Gallery consumption both resource group and subscription ID must be provided.
 	// If they are not, we assume use of community gallery.
 	if image.ComputeGallery.ResourceGroup != nil && image.ComputeGallery.SubscriptionID != nil {
-		return &compute.ImageReference{
-			ID: to.StringPtr(fmt.Sprintf(idTemplate,
-				image.ComputeGallery.SubscriptionID,
-				image.ComputeGallery.ResourceGroup,
+		return &armcompute.ImageReference{
+			ID: to.Ptr(fmt.Sprintf(idTemplate,
+				pointer.StringDeref(image.ComputeGallery.SubscriptionID, ""),
+				pointer.StringDeref(image.ComputeGallery.ResourceGroup, ""),
 				image.ComputeGallery.Gallery,
 				image.ComputeGallery.Name,
 				image.ComputeGallery.Version,
@@ -78,25 +80,39 @@ func computeImageToSDK(image *infrav1.Image) (*compute.ImageReference, error) {
 		}, nil
 	}
 
-	return &compute.ImageReference{
-		CommunityGalleryImageID: to.StringPtr(fmt.Sprintf("/CommunityGalleries/%s/Images/%s/Versions/%s",
+	return &armcompute.ImageReference{
+		CommunityGalleryImageID: to.Ptr(fmt.Sprintf("/CommunityGalleries/%s/Images/%s/Versions/%s",
 			image.ComputeGallery.Gallery,
 			image.ComputeGallery.Name,
 			image.ComputeGallery.Version)),
 	}, nil
 }
 
-func specificImageToSDK(image *infrav1.Image) (*compute.ImageReference, error) {
-	return &compute.ImageReference{
+func specificImageToSDK(image *infrav1.Image) (*armcompute.ImageReference, error) {
+	// Community and shared gallery image IDs aren't ARM resource IDs and are set in their own fields.
+	if resourceID, err := azure.ParseResourceID(*image.ID); err == nil {
+		switch {
+		case azure.IsResourceType(resourceID, azure.CommunityGalleryImageVersionResourceType):
+			return &armcompute.ImageReference{
+				CommunityGalleryImageID: image.ID,
+			}, nil
+		case azure.IsResourceType(resourceID, azure.SharedGalleryImageVersionResourceType):
+			return &armcompute.ImageReference{
+				SharedGalleryImageID: image.ID,
+			}, nil
+		}
+	}
+
+	return &armcompute.ImageReference{
 		ID: image.ID,
 	}, nil
 }
 
 // ImageToPlan converts a CAPZ Image to an Azure Compute Plan.
-func ImageToPlan(image *infrav1.Image) *compute.Plan {
+func ImageToPlan(image *infrav1.Image) *armcompute.Plan {
 	// Plan is needed when using a Shared Gallery image with Plan details.
 	if image.SharedGallery != nil && image.SharedGallery.Publisher != nil && image.SharedGallery.SKU != nil && image.SharedGallery.Offer != nil {
-		return &compute.Plan{
+		return &armcompute.Plan{
 			Publisher: image.SharedGallery.Publisher,
 			Name:      image.SharedGallery.SKU,
 			Product:   image.SharedGallery.Offer,
@@ -105,19 +121,19 @@ func ImageToPlan(image *infrav1.Image) *compute.Plan {
 
 	// Plan is needed for third party Marketplace images.
 	if image.Marketplace != nil && image.Marketplace.ThirdPartyImage {
-		return &compute.Plan{
-			Publisher: to.StringPtr(image.Marketplace.Publisher),
-			Name:      to.StringPtr(image.Marketplace.SKU),
-			Product:   to.StringPtr(image.Marketplace.Offer),
+		return &armcompute.Plan{
+			Publisher: to.Ptr(image.Marketplace.Publisher),
+			Name:      to.Ptr(image.Marketplace.SKU),
+			Product:   to.Ptr(image.Marketplace.Offer),
 		}
 	}
 
 	// Plan is needed when using a Azure Compute Gallery image with Plan details.
 	if image.ComputeGallery != nil && image.ComputeGallery.Plan != nil {
-		return &compute.Plan{
-			Publisher: to.StringPtr(image.ComputeGallery.Plan.Publisher),
-			Name:      to.StringPtr(image.ComputeGallery.Plan.SKU),
-			Product:   to.StringPtr(image.ComputeGallery.Plan.Offer),
+		return &armcompute.Plan{
+			Publisher: to.Ptr(image.ComputeGallery.Plan.Publisher),
+			Name:      to.Ptr(image.ComputeGallery.Plan.SKU),
+			Product:   to.Ptr(image.ComputeGallery.Plan.Offer),
 		}
 	}
 
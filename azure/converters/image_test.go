@@ -19,8 +19,8 @@ package converters
 import (
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
@@ -29,7 +29,7 @@ func Test_ImageToPlan(t *testing.T) {
 	cases := []struct {
 		name   string
 		image  *infrav1.Image
-		expect func(*GomegaWithT, *compute.Plan)
+		expect func(*GomegaWithT, *armcompute.Plan)
 	}{
 		{
 			name: "Should return a plan for a Community Gallery image with plan details",
@@ -42,11 +42,11 @@ func Test_ImageToPlan(t *testing.T) {
 					},
 				},
 			},
-			expect: func(g *GomegaWithT, result *compute.Plan) {
-				g.Expect(result).To(Equal(&compute.Plan{
-					Name:      to.StringPtr("my-sku"),
-					Publisher: to.StringPtr("my-publisher"),
-					Product:   to.StringPtr("my-offer"),
+			expect: func(g *GomegaWithT, result *armcompute.Plan) {
+				g.Expect(result).To(Equal(&armcompute.Plan{
+					Name:      to.Ptr("my-sku"),
+					Publisher: to.Ptr("my-publisher"),
+					Product:   to.Ptr("my-offer"),
 				}))
 			},
 		},
@@ -59,16 +59,16 @@ func Test_ImageToPlan(t *testing.T) {
 					Gallery:        "fake-gallery-name",
 					Name:           "fake-image-name",
 					Version:        "v1.0.0",
-					Publisher:      to.StringPtr("my-publisher"),
-					Offer:          to.StringPtr("my-offer"),
-					SKU:            to.StringPtr("my-sku"),
+					Publisher:      to.Ptr("my-publisher"),
+					Offer:          to.Ptr("my-offer"),
+					SKU:            to.Ptr("my-sku"),
 				},
 			},
-			expect: func(g *GomegaWithT, result *compute.Plan) {
-				g.Expect(result).To(Equal(&compute.Plan{
-					Name:      to.StringPtr("my-sku"),
-					Publisher: to.StringPtr("my-publisher"),
-					Product:   to.StringPtr("my-offer"),
+			expect: func(g *GomegaWithT, result *armcompute.Plan) {
+				g.Expect(result).To(Equal(&armcompute.Plan{
+					Name:      to.Ptr("my-sku"),
+					Publisher: to.Ptr("my-publisher"),
+					Product:   to.Ptr("my-offer"),
 				}))
 			},
 		},
@@ -83,7 +83,7 @@ func Test_ImageToPlan(t *testing.T) {
 					Version:        "v1.0.0",
 				},
 			},
-			expect: func(g *GomegaWithT, result *compute.Plan) {
+			expect: func(g *GomegaWithT, result *armcompute.Plan) {
 				g.Expect(result).To(BeNil())
 			},
 		},
@@ -100,7 +100,7 @@ func Test_ImageToPlan(t *testing.T) {
 					ThirdPartyImage: false,
 				},
 			},
-			expect: func(g *GomegaWithT, result *compute.Plan) {
+			expect: func(g *GomegaWithT, result *armcompute.Plan) {
 				g.Expect(result).To(BeNil())
 			},
 		},
@@ -117,20 +117,20 @@ func Test_ImageToPlan(t *testing.T) {
 					ThirdPartyImage: true,
 				},
 			},
-			expect: func(g *GomegaWithT, result *compute.Plan) {
-				g.Expect(result).To(Equal(&compute.Plan{
-					Name:      to.StringPtr("my-sku"),
-					Publisher: to.StringPtr("my-publisher"),
-					Product:   to.StringPtr("my-offer"),
+			expect: func(g *GomegaWithT, result *armcompute.Plan) {
+				g.Expect(result).To(Equal(&armcompute.Plan{
+					Name:      to.Ptr("my-sku"),
+					Publisher: to.Ptr("my-publisher"),
+					Product:   to.Ptr("my-offer"),
 				}))
 			},
 		},
 		{
 			name: "Should return nil for an image ID",
 			image: &infrav1.Image{
-				ID: to.StringPtr("fake/image/id"),
+				ID: to.Ptr("fake/image/id"),
 			},
-			expect: func(g *GomegaWithT, result *compute.Plan) {
+			expect: func(g *GomegaWithT, result *armcompute.Plan) {
 				g.Expect(result).To(BeNil())
 			},
 		},
@@ -146,3 +146,59 @@ func Test_ImageToPlan(t *testing.T) {
 		})
 	}
 }
+
+func Test_ImageToSDK(t *testing.T) {
+	cases := []struct {
+		name   string
+		image  *infrav1.Image
+		expect *armcompute.ImageReference
+	}{
+		{
+			name: "Should set the ID of a managed image",
+			image: &infrav1.Image{
+				ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/my-image"),
+			},
+			expect: &armcompute.ImageReference{
+				ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/images/my-image"),
+			},
+		},
+		{
+			name: "Should set the community gallery image ID of a community gallery image",
+			image: &infrav1.Image{
+				ID: to.Ptr("/CommunityGalleries/my-gallery/Images/my-image/Versions/1.0.0"),
+			},
+			expect: &armcompute.ImageReference{
+				CommunityGalleryImageID: to.Ptr("/CommunityGalleries/my-gallery/Images/my-image/Versions/1.0.0"),
+			},
+		},
+		{
+			name: "Should set the shared gallery image ID of a shared gallery image",
+			image: &infrav1.Image{
+				ID: to.Ptr("/sharedGalleries/my-gallery/images/my-image/versions/1.0.0"),
+			},
+			expect: &armcompute.ImageReference{
+				SharedGalleryImageID: to.Ptr("/sharedGalleries/my-gallery/images/my-image/versions/1.0.0"),
+			},
+		},
+		{
+			name: "Should set an ID which isn't a resource ID",
+			image: &infrav1.Image{
+				ID: to.Ptr("fake/image/id"),
+			},
+			expect: &armcompute.ImageReference{
+				ID: to.Ptr("fake/image/id"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+			result, err := ImageToSDK(c.image)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result).To(Equal(c.expect))
+		})
+	}
+}
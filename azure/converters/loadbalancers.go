@@ -17,14 +17,23 @@ limitations under the License.
 package converters
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
-// SKUtoSDK converts infrav1.SKU into a network.LoadBalancerSkuName.
-func SKUtoSDK(src infrav1.SKU) network.LoadBalancerSkuName {
+// SKUtoSDK converts infrav1.SKU into an armnetwork.LoadBalancerSKUName.
+func SKUtoSDK(src infrav1.SKU) *armnetwork.LoadBalancerSKUName {
 	if src == infrav1.SKUStandard {
-		return network.LoadBalancerSkuNameStandard
+		return to.Ptr(armnetwork.LoadBalancerSKUNameStandard)
 	}
-	return ""
+	return nil
+}
+
+// SKUTierToSDK converts infrav1.SKUTier into an armnetwork.LoadBalancerSKUTier. Load balancers without a tier are Regional.
+func SKUTierToSDK(src infrav1.SKUTier) *armnetwork.LoadBalancerSKUTier {
+	if src == infrav1.SKUTierGlobal {
+		return to.Ptr(armnetwork.LoadBalancerSKUTierGlobal)
+	}
+	return nil
 }
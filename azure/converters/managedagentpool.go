@@ -17,56 +17,104 @@ limitations under the License.
 package converters
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 )
 
 // AgentPoolToManagedClusterAgentPoolProfile converts a AgentPoolSpec to an Azure SDK ManagedClusterAgentPoolProfile used in managedcluster reconcile.
-func AgentPoolToManagedClusterAgentPoolProfile(pool azure.AgentPoolSpec) containerservice.ManagedClusterAgentPoolProfile {
-	return containerservice.ManagedClusterAgentPoolProfile{
-		Name:                &pool.Name,
-		VMSize:              &pool.SKU,
-		OsType:              containerservice.OSType(to.String(pool.OSType)),
-		OsDiskSizeGB:        &pool.OSDiskSizeGB,
-		Count:               &pool.Replicas,
-		Type:                containerservice.AgentPoolTypeVirtualMachineScaleSets,
+func AgentPoolToManagedClusterAgentPoolProfile(pool azure.AgentPoolSpec) armcontainerservice.ManagedClusterAgentPoolProfile {
+	return armcontainerservice.ManagedClusterAgentPoolProfile{
+		Name:                to.Ptr(pool.Name),
+		VMSize:              to.Ptr(pool.SKU),
+		OSType:              osType(pool.OSType),
+		OSDiskSizeGB:        to.Ptr(pool.OSDiskSizeGB),
+		Count:               to.Ptr(pool.Replicas),
+		Type:                to.Ptr(armcontainerservice.AgentPoolTypeVirtualMachineScaleSets),
 		OrchestratorVersion: pool.Version,
-		VnetSubnetID:        &pool.VnetSubnetID,
-		Mode:                containerservice.AgentPoolMode(pool.Mode),
+		VnetSubnetID:        to.Ptr(pool.VnetSubnetID),
+		Mode:                agentPoolMode(pool.Mode),
 		EnableAutoScaling:   pool.EnableAutoScaling,
 		MaxCount:            pool.MaxCount,
 		MinCount:            pool.MinCount,
-		NodeTaints:          &pool.NodeTaints,
-		AvailabilityZones:   &pool.AvailabilityZones,
+		NodeTaints:          to.SliceOfPtrs(pool.NodeTaints...),
+		AvailabilityZones:   to.SliceOfPtrs(pool.AvailabilityZones...),
 		MaxPods:             pool.MaxPods,
-		OsDiskType:          containerservice.OSDiskType(to.String(pool.OsDiskType)),
+		OSDiskType:          osDiskType(pool.OsDiskType),
 		NodeLabels:          pool.NodeLabels,
 		EnableUltraSSD:      pool.EnableUltraSSD,
 	}
 }
 
 // AgentPoolToContainerServiceAgentPool converts a AgentPoolSpec to an Azure SDK AgentPool used in agentpool reconcile.
-func AgentPoolToContainerServiceAgentPool(pool azure.AgentPoolSpec) containerservice.AgentPool {
-	return containerservice.AgentPool{
-		ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
-			VMSize:              &pool.SKU,
-			OsType:              containerservice.OSType(to.String(pool.OSType)),
-			OsDiskSizeGB:        &pool.OSDiskSizeGB,
-			Count:               &pool.Replicas,
-			Type:                containerservice.AgentPoolTypeVirtualMachineScaleSets,
+func AgentPoolToContainerServiceAgentPool(pool azure.AgentPoolSpec) armcontainerservice.AgentPool {
+	return armcontainerservice.AgentPool{
+		Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+			VMSize:              to.Ptr(pool.SKU),
+			OSType:              osType(pool.OSType),
+			OSDiskSizeGB:        to.Ptr(pool.OSDiskSizeGB),
+			Count:               to.Ptr(pool.Replicas),
+			Type:                to.Ptr(armcontainerservice.AgentPoolTypeVirtualMachineScaleSets),
 			OrchestratorVersion: pool.Version,
-			VnetSubnetID:        &pool.VnetSubnetID,
-			Mode:                containerservice.AgentPoolMode(pool.Mode),
+			VnetSubnetID:        to.Ptr(pool.VnetSubnetID),
+			Mode:                agentPoolMode(pool.Mode),
 			EnableAutoScaling:   pool.EnableAutoScaling,
 			MaxCount:            pool.MaxCount,
 			MinCount:            pool.MinCount,
-			NodeTaints:          &pool.NodeTaints,
-			AvailabilityZones:   &pool.AvailabilityZones,
+			NodeTaints:          to.SliceOfPtrs(pool.NodeTaints...),
+			AvailabilityZones:   to.SliceOfPtrs(pool.AvailabilityZones...),
 			MaxPods:             pool.MaxPods,
-			OsDiskType:          containerservice.OSDiskType(to.String(pool.OsDiskType)),
+			OSDiskType:          osDiskType(pool.OsDiskType),
 			NodeLabels:          pool.NodeLabels,
 			EnableUltraSSD:      pool.EnableUltraSSD,
 		},
 	}
 }
+
+// agentPoolMode returns the mode of an agent pool, or nil to let AKS default it.
+func agentPoolMode(mode string) *armcontainerservice.AgentPoolMode {
+	if mode == "" {
+		return nil
+	}
+	return to.Ptr(armcontainerservice.AgentPoolMode(mode))
+}
+
+// osType returns the OS type of an agent pool, or nil to let AKS default it.
+func osType(t *string) *armcontainerservice.OSType {
+	if t == nil {
+		return nil
+	}
+	return to.Ptr(armcontainerservice.OSType(*t))
+}
+
+// osDiskType returns the OS disk type of an agent pool, or nil to let AKS default it.
+func osDiskType(t *string) *armcontainerservice.OSDiskType {
+	if t == nil {
+		return nil
+	}
+	return to.Ptr(armcontainerservice.OSDiskType(*t))
+}
+
+// SDKToAgentPoolStatus converts an Azure SDK AgentPool to the status of an AzureManagedMachinePool.
+func SDKToAgentPoolStatus(agentPool armcontainerservice.AgentPool) *infrav1exp.AgentPoolStatus {
+	status := &infrav1exp.AgentPoolStatus{
+		Name: pointer.StringDeref(agentPool.Name, ""),
+	}
+
+	properties := agentPool.Properties
+	if properties == nil {
+		return status
+	}
+
+	status.ProvisioningState = pointer.StringDeref(properties.ProvisioningState, "")
+	if properties.PowerState != nil && properties.PowerState.Code != nil {
+		status.PowerState = string(*properties.PowerState.Code)
+	}
+	status.Count = properties.Count
+	status.KubernetesVersion = pointer.StringDeref(properties.OrchestratorVersion, "")
+	status.NodeImageVersion = pointer.StringDeref(properties.NodeImageVersion, "")
+
+	return status
+}
@@ -19,17 +19,18 @@ package converters
 import (
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 )
 
 func Test_AgentPoolToManagedClusterAgentPoolProfile(t *testing.T) {
 	cases := []struct {
 		name   string
 		pool   azure.AgentPoolSpec
-		expect func(*GomegaWithT, containerservice.ManagedClusterAgentPoolProfile)
+		expect func(*GomegaWithT, armcontainerservice.ManagedClusterAgentPoolProfile)
 	}{
 		{
 			name: "Should set all values correctly",
@@ -37,43 +38,43 @@ func Test_AgentPoolToManagedClusterAgentPoolProfile(t *testing.T) {
 				SKU:               "Standard_D2s_v3",
 				OSDiskSizeGB:      100,
 				Replicas:          2,
-				OSType:            to.StringPtr(azure.LinuxOS),
-				Version:           to.StringPtr("1.22.6"),
+				OSType:            to.Ptr(azure.LinuxOS),
+				Version:           to.Ptr("1.22.6"),
 				VnetSubnetID:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-123/providers/Microsoft.Network/virtualNetworks/vnet-123/subnets/subnet-123",
 				Mode:              "User",
-				EnableAutoScaling: to.BoolPtr(true),
-				MaxCount:          to.Int32Ptr(5),
-				MinCount:          to.Int32Ptr(2),
+				EnableAutoScaling: to.Ptr(true),
+				MaxCount:          to.Ptr[int32](5),
+				MinCount:          to.Ptr[int32](2),
 				NodeTaints:        []string{"key1=value1:NoSchedule"},
 				AvailabilityZones: []string{"zone1"},
-				MaxPods:           to.Int32Ptr(60),
-				OsDiskType:        to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+				MaxPods:           to.Ptr[int32](60),
+				OsDiskType:        to.Ptr(string(armcontainerservice.OSDiskTypeManaged)),
 				NodeLabels: map[string]*string{
-					"custom": to.StringPtr("default"),
+					"custom": to.Ptr("default"),
 				},
 				Name: "agentpool1",
 			},
 
-			expect: func(g *GomegaWithT, result containerservice.ManagedClusterAgentPoolProfile) {
-				g.Expect(result).To(Equal(containerservice.ManagedClusterAgentPoolProfile{
-					Name:                to.StringPtr("agentpool1"),
-					VMSize:              to.StringPtr("Standard_D2s_v3"),
-					OsType:              azure.LinuxOS,
-					OsDiskSizeGB:        to.Int32Ptr(100),
-					Count:               to.Int32Ptr(2),
-					Type:                containerservice.AgentPoolTypeVirtualMachineScaleSets,
-					OrchestratorVersion: to.StringPtr("1.22.6"),
-					VnetSubnetID:        to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-123/providers/Microsoft.Network/virtualNetworks/vnet-123/subnets/subnet-123"),
-					Mode:                containerservice.AgentPoolModeUser,
-					EnableAutoScaling:   to.BoolPtr(true),
-					MaxCount:            to.Int32Ptr(5),
-					MinCount:            to.Int32Ptr(2),
-					NodeTaints:          to.StringSlicePtr([]string{"key1=value1:NoSchedule"}),
-					AvailabilityZones:   to.StringSlicePtr([]string{"zone1"}),
-					MaxPods:             to.Int32Ptr(60),
-					OsDiskType:          containerservice.OSDiskTypeManaged,
+			expect: func(g *GomegaWithT, result armcontainerservice.ManagedClusterAgentPoolProfile) {
+				g.Expect(result).To(Equal(armcontainerservice.ManagedClusterAgentPoolProfile{
+					Name:                to.Ptr("agentpool1"),
+					VMSize:              to.Ptr("Standard_D2s_v3"),
+					OSType:              to.Ptr(armcontainerservice.OSTypeLinux),
+					OSDiskSizeGB:        to.Ptr[int32](100),
+					Count:               to.Ptr[int32](2),
+					Type:                to.Ptr(armcontainerservice.AgentPoolTypeVirtualMachineScaleSets),
+					OrchestratorVersion: to.Ptr("1.22.6"),
+					VnetSubnetID:        to.Ptr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-123/providers/Microsoft.Network/virtualNetworks/vnet-123/subnets/subnet-123"),
+					Mode:                to.Ptr(armcontainerservice.AgentPoolModeUser),
+					EnableAutoScaling:   to.Ptr(true),
+					MaxCount:            to.Ptr[int32](5),
+					MinCount:            to.Ptr[int32](2),
+					NodeTaints:          []*string{to.Ptr("key1=value1:NoSchedule")},
+					AvailabilityZones:   []*string{to.Ptr("zone1")},
+					MaxPods:             to.Ptr[int32](60),
+					OSDiskType:          to.Ptr(armcontainerservice.OSDiskTypeManaged),
 					NodeLabels: map[string]*string{
-						"custom": to.StringPtr("default"),
+						"custom": to.Ptr("default"),
 					},
 				}))
 			},
@@ -95,7 +96,7 @@ func Test_AgentPoolToAgentPoolToContainerServiceAgentPool(t *testing.T) {
 	cases := []struct {
 		name   string
 		pool   azure.AgentPoolSpec
-		expect func(*GomegaWithT, containerservice.AgentPool)
+		expect func(*GomegaWithT, armcontainerservice.AgentPool)
 	}{
 		{
 			name: "Should set all values correctly",
@@ -103,43 +104,43 @@ func Test_AgentPoolToAgentPoolToContainerServiceAgentPool(t *testing.T) {
 				Name:              "agentpool1",
 				SKU:               "Standard_D2s_v3",
 				OSDiskSizeGB:      100,
-				OSType:            to.StringPtr(azure.LinuxOS),
+				OSType:            to.Ptr(azure.LinuxOS),
 				Replicas:          2,
-				Version:           to.StringPtr("1.22.6"),
+				Version:           to.Ptr("1.22.6"),
 				VnetSubnetID:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-123/providers/Microsoft.Network/virtualNetworks/vnet-123/subnets/subnet-123",
 				Mode:              "User",
-				EnableAutoScaling: to.BoolPtr(true),
-				MaxCount:          to.Int32Ptr(5),
-				MinCount:          to.Int32Ptr(2),
+				EnableAutoScaling: to.Ptr(true),
+				MaxCount:          to.Ptr[int32](5),
+				MinCount:          to.Ptr[int32](2),
 				NodeTaints:        []string{"key1=value1:NoSchedule"},
 				AvailabilityZones: []string{"zone1"},
-				MaxPods:           to.Int32Ptr(60),
-				OsDiskType:        to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+				MaxPods:           to.Ptr[int32](60),
+				OsDiskType:        to.Ptr(string(armcontainerservice.OSDiskTypeManaged)),
 				NodeLabels: map[string]*string{
-					"custom": to.StringPtr("default"),
+					"custom": to.Ptr("default"),
 				},
 			},
 
-			expect: func(g *GomegaWithT, result containerservice.AgentPool) {
-				g.Expect(result).To(Equal(containerservice.AgentPool{
-					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
-						VMSize:              to.StringPtr("Standard_D2s_v3"),
-						OsType:              azure.LinuxOS,
-						OsDiskSizeGB:        to.Int32Ptr(100),
-						Count:               to.Int32Ptr(2),
-						Type:                containerservice.AgentPoolTypeVirtualMachineScaleSets,
-						OrchestratorVersion: to.StringPtr("1.22.6"),
-						VnetSubnetID:        to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-123/providers/Microsoft.Network/virtualNetworks/vnet-123/subnets/subnet-123"),
-						Mode:                containerservice.AgentPoolModeUser,
-						EnableAutoScaling:   to.BoolPtr(true),
-						MaxCount:            to.Int32Ptr(5),
-						MinCount:            to.Int32Ptr(2),
-						NodeTaints:          to.StringSlicePtr([]string{"key1=value1:NoSchedule"}),
-						AvailabilityZones:   to.StringSlicePtr([]string{"zone1"}),
-						MaxPods:             to.Int32Ptr(60),
-						OsDiskType:          containerservice.OSDiskTypeManaged,
+			expect: func(g *GomegaWithT, result armcontainerservice.AgentPool) {
+				g.Expect(result).To(Equal(armcontainerservice.AgentPool{
+					Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+						VMSize:              to.Ptr("Standard_D2s_v3"),
+						OSType:              to.Ptr(armcontainerservice.OSTypeLinux),
+						OSDiskSizeGB:        to.Ptr[int32](100),
+						Count:               to.Ptr[int32](2),
+						Type:                to.Ptr(armcontainerservice.AgentPoolTypeVirtualMachineScaleSets),
+						OrchestratorVersion: to.Ptr("1.22.6"),
+						VnetSubnetID:        to.Ptr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-123/providers/Microsoft.Network/virtualNetworks/vnet-123/subnets/subnet-123"),
+						Mode:                to.Ptr(armcontainerservice.AgentPoolModeUser),
+						EnableAutoScaling:   to.Ptr(true),
+						MaxCount:            to.Ptr[int32](5),
+						MinCount:            to.Ptr[int32](2),
+						NodeTaints:          []*string{to.Ptr("key1=value1:NoSchedule")},
+						AvailabilityZones:   []*string{to.Ptr("zone1")},
+						MaxPods:             to.Ptr[int32](60),
+						OSDiskType:          to.Ptr(armcontainerservice.OSDiskTypeManaged),
 						NodeLabels: map[string]*string{
-							"custom": to.StringPtr("default"),
+							"custom": to.Ptr("default"),
 						},
 					},
 				}))
@@ -157,3 +158,56 @@ func Test_AgentPoolToAgentPoolToContainerServiceAgentPool(t *testing.T) {
 		})
 	}
 }
+
+func Test_SDKToAgentPoolStatus(t *testing.T) {
+	cases := []struct {
+		name   string
+		pool   armcontainerservice.AgentPool
+		expect func(*GomegaWithT, *infrav1exp.AgentPoolStatus)
+	}{
+		{
+			name: "Should set all values correctly",
+			pool: armcontainerservice.AgentPool{
+				Name: to.Ptr("agentpool1"),
+				Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+					Count:               to.Ptr[int32](3),
+					OrchestratorVersion: to.Ptr("1.22.6"),
+					NodeImageVersion:    to.Ptr("AKSUbuntu-1804gen2containerd-2022.06.08"),
+					ProvisioningState:   to.Ptr("Succeeded"),
+					PowerState:          &armcontainerservice.PowerState{Code: to.Ptr(armcontainerservice.CodeRunning)},
+				},
+			},
+			expect: func(g *GomegaWithT, result *infrav1exp.AgentPoolStatus) {
+				g.Expect(result).To(Equal(&infrav1exp.AgentPoolStatus{
+					Name:              "agentpool1",
+					ProvisioningState: "Succeeded",
+					PowerState:        "Running",
+					Count:             to.Ptr[int32](3),
+					KubernetesVersion: "1.22.6",
+					NodeImageVersion:  "AKSUbuntu-1804gen2containerd-2022.06.08",
+				}))
+			},
+		},
+		{
+			name: "Should only set the name without properties",
+			pool: armcontainerservice.AgentPool{
+				Name: to.Ptr("agentpool1"),
+			},
+			expect: func(g *GomegaWithT, result *infrav1exp.AgentPoolStatus) {
+				g.Expect(result).To(Equal(&infrav1exp.AgentPoolStatus{
+					Name: "agentpool1",
+				}))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+			result := SDKToAgentPoolStatus(c.pool)
+			c.expect(g, result)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
+	"k8s.io/utils/pointer"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+)
+
+// kubeletIdentityKey is the key of the identity of the kubelets in the identity profile of a managed cluster.
+const kubeletIdentityKey = "kubeletidentity"
+
+// SDKToManagedClusterStatus converts an Azure SDK ManagedCluster to the status of an AzureManagedControlPlane.
+func SDKToManagedClusterStatus(managedCluster armcontainerservice.ManagedCluster) *infrav1exp.ManagedClusterStatus {
+	status := &infrav1exp.ManagedClusterStatus{}
+
+	if managedCluster.Identity != nil {
+		status.IdentityPrincipalID = pointer.StringDeref(managedCluster.Identity.PrincipalID, "")
+	}
+
+	properties := managedCluster.Properties
+	if properties == nil {
+		return status
+	}
+
+	status.ProvisioningState = pointer.StringDeref(properties.ProvisioningState, "")
+	if properties.PowerState != nil && properties.PowerState.Code != nil {
+		status.PowerState = string(*properties.PowerState.Code)
+	}
+	status.KubernetesVersion = pointer.StringDeref(properties.KubernetesVersion, "")
+	status.FQDN = pointer.StringDeref(properties.Fqdn, "")
+	status.PrivateFQDN = pointer.StringDeref(properties.PrivateFQDN, "")
+
+	if kubeletIdentity := properties.IdentityProfile[kubeletIdentityKey]; kubeletIdentity != nil {
+		status.KubeletIdentityClientID = pointer.StringDeref(kubeletIdentity.ClientID, "")
+		status.KubeletIdentityObjectID = pointer.StringDeref(kubeletIdentity.ObjectID, "")
+	}
+
+	for _, profile := range properties.AgentPoolProfiles {
+		if profile == nil {
+			continue
+		}
+		agentPool := infrav1exp.AgentPoolStatus{
+			Name:              pointer.StringDeref(profile.Name, ""),
+			ProvisioningState: pointer.StringDeref(profile.ProvisioningState, ""),
+			Count:             profile.Count,
+			KubernetesVersion: pointer.StringDeref(profile.OrchestratorVersion, ""),
+			NodeImageVersion:  pointer.StringDeref(profile.NodeImageVersion, ""),
+		}
+		if profile.PowerState != nil && profile.PowerState.Code != nil {
+			agentPool.PowerState = string(*profile.PowerState.Code)
+		}
+		status.AgentPools = append(status.AgentPools, agentPool)
+	}
+
+	return status
+}
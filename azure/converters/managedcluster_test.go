@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
+	. "github.com/onsi/gomega"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+)
+
+func Test_SDKToManagedClusterStatus(t *testing.T) {
+	cases := []struct {
+		name           string
+		managedCluster armcontainerservice.ManagedCluster
+		expect         func(*GomegaWithT, *infrav1exp.ManagedClusterStatus)
+	}{
+		{
+			name: "Should set all values correctly",
+			managedCluster: armcontainerservice.ManagedCluster{
+				Identity: &armcontainerservice.ManagedClusterIdentity{
+					Type:        to.Ptr(armcontainerservice.ResourceIdentityTypeSystemAssigned),
+					PrincipalID: to.Ptr("00000000-0000-0000-0000-000000000001"),
+				},
+				Properties: &armcontainerservice.ManagedClusterProperties{
+					ProvisioningState: to.Ptr("Succeeded"),
+					PowerState:        &armcontainerservice.PowerState{Code: to.Ptr(armcontainerservice.CodeRunning)},
+					KubernetesVersion: to.Ptr("1.22.6"),
+					Fqdn:              to.Ptr("my-cluster-dns.hcp.eastus.azmk8s.io"),
+					PrivateFQDN:       to.Ptr("my-cluster-dns.privatelink.eastus.azmk8s.io"),
+					IdentityProfile: map[string]*armcontainerservice.UserAssignedIdentity{
+						"kubeletidentity": {
+							ClientID: to.Ptr("00000000-0000-0000-0000-000000000002"),
+							ObjectID: to.Ptr("00000000-0000-0000-0000-000000000003"),
+						},
+					},
+					AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+						{
+							Name:                to.Ptr("agentpool1"),
+							Count:               to.Ptr[int32](3),
+							OrchestratorVersion: to.Ptr("1.22.6"),
+							NodeImageVersion:    to.Ptr("AKSUbuntu-1804gen2containerd-2022.06.08"),
+							ProvisioningState:   to.Ptr("Succeeded"),
+							PowerState:          &armcontainerservice.PowerState{Code: to.Ptr(armcontainerservice.CodeRunning)},
+						},
+						{
+							Name:              to.Ptr("agentpool2"),
+							Count:             to.Ptr[int32](0),
+							ProvisioningState: to.Ptr("Updating"),
+						},
+					},
+				},
+			},
+			expect: func(g *GomegaWithT, result *infrav1exp.ManagedClusterStatus) {
+				g.Expect(result).To(Equal(&infrav1exp.ManagedClusterStatus{
+					ProvisioningState:       "Succeeded",
+					PowerState:              "Running",
+					KubernetesVersion:       "1.22.6",
+					FQDN:                    "my-cluster-dns.hcp.eastus.azmk8s.io",
+					PrivateFQDN:             "my-cluster-dns.privatelink.eastus.azmk8s.io",
+					IdentityPrincipalID:     "00000000-0000-0000-0000-000000000001",
+					KubeletIdentityClientID: "00000000-0000-0000-0000-000000000002",
+					KubeletIdentityObjectID: "00000000-0000-0000-0000-000000000003",
+					AgentPools: []infrav1exp.AgentPoolStatus{
+						{
+							Name:              "agentpool1",
+							ProvisioningState: "Succeeded",
+							PowerState:        "Running",
+							Count:             to.Ptr[int32](3),
+							KubernetesVersion: "1.22.6",
+							NodeImageVersion:  "AKSUbuntu-1804gen2containerd-2022.06.08",
+						},
+						{
+							Name:              "agentpool2",
+							ProvisioningState: "Updating",
+							Count:             to.Ptr[int32](0),
+						},
+					},
+				}))
+			},
+		},
+		{
+			name:           "Should return an empty status without properties",
+			managedCluster: armcontainerservice.ManagedCluster{},
+			expect: func(g *GomegaWithT, result *infrav1exp.ManagedClusterStatus) {
+				g.Expect(result).To(Equal(&infrav1exp.ManagedClusterStatus{}))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+			result := SDKToManagedClusterStatus(c.managedCluster)
+			c.expect(g, result)
+		})
+	}
+}
@@ -17,42 +17,42 @@ limitations under the License.
 package converters
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
 // SecurityRuleToSDK converts a CAPZ security rule to an Azure network security rule.
-func SecurityRuleToSDK(rule infrav1.SecurityRule) network.SecurityRule {
-	secRule := network.SecurityRule{
-		Name: to.StringPtr(rule.Name),
-		SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
-			Description:              to.StringPtr(rule.Description),
+func SecurityRuleToSDK(rule infrav1.SecurityRule) *armnetwork.SecurityRule {
+	secRule := &armnetwork.SecurityRule{
+		Name: to.Ptr(rule.Name),
+		Properties: &armnetwork.SecurityRulePropertiesFormat{
+			Description:              to.Ptr(rule.Description),
 			SourceAddressPrefix:      rule.Source,
 			SourcePortRange:          rule.SourcePorts,
 			DestinationAddressPrefix: rule.Destination,
 			DestinationPortRange:     rule.DestinationPorts,
-			Access:                   network.SecurityRuleAccessAllow,
-			Priority:                 to.Int32Ptr(rule.Priority),
+			Access:                   to.Ptr(armnetwork.SecurityRuleAccessAllow),
+			Priority:                 to.Ptr(rule.Priority),
 		},
 	}
 
 	switch rule.Protocol {
 	case infrav1.SecurityGroupProtocolAll:
-		secRule.Protocol = network.SecurityRuleProtocolAsterisk
+		secRule.Properties.Protocol = to.Ptr(armnetwork.SecurityRuleProtocolAsterisk)
 	case infrav1.SecurityGroupProtocolTCP:
-		secRule.Protocol = network.SecurityRuleProtocolTCP
+		secRule.Properties.Protocol = to.Ptr(armnetwork.SecurityRuleProtocolTCP)
 	case infrav1.SecurityGroupProtocolUDP:
-		secRule.Protocol = network.SecurityRuleProtocolUDP
+		secRule.Properties.Protocol = to.Ptr(armnetwork.SecurityRuleProtocolUDP)
 	case infrav1.SecurityGroupProtocolICMP:
-		secRule.Protocol = network.SecurityRuleProtocolIcmp
+		secRule.Properties.Protocol = to.Ptr(armnetwork.SecurityRuleProtocolIcmp)
 	}
 
 	switch rule.Direction {
 	case infrav1.SecurityRuleDirectionOutbound:
-		secRule.Direction = network.SecurityRuleDirectionOutbound
+		secRule.Properties.Direction = to.Ptr(armnetwork.SecurityRuleDirectionOutbound)
 	case infrav1.SecurityRuleDirectionInbound:
-		secRule.Direction = network.SecurityRuleDirectionInbound
+		secRule.Properties.Direction = to.Ptr(armnetwork.SecurityRuleDirectionInbound)
 	}
 
 	return secRule
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// strictConversion holds whether the converters fail on Azure data they can't convert faithfully.
+var strictConversion = struct {
+	sync.RWMutex
+	enabled bool
+}{enabled: true}
+
+// SetStrictConversion sets whether the converters return an InvalidSDKDataError for Azure data they can't convert
+// faithfully, e.g. a malformed image ID, rather than converting it on a best effort basis. Strict conversion is
+// enabled by default.
+func SetStrictConversion(enabled bool) {
+	strictConversion.Lock()
+	defer strictConversion.Unlock()
+	strictConversion.enabled = enabled
+}
+
+// strict returns whether strict conversion is enabled.
+func strict() bool {
+	strictConversion.RLock()
+	defer strictConversion.RUnlock()
+	return strictConversion.enabled
+}
+
+// InvalidSDKDataError is returned by the converters, in strict mode, for Azure data they can't convert faithfully.
+// The converters of resources wrap it with the resource holding the data.
+type InvalidSDKDataError struct {
+	// Reason describes why the data can't be converted.
+	Reason string
+}
+
+// Error returns the error message.
+func (e InvalidSDKDataError) Error() string {
+	return fmt.Sprintf("invalid Azure data: %s", e.Reason)
+}
+
+// invalidSDKData returns an InvalidSDKDataError with a formatted reason.
+func invalidSDKData(format string, args ...interface{}) error {
+	return InvalidSDKDataError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// IsInvalidSDKData returns whether the error, or one of the errors it wraps, is an InvalidSDKDataError.
+func IsInvalidSDKData(err error) bool {
+	var invalidData InvalidSDKDataError
+	return errors.As(err, &invalidData)
+}
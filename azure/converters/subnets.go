@@ -17,16 +17,18 @@ limitations under the License.
 package converters
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"k8s.io/utils/pointer"
 )
 
-func GetSubnetAddresses(subnet network.Subnet) []string {
+func GetSubnetAddresses(subnet armnetwork.Subnet) []string {
 	var addresses []string
-	if subnet.SubnetPropertiesFormat != nil && subnet.SubnetPropertiesFormat.AddressPrefix != nil {
-		addresses = []string{to.String(subnet.SubnetPropertiesFormat.AddressPrefix)}
-	} else if subnet.SubnetPropertiesFormat != nil && subnet.SubnetPropertiesFormat.AddressPrefixes != nil {
-		addresses = to.StringSlice(subnet.SubnetPropertiesFormat.AddressPrefixes)
+	if subnet.Properties != nil && subnet.Properties.AddressPrefix != nil {
+		addresses = []string{*subnet.Properties.AddressPrefix}
+	} else if subnet.Properties != nil && subnet.Properties.AddressPrefixes != nil {
+		for _, prefix := range subnet.Properties.AddressPrefixes {
+			addresses = append(addresses, pointer.StringDeref(prefix, ""))
+		}
 	}
 	return addresses
 }
@@ -20,35 +20,35 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	. "github.com/onsi/gomega"
 )
 
 func TestGetSubnetAddresses(t *testing.T) {
 	tests := []struct {
 		name   string
-		subnet network.Subnet
+		subnet armnetwork.Subnet
 		want   []string
 	}{
 		{
 			name:   "nil properties subnet",
-			subnet: network.Subnet{},
+			subnet: armnetwork.Subnet{},
 		},
 		{
 			name: "subnet with single address prefix",
-			subnet: network.Subnet{
-				SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
-					AddressPrefix: to.StringPtr("test-address-prefix"),
+			subnet: armnetwork.Subnet{
+				Properties: &armnetwork.SubnetPropertiesFormat{
+					AddressPrefix: to.Ptr("test-address-prefix"),
 				},
 			},
 			want: []string{"test-address-prefix"},
 		},
 		{
 			name: "subnet with multiple address prefixes",
-			subnet: network.Subnet{
-				SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
-					AddressPrefixes: &[]string{"test-address-prefix-1", "test-address-prefix-2"},
+			subnet: armnetwork.Subnet{
+				Properties: &armnetwork.SubnetPropertiesFormat{
+					AddressPrefixes: []*string{to.Ptr("test-address-prefix-1"), to.Ptr("test-address-prefix-2")},
 				},
 			},
 			want: []string{"test-address-prefix-1", "test-address-prefix-2"},
@@ -17,7 +17,8 @@ limitations under the License.
 package converters
 
 import (
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
@@ -26,7 +27,7 @@ func MapToTags(src map[string]*string) infrav1.Tags {
 	tags := make(infrav1.Tags, len(src))
 
 	for k, v := range src {
-		tags[k] = to.String(v)
+		tags[k] = pointer.StringDeref(v, "")
 	}
 
 	return tags
@@ -37,7 +38,7 @@ func TagsToMap(src infrav1.Tags) map[string]*string {
 	tags := make(map[string]*string, len(src))
 
 	for k, v := range src {
-		tags[k] = to.StringPtr(v)
+		tags[k] = to.Ptr(v)
 	}
 
 	return tags
@@ -17,9 +17,9 @@ limitations under the License.
 package converters
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
@@ -38,30 +38,71 @@ type VM struct {
 	State    infrav1.ProvisioningState `json:"vmState,omitempty"`
 	Identity infrav1.VMIdentity        `json:"identity,omitempty"`
 	Tags     infrav1.Tags              `json:"tags,omitempty"`
+	// PowerState - The power state, which only appears when the instance view is expanded.
+	PowerState infrav1.PowerState `json:"powerState,omitempty"`
+	// Placement - Where the VM was placed, which is only set when the instance view is expanded.
+	Placement *infrav1.VMPlacement `json:"placement,omitempty"`
 
 	// Addresses contains the addresses associated with the Azure VM.
 	Addresses []corev1.NodeAddress `json:"addresses,omitempty"`
 }
 
 // SDKToVM converts an Azure SDK VirtualMachine to the CAPZ VM type.
-func SDKToVM(v compute.VirtualMachine) (*VM, error) {
+func SDKToVM(v armcompute.VirtualMachine) (*VM, error) {
 	vm := &VM{
-		ID:    to.String(v.ID),
-		Name:  to.String(v.Name),
-		State: infrav1.ProvisioningState(to.String(v.ProvisioningState)),
+		ID:   pointer.StringDeref(v.ID, ""),
+		Name: pointer.StringDeref(v.Name, ""),
 	}
 
-	if v.VirtualMachineProperties != nil && v.VirtualMachineProperties.HardwareProfile != nil {
-		vm.VMSize = string(v.VirtualMachineProperties.HardwareProfile.VMSize)
+	if v.Properties != nil {
+		vm.State = infrav1.ProvisioningState(pointer.StringDeref(v.Properties.ProvisioningState, ""))
 	}
 
-	if v.Zones != nil && len(*v.Zones) > 0 {
-		vm.AvailabilityZone = to.StringSlice(v.Zones)[0]
+	if v.Properties != nil && v.Properties.HardwareProfile != nil && v.Properties.HardwareProfile.VMSize != nil {
+		vm.VMSize = string(*v.Properties.HardwareProfile.VMSize)
 	}
 
+	if v.Properties != nil && v.Properties.InstanceView != nil {
+		vm.PowerState = SDKToPowerState(v.Properties.InstanceView.Statuses)
+	}
+
+	if len(v.Zones) > 0 {
+		vm.AvailabilityZone = pointer.StringDeref(v.Zones[0], "")
+	}
+
+	vm.Placement = SDKToVMPlacement(v)
+
 	if len(v.Tags) > 0 {
 		vm.Tags = MapToTags(v.Tags)
 	}
 
 	return vm, nil
 }
+
+// SDKToVMPlacement converts the placement of an Azure SDK VirtualMachine to infrav1.VMPlacement. It returns nil when
+// the VM wasn't read with its instance view, e.g. right after its creation, as its fault and update domains are unknown.
+func SDKToVMPlacement(v armcompute.VirtualMachine) *infrav1.VMPlacement {
+	if v.Properties == nil || v.Properties.InstanceView == nil {
+		return nil
+	}
+
+	instanceView := v.Properties.InstanceView
+	placement := &infrav1.VMPlacement{
+		FaultDomain:  instanceView.PlatformFaultDomain,
+		UpdateDomain: instanceView.PlatformUpdateDomain,
+	}
+	if len(v.Zones) > 0 {
+		placement.AvailabilityZone = pointer.StringDeref(v.Zones[0], "")
+	}
+	// The host assigned by Azure is only reported when the VM is placed in a dedicated host group rather than on a host.
+	if pointer.StringDeref(instanceView.AssignedHost, "") != "" {
+		placement.DedicatedHostID = pointer.StringDeref(instanceView.AssignedHost, "")
+	} else if v.Properties.Host != nil {
+		placement.DedicatedHostID = pointer.StringDeref(v.Properties.Host.ID, "")
+	}
+	if v.Properties.ProximityPlacementGroup != nil {
+		placement.ProximityPlacementGroupID = pointer.StringDeref(v.Properties.ProximityPlacementGroup.ID, "")
+	}
+
+	return placement
+}
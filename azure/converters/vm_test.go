@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestSDKToVMPlacement(t *testing.T) {
+	tests := []struct {
+		name string
+		vm   armcompute.VirtualMachine
+		want *infrav1.VMPlacement
+	}{
+		{
+			name: "vm without properties",
+			vm:   armcompute.VirtualMachine{},
+		},
+		{
+			name: "vm without instance view",
+			vm: armcompute.VirtualMachine{
+				Zones: []*string{to.Ptr("1")},
+				Properties: &armcompute.VirtualMachineProperties{
+					Host: &armcompute.SubResource{ID: to.Ptr("host-id")},
+				},
+			},
+		},
+		{
+			name: "vm in an availability zone",
+			vm: armcompute.VirtualMachine{
+				Zones: []*string{to.Ptr("1")},
+				Properties: &armcompute.VirtualMachineProperties{
+					InstanceView: &armcompute.VirtualMachineInstanceView{
+						PlatformFaultDomain:  to.Ptr[int32](0),
+						PlatformUpdateDomain: to.Ptr[int32](0),
+					},
+				},
+			},
+			want: &infrav1.VMPlacement{
+				FaultDomain:      to.Ptr[int32](0),
+				UpdateDomain:     to.Ptr[int32](0),
+				AvailabilityZone: "1",
+			},
+		},
+		{
+			name: "vm on a dedicated host in a proximity placement group",
+			vm: armcompute.VirtualMachine{
+				Properties: &armcompute.VirtualMachineProperties{
+					Host:                    &armcompute.SubResource{ID: to.Ptr("host-id")},
+					ProximityPlacementGroup: &armcompute.SubResource{ID: to.Ptr("ppg-id")},
+					InstanceView: &armcompute.VirtualMachineInstanceView{
+						PlatformFaultDomain:  to.Ptr[int32](1),
+						PlatformUpdateDomain: to.Ptr[int32](4),
+					},
+				},
+			},
+			want: &infrav1.VMPlacement{
+				FaultDomain:               to.Ptr[int32](1),
+				UpdateDomain:              to.Ptr[int32](4),
+				DedicatedHostID:           "host-id",
+				ProximityPlacementGroupID: "ppg-id",
+			},
+		},
+		{
+			name: "vm on a host assigned in a dedicated host group",
+			vm: armcompute.VirtualMachine{
+				Properties: &armcompute.VirtualMachineProperties{
+					HostGroup: &armcompute.SubResource{ID: to.Ptr("host-group-id")},
+					InstanceView: &armcompute.VirtualMachineInstanceView{
+						AssignedHost: to.Ptr("assigned-host-id"),
+					},
+				},
+			},
+			want: &infrav1.VMPlacement{
+				DedicatedHostID: "assigned-host-id",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+			g.Expect(SDKToVMPlacement(tt.vm)).To(Equal(tt.want))
+		})
+	}
+}
@@ -17,27 +17,51 @@ limitations under the License.
 package converters
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"k8s.io/utils/pointer"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 )
 
-// SDKToVMSS converts an Azure SDK VirtualMachineScaleSet to the AzureMachinePool type.
-func SDKToVMSS(sdkvmss compute.VirtualMachineScaleSet, sdkinstances []compute.VirtualMachineScaleSetVM) *azure.VMSS {
+// powerStatePrefix prefixes the code of the instance view status describing the power state of a virtual machine.
+const powerStatePrefix = "PowerState/"
+
+// hibernatedStatus is the code of the instance view status of a virtual machine which was hibernated when deallocated.
+const hibernatedStatus = "HibernationState/Hibernated"
+
+// provisioningStatePrefix prefixes the code of the instance view status describing the provisioning state of an
+// extension or a disk, e.g. "ProvisioningState/failed/VMExtensionProvisioningError".
+const provisioningStatePrefix = "ProvisioningState/"
+
+// maxStatusMessageLength is the maximum length of the instance view messages kept in status, as the messages of the
+// extensions can include their whole output.
+const maxStatusMessageLength = 512
+
+// SDKToVMSS converts an Azure SDK VirtualMachineScaleSet to the AzureMachinePool type. In strict mode, it returns an
+// error wrapping an InvalidSDKDataError for data of the scale set or its instances it can't convert faithfully.
+func SDKToVMSS(sdkvmss armcompute.VirtualMachineScaleSet, sdkinstances []armcompute.VirtualMachineScaleSetVM) (*azure.VMSS, error) {
 	vmss := &azure.VMSS{
-		ID:    to.String(sdkvmss.ID),
-		Name:  to.String(sdkvmss.Name),
-		State: infrav1.ProvisioningState(to.String(sdkvmss.ProvisioningState)),
+		ID:   pointer.StringDeref(sdkvmss.ID, ""),
+		Name: pointer.StringDeref(sdkvmss.Name, ""),
+	}
+
+	if sdkvmss.Properties != nil {
+		vmss.State = infrav1.ProvisioningState(pointer.StringDeref(sdkvmss.Properties.ProvisioningState, ""))
 	}
 
-	if sdkvmss.Sku != nil {
-		vmss.Sku = to.String(sdkvmss.Sku.Name)
-		vmss.Capacity = to.Int64(sdkvmss.Sku.Capacity)
+	if sdkvmss.SKU != nil {
+		vmss.Sku = pointer.StringDeref(sdkvmss.SKU.Name, "")
+		vmss.Capacity = pointer.Int64Deref(sdkvmss.SKU.Capacity, 0)
 	}
 
-	if sdkvmss.Zones != nil && len(*sdkvmss.Zones) > 0 {
-		vmss.Zones = to.StringSlice(sdkvmss.Zones)
+	for _, zone := range sdkvmss.Zones {
+		if zone != nil {
+			vmss.Zones = append(vmss.Zones, *zone)
+		}
 	}
 
 	if len(sdkvmss.Tags) > 0 {
@@ -47,65 +71,359 @@ func SDKToVMSS(sdkvmss compute.VirtualMachineScaleSet, sdkinstances []compute.Vi
 	if len(sdkinstances) > 0 {
 		vmss.Instances = make([]azure.VMSSVM, len(sdkinstances))
 		for i, vm := range sdkinstances {
-			vmss.Instances[i] = *SDKToVMSSVM(vm)
+			instance, err := SDKToVMSSVM(vm)
+			if err != nil {
+				return nil, err
+			}
+			vmss.Instances[i] = *instance
+		}
+	}
+
+	vmss.Plan = sdkToPlan(sdkvmss.Plan)
+	vmss.Identity, vmss.UserAssignedIdentities = sdkToVMSSIdentity(sdkvmss.Identity)
+
+	if sdkvmss.Properties == nil || sdkvmss.Properties.VirtualMachineProfile == nil {
+		return vmss, nil
+	}
+
+	profile := sdkvmss.Properties.VirtualMachineProfile
+	vmss.BootDiagnostics = sdkToBootDiagnostics(profile.DiagnosticsProfile)
+	if profile.ExtensionProfile != nil && profile.ExtensionProfile.Extensions != nil {
+		for _, extension := range profile.ExtensionProfile.Extensions {
+			if extension == nil {
+				continue
+			}
+			vmssExtension := azure.VMSSExtension{Name: pointer.StringDeref(extension.Name, "")}
+			if props := extension.Properties; props != nil {
+				vmssExtension.Publisher = pointer.StringDeref(props.Publisher, "")
+				vmssExtension.Type = pointer.StringDeref(props.Type, "")
+				vmssExtension.Version = pointer.StringDeref(props.TypeHandlerVersion, "")
+			}
+			vmss.Extensions = append(vmss.Extensions, vmssExtension)
+		}
+		sortExtensions(vmss.Extensions)
+	}
+
+	if profile.StorageProfile != nil && profile.StorageProfile.DataDisks != nil {
+		for _, disk := range profile.StorageProfile.DataDisks {
+			if disk != nil {
+				vmss.DataDisks = append(vmss.DataDisks, azure.VMSSDataDisk{Lun: pointer.Int32Deref(disk.Lun, 0), DiskSizeGB: pointer.Int32Deref(disk.DiskSizeGB, 0)})
+			}
 		}
+		sortDataDisks(vmss.DataDisks)
 	}
 
-	if sdkvmss.VirtualMachineProfile != nil &&
-		sdkvmss.VirtualMachineProfile.StorageProfile != nil &&
-		sdkvmss.VirtualMachineProfile.StorageProfile.ImageReference != nil {
-		imageRef := sdkvmss.VirtualMachineProfile.StorageProfile.ImageReference
-		vmss.Image = SDKImageToImage(imageRef, sdkvmss.Plan != nil)
+	if profile.StorageProfile != nil && profile.StorageProfile.ImageReference != nil {
+		image, err := SDKImageToImage(profile.StorageProfile.ImageReference, sdkvmss.Plan != nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the image of scale set %s", vmss.Name)
+		}
+		vmss.Image = image
 	}
 
-	return vmss
+	return vmss, nil
 }
 
-// SDKToVMSSVM converts an Azure SDK VirtualMachineScaleSetVM into an infrav1exp.VMSSVM.
-func SDKToVMSSVM(sdkInstance compute.VirtualMachineScaleSetVM) *azure.VMSSVM {
+// SDKToVMSSVM converts an Azure SDK VirtualMachineScaleSetVM into an infrav1exp.VMSSVM. In strict mode, it returns an
+// error wrapping an InvalidSDKDataError for data of the instance it can't convert faithfully.
+func SDKToVMSSVM(sdkInstance armcompute.VirtualMachineScaleSetVM) (*azure.VMSSVM, error) {
 	instance := azure.VMSSVM{
-		ID:         to.String(sdkInstance.ID),
-		InstanceID: to.String(sdkInstance.InstanceID),
+		ID:         pointer.StringDeref(sdkInstance.ID, ""),
+		InstanceID: pointer.StringDeref(sdkInstance.InstanceID, ""),
 	}
 
-	if sdkInstance.VirtualMachineScaleSetVMProperties == nil {
-		return &instance
+	props := sdkInstance.Properties
+	if props == nil {
+		return &instance, nil
 	}
 
+	// Azure only reports the latest model as not applied once the VMSS model changed.
+	instance.LatestModelApplied = pointer.BoolDeref(props.LatestModelApplied, false) || props.LatestModelApplied == nil
+
 	instance.State = infrav1.Creating
-	if sdkInstance.ProvisioningState != nil {
-		instance.State = infrav1.ProvisioningState(to.String(sdkInstance.ProvisioningState))
+	if props.ProvisioningState != nil {
+		instance.State = infrav1.ProvisioningState(*props.ProvisioningState)
+	}
+
+	if props.OSProfile != nil && props.OSProfile.ComputerName != nil {
+		instance.Name = *props.OSProfile.ComputerName
+	}
+
+	if props.StorageProfile != nil && props.StorageProfile.ImageReference != nil {
+		imageRef := props.StorageProfile.ImageReference
+		image, err := SDKImageToImage(imageRef, sdkInstance.Plan != nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert the image of scale set instance %s", instance.ID)
+		}
+		instance.Image = image
+		// the exact version differs from the version when the image reference uses the "latest" version.
+		instance.ImageVersion = pointer.StringDeref(imageRef.ExactVersion, "")
+		if instance.ImageVersion == "" {
+			instance.ImageVersion = pointer.StringDeref(imageRef.Version, "")
+		}
+	}
+
+	if props.StorageProfile != nil && props.StorageProfile.DataDisks != nil {
+		for _, disk := range props.StorageProfile.DataDisks {
+			if disk != nil {
+				instance.DataDisks = append(instance.DataDisks, azure.VMSSDataDisk{Lun: pointer.Int32Deref(disk.Lun, 0), DiskSizeGB: pointer.Int32Deref(disk.DiskSizeGB, 0)})
+			}
+		}
+		sortDataDisks(instance.DataDisks)
+	}
+
+	instance.Plan = sdkToPlan(sdkInstance.Plan)
+	instance.BootDiagnostics = sdkToBootDiagnostics(props.DiagnosticsProfile)
+
+	// the extensions of an instance are its resources.
+	for _, extension := range sdkInstance.Resources {
+		if extension == nil {
+			continue
+		}
+		vmssExtension := azure.VMSSExtension{Name: pointer.StringDeref(extension.Name, "")}
+		if extensionProps := extension.Properties; extensionProps != nil {
+			vmssExtension.Publisher = pointer.StringDeref(extensionProps.Publisher, "")
+			vmssExtension.Type = pointer.StringDeref(extensionProps.Type, "")
+			vmssExtension.Version = pointer.StringDeref(extensionProps.TypeHandlerVersion, "")
+		}
+		instance.Extensions = append(instance.Extensions, vmssExtension)
 	}
+	sortExtensions(instance.Extensions)
 
-	if sdkInstance.OsProfile != nil && sdkInstance.OsProfile.ComputerName != nil {
-		instance.Name = *sdkInstance.OsProfile.ComputerName
+	if props.ProtectionPolicy != nil {
+		instance.ProtectFromScaleIn = pointer.BoolDeref(props.ProtectionPolicy.ProtectFromScaleIn, false)
 	}
 
-	if sdkInstance.StorageProfile != nil && sdkInstance.StorageProfile.ImageReference != nil {
-		imageRef := sdkInstance.StorageProfile.ImageReference
-		instance.Image = SDKImageToImage(imageRef, sdkInstance.Plan != nil)
+	// the instance view is only set when listing the instances with the instance view expanded.
+	if props.InstanceView != nil {
+		instance.PowerState = SDKToPowerState(props.InstanceView.Statuses)
+		instance.FaultDomain = props.InstanceView.PlatformFaultDomain
+		instance.InstanceView = SDKToVMSSVMInstanceView(props.InstanceView)
 	}
 
-	if sdkInstance.Zones != nil && len(*sdkInstance.Zones) > 0 {
+	if len(sdkInstance.Zones) > 0 {
 		// an instance should only have 1 zone, so we select the first item of the slice
-		instance.AvailabilityZone = to.StringSlice(sdkInstance.Zones)[0]
+		instance.AvailabilityZone = pointer.StringDeref(sdkInstance.Zones[0], "")
+	}
+
+	return &instance, nil
+}
+
+// sdkToPlan converts the SDK plan of a virtual machine or scale set into an infrav1.ImagePlan.
+func sdkToPlan(plan *armcompute.Plan) *infrav1.ImagePlan {
+	if plan == nil {
+		return nil
+	}
+	return &infrav1.ImagePlan{
+		Publisher: pointer.StringDeref(plan.Publisher, ""),
+		Offer:     pointer.StringDeref(plan.Product, ""),
+		SKU:       pointer.StringDeref(plan.Name, ""),
+	}
+}
+
+// sdkToVMSSIdentity converts the SDK identity of a scale set into its type and the lowercase IDs of its user-assigned
+// identities, sorted, as Azure doesn't preserve the case of the IDs.
+func sdkToVMSSIdentity(identity *armcompute.VirtualMachineScaleSetIdentity) (infrav1.VMIdentity, []string) {
+	if identity == nil {
+		return "", nil
+	}
+
+	var vmIdentity infrav1.VMIdentity
+	if identity.Type != nil {
+		switch *identity.Type {
+		case armcompute.ResourceIdentityTypeSystemAssigned:
+			vmIdentity = infrav1.VMIdentitySystemAssigned
+		case armcompute.ResourceIdentityTypeUserAssigned, armcompute.ResourceIdentityTypeSystemAssignedUserAssigned:
+			vmIdentity = infrav1.VMIdentityUserAssigned
+		}
+	}
+
+	var userAssignedIdentities []string
+	for id := range identity.UserAssignedIdentities {
+		userAssignedIdentities = append(userAssignedIdentities, strings.ToLower(id))
+	}
+	sort.Strings(userAssignedIdentities)
+
+	return vmIdentity, userAssignedIdentities
+}
+
+// sdkToBootDiagnostics converts the SDK diagnostics profile of a virtual machine into its boot diagnostics.
+func sdkToBootDiagnostics(profile *armcompute.DiagnosticsProfile) *azure.BootDiagnostics {
+	if profile == nil || profile.BootDiagnostics == nil {
+		return nil
+	}
+	return &azure.BootDiagnostics{
+		Enabled:    pointer.BoolDeref(profile.BootDiagnostics.Enabled, false),
+		StorageURI: pointer.StringDeref(profile.BootDiagnostics.StorageURI, ""),
+	}
+}
+
+// sortExtensions sorts extensions by name, as Azure doesn't preserve their order.
+func sortExtensions(extensions []azure.VMSSExtension) {
+	sort.Slice(extensions, func(i, j int) bool {
+		return extensions[i].Name < extensions[j].Name
+	})
+}
+
+// sortDataDisks sorts data disks by LUN, as Azure doesn't preserve their order.
+func sortDataDisks(disks []azure.VMSSDataDisk) {
+	sort.Slice(disks, func(i, j int) bool {
+		return disks[i].Lun < disks[j].Lun
+	})
+}
+
+// SDKToPowerState returns the power state of a virtual machine from the statuses of its instance view.
+func SDKToPowerState(statuses []*armcompute.InstanceViewStatus) infrav1.PowerState {
+	hibernated := false
+	for _, status := range statuses {
+		if status != nil && strings.EqualFold(pointer.StringDeref(status.Code, ""), hibernatedStatus) {
+			hibernated = true
+		}
+	}
+
+	for _, status := range statuses {
+		if status == nil {
+			continue
+		}
+		code := pointer.StringDeref(status.Code, "")
+		if !strings.HasPrefix(code, powerStatePrefix) {
+			continue
+		}
+		switch strings.TrimPrefix(code, powerStatePrefix) {
+		case "starting":
+			return infrav1.PowerStateStarting
+		case "running":
+			return infrav1.PowerStateRunning
+		case "stopping":
+			return infrav1.PowerStateStopping
+		case "stopped":
+			return infrav1.PowerStateStopped
+		case "deallocating":
+			return infrav1.PowerStateDeallocating
+		case "deallocated":
+			if hibernated {
+				return infrav1.PowerStateHibernated
+			}
+			return infrav1.PowerStateDeallocated
+		}
+	}
+
+	return infrav1.PowerStateUnknown
+}
+
+// SDKToVMSSVMInstanceView converts the instance view of an Azure SDK VirtualMachineScaleSetVM into the health of its
+// extensions, disks and boot diagnostics.
+func SDKToVMSSVMInstanceView(view *armcompute.VirtualMachineScaleSetVMInstanceView) *infrav1.VMInstanceView {
+	if view == nil {
+		return nil
+	}
+
+	instanceView := &infrav1.VMInstanceView{}
+	for _, extension := range view.Extensions {
+		if extension != nil {
+			state, message := sdkToProvisioningState(extension.Statuses)
+			instanceView.Extensions = append(instanceView.Extensions, infrav1.VMExtensionStatus{
+				Name:              pointer.StringDeref(extension.Name, ""),
+				Type:              pointer.StringDeref(extension.Type, ""),
+				ProvisioningState: state,
+				Message:           message,
+			})
+		}
+	}
+	for _, disk := range view.Disks {
+		if disk != nil {
+			state, message := sdkToProvisioningState(disk.Statuses)
+			instanceView.Disks = append(instanceView.Disks, infrav1.VMDiskStatus{
+				Name:              pointer.StringDeref(disk.Name, ""),
+				ProvisioningState: state,
+				Message:           message,
+			})
+		}
+	}
+	if view.BootDiagnostics != nil {
+		// The status of the boot diagnostics is only set when they failed to be enabled.
+		instanceView.BootDiagnostics = &infrav1.VMBootDiagnosticsStatus{Available: true}
+		if status := view.BootDiagnostics.Status; status != nil {
+			instanceView.BootDiagnostics.Available = status.Level == nil || *status.Level != armcompute.StatusLevelTypesError
+			instanceView.BootDiagnostics.Message = truncateStatusMessage(pointer.StringDeref(status.Message, ""))
+		}
+	}
+
+	if len(instanceView.Extensions) == 0 && len(instanceView.Disks) == 0 && instanceView.BootDiagnostics == nil {
+		return nil
+	}
+
+	return instanceView
+}
+
+// sdkToProvisioningState returns the provisioning state and its message from the statuses of an instance view, e.g.
+// Failed for the "ProvisioningState/failed/VMExtensionProvisioningError" status code.
+func sdkToProvisioningState(statuses []*armcompute.InstanceViewStatus) (infrav1.ProvisioningState, string) {
+	for _, status := range statuses {
+		if status == nil {
+			continue
+		}
+		code := pointer.StringDeref(status.Code, "")
+		if !strings.HasPrefix(code, provisioningStatePrefix) {
+			continue
+		}
+		state := strings.SplitN(strings.TrimPrefix(code, provisioningStatePrefix), "/", 2)[0]
+		if state != "" {
+			state = strings.ToUpper(state[:1]) + state[1:]
+		}
+		return infrav1.ProvisioningState(state), truncateStatusMessage(pointer.StringDeref(status.Message, ""))
 	}
 
-	return &instance
+	return "", ""
 }
 
-// SDKImageToImage converts a SDK image reference to infrav1.Image.
-func SDKImageToImage(sdkImageRef *compute.ImageReference, isThirdPartyImage bool) infrav1.Image {
+// truncateStatusMessage truncates a message of an instance view to maxStatusMessageLength.
+func truncateStatusMessage(message string) string {
+	if len(message) <= maxStatusMessageLength {
+		return message
+	}
+	return message[:maxStatusMessageLength-3] + "..."
+}
+
+// SDKImageToImage converts a SDK image reference to infrav1.Image. The ID of community and shared gallery images is
+// reported as the ID of the image. In strict mode, it returns an InvalidSDKDataError for a reference whose ID isn't
+// a resource ID, or which references no image.
+func SDKImageToImage(sdkImageRef *armcompute.ImageReference, isThirdPartyImage bool) (infrav1.Image, error) {
+	if sdkImageRef == nil {
+		if strict() {
+			return infrav1.Image{}, invalidSDKData("missing image reference")
+		}
+		return infrav1.Image{}, nil
+	}
+
+	id := sdkImageRef.ID
+	switch {
+	case sdkImageRef.CommunityGalleryImageID != nil:
+		id = sdkImageRef.CommunityGalleryImageID
+	case sdkImageRef.SharedGalleryImageID != nil:
+		id = sdkImageRef.SharedGalleryImageID
+	}
+
+	if strict() {
+		if id != nil {
+			if _, err := azure.ParseResourceID(*id); err != nil {
+				return infrav1.Image{}, invalidSDKData("image reference ID %q is not a resource ID", *id)
+			}
+		} else if pointer.StringDeref(sdkImageRef.Publisher, "") == "" || pointer.StringDeref(sdkImageRef.Offer, "") == "" || pointer.StringDeref(sdkImageRef.SKU, "") == "" {
+			return infrav1.Image{}, invalidSDKData("image reference has neither an ID nor a Marketplace publisher, offer and SKU")
+		}
+	}
+
 	return infrav1.Image{
-		ID: sdkImageRef.ID,
+		ID: id,
 		Marketplace: &infrav1.AzureMarketplaceImage{
 			ImagePlan: infrav1.ImagePlan{
-				Publisher: to.String(sdkImageRef.Publisher),
-				Offer:     to.String(sdkImageRef.Offer),
-				SKU:       to.String(sdkImageRef.Sku),
+				Publisher: pointer.StringDeref(sdkImageRef.Publisher, ""),
+				Offer:     pointer.StringDeref(sdkImageRef.Offer, ""),
+				SKU:       pointer.StringDeref(sdkImageRef.SKU, ""),
 			},
-			Version:         to.String(sdkImageRef.Version),
+			Version:         pointer.StringDeref(sdkImageRef.Version, ""),
 			ThirdPartyImage: isThirdPartyImage,
 		},
-	}
+	}, nil
 }
@@ -18,66 +18,80 @@ package converters_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test/fixtures"
 )
 
 func Test_SDKToVMSS(t *testing.T) {
 	cases := []struct {
 		Name           string
-		SubjectFactory func(*gomega.GomegaWithT) (compute.VirtualMachineScaleSet, []compute.VirtualMachineScaleSetVM)
+		SubjectFactory func(*gomega.GomegaWithT) (armcompute.VirtualMachineScaleSet, []armcompute.VirtualMachineScaleSetVM)
 		Expect         func(*gomega.GomegaWithT, *azure.VMSS)
 	}{
 		{
 			Name: "ShouldPopulateWithData",
-			SubjectFactory: func(g *gomega.GomegaWithT) (compute.VirtualMachineScaleSet, []compute.VirtualMachineScaleSetVM) {
+			SubjectFactory: func(g *gomega.GomegaWithT) (armcompute.VirtualMachineScaleSet, []armcompute.VirtualMachineScaleSetVM) {
 				tags := map[string]*string{
-					"foo": to.StringPtr("bazz"),
+					"foo": to.Ptr("bazz"),
 				}
-				zones := []string{"zone0", "zone1"}
-				return compute.VirtualMachineScaleSet{
-						Sku: &compute.Sku{
-							Name:     to.StringPtr("skuName"),
-							Tier:     to.StringPtr("skuTier"),
-							Capacity: to.Int64Ptr(2),
+				zones := []*string{to.Ptr("zone0"), to.Ptr("zone1")}
+				return armcompute.VirtualMachineScaleSet{
+						SKU: &armcompute.SKU{
+							Name:     to.Ptr("skuName"),
+							Tier:     to.Ptr("skuTier"),
+							Capacity: to.Ptr[int64](2),
 						},
-						Zones:    to.StringSlicePtr(zones),
-						ID:       to.StringPtr("vmssID"),
-						Name:     to.StringPtr("vmssName"),
-						Location: to.StringPtr("westus2"),
+						Zones:    zones,
+						ID:       to.Ptr("vmssID"),
+						Name:     to.Ptr("vmssName"),
+						Location: to.Ptr("westus2"),
 						Tags:     tags,
-						VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
-							SinglePlacementGroup: to.BoolPtr(false),
-							ProvisioningState:    to.StringPtr(string(compute.ProvisioningState1Succeeded)),
+						Properties: &armcompute.VirtualMachineScaleSetProperties{
+							SinglePlacementGroup: to.Ptr(false),
+							ProvisioningState:    to.Ptr("Succeeded"),
 						},
 					},
-					[]compute.VirtualMachineScaleSetVM{
+					[]armcompute.VirtualMachineScaleSetVM{
 						{
-							InstanceID: to.StringPtr("0"),
-							ID:         to.StringPtr("vm/0"),
-							Name:       to.StringPtr("vm0"),
-							Zones:      to.StringSlicePtr([]string{"zone0"}),
-							VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
-								ProvisioningState: to.StringPtr(string(compute.ProvisioningState1Succeeded)),
-								OsProfile: &compute.OSProfile{
-									ComputerName: to.StringPtr("instance-000000"),
+							InstanceID: to.Ptr("0"),
+							ID:         to.Ptr("vm/0"),
+							Name:       to.Ptr("vm0"),
+							Zones:      []*string{to.Ptr("zone0")},
+							Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+								ProvisioningState: to.Ptr("Succeeded"),
+								OSProfile: &armcompute.OSProfile{
+									ComputerName: to.Ptr("instance-000000"),
 								},
 							},
 						},
 						{
-							InstanceID: to.StringPtr("1"),
-							ID:         to.StringPtr("vm/1"),
-							Name:       to.StringPtr("vm1"),
-							Zones:      to.StringSlicePtr([]string{"zone1"}),
-							VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
-								ProvisioningState: to.StringPtr(string(compute.ProvisioningState1Succeeded)),
-								OsProfile: &compute.OSProfile{
-									ComputerName: to.StringPtr("instance-000001"),
+							InstanceID: to.Ptr("1"),
+							ID:         to.Ptr("vm/1"),
+							Name:       to.Ptr("vm1"),
+							Zones:      []*string{to.Ptr("zone1")},
+							Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+								ProvisioningState: to.Ptr("Succeeded"),
+								OSProfile: &armcompute.OSProfile{
+									ComputerName: to.Ptr("instance-000001"),
+								},
+								LatestModelApplied: to.Ptr(false),
+								ProtectionPolicy: &armcompute.VirtualMachineScaleSetVMProtectionPolicy{
+									ProtectFromScaleIn: to.Ptr(true),
+								},
+								InstanceView: &armcompute.VirtualMachineScaleSetVMInstanceView{
+									PlatformFaultDomain: to.Ptr[int32](1),
+									Statuses: []*armcompute.InstanceViewStatus{
+										{Code: to.Ptr("ProvisioningState/succeeded")},
+										{Code: to.Ptr("PowerState/running")},
+									},
 								},
 							},
 						},
@@ -99,13 +113,17 @@ func Test_SDKToVMSS(t *testing.T) {
 
 				for i := 0; i < 2; i++ {
 					expected.Instances[i] = azure.VMSSVM{
-						ID:               fmt.Sprintf("vm/%d", i),
-						InstanceID:       fmt.Sprintf("%d", i),
-						Name:             fmt.Sprintf("instance-00000%d", i),
-						AvailabilityZone: fmt.Sprintf("zone%d", i),
-						State:            "Succeeded",
+						ID:                 fmt.Sprintf("vm/%d", i),
+						InstanceID:         fmt.Sprintf("%d", i),
+						Name:               fmt.Sprintf("instance-00000%d", i),
+						AvailabilityZone:   fmt.Sprintf("zone%d", i),
+						State:              "Succeeded",
+						LatestModelApplied: i == 0,
 					}
 				}
+				expected.Instances[1].PowerState = infrav1.PowerStateRunning
+				expected.Instances[1].FaultDomain = to.Ptr[int32](1)
+				expected.Instances[1].ProtectFromScaleIn = true
 				g.Expect(actual).To(gomega.Equal(&expected))
 			},
 		},
@@ -117,8 +135,286 @@ func Test_SDKToVMSS(t *testing.T) {
 			t.Parallel()
 			g := gomega.NewGomegaWithT(t)
 			vmss, instances := c.SubjectFactory(g)
-			subject := converters.SDKToVMSS(vmss, instances)
+			subject, err := converters.SDKToVMSS(vmss, instances)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
 			c.Expect(g, subject)
 		})
 	}
 }
+
+func Test_SDKToVMSSStrictConversion(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	vmss := fixtures.VMSSWithPlan()
+	vmss.Properties.VirtualMachineProfile.StorageProfile.ImageReference = fixtures.MalformedIDImageReference()
+
+	_, err := converters.SDKToVMSS(vmss, nil)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(converters.IsInvalidSDKData(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("my-cluster-mp-0"))
+
+	instances := fixtures.VMSSVMs()
+	instances[1].Properties.StorageProfile.ImageReference = fixtures.EmptyIDImageReference()
+	_, err = converters.SDKToVMSS(fixtures.VMSSWithPlan(), instances)
+	g.Expect(converters.IsInvalidSDKData(err)).To(gomega.BeTrue())
+
+	converters.SetStrictConversion(false)
+	defer converters.SetStrictConversion(true)
+	converted, err := converters.SDKToVMSS(vmss, instances)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(converted.Image.ID).To(gomega.Equal(fixtures.MalformedIDImageReference().ID))
+}
+
+func Test_SDKToVMSSModel(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	vmss, err := converters.SDKToVMSS(fixtures.VMSSWithPlan(), nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(vmss.Plan).To(gomega.Equal(&infrav1.ImagePlan{
+		Publisher: "kinvolk",
+		Offer:     "flatcar-container-linux-free",
+		SKU:       "stable-gen2",
+	}))
+	g.Expect(vmss.Identity).To(gomega.Equal(infrav1.VMIdentityUserAssigned))
+	g.Expect(vmss.UserAssignedIdentities).To(gomega.Equal([]string{
+		"/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/my-cluster-rg/providers/microsoft.managedidentity/userassignedidentities/my-cluster-identity",
+	}))
+	g.Expect(vmss.BootDiagnostics).To(gomega.Equal(&azure.BootDiagnostics{Enabled: true}))
+	g.Expect(vmss.Extensions).To(gomega.Equal([]azure.VMSSExtension{
+		{Name: "CAPZ.Linux.Bootstrapping", Publisher: "Microsoft.Azure.ContainerUpstream", Type: "linux-bootstrapping", Version: "1.0"},
+		{Name: "someExtension", Publisher: "somePublisher", Type: "someExtension", Version: "1.0"},
+	}))
+	g.Expect(vmss.DataDisks).To(gomega.Equal([]azure.VMSSDataDisk{
+		{Lun: 0, DiskSizeGB: 256},
+		{Lun: 1, DiskSizeGB: 64},
+	}))
+
+	// Azure changes the case of the identity IDs and the order of the extensions.
+	sdkvmss := fixtures.VMSSWithPlan()
+	identities := map[string]*armcompute.UserAssignedIdentitiesValue{}
+	for id, identity := range sdkvmss.Identity.UserAssignedIdentities {
+		identities[strings.ToLower(id)] = identity
+	}
+	sdkvmss.Identity.UserAssignedIdentities = identities
+	extensions := sdkvmss.Properties.VirtualMachineProfile.ExtensionProfile.Extensions
+	extensions[0], extensions[1] = extensions[1], extensions[0]
+	other, err := converters.SDKToVMSS(sdkvmss, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(vmss.HasModelChanges(*other)).To(gomega.BeFalse())
+
+	sdkvmss.Identity = &armcompute.VirtualMachineScaleSetIdentity{Type: to.Ptr(armcompute.ResourceIdentityTypeNone)}
+	sdkvmss.Properties.VirtualMachineProfile.DiagnosticsProfile = nil
+	other, err = converters.SDKToVMSS(sdkvmss, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(other.Identity).To(gomega.BeEmpty())
+	g.Expect(other.UserAssignedIdentities).To(gomega.BeNil())
+	g.Expect(other.BootDiagnostics).To(gomega.BeNil())
+	g.Expect(vmss.HasModelChanges(*other)).To(gomega.BeTrue())
+
+	// a data disk removed from the model is a model change, and the instances keep it until they are updated.
+	sdkvmss = fixtures.VMSSWithPlan()
+	sdkvmss.Properties.VirtualMachineProfile.StorageProfile.DataDisks = []*armcompute.VirtualMachineScaleSetDataDisk{
+		sdkvmss.Properties.VirtualMachineProfile.StorageProfile.DataDisks[1],
+	}
+	other, err = converters.SDKToVMSS(sdkvmss, fixtures.VMSSVMs())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(vmss.HasModelChanges(*other)).To(gomega.BeTrue())
+	g.Expect(other.HasDataDiskChanges(other.Instances[0])).To(gomega.BeTrue())
+	g.Expect(other.HasDataDiskChanges(other.Instances[1])).To(gomega.BeFalse())
+}
+
+func Test_SDKToPowerState(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Statuses []*armcompute.InstanceViewStatus
+		Expected infrav1.PowerState
+	}{
+		{
+			Name:     "ShouldBeUnknownWithoutStatuses",
+			Statuses: nil,
+			Expected: infrav1.PowerStateUnknown,
+		},
+		{
+			Name: "ShouldBeUnknownWithoutPowerState",
+			Statuses: []*armcompute.InstanceViewStatus{
+				{Code: to.Ptr("ProvisioningState/succeeded")},
+			},
+			Expected: infrav1.PowerStateUnknown,
+		},
+		{
+			Name: "ShouldBeRunning",
+			Statuses: []*armcompute.InstanceViewStatus{
+				{Code: to.Ptr("ProvisioningState/succeeded")},
+				{Code: to.Ptr("PowerState/running")},
+			},
+			Expected: infrav1.PowerStateRunning,
+		},
+		{
+			Name: "ShouldBeDeallocated",
+			Statuses: []*armcompute.InstanceViewStatus{
+				{Code: to.Ptr("PowerState/deallocated")},
+			},
+			Expected: infrav1.PowerStateDeallocated,
+		},
+		{
+			Name: "ShouldBeHibernated",
+			Statuses: []*armcompute.InstanceViewStatus{
+				{Code: to.Ptr("PowerState/deallocated")},
+				{Code: to.Ptr("HibernationState/Hibernated")},
+			},
+			Expected: infrav1.PowerStateHibernated,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(converters.SDKToPowerState(c.Statuses)).To(gomega.Equal(c.Expected))
+		})
+	}
+}
+
+func Test_SDKToVMSSVMInstanceView(t *testing.T) {
+	cases := []struct {
+		Name         string
+		InstanceView *armcompute.VirtualMachineScaleSetVMInstanceView
+		Expected     *infrav1.VMInstanceView
+	}{
+		{
+			Name:         "ShouldBeNilWithoutInstanceView",
+			InstanceView: nil,
+			Expected:     nil,
+		},
+		{
+			Name: "ShouldBeNilWithoutExtensionsDisksOrBootDiagnostics",
+			InstanceView: &armcompute.VirtualMachineScaleSetVMInstanceView{
+				Statuses: []*armcompute.InstanceViewStatus{
+					{Code: to.Ptr("PowerState/running")},
+				},
+			},
+			Expected: nil,
+		},
+		{
+			Name: "ShouldPopulateExtensionsAndDisks",
+			InstanceView: &armcompute.VirtualMachineScaleSetVMInstanceView{
+				Extensions: []*armcompute.VirtualMachineExtensionInstanceView{
+					{
+						Name: to.Ptr("CAPZ.Linux.Bootstrapping"),
+						Type: to.Ptr("Microsoft.Azure.ContainerUpstream.LinuxBootstrapping"),
+						Statuses: []*armcompute.InstanceViewStatus{
+							{Code: to.Ptr("ProvisioningState/succeeded"), Message: to.Ptr("Enable succeeded")},
+						},
+					},
+					{
+						Name: to.Ptr("CustomScript"),
+						Statuses: []*armcompute.InstanceViewStatus{
+							{Code: to.Ptr("ProvisioningState/failed/1"), Message: to.Ptr("Enable failed")},
+						},
+					},
+					{
+						Name: to.Ptr("NoStatus"),
+					},
+				},
+				Disks: []*armcompute.DiskInstanceView{
+					{
+						Name: to.Ptr("osdisk"),
+						Statuses: []*armcompute.InstanceViewStatus{
+							{Code: to.Ptr("ProvisioningState/succeeded"), Message: to.Ptr("Disk created")},
+						},
+					},
+					{
+						Name: to.Ptr("etcddisk"),
+						Statuses: []*armcompute.InstanceViewStatus{
+							{Code: to.Ptr("ProvisioningState/updating")},
+						},
+					},
+				},
+			},
+			Expected: &infrav1.VMInstanceView{
+				Extensions: []infrav1.VMExtensionStatus{
+					{
+						Name:              "CAPZ.Linux.Bootstrapping",
+						Type:              "Microsoft.Azure.ContainerUpstream.LinuxBootstrapping",
+						ProvisioningState: infrav1.Succeeded,
+						Message:           "Enable succeeded",
+					},
+					{
+						Name:              "CustomScript",
+						ProvisioningState: infrav1.Failed,
+						Message:           "Enable failed",
+					},
+					{
+						Name: "NoStatus",
+					},
+				},
+				Disks: []infrav1.VMDiskStatus{
+					{
+						Name:              "osdisk",
+						ProvisioningState: infrav1.Succeeded,
+						Message:           "Disk created",
+					},
+					{
+						Name:              "etcddisk",
+						ProvisioningState: infrav1.Updating,
+					},
+				},
+			},
+		},
+		{
+			Name: "ShouldBeAvailableWithoutBootDiagnosticsStatus",
+			InstanceView: &armcompute.VirtualMachineScaleSetVMInstanceView{
+				BootDiagnostics: &armcompute.BootDiagnosticsInstanceView{},
+			},
+			Expected: &infrav1.VMInstanceView{
+				BootDiagnostics: &infrav1.VMBootDiagnosticsStatus{Available: true},
+			},
+		},
+		{
+			Name: "ShouldBeUnavailableWithBootDiagnosticsError",
+			InstanceView: &armcompute.VirtualMachineScaleSetVMInstanceView{
+				BootDiagnostics: &armcompute.BootDiagnosticsInstanceView{
+					Status: &armcompute.InstanceViewStatus{
+						Level:   to.Ptr(armcompute.StatusLevelTypesError),
+						Message: to.Ptr("storage account not found"),
+					},
+				},
+			},
+			Expected: &infrav1.VMInstanceView{
+				BootDiagnostics: &infrav1.VMBootDiagnosticsStatus{Available: false, Message: "storage account not found"},
+			},
+		},
+		{
+			Name: "ShouldTruncateLongMessages",
+			InstanceView: &armcompute.VirtualMachineScaleSetVMInstanceView{
+				Extensions: []*armcompute.VirtualMachineExtensionInstanceView{
+					{
+						Name: to.Ptr("CustomScript"),
+						Statuses: []*armcompute.InstanceViewStatus{
+							{Code: to.Ptr("ProvisioningState/failed"), Message: to.Ptr(strings.Repeat("a", 1000))},
+						},
+					},
+				},
+			},
+			Expected: &infrav1.VMInstanceView{
+				Extensions: []infrav1.VMExtensionStatus{
+					{
+						Name:              "CustomScript",
+						ProvisioningState: infrav1.Failed,
+						Message:           strings.Repeat("a", 509) + "...",
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(converters.SDKToVMSSVMInstanceView(c.InstanceView)).To(gomega.Equal(c.Expected))
+		})
+	}
+}
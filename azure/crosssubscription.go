@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+const (
+	// GalleryImageVersionsReadAction is the action the cluster identity must be allowed to perform on a gallery image to
+	// create VMs from its versions.
+	GalleryImageVersionsReadAction = "Microsoft.Compute/galleries/images/versions/read"
+	// DiskEncryptionSetsReadAction is the action the cluster identity must be allowed to perform on a disk encryption
+	// set to encrypt disks with it.
+	DiskEncryptionSetsReadAction = "Microsoft.Compute/diskEncryptionSets/read"
+)
+
+// CrossSubscriptionResource is a resource used by a machine which is in another subscription than the cluster.
+type CrossSubscriptionResource struct {
+	// ID is the resource ID of the resource, the scope the cluster identity needs access to.
+	ID string
+	// SubscriptionID is the subscription of the resource.
+	SubscriptionID string
+	// Action is the action the cluster identity must be allowed to perform on the resource.
+	Action string
+}
+
+// CrossSubscriptionResources returns the Azure Compute Gallery images and the disk encryption sets of a machine which
+// are in another subscription than the given cluster subscription. The gallery image, rather than the image version,
+// is returned so that the access to the image outlives its versions. Community and directly shared gallery images
+// aren't subject to role-based access control and aren't returned.
+func CrossSubscriptionResources(subscriptionID string, image *infrav1.Image, osDisk infrav1.OSDisk, dataDisks []infrav1.DataDisk) []CrossSubscriptionResource {
+	var resources []CrossSubscriptionResource
+	seen := make(map[string]struct{})
+	add := func(id, action string) {
+		resourceID, err := ParseResourceID(id)
+		if err != nil || strings.EqualFold(resourceID.SubscriptionID, subscriptionID) {
+			return
+		}
+		if _, ok := seen[strings.ToLower(id)]; ok {
+			return
+		}
+		seen[strings.ToLower(id)] = struct{}{}
+		resources = append(resources, CrossSubscriptionResource{
+			ID:             id,
+			SubscriptionID: resourceID.SubscriptionID,
+			Action:         action,
+		})
+	}
+
+	if id := galleryImageID(image); id != "" {
+		add(id, GalleryImageVersionsReadAction)
+	}
+	if osDisk.ManagedDisk != nil && osDisk.ManagedDisk.DiskEncryptionSet != nil && osDisk.ManagedDisk.DiskEncryptionSet.ID != "" {
+		add(osDisk.ManagedDisk.DiskEncryptionSet.ID, DiskEncryptionSetsReadAction)
+	}
+	for _, disk := range dataDisks {
+		if disk.ManagedDisk != nil && disk.ManagedDisk.DiskEncryptionSet != nil && disk.ManagedDisk.DiskEncryptionSet.ID != "" {
+			add(disk.ManagedDisk.DiskEncryptionSet.ID, DiskEncryptionSetsReadAction)
+		}
+	}
+
+	return resources
+}
+
+// SubscriptionBoundResources returns the resource IDs of the network interfaces and the existing data disks of a
+// machine which are in another subscription than the given cluster subscription. Unlike gallery images and disk
+// encryption sets, the network interfaces and the disks of a virtual machine must be in its subscription.
+func SubscriptionBoundResources(subscriptionID string, networkInterfaces []infrav1.AzureNetworkInterface, dataDisks []infrav1.DataDisk) []string {
+	var ids []string
+	add := func(id string) {
+		resourceID, err := ParseResourceID(id)
+		if err != nil || strings.EqualFold(resourceID.SubscriptionID, subscriptionID) {
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	for _, nic := range networkInterfaces {
+		if nic.ID != "" {
+			add(nic.ID)
+		}
+	}
+	for _, disk := range dataDisks {
+		if disk.ExistingDiskID != "" {
+			add(disk.ExistingDiskID)
+		}
+	}
+
+	return ids
+}
+
+// galleryImageID returns the resource ID of the private Azure Compute Gallery image of an image, if any.
+func galleryImageID(image *infrav1.Image) string {
+	idTemplate := "/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/galleries/%s/images/%s"
+
+	switch {
+	case image == nil:
+		return ""
+	case image.ComputeGallery != nil && image.ComputeGallery.SubscriptionID != nil && image.ComputeGallery.ResourceGroup != nil:
+		return fmt.Sprintf(idTemplate, pointer.StringDeref(image.ComputeGallery.SubscriptionID, ""), pointer.StringDeref(image.ComputeGallery.ResourceGroup, ""),
+			image.ComputeGallery.Gallery, image.ComputeGallery.Name)
+	case image.SharedGallery != nil:
+		return fmt.Sprintf(idTemplate, image.SharedGallery.SubscriptionID, image.SharedGallery.ResourceGroup,
+			image.SharedGallery.Gallery, image.SharedGallery.Name)
+	case image.ID != nil:
+		resourceID, err := ParseResourceID(*image.ID)
+		if err == nil && IsResourceType(resourceID, GalleryImageVersionResourceType) {
+			return resourceID.Parent.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestCrossSubscriptionResources(t *testing.T) {
+	const (
+		imageID = "/subscriptions/456/resourceGroups/images-rg/providers/Microsoft.Compute/galleries/my-gallery/images/my-image"
+		desID   = "/subscriptions/789/resourceGroups/keys-rg/providers/Microsoft.Compute/diskEncryptionSets/my-des"
+		localID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/diskEncryptionSets/my-des"
+	)
+	encrypted := func(id string) *infrav1.ManagedDiskParameters {
+		return &infrav1.ManagedDiskParameters{DiskEncryptionSet: &infrav1.DiskEncryptionSetParameters{ID: id}}
+	}
+
+	testcases := []struct {
+		name      string
+		image     *infrav1.Image
+		osDisk    infrav1.OSDisk
+		dataDisks []infrav1.DataDisk
+		expected  []CrossSubscriptionResource
+	}{
+		{
+			name:     "marketplace image",
+			image:    &infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{}},
+			expected: nil,
+		},
+		{
+			name: "compute gallery image in another subscription",
+			image: &infrav1.Image{ComputeGallery: &infrav1.AzureComputeGalleryImage{
+				Gallery:        "my-gallery",
+				Name:           "my-image",
+				Version:        "1.0.0",
+				SubscriptionID: to.Ptr("456"),
+				ResourceGroup:  to.Ptr("images-rg"),
+			}},
+			expected: []CrossSubscriptionResource{{ID: imageID, SubscriptionID: "456", Action: GalleryImageVersionsReadAction}},
+		},
+		{
+			name: "community gallery image",
+			image: &infrav1.Image{ComputeGallery: &infrav1.AzureComputeGalleryImage{
+				Gallery: "my-community-gallery",
+				Name:    "my-image",
+				Version: "1.0.0",
+			}},
+			expected: nil,
+		},
+		{
+			name:     "gallery image version ID",
+			image:    &infrav1.Image{ID: to.Ptr(imageID + "/versions/1.0.0")},
+			expected: []CrossSubscriptionResource{{ID: imageID, SubscriptionID: "456", Action: GalleryImageVersionsReadAction}},
+		},
+		{
+			name:  "disk encryption sets are returned once and only from other subscriptions",
+			image: &infrav1.Image{ID: to.Ptr("/subscriptions/456/resourceGroups/images-rg/providers/Microsoft.Compute/images/my-image")},
+			osDisk: infrav1.OSDisk{
+				ManagedDisk: encrypted(desID),
+			},
+			dataDisks: []infrav1.DataDisk{
+				{ManagedDisk: encrypted(desID)},
+				{ManagedDisk: encrypted(localID)},
+				{},
+			},
+			expected: []CrossSubscriptionResource{{ID: desID, SubscriptionID: "789", Action: DiskEncryptionSetsReadAction}},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(CrossSubscriptionResources("123", tc.image, tc.osDisk, tc.dataDisks)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestSubscriptionBoundResources(t *testing.T) {
+	const (
+		localNIC    = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-nic"
+		foreignNIC  = "/subscriptions/456/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-nic"
+		localDisk   = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-disk"
+		foreignDisk = "/subscriptions/456/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-disk"
+	)
+
+	testcases := []struct {
+		name              string
+		networkInterfaces []infrav1.AzureNetworkInterface
+		dataDisks         []infrav1.DataDisk
+		expected          []string
+	}{
+		{
+			name:              "network interfaces and disks created with the machine",
+			networkInterfaces: []infrav1.AzureNetworkInterface{{SubnetName: "node-subnet"}},
+			dataDisks:         []infrav1.DataDisk{{NameSuffix: "etcddisk", DiskSizeGB: 256}},
+			expected:          nil,
+		},
+		{
+			name:              "existing network interfaces and disks in the cluster subscription",
+			networkInterfaces: []infrav1.AzureNetworkInterface{{ID: localNIC}},
+			dataDisks:         []infrav1.DataDisk{{ExistingDiskID: localDisk}},
+			expected:          nil,
+		},
+		{
+			name:              "existing network interfaces and disks in another subscription",
+			networkInterfaces: []infrav1.AzureNetworkInterface{{ID: localNIC}, {ID: foreignNIC}},
+			dataDisks:         []infrav1.DataDisk{{ExistingDiskID: foreignDisk}},
+			expected:          []string{foreignNIC, foreignDisk},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(SubscriptionBoundResources("123", tc.networkInterfaces, tc.dataDisks)).To(Equal(tc.expected))
+		})
+	}
+}
@@ -19,9 +19,15 @@ package azure
 import (
 	"fmt"
 	"net/http"
-
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/azure"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/google/uuid"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	"sigs.k8s.io/cluster-api-provider-azure/version"
 )
@@ -40,6 +46,11 @@ const (
 	DefaultWindowsImageOfferID = "capi-windows"
 	// DefaultImagePublisherID is the default Azure Marketplace publisher ID.
 	DefaultImagePublisherID = "cncf-upstream"
+	// DefaultPublicGalleryName is the public name of the community gallery of the reference images.
+	DefaultPublicGalleryName = "ClusterAPI-f72ceb4f-5159-4c26-a0fe-2ea738f0d019"
+	// DefaultGalleryImagePrefix is the prefix of the names of the reference image definitions in the community
+	// gallery, followed by the OS and its version, e.g. "capi-ubun2-2204".
+	DefaultGalleryImagePrefix = "capi-"
 	// LatestVersion is the image version latest.
 	LatestVersion = "latest"
 )
@@ -55,6 +66,24 @@ const (
 	// DefaultWindowsOsAndVersion is the default Windows Server version to use when
 	// genearating default images for Windows nodes.
 	DefaultWindowsOsAndVersion = "windows-2019"
+	// DefaultWindows2022OsAndVersion is the default Windows Server version to use when
+	// generating default images for Windows nodes running Kubernetes 1.25 and later.
+	DefaultWindows2022OsAndVersion = "windows-2022"
+	// DefaultAzureLinuxOsAndVersion is the default Azure Linux version to use when
+	// generating default images for Azure Linux nodes.
+	DefaultAzureLinuxOsAndVersion = "azurelinux-2"
+)
+
+const (
+	// DefaultBootstrapDataContainerName is the default name of the blob container holding bootstrap data
+	// exceeding the maximum custom data size.
+	DefaultBootstrapDataContainerName = "capz-bootstrap-data"
+	// DefaultBootstrapDataSASDuration is the default validity of the SAS URL given to machines to download
+	// their bootstrap data.
+	DefaultBootstrapDataSASDuration = time.Hour
+	// DefaultFederatedTokenFile is the path the Azure AD workload identity webhook projects the service account token
+	// to in the pods using workload identity.
+	DefaultFederatedTokenFile = "/var/run/secrets/azure/tokens/azure-identity-token"
 )
 
 const (
@@ -90,6 +119,12 @@ const (
 	// azureBuiltInContributorID the ID of the Contributor role in Azure
 	// Ref: https://docs.microsoft.com/en-us/azure/role-based-access-control/built-in-roles
 	azureBuiltInContributorID = "b24988ac-6180-42a0-ab88-20f7382dd24c"
+	// azureBuiltInAcrPullID the ID of the AcrPull role in Azure
+	// Ref: https://docs.microsoft.com/en-us/azure/role-based-access-control/built-in-roles
+	azureBuiltInAcrPullID = "7f951dda-4ed3-4680-a7ca-43fe172d538d"
+	// azureBuiltInReaderID the ID of the Reader role in Azure
+	// Ref: https://docs.microsoft.com/en-us/azure/role-based-access-control/built-in-roles
+	azureBuiltInReaderID = "acdd72a7-3374-42d3-a999-8ff1c7d3c1b7"
 )
 
 const (
@@ -110,7 +145,7 @@ var (
 
 // GenerateBackendAddressPoolName generates a load balancer backend address pool name.
 func GenerateBackendAddressPoolName(lbName string) string {
-	return fmt.Sprintf("%s-%s", lbName, "backendPool")
+	return resourceName(BackendAddressPoolKind, fmt.Sprintf("%s-%s", lbName, "backendPool"))
 }
 
 // GenerateSubscriptionScope generates a role assignment scope that applies to all resources in the subscription.
@@ -123,9 +158,25 @@ func GenerateContributorRoleDefinitionID(subscriptionID string) string {
 	return fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", subscriptionID, azureBuiltInContributorID)
 }
 
+// GenerateAcrPullRoleDefinitionID generates the AcrPull role definition ID.
+func GenerateAcrPullRoleDefinitionID(subscriptionID string) string {
+	return fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", subscriptionID, azureBuiltInAcrPullID)
+}
+
+// GenerateReaderRoleDefinitionID generates the Reader role definition ID.
+func GenerateReaderRoleDefinitionID(subscriptionID string) string {
+	return fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", subscriptionID, azureBuiltInReaderID)
+}
+
+// GenerateRoleAssignmentName generates a role assignment name, which must be a GUID. The name is derived from the
+// scope, the role definition and the principal, so that the same role assignment is never created twice.
+func GenerateRoleAssignmentName(scope, roleDefinitionID, principalID string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(strings.ToLower(scope+roleDefinitionID+principalID))).String()
+}
+
 // GenerateOutboundBackendAddressPoolName generates a load balancer outbound backend address pool name.
 func GenerateOutboundBackendAddressPoolName(lbName string) string {
-	return fmt.Sprintf("%s-%s", lbName, "outboundBackendPool")
+	return resourceName(OutboundBackendAddressPoolKind, fmt.Sprintf("%s-%s", lbName, "outboundBackendPool"))
 }
 
 // GenerateFrontendIPConfigName generates a load balancer frontend IP config name.
@@ -145,7 +196,7 @@ func GenerateNodeOutboundIPName(clusterName string) string {
 
 // GenerateNodePublicIPName generates a node public IP name, based on the machine name.
 func GenerateNodePublicIPName(machineName string) string {
-	return fmt.Sprintf("pip-%s", machineName)
+	return resourceName(NodePublicIPKind, fmt.Sprintf("pip-%s", machineName))
 }
 
 // GenerateControlPlaneOutboundLBName generates the name of the control plane outbound LB.
@@ -160,7 +211,7 @@ func GenerateControlPlaneOutboundIPName(clusterName string) string {
 
 // GeneratePrivateDNSZoneName generates the name of a private DNS zone based on the cluster name.
 func GeneratePrivateDNSZoneName(clusterName string) string {
-	return fmt.Sprintf("%s.capz.io", clusterName)
+	return resourceName(PrivateDNSZoneKind, fmt.Sprintf("%s.capz.io", clusterName))
 }
 
 // GeneratePrivateFQDN generates the FQDN for a private API Server based on the private DNS zone name.
@@ -170,32 +221,48 @@ func GeneratePrivateFQDN(zoneName string) string {
 
 // GenerateVNetLinkName generates the name of a virtual network link name based on the vnet name.
 func GenerateVNetLinkName(vnetName string) string {
-	return fmt.Sprintf("%s-link", vnetName)
+	return resourceName(VNetLinkKind, fmt.Sprintf("%s-link", vnetName))
 }
 
 // GenerateNICName generates the name of a network interface based on the name of a VM.
 func GenerateNICName(machineName string) string {
-	return fmt.Sprintf("%s-nic", machineName)
+	return resourceName(NICKind, fmt.Sprintf("%s-nic", machineName))
 }
 
 // GeneratePublicNICName generates the name of a public network interface based on the name of a VM.
 func GeneratePublicNICName(machineName string) string {
-	return fmt.Sprintf("%s-public-nic", machineName)
+	return resourceName(PublicNICKind, fmt.Sprintf("%s-public-nic", machineName))
 }
 
 // GenerateOSDiskName generates the name of an OS disk based on the name of a VM.
 func GenerateOSDiskName(machineName string) string {
-	return fmt.Sprintf("%s_OSDisk", machineName)
+	return resourceName(OSDiskKind, fmt.Sprintf("%s_OSDisk", machineName))
 }
 
 // GenerateDataDiskName generates the name of a data disk based on the name of a VM.
 func GenerateDataDiskName(machineName, nameSuffix string) string {
-	return fmt.Sprintf("%s_%s", machineName, nameSuffix)
+	return resourceName(DataDiskKind, fmt.Sprintf("%s_%s", machineName, nameSuffix))
+}
+
+// GenerateSnapshotName generates the name of a snapshot based on the name of the disk it is taken from.
+func GenerateSnapshotName(diskName, suffix string) string {
+	return resourceName(SnapshotKind, fmt.Sprintf("%s-%s", diskName, suffix))
+}
+
+// GenerateDNSForwardingRulesetLinkName generates the name of the link of a DNS forwarding ruleset to a vnet, which is
+// unique across the clusters of a subscription linked to the same ruleset.
+func GenerateDNSForwardingRulesetLinkName(vnetResourceGroup, vnetName string) string {
+	return resourceName(DNSForwardingRulesetLinkKind, fmt.Sprintf("%s-%s", vnetResourceGroup, vnetName))
 }
 
 // GenerateVnetPeeringName generates the name for a peering between two vnets.
 func GenerateVnetPeeringName(sourceVnetName string, remoteVnetName string) string {
-	return fmt.Sprintf("%s-To-%s", sourceVnetName, remoteVnetName)
+	return resourceName(VnetPeeringKind, fmt.Sprintf("%s-To-%s", sourceVnetName, remoteVnetName))
+}
+
+// GenerateEventSubscriptionName generates the name of the Event Grid subscription for a cluster resource group.
+func GenerateEventSubscriptionName(clusterName string) string {
+	return resourceName(EventSubscriptionKind, fmt.Sprintf("capz-%s", clusterName))
 }
 
 // GenerateAvailabilitySetName generates the name of a availability set based on the cluster name and the node group.
@@ -203,7 +270,27 @@ func GenerateVnetPeeringName(sourceVnetName string, remoteVnetName string) strin
 // For control plane nodes, this will be `control-plane`.
 // For worker nodes, this will be the machine deployment name.
 func GenerateAvailabilitySetName(clusterName, nodeGroup string) string {
-	return fmt.Sprintf("%s_%s-as", clusterName, nodeGroup)
+	return resourceName(AvailabilitySetKind, fmt.Sprintf("%s_%s-as", clusterName, nodeGroup))
+}
+
+// GenerateCloudProviderSecretName generates the name of the cloud provider config secret generated for a cluster.
+func GenerateCloudProviderSecretName(clusterName string) string {
+	return fmt.Sprintf("%s-cloud-config", clusterName)
+}
+
+// GenerateLogAnalyticsWorkspaceName generates the name of the Log Analytics workspace created for a cluster.
+func GenerateLogAnalyticsWorkspaceName(clusterName string) string {
+	return resourceName(LogAnalyticsWorkspaceKind, fmt.Sprintf("%s-logs", clusterName))
+}
+
+// GenerateDataCollectionEndpointName generates the name of the data collection endpoint of a cluster.
+func GenerateDataCollectionEndpointName(clusterName string) string {
+	return resourceName(DataCollectionEndpointKind, fmt.Sprintf("%s-dce", clusterName))
+}
+
+// GenerateDataCollectionRuleName generates the name of the data collection rule of a cluster.
+func GenerateDataCollectionRuleName(clusterName string) string {
+	return resourceName(DataCollectionRuleKind, fmt.Sprintf("%s-dcr", clusterName))
 }
 
 // WithIndex appends the index as suffix to a generated name.
@@ -231,6 +318,26 @@ func SubnetID(subscriptionID, resourceGroup, vnetName, subnetName string) string
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/%s/subnets/%s", subscriptionID, resourceGroup, vnetName, subnetName)
 }
 
+// PolicyExemptionID returns the azure resource ID for a given policy exemption of a resource group.
+func PolicyExemptionID(subscriptionID, resourceGroup, exemptionName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Authorization/policyExemptions/%s", subscriptionID, resourceGroup, exemptionName)
+}
+
+// DNSResolverID returns the azure resource ID for a given DNS private resolver.
+func DNSResolverID(subscriptionID, resourceGroup, resolverName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/dnsResolvers/%s", subscriptionID, resourceGroup, resolverName)
+}
+
+// DNSResolverOutboundEndpointID returns the azure resource ID for a given outbound endpoint of a DNS private resolver.
+func DNSResolverOutboundEndpointID(subscriptionID, resourceGroup, resolverName, endpointName string) string {
+	return fmt.Sprintf("%s/outboundEndpoints/%s", DNSResolverID(subscriptionID, resourceGroup, resolverName), endpointName)
+}
+
+// DNSForwardingRulesetID returns the azure resource ID for a given DNS forwarding ruleset.
+func DNSForwardingRulesetID(subscriptionID, resourceGroup, rulesetName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/dnsForwardingRulesets/%s", subscriptionID, resourceGroup, rulesetName)
+}
+
 // PublicIPID returns the azure resource ID for a given public IP.
 func PublicIPID(subscriptionID, resourceGroup, ipName string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/publicIPAddresses/%s", subscriptionID, resourceGroup, ipName)
@@ -281,6 +388,56 @@ func AvailabilitySetID(subscriptionID, resourceGroup, availabilitySetName string
 	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/availabilitySets/%s", subscriptionID, resourceGroup, availabilitySetName)
 }
 
+// DiskID returns the azure resource ID for a given managed disk.
+func DiskID(subscriptionID, resourceGroup, diskName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/disks/%s", subscriptionID, resourceGroup, diskName)
+}
+
+// ScaleSetID returns the azure resource ID for a given virtual machine scale set.
+func ScaleSetID(subscriptionID, resourceGroup, scaleSetName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachineScaleSets/%s", subscriptionID, resourceGroup, scaleSetName)
+}
+
+// LogAnalyticsWorkspaceID returns the azure resource ID for a given Log Analytics workspace.
+func LogAnalyticsWorkspaceID(subscriptionID, resourceGroup, workspaceName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.OperationalInsights/workspaces/%s", subscriptionID, resourceGroup, workspaceName)
+}
+
+// DataCollectionEndpointID returns the azure resource ID for a given data collection endpoint.
+func DataCollectionEndpointID(subscriptionID, resourceGroup, endpointName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Insights/dataCollectionEndpoints/%s", subscriptionID, resourceGroup, endpointName)
+}
+
+// DataCollectionRuleID returns the azure resource ID for a given data collection rule.
+func DataCollectionRuleID(subscriptionID, resourceGroup, ruleName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Insights/dataCollectionRules/%s", subscriptionID, resourceGroup, ruleName)
+}
+
+// ManagedClusterID returns the azure resource ID for a given managed cluster.
+func ManagedClusterID(subscriptionID, resourceGroup, clusterName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerService/managedClusters/%s", subscriptionID, resourceGroup, clusterName)
+}
+
+// GetAzureLinuxBootstrappingVMExtension returns the bootstrapping VM extension for Azure Linux machines.
+// The CAPZ Linux Bootstrapping extension is not published for Azure Linux, so the standard Custom Script
+// extension is used to run the same bootstrap check instead.
+func GetAzureLinuxBootstrappingVMExtension(cloud string, vmName string) *ExtensionSpec {
+	// currently, the bootstrap extension is only used in AzurePublicCloud.
+	if cloud != PublicCloud.Name {
+		return nil
+	}
+
+	return &ExtensionSpec{
+		Name:      "CustomScript",
+		VMName:    vmName,
+		Publisher: "Microsoft.Azure.Extensions",
+		Version:   "2.1",
+		ProtectedSettings: map[string]string{
+			"commandToExecute": LinuxBootstrapExtensionCommand,
+		},
+	}
+}
+
 // GetBootstrappingVMExtension returns the CAPZ Bootstrapping VM extension.
 // The CAPZ Bootstrapping extension is a simple clone of https://github.com/Azure/custom-script-extension-linux for Linux or
 // https://docs.microsoft.com/en-us/azure/virtual-machines/extensions/custom-script-windows for Windows.
@@ -288,7 +445,7 @@ func AvailabilitySetID(subscriptionID, resourceGroup, availabilitySetName string
 // Its role is to detect and report Kubernetes bootstrap failure or success.
 func GetBootstrappingVMExtension(osType string, cloud string, vmName string) *ExtensionSpec {
 	// currently, the bootstrap extension is only available in AzurePublicCloud.
-	if osType == LinuxOS && cloud == azure.PublicCloud.Name {
+	if osType == LinuxOS && cloud == PublicCloud.Name {
 		// The command checks for the existence of the bootstrapSentinelFile on the machine, with retries and sleep between retries.
 		return &ExtensionSpec{
 			Name:      "CAPZ.Linux.Bootstrapping",
@@ -299,7 +456,7 @@ func GetBootstrappingVMExtension(osType string, cloud string, vmName string) *Ex
 				"commandToExecute": LinuxBootstrapExtensionCommand,
 			},
 		}
-	} else if osType == WindowsOS && cloud == azure.PublicCloud.Name {
+	} else if osType == WindowsOS && cloud == PublicCloud.Name {
 		// This command for the existence of the bootstrapSentinelFile on the machine, with retries and sleep between reties.
 		// If the file is not present after the retries are exhausted the extension fails with return code '-2' - ERROR_FILE_NOT_FOUND.
 		return &ExtensionSpec{
@@ -316,40 +473,143 @@ func GetBootstrappingVMExtension(osType string, cloud string, vmName string) *Ex
 	return nil
 }
 
+// GetProxyExtensionCommand returns the command of a VM extension run with the given proxy environment variables, formatted
+// as NAME=value.
+func GetProxyExtensionCommand(osType string, command string, env []string) string {
+	var prefix strings.Builder
+	if osType == WindowsOS {
+		for _, variable := range env {
+			fmt.Fprintf(&prefix, "set \"%s\" && ", variable)
+		}
+	} else {
+		prefix.WriteString("export")
+		for _, variable := range env {
+			fmt.Fprintf(&prefix, " '%s'", variable)
+		}
+		prefix.WriteString("; ")
+	}
+	return prefix.String() + command
+}
+
+// GetCustomBootstrappingVMExtension returns the bootstrapping VM extension of the given source, for a VM of the given OS
+// type. The extension runs the same bootstrap check as the CAPZ Bootstrapping extension, or downloads and runs the
+// script of the source, in which case it must support the fileUris setting of the Custom Script extension.
+func GetCustomBootstrappingVMExtension(osType string, vmName string, source infrav1.BootstrapExtensionSource) *ExtensionSpec {
+	extension := &ExtensionSpec{
+		Name:      source.Type,
+		VMName:    vmName,
+		Publisher: source.Publisher,
+		Version:   source.Version,
+	}
+
+	command := LinuxBootstrapExtensionCommand
+	if osType == WindowsOS {
+		command = WindowsBootstrapExtensionCommand
+	}
+	if source.ScriptURL != "" {
+		script := source.ScriptURL
+		if u, err := url.Parse(source.ScriptURL); err == nil {
+			script = path.Base(u.Path)
+		}
+		command = fmt.Sprintf("sh %s", script)
+		if osType == WindowsOS {
+			command = fmt.Sprintf("powershell.exe -ExecutionPolicy Unrestricted -File %s", script)
+		}
+		extension.Settings = map[string]interface{}{
+			"fileUris": []string{source.ScriptURL},
+		}
+	}
+	extension.ProtectedSettings = map[string]string{
+		"commandToExecute": command,
+	}
+
+	return extension
+}
+
+// GetAzureMonitorAgentVMExtension returns the VM extension installing the Azure Monitor agent, which sends the metrics
+// and the logs of the VM to Azure Monitor as configured by the data collection rules associated with the VM.
+func GetAzureMonitorAgentVMExtension(osType string, vmName string) *ExtensionSpec {
+	name := "AzureMonitorLinuxAgent"
+	if osType == WindowsOS {
+		name = "AzureMonitorWindowsAgent"
+	}
+
+	return &ExtensionSpec{
+		Name:      name,
+		VMName:    vmName,
+		Publisher: "Microsoft.Azure.Monitor",
+		Version:   "1.0",
+	}
+}
+
+// GetAzureSecurityAgentVMExtension returns the VM extension installing the Azure security agent, which collects the
+// security events of the VM for Microsoft Defender for Cloud through the Azure Monitor agent.
+func GetAzureSecurityAgentVMExtension(osType string, vmName string) *ExtensionSpec {
+	name, version := "AzureSecurityLinuxAgent", "2.0"
+	if osType == WindowsOS {
+		name, version = "AzureSecurityWindowsAgent", "1.0"
+	}
+
+	return &ExtensionSpec{
+		Name:      name,
+		VMName:    vmName,
+		Publisher: "Microsoft.Azure.Security.Monitoring",
+		Version:   version,
+		Settings: map[string]interface{}{
+			"enableGenevaUpload": true,
+			"enableAutoConfig":   true,
+		},
+	}
+}
+
 // UserAgent specifies a string to append to the agent identifier.
 func UserAgent() string {
 	return fmt.Sprintf("cluster-api-provider-azure/%s", version.Get().String())
 }
 
-// SetAutoRestClientDefaults set authorizer and user agent for autorest client.
-func SetAutoRestClientDefaults(c *autorest.Client, auth autorest.Authorizer) {
-	c.Authorizer = auth
-	// Wrap the original Sender on the autorest.Client c.
-	// The wrapped Sender should set the x-ms-correlation-request-id on the given
-	// request, then pass the new request to the underlying Sender.
-	c.Sender = autorest.DecorateSender(c.Sender, msCorrelationIDSendDecorator)
-	// The default number of retries is 3. This means the client will attempt to retry operation results like resource
-	// conflicts (HTTP 409). For a reconciling controller, this is undesirable behavior since if the controller runs
-	// into an error reconciling, the controller would be better off to end with an error and try again later.
-	//
-	// Unfortunately, the naming of this field is a bit misleading. This is not actually "retry attempts", it actually
-	// is attempts. Setting this to a value of 0 will cause a panic in Go AutoRest.
-	c.RetryAttempts = 1
-	AutoRestClientAppendUserAgent(c, UserAgent())
-}
-
-// AutoRestClientAppendUserAgent autorest client calls "AddToUserAgent" but ignores errors.
-func AutoRestClientAppendUserAgent(c *autorest.Client, extension string) {
-	_ = c.AddToUserAgent(extension) // intentionally ignore error as it doesn't matter
-}
-
-func msCorrelationIDSendDecorator(snd autorest.Sender) autorest.Sender {
-	return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
-		// if the correlation ID was found in the request context, set
-		// it in the header
-		if corrID, ok := tele.CorrIDFromCtx(r.Context()); ok {
-			r.Header.Set(string(tele.CorrIDKeyVal), string(corrID))
-		}
-		return snd.Do(r)
-	})
+// ARMClientOptions returns the options of the Azure SDK ARM clients of the given Azure environment, e.g.
+// "AzurePublicCloud".
+func ARMClientOptions(azureEnvironment string) (*arm.ClientOptions, error) {
+	env, err := EnvironmentFromName(azureEnvironment)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &arm.ClientOptions{}
+	opts.Cloud = env.Cloud
+	opts.PerCallPolicies = []policy.Policy{
+		correlationIDPolicy{},
+		userAgentPolicy{},
+		apiVersionPolicy{},
+	}
+	// Don't retry the failed requests. Retrying operation results like resource conflicts (HTTP 409) is undesirable for
+	// a reconciling controller, which is better off ending with an error and trying again later.
+	opts.Retry.MaxRetries = -1
+
+	return opts, nil
+}
+
+// correlationIDPolicy sets the correlation ID of the requests.
+type correlationIDPolicy struct{}
+
+// Do sets the x-ms-correlation-request-id header of the request if the correlation ID was found in its context.
+func (correlationIDPolicy) Do(req *policy.Request) (*http.Response, error) {
+	if corrID, ok := tele.CorrIDFromCtx(req.Raw().Context()); ok {
+		req.Raw().Header.Set(string(tele.CorrIDKeyVal), string(corrID))
+	}
+	return req.Next()
+}
+
+// userAgentPolicy appends the user agent of the controller to the one of the Azure SDK. The telemetry options of the
+// Azure SDK aren't used since they truncate the application ID to 24 characters.
+type userAgentPolicy struct{}
+
+// Do appends the user agent of the controller to the User-Agent header of the request.
+func (userAgentPolicy) Do(req *policy.Request) (*http.Response, error) {
+	userAgent := UserAgent()
+	if existing := req.Raw().Header.Get("User-Agent"); existing != "" {
+		userAgent = existing + " " + userAgent
+	}
+	req.Raw().Header.Set("User-Agent", userAgent)
+	return req.Next()
 }
@@ -18,101 +18,128 @@ package azure
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"net/http/httptest"
-	"sync"
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
-func TestAutoRestClientAppendUserAgent(t *testing.T) {
-	g := NewWithT(t)
-	userAgent := "cluster-api-provider-azure/2.29.2"
-
-	type args struct {
-		c         *autorest.Client
-		extension string
-	}
+func TestARMClientOptions(t *testing.T) {
 	tests := []struct {
-		name string
-		args args
-		want string
+		name          string
+		cloudName     string
+		expectedCloud cloud.Configuration
+		expectedErr   string
 	}{
 		{
-			name: "should append extension to user agent if extension is not empty",
-			args: args{
-				c:         &autorest.Client{UserAgent: autorest.UserAgent()},
-				extension: userAgent,
-			},
-			want: fmt.Sprintf("%s %s", autorest.UserAgent(), userAgent),
+			name:          "should default to the public cloud",
+			cloudName:     "",
+			expectedCloud: cloud.AzurePublic,
+		},
+		{
+			name:          "should use the public cloud",
+			cloudName:     "AzurePublicCloud",
+			expectedCloud: cloud.AzurePublic,
+		},
+		{
+			name:          "should use the China cloud",
+			cloudName:     "AzureChinaCloud",
+			expectedCloud: cloud.AzureChina,
 		},
 		{
-			name: "should no changed if extension is empty",
-			args: args{
-				c:         &autorest.Client{UserAgent: userAgent},
-				extension: "",
-			},
-			want: userAgent,
+			name:          "should use the US government cloud",
+			cloudName:     "AzureUSGovernmentCloud",
+			expectedCloud: cloud.AzureGovernment,
+		},
+		{
+			name:        "should fail on an unknown cloud",
+			cloudName:   "AzureUnknownCloud",
+			expectedErr: "invalid cloud environment name \"AzureUnknownCloud\"",
 		},
 	}
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			AutoRestClientAppendUserAgent(tt.args.c, tt.args.extension)
-
-			g.Expect(tt.want).To(Equal(tt.args.c.UserAgent))
+			g := NewWithT(t)
+			opts, err := ARMClientOptions(tt.cloudName)
+			if tt.expectedErr != "" {
+				g.Expect(err).To(MatchError(tt.expectedErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(opts.Cloud).To(Equal(tt.expectedCloud))
+			g.Expect(opts.Retry.MaxRetries).To(Equal(int32(-1)))
 		})
 	}
 }
 
-func TestMSCorrelationIDSendDecorator(t *testing.T) {
+func TestARMClientOptionsPolicies(t *testing.T) {
 	g := NewWithT(t)
-	const corrID tele.CorrID = "TestMSCorrelationIDSendDecoratorCorrID"
+	const corrID tele.CorrID = "TestARMClientOptionsPoliciesCorrID"
 	ctx := context.WithValue(context.Background(), tele.CorrIDKeyVal, corrID)
 
-	// create a fake server so that the sender can send to
-	// somewhere
-	var wg sync.WaitGroup
-	receivedReqs := []*http.Request{}
-	wg.Add(1)
-	originHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		receivedReqs = append(receivedReqs, r)
-		wg.Done()
-	})
-
-	testSrv := httptest.NewServer(originHandler)
+	var received *http.Request
+	testSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+	}))
 	defer testSrv.Close()
 
-	// create a sender that sends to the fake server, then
-	// decorate the sender with the msCorrelationIDSendDecorator
-	origSender := autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
-		// preserve the incoming headers to the fake server, so that
-		// we can test that the fake server received the right
-		// correlation ID header.
-		req, err := http.NewRequest("GET", testSrv.URL, nil)
-		if err != nil {
-			return nil, err
-		}
-		req.Header = r.Header
-		return testSrv.Client().Do(req)
-	})
-	newSender := autorest.DecorateSender(origSender, msCorrelationIDSendDecorator)
-
-	// create a new HTTP request and send it via the new decorated sender
-	req, err := http.NewRequest("GET", "/abc", nil)
+	opts, err := ARMClientOptions("")
 	g.Expect(err).NotTo(HaveOccurred())
-
-	req = req.WithContext(ctx)
-	rsp, err := newSender.Do(req)
+	pl := runtime.NewPipeline("test", "v0.0.0", runtime.PipelineOptions{}, &opts.ClientOptions)
+	req, err := runtime.NewRequest(ctx, http.MethodGet, testSrv.URL)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = pl.Do(req)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(rsp.Body.Close()).To(Succeed())
-	wg.Wait()
-	g.Expect(len(receivedReqs)).To(Equal(1))
-	receivedReq := receivedReqs[0]
-	g.Expect(
-		receivedReq.Header.Get(string(tele.CorrIDKeyVal)),
-	).To(Equal(string(corrID)))
+
+	g.Expect(received).NotTo(BeNil())
+	g.Expect(received.Header.Get(string(tele.CorrIDKeyVal))).To(Equal(string(corrID)))
+	g.Expect(received.Header.Get("User-Agent")).To(HavePrefix("azsdk-go-test/v0.0.0"))
+	g.Expect(received.Header.Get("User-Agent")).To(HaveSuffix(" " + UserAgent()))
+}
+
+func TestGetProxyExtensionCommand(t *testing.T) {
+	g := NewWithT(t)
+
+	env := []string{"https_proxy=http://proxy:3128", "HTTPS_PROXY=http://proxy:3128"}
+	g.Expect(GetProxyExtensionCommand(LinuxOS, "test -f /run/done", env)).To(Equal(
+		"export 'https_proxy=http://proxy:3128' 'HTTPS_PROXY=http://proxy:3128'; test -f /run/done"))
+	g.Expect(GetProxyExtensionCommand(WindowsOS, "powershell.exe -Command \"exit 0\"", env)).To(Equal(
+		"set \"https_proxy=http://proxy:3128\" && set \"HTTPS_PROXY=http://proxy:3128\" && powershell.exe -Command \"exit 0\""))
+}
+
+func TestGetCustomBootstrappingVMExtension(t *testing.T) {
+	g := NewWithT(t)
+
+	source := infrav1.BootstrapExtensionSource{
+		Publisher: "Microsoft.Azure.Extensions",
+		Type:      "CustomScript",
+		Version:   "2.1",
+	}
+	extension := GetCustomBootstrappingVMExtension(LinuxOS, "vm-name", source)
+	g.Expect(extension).To(Equal(&ExtensionSpec{
+		Name:      "CustomScript",
+		VMName:    "vm-name",
+		Publisher: "Microsoft.Azure.Extensions",
+		Version:   "2.1",
+		ProtectedSettings: map[string]string{
+			"commandToExecute": LinuxBootstrapExtensionCommand,
+		},
+	}))
+
+	source.ScriptURL = "https://mirror.example.com/capz/check-bootstrap.sh?sv=2021"
+	extension = GetCustomBootstrappingVMExtension(LinuxOS, "vm-name", source)
+	g.Expect(extension.ProtectedSettings["commandToExecute"]).To(Equal("sh check-bootstrap.sh"))
+	g.Expect(extension.Settings).To(Equal(map[string]interface{}{
+		"fileUris": []string{"https://mirror.example.com/capz/check-bootstrap.sh?sv=2021"},
+	}))
+
+	source.ScriptURL = "https://mirror.example.com/capz/check-bootstrap.ps1"
+	extension = GetCustomBootstrappingVMExtension(WindowsOS, "vm-name", source)
+	g.Expect(extension.ProtectedSettings["commandToExecute"]).To(Equal("powershell.exe -ExecutionPolicy Unrestricted -File check-bootstrap.ps1"))
 }
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+// Environment is an Azure cloud environment, e.g. the Azure public cloud.
+type Environment struct {
+	// Name is the name of the environment, e.g. "AzurePublicCloud".
+	Name string
+	// ResourceManagerEndpoint is the endpoint of Azure Resource Manager, e.g. "https://management.azure.com/".
+	ResourceManagerEndpoint string
+	// ResourceManagerVMDNSSuffix is the DNS suffix of the public IP addresses with a domain name label, e.g.
+	// "cloudapp.azure.com".
+	ResourceManagerVMDNSSuffix string
+	// ActiveDirectoryEndpoint is the endpoint of Microsoft Entra ID, e.g. "https://login.microsoftonline.com/".
+	ActiveDirectoryEndpoint string
+	// Cloud is the configuration of the Azure SDK clients of the environment.
+	Cloud cloud.Configuration
+}
+
+var (
+	// PublicCloud is the Azure public cloud environment.
+	PublicCloud = Environment{
+		Name:                       "AzurePublicCloud",
+		ResourceManagerEndpoint:    "https://management.azure.com/",
+		ResourceManagerVMDNSSuffix: "cloudapp.azure.com",
+		ActiveDirectoryEndpoint:    "https://login.microsoftonline.com/",
+		Cloud:                      cloud.AzurePublic,
+	}
+	// ChinaCloud is the Azure China cloud environment.
+	ChinaCloud = Environment{
+		Name:                       "AzureChinaCloud",
+		ResourceManagerEndpoint:    "https://management.chinacloudapi.cn/",
+		ResourceManagerVMDNSSuffix: "cloudapp.chinacloudapi.cn",
+		ActiveDirectoryEndpoint:    "https://login.chinacloudapi.cn/",
+		Cloud:                      cloud.AzureChina,
+	}
+	// USGovernmentCloud is the Azure US government cloud environment.
+	USGovernmentCloud = Environment{
+		Name:                       "AzureUSGovernmentCloud",
+		ResourceManagerEndpoint:    "https://management.usgovcloudapi.net/",
+		ResourceManagerVMDNSSuffix: "cloudapp.usgovcloudapi.net",
+		ActiveDirectoryEndpoint:    "https://login.microsoftonline.us/",
+		Cloud:                      cloud.AzureGovernment,
+	}
+)
+
+// EnvironmentFromName returns the Azure cloud environment of the given name, e.g. "AzurePublicCloud", ignoring case.
+// An empty name is the Azure public cloud.
+func EnvironmentFromName(name string) (Environment, error) {
+	if name == "" {
+		return PublicCloud, nil
+	}
+	for _, env := range []Environment{PublicCloud, ChinaCloud, USGovernmentCloud} {
+		if strings.EqualFold(env.Name, name) {
+			return env, nil
+		}
+	}
+	return Environment{}, fmt.Errorf("invalid cloud environment name %q", name)
+}
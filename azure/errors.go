@@ -19,32 +19,82 @@ package azure
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
 const codeResourceGroupNotFound = "ResourceGroupNotFound"
 
+// allocationFailureCodes are the codes of the errors returned when VMs can't be allocated, because their VM size isn't
+// available to the subscription or has no capacity in their location or zones.
+var allocationFailureCodes = []string{
+	"SkuNotAvailable",
+	"AllocationFailed",
+	"ZonalAllocationFailed",
+	"OverconstrainedAllocationRequest",
+	"OverconstrainedZonalAllocationRequest",
+}
+
+// zonalAllocationFailureCodes are the codes of the errors returned when VMs can't be allocated because their VM size has
+// no capacity in their zones.
+var zonalAllocationFailureCodes = []string{
+	"ZonalAllocationFailed",
+	"OverconstrainedZonalAllocationRequest",
+}
+
 // ResourceGroupNotFound parses the error to check if it's a resource group not found error.
 func ResourceGroupNotFound(err error) bool {
-	derr := autorest.DetailedError{}
-	serr := &azure.ServiceError{}
-	return errors.As(err, &derr) && errors.As(derr.Original, &serr) && serr.Code == codeResourceGroupNotFound
+	var rerr *azcore.ResponseError
+	return errors.As(err, &rerr) && rerr.ErrorCode == codeResourceGroupNotFound
+}
+
+// AllocationFailure parses the error to check if it's a failure to allocate VMs, e.g. because their VM size has no
+// capacity in their zone.
+func AllocationFailure(err error) bool {
+	return hasErrorCode(err, allocationFailureCodes)
+}
+
+// ZonalAllocationFailure parses the error to check if it's a failure to allocate VMs because their VM size has no
+// capacity in their zones.
+func ZonalAllocationFailure(err error) bool {
+	return hasErrorCode(err, zonalAllocationFailureCodes)
+}
+
+// hasErrorCode returns whether the error is an Azure response error, or one of its details, with one of the codes.
+func hasErrorCode(err error, errorCodes []string) bool {
+	if err == nil {
+		return false
+	}
+	var rerr *azcore.ResponseError
+	if errors.As(err, &rerr) {
+		for _, errorCode := range errorCodes {
+			if rerr.ErrorCode == errorCode {
+				return true
+			}
+		}
+	}
+	// The details of the response errors are only available as part of their message.
+	for _, code := range errorCodes {
+		if strings.Contains(err.Error(), fmt.Sprintf(`"code": %q`, code)) {
+			return true
+		}
+	}
+	return false
 }
 
 // ResourceNotFound parses the error to check if it's a resource not found error.
 func ResourceNotFound(err error) bool {
-	derr := autorest.DetailedError{}
-	return errors.As(err, &derr) && derr.StatusCode == 404
+	var rerr *azcore.ResponseError
+	return errors.As(err, &rerr) && rerr.StatusCode == 404
 }
 
 // ResourceConflict parses the error to check if it's a resource conflict error (409).
 func ResourceConflict(err error) bool {
-	derr := autorest.DetailedError{}
-	return errors.As(err, &derr) && derr.StatusCode == 409
+	var rerr *azcore.ResponseError
+	return errors.As(err, &rerr) && rerr.StatusCode == 409
 }
 
 // VMDeletedError is returned when a virtual machine is deleted outside of capz.
@@ -126,6 +176,36 @@ func WithTerminalError(err error) ReconcileError {
 // OperationNotDoneError is used to represent a long-running operation that is not yet complete.
 type OperationNotDoneError struct {
 	Future *infrav1.Future
+
+	// Progress is the progress of the operation reported by Azure, if any.
+	Progress *OperationProgress
+}
+
+// OperationProgress is the progress of a long-running operation reported by Azure.
+type OperationProgress struct {
+	// Status is the current status of the operation, e.g. InProgress.
+	Status string
+
+	// PercentComplete is the percentage of the operation which is complete, if reported.
+	PercentComplete *float64
+
+	// StartTime is the time the operation started at, if reported.
+	StartTime *time.Time
+}
+
+// String returns the progress represented as a string.
+func (p OperationProgress) String() string {
+	var parts []string
+	if p.Status != "" {
+		parts = append(parts, p.Status)
+	}
+	if p.PercentComplete != nil {
+		parts = append(parts, fmt.Sprintf("%.0f%% complete", *p.PercentComplete))
+	}
+	if p.StartTime != nil {
+		parts = append(parts, fmt.Sprintf("started at %s", p.StartTime.UTC().Format(time.RFC3339)))
+	}
+	return strings.Join(parts, ", ")
 }
 
 // NewOperationNotDoneError returns a new OperationNotDoneError wrapping a Future.
@@ -145,6 +225,23 @@ func (onde OperationNotDoneError) Is(target error) bool {
 	return IsOperationNotDoneError(target)
 }
 
+// OperationProgressMessage returns the progress of the long-running operation of an OperationNotDoneError, formatted
+// to be appended to the message of a condition, or an empty string if Azure didn't report it.
+func OperationProgressMessage(err error) string {
+	reconcileErr := &ReconcileError{}
+	if errors.As(err, reconcileErr) {
+		return OperationProgressMessage(reconcileErr.error)
+	}
+	var notDoneErr OperationNotDoneError
+	if !errors.As(err, &notDoneErr) || notDoneErr.Progress == nil {
+		return ""
+	}
+	if progress := notDoneErr.Progress.String(); progress != "" {
+		return ": " + progress
+	}
+	return ""
+}
+
 // IsOperationNotDoneError returns true if the target is an OperationNotDoneError.
 func IsOperationNotDoneError(target error) bool {
 	reconcileErr := &ReconcileError{}
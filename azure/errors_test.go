@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestOperationProgressMessage(t *testing.T) {
+	future := &infrav1.Future{
+		Type:          infrav1.PutFuture,
+		ResourceGroup: "test-group",
+		Name:          "test-resource",
+	}
+	startTime := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "not an operation not done error",
+			err:      errors.New("boom"),
+			expected: "",
+		},
+		{
+			name:     "operation not done error without progress",
+			err:      NewOperationNotDoneError(future),
+			expected: "",
+		},
+		{
+			name: "operation not done error with a status",
+			err: OperationNotDoneError{
+				Future:   future,
+				Progress: &OperationProgress{Status: "InProgress"},
+			},
+			expected: ": InProgress",
+		},
+		{
+			name: "transient operation not done error with the full progress",
+			err: WithTransientError(OperationNotDoneError{
+				Future: future,
+				Progress: &OperationProgress{
+					Status:          "InProgress",
+					PercentComplete: to.Ptr(42.4),
+					StartTime:       &startTime,
+				},
+			}, time.Minute),
+			expected: ": InProgress, 42% complete, started at 2022-06-01T10:00:00Z",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			g.Expect(OperationProgressMessage(c.err)).To(Equal(c.expected))
+		})
+	}
+}
+
+func TestAllocationFailure(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected bool
+		zonal    bool
+	}{
+		{
+			name: "nil error",
+			err:  nil,
+		},
+		{
+			name: "other service error",
+			err:  responseError(`{"error": {"code": "InternalServerError", "message": "Internal server error."}}`),
+		},
+		{
+			name:     "VM size not available",
+			err:      responseError(`{"error": {"code": "SkuNotAvailable", "message": "The requested VM size is not available."}}`),
+			expected: true,
+		},
+		{
+			name:     "zonal allocation failure",
+			err:      responseError(`{"error": {"code": "ZonalAllocationFailed", "message": "Allocation failed."}}`),
+			expected: true,
+			zonal:    true,
+		},
+		{
+			name: "zonal allocation failure in the details",
+			err: responseError(`{"error": {"code": "OverconstrainedAllocationRequest", "message": "Allocation failed.",
+				"details": [{"code": "OverconstrainedZonalAllocationRequest", "message": "Allocation failed."}]}}`),
+			expected: true,
+			zonal:    true,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+			g.Expect(AllocationFailure(c.err)).To(Equal(c.expected))
+			g.Expect(ZonalAllocationFailure(c.err)).To(Equal(c.zonal))
+		})
+	}
+}
+
+// responseError returns the error an Azure client returns for a failed response with the given body.
+func responseError(body string) error {
+	return runtime.NewResponseError(&http.Response{
+		StatusCode: http.StatusConflict,
+		Status:     http.StatusText(http.StatusConflict),
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    &http.Request{Method: http.MethodPut, URL: &url.URL{}},
+	})
+}
@@ -19,8 +19,9 @@ package azure
 import (
 	"context"
 
-	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
@@ -37,7 +38,18 @@ type ServiceReconciler interface {
 	Reconciler
 }
 
-// Authorizer is an interface which can get the subscription ID, base URI, and authorizer for an Azure service.
+// ResourceExporter is implemented by the services which can add the desired state of their resources to an ARM template.
+type ResourceExporter interface {
+	ExportResources(ctx context.Context, template *armtemplate.Template) error
+}
+
+// PermissionRequirer is implemented by the services which can list the actions the cluster identity must be allowed to
+// perform on the resource group of the cluster to reconcile their resources.
+type PermissionRequirer interface {
+	RequiredActions() []string
+}
+
+// Authorizer is an interface which can get the subscription ID, base URI, and token credential for an Azure service.
 type Authorizer interface {
 	SubscriptionID() string
 	ClientID() string
@@ -45,7 +57,7 @@ type Authorizer interface {
 	CloudEnvironment() string
 	TenantID() string
 	BaseURI() string
-	Authorizer() autorest.Authorizer
+	Token() azcore.TokenCredential
 	HashKey() string
 }
 
@@ -75,12 +87,29 @@ type ClusterDescriber interface {
 	ResourceGroup() string
 	ClusterName() string
 	Location() string
+	ExtendedLocation() *infrav1.ExtendedLocationSpec
 	AdditionalTags() infrav1.Tags
 	AvailabilitySetEnabled() bool
 	CloudProviderConfigOverrides() *infrav1.CloudProviderConfigOverrides
+	BootstrapDataStorage() *infrav1.BootstrapDataStorage
+	ProxyConfig() *infrav1.ProxyConfig
+	BootstrapExtension() *infrav1.BootstrapExtension
+	RegistryMirrors() []infrav1.RegistryMirror
+	Monitoring() *infrav1.AzureMonitoring
+	SecurityProfile() *infrav1.ClusterSecurityProfile
+	AttachedACRs() []string
+	CrossSubscriptionAccess() infrav1.CrossSubscriptionAccessPolicy
 	FailureDomains() []string
 }
 
+// Poller is an ongoing long-running operation of the Azure SDK for Go track 2, e.g. a *runtime.Poller.
+type Poller interface {
+	// Done returns true if the operation reached a terminal state.
+	Done() bool
+	// ResumeToken returns a token that can be used to resume polling the operation later.
+	ResumeToken() (string, error)
+}
+
 // AsyncStatusUpdater is an interface used to keep track of long running operations in Status that has Conditions and Futures.
 type AsyncStatusUpdater interface {
 	SetLongRunningOperationState(*infrav1.Future)
@@ -91,6 +120,12 @@ type AsyncStatusUpdater interface {
 	UpdatePatchStatus(clusterv1.ConditionType, string, error)
 }
 
+// ResourceTracker is an interface used to record the Azure resources created or updated by services in Status.
+type ResourceTracker interface {
+	SetResourceStatus(id, resourceType, service string)
+	DeleteResourceStatus(name, service string)
+}
+
 // ClusterScoper combines the ClusterDescriber and NetworkDescriber interfaces.
 type ClusterScoper interface {
 	ClusterDescriber
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+)
+
+// ListConfig configures ListPager.
+type ListConfig struct {
+	// Timeout bounds the time spent listing all the pages, within the deadline of the context.
+	Timeout time.Duration
+	// MaxPages is the maximum number of pages listed.
+	MaxPages int
+}
+
+// ListOption is the modifier function used to configure ListPager.
+type ListOption func(*ListConfig)
+
+// WithListTimeout returns a ListOption bounding the time spent listing all the pages.
+func WithListTimeout(timeout time.Duration) ListOption {
+	return func(cfg *ListConfig) {
+		cfg.Timeout = timeout
+	}
+}
+
+// WithMaxPages returns a ListOption bounding the number of pages listed.
+func WithMaxPages(maxPages int) ListOption {
+	return func(cfg *ListConfig) {
+		cfg.MaxPages = maxPages
+	}
+}
+
+// ListLimitError is returned by ListPager when a list operation has more pages than its page limit.
+type ListLimitError struct {
+	Description string
+	MaxPages    int
+}
+
+// Error returns the error message.
+func (e ListLimitError) Error() string {
+	return fmt.Sprintf("listing %s exceeded the limit of %d pages", e.Description, e.MaxPages)
+}
+
+// IsListLimitError returns whether the error, or one of the errors it wraps, is a ListLimitError.
+func IsListLimitError(err error) bool {
+	var limitErr ListLimitError
+	return errors.As(err, &limitErr)
+}
+
+// newListConfig returns the configuration of a list operation with the given options.
+func newListConfig(opts ...ListOption) *ListConfig {
+	cfg := &ListConfig{
+		Timeout:  reconciler.DefaultAzureListTimeout,
+		MaxPages: reconciler.DefaultAzureListMaxPages,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// ListPager returns the results of all the pages of an Azure SDK list operation, e.g. the pager returned by the
+// NewListPager method of an SDK client. values returns the results of a page. The listing is bounded by the deadline of
+// the context, a timeout and a page limit, which default to reconciler.DefaultAzureListTimeout and
+// reconciler.DefaultAzureListMaxPages, so that unbounded result sets fail the listing rather than the whole reconcile.
+// The description, e.g. "resource SKUs", describes the listed resources in the errors.
+func ListPager[T any, P any](ctx context.Context, description string, pager *runtime.Pager[P], values func(page P) []*T, opts ...ListOption) ([]T, error) {
+	cfg := newListConfig(opts...)
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	var results []T
+	for pages := 0; pager.More(); pages++ {
+		if pages >= cfg.MaxPages {
+			return nil, ListLimitError{Description: description, MaxPages: cfg.MaxPages}
+		}
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			if pages == 0 {
+				return nil, errors.Wrapf(err, "failed to list %s", description)
+			}
+			return nil, errors.Wrapf(err, "failed to list %s after %d pages", description, pages)
+		}
+		for _, value := range values(page) {
+			if value != nil {
+				results = append(results, *value)
+			}
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+// fakePagerPage is a page of a track 2 list operation returning pages of strings.
+type fakePagerPage struct {
+	Value []*string
+	Index int
+}
+
+// newFakePager returns a pager over the given pages, which fails fetching the page at index errAt with err.
+func newFakePager(pages [][]string, err error, errAt int) *runtime.Pager[fakePagerPage] {
+	return runtime.NewPager(runtime.PagingHandler[fakePagerPage]{
+		More: func(page fakePagerPage) bool {
+			return page.Index+1 < len(pages)
+		},
+		Fetcher: func(ctx context.Context, page *fakePagerPage) (fakePagerPage, error) {
+			if err := ctx.Err(); err != nil {
+				return fakePagerPage{}, err
+			}
+			index := 0
+			if page != nil {
+				index = page.Index + 1
+			}
+			if err != nil && index == errAt {
+				return fakePagerPage{}, err
+			}
+			if len(pages) == 0 {
+				return fakePagerPage{Index: index}, nil
+			}
+			return fakePagerPage{Value: to.SliceOfPtrs(pages[index]...), Index: index}, nil
+		},
+	})
+}
+
+func TestListPager(t *testing.T) {
+	tests := []struct {
+		name          string
+		pages         [][]string
+		err           error
+		errAt         int
+		opts          []ListOption
+		expected      []string
+		expectedError string
+		limitErr      bool
+	}{
+		{
+			name:     "no results",
+			expected: nil,
+		},
+		{
+			name:     "results of all pages",
+			pages:    [][]string{{"a", "b"}, {"c"}, {"d"}},
+			expected: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:          "error listing the first page",
+			pages:         [][]string{{"a"}},
+			err:           errors.New("Internal Server Error"),
+			errAt:         0,
+			expectedError: "failed to list things: Internal Server Error",
+		},
+		{
+			name:          "error listing a next page",
+			pages:         [][]string{{"a"}, {"b"}, {"c"}},
+			err:           errors.New("Internal Server Error"),
+			errAt:         2,
+			expectedError: "failed to list things after 2 pages: Internal Server Error",
+		},
+		{
+			name:          "more pages than the page limit",
+			pages:         [][]string{{"a"}, {"b"}, {"c"}},
+			opts:          []ListOption{WithMaxPages(2)},
+			expectedError: "listing things exceeded the limit of 2 pages",
+			limitErr:      true,
+		},
+		{
+			name:     "as many pages as the page limit",
+			pages:    [][]string{{"a"}, {"b"}},
+			opts:     []ListOption{WithMaxPages(2)},
+			expected: []string{"a", "b"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewWithT(t)
+
+			pager := newFakePager(tc.pages, tc.err, tc.errAt)
+			results, err := ListPager(context.Background(), "things", pager, func(page fakePagerPage) []*string {
+				return page.Value
+			}, tc.opts...)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				g.Expect(IsListLimitError(err)).To(Equal(tc.limitErr))
+				g.Expect(results).To(BeNil())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(results).To(Equal(tc.expected))
+			}
+		})
+	}
+}
+
+func TestListPagerContextDeadline(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// the listing is bounded by the list timeout when the deadline of the context is later.
+	pager := runtime.NewPager(runtime.PagingHandler[fakePagerPage]{
+		More: func(page fakePagerPage) bool {
+			return false
+		},
+		Fetcher: func(ctx context.Context, page *fakePagerPage) (fakePagerPage, error) {
+			deadline, ok := ctx.Deadline()
+			g.Expect(ok).To(BeTrue())
+			g.Expect(time.Until(deadline)).To(BeNumerically("<=", time.Second))
+			return fakePagerPage{}, nil
+		},
+	})
+	_, err := ListPager(ctx, "things", pager, func(page fakePagerPage) []*string {
+		return page.Value
+	}, WithListTimeout(time.Second))
+	g.Expect(err).NotTo(HaveOccurred())
+}
@@ -24,9 +24,10 @@ import (
 	context "context"
 	reflect "reflect"
 
-	autorest "github.com/Azure/go-autorest/autorest"
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	gomock "github.com/golang/mock/gomock"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	armtemplate "sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	v1beta10 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
@@ -161,6 +162,80 @@ func (mr *MockServiceReconcilerMockRecorder) Reconcile(ctx interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockServiceReconciler)(nil).Reconcile), ctx)
 }
 
+// MockResourceExporter is a mock of ResourceExporter interface.
+type MockResourceExporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockResourceExporterMockRecorder
+}
+
+// MockResourceExporterMockRecorder is the mock recorder for MockResourceExporter.
+type MockResourceExporterMockRecorder struct {
+	mock *MockResourceExporter
+}
+
+// NewMockResourceExporter creates a new mock instance.
+func NewMockResourceExporter(ctrl *gomock.Controller) *MockResourceExporter {
+	mock := &MockResourceExporter{ctrl: ctrl}
+	mock.recorder = &MockResourceExporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResourceExporter) EXPECT() *MockResourceExporterMockRecorder {
+	return m.recorder
+}
+
+// ExportResources mocks base method.
+func (m *MockResourceExporter) ExportResources(ctx context.Context, template *armtemplate.Template) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportResources", ctx, template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportResources indicates an expected call of ExportResources.
+func (mr *MockResourceExporterMockRecorder) ExportResources(ctx, template interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportResources", reflect.TypeOf((*MockResourceExporter)(nil).ExportResources), ctx, template)
+}
+
+// MockPermissionRequirer is a mock of PermissionRequirer interface.
+type MockPermissionRequirer struct {
+	ctrl     *gomock.Controller
+	recorder *MockPermissionRequirerMockRecorder
+}
+
+// MockPermissionRequirerMockRecorder is the mock recorder for MockPermissionRequirer.
+type MockPermissionRequirerMockRecorder struct {
+	mock *MockPermissionRequirer
+}
+
+// NewMockPermissionRequirer creates a new mock instance.
+func NewMockPermissionRequirer(ctrl *gomock.Controller) *MockPermissionRequirer {
+	mock := &MockPermissionRequirer{ctrl: ctrl}
+	mock.recorder = &MockPermissionRequirerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPermissionRequirer) EXPECT() *MockPermissionRequirerMockRecorder {
+	return m.recorder
+}
+
+// RequiredActions mocks base method.
+func (m *MockPermissionRequirer) RequiredActions() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequiredActions")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// RequiredActions indicates an expected call of RequiredActions.
+func (mr *MockPermissionRequirerMockRecorder) RequiredActions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequiredActions", reflect.TypeOf((*MockPermissionRequirer)(nil).RequiredActions))
+}
+
 // MockAuthorizer is a mock of Authorizer interface.
 type MockAuthorizer struct {
 	ctrl     *gomock.Controller
@@ -184,20 +259,6 @@ func (m *MockAuthorizer) EXPECT() *MockAuthorizerMockRecorder {
 	return m.recorder
 }
 
-// Authorizer mocks base method.
-func (m *MockAuthorizer) Authorizer() autorest.Authorizer {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authorizer")
-	ret0, _ := ret[0].(autorest.Authorizer)
-	return ret0
-}
-
-// Authorizer indicates an expected call of Authorizer.
-func (mr *MockAuthorizerMockRecorder) Authorizer() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockAuthorizer)(nil).Authorizer))
-}
-
 // BaseURI mocks base method.
 func (m *MockAuthorizer) BaseURI() string {
 	m.ctrl.T.Helper()
@@ -296,6 +357,20 @@ func (mr *MockAuthorizerMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockAuthorizer)(nil).TenantID))
 }
 
+// Token mocks base method.
+func (m *MockAuthorizer) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockAuthorizerMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockAuthorizer)(nil).Token))
+}
+
 // MockNetworkDescriber is a mock of NetworkDescriber interface.
 type MockNetworkDescriber struct {
 	ctrl     *gomock.Controller
@@ -578,18 +653,18 @@ func (mr *MockClusterDescriberMockRecorder) AdditionalTags() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockClusterDescriber)(nil).AdditionalTags))
 }
 
-// Authorizer mocks base method.
-func (m *MockClusterDescriber) Authorizer() autorest.Authorizer {
+// AttachedACRs mocks base method.
+func (m *MockClusterDescriber) AttachedACRs() []string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authorizer")
-	ret0, _ := ret[0].(autorest.Authorizer)
+	ret := m.ctrl.Call(m, "AttachedACRs")
+	ret0, _ := ret[0].([]string)
 	return ret0
 }
 
-// Authorizer indicates an expected call of Authorizer.
-func (mr *MockClusterDescriberMockRecorder) Authorizer() *gomock.Call {
+// AttachedACRs indicates an expected call of AttachedACRs.
+func (mr *MockClusterDescriberMockRecorder) AttachedACRs() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockClusterDescriber)(nil).Authorizer))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachedACRs", reflect.TypeOf((*MockClusterDescriber)(nil).AttachedACRs))
 }
 
 // AvailabilitySetEnabled mocks base method.
@@ -620,6 +695,34 @@ func (mr *MockClusterDescriberMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockClusterDescriber)(nil).BaseURI))
 }
 
+// BootstrapDataStorage mocks base method.
+func (m *MockClusterDescriber) BootstrapDataStorage() *v1beta1.BootstrapDataStorage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorage")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataStorage)
+	return ret0
+}
+
+// BootstrapDataStorage indicates an expected call of BootstrapDataStorage.
+func (mr *MockClusterDescriberMockRecorder) BootstrapDataStorage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorage", reflect.TypeOf((*MockClusterDescriber)(nil).BootstrapDataStorage))
+}
+
+// BootstrapExtension mocks base method.
+func (m *MockClusterDescriber) BootstrapExtension() *v1beta1.BootstrapExtension {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapExtension")
+	ret0, _ := ret[0].(*v1beta1.BootstrapExtension)
+	return ret0
+}
+
+// BootstrapExtension indicates an expected call of BootstrapExtension.
+func (mr *MockClusterDescriberMockRecorder) BootstrapExtension() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapExtension", reflect.TypeOf((*MockClusterDescriber)(nil).BootstrapExtension))
+}
+
 // ClientID mocks base method.
 func (m *MockClusterDescriber) ClientID() string {
 	m.ctrl.T.Helper()
@@ -690,6 +793,34 @@ func (mr *MockClusterDescriberMockRecorder) ClusterName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockClusterDescriber)(nil).ClusterName))
 }
 
+// CrossSubscriptionAccess mocks base method.
+func (m *MockClusterDescriber) CrossSubscriptionAccess() v1beta1.CrossSubscriptionAccessPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CrossSubscriptionAccess")
+	ret0, _ := ret[0].(v1beta1.CrossSubscriptionAccessPolicy)
+	return ret0
+}
+
+// CrossSubscriptionAccess indicates an expected call of CrossSubscriptionAccess.
+func (mr *MockClusterDescriberMockRecorder) CrossSubscriptionAccess() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CrossSubscriptionAccess", reflect.TypeOf((*MockClusterDescriber)(nil).CrossSubscriptionAccess))
+}
+
+// ExtendedLocation mocks base method.
+func (m *MockClusterDescriber) ExtendedLocation() *v1beta1.ExtendedLocationSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendedLocation")
+	ret0, _ := ret[0].(*v1beta1.ExtendedLocationSpec)
+	return ret0
+}
+
+// ExtendedLocation indicates an expected call of ExtendedLocation.
+func (mr *MockClusterDescriberMockRecorder) ExtendedLocation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendedLocation", reflect.TypeOf((*MockClusterDescriber)(nil).ExtendedLocation))
+}
+
 // FailureDomains mocks base method.
 func (m *MockClusterDescriber) FailureDomains() []string {
 	m.ctrl.T.Helper()
@@ -732,6 +863,48 @@ func (mr *MockClusterDescriberMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockClusterDescriber)(nil).Location))
 }
 
+// Monitoring mocks base method.
+func (m *MockClusterDescriber) Monitoring() *v1beta1.AzureMonitoring {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Monitoring")
+	ret0, _ := ret[0].(*v1beta1.AzureMonitoring)
+	return ret0
+}
+
+// Monitoring indicates an expected call of Monitoring.
+func (mr *MockClusterDescriberMockRecorder) Monitoring() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Monitoring", reflect.TypeOf((*MockClusterDescriber)(nil).Monitoring))
+}
+
+// ProxyConfig mocks base method.
+func (m *MockClusterDescriber) ProxyConfig() *v1beta1.ProxyConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyConfig")
+	ret0, _ := ret[0].(*v1beta1.ProxyConfig)
+	return ret0
+}
+
+// ProxyConfig indicates an expected call of ProxyConfig.
+func (mr *MockClusterDescriberMockRecorder) ProxyConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyConfig", reflect.TypeOf((*MockClusterDescriber)(nil).ProxyConfig))
+}
+
+// RegistryMirrors mocks base method.
+func (m *MockClusterDescriber) RegistryMirrors() []v1beta1.RegistryMirror {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryMirrors")
+	ret0, _ := ret[0].([]v1beta1.RegistryMirror)
+	return ret0
+}
+
+// RegistryMirrors indicates an expected call of RegistryMirrors.
+func (mr *MockClusterDescriberMockRecorder) RegistryMirrors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryMirrors", reflect.TypeOf((*MockClusterDescriber)(nil).RegistryMirrors))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockClusterDescriber) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -746,6 +919,20 @@ func (mr *MockClusterDescriberMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockClusterDescriber)(nil).ResourceGroup))
 }
 
+// SecurityProfile mocks base method.
+func (m *MockClusterDescriber) SecurityProfile() *v1beta1.ClusterSecurityProfile {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecurityProfile")
+	ret0, _ := ret[0].(*v1beta1.ClusterSecurityProfile)
+	return ret0
+}
+
+// SecurityProfile indicates an expected call of SecurityProfile.
+func (mr *MockClusterDescriberMockRecorder) SecurityProfile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityProfile", reflect.TypeOf((*MockClusterDescriber)(nil).SecurityProfile))
+}
+
 // SubscriptionID mocks base method.
 func (m *MockClusterDescriber) SubscriptionID() string {
 	m.ctrl.T.Helper()
@@ -774,6 +961,72 @@ func (mr *MockClusterDescriberMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockClusterDescriber)(nil).TenantID))
 }
 
+// Token mocks base method.
+func (m *MockClusterDescriber) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockClusterDescriberMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockClusterDescriber)(nil).Token))
+}
+
+// MockPoller is a mock of Poller interface.
+type MockPoller struct {
+	ctrl     *gomock.Controller
+	recorder *MockPollerMockRecorder
+}
+
+// MockPollerMockRecorder is the mock recorder for MockPoller.
+type MockPollerMockRecorder struct {
+	mock *MockPoller
+}
+
+// NewMockPoller creates a new mock instance.
+func NewMockPoller(ctrl *gomock.Controller) *MockPoller {
+	mock := &MockPoller{ctrl: ctrl}
+	mock.recorder = &MockPollerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPoller) EXPECT() *MockPollerMockRecorder {
+	return m.recorder
+}
+
+// Done mocks base method.
+func (m *MockPoller) Done() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Done")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Done indicates an expected call of Done.
+func (mr *MockPollerMockRecorder) Done() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Done", reflect.TypeOf((*MockPoller)(nil).Done))
+}
+
+// ResumeToken mocks base method.
+func (m *MockPoller) ResumeToken() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeToken")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeToken indicates an expected call of ResumeToken.
+func (mr *MockPollerMockRecorder) ResumeToken() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeToken", reflect.TypeOf((*MockPoller)(nil).ResumeToken))
+}
+
 // MockAsyncStatusUpdater is a mock of AsyncStatusUpdater interface.
 type MockAsyncStatusUpdater struct {
 	ctrl     *gomock.Controller
@@ -871,6 +1124,53 @@ func (mr *MockAsyncStatusUpdaterMockRecorder) UpdatePutStatus(arg0, arg1, arg2 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePutStatus", reflect.TypeOf((*MockAsyncStatusUpdater)(nil).UpdatePutStatus), arg0, arg1, arg2)
 }
 
+// MockResourceTracker is a mock of ResourceTracker interface.
+type MockResourceTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockResourceTrackerMockRecorder
+}
+
+// MockResourceTrackerMockRecorder is the mock recorder for MockResourceTracker.
+type MockResourceTrackerMockRecorder struct {
+	mock *MockResourceTracker
+}
+
+// NewMockResourceTracker creates a new mock instance.
+func NewMockResourceTracker(ctrl *gomock.Controller) *MockResourceTracker {
+	mock := &MockResourceTracker{ctrl: ctrl}
+	mock.recorder = &MockResourceTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResourceTracker) EXPECT() *MockResourceTrackerMockRecorder {
+	return m.recorder
+}
+
+// DeleteResourceStatus mocks base method.
+func (m *MockResourceTracker) DeleteResourceStatus(name, service string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteResourceStatus", name, service)
+}
+
+// DeleteResourceStatus indicates an expected call of DeleteResourceStatus.
+func (mr *MockResourceTrackerMockRecorder) DeleteResourceStatus(name, service interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResourceStatus", reflect.TypeOf((*MockResourceTracker)(nil).DeleteResourceStatus), name, service)
+}
+
+// SetResourceStatus mocks base method.
+func (m *MockResourceTracker) SetResourceStatus(id, resourceType, service string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetResourceStatus", id, resourceType, service)
+}
+
+// SetResourceStatus indicates an expected call of SetResourceStatus.
+func (mr *MockResourceTrackerMockRecorder) SetResourceStatus(id, resourceType, service interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResourceStatus", reflect.TypeOf((*MockResourceTracker)(nil).SetResourceStatus), id, resourceType, service)
+}
+
 // MockClusterScoper is a mock of ClusterScoper interface.
 type MockClusterScoper struct {
 	ctrl     *gomock.Controller
@@ -950,18 +1250,18 @@ func (mr *MockClusterScoperMockRecorder) AdditionalTags() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockClusterScoper)(nil).AdditionalTags))
 }
 
-// Authorizer mocks base method.
-func (m *MockClusterScoper) Authorizer() autorest.Authorizer {
+// AttachedACRs mocks base method.
+func (m *MockClusterScoper) AttachedACRs() []string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authorizer")
-	ret0, _ := ret[0].(autorest.Authorizer)
+	ret := m.ctrl.Call(m, "AttachedACRs")
+	ret0, _ := ret[0].([]string)
 	return ret0
 }
 
-// Authorizer indicates an expected call of Authorizer.
-func (mr *MockClusterScoperMockRecorder) Authorizer() *gomock.Call {
+// AttachedACRs indicates an expected call of AttachedACRs.
+func (mr *MockClusterScoperMockRecorder) AttachedACRs() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockClusterScoper)(nil).Authorizer))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachedACRs", reflect.TypeOf((*MockClusterScoper)(nil).AttachedACRs))
 }
 
 // AvailabilitySetEnabled mocks base method.
@@ -992,6 +1292,34 @@ func (mr *MockClusterScoperMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockClusterScoper)(nil).BaseURI))
 }
 
+// BootstrapDataStorage mocks base method.
+func (m *MockClusterScoper) BootstrapDataStorage() *v1beta1.BootstrapDataStorage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorage")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataStorage)
+	return ret0
+}
+
+// BootstrapDataStorage indicates an expected call of BootstrapDataStorage.
+func (mr *MockClusterScoperMockRecorder) BootstrapDataStorage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorage", reflect.TypeOf((*MockClusterScoper)(nil).BootstrapDataStorage))
+}
+
+// BootstrapExtension mocks base method.
+func (m *MockClusterScoper) BootstrapExtension() *v1beta1.BootstrapExtension {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapExtension")
+	ret0, _ := ret[0].(*v1beta1.BootstrapExtension)
+	return ret0
+}
+
+// BootstrapExtension indicates an expected call of BootstrapExtension.
+func (mr *MockClusterScoperMockRecorder) BootstrapExtension() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapExtension", reflect.TypeOf((*MockClusterScoper)(nil).BootstrapExtension))
+}
+
 // ClientID mocks base method.
 func (m *MockClusterScoper) ClientID() string {
 	m.ctrl.T.Helper()
@@ -1090,6 +1418,34 @@ func (mr *MockClusterScoperMockRecorder) ControlPlaneSubnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnet", reflect.TypeOf((*MockClusterScoper)(nil).ControlPlaneSubnet))
 }
 
+// CrossSubscriptionAccess mocks base method.
+func (m *MockClusterScoper) CrossSubscriptionAccess() v1beta1.CrossSubscriptionAccessPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CrossSubscriptionAccess")
+	ret0, _ := ret[0].(v1beta1.CrossSubscriptionAccessPolicy)
+	return ret0
+}
+
+// CrossSubscriptionAccess indicates an expected call of CrossSubscriptionAccess.
+func (mr *MockClusterScoperMockRecorder) CrossSubscriptionAccess() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CrossSubscriptionAccess", reflect.TypeOf((*MockClusterScoper)(nil).CrossSubscriptionAccess))
+}
+
+// ExtendedLocation mocks base method.
+func (m *MockClusterScoper) ExtendedLocation() *v1beta1.ExtendedLocationSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendedLocation")
+	ret0, _ := ret[0].(*v1beta1.ExtendedLocationSpec)
+	return ret0
+}
+
+// ExtendedLocation indicates an expected call of ExtendedLocation.
+func (mr *MockClusterScoperMockRecorder) ExtendedLocation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendedLocation", reflect.TypeOf((*MockClusterScoper)(nil).ExtendedLocation))
+}
+
 // FailureDomains mocks base method.
 func (m *MockClusterScoper) FailureDomains() []string {
 	m.ctrl.T.Helper()
@@ -1188,6 +1544,20 @@ func (mr *MockClusterScoperMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockClusterScoper)(nil).Location))
 }
 
+// Monitoring mocks base method.
+func (m *MockClusterScoper) Monitoring() *v1beta1.AzureMonitoring {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Monitoring")
+	ret0, _ := ret[0].(*v1beta1.AzureMonitoring)
+	return ret0
+}
+
+// Monitoring indicates an expected call of Monitoring.
+func (mr *MockClusterScoperMockRecorder) Monitoring() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Monitoring", reflect.TypeOf((*MockClusterScoper)(nil).Monitoring))
+}
+
 // NodeSubnets mocks base method.
 func (m *MockClusterScoper) NodeSubnets() []v1beta1.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -1230,6 +1600,34 @@ func (mr *MockClusterScoperMockRecorder) OutboundPoolName(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboundPoolName", reflect.TypeOf((*MockClusterScoper)(nil).OutboundPoolName), arg0)
 }
 
+// ProxyConfig mocks base method.
+func (m *MockClusterScoper) ProxyConfig() *v1beta1.ProxyConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyConfig")
+	ret0, _ := ret[0].(*v1beta1.ProxyConfig)
+	return ret0
+}
+
+// ProxyConfig indicates an expected call of ProxyConfig.
+func (mr *MockClusterScoperMockRecorder) ProxyConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyConfig", reflect.TypeOf((*MockClusterScoper)(nil).ProxyConfig))
+}
+
+// RegistryMirrors mocks base method.
+func (m *MockClusterScoper) RegistryMirrors() []v1beta1.RegistryMirror {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryMirrors")
+	ret0, _ := ret[0].([]v1beta1.RegistryMirror)
+	return ret0
+}
+
+// RegistryMirrors indicates an expected call of RegistryMirrors.
+func (mr *MockClusterScoperMockRecorder) RegistryMirrors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryMirrors", reflect.TypeOf((*MockClusterScoper)(nil).RegistryMirrors))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockClusterScoper) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -1244,6 +1642,20 @@ func (mr *MockClusterScoperMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockClusterScoper)(nil).ResourceGroup))
 }
 
+// SecurityProfile mocks base method.
+func (m *MockClusterScoper) SecurityProfile() *v1beta1.ClusterSecurityProfile {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecurityProfile")
+	ret0, _ := ret[0].(*v1beta1.ClusterSecurityProfile)
+	return ret0
+}
+
+// SecurityProfile indicates an expected call of SecurityProfile.
+func (mr *MockClusterScoperMockRecorder) SecurityProfile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityProfile", reflect.TypeOf((*MockClusterScoper)(nil).SecurityProfile))
+}
+
 // SetSubnet mocks base method.
 func (m *MockClusterScoper) SetSubnet(arg0 v1beta1.SubnetSpec) {
 	m.ctrl.T.Helper()
@@ -1312,6 +1724,20 @@ func (mr *MockClusterScoperMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockClusterScoper)(nil).TenantID))
 }
 
+// Token mocks base method.
+func (m *MockClusterScoper) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockClusterScoperMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockClusterScoper)(nil).Token))
+}
+
 // Vnet mocks base method.
 func (m *MockClusterScoper) Vnet() *v1beta1.VnetSpec {
 	m.ctrl.T.Helper()
@@ -1363,18 +1789,18 @@ func (mr *MockManagedClusterScoperMockRecorder) AdditionalTags() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockManagedClusterScoper)(nil).AdditionalTags))
 }
 
-// Authorizer mocks base method.
-func (m *MockManagedClusterScoper) Authorizer() autorest.Authorizer {
+// AttachedACRs mocks base method.
+func (m *MockManagedClusterScoper) AttachedACRs() []string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authorizer")
-	ret0, _ := ret[0].(autorest.Authorizer)
+	ret := m.ctrl.Call(m, "AttachedACRs")
+	ret0, _ := ret[0].([]string)
 	return ret0
 }
 
-// Authorizer indicates an expected call of Authorizer.
-func (mr *MockManagedClusterScoperMockRecorder) Authorizer() *gomock.Call {
+// AttachedACRs indicates an expected call of AttachedACRs.
+func (mr *MockManagedClusterScoperMockRecorder) AttachedACRs() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockManagedClusterScoper)(nil).Authorizer))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachedACRs", reflect.TypeOf((*MockManagedClusterScoper)(nil).AttachedACRs))
 }
 
 // AvailabilitySetEnabled mocks base method.
@@ -1405,6 +1831,34 @@ func (mr *MockManagedClusterScoperMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockManagedClusterScoper)(nil).BaseURI))
 }
 
+// BootstrapDataStorage mocks base method.
+func (m *MockManagedClusterScoper) BootstrapDataStorage() *v1beta1.BootstrapDataStorage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorage")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataStorage)
+	return ret0
+}
+
+// BootstrapDataStorage indicates an expected call of BootstrapDataStorage.
+func (mr *MockManagedClusterScoperMockRecorder) BootstrapDataStorage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorage", reflect.TypeOf((*MockManagedClusterScoper)(nil).BootstrapDataStorage))
+}
+
+// BootstrapExtension mocks base method.
+func (m *MockManagedClusterScoper) BootstrapExtension() *v1beta1.BootstrapExtension {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapExtension")
+	ret0, _ := ret[0].(*v1beta1.BootstrapExtension)
+	return ret0
+}
+
+// BootstrapExtension indicates an expected call of BootstrapExtension.
+func (mr *MockManagedClusterScoperMockRecorder) BootstrapExtension() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapExtension", reflect.TypeOf((*MockManagedClusterScoper)(nil).BootstrapExtension))
+}
+
 // ClientID mocks base method.
 func (m *MockManagedClusterScoper) ClientID() string {
 	m.ctrl.T.Helper()
@@ -1475,6 +1929,34 @@ func (mr *MockManagedClusterScoperMockRecorder) ClusterName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockManagedClusterScoper)(nil).ClusterName))
 }
 
+// CrossSubscriptionAccess mocks base method.
+func (m *MockManagedClusterScoper) CrossSubscriptionAccess() v1beta1.CrossSubscriptionAccessPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CrossSubscriptionAccess")
+	ret0, _ := ret[0].(v1beta1.CrossSubscriptionAccessPolicy)
+	return ret0
+}
+
+// CrossSubscriptionAccess indicates an expected call of CrossSubscriptionAccess.
+func (mr *MockManagedClusterScoperMockRecorder) CrossSubscriptionAccess() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CrossSubscriptionAccess", reflect.TypeOf((*MockManagedClusterScoper)(nil).CrossSubscriptionAccess))
+}
+
+// ExtendedLocation mocks base method.
+func (m *MockManagedClusterScoper) ExtendedLocation() *v1beta1.ExtendedLocationSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendedLocation")
+	ret0, _ := ret[0].(*v1beta1.ExtendedLocationSpec)
+	return ret0
+}
+
+// ExtendedLocation indicates an expected call of ExtendedLocation.
+func (mr *MockManagedClusterScoperMockRecorder) ExtendedLocation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendedLocation", reflect.TypeOf((*MockManagedClusterScoper)(nil).ExtendedLocation))
+}
+
 // FailureDomains mocks base method.
 func (m *MockManagedClusterScoper) FailureDomains() []string {
 	m.ctrl.T.Helper()
@@ -1517,6 +1999,20 @@ func (mr *MockManagedClusterScoperMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockManagedClusterScoper)(nil).Location))
 }
 
+// Monitoring mocks base method.
+func (m *MockManagedClusterScoper) Monitoring() *v1beta1.AzureMonitoring {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Monitoring")
+	ret0, _ := ret[0].(*v1beta1.AzureMonitoring)
+	return ret0
+}
+
+// Monitoring indicates an expected call of Monitoring.
+func (mr *MockManagedClusterScoperMockRecorder) Monitoring() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Monitoring", reflect.TypeOf((*MockManagedClusterScoper)(nil).Monitoring))
+}
+
 // NodeResourceGroup mocks base method.
 func (m *MockManagedClusterScoper) NodeResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -1531,6 +2027,34 @@ func (mr *MockManagedClusterScoperMockRecorder) NodeResourceGroup() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NodeResourceGroup", reflect.TypeOf((*MockManagedClusterScoper)(nil).NodeResourceGroup))
 }
 
+// ProxyConfig mocks base method.
+func (m *MockManagedClusterScoper) ProxyConfig() *v1beta1.ProxyConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyConfig")
+	ret0, _ := ret[0].(*v1beta1.ProxyConfig)
+	return ret0
+}
+
+// ProxyConfig indicates an expected call of ProxyConfig.
+func (mr *MockManagedClusterScoperMockRecorder) ProxyConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyConfig", reflect.TypeOf((*MockManagedClusterScoper)(nil).ProxyConfig))
+}
+
+// RegistryMirrors mocks base method.
+func (m *MockManagedClusterScoper) RegistryMirrors() []v1beta1.RegistryMirror {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryMirrors")
+	ret0, _ := ret[0].([]v1beta1.RegistryMirror)
+	return ret0
+}
+
+// RegistryMirrors indicates an expected call of RegistryMirrors.
+func (mr *MockManagedClusterScoperMockRecorder) RegistryMirrors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryMirrors", reflect.TypeOf((*MockManagedClusterScoper)(nil).RegistryMirrors))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockManagedClusterScoper) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -1545,6 +2069,20 @@ func (mr *MockManagedClusterScoperMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockManagedClusterScoper)(nil).ResourceGroup))
 }
 
+// SecurityProfile mocks base method.
+func (m *MockManagedClusterScoper) SecurityProfile() *v1beta1.ClusterSecurityProfile {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecurityProfile")
+	ret0, _ := ret[0].(*v1beta1.ClusterSecurityProfile)
+	return ret0
+}
+
+// SecurityProfile indicates an expected call of SecurityProfile.
+func (mr *MockManagedClusterScoperMockRecorder) SecurityProfile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityProfile", reflect.TypeOf((*MockManagedClusterScoper)(nil).SecurityProfile))
+}
+
 // SubscriptionID mocks base method.
 func (m *MockManagedClusterScoper) SubscriptionID() string {
 	m.ctrl.T.Helper()
@@ -1573,6 +2111,20 @@ func (mr *MockManagedClusterScoperMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockManagedClusterScoper)(nil).TenantID))
 }
 
+// Token mocks base method.
+func (m *MockManagedClusterScoper) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockManagedClusterScoperMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockManagedClusterScoper)(nil).Token))
+}
+
 // MockResourceSpecGetter is a mock of ResourceSpecGetter interface.
 type MockResourceSpecGetter struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"sync"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// ResourceKind identifies the kind of an Azure resource, or sub-resource, whose name CAPZ generates.
+type ResourceKind string
+
+const (
+	// BackendAddressPoolKind is the kind of the backend address pool of a load balancer.
+	BackendAddressPoolKind ResourceKind = "BackendAddressPool"
+	// OutboundBackendAddressPoolKind is the kind of the outbound backend address pool of a load balancer.
+	OutboundBackendAddressPoolKind ResourceKind = "OutboundBackendAddressPool"
+	// NodePublicIPKind is the kind of the public IP of a node.
+	NodePublicIPKind ResourceKind = "NodePublicIP"
+	// PrivateDNSZoneKind is the kind of the private DNS zone of a private cluster.
+	PrivateDNSZoneKind ResourceKind = "PrivateDNSZone"
+	// VNetLinkKind is the kind of the link of a private DNS zone to a virtual network.
+	VNetLinkKind ResourceKind = "VNetLink"
+	// NICKind is the kind of the network interface of a VM.
+	NICKind ResourceKind = "NIC"
+	// PublicNICKind is the kind of the public network interface of a VM.
+	PublicNICKind ResourceKind = "PublicNIC"
+	// OSDiskKind is the kind of the OS disk of a VM.
+	OSDiskKind ResourceKind = "OSDisk"
+	// DataDiskKind is the kind of a data disk of a VM.
+	DataDiskKind ResourceKind = "DataDisk"
+	// SnapshotKind is the kind of a disk snapshot.
+	SnapshotKind ResourceKind = "Snapshot"
+	// DNSForwardingRulesetLinkKind is the kind of the link of a DNS forwarding ruleset to a virtual network.
+	DNSForwardingRulesetLinkKind ResourceKind = "DNSForwardingRulesetLink"
+	// VnetPeeringKind is the kind of a virtual network peering.
+	VnetPeeringKind ResourceKind = "VnetPeering"
+	// EventSubscriptionKind is the kind of the Event Grid subscription of a cluster resource group.
+	EventSubscriptionKind ResourceKind = "EventSubscription"
+	// AvailabilitySetKind is the kind of an availability set.
+	AvailabilitySetKind ResourceKind = "AvailabilitySet"
+	// LogAnalyticsWorkspaceKind is the kind of the Log Analytics workspace of a cluster.
+	LogAnalyticsWorkspaceKind ResourceKind = "LogAnalyticsWorkspace"
+	// DataCollectionEndpointKind is the kind of the data collection endpoint of a cluster.
+	DataCollectionEndpointKind ResourceKind = "DataCollectionEndpoint"
+	// DataCollectionRuleKind is the kind of the data collection rule of a cluster.
+	DataCollectionRuleKind ResourceKind = "DataCollectionRule"
+)
+
+// NamingPolicy customizes the names CAPZ generates for Azure resources, e.g. to follow the naming convention of an
+// organization. The names must be deterministic: CAPZ generates them again at each reconciliation to find the
+// resources it created, so changing the policy of existing clusters orphans their resources.
+type NamingPolicy interface {
+	// Name returns the name of a resource of the given kind, given the name CAPZ generates for it by default.
+	Name(kind ResourceKind, defaultName string) string
+}
+
+// TaggingPolicy sets mandatory tags on the Azure resources of clusters, e.g. a cost center.
+type TaggingPolicy interface {
+	// Tags returns the tags to set on the Azure resources of a cluster. They take precedence over the additional
+	// tags of the CAPZ resources, but not over the tags CAPZ manages itself.
+	Tags(clusterName string) infrav1.Tags
+}
+
+// policies holds the naming and tagging policies injected at startup.
+var policies = struct {
+	sync.RWMutex
+	naming  NamingPolicy
+	tagging TaggingPolicy
+}{}
+
+// SetNamingPolicy sets the policy customizing the names CAPZ generates for Azure resources. The default names are
+// used when the policy is nil.
+func SetNamingPolicy(policy NamingPolicy) {
+	policies.Lock()
+	defer policies.Unlock()
+	policies.naming = policy
+}
+
+// SetTaggingPolicy sets the policy adding mandatory tags to the Azure resources of clusters. No tags are added when
+// the policy is nil.
+func SetTaggingPolicy(policy TaggingPolicy) {
+	policies.Lock()
+	defer policies.Unlock()
+	policies.tagging = policy
+}
+
+// resourceName returns the name of a resource of the given kind according to the naming policy.
+func resourceName(kind ResourceKind, defaultName string) string {
+	policies.RLock()
+	defer policies.RUnlock()
+	if policies.naming == nil {
+		return defaultName
+	}
+	return policies.naming.Name(kind, defaultName)
+}
+
+// MandatoryTags returns the tags the tagging policy sets on the Azure resources of a cluster.
+func MandatoryTags(clusterName string) infrav1.Tags {
+	policies.RLock()
+	defer policies.RUnlock()
+	if policies.tagging == nil {
+		return nil
+	}
+	return policies.tagging.Tags(clusterName)
+}
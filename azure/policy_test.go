@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+type prefixNamingPolicy struct{}
+
+func (prefixNamingPolicy) Name(kind ResourceKind, defaultName string) string {
+	if kind == NICKind {
+		return fmt.Sprintf("nic-%s", defaultName)
+	}
+	return defaultName
+}
+
+type costCenterTaggingPolicy struct{}
+
+func (costCenterTaggingPolicy) Tags(clusterName string) infrav1.Tags {
+	return infrav1.Tags{"costCenter": clusterName}
+}
+
+func TestNamingPolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(GenerateNICName("my-vm")).To(Equal("my-vm-nic"))
+
+	SetNamingPolicy(prefixNamingPolicy{})
+	defer SetNamingPolicy(nil)
+	g.Expect(GenerateNICName("my-vm")).To(Equal("nic-my-vm-nic"))
+	g.Expect(GenerateOSDiskName("my-vm")).To(Equal("my-vm_OSDisk"))
+
+	SetNamingPolicy(nil)
+	g.Expect(GenerateNICName("my-vm")).To(Equal("my-vm-nic"))
+}
+
+func TestTaggingPolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(MandatoryTags("my-cluster")).To(BeEmpty())
+
+	SetTaggingPolicy(costCenterTaggingPolicy{})
+	defer SetTaggingPolicy(nil)
+	g.Expect(MandatoryTags("my-cluster")).To(Equal(infrav1.Tags{"costCenter": "my-cluster"}))
+}
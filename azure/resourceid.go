@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/pkg/errors"
+)
+
+const (
+	// CommunityGalleryImageVersionResourceType is the resource type of the community gallery image version IDs parsed
+	// by ParseResourceID.
+	CommunityGalleryImageVersionResourceType = "Microsoft.Compute/communityGalleries/images/versions"
+	// SharedGalleryImageVersionResourceType is the resource type of the shared gallery image version IDs parsed by
+	// ParseResourceID.
+	SharedGalleryImageVersionResourceType = "Microsoft.Compute/sharedGalleries/images/versions"
+	// GalleryImageVersionResourceType is the resource type of the Azure Compute Gallery image version IDs.
+	GalleryImageVersionResourceType = "Microsoft.Compute/galleries/images/versions"
+)
+
+// galleryIDPrefixes are the prefixes of the gallery image IDs which aren't ARM resource IDs, and of the provider scoped
+// IDs ParseResourceID parses them as.
+var galleryIDPrefixes = map[string]string{
+	"/communitygalleries/": "/providers/Microsoft.Compute/communityGalleries/",
+	"/sharedgalleries/":    "/providers/Microsoft.Compute/sharedGalleries/",
+}
+
+// ParseResourceID parses an Azure resource ID, such as
+// "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet".
+// The keys and the provider namespaces of the ID are case insensitive, and the name and the type of the returned ID
+// are the ones of the last, possibly child, resource of the ID.
+// It also parses the community and shared gallery image IDs, such as
+// "/CommunityGalleries/my-gallery/Images/my-image/Versions/1.0.0", as resources of the Microsoft.Compute provider.
+func ParseResourceID(id string) (*arm.ResourceID, error) {
+	for prefix, providerPrefix := range galleryIDPrefixes {
+		if len(id) > len(prefix) && strings.EqualFold(id[:len(prefix)], prefix) {
+			id = providerPrefix + id[len(prefix):]
+			break
+		}
+	}
+
+	resourceID, err := arm.ParseResourceID(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse resource ID %q", id)
+	}
+	return resourceID, nil
+}
+
+// IsResourceType returns true if the given parsed resource ID is of the given resource type, such as
+// "Microsoft.Network/virtualNetworks/subnets", ignoring case.
+func IsResourceType(resourceID *arm.ResourceID, resourceType string) bool {
+	return resourceID != nil && strings.EqualFold(resourceID.ResourceType.String(), resourceType)
+}
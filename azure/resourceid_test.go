@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseResourceID(t *testing.T) {
+	tests := []struct {
+		name                  string
+		id                    string
+		expectedErr           bool
+		expectedSubscription  string
+		expectedResourceGroup string
+		expectedType          string
+		expectedName          string
+		expectedParentName    string
+	}{
+		{
+			name:                  "resource group",
+			id:                    "/subscriptions/123/resourceGroups/my-rg",
+			expectedSubscription:  "123",
+			expectedResourceGroup: "my-rg",
+			expectedType:          "Microsoft.Resources/resourceGroups",
+			expectedName:          "my-rg",
+			expectedParentName:    "123",
+		},
+		{
+			name:                  "resource",
+			id:                    "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm",
+			expectedSubscription:  "123",
+			expectedResourceGroup: "my-rg",
+			expectedType:          "Microsoft.Compute/virtualMachines",
+			expectedName:          "my-vm",
+			expectedParentName:    "my-rg",
+		},
+		{
+			name:                  "child resource with lowercase keys",
+			id:                    "/subscriptions/123/resourcegroups/my-rg/providers/microsoft.network/virtualNetworks/my-vnet/subnets/my-subnet",
+			expectedSubscription:  "123",
+			expectedResourceGroup: "my-rg",
+			expectedType:          "microsoft.network/virtualNetworks/subnets",
+			expectedName:          "my-subnet",
+			expectedParentName:    "my-vnet",
+		},
+		{
+			name:                  "extension resource",
+			id:                    "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm/providers/Microsoft.Authorization/roleAssignments/456",
+			expectedSubscription:  "123",
+			expectedResourceGroup: "my-rg",
+			expectedType:          "Microsoft.Authorization/roleAssignments",
+			expectedName:          "456",
+			expectedParentName:    "my-vm",
+		},
+		{
+			name:               "community gallery image version",
+			id:                 "/CommunityGalleries/my-gallery/Images/my-image/Versions/1.0.0",
+			expectedType:       "Microsoft.Compute/communityGalleries/Images/Versions",
+			expectedName:       "1.0.0",
+			expectedParentName: "my-image",
+		},
+		{
+			name:               "shared gallery image version",
+			id:                 "/sharedGalleries/my-gallery/images/my-image/versions/1.0.0",
+			expectedType:       "Microsoft.Compute/sharedGalleries/images/versions",
+			expectedName:       "1.0.0",
+			expectedParentName: "my-image",
+		},
+		{
+			name:        "empty ID",
+			id:          "",
+			expectedErr: true,
+		},
+		{
+			name:        "not an ID",
+			id:          "not-an-id",
+			expectedErr: true,
+		},
+		{
+			name:        "resource type without name",
+			id:          "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines",
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			resourceID, err := ParseResourceID(tt.id)
+			if tt.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(resourceID.SubscriptionID).To(Equal(tt.expectedSubscription))
+			g.Expect(resourceID.ResourceGroupName).To(Equal(tt.expectedResourceGroup))
+			g.Expect(resourceID.ResourceType.String()).To(Equal(tt.expectedType))
+			g.Expect(resourceID.Name).To(Equal(tt.expectedName))
+			g.Expect(resourceID.Parent.Name).To(Equal(tt.expectedParentName))
+		})
+	}
+}
+
+func TestIsResourceType(t *testing.T) {
+	g := NewWithT(t)
+
+	resourceID, err := ParseResourceID("/CommunityGalleries/my-gallery/Images/my-image/Versions/1.0.0")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(IsResourceType(resourceID, CommunityGalleryImageVersionResourceType)).To(BeTrue())
+	g.Expect(IsResourceType(resourceID, SharedGalleryImageVersionResourceType)).To(BeFalse())
+	g.Expect(IsResourceType(nil, CommunityGalleryImageVersionResourceType)).To(BeFalse())
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
+)
+
+// getAcrPullRoleAssignmentSpecs returns the specs granting the AcrPull role on the Azure Container Registries attached
+// to a cluster to the identity of a VM, a scale set or the kubelets of a managed cluster.
+func getAcrPullRoleAssignmentSpecs(acrs []string, principalID *string, resourceType, name, resourceGroup string) []azure.ResourceSpecGetter {
+	specs := make([]azure.ResourceSpecGetter, 0, len(acrs))
+	for _, acr := range acrs {
+		resource, err := azure.ParseResourceID(acr)
+		if err != nil {
+			// The registries are validated by the webhooks.
+			continue
+		}
+		roleDefinitionID := azure.GenerateAcrPullRoleDefinitionID(resource.SubscriptionID)
+		specs = append(specs, &roleassignments.RoleAssignmentSpec{
+			Name:             azure.GenerateRoleAssignmentName(acr, roleDefinitionID, pointer.StringDeref(principalID, "")),
+			MachineName:      name,
+			ResourceGroup:    resourceGroup,
+			ResourceType:     resourceType,
+			Scope:            acr,
+			RoleDefinitionID: roleDefinitionID,
+			PrincipalID:      principalID,
+		})
+	}
+	return specs
+}
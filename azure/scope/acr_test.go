@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
+)
+
+func TestGetAcrPullRoleAssignmentSpecs(t *testing.T) {
+	g := NewWithT(t)
+
+	acrs := []string{
+		"/subscriptions/123/resourceGroups/registries/providers/Microsoft.ContainerRegistry/registries/shared",
+		"/subscriptions/456/resourceGroups/registries/providers/Microsoft.ContainerRegistry/registries/team",
+	}
+	specs := getAcrPullRoleAssignmentSpecs(acrs, to.Ptr("principal"), azure.VirtualMachine, "my-vm", "my-rg")
+	g.Expect(specs).To(Equal([]azure.ResourceSpecGetter{
+		&roleassignments.RoleAssignmentSpec{
+			Name:             azure.GenerateRoleAssignmentName(acrs[0], "/subscriptions/123/providers/Microsoft.Authorization/roleDefinitions/7f951dda-4ed3-4680-a7ca-43fe172d538d", "principal"),
+			MachineName:      "my-vm",
+			ResourceGroup:    "my-rg",
+			ResourceType:     azure.VirtualMachine,
+			Scope:            acrs[0],
+			RoleDefinitionID: "/subscriptions/123/providers/Microsoft.Authorization/roleDefinitions/7f951dda-4ed3-4680-a7ca-43fe172d538d",
+			PrincipalID:      to.Ptr("principal"),
+		},
+		&roleassignments.RoleAssignmentSpec{
+			Name:             azure.GenerateRoleAssignmentName(acrs[1], "/subscriptions/456/providers/Microsoft.Authorization/roleDefinitions/7f951dda-4ed3-4680-a7ca-43fe172d538d", "principal"),
+			MachineName:      "my-vm",
+			ResourceGroup:    "my-rg",
+			ResourceType:     azure.VirtualMachine,
+			Scope:            acrs[1],
+			RoleDefinitionID: "/subscriptions/456/providers/Microsoft.Authorization/roleDefinitions/7f951dda-4ed3-4680-a7ca-43fe172d538d",
+			PrincipalID:      to.Ptr("principal"),
+		},
+	}))
+
+	// Role assignments are named after the registry and the principal, so that a principal is granted the role once.
+	g.Expect(specs[0].ResourceName()).NotTo(Equal(specs[1].ResourceName()))
+	g.Expect(getAcrPullRoleAssignmentSpecs(acrs, to.Ptr("principal"), azure.VirtualMachine, "my-vm", "my-rg")[0].ResourceName()).To(Equal(specs[0].ResourceName()))
+	g.Expect(getAcrPullRoleAssignmentSpecs(acrs, to.Ptr("other"), azure.VirtualMachine, "my-vm", "my-rg")[0].ResourceName()).NotTo(Equal(specs[0].ResourceName()))
+}
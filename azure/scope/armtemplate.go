@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// saveARMTemplate stores an ARM template in the ConfigMap named after the object it was exported for, which owns it.
+func saveARMTemplate(ctx context.Context, c client.Client, owner client.Object, template *armtemplate.Template) error {
+	data, err := json.MarshalIndent(template, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to render the ARM template")
+	}
+
+	gvk, err := apiutil.GVKForObject(owner, c.Scheme())
+	if err != nil {
+		return errors.Wrap(err, "failed to get the kind of the ARM template owner")
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      owner.GetName() + infrav1.ARMTemplateSuffix,
+			Namespace: owner.GetNamespace(),
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, c, configMap, func() error {
+		configMap.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(owner, gvk)}
+		configMap.Data = map[string]string{infrav1.ARMTemplateKey: string(data)}
+		return nil
+	}); err != nil {
+		return errors.Wrapf(err, "failed to save the ARM template in ConfigMap %s", configMap.Name)
+	}
+	return nil
+}
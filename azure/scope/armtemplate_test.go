@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClusterScopeSaveARMTemplate(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-cluster",
+			Namespace:   "default",
+			UID:         "uid",
+			Annotations: map[string]string{infrav1.ARMTemplateExportRequestAnnotation: "1"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	clusterScope := &ClusterScope{Client: c, AzureCluster: azureCluster}
+
+	g.Expect(clusterScope.ARMTemplateExportRequested()).To(BeTrue())
+	template := armtemplate.New("my-rg")
+	g.Expect(template.AddResource("Microsoft.Network/virtualNetworks", armtemplate.NetworkAPIVersion, "my-vnet", struct{}{})).To(Succeed())
+	g.Expect(clusterScope.SaveARMTemplate(context.TODO(), template)).To(Succeed())
+	g.Expect(clusterScope.ARMTemplateExportRequested()).To(BeFalse())
+	g.Expect(azureCluster.Status.LastARMTemplateExportRequest).To(Equal("1"))
+
+	configMap := &corev1.ConfigMap{}
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "my-cluster-arm-template"}, configMap)).To(Succeed())
+	g.Expect(configMap.Data[infrav1.ARMTemplateKey]).To(ContainSubstring(`"name": "my-vnet"`))
+	g.Expect(configMap.OwnerReferences).To(HaveLen(1))
+	g.Expect(configMap.OwnerReferences[0].Kind).To(Equal("AzureCluster"))
+	g.Expect(configMap.OwnerReferences[0].Name).To(Equal("my-cluster"))
+
+	// A new request updates the template.
+	azureCluster.Annotations[infrav1.ARMTemplateExportRequestAnnotation] = "2"
+	g.Expect(clusterScope.ARMTemplateExportRequested()).To(BeTrue())
+	g.Expect(clusterScope.SaveARMTemplate(context.TODO(), armtemplate.New("my-rg"))).To(Succeed())
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "my-cluster-arm-template"}, configMap)).To(Succeed())
+	g.Expect(configMap.Data[infrav1.ARMTemplateKey]).NotTo(ContainSubstring("my-vnet"))
+	g.Expect(azureCluster.Status.LastARMTemplateExportRequest).To(Equal("2"))
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+// azureLinuxFirewallScript opens the firewall of Azure Linux, whose default iptables policies drop all the inbound and
+// forwarded traffic, including the API server, kubelet and pod traffic. The policies are saved to the files restored
+// by the iptables service, so that they survive a reboot.
+const azureLinuxFirewallScript = `set -e
+iptables -P INPUT ACCEPT
+iptables -P FORWARD ACCEPT
+iptables -P OUTPUT ACCEPT
+iptables-save > /etc/systemd/scripts/ip4save
+if [ -f /etc/systemd/scripts/ip6save ]; then
+  ip6tables -P INPUT ACCEPT
+  ip6tables -P FORWARD ACCEPT
+  ip6tables -P OUTPUT ACCEPT
+  ip6tables-save > /etc/systemd/scripts/ip6save
+fi
+`
+
+// getAzureLinuxCloudConfig returns a cloud-config adapting an Azure Linux virtual machine to Kubernetes before it is
+// bootstrapped, where Ubuntu works as is.
+func getAzureLinuxCloudConfig() *cloudConfig {
+	config := newCloudConfig()
+	config.RunCmd = append(config.RunCmd, []string{"sh", "-c", azureLinuxFirewallScript})
+	return config
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGetAzureLinuxCloudConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	data, err := getAzureLinuxCloudConfig().render()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(data).To(HavePrefix("#cloud-config\n"))
+	g.Expect(data).To(ContainSubstring(`"merge_how":[{"name":"list","settings":["prepend"]}`))
+	g.Expect(data).To(ContainSubstring(`"runcmd":[["sh","-c","set -e\niptables -P INPUT ACCEPT\n`))
+	g.Expect(data).To(ContainSubstring(`iptables -P FORWARD ACCEPT\n`))
+	g.Expect(data).To(ContainSubstring(`iptables-save \u003e /etc/systemd/scripts/ip4save\n`))
+	g.Expect(data).To(ContainSubstring(`ip6tables-save \u003e /etc/systemd/scripts/ip6save\n`))
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// getBootstrappingVMExtension returns the VM extension checking the bootstrap of a VM with the given OS disk, as
+// configured by the bootstrap extension of its cluster, or nil if the VM has none.
+func getBootstrappingVMExtension(config *infrav1.BootstrapExtension, osDisk infrav1.OSDisk, cloud string, vmName string) *azure.ExtensionSpec {
+	if config != nil {
+		if config.Disabled {
+			return nil
+		}
+		source := config.Linux
+		if osDisk.OSType == azure.WindowsOS {
+			source = config.Windows
+		}
+		if source != nil {
+			return azure.GetCustomBootstrappingVMExtension(osDisk.OSType, vmName, *source)
+		}
+	}
+
+	if osDisk.Distro == infrav1.OSDistroAzureLinux {
+		return azure.GetAzureLinuxBootstrappingVMExtension(cloud, vmName)
+	}
+	return azure.GetBootstrappingVMExtension(osDisk.OSType, cloud, vmName)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+func TestGetBootstrappingVMExtension(t *testing.T) {
+	linuxSource := &infrav1.BootstrapExtensionSource{
+		Publisher: "Contoso.Extensions",
+		Type:      "BootstrapCheck",
+		Version:   "1.2",
+	}
+
+	tests := []struct {
+		name          string
+		config        *infrav1.BootstrapExtension
+		osDisk        infrav1.OSDisk
+		cloud         string
+		wantName      string
+		wantPublisher string
+	}{
+		{
+			name:          "CAPZ bootstrapping extension by default",
+			osDisk:        infrav1.OSDisk{OSType: "Linux"},
+			cloud:         azure.PublicCloud.Name,
+			wantName:      "CAPZ.Linux.Bootstrapping",
+			wantPublisher: "Microsoft.Azure.ContainerUpstream",
+		},
+		{
+			name:          "Custom Script extension on Azure Linux by default",
+			osDisk:        infrav1.OSDisk{OSType: "Linux", Distro: infrav1.OSDistroAzureLinux},
+			cloud:         azure.PublicCloud.Name,
+			wantName:      "CustomScript",
+			wantPublisher: "Microsoft.Azure.Extensions",
+		},
+		{
+			name:   "no extension outside of the public cloud by default",
+			osDisk: infrav1.OSDisk{OSType: "Linux"},
+			cloud:  azure.USGovernmentCloud.Name,
+		},
+		{
+			name:   "no extension when disabled",
+			config: &infrav1.BootstrapExtension{Disabled: true},
+			osDisk: infrav1.OSDisk{OSType: "Linux"},
+			cloud:  azure.PublicCloud.Name,
+		},
+		{
+			name:          "custom extension in any cloud",
+			config:        &infrav1.BootstrapExtension{Linux: linuxSource},
+			osDisk:        infrav1.OSDisk{OSType: "Linux", Distro: infrav1.OSDistroAzureLinux},
+			cloud:         azure.USGovernmentCloud.Name,
+			wantName:      "BootstrapCheck",
+			wantPublisher: "Contoso.Extensions",
+		},
+		{
+			name:          "CAPZ bootstrapping extension on Windows without a custom Windows extension",
+			config:        &infrav1.BootstrapExtension{Linux: linuxSource},
+			osDisk:        infrav1.OSDisk{OSType: "Windows"},
+			cloud:         azure.PublicCloud.Name,
+			wantName:      "CAPZ.Windows.Bootstrapping",
+			wantPublisher: "Microsoft.Azure.ContainerUpstream",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			extension := getBootstrappingVMExtension(tc.config, tc.osDisk, tc.cloud, "vm-name")
+			if tc.wantName == "" {
+				g.Expect(extension).To(BeNil())
+				return
+			}
+			g.Expect(extension).NotTo(BeNil())
+			g.Expect(extension.Name).To(Equal(tc.wantName))
+			g.Expect(extension.Publisher).To(Equal(tc.wantPublisher))
+			g.Expect(extension.VMName).To(Equal("vm-name"))
+		})
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"path"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultCACertificatesKey is the ConfigMap or Secret key holding CA certificates when none is specified.
+	defaultCACertificatesKey = "ca.crt"
+	// caCertificatesFileName is the name of the file holding the additional CA certificates in the trust store.
+	caCertificatesFileName = "capz-ca.crt"
+)
+
+// getCACertificates returns the PEM encoded CA certificates referenced by a machine, reading the ConfigMap or Secret
+// from the given namespace. It returns nil when no CA certificates are specified.
+func getCACertificates(ctx context.Context, c client.Client, namespace string, caCertificates *infrav1.CACertificates) ([]byte, error) {
+	if caCertificates == nil {
+		return nil, nil
+	}
+
+	var (
+		kind  string
+		ref   *infrav1.CACertificatesReference
+		value []byte
+		ok    bool
+	)
+	switch {
+	case caCertificates.ConfigMapRef != nil:
+		kind, ref = "ConfigMap", caCertificates.ConfigMapRef
+		configMap := &corev1.ConfigMap{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, configMap); err != nil {
+			return nil, errors.Wrapf(err, "failed to get CA certificates ConfigMap %s/%s", namespace, ref.Name)
+		}
+		var data string
+		data, ok = configMap.Data[caCertificatesKey(ref)]
+		value = []byte(data)
+	case caCertificates.SecretRef != nil:
+		kind, ref = "Secret", caCertificates.SecretRef
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+			return nil, errors.Wrapf(err, "failed to get CA certificates Secret %s/%s", namespace, ref.Name)
+		}
+		value, ok = secret.Data[caCertificatesKey(ref)]
+	default:
+		return nil, errors.New("CA certificates reference neither a ConfigMap nor a Secret")
+	}
+
+	if !ok {
+		return nil, errors.Errorf("CA certificates %s %s/%s has no key %q", kind, namespace, ref.Name, caCertificatesKey(ref))
+	}
+	if err := validateCACertificates(value); err != nil {
+		return nil, errors.Wrapf(err, "invalid CA certificates in %s %s/%s", kind, namespace, ref.Name)
+	}
+	return value, nil
+}
+
+// caCertificatesKey returns the ConfigMap or Secret key holding the CA certificates of a reference.
+func caCertificatesKey(ref *infrav1.CACertificatesReference) string {
+	if ref.Key == "" {
+		return defaultCACertificatesKey
+	}
+	return ref.Key
+}
+
+// validateCACertificates checks that value holds at least one PEM encoded certificate, and nothing else.
+func validateCACertificates(value []byte) error {
+	found := false
+	rest := bytes.TrimSpace(value)
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return errors.New("data is not PEM encoded")
+		}
+		if block.Type != "CERTIFICATE" {
+			return errors.Errorf("unexpected PEM block of type %q", block.Type)
+		}
+		found = true
+		rest = bytes.TrimSpace(rest)
+	}
+	if !found {
+		return errors.New("no certificate found")
+	}
+	return nil
+}
+
+// getCACertificatesCloudConfig returns a cloud-config adding the CA certificates to the trust store of a virtual
+// machine running the given OS distribution, before it is bootstrapped. containerd is restarted if it is already
+// running, as it only loads the trust store on startup.
+func getCACertificatesCloudConfig(caCertificates []byte, distro infrav1.OSDistro) *cloudConfig {
+	if distro == "" {
+		distro = infrav1.OSDistroUbuntu
+	}
+
+	anchorsDir, updateCommand := "/usr/local/share/ca-certificates", "update-ca-certificates"
+	if distro == infrav1.OSDistroAzureLinux {
+		anchorsDir, updateCommand = "/etc/pki/ca-trust/source/anchors", "update-ca-trust"
+	}
+
+	config := newCloudConfig()
+	config.WriteFiles = append(config.WriteFiles, cloudConfigFile{
+		Path:        path.Join(anchorsDir, caCertificatesFileName),
+		Content:     string(caCertificates),
+		Owner:       "root:root",
+		Permissions: "0644",
+	})
+	config.RunCmd = append(config.RunCmd,
+		[]string{updateCommand},
+		[]string{"systemctl", "try-restart", "containerd"},
+	)
+	return config
+}
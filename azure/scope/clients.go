@@ -24,16 +24,35 @@ import (
 	"os"
 	"strings"
 
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/azure"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 )
 
+// The names of the environment variables the settings of the controller environment are read from.
+const (
+	envSubscriptionID      = "AZURE_SUBSCRIPTION_ID"
+	envTenantID            = "AZURE_TENANT_ID"
+	envClientID            = "AZURE_CLIENT_ID"
+	envClientSecret        = "AZURE_CLIENT_SECRET"
+	envCertificatePath     = "AZURE_CERTIFICATE_PATH"
+	envCertificatePassword = "AZURE_CERTIFICATE_PASSWORD"
+)
+
+// EnvironmentSettings are the settings of the Azure environment the controller runs in.
+type EnvironmentSettings struct {
+	// Values are the settings read from the environment variables, by variable name.
+	Values map[string]string
+	// Environment is the Azure cloud environment.
+	Environment azure.Environment
+}
+
 // AzureClients contains all the Azure clients used by the scopes.
 type AzureClients struct {
-	auth.EnvironmentSettings
+	EnvironmentSettings
 
-	Authorizer                 autorest.Authorizer
+	TokenCredential            azcore.TokenCredential
 	ResourceManagerEndpoint    string
 	ResourceManagerVMDNSSuffix string
 }
@@ -45,30 +64,36 @@ func (c *AzureClients) CloudEnvironment() string {
 
 // TenantID returns the Azure tenant id the controller runs in.
 func (c *AzureClients) TenantID() string {
-	return c.Values[auth.TenantID]
+	return c.Values[envTenantID]
 }
 
 // ClientID returns the Azure client id from the controller environment.
 func (c *AzureClients) ClientID() string {
-	return c.Values[auth.ClientID]
+	return c.Values[envClientID]
 }
 
 // ClientSecret returns the Azure client secret from the controller environment.
 func (c *AzureClients) ClientSecret() string {
-	return c.Values[auth.ClientSecret]
+	return c.Values[envClientSecret]
 }
 
 // SubscriptionID returns the Azure subscription id of the cluster,
 // either specified or from the environment.
 func (c *AzureClients) SubscriptionID() string {
-	return c.Values[auth.SubscriptionID]
+	return c.Values[envSubscriptionID]
+}
+
+// Token returns the Azure token credential of the Azure SDK clients.
+func (c *AzureClients) Token() azcore.TokenCredential {
+	return c.TokenCredential
 }
 
 // HashKey returns a base64 url encoded sha256 hash for the Auth scope (Azure TenantID + CloudEnv + SubscriptionID +
-// ClientID).
+// ClientID + ClientSecret). The client secret is part of the key so that the clients cached with a credential built
+// from a rotated secret aren't reused.
 func (c *AzureClients) HashKey() string {
 	hasher := sha256.New()
-	_, _ = hasher.Write([]byte(c.TenantID() + c.CloudEnvironment() + c.SubscriptionID() + c.ClientID()))
+	_, _ = hasher.Write([]byte(c.TenantID() + c.CloudEnvironment() + c.SubscriptionID() + c.ClientID() + c.ClientSecret()))
 	return base64.URLEncoding.EncodeToString(hasher.Sum(nil))
 }
 
@@ -79,7 +104,7 @@ func (c *AzureClients) setCredentials(subscriptionID, environmentName string) er
 	}
 
 	if subscriptionID == "" {
-		subscriptionID = settings.GetSubscriptionID()
+		subscriptionID = settings.Values[envSubscriptionID]
 		if subscriptionID == "" {
 			return fmt.Errorf("error creating azure services. subscriptionID is not set in cluster or AZURE_SUBSCRIPTION_ID env var")
 		}
@@ -88,79 +113,105 @@ func (c *AzureClients) setCredentials(subscriptionID, environmentName string) er
 	c.EnvironmentSettings = settings
 	c.ResourceManagerEndpoint = settings.Environment.ResourceManagerEndpoint
 	c.ResourceManagerVMDNSSuffix = settings.Environment.ResourceManagerVMDNSSuffix
-	c.Values[auth.ClientID] = strings.TrimSuffix(c.Values[auth.ClientID], "\n")
-	c.Values[auth.ClientSecret] = strings.TrimSuffix(c.Values[auth.ClientSecret], "\n")
-	c.Values[auth.SubscriptionID] = strings.TrimSuffix(subscriptionID, "\n")
-	c.Values[auth.TenantID] = strings.TrimSuffix(c.Values[auth.TenantID], "\n")
+	c.Values[envClientID] = strings.TrimSuffix(c.Values[envClientID], "\n")
+	c.Values[envClientSecret] = strings.TrimSuffix(c.Values[envClientSecret], "\n")
+	c.Values[envSubscriptionID] = strings.TrimSuffix(subscriptionID, "\n")
+	c.Values[envTenantID] = strings.TrimSuffix(c.Values[envTenantID], "\n")
 
-	if c.Authorizer == nil {
-		c.Authorizer, err = c.GetAuthorizer()
+	if c.TokenCredential == nil {
+		c.TokenCredential, err = c.getTokenCredential()
 	}
 	return err
 }
 
+// getTokenCredential returns the token credential of the controller environment: a client secret or certificate
+// credential if they are set, else the default Azure credential, which also supports workload and managed identities.
+func (c *AzureClients) getTokenCredential() (azcore.TokenCredential, error) {
+	clientOptions := azcore.ClientOptions{
+		Cloud: cloud.Configuration{
+			ActiveDirectoryAuthorityHost: c.Environment.ActiveDirectoryEndpoint,
+		},
+	}
+
+	switch {
+	case c.ClientSecret() != "":
+		return azidentity.NewClientSecretCredential(c.TenantID(), c.ClientID(), c.ClientSecret(), &azidentity.ClientSecretCredentialOptions{
+			ClientOptions: clientOptions,
+		})
+	case c.Values[envCertificatePath] != "":
+		data, err := os.ReadFile(c.Values[envCertificatePath])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the client certificate: %w", err)
+		}
+		certs, key, err := azidentity.ParseCertificates(data, []byte(c.Values[envCertificatePassword]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the client certificate: %w", err)
+		}
+		return azidentity.NewClientCertificateCredential(c.TenantID(), c.ClientID(), certs, key, &azidentity.ClientCertificateCredentialOptions{
+			ClientOptions: clientOptions,
+		})
+	default:
+		return azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+			ClientOptions: clientOptions,
+			TenantID:      c.TenantID(),
+		})
+	}
+}
+
 func (c *AzureClients) setCredentialsWithProvider(ctx context.Context, subscriptionID, environmentName string, credentialsProvider CredentialsProvider) error {
 	if credentialsProvider == nil {
 		return fmt.Errorf("credentials provider cannot have an empty value")
 	}
 
+	// The subscription of a cluster using an identity is never taken from the manager environment, which may hold the
+	// subscription of another cluster, or of the management cluster, when a fleet spans several subscriptions.
+	if subscriptionID == "" {
+		return fmt.Errorf("error creating azure services. subscriptionID must be set in cluster when using an AzureClusterIdentity")
+	}
+
 	settings, err := c.getSettingsFromEnvironment(environmentName)
 	if err != nil {
 		return err
 	}
 
-	if subscriptionID == "" {
-		subscriptionID = settings.GetSubscriptionID()
-		if subscriptionID == "" {
-			return fmt.Errorf("error creating azure services. subscriptionID is not set in cluster or AZURE_SUBSCRIPTION_ID env var")
-		}
-	}
-
 	c.EnvironmentSettings = settings
 	c.ResourceManagerEndpoint = settings.Environment.ResourceManagerEndpoint
 	c.ResourceManagerVMDNSSuffix = settings.Environment.ResourceManagerVMDNSSuffix
-	c.Values[auth.SubscriptionID] = strings.TrimSuffix(subscriptionID, "\n")
-	c.Values[auth.TenantID] = strings.TrimSuffix(credentialsProvider.GetTenantID(), "\n")
-	c.Values[auth.ClientID] = strings.TrimSuffix(credentialsProvider.GetClientID(), "\n")
+	c.Values[envSubscriptionID] = strings.TrimSuffix(subscriptionID, "\n")
+	c.Values[envTenantID] = strings.TrimSuffix(credentialsProvider.GetTenantID(), "\n")
+	c.Values[envClientID] = strings.TrimSuffix(credentialsProvider.GetClientID(), "\n")
 
 	clientSecret, err := credentialsProvider.GetClientSecret(ctx)
 	if err != nil {
 		return err
 	}
-	c.Values[auth.ClientSecret] = strings.TrimSuffix(clientSecret, "\n")
+	c.Values[envClientSecret] = strings.TrimSuffix(clientSecret, "\n")
 
-	c.Authorizer, err = credentialsProvider.GetAuthorizer(ctx, c.ResourceManagerEndpoint, c.Environment.ActiveDirectoryEndpoint)
+	c.TokenCredential, err = credentialsProvider.GetTokenCredential(ctx, c.ResourceManagerEndpoint, c.Environment.ActiveDirectoryEndpoint)
 	return err
 }
 
-func (c *AzureClients) getSettingsFromEnvironment(environmentName string) (s auth.EnvironmentSettings, err error) {
-	s = auth.EnvironmentSettings{
-		Values: map[string]string{},
-	}
-	s.Values[auth.EnvironmentName] = environmentName
-	setValue(s, auth.SubscriptionID)
-	setValue(s, auth.TenantID)
-	setValue(s, auth.AuxiliaryTenantIDs)
-	setValue(s, auth.ClientID)
-	setValue(s, auth.ClientSecret)
-	setValue(s, auth.CertificatePath)
-	setValue(s, auth.CertificatePassword)
-	setValue(s, auth.Username)
-	setValue(s, auth.Password)
-	setValue(s, auth.Resource)
-	if v := s.Values[auth.EnvironmentName]; v == "" {
-		s.Environment = azure.PublicCloud
-	} else {
-		s.Environment, err = azure.EnvironmentFromName(v)
+func (c *AzureClients) getSettingsFromEnvironment(environmentName string) (EnvironmentSettings, error) {
+	env, err := azure.EnvironmentFromName(environmentName)
+	if err != nil {
+		return EnvironmentSettings{}, err
 	}
-	if s.Values[auth.Resource] == "" {
-		s.Values[auth.Resource] = s.Environment.ResourceManagerEndpoint
+
+	s := EnvironmentSettings{
+		Values:      map[string]string{},
+		Environment: env,
 	}
-	return
+	setValue(s, envSubscriptionID)
+	setValue(s, envTenantID)
+	setValue(s, envClientID)
+	setValue(s, envClientSecret)
+	setValue(s, envCertificatePath)
+	setValue(s, envCertificatePassword)
+	return s, nil
 }
 
 // setValue adds the specified environment variable value to the Values map if it exists.
-func setValue(settings auth.EnvironmentSettings, key string) {
+func setValue(settings EnvironmentSettings, key string) {
 	if v := os.Getenv(key); v != "" {
 		settings.Values[key] = v
 	}
@@ -19,7 +19,8 @@ package scope
 import (
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	. "github.com/onsi/gomega"
 )
 
@@ -53,23 +54,16 @@ func TestGettingEnvironment(t *testing.T) {
 			expectedEndpoint:  "https://management.chinacloudapi.cn/",
 			expectedDNSSuffix: "cloudapp.chinacloudapi.cn",
 			expectedError:     false,
-		}, "AZURE_ENVIRONMENT is AzureGermany": {
-			azureEnv:          "AzureGermanCloud",
-			expectedEndpoint:  "https://management.microsoftazure.de/",
-			expectedDNSSuffix: "cloudapp.microsoftazure.de",
-			expectedError:     false,
 		}, "AZURE_ENVIRONMENT has an invalid value": {
 			azureEnv:             "AzureInSpace",
 			expectedEndpoint:     "",
 			expectedDNSSuffix:    "",
 			expectedError:        true,
-			expectedErrorMessage: "There is no cloud environment matching the name \"AZUREINSPACE\"",
+			expectedErrorMessage: "invalid cloud environment name \"AzureInSpace\"",
 		}}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			c := AzureClients{
-				Authorizer: autorest.NullAuthorizer{},
-			}
+			c := AzureClients{}
 			err := c.setCredentials("1234", test.azureEnv)
 			if test.expectedError {
 				g.Expect(err).To(HaveOccurred())
@@ -82,3 +76,53 @@ func TestGettingEnvironment(t *testing.T) {
 		})
 	}
 }
+
+func TestGettingTokenCredential(t *testing.T) {
+	var tests = map[string]struct {
+		clientSecret       string
+		expectedCredential azcore.TokenCredential
+	}{
+		"client secret is set": {
+			clientSecret:       "fooSecret",
+			expectedCredential: &azidentity.ClientSecretCredential{},
+		},
+		"client secret is empty": {
+			clientSecret:       "",
+			expectedCredential: &azidentity.DefaultAzureCredential{},
+		},
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Setenv("AZURE_TENANT_ID", "fooTenant")
+			t.Setenv("AZURE_CLIENT_ID", "fooClient")
+			t.Setenv("AZURE_CLIENT_SECRET", test.clientSecret)
+			t.Setenv("AZURE_CERTIFICATE_PATH", "")
+
+			c := AzureClients{}
+			g.Expect(c.setCredentials("1234", "")).To(Succeed())
+			g.Expect(c.Token()).To(BeAssignableToTypeOf(test.expectedCredential))
+		})
+	}
+}
+
+func TestHashKey(t *testing.T) {
+	g := NewWithT(t)
+
+	newClients := func(clientSecret string) *AzureClients {
+		return &AzureClients{
+			EnvironmentSettings: EnvironmentSettings{
+				Values: map[string]string{
+					envTenantID:       "fooTenant",
+					envSubscriptionID: "1234",
+					envClientID:       "fooClient",
+					envClientSecret:   clientSecret,
+				},
+			},
+		}
+	}
+
+	g.Expect(newClients("fooSecret").HashKey()).To(Equal(newClients("fooSecret").HashKey()))
+	g.Expect(newClients("fooSecret").HashKey()).NotTo(Equal(newClients("rotatedSecret").HashKey()))
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// cloudConfig is a cloud-config part merged with the bootstrap data of a virtual machine.
+// See https://cloudinit.readthedocs.io/en/latest/reference/modules.html.
+type cloudConfig struct {
+	MergeHow   []cloudConfigMerger `json:"merge_how"`
+	Packages   []string            `json:"packages,omitempty"`
+	WriteFiles []cloudConfigFile   `json:"write_files,omitempty"`
+	Mounts     [][]*string         `json:"mounts,omitempty"`
+	RunCmd     [][]string          `json:"runcmd,omitempty"`
+}
+
+// cloudConfigMerger configures how cloud-init merges a cloud-config part with the previous ones.
+// See https://cloudinit.readthedocs.io/en/latest/reference/merging.html.
+type cloudConfigMerger struct {
+	Name     string   `json:"name"`
+	Settings []string `json:"settings"`
+}
+
+// cloudConfigFile is a file written on the virtual machine.
+type cloudConfigFile struct {
+	Path        string `json:"path"`
+	Content     string `json:"content"`
+	Owner       string `json:"owner,omitempty"`
+	Permissions string `json:"permissions,omitempty"`
+	Append      bool   `json:"append,omitempty"`
+}
+
+// newCloudConfig returns a cloud-config part whose lists are prepended to the ones of the bootstrap data, so that its
+// commands run before the machine is bootstrapped.
+func newCloudConfig() *cloudConfig {
+	return &cloudConfig{
+		MergeHow: []cloudConfigMerger{
+			{Name: "list", Settings: []string{"prepend"}},
+			{Name: "dict", Settings: []string{"no_replace", "recurse_list"}},
+		},
+	}
+}
+
+// render returns the cloud-config part as a cloud-init user data section.
+func (c *cloudConfig) render() (string, error) {
+	// JSON is valid YAML, and keeps the rendered cloud-config stable between reconciliations.
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return "#cloud-config\n" + string(data) + "\n", nil
+}
+
+// bootUnitTemplate is a oneshot systemd unit running a script on every boot, before containerd and the kubelet start.
+const bootUnitTemplate = `[Unit]
+Description=%s
+After=local-fs.target
+Before=containerd.service kubelet.service
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=%s
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// addBootUnit writes the given script and a systemd unit running it on every boot, and starts the unit before the
+// machine is bootstrapped. Unlike the runcmd commands, which cloud-init only runs once per instance, the script also
+// runs when the VM is rebooted, or comes back on another host after it was deallocated or redeployed, so the script
+// must be idempotent.
+func (c *cloudConfig) addBootUnit(name, description, script string) {
+	scriptPath := "/usr/local/sbin/" + name
+	unit := name + ".service"
+	c.WriteFiles = append(c.WriteFiles,
+		cloudConfigFile{
+			Path:        scriptPath,
+			Content:     "#!/bin/sh\n" + script,
+			Owner:       "root:root",
+			Permissions: "0755",
+		},
+		cloudConfigFile{
+			Path:        "/etc/systemd/system/" + unit,
+			Content:     fmt.Sprintf(bootUnitTemplate, description, scriptPath),
+			Owner:       "root:root",
+			Permissions: "0644",
+		},
+	)
+	c.RunCmd = append(c.RunCmd, []string{"systemctl", "daemon-reload"}, []string{"systemctl", "enable", "--now", unit})
+}
+
+// mergeBootstrapCloudConfigs merges the cloud-config parts adapting the OS distribution, configuring the proxy, CA
+// certificates and registry mirrors, preparing the local and file storage and partitioning the GPUs of a virtual machine
+// into its bootstrap data, or returns the bootstrap data unchanged when none is needed.
+func mergeBootstrapCloudConfigs(bootstrapData []byte, osDisk infrav1.OSDisk, localStorage *infrav1.LocalStorage, mig *infrav1.MultiInstanceGPU, fileStorage *infrav1.FileStorage, proxy *infrav1.ProxyConfig, caCertificates []byte, registryMirrors []registryMirror) ([]byte, error) {
+	var configs []*cloudConfig
+	if osDisk.Distro == infrav1.OSDistroAzureLinux {
+		configs = append(configs, getAzureLinuxCloudConfig())
+	}
+	// Windows machines aren't bootstrapped with cloud-init.
+	if proxy != nil && osDisk.OSType != azure.WindowsOS {
+		configs = append(configs, getProxyCloudConfig(proxy, osDisk.Distro))
+	}
+	if len(caCertificates) > 0 && osDisk.OSType != azure.WindowsOS {
+		configs = append(configs, getCACertificatesCloudConfig(caCertificates, osDisk.Distro))
+	}
+	if len(registryMirrors) > 0 && osDisk.OSType != azure.WindowsOS {
+		configs = append(configs, getRegistryMirrorsCloudConfig(registryMirrors))
+	}
+	if localStorage != nil {
+		config, err := getLocalStorageCloudConfig(localStorage)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, config)
+	}
+	if mig != nil {
+		configs = append(configs, getMultiInstanceGPUCloudConfig(mig))
+	}
+	if fileStorage != nil {
+		config, err := getFileStorageCloudConfig(fileStorage, osDisk.Distro)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, config)
+	}
+	if len(configs) == 0 {
+		return bootstrapData, nil
+	}
+
+	sections := []string{string(bootstrapData)}
+	for _, config := range configs {
+		section, err := config.render()
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	merged, err := mergeUserDataSections(sections)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to merge cloud-config with bootstrap data")
+	}
+	return []byte(merged), nil
+}
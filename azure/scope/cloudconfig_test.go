@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestMergeBootstrapCloudConfigs(t *testing.T) {
+	bootstrapData := "## template: jinja\n#cloud-config\nruncmd:\n- kubeadm join\n"
+
+	tests := []struct {
+		name            string
+		osType          string
+		distro          infrav1.OSDistro
+		localStorage    *infrav1.LocalStorage
+		mig             *infrav1.MultiInstanceGPU
+		fileStorage     *infrav1.FileStorage
+		proxy           *infrav1.ProxyConfig
+		caCerts         []byte
+		registryMirrors []registryMirror
+		expect          func(g *WithT, data string)
+	}{
+		{
+			name: "returns the bootstrap data unchanged without local or file storage, proxy, CA certificates or registry mirrors",
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(Equal(bootstrapData))
+			},
+		},
+		{
+			name:         "merges local storage",
+			localStorage: &infrav1.LocalStorage{Source: infrav1.LocalStorageNVMe, MountPath: "/var/lib/etcd"},
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(ContainSubstring("multipart/mixed"))
+				g.Expect(data).To(ContainSubstring("Content-Type: text/jinja2"))
+				g.Expect(data).To(ContainSubstring(bootstrapData))
+				g.Expect(data).To(ContainSubstring("Content-Type: text/cloud-config"))
+				g.Expect(data).To(ContainSubstring("mdadm"))
+				g.Expect(data).To(ContainSubstring("part-001"))
+				g.Expect(data).NotTo(ContainSubstring("part-002"))
+			},
+		},
+		{
+			name:         "merges local and file storage",
+			localStorage: &infrav1.LocalStorage{Source: infrav1.LocalStorageNVMe, MountPath: "/var/lib/etcd"},
+			fileStorage:  &infrav1.FileStorage{Protocols: []infrav1.FileStorageProtocol{infrav1.FileStorageNFS}},
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(ContainSubstring(bootstrapData))
+				g.Expect(data).To(ContainSubstring("mdadm"))
+				g.Expect(data).To(ContainSubstring("nfs-common"))
+				g.Expect(data).To(ContainSubstring("part-002"))
+			},
+		},
+		{
+			name:         "merges local storage and MIG",
+			localStorage: &infrav1.LocalStorage{Source: infrav1.LocalStorageNVMe, MountPath: "/var/lib/etcd"},
+			mig:          &infrav1.MultiInstanceGPU{Profile: "1g.10gb"},
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(ContainSubstring(bootstrapData))
+				g.Expect(data).To(ContainSubstring("capz-local-storage.service"))
+				g.Expect(data).To(ContainSubstring("capz-multi-instance-gpu.service"))
+				g.Expect(data).To(ContainSubstring("part-002"))
+			},
+		},
+		{
+			name:         "merges proxy and local storage",
+			localStorage: &infrav1.LocalStorage{Source: infrav1.LocalStorageNVMe, MountPath: "/var/lib/etcd"},
+			proxy:        &infrav1.ProxyConfig{HTTPSProxy: "http://proxy.example.com:3128"},
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(ContainSubstring(bootstrapData))
+				g.Expect(data).To(ContainSubstring(proxyContainerdDropInPath))
+				g.Expect(data).To(ContainSubstring("mdadm"))
+				g.Expect(data).To(ContainSubstring("part-002"))
+			},
+		},
+		{
+			name:    "merges CA certificates",
+			caCerts: []byte(testCACertificate),
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(ContainSubstring(bootstrapData))
+				g.Expect(data).To(ContainSubstring("update-ca-certificates"))
+				g.Expect(data).To(ContainSubstring("part-001"))
+			},
+		},
+		{
+			name:    "doesn't merge CA certificates on Windows",
+			osType:  "Windows",
+			caCerts: []byte(testCACertificate),
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(Equal(bootstrapData))
+			},
+		},
+		{
+			name:            "merges registry mirrors",
+			registryMirrors: []registryMirror{{RegistryMirror: infrav1.RegistryMirror{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}}}},
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(ContainSubstring(bootstrapData))
+				g.Expect(data).To(ContainSubstring("/etc/containerd/certs.d/docker.io/hosts.toml"))
+				g.Expect(data).To(ContainSubstring("part-001"))
+			},
+		},
+		{
+			name:   "merges the Azure Linux configuration",
+			distro: infrav1.OSDistroAzureLinux,
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(ContainSubstring(bootstrapData))
+				g.Expect(data).To(ContainSubstring("iptables -P INPUT ACCEPT"))
+				g.Expect(data).To(ContainSubstring("part-001"))
+				g.Expect(data).NotTo(ContainSubstring("part-002"))
+			},
+		},
+		{
+			name:    "merges the Azure Linux configuration and CA certificates",
+			distro:  infrav1.OSDistroAzureLinux,
+			caCerts: []byte(testCACertificate),
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(ContainSubstring("iptables -P INPUT ACCEPT"))
+				g.Expect(data).To(ContainSubstring("update-ca-trust"))
+				g.Expect(data).NotTo(ContainSubstring("update-ca-certificates"))
+				g.Expect(data).To(ContainSubstring("part-002"))
+			},
+		},
+		{
+			name:   "doesn't merge the proxy on Windows",
+			osType: "Windows",
+			proxy:  &infrav1.ProxyConfig{HTTPSProxy: "http://proxy.example.com:3128"},
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(Equal(bootstrapData))
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			osType := tc.osType
+			if osType == "" {
+				osType = "Linux"
+			}
+			data, err := mergeBootstrapCloudConfigs([]byte(bootstrapData), infrav1.OSDisk{OSType: osType, Distro: tc.distro}, tc.localStorage, tc.mig, tc.fileStorage, tc.proxy, tc.caCerts, tc.registryMirrors)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, string(data))
+		})
+	}
+}
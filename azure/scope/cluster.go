@@ -24,23 +24,31 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/pkg/errors"
 	"k8s.io/utils/net"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/datacollection"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsresolvers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/eventsubscriptions"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/policyexemptions"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privatedns"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roledefinitions"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/securitygroups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
+	"sigs.k8s.io/cluster-api-provider-azure/util/resources"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -54,6 +62,9 @@ type ClusterScopeParams struct {
 	Client       client.Client
 	Cluster      *clusterv1.Cluster
 	AzureCluster *infrav1.AzureCluster
+	// EventGridWebhookURL is the URL Event Grid delivers resource notifications to.
+	// No event subscription is created for the cluster resource group when empty.
+	EventGridWebhookURL string
 }
 
 // NewClusterScope creates a new Scope from the supplied parameters.
@@ -96,6 +107,8 @@ func NewClusterScope(ctx context.Context, params ClusterScopeParams) (*ClusterSc
 		Cluster:      params.Cluster,
 		AzureCluster: params.AzureCluster,
 		patchHelper:  helper,
+
+		eventGridWebhookURL: params.EventGridWebhookURL,
 	}, nil
 }
 
@@ -107,6 +120,8 @@ type ClusterScope struct {
 	AzureClients
 	Cluster      *clusterv1.Cluster
 	AzureCluster *infrav1.AzureCluster
+
+	eventGridWebhookURL string
 }
 
 // BaseURI returns the Azure ResourceManagerEndpoint.
@@ -114,28 +129,39 @@ func (s *ClusterScope) BaseURI() string {
 	return s.ResourceManagerEndpoint
 }
 
-// Authorizer returns the Azure client Authorizer.
-func (s *ClusterScope) Authorizer() autorest.Authorizer {
-	return s.AzureClients.Authorizer
-}
-
 // PublicIPSpecs returns the public IP specs.
 func (s *ClusterScope) PublicIPSpecs() []azure.PublicIPSpec {
 	var publicIPSpecs []azure.PublicIPSpec
 
 	// Public IP specs for control plane lb
 	var controlPlaneOutboundIPSpecs []azure.PublicIPSpec
-	if s.IsAPIServerPrivate() {
+	switch {
+	case s.APIServerLB().External != nil:
+		// The frontend IPs of external load balancers are not managed.
+	case s.IsAPIServerPrivate():
 		// Public IP specs for control plane outbound lb
 		if s.ControlPlaneOutboundLB() != nil {
 			controlPlaneOutboundIPSpecs = s.getOutboundLBPublicIPSpecs(s.ControlPlaneOutboundLB(), azure.GenerateControlPlaneOutboundIPName)
 		}
-	} else {
+	default:
 		controlPlaneOutboundIPSpecs = []azure.PublicIPSpec{{
-			Name:    s.APIServerPublicIP().Name,
-			DNSName: s.APIServerPublicIP().DNSName,
-			IsIPv6:  false, // currently azure requires a ipv4 lb rule to enable ipv6
+			Name:         s.APIServerPublicIP().Name,
+			DNSName:      s.APIServerPublicIP().DNSName,
+			IsIPv6:       false, // currently azure requires a ipv4 lb rule to enable ipv6
+			Availability: s.APIServerPublicIP().Availability,
+			Zone:         s.APIServerPublicIP().Zone,
 		}}
+		// Public IP specs for the alias frontends of the API server lb
+		for _, alias := range s.APIServerLB().AliasFrontends {
+			if alias.PublicIP != nil {
+				controlPlaneOutboundIPSpecs = append(controlPlaneOutboundIPSpecs, azure.PublicIPSpec{
+					Name:         alias.PublicIP.Name,
+					DNSName:      alias.PublicIP.DNSName,
+					Availability: alias.PublicIP.Availability,
+					Zone:         alias.PublicIP.Zone,
+				})
+			}
+		}
 	}
 	publicIPSpecs = append(publicIPSpecs, controlPlaneOutboundIPSpecs...)
 
@@ -150,18 +176,32 @@ func (s *ClusterScope) PublicIPSpecs() []azure.PublicIPSpec {
 	for _, subnet := range s.NodeSubnets() {
 		if subnet.IsNatGatewayEnabled() {
 			nodeNatGatewayIPSpecs = append(nodeNatGatewayIPSpecs, azure.PublicIPSpec{
-				Name:    subnet.NatGateway.NatGatewayIP.Name,
-				DNSName: subnet.NatGateway.NatGatewayIP.DNSName,
+				Name:         subnet.NatGateway.NatGatewayIP.Name,
+				DNSName:      subnet.NatGateway.NatGatewayIP.DNSName,
+				Availability: subnet.NatGateway.NatGatewayIP.Availability,
+				Zone:         subnet.NatGateway.NatGatewayIP.Zone,
 			})
 		}
 		publicIPSpecs = append(publicIPSpecs, nodeNatGatewayIPSpecs...)
 	}
 
+	// Public IP spec for the cross-region API server lb
+	if globalLB := s.GlobalAPIServerLB(); globalLB != nil && globalLB.PublicIP != nil {
+		publicIPSpecs = append(publicIPSpecs, azure.PublicIPSpec{
+			Name:     globalLB.PublicIP.Name,
+			DNSName:  globalLB.PublicIP.DNSName,
+			Tier:     infrav1.SKUTierGlobal,
+			Location: globalLB.Location,
+		})
+	}
+
 	if s.AzureCluster.Spec.BastionSpec.AzureBastion != nil {
 		// public IP for Azure Bastion.
 		azureBastionPublicIP := azure.PublicIPSpec{
-			Name:    s.AzureCluster.Spec.BastionSpec.AzureBastion.PublicIP.Name,
-			DNSName: s.AzureCluster.Spec.BastionSpec.AzureBastion.PublicIP.DNSName,
+			Name:         s.AzureCluster.Spec.BastionSpec.AzureBastion.PublicIP.Name,
+			DNSName:      s.AzureCluster.Spec.BastionSpec.AzureBastion.PublicIP.DNSName,
+			Availability: s.AzureCluster.Spec.BastionSpec.AzureBastion.PublicIP.Availability,
+			Zone:         s.AzureCluster.Spec.BastionSpec.AzureBastion.PublicIP.Zone,
 		}
 		publicIPSpecs = append(publicIPSpecs, azureBastionPublicIP)
 	}
@@ -171,26 +211,45 @@ func (s *ClusterScope) PublicIPSpecs() []azure.PublicIPSpec {
 
 // LBSpecs returns the load balancer specs.
 func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
-	specs := []azure.ResourceSpecGetter{
-		&loadbalancers.LBSpec{
-			// API Server LB
+	var specs []azure.ResourceSpecGetter
+	if external := s.APIServerLB().External; external != nil {
+		// External API Server LB, which is only verified to exist.
+		subscriptionID, resourceGroup := parseExternalLB(external)
+		specs = append(specs, &loadbalancers.LBSpec{
+			Name:            s.APIServerLB().Name,
+			ResourceGroup:   resourceGroup,
+			SubscriptionID:  subscriptionID,
+			ClusterName:     s.ClusterName(),
+			Type:            s.APIServerLB().Type,
+			Role:            infrav1.APIServerRole,
+			BackendPoolName: external.BackendPoolName,
+			External:        true,
+		})
+	} else {
+		// API Server LB
+		specs = append(specs, &loadbalancers.LBSpec{
 			Name:                 s.APIServerLB().Name,
 			ResourceGroup:        s.ResourceGroup(),
 			SubscriptionID:       s.SubscriptionID(),
 			ClusterName:          s.ClusterName(),
 			Location:             s.Location(),
+			ExtendedLocation:     s.ExtendedLocation(),
 			VNetName:             s.Vnet().Name,
 			VNetResourceGroup:    s.Vnet().ResourceGroup,
 			SubnetName:           s.ControlPlaneSubnet().Name,
 			FrontendIPConfigs:    s.APIServerLB().FrontendIPs,
 			APIServerPort:        s.APIServerPort(),
+			FrontendPort:         s.APIServerFrontendPort(),
+			AliasFrontends:       s.APIServerLB().AliasFrontends,
 			Type:                 s.APIServerLB().Type,
 			SKU:                  infrav1.SKUStandard,
 			Role:                 infrav1.APIServerRole,
 			BackendPoolName:      s.APIServerLBPoolName(s.APIServerLB().Name),
 			IdleTimeoutInMinutes: s.APIServerLB().IdleTimeoutInMinutes,
+			EnableTCPReset:       s.APIServerLB().EnableTCPReset,
+			LoadDistribution:     s.APIServerLB().LoadDistribution,
 			AdditionalTags:       s.AdditionalTags(),
-		},
+		})
 	}
 
 	// Node outbound LB
@@ -201,6 +260,7 @@ func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
 			SubscriptionID:       s.SubscriptionID(),
 			ClusterName:          s.ClusterName(),
 			Location:             s.Location(),
+			ExtendedLocation:     s.ExtendedLocation(),
 			VNetName:             s.Vnet().Name,
 			VNetResourceGroup:    s.Vnet().ResourceGroup,
 			FrontendIPConfigs:    s.NodeOutboundLB().FrontendIPs,
@@ -208,6 +268,7 @@ func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
 			SKU:                  s.NodeOutboundLB().SKU,
 			BackendPoolName:      s.OutboundPoolName(s.NodeOutboundLBName()),
 			IdleTimeoutInMinutes: s.NodeOutboundLB().IdleTimeoutInMinutes,
+			EnableTCPReset:       s.NodeOutboundLB().EnableTCPReset,
 			Role:                 infrav1.NodeOutboundRole,
 			AdditionalTags:       s.AdditionalTags(),
 		})
@@ -221,6 +282,7 @@ func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
 			SubscriptionID:       s.SubscriptionID(),
 			ClusterName:          s.ClusterName(),
 			Location:             s.Location(),
+			ExtendedLocation:     s.ExtendedLocation(),
 			VNetName:             s.Vnet().Name,
 			VNetResourceGroup:    s.Vnet().ResourceGroup,
 			FrontendIPConfigs:    s.ControlPlaneOutboundLB().FrontendIPs,
@@ -228,11 +290,43 @@ func (s *ClusterScope) LBSpecs() []azure.ResourceSpecGetter {
 			SKU:                  s.ControlPlaneOutboundLB().SKU,
 			BackendPoolName:      s.OutboundPoolName(azure.GenerateControlPlaneOutboundLBName(s.ClusterName())),
 			IdleTimeoutInMinutes: s.NodeOutboundLB().IdleTimeoutInMinutes,
+			EnableTCPReset:       s.ControlPlaneOutboundLB().EnableTCPReset,
 			Role:                 infrav1.ControlPlaneOutboundRole,
 			AdditionalTags:       s.AdditionalTags(),
 		})
 	}
 
+	// Cross-region API Server LB
+	if globalLB := s.GlobalAPIServerLB(); globalLB != nil && globalLB.PublicIP != nil {
+		// The frontend of the API Server LB of the cluster is always a backend of the cross-region LB.
+		regionalFrontendIDs := []string{
+			azure.FrontendIPConfigID(s.SubscriptionID(), s.ResourceGroup(), s.APIServerLB().Name, s.APIServerLB().FrontendIPs[0].Name),
+		}
+		regionalFrontendIDs = append(regionalFrontendIDs, globalLB.RegionalFrontendIPConfigurationIDs...)
+		specs = append(specs, &loadbalancers.LBSpec{
+			Name:           globalLB.Name,
+			ResourceGroup:  s.ResourceGroup(),
+			SubscriptionID: s.SubscriptionID(),
+			ClusterName:    s.ClusterName(),
+			Location:       globalLB.Location,
+			FrontendIPConfigs: []infrav1.FrontendIP{
+				{
+					Name:     azure.GenerateFrontendIPConfigName(globalLB.Name),
+					PublicIP: globalLB.PublicIP,
+				},
+			},
+			APIServerPort:               s.APIServerFrontendPort(),
+			Type:                        infrav1.Public,
+			SKU:                         infrav1.SKUStandard,
+			Tier:                        infrav1.SKUTierGlobal,
+			Role:                        infrav1.APIServerRole,
+			BackendPoolName:             s.APIServerLBPoolName(globalLB.Name),
+			LoadDistribution:            s.APIServerLB().LoadDistribution,
+			RegionalFrontendIPConfigIDs: regionalFrontendIDs,
+			AdditionalTags:              s.AdditionalTags(),
+		})
+	}
+
 	return specs
 }
 
@@ -305,6 +399,10 @@ func (s *ClusterScope) SubnetSpecs() []azure.ResourceSpecGetter {
 	if s.IsAzureBastionEnabled() {
 		numberOfSubnets++
 	}
+	dnsResolver := s.DNSResolver()
+	if dnsResolver != nil && dnsResolver.ForwardingRulesetID == "" {
+		numberOfSubnets++
+	}
 
 	subnetSpecs := make([]azure.ResourceSpecGetter, 0, numberOfSubnets)
 
@@ -341,6 +439,19 @@ func (s *ClusterScope) SubnetSpecs() []azure.ResourceSpecGetter {
 		})
 	}
 
+	if dnsResolver != nil && dnsResolver.ForwardingRulesetID == "" {
+		subnetSpecs = append(subnetSpecs, &subnets.SubnetSpec{
+			Name:              dnsResolver.OutboundSubnet.Name,
+			ResourceGroup:     s.ResourceGroup(),
+			SubscriptionID:    s.SubscriptionID(),
+			CIDRs:             dnsResolver.OutboundSubnet.CIDRBlocks,
+			VNetName:          s.Vnet().Name,
+			VNetResourceGroup: s.Vnet().ResourceGroup,
+			IsVNetManaged:     s.IsVnetManaged(),
+			Delegation:        dnsresolvers.SubnetDelegation,
+		})
+	}
+
 	return subnetSpecs
 }
 
@@ -381,21 +492,162 @@ func (s *ClusterScope) VnetPeeringSpecs() []azure.ResourceSpecGetter {
 	return peeringSpecs
 }
 
+// EventSubscriptionSpec returns the Event Grid subscription spec delivering resource notifications for the cluster resource group.
+func (s *ClusterScope) EventSubscriptionSpec() azure.ResourceSpecGetter {
+	if s.eventGridWebhookURL == "" {
+		return nil
+	}
+	return &eventsubscriptions.EventSubscriptionSpec{
+		Name:          azure.GenerateEventSubscriptionName(s.ClusterName()),
+		ResourceGroup: s.ResourceGroup(),
+		WebhookURL:    s.eventGridWebhookURL,
+	}
+}
+
 // VNetSpec returns the virtual network spec.
 func (s *ClusterScope) VNetSpec() azure.ResourceSpecGetter {
 	return &virtualnetworks.VNetSpec{
-		ResourceGroup:  s.Vnet().ResourceGroup,
-		Name:           s.Vnet().Name,
-		CIDRs:          s.Vnet().CIDRBlocks,
+		ResourceGroup:    s.Vnet().ResourceGroup,
+		Name:             s.Vnet().Name,
+		CIDRs:            s.Vnet().CIDRBlocks,
+		Location:         s.Location(),
+		ExtendedLocation: s.ExtendedLocation(),
+		ClusterName:      s.ClusterName(),
+		AdditionalTags:   s.AdditionalTags(),
+	}
+}
+
+// LogAnalyticsWorkspaceSpec returns the spec of the Log Analytics workspace created for the cluster.
+func (s *ClusterScope) LogAnalyticsWorkspaceSpec() azure.ResourceSpecGetter {
+	return getLogAnalyticsWorkspaceSpec(s, s.Monitoring())
+}
+
+// DataCollectionSpecs returns the specs of the data collection endpoint and rule of the cluster, or nil if the cluster
+// isn't onboarded to Azure Monitor.
+func (s *ClusterScope) DataCollectionSpecs() (endpointSpec, ruleSpec azure.ResourceSpecGetter) {
+	monitoring := s.Monitoring()
+	if monitoring == nil {
+		return nil, nil
+	}
+
+	endpointSpec = &datacollection.DataCollectionEndpointSpec{
+		Name:           azure.GenerateDataCollectionEndpointName(s.ClusterName()),
+		ResourceGroup:  s.ResourceGroup(),
 		Location:       s.Location(),
 		ClusterName:    s.ClusterName(),
 		AdditionalTags: s.AdditionalTags(),
 	}
+	ruleSpec = &datacollection.DataCollectionRuleSpec{
+		Name:           azure.GenerateDataCollectionRuleName(s.ClusterName()),
+		ResourceGroup:  s.ResourceGroup(),
+		Location:       s.Location(),
+		WorkspaceID:    getLogAnalyticsWorkspaceID(s, monitoring),
+		ClusterName:    s.ClusterName(),
+		AdditionalTags: s.AdditionalTags(),
+	}
+	return endpointSpec, ruleSpec
+}
+
+// PolicyExemptionSpecs returns the specs of the policy exemptions of the resource group of the cluster.
+func (s *ClusterScope) PolicyExemptionSpecs() []azure.ResourceSpecGetter {
+	specs := make([]azure.ResourceSpecGetter, 0, len(s.AzureCluster.Spec.PolicyExemptions))
+	for _, exemption := range s.AzureCluster.Spec.PolicyExemptions {
+		specs = append(specs, &policyexemptions.PolicyExemptionSpec{
+			Name:                         exemption.Name,
+			ResourceGroup:                s.ResourceGroup(),
+			SubscriptionID:               s.SubscriptionID(),
+			ClusterName:                  s.ClusterName(),
+			PolicyAssignmentID:           exemption.PolicyAssignmentID,
+			PolicyDefinitionReferenceIDs: exemption.PolicyDefinitionReferenceIDs,
+			Category:                     exemption.Category,
+			Justification:                exemption.Justification,
+			Metadata:                     exemption.Metadata,
+			ExpiresOn:                    exemption.ExpiresOn,
+		})
+	}
+	return specs
+}
+
+// DNSResolverSpecs returns the specs of the DNS private resolver of the cluster, its outbound endpoint, its DNS forwarding
+// ruleset and rules and the link of the ruleset to the virtual network, in the order they need to be created.
+// When an existing DNS forwarding ruleset is referenced, only the link of that ruleset to the virtual network is returned.
+func (s *ClusterScope) DNSResolverSpecs() []azure.ResourceSpecGetter {
+	dnsResolver := s.DNSResolver()
+	if dnsResolver == nil {
+		return nil
+	}
+
+	linkSpec := &dnsresolvers.VirtualNetworkLinkSpec{
+		Name:   azure.GenerateDNSForwardingRulesetLinkName(s.Vnet().ResourceGroup, s.Vnet().Name),
+		VNetID: azure.VNetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name),
+	}
+
+	if dnsResolver.ForwardingRulesetID != "" {
+		rulesetID, err := azure.ParseResourceID(dnsResolver.ForwardingRulesetID)
+		if err != nil {
+			// The ID of the DNS forwarding ruleset is validated by the webhook.
+			return nil
+		}
+		linkSpec.RulesetID = dnsResolver.ForwardingRulesetID
+		linkSpec.RulesetName = rulesetID.Name
+		linkSpec.RulesetResourceGroup = rulesetID.ResourceGroupName
+		return []azure.ResourceSpecGetter{linkSpec}
+	}
+
+	specs := make([]azure.ResourceSpecGetter, 0, 4+len(dnsResolver.ForwardingRules))
+	specs = append(specs,
+		&dnsresolvers.DNSResolverSpec{
+			Name:           dnsResolver.Name,
+			ResourceGroup:  s.ResourceGroup(),
+			SubscriptionID: s.SubscriptionID(),
+			ClusterName:    s.ClusterName(),
+			Location:       s.Location(),
+			VNetID:         azure.VNetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name),
+			AdditionalTags: s.AdditionalTags(),
+		},
+		&dnsresolvers.OutboundEndpointSpec{
+			Name:           dnsResolver.Name,
+			ResourceGroup:  s.ResourceGroup(),
+			SubscriptionID: s.SubscriptionID(),
+			ClusterName:    s.ClusterName(),
+			Location:       s.Location(),
+			ResolverName:   dnsResolver.Name,
+			SubnetID:       azure.SubnetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name, dnsResolver.OutboundSubnet.Name),
+			AdditionalTags: s.AdditionalTags(),
+		},
+		&dnsresolvers.ForwardingRulesetSpec{
+			Name:               dnsResolver.Name,
+			ResourceGroup:      s.ResourceGroup(),
+			SubscriptionID:     s.SubscriptionID(),
+			ClusterName:        s.ClusterName(),
+			Location:           s.Location(),
+			OutboundEndpointID: azure.DNSResolverOutboundEndpointID(s.SubscriptionID(), s.ResourceGroup(), dnsResolver.Name, dnsResolver.Name),
+			AdditionalTags:     s.AdditionalTags(),
+		},
+	)
+
+	rulesetID := azure.DNSForwardingRulesetID(s.SubscriptionID(), s.ResourceGroup(), dnsResolver.Name)
+	for _, rule := range dnsResolver.ForwardingRules {
+		specs = append(specs, &dnsresolvers.ForwardingRuleSpec{
+			Name:                 rule.Name,
+			RulesetID:            rulesetID,
+			RulesetName:          dnsResolver.Name,
+			RulesetResourceGroup: s.ResourceGroup(),
+			DomainName:           rule.DomainName,
+			TargetDNSServers:     rule.TargetDNSServers,
+		})
+	}
+
+	linkSpec.RulesetID = rulesetID
+	linkSpec.RulesetName = dnsResolver.Name
+	linkSpec.RulesetResourceGroup = s.ResourceGroup()
+	return append(specs, linkSpec)
 }
 
-// PrivateDNSSpec returns the private dns zone spec.
+// PrivateDNSSpec returns the private dns zone spec. The host of the frontend of an external API Server LB is resolved
+// by the DNS of its owner.
 func (s *ClusterScope) PrivateDNSSpec() (zoneSpec azure.ResourceSpecGetter, linkSpec, recordSpec []azure.ResourceSpecGetter) {
-	if s.IsAPIServerPrivate() {
+	if s.IsAPIServerPrivate() && s.APIServerLB().External == nil {
 		zone := privatedns.ZoneSpec{
 			Name:           s.GetPrivateDNSZoneName(),
 			ResourceGroup:  s.ResourceGroup(),
@@ -580,8 +832,22 @@ func (s *ClusterScope) ControlPlaneOutboundLB() *infrav1.LoadBalancerSpec {
 	return s.AzureCluster.Spec.NetworkSpec.ControlPlaneOutboundLB
 }
 
-// APIServerLBName returns the API Server LB name.
+// DNSResolver returns the DNS private resolver of the cluster, if any.
+func (s *ClusterScope) DNSResolver() *infrav1.DNSResolverSpec {
+	return s.AzureCluster.Spec.NetworkSpec.DNSResolver
+}
+
+// GlobalAPIServerLB returns the cross-region load balancer in front of the API server load balancer.
+func (s *ClusterScope) GlobalAPIServerLB() *infrav1.GlobalLoadBalancerSpec {
+	return s.AzureCluster.Spec.NetworkSpec.GlobalAPIServerLB
+}
+
+// APIServerLBName returns the API Server LB name, or an empty string if the API Server LB is external, as the inbound
+// NAT rules and outbound rules of external load balancers are not managed.
 func (s *ClusterScope) APIServerLBName() string {
+	if s.APIServerLB().External != nil {
+		return ""
+	}
 	return s.APIServerLB().Name
 }
 
@@ -663,6 +929,27 @@ func (s *ClusterScope) Location() string {
 	return s.AzureCluster.Spec.Location
 }
 
+// ExtendedLocation returns the extended location of the cluster, or nil if the cluster isn't in an extended location.
+func (s *ClusterScope) ExtendedLocation() *infrav1.ExtendedLocationSpec {
+	return s.AzureCluster.Spec.ExtendedLocation
+}
+
+// GetClient returns the controller-runtime client.
+func (s *ClusterScope) GetClient() client.Client {
+	return s.Client
+}
+
+// ASOBackendEnabled returns true if the Azure resources of the cluster are reconciled through Azure Service Operator
+// resources.
+func (s *ClusterScope) ASOBackendEnabled() bool {
+	return s.AzureCluster.Spec.ReconciliationBackend == infrav1.ReconciliationBackendASO && feature.Gates.Enabled(feature.ASOBackend)
+}
+
+// IsHibernated returns true if the VMs of the cluster should be deallocated.
+func (s *ClusterScope) IsHibernated() bool {
+	return s.AzureCluster.Spec.Paused == infrav1.PowerStateDeallocated
+}
+
 // AvailabilitySetEnabled informs machines that they should be part of an Availability Set.
 func (s *ClusterScope) AvailabilitySetEnabled() bool {
 	return len(s.AzureCluster.Status.FailureDomains) == 0
@@ -673,6 +960,47 @@ func (s *ClusterScope) CloudProviderConfigOverrides() *infrav1.CloudProviderConf
 	return s.AzureCluster.Spec.CloudProviderConfigOverrides
 }
 
+// BootstrapDataStorage returns the storage used to deliver large bootstrap data to the machines of the cluster.
+func (s *ClusterScope) BootstrapDataStorage() *infrav1.BootstrapDataStorage {
+	return s.AzureCluster.Spec.BootstrapDataStorage
+}
+
+// ProxyConfig returns the HTTP proxy the machines of the cluster reach the internet through.
+func (s *ClusterScope) ProxyConfig() *infrav1.ProxyConfig {
+	return s.AzureCluster.Spec.Proxy
+}
+
+// BootstrapExtension returns the configuration of the VM extension checking the bootstrap of the machines of the cluster.
+func (s *ClusterScope) BootstrapExtension() *infrav1.BootstrapExtension {
+	return s.AzureCluster.Spec.BootstrapExtension
+}
+
+// RegistryMirrors returns the mirrors the machines of the cluster pull container images through.
+func (s *ClusterScope) RegistryMirrors() []infrav1.RegistryMirror {
+	return s.AzureCluster.Spec.RegistryMirrors
+}
+
+// Monitoring returns the Azure Monitor onboarding of the cluster.
+func (s *ClusterScope) Monitoring() *infrav1.AzureMonitoring {
+	return s.AzureCluster.Spec.Monitoring
+}
+
+// SecurityProfile returns the security settings of the cluster.
+func (s *ClusterScope) SecurityProfile() *infrav1.ClusterSecurityProfile {
+	return s.AzureCluster.Spec.SecurityProfile
+}
+
+// AttachedACRs returns the resource IDs of the Azure Container Registries attached to the cluster.
+func (s *ClusterScope) AttachedACRs() []string {
+	return s.AzureCluster.Spec.AttachedACRs
+}
+
+// CrossSubscriptionAccess returns how the access of the cluster identity to the resources of the machines in other
+// subscriptions is handled.
+func (s *ClusterScope) CrossSubscriptionAccess() infrav1.CrossSubscriptionAccessPolicy {
+	return s.AzureCluster.Spec.CrossSubscriptionAccess
+}
+
 // GenerateFQDN generates a fully qualified domain name, based on a hash, cluster name and cluster location.
 func (s *ClusterScope) GenerateFQDN(ipName string) string {
 	h := fnv.New32a()
@@ -714,10 +1042,14 @@ func (s *ClusterScope) PatchObject(ctx context.Context) error {
 		s.AzureCluster,
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			clusterv1.ReadyCondition,
+			infrav1.IdentityPermissionsReadyCondition,
 			infrav1.ResourceGroupReadyCondition,
 			infrav1.RouteTablesReadyCondition,
 			infrav1.NetworkInfrastructureReadyCondition,
 			infrav1.VnetPeeringReadyCondition,
+			infrav1.DNSResolverReadyCondition,
+			infrav1.PolicyExemptionsReadyCondition,
+			infrav1.EventSubscriptionReadyCondition,
 			infrav1.DisksReadyCondition,
 			infrav1.NATGatewaysReadyCondition,
 			infrav1.LoadBalancersReadyCondition,
@@ -736,12 +1068,14 @@ func (s *ClusterScope) Close(ctx context.Context) error {
 	return s.PatchObject(ctx)
 }
 
-// AdditionalTags returns AdditionalTags from the scope's AzureCluster.
+// AdditionalTags returns AdditionalTags from the scope's AzureCluster, merged with the mandatory tags of the tagging
+// policy, which take precedence.
 func (s *ClusterScope) AdditionalTags() infrav1.Tags {
 	tags := make(infrav1.Tags)
 	if s.AzureCluster.Spec.AdditionalTags != nil {
 		tags = s.AzureCluster.Spec.AdditionalTags.DeepCopy()
 	}
+	tags.Merge(azure.MandatoryTags(s.ClusterName()))
 	return tags
 }
 
@@ -753,8 +1087,17 @@ func (s *ClusterScope) APIServerPort() int32 {
 	return 6443
 }
 
+// APIServerFrontendPort returns the port of the frontend IP of the API server load balancer, which is the port of the
+// control plane endpoint.
+func (s *ClusterScope) APIServerFrontendPort() int32 {
+	return pointer.Int32Deref(s.APIServerLB().FrontendPort, s.APIServerPort())
+}
+
 // APIServerHost returns the hostname used to reach the API server.
 func (s *ClusterScope) APIServerHost() string {
+	if s.APIServerLB().External != nil {
+		return s.AzureCluster.Spec.ControlPlaneEndpoint.Host
+	}
 	if s.IsAPIServerPrivate() {
 		return azure.GeneratePrivateFQDN(s.GetPrivateDNSZoneName())
 	}
@@ -792,10 +1135,10 @@ func (s *ClusterScope) SetControlPlaneSecurityRules() {
 				Priority:         2200,
 				Protocol:         infrav1.SecurityGroupProtocolTCP,
 				Direction:        infrav1.SecurityRuleDirectionInbound,
-				Source:           to.StringPtr("*"),
-				SourcePorts:      to.StringPtr("*"),
-				Destination:      to.StringPtr("*"),
-				DestinationPorts: to.StringPtr("22"),
+				Source:           to.Ptr("*"),
+				SourcePorts:      to.Ptr("*"),
+				Destination:      to.Ptr("*"),
+				DestinationPorts: to.Ptr("22"),
 			},
 			infrav1.SecurityRule{
 				Name:             "allow_apiserver",
@@ -803,10 +1146,10 @@ func (s *ClusterScope) SetControlPlaneSecurityRules() {
 				Priority:         2201,
 				Protocol:         infrav1.SecurityGroupProtocolTCP,
 				Direction:        infrav1.SecurityRuleDirectionInbound,
-				Source:           to.StringPtr("*"),
-				SourcePorts:      to.StringPtr("*"),
-				Destination:      to.StringPtr("*"),
-				DestinationPorts: to.StringPtr(strconv.Itoa(int(s.APIServerPort()))),
+				Source:           to.Ptr("*"),
+				SourcePorts:      to.Ptr("*"),
+				Destination:      to.Ptr("*"),
+				DestinationPorts: to.Ptr(strconv.Itoa(int(s.APIServerPort()))),
 			},
 		}
 		s.AzureCluster.Spec.NetworkSpec.UpdateControlPlaneSubnet(subnet)
@@ -841,11 +1184,21 @@ func (s *ClusterScope) SetDNSName() {
 	}
 	// Generate valid FQDN if not set.
 	// Note: this function uses the AzureCluster subscription ID.
-	if !s.IsAPIServerPrivate() && s.APIServerPublicIP().DNSName == "" {
+	if !s.IsAPIServerPrivate() && s.APIServerLB().External == nil && s.APIServerPublicIP().DNSName == "" {
 		s.APIServerPublicIP().DNSName = s.GenerateFQDN(s.APIServerPublicIP().Name)
 	}
 }
 
+// parseExternalLB returns the subscription and resource group of an external load balancer, whose resource ID is
+// validated by the webhook.
+func parseExternalLB(external *infrav1.ExternalLoadBalancer) (subscriptionID, resourceGroup string) {
+	resource, err := azure.ParseResourceID(external.ID)
+	if err != nil {
+		return "", ""
+	}
+	return resource.SubscriptionID, resource.ResourceGroupName
+}
+
 // getOutboundLBPublicIPSpecs returns the public ip specs for a LoadBalancerSpec based on the number of frontend ips configured.
 func (s *ClusterScope) getOutboundLBPublicIPSpecs(outboundLB *infrav1.LoadBalancerSpec, generateOutboundIPName func(string) string) []azure.PublicIPSpec {
 	var outboundIPSpecs []azure.PublicIPSpec
@@ -864,6 +1217,13 @@ func (s *ClusterScope) getOutboundLBPublicIPSpecs(outboundLB *infrav1.LoadBalanc
 			})
 		}
 	}
+	// The frontend IPs are generated in the same order as the public IPs, see setOutboundLBFrontendIPs.
+	for i := range outboundIPSpecs {
+		if i < len(outboundLB.FrontendIPs) && outboundLB.FrontendIPs[i].PublicIP != nil {
+			outboundIPSpecs[i].Availability = outboundLB.FrontendIPs[i].PublicIP.Availability
+			outboundIPSpecs[i].Zone = outboundLB.FrontendIPs[i].PublicIP.Zone
+		}
+	}
 	return outboundIPSpecs
 }
 
@@ -883,13 +1243,23 @@ func (s *ClusterScope) DeleteLongRunningOperationState(name, service string) {
 	futures.Delete(s.AzureCluster, name, service)
 }
 
+// SetResourceStatus will record the Azure resource with the given ID on the AzureCluster status.
+func (s *ClusterScope) SetResourceStatus(id, resourceType, service string) {
+	resources.Set(s.AzureCluster, id, resourceType, service)
+}
+
+// DeleteResourceStatus will delete the Azure resource from the AzureCluster status.
+func (s *ClusterScope) DeleteResourceStatus(name, service string) {
+	resources.Delete(s.AzureCluster, name, service)
+}
+
 // UpdateDeleteStatus updates a condition on the AzureCluster status after a DELETE operation.
 func (s *ClusterScope) UpdateDeleteStatus(condition clusterv1.ConditionType, service string, err error) {
 	switch {
 	case err == nil:
 		conditions.MarkFalse(s.AzureCluster, condition, infrav1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s successfully deleted", service)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(s.AzureCluster, condition, infrav1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting", service)
+		conditions.MarkFalse(s.AzureCluster, condition, infrav1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(s.AzureCluster, condition, infrav1.DeletionFailedReason, clusterv1.ConditionSeverityError, "%s failed to delete. err: %s", service, err.Error())
 	}
@@ -901,7 +1271,7 @@ func (s *ClusterScope) UpdatePutStatus(condition clusterv1.ConditionType, servic
 	case err == nil:
 		conditions.MarkTrue(s.AzureCluster, condition)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(s.AzureCluster, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating", service)
+		conditions.MarkFalse(s.AzureCluster, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(s.AzureCluster, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to create or update. err: %s", service, err.Error())
 	}
@@ -913,12 +1283,76 @@ func (s *ClusterScope) UpdatePatchStatus(condition clusterv1.ConditionType, serv
 	case err == nil:
 		conditions.MarkTrue(s.AzureCluster, condition)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(s.AzureCluster, condition, infrav1.UpdatingReason, clusterv1.ConditionSeverityInfo, "%s updating", service)
+		conditions.MarkFalse(s.AzureCluster, condition, infrav1.UpdatingReason, clusterv1.ConditionSeverityInfo, "%s updating%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(s.AzureCluster, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to update. err: %s", service, err.Error())
 	}
 }
 
+// ARMTemplateExportRequested returns true if the ARMTemplateExportRequestAnnotation requests an ARM template which
+// wasn't exported yet.
+func (s *ClusterScope) ARMTemplateExportRequested() bool {
+	request := s.AzureCluster.Annotations[infrav1.ARMTemplateExportRequestAnnotation]
+	return request != "" && request != s.AzureCluster.Status.LastARMTemplateExportRequest
+}
+
+// SaveARMTemplate stores the ARM template exported for the AzureCluster and records that the request is handled.
+func (s *ClusterScope) SaveARMTemplate(ctx context.Context, template *armtemplate.Template) error {
+	if err := saveARMTemplate(ctx, s.Client, s.AzureCluster, template); err != nil {
+		return err
+	}
+	s.AzureCluster.Status.LastARMTemplateExportRequest = s.AzureCluster.Annotations[infrav1.ARMTemplateExportRequestAnnotation]
+	return nil
+}
+
+// RoleDefinitionExportRequested returns true if the RoleDefinitionExportRequestAnnotation requests a role definition
+// which wasn't exported yet.
+func (s *ClusterScope) RoleDefinitionExportRequested() bool {
+	request := s.AzureCluster.Annotations[infrav1.RoleDefinitionExportRequestAnnotation]
+	return request != "" && request != s.AzureCluster.Status.LastRoleDefinitionExportRequest
+}
+
+// RoleDefinitionCreationRequested returns true if the RoleDefinitionCreateAnnotation requests the creation of the
+// exported role definitions in Azure.
+func (s *ClusterScope) RoleDefinitionCreationRequested() bool {
+	return s.AzureCluster.Annotations[infrav1.RoleDefinitionCreateAnnotation] == "true"
+}
+
+// SaveRoleDefinition stores the role definition exported for the AzureCluster and records that the request is handled.
+func (s *ClusterScope) SaveRoleDefinition(ctx context.Context, roleDefinition roledefinitions.RoleDefinition) error {
+	if err := saveRoleDefinition(ctx, s.Client, s.AzureCluster, roleDefinition); err != nil {
+		return err
+	}
+	s.AzureCluster.Status.LastRoleDefinitionExportRequest = s.AzureCluster.Annotations[infrav1.RoleDefinitionExportRequestAnnotation]
+	return nil
+}
+
+// Identity returns the AzureClusterIdentity the AzureCluster is reconciled with, as "namespace/name", or an empty string
+// if the credentials of the manager environment are used.
+func (s *ClusterScope) Identity() string {
+	ref := s.AzureCluster.Spec.IdentityRef
+	if ref == nil {
+		return ""
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = s.AzureCluster.Namespace
+	}
+	return namespace + "/" + ref.Name
+}
+
+// RecordIdentityUsage records the AzureClusterIdentity and the token audience the AzureCluster is reconciled with in its
+// annotations. It returns the identity previously recorded, and false if no identity was recorded yet.
+func (s *ClusterScope) RecordIdentityUsage() (string, bool) {
+	previous, recorded := s.AzureCluster.Annotations[infrav1.IdentityAnnotation]
+	if s.AzureCluster.Annotations == nil {
+		s.AzureCluster.Annotations = make(map[string]string)
+	}
+	s.AzureCluster.Annotations[infrav1.IdentityAnnotation] = s.Identity()
+	s.AzureCluster.Annotations[infrav1.TokenAudienceAnnotation] = s.ResourceManagerEndpoint
+	return previous, recorded
+}
+
 // AnnotationJSON returns a map[string]interface from a JSON annotation.
 func (s *ClusterScope) AnnotationJSON(annotation string) (map[string]interface{}, error) {
 	out := map[string]interface{}{}
@@ -23,16 +23,18 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsresolvers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/policyexemptions"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/securitygroups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
@@ -138,6 +140,28 @@ func TestAPIServerHost(t *testing.T) {
 			},
 			want: "apiserver.example.private",
 		},
+		{
+			name: "external apiserver lb",
+			azureCluster: infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: fakeSubscriptionID,
+					},
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{
+						Host: "my-cluster.api.example.com",
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						APIServerLB: infrav1.LoadBalancerSpec{
+							External: &infrav1.ExternalLoadBalancer{
+								ID:              "/subscriptions/123/resourceGroups/network-rg/providers/Microsoft.Network/loadBalancers/shared-lb",
+								BackendPoolName: "my-cluster-pool",
+							},
+						},
+					},
+				},
+			},
+			want: "my-cluster.api.example.com",
+		},
 	}
 
 	for _, tc := range tests {
@@ -170,9 +194,6 @@ func TestAPIServerHost(t *testing.T) {
 		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
 		clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-			AzureClients: AzureClients{
-				Authorizer: autorest.NullAuthorizer{},
-			},
 			Cluster:      cluster,
 			AzureCluster: &tc.azureCluster,
 			Client:       fakeClient,
@@ -183,6 +204,61 @@ func TestAPIServerHost(t *testing.T) {
 	}
 }
 
+func TestExternalAPIServerLBSpecs(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterScope := &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: EnvironmentSettings{
+				Values: map[string]string{
+					envSubscriptionID: "123",
+				},
+			},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-cluster",
+			},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				NetworkSpec: infrav1.NetworkSpec{
+					APIServerLB: infrav1.LoadBalancerSpec{
+						Name: "shared-lb",
+						External: &infrav1.ExternalLoadBalancer{
+							ID:              "/subscriptions/123/resourceGroups/network-rg/providers/Microsoft.Network/loadBalancers/shared-lb",
+							BackendPoolName: "my-cluster-pool",
+						},
+						LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+							Type: infrav1.Internal,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	g.Expect(clusterScope.LBSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&loadbalancers.LBSpec{
+			Name:            "shared-lb",
+			ResourceGroup:   "network-rg",
+			SubscriptionID:  "123",
+			ClusterName:     "my-cluster",
+			Type:            infrav1.Internal,
+			Role:            infrav1.APIServerRole,
+			BackendPoolName: "my-cluster-pool",
+			External:        true,
+		},
+	}))
+	g.Expect(clusterScope.PublicIPSpecs()).To(BeEmpty())
+	g.Expect(clusterScope.APIServerLBName()).To(BeEmpty())
+	zone, links, records := clusterScope.PrivateDNSSpec()
+	g.Expect(zone).To(BeNil())
+	g.Expect(links).To(BeEmpty())
+	g.Expect(records).To(BeEmpty())
+}
+
 func TestGettingSecurityRules(t *testing.T) {
 	g := NewWithT(t)
 	scheme := runtime.NewScheme()
@@ -230,9 +306,6 @@ func TestGettingSecurityRules(t *testing.T) {
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
 	clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-		AzureClients: AzureClients{
-			Authorizer: autorest.NullAuthorizer{},
-		},
 		Cluster:      cluster,
 		AzureCluster: azureCluster,
 		Client:       fakeClient,
@@ -327,7 +400,7 @@ func TestPublicIPSpecs(t *testing.T) {
 					},
 					NetworkSpec: infrav1.NetworkSpec{
 						ControlPlaneOutboundLB: &infrav1.LoadBalancerSpec{
-							FrontendIPsCount:      to.Int32Ptr(1),
+							FrontendIPsCount:      to.Ptr[int32](1),
 							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{},
 						},
 						APIServerLB: infrav1.LoadBalancerSpec{
@@ -365,7 +438,7 @@ func TestPublicIPSpecs(t *testing.T) {
 					},
 					NetworkSpec: infrav1.NetworkSpec{
 						ControlPlaneOutboundLB: &infrav1.LoadBalancerSpec{
-							FrontendIPsCount:      to.Int32Ptr(3),
+							FrontendIPsCount:      to.Ptr[int32](3),
 							LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{},
 						},
 						APIServerLB: infrav1.LoadBalancerSpec{
@@ -555,6 +628,58 @@ func TestPublicIPSpecs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Azure cluster with a cross-region API server LB",
+			azureCluster: &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "cluster.x-k8s.io/v1beta1",
+							Kind:       "Cluster",
+							Name:       "my-cluster",
+						},
+					},
+				},
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						SubscriptionID: "123",
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						APIServerLB: infrav1.LoadBalancerSpec{
+							FrontendIPs: []infrav1.FrontendIP{
+								{
+									PublicIP: &infrav1.PublicIPSpec{
+										Name:    "40.60.89.22",
+										DNSName: "fake-dns",
+									},
+								},
+							},
+						},
+						GlobalAPIServerLB: &infrav1.GlobalLoadBalancerSpec{
+							Name:     "my-cluster-global-lb",
+							Location: "eastus2",
+							PublicIP: &infrav1.PublicIPSpec{
+								Name:    "pip-my-cluster-apiserver-global",
+								DNSName: "fake-global-dns",
+							},
+						},
+					},
+				},
+			},
+			expectedPublicIPSpec: []azure.PublicIPSpec{
+				{
+					Name:    "40.60.89.22",
+					DNSName: "fake-dns",
+				},
+				{
+					Name:     "pip-my-cluster-apiserver-global",
+					DNSName:  "fake-global-dns",
+					Tier:     infrav1.SKUTierGlobal,
+					Location: "eastus2",
+				},
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -576,9 +701,6 @@ func TestPublicIPSpecs(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
 			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster:      cluster,
 				AzureCluster: tc.azureCluster,
 				Client:       fakeClient,
@@ -700,9 +822,9 @@ func TestNatGatewaySpecs(t *testing.T) {
 					},
 				},
 				AzureClients: AzureClients{
-					EnvironmentSettings: auth.EnvironmentSettings{
+					EnvironmentSettings: EnvironmentSettings{
 						Values: map[string]string{
-							auth.SubscriptionID: "123",
+							envSubscriptionID: "123",
 						},
 					},
 				},
@@ -759,9 +881,9 @@ func TestNatGatewaySpecs(t *testing.T) {
 					},
 				},
 				AzureClients: AzureClients{
-					EnvironmentSettings: auth.EnvironmentSettings{
+					EnvironmentSettings: EnvironmentSettings{
 						Values: map[string]string{
-							auth.SubscriptionID: "123",
+							envSubscriptionID: "123",
 						},
 					},
 				},
@@ -836,9 +958,9 @@ func TestNatGatewaySpecs(t *testing.T) {
 					},
 				},
 				AzureClients: AzureClients{
-					EnvironmentSettings: auth.EnvironmentSettings{
+					EnvironmentSettings: EnvironmentSettings{
 						Values: map[string]string{
-							auth.SubscriptionID: "123",
+							envSubscriptionID: "123",
 						},
 					},
 				},
@@ -1024,9 +1146,9 @@ func TestSubnetSpecs(t *testing.T) {
 					},
 				},
 				AzureClients: AzureClients{
-					EnvironmentSettings: auth.EnvironmentSettings{
+					EnvironmentSettings: EnvironmentSettings{
 						Values: map[string]string{
-							auth.SubscriptionID: "123",
+							envSubscriptionID: "123",
 						},
 					},
 				},
@@ -1100,9 +1222,9 @@ func TestSubnetSpecs(t *testing.T) {
 					},
 				},
 				AzureClients: AzureClients{
-					EnvironmentSettings: auth.EnvironmentSettings{
+					EnvironmentSettings: EnvironmentSettings{
 						Values: map[string]string{
-							auth.SubscriptionID: "123",
+							envSubscriptionID: "123",
 						},
 					},
 				},
@@ -1217,6 +1339,186 @@ func TestSubnetSpecs(t *testing.T) {
 	}
 }
 
+func TestPolicyExemptionSpecs(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterScope := ClusterScope{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-cluster",
+			},
+		},
+		AzureClients: AzureClients{
+			EnvironmentSettings: EnvironmentSettings{
+				Values: map[string]string{
+					envSubscriptionID: "123",
+				},
+			},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+			},
+		},
+	}
+	g.Expect(clusterScope.PolicyExemptionSpecs()).To(BeEmpty())
+
+	clusterScope.AzureCluster.Spec.PolicyExemptions = []infrav1.PolicyExemption{
+		{
+			Name:               "allow-public-ip",
+			PolicyAssignmentID: "/providers/Microsoft.Management/managementGroups/my-mg/providers/Microsoft.Authorization/policyAssignments/deny-public-ip",
+			Category:           infrav1.PolicyExemptionCategoryWaiver,
+			Justification:      "The API server load balancer needs a public IP.",
+			Metadata:           map[string]string{"ticket": "SEC-1234"},
+		},
+	}
+	g.Expect(clusterScope.PolicyExemptionSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&policyexemptions.PolicyExemptionSpec{
+			Name:               "allow-public-ip",
+			ResourceGroup:      "my-rg",
+			SubscriptionID:     "123",
+			ClusterName:        "my-cluster",
+			PolicyAssignmentID: "/providers/Microsoft.Management/managementGroups/my-mg/providers/Microsoft.Authorization/policyAssignments/deny-public-ip",
+			Category:           infrav1.PolicyExemptionCategoryWaiver,
+			Justification:      "The API server load balancer needs a public IP.",
+			Metadata:           map[string]string{"ticket": "SEC-1234"},
+		},
+	}))
+}
+
+func TestDNSResolverSpecs(t *testing.T) {
+	newClusterScope := func(resolver *infrav1.DNSResolverSpec) ClusterScope {
+		return ClusterScope{
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster",
+				},
+			},
+			AzureClients: AzureClients{
+				EnvironmentSettings: EnvironmentSettings{
+					Values: map[string]string{
+						envSubscriptionID: "123",
+					},
+				},
+			},
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						Location: "westus",
+					},
+					NetworkSpec: infrav1.NetworkSpec{
+						Vnet: infrav1.VnetSpec{
+							Name:          "my-vnet",
+							ResourceGroup: "my-rg-vnet",
+						},
+						DNSResolver: resolver,
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		clusterScope ClusterScope
+		want         []azure.ResourceSpecGetter
+	}{
+		{
+			name:         "returns nil if no DNS private resolver is specified",
+			clusterScope: newClusterScope(nil),
+			want:         nil,
+		},
+		{
+			name: "returns the DNS private resolver, its forwarding ruleset and the link to the virtual network",
+			clusterScope: newClusterScope(&infrav1.DNSResolverSpec{
+				Name: "my-dns-resolver",
+				OutboundSubnet: infrav1.DNSResolverSubnet{
+					Name:       "dns-resolver-outbound-subnet",
+					CIDRBlocks: []string{"10.255.255.208/28"},
+				},
+				ForwardingRules: []infrav1.DNSForwardingRule{
+					{
+						Name:             "on-prem",
+						DomainName:       "corp.example.com",
+						TargetDNSServers: []infrav1.TargetDNSServer{{IPAddress: "10.1.0.4"}},
+					},
+				},
+			}),
+			want: []azure.ResourceSpecGetter{
+				&dnsresolvers.DNSResolverSpec{
+					Name:           "my-dns-resolver",
+					ResourceGroup:  "my-rg",
+					SubscriptionID: "123",
+					ClusterName:    "my-cluster",
+					Location:       "westus",
+					VNetID:         "/subscriptions/123/resourceGroups/my-rg-vnet/providers/Microsoft.Network/virtualNetworks/my-vnet",
+					AdditionalTags: infrav1.Tags{},
+				},
+				&dnsresolvers.OutboundEndpointSpec{
+					Name:           "my-dns-resolver",
+					ResourceGroup:  "my-rg",
+					SubscriptionID: "123",
+					ClusterName:    "my-cluster",
+					Location:       "westus",
+					ResolverName:   "my-dns-resolver",
+					SubnetID:       "/subscriptions/123/resourceGroups/my-rg-vnet/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/dns-resolver-outbound-subnet",
+					AdditionalTags: infrav1.Tags{},
+				},
+				&dnsresolvers.ForwardingRulesetSpec{
+					Name:               "my-dns-resolver",
+					ResourceGroup:      "my-rg",
+					SubscriptionID:     "123",
+					ClusterName:        "my-cluster",
+					Location:           "westus",
+					OutboundEndpointID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/dnsResolvers/my-dns-resolver/outboundEndpoints/my-dns-resolver",
+					AdditionalTags:     infrav1.Tags{},
+				},
+				&dnsresolvers.ForwardingRuleSpec{
+					Name:                 "on-prem",
+					RulesetID:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/dnsForwardingRulesets/my-dns-resolver",
+					RulesetName:          "my-dns-resolver",
+					RulesetResourceGroup: "my-rg",
+					DomainName:           "corp.example.com",
+					TargetDNSServers:     []infrav1.TargetDNSServer{{IPAddress: "10.1.0.4"}},
+				},
+				&dnsresolvers.VirtualNetworkLinkSpec{
+					Name:                 "my-rg-vnet-my-vnet",
+					RulesetID:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/dnsForwardingRulesets/my-dns-resolver",
+					RulesetName:          "my-dns-resolver",
+					RulesetResourceGroup: "my-rg",
+					VNetID:               "/subscriptions/123/resourceGroups/my-rg-vnet/providers/Microsoft.Network/virtualNetworks/my-vnet",
+				},
+			},
+		},
+		{
+			name: "returns only the link of an existing DNS forwarding ruleset",
+			clusterScope: newClusterScope(&infrav1.DNSResolverSpec{
+				ForwardingRulesetID: "/subscriptions/456/resourceGroups/hub-rg/providers/Microsoft.Network/dnsForwardingRulesets/hub-ruleset",
+			}),
+			want: []azure.ResourceSpecGetter{
+				&dnsresolvers.VirtualNetworkLinkSpec{
+					Name:                 "my-rg-vnet-my-vnet",
+					RulesetID:            "/subscriptions/456/resourceGroups/hub-rg/providers/Microsoft.Network/dnsForwardingRulesets/hub-ruleset",
+					RulesetName:          "hub-ruleset",
+					RulesetResourceGroup: "hub-rg",
+					VNetID:               "/subscriptions/123/resourceGroups/my-rg-vnet/providers/Microsoft.Network/virtualNetworks/my-vnet",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.clusterScope.DNSResolverSpecs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DNSResolverSpecs() = \n%s, want \n%s", specArrayToString(got), specArrayToString(tt.want))
+			}
+		})
+	}
+}
+
 func TestAzureBastionSpec(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1245,9 +1547,9 @@ func TestAzureBastionSpec(t *testing.T) {
 					},
 				},
 				AzureClients: AzureClients{
-					EnvironmentSettings: auth.EnvironmentSettings{
+					EnvironmentSettings: EnvironmentSettings{
 						Values: map[string]string{
-							auth.SubscriptionID: "123",
+							envSubscriptionID: "123",
 						},
 					},
 				},
@@ -1421,9 +1723,6 @@ func TestSubnet(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
 			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster:      cluster,
 				AzureCluster: azureCluster,
 				Client:       fakeClient,
@@ -1520,9 +1819,6 @@ func TestControlPlaneRouteTable(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
 			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster:      cluster,
 				AzureCluster: azureCluster,
 				Client:       fakeClient,
@@ -1589,9 +1885,6 @@ func TestGetPrivateDNSZoneName(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
 			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster:      cluster,
 				AzureCluster: azureCluster,
 				Client:       fakeClient,
@@ -1654,9 +1947,6 @@ func TestAPIServerLBPoolName(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
 			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster:      cluster,
 				AzureCluster: azureCluster,
 				Client:       fakeClient,
@@ -1795,9 +2085,6 @@ func TestOutboundLBName(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
 			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster:      cluster,
 				AzureCluster: azureCluster,
 				Client:       fakeClient,
@@ -1863,9 +2150,6 @@ func TestOutboundPoolName(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
 			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster:      cluster,
 				AzureCluster: azureCluster,
 				Client:       fakeClient,
@@ -1944,9 +2228,6 @@ func TestGenerateFQDN(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
 			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster:      cluster,
 				AzureCluster: azureCluster,
 				Client:       fakeClient,
@@ -1959,11 +2240,19 @@ func TestGenerateFQDN(t *testing.T) {
 	}
 }
 
+// fakeTaggingPolicy is a tagging policy setting the same tags on all the clusters.
+type fakeTaggingPolicy infrav1.Tags
+
+func (p fakeTaggingPolicy) Tags(_ string) infrav1.Tags {
+	return infrav1.Tags(p).DeepCopy()
+}
+
 func TestAdditionalTags(t *testing.T) {
 	tests := []struct {
 		name                       string
 		clusterName                string
 		azureClusterAdditionalTags infrav1.Tags
+		taggingPolicy              azure.TaggingPolicy
 		expectTags                 infrav1.Tags
 	}{
 		{
@@ -1996,10 +2285,27 @@ func TestAdditionalTags(t *testing.T) {
 				"fake-id-3": "fake-value-3",
 			},
 		},
+		{
+			name:        "Mandatory tags of the tagging policy take precedence",
+			clusterName: "my-cluster",
+			azureClusterAdditionalTags: infrav1.Tags{
+				"fake-id-1":  "fake-value-1",
+				"costCenter": "user-value",
+			},
+			taggingPolicy: fakeTaggingPolicy{
+				"costCenter": "1234",
+			},
+			expectTags: infrav1.Tags{
+				"fake-id-1":  "fake-value-1",
+				"costCenter": "1234",
+			},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			g := NewWithT(t)
+			azure.SetTaggingPolicy(tc.taggingPolicy)
+			defer azure.SetTaggingPolicy(nil)
 			scheme := runtime.NewScheme()
 			_ = infrav1.AddToScheme(scheme)
 			_ = clusterv1.AddToScheme(scheme)
@@ -2032,9 +2338,6 @@ func TestAdditionalTags(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
 			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster:      cluster,
 				AzureCluster: azureCluster,
 				Client:       fakeClient,
@@ -2069,7 +2372,7 @@ func TestAPIServerPort(t *testing.T) {
 			name:        "Non nil cluster network and non nil apiserverport",
 			clusterName: "my-cluster",
 			clusterNetowrk: &clusterv1.ClusterNetwork{
-				APIServerPort: to.Int32Ptr(7000),
+				APIServerPort: to.Ptr[int32](7000),
 			},
 			expectAPIServerPort: 7000,
 		},
@@ -2111,9 +2414,6 @@ func TestAPIServerPort(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
 			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster:      cluster,
 				AzureCluster: azureCluster,
 				Client:       fakeClient,
@@ -2125,6 +2425,112 @@ func TestAPIServerPort(t *testing.T) {
 	}
 }
 
+func TestAPIServerFrontendPort(t *testing.T) {
+	tests := []struct {
+		name               string
+		clusterNetwork     *clusterv1.ClusterNetwork
+		frontendPort       *int32
+		expectFrontendPort int32
+	}{
+		{
+			name:               "defaults to the API server port",
+			clusterNetwork:     &clusterv1.ClusterNetwork{APIServerPort: to.Ptr[int32](7000)},
+			expectFrontendPort: 7000,
+		},
+		{
+			name:               "frontend port",
+			clusterNetwork:     &clusterv1.ClusterNetwork{APIServerPort: to.Ptr[int32](7000)},
+			frontendPort:       to.Ptr[int32](443),
+			expectFrontendPort: 443,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterScope := &ClusterScope{
+				Cluster: &clusterv1.Cluster{
+					Spec: clusterv1.ClusterSpec{
+						ClusterNetwork: tc.clusterNetwork,
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						NetworkSpec: infrav1.NetworkSpec{
+							APIServerLB: infrav1.LoadBalancerSpec{
+								FrontendPort: tc.frontendPort,
+							},
+						},
+					},
+				},
+			}
+			g.Expect(clusterScope.APIServerFrontendPort()).To(Equal(tc.expectFrontendPort))
+		})
+	}
+}
+
+func TestAPIServerAliasFrontendSpecs(t *testing.T) {
+	g := NewWithT(t)
+
+	aliasFrontends := []infrav1.APIServerAliasFrontend{
+		{
+			Name: "corp",
+			Port: to.Ptr[int32](8443),
+		},
+		{
+			Name: "partner",
+			PublicIP: &infrav1.PublicIPSpec{
+				Name: "pip-my-cluster-apiserver-partner",
+			},
+		},
+	}
+	clusterScope := &ClusterScope{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-cluster",
+			},
+		},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				NetworkSpec: infrav1.NetworkSpec{
+					APIServerLB: infrav1.LoadBalancerSpec{
+						Name: "my-cluster-public-lb",
+						FrontendIPs: []infrav1.FrontendIP{
+							{
+								Name: "my-cluster-public-lb-frontEnd",
+								PublicIP: &infrav1.PublicIPSpec{
+									Name: "pip-my-cluster-apiserver",
+								},
+							},
+						},
+						FrontendPort:   to.Ptr[int32](443),
+						AliasFrontends: aliasFrontends,
+						LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+							Type: infrav1.Public,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	specs := clusterScope.LBSpecs()
+	g.Expect(specs).To(HaveLen(1))
+	lbSpec := specs[0].(*loadbalancers.LBSpec)
+	g.Expect(lbSpec.APIServerPort).To(Equal(int32(6443)))
+	g.Expect(lbSpec.FrontendPort).To(Equal(int32(443)))
+	g.Expect(lbSpec.AliasFrontends).To(Equal(aliasFrontends))
+	g.Expect(clusterScope.PublicIPSpecs()).To(Equal([]azure.PublicIPSpec{
+		{
+			Name: "pip-my-cluster-apiserver",
+		},
+		{
+			Name: "pip-my-cluster-apiserver-partner",
+		},
+	}))
+}
+
 func TestFailureDomains(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -2196,9 +2602,6 @@ func TestFailureDomains(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
 			clusterScope, err := NewClusterScope(context.TODO(), ClusterScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster:      cluster,
 				AzureCluster: azureCluster,
 				Client:       fakeClient,
@@ -2209,3 +2612,33 @@ func TestFailureDomains(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordIdentityUsage(t *testing.T) {
+	g := NewWithT(t)
+
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+	}
+	clusterScope := &ClusterScope{
+		AzureClients: AzureClients{ResourceManagerEndpoint: "https://management.azure.com/"},
+		AzureCluster: azureCluster,
+	}
+
+	// The credentials of the manager environment are recorded as an empty identity.
+	previous, recorded := clusterScope.RecordIdentityUsage()
+	g.Expect(recorded).To(BeFalse())
+	g.Expect(previous).To(BeEmpty())
+	g.Expect(azureCluster.Annotations).To(HaveKeyWithValue(infrav1.IdentityAnnotation, ""))
+	g.Expect(azureCluster.Annotations).To(HaveKeyWithValue(infrav1.TokenAudienceAnnotation, "https://management.azure.com/"))
+
+	// The identity ref defaults to the namespace of the AzureCluster.
+	azureCluster.Spec.IdentityRef = &corev1.ObjectReference{Name: "my-identity"}
+	previous, recorded = clusterScope.RecordIdentityUsage()
+	g.Expect(recorded).To(BeTrue())
+	g.Expect(previous).To(BeEmpty())
+	g.Expect(azureCluster.Annotations).To(HaveKeyWithValue(infrav1.IdentityAnnotation, "default/my-identity"))
+
+	previous, recorded = clusterScope.RecordIdentityUsage()
+	g.Expect(recorded).To(BeTrue())
+	g.Expect(previous).To(Equal("default/my-identity"))
+}
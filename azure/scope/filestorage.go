@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+const (
+	// fileStorageModulesPath loads the kernel modules of the file storage protocols at boot.
+	fileStorageModulesPath = "/etc/modules-load.d/capz-file-storage.conf"
+	// cifsOptionsPath holds the options of the cifs kernel module.
+	cifsOptionsPath = "/etc/modprobe.d/capz-cifs.conf"
+	// cifsOptions disables the SMB 1 dialect, which Azure Files doesn't support.
+	// See https://learn.microsoft.com/azure/storage/files/storage-how-to-use-files-linux.
+	cifsOptions = "options cifs disable_legacy_dialects=Y\n"
+)
+
+// ubuntuBlobfuseScript installs blobfuse2 from the Microsoft package repository, as it is not part of the Ubuntu ones.
+const ubuntuBlobfuseScript = `set -e
+. /etc/os-release
+curl -fsSL -o /tmp/packages-microsoft-prod.deb "https://packages.microsoft.com/config/ubuntu/${VERSION_ID}/packages-microsoft-prod.deb"
+dpkg -i /tmp/packages-microsoft-prod.deb
+rm -f /tmp/packages-microsoft-prod.deb
+apt-get update
+DEBIAN_FRONTEND=noninteractive apt-get install -y blobfuse2 fuse3
+`
+
+// fileStorageProtocol holds the prerequisites of a file storage protocol.
+type fileStorageProtocol struct {
+	// packages are the packages installed for the protocol, by OS distribution.
+	packages map[infrav1.OSDistro][]string
+	// module is the kernel module of the protocol.
+	module string
+}
+
+// fileStorageProtocols maps file storage protocols to their prerequisites.
+var fileStorageProtocols = map[infrav1.FileStorageProtocol]fileStorageProtocol{
+	infrav1.FileStorageSMB: {
+		packages: map[infrav1.OSDistro][]string{
+			infrav1.OSDistroUbuntu:     {"cifs-utils"},
+			infrav1.OSDistroAzureLinux: {"cifs-utils"},
+		},
+		module: "cifs",
+	},
+	infrav1.FileStorageNFS: {
+		packages: map[infrav1.OSDistro][]string{
+			infrav1.OSDistroUbuntu:     {"nfs-common"},
+			infrav1.OSDistroAzureLinux: {"nfs-utils"},
+		},
+		module: "nfs",
+	},
+	infrav1.FileStorageBlobfuse: {
+		packages: map[infrav1.OSDistro][]string{
+			// blobfuse2 is installed by ubuntuBlobfuseScript on Ubuntu.
+			infrav1.OSDistroAzureLinux: {"blobfuse2", "fuse3"},
+		},
+		module: "fuse",
+	},
+}
+
+// getFileStorageCloudConfig returns a cloud-config installing the packages and loading the kernel modules of the
+// given file storage protocols on a virtual machine running the given OS distribution.
+func getFileStorageCloudConfig(fileStorage *infrav1.FileStorage, distro infrav1.OSDistro) (*cloudConfig, error) {
+	if distro == "" {
+		distro = infrav1.OSDistroUbuntu
+	}
+
+	config := newCloudConfig()
+	modules := make([]string, 0, len(fileStorage.Protocols))
+	for _, protocol := range fileStorage.Protocols {
+		prerequisites, ok := fileStorageProtocols[protocol]
+		if !ok {
+			return nil, errors.Errorf("unsupported file storage protocol %q", protocol)
+		}
+		config.Packages = append(config.Packages, prerequisites.packages[distro]...)
+		modules = append(modules, prerequisites.module)
+
+		switch protocol {
+		case infrav1.FileStorageSMB:
+			config.WriteFiles = append(config.WriteFiles, cloudConfigFile{
+				Path:        cifsOptionsPath,
+				Content:     cifsOptions,
+				Owner:       "root:root",
+				Permissions: "0644",
+			})
+		case infrav1.FileStorageBlobfuse:
+			// Allow blobfuse mounts to be accessed by the users of the pods they are mounted in.
+			config.WriteFiles = append(config.WriteFiles, cloudConfigFile{
+				Path:    "/etc/fuse.conf",
+				Content: "user_allow_other\n",
+				Append:  true,
+			})
+			if distro == infrav1.OSDistroUbuntu {
+				config.RunCmd = append(config.RunCmd, []string{"sh", "-c", ubuntuBlobfuseScript})
+			}
+		}
+	}
+
+	config.WriteFiles = append(config.WriteFiles, cloudConfigFile{
+		Path:        fileStorageModulesPath,
+		Content:     strings.Join(modules, "\n") + "\n",
+		Owner:       "root:root",
+		Permissions: "0644",
+	})
+	config.RunCmd = append(config.RunCmd, append([]string{"modprobe", "-a"}, modules...))
+
+	return config, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestGetFileStorageCloudConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		fileStorage *infrav1.FileStorage
+		distro      infrav1.OSDistro
+		wantErr     bool
+		expect      func(g *WithT, config *cloudConfig)
+	}{
+		{
+			name:        "installs SMB and NFS prerequisites on Ubuntu by default",
+			fileStorage: &infrav1.FileStorage{Protocols: []infrav1.FileStorageProtocol{infrav1.FileStorageSMB, infrav1.FileStorageNFS}},
+			expect: func(g *WithT, config *cloudConfig) {
+				g.Expect(config.Packages).To(Equal([]string{"cifs-utils", "nfs-common"}))
+				g.Expect(config.WriteFiles).To(ContainElement(cloudConfigFile{
+					Path:        cifsOptionsPath,
+					Content:     cifsOptions,
+					Owner:       "root:root",
+					Permissions: "0644",
+				}))
+				g.Expect(config.WriteFiles).To(ContainElement(cloudConfigFile{
+					Path:        fileStorageModulesPath,
+					Content:     "cifs\nnfs\n",
+					Owner:       "root:root",
+					Permissions: "0644",
+				}))
+				g.Expect(config.RunCmd).To(Equal([][]string{{"modprobe", "-a", "cifs", "nfs"}}))
+			},
+		},
+		{
+			name:        "installs blobfuse from the Microsoft repository on Ubuntu",
+			fileStorage: &infrav1.FileStorage{Protocols: []infrav1.FileStorageProtocol{infrav1.FileStorageBlobfuse}},
+			distro:      infrav1.OSDistroUbuntu,
+			expect: func(g *WithT, config *cloudConfig) {
+				g.Expect(config.Packages).To(BeEmpty())
+				g.Expect(config.RunCmd).To(Equal([][]string{
+					{"sh", "-c", ubuntuBlobfuseScript},
+					{"modprobe", "-a", "fuse"},
+				}))
+			},
+		},
+		{
+			name:        "installs blobfuse and NFS packages on Azure Linux",
+			fileStorage: &infrav1.FileStorage{Protocols: []infrav1.FileStorageProtocol{infrav1.FileStorageBlobfuse, infrav1.FileStorageNFS}},
+			distro:      infrav1.OSDistroAzureLinux,
+			expect: func(g *WithT, config *cloudConfig) {
+				g.Expect(config.Packages).To(Equal([]string{"blobfuse2", "fuse3", "nfs-utils"}))
+				g.Expect(config.RunCmd).To(Equal([][]string{{"modprobe", "-a", "fuse", "nfs"}}))
+			},
+		},
+		{
+			name:        "fails on an unknown protocol",
+			fileStorage: &infrav1.FileStorage{Protocols: []infrav1.FileStorageProtocol{"AFP"}},
+			wantErr:     true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			config, err := getFileStorageCloudConfig(tc.fileStorage, tc.distro)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, config)
+		})
+	}
+}
@@ -23,8 +23,9 @@ import (
 
 	aadpodid "github.com/Azure/aad-pod-identity/pkg/apis/aadpodidentity"
 	aadpodv1 "github.com/Azure/aad-pod-identity/pkg/apis/aadpodidentity/v1"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -43,7 +44,7 @@ const azureSecretKey = "clientSecret"
 
 // CredentialsProvider defines the behavior for azure identity based credential providers.
 type CredentialsProvider interface {
-	GetAuthorizer(ctx context.Context, resourceManagerEndpoint, activeDirectoryEndpoint string) (autorest.Authorizer, error)
+	GetTokenCredential(ctx context.Context, resourceManagerEndpoint, activeDirectoryEndpoint string) (azcore.TokenCredential, error)
 	GetClientID() string
 	GetClientSecret(ctx context.Context) (string, error)
 	GetTenantID() string
@@ -97,9 +98,9 @@ func NewAzureClusterCredentialsProvider(ctx context.Context, kubeClient client.C
 	}, nil
 }
 
-// GetAuthorizer returns an Azure authorizer based on the provided azure identity. It delegates to AzureCredentialsProvider with AzureCluster metadata.
-func (p *AzureClusterCredentialsProvider) GetAuthorizer(ctx context.Context, resourceManagerEndpoint, activeDirectoryEndpoint string) (autorest.Authorizer, error) {
-	return p.AzureCredentialsProvider.GetAuthorizer(ctx, resourceManagerEndpoint, activeDirectoryEndpoint, p.AzureCluster.ObjectMeta)
+// GetTokenCredential returns an Azure token credential based on the provided azure identity. It delegates to AzureCredentialsProvider with AzureCluster metadata.
+func (p *AzureClusterCredentialsProvider) GetTokenCredential(ctx context.Context, resourceManagerEndpoint, activeDirectoryEndpoint string) (azcore.TokenCredential, error) {
+	return p.AzureCredentialsProvider.GetTokenCredential(ctx, resourceManagerEndpoint, activeDirectoryEndpoint, p.AzureCluster.ObjectMeta)
 }
 
 // NewManagedControlPlaneCredentialsProvider creates a new ManagedControlPlaneCredentialsProvider from the supplied inputs.
@@ -129,51 +130,51 @@ func NewManagedControlPlaneCredentialsProvider(ctx context.Context, kubeClient c
 	}, nil
 }
 
-// GetAuthorizer returns an Azure authorizer based on the provided azure identity. It delegates to AzureCredentialsProvider with AzureManagedControlPlane metadata.
-func (p *ManagedControlPlaneCredentialsProvider) GetAuthorizer(ctx context.Context, resourceManagerEndpoint, activeDirectoryEndpoint string) (autorest.Authorizer, error) {
-	return p.AzureCredentialsProvider.GetAuthorizer(ctx, resourceManagerEndpoint, activeDirectoryEndpoint, p.AzureManagedControlPlane.ObjectMeta)
+// GetTokenCredential returns an Azure token credential based on the provided azure identity. It delegates to AzureCredentialsProvider with AzureManagedControlPlane metadata.
+func (p *ManagedControlPlaneCredentialsProvider) GetTokenCredential(ctx context.Context, resourceManagerEndpoint, activeDirectoryEndpoint string) (azcore.TokenCredential, error) {
+	return p.AzureCredentialsProvider.GetTokenCredential(ctx, resourceManagerEndpoint, activeDirectoryEndpoint, p.AzureManagedControlPlane.ObjectMeta)
 }
 
-// GetAuthorizer returns an Azure authorizer based on the provided azure identity and cluster metadata.
-func (p *AzureCredentialsProvider) GetAuthorizer(ctx context.Context, resourceManagerEndpoint, activeDirectoryEndpoint string, clusterMeta metav1.ObjectMeta) (autorest.Authorizer, error) {
-	var spt *adal.ServicePrincipalToken
+// GetTokenCredential returns an Azure token credential based on the provided azure identity and cluster metadata.
+func (p *AzureCredentialsProvider) GetTokenCredential(ctx context.Context, resourceManagerEndpoint, activeDirectoryEndpoint string, clusterMeta metav1.ObjectMeta) (azcore.TokenCredential, error) {
+	clientOptions := azcore.ClientOptions{
+		Cloud: cloud.Configuration{
+			ActiveDirectoryAuthorityHost: activeDirectoryEndpoint,
+		},
+	}
+
 	switch p.Identity.Spec.Type {
 	case infrav1.ServicePrincipal, infrav1.ServicePrincipalCertificate, infrav1.UserAssignedMSI:
 		if err := createAzureIdentityWithBindings(ctx, p.Identity, resourceManagerEndpoint, activeDirectoryEndpoint, clusterMeta, p.Client); err != nil {
 			return nil, err
 		}
 
-		msiEndpoint, err := adal.GetMSIVMEndpoint()
-		if err != nil {
-			return nil, errors.Errorf("failed to get MSI endpoint: %v", err)
-		}
-
-		spt, err = adal.NewServicePrincipalTokenFromMSIWithUserAssignedID(msiEndpoint, resourceManagerEndpoint, p.Identity.Spec.ClientID)
+		cred, err := azidentity.NewManagedIdentityCredential(&azidentity.ManagedIdentityCredentialOptions{
+			ClientOptions: clientOptions,
+			ID:            azidentity.ClientID(p.Identity.Spec.ClientID),
+		})
 		if err != nil {
-			return nil, errors.Errorf("failed to get token from service principal identity: %v", err)
+			return nil, errors.Errorf("failed to get token credential from service principal identity: %v", err)
 		}
+		return cred, nil
 
 	case infrav1.ManualServicePrincipal:
-		oauthConfig, err := adal.NewOAuthConfig(activeDirectoryEndpoint, p.GetTenantID())
-		if err != nil {
-			return nil, err
-		}
-
 		clientSecret, err := p.GetClientSecret(ctx)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get client secret")
 		}
 
-		spt, err = adal.NewServicePrincipalToken(*oauthConfig, p.Identity.Spec.ClientID, clientSecret, resourceManagerEndpoint)
+		cred, err := azidentity.NewClientSecretCredential(p.GetTenantID(), p.Identity.Spec.ClientID, clientSecret, &azidentity.ClientSecretCredentialOptions{
+			ClientOptions: clientOptions,
+		})
 		if err != nil {
-			return nil, errors.Errorf("failed to get token from service principal identity: %v", err)
+			return nil, errors.Errorf("failed to get token credential from service principal identity: %v", err)
 		}
+		return cred, nil
 
 	default:
 		return nil, errors.Errorf("identity type %s not supported", p.Identity.Spec.Type)
 	}
-
-	return autorest.NewBearerAuthorizer(spt), nil
 }
 
 // GetClientID returns the Client ID associated with the AzureCredentialsProvider's Identity.
@@ -296,6 +297,49 @@ func getAzureIdentityType(identity *infrav1.AzureClusterIdentity) (aadpodv1.Iden
 	return -1, errors.New("AzureIdentity does not have a valid type")
 }
 
+// IsClusterNamespaceAllowedByIdentity indicates if the cluster namespace is allowed, and not denied, by an identity.
+func IsClusterNamespaceAllowedByIdentity(ctx context.Context, k8sClient client.Client, identity *infrav1.AzureClusterIdentity, namespace string) bool {
+	return IsClusterNamespaceAllowed(ctx, k8sClient, identity.Spec.AllowedNamespaces, namespace) &&
+		!IsClusterNamespaceDenied(ctx, k8sClient, identity.Spec.DeniedNamespaces, namespace)
+}
+
+// IsClusterNamespaceDenied indicates if the cluster namespace is denied. The namespace is considered denied if its
+// labels can't be checked against the selector.
+func IsClusterNamespaceDenied(ctx context.Context, k8sClient client.Client, deniedNamespaces *infrav1.DeniedNamespaces, namespace string) bool {
+	if deniedNamespaces == nil {
+		return false
+	}
+
+	for _, v := range deniedNamespaces.NamespaceList {
+		if v == namespace {
+			return true
+		}
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deniedNamespaces.Selector)
+	if err != nil {
+		return true
+	}
+
+	// A nil or empty selector denies nothing.
+	if selector.Empty() {
+		return false
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := k8sClient.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return true
+	}
+
+	for _, n := range namespaces.Items {
+		if n.Name == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
 // IsClusterNamespaceAllowed indicates if the cluster namespace is allowed.
 func IsClusterNamespaceAllowed(ctx context.Context, k8sClient client.Client, allowedNamespaces *infrav1.AllowedNamespaces, namespace string) bool {
 	if allowedNamespaces == nil {
@@ -135,6 +135,132 @@ func TestAllowedNamespaces(t *testing.T) {
 	}
 }
 
+func TestDeniedNamespaces(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	tests := []struct {
+		name             string
+		identity         *infrav1.AzureClusterIdentity
+		clusterNamespace string
+		expected         bool
+	}{
+		{
+			name: "allow cluster without denied namespaces",
+			identity: &infrav1.AzureClusterIdentity{
+				Spec: infrav1.AzureClusterIdentitySpec{
+					AllowedNamespaces: &infrav1.AllowedNamespaces{},
+				},
+			},
+			clusterNamespace: "namespace8",
+			expected:         true,
+		},
+		{
+			name: "don't allow cluster with namespace in denied list",
+			identity: &infrav1.AzureClusterIdentity{
+				Spec: infrav1.AzureClusterIdentitySpec{
+					AllowedNamespaces: &infrav1.AllowedNamespaces{},
+					DeniedNamespaces: &infrav1.DeniedNamespaces{
+						NamespaceList: []string{"namespace8"},
+					},
+				},
+			},
+			clusterNamespace: "namespace8",
+			expected:         false,
+		},
+		{
+			name: "denied list takes precedence over allowed list",
+			identity: &infrav1.AzureClusterIdentity{
+				Spec: infrav1.AzureClusterIdentitySpec{
+					AllowedNamespaces: &infrav1.AllowedNamespaces{
+						NamespaceList: []string{"namespace8"},
+					},
+					DeniedNamespaces: &infrav1.DeniedNamespaces{
+						NamespaceList: []string{"namespace8"},
+					},
+				},
+			},
+			clusterNamespace: "namespace8",
+			expected:         false,
+		},
+		{
+			name: "don't allow cluster when namespace matches denied selector",
+			identity: &infrav1.AzureClusterIdentity{
+				Spec: infrav1.AzureClusterIdentitySpec{
+					AllowedNamespaces: &infrav1.AllowedNamespaces{},
+					DeniedNamespaces: &infrav1.DeniedNamespaces{
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"c": "d"},
+						},
+					},
+				},
+			},
+			clusterNamespace: "namespace8",
+			expected:         false,
+		},
+		{
+			name: "allow cluster when namespace doesn't match denied selector",
+			identity: &infrav1.AzureClusterIdentity{
+				Spec: infrav1.AzureClusterIdentitySpec{
+					AllowedNamespaces: &infrav1.AllowedNamespaces{},
+					DeniedNamespaces: &infrav1.DeniedNamespaces{
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"x": "y"},
+						},
+					},
+				},
+			},
+			clusterNamespace: "namespace8",
+			expected:         true,
+		},
+		{
+			name: "empty denied selector denies nothing",
+			identity: &infrav1.AzureClusterIdentity{
+				Spec: infrav1.AzureClusterIdentitySpec{
+					AllowedNamespaces: &infrav1.AllowedNamespaces{},
+					DeniedNamespaces: &infrav1.DeniedNamespaces{
+						Selector: &metav1.LabelSelector{},
+					},
+				},
+			},
+			clusterNamespace: "namespace8",
+			expected:         true,
+		},
+		{
+			name: "don't allow cluster when denied selector is invalid",
+			identity: &infrav1.AzureClusterIdentity{
+				Spec: infrav1.AzureClusterIdentitySpec{
+					AllowedNamespaces: &infrav1.AllowedNamespaces{},
+					DeniedNamespaces: &infrav1.DeniedNamespaces{
+						Selector: &metav1.LabelSelector{
+							MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "c", Operator: "Unknown"}},
+						},
+					},
+				},
+			},
+			clusterNamespace: "namespace8",
+			expected:         false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeNamespace := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "namespace8",
+					Labels: map[string]string{"c": "d"},
+				},
+			}
+			initObjects := []runtime.Object{tc.identity, fakeNamespace}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+			actual := IsClusterNamespaceAllowedByIdentity(context.TODO(), fakeClient, tc.identity, tc.clusterNamespace)
+			g.Expect(actual).To(Equal(tc.expected))
+		})
+	}
+}
+
 func TestCreateAzureIdentityWithBindings(t *testing.T) {
 	g := NewWithT(t)
 	scheme := runtime.NewScheme()
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+const (
+	// tempDiskDevice is the udev link of the partition of the temporary disk of an Azure virtual machine.
+	tempDiskDevice = "/dev/disk/azure/resource-part1"
+	// nvmeModel is the model of the local NVMe disks of Azure virtual machines.
+	nvmeModel = "NVMe Direct Disk"
+	// localStorageRAIDDevice is the RAID 0 array striping several local NVMe disks.
+	localStorageRAIDDevice = "/dev/md/capz-local"
+)
+
+// localStorageUnit is the name of the systemd unit formatting and mounting the local disks on every boot.
+const localStorageUnit = "capz-local-storage"
+
+// tempDiskScript unmounts the temporary disk mounted by the Azure Linux agent or cloud-init and sets the device to format,
+// once the udev link of the disk shows up.
+const tempDiskScript = `device=` + tempDiskDevice + `
+for i in $(seq 1 60); do
+  [ -e "$device" ] && break
+  sleep 1
+done
+umount "$device" 2>/dev/null || true
+sed -i '\|^/dev/disk/cloud/azure_resource-part1|d' /etc/fstab
+`
+
+// nvmeScript finds the local NVMe disks and stripes them into a RAID 0 array when there are several. The array is
+// assembled again after a reboot, and only created when its disks are blank, e.g. after the VM was deallocated.
+const nvmeScript = `disks=""
+for model in /sys/block/nvme*n1/device/model; do
+  grep -q "` + nvmeModel + `" "$model" 2>/dev/null && disks="$disks /dev/$(basename "$(dirname "$(dirname "$model")")")"
+done
+set -- $disks
+if [ "$#" -eq 0 ]; then
+  echo "no local NVMe disk found" >&2
+  exit 1
+elif [ "$#" -eq 1 ]; then
+  device=$1
+else
+  device=` + localStorageRAIDDevice + `
+  if [ ! -e "$device" ] && ! mdadm --assemble "$device" "$@" 2>/dev/null; then
+    mdadm --create "$device" --level=0 --raid-devices="$#" "$@" --run --force
+  fi
+fi
+`
+
+// mountScript formats the device with the given filesystem unless it already holds one, e.g. after a reboot, and
+// mounts it on the given path. The mount isn't added to /etc/fstab, as the filesystem is recreated with a new UUID
+// when the VM comes back with blank local disks.
+const mountScript = `if [ "$(blkid -s TYPE -o value "$device")" != "%[1]s" ]; then
+  mkfs.%[1]s %[2]s "$device"
+fi
+mkdir -p %[3]s
+mountpoint -q %[3]s || mount -t %[1]s "$device" %[3]s
+`
+
+// getLocalStorageCloudConfig returns a cloud-config installing a systemd unit which formats and mounts the local disks
+// of a virtual machine on every boot, as they may be blank after the VM was deallocated or moved to another host.
+func getLocalStorageCloudConfig(localStorage *infrav1.LocalStorage) (*cloudConfig, error) {
+	config := newCloudConfig()
+
+	script := "set -e\n"
+	switch localStorage.Source {
+	case infrav1.LocalStorageTempDisk:
+		// Keep cloud-init from mounting the temporary disk on /mnt.
+		config.Mounts = [][]*string{{to.Ptr("ephemeral0"), nil}}
+		script += tempDiskScript
+	case infrav1.LocalStorageNVMe:
+		script += nvmeScript
+	default:
+		return nil, errors.Errorf("unsupported local storage source %q", localStorage.Source)
+	}
+
+	filesystem := localStorage.Filesystem
+	if filesystem == "" {
+		filesystem = "ext4"
+	}
+	force := "-F"
+	if filesystem == "xfs" {
+		force = "-f"
+	}
+	script += fmt.Sprintf(mountScript, filesystem, force, localStorage.MountPath)
+	config.addBootUnit(localStorageUnit, "Format and mount the local storage on "+localStorage.MountPath, script)
+
+	return config, nil
+}
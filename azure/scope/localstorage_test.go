@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestGetLocalStorageCloudConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		localStorage *infrav1.LocalStorage
+		wantErr      bool
+		expect       func(g *WithT, data string)
+	}{
+		{
+			name: "formats the temp disk with ext4 by default",
+			localStorage: &infrav1.LocalStorage{
+				Source:    infrav1.LocalStorageTempDisk,
+				MountPath: "/var/lib/containerd",
+			},
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(HavePrefix("#cloud-config\n"))
+				g.Expect(data).To(ContainSubstring(`"mounts":[["ephemeral0",null]]`))
+				g.Expect(data).To(ContainSubstring(`"merge_how":[{"name":"list","settings":["prepend"]}`))
+				g.Expect(data).To(ContainSubstring("device=/dev/disk/azure/resource-part1"))
+				g.Expect(data).To(ContainSubstring(`mkfs.ext4 -F \"$device\"`))
+				g.Expect(data).To(ContainSubstring("mountpoint -q /var/lib/containerd || mount -t ext4 \\\"$device\\\" /var/lib/containerd"))
+			},
+		},
+		{
+			name: "formats and mounts the local disks on every boot",
+			localStorage: &infrav1.LocalStorage{
+				Source:    infrav1.LocalStorageTempDisk,
+				MountPath: "/var/lib/containerd",
+			},
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(ContainSubstring(`"path":"/usr/local/sbin/capz-local-storage","content":"#!/bin/sh\nset -e\n`))
+				g.Expect(data).To(ContainSubstring(`"path":"/etc/systemd/system/capz-local-storage.service"`))
+				g.Expect(data).To(ContainSubstring(`Before=containerd.service kubelet.service`))
+				g.Expect(data).To(ContainSubstring(`"runcmd":[["systemctl","daemon-reload"],["systemctl","enable","--now","capz-local-storage.service"]]`))
+				// the filesystem is kept on reboots, and not added to /etc/fstab as it's recreated on blank disks
+				g.Expect(data).To(ContainSubstring(`if [ \"$(blkid -s TYPE -o value \"$device\")\" != \"ext4\" ]; then`))
+				g.Expect(data).NotTo(ContainSubstring(">> /etc/fstab"))
+			},
+		},
+		{
+			name: "stripes NVMe disks and formats them with xfs",
+			localStorage: &infrav1.LocalStorage{
+				Source:     infrav1.LocalStorageNVMe,
+				Filesystem: "xfs",
+				MountPath:  "/var/lib/etcd",
+			},
+			expect: func(g *WithT, data string) {
+				g.Expect(data).NotTo(ContainSubstring("mounts"))
+				g.Expect(data).To(ContainSubstring(`mdadm --assemble \"$device\" \"$@\"`))
+				g.Expect(data).To(ContainSubstring(`mdadm --create \"$device\" --level=0`))
+				g.Expect(data).To(ContainSubstring("device=/dev/md/capz-local"))
+				g.Expect(data).To(ContainSubstring(`mkfs.xfs -f \"$device\"`))
+				g.Expect(data).To(ContainSubstring("mount -t xfs"))
+			},
+		},
+		{
+			name: "fails on an unknown source",
+			localStorage: &infrav1.LocalStorage{
+				Source:    "Floppy",
+				MountPath: "/mnt/floppy",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			config, err := getLocalStorageCloudConfig(tc.localStorage)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			data, err := config.render()
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, data)
+		})
+	}
+}
@@ -20,27 +20,38 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"hash/fnv"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/availabilitysets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/backendaddresses"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/backendpooldrain"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/backupprotection"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inboundnatrules"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/jitnetworkaccesspolicies"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/snapshots"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
+	"sigs.k8s.io/cluster-api-provider-azure/util/resources"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
@@ -97,13 +108,17 @@ type MachineScope struct {
 	Machine      *clusterv1.Machine
 	AzureMachine *infrav1.AzureMachine
 	cache        *MachineCache
+
+	clusterPowerState infrav1.PowerState
 }
 
 // MachineCache stores common machine information so we don't have to hit the API multiple times within the same reconcile loop.
 type MachineCache struct {
 	BootstrapData      string
+	UserData           string
 	VMImage            *infrav1.Image
 	VMSKU              resourceskus.SKU
+	SSHPublicKey       string
 	availabilitySetSKU resourceskus.SKU
 }
 
@@ -121,7 +136,7 @@ func (m *MachineScope) InitMachineCache(ctx context.Context) error {
 			return err
 		}
 
-		m.cache.VMImage, err = m.GetVMImage(ctx)
+		m.cache.UserData, err = m.GetUserData(ctx)
 		if err != nil {
 			return err
 		}
@@ -136,9 +151,20 @@ func (m *MachineScope) InitMachineCache(ctx context.Context) error {
 			return errors.Wrapf(err, "failed to get VM SKU %s in compute api", m.AzureMachine.Spec.VMSize)
 		}
 
-		m.cache.availabilitySetSKU, err = skuCache.Get(ctx, string(compute.AvailabilitySetSkuTypesAligned), resourceskus.AvailabilitySets)
+		// The VM SKU is resolved first as it determines the Hyper-V generation of the default image.
+		m.cache.VMImage, err = m.GetVMImage(ctx)
+		if err != nil {
+			return err
+		}
+
+		m.cache.SSHPublicKey, err = m.GetSSHPublicKey(ctx)
+		if err != nil {
+			return err
+		}
+
+		m.cache.availabilitySetSKU, err = skuCache.Get(ctx, string(armcompute.AvailabilitySetSKUTypesAligned), resourceskus.AvailabilitySets)
 		if err != nil {
-			return errors.Wrapf(err, "failed to get availability set SKU %s in compute api", string(compute.AvailabilitySetSkuTypesAligned))
+			return errors.Wrapf(err, "failed to get availability set SKU %s in compute api", string(armcompute.AvailabilitySetSKUTypesAligned))
 		}
 	}
 
@@ -150,13 +176,16 @@ func (m *MachineScope) VMSpec() azure.ResourceSpecGetter {
 	spec := &virtualmachines.VMSpec{
 		Name:                   m.Name(),
 		Location:               m.Location(),
+		ExtendedLocation:       m.ExtendedLocation(),
 		ResourceGroup:          m.ResourceGroup(),
 		ClusterName:            m.ClusterName(),
 		Role:                   m.Role(),
 		NICIDs:                 m.NICIDs(),
 		SSHKeyData:             m.AzureMachine.Spec.SSHPublicKey,
+		AdditionalSSHKeyData:   m.AzureMachine.Spec.AdditionalSSHPublicKeys,
 		Size:                   m.AzureMachine.Spec.VMSize,
 		OSDisk:                 m.AzureMachine.Spec.OSDisk,
+		OSDiskName:             m.OSDiskName(),
 		DataDisks:              m.AzureMachine.Spec.DataDisks,
 		AvailabilitySetID:      m.AvailabilitySetID(),
 		Zone:                   m.AvailabilityZone(),
@@ -166,42 +195,235 @@ func (m *MachineScope) VMSpec() azure.ResourceSpecGetter {
 		SecurityProfile:        m.AzureMachine.Spec.SecurityProfile,
 		AdditionalTags:         m.AdditionalTags(),
 		AdditionalCapabilities: m.AzureMachine.Spec.AdditionalCapabilities,
+		DiskControllerType:     m.AzureMachine.Spec.DiskControllerType,
 		ProviderID:             m.ProviderID(),
 	}
+	if m.AzureMachine.Spec.OSDisk.SourceSnapshotID != "" {
+		spec.OSDiskID = azure.DiskID(m.SubscriptionID(), m.ResourceGroup(), m.OSDiskName())
+	}
 	if m.cache != nil {
 		spec.SKU = m.cache.VMSKU
 		spec.Image = m.cache.VMImage
 		spec.BootstrapData = m.cache.BootstrapData
+		spec.UserData = m.cache.UserData
+		spec.SSHKeyData = m.cache.SSHPublicKey
 	}
 	return spec
 }
 
-// TagsSpecs returns the tags for the AzureMachine.
+// BootstrapDataSpec returns the specification for delivering the bootstrap data of the machine through a storage blob,
+// or nil if the cluster doesn't configure a bootstrap data storage.
+func (m *MachineScope) BootstrapDataSpec() *azure.BootstrapDataSpec {
+	storage := m.ClusterScoper.BootstrapDataStorage()
+	if storage == nil {
+		return nil
+	}
+
+	spec := &azure.BootstrapDataSpec{
+		BlobName:           fmt.Sprintf("%s/%s", m.ClusterName(), m.Name()),
+		StorageAccountName: storage.StorageAccountName,
+		ResourceGroup:      storage.ResourceGroup,
+		ContainerName:      storage.ContainerName,
+		SASDuration:        azure.DefaultBootstrapDataSASDuration,
+		OSType:             m.AzureMachine.Spec.OSDisk.OSType,
+	}
+	if spec.ResourceGroup == "" {
+		spec.ResourceGroup = m.ResourceGroup()
+	}
+	if spec.ContainerName == "" {
+		spec.ContainerName = azure.DefaultBootstrapDataContainerName
+	}
+	if storage.SASDuration != nil {
+		spec.SASDuration = storage.SASDuration.Duration
+	}
+	if m.cache != nil {
+		spec.BootstrapData = m.cache.BootstrapData
+	}
+	return spec
+}
+
+// SetBootstrapData replaces the bootstrap data passed to the VM as custom data.
+func (m *MachineScope) SetBootstrapData(bootstrapData string) {
+	if m.cache != nil {
+		m.cache.BootstrapData = bootstrapData
+	}
+}
+
+// BootstrapDataRequired returns true until the VM is created from the bootstrap data and has run it. Without a
+// bootstrap extension reporting the bootstrap of the VM, it has run the bootstrap data once its node exists, or can't
+// run it anymore once the SAS URL to the bootstrap data expired.
+func (m *MachineScope) BootstrapDataRequired() bool {
+	if m.ProviderID() == "" || m.VMState() != infrav1.Succeeded || conditions.IsFalse(m.AzureMachine, infrav1.BootstrapSucceededCondition) {
+		return true
+	}
+	if conditions.Has(m.AzureMachine, infrav1.BootstrapSucceededCondition) {
+		return false
+	}
+	return m.Machine.Status.NodeRef == nil && time.Now().Before(m.BootstrapDataSASExpiry())
+}
+
+// BootstrapDataBlob returns the name of the blob the bootstrap data of the machine was uploaded to, or an empty string
+// if there is none.
+func (m *MachineScope) BootstrapDataBlob() string {
+	return m.AzureMachine.GetAnnotations()[azure.BootstrapDataBlobAnnotation]
+}
+
+// SetBootstrapDataBlob records the name of the blob the bootstrap data of the machine was uploaded to, or clears it
+// once the blob is deleted.
+func (m *MachineScope) SetBootstrapDataBlob(blobName string) {
+	if blobName == "" {
+		delete(m.AzureMachine.Annotations, azure.BootstrapDataBlobAnnotation)
+		delete(m.AzureMachine.Annotations, azure.BootstrapDataHashAnnotation)
+		return
+	}
+	m.SetAnnotation(azure.BootstrapDataBlobAnnotation, blobName)
+}
+
+// BootstrapDataHash returns the hash of the bootstrap data uploaded to the blob of the machine, or an empty string if
+// there is none.
+func (m *MachineScope) BootstrapDataHash() string {
+	return m.AzureMachine.GetAnnotations()[azure.BootstrapDataHashAnnotation]
+}
+
+// SetBootstrapDataHash records the hash of the bootstrap data uploaded to the blob of the machine.
+func (m *MachineScope) SetBootstrapDataHash(hash string) {
+	m.SetAnnotation(azure.BootstrapDataHashAnnotation, hash)
+}
+
+// BootstrapDataSASExpiry returns the expiry time of the SAS URL the custom data of the machine includes, or the zero
+// time if there is none.
+func (m *MachineScope) BootstrapDataSASExpiry() time.Time {
+	expiry, _ := time.Parse(time.RFC3339, m.AzureMachine.GetAnnotations()[azure.BootstrapDataSASExpiryAnnotation])
+	return expiry
+}
+
+// SetBootstrapDataSASExpiry records the expiry time of the SAS URL the custom data of the machine includes.
+func (m *MachineScope) SetBootstrapDataSASExpiry(expiry time.Time) {
+	m.SetAnnotation(azure.BootstrapDataSASExpiryAnnotation, expiry.UTC().Format(time.RFC3339))
+}
+
+// TagsSpecs returns the tags for the AzureMachine, and for the network interfaces and OS disk of the AzureMachine
+// when their tags are overridden.
 func (m *MachineScope) TagsSpecs() []azure.TagsSpec {
-	return []azure.TagsSpec{
+	specs := []azure.TagsSpec{
 		{
 			Scope:      azure.VMID(m.SubscriptionID(), m.ResourceGroup(), m.Name()),
 			Tags:       m.AdditionalTags(),
 			Annotation: azure.VMTagsLastAppliedAnnotation,
 		},
 	}
+
+	derived := m.derivedResources()
+	if derived.NetworkInterface != nil {
+		for i, nicSpec := range m.NICSpecs() {
+			specs = append(specs, azure.TagsSpec{
+				Scope:      azure.NetworkInterfaceID(m.SubscriptionID(), nicSpec.ResourceGroupName(), nicSpec.ResourceName()),
+				Tags:       m.derivedResourceTags(derived.NetworkInterface),
+				Annotation: fmt.Sprintf("%s-%d", azure.NICTagsLastAppliedAnnotation, i),
+				Owned:      true,
+			})
+		}
+	}
+	// An ephemeral OS disk isn't a resource of its own.
+	if derived.OSDisk != nil && m.AzureMachine.Spec.OSDisk.DiffDiskSettings == nil {
+		specs = append(specs, azure.TagsSpec{
+			Scope:      azure.DiskID(m.SubscriptionID(), m.ResourceGroup(), m.OSDiskName()),
+			Tags:       m.derivedResourceTags(derived.OSDisk),
+			Annotation: azure.OSDiskTagsLastAppliedAnnotation,
+			Owned:      true,
+		})
+	}
+	return specs
 }
 
 // PublicIPSpecs returns the public IP specs.
 func (m *MachineScope) PublicIPSpecs() []azure.PublicIPSpec {
 	var spec []azure.PublicIPSpec
 	if m.AzureMachine.Spec.AllocatePublicIP {
-		spec = append(spec, azure.PublicIPSpec{
-			Name: azure.GenerateNodePublicIPName(m.Name()),
-		})
+		ipSpec := azure.PublicIPSpec{
+			Name:         m.PublicIPName(),
+			DeleteWithVM: true,
+		}
+		if override := m.derivedResources().PublicIP; override != nil {
+			ipSpec.AdditionalTags = override.AdditionalTags
+		}
+		spec = append(spec, ipSpec)
 	}
 	return spec
 }
 
+// NICName returns the name of the network interface of the machine, to which the index of the network interface is
+// appended when the machine has several networkInterfaces.
+func (m *MachineScope) NICName() string {
+	return m.derivedResourceName(m.derivedResources().NetworkInterface, azure.GenerateNICName(m.Name()))
+}
+
+// OSDiskName returns the name of the OS disk of the machine.
+func (m *MachineScope) OSDiskName() string {
+	return m.derivedResourceName(m.derivedResources().OSDisk, azure.GenerateOSDiskName(m.Name()))
+}
+
+// PublicIPName returns the name of the public IP of the machine.
+func (m *MachineScope) PublicIPName() string {
+	return m.derivedResourceName(m.derivedResources().PublicIP, azure.GenerateNodePublicIPName(m.Name()))
+}
+
+// AzureMachineResourceNames returns the names of the VM of an AzureMachine and of the resources created along with it:
+// its network interfaces, OS disk, public IP and data disks.
+func AzureMachineResourceNames(azureMachine *infrav1.AzureMachine) []string {
+	m := &MachineScope{AzureMachine: azureMachine}
+	names := []string{m.Name(), m.OSDiskName()}
+	if m.AzureMachine.Spec.AllocatePublicIP {
+		names = append(names, m.PublicIPName())
+	}
+	if len(m.AzureMachine.Spec.NetworkInterfaces) < 1 {
+		names = append(names, m.NICName())
+	}
+	for i, n := range m.AzureMachine.Spec.NetworkInterfaces {
+		if n.ID == "" {
+			names = append(names, m.NICName()+"-"+strconv.Itoa(i))
+		}
+	}
+	for _, dd := range m.AzureMachine.Spec.DataDisks {
+		if dd.ExistingDiskID == "" {
+			names = append(names, azure.GenerateDataDiskName(m.Name(), dd.NameSuffix))
+		}
+	}
+	return names
+}
+
+// derivedResources returns the overrides of the names and tags of the resources created along with the VM.
+func (m *MachineScope) derivedResources() infrav1.DerivedResources {
+	if m.AzureMachine.Spec.DerivedResources == nil {
+		return infrav1.DerivedResources{}
+	}
+	return *m.AzureMachine.Spec.DerivedResources
+}
+
+// derivedResourceName returns the name of a resource created along with the VM: the name of the machine between the
+// prefix and suffix of its override, or its default name if the override has none.
+func (m *MachineScope) derivedResourceName(override *infrav1.DerivedResource, defaultName string) string {
+	if override == nil || (override.NamePrefix == "" && override.NameSuffix == "") {
+		return defaultName
+	}
+	return override.NamePrefix + m.Name() + override.NameSuffix
+}
+
+// derivedResourceTags returns the additional tags of a resource created along with the VM: the additional tags of the
+// machine, merged with the additional tags of its override.
+func (m *MachineScope) derivedResourceTags(override *infrav1.DerivedResource) infrav1.Tags {
+	tags := m.AdditionalTags()
+	if override != nil {
+		tags.Merge(override.AdditionalTags)
+	}
+	return tags
+}
+
 // InboundNatSpecs returns the inbound NAT specs.
 func (m *MachineScope) InboundNatSpecs(portsInUse map[int32]struct{}) []azure.ResourceSpecGetter {
 	// The existing inbound NAT rules are needed in order to find an available SSH port for each new inbound NAT rule.
-	if m.Role() == infrav1.ControlPlane {
+	// The API server load balancer has no name when its inbound NAT rules are not managed.
+	if m.Role() == infrav1.ControlPlane && m.APIServerLBName() != "" {
 		spec := &inboundnatrules.InboundNatSpec{
 			Name:                      m.Name(),
 			ResourceGroup:             m.ResourceGroup(),
@@ -212,7 +434,7 @@ func (m *MachineScope) InboundNatSpecs(portsInUse map[int32]struct{}) []azure.Re
 		if frontEndIPs := m.APIServerLB().FrontendIPs; len(frontEndIPs) > 0 {
 			ipConfig := frontEndIPs[0].Name
 			id := azure.FrontendIPConfigID(m.SubscriptionID(), m.ResourceGroup(), m.APIServerLBName(), ipConfig)
-			spec.FrontendIPConfigurationID = to.StringPtr(id)
+			spec.FrontendIPConfigurationID = to.Ptr(id)
 		}
 
 		return []azure.ResourceSpecGetter{spec}
@@ -220,6 +442,98 @@ func (m *MachineScope) InboundNatSpecs(portsInUse map[int32]struct{}) []azure.Re
 	return []azure.ResourceSpecGetter{}
 }
 
+// BackendAddressSpecs returns the spec of the address of the machine in the backend pool of the API server load
+// balancer, or nil if the machine doesn't join the pool by IP address.
+func (m *MachineScope) BackendAddressSpecs() []azure.ResourceSpecGetter {
+	lb := m.APIServerLB()
+	if m.Role() != infrav1.ControlPlane || lb.Name == "" || !lb.IsIPBackendPool() {
+		return nil
+	}
+
+	spec := &backendaddresses.BackendAddressSpec{
+		Name:             m.Name(),
+		PoolName:         m.APIServerLBPoolName(lb.Name),
+		LoadBalancerName: lb.Name,
+		ResourceGroup:    m.ResourceGroup(),
+		VNetID:           azure.VNetID(m.SubscriptionID(), m.Vnet().ResourceGroup, m.Vnet().Name),
+	}
+	if lb.External != nil {
+		_, spec.ResourceGroup = parseExternalLB(lb.External)
+		spec.PoolName = lb.External.BackendPoolName
+	}
+	// The private IP address of the machine is known once its virtual machine is created.
+	for _, address := range m.AzureMachine.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			spec.IPAddress = address.Address
+			break
+		}
+	}
+
+	return []azure.ResourceSpecGetter{spec}
+}
+
+// BackendPoolDrainSpecs returns the specs to remove the network interfaces of the machine from the load balancer
+// backend pools before it is deleted.
+func (m *MachineScope) BackendPoolDrainSpecs() []azure.ResourceSpecGetter {
+	specs := []azure.ResourceSpecGetter{}
+	for _, nicSpec := range m.NICSpecs() {
+		specs = append(specs, &backendpooldrain.BackendPoolDrainSpec{
+			Name:          nicSpec.ResourceName(),
+			ResourceGroup: nicSpec.ResourceGroupName(),
+		})
+	}
+	return specs
+}
+
+// BackendPoolDrainTimeout returns how long the machine is kept out of the load balancer backend pools before it is
+// deleted.
+func (m *MachineScope) BackendPoolDrainTimeout() time.Duration {
+	if m.AzureMachine.Spec.BackendPoolDrainTimeout == nil {
+		return 0
+	}
+	return m.AzureMachine.Spec.BackendPoolDrainTimeout.Duration
+}
+
+// BackendPoolDrainStartTime returns when the machine was removed from the load balancer backend pools, recording the
+// current time the first time it is called.
+func (m *MachineScope) BackendPoolDrainStartTime() time.Time {
+	if conditions.Get(m.AzureMachine, infrav1.BackendPoolsDrainedCondition) == nil {
+		conditions.MarkFalse(m.AzureMachine, infrav1.BackendPoolsDrainedCondition, infrav1.DrainingReason, clusterv1.ConditionSeverityInfo, "")
+	}
+	return conditions.GetLastTransitionTime(m.AzureMachine, infrav1.BackendPoolsDrainedCondition).Time
+}
+
+// BackendPoolsDrained returns true if the connections through the load balancer backend pools were drained.
+func (m *MachineScope) BackendPoolsDrained() bool {
+	return conditions.IsTrue(m.AzureMachine, infrav1.BackendPoolsDrainedCondition)
+}
+
+// SetBackendPoolsDrained marks the connections through the load balancer backend pools as drained.
+func (m *MachineScope) SetBackendPoolsDrained() {
+	conditions.MarkTrue(m.AzureMachine, infrav1.BackendPoolsDrainedCondition)
+}
+
+// TombstonePolicy returns the two-phase deletion policy of the AzureMachine VM.
+func (m *MachineScope) TombstonePolicy() *infrav1.TombstonePolicy {
+	return m.AzureMachine.Spec.TombstonePolicy
+}
+
+// TombstoneExpired returns true if the grace period of the tombstoned AzureMachine VM has passed.
+func (m *MachineScope) TombstoneExpired() bool {
+	return conditions.IsTrue(m.AzureMachine, infrav1.TombstoneExpiredCondition)
+}
+
+// SetTombstoned records that the AzureMachine VM is tombstoned until the given time.
+func (m *MachineScope) SetTombstoned(deleteAfter time.Time) {
+	conditions.MarkFalse(m.AzureMachine, infrav1.TombstoneExpiredCondition, infrav1.TombstonedReason, clusterv1.ConditionSeverityInfo,
+		"virtual machine is deleted after %s", deleteAfter.UTC().Format(time.RFC3339))
+}
+
+// SetTombstoneExpired records that the grace period of the tombstoned AzureMachine VM has passed.
+func (m *MachineScope) SetTombstoneExpired() {
+	conditions.MarkTrue(m.AzureMachine, infrav1.TombstoneExpiredCondition)
+}
+
 // NICSpecs returns the network interface specs.
 func (m *MachineScope) NICSpecs() []azure.ResourceSpecGetter {
 	nicSpecs := []azure.ResourceSpecGetter{}
@@ -239,18 +553,26 @@ func (m *MachineScope) NICSpecs() []azure.ResourceSpecGetter {
 			spec = &networkinterfaces.NICSpec{
 				ResourceGroup:      m.ResourceGroup(),
 				Location:           m.Location(),
+				ExtendedLocation:   m.ExtendedLocation(),
 				SubscriptionID:     m.SubscriptionID(),
 				MachineName:        m.Name(),
 				VNetName:           m.Vnet().Name,
 				VNetResourceGroup:  m.Vnet().ResourceGroup,
 				IPv6Enabled:        m.IsIPv6Enabled(),
 				EnableIPForwarding: m.AzureMachine.Spec.EnableIPForwarding,
+				ClusterName:        m.ClusterName(),
+				AdditionalTags:     m.derivedResourceTags(m.derivedResources().NetworkInterface),
 			}
 		}
-		spec.Name = azure.GenerateNICName(m.Name()) + "-" + strconv.Itoa(i)
+		spec.Name = m.NICName() + "-" + strconv.Itoa(i)
 		spec.SubnetName = n.SubnetName
 		spec.IPConfigs = []networkinterfaces.IPConfig{}
 		spec.AcceleratedNetworking = n.AcceleratedNetworking
+		if n.EnableIPForwarding != nil {
+			spec.EnableIPForwarding = *n.EnableIPForwarding
+		}
+		spec.InternalDNSNameLabel = n.InternalDNSNameLabel
+		spec.DNSServers = n.DNSServers
 
 		if m.cache != nil {
 			spec.SKU = &m.cache.VMSKU
@@ -290,9 +612,10 @@ func (m *MachineScope) NICIDs() []string {
 // DefaultNICSpec constructs a NICSpec for the default interface on a given MachineScope.
 func (m *MachineScope) DefaultNICSpec() *networkinterfaces.NICSpec {
 	spec := &networkinterfaces.NICSpec{
-		Name:                  azure.GenerateNICName(m.Name()),
+		Name:                  m.NICName(),
 		ResourceGroup:         m.ResourceGroup(),
 		Location:              m.Location(),
+		ExtendedLocation:      m.ExtendedLocation(),
 		SubscriptionID:        m.SubscriptionID(),
 		MachineName:           m.Name(),
 		VNetName:              m.Vnet().Name,
@@ -301,14 +624,26 @@ func (m *MachineScope) DefaultNICSpec() *networkinterfaces.NICSpec {
 		IPv6Enabled:           m.IsIPv6Enabled(),
 		EnableIPForwarding:    m.AzureMachine.Spec.EnableIPForwarding,
 		SubnetName:            m.Subnet().Name,
+		ClusterName:           m.ClusterName(),
+		AdditionalTags:        m.derivedResourceTags(m.derivedResources().NetworkInterface),
 	}
 	if m.Role() == infrav1.ControlPlane {
 		spec.PublicLBName = m.OutboundLBName(m.Role())
 		spec.PublicLBAddressPoolName = m.OutboundPoolName(m.OutboundLBName(m.Role()))
-		if m.IsAPIServerPrivate() {
-			spec.InternalLBName = m.APIServerLBName()
-			spec.InternalLBAddressPoolName = m.APIServerLBPoolName(m.APIServerLBName())
-		} else {
+		switch {
+		case m.APIServerLB().External != nil:
+			// Machines only join the backend pool of external load balancers, whose inbound NAT rules are not managed.
+			if !m.APIServerLB().IsIPBackendPool() {
+				subscriptionID, resourceGroup := parseExternalLB(m.APIServerLB().External)
+				spec.ExternalLBAddressPoolID = azure.AddressPoolID(subscriptionID, resourceGroup, m.APIServerLB().Name, m.APIServerLB().External.BackendPoolName)
+			}
+		case m.IsAPIServerPrivate():
+			// Machines join IP backend pools by their private IP address, see BackendAddressSpecs.
+			if !m.APIServerLB().IsIPBackendPool() {
+				spec.InternalLBName = m.APIServerLBName()
+				spec.InternalLBAddressPoolName = m.APIServerLBPoolName(m.APIServerLBName())
+			}
+		default:
 			spec.PublicLBNATRuleName = m.Name()
 			spec.PublicLBAddressPoolName = m.APIServerLBPoolName(m.APIServerLBName())
 		}
@@ -325,28 +660,279 @@ func (m *MachineScope) DefaultNICSpec() *networkinterfaces.NICSpec {
 	}
 
 	if m.Role() == infrav1.Node && m.AzureMachine.Spec.AllocatePublicIP {
-		spec.PublicIPName = azure.GenerateNodePublicIPName(m.Name())
+		spec.PublicIPName = m.PublicIPName()
 	}
 	return spec
 }
 
-// DiskSpecs returns the disk specs.
+// DiskSpecs returns the specs of the disks deleted with the machine.
 func (m *MachineScope) DiskSpecs() []azure.ResourceSpecGetter {
-	diskSpecs := make([]azure.ResourceSpecGetter, 1+len(m.AzureMachine.Spec.DataDisks))
+	diskSpecs := make([]azure.ResourceSpecGetter, 1, 1+len(m.AzureMachine.Spec.DataDisks))
 	diskSpecs[0] = &disks.DiskSpec{
-		Name:          azure.GenerateOSDiskName(m.Name()),
+		Name:          m.OSDiskName(),
 		ResourceGroup: m.ResourceGroup(),
 	}
 
-	for i, dd := range m.AzureMachine.Spec.DataDisks {
-		diskSpecs[i+1] = &disks.DiskSpec{
+	for _, dd := range m.AzureMachine.Spec.DataDisks {
+		// Detached disks are retained when the VM is deleted.
+		if dd.GetDeletePolicy() == infrav1.DiskDeletePolicyDetach {
+			continue
+		}
+		diskSpec := &disks.DiskSpec{
 			Name:          azure.GenerateDataDiskName(m.Name(), dd.NameSuffix),
 			ResourceGroup: m.ResourceGroup(),
 		}
+		if dd.ExistingDiskID != "" {
+			// The ID of existing disks is validated by the webhook.
+			if resource, err := azure.ParseResourceID(dd.ExistingDiskID); err == nil {
+				diskSpec.Name = resource.Name
+				diskSpec.ResourceGroup = resource.ResourceGroupName
+			}
+		}
+		diskSpecs = append(diskSpecs, diskSpec)
 	}
 	return diskSpecs
 }
 
+// DiskCreateSpecs returns the specs of the disks created before the VM, i.e. its OS disk when it is created from a
+// snapshot.
+func (m *MachineScope) DiskCreateSpecs() []azure.ResourceSpecGetter {
+	osDisk := m.AzureMachine.Spec.OSDisk
+	if osDisk.SourceSnapshotID == "" {
+		return nil
+	}
+
+	spec := &disks.DiskSpec{
+		Name:             m.OSDiskName(),
+		ResourceGroup:    m.ResourceGroup(),
+		Location:         m.Location(),
+		ExtendedLocation: m.ExtendedLocation(),
+		Zone:             m.AvailabilityZone(),
+		ClusterName:      m.ClusterName(),
+		OSType:           osDisk.OSType,
+		SourceSnapshotID: osDisk.SourceSnapshotID,
+		DiskSizeGB:       osDisk.DiskSizeGB,
+		AdditionalTags:   m.derivedResourceTags(m.derivedResources().OSDisk),
+	}
+	if osDisk.ManagedDisk != nil {
+		spec.StorageAccountType = osDisk.ManagedDisk.StorageAccountType
+		if osDisk.ManagedDisk.DiskEncryptionSet != nil {
+			spec.DiskEncryptionSetID = osDisk.ManagedDisk.DiskEncryptionSet.ID
+		}
+	}
+	return []azure.ResourceSpecGetter{spec}
+}
+
+// snapshotSourceDisk is a disk of the VM whose snapshots are taken.
+type snapshotSourceDisk struct {
+	name          string
+	resourceGroup string
+}
+
+// snapshotSourceDisks returns the disks of the VM whose snapshots are taken: its OS disk, unless it is ephemeral, and
+// its data disks when configured.
+func (m *MachineScope) snapshotSourceDisks() []snapshotSourceDisk {
+	var sources []snapshotSourceDisk
+	if m.AzureMachine.Spec.OSDisk.DiffDiskSettings == nil {
+		sources = append(sources, snapshotSourceDisk{name: m.OSDiskName(), resourceGroup: m.ResourceGroup()})
+	}
+
+	if m.AzureMachine.Spec.DiskSnapshots == nil || !m.AzureMachine.Spec.DiskSnapshots.IncludeDataDisks {
+		return sources
+	}
+	for _, dd := range m.AzureMachine.Spec.DataDisks {
+		source := snapshotSourceDisk{name: azure.GenerateDataDiskName(m.Name(), dd.NameSuffix), resourceGroup: m.ResourceGroup()}
+		if dd.ExistingDiskID != "" {
+			// The ID of existing disks is validated by the webhook.
+			if resource, err := azure.ParseResourceID(dd.ExistingDiskID); err == nil {
+				source = snapshotSourceDisk{name: resource.Name, resourceGroup: resource.ResourceGroupName}
+			}
+		}
+		sources = append(sources, source)
+	}
+	return sources
+}
+
+// NextDiskSnapshotTime returns the time the next scheduled snapshots of the disks are due, or nil if snapshots are not
+// scheduled.
+func (m *MachineScope) NextDiskSnapshotTime() *time.Time {
+	snapshots := m.AzureMachine.Spec.DiskSnapshots
+	if snapshots == nil || snapshots.Interval == nil {
+		return nil
+	}
+
+	last := m.AzureMachine.CreationTimestamp.Time
+	if m.AzureMachine.Status.LastDiskSnapshotTime != nil {
+		last = m.AzureMachine.Status.LastDiskSnapshotTime.Time
+	}
+	next := last.Add(snapshots.Interval.Duration)
+	return &next
+}
+
+// diskSnapshotSuffix returns the suffix of the names of the snapshots which are due, or an empty string if no snapshots
+// are due. It doesn't change until the snapshots are taken, so that they are created only once.
+func (m *MachineScope) diskSnapshotSuffix() string {
+	request := m.AzureMachine.Annotations[infrav1.DiskSnapshotRequestAnnotation]
+	if request != "" && request != m.AzureMachine.Status.LastDiskSnapshotRequest {
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(request))
+		if m.AzureMachine.Status.LastDiskSnapshotTime != nil {
+			_, _ = hash.Write([]byte(m.AzureMachine.Status.LastDiskSnapshotTime.UTC().String()))
+		}
+		return fmt.Sprintf("request-%08x", hash.Sum32())
+	}
+
+	if next := m.NextDiskSnapshotTime(); next != nil && !next.After(time.Now()) {
+		return next.UTC().Format("20060102150405")
+	}
+	return ""
+}
+
+// SnapshotSpecs returns the specs of the snapshots of the disks which are due.
+func (m *MachineScope) SnapshotSpecs() []azure.ResourceSpecGetter {
+	suffix := m.diskSnapshotSuffix()
+	if suffix == "" {
+		return nil
+	}
+
+	var specs []azure.ResourceSpecGetter
+	for _, source := range m.snapshotSourceDisks() {
+		specs = append(specs, &snapshots.SnapshotSpec{
+			Name:           azure.GenerateSnapshotName(source.name, suffix),
+			ResourceGroup:  m.ResourceGroup(),
+			Location:       m.Location(),
+			ClusterName:    m.ClusterName(),
+			SourceDiskName: source.name,
+			SourceDiskID:   azure.DiskID(m.SubscriptionID(), source.resourceGroup, source.name),
+			AdditionalTags: m.AdditionalTags(),
+		})
+	}
+	return specs
+}
+
+// SnapshotSourceDiskNames returns the names of the disks whose snapshots are retained, or nil if no snapshots were
+// taken or scheduled.
+func (m *MachineScope) SnapshotSourceDiskNames() []string {
+	if m.AzureMachine.Spec.DiskSnapshots == nil && m.AzureMachine.Status.LastDiskSnapshotTime == nil {
+		return nil
+	}
+
+	var names []string
+	for _, source := range m.snapshotSourceDisks() {
+		names = append(names, source.name)
+	}
+	return names
+}
+
+// SnapshotRetain returns the number of snapshots kept for each disk.
+func (m *MachineScope) SnapshotRetain() int {
+	return int(m.AzureMachine.Spec.DiskSnapshots.GetRetain())
+}
+
+// BackupProtectionSpecs returns the spec of the backup protection of the virtual machine, or nil if it isn't backed up.
+func (m *MachineScope) BackupProtectionSpecs() []azure.ResourceSpecGetter {
+	protection := m.AzureMachine.Spec.BackupProtection
+	if protection == nil {
+		return nil
+	}
+	vault, err := azure.ParseResourceID(protection.VaultID)
+	if err != nil {
+		// the vault ID is validated by the webhook
+		return nil
+	}
+
+	return []azure.ResourceSpecGetter{
+		&backupprotection.BackupProtectionSpec{
+			VMName:             m.Name(),
+			VMResourceGroup:    m.ResourceGroup(),
+			VMID:               azure.VMID(m.SubscriptionID(), m.ResourceGroup(), m.Name()),
+			VaultName:          vault.Name,
+			VaultResourceGroup: vault.ResourceGroupName,
+			PolicyID:           fmt.Sprintf("%s/backupPolicies/%s", protection.VaultID, protection.GetPolicyName()),
+		},
+	}
+}
+
+// JITNetworkAccessPolicySpecs returns the spec of the just-in-time network access policy of the virtual machine, or nil
+// if the cluster doesn't protect the management ports of its virtual machines.
+func (m *MachineScope) JITNetworkAccessPolicySpecs() []azure.ResourceSpecGetter {
+	securityProfile := m.SecurityProfile()
+	if securityProfile == nil || securityProfile.JustInTimeAccess == nil {
+		return nil
+	}
+
+	return []azure.ResourceSpecGetter{
+		&jitnetworkaccesspolicies.JITNetworkAccessPolicySpec{
+			Name:          m.Name(),
+			ResourceGroup: m.ResourceGroup(),
+			VMID:          azure.VMID(m.SubscriptionID(), m.ResourceGroup(), m.Name()),
+			Ports:         securityProfile.JustInTimeAccess.GetPorts(),
+		},
+	}
+}
+
+// DataCollectionRuleAssociationSpecs returns the specs associating the virtual machine with the data collection rule
+// and endpoint of the cluster, or nil if the cluster isn't onboarded to Azure Monitor.
+func (m *MachineScope) DataCollectionRuleAssociationSpecs() []azure.ResourceSpecGetter {
+	return getDataCollectionRuleAssociationSpecs(m, azure.VMID(m.SubscriptionID(), m.ResourceGroup(), m.Name()))
+}
+
+// SetEtcdDataDiskCondition warns when the etcd data disk of the machine can't meet the latency requirements of etcd,
+// either because the VM size limits its IOPS or because its storage type doesn't guarantee its latency.
+func (m *MachineScope) SetEtcdDataDiskCondition() {
+	etcdDisk := m.AzureMachine.Spec.EtcdDataDisk
+	if etcdDisk == nil {
+		return
+	}
+
+	storageType := etcdDisk.GetStorageAccountType()
+	diskIOPS := infrav1.DiskIOPS(storageType, etcdDisk.GetDiskSizeGB())
+	if m.cache != nil {
+		if value, ok := m.cache.VMSKU.GetCapability(resourceskus.UncachedDiskIOPS); ok {
+			if vmIOPS, err := strconv.ParseInt(value, 10, 32); err == nil && int32(vmIOPS) < diskIOPS {
+				conditions.MarkFalse(m.AzureMachine, infrav1.EtcdDataDiskPerformanceCondition, infrav1.EtcdDataDiskThrottledReason, clusterv1.ConditionSeverityWarning,
+					"VM size %s limits the uncached disks to %d IOPS, below the %d IOPS of the etcd data disk", m.AzureMachine.Spec.VMSize, vmIOPS, diskIOPS)
+				return
+			}
+		}
+	}
+	if strings.HasPrefix(storageType, "StandardSSD") {
+		conditions.MarkFalse(m.AzureMachine, infrav1.EtcdDataDiskPerformanceCondition, infrav1.EtcdDataDiskLatencyNotGuaranteedReason, clusterv1.ConditionSeverityWarning,
+			"%s disks don't guarantee the write latency etcd needs, use a Premium SSD disk", storageType)
+		return
+	}
+	conditions.MarkTrue(m.AzureMachine, infrav1.EtcdDataDiskPerformanceCondition)
+}
+
+// SetDiskSnapshotsTaken records that the snapshots of the disks which were due are taken.
+func (m *MachineScope) SetDiskSnapshotsTaken() {
+	now := metav1.Now()
+	m.AzureMachine.Status.LastDiskSnapshotTime = &now
+	m.AzureMachine.Status.LastDiskSnapshotRequest = m.AzureMachine.Annotations[infrav1.DiskSnapshotRequestAnnotation]
+}
+
+// ARMTemplateExportRequested returns true if the MachineARMTemplateExportRequestAnnotation requests an ARM template
+// which wasn't exported yet.
+func (m *MachineScope) ARMTemplateExportRequested() bool {
+	request := m.AzureMachine.Annotations[infrav1.MachineARMTemplateExportRequestAnnotation]
+	return request != "" && request != m.AzureMachine.Status.LastARMTemplateExportRequest
+}
+
+// SaveARMTemplate stores the ARM template exported for the AzureMachine and records that the request is handled.
+func (m *MachineScope) SaveARMTemplate(ctx context.Context, template *armtemplate.Template) error {
+	if err := saveARMTemplate(ctx, m.client, m.AzureMachine, template); err != nil {
+		return err
+	}
+	m.AzureMachine.Status.LastARMTemplateExportRequest = m.AzureMachine.Annotations[infrav1.MachineARMTemplateExportRequestAnnotation]
+	return nil
+}
+
+// CrossSubscriptionResources returns the gallery image and the disk encryption sets of the AzureMachine which are in
+// another subscription than the cluster.
+func (m *MachineScope) CrossSubscriptionResources() []azure.CrossSubscriptionResource {
+	return azure.CrossSubscriptionResources(m.SubscriptionID(), m.AzureMachine.Spec.Image, m.AzureMachine.Spec.OSDisk, m.AzureMachine.Spec.DataDisks)
+}
+
 // RoleAssignmentSpecs returns the role assignment specs.
 func (m *MachineScope) RoleAssignmentSpecs(principalID *string) []azure.ResourceSpecGetter {
 	roles := make([]azure.ResourceSpecGetter, 1)
@@ -360,7 +946,7 @@ func (m *MachineScope) RoleAssignmentSpecs(principalID *string) []azure.Resource
 			RoleDefinitionID: azure.GenerateContributorRoleDefinitionID(m.SubscriptionID()),
 			PrincipalID:      principalID,
 		}
-		return roles
+		return append(roles, getAcrPullRoleAssignmentSpecs(m.AttachedACRs(), principalID, azure.VirtualMachine, m.Name(), m.ResourceGroup())...)
 	}
 	return []azure.ResourceSpecGetter{}
 }
@@ -379,9 +965,13 @@ func (m *MachineScope) HasSystemAssignedIdentity() bool {
 // VMExtensionSpecs returns the VM extension specs.
 func (m *MachineScope) VMExtensionSpecs() []azure.ResourceSpecGetter {
 	var extensionSpecs = []azure.ResourceSpecGetter{}
-	bootstrapExtensionSpec := azure.GetBootstrappingVMExtension(m.AzureMachine.Spec.OSDisk.OSType, m.CloudEnvironment(), m.Name())
+	bootstrapExtensionSpec := getBootstrappingVMExtension(m.BootstrapExtension(), m.AzureMachine.Spec.OSDisk, m.CloudEnvironment(), m.Name())
 
 	if bootstrapExtensionSpec != nil {
+		if proxy := m.proxyConfig(); proxy != nil {
+			bootstrapExtensionSpec.ProtectedSettings["commandToExecute"] = azure.GetProxyExtensionCommand(m.AzureMachine.Spec.OSDisk.OSType,
+				bootstrapExtensionSpec.ProtectedSettings["commandToExecute"], getProxyEnvironment(proxy))
+		}
 		extensionSpecs = append(extensionSpecs, &vmextensions.VMExtensionSpec{
 			ExtensionSpec: *bootstrapExtensionSpec,
 			ResourceGroup: m.ResourceGroup(),
@@ -389,9 +979,30 @@ func (m *MachineScope) VMExtensionSpecs() []azure.ResourceSpecGetter {
 		})
 	}
 
+	if m.Monitoring() != nil {
+		extensionSpecs = append(extensionSpecs, &vmextensions.VMExtensionSpec{
+			ExtensionSpec: *azure.GetAzureMonitorAgentVMExtension(m.AzureMachine.Spec.OSDisk.OSType, m.Name()),
+			ResourceGroup: m.ResourceGroup(),
+			Location:      m.Location(),
+		})
+	}
+
+	if securityProfile := m.SecurityProfile(); securityProfile != nil && securityProfile.Defender != nil {
+		extensionSpecs = append(extensionSpecs, &vmextensions.VMExtensionSpec{
+			ExtensionSpec: *azure.GetAzureSecurityAgentVMExtension(m.AzureMachine.Spec.OSDisk.OSType, m.Name()),
+			ResourceGroup: m.ResourceGroup(),
+			Location:      m.Location(),
+		})
+	}
+
 	return extensionSpecs
 }
 
+// proxyConfig returns the proxy configuration of the machine, or nil if the cluster has no proxy.
+func (m *MachineScope) proxyConfig() *infrav1.ProxyConfig {
+	return getProxyConfig(m.ProxyConfig(), m.Vnet())
+}
+
 // Subnet returns the machine's subnet.
 func (m *MachineScope) Subnet() infrav1.SubnetSpec {
 	for _, subnet := range m.Subnets() {
@@ -461,7 +1072,7 @@ func (m *MachineScope) GetVMID() string {
 
 // ProviderID returns the AzureMachine providerID from the spec.
 func (m *MachineScope) ProviderID() string {
-	parsed, err := noderefutil.NewProviderID(to.String(m.AzureMachine.Spec.ProviderID))
+	parsed, err := noderefutil.NewProviderID(pointer.StringDeref(m.AzureMachine.Spec.ProviderID, ""))
 	if err != nil {
 		return ""
 	}
@@ -525,7 +1136,7 @@ func (m *MachineScope) AvailabilitySetID() string {
 
 // SetProviderID sets the AzureMachine providerID in spec.
 func (m *MachineScope) SetProviderID(v string) {
-	m.AzureMachine.Spec.ProviderID = to.StringPtr(v)
+	m.AzureMachine.Spec.ProviderID = to.Ptr(v)
 }
 
 // VMState returns the AzureMachine VM state.
@@ -541,6 +1152,54 @@ func (m *MachineScope) SetVMState(v infrav1.ProvisioningState) {
 	m.AzureMachine.Status.VMState = &v
 }
 
+// PowerState returns the AzureMachine VM power state.
+func (m *MachineScope) PowerState() infrav1.PowerState {
+	return m.AzureMachine.Status.PowerState
+}
+
+// SetPowerState sets the AzureMachine VM power state.
+func (m *MachineScope) SetPowerState(v infrav1.PowerState) {
+	m.AzureMachine.Status.PowerState = v
+}
+
+// SetPlacement sets where the AzureMachine VM was placed.
+func (m *MachineScope) SetPlacement(v *infrav1.VMPlacement) {
+	m.AzureMachine.Status.Placement = v
+}
+
+// DesiredPowerState returns the power state the AzureMachine VM should be kept in.
+// The power state required by the hibernation of the cluster takes precedence over the one of the AzureMachine.
+func (m *MachineScope) DesiredPowerState() infrav1.PowerState {
+	if m.clusterPowerState != "" {
+		return m.clusterPowerState
+	}
+	return m.AzureMachine.Spec.DesiredPowerState
+}
+
+// SetClusterPowerState sets the power state of the AzureMachine VM required by the hibernation of the cluster.
+func (m *MachineScope) SetClusterPowerState(v infrav1.PowerState) {
+	m.clusterPowerState = v
+}
+
+// IsHibernated returns true if the AzureMachine VM was deallocated by the hibernation of the cluster and was not
+// started again yet.
+func (m *MachineScope) IsHibernated() bool {
+	_, ok := m.AzureMachine.Annotations[infrav1.HibernatedAnnotation]
+	return ok
+}
+
+// SetHibernated marks the AzureMachine VM as deallocated by the hibernation of the cluster, or unmarks it.
+func (m *MachineScope) SetHibernated(hibernated bool) {
+	if !hibernated {
+		delete(m.AzureMachine.Annotations, infrav1.HibernatedAnnotation)
+		return
+	}
+	if m.AzureMachine.Annotations == nil {
+		m.AzureMachine.Annotations = map[string]string{}
+	}
+	m.AzureMachine.Annotations[infrav1.HibernatedAnnotation] = ""
+}
+
 // SetReady sets the AzureMachine Ready Status to true.
 func (m *MachineScope) SetReady() {
 	m.AzureMachine.Status.Ready = true
@@ -553,7 +1212,7 @@ func (m *MachineScope) SetNotReady() {
 
 // SetFailureMessage sets the AzureMachine status failure message.
 func (m *MachineScope) SetFailureMessage(v error) {
-	m.AzureMachine.Status.FailureMessage = to.StringPtr(v.Error())
+	m.AzureMachine.Status.FailureMessage = to.Ptr(v.Error())
 }
 
 // SetFailureReason sets the AzureMachine status failure reason.
@@ -645,13 +1304,15 @@ func (m *MachineScope) Close(ctx context.Context) error {
 }
 
 // AdditionalTags merges AdditionalTags from the scope's AzureCluster and AzureMachine. If the same key is present in both,
-// the value from AzureMachine takes precedence.
+// the value from AzureMachine takes precedence. The mandatory tags of the tagging policy take precedence over both.
 func (m *MachineScope) AdditionalTags() infrav1.Tags {
 	tags := make(infrav1.Tags)
 	// Start with the cluster-wide tags...
 	tags.Merge(m.ClusterScoper.AdditionalTags())
 	// ... and merge in the Machine's
 	tags.Merge(m.AzureMachine.Spec.AdditionalTags)
+	// ... the mandatory tags of the tagging policy take precedence
+	tags.Merge(azure.MandatoryTags(m.ClusterName()))
 	// Set the cloud provider tag
 	tags[infrav1.ClusterAzureCloudProviderTagKey(m.ClusterName())] = string(infrav1.ResourceLifecycleOwned)
 
@@ -676,9 +1337,52 @@ func (m *MachineScope) GetBootstrapData(ctx context.Context) (string, error) {
 	if !ok {
 		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
 	}
+
+	caCertificates, err := getCACertificates(ctx, m.client, m.Namespace(), m.AzureMachine.Spec.CACertificates)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get CA certificates for AzureMachine %s/%s", m.Namespace(), m.Name())
+	}
+
+	registryMirrors, err := getRegistryMirrors(ctx, m.client, m.Namespace(), m.RegistryMirrors())
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get registry mirrors for AzureMachine %s/%s", m.Namespace(), m.Name())
+	}
+
+	value, err = mergeBootstrapCloudConfigs(value, m.AzureMachine.Spec.OSDisk, m.AzureMachine.Spec.LocalStorage, m.AzureMachine.Spec.MultiInstanceGPU, m.AzureMachine.Spec.FileStorage, m.proxyConfig(), caCertificates, registryMirrors)
+	if err != nil {
+		return "", err
+	}
 	return base64.StdEncoding.EncodeToString(value), nil
 }
 
+// GetUserData returns the base64 encoded user data of the machine, or an empty string if it has none.
+func (m *MachineScope) GetUserData(ctx context.Context) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.GetUserData")
+	defer done()
+
+	userData, err := getUserData(ctx, m.client, m.Namespace(), m.AzureMachine.Spec.UserData)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get user data for AzureMachine %s/%s", m.Namespace(), m.Name())
+	}
+	return userData, nil
+}
+
+// GetSSHPublicKey returns the base64 encoded SSH public key of the machine, or the public key of the key pair generated
+// for the cluster if the machine has none.
+func (m *MachineScope) GetSSHPublicKey(ctx context.Context) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.GetSSHPublicKey")
+	defer done()
+
+	if m.AzureMachine.Spec.SSHPublicKey != "" {
+		return m.AzureMachine.Spec.SSHPublicKey, nil
+	}
+	sshPublicKey, err := getClusterSSHPublicKey(ctx, m.client, m.Namespace(), m.ClusterName())
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the cluster SSH public key for AzureMachine %s/%s", m.Namespace(), m.Name())
+	}
+	return sshPublicKey, nil
+}
+
 // GetVMImage returns the image from the machine configuration, or a default one.
 func (m *MachineScope) GetVMImage(ctx context.Context) (*infrav1.Image, error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.GetVMImage")
@@ -689,17 +1393,48 @@ func (m *MachineScope) GetVMImage(ctx context.Context) (*infrav1.Image, error) {
 		return m.AzureMachine.Spec.Image, nil
 	}
 
-	svc := virtualmachineimages.New(m)
+	// Reuse the default image resolved during a previous reconciliation.
+	if m.AzureMachine.Status.Image != nil {
+		return m.AzureMachine.Status.Image, nil
+	}
 
+	svc, err := virtualmachineimages.New(m)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create virtualmachineimages service")
+	}
+	svc.HyperVGeneration = m.hyperVGeneration()
+
+	var defaultImage *infrav1.Image
 	if m.AzureMachine.Spec.OSDisk.OSType == azure.WindowsOS {
 		runtime := m.AzureMachine.Annotations["runtime"]
 		windowsServerVersion := m.AzureMachine.Annotations["windowsServerVersion"]
 		log.Info("No image specified for machine, using default Windows Image", "machine", m.AzureMachine.GetName(), "runtime", runtime, "windowsServerVersion", windowsServerVersion)
-		return svc.GetDefaultWindowsImage(ctx, m.Location(), to.String(m.Machine.Spec.Version), runtime, windowsServerVersion)
+		defaultImage, err = svc.GetDefaultWindowsImage(ctx, m.Location(), pointer.StringDeref(m.Machine.Spec.Version, ""), runtime, windowsServerVersion)
+	} else if m.AzureMachine.Spec.OSDisk.Distro == infrav1.OSDistroAzureLinux {
+		log.Info("No image specified for machine, using default Azure Linux Image", "machine", m.AzureMachine.GetName())
+		defaultImage, err = svc.GetDefaultAzureLinuxImage(ctx, m.Location(), pointer.StringDeref(m.Machine.Spec.Version, ""))
+	} else {
+		log.Info("No image specified for machine, using default Linux Image", "machine", m.AzureMachine.GetName())
+		defaultImage, err = svc.GetDefaultUbuntuImage(ctx, m.Location(), pointer.StringDeref(m.Machine.Spec.Version, ""))
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	log.Info("No image specified for machine, using default Linux Image", "machine", m.AzureMachine.GetName())
-	return svc.GetDefaultUbuntuImage(ctx, m.Location(), to.String(m.Machine.Spec.Version))
+	m.AzureMachine.Status.Image = defaultImage
+	return defaultImage, nil
+}
+
+// hyperVGeneration returns the Hyper-V generation of the default image of the machine: V2 when its disks are attached
+// through an NVMe controller or its VM size can't boot generation 1 images, V1 otherwise.
+func (m *MachineScope) hyperVGeneration() armcompute.HyperVGenerationTypes {
+	if m.AzureMachine.Spec.DiskControllerType == infrav1.DiskControllerTypeNVMe {
+		return armcompute.HyperVGenerationTypesV2
+	}
+	if m.cache != nil && !m.cache.VMSKU.SupportsHyperVGeneration(resourceskus.HyperVGenerationV1) {
+		return armcompute.HyperVGenerationTypesV2
+	}
+	return armcompute.HyperVGenerationTypesV1
 }
 
 // SetSubnetName defaults the AzureMachine subnet name to the name of one the subnets with the machine role when there is only one of them.
@@ -742,13 +1477,23 @@ func (m *MachineScope) DeleteLongRunningOperationState(name, service string) {
 	futures.Delete(m.AzureMachine, name, service)
 }
 
+// SetResourceStatus will record the Azure resource with the given ID on the AzureMachine status.
+func (m *MachineScope) SetResourceStatus(id, resourceType, service string) {
+	resources.Set(m.AzureMachine, id, resourceType, service)
+}
+
+// DeleteResourceStatus will delete the Azure resource from the AzureMachine status.
+func (m *MachineScope) DeleteResourceStatus(name, service string) {
+	resources.Delete(m.AzureMachine, name, service)
+}
+
 // UpdateDeleteStatus updates a condition on the AzureMachine status after a DELETE operation.
 func (m *MachineScope) UpdateDeleteStatus(condition clusterv1.ConditionType, service string, err error) {
 	switch {
 	case err == nil:
 		conditions.MarkFalse(m.AzureMachine, condition, infrav1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s successfully deleted", service)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(m.AzureMachine, condition, infrav1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting", service)
+		conditions.MarkFalse(m.AzureMachine, condition, infrav1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(m.AzureMachine, condition, infrav1.DeletionFailedReason, clusterv1.ConditionSeverityError, "%s failed to delete. err: %s", service, err.Error())
 	}
@@ -760,7 +1505,7 @@ func (m *MachineScope) UpdatePutStatus(condition clusterv1.ConditionType, servic
 	case err == nil:
 		conditions.MarkTrue(m.AzureMachine, condition)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(m.AzureMachine, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating", service)
+		conditions.MarkFalse(m.AzureMachine, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(m.AzureMachine, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to create or update. err: %s", service, err.Error())
 	}
@@ -772,7 +1517,7 @@ func (m *MachineScope) UpdatePatchStatus(condition clusterv1.ConditionType, serv
 	case err == nil:
 		conditions.MarkTrue(m.AzureMachine, condition)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(m.AzureMachine, condition, infrav1.UpdatingReason, clusterv1.ConditionSeverityInfo, "%s updating", service)
+		conditions.MarkFalse(m.AzureMachine, condition, infrav1.UpdatingReason, clusterv1.ConditionSeverityInfo, "%s updating%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(m.AzureMachine, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to update. err: %s", service, err.Error())
 	}
@@ -20,26 +20,32 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
-	autorestazure "github.com/Azure/go-autorest/autorest/azure"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/backendaddresses"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/backupprotection"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inboundnatrules"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/jitnetworkaccesspolicies"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/snapshots"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages/mock_virtualmachineimages"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestMachineScope_Name(t *testing.T) {
@@ -57,7 +63,7 @@ func TestMachineScope_Name(t *testing.T) {
 						Name: "machine-with-a-long-name",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID: to.StringPtr("azure://compute/virtual-machines/machine-name"),
+						ProviderID: to.Ptr("azure://compute/virtual-machines/machine-name"),
 						OSDisk: infrav1.OSDisk{
 							OSType: "Windows",
 						},
@@ -163,7 +169,7 @@ func TestMachineScope_GetVMID(t *testing.T) {
 						Name: "not-this-name",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID: to.StringPtr("azure://compute/virtual-machines/machine-name"),
+						ProviderID: to.Ptr("azure://compute/virtual-machines/machine-name"),
 					},
 				},
 			},
@@ -177,7 +183,7 @@ func TestMachineScope_GetVMID(t *testing.T) {
 						Name: "machine-name",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID: to.StringPtr("foo"),
+						ProviderID: to.Ptr("foo"),
 					},
 				},
 			},
@@ -208,7 +214,7 @@ func TestMachineScope_ProviderID(t *testing.T) {
 						Name: "not-this-name",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID: to.StringPtr("azure://compute/virtual-machines/machine-name"),
+						ProviderID: to.Ptr("azure://compute/virtual-machines/machine-name"),
 					},
 				},
 			},
@@ -222,7 +228,7 @@ func TestMachineScope_ProviderID(t *testing.T) {
 						Name: "machine-name",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID: to.StringPtr("foo"),
+						ProviderID: to.Ptr("foo"),
 					},
 				},
 			},
@@ -273,7 +279,34 @@ func TestMachineScope_PublicIPSpecs(t *testing.T) {
 			},
 			want: []azure.PublicIPSpec{
 				{
-					Name: "pip-machine-name",
+					Name:         "pip-machine-name",
+					DeleteWithVM: true,
+				},
+			},
+		},
+		{
+			name: "overrides the name and tags of the public IP",
+			machineScope: MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						AllocatePublicIP: true,
+						DerivedResources: &infrav1.DerivedResources{
+							PublicIP: &infrav1.DerivedResource{
+								NameSuffix:     "-pip",
+								AdditionalTags: infrav1.Tags{"costcenter": "network"},
+							},
+						},
+					},
+				},
+			},
+			want: []azure.PublicIPSpec{
+				{
+					Name:           "machine-name-pip",
+					AdditionalTags: infrav1.Tags{"costcenter": "network"},
+					DeleteWithVM:   true,
 				},
 			},
 		},
@@ -287,6 +320,155 @@ func TestMachineScope_PublicIPSpecs(t *testing.T) {
 	}
 }
 
+func TestMachineScope_DerivedResourceNames(t *testing.T) {
+	tests := []struct {
+		name             string
+		derived          *infrav1.DerivedResources
+		wantNICName      string
+		wantOSDiskName   string
+		wantPublicIPName string
+	}{
+		{
+			name:             "default names",
+			derived:          nil,
+			wantNICName:      "machine-name-nic",
+			wantOSDiskName:   "machine-name_OSDisk",
+			wantPublicIPName: "pip-machine-name",
+		},
+		{
+			name: "overrides without a prefix nor a suffix keep the default names",
+			derived: &infrav1.DerivedResources{
+				NetworkInterface: &infrav1.DerivedResource{AdditionalTags: infrav1.Tags{"costcenter": "network"}},
+				OSDisk:           &infrav1.DerivedResource{AdditionalTags: infrav1.Tags{"costcenter": "storage"}},
+				PublicIP:         &infrav1.DerivedResource{AdditionalTags: infrav1.Tags{"costcenter": "network"}},
+			},
+			wantNICName:      "machine-name-nic",
+			wantOSDiskName:   "machine-name_OSDisk",
+			wantPublicIPName: "pip-machine-name",
+		},
+		{
+			name: "overridden names",
+			derived: &infrav1.DerivedResources{
+				NetworkInterface: &infrav1.DerivedResource{NamePrefix: "nic-"},
+				OSDisk:           &infrav1.DerivedResource{NamePrefix: "disk-", NameSuffix: "-os"},
+				PublicIP:         &infrav1.DerivedResource{NameSuffix: "-pip"},
+			},
+			wantNICName:      "nic-machine-name",
+			wantOSDiskName:   "disk-machine-name-os",
+			wantPublicIPName: "machine-name-pip",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						DerivedResources: tt.derived,
+					},
+				},
+			}
+			g.Expect(machineScope.NICName()).To(Equal(tt.wantNICName))
+			g.Expect(machineScope.OSDiskName()).To(Equal(tt.wantOSDiskName))
+			g.Expect(machineScope.PublicIPName()).To(Equal(tt.wantPublicIPName))
+		})
+	}
+}
+
+func TestMachineScope_TagsSpecs(t *testing.T) {
+	tests := []struct {
+		name     string
+		derived  *infrav1.DerivedResources
+		osDisk   infrav1.OSDisk
+		expected []azure.TagsSpec
+	}{
+		{
+			name:    "only the VM without overrides",
+			derived: nil,
+			expected: []azure.TagsSpec{
+				{
+					Scope:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machine-name",
+					Tags:       infrav1.Tags{"kubernetes.io_cluster_my-cluster": "owned", "team": "compute"},
+					Annotation: azure.VMTagsLastAppliedAnnotation,
+				},
+			},
+		},
+		{
+			name: "the OS disk with its override",
+			derived: &infrav1.DerivedResources{
+				OSDisk: &infrav1.DerivedResource{NameSuffix: "-os", AdditionalTags: infrav1.Tags{"team": "storage"}},
+			},
+			expected: []azure.TagsSpec{
+				{
+					Scope:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machine-name",
+					Tags:       infrav1.Tags{"kubernetes.io_cluster_my-cluster": "owned", "team": "compute"},
+					Annotation: azure.VMTagsLastAppliedAnnotation,
+				},
+				{
+					Scope:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/machine-name-os",
+					Tags:       infrav1.Tags{"kubernetes.io_cluster_my-cluster": "owned", "team": "storage"},
+					Annotation: azure.OSDiskTagsLastAppliedAnnotation,
+					Owned:      true,
+				},
+			},
+		},
+		{
+			name: "no OS disk with an ephemeral OS disk",
+			derived: &infrav1.DerivedResources{
+				OSDisk: &infrav1.DerivedResource{AdditionalTags: infrav1.Tags{"team": "storage"}},
+			},
+			osDisk: infrav1.OSDisk{DiffDiskSettings: &infrav1.DiffDiskSettings{Option: "Local"}},
+			expected: []azure.TagsSpec{
+				{
+					Scope:      "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machine-name",
+					Tags:       infrav1.Tags{"kubernetes.io_cluster_my-cluster": "owned", "team": "compute"},
+					Annotation: azure.VMTagsLastAppliedAnnotation,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: EnvironmentSettings{
+							Values: map[string]string{
+								envSubscriptionID: "123",
+							},
+						},
+					},
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "my-cluster",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						AdditionalTags:   infrav1.Tags{"team": "compute"},
+						OSDisk:           tt.osDisk,
+						DerivedResources: tt.derived,
+					},
+				},
+			}
+			g.Expect(machineScope.TagsSpecs()).To(Equal(tt.expected))
+		})
+	}
+}
+
 func TestMachineScope_InboundNatSpecs(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -322,9 +504,9 @@ func TestMachineScope_InboundNatSpecs(t *testing.T) {
 				},
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
+						EnvironmentSettings: EnvironmentSettings{
 							Values: map[string]string{
-								auth.SubscriptionID: "123",
+								envSubscriptionID: "123",
 							},
 						},
 					},
@@ -353,7 +535,7 @@ func TestMachineScope_InboundNatSpecs(t *testing.T) {
 					Name:                      "machine-name",
 					LoadBalancerName:          "foo-loadbalancer",
 					ResourceGroup:             "my-rg",
-					FrontendIPConfigurationID: to.StringPtr(azure.FrontendIPConfigID("123", "my-rg", "foo-loadbalancer", "foo-frontend-ip")),
+					FrontendIPConfigurationID: to.Ptr(azure.FrontendIPConfigID("123", "my-rg", "foo-loadbalancer", "foo-frontend-ip")),
 					PortsInUse:                make(map[int32]struct{}),
 				},
 			},
@@ -403,9 +585,9 @@ func TestMachineScope_RoleAssignmentSpecs(t *testing.T) {
 				},
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
+						EnvironmentSettings: EnvironmentSettings{
 							Values: map[string]string{
-								auth.SubscriptionID: "123",
+								envSubscriptionID: "123",
 							},
 						},
 					},
@@ -427,20 +609,208 @@ func TestMachineScope_RoleAssignmentSpecs(t *testing.T) {
 					ResourceGroup:    "my-rg",
 					Scope:            azure.GenerateSubscriptionScope("123"),
 					RoleDefinitionID: azure.GenerateContributorRoleDefinitionID("123"),
-					PrincipalID:      to.StringPtr("fakePrincipalID"),
+					PrincipalID:      to.Ptr("fakePrincipalID"),
 				},
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.machineScope.RoleAssignmentSpecs(to.StringPtr("fakePrincipalID")); !reflect.DeepEqual(got, tt.want) {
+			if got := tt.machineScope.RoleAssignmentSpecs(to.Ptr("fakePrincipalID")); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("RoleAssignmentSpecs() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func TestMachineScope_BootstrapDataSpec(t *testing.T) {
+	newClusterScope := func(storage *infrav1.BootstrapDataStorage) *ClusterScope {
+		return &ClusterScope{
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-cluster",
+				},
+			},
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						BootstrapDataStorage: storage,
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		machineScope MachineScope
+		want         *azure.BootstrapDataSpec
+	}{
+		{
+			name: "returns nil if the cluster has no bootstrap data storage",
+			machineScope: MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+				},
+				ClusterScoper: newClusterScope(nil),
+			},
+			want: nil,
+		},
+		{
+			name: "returns defaulted spec with the cached bootstrap data",
+			machineScope: MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							OSType: azure.LinuxOS,
+						},
+					},
+				},
+				ClusterScoper: newClusterScope(&infrav1.BootstrapDataStorage{
+					StorageAccountName: "capzbootstrap",
+				}),
+				cache: &MachineCache{
+					BootstrapData: "ZmFrZS1ib290c3RyYXAtZGF0YQ==",
+				},
+			},
+			want: &azure.BootstrapDataSpec{
+				BlobName:           "my-cluster/machine-name",
+				StorageAccountName: "capzbootstrap",
+				ResourceGroup:      "my-rg",
+				ContainerName:      azure.DefaultBootstrapDataContainerName,
+				SASDuration:        azure.DefaultBootstrapDataSASDuration,
+				OSType:             azure.LinuxOS,
+				BootstrapData:      "ZmFrZS1ib290c3RyYXAtZGF0YQ==",
+			},
+		},
+		{
+			name: "returns spec with the configured storage",
+			machineScope: MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+				},
+				ClusterScoper: newClusterScope(&infrav1.BootstrapDataStorage{
+					StorageAccountName: "capzbootstrap",
+					ResourceGroup:      "storage-rg",
+					ContainerName:      "bootstrap",
+					SASDuration:        &metav1.Duration{Duration: 30 * time.Minute},
+				}),
+			},
+			want: &azure.BootstrapDataSpec{
+				BlobName:           "my-cluster/machine-name",
+				StorageAccountName: "capzbootstrap",
+				ResourceGroup:      "storage-rg",
+				ContainerName:      "bootstrap",
+				SASDuration:        30 * time.Minute,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.machineScope.BootstrapDataSpec(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BootstrapDataSpec() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMachineScope_BootstrapDataRequired(t *testing.T) {
+	providerID := "azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/machine-name"
+	bootstrapped := func(status corev1.ConditionStatus) clusterv1.Conditions {
+		return clusterv1.Conditions{{Type: infrav1.BootstrapSucceededCondition, Status: status}}
+	}
+	withoutExtension := func(sasExpiry time.Time) *infrav1.AzureMachine {
+		return &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{azure.BootstrapDataSASExpiryAnnotation: sasExpiry.UTC().Format(time.RFC3339)},
+			},
+			Spec:   infrav1.AzureMachineSpec{ProviderID: &providerID},
+			Status: infrav1.AzureMachineStatus{VMState: &[]infrav1.ProvisioningState{infrav1.Succeeded}[0]},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		azureMachine *infrav1.AzureMachine
+		machine      *clusterv1.Machine
+		want         bool
+	}{
+		{
+			name:         "required before the VM is created",
+			azureMachine: &infrav1.AzureMachine{},
+			want:         true,
+		},
+		{
+			name: "required while the VM is provisioning",
+			azureMachine: &infrav1.AzureMachine{
+				Spec:   infrav1.AzureMachineSpec{ProviderID: &providerID},
+				Status: infrav1.AzureMachineStatus{VMState: &[]infrav1.ProvisioningState{infrav1.Creating}[0]},
+			},
+			want: true,
+		},
+		{
+			name: "required while the VM is bootstrapping",
+			azureMachine: &infrav1.AzureMachine{
+				Spec: infrav1.AzureMachineSpec{ProviderID: &providerID},
+				Status: infrav1.AzureMachineStatus{
+					VMState:    &[]infrav1.ProvisioningState{infrav1.Succeeded}[0],
+					Conditions: bootstrapped(corev1.ConditionFalse),
+				},
+			},
+			want: true,
+		},
+		{
+			name: "not required once the VM is bootstrapped",
+			azureMachine: &infrav1.AzureMachine{
+				Spec: infrav1.AzureMachineSpec{ProviderID: &providerID},
+				Status: infrav1.AzureMachineStatus{
+					VMState:    &[]infrav1.ProvisioningState{infrav1.Succeeded}[0],
+					Conditions: bootstrapped(corev1.ConditionTrue),
+				},
+			},
+			want: false,
+		},
+		{
+			name:         "required without a bootstrap extension until the node exists",
+			azureMachine: withoutExtension(time.Now().Add(time.Hour)),
+			want:         true,
+		},
+		{
+			name:         "not required without a bootstrap extension once the node exists",
+			azureMachine: withoutExtension(time.Now().Add(time.Hour)),
+			machine: &clusterv1.Machine{
+				Status: clusterv1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: "node-name"}},
+			},
+			want: false,
+		},
+		{
+			name:         "not required without a bootstrap extension once the SAS URL expired",
+			azureMachine: withoutExtension(time.Now().Add(-time.Minute)),
+			want:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine := tt.machine
+			if machine == nil {
+				machine = &clusterv1.Machine{}
+			}
+			m := &MachineScope{AzureMachine: tt.azureMachine, Machine: machine}
+			if got := m.BootstrapDataRequired(); got != tt.want {
+				t.Errorf("BootstrapDataRequired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -463,9 +833,9 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 				},
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
-							Environment: autorestazure.Environment{
-								Name: autorestazure.PublicCloud.Name,
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.PublicCloud.Name,
 							},
 						},
 					},
@@ -496,7 +866,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 			},
 		},
 		{
-			name: "If OS type is Linux and cloud is not AzurePublicCloud, it returns empty",
+			name: "If the cluster has a proxy, the bootstrapping ExtensionSpec command runs with the proxy environment",
 			machineScope: MachineScope{
 				Machine: &clusterv1.Machine{},
 				AzureMachine: &infrav1.AzureMachine{
@@ -511,9 +881,9 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 				},
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
-							Environment: autorestazure.Environment{
-								Name: autorestazure.USGovernmentCloud.Name,
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.PublicCloud.Name,
 							},
 						},
 					},
@@ -522,15 +892,41 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 							ResourceGroup: "my-rg",
 							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
 								Location: "westus",
+								Proxy: &infrav1.ProxyConfig{
+									HTTPSProxy: "http://proxy.example.com:3128",
+								},
+							},
+							NetworkSpec: infrav1.NetworkSpec{
+								Vnet: infrav1.VnetSpec{
+									VnetClassSpec: infrav1.VnetClassSpec{
+										CIDRBlocks: []string{"10.0.0.0/8"},
+									},
+								},
 							},
 						},
 					},
 				},
 			},
-			want: []azure.ResourceSpecGetter{},
+			want: []azure.ResourceSpecGetter{
+				&vmextensions.VMExtensionSpec{
+					ExtensionSpec: azure.ExtensionSpec{
+						Name:      "CAPZ.Linux.Bootstrapping",
+						VMName:    "machine-name",
+						Publisher: "Microsoft.Azure.ContainerUpstream",
+						Version:   "1.0",
+						ProtectedSettings: map[string]string{
+							"commandToExecute": "export 'https_proxy=http://proxy.example.com:3128' 'HTTPS_PROXY=http://proxy.example.com:3128' " +
+								"'no_proxy=localhost,127.0.0.1,169.254.169.254,168.63.129.16,10.0.0.0/8' " +
+								"'NO_PROXY=localhost,127.0.0.1,169.254.169.254,168.63.129.16,10.0.0.0/8'; " + azure.LinuxBootstrapExtensionCommand,
+						},
+					},
+					ResourceGroup: "my-rg",
+					Location:      "westus",
+				},
+			},
 		},
 		{
-			name: "If OS type is Windows and cloud is AzurePublicCloud, it returns ExtensionSpec",
+			name: "If MultiInstanceGPU is set, it only returns the bootstrapping ExtensionSpec, as the GPUs are partitioned by the bootstrap data",
 			machineScope: MachineScope{
 				Machine: &clusterv1.Machine{},
 				AzureMachine: &infrav1.AzureMachine{
@@ -539,15 +935,18 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					},
 					Spec: infrav1.AzureMachineSpec{
 						OSDisk: infrav1.OSDisk{
-							OSType: "Windows",
+							OSType: "Linux",
+						},
+						MultiInstanceGPU: &infrav1.MultiInstanceGPU{
+							Profile: "3g.40gb",
 						},
 					},
 				},
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
-							Environment: autorestazure.Environment{
-								Name: autorestazure.PublicCloud.Name,
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.PublicCloud.Name,
 							},
 						},
 					},
@@ -564,12 +963,12 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 			want: []azure.ResourceSpecGetter{
 				&vmextensions.VMExtensionSpec{
 					ExtensionSpec: azure.ExtensionSpec{
-						Name:      "CAPZ.Windows.Bootstrapping",
+						Name:      "CAPZ.Linux.Bootstrapping",
 						VMName:    "machine-name",
 						Publisher: "Microsoft.Azure.ContainerUpstream",
 						Version:   "1.0",
 						ProtectedSettings: map[string]string{
-							"commandToExecute": azure.WindowsBootstrapExtensionCommand,
+							"commandToExecute": azure.LinuxBootstrapExtensionCommand,
 						},
 					},
 					ResourceGroup: "my-rg",
@@ -578,7 +977,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 			},
 		},
 		{
-			name: "If OS type is Windows and cloud is not AzurePublicCloud, it returns empty",
+			name: "If OS type is Linux, distro is AzureLinux and cloud is AzurePublicCloud, it returns the Custom Script ExtensionSpec",
 			machineScope: MachineScope{
 				Machine: &clusterv1.Machine{},
 				AzureMachine: &infrav1.AzureMachine{
@@ -587,15 +986,16 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					},
 					Spec: infrav1.AzureMachineSpec{
 						OSDisk: infrav1.OSDisk{
-							OSType: "Windows",
+							OSType: "Linux",
+							Distro: infrav1.OSDistroAzureLinux,
 						},
 					},
 				},
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
-							Environment: autorestazure.Environment{
-								Name: autorestazure.USGovernmentCloud.Name,
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.PublicCloud.Name,
 							},
 						},
 					},
@@ -609,10 +1009,24 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					},
 				},
 			},
-			want: []azure.ResourceSpecGetter{},
+			want: []azure.ResourceSpecGetter{
+				&vmextensions.VMExtensionSpec{
+					ExtensionSpec: azure.ExtensionSpec{
+						Name:      "CustomScript",
+						VMName:    "machine-name",
+						Publisher: "Microsoft.Azure.Extensions",
+						Version:   "2.1",
+						ProtectedSettings: map[string]string{
+							"commandToExecute": azure.LinuxBootstrapExtensionCommand,
+						},
+					},
+					ResourceGroup: "my-rg",
+					Location:      "westus",
+				},
+			},
 		},
 		{
-			name: "If OS type is not Linux or Windows and cloud is AzurePublicCloud, it returns empty",
+			name: "If OS type is Linux and cloud is not AzurePublicCloud, it returns empty",
 			machineScope: MachineScope{
 				Machine: &clusterv1.Machine{},
 				AzureMachine: &infrav1.AzureMachine{
@@ -621,15 +1035,15 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					},
 					Spec: infrav1.AzureMachineSpec{
 						OSDisk: infrav1.OSDisk{
-							OSType: "Other",
+							OSType: "Linux",
 						},
 					},
 				},
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
-							Environment: autorestazure.Environment{
-								Name: autorestazure.PublicCloud.Name,
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.USGovernmentCloud.Name,
 							},
 						},
 					},
@@ -646,7 +1060,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 			want: []azure.ResourceSpecGetter{},
 		},
 		{
-			name: "If OS type is not Windows or Linux and cloud is not AzurePublicCloud, it returns empty",
+			name: "If OS type is Windows and cloud is AzurePublicCloud, it returns ExtensionSpec",
 			machineScope: MachineScope{
 				Machine: &clusterv1.Machine{},
 				AzureMachine: &infrav1.AzureMachine{
@@ -655,15 +1069,15 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					},
 					Spec: infrav1.AzureMachineSpec{
 						OSDisk: infrav1.OSDisk{
-							OSType: "Other",
+							OSType: "Windows",
 						},
 					},
 				},
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
-							Environment: autorestazure.Environment{
-								Name: autorestazure.USGovernmentCloud.Name,
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.PublicCloud.Name,
 							},
 						},
 					},
@@ -677,17 +1091,133 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					},
 				},
 			},
-			want: []azure.ResourceSpecGetter{},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.machineScope.VMExtensionSpecs(); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("VMExtensionSpecs() = \n%s, want \n%s", specArrayToString(got), specArrayToString(tt.want))
-			}
-		})
-	}
-}
+			want: []azure.ResourceSpecGetter{
+				&vmextensions.VMExtensionSpec{
+					ExtensionSpec: azure.ExtensionSpec{
+						Name:      "CAPZ.Windows.Bootstrapping",
+						VMName:    "machine-name",
+						Publisher: "Microsoft.Azure.ContainerUpstream",
+						Version:   "1.0",
+						ProtectedSettings: map[string]string{
+							"commandToExecute": azure.WindowsBootstrapExtensionCommand,
+						},
+					},
+					ResourceGroup: "my-rg",
+					Location:      "westus",
+				},
+			},
+		},
+		{
+			name: "If OS type is Windows and cloud is not AzurePublicCloud, it returns empty",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							OSType: "Windows",
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.USGovernmentCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+						},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{},
+		},
+		{
+			name: "If OS type is not Linux or Windows and cloud is AzurePublicCloud, it returns empty",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							OSType: "Other",
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.PublicCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+						},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{},
+		},
+		{
+			name: "If OS type is not Windows or Linux and cloud is not AzurePublicCloud, it returns empty",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							OSType: "Other",
+						},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.USGovernmentCloud.Name,
+							},
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+						},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.machineScope.VMExtensionSpecs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("VMExtensionSpecs() = \n%s, want \n%s", specArrayToString(got), specArrayToString(tt.want))
+			}
+		})
+	}
+}
 
 func TestMachineScope_Subnet(t *testing.T) {
 	tests := []struct {
@@ -1191,13 +1721,119 @@ func TestMachineScope_VMState(t *testing.T) {
 	}
 }
 
+func TestMachineScope_DesiredPowerState(t *testing.T) {
+	tests := []struct {
+		name         string
+		machineScope MachineScope
+		want         infrav1.PowerState
+	}{
+		{
+			name: "returns the DesiredPowerState of the AzureMachine spec",
+			machineScope: MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					Spec: infrav1.AzureMachineSpec{
+						DesiredPowerState: infrav1.PowerStateRunning,
+					},
+				},
+			},
+			want: infrav1.PowerStateRunning,
+		},
+		{
+			name: "returns the power state required by the hibernation of the cluster first",
+			machineScope: MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					Spec: infrav1.AzureMachineSpec{
+						DesiredPowerState: infrav1.PowerStateRunning,
+					},
+				},
+				clusterPowerState: infrav1.PowerStateDeallocated,
+			},
+			want: infrav1.PowerStateDeallocated,
+		},
+		{
+			name: "returns empty if no power state is desired",
+			machineScope: MachineScope{
+				AzureMachine: &infrav1.AzureMachine{},
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.machineScope.DesiredPowerState(); got != tt.want {
+				t.Errorf("DesiredPowerState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAzureMachineResourceNames(t *testing.T) {
+	tests := []struct {
+		name         string
+		azureMachine *infrav1.AzureMachine
+		want         []string
+	}{
+		{
+			name: "default resources",
+			azureMachine: &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine-1"},
+			},
+			want: []string{"machine-1", "machine-1_OSDisk", "machine-1-nic"},
+		},
+		{
+			name: "public IP, network interfaces and data disks",
+			azureMachine: &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine-1"},
+				Spec: infrav1.AzureMachineSpec{
+					AllocatePublicIP: true,
+					NetworkInterfaces: []infrav1.AzureNetworkInterface{
+						{SubnetName: "subnet-1"},
+						{ID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/existing"},
+						{SubnetName: "subnet-2"},
+					},
+					DataDisks: []infrav1.DataDisk{
+						{NameSuffix: "etcddisk"},
+						{NameSuffix: "existing", ExistingDiskID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/existing"},
+					},
+					DerivedResources: &infrav1.DerivedResources{
+						OSDisk: &infrav1.DerivedResource{NameSuffix: "-os"},
+					},
+				},
+			},
+			want: []string{"machine-1", "machine-1-os", "pip-machine-1", "machine-1-nic-0", "machine-1-nic-2", "machine-1_etcddisk"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(AzureMachineResourceNames(tt.azureMachine)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineScope_SetHibernated(t *testing.T) {
+	g := NewWithT(t)
+	machineScope := MachineScope{
+		AzureMachine: &infrav1.AzureMachine{},
+	}
+	g.Expect(machineScope.IsHibernated()).To(BeFalse())
+
+	machineScope.SetHibernated(true)
+	g.Expect(machineScope.IsHibernated()).To(BeTrue())
+	g.Expect(machineScope.AzureMachine.Annotations).To(HaveKey(infrav1.HibernatedAnnotation))
+
+	machineScope.SetHibernated(false)
+	g.Expect(machineScope.IsHibernated()).To(BeFalse())
+}
+
 func TestMachineScope_GetVMImage(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
 	clusterMock := mock_azure.NewMockClusterScoper(mockCtrl)
-	clusterMock.EXPECT().Authorizer().AnyTimes()
-	clusterMock.EXPECT().BaseURI().AnyTimes()
+	clusterMock.EXPECT().Token().AnyTimes()
+	clusterMock.EXPECT().CloudEnvironment().AnyTimes()
 	clusterMock.EXPECT().Location().AnyTimes()
 	clusterMock.EXPECT().SubscriptionID().AnyTimes()
 	svc := virtualmachineimages.Service{Client: mock_virtualmachineimages.NewMockClient(mockCtrl)}
@@ -1227,6 +1863,48 @@ func TestMachineScope_GetVMImage(t *testing.T) {
 			},
 			expectedErr: "",
 		},
+		{
+			name: "if no image is specified, returns the default image previously resolved and recorded in the AzureMachine status",
+			machineScope: MachineScope{
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: clusterv1.MachineSpec{
+						Version: pointer.String("1.24.0"),
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Status: infrav1.AzureMachineStatus{
+						Image: &infrav1.Image{
+							Marketplace: &infrav1.AzureMarketplaceImage{
+								ImagePlan: infrav1.ImagePlan{
+									Publisher: azure.DefaultImagePublisherID,
+									Offer:     azure.DefaultImageOfferID,
+									SKU:       "ubuntu-2004-gen1",
+								},
+								Version: "124.0.20220512",
+							},
+						},
+					},
+				},
+				ClusterScoper: clusterMock,
+			},
+			want: &infrav1.Image{
+				Marketplace: &infrav1.AzureMarketplaceImage{
+					ImagePlan: infrav1.ImagePlan{
+						Publisher: azure.DefaultImagePublisherID,
+						Offer:     azure.DefaultImageOfferID,
+						SKU:       "ubuntu-2004-gen1",
+					},
+					Version: "124.0.20220512",
+				},
+			},
+			expectedErr: "",
+		},
 		{
 			name: "if no image is specified and os specified is windows with version below 1.22, returns windows dockershim image",
 			machineScope: MachineScope{
@@ -1476,6 +2154,9 @@ func TestMachineScope_GetVMImage(t *testing.T) {
 			if !reflect.DeepEqual(gotImage, tt.want) {
 				t.Errorf("GetVMImage(), gotImage = %v, wantImage %v", gotImage, tt.want)
 			}
+			if tt.machineScope.AzureMachine.Spec.Image == nil && !reflect.DeepEqual(tt.machineScope.AzureMachine.Status.Image, tt.want) {
+				t.Errorf("GetVMImage(), status image = %v, wantImage %v", tt.machineScope.AzureMachine.Status.Image, tt.want)
+			}
 		})
 	}
 }
@@ -1491,9 +2172,9 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
+						EnvironmentSettings: EnvironmentSettings{
 							Values: map[string]string{
-								auth.SubscriptionID: "123",
+								envSubscriptionID: "123",
 							},
 						},
 					},
@@ -1545,7 +2226,7 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 						Name: "machine",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID: to.StringPtr("azure://compute/virtual-machines/machine-name"),
+						ProviderID: to.Ptr("azure://compute/virtual-machines/machine-name"),
 						SubnetName: "subnet1",
 					},
 				},
@@ -1565,6 +2246,8 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					Location:                  "westus",
 					SubscriptionID:            "123",
 					MachineName:               "machine-name",
+					ClusterName:               "cluster",
+					AdditionalTags:            infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
 					SubnetName:                "subnet1",
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
@@ -1586,9 +2269,9 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
+						EnvironmentSettings: EnvironmentSettings{
 							Values: map[string]string{
-								auth.SubscriptionID: "123",
+								envSubscriptionID: "123",
 							},
 						},
 					},
@@ -1640,7 +2323,7 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 						Name: "machine",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID: to.StringPtr("azure://compute/virtual-machines/machine-name"),
+						ProviderID: to.Ptr("azure://compute/virtual-machines/machine-name"),
 						SubnetName: "subnet1",
 					},
 				},
@@ -1654,7 +2337,7 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 				},
 				cache: &MachineCache{
 					VMSKU: resourceskus.SKU{
-						Name: to.StringPtr("Standard_D2v2"),
+						Name: to.Ptr("Standard_D2v2"),
 					},
 				},
 			},
@@ -1665,6 +2348,8 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					Location:                  "westus",
 					SubscriptionID:            "123",
 					MachineName:               "machine-name",
+					ClusterName:               "cluster",
+					AdditionalTags:            infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
 					SubnetName:                "subnet1",
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
@@ -1678,7 +2363,7 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					IPv6Enabled:               false,
 					EnableIPForwarding:        false,
 					SKU: &resourceskus.SKU{
-						Name: to.StringPtr("Standard_D2v2"),
+						Name: to.Ptr("Standard_D2v2"),
 					},
 				},
 			},
@@ -1688,9 +2373,9 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
+						EnvironmentSettings: EnvironmentSettings{
 							Values: map[string]string{
-								auth.SubscriptionID: "123",
+								envSubscriptionID: "123",
 							},
 						},
 					},
@@ -1747,7 +2432,7 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 						Name: "machine",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID: to.StringPtr("azure://compute/virtual-machines/machine-name"),
+						ProviderID: to.Ptr("azure://compute/virtual-machines/machine-name"),
 						SubnetName: "subnet1",
 					},
 				},
@@ -1767,6 +2452,8 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					Location:                  "westus",
 					SubscriptionID:            "123",
 					MachineName:               "machine-name",
+					ClusterName:               "cluster",
+					AdditionalTags:            infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
 					SubnetName:                "subnet1",
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
@@ -1788,9 +2475,9 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
+						EnvironmentSettings: EnvironmentSettings{
 							Values: map[string]string{
-								auth.SubscriptionID: "123",
+								envSubscriptionID: "123",
 							},
 						},
 					},
@@ -1842,7 +2529,7 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 						Name: "machine",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID:       to.StringPtr("azure://compute/virtual-machines/machine-name"),
+						ProviderID:       to.Ptr("azure://compute/virtual-machines/machine-name"),
 						SubnetName:       "subnet1",
 						AllocatePublicIP: true,
 					},
@@ -1863,6 +2550,8 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					Location:                  "westus",
 					SubscriptionID:            "123",
 					MachineName:               "machine-name",
+					ClusterName:               "cluster",
+					AdditionalTags:            infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
 					SubnetName:                "subnet1",
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
@@ -1884,9 +2573,9 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
+						EnvironmentSettings: EnvironmentSettings{
 							Values: map[string]string{
-								auth.SubscriptionID: "123",
+								envSubscriptionID: "123",
 							},
 						},
 					},
@@ -1944,7 +2633,7 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 						Name: "machine",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID: to.StringPtr("azure://compute/virtual-machines/machine-name"),
+						ProviderID: to.Ptr("azure://compute/virtual-machines/machine-name"),
 						SubnetName: "subnet1",
 					},
 				},
@@ -1964,6 +2653,8 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					Location:                  "westus",
 					SubscriptionID:            "123",
 					MachineName:               "machine-name",
+					ClusterName:               "cluster",
+					AdditionalTags:            infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
 					SubnetName:                "subnet1",
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
@@ -1985,9 +2676,9 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
+						EnvironmentSettings: EnvironmentSettings{
 							Values: map[string]string{
-								auth.SubscriptionID: "123",
+								envSubscriptionID: "123",
 							},
 						},
 					},
@@ -2042,7 +2733,7 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 						Name: "machine",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID: to.StringPtr("azure://compute/virtual-machines/machine-name"),
+						ProviderID: to.Ptr("azure://compute/virtual-machines/machine-name"),
 						SubnetName: "subnet1",
 					},
 				},
@@ -2062,6 +2753,8 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					Location:                  "westus",
 					SubscriptionID:            "123",
 					MachineName:               "machine-name",
+					ClusterName:               "cluster",
+					AdditionalTags:            infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
 					SubnetName:                "subnet1",
 					VNetName:                  "vnet1",
 					VNetResourceGroup:         "rg1",
@@ -2083,9 +2776,9 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
+						EnvironmentSettings: EnvironmentSettings{
 							Values: map[string]string{
-								auth.SubscriptionID: "123",
+								envSubscriptionID: "123",
 							},
 						},
 					},
@@ -2140,7 +2833,7 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 						Name: "machine",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID: to.StringPtr("azure://compute/virtual-machines/machine-name"),
+						ProviderID: to.Ptr("azure://compute/virtual-machines/machine-name"),
 						NetworkInterfaces: []infrav1.AzureNetworkInterface{
 							{
 								SubnetName:            "subnet1",
@@ -2170,6 +2863,8 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					Location:                  "westus",
 					SubscriptionID:            "123",
 					MachineName:               "machine-name",
+					ClusterName:               "cluster",
+					AdditionalTags:            infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
 					SubnetName:                "subnet1",
 					IPConfigs:                 []networkinterfaces.IPConfig{{}, {}},
 					VNetName:                  "vnet1",
@@ -2191,6 +2886,8 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					Location:                  "westus",
 					SubscriptionID:            "123",
 					MachineName:               "machine-name",
+					ClusterName:               "cluster",
+					AdditionalTags:            infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
 					SubnetName:                "subnet2",
 					IPConfigs:                 []networkinterfaces.IPConfig{{PublicIP: true}, {}},
 					VNetName:                  "vnet1",
@@ -2213,9 +2910,9 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
+						EnvironmentSettings: EnvironmentSettings{
 							Values: map[string]string{
-								auth.SubscriptionID: "123",
+								envSubscriptionID: "123",
 							},
 						},
 					},
@@ -2270,7 +2967,7 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 						Name: "machine",
 					},
 					Spec: infrav1.AzureMachineSpec{
-						ProviderID: to.StringPtr("azure://compute/virtual-machines/machine-name"),
+						ProviderID: to.Ptr("azure://compute/virtual-machines/machine-name"),
 						NetworkInterfaces: []infrav1.AzureNetworkInterface{
 							{
 								SubnetName:            "subnet1",
@@ -2294,6 +2991,8 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 					Location:                  "westus",
 					SubscriptionID:            "123",
 					MachineName:               "machine-name",
+					ClusterName:               "cluster",
+					AdditionalTags:            infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
 					SubnetName:                "subnet1",
 					IPConfigs:                 []networkinterfaces.IPConfig{{}, {}, {}, {}, {}, {}, {}, {}, {}, {}},
 					VNetName:                  "vnet1",
@@ -2311,52 +3010,267 @@ func TestMachineScope_NICSpecs(t *testing.T) {
 				},
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			gotNicSpecs := tt.machineScope.NICSpecs()
-			if !reflect.DeepEqual(gotNicSpecs, tt.want) {
-				t.Errorf("NICSpecs(), gotNicSpecs = %s, want %s", specArrayToString(gotNicSpecs), specArrayToString(tt.want))
-			}
-		})
-	}
-}
-
-func TestDiskSpecs(t *testing.T) {
-	testcases := []struct {
-		name         string
-		machineScope MachineScope
-		want         []azure.ResourceSpecGetter
-	}{
 		{
-			name: "only os disk",
+			name: "Node Machine with DNS settings",
 			machineScope: MachineScope{
 				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: EnvironmentSettings{
+							Values: map[string]string{
+								envSubscriptionID: "123",
+							},
+						},
+					},
 					Cluster: &clusterv1.Cluster{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "cluster",
+							Name:      "cluster",
+							Namespace: "default",
 						},
 					},
 					AzureCluster: &infrav1.AzureCluster{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "cluster",
+							Name:      "cluster",
+							Namespace: "default",
+							OwnerReferences: []metav1.OwnerReference{
+								{
+									APIVersion: "cluster.x-k8s.io/v1beta1",
+									Kind:       "Cluster",
+									Name:       "cluster",
+								},
+							},
 						},
 						Spec: infrav1.AzureClusterSpec{
 							ResourceGroup: "my-rg",
-						},
-					},
-				},
-				AzureMachine: &infrav1.AzureMachine{
-					ObjectMeta: metav1.ObjectMeta{
-						Name: "my-azure-machine",
-					},
-					Spec: infrav1.AzureMachineSpec{
-						OSDisk: infrav1.OSDisk{
-							DiskSizeGB: to.Int32Ptr(30),
-							OSType:     "Linux",
-						},
-					},
-				},
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+							NetworkSpec: infrav1.NetworkSpec{
+								Vnet: infrav1.VnetSpec{
+									Name:          "vnet1",
+									ResourceGroup: "rg1",
+								},
+								Subnets: []infrav1.SubnetSpec{
+									{
+										SubnetClassSpec: infrav1.SubnetClassSpec{
+											Role: infrav1.SubnetNode,
+										},
+										Name: "subnet1",
+									},
+								},
+								APIServerLB: infrav1.LoadBalancerSpec{
+									Name: "api-lb",
+								},
+								NodeOutboundLB: &infrav1.LoadBalancerSpec{
+									Name: "outbound-lb",
+								},
+							},
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						ProviderID: to.Ptr("azure://compute/virtual-machines/machine-name"),
+						NetworkInterfaces: []infrav1.AzureNetworkInterface{
+							{
+								SubnetName:            "subnet1",
+								AcceleratedNetworking: pointer.Bool(true),
+								PrivateIPConfigs:      10,
+								InternalDNSNameLabel:  "win-node",
+								DNSServers:            []string{"10.0.0.4"},
+							},
+						},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "machine",
+						Labels: map[string]string{},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&networkinterfaces.NICSpec{
+					Name:                      "machine-name-nic-0",
+					ResourceGroup:             "my-rg",
+					Location:                  "westus",
+					SubscriptionID:            "123",
+					MachineName:               "machine-name",
+					ClusterName:               "cluster",
+					AdditionalTags:            infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
+					SubnetName:                "subnet1",
+					IPConfigs:                 []networkinterfaces.IPConfig{{}, {}, {}, {}, {}, {}, {}, {}, {}, {}},
+					VNetName:                  "vnet1",
+					VNetResourceGroup:         "rg1",
+					PublicLBName:              "outbound-lb",
+					PublicLBAddressPoolName:   "outbound-lb-outboundBackendPool",
+					PublicLBNATRuleName:       "",
+					InternalLBName:            "",
+					InternalLBAddressPoolName: "",
+					PublicIPName:              "",
+					AcceleratedNetworking:     pointer.Bool(true),
+					IPv6Enabled:               false,
+					EnableIPForwarding:        false,
+					SKU:                       nil,
+					InternalDNSNameLabel:      "win-node",
+					DNSServers:                []string{"10.0.0.4"},
+				},
+			},
+		},
+		{
+			name: "Node Machine with IP forwarding enabled on the interface",
+			machineScope: MachineScope{
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: EnvironmentSettings{
+							Values: map[string]string{
+								envSubscriptionID: "123",
+							},
+						},
+					},
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "cluster",
+							Namespace: "default",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "cluster",
+							Namespace: "default",
+							OwnerReferences: []metav1.OwnerReference{
+								{
+									APIVersion: "cluster.x-k8s.io/v1beta1",
+									Kind:       "Cluster",
+									Name:       "cluster",
+								},
+							},
+						},
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus",
+							},
+							NetworkSpec: infrav1.NetworkSpec{
+								Vnet: infrav1.VnetSpec{
+									Name:          "vnet1",
+									ResourceGroup: "rg1",
+								},
+								Subnets: []infrav1.SubnetSpec{
+									{
+										SubnetClassSpec: infrav1.SubnetClassSpec{
+											Role: infrav1.SubnetNode,
+										},
+										Name: "subnet1",
+									},
+								},
+								APIServerLB: infrav1.LoadBalancerSpec{
+									Name: "api-lb",
+								},
+								NodeOutboundLB: &infrav1.LoadBalancerSpec{
+									Name: "outbound-lb",
+								},
+							},
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						ProviderID: to.Ptr("azure://compute/virtual-machines/machine-name"),
+						NetworkInterfaces: []infrav1.AzureNetworkInterface{
+							{
+								SubnetName:            "subnet1",
+								AcceleratedNetworking: pointer.Bool(true),
+								PrivateIPConfigs:      10,
+								EnableIPForwarding:    pointer.Bool(true),
+							},
+						},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "machine",
+						Labels: map[string]string{},
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&networkinterfaces.NICSpec{
+					Name:                      "machine-name-nic-0",
+					ResourceGroup:             "my-rg",
+					Location:                  "westus",
+					SubscriptionID:            "123",
+					MachineName:               "machine-name",
+					ClusterName:               "cluster",
+					AdditionalTags:            infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
+					SubnetName:                "subnet1",
+					IPConfigs:                 []networkinterfaces.IPConfig{{}, {}, {}, {}, {}, {}, {}, {}, {}, {}},
+					VNetName:                  "vnet1",
+					VNetResourceGroup:         "rg1",
+					PublicLBName:              "outbound-lb",
+					PublicLBAddressPoolName:   "outbound-lb-outboundBackendPool",
+					PublicLBNATRuleName:       "",
+					InternalLBName:            "",
+					InternalLBAddressPoolName: "",
+					PublicIPName:              "",
+					AcceleratedNetworking:     pointer.Bool(true),
+					IPv6Enabled:               false,
+					EnableIPForwarding:        true,
+					SKU:                       nil,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotNicSpecs := tt.machineScope.NICSpecs()
+			if !reflect.DeepEqual(gotNicSpecs, tt.want) {
+				t.Errorf("NICSpecs(), gotNicSpecs = %s, want %s", specArrayToString(gotNicSpecs), specArrayToString(tt.want))
+			}
+		})
+	}
+}
+
+func TestDiskSpecs(t *testing.T) {
+	testcases := []struct {
+		name         string
+		machineScope MachineScope
+		want         []azure.ResourceSpecGetter
+	}{
+		{
+			name: "only os disk",
+			machineScope: MachineScope{
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-azure-machine",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							DiskSizeGB: to.Ptr[int32](30),
+							OSType:     "Linux",
+						},
+					},
+				},
 				Machine: &clusterv1.Machine{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: "machine",
@@ -2394,7 +3308,7 @@ func TestDiskSpecs(t *testing.T) {
 					},
 					Spec: infrav1.AzureMachineSpec{
 						OSDisk: infrav1.OSDisk{
-							DiskSizeGB: to.Int32Ptr(30),
+							DiskSizeGB: to.Ptr[int32](30),
 							OSType:     "Linux",
 						},
 						DataDisks: []infrav1.DataDisk{
@@ -2444,7 +3358,7 @@ func TestDiskSpecs(t *testing.T) {
 					},
 					Spec: infrav1.AzureMachineSpec{
 						OSDisk: infrav1.OSDisk{
-							DiskSizeGB: to.Int32Ptr(30),
+							DiskSizeGB: to.Ptr[int32](30),
 							OSType:     "Linux",
 						},
 						DataDisks: []infrav1.DataDisk{
@@ -2477,6 +3391,66 @@ func TestDiskSpecs(t *testing.T) {
 					ResourceGroup: "my-rg",
 				},
 			},
+		}, {
+			name: "existing and detached data disks",
+			machineScope: MachineScope{
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+					},
+					AzureCluster: &infrav1.AzureCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "cluster",
+						},
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+						},
+					},
+				},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-azure-machine",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							DiskSizeGB: to.Ptr[int32](30),
+							OSType:     "Linux",
+						},
+						DataDisks: []infrav1.DataDisk{
+							{
+								NameSuffix:     "existingdisk",
+								ExistingDiskID: "/subscriptions/123/resourceGroups/data-rg/providers/Microsoft.Compute/disks/my-data",
+							},
+							{
+								NameSuffix:     "deletedexistingdisk",
+								ExistingDiskID: "/subscriptions/123/resourceGroups/data-rg/providers/Microsoft.Compute/disks/my-scratch",
+								DeletePolicy:   infrav1.DiskDeletePolicyDelete,
+							},
+							{
+								NameSuffix:   "retaineddisk",
+								DeletePolicy: infrav1.DiskDeletePolicyDetach,
+							},
+						},
+					},
+				},
+				Machine: &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine",
+					},
+				},
+			},
+			want: []azure.ResourceSpecGetter{
+				&disks.DiskSpec{
+					Name:          "my-azure-machine_OSDisk",
+					ResourceGroup: "my-rg",
+				},
+				&disks.DiskSpec{
+					Name:          "my-scratch",
+					ResourceGroup: "data-rg",
+				},
+			},
 		},
 	}
 
@@ -2491,3 +3465,572 @@ func TestDiskSpecs(t *testing.T) {
 		})
 	}
 }
+
+func TestSnapshotSpecs(t *testing.T) {
+	created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	newMachineScope := func(spec infrav1.AzureMachineSpec, annotations map[string]string, status infrav1.AzureMachineStatus) *MachineScope {
+		return &MachineScope{
+			ClusterScoper: &ClusterScope{
+				AzureClients: AzureClients{
+					EnvironmentSettings: EnvironmentSettings{
+						Values: map[string]string{
+							envSubscriptionID: "123",
+						},
+					},
+				},
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cluster",
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+							Location: "westus",
+						},
+					},
+				},
+			},
+			AzureMachine: &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "my-azure-machine",
+					CreationTimestamp: metav1.NewTime(created),
+					Annotations:       annotations,
+				},
+				Spec:   spec,
+				Status: status,
+			},
+		}
+	}
+	lastSnapshotTime := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	testcases := []struct {
+		name          string
+		machineScope  *MachineScope
+		wantNames     []string
+		wantSourceIDs []string
+	}{
+		{
+			name:         "no snapshots without a schedule or a request",
+			machineScope: newMachineScope(infrav1.AzureMachineSpec{}, nil, infrav1.AzureMachineStatus{}),
+		},
+		{
+			name: "snapshot of the OS disk one interval after the machine was created",
+			machineScope: newMachineScope(infrav1.AzureMachineSpec{
+				DiskSnapshots: &infrav1.DiskSnapshots{Interval: &metav1.Duration{Duration: 24 * time.Hour}},
+				DataDisks:     []infrav1.DataDisk{{NameSuffix: "etcddisk"}},
+			}, nil, infrav1.AzureMachineStatus{}),
+			wantNames:     []string{"my-azure-machine_OSDisk-20220102000000"},
+			wantSourceIDs: []string{"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-azure-machine_OSDisk"},
+		},
+		{
+			name: "no snapshots before the next scheduled ones",
+			machineScope: newMachineScope(infrav1.AzureMachineSpec{
+				DiskSnapshots: &infrav1.DiskSnapshots{Interval: &metav1.Duration{Duration: 24 * time.Hour}},
+			}, nil, infrav1.AzureMachineStatus{LastDiskSnapshotTime: &lastSnapshotTime}),
+		},
+		{
+			name: "requested snapshots of the OS and data disks",
+			machineScope: newMachineScope(infrav1.AzureMachineSpec{
+				DiskSnapshots: &infrav1.DiskSnapshots{IncludeDataDisks: true},
+				DataDisks: []infrav1.DataDisk{
+					{NameSuffix: "etcddisk"},
+					{NameSuffix: "existingdisk", ExistingDiskID: "/subscriptions/123/resourceGroups/data-rg/providers/Microsoft.Compute/disks/my-data"},
+				},
+			}, map[string]string{infrav1.DiskSnapshotRequestAnnotation: "before-upgrade"}, infrav1.AzureMachineStatus{}),
+			wantNames: []string{"my-azure-machine_OSDisk-request-0373de13", "my-azure-machine_etcddisk-request-0373de13", "my-data-request-0373de13"},
+			wantSourceIDs: []string{
+				"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-azure-machine_OSDisk",
+				"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-azure-machine_etcddisk",
+				"/subscriptions/123/resourceGroups/data-rg/providers/Microsoft.Compute/disks/my-data",
+			},
+		},
+		{
+			name:         "no snapshots for a request already fulfilled",
+			machineScope: newMachineScope(infrav1.AzureMachineSpec{}, map[string]string{infrav1.DiskSnapshotRequestAnnotation: "before-upgrade"}, infrav1.AzureMachineStatus{LastDiskSnapshotRequest: "before-upgrade"}),
+		},
+		{
+			name: "no snapshots of an ephemeral OS disk",
+			machineScope: newMachineScope(infrav1.AzureMachineSpec{
+				OSDisk: infrav1.OSDisk{DiffDiskSettings: &infrav1.DiffDiskSettings{Option: "Local"}},
+			}, map[string]string{infrav1.DiskSnapshotRequestAnnotation: "before-upgrade"}, infrav1.AzureMachineStatus{}),
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			var names, sourceIDs []string
+			for _, spec := range tc.machineScope.SnapshotSpecs() {
+				snapshotSpec := spec.(*snapshots.SnapshotSpec)
+				g.Expect(snapshotSpec.ResourceGroup).To(Equal("my-rg"))
+				g.Expect(snapshotSpec.Location).To(Equal("westus"))
+				g.Expect(snapshotSpec.ClusterName).To(Equal("cluster"))
+				names = append(names, snapshotSpec.Name)
+				sourceIDs = append(sourceIDs, snapshotSpec.SourceDiskID)
+			}
+			g.Expect(names).To(Equal(tc.wantNames))
+			g.Expect(sourceIDs).To(Equal(tc.wantSourceIDs))
+		})
+	}
+}
+
+func TestSetDiskSnapshotsTaken(t *testing.T) {
+	g := NewWithT(t)
+
+	machineScope := MachineScope{
+		ClusterScoper: &ClusterScope{
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+				},
+			},
+		},
+		AzureMachine: &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "my-azure-machine",
+				Annotations: map[string]string{infrav1.DiskSnapshotRequestAnnotation: "before-upgrade"},
+			},
+		},
+	}
+	g.Expect(machineScope.SnapshotSourceDiskNames()).To(BeNil())
+	g.Expect(machineScope.SnapshotRetain()).To(Equal(3))
+
+	machineScope.SetDiskSnapshotsTaken()
+	g.Expect(machineScope.AzureMachine.Status.LastDiskSnapshotTime).NotTo(BeNil())
+	g.Expect(machineScope.AzureMachine.Status.LastDiskSnapshotRequest).To(Equal("before-upgrade"))
+	g.Expect(machineScope.SnapshotSourceDiskNames()).To(Equal([]string{"my-azure-machine_OSDisk"}))
+	g.Expect(machineScope.NextDiskSnapshotTime()).To(BeNil())
+
+	machineScope.AzureMachine.Spec.DiskSnapshots = &infrav1.DiskSnapshots{Interval: &metav1.Duration{Duration: time.Hour}, Retain: 5}
+	g.Expect(machineScope.SnapshotRetain()).To(Equal(5))
+	g.Expect(*machineScope.NextDiskSnapshotTime()).To(Equal(machineScope.AzureMachine.Status.LastDiskSnapshotTime.Add(time.Hour)))
+}
+
+func TestDiskCreateSpecs(t *testing.T) {
+	g := NewWithT(t)
+
+	machineScope := MachineScope{
+		ClusterScoper: &ClusterScope{
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster",
+				},
+			},
+			AzureCluster: &infrav1.AzureCluster{
+				Spec: infrav1.AzureClusterSpec{
+					ResourceGroup: "my-rg",
+					AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+						Location: "westus",
+					},
+				},
+			},
+		},
+		AzureMachine: &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-azure-machine",
+			},
+			Spec: infrav1.AzureMachineSpec{
+				OSDisk: infrav1.OSDisk{
+					OSType:     "Linux",
+					DiskSizeGB: to.Ptr[int32](128),
+				},
+			},
+		},
+		Machine: &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "machine",
+			},
+			Spec: clusterv1.MachineSpec{
+				FailureDomain: to.Ptr("2"),
+			},
+		},
+	}
+	g.Expect(machineScope.DiskCreateSpecs()).To(BeNil())
+
+	machineScope.AzureMachine.Spec.OSDisk.SourceSnapshotID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot"
+	machineScope.AzureMachine.Spec.OSDisk.ManagedDisk = &infrav1.ManagedDiskParameters{StorageAccountType: "Premium_LRS"}
+	g.Expect(machineScope.DiskCreateSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&disks.DiskSpec{
+			Name:               "my-azure-machine_OSDisk",
+			ResourceGroup:      "my-rg",
+			Location:           "westus",
+			Zone:               "2",
+			ClusterName:        "cluster",
+			OSType:             "Linux",
+			SourceSnapshotID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot",
+			DiskSizeGB:         to.Ptr[int32](128),
+			StorageAccountType: "Premium_LRS",
+			AdditionalTags:     infrav1.Tags{"kubernetes.io_cluster_cluster": "owned"},
+		},
+	}))
+}
+
+func TestBackupProtectionSpecs(t *testing.T) {
+	g := NewWithT(t)
+	newMachineScope := func(protection *infrav1.BackupProtection) *MachineScope {
+		return &MachineScope{
+			ClusterScoper: &ClusterScope{
+				AzureClients: AzureClients{
+					EnvironmentSettings: EnvironmentSettings{
+						Values: map[string]string{
+							envSubscriptionID: "123",
+						},
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+					},
+				},
+			},
+			AzureMachine: &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-azure-machine",
+				},
+				Spec: infrav1.AzureMachineSpec{
+					BackupProtection: protection,
+				},
+			},
+		}
+	}
+
+	g.Expect(newMachineScope(nil).BackupProtectionSpecs()).To(BeEmpty())
+
+	vaultID := "/subscriptions/123/resourceGroups/backup-rg/providers/Microsoft.RecoveryServices/vaults/my-vault"
+	g.Expect(newMachineScope(&infrav1.BackupProtection{VaultID: vaultID}).BackupProtectionSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&backupprotection.BackupProtectionSpec{
+			VMName:             "my-azure-machine",
+			VMResourceGroup:    "my-rg",
+			VMID:               "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-azure-machine",
+			VaultName:          "my-vault",
+			VaultResourceGroup: "backup-rg",
+			PolicyID:           vaultID + "/backupPolicies/DefaultPolicy",
+		},
+	}))
+
+	specs := newMachineScope(&infrav1.BackupProtection{VaultID: vaultID, PolicyName: "etcd"}).BackupProtectionSpecs()
+	g.Expect(specs).To(HaveLen(1))
+	g.Expect(specs[0].(*backupprotection.BackupProtectionSpec).PolicyID).To(Equal(vaultID + "/backupPolicies/etcd"))
+}
+
+func TestJITNetworkAccessPolicySpecs(t *testing.T) {
+	g := NewWithT(t)
+	newMachineScope := func(jit *infrav1.JustInTimeAccess) *MachineScope {
+		return &MachineScope{
+			ClusterScoper: &ClusterScope{
+				AzureClients: AzureClients{
+					EnvironmentSettings: EnvironmentSettings{
+						Values: map[string]string{
+							envSubscriptionID: "123",
+						},
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup:   "my-rg",
+						SecurityProfile: &infrav1.ClusterSecurityProfile{JustInTimeAccess: jit},
+					},
+				},
+			},
+			AzureMachine: &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-azure-machine",
+				},
+			},
+		}
+	}
+
+	g.Expect(newMachineScope(nil).JITNetworkAccessPolicySpecs()).To(BeEmpty())
+
+	g.Expect(newMachineScope(&infrav1.JustInTimeAccess{}).JITNetworkAccessPolicySpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&jitnetworkaccesspolicies.JITNetworkAccessPolicySpec{
+			Name:          "my-azure-machine",
+			ResourceGroup: "my-rg",
+			VMID:          "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-azure-machine",
+			Ports:         []infrav1.JustInTimeAccessPort{{Number: 22}},
+		},
+	}))
+
+	ports := []infrav1.JustInTimeAccessPort{{Number: 3389, Protocol: infrav1.JustInTimeAccessProtocolTCP}}
+	specs := newMachineScope(&infrav1.JustInTimeAccess{Ports: ports}).JITNetworkAccessPolicySpecs()
+	g.Expect(specs).To(HaveLen(1))
+	g.Expect(specs[0].(*jitnetworkaccesspolicies.JITNetworkAccessPolicySpec).Ports).To(Equal(ports))
+}
+
+func TestBackendAddressSpecs(t *testing.T) {
+	g := NewWithT(t)
+	newMachineScope := func(role string, backendPoolType infrav1.BackendPoolType) *MachineScope {
+		labels := map[string]string{}
+		if role == infrav1.ControlPlane {
+			labels[clusterv1.MachineControlPlaneLabelName] = ""
+		}
+		return &MachineScope{
+			ClusterScoper: &ClusterScope{
+				AzureClients: AzureClients{
+					EnvironmentSettings: EnvironmentSettings{
+						Values: map[string]string{
+							envSubscriptionID: "123",
+						},
+					},
+				},
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet: infrav1.VnetSpec{
+								Name:          "my-vnet",
+								ResourceGroup: "vnet-rg",
+							},
+							APIServerLB: infrav1.LoadBalancerSpec{
+								Name: "my-lb",
+								LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+									Type:            infrav1.Internal,
+									BackendPoolType: backendPoolType,
+								},
+							},
+						},
+					},
+				},
+			},
+			Machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+			},
+			AzureMachine: &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-azure-machine",
+				},
+				Status: infrav1.AzureMachineStatus{
+					Addresses: []corev1.NodeAddress{
+						{Type: corev1.NodeInternalDNS, Address: "my-azure-machine"},
+						{Type: corev1.NodeInternalIP, Address: "10.0.0.4"},
+					},
+				},
+			},
+		}
+	}
+
+	g.Expect(newMachineScope(infrav1.ControlPlane, "").BackendAddressSpecs()).To(BeEmpty())
+	g.Expect(newMachineScope(infrav1.Node, infrav1.BackendPoolTypeIP).BackendAddressSpecs()).To(BeEmpty())
+	g.Expect(newMachineScope(infrav1.ControlPlane, infrav1.BackendPoolTypeIP).BackendAddressSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&backendaddresses.BackendAddressSpec{
+			Name:             "my-azure-machine",
+			PoolName:         "my-lb-backendPool",
+			LoadBalancerName: "my-lb",
+			ResourceGroup:    "my-rg",
+			IPAddress:        "10.0.0.4",
+			VNetID:           "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+		},
+	}))
+
+	// control plane machines don't join IP backend pools through their network interface.
+	nicSpec := newMachineScope(infrav1.ControlPlane, infrav1.BackendPoolTypeIP).DefaultNICSpec()
+	g.Expect(nicSpec.InternalLBName).To(BeEmpty())
+	g.Expect(nicSpec.InternalLBAddressPoolName).To(BeEmpty())
+	nicSpec = newMachineScope(infrav1.ControlPlane, "").DefaultNICSpec()
+	g.Expect(nicSpec.InternalLBAddressPoolName).To(Equal("my-lb-backendPool"))
+}
+
+func TestExternalAPIServerLBMachineSpecs(t *testing.T) {
+	g := NewWithT(t)
+	newMachineScope := func(backendPoolType infrav1.BackendPoolType) *MachineScope {
+		return &MachineScope{
+			ClusterScoper: &ClusterScope{
+				AzureClients: AzureClients{
+					EnvironmentSettings: EnvironmentSettings{
+						Values: map[string]string{
+							envSubscriptionID: "123",
+						},
+					},
+				},
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "my-cluster",
+					},
+				},
+				AzureCluster: &infrav1.AzureCluster{
+					Spec: infrav1.AzureClusterSpec{
+						ResourceGroup: "my-rg",
+						NetworkSpec: infrav1.NetworkSpec{
+							Vnet: infrav1.VnetSpec{
+								Name:          "my-vnet",
+								ResourceGroup: "vnet-rg",
+							},
+							APIServerLB: infrav1.LoadBalancerSpec{
+								Name: "shared-lb",
+								External: &infrav1.ExternalLoadBalancer{
+									ID:              "/subscriptions/123/resourceGroups/network-rg/providers/Microsoft.Network/loadBalancers/shared-lb",
+									BackendPoolName: "my-cluster-pool",
+								},
+								LoadBalancerClassSpec: infrav1.LoadBalancerClassSpec{
+									Type:            infrav1.Internal,
+									BackendPoolType: backendPoolType,
+								},
+							},
+						},
+					},
+				},
+			},
+			Machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						clusterv1.MachineControlPlaneLabelName: "",
+					},
+				},
+			},
+			AzureMachine: &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-azure-machine",
+				},
+				Status: infrav1.AzureMachineStatus{
+					Addresses: []corev1.NodeAddress{
+						{Type: corev1.NodeInternalIP, Address: "10.0.0.4"},
+					},
+				},
+			},
+		}
+	}
+
+	// control plane machines join the backend pool of the external load balancer through their network interface.
+	nicSpec := newMachineScope("").DefaultNICSpec()
+	g.Expect(nicSpec.ExternalLBAddressPoolID).To(Equal("/subscriptions/123/resourceGroups/network-rg/providers/Microsoft.Network/loadBalancers/shared-lb/backendAddressPools/my-cluster-pool"))
+	g.Expect(nicSpec.InternalLBName).To(BeEmpty())
+	g.Expect(nicSpec.PublicLBName).To(BeEmpty())
+	g.Expect(nicSpec.PublicLBNATRuleName).To(BeEmpty())
+	g.Expect(newMachineScope("").BackendAddressSpecs()).To(BeEmpty())
+	g.Expect(newMachineScope("").InboundNatSpecs(map[int32]struct{}{})).To(BeEmpty())
+
+	// or by their private IP address when the backend pool is of type IP.
+	g.Expect(newMachineScope(infrav1.BackendPoolTypeIP).DefaultNICSpec().ExternalLBAddressPoolID).To(BeEmpty())
+	g.Expect(newMachineScope(infrav1.BackendPoolTypeIP).BackendAddressSpecs()).To(Equal([]azure.ResourceSpecGetter{
+		&backendaddresses.BackendAddressSpec{
+			Name:             "my-azure-machine",
+			PoolName:         "my-cluster-pool",
+			LoadBalancerName: "shared-lb",
+			ResourceGroup:    "network-rg",
+			IPAddress:        "10.0.0.4",
+			VNetID:           "/subscriptions/123/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+		},
+	}))
+}
+
+func TestBackendPoolDrain(t *testing.T) {
+	g := NewWithT(t)
+	machineScope := &MachineScope{
+		AzureMachine: &infrav1.AzureMachine{},
+	}
+	g.Expect(machineScope.BackendPoolDrainTimeout()).To(BeZero())
+
+	machineScope.AzureMachine.Spec.BackendPoolDrainTimeout = &metav1.Duration{Duration: time.Minute}
+	g.Expect(machineScope.BackendPoolDrainTimeout()).To(Equal(time.Minute))
+	g.Expect(machineScope.BackendPoolsDrained()).To(BeFalse())
+
+	// the drain starts the first time its start time is requested.
+	start := machineScope.BackendPoolDrainStartTime()
+	g.Expect(start).To(BeTemporally("~", time.Now(), 5*time.Second))
+	g.Expect(conditions.GetReason(machineScope.AzureMachine, infrav1.BackendPoolsDrainedCondition)).To(Equal(infrav1.DrainingReason))
+	g.Expect(machineScope.BackendPoolDrainStartTime()).To(Equal(start))
+
+	machineScope.SetBackendPoolsDrained()
+	g.Expect(machineScope.BackendPoolsDrained()).To(BeTrue())
+}
+
+func TestTombstone(t *testing.T) {
+	g := NewWithT(t)
+	machineScope := &MachineScope{
+		AzureMachine: &infrav1.AzureMachine{},
+	}
+	g.Expect(machineScope.TombstonePolicy()).To(BeNil())
+	g.Expect(machineScope.TombstoneExpired()).To(BeFalse())
+
+	deleteAfter := time.Date(2022, time.June, 1, 10, 0, 0, 0, time.UTC)
+	machineScope.SetTombstoned(deleteAfter)
+	g.Expect(machineScope.TombstoneExpired()).To(BeFalse())
+	g.Expect(conditions.GetReason(machineScope.AzureMachine, infrav1.TombstoneExpiredCondition)).To(Equal(infrav1.TombstonedReason))
+	g.Expect(conditions.GetMessage(machineScope.AzureMachine, infrav1.TombstoneExpiredCondition)).To(Equal("virtual machine is deleted after 2022-06-01T10:00:00Z"))
+
+	machineScope.SetTombstoneExpired()
+	g.Expect(machineScope.TombstoneExpired()).To(BeTrue())
+}
+
+func TestSetEtcdDataDiskCondition(t *testing.T) {
+	vmSKU := resourceskus.SKU{
+		Name: to.Ptr("Standard_B2s"),
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
+			{
+				Name:  to.Ptr(resourceskus.UncachedDiskIOPS),
+				Value: to.Ptr("1280"),
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		etcdDisk   *infrav1.EtcdDataDisk
+		wantStatus corev1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:     "no condition without an etcd data disk",
+			etcdDisk: nil,
+		},
+		{
+			name:       "Premium SSD disk within the IOPS of the VM size",
+			etcdDisk:   &infrav1.EtcdDataDisk{},
+			wantStatus: corev1.ConditionTrue,
+		},
+		{
+			name:       "Premium SSD disk above the IOPS of the VM size",
+			etcdDisk:   &infrav1.EtcdDataDisk{DiskSizeGB: 512},
+			wantStatus: corev1.ConditionFalse,
+			wantReason: infrav1.EtcdDataDiskThrottledReason,
+		},
+		{
+			name:       "Standard SSD disk",
+			etcdDisk:   &infrav1.EtcdDataDisk{StorageAccountType: "StandardSSD_LRS"},
+			wantStatus: corev1.ConditionFalse,
+			wantReason: infrav1.EtcdDataDiskLatencyNotGuaranteedReason,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := &MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					Spec: infrav1.AzureMachineSpec{
+						VMSize:       "Standard_B2s",
+						EtcdDataDisk: tc.etcdDisk,
+					},
+				},
+				cache: &MachineCache{
+					VMSKU: vmSKU,
+				},
+			}
+			machineScope.SetEtcdDataDiskCondition()
+
+			condition := conditions.Get(machineScope.AzureMachine, infrav1.EtcdDataDiskPerformanceCondition)
+			if tc.wantStatus == "" {
+				g.Expect(condition).To(BeNil())
+				return
+			}
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(tc.wantStatus))
+			g.Expect(condition.Reason).To(Equal(tc.wantReason))
+			if tc.wantStatus == corev1.ConditionFalse {
+				g.Expect(condition.Severity).To(Equal(clusterv1.ConditionSeverityWarning))
+			}
+		})
+	}
+}
@@ -19,22 +19,28 @@ package scope
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	machinepool "sigs.k8s.io/cluster-api-provider-azure/azure/scope/strategies/machinepool_deployments"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/autoscalesettings"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cron"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -52,6 +58,10 @@ import (
 // added here to avoid a circular dependency.
 const ScalesetsServiceName = "scalesets"
 
+// zonalCapacityBackoff is how long an autoscaler should prefer other node groups than a MachinePool after its scale set
+// failed to allocate VMs in a zone, matching the initial node group backoff of the cluster-autoscaler.
+const zonalCapacityBackoff = 5 * time.Minute
+
 type (
 	// MachinePoolScopeParams defines the input parameters used to create a new MachinePoolScope.
 	MachinePoolScopeParams struct {
@@ -69,6 +79,15 @@ type (
 		client           client.Client
 		patchHelper      *patch.Helper
 		vmssState        *azure.VMSS
+		cache            *MachinePoolCache
+
+		clusterPowerState infrav1.PowerState
+	}
+
+	// MachinePoolCache stores common machine pool information so we don't have to hit the API multiple times within
+	// the same reconcile loop.
+	MachinePoolCache struct {
+		BootstrapData string
 	}
 
 	// NodeStatus represents the status of a Kubernetes node.
@@ -109,11 +128,24 @@ func NewMachinePoolScope(params MachinePoolScopeParams) (*MachinePoolScope, erro
 
 // ScaleSetSpec returns the scale set spec.
 func (m *MachinePoolScope) ScaleSetSpec() azure.ScaleSetSpec {
+	replicas := m.DesiredReplicas()
+	var minCapacity, maxCapacity *int64
+	if window := m.scalingScheduleWindow(); window != nil {
+		replicas = boundReplicas(replicas, window)
+		minCapacity = to.Ptr[int64](int64(window.MinReplicas + m.WarmPoolSize()))
+		maxCapacity = to.Ptr[int64](int64(window.MaxReplicas + m.WarmPoolSize()))
+	}
+	vmSize, zones := m.placement()
+	sasExpiry, _ := m.GetAnnotation(azure.BootstrapDataSASExpiryAnnotation)
+
 	return azure.ScaleSetSpec{
 		Name:                         m.Name(),
-		Size:                         m.AzureMachinePool.Spec.Template.VMSize,
-		Capacity:                     int64(to.Int32(m.MachinePool.Spec.Replicas)),
+		Size:                         vmSize,
+		Capacity:                     int64(replicas + m.WarmPoolSize()),
+		MinCapacity:                  minCapacity,
+		MaxCapacity:                  maxCapacity,
 		SSHKeyData:                   m.AzureMachinePool.Spec.Template.SSHPublicKey,
+		AdditionalSSHKeyData:         m.AzureMachinePool.Spec.Template.AdditionalSSHPublicKeys,
 		OSDisk:                       m.AzureMachinePool.Spec.Template.OSDisk,
 		DataDisks:                    m.AzureMachinePool.Spec.Template.DataDisks,
 		SubnetName:                   m.AzureMachinePool.Spec.Template.SubnetName,
@@ -126,12 +158,70 @@ func (m *MachinePoolScope) ScaleSetSpec() azure.ScaleSetSpec {
 		UserAssignedIdentities:       m.AzureMachinePool.Spec.UserAssignedIdentities,
 		SecurityProfile:              m.AzureMachinePool.Spec.Template.SecurityProfile,
 		SpotVMOptions:                m.AzureMachinePool.Spec.Template.SpotVMOptions,
-		FailureDomains:               m.MachinePool.Spec.FailureDomains,
+		AdditionalCapabilities:       m.AzureMachinePool.Spec.Template.AdditionalCapabilities,
+		FailureDomains:               zones,
 		TerminateNotificationTimeout: m.AzureMachinePool.Spec.Template.TerminateNotificationTimeout,
 		NetworkInterfaces:            m.AzureMachinePool.Spec.Template.NetworkInterfaces,
+		Autoscaled:                   m.AzureMachinePool.Spec.Autoscale != nil,
+		RolloutModelUpdates:          m.RolloutModelUpdates(),
+		WarmPoolSize:                 int64(m.WarmPoolSize()),
+		BootstrapDataSASExpiry:       sasExpiry,
 	}
 }
 
+// placement returns the VM size and the availability zones of the scale set: the ones of the placement fallback in
+// use, or the VM size of the template and the failure domains of the MachinePool.
+func (m *MachinePoolScope) placement() (string, []string) {
+	vmSize, zones := m.AzureMachinePool.Spec.Template.VMSize, m.MachinePool.Spec.FailureDomains
+	status := m.AzureMachinePool.Status.Placement
+	if status == nil || status.Fallback == nil || int(*status.Fallback) >= len(m.AzureMachinePool.Spec.PlacementFallbacks) {
+		return vmSize, zones
+	}
+
+	fallback := m.AzureMachinePool.Spec.PlacementFallbacks[*status.Fallback]
+	if fallback.VMSize != "" {
+		vmSize = fallback.VMSize
+	}
+	if len(fallback.Zones) > 0 {
+		zones = fallback.Zones
+	}
+	return vmSize, zones
+}
+
+// FallBackPlacement moves the scale set to the next placement fallback after a failure to allocate its VMs, or back to
+// the VM size of the template and the failure domains of the MachinePool after the last one. It returns false if the
+// AzureMachinePool has no placement fallbacks.
+func (m *MachinePoolScope) FallBackPlacement(ctx context.Context, reason error) bool {
+	_, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.FallBackPlacement")
+	defer done()
+
+	fallbacks := m.AzureMachinePool.Spec.PlacementFallbacks
+	if len(fallbacks) == 0 {
+		return false
+	}
+
+	var next *int32
+	switch current := m.AzureMachinePool.Status.Placement; {
+	case current == nil || current.Fallback == nil:
+		next = to.Ptr[int32](0)
+	case int(*current.Fallback)+1 < len(fallbacks):
+		next = to.Ptr[int32](*current.Fallback + 1)
+	}
+
+	now := metav1.Now()
+	m.AzureMachinePool.Status.Placement = &infrav1exp.AzureMachinePoolPlacementStatus{
+		Fallback:              next,
+		LastAllocationFailure: reason.Error(),
+		LastTransitionTime:    &now,
+	}
+	vmSize, zones := m.placement()
+	m.AzureMachinePool.Status.Placement.VMSize = vmSize
+	m.AzureMachinePool.Status.Placement.Zones = zones
+
+	log.Info("falling back to the next placement after an allocation failure", "vmSize", vmSize, "zones", zones, "reason", reason.Error())
+	return true
+}
+
 // Name returns the Azure Machine Pool Name.
 func (m *MachinePoolScope) Name() string {
 	// Windows Machine pools names cannot be longer than 9 chars
@@ -180,13 +270,44 @@ func (m *MachinePoolScope) NeedsRequeue() bool {
 		return true
 	}
 
-	desiredMatchesActual := len(m.vmssState.Instances) == int(m.DesiredReplicas())
+	if m.RolloutModelUpdates() && m.vmssState.OutdatedInstanceCount() > 0 {
+		return true
+	}
+
+	for _, instance := range m.vmssState.Instances {
+		if m.vmssState.HasDataDiskChanges(instance) {
+			return true
+		}
+	}
+
+	desiredMatchesActual := len(m.vmssState.Instances) == int(m.DesiredReplicas()+m.WarmPoolSize())
 	return !(state != nil && infrav1.IsTerminalProvisioningState(*state) && desiredMatchesActual)
 }
 
+// RolloutModelUpdates returns true if the machines that run the latest image but not the latest VMSS model should be
+// replaced per the rolling update strategy.
+func (m *MachinePoolScope) RolloutModelUpdates() bool {
+	rollingUpdate := m.AzureMachinePool.Spec.Strategy.RollingUpdate
+	return rollingUpdate != nil && rollingUpdate.RolloutModelUpdates
+}
+
 // DesiredReplicas returns the replica count on machine pool or 0 if machine pool replicas is nil.
 func (m MachinePoolScope) DesiredReplicas() int32 {
-	return to.Int32(m.MachinePool.Spec.Replicas)
+	return pointer.Int32Deref(m.MachinePool.Spec.Replicas, 0)
+}
+
+// WarmPoolSize returns the number of deallocated instances to keep in the scale set in addition to its replicas, or 0
+// if the AzureMachinePool has no warm pool.
+func (m MachinePoolScope) WarmPoolSize() int32 {
+	if m.AzureMachinePool.Spec.WarmPool == nil {
+		return 0
+	}
+	return m.AzureMachinePool.Spec.WarmPool.Size
+}
+
+// ReplicaProviderIDs returns the provider IDs of the scale set instances backing the replicas of the AzureMachinePool.
+func (m *MachinePoolScope) ReplicaProviderIDs() []string {
+	return m.AzureMachinePool.Spec.ProviderIDList
 }
 
 // MaxSurge returns the number of machines to surge, or 0 if the deployment strategy does not support surge.
@@ -204,7 +325,7 @@ func (m MachinePoolScope) MaxSurge() (int, error) {
 }
 
 // updateReplicasAndProviderIDs ties the Azure VMSS instance data and the Node status data together to build and update
-// the AzureMachinePool replica count and providerIDList.
+// the AzureMachinePool replica count, providerIDList and instance statuses.
 func (m *MachinePoolScope) updateReplicasAndProviderIDs(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.UpdateInstanceStatuses")
 	defer done()
@@ -225,9 +346,40 @@ func (m *MachinePoolScope) updateReplicasAndProviderIDs(ctx context.Context) err
 
 	m.AzureMachinePool.Status.Replicas = readyReplicas
 	m.AzureMachinePool.Spec.ProviderIDList = providerIDs
+	if m.vmssState != nil {
+		m.AzureMachinePool.Status.OutdatedReplicas = m.vmssState.OutdatedInstanceCount()
+		m.AzureMachinePool.Status.Instances = m.instanceStatuses(machines)
+	}
 	return nil
 }
 
+// instanceStatuses builds the status of each VMSS instance, including the Kubernetes version of its node when known.
+func (m *MachinePoolScope) instanceStatuses(machines []infrav1exp.AzureMachinePoolMachine) []*infrav1exp.AzureMachinePoolInstanceStatus {
+	versionsByProviderID := make(map[string]string, len(machines))
+	for _, machine := range machines {
+		versionsByProviderID[machine.Spec.ProviderID] = machine.Status.Version
+	}
+
+	statuses := make([]*infrav1exp.AzureMachinePoolInstanceStatus, len(m.vmssState.Instances))
+	for i, instance := range m.vmssState.Instances {
+		instance := instance
+		statuses[i] = &infrav1exp.AzureMachinePoolInstanceStatus{
+			Version:              versionsByProviderID[instance.ProviderID()],
+			ProvisioningState:    &instance.State,
+			ProviderID:           instance.ProviderID(),
+			InstanceID:           instance.InstanceID,
+			InstanceName:         instance.Name,
+			LatestModelApplied:   m.vmssState.HasLatestModelApplied(instance) && instance.LatestModelApplied,
+			PowerState:           instance.PowerState,
+			FaultDomain:          instance.FaultDomain,
+			ImageVersion:         instance.ImageVersion,
+			ProtectedFromScaleIn: instance.ProtectFromScaleIn,
+		}
+	}
+
+	return statuses
+}
+
 func (m *MachinePoolScope) getMachinePoolMachines(ctx context.Context) ([]infrav1exp.AzureMachinePoolMachine, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.getMachinePoolMachines")
 	defer done()
@@ -270,13 +422,21 @@ func (m *MachinePoolScope) applyAzureMachinePoolMachines(ctx context.Context) er
 
 	// determine which machines need to be created to reflect the current state in Azure
 	azureMachinesByProviderID := m.vmssState.InstancesByProviderID()
+	var newInstances []azure.VMSSVM
 	for key, val := range azureMachinesByProviderID {
 		if _, ok := existingMachinesByProviderID[key]; !ok {
-			log.V(4).Info("creating AzureMachinePoolMachine", "providerID", key)
-			if err := m.createMachine(ctx, val); err != nil {
-				return errors.Wrap(err, "failed creating AzureMachinePoolMachine")
-			}
-			continue
+			newInstances = append(newInstances, val)
+		}
+	}
+
+	if m.WarmPoolSize() > 0 {
+		newInstances = m.selectInstancesToAdopt(newInstances, len(existingMachinesByProviderID))
+	}
+
+	for _, instance := range newInstances {
+		log.V(4).Info("creating AzureMachinePoolMachine", "providerID", instance.ProviderID())
+		if err := m.createMachine(ctx, instance); err != nil {
+			return errors.Wrap(err, "failed creating AzureMachinePoolMachine")
 		}
 	}
 
@@ -312,6 +472,12 @@ func (m *MachinePoolScope) applyAzureMachinePoolMachines(ctx context.Context) er
 		return nil
 	}
 
+	// the autoscale setting of the scale set removes its instances itself when scaling in
+	if m.AzureMachinePool.Spec.Autoscale != nil {
+		log.V(4).Info("Avoiding AzureMachinePoolMachine deletion as the scale set is autoscaled")
+		return nil
+	}
+
 	deleteSelector := m.getDeploymentStrategy()
 	if deleteSelector == nil {
 		log.V(4).Info("can not select AzureMachinePoolMachines to delete because no deployment strategy is specified")
@@ -354,7 +520,7 @@ func (m *MachinePoolScope) createMachine(ctx context.Context, machine azure.VMSS
 					APIVersion:         infrav1exp.GroupVersion.String(),
 					Kind:               "AzureMachinePool",
 					Name:               m.AzureMachinePool.Name,
-					BlockOwnerDeletion: to.BoolPtr(true),
+					BlockOwnerDeletion: to.Ptr(true),
 					UID:                m.AzureMachinePool.UID,
 				},
 			},
@@ -474,13 +640,15 @@ func (m *MachinePoolScope) SetBootstrapConditions(ctx context.Context, provision
 }
 
 // AdditionalTags merges AdditionalTags from the scope's AzureCluster and AzureMachinePool. If the same key is present in both,
-// the value from AzureMachinePool takes precedence.
+// the value from AzureMachinePool takes precedence. The mandatory tags of the tagging policy take precedence over both.
 func (m *MachinePoolScope) AdditionalTags() infrav1.Tags {
 	tags := make(infrav1.Tags)
 	// Start with the cluster-wide tags...
 	tags.Merge(m.ClusterScoper.AdditionalTags())
 	// ... and merge in the Machine Pool's
 	tags.Merge(m.AzureMachinePool.Spec.AdditionalTags)
+	// ... the mandatory tags of the tagging policy take precedence
+	tags.Merge(azure.MandatoryTags(m.ClusterName()))
 	// Set the cloud provider tag
 	tags[infrav1.ClusterAzureCloudProviderTagKey(m.ClusterName())] = string(infrav1.ResourceLifecycleOwned)
 
@@ -504,6 +672,37 @@ func (m *MachinePoolScope) GetAnnotation(key string) (string, bool) {
 	return value, ok
 }
 
+// DesiredPowerState returns the power state the instances of the scale set should be kept in, as required by the
+// hibernation of the cluster, or an empty power state if they are left as they are.
+func (m *MachinePoolScope) DesiredPowerState() infrav1.PowerState {
+	return m.clusterPowerState
+}
+
+// SetClusterPowerState sets the power state of the instances of the scale set required by the hibernation of the
+// cluster.
+func (m *MachinePoolScope) SetClusterPowerState(v infrav1.PowerState) {
+	m.clusterPowerState = v
+}
+
+// IsHibernated returns true if the instances of the scale set were deallocated by the hibernation of the cluster and
+// were not all started again yet.
+func (m *MachinePoolScope) IsHibernated() bool {
+	_, ok := m.AzureMachinePool.Annotations[infrav1exp.HibernatedAnnotation]
+	return ok
+}
+
+// SetHibernated marks the instances of the scale set as deallocated by the hibernation of the cluster, or unmarks them.
+func (m *MachinePoolScope) SetHibernated(hibernated bool) {
+	if !hibernated {
+		delete(m.AzureMachinePool.Annotations, infrav1exp.HibernatedAnnotation)
+		return
+	}
+	if m.AzureMachinePool.Annotations == nil {
+		m.AzureMachinePool.Annotations = map[string]string{}
+	}
+	m.AzureMachinePool.Annotations[infrav1exp.HibernatedAnnotation] = ""
+}
+
 // PatchObject persists the machine spec and status.
 func (m *MachinePoolScope) PatchObject(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.PatchObject")
@@ -512,6 +711,28 @@ func (m *MachinePoolScope) PatchObject(ctx context.Context) error {
 	return m.patchHelper.Patch(ctx, m.AzureMachinePool)
 }
 
+// selectInstancesToAdopt returns the instances of a scale set with a warm pool that should back new replicas. Warm
+// instances are skipped, and at most as many of the oldest instances are adopted as replicas are missing.
+func (m *MachinePoolScope) selectInstancesToAdopt(instances []azure.VMSSVM, existing int) []azure.VMSSVM {
+	missing := int(m.DesiredReplicas()) - existing
+	if missing <= 0 {
+		return nil
+	}
+
+	var running []azure.VMSSVM
+	for _, instance := range instances {
+		if !instance.IsDeallocated() {
+			running = append(running, instance)
+		}
+	}
+
+	azure.SortByInstanceID(running)
+	if len(running) > missing {
+		running = running[:missing]
+	}
+	return running
+}
+
 // UpdateScaleSetReplicas patches the AzureMachinePool Spec with the correct Replica count.
 func (m *MachinePoolScope) UpdateScaleSetReplicas(ctx context.Context, fetchedVMSS *azure.VMSS) error {
 	helper, err := patch.NewHelper(m.MachinePool, m.client)
@@ -519,12 +740,264 @@ func (m *MachinePoolScope) UpdateScaleSetReplicas(ctx context.Context, fetchedVM
 		return errors.Wrap(err, "failed to init patch helper")
 	}
 
-	capacity := int32(fetchedVMSS.Capacity)
+	// the warm pool is part of the capacity of the scale set but does not count as replicas.
+	capacity := int32(fetchedVMSS.Capacity) - m.WarmPoolSize()
+	if capacity < 0 {
+		capacity = 0
+	}
 	m.MachinePool.Spec.Replicas = &capacity
+	// keep the replicas set through the scale subresource from scaling the MachinePool back.
+	if m.AzureMachinePool.Spec.Replicas != nil {
+		m.AzureMachinePool.Spec.Replicas = to.Ptr[int32](capacity)
+	}
+
+	return helper.Patch(ctx, m.MachinePool)
+}
+
+// BackOffZones records on the MachinePool that its scale set failed to allocate VMs in zones for lack of capacity, so
+// that an autoscaler prefers other node groups for zonalCapacityBackoff. The backoffs of other zones are kept until
+// they expire.
+func (m *MachinePoolScope) BackOffZones(ctx context.Context, zones []string, now time.Time) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.BackOffZones")
+	defer done()
+
+	backoffs := map[string]string{}
+	if value, ok := m.MachinePool.GetAnnotations()[azure.ZonalCapacityBackoffAnnotation]; ok {
+		if err := json.Unmarshal([]byte(value), &backoffs); err != nil {
+			log.V(2).Info("discarding malformed zonal capacity backoff annotation", "value", value, "error", err.Error())
+			backoffs = map[string]string{}
+		}
+	}
+	for zone, expiry := range backoffs {
+		if t, err := time.Parse(time.RFC3339, expiry); err != nil || !t.After(now) {
+			delete(backoffs, zone)
+		}
+	}
+	until := now.Add(zonalCapacityBackoff)
+	for _, zone := range zones {
+		backoffs[zone] = until.UTC().Format(time.RFC3339)
+	}
+
+	value, err := json.Marshal(backoffs)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the zonal capacity backoffs")
+	}
+
+	helper, err := patch.NewHelper(m.MachinePool, m.client)
+	if err != nil {
+		return errors.Wrap(err, "failed to init patch helper")
+	}
+
+	annotations := m.MachinePool.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[azure.ZonalCapacityBackoffAnnotation] = string(value)
+	m.MachinePool.SetAnnotations(annotations)
+
+	log.Info("backing off zones after a zonal allocation failure", "zones", zones, "until", until)
+	return helper.Patch(ctx, m.MachinePool)
+}
+
+// ReconcileReplicas scales the MachinePool to the replicas set through the scale subresource of the AzureMachinePool,
+// and sets the label selector the scale subresource reports.
+func (m *MachinePoolScope) ReconcileReplicas(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.ReconcileReplicas")
+	defer done()
+
+	m.AzureMachinePool.Status.Selector = labels.SelectorFromSet(labels.Set{
+		clusterv1.ClusterLabelName:      m.ClusterName(),
+		infrav1exp.MachinePoolNameLabel: m.AzureMachinePool.Name,
+	}).String()
+
+	replicas := m.AzureMachinePool.Spec.Replicas
+	if replicas == nil || (m.MachinePool.Spec.Replicas != nil && *m.MachinePool.Spec.Replicas == *replicas) {
+		return nil
+	}
+
+	helper, err := patch.NewHelper(m.MachinePool, m.client)
+	if err != nil {
+		return errors.Wrap(err, "failed to init patch helper")
+	}
+
+	log.Info("scaling MachinePool to the replicas of the AzureMachinePool", "replicas", *replicas, "machinePoolReplicas", m.MachinePool.Spec.Replicas)
+	m.MachinePool.Spec.Replicas = to.Ptr[int32](*replicas)
 
 	return helper.Patch(ctx, m.MachinePool)
 }
 
+// ReconcileScalingSchedule bounds the replicas of the AzureMachinePool by the active window of its scaling schedule.
+// When the replicas are managed by an autoscaler, the bounds of the window are set as the cluster-autoscaler node
+// group size of the MachinePool instead, and the scale set is only brought back within them if it is outside.
+func (m *MachinePoolScope) ReconcileScalingSchedule(ctx context.Context, now time.Time) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.ReconcileScalingSchedule")
+	defer done()
+
+	entry, start, err := activeScalingScheduleEntry(m.AzureMachinePool.Spec.ScalingSchedule, now)
+	if err != nil {
+		return errors.Wrap(err, "failed to find the active window of the scaling schedule")
+	}
+
+	status := m.AzureMachinePool.Status.ScalingSchedule
+	if entry == nil {
+		if status != nil && status.AutoscalerSizesOverridden {
+			if err := m.restoreAutoscalerSizes(ctx); err != nil {
+				return err
+			}
+		}
+		m.AzureMachinePool.Status.ScalingSchedule = nil
+		return nil
+	}
+
+	if status == nil {
+		status = &infrav1exp.AzureMachinePoolScalingScheduleStatus{}
+		m.AzureMachinePool.Status.ScalingSchedule = status
+	}
+	if status.ActiveEntry != entry.Name || status.WindowStartTime == nil || !status.WindowStartTime.Time.Equal(start) {
+		log.V(2).Info("scaling schedule window started", "entry", entry.Name, "minReplicas", entry.MinReplicas, "maxReplicas", entry.MaxReplicas)
+	}
+	status.ActiveEntry = entry.Name
+	status.WindowStartTime = &metav1.Time{Time: start}
+
+	if value, _ := m.GetAnnotation(azure.ReplicasManagedByAutoscalerAnnotation); value == "true" {
+		// the replicas follow the capacity of the scale set, which the scalesets service brings within the bounds
+		return m.overrideAutoscalerSizes(ctx, entry)
+	}
+
+	if status.AutoscalerSizesOverridden {
+		if err := m.restoreAutoscalerSizes(ctx); err != nil {
+			return err
+		}
+	}
+
+	current := m.DesiredReplicas()
+	if m.AzureMachinePool.Spec.Replicas != nil {
+		current = *m.AzureMachinePool.Spec.Replicas
+	}
+	if bounded := boundReplicas(current, entry); bounded != current {
+		log.V(2).Info("scaling to the bounds of the scaling schedule window", "entry", entry.Name, "replicas", bounded)
+		m.AzureMachinePool.Spec.Replicas = to.Ptr[int32](bounded)
+	}
+
+	return nil
+}
+
+// overrideAutoscalerSizes sets the bounds of a scaling schedule window as the cluster-autoscaler node group size of the
+// MachinePool, saving the original annotations the first time.
+func (m *MachinePoolScope) overrideAutoscalerSizes(ctx context.Context, entry *infrav1exp.ScalingScheduleEntry) error {
+	status := m.AzureMachinePool.Status.ScalingSchedule
+	annotations := m.MachinePool.GetAnnotations()
+	minSize, maxSize := strconv.Itoa(int(entry.MinReplicas)), strconv.Itoa(int(entry.MaxReplicas))
+	if status.AutoscalerSizesOverridden && annotations[azure.AutoscalerMinSizeAnnotation] == minSize && annotations[azure.AutoscalerMaxSizeAnnotation] == maxSize {
+		return nil
+	}
+
+	helper, err := patch.NewHelper(m.MachinePool, m.client)
+	if err != nil {
+		return errors.Wrap(err, "failed to init patch helper")
+	}
+
+	if !status.AutoscalerSizesOverridden {
+		status.OriginalAutoscalerSizes = map[string]string{}
+		for _, key := range []string{azure.AutoscalerMinSizeAnnotation, azure.AutoscalerMaxSizeAnnotation} {
+			if value, ok := annotations[key]; ok {
+				status.OriginalAutoscalerSizes[key] = value
+			}
+		}
+		status.AutoscalerSizesOverridden = true
+	}
+
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[azure.AutoscalerMinSizeAnnotation] = minSize
+	annotations[azure.AutoscalerMaxSizeAnnotation] = maxSize
+	m.MachinePool.SetAnnotations(annotations)
+
+	return helper.Patch(ctx, m.MachinePool)
+}
+
+// restoreAutoscalerSizes restores the cluster-autoscaler node group size annotations of the MachinePool overridden by
+// a scaling schedule window.
+func (m *MachinePoolScope) restoreAutoscalerSizes(ctx context.Context) error {
+	status := m.AzureMachinePool.Status.ScalingSchedule
+	helper, err := patch.NewHelper(m.MachinePool, m.client)
+	if err != nil {
+		return errors.Wrap(err, "failed to init patch helper")
+	}
+
+	annotations := m.MachinePool.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for _, key := range []string{azure.AutoscalerMinSizeAnnotation, azure.AutoscalerMaxSizeAnnotation} {
+		if value, ok := status.OriginalAutoscalerSizes[key]; ok {
+			annotations[key] = value
+		} else {
+			delete(annotations, key)
+		}
+	}
+	m.MachinePool.SetAnnotations(annotations)
+	if err := helper.Patch(ctx, m.MachinePool); err != nil {
+		return err
+	}
+
+	status.AutoscalerSizesOverridden = false
+	status.OriginalAutoscalerSizes = nil
+	return nil
+}
+
+// activeScalingScheduleEntry returns the entry of a scaling schedule whose schedule fired last, and when it fired, or
+// nil if none fired during the previous year.
+func activeScalingScheduleEntry(entries []infrav1exp.ScalingScheduleEntry, now time.Time) (*infrav1exp.ScalingScheduleEntry, time.Time, error) {
+	var (
+		active *infrav1exp.ScalingScheduleEntry
+		start  time.Time
+	)
+	for i := range entries {
+		entry := &entries[i]
+		schedule, err := cron.Parse(entry.Schedule)
+		if err != nil {
+			return nil, time.Time{}, errors.Wrapf(err, "invalid schedule for entry %s", entry.Name)
+		}
+		loc, err := time.LoadLocation(entry.TimeZone)
+		if err != nil {
+			return nil, time.Time{}, errors.Wrapf(err, "invalid time zone for entry %s", entry.Name)
+		}
+
+		if prev, ok := schedule.Prev(now.In(loc)); ok && (active == nil || prev.After(start)) {
+			active, start = entry, prev
+		}
+	}
+
+	return active, start.UTC(), nil
+}
+
+// boundReplicas returns replicas within the bounds of a scaling schedule window.
+func boundReplicas(replicas int32, entry *infrav1exp.ScalingScheduleEntry) int32 {
+	if replicas < entry.MinReplicas {
+		return entry.MinReplicas
+	}
+	if replicas > entry.MaxReplicas {
+		return entry.MaxReplicas
+	}
+	return replicas
+}
+
+// scalingScheduleWindow returns the entry of the scaling schedule whose window is active, or nil.
+func (m *MachinePoolScope) scalingScheduleWindow() *infrav1exp.ScalingScheduleEntry {
+	status := m.AzureMachinePool.Status.ScalingSchedule
+	if status == nil {
+		return nil
+	}
+	for i, entry := range m.AzureMachinePool.Spec.ScalingSchedule {
+		if entry.Name == status.ActiveEntry {
+			return &m.AzureMachinePool.Spec.ScalingSchedule[i]
+		}
+	}
+	return nil
+}
+
 // Close the MachineScope by updating the machine spec, machine status.
 func (m *MachinePoolScope) Close(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.Close")
@@ -545,11 +1018,151 @@ func (m *MachinePoolScope) Close(ctx context.Context) error {
 	return m.patchHelper.Patch(ctx, m.AzureMachinePool)
 }
 
-// GetBootstrapData returns the bootstrap data from the secret in the Machine's bootstrap.dataSecretName.
+// InitMachinePoolCache sets cached information about the machine pool to be used in the scope.
+func (m *MachinePoolScope) InitMachinePoolCache(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.InitMachinePoolCache")
+	defer done()
+
+	if m.cache == nil {
+		bootstrapData, err := m.GetBootstrapData(ctx)
+		if err != nil {
+			return err
+		}
+		m.cache = &MachinePoolCache{
+			BootstrapData: bootstrapData,
+		}
+	}
+
+	return nil
+}
+
+// BootstrapDataSpec returns the specification for delivering the bootstrap data of the scale set through a storage
+// blob, or nil if the cluster doesn't configure a bootstrap data storage.
+func (m *MachinePoolScope) BootstrapDataSpec() *azure.BootstrapDataSpec {
+	storage := m.ClusterScoper.BootstrapDataStorage()
+	if storage == nil {
+		return nil
+	}
+
+	spec := &azure.BootstrapDataSpec{
+		BlobName:           fmt.Sprintf("%s/%s", m.ClusterName(), m.Name()),
+		StorageAccountName: storage.StorageAccountName,
+		ResourceGroup:      storage.ResourceGroup,
+		ContainerName:      storage.ContainerName,
+		SASDuration:        azure.DefaultBootstrapDataSASDuration,
+		OSType:             m.AzureMachinePool.Spec.Template.OSDisk.OSType,
+	}
+	if spec.ResourceGroup == "" {
+		spec.ResourceGroup = m.ResourceGroup()
+	}
+	if spec.ContainerName == "" {
+		spec.ContainerName = azure.DefaultBootstrapDataContainerName
+	}
+	if storage.SASDuration != nil {
+		spec.SASDuration = storage.SASDuration.Duration
+	}
+	if m.cache != nil {
+		spec.BootstrapData = m.cache.BootstrapData
+	}
+	return spec
+}
+
+// SetBootstrapData replaces the bootstrap data passed to the instances of the scale set as custom data.
+func (m *MachinePoolScope) SetBootstrapData(bootstrapData string) {
+	if m.cache != nil {
+		m.cache.BootstrapData = bootstrapData
+	}
+}
+
+// BootstrapDataRequired returns true while instances of the scale set may be created from the bootstrap data: until
+// all the desired replicas of the scale set run the latest model and have run the bootstrap data, and whenever the
+// AzureMachinePool or its MachinePool changed since the bootstrap data was last uploaded. Without a bootstrap
+// extension reporting the bootstrap of the instances, they have run the bootstrap data once all their nodes exist, or
+// can't run it anymore once the SAS URL to the bootstrap data expired. The bootstrap data of scale sets whose
+// replicas are managed by an autoscaler is always required.
+func (m *MachinePoolScope) BootstrapDataRequired() bool {
+	if m.AzureMachinePool.Spec.Autoscale != nil {
+		return true
+	}
+	if value, _ := m.GetAnnotation(azure.ReplicasManagedByAutoscalerAnnotation); value == "true" {
+		return true
+	}
+	if value, _ := m.GetAnnotation(azure.BootstrapDataGenerationAnnotation); value != m.bootstrapDataGeneration() {
+		return true
+	}
+	if m.ProvisioningState() != infrav1.Succeeded ||
+		m.AzureMachinePool.Status.Replicas != m.DesiredReplicas() ||
+		!conditions.IsTrue(m.AzureMachinePool, infrav1.ScaleSetModelUpdatedCondition) ||
+		conditions.IsFalse(m.AzureMachinePool, infrav1.BootstrapSucceededCondition) {
+		return true
+	}
+	if conditions.Has(m.AzureMachinePool, infrav1.BootstrapSucceededCondition) {
+		return false
+	}
+	return len(m.MachinePool.Status.NodeRefs) < int(m.DesiredReplicas()) && time.Now().Before(m.BootstrapDataSASExpiry())
+}
+
+// BootstrapDataBlob returns the name of the blob the bootstrap data of the scale set was uploaded to, or an empty
+// string if there is none.
+func (m *MachinePoolScope) BootstrapDataBlob() string {
+	value, _ := m.GetAnnotation(azure.BootstrapDataBlobAnnotation)
+	return value
+}
+
+// SetBootstrapDataBlob records the name of the blob the bootstrap data of the scale set was uploaded to along with the
+// generations it was uploaded for, or clears it once the blob is deleted.
+func (m *MachinePoolScope) SetBootstrapDataBlob(blobName string) {
+	if blobName == "" {
+		delete(m.AzureMachinePool.Annotations, azure.BootstrapDataBlobAnnotation)
+		delete(m.AzureMachinePool.Annotations, azure.BootstrapDataHashAnnotation)
+		return
+	}
+	m.SetAnnotation(azure.BootstrapDataBlobAnnotation, blobName)
+	m.SetAnnotation(azure.BootstrapDataGenerationAnnotation, m.bootstrapDataGeneration())
+}
+
+// BootstrapDataHash returns the hash of the bootstrap data uploaded to the blob of the scale set, or an empty string
+// if there is none.
+func (m *MachinePoolScope) BootstrapDataHash() string {
+	value, _ := m.GetAnnotation(azure.BootstrapDataHashAnnotation)
+	return value
+}
+
+// SetBootstrapDataHash records the hash of the bootstrap data uploaded to the blob of the scale set.
+func (m *MachinePoolScope) SetBootstrapDataHash(hash string) {
+	m.SetAnnotation(azure.BootstrapDataHashAnnotation, hash)
+}
+
+// BootstrapDataSASExpiry returns the expiry time of the SAS URL the custom data of the scale set includes, or the
+// zero time if there is none.
+func (m *MachinePoolScope) BootstrapDataSASExpiry() time.Time {
+	value, _ := m.GetAnnotation(azure.BootstrapDataSASExpiryAnnotation)
+	expiry, _ := time.Parse(time.RFC3339, value)
+	return expiry
+}
+
+// SetBootstrapDataSASExpiry records the expiry time of the SAS URL the custom data of the scale set includes. It's
+// kept once the blob is deleted, so that the model of the scale set only changes when the URL is renewed.
+func (m *MachinePoolScope) SetBootstrapDataSASExpiry(expiry time.Time) {
+	m.SetAnnotation(azure.BootstrapDataSASExpiryAnnotation, expiry.UTC().Format(time.RFC3339))
+}
+
+// bootstrapDataGeneration returns the generations of the AzureMachinePool and its MachinePool, whose changes may
+// create instances of the scale set from its bootstrap data.
+func (m *MachinePoolScope) bootstrapDataGeneration() string {
+	return fmt.Sprintf("%d/%d", m.AzureMachinePool.Generation, m.MachinePool.Generation)
+}
+
+// GetBootstrapData returns the bootstrap data from the secret in the Machine's bootstrap.dataSecretName, or the
+// cached bootstrap data once the cache is initialized.
 func (m *MachinePoolScope) GetBootstrapData(ctx context.Context) (string, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.GetBootstrapData")
 	defer done()
 
+	if m.cache != nil {
+		return m.cache.BootstrapData, nil
+	}
+
 	dataSecretName := m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName
 	if dataSecretName == nil {
 		return "", errors.New("error retrieving bootstrap data: linked Machine Spec's bootstrap.dataSecretName is nil")
@@ -564,9 +1177,52 @@ func (m *MachinePoolScope) GetBootstrapData(ctx context.Context) (string, error)
 	if !ok {
 		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
 	}
+
+	caCertificates, err := getCACertificates(ctx, m.client, m.AzureMachinePool.Namespace, m.AzureMachinePool.Spec.Template.CACertificates)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get CA certificates for AzureMachinePool %s/%s", m.AzureMachinePool.Namespace, m.Name())
+	}
+
+	registryMirrors, err := getRegistryMirrors(ctx, m.client, m.AzureMachinePool.Namespace, m.RegistryMirrors())
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get registry mirrors for AzureMachinePool %s/%s", m.AzureMachinePool.Namespace, m.Name())
+	}
+
+	value, err = mergeBootstrapCloudConfigs(value, m.AzureMachinePool.Spec.Template.OSDisk, m.AzureMachinePool.Spec.Template.LocalStorage, m.AzureMachinePool.Spec.Template.MultiInstanceGPU, m.AzureMachinePool.Spec.Template.FileStorage, m.proxyConfig(), caCertificates, registryMirrors)
+	if err != nil {
+		return "", err
+	}
 	return base64.StdEncoding.EncodeToString(value), nil
 }
 
+// GetUserData returns the base64 encoded user data of the machine pool, or an empty string if it has none.
+func (m *MachinePoolScope) GetUserData(ctx context.Context) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.GetUserData")
+	defer done()
+
+	userData, err := getUserData(ctx, m.client, m.AzureMachinePool.Namespace, m.AzureMachinePool.Spec.Template.UserData)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get user data for AzureMachinePool %s/%s", m.AzureMachinePool.Namespace, m.Name())
+	}
+	return userData, nil
+}
+
+// GetSSHPublicKey returns the base64 encoded SSH public key of the machine pool, or the public key of the key pair
+// generated for the cluster if the machine pool has none.
+func (m *MachinePoolScope) GetSSHPublicKey(ctx context.Context) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.GetSSHPublicKey")
+	defer done()
+
+	if m.AzureMachinePool.Spec.Template.SSHPublicKey != "" {
+		return m.AzureMachinePool.Spec.Template.SSHPublicKey, nil
+	}
+	sshPublicKey, err := getClusterSSHPublicKey(ctx, m.client, m.AzureMachinePool.Namespace, m.ClusterName())
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the cluster SSH public key for AzureMachinePool %s/%s", m.AzureMachinePool.Namespace, m.Name())
+	}
+	return sshPublicKey, nil
+}
+
 // GetVMImage picks an image from the machine configuration, or uses a default one.
 func (m *MachinePoolScope) GetVMImage(ctx context.Context) (*infrav1.Image, error) {
 	_, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.GetVMImage")
@@ -577,19 +1233,22 @@ func (m *MachinePoolScope) GetVMImage(ctx context.Context) (*infrav1.Image, erro
 		return m.AzureMachinePool.Spec.Template.Image, nil
 	}
 
-	svc := virtualmachineimages.New(m)
+	svc, err := virtualmachineimages.New(m)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create virtualmachineimages service")
+	}
 
-	var (
-		err          error
-		defaultImage *infrav1.Image
-	)
+	var defaultImage *infrav1.Image
 	if m.AzureMachinePool.Spec.Template.OSDisk.OSType == azure.WindowsOS {
 		runtime := m.AzureMachinePool.Annotations["runtime"]
 		windowsServerVersion := m.AzureMachinePool.Annotations["windowsServerVersion"]
 		log.V(4).Info("No image specified for machine, using default Windows Image", "machine", m.MachinePool.GetName(), "runtime", runtime, "windowsServerVersion", windowsServerVersion)
-		defaultImage, err = svc.GetDefaultWindowsImage(ctx, m.Location(), to.String(m.MachinePool.Spec.Template.Spec.Version), runtime, windowsServerVersion)
+		defaultImage, err = svc.GetDefaultWindowsImage(ctx, m.Location(), pointer.StringDeref(m.MachinePool.Spec.Template.Spec.Version, ""), runtime, windowsServerVersion)
+	} else if m.AzureMachinePool.Spec.Template.OSDisk.Distro == infrav1.OSDistroAzureLinux {
+		log.V(4).Info("No image specified for machine, using default Azure Linux Image", "machine", m.MachinePool.GetName())
+		defaultImage, err = svc.GetDefaultAzureLinuxImage(ctx, m.Location(), pointer.StringDeref(m.MachinePool.Spec.Template.Spec.Version, ""))
 	} else {
-		defaultImage, err = svc.GetDefaultUbuntuImage(ctx, m.Location(), to.String(m.MachinePool.Spec.Template.Spec.Version))
+		defaultImage, err = svc.GetDefaultUbuntuImage(ctx, m.Location(), pointer.StringDeref(m.MachinePool.Spec.Template.Spec.Version, ""))
 	}
 
 	if err != nil {
@@ -604,6 +1263,22 @@ func (m *MachinePoolScope) SaveVMImageToStatus(image *infrav1.Image) {
 	m.AzureMachinePool.Status.Image = image
 }
 
+// SetImageReplicatedCondition sets the ImageReplicated condition of the AzureMachinePool, to true if reason is empty.
+func (m *MachinePoolScope) SetImageReplicatedCondition(reason string, severity clusterv1.ConditionSeverity, message string) {
+	if reason == "" {
+		conditions.MarkTrue(m.AzureMachinePool, infrav1.ImageReplicatedCondition)
+		return
+	}
+	conditions.MarkFalse(m.AzureMachinePool, infrav1.ImageReplicatedCondition, reason, severity, message)
+}
+
+// CrossSubscriptionResources returns the gallery image and the disk encryption sets of the AzureMachinePool which are
+// in another subscription than the cluster.
+func (m *MachinePoolScope) CrossSubscriptionResources() []azure.CrossSubscriptionResource {
+	template := m.AzureMachinePool.Spec.Template
+	return azure.CrossSubscriptionResources(m.SubscriptionID(), template.Image, template.OSDisk, template.DataDisks)
+}
+
 // RoleAssignmentSpecs returns the role assignment specs.
 func (m *MachinePoolScope) RoleAssignmentSpecs(principalID *string) []azure.ResourceSpecGetter {
 	roles := make([]azure.ResourceSpecGetter, 1)
@@ -615,11 +1290,38 @@ func (m *MachinePoolScope) RoleAssignmentSpecs(principalID *string) []azure.Reso
 			ResourceType:  azure.VirtualMachineScaleSet,
 			PrincipalID:   principalID,
 		}
-		return roles
+		return append(roles, getAcrPullRoleAssignmentSpecs(m.AttachedACRs(), principalID, azure.VirtualMachineScaleSet, m.Name(), m.ResourceGroup())...)
 	}
 	return []azure.ResourceSpecGetter{}
 }
 
+// AutoscaleSettingSpec returns the autoscale setting spec of the scale set. It returns nil if the scale set was never
+// autoscaled, and a spec without an autoscale profile if its autoscale setting needs to be deleted.
+func (m *MachinePoolScope) AutoscaleSettingSpec() azure.ResourceSpecGetter {
+	if m.AzureMachinePool.Spec.Autoscale == nil {
+		if !conditions.Has(m.AzureMachinePool, infrav1.AutoscaleSettingReadyCondition) ||
+			conditions.GetReason(m.AzureMachinePool, infrav1.AutoscaleSettingReadyCondition) == infrav1.DeletedReason {
+			return nil
+		}
+	}
+
+	return &autoscalesettings.AutoscaleSettingSpec{
+		Name:             m.Name(),
+		ResourceGroup:    m.ResourceGroup(),
+		ClusterName:      m.ClusterName(),
+		Location:         m.Location(),
+		TargetResourceID: azure.ScaleSetID(m.SubscriptionID(), m.ResourceGroup(), m.Name()),
+		Autoscale:        m.AzureMachinePool.Spec.Autoscale,
+		AdditionalTags:   m.AdditionalTags(),
+	}
+}
+
+// DataCollectionRuleAssociationSpecs returns the specs associating the scale set with the data collection rule and
+// endpoint of the cluster, or nil if the cluster isn't onboarded to Azure Monitor.
+func (m *MachinePoolScope) DataCollectionRuleAssociationSpecs() []azure.ResourceSpecGetter {
+	return getDataCollectionRuleAssociationSpecs(m, azure.ScaleSetID(m.SubscriptionID(), m.ResourceGroup(), m.Name()))
+}
+
 // RoleAssignmentResourceType returns the role assignment resource type.
 func (m *MachinePoolScope) RoleAssignmentResourceType() string {
 	return azure.VirtualMachineScaleSet
@@ -634,18 +1336,41 @@ func (m *MachinePoolScope) HasSystemAssignedIdentity() bool {
 // VMSSExtensionSpecs returns the vmss extension specs.
 func (m *MachinePoolScope) VMSSExtensionSpecs() []azure.ResourceSpecGetter {
 	var extensionSpecs = []azure.ResourceSpecGetter{}
-	bootstrapExtensionSpec := azure.GetBootstrappingVMExtension(m.AzureMachinePool.Spec.Template.OSDisk.OSType, m.CloudEnvironment(), m.Name())
+	bootstrapExtensionSpec := getBootstrappingVMExtension(m.BootstrapExtension(), m.AzureMachinePool.Spec.Template.OSDisk, m.CloudEnvironment(), m.Name())
 
 	if bootstrapExtensionSpec != nil {
+		if proxy := m.proxyConfig(); proxy != nil {
+			bootstrapExtensionSpec.ProtectedSettings["commandToExecute"] = azure.GetProxyExtensionCommand(m.AzureMachinePool.Spec.Template.OSDisk.OSType,
+				bootstrapExtensionSpec.ProtectedSettings["commandToExecute"], getProxyEnvironment(proxy))
+		}
 		extensionSpecs = append(extensionSpecs, &scalesets.VMSSExtensionSpec{
 			ExtensionSpec: *bootstrapExtensionSpec,
 			ResourceGroup: m.ResourceGroup(),
 		})
 	}
 
+	if m.Monitoring() != nil {
+		extensionSpecs = append(extensionSpecs, &scalesets.VMSSExtensionSpec{
+			ExtensionSpec: *azure.GetAzureMonitorAgentVMExtension(m.AzureMachinePool.Spec.Template.OSDisk.OSType, m.Name()),
+			ResourceGroup: m.ResourceGroup(),
+		})
+	}
+
+	if securityProfile := m.SecurityProfile(); securityProfile != nil && securityProfile.Defender != nil {
+		extensionSpecs = append(extensionSpecs, &scalesets.VMSSExtensionSpec{
+			ExtensionSpec: *azure.GetAzureSecurityAgentVMExtension(m.AzureMachinePool.Spec.Template.OSDisk.OSType, m.Name()),
+			ResourceGroup: m.ResourceGroup(),
+		})
+	}
+
 	return extensionSpecs
 }
 
+// proxyConfig returns the proxy configuration of the machine pool, or nil if the cluster has no proxy.
+func (m *MachinePoolScope) proxyConfig() *infrav1.ProxyConfig {
+	return getProxyConfig(m.ProxyConfig(), m.Vnet())
+}
+
 func (m *MachinePoolScope) getDeploymentStrategy() machinepool.TypedDeleteSelector {
 	if m.AzureMachinePool == nil {
 		return nil
@@ -679,7 +1404,7 @@ func (m *MachinePoolScope) UpdateDeleteStatus(condition clusterv1.ConditionType,
 	case err == nil:
 		conditions.MarkFalse(m.AzureMachinePool, condition, infrav1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s successfully deleted", service)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(m.AzureMachinePool, condition, infrav1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting", service)
+		conditions.MarkFalse(m.AzureMachinePool, condition, infrav1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(m.AzureMachinePool, condition, infrav1.DeletionFailedReason, clusterv1.ConditionSeverityError, "%s failed to delete. err: %s", service, err.Error())
 	}
@@ -691,7 +1416,7 @@ func (m *MachinePoolScope) UpdatePutStatus(condition clusterv1.ConditionType, se
 	case err == nil:
 		conditions.MarkTrue(m.AzureMachinePool, condition)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(m.AzureMachinePool, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating", service)
+		conditions.MarkFalse(m.AzureMachinePool, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(m.AzureMachinePool, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to create or update. err: %s", service, err.Error())
 	}
@@ -703,7 +1428,7 @@ func (m *MachinePoolScope) UpdatePatchStatus(condition clusterv1.ConditionType,
 	case err == nil:
 		conditions.MarkTrue(m.AzureMachinePool, condition)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(m.AzureMachinePool, condition, infrav1.UpdatingReason, clusterv1.ConditionSeverityInfo, "%s updating", service)
+		conditions.MarkFalse(m.AzureMachinePool, condition, infrav1.UpdatingReason, clusterv1.ConditionSeverityInfo, "%s updating%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(m.AzureMachinePool, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to update. err: %s", service, err.Error())
 	}
@@ -18,22 +18,24 @@ package scope
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
-	autorestazure "github.com/Azure/go-autorest/autorest/azure"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/autoscalesettings"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -220,7 +222,7 @@ func TestMachinePoolScope_MaxSurge(t *testing.T) {
 		{
 			Name: "default surge should be 1 regardless of replica count with no surger",
 			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool) {
-				mp.Spec.Replicas = to.Int32Ptr(3)
+				mp.Spec.Replicas = to.Ptr[int32](3)
 			},
 			Verify: func(g *WithT, surge int, err error) {
 				g.Expect(surge).To(Equal(1))
@@ -230,7 +232,7 @@ func TestMachinePoolScope_MaxSurge(t *testing.T) {
 		{
 			Name: "default surge should be 2 as specified by the surger",
 			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool) {
-				mp.Spec.Replicas = to.Int32Ptr(3)
+				mp.Spec.Replicas = to.Ptr[int32](3)
 				two := intstr.FromInt(2)
 				amp.Spec.Strategy = infrav1exp.AzureMachinePoolDeploymentStrategy{
 					Type: infrav1exp.RollingUpdateAzureMachinePoolDeploymentStrategyType,
@@ -247,7 +249,7 @@ func TestMachinePoolScope_MaxSurge(t *testing.T) {
 		{
 			Name: "default surge should be 2 (50%) of the desired replicas",
 			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool) {
-				mp.Spec.Replicas = to.Int32Ptr(4)
+				mp.Spec.Replicas = to.Ptr[int32](4)
 				fiftyPercent := intstr.FromString("50%")
 				amp.Spec.Strategy = infrav1exp.AzureMachinePoolDeploymentStrategy{
 					Type: infrav1exp.RollingUpdateAzureMachinePoolDeploymentStrategyType,
@@ -347,8 +349,8 @@ func TestMachinePoolScope_GetVMImage(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	clusterMock := mock_azure.NewMockClusterScoper(mockCtrl)
-	clusterMock.EXPECT().Authorizer().AnyTimes()
-	clusterMock.EXPECT().BaseURI().AnyTimes()
+	clusterMock.EXPECT().Token().AnyTimes()
+	clusterMock.EXPECT().CloudEnvironment().AnyTimes()
 	clusterMock.EXPECT().Location().AnyTimes()
 	clusterMock.EXPECT().SubscriptionID().AnyTimes()
 	cases := []struct {
@@ -359,7 +361,7 @@ func TestMachinePoolScope_GetVMImage(t *testing.T) {
 		{
 			Name: "should set and default the image if no image is specified for the AzureMachinePool",
 			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool) {
-				mp.Spec.Template.Spec.Version = to.StringPtr("v1.19.11")
+				mp.Spec.Template.Spec.Version = to.Ptr("v1.19.11")
 			},
 			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, vmImage *infrav1.Image, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
@@ -381,7 +383,7 @@ func TestMachinePoolScope_GetVMImage(t *testing.T) {
 		{
 			Name: "should not default or set the image on the AzureMachinePool if it already exists",
 			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool) {
-				mp.Spec.Template.Spec.Version = to.StringPtr("v1.19.11")
+				mp.Spec.Template.Spec.Version = to.Ptr("v1.19.11")
 				amp.Spec.Template.Image = &infrav1.Image{
 					Marketplace: &infrav1.AzureMarketplaceImage{
 						ImagePlan: infrav1.ImagePlan{
@@ -465,7 +467,7 @@ func TestMachinePoolScope_NeedsRequeue(t *testing.T) {
 			Name: "should requeue if the machine is not in succeeded state",
 			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool, vmss *azure.VMSS) {
 				creating := infrav1.Creating
-				mp.Spec.Replicas = to.Int32Ptr(0)
+				mp.Spec.Replicas = to.Ptr[int32](0)
 				amp.Status.ProvisioningState = &creating
 			},
 			Verify: func(g *WithT, requeue bool) {
@@ -476,7 +478,7 @@ func TestMachinePoolScope_NeedsRequeue(t *testing.T) {
 			Name: "should not requeue if the machine is in succeeded state",
 			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool, vmss *azure.VMSS) {
 				succeeded := infrav1.Succeeded
-				mp.Spec.Replicas = to.Int32Ptr(0)
+				mp.Spec.Replicas = to.Ptr[int32](0)
 				amp.Status.ProvisioningState = &succeeded
 			},
 			Verify: func(g *WithT, requeue bool) {
@@ -487,7 +489,7 @@ func TestMachinePoolScope_NeedsRequeue(t *testing.T) {
 			Name: "should requeue if the machine is in succeeded state but desired replica count does not match",
 			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool, vmss *azure.VMSS) {
 				succeeded := infrav1.Succeeded
-				mp.Spec.Replicas = to.Int32Ptr(1)
+				mp.Spec.Replicas = to.Ptr[int32](1)
 				amp.Status.ProvisioningState = &succeeded
 			},
 			Verify: func(g *WithT, requeue bool) {
@@ -498,7 +500,7 @@ func TestMachinePoolScope_NeedsRequeue(t *testing.T) {
 			Name: "should not requeue if the machine is in succeeded state but desired replica count does match",
 			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool, vmss *azure.VMSS) {
 				succeeded := infrav1.Succeeded
-				mp.Spec.Replicas = to.Int32Ptr(1)
+				mp.Spec.Replicas = to.Ptr[int32](1)
 				amp.Status.ProvisioningState = &succeeded
 				vmss.Instances = []azure.VMSSVM{
 					{
@@ -514,7 +516,7 @@ func TestMachinePoolScope_NeedsRequeue(t *testing.T) {
 			Name: "should requeue if an instance VM image does not match the VM image of the VMSS",
 			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool, vmss *azure.VMSS) {
 				succeeded := infrav1.Succeeded
-				mp.Spec.Replicas = to.Int32Ptr(1)
+				mp.Spec.Replicas = to.Ptr[int32](1)
 				amp.Status.ProvisioningState = &succeeded
 				vmss.Instances = []azure.VMSSVM{
 					{
@@ -531,6 +533,98 @@ func TestMachinePoolScope_NeedsRequeue(t *testing.T) {
 				g.Expect(requeue).To(BeTrue())
 			},
 		},
+		{
+			Name: "should not requeue if an instance does not have the latest model applied and model updates are not rolled out",
+			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool, vmss *azure.VMSS) {
+				succeeded := infrav1.Succeeded
+				mp.Spec.Replicas = to.Ptr[int32](1)
+				amp.Status.ProvisioningState = &succeeded
+				vmss.Instances = []azure.VMSSVM{
+					{
+						Name:               "instance1",
+						LatestModelApplied: false,
+					},
+				}
+			},
+			Verify: func(g *WithT, requeue bool) {
+				g.Expect(requeue).To(BeFalse())
+			},
+		},
+		{
+			Name: "should requeue if an instance does not have the latest model applied and model updates are rolled out",
+			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool, vmss *azure.VMSS) {
+				succeeded := infrav1.Succeeded
+				mp.Spec.Replicas = to.Ptr[int32](1)
+				amp.Status.ProvisioningState = &succeeded
+				amp.Spec.Strategy.RollingUpdate = &infrav1exp.MachineRollingUpdateDeployment{
+					RolloutModelUpdates: true,
+				}
+				vmss.Instances = []azure.VMSSVM{
+					{
+						Name:               "instance1",
+						LatestModelApplied: false,
+					},
+				}
+			},
+			Verify: func(g *WithT, requeue bool) {
+				g.Expect(requeue).To(BeTrue())
+			},
+		},
+		{
+			Name: "should requeue if the data disks of an instance do not match the data disks of the VMSS",
+			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool, vmss *azure.VMSS) {
+				succeeded := infrav1.Succeeded
+				mp.Spec.Replicas = to.Ptr[int32](1)
+				amp.Status.ProvisioningState = &succeeded
+				vmss.DataDisks = []azure.VMSSDataDisk{{Lun: 0, DiskSizeGB: 128}}
+				vmss.Instances = []azure.VMSSVM{
+					{
+						Name: "instance1",
+					},
+				}
+			},
+			Verify: func(g *WithT, requeue bool) {
+				g.Expect(requeue).To(BeTrue())
+			},
+		},
+		{
+			Name: "should requeue if the warm pool is not filled",
+			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool, vmss *azure.VMSS) {
+				succeeded := infrav1.Succeeded
+				mp.Spec.Replicas = to.Ptr[int32](1)
+				amp.Status.ProvisioningState = &succeeded
+				amp.Spec.WarmPool = &infrav1exp.AzureMachinePoolWarmPool{Size: 1}
+				vmss.Instances = []azure.VMSSVM{
+					{
+						Name: "instance1",
+					},
+				}
+			},
+			Verify: func(g *WithT, requeue bool) {
+				g.Expect(requeue).To(BeTrue())
+			},
+		},
+		{
+			Name: "should not requeue if the replicas and the warm pool match the instances",
+			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool, vmss *azure.VMSS) {
+				succeeded := infrav1.Succeeded
+				mp.Spec.Replicas = to.Ptr[int32](1)
+				amp.Status.ProvisioningState = &succeeded
+				amp.Spec.WarmPool = &infrav1exp.AzureMachinePoolWarmPool{Size: 1}
+				vmss.Instances = []azure.VMSSVM{
+					{
+						Name: "instance1",
+					},
+					{
+						Name:       "instance2",
+						PowerState: infrav1.PowerStateDeallocated,
+					},
+				}
+			},
+			Verify: func(g *WithT, requeue bool) {
+				g.Expect(requeue).To(BeFalse())
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -575,6 +669,65 @@ func TestMachinePoolScope_NeedsRequeue(t *testing.T) {
 	}
 }
 
+func TestMachinePoolScope_selectInstancesToAdopt(t *testing.T) {
+	instances := []azure.VMSSVM{
+		{InstanceID: "10", PowerState: infrav1.PowerStateRunning},
+		{InstanceID: "2", PowerState: infrav1.PowerStateDeallocated},
+		{InstanceID: "9", PowerState: infrav1.PowerStateStarting},
+		{InstanceID: "11", PowerState: infrav1.PowerStateDeallocating},
+	}
+
+	cases := []struct {
+		Name     string
+		Replicas int32
+		Existing int
+		Expected []string
+	}{
+		{
+			Name:     "should adopt the oldest running instances up to the missing replicas",
+			Replicas: 2,
+			Existing: 1,
+			Expected: []string{"9"},
+		},
+		{
+			Name:     "should skip warm instances",
+			Replicas: 5,
+			Existing: 0,
+			Expected: []string{"9", "10"},
+		},
+		{
+			Name:     "should not adopt instances when no replicas are missing",
+			Replicas: 1,
+			Existing: 1,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &MachinePoolScope{
+				MachinePool: &clusterv1exp.MachinePool{
+					Spec: clusterv1exp.MachinePoolSpec{
+						Replicas: to.Ptr[int32](c.Replicas),
+					},
+				},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						WarmPool: &infrav1exp.AzureMachinePoolWarmPool{Size: 2},
+					},
+				},
+			}
+
+			var adopted []string
+			for _, instance := range s.selectInstancesToAdopt(append([]azure.VMSSVM{}, instances...), c.Existing) {
+				adopted = append(adopted, instance.InstanceID)
+			}
+			g.Expect(adopted).To(Equal(c.Expected))
+		})
+	}
+}
+
 func TestMachinePoolScope_updateReplicasAndProviderIDs(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = clusterv1.AddToScheme(scheme)
@@ -583,6 +736,7 @@ func TestMachinePoolScope_updateReplicasAndProviderIDs(t *testing.T) {
 	cases := []struct {
 		Name   string
 		Setup  func(cb *fake.ClientBuilder)
+		VMSS   *azure.VMSS
 		Verify func(g *WithT, amp *infrav1exp.AzureMachinePool, err error)
 	}{
 		{
@@ -599,6 +753,50 @@ func TestMachinePoolScope_updateReplicasAndProviderIDs(t *testing.T) {
 				g.Expect(amp.Spec.ProviderIDList).To(ConsistOf("/foo/ampm0", "/foo/ampm1", "/foo/ampm2"))
 			},
 		},
+		{
+			Name: "should set the instance statuses from the vmss state",
+			Setup: func(cb *fake.ClientBuilder) {
+				machines := getReadyAzureMachinePoolMachines(1)
+				machines[0].Spec.ProviderID = "azure:///vm/0"
+				machines[0].Status.Version = "v1.22.1"
+				for _, machine := range machines {
+					obj := machine
+					cb.WithObjects(&obj)
+				}
+			},
+			VMSS: &azure.VMSS{
+				Instances: []azure.VMSSVM{
+					{
+						ID:                 "/vm/0",
+						InstanceID:         "0",
+						Name:               "instance-000000",
+						State:              infrav1.Succeeded,
+						LatestModelApplied: true,
+						PowerState:         infrav1.PowerStateRunning,
+						FaultDomain:        to.Ptr[int32](1),
+						ImageVersion:       "1.2.3",
+						ProtectFromScaleIn: true,
+					},
+				},
+			},
+			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				succeeded := infrav1.Succeeded
+				g.Expect(amp.Status.Instances).To(HaveLen(1))
+				g.Expect(*amp.Status.Instances[0]).To(Equal(infrav1exp.AzureMachinePoolInstanceStatus{
+					Version:              "v1.22.1",
+					ProvisioningState:    &succeeded,
+					ProviderID:           "azure:///vm/0",
+					InstanceID:           "0",
+					InstanceName:         "instance-000000",
+					LatestModelApplied:   true,
+					PowerState:           infrav1.PowerStateRunning,
+					FaultDomain:          to.Ptr[int32](1),
+					ImageVersion:         "1.2.3",
+					ProtectedFromScaleIn: true,
+				}))
+			},
+		},
 		{
 			Name: "should only count machines with matching machine pool label",
 			Setup: func(cb *fake.ClientBuilder) {
@@ -674,6 +872,7 @@ func TestMachinePoolScope_updateReplicasAndProviderIDs(t *testing.T) {
 					Cluster: cluster,
 				},
 				AzureMachinePool: amp,
+				vmssState:        c.VMSS,
 			}
 			err := s.updateReplicasAndProviderIDs(context.TODO())
 			c.Verify(g, s.AzureMachinePool, err)
@@ -681,6 +880,408 @@ func TestMachinePoolScope_updateReplicasAndProviderIDs(t *testing.T) {
 	}
 }
 
+func TestMachinePoolScope_ReconcileReplicas(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1exp.AddToScheme(scheme)
+	_ = infrav1exp.AddToScheme(scheme)
+
+	cases := []struct {
+		Name                string
+		MachinePoolReplicas *int32
+		Replicas            *int32
+		Expected            *int32
+	}{
+		{
+			Name:                "should not scale the MachinePool when the AzureMachinePool replicas are not set",
+			MachinePoolReplicas: to.Ptr[int32](3),
+			Expected:            to.Ptr[int32](3),
+		},
+		{
+			Name:                "should scale the MachinePool to the AzureMachinePool replicas",
+			MachinePoolReplicas: to.Ptr[int32](3),
+			Replicas:            to.Ptr[int32](5),
+			Expected:            to.Ptr[int32](5),
+		},
+		{
+			Name:     "should set the MachinePool replicas when not set",
+			Replicas: to.Ptr[int32](0),
+			Expected: to.Ptr[int32](0),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			mp := &clusterv1exp.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "mp1", Namespace: "default"},
+				Spec:       clusterv1exp.MachinePoolSpec{Replicas: tc.MachinePoolReplicas},
+			}
+			amp := &infrav1exp.AzureMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "amp1", Namespace: "default"},
+				Spec:       infrav1exp.AzureMachinePoolSpec{Replicas: tc.Replicas},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mp, amp).Build()
+			s := &MachinePoolScope{
+				client: c,
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+				},
+				MachinePool:      mp,
+				AzureMachinePool: amp,
+			}
+
+			g.Expect(s.ReconcileReplicas(context.TODO())).To(Succeed())
+			g.Expect(amp.Status.Selector).To(Equal("azuremachinepool.infrastructure.cluster.x-k8s.io/machine-pool=amp1,cluster.x-k8s.io/cluster-name=cluster1"))
+
+			got := &clusterv1exp.MachinePool{}
+			g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: mp.Name, Namespace: mp.Namespace}, got)).To(Succeed())
+			g.Expect(got.Spec.Replicas).To(Equal(tc.Expected))
+		})
+	}
+}
+
+func TestMachinePoolScope_ReconcileScalingSchedule(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1exp.AddToScheme(scheme)
+	_ = infrav1exp.AddToScheme(scheme)
+
+	schedule := []infrav1exp.ScalingScheduleEntry{
+		{Name: "workday", Schedule: "0 7 * * 1-5", MinReplicas: 2, MaxReplicas: 10},
+		{Name: "night", Schedule: "0 20 * * 1-5", MinReplicas: 0, MaxReplicas: 0},
+	}
+	// Wednesday
+	morning := time.Date(2022, 6, 15, 9, 0, 0, 0, time.UTC)
+	evening := time.Date(2022, 6, 15, 21, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		Name                  string
+		Now                   time.Time
+		MachinePoolReplicas   int32
+		Replicas              *int32
+		AutoscalerManaged     bool
+		Status                *infrav1exp.AzureMachinePoolScalingScheduleStatus
+		ExpectedReplicas      *int32
+		ExpectedActiveEntry   string
+		ExpectedAnnotations   map[string]string
+		ExpectedOriginalSizes map[string]string
+	}{
+		{
+			Name:                "should scale up to the minimum of the window",
+			Now:                 morning,
+			MachinePoolReplicas: 0,
+			ExpectedReplicas:    to.Ptr[int32](2),
+			ExpectedActiveEntry: "workday",
+		},
+		{
+			Name:                "should leave replicas within the bounds of the window",
+			Now:                 morning,
+			MachinePoolReplicas: 3,
+			Replicas:            to.Ptr[int32](5),
+			ExpectedReplicas:    to.Ptr[int32](5),
+			ExpectedActiveEntry: "workday",
+		},
+		{
+			Name:                "should scale to zero at night",
+			Now:                 evening,
+			MachinePoolReplicas: 3,
+			Replicas:            to.Ptr[int32](3),
+			ExpectedReplicas:    to.Ptr[int32](0),
+			ExpectedActiveEntry: "night",
+		},
+		{
+			Name:                "should set the bounds as the cluster-autoscaler sizes when an autoscaler manages the replicas",
+			Now:                 evening,
+			MachinePoolReplicas: 3,
+			AutoscalerManaged:   true,
+			ExpectedActiveEntry: "night",
+			ExpectedAnnotations: map[string]string{
+				azure.AutoscalerMinSizeAnnotation: "0",
+				azure.AutoscalerMaxSizeAnnotation: "0",
+			},
+			ExpectedOriginalSizes: map[string]string{
+				azure.AutoscalerMaxSizeAnnotation: "20",
+			},
+		},
+		{
+			Name:                "should restore the cluster-autoscaler sizes once the replicas are no longer managed by an autoscaler",
+			Now:                 morning,
+			MachinePoolReplicas: 3,
+			Status: &infrav1exp.AzureMachinePoolScalingScheduleStatus{
+				AutoscalerSizesOverridden: true,
+				OriginalAutoscalerSizes: map[string]string{
+					azure.AutoscalerMaxSizeAnnotation: "20",
+				},
+			},
+			ExpectedActiveEntry: "workday",
+			ExpectedAnnotations: map[string]string{
+				azure.AutoscalerMaxSizeAnnotation: "20",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			mp := &clusterv1exp.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "mp1",
+					Namespace:   "default",
+					Annotations: map[string]string{azure.AutoscalerMaxSizeAnnotation: "20"},
+				},
+				Spec: clusterv1exp.MachinePoolSpec{Replicas: to.Ptr[int32](tc.MachinePoolReplicas)},
+			}
+			amp := &infrav1exp.AzureMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "amp1", Namespace: "default"},
+				Spec: infrav1exp.AzureMachinePoolSpec{
+					Replicas:        tc.Replicas,
+					ScalingSchedule: schedule,
+				},
+				Status: infrav1exp.AzureMachinePoolStatus{ScalingSchedule: tc.Status},
+			}
+			if tc.AutoscalerManaged {
+				amp.Annotations = map[string]string{azure.ReplicasManagedByAutoscalerAnnotation: "true"}
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mp, amp).Build()
+			s := &MachinePoolScope{
+				client:           c,
+				MachinePool:      mp,
+				AzureMachinePool: amp,
+			}
+
+			g.Expect(s.ReconcileScalingSchedule(context.TODO(), tc.Now)).To(Succeed())
+			g.Expect(amp.Spec.Replicas).To(Equal(tc.ExpectedReplicas))
+			g.Expect(amp.Status.ScalingSchedule.ActiveEntry).To(Equal(tc.ExpectedActiveEntry))
+			g.Expect(amp.Status.ScalingSchedule.OriginalAutoscalerSizes).To(Equal(tc.ExpectedOriginalSizes))
+
+			if tc.ExpectedAnnotations != nil {
+				got := &clusterv1exp.MachinePool{}
+				g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: mp.Name, Namespace: mp.Namespace}, got)).To(Succeed())
+				g.Expect(got.Annotations).To(Equal(tc.ExpectedAnnotations))
+			}
+		})
+	}
+}
+
+func TestMachinePoolScope_ScaleSetSpecScalingSchedule(t *testing.T) {
+	g := NewWithT(t)
+	s := &MachinePoolScope{
+		MachinePool: &clusterv1exp.MachinePool{
+			Spec: clusterv1exp.MachinePoolSpec{Replicas: to.Ptr[int32](5)},
+		},
+		AzureMachinePool: &infrav1exp.AzureMachinePool{
+			Spec: infrav1exp.AzureMachinePoolSpec{
+				ScalingSchedule: []infrav1exp.ScalingScheduleEntry{
+					{Name: "night", Schedule: "0 20 * * *", MinReplicas: 0, MaxReplicas: 1},
+				},
+				WarmPool: &infrav1exp.AzureMachinePoolWarmPool{Size: 1},
+			},
+			Status: infrav1exp.AzureMachinePoolStatus{
+				ScalingSchedule: &infrav1exp.AzureMachinePoolScalingScheduleStatus{ActiveEntry: "night"},
+			},
+		},
+		ClusterScoper: &ClusterScope{
+			AzureCluster: &infrav1.AzureCluster{},
+		},
+	}
+
+	spec := s.ScaleSetSpec()
+	g.Expect(spec.Capacity).To(Equal(int64(2)))
+	g.Expect(spec.MinCapacity).To(Equal(to.Ptr[int64](1)))
+	g.Expect(spec.MaxCapacity).To(Equal(to.Ptr[int64](2)))
+}
+
+func TestMachinePoolScope_SetHibernated(t *testing.T) {
+	g := NewWithT(t)
+	machinePoolScope := MachinePoolScope{
+		AzureMachinePool: &infrav1exp.AzureMachinePool{},
+	}
+	g.Expect(machinePoolScope.IsHibernated()).To(BeFalse())
+
+	machinePoolScope.SetHibernated(true)
+	g.Expect(machinePoolScope.IsHibernated()).To(BeTrue())
+	g.Expect(machinePoolScope.AzureMachinePool.Annotations).To(HaveKey(infrav1exp.HibernatedAnnotation))
+
+	machinePoolScope.SetHibernated(false)
+	g.Expect(machinePoolScope.IsHibernated()).To(BeFalse())
+}
+
+func TestMachinePoolScope_FallBackPlacement(t *testing.T) {
+	g := NewWithT(t)
+	s := &MachinePoolScope{
+		MachinePool: &clusterv1exp.MachinePool{
+			Spec: clusterv1exp.MachinePoolSpec{
+				Replicas:       to.Ptr[int32](3),
+				FailureDomains: []string{"1", "2", "3"},
+			},
+		},
+		AzureMachinePool: &infrav1exp.AzureMachinePool{
+			Spec: infrav1exp.AzureMachinePoolSpec{
+				Template: infrav1exp.AzureMachinePoolMachineTemplate{VMSize: "Standard_D4s_v5"},
+				PlacementFallbacks: []infrav1exp.AzureMachinePoolPlacement{
+					{VMSize: "Standard_D4s_v4"},
+					{VMSize: "Standard_D4as_v5", Zones: []string{"1"}},
+				},
+			},
+		},
+		ClusterScoper: &ClusterScope{
+			AzureCluster: &infrav1.AzureCluster{},
+		},
+	}
+
+	spec := s.ScaleSetSpec()
+	g.Expect(spec.Size).To(Equal("Standard_D4s_v5"))
+	g.Expect(spec.FailureDomains).To(Equal([]string{"1", "2", "3"}))
+
+	g.Expect(s.FallBackPlacement(context.TODO(), errors.New("SkuNotAvailable"))).To(BeTrue())
+	g.Expect(s.AzureMachinePool.Status.Placement.Fallback).To(Equal(to.Ptr[int32](0)))
+	g.Expect(s.AzureMachinePool.Status.Placement.VMSize).To(Equal("Standard_D4s_v4"))
+	g.Expect(s.AzureMachinePool.Status.Placement.LastAllocationFailure).To(Equal("SkuNotAvailable"))
+	spec = s.ScaleSetSpec()
+	g.Expect(spec.Size).To(Equal("Standard_D4s_v4"))
+	g.Expect(spec.FailureDomains).To(Equal([]string{"1", "2", "3"}))
+
+	g.Expect(s.FallBackPlacement(context.TODO(), errors.New("ZonalAllocationFailed"))).To(BeTrue())
+	spec = s.ScaleSetSpec()
+	g.Expect(spec.Size).To(Equal("Standard_D4as_v5"))
+	g.Expect(spec.FailureDomains).To(Equal([]string{"1"}))
+	g.Expect(s.AzureMachinePool.Status.Placement.Zones).To(Equal([]string{"1"}))
+
+	// after the last fallback, the scale set goes back to its primary placement
+	g.Expect(s.FallBackPlacement(context.TODO(), errors.New("AllocationFailed"))).To(BeTrue())
+	g.Expect(s.AzureMachinePool.Status.Placement.Fallback).To(BeNil())
+	spec = s.ScaleSetSpec()
+	g.Expect(spec.Size).To(Equal("Standard_D4s_v5"))
+	g.Expect(spec.FailureDomains).To(Equal([]string{"1", "2", "3"}))
+
+	s.AzureMachinePool.Spec.PlacementFallbacks = nil
+	g.Expect(s.FallBackPlacement(context.TODO(), errors.New("AllocationFailed"))).To(BeFalse())
+}
+
+func TestMachinePoolScope_BackOffZones(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = clusterv1exp.AddToScheme(scheme)
+
+	now := time.Date(2022, 6, 15, 9, 0, 0, 0, time.UTC)
+	mp := &clusterv1exp.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mp1",
+			Namespace: "default",
+			Annotations: map[string]string{
+				azure.ZonalCapacityBackoffAnnotation: `{"1":"2022-06-15T08:59:00Z","2":"2022-06-15T09:02:00Z"}`,
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mp).Build()
+	s := &MachinePoolScope{
+		client:      c,
+		MachinePool: mp,
+	}
+
+	// the expired backoff of zone 1 is dropped, the one of zone 2 is kept.
+	g.Expect(s.BackOffZones(context.TODO(), []string{"3"}, now)).To(Succeed())
+	got := &clusterv1exp.MachinePool{}
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: mp.Name, Namespace: mp.Namespace}, got)).To(Succeed())
+	g.Expect(got.Annotations[azure.ZonalCapacityBackoffAnnotation]).To(Equal(`{"2":"2022-06-15T09:02:00Z","3":"2022-06-15T09:05:00Z"}`))
+
+	// a malformed annotation is replaced.
+	s.MachinePool.Annotations[azure.ZonalCapacityBackoffAnnotation] = "zone 2"
+	g.Expect(s.BackOffZones(context.TODO(), []string{"2"}, now)).To(Succeed())
+	g.Expect(s.MachinePool.Annotations[azure.ZonalCapacityBackoffAnnotation]).To(Equal(`{"2":"2022-06-15T09:05:00Z"}`))
+}
+
+func TestMachinePoolScope_AutoscaleSettingSpec(t *testing.T) {
+	autoscale := &infrav1exp.AzureMachinePoolAutoscale{
+		MinReplicas: 1,
+		MaxReplicas: 5,
+	}
+
+	cases := []struct {
+		Name       string
+		Autoscale  *infrav1exp.AzureMachinePoolAutoscale
+		Conditions clusterv1.Conditions
+		Expected   azure.ResourceSpecGetter
+	}{
+		{
+			Name:     "should return nil if the scale set was never autoscaled",
+			Expected: nil,
+		},
+		{
+			Name:      "should return the autoscale setting spec if the scale set is autoscaled",
+			Autoscale: autoscale,
+			Expected: &autoscalesettings.AutoscaleSettingSpec{
+				Name:             "amp1",
+				ResourceGroup:    "my-rg",
+				ClusterName:      "cluster1",
+				Location:         "westus2",
+				TargetResourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/amp1",
+				Autoscale:        autoscale,
+				AdditionalTags:   infrav1.Tags{"kubernetes.io_cluster_cluster1": "owned"},
+			},
+		},
+		{
+			Name: "should return a spec without autoscale profile if autoscaling was disabled",
+			Conditions: clusterv1.Conditions{
+				{Type: infrav1.AutoscaleSettingReadyCondition, Status: corev1.ConditionTrue},
+			},
+			Expected: &autoscalesettings.AutoscaleSettingSpec{
+				Name:             "amp1",
+				ResourceGroup:    "my-rg",
+				ClusterName:      "cluster1",
+				Location:         "westus2",
+				TargetResourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/amp1",
+				AdditionalTags:   infrav1.Tags{"kubernetes.io_cluster_cluster1": "owned"},
+			},
+		},
+		{
+			Name: "should return nil if the autoscale setting was already deleted",
+			Conditions: clusterv1.Conditions{
+				{Type: infrav1.AutoscaleSettingReadyCondition, Status: corev1.ConditionFalse, Reason: infrav1.DeletedReason},
+			},
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &MachinePoolScope{
+				MachinePool: &clusterv1exp.MachinePool{},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{Name: "amp1"},
+					Spec:       infrav1exp.AzureMachinePoolSpec{Autoscale: tc.Autoscale},
+					Status:     infrav1exp.AzureMachinePoolStatus{Conditions: tc.Conditions},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: EnvironmentSettings{
+							Values: map[string]string{envSubscriptionID: "123"},
+						},
+					},
+					Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+							AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+								Location: "westus2",
+							},
+						},
+					},
+				},
+			}
+
+			if tc.Expected == nil {
+				g.Expect(s.AutoscaleSettingSpec()).To(BeNil())
+			} else {
+				g.Expect(s.AutoscaleSettingSpec()).To(Equal(tc.Expected))
+			}
+		})
+	}
+}
+
 func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -705,9 +1306,9 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 				},
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
-							Environment: autorestazure.Environment{
-								Name: autorestazure.PublicCloud.Name,
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.PublicCloud.Name,
 							},
 						},
 					},
@@ -751,9 +1352,9 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 				},
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
-							Environment: autorestazure.Environment{
-								Name: autorestazure.USGovernmentCloud.Name,
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.USGovernmentCloud.Name,
 							},
 						},
 					},
@@ -785,9 +1386,9 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 				},
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
-							Environment: autorestazure.Environment{
-								Name: autorestazure.PublicCloud.Name,
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.PublicCloud.Name,
 							},
 						},
 					},
@@ -832,9 +1433,9 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 				},
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
-							Environment: autorestazure.Environment{
-								Name: autorestazure.USGovernmentCloud.Name,
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.USGovernmentCloud.Name,
 							},
 						},
 					},
@@ -865,9 +1466,9 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 				},
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
-							Environment: autorestazure.Environment{
-								Name: autorestazure.PublicCloud.Name,
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.PublicCloud.Name,
 							},
 						},
 					},
@@ -898,9 +1499,9 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 				},
 				ClusterScoper: &ClusterScope{
 					AzureClients: AzureClients{
-						EnvironmentSettings: auth.EnvironmentSettings{
-							Environment: autorestazure.Environment{
-								Name: autorestazure.USGovernmentCloud.Name,
+						EnvironmentSettings: EnvironmentSettings{
+							Environment: azure.Environment{
+								Name: azure.USGovernmentCloud.Name,
 							},
 						},
 					},
@@ -953,3 +1554,144 @@ func getReadyAzureMachinePoolMachines(count int32) []infrav1exp.AzureMachinePool
 
 	return machines
 }
+
+func TestMachinePoolScope_BootstrapDataRequired(t *testing.T) {
+	settled := func(amp *infrav1exp.AzureMachinePool) {
+		amp.Annotations = map[string]string{azure.BootstrapDataGenerationAnnotation: "2/3"}
+		state := infrav1.Succeeded
+		amp.Status.ProvisioningState = &state
+		amp.Status.Replicas = 3
+		conditions.MarkTrue(amp, infrav1.ScaleSetModelUpdatedCondition)
+		conditions.MarkTrue(amp, infrav1.BootstrapSucceededCondition)
+	}
+
+	cases := []struct {
+		Name     string
+		Setup    func(amp *infrav1exp.AzureMachinePool, mp *clusterv1exp.MachinePool)
+		Expected bool
+	}{
+		{
+			Name:     "should not be required once the desired replicas run the latest model and are bootstrapped",
+			Setup:    func(amp *infrav1exp.AzureMachinePool, mp *clusterv1exp.MachinePool) {},
+			Expected: false,
+		},
+		{
+			Name: "should be required while the scale set is scaling",
+			Setup: func(amp *infrav1exp.AzureMachinePool, mp *clusterv1exp.MachinePool) {
+				amp.Status.Replicas = 2
+			},
+			Expected: true,
+		},
+		{
+			Name: "should be required while the model of the scale set is updating",
+			Setup: func(amp *infrav1exp.AzureMachinePool, mp *clusterv1exp.MachinePool) {
+				state := infrav1.Updating
+				amp.Status.ProvisioningState = &state
+				conditions.MarkFalse(amp, infrav1.ScaleSetModelUpdatedCondition, infrav1.ScaleSetModelOutOfDateReason, clusterv1.ConditionSeverityInfo, "")
+			},
+			Expected: true,
+		},
+		{
+			Name: "should be required while the instances are bootstrapping",
+			Setup: func(amp *infrav1exp.AzureMachinePool, mp *clusterv1exp.MachinePool) {
+				conditions.MarkFalse(amp, infrav1.BootstrapSucceededCondition, infrav1.BootstrapInProgressReason, clusterv1.ConditionSeverityInfo, "")
+			},
+			Expected: true,
+		},
+		{
+			Name: "should be required when the MachinePool changed since the last upload",
+			Setup: func(amp *infrav1exp.AzureMachinePool, mp *clusterv1exp.MachinePool) {
+				mp.Generation = 4
+			},
+			Expected: true,
+		},
+		{
+			Name: "should be required when the bootstrap data was never uploaded",
+			Setup: func(amp *infrav1exp.AzureMachinePool, mp *clusterv1exp.MachinePool) {
+				delete(amp.Annotations, azure.BootstrapDataGenerationAnnotation)
+			},
+			Expected: true,
+		},
+		{
+			Name: "should be required without a bootstrap extension until the nodes of the desired replicas exist",
+			Setup: func(amp *infrav1exp.AzureMachinePool, mp *clusterv1exp.MachinePool) {
+				conditions.Delete(amp, infrav1.BootstrapSucceededCondition)
+				amp.Annotations[azure.BootstrapDataSASExpiryAnnotation] = time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+				mp.Status.NodeRefs = []corev1.ObjectReference{{Name: "node1"}, {Name: "node2"}}
+			},
+			Expected: true,
+		},
+		{
+			Name: "should not be required without a bootstrap extension once the nodes of the desired replicas exist",
+			Setup: func(amp *infrav1exp.AzureMachinePool, mp *clusterv1exp.MachinePool) {
+				conditions.Delete(amp, infrav1.BootstrapSucceededCondition)
+				amp.Annotations[azure.BootstrapDataSASExpiryAnnotation] = time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+				mp.Status.NodeRefs = []corev1.ObjectReference{{Name: "node1"}, {Name: "node2"}, {Name: "node3"}}
+			},
+			Expected: false,
+		},
+		{
+			Name: "should not be required without a bootstrap extension once the SAS URL expired",
+			Setup: func(amp *infrav1exp.AzureMachinePool, mp *clusterv1exp.MachinePool) {
+				conditions.Delete(amp, infrav1.BootstrapSucceededCondition)
+				amp.Annotations[azure.BootstrapDataSASExpiryAnnotation] = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+			},
+			Expected: false,
+		},
+		{
+			Name: "should always be required when the replicas are managed by an autoscaler",
+			Setup: func(amp *infrav1exp.AzureMachinePool, mp *clusterv1exp.MachinePool) {
+				amp.Annotations[azure.ReplicasManagedByAutoscalerAnnotation] = "true"
+			},
+			Expected: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			mp := &clusterv1exp.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "mp1", Namespace: "default", Generation: 3},
+				Spec:       clusterv1exp.MachinePoolSpec{Replicas: to.Ptr[int32](3)},
+			}
+			amp := &infrav1exp.AzureMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "amp1", Namespace: "default", Generation: 2},
+			}
+			settled(amp)
+			tc.Setup(amp, mp)
+			s := &MachinePoolScope{
+				MachinePool:      mp,
+				AzureMachinePool: amp,
+			}
+
+			g.Expect(s.BootstrapDataRequired()).To(Equal(tc.Expected))
+		})
+	}
+}
+
+func TestMachinePoolScope_SetBootstrapDataBlob(t *testing.T) {
+	g := NewWithT(t)
+	s := &MachinePoolScope{
+		MachinePool: &clusterv1exp.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "mp1", Namespace: "default", Generation: 3},
+		},
+		AzureMachinePool: &infrav1exp.AzureMachinePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "amp1", Namespace: "default", Generation: 2},
+		},
+	}
+
+	s.SetBootstrapDataBlob("cluster1/amp1")
+	s.SetBootstrapDataHash("abc")
+	s.SetBootstrapDataSASExpiry(time.Date(2022, 6, 15, 9, 5, 0, 0, time.UTC))
+	g.Expect(s.BootstrapDataBlob()).To(Equal("cluster1/amp1"))
+	g.Expect(s.BootstrapDataHash()).To(Equal("abc"))
+	g.Expect(s.BootstrapDataSASExpiry()).To(Equal(time.Date(2022, 6, 15, 9, 5, 0, 0, time.UTC)))
+	g.Expect(s.AzureMachinePool.Annotations).To(HaveKeyWithValue(azure.BootstrapDataGenerationAnnotation, "2/3"))
+
+	s.SetBootstrapDataBlob("")
+	g.Expect(s.BootstrapDataBlob()).To(BeEmpty())
+	g.Expect(s.BootstrapDataHash()).To(BeEmpty())
+	g.Expect(s.AzureMachinePool.Annotations).To(HaveKeyWithValue(azure.BootstrapDataGenerationAnnotation, "2/3"))
+	g.Expect(s.AzureMachinePool.Annotations).To(HaveKeyWithValue(azure.BootstrapDataSASExpiryAnnotation, "2022-06-15T09:05:00Z"))
+}
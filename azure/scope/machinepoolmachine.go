@@ -185,7 +185,7 @@ func (s *MachinePoolMachineScope) UpdateDeleteStatus(condition clusterv1.Conditi
 	case err == nil:
 		conditions.MarkFalse(s.AzureMachinePoolMachine, condition, infrav1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s successfully deleted", service)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(s.AzureMachinePoolMachine, condition, infrav1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting", service)
+		conditions.MarkFalse(s.AzureMachinePoolMachine, condition, infrav1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(s.AzureMachinePoolMachine, condition, infrav1.DeletionFailedReason, clusterv1.ConditionSeverityError, "%s failed to delete. err: %s", service, err.Error())
 	}
@@ -197,7 +197,7 @@ func (s *MachinePoolMachineScope) UpdatePutStatus(condition clusterv1.ConditionT
 	case err == nil:
 		conditions.MarkTrue(s.AzureMachinePoolMachine, condition)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(s.AzureMachinePoolMachine, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating", service)
+		conditions.MarkFalse(s.AzureMachinePoolMachine, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(s.AzureMachinePoolMachine, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to create or update. err: %s", service, err.Error())
 	}
@@ -209,7 +209,7 @@ func (s *MachinePoolMachineScope) UpdatePatchStatus(condition clusterv1.Conditio
 	case err == nil:
 		conditions.MarkTrue(s.AzureMachinePoolMachine, condition)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(s.AzureMachinePoolMachine, condition, infrav1.UpdatingReason, clusterv1.ConditionSeverityInfo, "%s updating", service)
+		conditions.MarkFalse(s.AzureMachinePoolMachine, condition, infrav1.UpdatingReason, clusterv1.ConditionSeverityInfo, "%s updating%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(s.AzureMachinePoolMachine, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to update. err: %s", service, err.Error())
 	}
@@ -304,7 +304,13 @@ func (s *MachinePoolMachineScope) UpdateStatus(ctx context.Context) error {
 		}
 
 		s.AzureMachinePoolMachine.Status.LatestModelApplied = hasLatestModel
+		s.AzureMachinePoolMachine.Status.ProtectedFromScaleIn = s.instance.ProtectFromScaleIn
 		s.AzureMachinePoolMachine.Status.ProvisioningState = &s.instance.State
+		s.AzureMachinePoolMachine.Status.InstanceView = s.instance.InstanceView
+		// The addresses are not fetched when the instance is read while deleting it.
+		if s.instance.Addresses != nil {
+			s.AzureMachinePoolMachine.Status.Addresses = s.instance.Addresses
+		}
 	}
 
 	return nil
@@ -495,8 +501,14 @@ func (s *MachinePoolMachineScope) hasLatestModelApplied(ctx context.Context) (bo
 		return false, errors.New("machinepoolscope image must not be nil")
 	}
 
-	// if the images match, then the VM is of the same model
-	return reflect.DeepEqual(s.instance.Image, *image), nil
+	// if the images don't match, then the VM is not of the same model
+	if !reflect.DeepEqual(s.instance.Image, *image) {
+		return false, nil
+	}
+
+	// the model may also have changed without an image change, e.g. after an extension update, which is only taken into
+	// account when the machine pool rolls out model updates.
+	return s.instance.LatestModelApplied || !s.MachinePoolScope.RolloutModelUpdates(), nil
 }
 
 func newWorkloadClusterProxy(c client.Client, cluster client.ObjectKey) *workloadClusterProxy {
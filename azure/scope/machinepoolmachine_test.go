@@ -20,7 +20,7 @@ import (
 	"context"
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
@@ -143,8 +143,9 @@ func TestMachineScope_UpdateStatus(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	clusterScope := mock_azure.NewMockClusterScoper(mockCtrl)
-	clusterScope.EXPECT().Authorizer().AnyTimes()
 	clusterScope.EXPECT().BaseURI().AnyTimes()
+	clusterScope.EXPECT().CloudEnvironment().AnyTimes()
+	clusterScope.EXPECT().Token().AnyTimes()
 	clusterScope.EXPECT().Location().AnyTimes()
 	clusterScope.EXPECT().SubscriptionID().AnyTimes()
 	clusterScope.EXPECT().ClusterName().Return("cluster-foo").AnyTimes()
@@ -267,6 +268,52 @@ func TestMachineScope_UpdateStatus(t *testing.T) {
 				}))
 			},
 		},
+		{
+			Name: "instance view and addresses populate the AMPM status",
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) (*azure.VMSSVM, *infrav1.AzureMachinePoolMachine) {
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(nil, nil)
+				return &azure.VMSSVM{
+					State: v1beta1.Succeeded,
+					Image: v1beta1.Image{
+						Marketplace: &v1beta1.AzureMarketplaceImage{
+							ImagePlan: v1beta1.ImagePlan{
+								Publisher: "cncf-upstream",
+								Offer:     "capi",
+								SKU:       "k8s-1dot19dot11-ubuntu-1804",
+							},
+							Version: "latest",
+						},
+					},
+					InstanceView: &v1beta1.VMInstanceView{
+						Extensions: []v1beta1.VMExtensionStatus{
+							{Name: "CAPZ.Linux.Bootstrapping", ProvisioningState: v1beta1.Failed, Message: "Enable failed"},
+						},
+						BootDiagnostics: &v1beta1.VMBootDiagnosticsStatus{Available: true},
+					},
+					Addresses: []corev1.NodeAddress{
+						{Type: corev1.NodeInternalDNS, Address: "vmss000000"},
+						{Type: corev1.NodeInternalIP, Address: "10.0.0.4"},
+					},
+				}, ampm
+			},
+			Verify: func(g *WithT, scope *MachinePoolMachineScope) {
+				succeeded := v1beta1.Succeeded
+				g.Expect(scope.AzureMachinePoolMachine.Status).To(Equal(infrav1.AzureMachinePoolMachineStatus{
+					ProvisioningState:  &succeeded,
+					LatestModelApplied: true,
+					InstanceView: &v1beta1.VMInstanceView{
+						Extensions: []v1beta1.VMExtensionStatus{
+							{Name: "CAPZ.Linux.Bootstrapping", ProvisioningState: v1beta1.Failed, Message: "Enable failed"},
+						},
+						BootDiagnostics: &v1beta1.VMBootDiagnosticsStatus{Available: true},
+					},
+					Addresses: []corev1.NodeAddress{
+						{Type: corev1.NodeInternalDNS, Address: "vmss000000"},
+						{Type: corev1.NodeInternalIP, Address: "10.0.0.4"},
+					},
+				}))
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -282,7 +329,7 @@ func TestMachineScope_UpdateStatus(t *testing.T) {
 						Spec: capiv1exp.MachinePoolSpec{
 							Template: clusterv1.MachineTemplateSpec{
 								Spec: clusterv1.MachineSpec{
-									Version: to.StringPtr("v1.19.11"),
+									Version: to.Ptr("v1.19.11"),
 								},
 							},
 						},
@@ -380,7 +427,7 @@ func TestMachinePoolMachineScope_CordonAndDrain(t *testing.T) {
 						Spec: capiv1exp.MachinePoolSpec{
 							Template: clusterv1.MachineTemplateSpec{
 								Spec: clusterv1.MachineSpec{
-									Version: to.StringPtr("v1.19.11"),
+									Version: to.Ptr("v1.19.11"),
 								},
 							},
 						},
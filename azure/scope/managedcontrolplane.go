@@ -21,13 +21,14 @@ import (
 	"encoding/json"
 	"strings"
 
-	"github.com/Azure/go-autorest/autorest"
 	"github.com/pkg/errors"
 	"golang.org/x/mod/semver"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/fleetsmembers"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
@@ -43,6 +44,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// containerInsightsAddonName is the name of the AKS add-on sending the metrics and the logs of a managed cluster to a
+// Log Analytics workspace.
+const containerInsightsAddonName = "omsagent"
+
+// userKubeconfigSecretPurpose is the suffix of the name of the secret holding the kubeconfig of the users of a cluster.
+const userKubeconfigSecretPurpose secret.Purpose = "user-kubeconfig"
+
 // ManagedControlPlaneScopeParams defines the input parameters used to create a new managed
 // control plane.
 type ManagedControlPlaneScopeParams struct {
@@ -99,9 +107,10 @@ func NewManagedControlPlaneScope(ctx context.Context, params ManagedControlPlane
 
 // ManagedControlPlaneScope defines the basic context for an actuator to operate upon.
 type ManagedControlPlaneScope struct {
-	Client         client.Client
-	patchHelper    *patch.Helper
-	kubeConfigData []byte
+	Client             client.Client
+	patchHelper        *patch.Helper
+	kubeConfigData     []byte
+	userKubeConfigData []byte
 
 	AzureClients
 	Cluster             *clusterv1.Cluster
@@ -130,6 +139,11 @@ func (s *ManagedControlPlaneScope) ClusterName() string {
 	return s.Cluster.Name
 }
 
+// Name returns the name of the AKS cluster.
+func (s *ManagedControlPlaneScope) Name() string {
+	return s.ControlPlane.Name
+}
+
 // Location returns the managed control plane's Azure location, or an empty string.
 func (s *ManagedControlPlaneScope) Location() string {
 	if s.ControlPlane == nil {
@@ -143,12 +157,14 @@ func (s *ManagedControlPlaneScope) AvailabilitySetEnabled() bool {
 	return false // not applicable for a managed control plane
 }
 
-// AdditionalTags returns AdditionalTags from the ControlPlane spec.
+// AdditionalTags returns AdditionalTags from the ControlPlane spec, merged with the mandatory tags of the tagging
+// policy, which take precedence.
 func (s *ManagedControlPlaneScope) AdditionalTags() infrav1.Tags {
 	tags := make(infrav1.Tags)
 	if s.ControlPlane.Spec.AdditionalTags != nil {
 		tags = s.ControlPlane.Spec.AdditionalTags.DeepCopy()
 	}
+	tags.Merge(azure.MandatoryTags(s.ClusterName()))
 	return tags
 }
 
@@ -162,11 +178,6 @@ func (s *ManagedControlPlaneScope) BaseURI() string {
 	return s.AzureClients.ResourceManagerEndpoint
 }
 
-// Authorizer returns the Azure client Authorizer.
-func (s *ManagedControlPlaneScope) Authorizer() autorest.Authorizer {
-	return s.AzureClients.Authorizer
-}
-
 // PatchObject persists the cluster configuration and status.
 func (s *ManagedControlPlaneScope) PatchObject(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ManagedControlPlaneScope.PatchObject")
@@ -245,7 +256,7 @@ func (s *ManagedControlPlaneScope) NodeNatGateway() infrav1.NatGateway {
 
 // SubnetSpecs returns the subnets specs.
 func (s *ManagedControlPlaneScope) SubnetSpecs() []azure.ResourceSpecGetter {
-	return []azure.ResourceSpecGetter{
+	subnetSpecs := []azure.ResourceSpecGetter{
 		&subnets.SubnetSpec{
 			Name:              s.NodeSubnet().Name,
 			ResourceGroup:     s.ResourceGroup(),
@@ -257,6 +268,32 @@ func (s *ManagedControlPlaneScope) SubnetSpecs() []azure.ResourceSpecGetter {
 			Role:              infrav1.SubnetNode,
 		},
 	}
+
+	if apiServerSubnet := s.apiServerSubnet(); apiServerSubnet != nil {
+		subnetSpecs = append(subnetSpecs, &subnets.SubnetSpec{
+			Name:              apiServerSubnet.Name,
+			ResourceGroup:     s.ResourceGroup(),
+			SubscriptionID:    s.SubscriptionID(),
+			CIDRs:             []string{apiServerSubnet.CIDRBlock},
+			VNetName:          s.Vnet().Name,
+			VNetResourceGroup: s.Vnet().ResourceGroup,
+			IsVNetManaged:     s.IsVnetManaged(),
+			Role:              infrav1.SubnetControlPlane,
+			Delegation:        managedclusters.APIServerSubnetDelegation,
+		})
+	}
+
+	return subnetSpecs
+}
+
+// apiServerSubnet returns the subnet the API server is projected into, or nil when API server VNet integration is
+// disabled.
+func (s *ManagedControlPlaneScope) apiServerSubnet() *infrav1exp.ManagedControlPlaneSubnet {
+	profile := s.ControlPlane.Spec.APIServerAccessProfile
+	if profile == nil || !pointer.BoolDeref(profile.EnableVnetIntegration, false) {
+		return nil
+	}
+	return profile.Subnet
 }
 
 // Subnets returns the subnets specs.
@@ -374,6 +411,112 @@ func (s *ManagedControlPlaneScope) CloudProviderConfigOverrides() *infrav1.Cloud
 	return nil
 }
 
+// BootstrapDataStorage returns nil as managed clusters don't deliver bootstrap data through storage.
+func (s *ManagedControlPlaneScope) BootstrapDataStorage() *infrav1.BootstrapDataStorage {
+	return nil
+}
+
+// ProxyConfig returns nil as the proxy of managed clusters is configured on the AKS cluster.
+func (s *ManagedControlPlaneScope) ProxyConfig() *infrav1.ProxyConfig {
+	return nil
+}
+
+// BootstrapExtension returns nil as the machines of managed clusters are bootstrapped by AKS.
+func (s *ManagedControlPlaneScope) BootstrapExtension() *infrav1.BootstrapExtension {
+	return nil
+}
+
+// RegistryMirrors returns nil as the container runtime of managed clusters is configured by AKS.
+func (s *ManagedControlPlaneScope) RegistryMirrors() []infrav1.RegistryMirror {
+	return nil
+}
+
+// LogAnalyticsWorkspaceSpec returns the spec of the Log Analytics workspace created for the managed cluster.
+func (s *ManagedControlPlaneScope) LogAnalyticsWorkspaceSpec() azure.ResourceSpecGetter {
+	return getLogAnalyticsWorkspaceSpec(s, s.ControlPlane.Spec.Monitoring)
+}
+
+// hasAddonProfile returns true if the add-on profiles configure the given add-on.
+func hasAddonProfile(profiles []infrav1exp.AddonProfile, name string) bool {
+	for _, profile := range profiles {
+		if strings.EqualFold(profile.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtendedLocation returns nil as managed clusters aren't created in extended locations.
+func (s *ManagedControlPlaneScope) ExtendedLocation() *infrav1.ExtendedLocationSpec {
+	return nil
+}
+
+// Monitoring returns nil as managed clusters are onboarded to Azure Monitor through the omsagent add-on of the AKS
+// cluster, not through the agent of the machines.
+func (s *ManagedControlPlaneScope) Monitoring() *infrav1.AzureMonitoring {
+	return nil
+}
+
+// SecurityProfile returns nil as managed clusters are covered by Defender through the security profile of the AKS
+// cluster, not through the agent of the machines.
+func (s *ManagedControlPlaneScope) SecurityProfile() *infrav1.ClusterSecurityProfile {
+	return nil
+}
+
+// AttachedACRs returns the resource IDs of the Azure Container Registries attached to the managed cluster.
+func (s *ManagedControlPlaneScope) AttachedACRs() []string {
+	return s.ControlPlane.Spec.AttachedACRs
+}
+
+// CrossSubscriptionAccess returns the default policy as the machines of managed clusters are created by AKS.
+func (s *ManagedControlPlaneScope) CrossSubscriptionAccess() infrav1.CrossSubscriptionAccessPolicy {
+	return infrav1.CrossSubscriptionAccessValidate
+}
+
+// RoleAssignmentSpecs returns the specs granting the AcrPull role on the attached registries to the kubelet identity.
+func (s *ManagedControlPlaneScope) RoleAssignmentSpecs(principalID *string) []azure.ResourceSpecGetter {
+	return getAcrPullRoleAssignmentSpecs(s.AttachedACRs(), principalID, azure.ManagedCluster, s.Name(), s.ResourceGroup())
+}
+
+// RoleAssignmentResourceType returns the role assignment resource type.
+func (s *ManagedControlPlaneScope) RoleAssignmentResourceType() string {
+	return azure.ManagedCluster
+}
+
+// HasSystemAssignedIdentity returns true if registries are attached to the managed cluster, as AKS always reports the
+// identity of the kubelets, whether it created it or it was user-assigned.
+func (s *ManagedControlPlaneScope) HasSystemAssignedIdentity() bool {
+	return len(s.AttachedACRs()) > 0
+}
+
+// FleetsMemberSpec returns the spec of the membership of the managed cluster in its fleet, or nil if the cluster
+// doesn't join a fleet.
+func (s *ManagedControlPlaneScope) FleetsMemberSpec() azure.ResourceSpecGetter {
+	member := s.ControlPlane.Spec.FleetsMember
+	if member == nil {
+		return nil
+	}
+
+	// The resource ID of the fleet is validated by the webhook.
+	fleet, err := azure.ParseResourceID(member.FleetResourceID)
+	if err != nil {
+		return nil
+	}
+	name := member.Name
+	if name == "" {
+		name = s.Name()
+	}
+
+	return &fleetsmembers.FleetsMemberSpec{
+		Name:             name,
+		ResourceGroup:    fleet.ResourceGroupName,
+		FleetResourceID:  member.FleetResourceID,
+		FleetName:        fleet.Name,
+		ManagedClusterID: azure.ManagedClusterID(s.SubscriptionID(), s.ResourceGroup(), s.Name()),
+		Group:            member.Group,
+	}
+}
+
 // FailureDomains returns the failure domains for the cluster.
 func (s *ManagedControlPlaneScope) FailureDomains() []string {
 	return []string{}
@@ -386,17 +529,18 @@ func (s *ManagedControlPlaneScope) ManagedClusterAnnotations() map[string]string
 // ManagedClusterSpec returns the managed cluster spec.
 func (s *ManagedControlPlaneScope) ManagedClusterSpec(ctx context.Context) azure.ResourceSpecGetter {
 	managedClusterSpec := managedclusters.ManagedClusterSpec{
-		Name:              s.ControlPlane.Name,
-		ResourceGroup:     s.ControlPlane.Spec.ResourceGroupName,
-		NodeResourceGroup: s.ControlPlane.Spec.NodeResourceGroupName,
-		Location:          s.ControlPlane.Spec.Location,
-		Tags:              s.ControlPlane.Spec.AdditionalTags,
-		Headers:           maps.FilterByKeyPrefix(s.ManagedClusterAnnotations(), azure.CustomHeaderPrefix),
-		Version:           strings.TrimPrefix(s.ControlPlane.Spec.Version, "v"),
-		SSHPublicKey:      s.ControlPlane.Spec.SSHPublicKey,
-		DNSServiceIP:      s.ControlPlane.Spec.DNSServiceIP,
+		Name:                        s.ControlPlane.Name,
+		ResourceGroup:               s.ControlPlane.Spec.ResourceGroupName,
+		NodeResourceGroup:           s.ControlPlane.Spec.NodeResourceGroupName,
+		Location:                    s.ControlPlane.Spec.Location,
+		Tags:                        s.AdditionalTags(),
+		Headers:                     maps.FilterByKeyPrefix(s.ManagedClusterAnnotations(), azure.CustomHeaderPrefix),
+		Version:                     strings.TrimPrefix(s.ControlPlane.Spec.Version, "v"),
+		SSHPublicKey:                s.ControlPlane.Spec.SSHPublicKey,
+		DNSServiceIP:                s.ControlPlane.Spec.DNSServiceIP,
+		KubeletUserAssignedIdentity: s.ControlPlane.Spec.KubeletUserAssignedIdentity,
 		VnetSubnetID: azure.SubnetID(
-			s.ControlPlane.Spec.SubscriptionID,
+			s.SubscriptionID(),
 			s.ControlPlane.Spec.ResourceGroupName,
 			s.ControlPlane.Spec.VirtualNetwork.Name,
 			s.ControlPlane.Spec.VirtualNetwork.Subnet.Name,
@@ -423,6 +567,12 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec(ctx context.Context) azure
 		}
 	}
 
+	if s.ControlPlane.Spec.Identity != nil {
+		managedClusterSpec.Identity = &managedclusters.Identity{
+			Type:                           string(s.ControlPlane.Spec.Identity.Type),
+			UserAssignedIdentityResourceID: s.ControlPlane.Spec.Identity.UserAssignedIdentityResourceID,
+		}
+	}
 	if s.ControlPlane.Spec.AADProfile != nil {
 		managedClusterSpec.AADProfile = &managedclusters.AADProfile{
 			Managed:             s.ControlPlane.Spec.AADProfile.Managed,
@@ -430,6 +580,7 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec(ctx context.Context) azure
 			AdminGroupObjectIDs: s.ControlPlane.Spec.AADProfile.AdminGroupObjectIDs,
 		}
 	}
+	managedClusterSpec.DisableLocalAccounts = s.ControlPlane.Spec.DisableLocalAccounts
 
 	if s.ControlPlane.Spec.AddonProfiles != nil {
 		for _, profile := range s.ControlPlane.Spec.AddonProfiles {
@@ -441,6 +592,40 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec(ctx context.Context) azure
 		}
 	}
 
+	if monitoring := s.ControlPlane.Spec.Monitoring; monitoring != nil && !hasAddonProfile(s.ControlPlane.Spec.AddonProfiles, containerInsightsAddonName) {
+		managedClusterSpec.AddonProfiles = append(managedClusterSpec.AddonProfiles, managedclusters.AddonProfile{
+			Name:    containerInsightsAddonName,
+			Enabled: true,
+			Config: map[string]string{
+				"logAnalyticsWorkspaceResourceID": getLogAnalyticsWorkspaceID(s, monitoring),
+			},
+		})
+	}
+
+	if securityProfile := s.ControlPlane.Spec.SecurityProfile; securityProfile != nil && securityProfile.Defender != nil {
+		workspaceID := securityProfile.Defender.LogAnalyticsWorkspaceID
+		if workspaceID == "" {
+			workspaceID = getLogAnalyticsWorkspaceID(s, s.ControlPlane.Spec.Monitoring)
+		}
+		managedClusterSpec.DefenderProfile = &managedclusters.DefenderProfile{
+			LogAnalyticsWorkspaceID: workspaceID,
+		}
+	}
+
+	if imageCleaner := s.ControlPlane.Spec.ImageCleaner; imageCleaner != nil {
+		managedClusterSpec.ImageCleaner = &managedclusters.ImageCleaner{
+			Enabled:       imageCleaner.Enabled,
+			IntervalHours: imageCleaner.IntervalHours,
+		}
+	}
+
+	if workloadAutoScalerProfile := s.ControlPlane.Spec.WorkloadAutoScalerProfile; workloadAutoScalerProfile != nil {
+		managedClusterSpec.WorkloadAutoScalerProfile = &managedclusters.WorkloadAutoScalerProfile{
+			Keda:                  workloadAutoScalerProfile.Keda,
+			VerticalPodAutoscaler: workloadAutoScalerProfile.VerticalPodAutoscaler,
+		}
+	}
+
 	if s.ControlPlane.Spec.SKU != nil {
 		managedClusterSpec.SKU = &managedclusters.SKU{
 			Tier: string(s.ControlPlane.Spec.SKU.Tier),
@@ -457,6 +642,17 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec(ctx context.Context) azure
 		}
 	}
 
+	if s.ControlPlane.Spec.OutboundType != nil {
+		managedClusterSpec.OutboundType = string(*s.ControlPlane.Spec.OutboundType)
+	}
+
+	if s.ControlPlane.Spec.NatGatewayProfile != nil {
+		managedClusterSpec.NatGatewayProfile = &managedclusters.NatGatewayProfile{
+			ManagedOutboundIPs:   s.ControlPlane.Spec.NatGatewayProfile.ManagedOutboundIPs,
+			IdleTimeoutInMinutes: s.ControlPlane.Spec.NatGatewayProfile.IdleTimeoutInMinutes,
+		}
+	}
+
 	if s.ControlPlane.Spec.APIServerAccessProfile != nil {
 		managedClusterSpec.APIServerAccessProfile = &managedclusters.APIServerAccessProfile{
 			AuthorizedIPRanges:             s.ControlPlane.Spec.APIServerAccessProfile.AuthorizedIPRanges,
@@ -464,6 +660,28 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec(ctx context.Context) azure
 			PrivateDNSZone:                 s.ControlPlane.Spec.APIServerAccessProfile.PrivateDNSZone,
 			EnablePrivateClusterPublicFQDN: s.ControlPlane.Spec.APIServerAccessProfile.EnablePrivateClusterPublicFQDN,
 		}
+		if apiServerSubnet := s.apiServerSubnet(); apiServerSubnet != nil {
+			managedClusterSpec.APIServerAccessProfile.EnableVnetIntegration = pointer.Bool(true)
+			managedClusterSpec.APIServerAccessProfile.SubnetID = pointer.String(azure.SubnetID(
+				s.SubscriptionID(),
+				s.ControlPlane.Spec.ResourceGroupName,
+				s.ControlPlane.Spec.VirtualNetwork.Name,
+				apiServerSubnet.Name,
+			))
+		}
+	}
+
+	if httpProxyConfig := s.ControlPlane.Spec.HTTPProxyConfig; httpProxyConfig != nil {
+		managedClusterSpec.HTTPProxyConfig = &managedclusters.HTTPProxyConfig{
+			HTTPProxy:  httpProxyConfig.HTTPProxy,
+			HTTPSProxy: httpProxyConfig.HTTPSProxy,
+			NoProxy:    httpProxyConfig.NoProxy,
+		}
+		if trustedCA := httpProxyConfig.TrustedCA; trustedCA != nil {
+			managedClusterSpec.HTTPProxyConfig.GetTrustedCA = func() ([]byte, error) {
+				return getCACertificates(ctx, s.Client, s.ControlPlane.Namespace, &infrav1.CACertificates{SecretRef: trustedCA})
+			}
+		}
 	}
 
 	return &managedClusterSpec
@@ -488,7 +706,7 @@ func (s *ManagedControlPlaneScope) GetAllAgentPoolSpecs() ([]azure.AgentPoolSpec
 			foundSystemPool = true
 		}
 
-		ammp := buildAgentPoolSpec(s.ControlPlane, pool.MachinePool, pool.InfraMachinePool)
+		ammp := buildAgentPoolSpec(s.SubscriptionID(), s.ControlPlane, pool.MachinePool, pool.InfraMachinePool)
 		ammps = append(ammps, ammp)
 	}
 
@@ -504,6 +722,11 @@ func (s *ManagedControlPlaneScope) SetControlPlaneEndpoint(endpoint clusterv1.AP
 	s.ControlPlane.Spec.ControlPlaneEndpoint = endpoint
 }
 
+// SetManagedClusterStatus sets the state of the managed cluster reported by Azure.
+func (s *ManagedControlPlaneScope) SetManagedClusterStatus(status *infrav1exp.ManagedClusterStatus) {
+	s.ControlPlane.Status.ManagedCluster = status
+}
+
 // MakeEmptyKubeConfigSecret creates an empty secret object that is used for storing kubeconfig secret data.
 func (s *ManagedControlPlaneScope) MakeEmptyKubeConfigSecret() corev1.Secret {
 	return corev1.Secret{
@@ -527,6 +750,42 @@ func (s *ManagedControlPlaneScope) SetKubeConfigData(kubeConfigData []byte) {
 	s.kubeConfigData = kubeConfigData
 }
 
+// MakeEmptyUserKubeConfigSecret creates an empty secret object that is used for storing the kubeconfig of the users of
+// the cluster.
+func (s *ManagedControlPlaneScope) MakeEmptyUserKubeConfigSecret() corev1.Secret {
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secret.Name(s.Cluster.Name, userKubeconfigSecretPurpose),
+			Namespace: s.Cluster.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(s.ControlPlane, infrav1exp.GroupVersion.WithKind("AzureManagedControlPlane")),
+			},
+		},
+	}
+}
+
+// GetUserKubeConfigData returns a []byte that contains the kubeconfig of the users of the cluster.
+func (s *ManagedControlPlaneScope) GetUserKubeConfigData() []byte {
+	return s.userKubeConfigData
+}
+
+// SetUserKubeConfigData sets the kubeconfig data of the users of the cluster.
+func (s *ManagedControlPlaneScope) SetUserKubeConfigData(userKubeConfigData []byte) {
+	s.userKubeConfigData = userKubeConfigData
+}
+
+// UserKubeconfigCredentials returns the type of the credentials of the kubeconfig of the users of the cluster. It
+// defaults to the user credentials when the local accounts are disabled, to the admin credentials otherwise.
+func (s *ManagedControlPlaneScope) UserKubeconfigCredentials() infrav1exp.KubeconfigCredentialsType {
+	if credentials := s.ControlPlane.Spec.UserKubeconfigCredentials; credentials != nil {
+		return *credentials
+	}
+	if pointer.BoolDeref(s.ControlPlane.Spec.DisableLocalAccounts, false) {
+		return infrav1exp.KubeconfigCredentialsTypeUser
+	}
+	return infrav1exp.KubeconfigCredentialsTypeAdmin
+}
+
 // SetLongRunningOperationState will set the future on the AzureManagedControlPlane status to allow the resource to continue
 // in the next reconciliation.
 func (s *ManagedControlPlaneScope) SetLongRunningOperationState(future *infrav1.Future) {
@@ -549,7 +808,7 @@ func (s *ManagedControlPlaneScope) UpdateDeleteStatus(condition clusterv1.Condit
 	case err == nil:
 		conditions.MarkFalse(s.ControlPlane, condition, infrav1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s successfully deleted", service)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(s.ControlPlane, condition, infrav1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting", service)
+		conditions.MarkFalse(s.ControlPlane, condition, infrav1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(s.ControlPlane, condition, infrav1.DeletionFailedReason, clusterv1.ConditionSeverityError, "%s failed to delete. err: %s", service, err.Error())
 	}
@@ -561,7 +820,7 @@ func (s *ManagedControlPlaneScope) UpdatePutStatus(condition clusterv1.Condition
 	case err == nil:
 		conditions.MarkTrue(s.ControlPlane, condition)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(s.ControlPlane, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating", service)
+		conditions.MarkFalse(s.ControlPlane, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(s.ControlPlane, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to create or update. err: %s", service, err.Error())
 	}
@@ -573,7 +832,7 @@ func (s *ManagedControlPlaneScope) UpdatePatchStatus(condition clusterv1.Conditi
 	case err == nil:
 		conditions.MarkTrue(s.ControlPlane, condition)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(s.ControlPlane, condition, infrav1.UpdatingReason, clusterv1.ConditionSeverityInfo, "%s updating", service)
+		conditions.MarkFalse(s.ControlPlane, condition, infrav1.UpdatingReason, clusterv1.ConditionSeverityInfo, "%s updating%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(s.ControlPlane, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to update. err: %s", service, err.Error())
 	}
@@ -622,5 +881,12 @@ func (s *ManagedControlPlaneScope) TagsSpecs() []azure.TagsSpec {
 			Tags:       s.AdditionalTags(),
 			Annotation: azure.RGTagsLastAppliedAnnotation,
 		},
+		{
+			// The node resource group is created by AKS, so it doesn't have the owned tag of the cluster.
+			Scope:      azure.ResourceGroupID(s.SubscriptionID(), s.NodeResourceGroup()),
+			Tags:       s.ControlPlane.Spec.NodeResourceGroupTags,
+			Annotation: azure.NodeResourceGroupTagsLastAppliedAnnotation,
+			Owned:      true,
+		},
 	}
 }
@@ -20,13 +20,16 @@ import (
 	"context"
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	apiv1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/fleetsmembers"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
@@ -47,9 +50,6 @@ func TestManagedControlPlaneScope_PoolVersion(t *testing.T) {
 		{
 			Name: "Without Version",
 			Input: ManagedControlPlaneScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster: &clusterv1.Cluster{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "cluster1",
@@ -86,9 +86,6 @@ func TestManagedControlPlaneScope_PoolVersion(t *testing.T) {
 		{
 			Name: "With Version",
 			Input: ManagedControlPlaneScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster: &clusterv1.Cluster{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "cluster1",
@@ -118,7 +115,7 @@ func TestManagedControlPlaneScope_PoolVersion(t *testing.T) {
 					SKU:          "Standard_D2s_v3",
 					Mode:         "System",
 					Replicas:     1,
-					Version:      to.StringPtr("1.21.1"),
+					Version:      to.Ptr("1.21.1"),
 					Cluster:      "cluster1",
 					VnetSubnetID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups//providers/Microsoft.Network/virtualNetworks//subnets/",
 				},
@@ -127,9 +124,6 @@ func TestManagedControlPlaneScope_PoolVersion(t *testing.T) {
 		{
 			Name: "With bad version",
 			Input: ManagedControlPlaneScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster: &clusterv1.Cluster{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "cluster1",
@@ -188,9 +182,6 @@ func TestManagedControlPlaneScope_AddonProfiles(t *testing.T) {
 		{
 			Name: "Without add-ons",
 			Input: ManagedControlPlaneScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster: &clusterv1.Cluster{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "cluster1",
@@ -218,9 +209,6 @@ func TestManagedControlPlaneScope_AddonProfiles(t *testing.T) {
 		{
 			Name: "With add-ons",
 			Input: ManagedControlPlaneScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster: &clusterv1.Cluster{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "cluster1",
@@ -252,6 +240,75 @@ func TestManagedControlPlaneScope_AddonProfiles(t *testing.T) {
 				{Name: "addon2", Config: map[string]string{"k1": "v1", "k2": "v2"}, Enabled: true},
 			},
 		},
+		{
+			Name: "With monitoring",
+			Input: ManagedControlPlaneScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+						ResourceGroupName: "rg1",
+						Monitoring:        &apiv1beta1.AzureMonitoring{},
+					},
+				},
+				ManagedMachinePools: []ManagedMachinePool{
+					{
+						MachinePool:      getMachinePool("pool0"),
+						InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+					},
+				},
+			},
+			Expected: []managedclusters.AddonProfile{
+				{
+					Name:    "omsagent",
+					Config:  map[string]string{"logAnalyticsWorkspaceResourceID": "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.OperationalInsights/workspaces/cluster1-logs"},
+					Enabled: true,
+				},
+			},
+		},
+		{
+			Name: "With monitoring and the omsagent add-on",
+			Input: ManagedControlPlaneScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+						ResourceGroupName: "rg1",
+						AddonProfiles: []infrav1.AddonProfile{
+							{Name: "omsagent", Config: map[string]string{"logAnalyticsWorkspaceResourceID": "my-workspace"}, Enabled: true},
+						},
+						Monitoring: &apiv1beta1.AzureMonitoring{},
+					},
+				},
+				ManagedMachinePools: []ManagedMachinePool{
+					{
+						MachinePool:      getMachinePool("pool0"),
+						InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+					},
+				},
+			},
+			Expected: []managedclusters.AddonProfile{
+				{Name: "omsagent", Config: map[string]string{"logAnalyticsWorkspaceResourceID": "my-workspace"}, Enabled: true},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -268,6 +325,571 @@ func TestManagedControlPlaneScope_AddonProfiles(t *testing.T) {
 	}
 }
 
+func TestManagedControlPlaneScope_DefenderProfile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	cases := []struct {
+		Name     string
+		Input    ManagedControlPlaneScopeParams
+		Expected *managedclusters.DefenderProfile
+	}{
+		{
+			Name: "Without Defender",
+			Input: ManagedControlPlaneScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+						ResourceGroupName: "rg1",
+					},
+				},
+				ManagedMachinePools: []ManagedMachinePool{
+					{
+						MachinePool:      getMachinePool("pool0"),
+						InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+					},
+				},
+			},
+			Expected: nil,
+		},
+		{
+			Name: "With Defender and monitoring",
+			Input: ManagedControlPlaneScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+						ResourceGroupName: "rg1",
+						Monitoring:        &apiv1beta1.AzureMonitoring{},
+						SecurityProfile: &apiv1beta1.ClusterSecurityProfile{
+							Defender: &apiv1beta1.DefenderProfile{},
+						},
+					},
+				},
+				ManagedMachinePools: []ManagedMachinePool{
+					{
+						MachinePool:      getMachinePool("pool0"),
+						InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+					},
+				},
+			},
+			Expected: &managedclusters.DefenderProfile{
+				LogAnalyticsWorkspaceID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.OperationalInsights/workspaces/cluster1-logs",
+			},
+		},
+		{
+			Name: "With Defender and a workspace",
+			Input: ManagedControlPlaneScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+						ResourceGroupName: "rg1",
+						Monitoring:        &apiv1beta1.AzureMonitoring{},
+						SecurityProfile: &apiv1beta1.ClusterSecurityProfile{
+							Defender: &apiv1beta1.DefenderProfile{
+								LogAnalyticsWorkspaceID: "/subscriptions/123/resourceGroups/security/providers/Microsoft.OperationalInsights/workspaces/defender",
+							},
+						},
+					},
+				},
+				ManagedMachinePools: []ManagedMachinePool{
+					{
+						MachinePool:      getMachinePool("pool0"),
+						InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+					},
+				},
+			},
+			Expected: &managedclusters.DefenderProfile{
+				LogAnalyticsWorkspaceID: "/subscriptions/123/resourceGroups/security/providers/Microsoft.OperationalInsights/workspaces/defender",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(c.Input.ControlPlane).Build()
+			c.Input.Client = fakeClient
+			s, err := NewManagedControlPlaneScope(context.TODO(), c.Input)
+			g.Expect(err).To(Succeed())
+			managedCluster := s.ManagedClusterSpec(context.TODO())
+			g.Expect(managedCluster.(*managedclusters.ManagedClusterSpec).DefenderProfile).To(Equal(c.Expected))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_APIServerVnetIntegration(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	nodeSubnetSpec := &subnets.SubnetSpec{
+		Name:              "default",
+		ResourceGroup:     "rg1",
+		SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+		CIDRs:             []string{"10.240.0.0/16"},
+		VNetName:          "vnet1",
+		VNetResourceGroup: "rg1",
+		IsVNetManaged:     true,
+		Role:              apiv1beta1.SubnetNode,
+	}
+
+	cases := []struct {
+		Name                           string
+		APIServerAccessProfile         *infrav1.APIServerAccessProfile
+		ExpectedSubnetSpecs            []azure.ResourceSpecGetter
+		ExpectedAPIServerAccessProfile *managedclusters.APIServerAccessProfile
+	}{
+		{
+			Name:                           "Without API server access profile",
+			ExpectedSubnetSpecs:            []azure.ResourceSpecGetter{nodeSubnetSpec},
+			ExpectedAPIServerAccessProfile: nil,
+		},
+		{
+			Name: "With VNet integration disabled",
+			APIServerAccessProfile: &infrav1.APIServerAccessProfile{
+				EnableVnetIntegration: to.Ptr(false),
+			},
+			ExpectedSubnetSpecs:            []azure.ResourceSpecGetter{nodeSubnetSpec},
+			ExpectedAPIServerAccessProfile: &managedclusters.APIServerAccessProfile{},
+		},
+		{
+			Name: "With VNet integration enabled",
+			APIServerAccessProfile: &infrav1.APIServerAccessProfile{
+				EnableVnetIntegration: to.Ptr(true),
+				Subnet: &infrav1.ManagedControlPlaneSubnet{
+					Name:      "apiserver",
+					CIDRBlock: "10.241.0.0/28",
+				},
+			},
+			ExpectedSubnetSpecs: []azure.ResourceSpecGetter{
+				nodeSubnetSpec,
+				&subnets.SubnetSpec{
+					Name:              "apiserver",
+					ResourceGroup:     "rg1",
+					SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+					CIDRs:             []string{"10.241.0.0/28"},
+					VNetName:          "vnet1",
+					VNetResourceGroup: "rg1",
+					IsVNetManaged:     true,
+					Role:              apiv1beta1.SubnetControlPlane,
+					Delegation:        "Microsoft.ContainerService/managedClusters",
+				},
+			},
+			ExpectedAPIServerAccessProfile: &managedclusters.APIServerAccessProfile{
+				EnableVnetIntegration: to.Ptr(true),
+				SubnetID:              to.Ptr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.Network/virtualNetworks/vnet1/subnets/apiserver"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			params := ManagedControlPlaneScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+						ResourceGroupName: "rg1",
+						VirtualNetwork: infrav1.ManagedControlPlaneVirtualNetwork{
+							Name:      "vnet1",
+							CIDRBlock: "10.0.0.0/8",
+							Subnet: infrav1.ManagedControlPlaneSubnet{
+								Name:      "default",
+								CIDRBlock: "10.240.0.0/16",
+							},
+						},
+						APIServerAccessProfile: c.APIServerAccessProfile,
+					},
+				},
+				ManagedMachinePools: []ManagedMachinePool{
+					{
+						MachinePool:      getMachinePool("pool0"),
+						InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+					},
+				},
+			}
+			params.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(params.ControlPlane).Build()
+			s, err := NewManagedControlPlaneScope(context.TODO(), params)
+			g.Expect(err).To(Succeed())
+			g.Expect(s.SubnetSpecs()).To(Equal(c.ExpectedSubnetSpecs))
+			managedCluster := s.ManagedClusterSpec(context.TODO())
+			g.Expect(managedCluster.(*managedclusters.ManagedClusterSpec).APIServerAccessProfile).To(Equal(c.ExpectedAPIServerAccessProfile))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_WorkloadAutoScalerProfileAndImageCleaner(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	cases := []struct {
+		Name                              string
+		WorkloadAutoScalerProfile         *infrav1.WorkloadAutoScalerProfile
+		ImageCleaner                      *infrav1.ImageCleaner
+		ExpectedWorkloadAutoScalerProfile *managedclusters.WorkloadAutoScalerProfile
+		ExpectedImageCleaner              *managedclusters.ImageCleaner
+	}{
+		{
+			Name:                              "Without workload autoscalers nor image cleaner",
+			ExpectedWorkloadAutoScalerProfile: nil,
+			ExpectedImageCleaner:              nil,
+		},
+		{
+			Name: "With workload autoscalers and image cleaner",
+			WorkloadAutoScalerProfile: &infrav1.WorkloadAutoScalerProfile{
+				Keda:                  true,
+				VerticalPodAutoscaler: true,
+			},
+			ImageCleaner: &infrav1.ImageCleaner{
+				Enabled:       true,
+				IntervalHours: to.Ptr[int32](48),
+			},
+			ExpectedWorkloadAutoScalerProfile: &managedclusters.WorkloadAutoScalerProfile{
+				Keda:                  true,
+				VerticalPodAutoscaler: true,
+			},
+			ExpectedImageCleaner: &managedclusters.ImageCleaner{
+				Enabled:       true,
+				IntervalHours: to.Ptr[int32](48),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			params := ManagedControlPlaneScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						SubscriptionID:            "00000000-0000-0000-0000-000000000000",
+						ResourceGroupName:         "rg1",
+						WorkloadAutoScalerProfile: c.WorkloadAutoScalerProfile,
+						ImageCleaner:              c.ImageCleaner,
+					},
+				},
+				ManagedMachinePools: []ManagedMachinePool{
+					{
+						MachinePool:      getMachinePool("pool0"),
+						InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+					},
+				},
+			}
+			params.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(params.ControlPlane).Build()
+			s, err := NewManagedControlPlaneScope(context.TODO(), params)
+			g.Expect(err).To(Succeed())
+			managedCluster := s.ManagedClusterSpec(context.TODO()).(*managedclusters.ManagedClusterSpec)
+			g.Expect(managedCluster.WorkloadAutoScalerProfile).To(Equal(c.ExpectedWorkloadAutoScalerProfile))
+			g.Expect(managedCluster.ImageCleaner).To(Equal(c.ExpectedImageCleaner))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_UserKubeconfigCredentials(t *testing.T) {
+	user := infrav1.KubeconfigCredentialsTypeUser
+	cases := []struct {
+		Name     string
+		Spec     infrav1.AzureManagedControlPlaneSpec
+		Expected infrav1.KubeconfigCredentialsType
+	}{
+		{
+			Name:     "Defaults to admin credentials",
+			Spec:     infrav1.AzureManagedControlPlaneSpec{},
+			Expected: infrav1.KubeconfigCredentialsTypeAdmin,
+		},
+		{
+			Name:     "Defaults to user credentials with local accounts disabled",
+			Spec:     infrav1.AzureManagedControlPlaneSpec{DisableLocalAccounts: to.Ptr(true)},
+			Expected: infrav1.KubeconfigCredentialsTypeUser,
+		},
+		{
+			Name:     "With user credentials",
+			Spec:     infrav1.AzureManagedControlPlaneSpec{UserKubeconfigCredentials: &user},
+			Expected: infrav1.KubeconfigCredentialsTypeUser,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &ManagedControlPlaneScope{
+				ControlPlane: &infrav1.AzureManagedControlPlane{Spec: c.Spec},
+			}
+			g.Expect(s.UserKubeconfigCredentials()).To(Equal(c.Expected))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_FleetsMemberSpec(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	cases := []struct {
+		Name         string
+		FleetsMember *infrav1.FleetsMember
+		Expected     azure.ResourceSpecGetter
+	}{
+		{
+			Name:     "Without fleet",
+			Expected: nil,
+		},
+		{
+			Name: "With fleet",
+			FleetsMember: &infrav1.FleetsMember{
+				FleetResourceID: "/subscriptions/123/resourceGroups/fleets/providers/Microsoft.ContainerService/fleets/my-fleet",
+				Group:           "canary",
+			},
+			Expected: &fleetsmembers.FleetsMemberSpec{
+				Name:             "cluster1",
+				ResourceGroup:    "fleets",
+				FleetResourceID:  "/subscriptions/123/resourceGroups/fleets/providers/Microsoft.ContainerService/fleets/my-fleet",
+				FleetName:        "my-fleet",
+				ManagedClusterID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.ContainerService/managedClusters/cluster1",
+				Group:            "canary",
+			},
+		},
+		{
+			Name: "With fleet and member name",
+			FleetsMember: &infrav1.FleetsMember{
+				FleetResourceID: "/subscriptions/123/resourceGroups/fleets/providers/Microsoft.ContainerService/fleets/my-fleet",
+				Name:            "member1",
+			},
+			Expected: &fleetsmembers.FleetsMemberSpec{
+				Name:             "member1",
+				ResourceGroup:    "fleets",
+				FleetResourceID:  "/subscriptions/123/resourceGroups/fleets/providers/Microsoft.ContainerService/fleets/my-fleet",
+				FleetName:        "my-fleet",
+				ManagedClusterID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1/providers/Microsoft.ContainerService/managedClusters/cluster1",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			input := ManagedControlPlaneScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+						ResourceGroupName: "rg1",
+						FleetsMember:      c.FleetsMember,
+					},
+				},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(input.ControlPlane).Build()
+			input.Client = fakeClient
+			s, err := NewManagedControlPlaneScope(context.TODO(), input)
+			g.Expect(err).To(Succeed())
+			if c.Expected == nil {
+				g.Expect(s.FleetsMemberSpec()).To(BeNil())
+			} else {
+				g.Expect(s.FleetsMemberSpec()).To(Equal(c.Expected))
+			}
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_HTTPProxyConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	cases := []struct {
+		Name          string
+		TrustedCA     *apiv1beta1.CACertificatesReference
+		ExpectedCA    []byte
+		ExpectedError string
+	}{
+		{
+			Name:      "Without a trusted CA",
+			TrustedCA: nil,
+		},
+		{
+			Name:       "With a trusted CA",
+			TrustedCA:  &apiv1beta1.CACertificatesReference{Name: "proxy-ca"},
+			ExpectedCA: []byte(testCACertificate),
+		},
+		{
+			Name:          "With a missing trusted CA",
+			TrustedCA:     &apiv1beta1.CACertificatesReference{Name: "missing"},
+			ExpectedError: "failed to get CA certificates Secret default/missing",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			input := ManagedControlPlaneScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+						ResourceGroupName: "rg1",
+						HTTPProxyConfig: &infrav1.HTTPProxyConfig{
+							HTTPSProxy: "http://proxy.example.com:3128/",
+							NoProxy:    []string{"example.com"},
+							TrustedCA:  c.TrustedCA,
+						},
+					},
+				},
+				ManagedMachinePools: []ManagedMachinePool{
+					{
+						MachinePool:      getMachinePool("pool0"),
+						InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+					},
+				},
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "proxy-ca", Namespace: "default"},
+				Data:       map[string][]byte{"ca.crt": []byte(testCACertificate)},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(input.ControlPlane, secret).Build()
+			input.Client = fakeClient
+			s, err := NewManagedControlPlaneScope(context.TODO(), input)
+			g.Expect(err).To(Succeed())
+			httpProxyConfig := s.ManagedClusterSpec(context.TODO()).(*managedclusters.ManagedClusterSpec).HTTPProxyConfig
+			g.Expect(httpProxyConfig.HTTPSProxy).To(Equal("http://proxy.example.com:3128/"))
+			g.Expect(httpProxyConfig.NoProxy).To(Equal([]string{"example.com"}))
+			if c.TrustedCA == nil {
+				g.Expect(httpProxyConfig.GetTrustedCA).To(BeNil())
+				return
+			}
+			trustedCA, err := httpProxyConfig.GetTrustedCA()
+			if c.ExpectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(c.ExpectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(trustedCA).To(Equal(c.ExpectedCA))
+			}
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_TagsSpecs(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	g := NewWithT(t)
+	input := ManagedControlPlaneScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+		},
+		ControlPlane: &infrav1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				SubscriptionID:        "00000000-0000-0000-0000-000000000000",
+				ResourceGroupName:     "rg1",
+				NodeResourceGroupName: "rg1-nodes",
+				AdditionalTags:        apiv1beta1.Tags{"team": "platform"},
+				NodeResourceGroupTags: apiv1beta1.Tags{"costCenter": "1234"},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(input.ControlPlane).Build()
+	input.Client = fakeClient
+	s, err := NewManagedControlPlaneScope(context.TODO(), input)
+	g.Expect(err).To(Succeed())
+	g.Expect(s.TagsSpecs()).To(Equal([]azure.TagsSpec{
+		{
+			Scope:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1",
+			Tags:       apiv1beta1.Tags{"team": "platform"},
+			Annotation: azure.RGTagsLastAppliedAnnotation,
+		},
+		{
+			Scope:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg1-nodes",
+			Tags:       apiv1beta1.Tags{"costCenter": "1234"},
+			Annotation: azure.NodeResourceGroupTagsLastAppliedAnnotation,
+			Owned:      true,
+		},
+	}))
+}
+
 func TestManagedControlPlaneScope_OSType(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = capiv1exp.AddToScheme(scheme)
@@ -282,9 +904,6 @@ func TestManagedControlPlaneScope_OSType(t *testing.T) {
 		{
 			Name: "with Linux and Windows pools",
 			Input: ManagedControlPlaneScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster: &clusterv1.Cluster{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "cluster1",
@@ -332,7 +951,7 @@ func TestManagedControlPlaneScope_OSType(t *testing.T) {
 					Replicas:     1,
 					Cluster:      "cluster1",
 					VnetSubnetID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups//providers/Microsoft.Network/virtualNetworks//subnets/",
-					OSType:       to.StringPtr(azure.LinuxOS),
+					OSType:       to.Ptr(azure.LinuxOS),
 				},
 				{
 					Name:         "pool2",
@@ -341,16 +960,13 @@ func TestManagedControlPlaneScope_OSType(t *testing.T) {
 					Replicas:     1,
 					Cluster:      "cluster1",
 					VnetSubnetID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups//providers/Microsoft.Network/virtualNetworks//subnets/",
-					OSType:       to.StringPtr(azure.WindowsOS),
+					OSType:       to.Ptr(azure.WindowsOS),
 				},
 			},
 		},
 		{
 			Name: "system pool required",
 			Input: ManagedControlPlaneScopeParams{
-				AzureClients: AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster: &clusterv1.Cluster{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "cluster1",
@@ -21,8 +21,9 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
@@ -64,6 +65,10 @@ func NewManagedMachinePoolScope(ctx context.Context, params ManagedMachinePoolSc
 		return nil, errors.New("failed to generate new scope from nil ControlPlane")
 	}
 
+	if params.ManagedControlPlaneScope == nil {
+		return nil, errors.New("failed to generate new scope from nil ManagedControlPlaneScope")
+	}
+
 	helper, err := patch.NewHelper(params.InfraMachinePool, params.Client)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to init patch helper")
@@ -122,10 +127,12 @@ func (s *ManagedMachinePoolScope) AgentPoolAnnotations() map[string]string {
 
 // AgentPoolSpec returns an azure.AgentPoolSpec for currently reconciled AzureManagedMachinePool.
 func (s *ManagedMachinePoolScope) AgentPoolSpec() azure.AgentPoolSpec {
-	return buildAgentPoolSpec(s.ControlPlane, s.MachinePool, s.InfraMachinePool)
+	return buildAgentPoolSpec(s.SubscriptionID(), s.ControlPlane, s.MachinePool, s.InfraMachinePool)
 }
 
-func buildAgentPoolSpec(managedControlPlane *infrav1exp.AzureManagedControlPlane,
+// buildAgentPoolSpec builds the spec of an agent pool in a subscription, which is derived from the scope rather than
+// the spec of the control plane as the latter may be empty.
+func buildAgentPoolSpec(subscriptionID string, managedControlPlane *infrav1exp.AzureManagedControlPlane,
 	machinePool *clusterv1exp.MachinePool,
 	managedMachinePool *infrav1exp.AzureManagedMachinePool) azure.AgentPoolSpec {
 	var normalizedVersion *string
@@ -140,7 +147,7 @@ func buildAgentPoolSpec(managedControlPlane *infrav1exp.AzureManagedControlPlane
 	}
 
 	agentPoolSpec := azure.AgentPoolSpec{
-		Name:          to.String(managedMachinePool.Spec.Name),
+		Name:          pointer.StringDeref(managedMachinePool.Spec.Name, ""),
 		ResourceGroup: managedControlPlane.Spec.ResourceGroupName,
 		Cluster:       managedControlPlane.Name,
 		SKU:           managedMachinePool.Spec.SKU,
@@ -148,7 +155,7 @@ func buildAgentPoolSpec(managedControlPlane *infrav1exp.AzureManagedControlPlane
 		Version:       normalizedVersion,
 		OSType:        managedMachinePool.Spec.OSType,
 		VnetSubnetID: azure.SubnetID(
-			managedControlPlane.Spec.SubscriptionID,
+			subscriptionID,
 			managedControlPlane.Spec.ResourceGroupName,
 			managedControlPlane.Spec.VirtualNetwork.Name,
 			managedControlPlane.Spec.VirtualNetwork.Subnet.Name,
@@ -173,7 +180,7 @@ func buildAgentPoolSpec(managedControlPlane *infrav1exp.AzureManagedControlPlane
 	}
 
 	if managedMachinePool.Spec.Scaling != nil {
-		agentPoolSpec.EnableAutoScaling = to.BoolPtr(true)
+		agentPoolSpec.EnableAutoScaling = to.Ptr(true)
 		agentPoolSpec.MaxCount = managedMachinePool.Spec.Scaling.MaxSize
 		agentPoolSpec.MinCount = managedMachinePool.Spec.Scaling.MinSize
 	}
@@ -181,7 +188,7 @@ func buildAgentPoolSpec(managedControlPlane *infrav1exp.AzureManagedControlPlane
 	if len(managedMachinePool.Spec.NodeLabels) > 0 {
 		agentPoolSpec.NodeLabels = make(map[string]*string, len(managedMachinePool.Spec.NodeLabels))
 		for k, v := range managedMachinePool.Spec.NodeLabels {
-			agentPoolSpec.NodeLabels[k] = to.StringPtr(v)
+			agentPoolSpec.NodeLabels[k] = to.Ptr(v)
 		}
 	}
 
@@ -198,6 +205,16 @@ func (s *ManagedMachinePoolScope) SetAgentPoolReplicas(replicas int32) {
 	s.InfraMachinePool.Status.Replicas = replicas
 }
 
+// SetAgentPoolReadyReplicas sets the number of agent pool replicas which have been provisioned successfully.
+func (s *ManagedMachinePoolScope) SetAgentPoolReadyReplicas(readyReplicas int32) {
+	s.InfraMachinePool.Status.ReadyReplicas = readyReplicas
+}
+
+// SetAgentPoolStatus sets the state of the agent pool reported by Azure.
+func (s *ManagedMachinePoolScope) SetAgentPoolStatus(status *infrav1exp.AgentPoolStatus) {
+	s.InfraMachinePool.Status.AgentPool = status
+}
+
 // SetAgentPoolReady sets the flag that indicates if the agent pool is ready or not.
 func (s *ManagedMachinePoolScope) SetAgentPoolReady(ready bool) {
 	s.InfraMachinePool.Status.Ready = ready
@@ -225,7 +242,7 @@ func (s *ManagedMachinePoolScope) UpdateDeleteStatus(condition clusterv1.Conditi
 	case err == nil:
 		conditions.MarkFalse(s.InfraMachinePool, condition, infrav1.DeletedReason, clusterv1.ConditionSeverityInfo, "%s successfully deleted", service)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(s.InfraMachinePool, condition, infrav1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting", service)
+		conditions.MarkFalse(s.InfraMachinePool, condition, infrav1.DeletingReason, clusterv1.ConditionSeverityInfo, "%s deleting%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(s.InfraMachinePool, condition, infrav1.DeletionFailedReason, clusterv1.ConditionSeverityError, "%s failed to delete. err: %s", service, err.Error())
 	}
@@ -237,7 +254,7 @@ func (s *ManagedMachinePoolScope) UpdatePutStatus(condition clusterv1.ConditionT
 	case err == nil:
 		conditions.MarkTrue(s.InfraMachinePool, condition)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(s.InfraMachinePool, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating", service)
+		conditions.MarkFalse(s.InfraMachinePool, condition, infrav1.CreatingReason, clusterv1.ConditionSeverityInfo, "%s creating or updating%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(s.InfraMachinePool, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to create or update. err: %s", service, err.Error())
 	}
@@ -249,7 +266,7 @@ func (s *ManagedMachinePoolScope) UpdatePatchStatus(condition clusterv1.Conditio
 	case err == nil:
 		conditions.MarkTrue(s.InfraMachinePool, condition)
 	case azure.IsOperationNotDoneError(err):
-		conditions.MarkFalse(s.InfraMachinePool, condition, infrav1.UpdatingReason, clusterv1.ConditionSeverityInfo, "%s updating", service)
+		conditions.MarkFalse(s.InfraMachinePool, condition, infrav1.UpdatingReason, clusterv1.ConditionSeverityInfo, "%s updating%s", service, azure.OperationProgressMessage(err))
 	default:
 		conditions.MarkFalse(s.InfraMachinePool, condition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "%s failed to update. err: %s", service, err.Error())
 	}
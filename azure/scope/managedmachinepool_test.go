@@ -20,8 +20,8 @@ import (
 	"context"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -104,9 +104,9 @@ func TestManagedMachinePoolScope_Autoscaling(t *testing.T) {
 				Mode:              "User",
 				Cluster:           "cluster1",
 				Replicas:          1,
-				EnableAutoScaling: to.BoolPtr(true),
-				MinCount:          to.Int32Ptr(2),
-				MaxCount:          to.Int32Ptr(10),
+				EnableAutoScaling: to.Ptr(true),
+				MinCount:          to.Ptr[int32](2),
+				MaxCount:          to.Ptr[int32](10),
 				VnetSubnetID:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups//providers/Microsoft.Network/virtualNetworks//subnets/",
 			},
 		},
@@ -118,6 +118,7 @@ func TestManagedMachinePoolScope_Autoscaling(t *testing.T) {
 			g := NewWithT(t)
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(c.Input.MachinePool, c.Input.InfraMachinePool, c.Input.ControlPlane).Build()
 			c.Input.Client = fakeClient
+			c.Input.ManagedControlPlaneScope = newTestManagedControlPlaneScope(c.Input.ControlPlane)
 			s, err := NewManagedMachinePoolScope(context.TODO(), c.Input)
 			g.Expect(err).To(Succeed())
 			agentPool := s.AgentPoolSpec()
@@ -201,7 +202,7 @@ func TestManagedMachinePoolScope_NodeLabels(t *testing.T) {
 				Cluster:  "cluster1",
 				Replicas: 1,
 				NodeLabels: map[string]*string{
-					"custom": to.StringPtr("default"),
+					"custom": to.Ptr("default"),
 				},
 				VnetSubnetID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups//providers/Microsoft.Network/virtualNetworks//subnets/",
 			},
@@ -214,6 +215,7 @@ func TestManagedMachinePoolScope_NodeLabels(t *testing.T) {
 			g := NewWithT(t)
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(c.Input.MachinePool, c.Input.InfraMachinePool, c.Input.ControlPlane).Build()
 			c.Input.Client = fakeClient
+			c.Input.ManagedControlPlaneScope = newTestManagedControlPlaneScope(c.Input.ControlPlane)
 			s, err := NewManagedMachinePoolScope(context.TODO(), c.Input)
 			g.Expect(err).To(Succeed())
 			agentPool := s.AgentPoolSpec()
@@ -293,7 +295,7 @@ func TestManagedMachinePoolScope_MaxPods(t *testing.T) {
 				Mode:         "System",
 				Cluster:      "cluster1",
 				Replicas:     1,
-				MaxPods:      to.Int32Ptr(12),
+				MaxPods:      to.Ptr[int32](12),
 				VnetSubnetID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups//providers/Microsoft.Network/virtualNetworks//subnets/",
 			},
 		},
@@ -305,6 +307,7 @@ func TestManagedMachinePoolScope_MaxPods(t *testing.T) {
 			g := NewWithT(t)
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(c.Input.MachinePool, c.Input.InfraMachinePool, c.Input.ControlPlane).Build()
 			c.Input.Client = fakeClient
+			c.Input.ManagedControlPlaneScope = newTestManagedControlPlaneScope(c.Input.ControlPlane)
 			s, err := NewManagedMachinePoolScope(context.TODO(), c.Input)
 			g.Expect(err).To(Succeed())
 			agentPool := s.AgentPoolSpec()
@@ -403,6 +406,7 @@ func TestManagedMachinePoolScope_Taints(t *testing.T) {
 			g := NewWithT(t)
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(c.Input.MachinePool, c.Input.InfraMachinePool, c.Input.ControlPlane).Build()
 			c.Input.Client = fakeClient
+			c.Input.ManagedControlPlaneScope = newTestManagedControlPlaneScope(c.Input.ControlPlane)
 			s, err := NewManagedMachinePoolScope(context.TODO(), c.Input)
 			g.Expect(err).To(Succeed())
 			agentPool := s.AgentPoolSpec()
@@ -473,7 +477,7 @@ func TestManagedMachinePoolScope_OSDiskType(t *testing.T) {
 				},
 				ManagedMachinePool: ManagedMachinePool{
 					MachinePool:      getMachinePool("pool1"),
-					InfraMachinePool: getAzureMachinePoolWithOsDiskType("pool1", string(containerservice.OSDiskTypeEphemeral)),
+					InfraMachinePool: getAzureMachinePoolWithOsDiskType("pool1", string(armcontainerservice.OSDiskTypeEphemeral)),
 				},
 			},
 			Expected: azure.AgentPoolSpec{
@@ -482,7 +486,7 @@ func TestManagedMachinePoolScope_OSDiskType(t *testing.T) {
 				Mode:         "User",
 				Cluster:      "cluster1",
 				Replicas:     1,
-				OsDiskType:   to.StringPtr(string(containerservice.OSDiskTypeEphemeral)),
+				OsDiskType:   to.Ptr(string(armcontainerservice.OSDiskTypeEphemeral)),
 				VnetSubnetID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups//providers/Microsoft.Network/virtualNetworks//subnets/",
 			},
 		},
@@ -494,6 +498,7 @@ func TestManagedMachinePoolScope_OSDiskType(t *testing.T) {
 			g := NewWithT(t)
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(c.Input.MachinePool, c.Input.InfraMachinePool, c.Input.ControlPlane).Build()
 			c.Input.Client = fakeClient
+			c.Input.ManagedControlPlaneScope = newTestManagedControlPlaneScope(c.Input.ControlPlane)
 			s, err := NewManagedMachinePoolScope(context.TODO(), c.Input)
 			g.Expect(err).To(Succeed())
 			agentPool := s.AgentPoolSpec()
@@ -521,7 +526,7 @@ func getAzureMachinePool(name string, mode infrav1.NodePoolMode) *infrav1.AzureM
 		Spec: infrav1.AzureManagedMachinePoolSpec{
 			Mode: string(mode),
 			SKU:  "Standard_D2s_v3",
-			Name: to.StringPtr(name),
+			Name: to.Ptr(name),
 		},
 	}
 }
@@ -529,15 +534,15 @@ func getAzureMachinePool(name string, mode infrav1.NodePoolMode) *infrav1.AzureM
 func getAzureMachinePoolWithScaling(name string, min, max int32) *infrav1.AzureManagedMachinePool {
 	managedPool := getAzureMachinePool(name, infrav1.NodePoolModeUser)
 	managedPool.Spec.Scaling = &infrav1.ManagedMachinePoolScaling{
-		MinSize: to.Int32Ptr(min),
-		MaxSize: to.Int32Ptr(max),
+		MinSize: to.Ptr[int32](min),
+		MaxSize: to.Ptr[int32](max),
 	}
 	return managedPool
 }
 
 func getAzureMachinePoolWithMaxPods(name string, maxPods int32) *infrav1.AzureManagedMachinePool {
 	managedPool := getAzureMachinePool(name, infrav1.NodePoolModeSystem)
-	managedPool.Spec.MaxPods = to.Int32Ptr(maxPods)
+	managedPool.Spec.MaxPods = to.Ptr[int32](maxPods)
 	return managedPool
 }
 
@@ -549,7 +554,7 @@ func getAzureMachinePoolWithTaints(name string, taints infrav1.Taints) *infrav1.
 
 func getAzureMachinePoolWithOsDiskType(name string, osDiskType string) *infrav1.AzureManagedMachinePool {
 	managedPool := getAzureMachinePool(name, infrav1.NodePoolModeUser)
-	managedPool.Spec.OsDiskType = to.StringPtr(osDiskType)
+	managedPool.Spec.OsDiskType = to.Ptr(osDiskType)
 	return managedPool
 }
 
@@ -576,18 +581,30 @@ func getMachinePool(name string) *capiv1exp.MachinePool {
 
 func getLinuxAzureMachinePool(name string) *infrav1.AzureManagedMachinePool {
 	managedPool := getAzureMachinePool(name, infrav1.NodePoolModeUser)
-	managedPool.Spec.OSType = to.StringPtr(azure.LinuxOS)
+	managedPool.Spec.OSType = to.Ptr(azure.LinuxOS)
 	return managedPool
 }
 
 func getWindowsAzureMachinePool(name string) *infrav1.AzureManagedMachinePool {
 	managedPool := getAzureMachinePool(name, infrav1.NodePoolModeUser)
-	managedPool.Spec.OSType = to.StringPtr(azure.WindowsOS)
+	managedPool.Spec.OSType = to.Ptr(azure.WindowsOS)
 	return managedPool
 }
 
 func getMachinePoolWithVersion(name, version string) *capiv1exp.MachinePool {
 	machine := getMachinePool(name)
-	machine.Spec.Template.Spec.Version = to.StringPtr(version)
+	machine.Spec.Template.Spec.Version = to.Ptr(version)
 	return machine
 }
+
+// newTestManagedControlPlaneScope returns a control plane scope whose subscription is the one of its control plane.
+func newTestManagedControlPlaneScope(controlPlane *infrav1.AzureManagedControlPlane) *ManagedControlPlaneScope {
+	return &ManagedControlPlaneScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: EnvironmentSettings{
+				Values: map[string]string{envSubscriptionID: controlPlane.Spec.SubscriptionID},
+			},
+		},
+		ControlPlane: controlPlane,
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/datacollectionruleassociations"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loganalyticsworkspaces"
+)
+
+// getLogAnalyticsWorkspaceSpec returns the spec of the Log Analytics workspace created for a cluster onboarded to
+// Azure Monitor, or nil if the cluster isn't onboarded or sends its data to an existing workspace.
+func getLogAnalyticsWorkspaceSpec(cluster azure.ClusterDescriber, monitoring *infrav1.AzureMonitoring) azure.ResourceSpecGetter {
+	if monitoring == nil || monitoring.LogAnalyticsWorkspaceID != "" {
+		return nil
+	}
+
+	return &loganalyticsworkspaces.LogAnalyticsWorkspaceSpec{
+		Name:            azure.GenerateLogAnalyticsWorkspaceName(cluster.ClusterName()),
+		ResourceGroup:   cluster.ResourceGroup(),
+		Location:        cluster.Location(),
+		RetentionInDays: monitoring.RetentionInDays,
+		ClusterName:     cluster.ClusterName(),
+		AdditionalTags:  cluster.AdditionalTags(),
+	}
+}
+
+// getLogAnalyticsWorkspaceID returns the resource ID of the Log Analytics workspace receiving the metrics and the logs
+// of a cluster onboarded to Azure Monitor.
+func getLogAnalyticsWorkspaceID(cluster azure.ClusterDescriber, monitoring *infrav1.AzureMonitoring) string {
+	if monitoring.LogAnalyticsWorkspaceID != "" {
+		return monitoring.LogAnalyticsWorkspaceID
+	}
+	return azure.LogAnalyticsWorkspaceID(cluster.SubscriptionID(), cluster.ResourceGroup(), azure.GenerateLogAnalyticsWorkspaceName(cluster.ClusterName()))
+}
+
+// getDataCollectionRuleAssociationSpecs returns the specs associating a virtual machine or scale set with the data
+// collection rule and endpoint of its cluster, or nil if the cluster isn't onboarded to Azure Monitor.
+func getDataCollectionRuleAssociationSpecs(cluster azure.ClusterDescriber, resourceID string) []azure.ResourceSpecGetter {
+	if cluster.Monitoring() == nil {
+		return nil
+	}
+
+	return []azure.ResourceSpecGetter{
+		&datacollectionruleassociations.DataCollectionRuleAssociationSpec{
+			Name:                 azure.GenerateDataCollectionRuleName(cluster.ClusterName()),
+			ResourceGroup:        cluster.ResourceGroup(),
+			ResourceID:           resourceID,
+			DataCollectionRuleID: azure.DataCollectionRuleID(cluster.SubscriptionID(), cluster.ResourceGroup(), azure.GenerateDataCollectionRuleName(cluster.ClusterName())),
+		},
+		&datacollectionruleassociations.DataCollectionRuleAssociationSpec{
+			Name:                     datacollectionruleassociations.EndpointAssociationName,
+			ResourceGroup:            cluster.ResourceGroup(),
+			ResourceID:               resourceID,
+			DataCollectionEndpointID: azure.DataCollectionEndpointID(cluster.SubscriptionID(), cluster.ResourceGroup(), azure.GenerateDataCollectionEndpointName(cluster.ClusterName())),
+		},
+	}
+}
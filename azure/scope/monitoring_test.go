@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/datacollection"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/datacollectionruleassociations"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loganalyticsworkspaces"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const existingWorkspaceID = "/subscriptions/123/resourceGroups/monitoring/providers/Microsoft.OperationalInsights/workspaces/shared"
+
+// newMonitoredClusterScope returns the scope of a cluster onboarded to Azure Monitor as configured.
+func newMonitoredClusterScope(monitoring *infrav1.AzureMonitoring) *ClusterScope {
+	return &ClusterScope{
+		AzureClients: AzureClients{
+			EnvironmentSettings: EnvironmentSettings{
+				Values: map[string]string{envSubscriptionID: "123"},
+			},
+		},
+		Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+		AzureCluster: &infrav1.AzureCluster{
+			Spec: infrav1.AzureClusterSpec{
+				ResourceGroup: "my-rg",
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+					Location: "westus2",
+				},
+				Monitoring: monitoring,
+			},
+		},
+	}
+}
+
+func TestClusterScope_LogAnalyticsWorkspaceSpec(t *testing.T) {
+	cases := []struct {
+		Name       string
+		Monitoring *infrav1.AzureMonitoring
+		Expected   azure.ResourceSpecGetter
+	}{
+		{
+			Name:     "should return nil if the cluster isn't onboarded to Azure Monitor",
+			Expected: nil,
+		},
+		{
+			Name:       "should return nil if the cluster sends its data to an existing workspace",
+			Monitoring: &infrav1.AzureMonitoring{LogAnalyticsWorkspaceID: existingWorkspaceID},
+			Expected:   nil,
+		},
+		{
+			Name:       "should return the workspace spec if the cluster needs a workspace",
+			Monitoring: &infrav1.AzureMonitoring{RetentionInDays: to.Ptr[int32](90)},
+			Expected: &loganalyticsworkspaces.LogAnalyticsWorkspaceSpec{
+				Name:            "cluster1-logs",
+				ResourceGroup:   "my-rg",
+				Location:        "westus2",
+				RetentionInDays: to.Ptr[int32](90),
+				ClusterName:     "cluster1",
+				AdditionalTags:  infrav1.Tags{},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newMonitoredClusterScope(tc.Monitoring)
+
+			if tc.Expected == nil {
+				g.Expect(s.LogAnalyticsWorkspaceSpec()).To(BeNil())
+			} else {
+				g.Expect(s.LogAnalyticsWorkspaceSpec()).To(Equal(tc.Expected))
+			}
+		})
+	}
+}
+
+func TestClusterScope_DataCollectionSpecs(t *testing.T) {
+	cases := []struct {
+		Name                string
+		Monitoring          *infrav1.AzureMonitoring
+		ExpectedWorkspaceID string
+	}{
+		{
+			Name:                "should send the data to the workspace created for the cluster",
+			Monitoring:          &infrav1.AzureMonitoring{},
+			ExpectedWorkspaceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.OperationalInsights/workspaces/cluster1-logs",
+		},
+		{
+			Name:                "should send the data to an existing workspace",
+			Monitoring:          &infrav1.AzureMonitoring{LogAnalyticsWorkspaceID: existingWorkspaceID},
+			ExpectedWorkspaceID: existingWorkspaceID,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := newMonitoredClusterScope(tc.Monitoring)
+
+			endpointSpec, ruleSpec := s.DataCollectionSpecs()
+			g.Expect(endpointSpec).To(Equal(&datacollection.DataCollectionEndpointSpec{
+				Name:           "cluster1-dce",
+				ResourceGroup:  "my-rg",
+				Location:       "westus2",
+				ClusterName:    "cluster1",
+				AdditionalTags: infrav1.Tags{},
+			}))
+			g.Expect(ruleSpec).To(Equal(&datacollection.DataCollectionRuleSpec{
+				Name:           "cluster1-dcr",
+				ResourceGroup:  "my-rg",
+				Location:       "westus2",
+				WorkspaceID:    tc.ExpectedWorkspaceID,
+				ClusterName:    "cluster1",
+				AdditionalTags: infrav1.Tags{},
+			}))
+		})
+	}
+
+	t.Run("should return nil if the cluster isn't onboarded to Azure Monitor", func(t *testing.T) {
+		g := NewWithT(t)
+		endpointSpec, ruleSpec := newMonitoredClusterScope(nil).DataCollectionSpecs()
+		g.Expect(endpointSpec).To(BeNil())
+		g.Expect(ruleSpec).To(BeNil())
+	})
+}
+
+func TestGetDataCollectionRuleAssociationSpecs(t *testing.T) {
+	g := NewWithT(t)
+	vmID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/vm1"
+
+	g.Expect(getDataCollectionRuleAssociationSpecs(newMonitoredClusterScope(nil), vmID)).To(BeNil())
+	g.Expect(getDataCollectionRuleAssociationSpecs(newMonitoredClusterScope(&infrav1.AzureMonitoring{}), vmID)).To(Equal([]azure.ResourceSpecGetter{
+		&datacollectionruleassociations.DataCollectionRuleAssociationSpec{
+			Name:                 "cluster1-dcr",
+			ResourceGroup:        "my-rg",
+			ResourceID:           vmID,
+			DataCollectionRuleID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Insights/dataCollectionRules/cluster1-dcr",
+		},
+		&datacollectionruleassociations.DataCollectionRuleAssociationSpec{
+			Name:                     "configurationAccessEndpoint",
+			ResourceGroup:            "my-rg",
+			ResourceID:               vmID,
+			DataCollectionEndpointID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Insights/dataCollectionEndpoints/cluster1-dce",
+		},
+	}))
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"fmt"
+	"strings"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// multiInstanceGPUUnit is the name of the systemd unit partitioning the GPUs on every boot.
+const multiInstanceGPUUnit = "capz-multi-instance-gpu"
+
+// multiInstanceGPUScript waits for the NVIDIA driver to be loaded, enables MIG and recreates the GPU instances, with
+// their default compute instance, on all the GPUs.
+const multiInstanceGPUScript = `set -e
+for i in $(seq 1 60); do
+  nvidia-smi -L >/dev/null 2>&1 && break
+  if [ "$i" -eq 60 ]; then
+    echo "the NVIDIA driver is not loaded" >&2
+    exit 1
+  fi
+  sleep 5
+done
+nvidia-smi -mig 1
+nvidia-smi --gpu-reset || true
+nvidia-smi mig -dci || true
+nvidia-smi mig -dgi || true
+nvidia-smi mig -cgi %s -C
+`
+
+// getMultiInstanceGPUCloudConfig returns a cloud-config installing a systemd unit which partitions each GPU of a
+// virtual machine into GPU instances of a MIG profile on every boot, as the GPU instances don't survive a reboot, and
+// the GPUs of a VM moved to another host don't even have MIG enabled.
+func getMultiInstanceGPUCloudConfig(mig *infrav1.MultiInstanceGPU) *cloudConfig {
+	profiles := make([]string, mig.GetInstancesPerGPU())
+	for i := range profiles {
+		profiles[i] = mig.Profile
+	}
+
+	config := newCloudConfig()
+	config.addBootUnit(multiInstanceGPUUnit, "Partition the GPUs into "+mig.Profile+" MIG instances",
+		fmt.Sprintf(multiInstanceGPUScript, strings.Join(profiles, ",")))
+	return config
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestGetMultiInstanceGPUCloudConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		mig    *infrav1.MultiInstanceGPU
+		expect func(g *WithT, data string)
+	}{
+		{
+			name: "partitions the GPUs on every boot",
+			mig:  &infrav1.MultiInstanceGPU{Profile: "2g.20gb", InstancesPerGPU: to.Ptr[int32](3)},
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(ContainSubstring(`"path":"/usr/local/sbin/capz-multi-instance-gpu"`))
+				g.Expect(data).To(ContainSubstring(`"path":"/etc/systemd/system/capz-multi-instance-gpu.service"`))
+				g.Expect(data).To(ContainSubstring(`Description=Partition the GPUs into 2g.20gb MIG instances`))
+				g.Expect(data).To(ContainSubstring(`Before=containerd.service kubelet.service`))
+				g.Expect(data).To(ContainSubstring(`"runcmd":[["systemctl","daemon-reload"],["systemctl","enable","--now","capz-multi-instance-gpu.service"]]`))
+				g.Expect(data).To(ContainSubstring(`nvidia-smi -mig 1\n`))
+				g.Expect(data).To(ContainSubstring(`nvidia-smi mig -cgi 2g.20gb,2g.20gb,2g.20gb -C\n`))
+			},
+		},
+		{
+			name: "defaults to as many instances as fit on a GPU",
+			mig:  &infrav1.MultiInstanceGPU{Profile: "3g.40gb"},
+			expect: func(g *WithT, data string) {
+				g.Expect(data).To(ContainSubstring(`nvidia-smi mig -cgi 3g.40gb,3g.40gb -C\n`))
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			data, err := getMultiInstanceGPUCloudConfig(tc.mig).render()
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, data)
+		})
+	}
+}
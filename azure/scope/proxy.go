@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"fmt"
+	"strings"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+const (
+	// proxyAPTConfigPath is the APT configuration file setting the proxy of the package downloads.
+	proxyAPTConfigPath = "/etc/apt/apt.conf.d/95capz-proxy"
+	// proxyContainerdDropInPath is the systemd drop-in setting the proxy environment of containerd, used to pull images.
+	proxyContainerdDropInPath = "/etc/systemd/system/containerd.service.d/http-proxy.conf"
+)
+
+// defaultNoProxy lists the addresses machines always reach without the proxy: localhost, and the Azure Instance
+// Metadata Service and wire server the Azure Linux agent and cloud-init talk to.
+var defaultNoProxy = []string{"localhost", "127.0.0.1", "169.254.169.254", "168.63.129.16"}
+
+// getProxyConfig returns the proxy configuration of the machines of a cluster, with the addresses always reached
+// without the proxy and the address space of its virtual network added to the no-proxy list, or nil if the cluster
+// has no proxy.
+func getProxyConfig(proxy *infrav1.ProxyConfig, vnet *infrav1.VnetSpec) *infrav1.ProxyConfig {
+	if proxy == nil {
+		return nil
+	}
+
+	config := proxy.DeepCopy()
+	config.NoProxy = append(append([]string{}, defaultNoProxy...), proxy.NoProxy...)
+	if vnet != nil {
+		config.NoProxy = append(config.NoProxy, vnet.CIDRBlocks...)
+	}
+	return config
+}
+
+// getProxyEnvironment returns the proxy environment variables, formatted as NAME=value. Each variable is set in lower
+// and upper case, as tools disagree on the one they read.
+func getProxyEnvironment(proxy *infrav1.ProxyConfig) []string {
+	var env []string
+	add := func(name, value string) {
+		if value != "" {
+			env = append(env, fmt.Sprintf("%s=%s", strings.ToLower(name), value), fmt.Sprintf("%s=%s", name, value))
+		}
+	}
+	add("HTTP_PROXY", proxy.HTTPProxy)
+	add("HTTPS_PROXY", proxy.HTTPSProxy)
+	add("NO_PROXY", strings.Join(proxy.NoProxy, ","))
+	return env
+}
+
+// getProxyCloudConfig returns a cloud-config writing the proxy configuration to the environment, APT and containerd of
+// a virtual machine running the given OS distribution, before it is bootstrapped.
+func getProxyCloudConfig(proxy *infrav1.ProxyConfig, distro infrav1.OSDistro) *cloudConfig {
+	if distro == "" {
+		distro = infrav1.OSDistroUbuntu
+	}
+
+	env := getProxyEnvironment(proxy)
+	config := newCloudConfig()
+	config.WriteFiles = append(config.WriteFiles, cloudConfigFile{
+		Path:    "/etc/environment",
+		Content: strings.Join(env, "\n") + "\n",
+		Append:  true,
+	})
+
+	if distro == infrav1.OSDistroUbuntu {
+		var apt strings.Builder
+		if proxy.HTTPProxy != "" {
+			fmt.Fprintf(&apt, "Acquire::http::Proxy \"%s\";\n", proxy.HTTPProxy)
+		}
+		if proxy.HTTPSProxy != "" {
+			fmt.Fprintf(&apt, "Acquire::https::Proxy \"%s\";\n", proxy.HTTPSProxy)
+		}
+		config.WriteFiles = append(config.WriteFiles, cloudConfigFile{
+			Path:        proxyAPTConfigPath,
+			Content:     apt.String(),
+			Owner:       "root:root",
+			Permissions: "0644",
+		})
+	}
+
+	var dropIn strings.Builder
+	dropIn.WriteString("[Service]\n")
+	for _, variable := range env {
+		fmt.Fprintf(&dropIn, "Environment=\"%s\"\n", variable)
+	}
+	config.WriteFiles = append(config.WriteFiles, cloudConfigFile{
+		Path:        proxyContainerdDropInPath,
+		Content:     dropIn.String(),
+		Owner:       "root:root",
+		Permissions: "0644",
+	})
+	// containerd may already be running when cloud-init writes its drop-in.
+	config.RunCmd = append(config.RunCmd, []string{"sh", "-c", "systemctl daemon-reload && systemctl try-restart containerd"})
+
+	return config
+}
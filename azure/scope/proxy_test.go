@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestGetProxyConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(getProxyConfig(nil, &infrav1.VnetSpec{})).To(BeNil())
+
+	proxy := &infrav1.ProxyConfig{
+		HTTPProxy: "http://proxy.example.com:3128",
+		NoProxy:   []string{".svc", "10.96.0.0/12"},
+	}
+	config := getProxyConfig(proxy, &infrav1.VnetSpec{VnetClassSpec: infrav1.VnetClassSpec{CIDRBlocks: []string{"10.0.0.0/8"}}})
+	g.Expect(config.HTTPProxy).To(Equal("http://proxy.example.com:3128"))
+	g.Expect(config.NoProxy).To(Equal([]string{"localhost", "127.0.0.1", "169.254.169.254", "168.63.129.16", ".svc", "10.96.0.0/12", "10.0.0.0/8"}))
+	// the proxy configuration of the cluster is left unchanged.
+	g.Expect(proxy.NoProxy).To(Equal([]string{".svc", "10.96.0.0/12"}))
+}
+
+func TestGetProxyCloudConfig(t *testing.T) {
+	proxy := &infrav1.ProxyConfig{
+		HTTPProxy:  "http://proxy.example.com:3128",
+		HTTPSProxy: "http://proxy.example.com:3129",
+		NoProxy:    []string{"localhost", ".svc"},
+	}
+
+	tests := []struct {
+		name   string
+		distro infrav1.OSDistro
+		expect func(g *WithT, config *cloudConfig)
+	}{
+		{
+			name: "configures the environment, APT and containerd on Ubuntu by default",
+			expect: func(g *WithT, config *cloudConfig) {
+				g.Expect(config.WriteFiles).To(Equal([]cloudConfigFile{
+					{
+						Path: "/etc/environment",
+						Content: "http_proxy=http://proxy.example.com:3128\nHTTP_PROXY=http://proxy.example.com:3128\n" +
+							"https_proxy=http://proxy.example.com:3129\nHTTPS_PROXY=http://proxy.example.com:3129\n" +
+							"no_proxy=localhost,.svc\nNO_PROXY=localhost,.svc\n",
+						Append: true,
+					},
+					{
+						Path:        proxyAPTConfigPath,
+						Content:     "Acquire::http::Proxy \"http://proxy.example.com:3128\";\nAcquire::https::Proxy \"http://proxy.example.com:3129\";\n",
+						Owner:       "root:root",
+						Permissions: "0644",
+					},
+					{
+						Path: proxyContainerdDropInPath,
+						Content: "[Service]\n" +
+							"Environment=\"http_proxy=http://proxy.example.com:3128\"\nEnvironment=\"HTTP_PROXY=http://proxy.example.com:3128\"\n" +
+							"Environment=\"https_proxy=http://proxy.example.com:3129\"\nEnvironment=\"HTTPS_PROXY=http://proxy.example.com:3129\"\n" +
+							"Environment=\"no_proxy=localhost,.svc\"\nEnvironment=\"NO_PROXY=localhost,.svc\"\n",
+						Owner:       "root:root",
+						Permissions: "0644",
+					},
+				}))
+				g.Expect(config.RunCmd).To(Equal([][]string{{"sh", "-c", "systemctl daemon-reload && systemctl try-restart containerd"}}))
+			},
+		},
+		{
+			name:   "doesn't configure APT on Azure Linux",
+			distro: infrav1.OSDistroAzureLinux,
+			expect: func(g *WithT, config *cloudConfig) {
+				g.Expect(config.WriteFiles).To(HaveLen(2))
+				g.Expect(config.WriteFiles[0].Path).To(Equal("/etc/environment"))
+				g.Expect(config.WriteFiles[1].Path).To(Equal(proxyContainerdDropInPath))
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			tc.expect(g, getProxyCloudConfig(proxy, tc.distro))
+		})
+	}
+}
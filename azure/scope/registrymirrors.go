@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// registryHostsDir is the directory of the containerd registry host configuration.
+	// See https://github.com/containerd/containerd/blob/main/docs/hosts.md.
+	registryHostsDir = "/etc/containerd/certs.d"
+	// registryMirrorsContainerdConfigPath is the containerd configuration file, imported by the configuration of the
+	// reference images, pointing the CRI plugin to the registry host configuration.
+	registryMirrorsContainerdConfigPath = "/etc/containerd/conf.d/capz-registry-mirrors.toml"
+	// dockerHubRegistry is the name of the Docker Hub registry in image references.
+	dockerHubRegistry = "docker.io"
+	// dockerHubServer is the URL of the Docker Hub registry API.
+	dockerHubServer = "https://registry-1.docker.io"
+)
+
+// registryMirror is a registry mirror with the Authorization header of its credentials, if any.
+type registryMirror struct {
+	infrav1.RegistryMirror
+	authorization string
+}
+
+// getRegistryMirrors returns the registry mirrors of a cluster, reading the Secrets holding their credentials from the
+// given namespace.
+func getRegistryMirrors(ctx context.Context, c client.Client, namespace string, mirrors []infrav1.RegistryMirror) ([]registryMirror, error) {
+	result := make([]registryMirror, 0, len(mirrors))
+	for _, mirror := range mirrors {
+		m := registryMirror{RegistryMirror: mirror}
+		if ref := mirror.CredentialsSecretRef; ref != nil {
+			secret := &corev1.Secret{}
+			if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+				return nil, errors.Wrapf(err, "failed to get credentials secret %s/%s of the %s registry mirrors", namespace, ref.Name, mirror.Registry)
+			}
+			username, ok := secret.Data[corev1.BasicAuthUsernameKey]
+			if !ok {
+				return nil, errors.Errorf("registry credentials secret %s/%s has no key %q", namespace, ref.Name, corev1.BasicAuthUsernameKey)
+			}
+			password, ok := secret.Data[corev1.BasicAuthPasswordKey]
+			if !ok {
+				return nil, errors.Errorf("registry credentials secret %s/%s has no key %q", namespace, ref.Name, corev1.BasicAuthPasswordKey)
+			}
+			m.authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(string(username)+":"+string(password)))
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// getRegistryMirrorsCloudConfig returns a cloud-config writing the registry host configuration of the mirrors to
+// containerd, before the virtual machine is bootstrapped. containerd is restarted if it is already running, as it only
+// loads the path of the registry host configuration on startup.
+func getRegistryMirrorsCloudConfig(mirrors []registryMirror) *cloudConfig {
+	config := newCloudConfig()
+	config.WriteFiles = append(config.WriteFiles, cloudConfigFile{
+		Path:        registryMirrorsContainerdConfigPath,
+		Content:     fmt.Sprintf("version = 2\n\n[plugins.\"io.containerd.grpc.v1.cri\".registry]\n  config_path = %q\n", registryHostsDir),
+		Owner:       "root:root",
+		Permissions: "0644",
+	})
+	for _, mirror := range mirrors {
+		permissions := "0644"
+		if mirror.authorization != "" {
+			permissions = "0600"
+		}
+		config.WriteFiles = append(config.WriteFiles, cloudConfigFile{
+			Path:        path.Join(registryHostsDir, mirror.Registry, "hosts.toml"),
+			Content:     getRegistryHostsConfig(mirror),
+			Owner:       "root:root",
+			Permissions: permissions,
+		})
+	}
+	config.RunCmd = append(config.RunCmd, []string{"systemctl", "try-restart", "containerd"})
+	return config
+}
+
+// getRegistryHostsConfig returns the containerd registry host configuration of a registry mirror.
+func getRegistryHostsConfig(mirror registryMirror) string {
+	var hosts strings.Builder
+	switch mirror.Registry {
+	case infrav1.DefaultRegistryMirror:
+		// The default configuration falls back to the registry of each image.
+	case dockerHubRegistry:
+		fmt.Fprintf(&hosts, "server = %q\n", dockerHubServer)
+	default:
+		fmt.Fprintf(&hosts, "server = %q\n", "https://"+mirror.Registry)
+	}
+
+	for _, endpoint := range mirror.Endpoints {
+		fmt.Fprintf(&hosts, "\n[host.%q]\n", endpoint)
+		hosts.WriteString("  capabilities = [\"pull\", \"resolve\"]\n")
+		if u, err := url.Parse(endpoint); err == nil && strings.Trim(u.Path, "/") != "" {
+			hosts.WriteString("  override_path = true\n")
+		}
+		if mirror.authorization != "" {
+			fmt.Fprintf(&hosts, "  [host.%q.header]\n", endpoint)
+			fmt.Fprintf(&hosts, "    Authorization = %q\n", mirror.authorization)
+		}
+	}
+	return hosts.String()
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetRegistryMirrors(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	secrets := []*corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "mirror-credentials", Namespace: "default"},
+			Type:       corev1.SecretTypeBasicAuth,
+			Data:       map[string][]byte{"username": []byte("user"), "password": []byte("secret")},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "no-password", Namespace: "default"},
+			Data:       map[string][]byte{"username": []byte("user")},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		mirrors     []infrav1.RegistryMirror
+		expected    []registryMirror
+		expectedErr string
+	}{
+		{
+			name:     "no mirrors",
+			expected: []registryMirror{},
+		},
+		{
+			name: "mirrors with and without credentials",
+			mirrors: []infrav1.RegistryMirror{
+				{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}, CredentialsSecretRef: &infrav1.RegistryCredentialsReference{Name: "mirror-credentials"}},
+				{Registry: "registry.k8s.io", Endpoints: []string{"https://mirror.example.com"}},
+			},
+			expected: []registryMirror{
+				{
+					RegistryMirror: infrav1.RegistryMirror{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}, CredentialsSecretRef: &infrav1.RegistryCredentialsReference{Name: "mirror-credentials"}},
+					authorization:  "Basic dXNlcjpzZWNyZXQ=",
+				},
+				{
+					RegistryMirror: infrav1.RegistryMirror{Registry: "registry.k8s.io", Endpoints: []string{"https://mirror.example.com"}},
+				},
+			},
+		},
+		{
+			name: "missing secret",
+			mirrors: []infrav1.RegistryMirror{
+				{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}, CredentialsSecretRef: &infrav1.RegistryCredentialsReference{Name: "missing"}},
+			},
+			expectedErr: "failed to get credentials secret default/missing of the docker.io registry mirrors",
+		},
+		{
+			name: "secret without password",
+			mirrors: []infrav1.RegistryMirror{
+				{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}, CredentialsSecretRef: &infrav1.RegistryCredentialsReference{Name: "no-password"}},
+			},
+			expectedErr: "registry credentials secret default/no-password has no key \"password\"",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secrets[0], secrets[1]).Build()
+
+			mirrors, err := getRegistryMirrors(context.TODO(), c, "default", tc.mirrors)
+			if tc.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(mirrors).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestGetRegistryHostsConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		mirror   registryMirror
+		expected string
+	}{
+		{
+			name: "Docker Hub mirror with credentials",
+			mirror: registryMirror{
+				RegistryMirror: infrav1.RegistryMirror{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}},
+				authorization:  "Basic dXNlcjpzZWNyZXQ=",
+			},
+			expected: `server = "https://registry-1.docker.io"
+
+[host."https://mirror.example.com"]
+  capabilities = ["pull", "resolve"]
+  [host."https://mirror.example.com".header]
+    Authorization = "Basic dXNlcjpzZWNyZXQ="
+`,
+		},
+		{
+			name: "registry mirrors with a path",
+			mirror: registryMirror{
+				RegistryMirror: infrav1.RegistryMirror{Registry: "registry.example.com:5000", Endpoints: []string{"https://harbor.example.com/v2/example", "http://10.0.0.4:5000"}},
+			},
+			expected: `server = "https://registry.example.com:5000"
+
+[host."https://harbor.example.com/v2/example"]
+  capabilities = ["pull", "resolve"]
+  override_path = true
+
+[host."http://10.0.0.4:5000"]
+  capabilities = ["pull", "resolve"]
+`,
+		},
+		{
+			name: "default mirror",
+			mirror: registryMirror{
+				RegistryMirror: infrav1.RegistryMirror{Registry: infrav1.DefaultRegistryMirror, Endpoints: []string{"https://mirror.example.com"}},
+			},
+			expected: `
+[host."https://mirror.example.com"]
+  capabilities = ["pull", "resolve"]
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(getRegistryHostsConfig(tc.mirror)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestGetRegistryMirrorsCloudConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	config := getRegistryMirrorsCloudConfig([]registryMirror{
+		{RegistryMirror: infrav1.RegistryMirror{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}}, authorization: "Basic dXNlcjpzZWNyZXQ="},
+		{RegistryMirror: infrav1.RegistryMirror{Registry: "registry.k8s.io", Endpoints: []string{"https://mirror.example.com"}}},
+	})
+	g.Expect(config.WriteFiles).To(HaveLen(3))
+	g.Expect(config.WriteFiles[0].Path).To(Equal(registryMirrorsContainerdConfigPath))
+	g.Expect(config.WriteFiles[0].Content).To(ContainSubstring(`config_path = "/etc/containerd/certs.d"`))
+	g.Expect(config.WriteFiles[1].Path).To(Equal("/etc/containerd/certs.d/docker.io/hosts.toml"))
+	g.Expect(config.WriteFiles[1].Permissions).To(Equal("0600"))
+	g.Expect(config.WriteFiles[2].Path).To(Equal("/etc/containerd/certs.d/registry.k8s.io/hosts.toml"))
+	g.Expect(config.WriteFiles[2].Permissions).To(Equal("0644"))
+	g.Expect(config.RunCmd).To(Equal([][]string{{"systemctl", "try-restart", "containerd"}}))
+}
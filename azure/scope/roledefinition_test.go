@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roledefinitions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClusterScopeSaveRoleDefinition(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-cluster",
+			Namespace:   "default",
+			UID:         "uid",
+			Annotations: map[string]string{infrav1.RoleDefinitionExportRequestAnnotation: "1"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	clusterScope := &ClusterScope{Client: c, AzureCluster: azureCluster}
+
+	g.Expect(clusterScope.RoleDefinitionExportRequested()).To(BeTrue())
+	g.Expect(clusterScope.RoleDefinitionCreationRequested()).To(BeFalse())
+	roleDefinition := roledefinitions.New("123", "default", "my-cluster", []string{"Microsoft.Network/bastionHosts/write"})
+	g.Expect(clusterScope.SaveRoleDefinition(context.TODO(), roleDefinition)).To(Succeed())
+	g.Expect(clusterScope.RoleDefinitionExportRequested()).To(BeFalse())
+	g.Expect(azureCluster.Status.LastRoleDefinitionExportRequest).To(Equal("1"))
+
+	configMap := &corev1.ConfigMap{}
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "my-cluster-role-definition"}, configMap)).To(Succeed())
+	g.Expect(configMap.Data[infrav1.RoleDefinitionKey]).To(ContainSubstring(`"Name": "capz-default-my-cluster"`))
+	g.Expect(configMap.Data[infrav1.RoleDefinitionKey]).To(ContainSubstring(`"Microsoft.Network/bastionHosts/delete"`))
+	g.Expect(configMap.OwnerReferences).To(HaveLen(1))
+	g.Expect(configMap.OwnerReferences[0].Kind).To(Equal("AzureCluster"))
+
+	// The creation of the role definition is requested separately.
+	azureCluster.Annotations[infrav1.RoleDefinitionCreateAnnotation] = "true"
+	g.Expect(clusterScope.RoleDefinitionCreationRequested()).To(BeTrue())
+}
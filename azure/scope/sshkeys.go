@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	utilSSH "sigs.k8s.io/cluster-api-provider-azure/util/ssh"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// sshPublicKeySecretKey is the Secret key holding the public key of a generated SSH key pair in authorized_keys format.
+const sshPublicKeySecretKey = "ssh-publickey"
+
+// ClusterSSHKeySecretName returns the name of the Secret holding the SSH key pair generated for a cluster.
+func ClusterSSHKeySecretName(clusterName string) string {
+	return fmt.Sprintf("%s-ssh", clusterName)
+}
+
+// ReconcileSSHKeyPair ensures the cluster has an SSH key pair, generating one and storing it in a Secret when missing,
+// and records its public key on the AzureCluster status.
+func (s *ClusterScope) ReconcileSSHKeyPair(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.ClusterScope.ReconcileSSHKeyPair")
+	defer done()
+
+	publicKey, err := getClusterSSHPublicKey(ctx, s.Client, s.Namespace(), s.ClusterName())
+	switch {
+	case err == nil:
+		s.AzureCluster.Status.SSHPublicKey = publicKey
+		return nil
+	case !apierrors.IsNotFound(errors.Cause(err)):
+		return err
+	}
+
+	secret, err := s.newSSHKeySecret()
+	if err != nil {
+		return err
+	}
+	if err := s.Client.Create(ctx, secret); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			// Another reconcile created the Secret, its public key is recorded on the next one.
+			return nil
+		}
+		return errors.Wrapf(err, "failed to create SSH key Secret %s/%s", secret.Namespace, secret.Name)
+	}
+	log.V(2).Info("generated SSH key pair for cluster", "secret", secret.Name)
+	s.AzureCluster.Status.SSHPublicKey = base64.StdEncoding.EncodeToString(secret.Data[sshPublicKeySecretKey])
+	return nil
+}
+
+// newSSHKeySecret generates an SSH key pair and returns the Secret storing it for the cluster.
+func (s *ClusterScope) newSSHKeySecret() (*corev1.Secret, error) {
+	privateKey, publicKey, err := utilSSH.GenerateSSHKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate SSH key pair")
+	}
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ClusterSSHKeySecretName(s.ClusterName()),
+			Namespace: s.Namespace(),
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: s.ClusterName(),
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(s.AzureCluster, infrav1.GroupVersion.WithKind("AzureCluster")),
+			},
+		},
+		Type: corev1.SecretTypeSSHAuth,
+		Data: map[string][]byte{
+			corev1.SSHAuthPrivateKey: privateKeyPEM,
+			sshPublicKeySecretKey:    ssh.MarshalAuthorizedKey(publicKey),
+		},
+	}, nil
+}
+
+// getClusterSSHPublicKey returns the base64 encoded public key of the SSH key pair generated for a cluster.
+func getClusterSSHPublicKey(ctx context.Context, c client.Client, namespace, clusterName string) (string, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: ClusterSSHKeySecretName(clusterName)}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, key, secret); err != nil {
+		return "", errors.Wrapf(err, "failed to get SSH key Secret %s", key)
+	}
+	publicKey, ok := secret.Data[sshPublicKeySecretKey]
+	if !ok {
+		return "", errors.Errorf("SSH key Secret %s has no key %q", key, sshPublicKeySecretKey)
+	}
+	return base64.StdEncoding.EncodeToString(publicKey), nil
+}
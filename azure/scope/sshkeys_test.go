@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileSSHKeyPair(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-ssh", Namespace: "default"},
+		Type:       corev1.SecretTypeSSHAuth,
+		Data: map[string][]byte{
+			corev1.SSHAuthPrivateKey: []byte("private"),
+			"ssh-publickey":          []byte("ssh-rsa AAAA"),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		objects     []*corev1.Secret
+		expectedKey string
+		expectedErr string
+	}{
+		{
+			name: "generates a key pair",
+		},
+		{
+			name:        "reuses the existing key pair",
+			objects:     []*corev1.Secret{existing},
+			expectedKey: base64.StdEncoding.EncodeToString([]byte("ssh-rsa AAAA")),
+		},
+		{
+			name: "existing Secret without a public key",
+			objects: []*corev1.Secret{{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-ssh", Namespace: "default"},
+			}},
+			expectedErr: "SSH key Secret default/my-cluster-ssh has no key \"ssh-publickey\"",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			for _, obj := range tc.objects {
+				builder = builder.WithObjects(obj.DeepCopy())
+			}
+			c := builder.Build()
+			s := &ClusterScope{
+				Client:  c,
+				Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"}},
+				AzureCluster: &infrav1.AzureCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-azure-cluster", Namespace: "default", UID: "uid"},
+				},
+			}
+
+			err := s.ReconcileSSHKeyPair(context.TODO())
+			if tc.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			g.Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "my-cluster-ssh"}, secret)).To(Succeed())
+			g.Expect(s.AzureCluster.Status.SSHPublicKey).To(Equal(base64.StdEncoding.EncodeToString(secret.Data["ssh-publickey"])))
+			if tc.expectedKey != "" {
+				g.Expect(s.AzureCluster.Status.SSHPublicKey).To(Equal(tc.expectedKey))
+				return
+			}
+
+			g.Expect(secret.Type).To(Equal(corev1.SecretTypeSSHAuth))
+			g.Expect(secret.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "my-cluster"))
+			g.Expect(secret.OwnerReferences).To(HaveLen(1))
+			g.Expect(secret.OwnerReferences[0].Name).To(Equal("my-azure-cluster"))
+			block, _ := pem.Decode(secret.Data[corev1.SSHAuthPrivateKey])
+			g.Expect(block).NotTo(BeNil())
+			g.Expect(block.Type).To(Equal("RSA PRIVATE KEY"))
+			_, _, _, _, err = ssh.ParseAuthorizedKey(secret.Data["ssh-publickey"])
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestGetClusterSSHPublicKey(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster-ssh", Namespace: "default"},
+		Data:       map[string][]byte{"ssh-publickey": []byte("ssh-rsa AAAA")},
+	}).Build()
+
+	key, err := getClusterSSHPublicKey(context.TODO(), c, "default", "my-cluster")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(key).To(Equal(base64.StdEncoding.EncodeToString([]byte("ssh-rsa AAAA"))))
+
+	_, err = getClusterSSHPublicKey(context.TODO(), c, "default", "other-cluster")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to get SSH key Secret default/other-cluster-ssh"))
+}
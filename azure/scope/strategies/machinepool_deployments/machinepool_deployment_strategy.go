@@ -174,25 +174,37 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 	if overProvisionCount > 0 {
 		var toDelete []infrav1exp.AzureMachinePoolMachine
 		log.Info("over-provisioned", "desiredReplicaCount", desiredReplicaCount, "overProvisionCount", overProvisionCount, "machinesWithoutLatestModel", getProviderIDs(machinesWithoutLatestModel))
-		// we are over-provisioned try to remove old models
+		// we are over-provisioned try to remove old models, except the machines protected from scale-in
 		for _, v := range machinesWithoutLatestModel {
 			if len(toDelete) >= overProvisionCount {
 				return toDelete, nil
 			}
 
+			if v.Status.ProtectedFromScaleIn {
+				continue
+			}
+
 			toDelete = append(toDelete, v)
 		}
 
 		log.Info("over-provisioned ready", "desiredReplicaCount", desiredReplicaCount, "overProvisionCount", overProvisionCount, "readyMachines", getProviderIDs(readyMachines))
-		// remove ready machines
+		// remove ready machines, except the machines protected from scale-in
 		for _, v := range readyMachines {
 			if len(toDelete) >= overProvisionCount {
 				return toDelete, nil
 			}
 
+			if v.Status.ProtectedFromScaleIn {
+				continue
+			}
+
 			toDelete = append(toDelete, v)
 		}
 
+		if len(toDelete) < overProvisionCount {
+			log.Info("not enough machines unprotected from scale-in to reach the desired replica count", "desiredReplicaCount", desiredReplicaCount, "overProvisionCount", overProvisionCount, "toDelete", getProviderIDs(toDelete))
+		}
+
 		return toDelete, nil
 	}
 
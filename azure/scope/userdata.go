@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxUserDataSize is the maximum size of the base64 encoded user data of a virtual machine.
+	maxUserDataSize = 64 * 1024
+	// defaultUserDataSecretKey is the Secret key holding user data when none is specified.
+	defaultUserDataSecretKey = "value"
+	// userDataBoundary is the boundary of the MIME multi-part document merging user data sections.
+	// It is fixed so that the user data of a machine doesn't change between reconciliations.
+	userDataBoundary = "==CAPZ-USERDATA-BOUNDARY=="
+)
+
+// userDataContentTypes maps the cloud-init user data format prefixes to their MIME type.
+// See https://cloudinit.readthedocs.io/en/latest/topics/format.html.
+var userDataContentTypes = []struct {
+	prefix      string
+	contentType string
+}{
+	{prefix: "## template: jinja", contentType: "text/jinja2"},
+	{prefix: "#cloud-config", contentType: "text/cloud-config"},
+	{prefix: "#cloud-boothook", contentType: "text/cloud-boothook"},
+	{prefix: "#include", contentType: "text/x-include-url"},
+	{prefix: "#!", contentType: "text/x-shellscript"},
+}
+
+// getUserData returns the base64 encoded user data of a virtual machine, reading sections referencing a Secret from
+// the given namespace. It returns an empty string when no user data is specified.
+func getUserData(ctx context.Context, c client.Client, namespace string, userData *infrav1.UserData) (string, error) {
+	if userData == nil || len(userData.Sections) == 0 {
+		return "", nil
+	}
+
+	sections := make([]string, 0, len(userData.Sections))
+	for _, section := range userData.Sections {
+		content, err := getUserDataSectionContent(ctx, c, namespace, section)
+		if err != nil {
+			return "", err
+		}
+		sections = append(sections, content)
+	}
+
+	merged := sections[0]
+	if len(sections) > 1 {
+		var err error
+		if merged, err = mergeUserDataSections(sections); err != nil {
+			return "", errors.Wrap(err, "failed to merge user data sections")
+		}
+	}
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(merged))
+	if len(encoded) > maxUserDataSize {
+		return "", errors.Errorf("user data is %d bytes once encoded, exceeding the maximum size of %d bytes", len(encoded), maxUserDataSize)
+	}
+
+	return encoded, nil
+}
+
+// getUserDataSectionContent returns the content of a user data section.
+func getUserDataSectionContent(ctx context.Context, c client.Client, namespace string, section infrav1.UserDataSection) (string, error) {
+	if section.SecretRef == nil {
+		return section.Content, nil
+	}
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: namespace, Name: section.SecretRef.Name}
+	if err := c.Get(ctx, key, secret); err != nil {
+		return "", errors.Wrapf(err, "failed to get user data secret %s/%s", namespace, section.SecretRef.Name)
+	}
+
+	dataKey := section.SecretRef.Key
+	if dataKey == "" {
+		dataKey = defaultUserDataSecretKey
+	}
+	value, ok := secret.Data[dataKey]
+	if !ok {
+		return "", errors.Errorf("user data secret %s/%s has no key %q", namespace, section.SecretRef.Name, dataKey)
+	}
+
+	return string(value), nil
+}
+
+// mergeUserDataSections merges user data sections into a MIME multi-part document.
+func mergeUserDataSections(sections []string) (string, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.SetBoundary(userDataBoundary); err != nil {
+		return "", err
+	}
+
+	for i, section := range sections {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", fmt.Sprintf("%s; charset=\"utf-8\"", userDataContentType(section)))
+		header.Set("MIME-Version", "1.0")
+		header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"part-%03d\"", i))
+		part, err := w.CreatePart(header)
+		if err != nil {
+			return "", err
+		}
+		if _, err := part.Write([]byte(section)); err != nil {
+			return "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Content-Type: multipart/mixed; boundary=\"%s\"\r\nMIME-Version: 1.0\r\n\r\n%s", userDataBoundary, body.String()), nil
+}
+
+// userDataContentType returns the MIME type of a user data section from its format prefix.
+func userDataContentType(section string) string {
+	for _, t := range userDataContentTypes {
+		if strings.HasPrefix(section, t.prefix) {
+			return t.contentType
+		}
+	}
+	return "text/plain"
+}
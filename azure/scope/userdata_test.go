@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetUserData(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "agent",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"value":      []byte("#!/bin/bash\necho value\n"),
+			"install.sh": []byte("#!/bin/bash\necho install\n"),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		userData    *infrav1.UserData
+		expect      func(g *WithT, decoded string)
+		expectedErr string
+	}{
+		{
+			name:     "no user data",
+			userData: nil,
+			expect: func(g *WithT, decoded string) {
+				g.Expect(decoded).To(BeEmpty())
+			},
+		},
+		{
+			name: "single inline section is passed as-is",
+			userData: &infrav1.UserData{
+				Sections: []infrav1.UserDataSection{{Content: "#cloud-config\nruncmd: []\n"}},
+			},
+			expect: func(g *WithT, decoded string) {
+				g.Expect(decoded).To(Equal("#cloud-config\nruncmd: []\n"))
+			},
+		},
+		{
+			name: "single secret section with default key",
+			userData: &infrav1.UserData{
+				Sections: []infrav1.UserDataSection{{SecretRef: &infrav1.UserDataSecretReference{Name: "agent"}}},
+			},
+			expect: func(g *WithT, decoded string) {
+				g.Expect(decoded).To(Equal("#!/bin/bash\necho value\n"))
+			},
+		},
+		{
+			name: "multiple sections are merged into a multi-part document",
+			userData: &infrav1.UserData{
+				Sections: []infrav1.UserDataSection{
+					{Content: "#cloud-config\nruncmd: []\n"},
+					{SecretRef: &infrav1.UserDataSecretReference{Name: "agent", Key: "install.sh"}},
+				},
+			},
+			expect: func(g *WithT, decoded string) {
+				g.Expect(decoded).To(HavePrefix("Content-Type: multipart/mixed; boundary=\"" + userDataBoundary + "\""))
+				g.Expect(decoded).To(ContainSubstring("Content-Type: text/cloud-config; charset=\"utf-8\"\r\n"))
+				g.Expect(decoded).To(ContainSubstring("Content-Type: text/x-shellscript; charset=\"utf-8\"\r\n"))
+				g.Expect(decoded).To(ContainSubstring("echo install"))
+				g.Expect(strings.Index(decoded, "runcmd")).To(BeNumerically("<", strings.Index(decoded, "echo install")))
+			},
+		},
+		{
+			name: "missing secret",
+			userData: &infrav1.UserData{
+				Sections: []infrav1.UserDataSection{{SecretRef: &infrav1.UserDataSecretReference{Name: "missing"}}},
+			},
+			expectedErr: "failed to get user data secret default/missing",
+		},
+		{
+			name: "missing secret key",
+			userData: &infrav1.UserData{
+				Sections: []infrav1.UserDataSection{{SecretRef: &infrav1.UserDataSecretReference{Name: "agent", Key: "missing"}}},
+			},
+			expectedErr: "user data secret default/agent has no key \"missing\"",
+		},
+		{
+			name: "user data exceeding the maximum size",
+			userData: &infrav1.UserData{
+				Sections: []infrav1.UserDataSection{{Content: strings.Repeat("a", maxUserDataSize)}},
+			},
+			expectedErr: "exceeding the maximum size",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+			encoded, err := getUserData(context.TODO(), c, "default", tc.userData)
+			if tc.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, string(decoded))
+		})
+	}
+}
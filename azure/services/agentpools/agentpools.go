@@ -19,15 +19,20 @@ package agentpools
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
 	infrav1alpha4 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/maps"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
@@ -42,6 +47,8 @@ type ManagedMachinePoolScope interface {
 	AgentPoolSpec() azure.AgentPoolSpec
 	SetAgentPoolProviderIDList([]string)
 	SetAgentPoolReplicas(int32)
+	SetAgentPoolReadyReplicas(int32)
+	SetAgentPoolStatus(*infrav1exp.AgentPoolStatus)
 	SetAgentPoolReady(bool)
 }
 
@@ -52,11 +59,15 @@ type Service struct {
 }
 
 // New creates a new service.
-func New(scope ManagedMachinePoolScope) *Service {
+func New(scope ManagedMachinePoolScope) (*Service, error) {
+	client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		scope:  scope,
-		Client: NewClient(scope),
-	}
+		Client: client,
+	}, nil
 }
 
 // Name returns the service name.
@@ -87,41 +98,56 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	customHeaders := maps.FilterByKeyPrefix(s.scope.AgentPoolAnnotations(), azure.CustomHeaderPrefix)
 	if isCreate := azure.ResourceNotFound(err); isCreate {
-		err = s.Client.CreateOrUpdate(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name,
-			profile, customHeaders)
+		err = s.createOrUpdate(ctx, agentPoolSpec, profile, customHeaders)
 		if err != nil && azure.ResourceNotFound(err) {
 			return azure.WithTransientError(errors.Wrap(err, "agent pool dependent resource does not exist yet"), 20*time.Second)
 		} else if err != nil {
 			return errors.Wrap(err, "failed to create or update agent pool")
 		}
 	} else {
-		ps := *existingPool.ManagedClusterAgentPoolProfileProperties.ProvisioningState
+		// Update the state of the agent pool reported by Azure.
+		s.scope.SetAgentPoolStatus(converters.SDKToAgentPoolStatus(existingPool))
+
+		ps := pointer.StringDeref(existingPool.Properties.ProvisioningState, "")
 		if ps != string(infrav1alpha4.Canceled) && ps != string(infrav1alpha4.Failed) && ps != string(infrav1alpha4.Succeeded) {
 			msg := fmt.Sprintf("Unable to update existing agent pool in non terminal state. Agent pool must be in one of the following provisioning states: canceled, failed, or succeeded. Actual state: %s", ps)
 			log.V(2).Info(msg)
 			return azure.WithTransientError(errors.New(msg), 20*time.Second)
 		}
 
+		// AKS keeps the node labels and taints of an agent pool when they are omitted, so removing all of them
+		// requires sending them empty.
+		if len(profile.Properties.NodeLabels) == 0 && len(existingPool.Properties.NodeLabels) > 0 {
+			profile.Properties.NodeLabels = map[string]*string{}
+		}
+		if normalizeNodeTaints(profile.Properties.NodeTaints) == nil && normalizeNodeTaints(existingPool.Properties.NodeTaints) != nil {
+			profile.Properties.NodeTaints = []*string{}
+		}
+
 		// Normalize individual agent pools to diff in case we need to update
-		existingProfile := containerservice.AgentPool{
-			ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
-				Count:               existingPool.Count,
-				OrchestratorVersion: existingPool.OrchestratorVersion,
-				Mode:                existingPool.Mode,
-				EnableAutoScaling:   existingPool.EnableAutoScaling,
-				MinCount:            existingPool.MinCount,
-				MaxCount:            existingPool.MaxCount,
+		existingProfile := armcontainerservice.AgentPool{
+			Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+				Count:               existingPool.Properties.Count,
+				OrchestratorVersion: existingPool.Properties.OrchestratorVersion,
+				Mode:                existingPool.Properties.Mode,
+				EnableAutoScaling:   existingPool.Properties.EnableAutoScaling,
+				MinCount:            existingPool.Properties.MinCount,
+				MaxCount:            existingPool.Properties.MaxCount,
+				NodeLabels:          normalizeNodeLabels(existingPool.Properties.NodeLabels),
+				NodeTaints:          normalizeNodeTaints(existingPool.Properties.NodeTaints),
 			},
 		}
 
-		normalizedProfile := containerservice.AgentPool{
-			ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
-				Count:               profile.Count,
-				OrchestratorVersion: profile.OrchestratorVersion,
-				Mode:                profile.Mode,
-				EnableAutoScaling:   profile.EnableAutoScaling,
-				MinCount:            profile.MinCount,
-				MaxCount:            profile.MaxCount,
+		normalizedProfile := armcontainerservice.AgentPool{
+			Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+				Count:               profile.Properties.Count,
+				OrchestratorVersion: profile.Properties.OrchestratorVersion,
+				Mode:                profile.Properties.Mode,
+				EnableAutoScaling:   profile.Properties.EnableAutoScaling,
+				MinCount:            profile.Properties.MinCount,
+				MaxCount:            profile.Properties.MaxCount,
+				NodeLabels:          normalizeNodeLabels(profile.Properties.NodeLabels),
+				NodeTaints:          normalizeNodeTaints(profile.Properties.NodeTaints),
 			},
 		}
 
@@ -129,8 +155,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		diff := cmp.Diff(normalizedProfile, existingProfile)
 		if diff != "" {
 			log.V(2).Info(fmt.Sprintf("Update required (+new -old):\n%s", diff))
-			err = s.Client.CreateOrUpdate(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name,
-				profile, customHeaders)
+			err = s.createOrUpdate(ctx, agentPoolSpec, profile, customHeaders)
 			if err != nil {
 				return errors.Wrap(err, "failed to create or update agent pool")
 			}
@@ -152,6 +177,12 @@ func (s *Service) Delete(ctx context.Context) error {
 
 	agentPoolSpec := s.scope.AgentPoolSpec()
 
+	poolName := operationResourceName(agentPoolSpec)
+	if !async.AcquireOperation(serviceName, agentPoolSpec.ResourceGroup, poolName) {
+		return azure.WithTransientError(async.ErrTooManyOperations, reconciler.DefaultReconcilerRequeue)
+	}
+	defer async.ReleaseOperation(serviceName, agentPoolSpec.ResourceGroup, poolName)
+
 	log.V(2).Info(fmt.Sprintf("deleting agent pool  %s ", agentPoolSpec.Name))
 	err := s.Client.Delete(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name)
 	if err != nil {
@@ -165,3 +196,42 @@ func (s *Service) Delete(ctx context.Context) error {
 	log.V(2).Info(fmt.Sprintf("Successfully deleted agent pool %s ", agentPoolSpec.Name))
 	return nil
 }
+
+// createOrUpdate creates or updates the agent pool and waits for the operation to complete, once the maximum number of
+// long-running operations in flight allows it.
+func (s *Service) createOrUpdate(ctx context.Context, agentPoolSpec azure.AgentPoolSpec, profile armcontainerservice.AgentPool, customHeaders map[string]string) error {
+	poolName := operationResourceName(agentPoolSpec)
+	if !async.AcquireOperation(serviceName, agentPoolSpec.ResourceGroup, poolName) {
+		return azure.WithTransientError(async.ErrTooManyOperations, reconciler.DefaultReconcilerRequeue)
+	}
+	defer async.ReleaseOperation(serviceName, agentPoolSpec.ResourceGroup, poolName)
+
+	return s.Client.CreateOrUpdate(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name, profile, customHeaders)
+}
+
+// operationResourceName returns the name identifying the agent pool among the long-running operations in flight, as
+// the agent pools of different managed clusters may have the same name.
+func operationResourceName(agentPoolSpec azure.AgentPoolSpec) string {
+	return agentPoolSpec.Cluster + "/" + agentPoolSpec.Name
+}
+
+// normalizeNodeLabels returns nil for an agent pool without node labels, as AKS may return them either as nil or empty.
+func normalizeNodeLabels(labels map[string]*string) map[string]*string {
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+// normalizeNodeTaints returns the sorted node taints of an agent pool, or nil for an agent pool without any, as the
+// order of the taints doesn't matter and AKS may return them either as nil or empty.
+func normalizeNodeTaints(taints []*string) []*string {
+	if len(taints) == 0 {
+		return nil
+	}
+	sorted := append([]*string(nil), taints...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return pointer.StringDeref(sorted[i], "") < pointer.StringDeref(sorted[j], "")
+	})
+	return sorted
+}
@@ -18,18 +18,20 @@ package agentpools
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/agentpools/mock_agentpools"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	infraexpv1 "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -56,7 +58,7 @@ func TestReconcile(t *testing.T) {
 			expect: func(m *mock_agentpools.MockClientMockRecorder, provisioningstate string) {
 				pv := provisioningstate
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agentpool", gomock.Any(), gomock.Any()).Return(nil)
-				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agentpool").Return(containerservice.AgentPool{ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agentpool").Return(armcontainerservice.AgentPool{Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
 					ProvisioningState: &pv,
 				}}, nil)
 			},
@@ -71,7 +73,7 @@ func TestReconcile(t *testing.T) {
 			provisioningStatesToTest: []string{"Deleting", "InProgress", "randomStringHere"},
 			expectedError:            "Unable to update existing agent pool in non terminal state. Agent pool must be in one of the following provisioning states: canceled, failed, or succeeded. Actual state:",
 			expect: func(m *mock_agentpools.MockClientMockRecorder, provisioningstate string) {
-				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agentpool").Return(containerservice.AgentPool{ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agentpool").Return(armcontainerservice.AgentPool{Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
 					ProvisioningState: &provisioningstate,
 				}}, nil)
 			},
@@ -92,6 +94,7 @@ func TestReconcile(t *testing.T) {
 
 				agentpoolsMock := mock_agentpools.NewMockClient(mockCtrl)
 				machinePoolScope := &scope.ManagedMachinePoolScope{
+					ManagedClusterScoper: &scope.ManagedControlPlaneScope{},
 					ControlPlane: &infraexpv1.AzureManagedControlPlane{
 						ObjectMeta: metav1.ObjectMeta{
 							Name: tc.agentpoolSpec.Cluster,
@@ -125,6 +128,8 @@ func TestReconcile(t *testing.T) {
 				} else {
 					g.Expect(err).NotTo(HaveOccurred())
 				}
+				g.Expect(machinePoolScope.InfraMachinePool.Status.AgentPool).NotTo(BeNil())
+				g.Expect(machinePoolScope.InfraMachinePool.Status.AgentPool.ProvisioningState).To(Equal(provisioningstate))
 			})
 		}
 	}
@@ -132,6 +137,8 @@ func TestReconcile(t *testing.T) {
 	testcases := []struct {
 		name           string
 		agentPoolsSpec azure.AgentPoolSpec
+		nodeLabels     map[string]string
+		taints         infraexpv1.Taints
 		expectedError  string
 		expect         func(m *mock_agentpools.MockClientMockRecorder)
 	}{
@@ -145,7 +152,7 @@ func TestReconcile(t *testing.T) {
 			expectedError: "",
 			expect: func(m *mock_agentpools.MockClientMockRecorder) {
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agentpool", gomock.Any(), gomock.Any()).Return(nil)
-				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agentpool").Return(containerservice.AgentPool{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agentpool").Return(armcontainerservice.AgentPool{}, &azcore.ResponseError{StatusCode: http.StatusNotFound})
 			},
 		},
 		{
@@ -155,15 +162,15 @@ func TestReconcile(t *testing.T) {
 				ResourceGroup: "my-rg",
 				Cluster:       "my-cluster",
 				SKU:           "SKU123",
-				Version:       to.StringPtr("9.99.9999"),
+				Version:       to.Ptr("9.99.9999"),
 				Replicas:      2,
 				OSDiskSizeGB:  100,
-				MaxPods:       to.Int32Ptr(12),
-				OsDiskType:    to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+				MaxPods:       to.Ptr[int32](12),
+				OsDiskType:    to.Ptr(string(armcontainerservice.OSDiskTypeManaged)),
 			},
-			expectedError: "failed to get existing agent pool: #: Internal Server Error: StatusCode=500",
+			expectedError: "failed to get existing agent pool: Internal Server Error",
 			expect: func(m *mock_agentpools.MockClientMockRecorder) {
-				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(armcontainerservice.AgentPool{}, errors.New("Internal Server Error"))
 			},
 		},
 		{
@@ -173,16 +180,16 @@ func TestReconcile(t *testing.T) {
 				ResourceGroup: "my-rg",
 				Cluster:       "my-cluster",
 				SKU:           "SKU123",
-				Version:       to.StringPtr("9.99.9999"),
+				Version:       to.Ptr("9.99.9999"),
 				Replicas:      2,
 				OSDiskSizeGB:  100,
-				MaxPods:       to.Int32Ptr(12),
-				OsDiskType:    to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+				MaxPods:       to.Ptr[int32](12),
+				OsDiskType:    to.Ptr(string(armcontainerservice.OSDiskTypeManaged)),
 			},
 			expectedError: "",
 			expect: func(m *mock_agentpools.MockClientMockRecorder) {
-				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{}), gomock.Any()).Return(nil)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(armcontainerservice.AgentPool{}, &azcore.ResponseError{StatusCode: http.StatusNotFound})
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(armcontainerservice.AgentPool{}), gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -192,16 +199,16 @@ func TestReconcile(t *testing.T) {
 				ResourceGroup: "my-rg",
 				Cluster:       "my-cluster",
 				SKU:           "SKU123",
-				Version:       to.StringPtr("9.99.9999"),
+				Version:       to.Ptr("9.99.9999"),
 				Replicas:      2,
 				OSDiskSizeGB:  100,
-				MaxPods:       to.Int32Ptr(12),
-				OsDiskType:    to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+				MaxPods:       to.Ptr[int32](12),
+				OsDiskType:    to.Ptr(string(armcontainerservice.OSDiskTypeManaged)),
 			},
-			expectedError: "failed to create or update agent pool: #: Internal Server Error: StatusCode=500",
+			expectedError: "failed to create or update agent pool: Internal Server Error",
 			expect: func(m *mock_agentpools.MockClientMockRecorder) {
-				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{}), gomock.Any()).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(armcontainerservice.AgentPool{}, &azcore.ResponseError{StatusCode: http.StatusNotFound})
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(armcontainerservice.AgentPool{}), gomock.Any()).Return(errors.New("Internal Server Error"))
 			},
 		},
 		{
@@ -211,24 +218,24 @@ func TestReconcile(t *testing.T) {
 				ResourceGroup: "my-rg",
 				Cluster:       "my-cluster",
 				SKU:           "SKU123",
-				Version:       to.StringPtr("9.99.9999"),
+				Version:       to.Ptr("9.99.9999"),
 				Replicas:      2,
 				OSDiskSizeGB:  100,
-				MaxPods:       to.Int32Ptr(12),
-				OsDiskType:    to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+				MaxPods:       to.Ptr[int32](12),
+				OsDiskType:    to.Ptr(string(armcontainerservice.OSDiskTypeManaged)),
 			},
-			expectedError: "failed to create or update agent pool: #: Internal Server Error: StatusCode=500",
+			expectedError: "failed to create or update agent pool: Internal Server Error",
 			expect: func(m *mock_agentpools.MockClientMockRecorder) {
-				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
-					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
-						Count:               to.Int32Ptr(3),
-						OsDiskSizeGB:        to.Int32Ptr(20),
-						VMSize:              to.StringPtr(string(containerservice.VMSizeTypesStandardA1)),
-						OrchestratorVersion: to.StringPtr("9.99.9999"),
-						ProvisioningState:   to.StringPtr("Failed"),
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(armcontainerservice.AgentPool{
+					Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Ptr[int32](3),
+						OSDiskSizeGB:        to.Ptr[int32](20),
+						VMSize:              to.Ptr("Standard_A1"),
+						OrchestratorVersion: to.Ptr("9.99.9999"),
+						ProvisioningState:   to.Ptr("Failed"),
 					},
 				}, nil)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{}), gomock.Any()).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(armcontainerservice.AgentPool{}), gomock.Any()).Return(errors.New("Internal Server Error"))
 			},
 		},
 		{
@@ -238,25 +245,159 @@ func TestReconcile(t *testing.T) {
 				ResourceGroup: "my-rg",
 				Cluster:       "my-cluster",
 				SKU:           "Standard_D2s_v3",
-				Version:       to.StringPtr("9.99.9999"),
+				Version:       to.Ptr("9.99.9999"),
 				Replicas:      2,
 				OSDiskSizeGB:  100,
-				MaxPods:       to.Int32Ptr(12),
-				OsDiskType:    to.StringPtr(string(containerservice.OSDiskTypeEphemeral)),
+				MaxPods:       to.Ptr[int32](12),
+				OsDiskType:    to.Ptr(string(armcontainerservice.OSDiskTypeEphemeral)),
 			},
 			expectedError: "",
 			expect: func(m *mock_agentpools.MockClientMockRecorder) {
-				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
-					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
-						Count:               to.Int32Ptr(2),
-						OsDiskSizeGB:        to.Int32Ptr(100),
-						VMSize:              to.StringPtr(string(containerservice.VMSizeTypesStandardD2sV3)),
-						OsType:              containerservice.OSTypeLinux,
-						OrchestratorVersion: to.StringPtr("9.99.9999"),
-						ProvisioningState:   to.StringPtr("Succeeded"),
-						VnetSubnetID:        to.StringPtr(""),
-						MaxPods:             to.Int32Ptr(12),
-						OsDiskType:          containerservice.OSDiskTypeEphemeral,
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(armcontainerservice.AgentPool{
+					Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Ptr[int32](2),
+						OSDiskSizeGB:        to.Ptr[int32](100),
+						VMSize:              to.Ptr("Standard_D2s_v3"),
+						OSType:              to.Ptr(armcontainerservice.OSTypeLinux),
+						OrchestratorVersion: to.Ptr("9.99.9999"),
+						ProvisioningState:   to.Ptr("Succeeded"),
+						VnetSubnetID:        to.Ptr(""),
+						MaxPods:             to.Ptr[int32](12),
+						OSDiskType:          to.Ptr(armcontainerservice.OSDiskTypeEphemeral),
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "update the node labels and taints of an Agent Pool",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-agent-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "Standard_D2s_v3",
+				Version:       to.Ptr("9.99.9999"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+			},
+			nodeLabels: map[string]string{"dedicated": "kafka"},
+			taints: infraexpv1.Taints{
+				{Key: "dedicated", Value: "kafka", Effect: infraexpv1.TaintEffect("NoSchedule")},
+			},
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(armcontainerservice.AgentPool{
+					Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Ptr[int32](2),
+						OrchestratorVersion: to.Ptr("9.99.9999"),
+						ProvisioningState:   to.Ptr("Succeeded"),
+						NodeLabels:          map[string]*string{"dedicated": to.Ptr("redis")},
+					},
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomockinternal.DiffEq(armcontainerservice.AgentPool{
+					Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Ptr[int32](2),
+						VMSize:              to.Ptr("Standard_D2s_v3"),
+						OSDiskSizeGB:        to.Ptr[int32](100),
+						OSDiskType:          to.Ptr(armcontainerservice.OSDiskTypeManaged),
+						VnetSubnetID:        to.Ptr("/subscriptions//resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks//subnets/"),
+						MaxPods:             to.Ptr[int32](12),
+						Type:                to.Ptr(armcontainerservice.AgentPoolTypeVirtualMachineScaleSets),
+						OrchestratorVersion: to.Ptr("9.99.9999"),
+						AvailabilityZones:   []*string{},
+						NodeLabels:          map[string]*string{"dedicated": to.Ptr("kafka")},
+						NodeTaints:          []*string{to.Ptr("dedicated=kafka:NoSchedule")},
+					},
+				}), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "remove all the node labels and taints of an Agent Pool",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-agent-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "Standard_D2s_v3",
+				Version:       to.Ptr("9.99.9999"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+			},
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(armcontainerservice.AgentPool{
+					Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Ptr[int32](2),
+						OrchestratorVersion: to.Ptr("9.99.9999"),
+						ProvisioningState:   to.Ptr("Succeeded"),
+						NodeLabels:          map[string]*string{"dedicated": to.Ptr("kafka")},
+						NodeTaints:          []*string{to.Ptr("dedicated=kafka:NoSchedule")},
+					},
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomockinternal.DiffEq(armcontainerservice.AgentPool{
+					Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Ptr[int32](2),
+						VMSize:              to.Ptr("Standard_D2s_v3"),
+						OSDiskSizeGB:        to.Ptr[int32](100),
+						OSDiskType:          to.Ptr(armcontainerservice.OSDiskTypeManaged),
+						VnetSubnetID:        to.Ptr("/subscriptions//resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks//subnets/"),
+						MaxPods:             to.Ptr[int32](12),
+						Type:                to.Ptr(armcontainerservice.AgentPoolTypeVirtualMachineScaleSets),
+						OrchestratorVersion: to.Ptr("9.99.9999"),
+						AvailabilityZones:   []*string{},
+						NodeLabels:          map[string]*string{},
+						NodeTaints:          []*string{},
+					},
+				}), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "no update needed on Agent Pool with the same node labels and taints",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-agent-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "Standard_D2s_v3",
+				Version:       to.Ptr("9.99.9999"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+			},
+			nodeLabels: map[string]string{"dedicated": "kafka"},
+			taints: infraexpv1.Taints{
+				{Key: "dedicated", Value: "kafka", Effect: infraexpv1.TaintEffect("NoSchedule")},
+				{Key: "tier", Value: "data", Effect: infraexpv1.TaintEffect("NoExecute")},
+			},
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(armcontainerservice.AgentPool{
+					Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Ptr[int32](2),
+						OrchestratorVersion: to.Ptr("9.99.9999"),
+						ProvisioningState:   to.Ptr("Succeeded"),
+						NodeLabels:          map[string]*string{"dedicated": to.Ptr("kafka")},
+						NodeTaints:          []*string{to.Ptr("tier=data:NoExecute"), to.Ptr("dedicated=kafka:NoSchedule")},
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "no update needed on Agent Pool without node labels and taints",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-agent-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "Standard_D2s_v3",
+				Version:       to.Ptr("9.99.9999"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+			},
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(armcontainerservice.AgentPool{
+					Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Ptr[int32](2),
+						OrchestratorVersion: to.Ptr("9.99.9999"),
+						ProvisioningState:   to.Ptr("Succeeded"),
+						NodeLabels:          map[string]*string{},
+						NodeTaints:          []*string{},
 					},
 				}, nil)
 			},
@@ -277,6 +418,7 @@ func TestReconcile(t *testing.T) {
 
 			agentpoolsMock := mock_agentpools.NewMockClient(mockCtrl)
 			machinePoolScope := &scope.ManagedMachinePoolScope{
+				ManagedClusterScoper: &scope.ManagedControlPlaneScope{},
 				ControlPlane: &infraexpv1.AzureManagedControlPlane{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: tc.agentPoolsSpec.Cluster,
@@ -303,8 +445,10 @@ func TestReconcile(t *testing.T) {
 						Name:         &tc.agentPoolsSpec.Name,
 						SKU:          tc.agentPoolsSpec.SKU,
 						OSDiskSizeGB: &osDiskSizeGB,
-						MaxPods:      to.Int32Ptr(12),
-						OsDiskType:   to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+						MaxPods:      to.Ptr[int32](12),
+						OsDiskType:   to.Ptr(string(armcontainerservice.OSDiskTypeManaged)),
+						NodeLabels:   tc.nodeLabels,
+						Taints:       tc.taints,
 					},
 				},
 			}
@@ -356,7 +500,7 @@ func TestDeleteAgentPools(t *testing.T) {
 			expectedError: "",
 			expect: func(m *mock_agentpools.MockClientMockRecorder) {
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+					Return(&azcore.ResponseError{StatusCode: http.StatusNotFound})
 			},
 		},
 		{
@@ -366,10 +510,10 @@ func TestDeleteAgentPools(t *testing.T) {
 				ResourceGroup: "my-rg",
 				Cluster:       "my-cluster",
 			},
-			expectedError: "failed to delete agent pool my-agent-pool in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expectedError: "failed to delete agent pool my-agent-pool in resource group my-rg: Internal Server Error",
 			expect: func(m *mock_agentpools.MockClientMockRecorder) {
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+					Return(errors.New("Internal Server Error"))
 			},
 		},
 	}
@@ -384,6 +528,7 @@ func TestDeleteAgentPools(t *testing.T) {
 
 			agentPoolsMock := mock_agentpools.NewMockClient(mockCtrl)
 			machinePoolScope := &scope.ManagedMachinePoolScope{
+				ManagedClusterScoper: &scope.ManagedControlPlaneScope{},
 				ControlPlane: &infraexpv1.AzureManagedControlPlane{
 					ObjectMeta: metav1.ObjectMeta{
 						Name: tc.agentPoolsSpec.Cluster,
@@ -420,3 +565,40 @@ func TestDeleteAgentPools(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteAgentPoolOperationLimit(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	async.SetMaxConcurrentOperations(1)
+	defer async.SetMaxConcurrentOperations(0)
+	g.Expect(async.AcquireOperation("virtualmachine", "my-rg", "my-vm")).To(BeTrue())
+	defer async.ReleaseOperation("virtualmachine", "my-rg", "my-vm")
+
+	// the agent pool isn't deleted while the maximum number of operations is in flight
+	agentPoolsMock := mock_agentpools.NewMockClient(mockCtrl)
+	s := &Service{
+		Client: agentPoolsMock,
+		scope: &scope.ManagedMachinePoolScope{
+			ManagedClusterScoper: &scope.ManagedControlPlaneScope{},
+			ControlPlane: &infraexpv1.AzureManagedControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"},
+				Spec:       infraexpv1.AzureManagedControlPlaneSpec{ResourceGroupName: "my-rg"},
+			},
+			MachinePool: &capiexp.MachinePool{},
+			InfraMachinePool: &infraexpv1.AzureManagedMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-agent-pool"},
+				Spec:       infraexpv1.AzureManagedMachinePoolSpec{Name: to.Ptr("my-agent-pool")},
+			},
+		},
+	}
+	err := s.Delete(context.TODO())
+	g.Expect(err).To(MatchError("maximum number of in-flight long-running operations reached, retrying later. Object will be requeued after 15s"))
+
+	// the slot of the agent pool is released once it is deleted
+	async.ReleaseOperation("virtualmachine", "my-rg", "my-vm")
+	agentPoolsMock.EXPECT().Delete(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool")
+	g.Expect(s.Delete(context.TODO())).To(Succeed())
+	g.Expect(async.AcquireOperation("virtualmachine", "my-rg", "my-vm")).To(BeTrue())
+}
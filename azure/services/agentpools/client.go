@@ -18,72 +18,78 @@ package agentpools
 
 import (
 	"context"
+	"net/http"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
-	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // Client wraps go-sdk.
 type Client interface {
-	Get(context.Context, string, string, string) (containerservice.AgentPool, error)
-	CreateOrUpdate(context.Context, string, string, string, containerservice.AgentPool, map[string]string) error
+	Get(context.Context, string, string, string) (armcontainerservice.AgentPool, error)
+	CreateOrUpdate(context.Context, string, string, string, armcontainerservice.AgentPool, map[string]string) error
 	Delete(context.Context, string, string, string) error
 }
 
 // AzureClient contains the Azure go-sdk Client.
 type AzureClient struct {
-	agentpools containerservice.AgentPoolsClient
+	agentpools *armcontainerservice.AgentPoolsClient
 }
 
 var _ Client = &AzureClient{}
 
 // NewClient creates a new agent pools client from subscription ID.
-func NewClient(auth azure.Authorizer) *AzureClient {
-	c := newAgentPoolsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
-	return &AzureClient{c}
-}
-
-// newAgentPoolsClient creates a new agent pool client from subscription ID.
-func newAgentPoolsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) containerservice.AgentPoolsClient {
-	agentPoolsClient := containerservice.NewAgentPoolsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&agentPoolsClient.Client, authorizer)
-	return agentPoolsClient
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armcontainerservice.NewAgentPoolsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create agent pools client")
+	}
+	return &AzureClient{c}, nil
 }
 
 // Get gets an agent pool.
-func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, cluster, name string) (containerservice.AgentPool, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, cluster, name string) (armcontainerservice.AgentPool, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "agentpools.AzureClient.Get")
 	defer done()
 
-	return ac.agentpools.Get(ctx, resourceGroupName, cluster, name)
+	resp, err := ac.agentpools.Get(ctx, resourceGroupName, cluster, name, nil)
+	if err != nil {
+		return armcontainerservice.AgentPool{}, err
+	}
+	return resp.AgentPool, nil
 }
 
 // CreateOrUpdate creates or updates an agent pool.
 func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, cluster, name string,
-	properties containerservice.AgentPool, customHeaders map[string]string) error {
+	properties armcontainerservice.AgentPool, customHeaders map[string]string) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "agentpools.AzureClient.CreateOrUpdate")
 	defer done()
 
-	preparer, err := ac.agentpools.CreateOrUpdatePreparer(ctx, resourceGroupName, cluster, name, properties)
-	if err != nil {
-		return errors.Wrap(err, "failed to prepare operation")
-	}
-	for key, element := range customHeaders {
-		preparer.Header.Add(key, element)
+	if len(customHeaders) > 0 {
+		headers := http.Header{}
+		for key, element := range customHeaders {
+			headers.Add(key, element)
+		}
+		ctx = policy.WithHTTPHeader(ctx, headers)
 	}
 
-	future, err := ac.agentpools.CreateOrUpdateSender(preparer)
+	poller, err := ac.agentpools.BeginCreateOrUpdate(ctx, resourceGroupName, cluster, name, properties, nil)
 	if err != nil {
 		return errors.Wrap(err, "failed to begin operation")
 	}
-	if err := future.WaitForCompletionRef(ctx, ac.agentpools.Client); err != nil {
+	if _, err := poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}); err != nil {
 		return errors.Wrap(err, "failed to end operation")
 	}
-	_, err = future.Result(ac.agentpools)
-	return err
+	return nil
 }
 
 // Delete deletes an agent pool.
@@ -91,13 +97,12 @@ func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, cluster, n
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "agentpools.AzureClient.Delete")
 	defer done()
 
-	future, err := ac.agentpools.Delete(ctx, resourceGroupName, cluster, name)
+	poller, err := ac.agentpools.BeginDelete(ctx, resourceGroupName, cluster, name, nil)
 	if err != nil {
 		return errors.Wrap(err, "failed to begin operation")
 	}
-	if err := future.WaitForCompletionRef(ctx, ac.agentpools.Client); err != nil {
+	if _, err := poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}); err != nil {
 		return errors.Wrap(err, "failed to end operation")
 	}
-	_, err = future.Result(ac.agentpools)
-	return err
+	return nil
 }
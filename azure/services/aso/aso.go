@@ -0,0 +1,349 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package aso reconciles Azure resources through Azure Service Operator (ASO) resources instead of calling the Azure
+// API directly.
+package aso
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// ServiceLabel is the label of the ASO resources holding the name of the service which reconciles them.
+	ServiceLabel = "azure.infrastructure.cluster.x-k8s.io/service"
+
+	// requeueAfter is the interval at which ASO resources which are not ready are checked again.
+	requeueAfter = 15 * time.Second
+)
+
+// resourceKind describes the ASO resource reconciling the resources of a service.
+type resourceKind struct {
+	schema.GroupVersionKind
+	// ownerType is the Azure resource type of the owner of child resources, e.g. the virtual network of a subnet.
+	ownerType string
+}
+
+// resourceKinds maps the names of the services to the kinds of the ASO resources reconciling their resources. The
+// resources of other services are reconciled through the Azure API. Resources whose children are reconciled separately
+// by CAPZ or by the cloud provider, e.g. the security rules of network security groups, the routes of route tables or
+// the inbound NAT rules of load balancers, aren't reconciled by ASO as it would remove these children.
+var resourceKinds = map[string]resourceKind{
+	"virtualnetworks": {
+		GroupVersionKind: schema.GroupVersionKind{Group: "network.azure.com", Version: "v1beta20201101", Kind: "VirtualNetwork"},
+	},
+	"subnets": {
+		GroupVersionKind: schema.GroupVersionKind{Group: "network.azure.com", Version: "v1beta20201101", Kind: "VirtualNetworksSubnet"},
+		ownerType:        "Microsoft.Network/virtualNetworks",
+	},
+	"vnetpeerings": {
+		GroupVersionKind: schema.GroupVersionKind{Group: "network.azure.com", Version: "v1beta20201101", Kind: "VirtualNetworksVirtualNetworkPeering"},
+		ownerType:        "Microsoft.Network/virtualNetworks",
+	},
+	"natgateways": {
+		GroupVersionKind: schema.GroupVersionKind{Group: "network.azure.com", Version: "v1beta20220701", Kind: "NatGateway"},
+	},
+}
+
+// Scope is the scope of the services whose resources can be reconciled through ASO resources.
+type Scope interface {
+	async.FutureScope
+	SubscriptionID() string
+	ClusterName() string
+	Namespace() string
+	GetClient() client.Client
+	// ASOBackendEnabled returns true if the resources of the cluster are reconciled through ASO resources.
+	ASOBackendEnabled() bool
+}
+
+// Reconciler reconciles the resources of a service through ASO resources.
+type Reconciler struct {
+	Scope Scope
+	// Getter gets the resources which already exist in Azure.
+	Getter async.Getter
+	// Fallback reconciles the resources through the Azure API.
+	Fallback async.Reconciler
+}
+
+// NewReconciler returns the reconciler of the resources of a service: through ASO resources when the scope enables
+// the ASO backend, or through the Azure API otherwise.
+func NewReconciler(scope async.FutureScope, createClient async.PollerCreator, deleteClient async.PollerDeleter) async.Reconciler {
+	fallback := async.NewPollerService(scope, createClient, deleteClient)
+	if asoScope, ok := scope.(Scope); ok && asoScope.ASOBackendEnabled() {
+		return &Reconciler{
+			Scope:    asoScope,
+			Getter:   createClient,
+			Fallback: fallback,
+		}
+	}
+	return fallback
+}
+
+// CreateResource creates or updates the ASO resource of a resource and returns the resource once ASO reconciled it.
+// Resources which already exist in Azure without an ASO resource, e.g. because they were created before the ASO
+// backend was enabled, keep being reconciled through the Azure API.
+func (r *Reconciler) CreateResource(ctx context.Context, spec azure.ResourceSpecGetter, serviceName string) (interface{}, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "aso.Reconciler.CreateResource")
+	defer done()
+
+	kind, ok := resourceKinds[serviceName]
+	if !ok {
+		return r.Fallback.CreateResource(ctx, spec, serviceName)
+	}
+
+	c := r.Scope.GetClient()
+	obj := r.newObject(kind, spec)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get %s %s", kind.Kind, obj.GetName())
+		}
+		if _, err := r.Getter.Get(ctx, spec); err == nil {
+			return r.Fallback.CreateResource(ctx, spec, serviceName)
+		} else if !azure.ResourceNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get existing resource %s/%s (service: %s)", spec.ResourceGroupName(), spec.ResourceName(), serviceName)
+		}
+	}
+
+	parameters, err := spec.Parameters(nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get desired parameters for resource %s/%s (service: %s)", spec.ResourceGroupName(), spec.ResourceName(), serviceName)
+	}
+	if parameters == nil {
+		return nil, nil
+	}
+	resourceSpec, err := r.resourceSpec(kind, spec, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := controllerutil.CreateOrUpdate(ctx, c, obj, func() error {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[clusterv1.ClusterLabelName] = r.Scope.ClusterName()
+		labels[ServiceLabel] = serviceName
+		obj.SetLabels(labels)
+		return unstructured.SetNestedField(obj.Object, resourceSpec, "spec")
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to create or update %s %s", kind.Kind, obj.GetName())
+	}
+
+	if err := readyError(obj, spec, serviceName); err != nil {
+		if azure.IsOperationNotDoneError(err) {
+			log.V(2).Info("waiting for ASO to reconcile resource", tele.LogKeyService, serviceName, tele.LogKeyResource, spec.ResourceName(), "kind", kind.Kind)
+		}
+		return nil, err
+	}
+
+	res, err := result(obj, parameters)
+	if err != nil {
+		return nil, err
+	}
+	async.TrackResource(r.Scope, res, serviceName)
+	return res, nil
+}
+
+// DeleteResource deletes the ASO resource of a resource, which deletes the resource, and returns once it is deleted.
+// Resources without an ASO resource are deleted through the Azure API.
+func (r *Reconciler) DeleteResource(ctx context.Context, spec azure.ResourceSpecGetter, serviceName string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "aso.Reconciler.DeleteResource")
+	defer done()
+
+	kind, ok := resourceKinds[serviceName]
+	if !ok {
+		return r.Fallback.DeleteResource(ctx, spec, serviceName)
+	}
+
+	c := r.Scope.GetClient()
+	obj := r.newObject(kind, spec)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.Fallback.DeleteResource(ctx, spec, serviceName)
+		}
+		return errors.Wrapf(err, "failed to get %s %s", kind.Kind, obj.GetName())
+	}
+
+	if obj.GetDeletionTimestamp().IsZero() {
+		if err := c.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete %s %s", kind.Kind, obj.GetName())
+		}
+	}
+	return notDoneError(infrav1.DeleteFuture, spec, serviceName)
+}
+
+// newObject returns the ASO resource of a resource, without its spec.
+func (r *Reconciler) newObject(kind resourceKind, spec azure.ResourceSpecGetter) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(kind.GroupVersionKind)
+	obj.SetNamespace(r.Scope.Namespace())
+	obj.SetName(objectName(r.Scope.ClusterName(), spec, kind.ownerType != ""))
+	return obj
+}
+
+// resourceSpec returns the spec of the ASO resource of a resource from its parameters: the properties of the
+// parameters are flattened in the spec, and the references to other resources are converted to ARM ID references.
+func (r *Reconciler) resourceSpec(kind resourceKind, spec azure.ResourceSpecGetter, parameters interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(parameters)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal the parameters of %s", spec.ResourceName())
+	}
+	arm := map[string]interface{}{}
+	if err := json.Unmarshal(data, &arm); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the parameters of %s", spec.ResourceName())
+	}
+
+	resourceSpec := map[string]interface{}{}
+	for key, value := range arm {
+		switch key {
+		case "id", "name", "type", "etag":
+		case "properties":
+			if properties, ok := value.(map[string]interface{}); ok {
+				for property, propertyValue := range properties {
+					resourceSpec[property] = propertyValue
+				}
+			}
+		default:
+			resourceSpec[key] = value
+		}
+	}
+	resourceSpec = toReferences(resourceSpec).(map[string]interface{})
+
+	ownerID := azure.ResourceGroupID(r.Scope.SubscriptionID(), spec.ResourceGroupName())
+	if kind.ownerType != "" {
+		ownerID = fmt.Sprintf("%s/providers/%s/%s", ownerID, kind.ownerType, spec.OwnerResourceName())
+	}
+	resourceSpec["azureName"] = spec.ResourceName()
+	resourceSpec["owner"] = map[string]interface{}{"armId": ownerID}
+	return resourceSpec, nil
+}
+
+// toReferences converts the references to other resources, which are objects with an ID only in the Azure API, to the
+// ARM ID references of ASO.
+func toReferences(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if id, ok := v["id"].(string); ok && len(v) == 1 {
+			return map[string]interface{}{"reference": map[string]interface{}{"armId": id}}
+		}
+		for key, item := range v {
+			v[key] = toReferences(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = toReferences(item)
+		}
+	}
+	return value
+}
+
+// readyError returns nil if ASO reconciled the current spec of a resource, an OperationNotDoneError while it is
+// reconciling it, or the error reported by ASO if it failed.
+func readyError(obj *unstructured.Unstructured, spec azure.ResourceSpecGetter, serviceName string) error {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		if observedGeneration, ok, _ := unstructured.NestedInt64(condition, "observedGeneration"); ok && observedGeneration < obj.GetGeneration() {
+			break
+		}
+		if condition["status"] == "True" {
+			return nil
+		}
+		if condition["severity"] == "Error" {
+			return errors.Errorf("ASO failed to reconcile %s %s: %s: %v", obj.GetKind(), obj.GetName(), condition["reason"], condition["message"])
+		}
+		break
+	}
+	return notDoneError(infrav1.PutFuture, spec, serviceName)
+}
+
+// notDoneError returns an OperationNotDoneError for an operation run by ASO, which isn't tracked as a future.
+func notDoneError(futureType string, spec azure.ResourceSpecGetter, serviceName string) error {
+	future := &infrav1.Future{
+		Type:          futureType,
+		ServiceName:   serviceName,
+		Name:          spec.ResourceName(),
+		ResourceGroup: spec.ResourceGroupName(),
+	}
+	return azure.WithTransientError(azure.NewOperationNotDoneError(future), requeueAfter)
+}
+
+// result returns the resource reconciled by ASO, as the SDK type of its parameters, from the status of its ASO
+// resource.
+func result(obj *unstructured.Unstructured, parameters interface{}) (interface{}, error) {
+	status, _, _ := unstructured.NestedMap(obj.Object, "status")
+	arm := map[string]interface{}{}
+	properties := map[string]interface{}{}
+	for key, value := range status {
+		switch key {
+		case "conditions":
+		case "id", "name", "type", "etag", "location", "tags", "sku", "zones":
+			arm[key] = value
+		default:
+			properties[key] = value
+		}
+	}
+	arm["properties"] = properties
+
+	data, err := json.Marshal(arm)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal the status of %s %s", obj.GetKind(), obj.GetName())
+	}
+	resource := reflect.New(reflect.Indirect(reflect.ValueOf(parameters)).Type())
+	if err := json.Unmarshal(data, resource.Interface()); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the status of %s %s", obj.GetKind(), obj.GetName())
+	}
+	return resource.Elem().Interface(), nil
+}
+
+// objectNameUnsafeChars matches the characters that can't be used in the names of Kubernetes objects.
+var objectNameUnsafeChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// objectName returns the name of the ASO resource of a resource, prefixed by the name of the cluster and, for child
+// resources, by the name of their owner.
+func objectName(clusterName string, spec azure.ResourceSpecGetter, child bool) string {
+	parts := []string{clusterName}
+	if child {
+		parts = append(parts, spec.OwnerResourceName())
+	}
+	parts = append(parts, spec.ResourceName())
+	name := objectNameUnsafeChars.ReplaceAllString(strings.ToLower(strings.Join(parts, "-")), "-")
+	if len(name) > 253 {
+		name = name[:253]
+	}
+	return strings.Trim(name, "-.")
+}
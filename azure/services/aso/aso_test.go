@@ -0,0 +1,213 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aso
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeScope struct {
+	*mock_azure.MockAsyncStatusUpdater
+	client  client.Client
+	enabled bool
+}
+
+func (s *fakeScope) SubscriptionID() string   { return "123" }
+func (s *fakeScope) ClusterName() string      { return "my-cluster" }
+func (s *fakeScope) Namespace() string        { return "default" }
+func (s *fakeScope) GetClient() client.Client { return s.client }
+func (s *fakeScope) ASOBackendEnabled() bool  { return s.enabled }
+
+var notFoundError = test.NewResponseError(http.StatusNotFound)
+
+func newFakeClient() client.Client {
+	scheme := runtime.NewScheme()
+	for _, kind := range resourceKinds {
+		scheme.AddKnownTypeWithName(kind.GroupVersionKind, &unstructured.Unstructured{})
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).Build()
+}
+
+func newSubnetSpec(mockCtrl *gomock.Controller) *mock_azure.MockResourceSpecGetter {
+	spec := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+	spec.EXPECT().ResourceName().AnyTimes().Return("node-subnet")
+	spec.EXPECT().OwnerResourceName().AnyTimes().Return("my-vnet")
+	spec.EXPECT().ResourceGroupName().AnyTimes().Return("my-rg")
+	spec.EXPECT().Parameters(nil).AnyTimes().Return(armnetwork.Subnet{
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefixes: []*string{to.Ptr("10.1.0.0/16")},
+			NetworkSecurityGroup: &armnetwork.SecurityGroup{
+				ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/node-nsg"),
+			},
+		},
+	}, nil)
+	return spec
+}
+
+func TestNewReconciler(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	creator := mock_async.NewMockPollerCreator(mockCtrl)
+	deleter := mock_async.NewMockPollerDeleter(mockCtrl)
+
+	g.Expect(NewReconciler(mock_async.NewMockFutureScope(mockCtrl), creator, deleter)).NotTo(BeAssignableToTypeOf(&Reconciler{}))
+	g.Expect(NewReconciler(&fakeScope{}, creator, deleter)).NotTo(BeAssignableToTypeOf(&Reconciler{}))
+	g.Expect(NewReconciler(&fakeScope{enabled: true}, creator, deleter)).To(BeAssignableToTypeOf(&Reconciler{}))
+}
+
+func TestCreateResource(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	c := newFakeClient()
+	getter := mock_async.NewMockGetter(mockCtrl)
+	spec := newSubnetSpec(mockCtrl)
+	r := &Reconciler{
+		Scope:    &fakeScope{client: c, enabled: true},
+		Getter:   getter,
+		Fallback: mock_async.NewMockReconciler(mockCtrl),
+	}
+
+	// The subnet doesn't exist yet, so an ASO resource is created.
+	getter.EXPECT().Get(gomock.Any(), spec).Return(nil, notFoundError)
+	_, err := r.CreateResource(context.TODO(), spec, "subnets")
+	g.Expect(azure.IsOperationNotDoneError(err)).To(BeTrue())
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(resourceKinds["subnets"].GroupVersionKind)
+	g.Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "my-cluster-my-vnet-node-subnet"}, obj)).To(Succeed())
+	g.Expect(obj.GetLabels()).To(HaveKeyWithValue(ServiceLabel, "subnets"))
+	g.Expect(obj.Object["spec"]).To(Equal(map[string]interface{}{
+		"azureName":       "node-subnet",
+		"owner":           map[string]interface{}{"armId": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"},
+		"addressPrefixes": []interface{}{"10.1.0.0/16"},
+		"networkSecurityGroup": map[string]interface{}{
+			"reference": map[string]interface{}{"armId": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/node-nsg"},
+		},
+	}))
+
+	// ASO reports an error.
+	g.Expect(unstructured.SetNestedSlice(obj.Object, []interface{}{map[string]interface{}{
+		"type":     "Ready",
+		"status":   "False",
+		"severity": "Error",
+		"reason":   "InvalidRequestContent",
+		"message":  "bad subnet",
+	}}, "status", "conditions")).To(Succeed())
+	g.Expect(c.Update(context.TODO(), obj)).To(Succeed())
+	_, err = r.CreateResource(context.TODO(), spec, "subnets")
+	g.Expect(err).To(MatchError(ContainSubstring("bad subnet")))
+
+	// ASO created the subnet, whose status is returned as its SDK type.
+	g.Expect(unstructured.SetNestedField(obj.Object, map[string]interface{}{
+		"id":              "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/node-subnet",
+		"name":            "node-subnet",
+		"addressPrefixes": []interface{}{"10.1.0.0/16"},
+		"conditions":      []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+	}, "status")).To(Succeed())
+	g.Expect(c.Update(context.TODO(), obj)).To(Succeed())
+	result, err := r.CreateResource(context.TODO(), spec, "subnets")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Subnet{}))
+	subnet := result.(armnetwork.Subnet)
+	g.Expect(*subnet.ID).To(HaveSuffix("/subnets/node-subnet"))
+	g.Expect(subnet.Properties.AddressPrefixes).To(Equal([]*string{to.Ptr("10.1.0.0/16")}))
+}
+
+func TestCreateResourceFallback(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	getter := mock_async.NewMockGetter(mockCtrl)
+	fallback := mock_async.NewMockReconciler(mockCtrl)
+	spec := newSubnetSpec(mockCtrl)
+	r := &Reconciler{
+		Scope:    &fakeScope{client: newFakeClient(), enabled: true},
+		Getter:   getter,
+		Fallback: fallback,
+	}
+
+	// Resources of services without ASO resources are reconciled through the Azure API.
+	fallback.EXPECT().CreateResource(gomock.Any(), spec, "securitygroups").Return(armnetwork.SecurityGroup{}, nil)
+	_, err := r.CreateResource(context.TODO(), spec, "securitygroups")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Existing resources without ASO resources are reconciled through the Azure API.
+	getter.EXPECT().Get(gomock.Any(), spec).Return(armnetwork.Subnet{}, nil)
+	fallback.EXPECT().CreateResource(gomock.Any(), spec, "subnets").Return(armnetwork.Subnet{}, nil)
+	_, err = r.CreateResource(context.TODO(), spec, "subnets")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	fallback.EXPECT().DeleteResource(gomock.Any(), spec, "subnets").Return(nil)
+	g.Expect(r.DeleteResource(context.TODO(), spec, "subnets")).To(Succeed())
+}
+
+func TestDeleteResource(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	c := newFakeClient()
+	spec := newSubnetSpec(mockCtrl)
+	r := &Reconciler{
+		Scope:    &fakeScope{client: c, enabled: true},
+		Getter:   mock_async.NewMockGetter(mockCtrl),
+		Fallback: mock_async.NewMockReconciler(mockCtrl),
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(resourceKinds["subnets"].GroupVersionKind)
+	obj.SetNamespace("default")
+	obj.SetName("my-cluster-my-vnet-node-subnet")
+	g.Expect(c.Create(context.TODO(), obj)).To(Succeed())
+
+	err := r.DeleteResource(context.TODO(), spec, "subnets")
+	g.Expect(azure.IsOperationNotDoneError(err)).To(BeTrue())
+	g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj)).NotTo(Succeed())
+}
+
+func TestObjectName(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	spec := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+	spec.EXPECT().ResourceName().AnyTimes().Return("My_VNet-To-Remote")
+	spec.EXPECT().OwnerResourceName().AnyTimes().Return("My_VNet")
+
+	g.Expect(objectName("my-cluster", spec, false)).To(Equal("my-cluster-my-vnet-to-remote"))
+	g.Expect(objectName("my-cluster", spec, true)).To(Equal("my-cluster-my-vnet-my-vnet-to-remote"))
+}
@@ -19,7 +19,6 @@ package async
 import (
 	"context"
 
-	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 )
 
@@ -28,30 +27,25 @@ type FutureScope interface {
 	azure.AsyncStatusUpdater
 }
 
-// FutureHandler is a client that can check on the progress of a future.
-type FutureHandler interface {
-	// IsDone returns true if the operation is complete.
-	IsDone(ctx context.Context, future azureautorest.FutureAPI) (isDone bool, err error)
-	// Result returns the result of the operation.
-	Result(ctx context.Context, future azureautorest.FutureAPI, futureType string) (result interface{}, err error)
-}
-
 // Getter is an interface that can get a resource.
 type Getter interface {
 	Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error)
 }
 
-// Creator is a client that can create or update a resource asynchronously.
-type Creator interface {
-	FutureHandler
+// PollerCreator is a track 2 client that can create or update a resource asynchronously.
+// If resumeToken isn't empty, the client resumes the ongoing operation it identifies instead of starting a new one, and
+// parameters is nil.
+// The client returns the poller of the operation if it didn't complete within the call timeout.
+type PollerCreator interface {
 	Getter
-	CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (result interface{}, future azureautorest.FutureAPI, err error)
+	CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error)
 }
 
-// Deleter is a client that can delete a resource asynchronously.
-type Deleter interface {
-	FutureHandler
-	DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (future azureautorest.FutureAPI, err error)
+// PollerDeleter is a track 2 client that can delete a resource asynchronously.
+// If resumeToken isn't empty, the client resumes the ongoing operation it identifies instead of starting a new one.
+// The client returns the poller of the operation if it didn't complete within the call timeout.
+type PollerDeleter interface {
+	DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error)
 }
 
 // Reconciler is a generic interface used to perform asynchronous reconciliation of Azure resources.
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package async
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// operationTTL is how long an in-flight long-running operation is counted against the limit without being seen again.
+// It prevents operations whose owner was deleted before completion from being counted forever.
+const operationTTL = time.Hour
+
+// ErrTooManyOperations is returned when an operation can't be started because the maximum number of long-running
+// operations in flight is reached.
+var ErrTooManyOperations = errors.New("maximum number of in-flight long-running operations reached, retrying later")
+
+// limiter caps the number of long-running operations in flight across all the controllers.
+var limiter = newOperationLimiter()
+
+// SetMaxConcurrentOperations sets the maximum number of long-running Azure operations that can be in flight at the same
+// time across all the controllers. Zero or a negative value means no limit.
+func SetMaxConcurrentOperations(max int) {
+	limiter.setMax(max)
+}
+
+// AcquireOperation registers a long-running operation started by a service which handles its futures itself, returning
+// false if the maximum number of in-flight operations is reached, in which case the service must not start it.
+// Operations already in flight are always allowed.
+func AcquireOperation(serviceName, resourceGroup, resourceName string) bool {
+	return limiter.acquire(operationKey(serviceName, resourceGroup, resourceName))
+}
+
+// TrackOperation registers a long-running operation started by a service which handles its futures itself as in
+// flight regardless of the limit, e.g. when the service checks an operation started before a restart.
+func TrackOperation(serviceName, resourceGroup, resourceName string) {
+	limiter.track(operationKey(serviceName, resourceGroup, resourceName))
+}
+
+// ReleaseOperation unregisters a long-running operation started by a service which handles its futures itself, once it
+// is done or failed to start.
+func ReleaseOperation(serviceName, resourceGroup, resourceName string) {
+	limiter.release(operationKey(serviceName, resourceGroup, resourceName))
+}
+
+// operationLimiter tracks the in-flight long-running operations and caps how many can be started.
+type operationLimiter struct {
+	mu       sync.Mutex
+	max      int
+	inFlight map[string]*inFlightOperation
+	now      func() time.Time
+}
+
+// inFlightOperation is a long-running operation in flight.
+type inFlightOperation struct {
+	lastSeen time.Time
+	// attempts is the number of times the operation was started or checked by this controller.
+	attempts int
+}
+
+func newOperationLimiter() *operationLimiter {
+	return &operationLimiter{
+		inFlight: make(map[string]*inFlightOperation),
+		now:      time.Now,
+	}
+}
+
+func (l *operationLimiter) setMax(max int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.max = max
+}
+
+// acquire registers a new operation, returning false if the maximum number of in-flight operations is reached.
+// Operations already in flight are always allowed.
+func (l *operationLimiter) acquire(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	for k, op := range l.inFlight {
+		if now.Sub(op.lastSeen) > operationTTL {
+			delete(l.inFlight, k)
+		}
+	}
+
+	op, ok := l.inFlight[key]
+	if !ok {
+		if l.max > 0 && len(l.inFlight) >= l.max {
+			return false
+		}
+		op = &inFlightOperation{}
+		l.inFlight[key] = op
+	}
+	op.lastSeen = now
+	op.attempts++
+	return true
+}
+
+// track registers an operation as in flight regardless of the limit, e.g. an operation started before a restart, and
+// returns the number of times it was started or checked.
+func (l *operationLimiter) track(key string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	op, ok := l.inFlight[key]
+	if !ok {
+		op = &inFlightOperation{}
+		l.inFlight[key] = op
+	}
+	op.lastSeen = l.now()
+	op.attempts++
+	return op.attempts
+}
+
+// release unregisters an operation once it is done.
+func (l *operationLimiter) release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.inFlight, key)
+}
+
+// operationKey returns the key identifying the long-running operation on a resource.
+func operationKey(serviceName, resourceGroup, resourceName string) string {
+	return fmt.Sprintf("%s/%s/%s", serviceName, resourceGroup, resourceName)
+}
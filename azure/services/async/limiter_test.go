@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package async
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestOperationLimiter(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	l := newOperationLimiter()
+	l.now = func() time.Time { return now }
+
+	// no limit by default
+	for _, key := range []string{"a", "b", "c"} {
+		g.Expect(l.acquire(key)).To(BeTrue())
+	}
+	l.release("a")
+	l.release("b")
+	l.release("c")
+
+	l.setMax(2)
+	g.Expect(l.acquire("a")).To(BeTrue())
+	g.Expect(l.acquire("b")).To(BeTrue())
+	g.Expect(l.acquire("c")).To(BeFalse())
+
+	// an operation already in flight is always allowed
+	g.Expect(l.acquire("a")).To(BeTrue())
+
+	// releasing an operation frees a slot
+	l.release("a")
+	g.Expect(l.acquire("c")).To(BeTrue())
+	g.Expect(l.acquire("a")).To(BeFalse())
+
+	// tracked operations count against the limit
+	l.release("b")
+	l.release("c")
+	l.track("d")
+	l.track("e")
+	l.track("f")
+	g.Expect(l.acquire("a")).To(BeFalse())
+
+	// stale operations are forgotten
+	now = now.Add(operationTTL + time.Minute)
+	g.Expect(l.acquire("a")).To(BeTrue())
+	g.Expect(l.inFlight).To(HaveLen(1))
+
+	// the attempts of an operation are counted from its start until its release
+	g.Expect(l.track("a")).To(Equal(2))
+	g.Expect(l.track("a")).To(Equal(3))
+	l.release("a")
+	g.Expect(l.track("a")).To(Equal(1))
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/inflight"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// DefaultPollerFrequency is how often track 2 clients poll a long-running operation while waiting for it to complete.
+const DefaultPollerFrequency = 1 * time.Second
+
+// PollerService is an implementation of the Reconciler interface for track 2 clients. It handles asynchronous creation
+// and deletion of resources, storing the resume token of their pollers in Status while the operations are ongoing.
+type PollerService struct {
+	Scope FutureScope
+	PollerCreator
+	PollerDeleter
+}
+
+// NewPollerService creates a new async service for track 2 clients.
+func NewPollerService(scope FutureScope, createClient PollerCreator, deleteClient PollerDeleter) *PollerService {
+	return &PollerService{
+		Scope:         scope,
+		PollerCreator: createClient,
+		PollerDeleter: deleteClient,
+	}
+}
+
+// CreateResource implements the logic for creating a resource Asynchronously.
+func (s *PollerService) CreateResource(ctx context.Context, spec azure.ResourceSpecGetter, serviceName string) (result interface{}, err error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "async.PollerService.CreateResource")
+	defer done()
+
+	resourceName := spec.ResourceName()
+	rgName := spec.ResourceGroupName()
+	log = log.WithValues(tele.LogKeyService, serviceName, tele.LogKeyResource, resourceName, tele.LogKeyResourceGroup, rgName)
+	key := operationKey(serviceName, rgName, resourceName)
+
+	// Check if there is an ongoing long running operation.
+	resumeToken, err := getResumeToken(s.Scope, resourceName, serviceName, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var parameters interface{}
+	if resumeToken != "" {
+		log.V(2).Info("resuming long running operation", tele.LogKeyOperation, infrav1.PutFuture)
+	} else {
+		// Get the resource if it already exists, and use it to construct the desired resource parameters.
+		var existingResource interface{}
+		if existing, err := s.PollerCreator.Get(ctx, spec); err != nil && !azure.ResourceNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get existing resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+		} else if err == nil {
+			existingResource = existing
+			existingID, _ := resourceIdentity(existing)
+			log.V(2).Info("successfully got existing resource", tele.LogKeyResourceID, existingID)
+		}
+
+		// Construct parameters using the resource spec and information from the existing resource, if there is one.
+		parameters, err = spec.Parameters(existingResource)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get desired parameters for resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+		} else if parameters == nil {
+			// Nothing to do, don't create or update the resource and return the existing resource.
+			log.V(2).Info("resource up to date")
+			return existingResource, nil
+		}
+
+		if !limiter.acquire(key) {
+			return nil, azure.WithTransientError(ErrTooManyOperations, reconciler.DefaultReconcilerRequeue)
+		}
+		log.V(2).Info("creating resource", tele.LogKeyOperation, infrav1.PutFuture)
+	}
+
+	// Create or update the resource with the desired parameters, or resume the ongoing operation.
+	var resp *http.Response
+	result, poller, err := s.PollerCreator.CreateOrUpdateAsync(runtime.WithCaptureResponse(ctx, &resp), spec, resumeToken, parameters)
+	if poller != nil && !poller.Done() {
+		return nil, operationNotDone(ctx, s.Scope, poller, resp, infrav1.PutFuture, serviceName, resourceName, rgName)
+	}
+
+	// The operation is done, failed, or failed to start. Even if it failed, clear the long running operation state so
+	// the next reconciliation starts a new one instead of resuming polling a failed operation.
+	limiter.release(key)
+	if resumeToken != "" {
+		s.Scope.DeleteLongRunningOperationState(resourceName, serviceName)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+	}
+
+	resultID, _ := resourceIdentity(result)
+	log.V(2).Info("successfully created resource", tele.LogKeyResourceID, resultID)
+	TrackResource(s.Scope, result, serviceName)
+	return result, nil
+}
+
+// DeleteResource implements the logic for deleting a resource Asynchronously.
+func (s *PollerService) DeleteResource(ctx context.Context, spec azure.ResourceSpecGetter, serviceName string) (err error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "async.PollerService.DeleteResource")
+	defer done()
+
+	resourceName := spec.ResourceName()
+	rgName := spec.ResourceGroupName()
+	log = log.WithValues(tele.LogKeyService, serviceName, tele.LogKeyResource, resourceName, tele.LogKeyResourceGroup, rgName)
+	key := operationKey(serviceName, rgName, resourceName)
+
+	// Check if there is an ongoing long running operation.
+	resumeToken, err := getResumeToken(s.Scope, resourceName, serviceName, key)
+	if err != nil {
+		return err
+	}
+
+	if resumeToken != "" {
+		log.V(2).Info("resuming long running operation", tele.LogKeyOperation, infrav1.DeleteFuture)
+	} else {
+		if !limiter.acquire(key) {
+			return azure.WithTransientError(ErrTooManyOperations, reconciler.DefaultReconcilerRequeue)
+		}
+		log.V(2).Info("deleting resource", tele.LogKeyOperation, infrav1.DeleteFuture)
+	}
+
+	// Delete the resource, or resume the ongoing operation.
+	var resp *http.Response
+	poller, err := s.PollerDeleter.DeleteAsync(runtime.WithCaptureResponse(ctx, &resp), spec, resumeToken)
+	if poller != nil && !poller.Done() {
+		return operationNotDone(ctx, s.Scope, poller, resp, infrav1.DeleteFuture, serviceName, resourceName, rgName)
+	}
+
+	// The operation is done, failed, or failed to start.
+	limiter.release(key)
+	if resumeToken != "" {
+		s.Scope.DeleteLongRunningOperationState(resourceName, serviceName)
+	}
+	if err != nil {
+		if azure.ResourceNotFound(err) {
+			// already deleted
+			untrackResource(s.Scope, resourceName, serviceName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+	}
+
+	log.V(2).Info("successfully deleted resource")
+	untrackResource(s.Scope, resourceName, serviceName)
+	return nil
+}
+
+// getResumeToken returns the resume token of the ongoing long-running operation on a resource, or "" if there is none.
+func getResumeToken(scope FutureScope, resourceName, serviceName, key string) (string, error) {
+	future := scope.GetLongRunningOperationState(resourceName, serviceName)
+	if future == nil {
+		return "", nil
+	}
+	token, err := converters.FutureToResumeToken(*future)
+	if err != nil {
+		limiter.release(key)
+		// Reset the future data to avoid getting stuck in a bad loop.
+		// This also happens when the future was stored by a track 1 client before the service was migrated, in which
+		// case the operation is started again.
+		scope.DeleteLongRunningOperationState(resourceName, serviceName)
+		return "", errors.Wrap(err, "could not decode future data, resetting long-running operation state")
+	}
+	limiter.track(key)
+	return token, nil
+}
+
+// operationNotDone stores the resume token of an ongoing operation in Status and returns a transient error to requeue
+// until it is done.
+func operationNotDone(ctx context.Context, scope FutureScope, poller azure.Poller, resp *http.Response, futureType, serviceName, resourceName, rgName string) error {
+	_, log, done := tele.StartSpanWithLogger(ctx, "async.operationNotDone")
+	defer done()
+
+	token, err := poller.ResumeToken()
+	if err != nil {
+		limiter.release(operationKey(serviceName, rgName, resourceName))
+		scope.DeleteLongRunningOperationState(resourceName, serviceName)
+		return errors.Wrapf(err, "failed to get resume token of the operation on resource %s/%s (service: %s)", rgName, resourceName, serviceName)
+	}
+	future := converters.ResumeTokenToFuture(token, futureType, serviceName, resourceName, rgName)
+	scope.SetLongRunningOperationState(future)
+	inflight.WaitingOn(ctx, *future)
+
+	notDoneErr := azure.NewOperationNotDoneError(future)
+	notDoneErr.Progress = pollerProgress(resp)
+	log.V(2).Info("long running operation is still ongoing", tele.LogKeyService, serviceName, tele.LogKeyResource, resourceName,
+		tele.LogKeyResourceGroup, rgName, tele.LogKeyOperation, futureType, "progress", notDoneErr.Progress)
+	return azure.WithTransientError(notDoneErr, pollerRetryAfter(resp))
+}
+
+// operationStatus is the body of the status of an Azure-AsyncOperation, of which only the progress is read.
+type operationStatus struct {
+	Status          string     `json:"status"`
+	PercentComplete *float64   `json:"percentComplete"`
+	StartTime       *time.Time `json:"startTime"`
+}
+
+// pollerProgress returns the progress of an ongoing operation from the last response received while polling it, or nil
+// if Azure doesn't report any.
+func pollerProgress(resp *http.Response) *azure.OperationProgress {
+	if resp == nil {
+		return nil
+	}
+	body, err := runtime.Payload(resp)
+	if err != nil {
+		return nil
+	}
+	var status operationStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil
+	}
+	if status.Status == "" && status.PercentComplete == nil && status.StartTime == nil {
+		return nil
+	}
+	return &azure.OperationProgress{
+		Status:          status.Status,
+		PercentComplete: status.PercentComplete,
+		StartTime:       status.StartTime,
+	}
+}
+
+// pollerRetryAfter returns the max between the `RETRY-AFTER` header of the last response received while polling an
+// operation and the default requeue time.
+func pollerRetryAfter(resp *http.Response) time.Duration {
+	retryAfter := reconciler.DefaultReconcilerRequeue
+	if resp == nil {
+		return retryAfter
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && time.Duration(seconds)*time.Second > retryAfter {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	return retryAfter
+}
@@ -0,0 +1,457 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package async
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+const (
+	fakeCreateResumeToken = `{"type":"GenericResourcesClientCreateOrUpdateResponse","token":{"state":"InProgress"}}`
+	fakeDeleteResumeToken = `{"type":"GenericResourcesClientDeleteResponse","token":{"state":"InProgress"}}`
+)
+
+var (
+	validCreateResumeFuture = converters.ResumeTokenToFuture(fakeCreateResumeToken, infrav1.PutFuture, "test-service", "test-resource", "test-group")
+	validDeleteResumeFuture = converters.ResumeTokenToFuture(fakeDeleteResumeToken, infrav1.DeleteFuture, "test-service", "test-resource", "test-group")
+	// validCreateFuture is a future stored by a track 1 client, which a track 2 client can't resume.
+	validCreateFuture = infrav1.Future{
+		Type:          infrav1.PutFuture,
+		ServiceName:   "test-service",
+		Name:          "test-resource",
+		ResourceGroup: "test-group",
+		Data:          "eyJtZXRob2QiOiJQVVQiLCJwb2xsaW5nTWV0aG9kIjoiTG9jYXRpb24iLCJscm9TdGF0ZSI6IkluUHJvZ3Jlc3MifQ==",
+	}
+	invalidFuture = infrav1.Future{
+		Type:          infrav1.DeleteFuture,
+		ServiceName:   "test-service",
+		Name:          "test-resource",
+		ResourceGroup: "test-group",
+		Data:          "ZmFrZSBiNjQgZnV0dXJlIGRhdGEK",
+	}
+	fakeExistingResource   = armresources.GenericResource{}
+	fakeResourceParameters = armresources.GenericResource{}
+	fakeInternalError      = errors.New("Internal Server Error")
+	fakeNotFoundError      = &azcore.ResponseError{StatusCode: http.StatusNotFound}
+)
+
+// TestPollerServiceCreateResource tests the CreateResource function of the track 2 async service.
+func TestPollerServiceCreateResource(t *testing.T) {
+	testcases := []struct {
+		name           string
+		serviceName    string
+		expectedError  string
+		expectedResult interface{}
+		expect         func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockPollerCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller)
+	}{
+		{
+			name:          "create operation is already in progress",
+			expectedError: "operation type PUT on Azure resource test-group/test-resource is not done. Object will be requeued after 15s",
+			serviceName:   "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockPollerCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(validCreateResumeFuture)
+				c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), fakeCreateResumeToken, nil).Return(nil, p, context.DeadlineExceeded)
+				p.EXPECT().Done().Return(false)
+				p.EXPECT().ResumeToken().Return(fakeCreateResumeToken, nil)
+				s.SetLongRunningOperationState(validCreateResumeFuture)
+			},
+		},
+		{
+			name:           "create operation in progress is done",
+			expectedResult: &fakeExistingResource,
+			serviceName:    "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockPollerCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(validCreateResumeFuture)
+				c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), fakeCreateResumeToken, nil).Return(&fakeExistingResource, nil, nil)
+				s.DeleteLongRunningOperationState("test-resource", "test-service")
+			},
+		},
+		{
+			name:          "create operation in progress failed",
+			expectedError: "failed to create resource test-group/test-resource (service: test-service)",
+			serviceName:   "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockPollerCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(validCreateResumeFuture)
+				c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), fakeCreateResumeToken, nil).Return(nil, nil, fakeInternalError)
+				s.DeleteLongRunningOperationState("test-resource", "test-service")
+			},
+		},
+		{
+			name:          "create operation in progress reached a failed terminal state",
+			expectedError: "failed to create resource test-group/test-resource (service: test-service)",
+			serviceName:   "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockPollerCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(validCreateResumeFuture)
+				c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), fakeCreateResumeToken, nil).Return(nil, p, fakeInternalError)
+				p.EXPECT().Done().Return(true)
+				s.DeleteLongRunningOperationState("test-resource", "test-service")
+			},
+		},
+		{
+			name:          "future data stored in status is not a resume token",
+			expectedError: "could not decode future data, resetting long-running operation state",
+			serviceName:   "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockPollerCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(&validCreateFuture)
+				s.DeleteLongRunningOperationState("test-resource", "test-service")
+			},
+		},
+		{
+			name:           "create async returns success",
+			expectedResult: "test-resource",
+			serviceName:    "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockPollerCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(nil)
+				c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{})).Return(&fakeExistingResource, nil)
+				r.Parameters(&fakeExistingResource).Return(&fakeResourceParameters, nil)
+				c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), "", &fakeResourceParameters).Return("test-resource", nil, nil)
+			},
+		},
+		{
+			name:          "error occurs while running async get",
+			expectedError: "failed to get existing resource test-group/test-resource (service: test-service)",
+			serviceName:   "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockPollerCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(nil)
+				c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{})).Return(nil, fakeInternalError)
+			},
+		},
+		{
+			name:           "async get returns not found",
+			serviceName:    "test-service",
+			expectedResult: &fakeExistingResource,
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockPollerCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(nil)
+				c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{})).Return(nil, fakeNotFoundError)
+				r.Parameters(nil).Return(&fakeResourceParameters, nil)
+				c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), "", &fakeResourceParameters).Return(&fakeExistingResource, nil, nil)
+			},
+		},
+		{
+			name:           "async spec parameters returns nil",
+			serviceName:    "test-service",
+			expectedResult: &fakeExistingResource,
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockPollerCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(nil)
+				c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{})).Return(&fakeExistingResource, nil)
+				r.Parameters(&fakeExistingResource).Return(nil, nil)
+			},
+		},
+		{
+			name:          "error occurs while running async create",
+			expectedError: "failed to create resource test-group/test-resource (service: test-service)",
+			serviceName:   "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockPollerCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(nil)
+				c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{})).Return(&fakeExistingResource, nil)
+				r.Parameters(&fakeExistingResource).Return(&fakeResourceParameters, nil)
+				c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), "", &fakeResourceParameters).Return(nil, nil, fakeInternalError)
+			},
+		},
+		{
+			name:          "create async exits before completing",
+			expectedError: "operation type PUT on Azure resource test-group/test-resource is not done. Object will be requeued after 15s",
+			serviceName:   "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, c *mock_async.MockPollerCreatorMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(nil)
+				c.Get(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{})).Return(&fakeExistingResource, nil)
+				r.Parameters(&fakeExistingResource).Return(&fakeResourceParameters, nil)
+				c.CreateOrUpdateAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), "", &fakeResourceParameters).Return(nil, p, context.DeadlineExceeded)
+				p.EXPECT().Done().Return(false)
+				p.EXPECT().ResumeToken().Return(fakeCreateResumeToken, nil)
+				s.SetLongRunningOperationState(validCreateResumeFuture)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_async.NewMockFutureScope(mockCtrl)
+			creatorMock := mock_async.NewMockPollerCreator(mockCtrl)
+			specMock := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+			pollerMock := mock_azure.NewMockPoller(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), creatorMock.EXPECT(), specMock.EXPECT(), pollerMock)
+
+			s := NewPollerService(scopeMock, creatorMock, nil)
+			result, err := s.CreateResource(context.TODO(), specMock, tc.serviceName)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(result).To(Equal(tc.expectedResult))
+			}
+		})
+	}
+}
+
+// TestPollerServiceDeleteResource tests the DeleteResource function of the track 2 async service.
+func TestPollerServiceDeleteResource(t *testing.T) {
+	testcases := []struct {
+		name          string
+		serviceName   string
+		expectedError string
+		expect        func(s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockPollerDeleterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller)
+	}{
+		{
+			name:          "delete operation is already in progress",
+			expectedError: "operation type DELETE on Azure resource test-group/test-resource is not done. Object will be requeued after 15s",
+			serviceName:   "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockPollerDeleterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(validDeleteResumeFuture)
+				d.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), fakeDeleteResumeToken).Return(p, context.DeadlineExceeded)
+				p.EXPECT().Done().Return(false)
+				p.EXPECT().ResumeToken().Return(fakeDeleteResumeToken, nil)
+				s.SetLongRunningOperationState(validDeleteResumeFuture)
+			},
+		},
+		{
+			name:        "delete operation in progress is done",
+			serviceName: "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockPollerDeleterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(validDeleteResumeFuture)
+				d.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), fakeDeleteResumeToken).Return(nil, nil)
+				s.DeleteLongRunningOperationState("test-resource", "test-service")
+			},
+		},
+		{
+			name:          "future data stored in status is not a resume token",
+			expectedError: "could not decode future data, resetting long-running operation state",
+			serviceName:   "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockPollerDeleterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(&invalidFuture)
+				s.DeleteLongRunningOperationState("test-resource", "test-service")
+			},
+		},
+		{
+			name:        "delete async returns success",
+			serviceName: "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockPollerDeleterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(nil)
+				d.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), "").Return(nil, nil)
+			},
+		},
+		{
+			name:        "delete async returns not found",
+			serviceName: "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockPollerDeleterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(nil)
+				d.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), "").Return(nil, fakeNotFoundError)
+			},
+		},
+		{
+			name:          "error occurs while running async delete",
+			expectedError: "failed to delete resource test-group/test-resource (service: test-service)",
+			serviceName:   "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockPollerDeleterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(nil)
+				d.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), "").Return(nil, fakeInternalError)
+			},
+		},
+		{
+			name:          "delete async exits before completing",
+			expectedError: "operation type DELETE on Azure resource test-group/test-resource is not done. Object will be requeued after 15s",
+			serviceName:   "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockPollerDeleterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(nil)
+				d.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), "").Return(p, context.DeadlineExceeded)
+				p.EXPECT().Done().Return(false)
+				p.EXPECT().ResumeToken().Return(fakeDeleteResumeToken, nil)
+				s.SetLongRunningOperationState(validDeleteResumeFuture)
+			},
+		},
+		{
+			name:          "resume token of the ongoing operation can't be read",
+			expectedError: "failed to get resume token of the operation on resource test-group/test-resource (service: test-service)",
+			serviceName:   "test-service",
+			expect: func(s *mock_async.MockFutureScopeMockRecorder, d *mock_async.MockPollerDeleterMockRecorder, r *mock_azure.MockResourceSpecGetterMockRecorder, p *mock_azure.MockPoller) {
+				r.ResourceName().Return("test-resource")
+				r.ResourceGroupName().Return("test-group")
+				s.GetLongRunningOperationState("test-resource", "test-service").Return(nil)
+				d.DeleteAsync(gomockinternal.AContext(), gomock.AssignableToTypeOf(&mock_azure.MockResourceSpecGetter{}), "").Return(p, context.DeadlineExceeded)
+				p.EXPECT().Done().Return(false)
+				p.EXPECT().ResumeToken().Return("", fakeInternalError)
+				s.DeleteLongRunningOperationState("test-resource", "test-service")
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_async.NewMockFutureScope(mockCtrl)
+			deleterMock := mock_async.NewMockPollerDeleter(mockCtrl)
+			specMock := mock_azure.NewMockResourceSpecGetter(mockCtrl)
+			pollerMock := mock_azure.NewMockPoller(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), deleterMock.EXPECT(), specMock.EXPECT(), pollerMock)
+
+			s := NewPollerService(scopeMock, nil, deleterMock)
+			err := s.DeleteResource(context.TODO(), specMock, tc.serviceName)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestPollerProgress(t *testing.T) {
+	startTime := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		name     string
+		resp     *http.Response
+		expected *azure.OperationProgress
+	}{
+		{
+			name:     "no response received",
+			resp:     nil,
+			expected: nil,
+		},
+		{
+			name:     "no progress reported",
+			resp:     &http.Response{Body: io.NopCloser(strings.NewReader(`{"name":"test-resource","properties":{"provisioningState":"Updating"}}`))},
+			expected: nil,
+		},
+		{
+			name:     "progress of an async operation",
+			resp:     &http.Response{Body: io.NopCloser(strings.NewReader(`{"name":"test-operation","status":"InProgress","percentComplete":42.5,"startTime":"2022-06-01T10:00:00Z"}`))},
+			expected: &azure.OperationProgress{Status: "InProgress", PercentComplete: to.Ptr[float64](42.5), StartTime: &startTime},
+		},
+		{
+			name:     "unexpected body",
+			resp:     &http.Response{Body: io.NopCloser(strings.NewReader(`not json`))},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			g.Expect(pollerProgress(tc.resp)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestPollerRetryAfter(t *testing.T) {
+	testcases := []struct {
+		name     string
+		resp     *http.Response
+		expected time.Duration
+	}{
+		{
+			name:     "no response received",
+			resp:     nil,
+			expected: 15 * time.Second,
+		},
+		{
+			name:     "no retry-after header",
+			resp:     &http.Response{Header: http.Header{}},
+			expected: 15 * time.Second,
+		},
+		{
+			name:     "retry-after header shorter than the default requeue",
+			resp:     &http.Response{Header: http.Header{"Retry-After": []string{"5"}}},
+			expected: 15 * time.Second,
+		},
+		{
+			name:     "retry-after header longer than the default requeue",
+			resp:     &http.Response{Header: http.Header{"Retry-After": []string{"60"}}},
+			expected: time.Minute,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			g.Expect(pollerRetryAfter(tc.resp)).To(Equal(tc.expected))
+		})
+	}
+}
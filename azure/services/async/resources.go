@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package async
+
+import (
+	"reflect"
+
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// TrackResource records the resource returned by a create or update operation in Status,
+// if the scope keeps track of the Azure resources it manages.
+func TrackResource(scope interface{}, result interface{}, serviceName string) {
+	tracker, ok := scope.(azure.ResourceTracker)
+	if !ok {
+		return
+	}
+	id, resourceType := resourceIdentity(result)
+	if id == "" {
+		return
+	}
+	tracker.SetResourceStatus(id, resourceType, serviceName)
+}
+
+// untrackResource removes a deleted resource from Status, if the scope keeps track of the Azure resources it manages.
+func untrackResource(scope interface{}, resourceName, serviceName string) {
+	if tracker, ok := scope.(azure.ResourceTracker); ok {
+		tracker.DeleteResourceStatus(resourceName, serviceName)
+	}
+}
+
+// resourceIdentity returns the ID and Type fields of an Azure SDK resource.
+// Read-only fields like these are omitted when SDK types are marshaled, so they are read with reflection.
+func resourceIdentity(result interface{}) (id, resourceType string) {
+	v := reflect.ValueOf(result)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", ""
+	}
+	return stringField(v, "ID"), stringField(v, "Type")
+}
+
+// stringField returns the value of a string or *string field of a struct, or "" if it is not set.
+func stringField(v reflect.Value, name string) string {
+	f := v.FieldByName(name)
+	if !f.IsValid() {
+		return ""
+	}
+	if f.Kind() == reflect.Ptr {
+		if f.IsNil() {
+			return ""
+		}
+		f = f.Elem()
+	}
+	if f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
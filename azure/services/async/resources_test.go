@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package async
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/gomega"
+)
+
+type fakeTracker struct {
+	set     map[string]string
+	deleted []string
+}
+
+func (f *fakeTracker) SetResourceStatus(id, resourceType, service string) {
+	f.set[id] = resourceType
+}
+
+func (f *fakeTracker) DeleteResourceStatus(name, service string) {
+	f.deleted = append(f.deleted, name)
+}
+
+func TestTrackResource(t *testing.T) {
+	vnetID := "/subscriptions/123/resourceGroups/test-group/providers/Microsoft.Network/virtualNetworks/test-vnet"
+
+	tests := []struct {
+		name   string
+		result interface{}
+		want   map[string]string
+	}{
+		{
+			name: "records a resource",
+			result: armnetwork.VirtualNetwork{
+				ID:   to.Ptr(vnetID),
+				Type: to.Ptr("Microsoft.Network/virtualNetworks"),
+			},
+			want: map[string]string{vnetID: "Microsoft.Network/virtualNetworks"},
+		},
+		{
+			name:   "records a resource pointer without a type",
+			result: &armnetwork.VirtualNetwork{ID: to.Ptr(vnetID)},
+			want:   map[string]string{vnetID: ""},
+		},
+		{
+			name:   "ignores a resource without an ID",
+			result: armnetwork.VirtualNetwork{},
+			want:   map[string]string{},
+		},
+		{
+			name:   "ignores a nil result",
+			result: (*armnetwork.VirtualNetwork)(nil),
+			want:   map[string]string{},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			tracker := &fakeTracker{set: map[string]string{}}
+
+			TrackResource(tracker, tc.result, "test-service")
+
+			g.Expect(tracker.set).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestUntrackResource(t *testing.T) {
+	g := NewWithT(t)
+	tracker := &fakeTracker{set: map[string]string{}}
+
+	untrackResource(tracker, "test-resource", "test-service")
+	// Scopes that don't keep track of resources are ignored.
+	untrackResource(struct{}{}, "test-resource", "test-service")
+
+	g.Expect(tracker.deleted).To(Equal([]string{"test-resource"}))
+}
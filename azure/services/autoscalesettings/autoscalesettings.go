@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscalesettings
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "autoscalesettings"
+
+// AutoscaleSettingScope defines the scope interface for an autoscale settings service.
+type AutoscaleSettingScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	AutoscaleSettingSpec() azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope AutoscaleSettingScope
+	client
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope AutoscaleSettingScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:      scope,
+		client:     client,
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile creates or updates the autoscale setting of the scale set, or deletes it when autoscaling was disabled.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "autoscalesettings.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.AutoscaleSettingSpec()
+	if spec == nil {
+		return nil
+	}
+	settingSpec, ok := spec.(*AutoscaleSettingSpec)
+	if !ok {
+		return errors.Errorf("%T is not an *AutoscaleSettingSpec", spec)
+	}
+
+	if settingSpec.Autoscale == nil {
+		err := s.DeleteResource(ctx, spec, serviceName)
+		s.Scope.UpdateDeleteStatus(infrav1.AutoscaleSettingReadyCondition, serviceName, err)
+		return err
+	}
+
+	_, err := s.CreateResource(ctx, spec, serviceName)
+	s.Scope.UpdatePutStatus(infrav1.AutoscaleSettingReadyCondition, serviceName, err)
+	return err
+}
+
+// Delete deletes the autoscale setting of the scale set.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "autoscalesettings.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.AutoscaleSettingSpec()
+	if spec == nil {
+		return nil
+	}
+
+	err := s.DeleteResource(ctx, spec, serviceName)
+	s.Scope.UpdateDeleteStatus(infrav1.AutoscaleSettingReadyCondition, serviceName, err)
+	return err
+}
+
+// IsManaged always returns true as the autoscale setting of the scale set is always created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
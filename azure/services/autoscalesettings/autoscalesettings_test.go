@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscalesettings
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/autoscalesettings/mock_autoscalesettings"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeAutoscaleSettingSpec = AutoscaleSettingSpec{
+		Name:             "my-vmss",
+		ResourceGroup:    "my-rg",
+		ClusterName:      "my-cluster",
+		Location:         "westus2",
+		TargetResourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss",
+		Autoscale: &infrav1exp.AzureMachinePoolAutoscale{
+			MinReplicas: 1,
+			MaxReplicas: 10,
+			Rules: []infrav1exp.AutoscaleRule{
+				{
+					MetricName: "Percentage CPU",
+					Operator:   "GreaterThan",
+					Threshold:  resource.MustParse("75"),
+					Direction:  "Increase",
+				},
+				{
+					MetricName: "Percentage CPU",
+					Operator:   "LessThan",
+					Threshold:  resource.MustParse("25"),
+					Direction:  "Decrease",
+				},
+			},
+		},
+	}
+	fakeDisabledAutoscaleSettingSpec = AutoscaleSettingSpec{
+		Name:             "my-vmss",
+		ResourceGroup:    "my-rg",
+		ClusterName:      "my-cluster",
+		Location:         "westus2",
+		TargetResourceID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss",
+	}
+
+	internalError = errors.New("Internal Server Error")
+)
+
+func TestReconcileAutoscaleSetting(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_autoscalesettings.MockAutoscaleSettingScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the scale set was never autoscaled",
+			expectedError: "",
+			expect: func(s *mock_autoscalesettings.MockAutoscaleSettingScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.AutoscaleSettingSpec().Return(nil)
+			},
+		},
+		{
+			name:          "create the autoscale setting of the scale set",
+			expectedError: "",
+			expect: func(s *mock_autoscalesettings.MockAutoscaleSettingScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.AutoscaleSettingSpec().Return(&fakeAutoscaleSettingSpec)
+				r.CreateResource(gomockinternal.AContext(), &fakeAutoscaleSettingSpec, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.AutoscaleSettingReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to create the autoscale setting of the scale set",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_autoscalesettings.MockAutoscaleSettingScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.AutoscaleSettingSpec().Return(&fakeAutoscaleSettingSpec)
+				r.CreateResource(gomockinternal.AContext(), &fakeAutoscaleSettingSpec, serviceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.AutoscaleSettingReadyCondition, serviceName, internalError)
+			},
+		},
+		{
+			name:          "delete the autoscale setting when autoscaling was disabled",
+			expectedError: "",
+			expect: func(s *mock_autoscalesettings.MockAutoscaleSettingScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.AutoscaleSettingSpec().Return(&fakeDisabledAutoscaleSettingSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeDisabledAutoscaleSettingSpec, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.AutoscaleSettingReadyCondition, serviceName, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_autoscalesettings.NewMockAutoscaleSettingScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteAutoscaleSetting(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_autoscalesettings.MockAutoscaleSettingScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the scale set was never autoscaled",
+			expectedError: "",
+			expect: func(s *mock_autoscalesettings.MockAutoscaleSettingScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.AutoscaleSettingSpec().Return(nil)
+			},
+		},
+		{
+			name:          "delete the autoscale setting of the scale set",
+			expectedError: "",
+			expect: func(s *mock_autoscalesettings.MockAutoscaleSettingScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.AutoscaleSettingSpec().Return(&fakeAutoscaleSettingSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeAutoscaleSettingSpec, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.AutoscaleSettingReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to delete the autoscale setting of the scale set",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_autoscalesettings.MockAutoscaleSettingScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.AutoscaleSettingSpec().Return(&fakeAutoscaleSettingSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeAutoscaleSettingSpec, serviceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.AutoscaleSettingReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_autoscalesettings.NewMockAutoscaleSettingScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscalesettings
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, azure.ResourceSpecGetter) (result interface{}, err error)
+	CreateOrUpdateAsync(context.Context, azure.ResourceSpecGetter, string, interface{}) (result interface{}, poller azure.Poller, err error)
+	DeleteAsync(context.Context, azure.ResourceSpecGetter, string) (poller azure.Poller, err error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	autoscalesettings *armmonitor.AutoscaleSettingsClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new autoscale settings client from subscription ID.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armmonitor.NewAutoscaleSettingsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create autoscale settings client")
+	}
+	return &azureClient{c}, nil
+}
+
+// Get gets the specified autoscale setting.
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "autoscalesettings.azureClient.Get")
+	defer done()
+
+	resp, err := ac.autoscalesettings.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.AutoscaleSettingResource, nil
+}
+
+// CreateOrUpdateAsync creates or updates an autoscale setting.
+// Creating an autoscale setting is not a long running operation, so we don't ever return a poller.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "autoscalesettings.azureClient.CreateOrUpdateAsync")
+	defer done()
+
+	setting, ok := parameters.(armmonitor.AutoscaleSettingResource)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not an armmonitor.AutoscaleSettingResource", parameters)
+	}
+
+	resp, err := ac.autoscalesettings.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), setting, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.AutoscaleSettingResource, nil, nil
+}
+
+// DeleteAsync deletes an autoscale setting.
+// Deleting an autoscale setting is not a long running operation, so we don't ever return a poller.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "autoscalesettings.azureClient.DeleteAsync")
+	defer done()
+
+	_, err = ac.autoscalesettings.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	return nil, err
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscalesettings
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+)
+
+const (
+	// profileName is the name of the single profile of the autoscale settings managed by CAPZ.
+	profileName = "capz"
+	// timeGrain is the granularity of the metrics monitored by the rules, the finest Azure Monitor supports.
+	timeGrain = "PT1M"
+
+	defaultTimeWindow  = 10
+	defaultChangeCount = 1
+	defaultCooldown    = 5
+)
+
+// AutoscaleSettingSpec defines the specification for the autoscale setting of a scale set.
+type AutoscaleSettingSpec struct {
+	Name             string
+	ResourceGroup    string
+	ClusterName      string
+	Location         string
+	TargetResourceID string
+	// Autoscale is the autoscale profile of the scale set. When nil, the autoscale setting is deleted.
+	Autoscale      *infrav1exp.AzureMachinePoolAutoscale
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the autoscale setting.
+func (s *AutoscaleSettingSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *AutoscaleSettingSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for autoscale settings.
+func (s *AutoscaleSettingSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the autoscale setting.
+func (s *AutoscaleSettingSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	if s.Autoscale == nil {
+		return nil, errors.New("autoscale profile is required to create an autoscale setting")
+	}
+
+	profile := s.profile()
+	if existing != nil {
+		existingSetting, ok := existing.(armmonitor.AutoscaleSettingResource)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armmonitor.AutoscaleSettingResource", existing)
+		}
+		if existingSetting.Properties != nil && len(existingSetting.Properties.Profiles) == 1 && existingSetting.Properties.Profiles[0] != nil &&
+			pointer.BoolDeref(existingSetting.Properties.Enabled, false) && profileMatches(*existingSetting.Properties.Profiles[0], profile) {
+			// autoscale setting is up to date
+			return nil, nil
+		}
+	}
+
+	return armmonitor.AutoscaleSettingResource{
+		Properties: &armmonitor.AutoscaleSetting{
+			Name:              to.Ptr(s.Name),
+			Enabled:           to.Ptr(true),
+			TargetResourceURI: to.Ptr(s.TargetResourceID),
+			Profiles:          []*armmonitor.AutoscaleProfile{&profile},
+		},
+		Location: to.Ptr(s.Location),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.Ptr(s.Name),
+			Role:        to.Ptr(infrav1.Node),
+			Additional:  s.AdditionalTags,
+		})),
+	}, nil
+}
+
+// profile returns the autoscale profile of the scale set.
+func (s *AutoscaleSettingSpec) profile() armmonitor.AutoscaleProfile {
+	defaultReplicas := s.Autoscale.MinReplicas
+	if s.Autoscale.DefaultReplicas != nil {
+		defaultReplicas = *s.Autoscale.DefaultReplicas
+	}
+
+	rules := make([]*armmonitor.ScaleRule, 0, len(s.Autoscale.Rules))
+	for _, rule := range s.Autoscale.Rules {
+		metricResourceID := rule.MetricResourceID
+		if metricResourceID == "" {
+			metricResourceID = s.TargetResourceID
+		}
+		var metricNamespace *string
+		if rule.MetricNamespace != "" {
+			metricNamespace = to.Ptr(rule.MetricNamespace)
+		}
+		statistic := armmonitor.MetricStatisticType(rule.Statistic)
+		if statistic == "" {
+			statistic = armmonitor.MetricStatisticTypeAverage
+		}
+		timeAggregation := armmonitor.TimeAggregationType(rule.TimeAggregation)
+		if timeAggregation == "" {
+			timeAggregation = armmonitor.TimeAggregationTypeAverage
+		}
+
+		rules = append(rules, &armmonitor.ScaleRule{
+			MetricTrigger: &armmonitor.MetricTrigger{
+				MetricName:        to.Ptr(rule.MetricName),
+				MetricNamespace:   metricNamespace,
+				MetricResourceURI: to.Ptr(metricResourceID),
+				TimeGrain:         to.Ptr(timeGrain),
+				Statistic:         to.Ptr(statistic),
+				TimeWindow:        to.Ptr(minutes(rule.TimeWindow, defaultTimeWindow)),
+				TimeAggregation:   to.Ptr(timeAggregation),
+				Operator:          to.Ptr(armmonitor.ComparisonOperationType(rule.Operator)),
+				Threshold:         to.Ptr(rule.Threshold.AsApproximateFloat64()),
+			},
+			ScaleAction: &armmonitor.ScaleAction{
+				Direction: to.Ptr(armmonitor.ScaleDirection(rule.Direction)),
+				Type:      to.Ptr(armmonitor.ScaleTypeChangeCount),
+				Value:     to.Ptr(strconv.Itoa(int(valueOrDefault(rule.ChangeCount, defaultChangeCount)))),
+				Cooldown:  to.Ptr(minutes(rule.Cooldown, defaultCooldown)),
+			},
+		})
+	}
+
+	return armmonitor.AutoscaleProfile{
+		Name: to.Ptr(profileName),
+		Capacity: &armmonitor.ScaleCapacity{
+			Minimum: to.Ptr(strconv.Itoa(int(s.Autoscale.MinReplicas))),
+			Maximum: to.Ptr(strconv.Itoa(int(s.Autoscale.MaxReplicas))),
+			Default: to.Ptr(strconv.Itoa(int(defaultReplicas))),
+		},
+		Rules: rules,
+	}
+}
+
+// profileMatches returns true if an existing autoscale profile has the capacity and the rules of the desired one.
+func profileMatches(existing, desired armmonitor.AutoscaleProfile) bool {
+	if existing.Capacity == nil || existing.Rules == nil ||
+		pointer.StringDeref(existing.Capacity.Minimum, "") != pointer.StringDeref(desired.Capacity.Minimum, "") ||
+		pointer.StringDeref(existing.Capacity.Maximum, "") != pointer.StringDeref(desired.Capacity.Maximum, "") ||
+		pointer.StringDeref(existing.Capacity.Default, "") != pointer.StringDeref(desired.Capacity.Default, "") ||
+		len(existing.Rules) != len(desired.Rules) {
+		return false
+	}
+
+	for i, rule := range desired.Rules {
+		existingRule := existing.Rules[i]
+		if existingRule == nil || existingRule.MetricTrigger == nil || existingRule.ScaleAction == nil {
+			return false
+		}
+		trigger, existingTrigger := rule.MetricTrigger, existingRule.MetricTrigger
+		if pointer.StringDeref(existingTrigger.MetricName, "") != pointer.StringDeref(trigger.MetricName, "") ||
+			(trigger.MetricNamespace != nil && !strings.EqualFold(pointer.StringDeref(existingTrigger.MetricNamespace, ""), *trigger.MetricNamespace)) ||
+			!strings.EqualFold(pointer.StringDeref(existingTrigger.MetricResourceURI, ""), pointer.StringDeref(trigger.MetricResourceURI, "")) ||
+			!equalPtr(existingTrigger.Statistic, trigger.Statistic) ||
+			pointer.StringDeref(existingTrigger.TimeWindow, "") != pointer.StringDeref(trigger.TimeWindow, "") ||
+			!equalPtr(existingTrigger.TimeAggregation, trigger.TimeAggregation) ||
+			!equalPtr(existingTrigger.Operator, trigger.Operator) ||
+			!equalPtr(existingTrigger.Threshold, trigger.Threshold) {
+			return false
+		}
+		action, existingAction := rule.ScaleAction, existingRule.ScaleAction
+		if !equalPtr(existingAction.Direction, action.Direction) ||
+			!equalPtr(existingAction.Type, action.Type) ||
+			pointer.StringDeref(existingAction.Value, "") != pointer.StringDeref(action.Value, "") ||
+			pointer.StringDeref(existingAction.Cooldown, "") != pointer.StringDeref(action.Cooldown, "") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equalPtr returns true if two pointers are both nil or point to equal values.
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// minutes returns a number of minutes, or a default when it is not set, as an ISO 8601 duration.
+func minutes(value int32, defaultValue int32) string {
+	return fmt.Sprintf("PT%dM", valueOrDefault(value, defaultValue))
+}
+
+// valueOrDefault returns a value, or a default when it is not set.
+func valueOrDefault(value int32, defaultValue int32) int32 {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscalesettings
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := fakeAutoscaleSettingSpec
+	params, err := spec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	setting, ok := params.(armmonitor.AutoscaleSettingResource)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(*setting.Properties.TargetResourceURI).To(Equal(spec.TargetResourceID))
+	g.Expect(setting.Properties.Profiles).To(HaveLen(1))
+	profile := setting.Properties.Profiles[0]
+	g.Expect(*profile.Capacity).To(Equal(armmonitor.ScaleCapacity{
+		Minimum: to.Ptr("1"),
+		Maximum: to.Ptr("10"),
+		Default: to.Ptr("1"),
+	}))
+	g.Expect(profile.Rules).To(HaveLen(2))
+	scaleOut := profile.Rules[0]
+	g.Expect(*scaleOut.MetricTrigger).To(Equal(armmonitor.MetricTrigger{
+		MetricName:        to.Ptr("Percentage CPU"),
+		MetricResourceURI: to.Ptr(spec.TargetResourceID),
+		TimeGrain:         to.Ptr("PT1M"),
+		Statistic:         to.Ptr(armmonitor.MetricStatisticTypeAverage),
+		TimeWindow:        to.Ptr("PT10M"),
+		TimeAggregation:   to.Ptr(armmonitor.TimeAggregationTypeAverage),
+		Operator:          to.Ptr(armmonitor.ComparisonOperationTypeGreaterThan),
+		Threshold:         to.Ptr[float64](75),
+	}))
+	g.Expect(*scaleOut.ScaleAction).To(Equal(armmonitor.ScaleAction{
+		Direction: to.Ptr(armmonitor.ScaleDirectionIncrease),
+		Type:      to.Ptr(armmonitor.ScaleTypeChangeCount),
+		Value:     to.Ptr("1"),
+		Cooldown:  to.Ptr("PT5M"),
+	}))
+
+	// An existing autoscale setting with the same profile is left untouched.
+	params, err = spec.Parameters(setting)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).To(BeNil())
+
+	// A changed threshold updates the existing autoscale setting.
+	changed := spec
+	autoscale := *spec.Autoscale
+	autoscale.Rules = append(autoscale.Rules[:0:0], autoscale.Rules...)
+	autoscale.Rules[0].Threshold = resource.MustParse("80")
+	changed.Autoscale = &autoscale
+	params, err = changed.Parameters(setting)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).NotTo(BeNil())
+
+	// A disabled autoscale setting is updated to be enabled again.
+	setting.Properties.Enabled = to.Ptr(false)
+	params, err = spec.Parameters(setting)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).NotTo(BeNil())
+
+	_, err = spec.Parameters("not an autoscale setting")
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = fakeDisabledAutoscaleSettingSpec.Parameters(nil)
+	g.Expect(err).To(HaveOccurred())
+}
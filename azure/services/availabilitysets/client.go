@@ -19,9 +19,7 @@ package availabilitysets
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest"
-	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -29,21 +27,22 @@ import (
 
 // AzureClient contains the Azure go-sdk Client.
 type AzureClient struct {
-	availabilitySets compute.AvailabilitySetsClient
+	availabilitySets *armcompute.AvailabilitySetsClient
 }
 
-// NewClient creates a new Resource SKUs Client from subscription ID.
-func NewClient(auth azure.Authorizer) *AzureClient {
-	return &AzureClient{
-		availabilitySets: newAvailabilitySetsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+// NewClient creates a new availability sets client from subscription ID.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
 	}
-}
-
-// newAvailabilitySetsClient creates a new AvailabilitySets Client from subscription ID.
-func newAvailabilitySetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.AvailabilitySetsClient {
-	asClient := compute.NewAvailabilitySetsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&asClient.Client, authorizer)
-	return asClient
+	c, err := armcompute.NewAvailabilitySetsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create availability sets client")
+	}
+	return &AzureClient{
+		availabilitySets: c,
+	}, nil
 }
 
 // Get gets an availability set.
@@ -51,56 +50,37 @@ func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "availabilitysets.AzureClient.Get")
 	defer done()
 
-	return ac.availabilitySets.Get(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	resp, err := ac.availabilitySets.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.AvailabilitySet, nil
 }
 
-// CreateOrUpdateAsync creates or updates a availability set asynchronously.
-// It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
-// progress of the operation.
-func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (result interface{}, future azureautorest.FutureAPI, err error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "availabilitySets.AzureClient.CreateOrUpdateAsync")
+// CreateOrUpdateAsync creates or updates an availability set.
+// Creating an availability set is not a long running operation, so we don't ever return a poller.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "availabilitysets.AzureClient.CreateOrUpdateAsync")
 	defer done()
 
-	availabilitySet, ok := parameters.(compute.AvailabilitySet)
+	availabilitySet, ok := parameters.(armcompute.AvailabilitySet)
 	if !ok {
-		return nil, nil, errors.Errorf("%T is not a compute.AvailabilitySet", parameters)
+		return nil, nil, errors.Errorf("%T is not an armcompute.AvailabilitySet", parameters)
 	}
 
-	result, err = ac.availabilitySets.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), availabilitySet)
-	return result, nil, err
-}
-
-// DeleteAsync deletes a availability set asynchronously. DeleteAsync sends a DELETE
-// request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
-// progress of the operation.
-func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (future azureautorest.FutureAPI, err error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "availabilitysets.AzureClient.Delete")
-	defer done()
-
-	_, err = ac.availabilitySets.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName())
-
+	resp, err := ac.availabilitySets.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), availabilitySet, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	return nil, nil
+	return resp.AvailabilitySet, nil, nil
 }
 
-// Result fetches the result of a long-running operation future.
-func (ac *AzureClient) Result(ctx context.Context, future azureautorest.FutureAPI, futureType string) (result interface{}, err error) {
-	// Result is a no-op for resource groups as only Delete operations return a future.
-	return nil, nil
-}
-
-// IsDone returns true if the long-running operation has completed.
-func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (isDone bool, err error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "availabilitysets.AzureClient.IsDone")
+// DeleteAsync deletes an availability set.
+// Deleting an availability set is not a long running operation, so we don't ever return a poller.
+func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "availabilitysets.AzureClient.DeleteAsync")
 	defer done()
 
-	isDone, err = future.DoneWithContext(ctx, ac.availabilitySets)
-	if err != nil {
-		return false, errors.Wrap(err, "failed checking if the operation was complete")
-	}
-
-	return isDone, nil
+	_, err = ac.availabilitySets.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	return nil, err
 }
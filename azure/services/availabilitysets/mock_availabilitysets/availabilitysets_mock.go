@@ -23,7 +23,7 @@ package mock_availabilitysets
 import (
 	reflect "reflect"
 
-	autorest "github.com/Azure/go-autorest/autorest"
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	gomock "github.com/golang/mock/gomock"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -67,18 +67,18 @@ func (mr *MockAvailabilitySetScopeMockRecorder) AdditionalTags() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockAvailabilitySetScope)(nil).AdditionalTags))
 }
 
-// Authorizer mocks base method.
-func (m *MockAvailabilitySetScope) Authorizer() autorest.Authorizer {
+// AttachedACRs mocks base method.
+func (m *MockAvailabilitySetScope) AttachedACRs() []string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authorizer")
-	ret0, _ := ret[0].(autorest.Authorizer)
+	ret := m.ctrl.Call(m, "AttachedACRs")
+	ret0, _ := ret[0].([]string)
 	return ret0
 }
 
-// Authorizer indicates an expected call of Authorizer.
-func (mr *MockAvailabilitySetScopeMockRecorder) Authorizer() *gomock.Call {
+// AttachedACRs indicates an expected call of AttachedACRs.
+func (mr *MockAvailabilitySetScopeMockRecorder) AttachedACRs() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockAvailabilitySetScope)(nil).Authorizer))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachedACRs", reflect.TypeOf((*MockAvailabilitySetScope)(nil).AttachedACRs))
 }
 
 // AvailabilitySetEnabled mocks base method.
@@ -123,6 +123,34 @@ func (mr *MockAvailabilitySetScopeMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockAvailabilitySetScope)(nil).BaseURI))
 }
 
+// BootstrapDataStorage mocks base method.
+func (m *MockAvailabilitySetScope) BootstrapDataStorage() *v1beta1.BootstrapDataStorage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorage")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataStorage)
+	return ret0
+}
+
+// BootstrapDataStorage indicates an expected call of BootstrapDataStorage.
+func (mr *MockAvailabilitySetScopeMockRecorder) BootstrapDataStorage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorage", reflect.TypeOf((*MockAvailabilitySetScope)(nil).BootstrapDataStorage))
+}
+
+// BootstrapExtension mocks base method.
+func (m *MockAvailabilitySetScope) BootstrapExtension() *v1beta1.BootstrapExtension {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapExtension")
+	ret0, _ := ret[0].(*v1beta1.BootstrapExtension)
+	return ret0
+}
+
+// BootstrapExtension indicates an expected call of BootstrapExtension.
+func (mr *MockAvailabilitySetScopeMockRecorder) BootstrapExtension() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapExtension", reflect.TypeOf((*MockAvailabilitySetScope)(nil).BootstrapExtension))
+}
+
 // ClientID mocks base method.
 func (m *MockAvailabilitySetScope) ClientID() string {
 	m.ctrl.T.Helper()
@@ -193,6 +221,20 @@ func (mr *MockAvailabilitySetScopeMockRecorder) ClusterName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockAvailabilitySetScope)(nil).ClusterName))
 }
 
+// CrossSubscriptionAccess mocks base method.
+func (m *MockAvailabilitySetScope) CrossSubscriptionAccess() v1beta1.CrossSubscriptionAccessPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CrossSubscriptionAccess")
+	ret0, _ := ret[0].(v1beta1.CrossSubscriptionAccessPolicy)
+	return ret0
+}
+
+// CrossSubscriptionAccess indicates an expected call of CrossSubscriptionAccess.
+func (mr *MockAvailabilitySetScopeMockRecorder) CrossSubscriptionAccess() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CrossSubscriptionAccess", reflect.TypeOf((*MockAvailabilitySetScope)(nil).CrossSubscriptionAccess))
+}
+
 // DeleteLongRunningOperationState mocks base method.
 func (m *MockAvailabilitySetScope) DeleteLongRunningOperationState(arg0, arg1 string) {
 	m.ctrl.T.Helper()
@@ -205,6 +247,20 @@ func (mr *MockAvailabilitySetScopeMockRecorder) DeleteLongRunningOperationState(
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockAvailabilitySetScope)(nil).DeleteLongRunningOperationState), arg0, arg1)
 }
 
+// ExtendedLocation mocks base method.
+func (m *MockAvailabilitySetScope) ExtendedLocation() *v1beta1.ExtendedLocationSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendedLocation")
+	ret0, _ := ret[0].(*v1beta1.ExtendedLocationSpec)
+	return ret0
+}
+
+// ExtendedLocation indicates an expected call of ExtendedLocation.
+func (mr *MockAvailabilitySetScopeMockRecorder) ExtendedLocation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendedLocation", reflect.TypeOf((*MockAvailabilitySetScope)(nil).ExtendedLocation))
+}
+
 // FailureDomains mocks base method.
 func (m *MockAvailabilitySetScope) FailureDomains() []string {
 	m.ctrl.T.Helper()
@@ -261,6 +317,48 @@ func (mr *MockAvailabilitySetScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockAvailabilitySetScope)(nil).Location))
 }
 
+// Monitoring mocks base method.
+func (m *MockAvailabilitySetScope) Monitoring() *v1beta1.AzureMonitoring {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Monitoring")
+	ret0, _ := ret[0].(*v1beta1.AzureMonitoring)
+	return ret0
+}
+
+// Monitoring indicates an expected call of Monitoring.
+func (mr *MockAvailabilitySetScopeMockRecorder) Monitoring() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Monitoring", reflect.TypeOf((*MockAvailabilitySetScope)(nil).Monitoring))
+}
+
+// ProxyConfig mocks base method.
+func (m *MockAvailabilitySetScope) ProxyConfig() *v1beta1.ProxyConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyConfig")
+	ret0, _ := ret[0].(*v1beta1.ProxyConfig)
+	return ret0
+}
+
+// ProxyConfig indicates an expected call of ProxyConfig.
+func (mr *MockAvailabilitySetScopeMockRecorder) ProxyConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyConfig", reflect.TypeOf((*MockAvailabilitySetScope)(nil).ProxyConfig))
+}
+
+// RegistryMirrors mocks base method.
+func (m *MockAvailabilitySetScope) RegistryMirrors() []v1beta1.RegistryMirror {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryMirrors")
+	ret0, _ := ret[0].([]v1beta1.RegistryMirror)
+	return ret0
+}
+
+// RegistryMirrors indicates an expected call of RegistryMirrors.
+func (mr *MockAvailabilitySetScopeMockRecorder) RegistryMirrors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryMirrors", reflect.TypeOf((*MockAvailabilitySetScope)(nil).RegistryMirrors))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockAvailabilitySetScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -275,6 +373,20 @@ func (mr *MockAvailabilitySetScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockAvailabilitySetScope)(nil).ResourceGroup))
 }
 
+// SecurityProfile mocks base method.
+func (m *MockAvailabilitySetScope) SecurityProfile() *v1beta1.ClusterSecurityProfile {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecurityProfile")
+	ret0, _ := ret[0].(*v1beta1.ClusterSecurityProfile)
+	return ret0
+}
+
+// SecurityProfile indicates an expected call of SecurityProfile.
+func (mr *MockAvailabilitySetScopeMockRecorder) SecurityProfile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityProfile", reflect.TypeOf((*MockAvailabilitySetScope)(nil).SecurityProfile))
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockAvailabilitySetScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -315,6 +427,20 @@ func (mr *MockAvailabilitySetScopeMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockAvailabilitySetScope)(nil).TenantID))
 }
 
+// Token mocks base method.
+func (m *MockAvailabilitySetScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockAvailabilitySetScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockAvailabilitySetScope)(nil).Token))
+}
+
 // UpdateDeleteStatus mocks base method.
 func (m *MockAvailabilitySetScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
 	m.ctrl.T.Helper()
@@ -19,8 +19,8 @@ package availabilitysets
 import (
 	"strconv"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
@@ -55,8 +55,8 @@ func (s *AvailabilitySetSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the availability set.
 func (s *AvailabilitySetSpec) Parameters(existing interface{}) (params interface{}, err error) {
 	if existing != nil {
-		if _, ok := existing.(compute.AvailabilitySet); !ok {
-			return nil, errors.Errorf("%T is not a compute.AvailabilitySet", existing)
+		if _, ok := existing.(armcompute.AvailabilitySet); !ok {
+			return nil, errors.Errorf("%T is not an armcompute.AvailabilitySet", existing)
 		}
 		// availability set already exists
 		return nil, nil
@@ -75,23 +75,23 @@ func (s *AvailabilitySetSpec) Parameters(existing interface{}) (params interface
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to parse availability set fault domain count")
 	}
-	faultDomainCount = to.Int32Ptr(int32(count))
+	faultDomainCount = to.Ptr(int32(count))
 
-	asParams := compute.AvailabilitySet{
-		Sku: &compute.Sku{
-			Name: to.StringPtr(string(compute.AvailabilitySetSkuTypesAligned)),
+	asParams := armcompute.AvailabilitySet{
+		SKU: &armcompute.SKU{
+			Name: to.Ptr(string(armcompute.AvailabilitySetSKUTypesAligned)),
 		},
-		AvailabilitySetProperties: &compute.AvailabilitySetProperties{
+		Properties: &armcompute.AvailabilitySetProperties{
 			PlatformFaultDomainCount: faultDomainCount,
 		},
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: s.ClusterName,
 			Lifecycle:   infrav1.ResourceLifecycleOwned,
-			Name:        to.StringPtr(s.Name),
-			Role:        to.StringPtr(infrav1.CommonRole),
+			Name:        to.Ptr(s.Name),
+			Role:        to.Ptr(infrav1.CommonRole),
 			Additional:  s.AdditionalTags,
 		})),
-		Location: to.StringPtr(s.Location),
+		Location: to.Ptr(s.Location),
 	}
 
 	return asParams, nil
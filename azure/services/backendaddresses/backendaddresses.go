@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendaddresses
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "backendaddresses"
+
+// BackendAddressScope defines the scope interface for a backend address service.
+type BackendAddressScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	BackendAddressSpecs() []azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope BackendAddressScope
+	client
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope BackendAddressScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:      scope,
+		client:     client,
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile adds the addresses of the machine to the backend pools of type IP.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "backendaddresses.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.BackendAddressSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	// We go through the list of BackendAddressSpecs to reconcile each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+	var result error
+	for _, spec := range specs {
+		if _, err := s.CreateResource(ctx, spec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.BackendAddressesReadyCondition, serviceName, result)
+	return result
+}
+
+// Delete removes the addresses of the machine from the backend pools of type IP.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "backendaddresses.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.BackendAddressSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	var result error
+	for _, spec := range specs {
+		addressSpec, ok := spec.(*BackendAddressSpec)
+		if !ok {
+			return errors.Errorf("%T is not a *BackendAddressSpec", spec)
+		}
+		removeSpec := *addressSpec
+		removeSpec.Remove = true
+		if _, err := s.CreateResource(ctx, &removeSpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdateDeleteStatus(infrav1.BackendAddressesReadyCondition, serviceName, result)
+	return result
+}
+
+// IsManaged always returns true as the addresses of the machine are always managed by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
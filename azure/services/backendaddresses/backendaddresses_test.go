@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendaddresses
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/backendaddresses/mock_backendaddresses"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeBackendAddressSpec = BackendAddressSpec{
+		Name:             "my-machine",
+		PoolName:         "my-lb-backendPool",
+		LoadBalancerName: "my-lb",
+		ResourceGroup:    "my-rg",
+		IPAddress:        "10.0.0.4",
+		VNetID:           "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+	}
+
+	internalError = errors.New("Internal Server Error")
+)
+
+func TestReconcileBackendAddresses(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_backendaddresses.MockBackendAddressScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the machine doesn't join a backend pool by IP address",
+			expectedError: "",
+			expect: func(s *mock_backendaddresses.MockBackendAddressScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackendAddressSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "add the address of the machine to the backend pool",
+			expectedError: "",
+			expect: func(s *mock_backendaddresses.MockBackendAddressScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackendAddressSpecs().Return([]azure.ResourceSpecGetter{&fakeBackendAddressSpec})
+				r.CreateResource(gomockinternal.AContext(), &fakeBackendAddressSpec, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.BackendAddressesReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to add the address of the machine to the backend pool",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_backendaddresses.MockBackendAddressScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackendAddressSpecs().Return([]azure.ResourceSpecGetter{&fakeBackendAddressSpec})
+				r.CreateResource(gomockinternal.AContext(), &fakeBackendAddressSpec, serviceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.BackendAddressesReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_backendaddresses.NewMockBackendAddressScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteBackendAddresses(t *testing.T) {
+	removeSpec := fakeBackendAddressSpec
+	removeSpec.Remove = true
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_backendaddresses.MockBackendAddressScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the machine doesn't join a backend pool by IP address",
+			expectedError: "",
+			expect: func(s *mock_backendaddresses.MockBackendAddressScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackendAddressSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "remove the address of the machine from the backend pool",
+			expectedError: "",
+			expect: func(s *mock_backendaddresses.MockBackendAddressScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackendAddressSpecs().Return([]azure.ResourceSpecGetter{&fakeBackendAddressSpec})
+				r.CreateResource(gomockinternal.AContext(), &removeSpec, serviceName).Return(nil, nil)
+				s.UpdateDeleteStatus(infrav1.BackendAddressesReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to remove the address of the machine from the backend pool",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_backendaddresses.MockBackendAddressScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackendAddressSpecs().Return([]azure.ResourceSpecGetter{&fakeBackendAddressSpec})
+				r.CreateResource(gomockinternal.AContext(), &removeSpec, serviceName).Return(nil, internalError)
+				s.UpdateDeleteStatus(infrav1.BackendAddressesReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_backendaddresses.NewMockBackendAddressScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendaddresses
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, azure.ResourceSpecGetter) (result interface{}, err error)
+	CreateOrUpdateAsync(context.Context, azure.ResourceSpecGetter, string, interface{}) (result interface{}, poller azure.Poller, err error)
+	DeleteAsync(context.Context, azure.ResourceSpecGetter, string) (poller azure.Poller, err error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	backendaddresspools *armnetwork.LoadBalancerBackendAddressPoolsClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new backend address pools client from subscription ID.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armnetwork.NewLoadBalancerBackendAddressPoolsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create backend address pools client")
+	}
+	return &azureClient{c}, nil
+}
+
+// Get gets the specified backend address pool.
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "backendaddresses.azureClient.Get")
+	defer done()
+
+	resp, err := ac.backendaddresspools.Get(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.BackendAddressPool, nil
+}
+
+// CreateOrUpdateAsync updates the addresses of a backend address pool asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "backendaddresses.azureClient.CreateOrUpdateAsync")
+	defer done()
+
+	pool, ok := parameters.(armnetwork.BackendAddressPool)
+	if !ok && parameters != nil {
+		return nil, nil, errors.Errorf("%T is not an armnetwork.BackendAddressPool", parameters)
+	}
+
+	// The machines of the pool update it concurrently, so only apply the update if the pool has not been modified.
+	if pool.Etag != nil {
+		ctx = policy.WithHTTPHeader(ctx, http.Header{"If-Match": {*pool.Etag}})
+	}
+
+	opts := &armnetwork.LoadBalancerBackendAddressPoolsClientBeginCreateOrUpdateOptions{ResumeToken: resumeToken}
+	createPoller, err := ac.backendaddresspools.BeginCreateOrUpdate(ctx, spec.ResourceGroupName(), spec.OwnerResourceName(), spec.ResourceName(), pool, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	resp, err := createPoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, createPoller, err
+	}
+
+	// if the operation completed, return a nil poller
+	return resp.BackendAddressPool, nil, nil
+}
+
+// DeleteAsync is a no-op for backend addresses. The backend address pool belongs to the load balancer, so the
+// addresses of a machine are removed by updating the pool instead.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
+	return nil, nil
+}
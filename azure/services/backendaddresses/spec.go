@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendaddresses
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+)
+
+// BackendAddressSpec defines the specification for the address of a machine in a load balancer backend pool of type IP.
+type BackendAddressSpec struct {
+	Name             string
+	PoolName         string
+	LoadBalancerName string
+	ResourceGroup    string
+	IPAddress        string
+	VNetID           string
+	// Remove removes the address from the backend pool rather than adding it.
+	Remove bool
+}
+
+// ResourceName returns the name of the backend address pool.
+func (s *BackendAddressSpec) ResourceName() string {
+	return s.PoolName
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *BackendAddressSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName returns the name of the load balancer of the backend address pool.
+func (s *BackendAddressSpec) OwnerResourceName() string {
+	return s.LoadBalancerName
+}
+
+// Parameters returns the backend address pool with the address of the machine added or removed, or nil if the pool is
+// already up to date.
+func (s *BackendAddressSpec) Parameters(existing interface{}) (parameters interface{}, err error) {
+	if existing == nil {
+		if s.Remove {
+			return nil, nil
+		}
+		return nil, errors.Errorf("backend address pool %s of load balancer %s does not exist", s.PoolName, s.LoadBalancerName)
+	}
+
+	pool, ok := existing.(armnetwork.BackendAddressPool)
+	if !ok {
+		return nil, errors.Errorf("%T is not an armnetwork.BackendAddressPool", existing)
+	}
+	if !s.Remove && s.IPAddress == "" {
+		return nil, errors.Errorf("private IP address of %s is not known yet", s.Name)
+	}
+
+	var existingAddresses []*armnetwork.LoadBalancerBackendAddress
+	if pool.Properties != nil {
+		existingAddresses = pool.Properties.LoadBalancerBackendAddresses
+	}
+
+	addresses := make([]*armnetwork.LoadBalancerBackendAddress, 0, len(existingAddresses)+1)
+	found := false
+	for _, address := range existingAddresses {
+		if pointer.StringDeref(address.Name, "") != s.Name {
+			addresses = append(addresses, address)
+			continue
+		}
+		found = true
+		if s.Remove {
+			continue
+		}
+		if address.Properties != nil && pointer.StringDeref(address.Properties.IPAddress, "") == s.IPAddress &&
+			address.Properties.VirtualNetwork != nil && pointer.StringDeref(address.Properties.VirtualNetwork.ID, "") == s.VNetID {
+			// the address of the machine is up to date.
+			return nil, nil
+		}
+		addresses = append(addresses, s.address())
+	}
+
+	if s.Remove && !found {
+		return nil, nil
+	}
+	if !s.Remove && !found {
+		addresses = append(addresses, s.address())
+	}
+
+	// copy the properties so that the existing pool is not modified.
+	var properties armnetwork.BackendAddressPoolPropertiesFormat
+	if pool.Properties != nil {
+		properties = *pool.Properties
+	}
+	properties.LoadBalancerBackendAddresses = addresses
+	pool.Properties = &properties
+
+	return pool, nil
+}
+
+// address returns the address of the machine in the backend pool.
+func (s *BackendAddressSpec) address() *armnetwork.LoadBalancerBackendAddress {
+	return &armnetwork.LoadBalancerBackendAddress{
+		Name: to.Ptr(s.Name),
+		Properties: &armnetwork.LoadBalancerBackendAddressPropertiesFormat{
+			IPAddress: to.Ptr(s.IPAddress),
+			VirtualNetwork: &armnetwork.SubResource{
+				ID: to.Ptr(s.VNetID),
+			},
+		},
+	}
+}
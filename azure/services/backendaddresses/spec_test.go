@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendaddresses
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/gomega"
+)
+
+// fakePool returns the backend address pool with the given addresses.
+func fakePool(addresses ...*armnetwork.LoadBalancerBackendAddress) armnetwork.BackendAddressPool {
+	return armnetwork.BackendAddressPool{
+		Name: to.Ptr("my-lb-backendPool"),
+		Etag: to.Ptr("W/\"1\""),
+		Properties: &armnetwork.BackendAddressPoolPropertiesFormat{
+			LoadBalancerBackendAddresses: addresses,
+		},
+	}
+}
+
+// fakeAddress returns the backend address with the given name and IP address.
+func fakeAddress(name, ip string) *armnetwork.LoadBalancerBackendAddress {
+	return &armnetwork.LoadBalancerBackendAddress{
+		Name: to.Ptr(name),
+		Properties: &armnetwork.LoadBalancerBackendAddressPropertiesFormat{
+			IPAddress: to.Ptr(ip),
+			VirtualNetwork: &armnetwork.SubResource{
+				ID: to.Ptr(fakeBackendAddressSpec.VNetID),
+			},
+		},
+	}
+}
+
+func TestBackendAddressSpecParameters(t *testing.T) {
+	removeSpec := fakeBackendAddressSpec
+	removeSpec.Remove = true
+	unknownIPSpec := fakeBackendAddressSpec
+	unknownIPSpec.IPAddress = ""
+
+	testcases := []struct {
+		name          string
+		spec          BackendAddressSpec
+		existing      interface{}
+		expected      interface{}
+		expectedError string
+	}{
+		{
+			name:     "add the address to the pool",
+			spec:     fakeBackendAddressSpec,
+			existing: fakePool(fakeAddress("other-machine", "10.0.0.5")),
+			expected: fakePool(fakeAddress("other-machine", "10.0.0.5"), fakeAddress("my-machine", "10.0.0.4")),
+		},
+		{
+			name:     "update the address in the pool",
+			spec:     fakeBackendAddressSpec,
+			existing: fakePool(fakeAddress("my-machine", "10.0.0.6"), fakeAddress("other-machine", "10.0.0.5")),
+			expected: fakePool(fakeAddress("my-machine", "10.0.0.4"), fakeAddress("other-machine", "10.0.0.5")),
+		},
+		{
+			name:     "noop if the address is up to date",
+			spec:     fakeBackendAddressSpec,
+			existing: fakePool(fakeAddress("my-machine", "10.0.0.4")),
+			expected: nil,
+		},
+		{
+			name:          "fail if the pool does not exist",
+			spec:          fakeBackendAddressSpec,
+			existing:      nil,
+			expectedError: "backend address pool my-lb-backendPool of load balancer my-lb does not exist",
+		},
+		{
+			name:          "fail if the IP address of the machine is not known",
+			spec:          unknownIPSpec,
+			existing:      fakePool(),
+			expectedError: "private IP address of my-machine is not known yet",
+		},
+		{
+			name:     "remove the address from the pool",
+			spec:     removeSpec,
+			existing: fakePool(fakeAddress("my-machine", "10.0.0.4"), fakeAddress("other-machine", "10.0.0.5")),
+			expected: fakePool(fakeAddress("other-machine", "10.0.0.5")),
+		},
+		{
+			name:     "noop if the address is not in the pool",
+			spec:     removeSpec,
+			existing: fakePool(fakeAddress("other-machine", "10.0.0.5")),
+			expected: nil,
+		},
+		{
+			name:     "noop if the pool does not exist anymore",
+			spec:     removeSpec,
+			existing: nil,
+			expected: nil,
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := tc.spec.Parameters(tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expected == nil {
+				g.Expect(result).To(BeNil())
+			} else {
+				g.Expect(result).To(Equal(tc.expected))
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendpooldrain
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "backendpooldrain"
+
+// BackendPoolDrainScope defines the scope interface for a backend pool drain service.
+type BackendPoolDrainScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	BackendPoolDrainSpecs() []azure.ResourceSpecGetter
+	BackendPoolDrainTimeout() time.Duration
+	BackendPoolDrainStartTime() time.Time
+	BackendPoolsDrained() bool
+	SetBackendPoolsDrained()
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope BackendPoolDrainScope
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope BackendPoolDrainScope) (*Service, error) {
+	client, err := networkinterfaces.NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:      scope,
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile is a no-op, the network interfaces join the backend pools when they are created.
+func (s *Service) Reconcile(ctx context.Context) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "backendpooldrain.Service.Reconcile")
+	defer done()
+
+	return nil
+}
+
+// Delete removes the network interfaces of the machine from the load balancer backend pools, and waits for the drain
+// timeout to expire before the virtual machine is deleted.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "backendpooldrain.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	timeout := s.Scope.BackendPoolDrainTimeout()
+	if timeout <= 0 || s.Scope.BackendPoolsDrained() {
+		return nil
+	}
+
+	var result error
+	for _, spec := range s.Scope.BackendPoolDrainSpecs() {
+		if _, err := s.CreateResource(ctx, spec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+	if result != nil {
+		// The drain only starts once the network interfaces are out of the backend pools.
+		return result
+	}
+
+	if remaining := timeout - time.Since(s.Scope.BackendPoolDrainStartTime()); remaining > 0 {
+		// Requeue on the next full second after the drain timeout expires.
+		remaining = remaining.Truncate(time.Second) + time.Second
+		return azure.WithTransientError(errors.Errorf("waiting %s for the connections to drain from the load balancer backend pools", remaining), remaining)
+	}
+
+	s.Scope.SetBackendPoolsDrained()
+	return nil
+}
+
+// IsManaged always returns true as the network interfaces of the machine are always managed by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
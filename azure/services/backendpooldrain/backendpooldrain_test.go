@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendpooldrain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/backendpooldrain/mock_backendpooldrain"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeDrainSpec1 = BackendPoolDrainSpec{
+		Name:          "my-machine-nic",
+		ResourceGroup: "my-rg",
+	}
+	fakeDrainSpec2 = BackendPoolDrainSpec{
+		Name:          "my-machine-nic-1",
+		ResourceGroup: "my-rg",
+	}
+
+	internalError = errors.New("Internal Server Error")
+)
+
+func TestDeleteBackendPoolDrain(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_backendpooldrain.MockBackendPoolDrainScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no drain timeout is set",
+			expectedError: "",
+			expect: func(s *mock_backendpooldrain.MockBackendPoolDrainScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackendPoolDrainTimeout().Return(time.Duration(0))
+			},
+		},
+		{
+			name:          "noop if the backend pools were already drained",
+			expectedError: "",
+			expect: func(s *mock_backendpooldrain.MockBackendPoolDrainScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackendPoolDrainTimeout().Return(time.Minute)
+				s.BackendPoolsDrained().Return(true)
+			},
+		},
+		{
+			name:          "remove the network interfaces from the backend pools and wait for the drain timeout",
+			expectedError: "waiting 1m0s for the connections to drain from the load balancer backend pools. Object will be requeued after 1m0s",
+			expect: func(s *mock_backendpooldrain.MockBackendPoolDrainScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackendPoolDrainTimeout().Return(time.Minute)
+				s.BackendPoolsDrained().Return(false)
+				s.BackendPoolDrainSpecs().Return([]azure.ResourceSpecGetter{&fakeDrainSpec1, &fakeDrainSpec2})
+				r.CreateResource(gomockinternal.AContext(), &fakeDrainSpec1, serviceName).Return(nil, nil)
+				r.CreateResource(gomockinternal.AContext(), &fakeDrainSpec2, serviceName).Return(nil, nil)
+				s.BackendPoolDrainStartTime().Return(time.Now())
+			},
+		},
+		{
+			name:          "mark the backend pools drained once the drain timeout expired",
+			expectedError: "",
+			expect: func(s *mock_backendpooldrain.MockBackendPoolDrainScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackendPoolDrainTimeout().Return(time.Minute)
+				s.BackendPoolsDrained().Return(false)
+				s.BackendPoolDrainSpecs().Return([]azure.ResourceSpecGetter{&fakeDrainSpec1})
+				r.CreateResource(gomockinternal.AContext(), &fakeDrainSpec1, serviceName).Return(nil, nil)
+				s.BackendPoolDrainStartTime().Return(time.Now().Add(-2 * time.Minute))
+				s.SetBackendPoolsDrained()
+			},
+		},
+		{
+			name:          "don't start the drain if a network interface fails to be removed from the backend pools",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_backendpooldrain.MockBackendPoolDrainScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackendPoolDrainTimeout().Return(time.Minute)
+				s.BackendPoolsDrained().Return(false)
+				s.BackendPoolDrainSpecs().Return([]azure.ResourceSpecGetter{&fakeDrainSpec1, &fakeDrainSpec2})
+				r.CreateResource(gomockinternal.AContext(), &fakeDrainSpec1, serviceName).Return(nil, internalError)
+				r.CreateResource(gomockinternal.AContext(), &fakeDrainSpec2, serviceName).Return(nil, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_backendpooldrain.NewMockBackendPoolDrainScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../backendpooldrain.go
+
+// Package mock_backendpooldrain is a generated GoMock package.
+package mock_backendpooldrain
+
+import (
+	reflect "reflect"
+	time "time"
+
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	gomock "github.com/golang/mock/gomock"
+	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+	v1beta10 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// MockBackendPoolDrainScope is a mock of BackendPoolDrainScope interface.
+type MockBackendPoolDrainScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackendPoolDrainScopeMockRecorder
+}
+
+// MockBackendPoolDrainScopeMockRecorder is the mock recorder for MockBackendPoolDrainScope.
+type MockBackendPoolDrainScopeMockRecorder struct {
+	mock *MockBackendPoolDrainScope
+}
+
+// NewMockBackendPoolDrainScope creates a new mock instance.
+func NewMockBackendPoolDrainScope(ctrl *gomock.Controller) *MockBackendPoolDrainScope {
+	mock := &MockBackendPoolDrainScope{ctrl: ctrl}
+	mock.recorder = &MockBackendPoolDrainScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackendPoolDrainScope) EXPECT() *MockBackendPoolDrainScopeMockRecorder {
+	return m.recorder
+}
+
+// BackendPoolDrainSpecs mocks base method.
+func (m *MockBackendPoolDrainScope) BackendPoolDrainSpecs() []azure.ResourceSpecGetter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackendPoolDrainSpecs")
+	ret0, _ := ret[0].([]azure.ResourceSpecGetter)
+	return ret0
+}
+
+// BackendPoolDrainSpecs indicates an expected call of BackendPoolDrainSpecs.
+func (mr *MockBackendPoolDrainScopeMockRecorder) BackendPoolDrainSpecs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackendPoolDrainSpecs", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).BackendPoolDrainSpecs))
+}
+
+// BackendPoolDrainStartTime mocks base method.
+func (m *MockBackendPoolDrainScope) BackendPoolDrainStartTime() time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackendPoolDrainStartTime")
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+// BackendPoolDrainStartTime indicates an expected call of BackendPoolDrainStartTime.
+func (mr *MockBackendPoolDrainScopeMockRecorder) BackendPoolDrainStartTime() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackendPoolDrainStartTime", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).BackendPoolDrainStartTime))
+}
+
+// BackendPoolDrainTimeout mocks base method.
+func (m *MockBackendPoolDrainScope) BackendPoolDrainTimeout() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackendPoolDrainTimeout")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// BackendPoolDrainTimeout indicates an expected call of BackendPoolDrainTimeout.
+func (mr *MockBackendPoolDrainScopeMockRecorder) BackendPoolDrainTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackendPoolDrainTimeout", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).BackendPoolDrainTimeout))
+}
+
+// BackendPoolsDrained mocks base method.
+func (m *MockBackendPoolDrainScope) BackendPoolsDrained() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackendPoolsDrained")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// BackendPoolsDrained indicates an expected call of BackendPoolsDrained.
+func (mr *MockBackendPoolDrainScopeMockRecorder) BackendPoolsDrained() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackendPoolsDrained", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).BackendPoolsDrained))
+}
+
+// BaseURI mocks base method.
+func (m *MockBackendPoolDrainScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockBackendPoolDrainScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).BaseURI))
+}
+
+// ClientID mocks base method.
+func (m *MockBackendPoolDrainScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockBackendPoolDrainScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockBackendPoolDrainScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockBackendPoolDrainScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockBackendPoolDrainScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockBackendPoolDrainScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).CloudEnvironment))
+}
+
+// DeleteLongRunningOperationState mocks base method.
+func (m *MockBackendPoolDrainScope) DeleteLongRunningOperationState(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteLongRunningOperationState", arg0, arg1)
+}
+
+// DeleteLongRunningOperationState indicates an expected call of DeleteLongRunningOperationState.
+func (mr *MockBackendPoolDrainScopeMockRecorder) DeleteLongRunningOperationState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).DeleteLongRunningOperationState), arg0, arg1)
+}
+
+// GetLongRunningOperationState mocks base method.
+func (m *MockBackendPoolDrainScope) GetLongRunningOperationState(arg0, arg1 string) *v1beta1.Future {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLongRunningOperationState", arg0, arg1)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	return ret0
+}
+
+// GetLongRunningOperationState indicates an expected call of GetLongRunningOperationState.
+func (mr *MockBackendPoolDrainScopeMockRecorder) GetLongRunningOperationState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongRunningOperationState", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).GetLongRunningOperationState), arg0, arg1)
+}
+
+// HashKey mocks base method.
+func (m *MockBackendPoolDrainScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockBackendPoolDrainScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).HashKey))
+}
+
+// SetBackendPoolsDrained mocks base method.
+func (m *MockBackendPoolDrainScope) SetBackendPoolsDrained() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBackendPoolsDrained")
+}
+
+// SetBackendPoolsDrained indicates an expected call of SetBackendPoolsDrained.
+func (mr *MockBackendPoolDrainScopeMockRecorder) SetBackendPoolsDrained() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBackendPoolsDrained", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).SetBackendPoolsDrained))
+}
+
+// SetLongRunningOperationState mocks base method.
+func (m *MockBackendPoolDrainScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLongRunningOperationState", arg0)
+}
+
+// SetLongRunningOperationState indicates an expected call of SetLongRunningOperationState.
+func (mr *MockBackendPoolDrainScopeMockRecorder) SetLongRunningOperationState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).SetLongRunningOperationState), arg0)
+}
+
+// SubscriptionID mocks base method.
+func (m *MockBackendPoolDrainScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockBackendPoolDrainScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockBackendPoolDrainScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockBackendPoolDrainScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).TenantID))
+}
+
+// Token mocks base method.
+func (m *MockBackendPoolDrainScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockBackendPoolDrainScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).Token))
+}
+
+// UpdateDeleteStatus mocks base method.
+func (m *MockBackendPoolDrainScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateDeleteStatus", arg0, arg1, arg2)
+}
+
+// UpdateDeleteStatus indicates an expected call of UpdateDeleteStatus.
+func (mr *MockBackendPoolDrainScopeMockRecorder) UpdateDeleteStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDeleteStatus", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).UpdateDeleteStatus), arg0, arg1, arg2)
+}
+
+// UpdatePatchStatus mocks base method.
+func (m *MockBackendPoolDrainScope) UpdatePatchStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdatePatchStatus", arg0, arg1, arg2)
+}
+
+// UpdatePatchStatus indicates an expected call of UpdatePatchStatus.
+func (mr *MockBackendPoolDrainScopeMockRecorder) UpdatePatchStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePatchStatus", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).UpdatePatchStatus), arg0, arg1, arg2)
+}
+
+// UpdatePutStatus mocks base method.
+func (m *MockBackendPoolDrainScope) UpdatePutStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdatePutStatus", arg0, arg1, arg2)
+}
+
+// UpdatePutStatus indicates an expected call of UpdatePutStatus.
+func (mr *MockBackendPoolDrainScopeMockRecorder) UpdatePutStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePutStatus", reflect.TypeOf((*MockBackendPoolDrainScope)(nil).UpdatePutStatus), arg0, arg1, arg2)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendpooldrain
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
+)
+
+// BackendPoolDrainSpec defines the specification for removing a network interface from the load balancer backend pools.
+type BackendPoolDrainSpec struct {
+	Name          string
+	ResourceGroup string
+}
+
+// ResourceName returns the name of the network interface.
+func (s *BackendPoolDrainSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *BackendPoolDrainSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for network interfaces.
+func (s *BackendPoolDrainSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the network interface with its IP configurations removed from the load balancer backend pools,
+// or nil if the network interface doesn't exist or isn't a member of any backend pool.
+func (s *BackendPoolDrainSpec) Parameters(existing interface{}) (parameters interface{}, err error) {
+	if existing == nil {
+		return nil, nil
+	}
+
+	nic, ok := existing.(armnetwork.Interface)
+	if !ok {
+		return nil, errors.Errorf("%T is not an armnetwork.Interface", existing)
+	}
+	if nic.Properties == nil {
+		return nil, nil
+	}
+
+	removed := false
+	for _, ipConfig := range nic.Properties.IPConfigurations {
+		if ipConfig == nil || ipConfig.Properties == nil || len(ipConfig.Properties.LoadBalancerBackendAddressPools) == 0 {
+			continue
+		}
+		ipConfig.Properties.LoadBalancerBackendAddressPools = []*armnetwork.BackendAddressPool{}
+		removed = true
+	}
+	if !removed {
+		return nil, nil
+	}
+
+	return nic, nil
+}
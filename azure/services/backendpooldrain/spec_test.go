@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backendpooldrain
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	. "github.com/onsi/gomega"
+)
+
+// fakeNIC returns the network interface with one IP configuration per given list of backend pool IDs.
+func fakeNIC(poolIDs ...[]string) armnetwork.Interface {
+	ipConfigs := make([]*armnetwork.InterfaceIPConfiguration, 0, len(poolIDs))
+	for _, ids := range poolIDs {
+		pools := make([]*armnetwork.BackendAddressPool, 0, len(ids))
+		for _, id := range ids {
+			pools = append(pools, &armnetwork.BackendAddressPool{ID: to.Ptr(id)})
+		}
+		ipConfigs = append(ipConfigs, &armnetwork.InterfaceIPConfiguration{
+			Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+				PrivateIPAddress:                to.Ptr("10.0.0.4"),
+				LoadBalancerBackendAddressPools: pools,
+			},
+		})
+	}
+	return armnetwork.Interface{
+		Name: to.Ptr("my-machine-nic"),
+		Properties: &armnetwork.InterfacePropertiesFormat{
+			IPConfigurations: ipConfigs,
+		},
+	}
+}
+
+func TestBackendPoolDrainSpecParameters(t *testing.T) {
+	publicPool := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-lb/backendAddressPools/my-lb-backendPool"
+	internalPool := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-lb-internal/backendAddressPools/my-lb-internal-backendPool"
+
+	testcases := []struct {
+		name          string
+		existing      interface{}
+		expected      interface{}
+		expectedError string
+	}{
+		{
+			name:     "remove the IP configurations from the backend pools",
+			existing: fakeNIC([]string{publicPool, internalPool}, []string{internalPool}),
+			expected: fakeNIC([]string{}, []string{}),
+		},
+		{
+			name:     "remove only the IP configurations that are in a backend pool",
+			existing: fakeNIC([]string{publicPool}, nil),
+			expected: fakeNIC([]string{}, nil),
+		},
+		{
+			name:     "noop if the network interface is not in a backend pool",
+			existing: fakeNIC(nil, nil),
+			expected: nil,
+		},
+		{
+			name:     "noop if the network interface does not exist anymore",
+			existing: nil,
+			expected: nil,
+		},
+		{
+			name:          "fail if the existing resource is not a network interface",
+			existing:      armnetwork.LoadBalancer{},
+			expectedError: "armnetwork.LoadBalancer is not an armnetwork.Interface",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			spec := fakeDrainSpec1
+			result, err := spec.Parameters(tc.existing)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expected == nil {
+				g.Expect(result).To(BeNil())
+			} else {
+				g.Expect(result).To(Equal(tc.expected))
+			}
+		})
+	}
+}
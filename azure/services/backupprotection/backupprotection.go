@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupprotection
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "backupprotection"
+
+// BackupProtectionScope defines the scope interface for a backup protection service.
+type BackupProtectionScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	BackupProtectionSpecs() []azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope BackupProtectionScope
+	client
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope BackupProtectionScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:      scope,
+		client:     client,
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile enrolls the virtual machine in its backup policy.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "backupprotection.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.BackupProtectionSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	// We go through the list of BackupProtectionSpecs to reconcile each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+	var result error
+	for _, protectionSpec := range specs {
+		if _, err := s.CreateResource(ctx, protectionSpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.BackupProtectionReadyCondition, serviceName, result)
+	return result
+}
+
+// Delete stops the backup of the virtual machine before it is deleted. Its recovery points are retained in the vault
+// according to the backup policy, so that it can still be restored.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "backupprotection.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.BackupProtectionSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	var result error
+	for _, spec := range specs {
+		protectionSpec, ok := spec.(*BackupProtectionSpec)
+		if !ok {
+			return errors.Errorf("%T is not a *BackupProtectionSpec", spec)
+		}
+		stopSpec := *protectionSpec
+		stopSpec.StopProtection = true
+		if _, err := s.CreateResource(ctx, &stopSpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdateDeleteStatus(infrav1.BackupProtectionReadyCondition, serviceName, result)
+	return result
+}
+
+// IsManaged always returns true as the protection of the virtual machine is always configured by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
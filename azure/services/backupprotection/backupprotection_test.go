@@ -0,0 +1,274 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupprotection
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/recoveryservices/armrecoveryservicesbackup/v4"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/backupprotection/mock_backupprotection"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeBackupProtectionSpec = BackupProtectionSpec{
+		VMName:             "my-vm",
+		VMResourceGroup:    "my-rg",
+		VMID:               "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm",
+		VaultName:          "my-vault",
+		VaultResourceGroup: "backup-rg",
+		PolicyID:           "/subscriptions/123/resourceGroups/backup-rg/providers/Microsoft.RecoveryServices/vaults/my-vault/backupPolicies/DefaultPolicy",
+	}
+
+	internalError = errors.New("Internal Server Error")
+)
+
+func TestReconcileBackupProtection(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_backupprotection.MockBackupProtectionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the virtual machine isn't backed up",
+			expectedError: "",
+			expect: func(s *mock_backupprotection.MockBackupProtectionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackupProtectionSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "enroll the virtual machine in its backup policy",
+			expectedError: "",
+			expect: func(s *mock_backupprotection.MockBackupProtectionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackupProtectionSpecs().Return([]azure.ResourceSpecGetter{&fakeBackupProtectionSpec})
+				r.CreateResource(gomockinternal.AContext(), &fakeBackupProtectionSpec, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.BackupProtectionReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to enroll the virtual machine in its backup policy",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_backupprotection.MockBackupProtectionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackupProtectionSpecs().Return([]azure.ResourceSpecGetter{&fakeBackupProtectionSpec})
+				r.CreateResource(gomockinternal.AContext(), &fakeBackupProtectionSpec, serviceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.BackupProtectionReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_backupprotection.NewMockBackupProtectionScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteBackupProtection(t *testing.T) {
+	stopSpec := fakeBackupProtectionSpec
+	stopSpec.StopProtection = true
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_backupprotection.MockBackupProtectionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the virtual machine isn't backed up",
+			expectedError: "",
+			expect: func(s *mock_backupprotection.MockBackupProtectionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackupProtectionSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "stop the protection of the virtual machine",
+			expectedError: "",
+			expect: func(s *mock_backupprotection.MockBackupProtectionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackupProtectionSpecs().Return([]azure.ResourceSpecGetter{&fakeBackupProtectionSpec})
+				r.CreateResource(gomockinternal.AContext(), &stopSpec, serviceName).Return(nil, nil)
+				s.UpdateDeleteStatus(infrav1.BackupProtectionReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to stop the protection of the virtual machine",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_backupprotection.MockBackupProtectionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.BackupProtectionSpecs().Return([]azure.ResourceSpecGetter{&fakeBackupProtectionSpec})
+				r.CreateResource(gomockinternal.AContext(), &stopSpec, serviceName).Return(nil, internalError)
+				s.UpdateDeleteStatus(infrav1.BackupProtectionReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_backupprotection.NewMockBackupProtectionScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+// fakeProtectedItem returns the protected item of the virtual machine in the given state.
+func fakeProtectedItem(policyID string, state armrecoveryservicesbackup.ProtectionState) armrecoveryservicesbackup.ProtectedItemResource {
+	return armrecoveryservicesbackup.ProtectedItemResource{
+		Properties: &armrecoveryservicesbackup.AzureIaaSComputeVMProtectedItem{
+			ProtectedItemType: to.Ptr(protectedItemType),
+			SourceResourceID:  to.Ptr(fakeBackupProtectionSpec.VMID),
+			PolicyID:          to.Ptr(policyID),
+			ProtectionState:   to.Ptr(state),
+		},
+	}
+}
+
+func TestBackupProtectionSpecParameters(t *testing.T) {
+	stopSpec := fakeBackupProtectionSpec
+	stopSpec.StopProtection = true
+	otherPolicyID := "/subscriptions/123/resourceGroups/backup-rg/providers/Microsoft.RecoveryServices/vaults/my-vault/backupPolicies/etcd"
+
+	testcases := []struct {
+		name     string
+		spec     BackupProtectionSpec
+		existing interface{}
+		expected interface{}
+	}{
+		{
+			name: "enable the protection of a new virtual machine",
+			spec: fakeBackupProtectionSpec,
+			expected: armrecoveryservicesbackup.ProtectedItemResource{
+				Properties: &armrecoveryservicesbackup.AzureIaaSComputeVMProtectedItem{
+					ProtectedItemType: to.Ptr(protectedItemType),
+					SourceResourceID:  to.Ptr(fakeBackupProtectionSpec.VMID),
+					PolicyID:          to.Ptr(fakeBackupProtectionSpec.PolicyID),
+				},
+			},
+		},
+		{
+			name:     "noop if the virtual machine is protected by the policy",
+			spec:     fakeBackupProtectionSpec,
+			existing: fakeProtectedItem(fakeBackupProtectionSpec.PolicyID, armrecoveryservicesbackup.ProtectionStateProtected),
+		},
+		{
+			name:     "move the virtual machine to another policy",
+			spec:     fakeBackupProtectionSpec,
+			existing: fakeProtectedItem(otherPolicyID, armrecoveryservicesbackup.ProtectionStateProtected),
+			expected: armrecoveryservicesbackup.ProtectedItemResource{
+				Properties: &armrecoveryservicesbackup.AzureIaaSComputeVMProtectedItem{
+					ProtectedItemType: to.Ptr(protectedItemType),
+					SourceResourceID:  to.Ptr(fakeBackupProtectionSpec.VMID),
+					PolicyID:          to.Ptr(fakeBackupProtectionSpec.PolicyID),
+				},
+			},
+		},
+		{
+			name:     "resume the protection of the virtual machine",
+			spec:     fakeBackupProtectionSpec,
+			existing: fakeProtectedItem(fakeBackupProtectionSpec.PolicyID, armrecoveryservicesbackup.ProtectionStateProtectionStopped),
+			expected: armrecoveryservicesbackup.ProtectedItemResource{
+				Properties: &armrecoveryservicesbackup.AzureIaaSComputeVMProtectedItem{
+					ProtectedItemType: to.Ptr(protectedItemType),
+					SourceResourceID:  to.Ptr(fakeBackupProtectionSpec.VMID),
+					PolicyID:          to.Ptr(fakeBackupProtectionSpec.PolicyID),
+				},
+			},
+		},
+		{
+			name:     "stop the protection of the virtual machine",
+			spec:     stopSpec,
+			existing: fakeProtectedItem(fakeBackupProtectionSpec.PolicyID, armrecoveryservicesbackup.ProtectionStateProtected),
+			expected: armrecoveryservicesbackup.ProtectedItemResource{
+				Properties: &armrecoveryservicesbackup.AzureIaaSComputeVMProtectedItem{
+					ProtectedItemType: to.Ptr(protectedItemType),
+					SourceResourceID:  to.Ptr(fakeBackupProtectionSpec.VMID),
+					ProtectionState:   to.Ptr(armrecoveryservicesbackup.ProtectionStateProtectionStopped),
+				},
+			},
+		},
+		{
+			name:     "noop if the protection of the virtual machine is stopped",
+			spec:     stopSpec,
+			existing: fakeProtectedItem(fakeBackupProtectionSpec.PolicyID, armrecoveryservicesbackup.ProtectionStateProtectionStopped),
+		},
+		{
+			name: "noop if the virtual machine was never protected",
+			spec: stopSpec,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			params, err := tc.spec.Parameters(tc.existing)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expected == nil {
+				g.Expect(params).To(BeNil())
+			} else {
+				g.Expect(params).To(Equal(tc.expected))
+			}
+		})
+	}
+}
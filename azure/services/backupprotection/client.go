@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupprotection
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/recoveryservices/armrecoveryservicesbackup/v4"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// fabricName is the backup fabric of Azure resources.
+	fabricName = "Azure"
+	// iaasVMFilter restricts the discovery of protectable items to Azure virtual machines.
+	iaasVMFilter = "backupManagementType eq 'AzureIaasVM'"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, azure.ResourceSpecGetter) (result interface{}, err error)
+	CreateOrUpdateAsync(context.Context, azure.ResourceSpecGetter, string, interface{}) (result interface{}, poller azure.Poller, err error)
+	DeleteAsync(context.Context, azure.ResourceSpecGetter, string) (poller azure.Poller, err error)
+}
+
+// protectedItemSpec is a ResourceSpecGetter of a protected item, which lives in a protection container of a vault.
+type protectedItemSpec interface {
+	azure.ResourceSpecGetter
+	ContainerName() string
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	protectedItems       *armrecoveryservicesbackup.ProtectedItemsClient
+	protectionContainers *armrecoveryservicesbackup.ProtectionContainersClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new backup protection client from subscription ID.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	protectedItems, err := armrecoveryservicesbackup.NewProtectedItemsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create protected items client")
+	}
+	protectionContainers, err := armrecoveryservicesbackup.NewProtectionContainersClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create protection containers client")
+	}
+	return &azureClient{
+		protectedItems:       protectedItems,
+		protectionContainers: protectionContainers,
+	}, nil
+}
+
+// Get gets the specified protected item.
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "backupprotection.azureClient.Get")
+	defer done()
+
+	itemSpec, ok := spec.(protectedItemSpec)
+	if !ok {
+		return nil, errors.Errorf("%T is not a protected item spec", spec)
+	}
+
+	resp, err := ac.protectedItems.Get(ctx, spec.OwnerResourceName(), spec.ResourceGroupName(), fabricName, itemSpec.ContainerName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.ProtectedItemResource, nil
+}
+
+// CreateOrUpdateAsync enables, updates or stops the protection of a virtual machine.
+// The vault processes the request in the background, so we don't ever return a poller.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "backupprotection.azureClient.CreateOrUpdateAsync")
+	defer done()
+
+	itemSpec, ok := spec.(protectedItemSpec)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not a protected item spec", spec)
+	}
+	protectedItem, ok := parameters.(armrecoveryservicesbackup.ProtectedItemResource)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not an armrecoveryservicesbackup.ProtectedItemResource", parameters)
+	}
+
+	// The vault only protects the virtual machines it has discovered, so make sure it knows about new ones first.
+	// Discovery runs in the background: if it isn't done yet, protecting the virtual machine fails and is retried.
+	refreshOpts := &armrecoveryservicesbackup.ProtectionContainersClientRefreshOptions{Filter: to.Ptr(iaasVMFilter)}
+	if _, err := ac.protectionContainers.Refresh(ctx, spec.OwnerResourceName(), spec.ResourceGroupName(), fabricName, refreshOpts); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to discover virtual machines")
+	}
+
+	resp, err := ac.protectedItems.CreateOrUpdate(ctx, spec.OwnerResourceName(), spec.ResourceGroupName(), fabricName, itemSpec.ContainerName(), spec.ResourceName(), protectedItem, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.ProtectedItemResource, nil, nil
+}
+
+// DeleteAsync is a no-op for protected items. The protection of a virtual machine is stopped rather than deleted, so
+// that its recovery points are retained.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
+	return nil, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupprotection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/recoveryservices/armrecoveryservicesbackup/v4"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+)
+
+// protectedItemType is the type of the protected item of an Azure virtual machine.
+const protectedItemType = "Microsoft.Compute/virtualMachines"
+
+// BackupProtectionSpec defines the specification for the backup protection of a virtual machine.
+type BackupProtectionSpec struct {
+	VMName             string
+	VMResourceGroup    string
+	VMID               string
+	VaultName          string
+	VaultResourceGroup string
+	PolicyID           string
+	// StopProtection stops the backup of the virtual machine while retaining its recovery points.
+	StopProtection bool
+}
+
+// ResourceName returns the name of the protected item of the virtual machine.
+func (s *BackupProtectionSpec) ResourceName() string {
+	return fmt.Sprintf("vm;iaasvmcontainerv2;%s;%s", s.VMResourceGroup, s.VMName)
+}
+
+// ContainerName returns the name of the protection container of the virtual machine.
+func (s *BackupProtectionSpec) ContainerName() string {
+	return fmt.Sprintf("iaasvmcontainer;iaasvmcontainerv2;%s;%s", s.VMResourceGroup, s.VMName)
+}
+
+// ResourceGroupName returns the name of the resource group of the vault.
+func (s *BackupProtectionSpec) ResourceGroupName() string {
+	return s.VaultResourceGroup
+}
+
+// OwnerResourceName returns the name of the vault.
+func (s *BackupProtectionSpec) OwnerResourceName() string {
+	return s.VaultName
+}
+
+// Parameters returns the parameters for the protected item of the virtual machine.
+func (s *BackupProtectionSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		existingItem, ok := existing.(armrecoveryservicesbackup.ProtectedItemResource)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armrecoveryservicesbackup.ProtectedItemResource", existing)
+		}
+		if vmItem, ok := existingItem.Properties.(*armrecoveryservicesbackup.AzureIaaSComputeVMProtectedItem); ok {
+			stopped := pointer.StringDeref((*string)(vmItem.ProtectionState), "") == string(armrecoveryservicesbackup.ProtectionStateProtectionStopped)
+			if s.StopProtection && stopped {
+				// protection is already stopped
+				return nil, nil
+			}
+			if !s.StopProtection && !stopped && strings.EqualFold(pointer.StringDeref(vmItem.PolicyID, ""), s.PolicyID) {
+				// virtual machine is already protected by the policy
+				return nil, nil
+			}
+		}
+	} else if s.StopProtection {
+		// virtual machine was never protected
+		return nil, nil
+	}
+
+	item := &armrecoveryservicesbackup.AzureIaaSComputeVMProtectedItem{
+		ProtectedItemType: to.Ptr(protectedItemType),
+		SourceResourceID:  to.Ptr(s.VMID),
+	}
+	if s.StopProtection {
+		item.ProtectionState = to.Ptr(armrecoveryservicesbackup.ProtectionStateProtectionStopped)
+	} else {
+		item.PolicyID = to.Ptr(s.PolicyID)
+	}
+
+	return armrecoveryservicesbackup.ProtectedItemResource{
+		Properties: item,
+	}, nil
+}
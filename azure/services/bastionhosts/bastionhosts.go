@@ -21,6 +21,7 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -42,12 +43,15 @@ type Service struct {
 }
 
 // New creates a new service.
-func New(scope BastionScope) *Service {
-	client := newClient(scope)
+func New(scope BastionScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:      scope,
-		Reconciler: async.New(scope, client, client),
-	}
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
 }
 
 // Name returns the service name.
@@ -55,6 +59,15 @@ func (s *Service) Name() string {
 	return serviceName
 }
 
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the bastion host.
+func (s *Service) RequiredActions() []string {
+	if s.Scope.AzureBastionSpec() == nil {
+		return nil
+	}
+	return []string{"Microsoft.Network/bastionHosts/read", "Microsoft.Network/bastionHosts/write"}
+}
+
 // Reconcile gets/creates/updates a bastion host.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "bastionhosts.Service.Reconcile")
@@ -93,6 +106,18 @@ func (s *Service) Delete(ctx context.Context) error {
 	return resultingErr
 }
 
+// ExportResources adds the bastion host to an ARM template.
+func (s *Service) ExportResources(ctx context.Context, template *armtemplate.Template) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "bastionhosts.Service.ExportResources")
+	defer done()
+
+	if spec := s.Scope.AzureBastionSpec(); spec != nil {
+		return template.AddSpec(spec)
+	}
+
+	return nil
+}
+
 // IsManaged returns always returns true as CAPZ does not support BYO bastion.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	return true, nil
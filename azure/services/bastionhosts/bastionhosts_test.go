@@ -18,10 +18,9 @@ package bastionhosts
 
 import (
 	"context"
-	"net/http"
+	"errors"
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -42,7 +41,7 @@ var (
 		SubnetID:    fakeSubnetID,
 		PublicIPID:  fakePublicIPID,
 	}
-	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+	internalError = errors.New("Internal Server Error")
 )
 
 func init() {
@@ -18,34 +18,32 @@ package bastionhosts
 
 import (
 	"context"
-	"encoding/json"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest"
-	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
-	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // azureClient contains the Azure go-sdk Client.
 type azureClient struct {
-	bastionhosts network.BastionHostsClient
+	bastionhosts *armnetwork.BastionHostsClient
 }
 
 // newClient creates a new VM client from subscription ID.
-func newClient(auth azure.Authorizer) *azureClient {
-	c := newBastionHostsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
-	return &azureClient{c}
-}
-
-// newBastionHostsClient creates a new bastion host client from subscription ID.
-func newBastionHostsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.BastionHostsClient {
-	bastionClient := network.NewBastionHostsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&bastionClient.Client, authorizer)
-	return bastionClient
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armnetwork.NewBastionHostsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create bastion hosts client")
+	}
+	return &azureClient{c}, nil
 }
 
 // Get gets the specified bastion host.
@@ -53,22 +51,27 @@ func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "bastionhosts.azureClient.Get")
 	defer done()
 
-	return ac.bastionhosts.Get(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	resp, err := ac.bastionhosts.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.BastionHost, nil
 }
 
 // CreateOrUpdateAsync creates or updates a bastion host asynchronously.
-// It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// It sends a PUT request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
 // progress of the operation.
-func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (result interface{}, future azureautorest.FutureAPI, err error) {
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "bastionhosts.azureClient.CreateOrUpdateAsync")
 	defer done()
 
-	host, ok := parameters.(network.BastionHost)
-	if !ok {
-		return nil, nil, errors.Errorf("%T is not a network.BastionHost", parameters)
+	host, ok := parameters.(armnetwork.BastionHost)
+	if !ok && parameters != nil {
+		return nil, nil, errors.Errorf("%T is not an armnetwork.BastionHost", parameters)
 	}
 
-	createFuture, err := ac.bastionhosts.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), host)
+	opts := &armnetwork.BastionHostsClientBeginCreateOrUpdateOptions{ResumeToken: resumeToken}
+	createPoller, err := ac.bastionhosts.BeginCreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), host, opts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -76,26 +79,26 @@ func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
 	defer cancel()
 
-	err = createFuture.WaitForCompletionRef(ctx, ac.bastionhosts.Client)
+	resp, err := createPoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
 	if err != nil {
-		// if an error occurs, return the future.
+		// if an error occurs, return the poller.
 		// this means the long-running operation didn't finish in the specified timeout.
-		return nil, &createFuture, err
+		return nil, createPoller, err
 	}
 
-	result, err = createFuture.Result(ac.bastionhosts)
-	// if the operation completed, return a nil future
-	return result, nil, err
+	// if the operation completed, return a nil poller
+	return resp.BastionHost, nil, nil
 }
 
 // DeleteAsync deletes a bastion host asynchronously. DeleteAsync sends a DELETE
-// request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
 // progress of the operation.
-func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (future azureautorest.FutureAPI, err error) {
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "bastionhosts.azureClient.Delete")
 	defer done()
 
-	deleteFuture, err := ac.bastionhosts.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	opts := &armnetwork.BastionHostsClientBeginDeleteOptions{ResumeToken: resumeToken}
+	deletePoller, err := ac.bastionhosts.BeginDelete(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -103,59 +106,13 @@ func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
 	defer cancel()
 
-	err = deleteFuture.WaitForCompletionRef(ctx, ac.bastionhosts.Client)
+	_, err = deletePoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
 	if err != nil {
-		// if an error occurs, return the future.
+		// if an error occurs, return the poller.
 		// this means the long-running operation didn't finish in the specified timeout.
-		return &deleteFuture, err
-	}
-	_, err = deleteFuture.Result(ac.bastionhosts)
-	// if the operation completed, return a nil future.
-	return nil, err
-}
-
-// IsDone returns true if the long-running operation has completed.
-func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (isDone bool, err error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "bastionhosts.azureClient.IsDone")
-	defer done()
-
-	isDone, err = future.DoneWithContext(ctx, ac.bastionhosts)
-	if err != nil {
-		return false, errors.Wrap(err, "failed checking if the operation was complete")
+		return deletePoller, err
 	}
 
-	return isDone, nil
-}
-
-// Result fetches the result of a long-running operation future.
-func (ac *azureClient) Result(ctx context.Context, future azureautorest.FutureAPI, futureType string) (result interface{}, err error) {
-	_, _, done := tele.StartSpanWithLogger(ctx, "bastionhosts.azureClient.Result")
-	defer done()
-
-	if future == nil {
-		return nil, errors.Errorf("cannot get result from nil future")
-	}
-
-	switch futureType {
-	case infrav1.PutFuture:
-		// Marshal and Unmarshal the future to put it into the correct future type so we can access the Result function.
-		// Unfortunately the FutureAPI can't be casted directly to BastionHostsCreateOrUpdateFuture because it is a azureautorest.Future, which doesn't implement the Result function. See PR #1686 for discussion on alternatives.
-		// It was converted back to a generic azureautorest.Future from the CAPZ infrav1.Future type stored in Status: https://github.com/kubernetes-sigs/cluster-api-provider-azure/blob/main/azure/converters/futures.go#L49.
-		var createFuture *network.BastionHostsCreateOrUpdateFuture
-		jsonData, err := future.MarshalJSON()
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to marshal future")
-		}
-		if err := json.Unmarshal(jsonData, &createFuture); err != nil {
-			return nil, errors.Wrap(err, "failed to unmarshal future data")
-		}
-		return createFuture.Result(ac.bastionhosts)
-
-	case infrav1.DeleteFuture:
-		// Delete does not return a result bastion host
-		return nil, nil
-
-	default:
-		return nil, errors.Errorf("unknown future type %q", futureType)
-	}
+	// if the operation completed, return a nil poller.
+	return nil, nil
 }
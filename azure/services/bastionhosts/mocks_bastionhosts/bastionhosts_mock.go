@@ -23,7 +23,7 @@ package mock_bastionhosts
 import (
 	reflect "reflect"
 
-	autorest "github.com/Azure/go-autorest/autorest"
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	gomock "github.com/golang/mock/gomock"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -109,18 +109,18 @@ func (mr *MockBastionScopeMockRecorder) AdditionalTags() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockBastionScope)(nil).AdditionalTags))
 }
 
-// Authorizer mocks base method.
-func (m *MockBastionScope) Authorizer() autorest.Authorizer {
+// AttachedACRs mocks base method.
+func (m *MockBastionScope) AttachedACRs() []string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authorizer")
-	ret0, _ := ret[0].(autorest.Authorizer)
+	ret := m.ctrl.Call(m, "AttachedACRs")
+	ret0, _ := ret[0].([]string)
 	return ret0
 }
 
-// Authorizer indicates an expected call of Authorizer.
-func (mr *MockBastionScopeMockRecorder) Authorizer() *gomock.Call {
+// AttachedACRs indicates an expected call of AttachedACRs.
+func (mr *MockBastionScopeMockRecorder) AttachedACRs() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockBastionScope)(nil).Authorizer))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachedACRs", reflect.TypeOf((*MockBastionScope)(nil).AttachedACRs))
 }
 
 // AvailabilitySetEnabled mocks base method.
@@ -165,6 +165,34 @@ func (mr *MockBastionScopeMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockBastionScope)(nil).BaseURI))
 }
 
+// BootstrapDataStorage mocks base method.
+func (m *MockBastionScope) BootstrapDataStorage() *v1beta1.BootstrapDataStorage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorage")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataStorage)
+	return ret0
+}
+
+// BootstrapDataStorage indicates an expected call of BootstrapDataStorage.
+func (mr *MockBastionScopeMockRecorder) BootstrapDataStorage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorage", reflect.TypeOf((*MockBastionScope)(nil).BootstrapDataStorage))
+}
+
+// BootstrapExtension mocks base method.
+func (m *MockBastionScope) BootstrapExtension() *v1beta1.BootstrapExtension {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapExtension")
+	ret0, _ := ret[0].(*v1beta1.BootstrapExtension)
+	return ret0
+}
+
+// BootstrapExtension indicates an expected call of BootstrapExtension.
+func (mr *MockBastionScopeMockRecorder) BootstrapExtension() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapExtension", reflect.TypeOf((*MockBastionScope)(nil).BootstrapExtension))
+}
+
 // ClientID mocks base method.
 func (m *MockBastionScope) ClientID() string {
 	m.ctrl.T.Helper()
@@ -263,6 +291,20 @@ func (mr *MockBastionScopeMockRecorder) ControlPlaneSubnet() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ControlPlaneSubnet", reflect.TypeOf((*MockBastionScope)(nil).ControlPlaneSubnet))
 }
 
+// CrossSubscriptionAccess mocks base method.
+func (m *MockBastionScope) CrossSubscriptionAccess() v1beta1.CrossSubscriptionAccessPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CrossSubscriptionAccess")
+	ret0, _ := ret[0].(v1beta1.CrossSubscriptionAccessPolicy)
+	return ret0
+}
+
+// CrossSubscriptionAccess indicates an expected call of CrossSubscriptionAccess.
+func (mr *MockBastionScopeMockRecorder) CrossSubscriptionAccess() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CrossSubscriptionAccess", reflect.TypeOf((*MockBastionScope)(nil).CrossSubscriptionAccess))
+}
+
 // DeleteLongRunningOperationState mocks base method.
 func (m *MockBastionScope) DeleteLongRunningOperationState(arg0, arg1 string) {
 	m.ctrl.T.Helper()
@@ -275,6 +317,20 @@ func (mr *MockBastionScopeMockRecorder) DeleteLongRunningOperationState(arg0, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockBastionScope)(nil).DeleteLongRunningOperationState), arg0, arg1)
 }
 
+// ExtendedLocation mocks base method.
+func (m *MockBastionScope) ExtendedLocation() *v1beta1.ExtendedLocationSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendedLocation")
+	ret0, _ := ret[0].(*v1beta1.ExtendedLocationSpec)
+	return ret0
+}
+
+// ExtendedLocation indicates an expected call of ExtendedLocation.
+func (mr *MockBastionScopeMockRecorder) ExtendedLocation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendedLocation", reflect.TypeOf((*MockBastionScope)(nil).ExtendedLocation))
+}
+
 // FailureDomains mocks base method.
 func (m *MockBastionScope) FailureDomains() []string {
 	m.ctrl.T.Helper()
@@ -387,6 +443,20 @@ func (mr *MockBastionScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockBastionScope)(nil).Location))
 }
 
+// Monitoring mocks base method.
+func (m *MockBastionScope) Monitoring() *v1beta1.AzureMonitoring {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Monitoring")
+	ret0, _ := ret[0].(*v1beta1.AzureMonitoring)
+	return ret0
+}
+
+// Monitoring indicates an expected call of Monitoring.
+func (mr *MockBastionScopeMockRecorder) Monitoring() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Monitoring", reflect.TypeOf((*MockBastionScope)(nil).Monitoring))
+}
+
 // NodeSubnets mocks base method.
 func (m *MockBastionScope) NodeSubnets() []v1beta1.SubnetSpec {
 	m.ctrl.T.Helper()
@@ -429,6 +499,34 @@ func (mr *MockBastionScopeMockRecorder) OutboundPoolName(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutboundPoolName", reflect.TypeOf((*MockBastionScope)(nil).OutboundPoolName), arg0)
 }
 
+// ProxyConfig mocks base method.
+func (m *MockBastionScope) ProxyConfig() *v1beta1.ProxyConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyConfig")
+	ret0, _ := ret[0].(*v1beta1.ProxyConfig)
+	return ret0
+}
+
+// ProxyConfig indicates an expected call of ProxyConfig.
+func (mr *MockBastionScopeMockRecorder) ProxyConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyConfig", reflect.TypeOf((*MockBastionScope)(nil).ProxyConfig))
+}
+
+// RegistryMirrors mocks base method.
+func (m *MockBastionScope) RegistryMirrors() []v1beta1.RegistryMirror {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryMirrors")
+	ret0, _ := ret[0].([]v1beta1.RegistryMirror)
+	return ret0
+}
+
+// RegistryMirrors indicates an expected call of RegistryMirrors.
+func (mr *MockBastionScopeMockRecorder) RegistryMirrors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryMirrors", reflect.TypeOf((*MockBastionScope)(nil).RegistryMirrors))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockBastionScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -443,6 +541,20 @@ func (mr *MockBastionScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockBastionScope)(nil).ResourceGroup))
 }
 
+// SecurityProfile mocks base method.
+func (m *MockBastionScope) SecurityProfile() *v1beta1.ClusterSecurityProfile {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecurityProfile")
+	ret0, _ := ret[0].(*v1beta1.ClusterSecurityProfile)
+	return ret0
+}
+
+// SecurityProfile indicates an expected call of SecurityProfile.
+func (mr *MockBastionScopeMockRecorder) SecurityProfile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityProfile", reflect.TypeOf((*MockBastionScope)(nil).SecurityProfile))
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockBastionScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -523,6 +635,20 @@ func (mr *MockBastionScopeMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockBastionScope)(nil).TenantID))
 }
 
+// Token mocks base method.
+func (m *MockBastionScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockBastionScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockBastionScope)(nil).Token))
+}
+
 // UpdateDeleteStatus mocks base method.
 func (m *MockBastionScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
 	m.ctrl.T.Helper()
@@ -20,8 +20,8 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
@@ -62,8 +62,8 @@ func (s *AzureBastionSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the bastion host.
 func (s *AzureBastionSpec) Parameters(existing interface{}) (parameters interface{}, err error) {
 	if existing != nil {
-		if _, ok := existing.(network.BastionHost); !ok {
-			return nil, errors.Errorf("%T is not a network.BastionHost", existing)
+		if _, ok := existing.(armnetwork.BastionHost); !ok {
+			return nil, errors.Errorf("%T is not an armnetwork.BastionHost", existing)
 		}
 		// bastion host already exists
 		return nil, nil
@@ -71,28 +71,28 @@ func (s *AzureBastionSpec) Parameters(existing interface{}) (parameters interfac
 
 	bastionHostIPConfigName := fmt.Sprintf("%s-%s", s.Name, "bastionIP")
 
-	return network.BastionHost{
-		Name:     to.StringPtr(s.Name),
-		Location: to.StringPtr(s.Location),
+	return armnetwork.BastionHost{
+		Name:     to.Ptr(s.Name),
+		Location: to.Ptr(s.Location),
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: s.ClusterName,
 			Lifecycle:   infrav1.ResourceLifecycleOwned,
-			Name:        to.StringPtr(s.Name),
-			Role:        to.StringPtr("Bastion"),
+			Name:        to.Ptr(s.Name),
+			Role:        to.Ptr("Bastion"),
 		})),
-		BastionHostPropertiesFormat: &network.BastionHostPropertiesFormat{
-			DNSName: to.StringPtr(fmt.Sprintf("%s-bastion", strings.ToLower(s.Name))),
-			IPConfigurations: &[]network.BastionHostIPConfiguration{
+		Properties: &armnetwork.BastionHostPropertiesFormat{
+			DNSName: to.Ptr(fmt.Sprintf("%s-bastion", strings.ToLower(s.Name))),
+			IPConfigurations: []*armnetwork.BastionHostIPConfiguration{
 				{
-					Name: to.StringPtr(bastionHostIPConfigName),
-					BastionHostIPConfigurationPropertiesFormat: &network.BastionHostIPConfigurationPropertiesFormat{
-						Subnet: &network.SubResource{
+					Name: to.Ptr(bastionHostIPConfigName),
+					Properties: &armnetwork.BastionHostIPConfigurationPropertiesFormat{
+						Subnet: &armnetwork.SubResource{
 							ID: &s.SubnetID,
 						},
-						PublicIPAddress: &network.SubResource{
+						PublicIPAddress: &armnetwork.SubResource{
 							ID: &s.PublicIPID,
 						},
-						PrivateIPAllocationMethod: network.IPAllocationMethodDynamic,
+						PrivateIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodDynamic),
 					},
 				},
 			},
@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapdata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	serviceName = "bootstrapdata"
+
+	// MaxCustomDataSize is the maximum length of the base64 encoded custom data of a virtual machine.
+	MaxCustomDataSize = 87380
+)
+
+// BootstrapDataScope defines the scope interface for a bootstrap data service.
+type BootstrapDataScope interface {
+	azure.Authorizer
+	BootstrapDataSpec() *azure.BootstrapDataSpec
+	SetBootstrapData(string)
+	BootstrapDataRequired() bool
+	BootstrapDataBlob() string
+	SetBootstrapDataBlob(string)
+	BootstrapDataHash() string
+	SetBootstrapDataHash(string)
+	BootstrapDataSASExpiry() time.Time
+	SetBootstrapDataSASExpiry(time.Time)
+}
+
+// Service provides operations on the storage delivering bootstrap data exceeding the custom data size limit.
+type Service struct {
+	Scope BootstrapDataScope
+	client
+}
+
+// New creates a new service.
+func New(scope BootstrapDataScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:  scope,
+		client: client,
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile uploads the bootstrap data of the machine to a blob when it exceeds the maximum custom data size, and
+// replaces it with a cloud-init include of a SAS URL to that blob. The blob is only uploaded again once the bootstrap
+// data changes, and the SAS URL is only renewed once less than half of its duration remains, so that the custom data
+// doesn't change on every reconciliation. The blob is deleted as soon as no machine is going to be created from the
+// bootstrap data anymore, so that the secrets it holds don't outlive the bootstrap.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "bootstrapdata.Service.Reconcile")
+	defer done()
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.BootstrapDataSpec()
+	if spec == nil {
+		return nil
+	}
+
+	if !s.Scope.BootstrapDataRequired() {
+		blobName := s.Scope.BootstrapDataBlob()
+		if blobName == "" {
+			return nil
+		}
+		log.V(2).Info("deleting bootstrap data blob of bootstrapped machines", tele.LogKeyResource, blobName, "storageAccount", spec.StorageAccountName)
+		if err := s.client.DeleteBlob(ctx, spec.ResourceGroup, spec.StorageAccountName, spec.ContainerName, blobName); err != nil {
+			return errors.Wrapf(err, "failed to delete bootstrap data blob %s", blobName)
+		}
+		s.Scope.SetBootstrapDataBlob("")
+		return nil
+	}
+
+	if len(spec.BootstrapData) <= MaxCustomDataSize {
+		return nil
+	}
+
+	if spec.OSType == azure.WindowsOS {
+		return errors.Errorf("bootstrap data is %d bytes once encoded, exceeding the maximum custom data size of %d bytes, "+
+			"which can only be delivered through storage to Linux machines", len(spec.BootstrapData), MaxCustomDataSize)
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(spec.BootstrapData)))
+	if s.Scope.BootstrapDataBlob() != spec.BlobName || s.Scope.BootstrapDataHash() != hash {
+		data, err := base64.StdEncoding.DecodeString(spec.BootstrapData)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode bootstrap data")
+		}
+
+		log.V(2).Info("uploading bootstrap data exceeding the maximum custom data size", tele.LogKeyResource, spec.BlobName, "storageAccount", spec.StorageAccountName)
+		if err := s.client.CreateContainerIfNotExists(ctx, spec.ResourceGroup, spec.StorageAccountName, spec.ContainerName); err != nil {
+			return err
+		}
+		if err := s.client.UploadBlob(ctx, spec.ResourceGroup, spec.StorageAccountName, spec.ContainerName, spec.BlobName, data); err != nil {
+			return errors.Wrapf(err, "failed to upload bootstrap data to blob %s", spec.BlobName)
+		}
+		s.Scope.SetBootstrapDataBlob(spec.BlobName)
+		s.Scope.SetBootstrapDataHash(hash)
+	}
+
+	// the SAS of a given expiry is the same on every request, so the custom data including it only changes once it's
+	// renewed.
+	expiry := s.Scope.BootstrapDataSASExpiry()
+	if time.Until(expiry) < spec.SASDuration/2 {
+		expiry = time.Now().Add(spec.SASDuration).UTC().Truncate(time.Second)
+		log.V(2).Info("renewing the SAS URL of the bootstrap data blob", tele.LogKeyResource, spec.BlobName, "expiry", expiry)
+		s.Scope.SetBootstrapDataSASExpiry(expiry)
+	}
+
+	url, err := s.client.GetBlobReadURL(ctx, spec.ResourceGroup, spec.StorageAccountName, spec.ContainerName, spec.BlobName, expiry)
+	if err != nil {
+		return err
+	}
+
+	s.Scope.SetBootstrapData(base64.StdEncoding.EncodeToString([]byte(fetchStub(url))))
+	return nil
+}
+
+// Delete deletes the blob holding the bootstrap data of the machine, if any.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "bootstrapdata.Service.Delete")
+	defer done()
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.BootstrapDataSpec()
+	if spec == nil {
+		return nil
+	}
+
+	log.V(2).Info("deleting bootstrap data blob", tele.LogKeyResource, spec.BlobName, "storageAccount", spec.StorageAccountName)
+	if err := s.client.DeleteBlob(ctx, spec.ResourceGroup, spec.StorageAccountName, spec.ContainerName, spec.BlobName); err != nil {
+		return errors.Wrapf(err, "failed to delete bootstrap data blob %s", spec.BlobName)
+	}
+	return nil
+}
+
+// IsManaged always returns true as the bootstrap data blobs are always created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+// fetchStub returns the cloud-init user data including the bootstrap data from the given URL.
+// See https://cloudinit.readthedocs.io/en/latest/topics/format.html#include-file.
+func fetchStub(url string) string {
+	return fmt.Sprintf("#include\n%s\n", url)
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapdata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bootstrapdata/mock_bootstrapdata"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	smallBootstrapData = base64.StdEncoding.EncodeToString([]byte("#cloud-config\n"))
+	largeBootstrapData = base64.StdEncoding.EncodeToString([]byte("#cloud-config\n" + strings.Repeat("a", 70*1024)))
+	largeBootstrapHash = fmt.Sprintf("%x", sha256.Sum256([]byte(largeBootstrapData)))
+	sasExpiry          = time.Now().Add(45 * time.Minute).UTC().Truncate(time.Second)
+)
+
+const (
+	blobReadURL   = "https://capzbootstrap.blob.core.windows.net/capz-bootstrap-data/my-cluster/my-vm?sig=fake"
+	fetchStubData = "#include\nhttps://capzbootstrap.blob.core.windows.net/capz-bootstrap-data/my-cluster/my-vm?sig=fake\n"
+)
+
+func newSpec(bootstrapData string) *azure.BootstrapDataSpec {
+	return &azure.BootstrapDataSpec{
+		BlobName:           "my-cluster/my-vm",
+		StorageAccountName: "capzbootstrap",
+		ResourceGroup:      "my-rg",
+		ContainerName:      "capz-bootstrap-data",
+		SASDuration:        time.Hour,
+		OSType:             azure.LinuxOS,
+		BootstrapData:      bootstrapData,
+	}
+}
+
+// expiresAfter matches expiry times at least the given duration from now.
+type expiresAfter time.Duration
+
+func (d expiresAfter) Matches(x interface{}) bool {
+	expiry, ok := x.(time.Time)
+	return ok && time.Until(expiry) >= time.Duration(d)
+}
+
+func (d expiresAfter) String() string {
+	return fmt.Sprintf("expires after %s", time.Duration(d))
+}
+
+func TestReconcileBootstrapData(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder)
+	}{
+		{
+			name:          "noop if no bootstrap data storage is configured",
+			expectedError: "",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder) {
+				s.BootstrapDataSpec().Return(nil)
+			},
+		},
+		{
+			name:          "noop if bootstrap data fits in custom data",
+			expectedError: "",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder) {
+				s.BootstrapDataSpec().Return(newSpec(smallBootstrapData))
+				s.BootstrapDataRequired().Return(true)
+			},
+		},
+		{
+			name:          "noop if the bootstrap data is not required and was never uploaded",
+			expectedError: "",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder) {
+				s.BootstrapDataSpec().Return(newSpec(largeBootstrapData))
+				s.BootstrapDataRequired().Return(false)
+				s.BootstrapDataBlob().Return("")
+			},
+		},
+		{
+			name:          "deletes the blob once the bootstrap data is not required anymore",
+			expectedError: "",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder) {
+				s.BootstrapDataSpec().Return(newSpec(largeBootstrapData))
+				s.BootstrapDataRequired().Return(false)
+				s.BootstrapDataBlob().Return("my-cluster/my-vm")
+				gomock.InOrder(
+					m.DeleteBlob(gomockinternal.AContext(), "my-rg", "capzbootstrap", "capz-bootstrap-data", "my-cluster/my-vm"),
+					s.SetBootstrapDataBlob(""),
+				)
+			},
+		},
+		{
+			name:          "keeps the blob recorded if it can't be deleted",
+			expectedError: "failed to delete bootstrap data blob my-cluster/my-vm: #: Internal Server Error",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder) {
+				s.BootstrapDataSpec().Return(newSpec(largeBootstrapData))
+				s.BootstrapDataRequired().Return(false)
+				s.BootstrapDataBlob().Return("my-cluster/my-vm")
+				m.DeleteBlob(gomockinternal.AContext(), "my-rg", "capzbootstrap", "capz-bootstrap-data", "my-cluster/my-vm").
+					Return(errors.New("#: Internal Server Error"))
+			},
+		},
+		{
+			name:          "uploads large bootstrap data and sets a fetch stub",
+			expectedError: "",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder) {
+				s.BootstrapDataSpec().Return(newSpec(largeBootstrapData))
+				s.BootstrapDataRequired().Return(true)
+				s.BootstrapDataBlob().Return("")
+				s.BootstrapDataSASExpiry().Return(time.Time{})
+				data, _ := base64.StdEncoding.DecodeString(largeBootstrapData)
+				gomock.InOrder(
+					m.CreateContainerIfNotExists(gomockinternal.AContext(), "my-rg", "capzbootstrap", "capz-bootstrap-data"),
+					m.UploadBlob(gomockinternal.AContext(), "my-rg", "capzbootstrap", "capz-bootstrap-data", "my-cluster/my-vm", data),
+					s.SetBootstrapDataBlob("my-cluster/my-vm"),
+					s.SetBootstrapDataHash(largeBootstrapHash),
+					s.SetBootstrapDataSASExpiry(gomock.Any()),
+					m.GetBlobReadURL(gomockinternal.AContext(), "my-rg", "capzbootstrap", "capz-bootstrap-data", "my-cluster/my-vm", gomock.Any()).
+						Return(blobReadURL, nil),
+					s.SetBootstrapData(base64.StdEncoding.EncodeToString([]byte(fetchStubData))),
+				)
+			},
+		},
+		{
+			name:          "reuses the uploaded bootstrap data and the SAS URL until it's close to expiry",
+			expectedError: "",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder) {
+				s.BootstrapDataSpec().Return(newSpec(largeBootstrapData))
+				s.BootstrapDataRequired().Return(true)
+				s.BootstrapDataBlob().Return("my-cluster/my-vm")
+				s.BootstrapDataHash().Return(largeBootstrapHash)
+				s.BootstrapDataSASExpiry().Return(sasExpiry)
+				gomock.InOrder(
+					m.GetBlobReadURL(gomockinternal.AContext(), "my-rg", "capzbootstrap", "capz-bootstrap-data", "my-cluster/my-vm", sasExpiry).
+						Return(blobReadURL, nil),
+					s.SetBootstrapData(base64.StdEncoding.EncodeToString([]byte(fetchStubData))),
+				)
+			},
+		},
+		{
+			name:          "uploads changed bootstrap data without renewing the SAS URL",
+			expectedError: "",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder) {
+				s.BootstrapDataSpec().Return(newSpec(largeBootstrapData))
+				s.BootstrapDataRequired().Return(true)
+				s.BootstrapDataBlob().Return("my-cluster/my-vm")
+				s.BootstrapDataHash().Return("outdated")
+				s.BootstrapDataSASExpiry().Return(sasExpiry)
+				gomock.InOrder(
+					m.CreateContainerIfNotExists(gomockinternal.AContext(), "my-rg", "capzbootstrap", "capz-bootstrap-data"),
+					m.UploadBlob(gomockinternal.AContext(), "my-rg", "capzbootstrap", "capz-bootstrap-data", "my-cluster/my-vm", gomock.Any()),
+					s.SetBootstrapDataBlob("my-cluster/my-vm"),
+					s.SetBootstrapDataHash(largeBootstrapHash),
+					m.GetBlobReadURL(gomockinternal.AContext(), "my-rg", "capzbootstrap", "capz-bootstrap-data", "my-cluster/my-vm", sasExpiry).
+						Return(blobReadURL, nil),
+					s.SetBootstrapData(base64.StdEncoding.EncodeToString([]byte(fetchStubData))),
+				)
+			},
+		},
+		{
+			name:          "renews the SAS URL once less than half of its duration remains",
+			expectedError: "",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder) {
+				s.BootstrapDataSpec().Return(newSpec(largeBootstrapData))
+				s.BootstrapDataRequired().Return(true)
+				s.BootstrapDataBlob().Return("my-cluster/my-vm")
+				s.BootstrapDataHash().Return(largeBootstrapHash)
+				s.BootstrapDataSASExpiry().Return(time.Now().Add(20 * time.Minute))
+				renewed := expiresAfter(59 * time.Minute)
+				gomock.InOrder(
+					s.SetBootstrapDataSASExpiry(renewed),
+					m.GetBlobReadURL(gomockinternal.AContext(), "my-rg", "capzbootstrap", "capz-bootstrap-data", "my-cluster/my-vm", renewed).
+						Return(blobReadURL, nil),
+					s.SetBootstrapData(base64.StdEncoding.EncodeToString([]byte(fetchStubData))),
+				)
+			},
+		},
+		{
+			name:          "fails for large bootstrap data on Windows",
+			expectedError: "which can only be delivered through storage to Linux machines",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder) {
+				spec := newSpec(largeBootstrapData)
+				spec.OSType = azure.WindowsOS
+				s.BootstrapDataSpec().Return(spec)
+				s.BootstrapDataRequired().Return(true)
+			},
+		},
+		{
+			name:          "fails if the upload fails",
+			expectedError: "failed to upload bootstrap data to blob my-cluster/my-vm: #: Internal Server Error",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder) {
+				s.BootstrapDataSpec().Return(newSpec(largeBootstrapData))
+				s.BootstrapDataRequired().Return(true)
+				s.BootstrapDataBlob().Return("")
+				m.CreateContainerIfNotExists(gomockinternal.AContext(), "my-rg", "capzbootstrap", "capz-bootstrap-data")
+				m.UploadBlob(gomockinternal.AContext(), "my-rg", "capzbootstrap", "capz-bootstrap-data", "my-cluster/my-vm", gomock.Any()).
+					Return(errors.New("#: Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_bootstrapdata.NewMockBootstrapDataScope(mockCtrl)
+			clientMock := mock_bootstrapdata.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteBootstrapData(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder)
+	}{
+		{
+			name:          "noop if no bootstrap data storage is configured",
+			expectedError: "",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder) {
+				s.BootstrapDataSpec().Return(nil)
+			},
+		},
+		{
+			name:          "deletes the bootstrap data blob",
+			expectedError: "",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder) {
+				s.BootstrapDataSpec().Return(newSpec(""))
+				m.DeleteBlob(gomockinternal.AContext(), "my-rg", "capzbootstrap", "capz-bootstrap-data", "my-cluster/my-vm")
+			},
+		},
+		{
+			name:          "fails if the blob can't be deleted",
+			expectedError: "failed to delete bootstrap data blob my-cluster/my-vm: #: Internal Server Error",
+			expect: func(s *mock_bootstrapdata.MockBootstrapDataScopeMockRecorder, m *mock_bootstrapdata.MockclientMockRecorder) {
+				s.BootstrapDataSpec().Return(newSpec(""))
+				m.DeleteBlob(gomockinternal.AContext(), "my-rg", "capzbootstrap", "capz-bootstrap-data", "my-cluster/my-vm").
+					Return(errors.New("#: Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_bootstrapdata.NewMockBootstrapDataScope(mockCtrl)
+			clientMock := mock_bootstrapdata.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
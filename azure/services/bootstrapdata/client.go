@@ -0,0 +1,210 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapdata
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// blobWriteSASDuration is how long the SAS used by the controller to write or delete a blob is valid for.
+	blobWriteSASDuration = 15 * time.Minute
+	// blobRequestTimeout is the timeout of the requests sent to the blob service.
+	blobRequestTimeout = 30 * time.Second
+)
+
+// client wraps go-sdk.
+type client interface {
+	CreateContainerIfNotExists(ctx context.Context, resourceGroup, accountName, containerName string) error
+	UploadBlob(ctx context.Context, resourceGroup, accountName, containerName, blobName string, data []byte) error
+	GetBlobReadURL(ctx context.Context, resourceGroup, accountName, containerName, blobName string, expiry time.Time) (string, error)
+	DeleteBlob(ctx context.Context, resourceGroup, accountName, containerName, blobName string) error
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	accounts   *armstorage.AccountsClient
+	containers *armstorage.BlobContainersClient
+	http       *http.Client
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new bootstrap data client from subscription ID.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	accounts, err := armstorage.NewAccountsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create storage accounts client")
+	}
+	containers, err := armstorage.NewBlobContainersClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create blob containers client")
+	}
+	return &azureClient{
+		accounts:   accounts,
+		containers: containers,
+		http:       &http.Client{Timeout: blobRequestTimeout},
+	}, nil
+}
+
+// CreateContainerIfNotExists creates a private blob container in the storage account if it doesn't exist yet.
+func (ac *azureClient) CreateContainerIfNotExists(ctx context.Context, resourceGroup, accountName, containerName string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "bootstrapdata.AzureClient.CreateContainerIfNotExists")
+	defer done()
+
+	_, err := ac.containers.Get(ctx, resourceGroup, accountName, containerName, nil)
+	if err == nil {
+		return nil
+	}
+	if !azure.ResourceNotFound(err) {
+		return errors.Wrapf(err, "failed to get blob container %s", containerName)
+	}
+	_, err = ac.containers.Create(ctx, resourceGroup, accountName, containerName, armstorage.BlobContainer{
+		ContainerProperties: &armstorage.ContainerProperties{
+			PublicAccess: to.Ptr(armstorage.PublicAccessNone),
+		},
+	}, nil)
+	if err != nil && !azure.ResourceConflict(err) {
+		return errors.Wrapf(err, "failed to create blob container %s", containerName)
+	}
+	return nil
+}
+
+// UploadBlob uploads data to a block blob, replacing any existing content.
+func (ac *azureClient) UploadBlob(ctx context.Context, resourceGroup, accountName, containerName, blobName string, data []byte) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "bootstrapdata.AzureClient.UploadBlob")
+	defer done()
+
+	blobURL, err := ac.blobSASURL(ctx, resourceGroup, accountName, containerName, blobName, armstorage.Permissions("cw"), time.Now().Add(blobWriteSASDuration))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, blobURL, bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "failed to create blob upload request")
+	}
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	return ac.sendBlobRequest(req, http.StatusCreated)
+}
+
+// GetBlobReadURL returns a URL granting read access to a blob until expiry.
+func (ac *azureClient) GetBlobReadURL(ctx context.Context, resourceGroup, accountName, containerName, blobName string, expiry time.Time) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "bootstrapdata.AzureClient.GetBlobReadURL")
+	defer done()
+
+	return ac.blobSASURL(ctx, resourceGroup, accountName, containerName, blobName, armstorage.PermissionsR, expiry)
+}
+
+// DeleteBlob deletes a blob. It is a no-op if the blob doesn't exist.
+func (ac *azureClient) DeleteBlob(ctx context.Context, resourceGroup, accountName, containerName, blobName string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "bootstrapdata.AzureClient.DeleteBlob")
+	defer done()
+
+	blobURL, err := ac.blobSASURL(ctx, resourceGroup, accountName, containerName, blobName, armstorage.PermissionsD, time.Now().Add(blobWriteSASDuration))
+	if err != nil {
+		if azure.ResourceNotFound(err) {
+			// the storage account is gone, and the blob with it.
+			return nil
+		}
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, blobURL, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create blob delete request")
+	}
+
+	return ac.sendBlobRequest(req, http.StatusAccepted, http.StatusNotFound)
+}
+
+// blobSASURL returns the URL of a blob with a service SAS granting the given permissions until expiry.
+func (ac *azureClient) blobSASURL(ctx context.Context, resourceGroup, accountName, containerName, blobName string, permissions armstorage.Permissions, expiry time.Time) (string, error) {
+	account, err := ac.accounts.GetProperties(ctx, resourceGroup, accountName, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get storage account %s", accountName)
+	}
+	if account.Properties == nil || account.Properties.PrimaryEndpoints == nil || account.Properties.PrimaryEndpoints.Blob == nil {
+		return "", errors.Errorf("storage account %s has no blob endpoint", accountName)
+	}
+
+	sas, err := ac.accounts.ListServiceSAS(ctx, resourceGroup, accountName, armstorage.ServiceSasParameters{
+		CanonicalizedResource:  to.Ptr(fmt.Sprintf("/blob/%s/%s/%s", accountName, containerName, blobName)),
+		Resource:               to.Ptr(armstorage.SignedResourceB),
+		Permissions:            to.Ptr(permissions),
+		Protocols:              to.Ptr(armstorage.HTTPProtocolHTTPS),
+		SharedAccessExpiryTime: to.Ptr(expiry.UTC()),
+	}, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create a SAS for blob %s/%s", containerName, blobName)
+	}
+	if sas.ServiceSasToken == nil {
+		return "", errors.Errorf("no SAS token returned for blob %s/%s", containerName, blobName)
+	}
+
+	return fmt.Sprintf("%s%s/%s?%s", ensureTrailingSlash(*account.Properties.PrimaryEndpoints.Blob), url.PathEscape(containerName),
+		escapeBlobName(blobName), *sas.ServiceSasToken), nil
+}
+
+// sendBlobRequest sends a request to the blob service and checks the response has one of the expected status codes.
+func (ac *azureClient) sendBlobRequest(req *http.Request, expectedStatusCodes ...int) error {
+	req.Header.Set("x-ms-version", "2020-10-02")
+	resp, err := ac.http.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to send %s blob request", req.Method)
+	}
+	defer resp.Body.Close()
+
+	for _, code := range expectedStatusCodes {
+		if resp.StatusCode == code {
+			return nil
+		}
+	}
+	return errors.Errorf("%s blob request failed with status %s", req.Method, resp.Status)
+}
+
+// escapeBlobName escapes each segment of a blob name, keeping the virtual directory separators.
+func escapeBlobName(blobName string) string {
+	segments := strings.Split(blobName, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
+
+func ensureTrailingSlash(s string) string {
+	if strings.HasSuffix(s, "/") {
+		return s
+	}
+	return s + "/"
+}
@@ -0,0 +1,283 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../bootstrapdata.go
+
+// Package mock_bootstrapdata is a generated GoMock package.
+package mock_bootstrapdata
+
+import (
+	reflect "reflect"
+	time "time"
+
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	gomock "github.com/golang/mock/gomock"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// MockBootstrapDataScope is a mock of BootstrapDataScope interface.
+type MockBootstrapDataScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockBootstrapDataScopeMockRecorder
+}
+
+// MockBootstrapDataScopeMockRecorder is the mock recorder for MockBootstrapDataScope.
+type MockBootstrapDataScopeMockRecorder struct {
+	mock *MockBootstrapDataScope
+}
+
+// NewMockBootstrapDataScope creates a new mock instance.
+func NewMockBootstrapDataScope(ctrl *gomock.Controller) *MockBootstrapDataScope {
+	mock := &MockBootstrapDataScope{ctrl: ctrl}
+	mock.recorder = &MockBootstrapDataScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBootstrapDataScope) EXPECT() *MockBootstrapDataScopeMockRecorder {
+	return m.recorder
+}
+
+// BaseURI mocks base method.
+func (m *MockBootstrapDataScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockBootstrapDataScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockBootstrapDataScope)(nil).BaseURI))
+}
+
+// BootstrapDataBlob mocks base method.
+func (m *MockBootstrapDataScope) BootstrapDataBlob() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataBlob")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BootstrapDataBlob indicates an expected call of BootstrapDataBlob.
+func (mr *MockBootstrapDataScopeMockRecorder) BootstrapDataBlob() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataBlob", reflect.TypeOf((*MockBootstrapDataScope)(nil).BootstrapDataBlob))
+}
+
+// BootstrapDataHash mocks base method.
+func (m *MockBootstrapDataScope) BootstrapDataHash() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataHash")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BootstrapDataHash indicates an expected call of BootstrapDataHash.
+func (mr *MockBootstrapDataScopeMockRecorder) BootstrapDataHash() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataHash", reflect.TypeOf((*MockBootstrapDataScope)(nil).BootstrapDataHash))
+}
+
+// BootstrapDataRequired mocks base method.
+func (m *MockBootstrapDataScope) BootstrapDataRequired() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataRequired")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// BootstrapDataRequired indicates an expected call of BootstrapDataRequired.
+func (mr *MockBootstrapDataScopeMockRecorder) BootstrapDataRequired() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataRequired", reflect.TypeOf((*MockBootstrapDataScope)(nil).BootstrapDataRequired))
+}
+
+// BootstrapDataSASExpiry mocks base method.
+func (m *MockBootstrapDataScope) BootstrapDataSASExpiry() time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataSASExpiry")
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+// BootstrapDataSASExpiry indicates an expected call of BootstrapDataSASExpiry.
+func (mr *MockBootstrapDataScopeMockRecorder) BootstrapDataSASExpiry() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataSASExpiry", reflect.TypeOf((*MockBootstrapDataScope)(nil).BootstrapDataSASExpiry))
+}
+
+// BootstrapDataSpec mocks base method.
+func (m *MockBootstrapDataScope) BootstrapDataSpec() *azure.BootstrapDataSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataSpec")
+	ret0, _ := ret[0].(*azure.BootstrapDataSpec)
+	return ret0
+}
+
+// BootstrapDataSpec indicates an expected call of BootstrapDataSpec.
+func (mr *MockBootstrapDataScopeMockRecorder) BootstrapDataSpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataSpec", reflect.TypeOf((*MockBootstrapDataScope)(nil).BootstrapDataSpec))
+}
+
+// ClientID mocks base method.
+func (m *MockBootstrapDataScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockBootstrapDataScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockBootstrapDataScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockBootstrapDataScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockBootstrapDataScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockBootstrapDataScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockBootstrapDataScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockBootstrapDataScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockBootstrapDataScope)(nil).CloudEnvironment))
+}
+
+// HashKey mocks base method.
+func (m *MockBootstrapDataScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockBootstrapDataScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockBootstrapDataScope)(nil).HashKey))
+}
+
+// SetBootstrapData mocks base method.
+func (m *MockBootstrapDataScope) SetBootstrapData(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBootstrapData", arg0)
+}
+
+// SetBootstrapData indicates an expected call of SetBootstrapData.
+func (mr *MockBootstrapDataScopeMockRecorder) SetBootstrapData(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapData", reflect.TypeOf((*MockBootstrapDataScope)(nil).SetBootstrapData), arg0)
+}
+
+// SetBootstrapDataBlob mocks base method.
+func (m *MockBootstrapDataScope) SetBootstrapDataBlob(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBootstrapDataBlob", arg0)
+}
+
+// SetBootstrapDataBlob indicates an expected call of SetBootstrapDataBlob.
+func (mr *MockBootstrapDataScopeMockRecorder) SetBootstrapDataBlob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapDataBlob", reflect.TypeOf((*MockBootstrapDataScope)(nil).SetBootstrapDataBlob), arg0)
+}
+
+// SetBootstrapDataHash mocks base method.
+func (m *MockBootstrapDataScope) SetBootstrapDataHash(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBootstrapDataHash", arg0)
+}
+
+// SetBootstrapDataHash indicates an expected call of SetBootstrapDataHash.
+func (mr *MockBootstrapDataScopeMockRecorder) SetBootstrapDataHash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapDataHash", reflect.TypeOf((*MockBootstrapDataScope)(nil).SetBootstrapDataHash), arg0)
+}
+
+// SetBootstrapDataSASExpiry mocks base method.
+func (m *MockBootstrapDataScope) SetBootstrapDataSASExpiry(arg0 time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBootstrapDataSASExpiry", arg0)
+}
+
+// SetBootstrapDataSASExpiry indicates an expected call of SetBootstrapDataSASExpiry.
+func (mr *MockBootstrapDataScopeMockRecorder) SetBootstrapDataSASExpiry(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapDataSASExpiry", reflect.TypeOf((*MockBootstrapDataScope)(nil).SetBootstrapDataSASExpiry), arg0)
+}
+
+// SubscriptionID mocks base method.
+func (m *MockBootstrapDataScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockBootstrapDataScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockBootstrapDataScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockBootstrapDataScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockBootstrapDataScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockBootstrapDataScope)(nil).TenantID))
+}
+
+// Token mocks base method.
+func (m *MockBootstrapDataScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockBootstrapDataScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockBootstrapDataScope)(nil).Token))
+}
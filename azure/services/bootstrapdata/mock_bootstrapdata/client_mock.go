@@ -0,0 +1,109 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../client.go
+
+// Package mock_bootstrapdata is a generated GoMock package.
+package mock_bootstrapdata
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// Mockclient is a mock of client interface.
+type Mockclient struct {
+	ctrl     *gomock.Controller
+	recorder *MockclientMockRecorder
+}
+
+// MockclientMockRecorder is the mock recorder for Mockclient.
+type MockclientMockRecorder struct {
+	mock *Mockclient
+}
+
+// NewMockclient creates a new mock instance.
+func NewMockclient(ctrl *gomock.Controller) *Mockclient {
+	mock := &Mockclient{ctrl: ctrl}
+	mock.recorder = &MockclientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockclient) EXPECT() *MockclientMockRecorder {
+	return m.recorder
+}
+
+// CreateContainerIfNotExists mocks base method.
+func (m *Mockclient) CreateContainerIfNotExists(ctx context.Context, resourceGroup, accountName, containerName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateContainerIfNotExists", ctx, resourceGroup, accountName, containerName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateContainerIfNotExists indicates an expected call of CreateContainerIfNotExists.
+func (mr *MockclientMockRecorder) CreateContainerIfNotExists(ctx, resourceGroup, accountName, containerName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContainerIfNotExists", reflect.TypeOf((*Mockclient)(nil).CreateContainerIfNotExists), ctx, resourceGroup, accountName, containerName)
+}
+
+// DeleteBlob mocks base method.
+func (m *Mockclient) DeleteBlob(ctx context.Context, resourceGroup, accountName, containerName, blobName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBlob", ctx, resourceGroup, accountName, containerName, blobName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBlob indicates an expected call of DeleteBlob.
+func (mr *MockclientMockRecorder) DeleteBlob(ctx, resourceGroup, accountName, containerName, blobName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlob", reflect.TypeOf((*Mockclient)(nil).DeleteBlob), ctx, resourceGroup, accountName, containerName, blobName)
+}
+
+// GetBlobReadURL mocks base method.
+func (m *Mockclient) GetBlobReadURL(ctx context.Context, resourceGroup, accountName, containerName, blobName string, expiry time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlobReadURL", ctx, resourceGroup, accountName, containerName, blobName, expiry)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlobReadURL indicates an expected call of GetBlobReadURL.
+func (mr *MockclientMockRecorder) GetBlobReadURL(ctx, resourceGroup, accountName, containerName, blobName, expiry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlobReadURL", reflect.TypeOf((*Mockclient)(nil).GetBlobReadURL), ctx, resourceGroup, accountName, containerName, blobName, expiry)
+}
+
+// UploadBlob mocks base method.
+func (m *Mockclient) UploadBlob(ctx context.Context, resourceGroup, accountName, containerName, blobName string, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadBlob", ctx, resourceGroup, accountName, containerName, blobName, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UploadBlob indicates an expected call of UploadBlob.
+func (mr *MockclientMockRecorder) UploadBlob(ctx, resourceGroup, accountName, containerName, blobName, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadBlob", reflect.TypeOf((*Mockclient)(nil).UploadBlob), ctx, resourceGroup, accountName, containerName, blobName, data)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datacollection
+
+import (
+	"context"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "datacollection"
+
+// Scope defines the scope interface for a data collection service.
+type Scope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	DataCollectionSpecs() (endpointSpec, ruleSpec azure.ResourceSpecGetter)
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope              Scope
+	endpointReconciler async.Reconciler
+	ruleReconciler     async.Reconciler
+}
+
+// New creates a new data collection service.
+func New(scope Scope) (*Service, error) {
+	endpointsClient, err := newDataCollectionEndpointsClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	rulesClient, err := newDataCollectionRulesClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:              scope,
+		endpointReconciler: async.NewPollerService(scope, endpointsClient, endpointsClient),
+		ruleReconciler:     async.NewPollerService(scope, rulesClient, rulesClient),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the data collection endpoint and rule.
+func (s *Service) RequiredActions() []string {
+	if endpointSpec, _ := s.Scope.DataCollectionSpecs(); endpointSpec == nil {
+		return nil
+	}
+	return []string{
+		"Microsoft.Insights/dataCollectionEndpoints/read",
+		"Microsoft.Insights/dataCollectionEndpoints/write",
+		"Microsoft.Insights/dataCollectionRules/read",
+		"Microsoft.Insights/dataCollectionRules/write",
+	}
+}
+
+// Reconcile creates or updates the data collection endpoint and rule of the cluster.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "datacollection.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	endpointSpec, ruleSpec := s.Scope.DataCollectionSpecs()
+	if endpointSpec == nil {
+		return nil
+	}
+
+	_, err := s.endpointReconciler.CreateResource(ctx, endpointSpec, serviceName)
+	if err == nil {
+		_, err = s.ruleReconciler.CreateResource(ctx, ruleSpec, serviceName)
+	}
+	s.Scope.UpdatePutStatus(infrav1.DataCollectionReadyCondition, serviceName, err)
+	return err
+}
+
+// Delete deletes the data collection rule and endpoint of the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "datacollection.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	endpointSpec, ruleSpec := s.Scope.DataCollectionSpecs()
+	if endpointSpec == nil {
+		return nil
+	}
+
+	err := s.ruleReconciler.DeleteResource(ctx, ruleSpec, serviceName)
+	if err == nil {
+		err = s.endpointReconciler.DeleteResource(ctx, endpointSpec, serviceName)
+	}
+	s.Scope.UpdateDeleteStatus(infrav1.DataCollectionReadyCondition, serviceName, err)
+	return err
+}
+
+// IsManaged always returns true as the data collection endpoint and rule of the cluster are always created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
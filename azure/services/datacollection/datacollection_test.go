@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datacollection
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/datacollection/mock_datacollection"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeEndpointSpec = DataCollectionEndpointSpec{
+		Name:          "my-cluster-dce",
+		ResourceGroup: "my-rg",
+		Location:      "westus",
+		ClusterName:   "my-cluster",
+	}
+	fakeRuleSpec = DataCollectionRuleSpec{
+		Name:          "my-cluster-dcr",
+		ResourceGroup: "my-rg",
+		Location:      "westus",
+		WorkspaceID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.OperationalInsights/workspaces/my-cluster-logs",
+		ClusterName:   "my-cluster",
+	}
+
+	internalError = errors.New("Internal Server Error")
+)
+
+func TestReconcileDataCollection(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_datacollection.MockScopeMockRecorder, e, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the cluster isn't onboarded to Azure Monitor",
+			expectedError: "",
+			expect: func(s *mock_datacollection.MockScopeMockRecorder, e, r *mock_async.MockReconcilerMockRecorder) {
+				s.DataCollectionSpecs().Return(nil, nil)
+			},
+		},
+		{
+			name:          "create the data collection endpoint and rule",
+			expectedError: "",
+			expect: func(s *mock_datacollection.MockScopeMockRecorder, e, r *mock_async.MockReconcilerMockRecorder) {
+				s.DataCollectionSpecs().Return(&fakeEndpointSpec, &fakeRuleSpec)
+				e.CreateResource(gomockinternal.AContext(), &fakeEndpointSpec, serviceName).Return(nil, nil)
+				r.CreateResource(gomockinternal.AContext(), &fakeRuleSpec, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.DataCollectionReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "don't create the rule if the endpoint fails to be created",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_datacollection.MockScopeMockRecorder, e, r *mock_async.MockReconcilerMockRecorder) {
+				s.DataCollectionSpecs().Return(&fakeEndpointSpec, &fakeRuleSpec)
+				e.CreateResource(gomockinternal.AContext(), &fakeEndpointSpec, serviceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.DataCollectionReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_datacollection.NewMockScope(mockCtrl)
+			endpointMock := mock_async.NewMockReconciler(mockCtrl)
+			ruleMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), endpointMock.EXPECT(), ruleMock.EXPECT())
+
+			s := &Service{
+				Scope:              scopeMock,
+				endpointReconciler: endpointMock,
+				ruleReconciler:     ruleMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteDataCollection(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_datacollection.MockScopeMockRecorder, e, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the cluster isn't onboarded to Azure Monitor",
+			expectedError: "",
+			expect: func(s *mock_datacollection.MockScopeMockRecorder, e, r *mock_async.MockReconcilerMockRecorder) {
+				s.DataCollectionSpecs().Return(nil, nil)
+			},
+		},
+		{
+			name:          "delete the data collection rule, then the endpoint",
+			expectedError: "",
+			expect: func(s *mock_datacollection.MockScopeMockRecorder, e, r *mock_async.MockReconcilerMockRecorder) {
+				s.DataCollectionSpecs().Return(&fakeEndpointSpec, &fakeRuleSpec)
+				gomock.InOrder(
+					r.DeleteResource(gomockinternal.AContext(), &fakeRuleSpec, serviceName).Return(nil),
+					e.DeleteResource(gomockinternal.AContext(), &fakeEndpointSpec, serviceName).Return(nil),
+				)
+				s.UpdateDeleteStatus(infrav1.DataCollectionReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "keep the endpoint if the rule fails to be deleted",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_datacollection.MockScopeMockRecorder, e, r *mock_async.MockReconcilerMockRecorder) {
+				s.DataCollectionSpecs().Return(&fakeEndpointSpec, &fakeRuleSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeRuleSpec, serviceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.DataCollectionReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_datacollection.NewMockScope(mockCtrl)
+			endpointMock := mock_async.NewMockReconciler(mockCtrl)
+			ruleMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), endpointMock.EXPECT(), ruleMock.EXPECT())
+
+			s := &Service{
+				Scope:              scopeMock,
+				endpointReconciler: endpointMock,
+				ruleReconciler:     ruleMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
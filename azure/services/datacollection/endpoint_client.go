@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datacollection
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// azureEndpointsClient contains the Azure go-sdk Client for data collection endpoints.
+type azureEndpointsClient struct {
+	endpoints *armmonitor.DataCollectionEndpointsClient
+}
+
+// newDataCollectionEndpointsClient creates a new data collection endpoints client from subscription ID.
+func newDataCollectionEndpointsClient(auth azure.Authorizer) (*azureEndpointsClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armmonitor.NewDataCollectionEndpointsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create data collection endpoints client")
+	}
+	return &azureEndpointsClient{c}, nil
+}
+
+// Get gets the specified data collection endpoint.
+func (ac *azureEndpointsClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "datacollection.azureEndpointsClient.Get")
+	defer done()
+
+	resp, err := ac.endpoints.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.DataCollectionEndpointResource, nil
+}
+
+// CreateOrUpdateAsync creates or updates a data collection endpoint.
+// Creating a data collection endpoint is not a long running operation, so we don't ever return a poller.
+func (ac *azureEndpointsClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "datacollection.azureEndpointsClient.CreateOrUpdateAsync")
+	defer done()
+
+	endpoint, ok := parameters.(armmonitor.DataCollectionEndpointResource)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not an armmonitor.DataCollectionEndpointResource", parameters)
+	}
+
+	resp, err := ac.endpoints.Create(ctx, spec.ResourceGroupName(), spec.ResourceName(), &armmonitor.DataCollectionEndpointsClientCreateOptions{Body: &endpoint})
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.DataCollectionEndpointResource, nil, nil
+}
+
+// DeleteAsync deletes a data collection endpoint.
+// Deleting a data collection endpoint is not a long running operation, so we don't ever return a poller.
+func (ac *azureEndpointsClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "datacollection.azureEndpointsClient.DeleteAsync")
+	defer done()
+
+	_, err = ac.endpoints.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	return nil, err
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datacollection
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// DataCollectionEndpointSpec defines the specification for the data collection endpoint the Azure Monitor agent of the
+// machines of a cluster fetches its configuration from.
+type DataCollectionEndpointSpec struct {
+	Name           string
+	ResourceGroup  string
+	Location       string
+	ClusterName    string
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the data collection endpoint.
+func (s *DataCollectionEndpointSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *DataCollectionEndpointSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for data collection endpoints.
+func (s *DataCollectionEndpointSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the data collection endpoint.
+func (s *DataCollectionEndpointSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(armmonitor.DataCollectionEndpointResource); !ok {
+			return nil, errors.Errorf("%T is not an armmonitor.DataCollectionEndpointResource", existing)
+		}
+
+		// Data collection endpoint already exists, nothing to update.
+		return nil, nil
+	}
+
+	return armmonitor.DataCollectionEndpointResource{
+		Location: to.Ptr(s.Location),
+		Properties: &armmonitor.DataCollectionEndpointResourceProperties{
+			Description: to.Ptr("Configuration access for the Azure Monitor agent of the machines of cluster " + s.ClusterName),
+			NetworkACLs: &armmonitor.DataCollectionEndpointNetworkACLs{
+				PublicNetworkAccess: to.Ptr(armmonitor.KnownPublicNetworkAccessOptionsEnabled),
+			},
+		},
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.Ptr(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+	}, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datacollection
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// azureRulesClient contains the Azure go-sdk Client for data collection rules.
+type azureRulesClient struct {
+	rules *armmonitor.DataCollectionRulesClient
+}
+
+// newDataCollectionRulesClient creates a new data collection rules client from subscription ID.
+func newDataCollectionRulesClient(auth azure.Authorizer) (*azureRulesClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armmonitor.NewDataCollectionRulesClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create data collection rules client")
+	}
+	return &azureRulesClient{c}, nil
+}
+
+// Get gets the specified data collection rule.
+func (ac *azureRulesClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "datacollection.azureRulesClient.Get")
+	defer done()
+
+	resp, err := ac.rules.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.DataCollectionRuleResource, nil
+}
+
+// CreateOrUpdateAsync creates or updates a data collection rule.
+// Creating a data collection rule is not a long running operation, so we don't ever return a poller.
+func (ac *azureRulesClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "datacollection.azureRulesClient.CreateOrUpdateAsync")
+	defer done()
+
+	rule, ok := parameters.(armmonitor.DataCollectionRuleResource)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not an armmonitor.DataCollectionRuleResource", parameters)
+	}
+
+	resp, err := ac.rules.Create(ctx, spec.ResourceGroupName(), spec.ResourceName(), &armmonitor.DataCollectionRulesClientCreateOptions{Body: &rule})
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.DataCollectionRuleResource, nil, nil
+}
+
+// DeleteAsync deletes a data collection rule.
+// Deleting a data collection rule is not a long running operation, so we don't ever return a poller.
+func (ac *azureRulesClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "datacollection.azureRulesClient.DeleteAsync")
+	defer done()
+
+	_, err = ac.rules.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	return nil, err
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datacollection
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+const (
+	// logAnalyticsDestination is the name of the Log Analytics destination of the data collection rule.
+	logAnalyticsDestination = "logAnalytics"
+	// perfCounterSamplingFrequencyInSeconds is how often the performance counters of the machines are sampled.
+	perfCounterSamplingFrequencyInSeconds = 60
+)
+
+// perfCounterSpecifiers are the performance counters collected on the machines of a cluster.
+var perfCounterSpecifiers = []string{
+	"\\Processor(*)\\% Processor Time",
+	"\\Memory(*)\\% Used Memory",
+	"\\Memory(*)\\Available MBytes Memory",
+	"\\Logical Disk(*)\\% Used Space",
+	"\\Logical Disk(*)\\Disk Transfers/sec",
+	"\\Network(*)\\Total Bytes Transmitted",
+	"\\Network(*)\\Total Bytes Received",
+}
+
+// DataCollectionRuleSpec defines the specification for the data collection rule sending the performance counters and
+// the syslog of the machines of a cluster to a Log Analytics workspace.
+type DataCollectionRuleSpec struct {
+	Name           string
+	ResourceGroup  string
+	Location       string
+	WorkspaceID    string
+	ClusterName    string
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the data collection rule.
+func (s *DataCollectionRuleSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *DataCollectionRuleSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for data collection rules.
+func (s *DataCollectionRuleSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the data collection rule.
+func (s *DataCollectionRuleSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		existingRule, ok := existing.(armmonitor.DataCollectionRuleResource)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armmonitor.DataCollectionRuleResource", existing)
+		}
+
+		if hasWorkspaceDestination(existingRule, s.WorkspaceID) {
+			// Skip update for the data collection rule as it already sends the data to the expected workspace.
+			return nil, nil
+		}
+	}
+
+	return armmonitor.DataCollectionRuleResource{
+		Location: to.Ptr(s.Location),
+		Properties: &armmonitor.DataCollectionRuleResourceProperties{
+			Description: to.Ptr("Performance counters and syslog of the machines of cluster " + s.ClusterName),
+			DataSources: &armmonitor.DataCollectionRuleDataSources{
+				PerformanceCounters: []*armmonitor.PerfCounterDataSource{
+					{
+						Name:                       to.Ptr("perfCounters"),
+						Streams:                    []*armmonitor.KnownPerfCounterDataSourceStreams{to.Ptr(armmonitor.KnownPerfCounterDataSourceStreamsMicrosoftPerf)},
+						SamplingFrequencyInSeconds: to.Ptr[int32](perfCounterSamplingFrequencyInSeconds),
+						CounterSpecifiers:          to.SliceOfPtrs(perfCounterSpecifiers...),
+					},
+				},
+				Syslog: []*armmonitor.SyslogDataSource{
+					{
+						Name:          to.Ptr("syslog"),
+						Streams:       []*armmonitor.KnownSyslogDataSourceStreams{to.Ptr(armmonitor.KnownSyslogDataSourceStreamsMicrosoftSyslog)},
+						FacilityNames: []*armmonitor.KnownSyslogDataSourceFacilityNames{to.Ptr(armmonitor.KnownSyslogDataSourceFacilityNamesAsterisk)},
+						LogLevels: to.SliceOfPtrs(
+							armmonitor.KnownSyslogDataSourceLogLevelsWarning,
+							armmonitor.KnownSyslogDataSourceLogLevelsError,
+							armmonitor.KnownSyslogDataSourceLogLevelsCritical,
+							armmonitor.KnownSyslogDataSourceLogLevelsAlert,
+							armmonitor.KnownSyslogDataSourceLogLevelsEmergency,
+						),
+					},
+				},
+			},
+			Destinations: &armmonitor.DataCollectionRuleDestinations{
+				LogAnalytics: []*armmonitor.LogAnalyticsDestination{
+					{
+						Name:                to.Ptr(logAnalyticsDestination),
+						WorkspaceResourceID: to.Ptr(s.WorkspaceID),
+					},
+				},
+			},
+			DataFlows: []*armmonitor.DataFlow{
+				{
+					Streams:      to.SliceOfPtrs(armmonitor.KnownDataFlowStreamsMicrosoftPerf, armmonitor.KnownDataFlowStreamsMicrosoftSyslog),
+					Destinations: []*string{to.Ptr(logAnalyticsDestination)},
+				},
+			},
+		},
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.Ptr(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+	}, nil
+}
+
+// hasWorkspaceDestination returns true if the data collection rule sends its data to the given Log Analytics workspace.
+func hasWorkspaceDestination(rule armmonitor.DataCollectionRuleResource, workspaceID string) bool {
+	if rule.Properties == nil || rule.Properties.Destinations == nil {
+		return false
+	}
+
+	for _, destination := range rule.Properties.Destinations.LogAnalytics {
+		if destination != nil && strings.EqualFold(pointer.StringDeref(destination.WorkspaceResourceID, ""), workspaceID) {
+			return true
+		}
+	}
+	return false
+}
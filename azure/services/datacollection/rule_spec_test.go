@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datacollection
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	. "github.com/onsi/gomega"
+)
+
+func TestRuleParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := fakeRuleSpec
+	params, err := spec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	rule, ok := params.(armmonitor.DataCollectionRuleResource)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(rule.Properties.Destinations.LogAnalytics).To(Equal([]*armmonitor.LogAnalyticsDestination{
+		{
+			Name:                to.Ptr(logAnalyticsDestination),
+			WorkspaceResourceID: to.Ptr(spec.WorkspaceID),
+		},
+	}))
+	g.Expect(rule.Properties.DataSources.PerformanceCounters).To(HaveLen(1))
+	g.Expect(rule.Properties.DataSources.Syslog).To(HaveLen(1))
+	g.Expect(rule.Properties.DataFlows).To(Equal([]*armmonitor.DataFlow{
+		{
+			Streams:      to.SliceOfPtrs(armmonitor.KnownDataFlowStreamsMicrosoftPerf, armmonitor.KnownDataFlowStreamsMicrosoftSyslog),
+			Destinations: []*string{to.Ptr(logAnalyticsDestination)},
+		},
+	}))
+
+	// An existing rule sending the data to the same workspace is left untouched, whatever the case of its ID.
+	rule.Properties.Destinations.LogAnalytics[0].WorkspaceResourceID = to.Ptr(strings.ToLower(spec.WorkspaceID))
+	params, err = spec.Parameters(rule)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).To(BeNil())
+
+	// A rule sending the data to another workspace is updated.
+	changed := spec
+	changed.WorkspaceID = "/subscriptions/123/resourceGroups/monitoring/providers/Microsoft.OperationalInsights/workspaces/shared"
+	params, err = changed.Parameters(rule)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).NotTo(BeNil())
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datacollectionruleassociations
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	associations *armmonitor.DataCollectionRuleAssociationsClient
+}
+
+// newClient creates a new data collection rule associations client from subscription ID.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armmonitor.NewDataCollectionRuleAssociationsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create data collection rule associations client")
+	}
+	return &azureClient{c}, nil
+}
+
+// Get gets the specified association of a resource.
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "datacollectionruleassociations.azureClient.Get")
+	defer done()
+
+	resp, err := ac.associations.Get(ctx, spec.OwnerResourceName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.DataCollectionRuleAssociationProxyOnlyResource, nil
+}
+
+// CreateOrUpdateAsync creates or updates an association of a resource.
+// Creating an association is not a long running operation, so we don't ever return a poller.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "datacollectionruleassociations.azureClient.CreateOrUpdateAsync")
+	defer done()
+
+	association, ok := parameters.(armmonitor.DataCollectionRuleAssociationProxyOnlyResource)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not an armmonitor.DataCollectionRuleAssociationProxyOnlyResource", parameters)
+	}
+
+	opts := &armmonitor.DataCollectionRuleAssociationsClientCreateOptions{Body: &association}
+	resp, err := ac.associations.Create(ctx, spec.OwnerResourceName(), spec.ResourceName(), opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.DataCollectionRuleAssociationProxyOnlyResource, nil, nil
+}
+
+// DeleteAsync is a no-op for associations as they are deleted along with the resource they belong to.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
+	return nil, nil
+}
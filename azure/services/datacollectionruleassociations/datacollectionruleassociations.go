@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datacollectionruleassociations
+
+import (
+	"context"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "datacollectionruleassociations"
+
+// DataCollectionRuleAssociationScope defines the scope interface for a data collection rule associations service.
+type DataCollectionRuleAssociationScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	DataCollectionRuleAssociationSpecs() []azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope DataCollectionRuleAssociationScope
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope DataCollectionRuleAssociationScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:      scope,
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile associates the virtual machine or scale set with the data collection rule and endpoint of the cluster, so
+// that its Azure Monitor agent sends its metrics and logs to the Log Analytics workspace of the cluster.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "datacollectionruleassociations.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.DataCollectionRuleAssociationSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	// We go through the list of DataCollectionRuleAssociationSpecs to reconcile each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+	var result error
+	for _, associationSpec := range specs {
+		if _, err := s.CreateResource(ctx, associationSpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.DataCollectionRuleAssociationReadyCondition, serviceName, result)
+	return result
+}
+
+// Delete is a no-op as the associations get deleted as part of the deletion of the virtual machine or scale set.
+func (s *Service) Delete(ctx context.Context) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "datacollectionruleassociations.Service.Delete")
+	defer done()
+	return nil
+}
+
+// IsManaged always returns true as the associations are always created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datacollectionruleassociations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/datacollectionruleassociations/mock_datacollectionruleassociations"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeRuleAssociationSpec = DataCollectionRuleAssociationSpec{
+		Name:                 "my-cluster-dcr",
+		ResourceGroup:        "my-rg",
+		ResourceID:           "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm",
+		DataCollectionRuleID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Insights/dataCollectionRules/my-cluster-dcr",
+	}
+	fakeEndpointAssociationSpec = DataCollectionRuleAssociationSpec{
+		Name:                     EndpointAssociationName,
+		ResourceGroup:            "my-rg",
+		ResourceID:               "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm",
+		DataCollectionEndpointID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Insights/dataCollectionEndpoints/my-cluster-dce",
+	}
+
+	internalError = errors.New("Internal Server Error")
+)
+
+func TestReconcileDataCollectionRuleAssociations(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_datacollectionruleassociations.MockDataCollectionRuleAssociationScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the cluster isn't onboarded to Azure Monitor",
+			expectedError: "",
+			expect: func(s *mock_datacollectionruleassociations.MockDataCollectionRuleAssociationScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DataCollectionRuleAssociationSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "associate the machine with the data collection rule and endpoint",
+			expectedError: "",
+			expect: func(s *mock_datacollectionruleassociations.MockDataCollectionRuleAssociationScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DataCollectionRuleAssociationSpecs().Return([]azure.ResourceSpecGetter{&fakeRuleAssociationSpec, &fakeEndpointAssociationSpec})
+				r.CreateResource(gomockinternal.AContext(), &fakeRuleAssociationSpec, serviceName).Return(nil, nil)
+				r.CreateResource(gomockinternal.AContext(), &fakeEndpointAssociationSpec, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.DataCollectionRuleAssociationReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "associate the machine with the endpoint even if the association with the rule fails",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_datacollectionruleassociations.MockDataCollectionRuleAssociationScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DataCollectionRuleAssociationSpecs().Return([]azure.ResourceSpecGetter{&fakeRuleAssociationSpec, &fakeEndpointAssociationSpec})
+				r.CreateResource(gomockinternal.AContext(), &fakeRuleAssociationSpec, serviceName).Return(nil, internalError)
+				r.CreateResource(gomockinternal.AContext(), &fakeEndpointAssociationSpec, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.DataCollectionRuleAssociationReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_datacollectionruleassociations.NewMockDataCollectionRuleAssociationScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
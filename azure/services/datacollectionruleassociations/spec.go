@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datacollectionruleassociations
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+)
+
+// EndpointAssociationName is the name Azure Monitor requires for the association of a resource with the data
+// collection endpoint its agent fetches its configuration from.
+const EndpointAssociationName = "configurationAccessEndpoint"
+
+// DataCollectionRuleAssociationSpec defines the specification for the association of a virtual machine or scale set
+// with either a data collection rule or a data collection endpoint.
+type DataCollectionRuleAssociationSpec struct {
+	Name                     string
+	ResourceGroup            string
+	ResourceID               string
+	DataCollectionRuleID     string
+	DataCollectionEndpointID string
+}
+
+// ResourceName returns the name of the association.
+func (s *DataCollectionRuleAssociationSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the associated resource.
+func (s *DataCollectionRuleAssociationSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName returns the resource ID of the associated resource.
+func (s *DataCollectionRuleAssociationSpec) OwnerResourceName() string {
+	return s.ResourceID
+}
+
+// Parameters returns the parameters for the association.
+func (s *DataCollectionRuleAssociationSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		existingAssociation, ok := existing.(armmonitor.DataCollectionRuleAssociationProxyOnlyResource)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armmonitor.DataCollectionRuleAssociationProxyOnlyResource", existing)
+		}
+
+		if props := existingAssociation.Properties; props != nil &&
+			strings.EqualFold(pointer.StringDeref(props.DataCollectionRuleID, ""), s.DataCollectionRuleID) &&
+			strings.EqualFold(pointer.StringDeref(props.DataCollectionEndpointID, ""), s.DataCollectionEndpointID) {
+			// Skip update for the association as it exists with expected values
+			return nil, nil
+		}
+	}
+
+	props := &armmonitor.DataCollectionRuleAssociationProxyOnlyResourceProperties{}
+	if s.DataCollectionRuleID != "" {
+		props.DataCollectionRuleID = to.Ptr(s.DataCollectionRuleID)
+	}
+	if s.DataCollectionEndpointID != "" {
+		props.DataCollectionEndpointID = to.Ptr(s.DataCollectionEndpointID)
+	}
+
+	return armmonitor.DataCollectionRuleAssociationProxyOnlyResource{
+		Properties: props,
+	}, nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datacollectionruleassociations
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	. "github.com/onsi/gomega"
+)
+
+func TestParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	params, err := fakeRuleAssociationSpec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	association, ok := params.(armmonitor.DataCollectionRuleAssociationProxyOnlyResource)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(*association.Properties.DataCollectionRuleID).To(Equal(fakeRuleAssociationSpec.DataCollectionRuleID))
+	g.Expect(association.Properties.DataCollectionEndpointID).To(BeNil())
+
+	params, err = fakeEndpointAssociationSpec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	endpointAssociation, ok := params.(armmonitor.DataCollectionRuleAssociationProxyOnlyResource)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(endpointAssociation.Properties.DataCollectionRuleID).To(BeNil())
+	g.Expect(*endpointAssociation.Properties.DataCollectionEndpointID).To(Equal(fakeEndpointAssociationSpec.DataCollectionEndpointID))
+
+	// An existing association with the same rule is left untouched.
+	params, err = fakeRuleAssociationSpec.Parameters(association)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).To(BeNil())
+
+	// An association with another rule is updated.
+	params, err = fakeRuleAssociationSpec.Parameters(endpointAssociation)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).NotTo(BeNil())
+}
@@ -19,73 +19,100 @@ package disks
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest"
-	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // azureClient contains the Azure go-sdk Client.
 type azureClient struct {
-	disks compute.DisksClient
+	disks *armcompute.DisksClient
 }
 
 // newClient creates a new disk Client from subscription ID.
-func newClient(auth azure.Authorizer) *azureClient {
-	c := NewDisksClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
-	return &azureClient{c}
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armcompute.NewDisksClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create disks client")
+	}
+	return &azureClient{c}, nil
 }
 
-// NewDisksClient creates a new disks Client from subscription ID.
-func NewDisksClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.DisksClient {
-	disksClient := compute.NewDisksClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&disksClient.Client, authorizer)
-	return disksClient
+// Get gets the specified disk.
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.azureClient.Get")
+	defer done()
+
+	resp, err := ac.disks.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Disk, nil
 }
 
-// DeleteAsync deletes a route table asynchronously. DeleteAsync sends a DELETE
-// request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// CreateOrUpdateAsync creates or updates a disk asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
 // progress of the operation.
-func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (future azureautorest.FutureAPI, err error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.azureClient.DeleteAsync")
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.azureClient.CreateOrUpdateAsync")
 	defer done()
 
-	deleteFuture, err := ac.disks.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	disk, ok := parameters.(armcompute.Disk)
+	if !ok && parameters != nil {
+		return nil, nil, errors.Errorf("%T is not an armcompute.Disk", parameters)
+	}
+
+	opts := &armcompute.DisksClientBeginCreateOrUpdateOptions{ResumeToken: resumeToken}
+	createPoller, err := ac.disks.BeginCreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), disk, opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
 	defer cancel()
 
-	err = deleteFuture.WaitForCompletionRef(ctx, ac.disks.Client)
+	resp, err := createPoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
 	if err != nil {
-		// if an error occurs, return the future.
+		// if an error occurs, return the poller.
 		// this means the long-running operation didn't finish in the specified timeout.
-		return &deleteFuture, err
+		return nil, createPoller, err
 	}
-	_, err = deleteFuture.Result(ac.disks)
-	// if the operation completed, return a nil future.
-	return nil, err
-}
 
-// Result fetches the result of a long-running operation future.
-func (ac *azureClient) Result(ctx context.Context, future azureautorest.FutureAPI, futureType string) (result interface{}, err error) {
-	return nil, nil
+	// if the operation completed, return a nil poller
+	return resp.Disk, nil, nil
 }
 
-// IsDone returns true if the long-running operation has completed.
-func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (isDone bool, err error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.azureClient.IsDone")
+// DeleteAsync deletes a disk asynchronously. DeleteAsync sends a DELETE
+// request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.azureClient.DeleteAsync")
 	defer done()
 
-	isDone, err = future.DoneWithContext(ctx, ac.disks)
+	opts := &armcompute.DisksClientBeginDeleteOptions{ResumeToken: resumeToken}
+	deletePoller, err := ac.disks.BeginDelete(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
 	if err != nil {
-		return false, errors.Wrap(err, "failed checking if the operation was complete")
+		return nil, err
 	}
 
-	return isDone, nil
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	_, err = deletePoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return deletePoller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, nil
 }
@@ -33,6 +33,7 @@ type DiskScope interface {
 	azure.ClusterDescriber
 	azure.AsyncStatusUpdater
 	DiskSpecs() []azure.ResourceSpecGetter
+	DiskCreateSpecs() []azure.ResourceSpecGetter
 }
 
 // Service provides operations on Azure resources.
@@ -42,12 +43,15 @@ type Service struct {
 }
 
 // New creates a new disks service.
-func New(scope DiskScope) *Service {
-	client := newClient(scope)
+func New(scope DiskScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:      scope,
-		Reconciler: async.New(scope, nil, client),
-	}
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
 }
 
 // Name returns the service name.
@@ -57,11 +61,32 @@ func (s *Service) Name() string {
 
 // Reconcile on disk is currently no-op. OS disks should only be deleted and will create with the VM automatically.
 func (s *Service) Reconcile(ctx context.Context) error {
-	_, _, done := tele.StartSpanWithLogger(ctx, "disks.Service.Reconcile")
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "disks.Service.Reconcile")
 	defer done()
 
-	// DisksReadyCondition is set in the VM service.
-	return nil
+	// Disks are created with their VM, unless they are created from a snapshot. DisksReadyCondition is set in the VM
+	// service otherwise.
+	specs := s.Scope.DiskCreateSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	// We go through the list of DiskSpecs to create each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+	var result error
+	for _, diskSpec := range specs {
+		if _, err := s.CreateResource(ctx, diskSpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+	s.Scope.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, result)
+	return result
 }
 
 // Delete deletes the disk associated with a VM.
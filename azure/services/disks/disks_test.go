@@ -21,13 +21,15 @@ import (
 	"net/http"
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks/mock_disks"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
@@ -47,9 +49,110 @@ var (
 		&diskSpec2,
 	}
 
-	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+	osDiskFromSnapshotSpec = DiskSpec{
+		Name:             "my-vm_OSDisk",
+		ResourceGroup:    "my-group",
+		Location:         "westus",
+		ClusterName:      "my-cluster",
+		OSType:           "Linux",
+		SourceSnapshotID: "/subscriptions/123/resourceGroups/my-group/providers/Microsoft.Compute/snapshots/my-snapshot",
+	}
+
+	internalError = test.NewResponseError(http.StatusInternalServerError)
 )
 
+func TestReconcileDisk(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no disk is created before the VM",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiskCreateSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "create the OS disk from a snapshot",
+			expectedError: "",
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiskCreateSpecs().Return([]azure.ResourceSpecGetter{&osDiskFromSnapshotSpec})
+				gomock.InOrder(
+					r.CreateResource(gomockinternal.AContext(), &osDiskFromSnapshotSpec, serviceName).Return(nil, nil),
+					s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, nil),
+				)
+			},
+		},
+		{
+			name:          "error while trying to create the OS disk",
+			expectedError: internalError.Error(),
+			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DiskCreateSpecs().Return([]azure.ResourceSpecGetter{&osDiskFromSnapshotSpec})
+				gomock.InOrder(
+					r.CreateResource(gomockinternal.AContext(), &osDiskFromSnapshotSpec, serviceName).Return(nil, internalError),
+					s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, internalError),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_disks.NewMockDiskScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDiskSpecParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := osDiskFromSnapshotSpec
+	spec.Zone = "2"
+	spec.StorageAccountType = "Premium_LRS"
+	params, err := spec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	disk, ok := params.(armcompute.Disk)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(disk.Zones).To(Equal([]*string{to.Ptr("2")}))
+	g.Expect(disk.SKU.Name).To(Equal(to.Ptr(armcompute.DiskStorageAccountTypesPremiumLRS)))
+	g.Expect(disk.Properties.OSType).To(Equal(to.Ptr(armcompute.OperatingSystemTypesLinux)))
+	g.Expect(disk.Properties.CreationData.CreateOption).To(Equal(to.Ptr(armcompute.DiskCreateOptionCopy)))
+	g.Expect(disk.Properties.CreationData.SourceResourceID).To(Equal(to.Ptr(osDiskFromSnapshotSpec.SourceSnapshotID)))
+	g.Expect(disk.Properties.Encryption).To(BeNil())
+
+	params, err = spec.Parameters(armcompute.Disk{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).To(BeNil())
+
+	params, err = diskSpec1.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).To(BeNil())
+}
+
 func TestDeleteDisk(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -89,7 +192,7 @@ func TestDeleteDisk(t *testing.T) {
 		},
 		{
 			name:          "error while trying to delete the disk",
-			expectedError: "#: Internal Server Error: StatusCode=500",
+			expectedError: internalError.Error(),
 			expect: func(s *mock_disks.MockDiskScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.DiskSpecs().Return(fakeDiskSpecs)
 				gomock.InOrder(
@@ -23,7 +23,7 @@ package mock_disks
 import (
 	reflect "reflect"
 
-	autorest "github.com/Azure/go-autorest/autorest"
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	gomock "github.com/golang/mock/gomock"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -67,18 +67,18 @@ func (mr *MockDiskScopeMockRecorder) AdditionalTags() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockDiskScope)(nil).AdditionalTags))
 }
 
-// Authorizer mocks base method.
-func (m *MockDiskScope) Authorizer() autorest.Authorizer {
+// AttachedACRs mocks base method.
+func (m *MockDiskScope) AttachedACRs() []string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authorizer")
-	ret0, _ := ret[0].(autorest.Authorizer)
+	ret := m.ctrl.Call(m, "AttachedACRs")
+	ret0, _ := ret[0].([]string)
 	return ret0
 }
 
-// Authorizer indicates an expected call of Authorizer.
-func (mr *MockDiskScopeMockRecorder) Authorizer() *gomock.Call {
+// AttachedACRs indicates an expected call of AttachedACRs.
+func (mr *MockDiskScopeMockRecorder) AttachedACRs() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockDiskScope)(nil).Authorizer))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachedACRs", reflect.TypeOf((*MockDiskScope)(nil).AttachedACRs))
 }
 
 // AvailabilitySetEnabled mocks base method.
@@ -109,6 +109,34 @@ func (mr *MockDiskScopeMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockDiskScope)(nil).BaseURI))
 }
 
+// BootstrapDataStorage mocks base method.
+func (m *MockDiskScope) BootstrapDataStorage() *v1beta1.BootstrapDataStorage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorage")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataStorage)
+	return ret0
+}
+
+// BootstrapDataStorage indicates an expected call of BootstrapDataStorage.
+func (mr *MockDiskScopeMockRecorder) BootstrapDataStorage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorage", reflect.TypeOf((*MockDiskScope)(nil).BootstrapDataStorage))
+}
+
+// BootstrapExtension mocks base method.
+func (m *MockDiskScope) BootstrapExtension() *v1beta1.BootstrapExtension {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapExtension")
+	ret0, _ := ret[0].(*v1beta1.BootstrapExtension)
+	return ret0
+}
+
+// BootstrapExtension indicates an expected call of BootstrapExtension.
+func (mr *MockDiskScopeMockRecorder) BootstrapExtension() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapExtension", reflect.TypeOf((*MockDiskScope)(nil).BootstrapExtension))
+}
+
 // ClientID mocks base method.
 func (m *MockDiskScope) ClientID() string {
 	m.ctrl.T.Helper()
@@ -179,6 +207,20 @@ func (mr *MockDiskScopeMockRecorder) ClusterName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockDiskScope)(nil).ClusterName))
 }
 
+// CrossSubscriptionAccess mocks base method.
+func (m *MockDiskScope) CrossSubscriptionAccess() v1beta1.CrossSubscriptionAccessPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CrossSubscriptionAccess")
+	ret0, _ := ret[0].(v1beta1.CrossSubscriptionAccessPolicy)
+	return ret0
+}
+
+// CrossSubscriptionAccess indicates an expected call of CrossSubscriptionAccess.
+func (mr *MockDiskScopeMockRecorder) CrossSubscriptionAccess() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CrossSubscriptionAccess", reflect.TypeOf((*MockDiskScope)(nil).CrossSubscriptionAccess))
+}
+
 // DeleteLongRunningOperationState mocks base method.
 func (m *MockDiskScope) DeleteLongRunningOperationState(arg0, arg1 string) {
 	m.ctrl.T.Helper()
@@ -191,6 +233,20 @@ func (mr *MockDiskScopeMockRecorder) DeleteLongRunningOperationState(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockDiskScope)(nil).DeleteLongRunningOperationState), arg0, arg1)
 }
 
+// DiskCreateSpecs mocks base method.
+func (m *MockDiskScope) DiskCreateSpecs() []azure.ResourceSpecGetter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiskCreateSpecs")
+	ret0, _ := ret[0].([]azure.ResourceSpecGetter)
+	return ret0
+}
+
+// DiskCreateSpecs indicates an expected call of DiskCreateSpecs.
+func (mr *MockDiskScopeMockRecorder) DiskCreateSpecs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiskCreateSpecs", reflect.TypeOf((*MockDiskScope)(nil).DiskCreateSpecs))
+}
+
 // DiskSpecs mocks base method.
 func (m *MockDiskScope) DiskSpecs() []azure.ResourceSpecGetter {
 	m.ctrl.T.Helper()
@@ -205,6 +261,20 @@ func (mr *MockDiskScopeMockRecorder) DiskSpecs() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiskSpecs", reflect.TypeOf((*MockDiskScope)(nil).DiskSpecs))
 }
 
+// ExtendedLocation mocks base method.
+func (m *MockDiskScope) ExtendedLocation() *v1beta1.ExtendedLocationSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendedLocation")
+	ret0, _ := ret[0].(*v1beta1.ExtendedLocationSpec)
+	return ret0
+}
+
+// ExtendedLocation indicates an expected call of ExtendedLocation.
+func (mr *MockDiskScopeMockRecorder) ExtendedLocation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendedLocation", reflect.TypeOf((*MockDiskScope)(nil).ExtendedLocation))
+}
+
 // FailureDomains mocks base method.
 func (m *MockDiskScope) FailureDomains() []string {
 	m.ctrl.T.Helper()
@@ -261,6 +331,48 @@ func (mr *MockDiskScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockDiskScope)(nil).Location))
 }
 
+// Monitoring mocks base method.
+func (m *MockDiskScope) Monitoring() *v1beta1.AzureMonitoring {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Monitoring")
+	ret0, _ := ret[0].(*v1beta1.AzureMonitoring)
+	return ret0
+}
+
+// Monitoring indicates an expected call of Monitoring.
+func (mr *MockDiskScopeMockRecorder) Monitoring() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Monitoring", reflect.TypeOf((*MockDiskScope)(nil).Monitoring))
+}
+
+// ProxyConfig mocks base method.
+func (m *MockDiskScope) ProxyConfig() *v1beta1.ProxyConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyConfig")
+	ret0, _ := ret[0].(*v1beta1.ProxyConfig)
+	return ret0
+}
+
+// ProxyConfig indicates an expected call of ProxyConfig.
+func (mr *MockDiskScopeMockRecorder) ProxyConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyConfig", reflect.TypeOf((*MockDiskScope)(nil).ProxyConfig))
+}
+
+// RegistryMirrors mocks base method.
+func (m *MockDiskScope) RegistryMirrors() []v1beta1.RegistryMirror {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryMirrors")
+	ret0, _ := ret[0].([]v1beta1.RegistryMirror)
+	return ret0
+}
+
+// RegistryMirrors indicates an expected call of RegistryMirrors.
+func (mr *MockDiskScopeMockRecorder) RegistryMirrors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryMirrors", reflect.TypeOf((*MockDiskScope)(nil).RegistryMirrors))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockDiskScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -275,6 +387,20 @@ func (mr *MockDiskScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockDiskScope)(nil).ResourceGroup))
 }
 
+// SecurityProfile mocks base method.
+func (m *MockDiskScope) SecurityProfile() *v1beta1.ClusterSecurityProfile {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecurityProfile")
+	ret0, _ := ret[0].(*v1beta1.ClusterSecurityProfile)
+	return ret0
+}
+
+// SecurityProfile indicates an expected call of SecurityProfile.
+func (mr *MockDiskScopeMockRecorder) SecurityProfile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityProfile", reflect.TypeOf((*MockDiskScope)(nil).SecurityProfile))
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockDiskScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -315,6 +441,20 @@ func (mr *MockDiskScopeMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockDiskScope)(nil).TenantID))
 }
 
+// Token mocks base method.
+func (m *MockDiskScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockDiskScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockDiskScope)(nil).Token))
+}
+
 // UpdateDeleteStatus mocks base method.
 func (m *MockDiskScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
 	m.ctrl.T.Helper()
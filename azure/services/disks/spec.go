@@ -16,10 +16,29 @@ limitations under the License.
 
 package disks
 
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
 // DiskSpec defines the specification for a disk.
 type DiskSpec struct {
 	Name          string
 	ResourceGroup string
+	// The following fields are only set for disks created from a snapshot before their VM.
+	Location            string
+	ExtendedLocation    *infrav1.ExtendedLocationSpec
+	Zone                string
+	ClusterName         string
+	OSType              string
+	SourceSnapshotID    string
+	DiskSizeGB          *int32
+	StorageAccountType  string
+	DiskEncryptionSetID string
+	AdditionalTags      infrav1.Tags
 }
 
 // ResourceName returns the name of the disk.
@@ -37,7 +56,53 @@ func (s *DiskSpec) OwnerResourceName() string {
 	return ""
 }
 
-// Parameters is a no-op for disks.
+// Parameters returns the parameters for a disk created from a snapshot. It is a no-op for other disks, which are
+// created with their VM.
 func (s *DiskSpec) Parameters(existing interface{}) (params interface{}, err error) {
-	return nil, nil
+	if existing != nil {
+		if _, ok := existing.(armcompute.Disk); !ok {
+			return nil, errors.Errorf("%T is not an armcompute.Disk", existing)
+		}
+		// disk already exists
+		return nil, nil
+	}
+
+	if s.SourceSnapshotID == "" {
+		return nil, nil
+	}
+
+	disk := armcompute.Disk{
+		Location:         to.Ptr(s.Location),
+		ExtendedLocation: converters.ExtendedLocationToComputeSDK(s.ExtendedLocation),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.Ptr(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+		Properties: &armcompute.DiskProperties{
+			OSType: to.Ptr(armcompute.OperatingSystemTypes(s.OSType)),
+			CreationData: &armcompute.CreationData{
+				CreateOption:     to.Ptr(armcompute.DiskCreateOptionCopy),
+				SourceResourceID: to.Ptr(s.SourceSnapshotID),
+			},
+			DiskSizeGB: s.DiskSizeGB,
+		},
+	}
+	if s.Zone != "" {
+		disk.Zones = []*string{to.Ptr(s.Zone)}
+	}
+	if s.StorageAccountType != "" {
+		disk.SKU = &armcompute.DiskSKU{
+			Name: to.Ptr(armcompute.DiskStorageAccountTypes(s.StorageAccountType)),
+		}
+	}
+	if s.DiskEncryptionSetID != "" {
+		disk.Properties.Encryption = &armcompute.Encryption{
+			DiskEncryptionSetID: to.Ptr(s.DiskEncryptionSetID),
+			Type:                to.Ptr(armcompute.EncryptionTypeEncryptionAtRestWithCustomerKey),
+		}
+	}
+
+	return disk, nil
 }
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsresolvers
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// apiVersion is the version of the Azure DNS Private Resolver API used to manage the DNS private resolvers, their
+// outbound endpoints and the DNS forwarding rulesets. The Azure SDK doesn't provide a client for DNS private resolvers,
+// so they are managed as generic resources.
+const apiVersion = "2022-07-01"
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	resources *armresources.Client
+}
+
+// newClient creates a new DNS private resolvers client from subscription ID.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armresources.NewClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create resources client")
+	}
+	return &azureClient{c}, nil
+}
+
+// resourceIDGetter is implemented by the specs of the DNS private resolver resources, which are identified by their
+// resource ID as they are managed as generic resources.
+type resourceIDGetter interface {
+	ResourceID() string
+}
+
+// resourceID returns the resource ID of the DNS private resolver resource described by the spec.
+func resourceID(spec azure.ResourceSpecGetter) (string, error) {
+	idGetter, ok := spec.(resourceIDGetter)
+	if !ok {
+		return "", errors.Errorf("%T is not a DNS private resolver resource spec", spec)
+	}
+	return idGetter.ResourceID(), nil
+}
+
+// Get gets the specified DNS private resolver resource.
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "dnsresolvers.azureClient.Get")
+	defer done()
+
+	id, err := resourceID(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ac.resources.GetByID(ctx, id, apiVersion, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GenericResource, nil
+}
+
+// CreateOrUpdateAsync creates or updates a DNS private resolver resource asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "dnsresolvers.azureClient.CreateOrUpdateAsync")
+	defer done()
+
+	resource, ok := parameters.(armresources.GenericResource)
+	if !ok && parameters != nil {
+		return nil, nil, errors.Errorf("%T is not an armresources.GenericResource", parameters)
+	}
+
+	id, err := resourceID(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts := &armresources.ClientBeginCreateOrUpdateByIDOptions{ResumeToken: resumeToken}
+	createPoller, err := ac.resources.BeginCreateOrUpdateByID(ctx, id, apiVersion, resource, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	resp, err := createPoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, createPoller, err
+	}
+
+	// if the operation completed, return a nil poller
+	return resp.GenericResource, nil, nil
+}
+
+// DeleteAsync deletes a DNS private resolver resource asynchronously. DeleteAsync sends a DELETE
+// request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "dnsresolvers.azureClient.DeleteAsync")
+	defer done()
+
+	id, err := resourceID(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &armresources.ClientBeginDeleteByIDOptions{ResumeToken: resumeToken}
+	deletePoller, err := ac.resources.BeginDeleteByID(ctx, id, apiVersion, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	_, err = deletePoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return deletePoller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsresolvers
+
+import (
+	"context"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// ServiceName is the name of the DNS private resolvers service.
+	ServiceName = "dnsresolvers"
+	// SubnetDelegation is the service the outbound endpoint subnet of a DNS private resolver must be delegated to.
+	SubnetDelegation = "Microsoft.Network/dnsResolvers"
+)
+
+// DNSResolverScope defines the scope interface for a DNS private resolvers service.
+type DNSResolverScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	DNSResolverSpecs() []azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope DNSResolverScope
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope DNSResolverScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:      scope,
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the DNS private resolver, or to link an existing DNS forwarding ruleset to the virtual network.
+func (s *Service) RequiredActions() []string {
+	specs := s.Scope.DNSResolverSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+	actions := []string{"Microsoft.Network/virtualNetworks/join/action"}
+	for _, spec := range specs {
+		if _, ok := spec.(*DNSResolverSpec); ok {
+			actions = append(actions,
+				"Microsoft.Network/dnsResolvers/read",
+				"Microsoft.Network/dnsResolvers/write",
+				"Microsoft.Network/dnsResolvers/outboundEndpoints/read",
+				"Microsoft.Network/dnsResolvers/outboundEndpoints/write",
+				"Microsoft.Network/dnsForwardingRulesets/read",
+				"Microsoft.Network/dnsForwardingRulesets/write",
+				"Microsoft.Network/dnsForwardingRulesets/forwardingRules/read",
+				"Microsoft.Network/dnsForwardingRulesets/forwardingRules/write",
+				"Microsoft.Network/dnsForwardingRulesets/virtualNetworkLinks/read",
+				"Microsoft.Network/dnsForwardingRulesets/virtualNetworkLinks/write",
+				"Microsoft.Network/virtualNetworks/subnets/join/action",
+			)
+			break
+		}
+	}
+	return actions
+}
+
+// Reconcile creates or updates the DNS private resolver, its outbound endpoint, its DNS forwarding ruleset and rules,
+// and the link of the ruleset to the virtual network of the cluster, or only the link of an existing ruleset.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "dnsresolvers.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.DNSResolverSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	// Each resource references the previous ones, e.g. the outbound endpoint its DNS private resolver, so the resources
+	// are created in order, and the reconciliation stops at the first one that isn't done.
+	var result error
+	for _, spec := range specs {
+		if _, err := s.CreateResource(ctx, spec, ServiceName); err != nil {
+			result = err
+			break
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.DNSResolverReadyCondition, ServiceName, result)
+	return result
+}
+
+// Delete deletes the resources of the DNS private resolver in the reverse order of their creation.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "dnsresolvers.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.DNSResolverSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	var result error
+	for i := len(specs) - 1; i >= 0; i-- {
+		if err := s.DeleteResource(ctx, specs[i], ServiceName); err != nil {
+			result = err
+			break
+		}
+	}
+
+	s.Scope.UpdateDeleteStatus(infrav1.DNSResolverReadyCondition, ServiceName, result)
+	return result
+}
+
+// IsManaged always returns true as the DNS private resolver resources, or the link of an existing DNS
+// forwarding ruleset, only exist because CAPZ created them.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
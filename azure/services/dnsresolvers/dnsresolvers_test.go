@@ -0,0 +1,239 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsresolvers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsresolvers/mock_dnsresolvers"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeResolverSpec = DNSResolverSpec{
+		Name:           "my-cluster-dns-resolver",
+		ResourceGroup:  "my-rg",
+		SubscriptionID: "123",
+		ClusterName:    "my-cluster",
+		Location:       "westus",
+		VNetID:         "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+	}
+	fakeOutboundEndpointSpec = OutboundEndpointSpec{
+		Name:           "my-cluster-dns-resolver",
+		ResourceGroup:  "my-rg",
+		SubscriptionID: "123",
+		ClusterName:    "my-cluster",
+		Location:       "westus",
+		ResolverName:   "my-cluster-dns-resolver",
+		SubnetID:       "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/dns-resolver-outbound-subnet",
+	}
+	fakeRulesetSpec = ForwardingRulesetSpec{
+		Name:               "my-cluster-dns-resolver",
+		ResourceGroup:      "my-rg",
+		SubscriptionID:     "123",
+		ClusterName:        "my-cluster",
+		Location:           "westus",
+		OutboundEndpointID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/dnsResolvers/my-cluster-dns-resolver/outboundEndpoints/my-cluster-dns-resolver",
+	}
+	fakeRuleSpec = ForwardingRuleSpec{
+		Name:                 "on-prem",
+		RulesetID:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/dnsForwardingRulesets/my-cluster-dns-resolver",
+		RulesetName:          "my-cluster-dns-resolver",
+		RulesetResourceGroup: "my-rg",
+		DomainName:           "corp.example.com",
+		TargetDNSServers: []infrav1.TargetDNSServer{
+			{IPAddress: "10.1.0.4"},
+			{IPAddress: "10.1.0.5", Port: pointer.Int32(5353)},
+		},
+	}
+	fakeLinkSpec = VirtualNetworkLinkSpec{
+		Name:                 "my-rg-my-vnet",
+		RulesetID:            "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/dnsForwardingRulesets/my-cluster-dns-resolver",
+		RulesetName:          "my-cluster-dns-resolver",
+		RulesetResourceGroup: "my-rg",
+		VNetID:               "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet",
+	}
+	fakeSpecs = []azure.ResourceSpecGetter{&fakeResolverSpec, &fakeOutboundEndpointSpec, &fakeRulesetSpec, &fakeRuleSpec, &fakeLinkSpec}
+
+	internalError = errors.New("Internal Server Error")
+	notDoneError  = azure.NewOperationNotDoneError(&infrav1.Future{})
+)
+
+func TestReconcileDNSResolver(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_dnsresolvers.MockDNSResolverScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the cluster has no DNS private resolver",
+			expectedError: "",
+			expect: func(s *mock_dnsresolvers.MockDNSResolverScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSResolverSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "create the DNS private resolver and its forwarding ruleset",
+			expectedError: "",
+			expect: func(s *mock_dnsresolvers.MockDNSResolverScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSResolverSpecs().Return(fakeSpecs)
+				gomock.InOrder(
+					r.CreateResource(gomockinternal.AContext(), &fakeResolverSpec, ServiceName).Return(nil, nil),
+					r.CreateResource(gomockinternal.AContext(), &fakeOutboundEndpointSpec, ServiceName).Return(nil, nil),
+					r.CreateResource(gomockinternal.AContext(), &fakeRulesetSpec, ServiceName).Return(nil, nil),
+					r.CreateResource(gomockinternal.AContext(), &fakeRuleSpec, ServiceName).Return(nil, nil),
+					r.CreateResource(gomockinternal.AContext(), &fakeLinkSpec, ServiceName).Return(nil, nil),
+				)
+				s.UpdatePutStatus(infrav1.DNSResolverReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "link an existing DNS forwarding ruleset",
+			expectedError: "",
+			expect: func(s *mock_dnsresolvers.MockDNSResolverScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSResolverSpecs().Return([]azure.ResourceSpecGetter{&fakeLinkSpec})
+				r.CreateResource(gomockinternal.AContext(), &fakeLinkSpec, ServiceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.DNSResolverReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "stop at the resource that is still being created",
+			expectedError: notDoneError.Error(),
+			expect: func(s *mock_dnsresolvers.MockDNSResolverScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSResolverSpecs().Return(fakeSpecs)
+				gomock.InOrder(
+					r.CreateResource(gomockinternal.AContext(), &fakeResolverSpec, ServiceName).Return(nil, nil),
+					r.CreateResource(gomockinternal.AContext(), &fakeOutboundEndpointSpec, ServiceName).Return(nil, notDoneError),
+				)
+				s.UpdatePutStatus(infrav1.DNSResolverReadyCondition, ServiceName, notDoneError)
+			},
+		},
+		{
+			name:          "fail to create the DNS private resolver",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_dnsresolvers.MockDNSResolverScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSResolverSpecs().Return(fakeSpecs)
+				r.CreateResource(gomockinternal.AContext(), &fakeResolverSpec, ServiceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.DNSResolverReadyCondition, ServiceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_dnsresolvers.NewMockDNSResolverScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteDNSResolver(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_dnsresolvers.MockDNSResolverScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the cluster has no DNS private resolver",
+			expectedError: "",
+			expect: func(s *mock_dnsresolvers.MockDNSResolverScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSResolverSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "delete the DNS private resolver and its forwarding ruleset in reverse order",
+			expectedError: "",
+			expect: func(s *mock_dnsresolvers.MockDNSResolverScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSResolverSpecs().Return(fakeSpecs)
+				gomock.InOrder(
+					r.DeleteResource(gomockinternal.AContext(), &fakeLinkSpec, ServiceName).Return(nil),
+					r.DeleteResource(gomockinternal.AContext(), &fakeRuleSpec, ServiceName).Return(nil),
+					r.DeleteResource(gomockinternal.AContext(), &fakeRulesetSpec, ServiceName).Return(nil),
+					r.DeleteResource(gomockinternal.AContext(), &fakeOutboundEndpointSpec, ServiceName).Return(nil),
+					r.DeleteResource(gomockinternal.AContext(), &fakeResolverSpec, ServiceName).Return(nil),
+				)
+				s.UpdateDeleteStatus(infrav1.DNSResolverReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "fail to delete the link of the DNS forwarding ruleset",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_dnsresolvers.MockDNSResolverScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.DNSResolverSpecs().Return(fakeSpecs)
+				r.DeleteResource(gomockinternal.AContext(), &fakeLinkSpec, ServiceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.DNSResolverReadyCondition, ServiceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_dnsresolvers.NewMockDNSResolverScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsresolvers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// DNSResolverSpec defines the specification for a DNS private resolver in the virtual network of a cluster.
+type DNSResolverSpec struct {
+	Name           string
+	ResourceGroup  string
+	SubscriptionID string
+	ClusterName    string
+	Location       string
+	VNetID         string
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the DNS private resolver.
+func (s *DNSResolverSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the DNS private resolver.
+func (s *DNSResolverSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for DNS private resolvers.
+func (s *DNSResolverSpec) OwnerResourceName() string {
+	return ""
+}
+
+// ResourceID returns the resource ID of the DNS private resolver.
+func (s *DNSResolverSpec) ResourceID() string {
+	return azure.DNSResolverID(s.SubscriptionID, s.ResourceGroup, s.Name)
+}
+
+// Parameters returns the parameters for the DNS private resolver.
+func (s *DNSResolverSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(armresources.GenericResource); !ok {
+			return nil, errors.Errorf("%T is not an armarmresources.GenericResource", existing)
+		}
+		// The virtual network of a DNS private resolver can't be changed.
+		return nil, nil
+	}
+
+	return armresources.GenericResource{
+		Location: to.Ptr(s.Location),
+		Tags:     tags(s.ClusterName, s.Name, s.AdditionalTags),
+		Properties: map[string]interface{}{
+			"virtualNetwork": map[string]interface{}{"id": s.VNetID},
+		},
+	}, nil
+}
+
+// OutboundEndpointSpec defines the specification for the outbound endpoint of a DNS private resolver.
+type OutboundEndpointSpec struct {
+	Name           string
+	ResourceGroup  string
+	SubscriptionID string
+	ClusterName    string
+	Location       string
+	ResolverName   string
+	SubnetID       string
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the outbound endpoint.
+func (s *OutboundEndpointSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the DNS private resolver.
+func (s *OutboundEndpointSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName returns the name of the DNS private resolver.
+func (s *OutboundEndpointSpec) OwnerResourceName() string {
+	return s.ResolverName
+}
+
+// ResourceID returns the resource ID of the outbound endpoint.
+func (s *OutboundEndpointSpec) ResourceID() string {
+	return azure.DNSResolverOutboundEndpointID(s.SubscriptionID, s.ResourceGroup, s.ResolverName, s.Name)
+}
+
+// Parameters returns the parameters for the outbound endpoint.
+func (s *OutboundEndpointSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(armresources.GenericResource); !ok {
+			return nil, errors.Errorf("%T is not an armarmresources.GenericResource", existing)
+		}
+		// The subnet of an outbound endpoint can't be changed.
+		return nil, nil
+	}
+
+	return armresources.GenericResource{
+		Location: to.Ptr(s.Location),
+		Tags:     tags(s.ClusterName, s.Name, s.AdditionalTags),
+		Properties: map[string]interface{}{
+			"subnet": map[string]interface{}{"id": s.SubnetID},
+		},
+	}, nil
+}
+
+// ForwardingRulesetSpec defines the specification for the DNS forwarding ruleset of a DNS private resolver.
+type ForwardingRulesetSpec struct {
+	Name               string
+	ResourceGroup      string
+	SubscriptionID     string
+	ClusterName        string
+	Location           string
+	OutboundEndpointID string
+	AdditionalTags     infrav1.Tags
+}
+
+// ResourceName returns the name of the DNS forwarding ruleset.
+func (s *ForwardingRulesetSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the DNS forwarding ruleset.
+func (s *ForwardingRulesetSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for DNS forwarding rulesets.
+func (s *ForwardingRulesetSpec) OwnerResourceName() string {
+	return ""
+}
+
+// ResourceID returns the resource ID of the DNS forwarding ruleset.
+func (s *ForwardingRulesetSpec) ResourceID() string {
+	return azure.DNSForwardingRulesetID(s.SubscriptionID, s.ResourceGroup, s.Name)
+}
+
+// Parameters returns the parameters for the DNS forwarding ruleset.
+func (s *ForwardingRulesetSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(armresources.GenericResource); !ok {
+			return nil, errors.Errorf("%T is not an armarmresources.GenericResource", existing)
+		}
+		return nil, nil
+	}
+
+	return armresources.GenericResource{
+		Location: to.Ptr(s.Location),
+		Tags:     tags(s.ClusterName, s.Name, s.AdditionalTags),
+		Properties: map[string]interface{}{
+			"dnsResolverOutboundEndpoints": []interface{}{
+				map[string]interface{}{"id": s.OutboundEndpointID},
+			},
+		},
+	}, nil
+}
+
+// ForwardingRuleSpec defines the specification for a rule of a DNS forwarding ruleset.
+type ForwardingRuleSpec struct {
+	Name                 string
+	RulesetID            string
+	RulesetName          string
+	RulesetResourceGroup string
+	DomainName           string
+	TargetDNSServers     []infrav1.TargetDNSServer
+}
+
+// ResourceName returns the name of the forwarding rule.
+func (s *ForwardingRuleSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the DNS forwarding ruleset.
+func (s *ForwardingRuleSpec) ResourceGroupName() string {
+	return s.RulesetResourceGroup
+}
+
+// OwnerResourceName returns the name of the DNS forwarding ruleset.
+func (s *ForwardingRuleSpec) OwnerResourceName() string {
+	return s.RulesetName
+}
+
+// ResourceID returns the resource ID of the forwarding rule.
+func (s *ForwardingRuleSpec) ResourceID() string {
+	return strings.TrimSuffix(s.RulesetID, "/") + "/forwardingRules/" + s.Name
+}
+
+// Parameters returns the parameters for the forwarding rule.
+func (s *ForwardingRuleSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	domainName := fullyQualifiedDomainName(s.DomainName)
+	targets := make([]interface{}, 0, len(s.TargetDNSServers))
+	wantedTargets := make([]string, 0, len(s.TargetDNSServers))
+	for _, server := range s.TargetDNSServers {
+		port := pointer.Int32Deref(server.Port, 0)
+		if server.Port == nil {
+			port = infrav1.DefaultTargetDNSServerPort
+		}
+		targets = append(targets, map[string]interface{}{
+			"ipAddress": server.IPAddress,
+			"port":      port,
+		})
+		wantedTargets = append(wantedTargets, fmt.Sprintf("%s:%d", server.IPAddress, port))
+	}
+
+	if existing != nil {
+		existingRule, ok := existing.(armresources.GenericResource)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armarmresources.GenericResource", existing)
+		}
+		if properties, ok := existingRule.Properties.(map[string]interface{}); ok {
+			existingDomainName, _ := properties["domainName"].(string)
+			if strings.EqualFold(existingDomainName, domainName) && sameTargets(existingTargets(properties), wantedTargets) {
+				// Skip update for the forwarding rule as it exists with expected values
+				return nil, nil
+			}
+		}
+	}
+
+	return armresources.GenericResource{
+		Properties: map[string]interface{}{
+			"domainName":          domainName,
+			"targetDnsServers":    targets,
+			"forwardingRuleState": "Enabled",
+		},
+	}, nil
+}
+
+// VirtualNetworkLinkSpec defines the specification for the link of a DNS forwarding ruleset to the virtual network of
+// a cluster.
+type VirtualNetworkLinkSpec struct {
+	Name                 string
+	RulesetID            string
+	RulesetName          string
+	RulesetResourceGroup string
+	VNetID               string
+}
+
+// ResourceName returns the name of the virtual network link.
+func (s *VirtualNetworkLinkSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the DNS forwarding ruleset.
+func (s *VirtualNetworkLinkSpec) ResourceGroupName() string {
+	return s.RulesetResourceGroup
+}
+
+// OwnerResourceName returns the name of the DNS forwarding ruleset.
+func (s *VirtualNetworkLinkSpec) OwnerResourceName() string {
+	return s.RulesetName
+}
+
+// ResourceID returns the resource ID of the virtual network link.
+func (s *VirtualNetworkLinkSpec) ResourceID() string {
+	return strings.TrimSuffix(s.RulesetID, "/") + "/virtualNetworkLinks/" + s.Name
+}
+
+// Parameters returns the parameters for the virtual network link.
+func (s *VirtualNetworkLinkSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(armresources.GenericResource); !ok {
+			return nil, errors.Errorf("%T is not an armarmresources.GenericResource", existing)
+		}
+		// The virtual network of a link can't be changed.
+		return nil, nil
+	}
+
+	return armresources.GenericResource{
+		Properties: map[string]interface{}{
+			"virtualNetwork": map[string]interface{}{"id": s.VNetID},
+		},
+	}, nil
+}
+
+// tags returns the tags of a DNS private resolver resource owned by the cluster.
+func tags(clusterName, name string, additionalTags infrav1.Tags) map[string]*string {
+	return converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+		ClusterName: clusterName,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        to.Ptr(name),
+		Additional:  additionalTags,
+	}))
+}
+
+// fullyQualifiedDomainName returns the domain name with the trailing dot DNS forwarding rules require.
+func fullyQualifiedDomainName(domainName string) string {
+	if strings.HasSuffix(domainName, ".") {
+		return domainName
+	}
+	return domainName + "."
+}
+
+// existingTargets returns the target DNS servers of the properties of an existing forwarding rule, as ip:port strings.
+func existingTargets(properties map[string]interface{}) []string {
+	servers, _ := properties["targetDnsServers"].([]interface{})
+	targets := make([]string, 0, len(servers))
+	for _, server := range servers {
+		serverProperties, ok := server.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ipAddress, _ := serverProperties["ipAddress"].(string)
+		// JSON numbers are unmarshaled as float64.
+		port, _ := serverProperties["port"].(float64)
+		targets = append(targets, fmt.Sprintf("%s:%d", ipAddress, int32(port)))
+	}
+	return targets
+}
+
+// sameTargets returns whether two lists of ip:port target DNS servers are the same, in any order.
+func sameTargets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
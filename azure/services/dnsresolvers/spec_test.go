@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsresolvers
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	. "github.com/onsi/gomega"
+)
+
+func TestResourceID(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(fakeResolverSpec.ResourceID()).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/dnsResolvers/my-cluster-dns-resolver"))
+	g.Expect(fakeOutboundEndpointSpec.ResourceID()).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/dnsResolvers/my-cluster-dns-resolver/outboundEndpoints/my-cluster-dns-resolver"))
+	g.Expect(fakeRulesetSpec.ResourceID()).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/dnsForwardingRulesets/my-cluster-dns-resolver"))
+	g.Expect(fakeRuleSpec.ResourceID()).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/dnsForwardingRulesets/my-cluster-dns-resolver/forwardingRules/on-prem"))
+	g.Expect(fakeLinkSpec.ResourceID()).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/dnsForwardingRulesets/my-cluster-dns-resolver/virtualNetworkLinks/my-rg-my-vnet"))
+}
+
+func TestDNSResolverParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	params, err := fakeResolverSpec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	resolver, ok := params.(armresources.GenericResource)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(resolver.Location).To(Equal(to.Ptr("westus")))
+	g.Expect(resolver.Tags).To(HaveKeyWithValue("sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster", to.Ptr("owned")))
+	g.Expect(resolver.Properties).To(Equal(map[string]interface{}{
+		"virtualNetwork": map[string]interface{}{"id": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"},
+	}))
+
+	// An existing DNS private resolver is left untouched.
+	params, err = fakeResolverSpec.Parameters(resolver)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).To(BeNil())
+
+	_, err = fakeResolverSpec.Parameters("not a resource")
+	g.Expect(err).To(MatchError("string is not an armarmresources.GenericResource"))
+
+	params, err = fakeOutboundEndpointSpec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params.(armresources.GenericResource).Properties).To(Equal(map[string]interface{}{
+		"subnet": map[string]interface{}{"id": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/dns-resolver-outbound-subnet"},
+	}))
+
+	params, err = fakeRulesetSpec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params.(armresources.GenericResource).Properties).To(Equal(map[string]interface{}{
+		"dnsResolverOutboundEndpoints": []interface{}{
+			map[string]interface{}{"id": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/dnsResolvers/my-cluster-dns-resolver/outboundEndpoints/my-cluster-dns-resolver"},
+		},
+	}))
+
+	params, err = fakeLinkSpec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params.(armresources.GenericResource).Properties).To(Equal(map[string]interface{}{
+		"virtualNetwork": map[string]interface{}{"id": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"},
+	}))
+}
+
+func TestForwardingRuleParameters(t *testing.T) {
+	testcases := []struct {
+		name     string
+		existing interface{}
+		expect   func(g *WithT, result interface{})
+	}{
+		{
+			name:     "new forwarding rule",
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armresources.GenericResource{
+					Properties: map[string]interface{}{
+						"domainName": "corp.example.com.",
+						"targetDnsServers": []interface{}{
+							map[string]interface{}{"ipAddress": "10.1.0.4", "port": int32(53)},
+							map[string]interface{}{"ipAddress": "10.1.0.5", "port": int32(5353)},
+						},
+						"forwardingRuleState": "Enabled",
+					},
+				}))
+			},
+		},
+		{
+			name: "existing forwarding rule with the same targets in another order",
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"domainName": "CORP.example.com.",
+					"targetDnsServers": []interface{}{
+						map[string]interface{}{"ipAddress": "10.1.0.5", "port": float64(5353)},
+						map[string]interface{}{"ipAddress": "10.1.0.4", "port": float64(53)},
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "existing forwarding rule with other targets",
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"domainName": "corp.example.com.",
+					"targetDnsServers": []interface{}{
+						map[string]interface{}{"ipAddress": "10.1.0.4", "port": float64(53)},
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).NotTo(BeNil())
+				g.Expect(result.(armresources.GenericResource).Properties).To(HaveKeyWithValue("targetDnsServers", HaveLen(2)))
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := fakeRuleSpec.Parameters(tc.existing)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, result)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsubscriptions
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/eventgrid/armeventgrid/v2"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	subscriptionID string
+	subscriptions  *armeventgrid.EventSubscriptionsClient
+}
+
+// NewClient creates a new Event Grid event subscriptions client from subscription ID.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armeventgrid.NewEventSubscriptionsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create event subscriptions client")
+	}
+	return &AzureClient{
+		subscriptionID: auth.SubscriptionID(),
+		subscriptions:  c,
+	}, nil
+}
+
+// scope returns the ID of the resource group the event subscription is scoped to.
+func (ac *AzureClient) scope(spec azure.ResourceSpecGetter) string {
+	return azure.ResourceGroupID(ac.subscriptionID, spec.ResourceGroupName())
+}
+
+// Get gets the specified event subscription on a resource group.
+func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "eventsubscriptions.AzureClient.Get")
+	defer done()
+
+	resp, err := ac.subscriptions.Get(ctx, ac.scope(spec), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.EventSubscription, nil
+}
+
+// CreateOrUpdateAsync creates or updates an event subscription asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "eventsubscriptions.AzureClient.CreateOrUpdateAsync")
+	defer done()
+
+	subscription, ok := parameters.(armeventgrid.EventSubscription)
+	if !ok && parameters != nil {
+		return nil, nil, errors.Errorf("%T is not an armeventgrid.EventSubscription", parameters)
+	}
+
+	opts := &armeventgrid.EventSubscriptionsClientBeginCreateOrUpdateOptions{ResumeToken: resumeToken}
+	createPoller, err := ac.subscriptions.BeginCreateOrUpdate(ctx, ac.scope(spec), spec.ResourceName(), subscription, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	resp, err := createPoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, createPoller, err
+	}
+
+	// if the operation completed, return a nil poller
+	return resp.EventSubscription, nil, nil
+}
+
+// DeleteAsync deletes an event subscription asynchronously. DeleteAsync sends a DELETE
+// request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "eventsubscriptions.AzureClient.DeleteAsync")
+	defer done()
+
+	opts := &armeventgrid.EventSubscriptionsClientBeginDeleteOptions{ResumeToken: resumeToken}
+	deletePoller, err := ac.subscriptions.BeginDelete(ctx, ac.scope(spec), spec.ResourceName(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	_, err = deletePoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return deletePoller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, nil
+}
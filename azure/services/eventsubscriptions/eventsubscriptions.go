@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsubscriptions
+
+import (
+	"context"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "eventsubscriptions"
+
+// EventSubscriptionScope defines the scope interface for an event subscription service.
+type EventSubscriptionScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	EventSubscriptionSpec() azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope EventSubscriptionScope
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope EventSubscriptionScope) (*Service, error) {
+	client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:      scope,
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the Event Grid subscription.
+func (s *Service) RequiredActions() []string {
+	if s.Scope.EventSubscriptionSpec() == nil {
+		return nil
+	}
+	return []string{"Microsoft.EventGrid/eventSubscriptions/read", "Microsoft.EventGrid/eventSubscriptions/write"}
+}
+
+// Reconcile creates or updates the Event Grid subscription delivering resource notifications for the cluster resource group.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "eventsubscriptions.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.EventSubscriptionSpec()
+	if spec == nil {
+		return nil
+	}
+
+	_, err := s.CreateResource(ctx, spec, serviceName)
+	s.Scope.UpdatePutStatus(infrav1.EventSubscriptionReadyCondition, serviceName, err)
+	return err
+}
+
+// Delete deletes the Event Grid subscription of the cluster resource group.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "eventsubscriptions.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.EventSubscriptionSpec()
+	if spec == nil {
+		return nil
+	}
+
+	err := s.DeleteResource(ctx, spec, serviceName)
+	s.Scope.UpdateDeleteStatus(infrav1.EventSubscriptionReadyCondition, serviceName, err)
+	return err
+}
+
+// IsManaged always returns true as the event subscription is always created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
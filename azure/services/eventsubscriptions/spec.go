@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsubscriptions
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/eventgrid/armeventgrid/v2"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ResourceDeleteSuccessEventType is the Event Grid event type raised when a resource is deleted.
+	ResourceDeleteSuccessEventType = "Microsoft.Resources.ResourceDeleteSuccess"
+
+	// webhookLabelPrefix prefixes the label recording which webhook URL the event subscription delivers to.
+	// Azure never returns the full endpoint URL of a webhook destination, so the label is used to detect changes.
+	webhookLabelPrefix = "capz-webhook-"
+)
+
+// EventSubscriptionSpec defines the specification for an Event Grid event subscription on a resource group.
+type EventSubscriptionSpec struct {
+	Name          string
+	ResourceGroup string
+	WebhookURL    string
+}
+
+// ResourceName returns the name of the event subscription.
+func (s *EventSubscriptionSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group the event subscription is scoped to.
+func (s *EventSubscriptionSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for event subscriptions.
+func (s *EventSubscriptionSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the event subscription.
+func (s *EventSubscriptionSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	label := webhookLabel(s.WebhookURL)
+	if existing != nil {
+		existingSubscription, ok := existing.(armeventgrid.EventSubscription)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armeventgrid.EventSubscription", existing)
+		}
+		if existingSubscription.Properties != nil {
+			for _, l := range existingSubscription.Properties.Labels {
+				if l != nil && *l == label {
+					// event subscription already delivers to the desired webhook
+					return nil, nil
+				}
+			}
+		}
+	}
+
+	return armeventgrid.EventSubscription{
+		Properties: &armeventgrid.EventSubscriptionProperties{
+			Destination: &armeventgrid.WebHookEventSubscriptionDestination{
+				EndpointType: to.Ptr(armeventgrid.EndpointTypeWebHook),
+				Properties: &armeventgrid.WebHookEventSubscriptionDestinationProperties{
+					EndpointURL: to.Ptr(s.WebhookURL),
+				},
+			},
+			Filter: &armeventgrid.EventSubscriptionFilter{
+				// Only deletions are delivered: writes made by CAPZ itself would otherwise trigger a reconcile loop.
+				IncludedEventTypes: []*string{to.Ptr(ResourceDeleteSuccessEventType)},
+			},
+			Labels:              []*string{to.Ptr(label)},
+			EventDeliverySchema: to.Ptr(armeventgrid.EventDeliverySchemaEventGridSchema),
+		},
+	}, nil
+}
+
+// webhookLabel returns a label identifying the webhook URL without exposing any secret it contains.
+func webhookLabel(url string) string {
+	return fmt.Sprintf("%s%x", webhookLabelPrefix, sha256.Sum256([]byte(url)))[:len(webhookLabelPrefix)+16]
+}
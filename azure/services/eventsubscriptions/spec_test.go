@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsubscriptions
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/eventgrid/armeventgrid/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := &EventSubscriptionSpec{
+		Name:          "capz-my-cluster",
+		ResourceGroup: "my-rg",
+		WebhookURL:    "https://capz.example.com/eventgrid?token=secret",
+	}
+
+	params, err := spec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	subscription, ok := params.(armeventgrid.EventSubscription)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(subscription.Properties.Filter.IncludedEventTypes).To(HaveLen(1))
+	g.Expect(*subscription.Properties.Filter.IncludedEventTypes[0]).To(Equal(ResourceDeleteSuccessEventType))
+	webhook, ok := subscription.Properties.Destination.(*armeventgrid.WebHookEventSubscriptionDestination)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(*webhook.Properties.EndpointURL).To(Equal(spec.WebhookURL))
+	g.Expect(subscription.Properties.Labels).To(HaveLen(1))
+	g.Expect(*subscription.Properties.Labels[0]).NotTo(ContainSubstring("secret"))
+
+	// An existing subscription delivering to the same webhook is left untouched.
+	params, err = spec.Parameters(subscription)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).To(BeNil())
+
+	// A changed webhook URL updates the existing subscription.
+	changed := *spec
+	changed.WebhookURL = "https://capz.example.com/eventgrid?token=rotated"
+	params, err = changed.Parameters(subscription)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).NotTo(BeNil())
+
+	_, err = spec.Parameters("not a subscription")
+	g.Expect(err).To(HaveOccurred())
+}
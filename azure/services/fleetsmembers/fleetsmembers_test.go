@@ -0,0 +1,167 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleetsmembers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/fleetsmembers/mock_fleetsmembers"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeMemberSpec = FleetsMemberSpec{
+		Name:             "my-cluster",
+		ResourceGroup:    "fleets-rg",
+		FleetResourceID:  "/subscriptions/123/resourceGroups/fleets-rg/providers/Microsoft.ContainerService/fleets/my-fleet",
+		FleetName:        "my-fleet",
+		ManagedClusterID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ContainerService/managedClusters/my-cluster",
+		Group:            "canary",
+	}
+
+	internalError = errors.New("Internal Server Error")
+)
+
+func TestReconcileFleetsMember(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_fleetsmembers.MockFleetsMemberScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the cluster doesn't join a fleet",
+			expectedError: "",
+			expect: func(s *mock_fleetsmembers.MockFleetsMemberScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.FleetsMemberSpec().Return(nil)
+			},
+		},
+		{
+			name:          "join the fleet",
+			expectedError: "",
+			expect: func(s *mock_fleetsmembers.MockFleetsMemberScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.FleetsMemberSpec().Return(&fakeMemberSpec)
+				r.CreateResource(gomockinternal.AContext(), &fakeMemberSpec, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.FleetsMemberReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to join the fleet",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_fleetsmembers.MockFleetsMemberScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.FleetsMemberSpec().Return(&fakeMemberSpec)
+				r.CreateResource(gomockinternal.AContext(), &fakeMemberSpec, serviceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.FleetsMemberReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_fleetsmembers.NewMockFleetsMemberScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteFleetsMember(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_fleetsmembers.MockFleetsMemberScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the cluster doesn't join a fleet",
+			expectedError: "",
+			expect: func(s *mock_fleetsmembers.MockFleetsMemberScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.FleetsMemberSpec().Return(nil)
+			},
+		},
+		{
+			name:          "leave the fleet",
+			expectedError: "",
+			expect: func(s *mock_fleetsmembers.MockFleetsMemberScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.FleetsMemberSpec().Return(&fakeMemberSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeMemberSpec, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.FleetsMemberReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to leave the fleet",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_fleetsmembers.MockFleetsMemberScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.FleetsMemberSpec().Return(&fakeMemberSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeMemberSpec, serviceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.FleetsMemberReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_fleetsmembers.NewMockFleetsMemberScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleetsmembers
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+)
+
+// FleetsMemberSpec defines the specification for the membership of a managed cluster in a fleet.
+type FleetsMemberSpec struct {
+	Name             string
+	ResourceGroup    string
+	FleetResourceID  string
+	FleetName        string
+	ManagedClusterID string
+	Group            string
+}
+
+// ResourceName returns the name of the fleet member.
+func (s *FleetsMemberSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the fleet.
+func (s *FleetsMemberSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName returns the name of the fleet.
+func (s *FleetsMemberSpec) OwnerResourceName() string {
+	return s.FleetName
+}
+
+// ResourceID returns the resource ID of the fleet member.
+func (s *FleetsMemberSpec) ResourceID() string {
+	return strings.TrimSuffix(s.FleetResourceID, "/") + "/members/" + s.Name
+}
+
+// Parameters returns the parameters for the fleet member.
+func (s *FleetsMemberSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		existingMember, ok := existing.(armresources.GenericResource)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armarmresources.GenericResource", existing)
+		}
+
+		if properties, ok := existingMember.Properties.(map[string]interface{}); ok {
+			clusterID, _ := properties["clusterResourceId"].(string)
+			group, _ := properties["group"].(string)
+			if strings.EqualFold(clusterID, s.ManagedClusterID) && group == s.Group {
+				// Skip update for the fleet member as it exists with expected values
+				return nil, nil
+			}
+		}
+	}
+
+	properties := map[string]interface{}{
+		"clusterResourceId": s.ManagedClusterID,
+	}
+	if s.Group != "" {
+		properties["group"] = s.Group
+	}
+
+	return armresources.GenericResource{
+		Properties: properties,
+	}, nil
+}
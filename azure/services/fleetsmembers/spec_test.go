@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fleetsmembers
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	. "github.com/onsi/gomega"
+)
+
+func TestResourceID(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(fakeMemberSpec.ResourceID()).To(Equal("/subscriptions/123/resourceGroups/fleets-rg/providers/Microsoft.ContainerService/fleets/my-fleet/members/my-cluster"))
+}
+
+func TestParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := fakeMemberSpec
+	params, err := spec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	member, ok := params.(armresources.GenericResource)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(member.Properties).To(Equal(map[string]interface{}{
+		"clusterResourceId": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ContainerService/managedClusters/my-cluster",
+		"group":             "canary",
+	}))
+
+	// An existing member of the same update group is left untouched.
+	params, err = spec.Parameters(member)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).To(BeNil())
+
+	// A changed update group updates the existing member.
+	changed := spec
+	changed.Group = "production"
+	params, err = changed.Parameters(member)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params.(armresources.GenericResource).Properties).To(HaveKeyWithValue("group", "production"))
+
+	// A member without update group doesn't send one.
+	changed.Group = ""
+	params, err = changed.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params.(armresources.GenericResource).Properties).NotTo(HaveKey("group"))
+}
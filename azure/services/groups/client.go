@@ -19,11 +19,11 @@ package groups
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
-	"github.com/Azure/go-autorest/autorest"
-	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -31,32 +31,30 @@ import (
 // client wraps go-sdk.
 type client interface {
 	Get(context.Context, azure.ResourceSpecGetter) (interface{}, error)
-	CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (result interface{}, future azureautorest.FutureAPI, err error)
-	DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (future azureautorest.FutureAPI, err error)
-	IsDone(ctx context.Context, future azureautorest.FutureAPI) (isDone bool, err error)
-	Result(ctx context.Context, future azureautorest.FutureAPI, futureType string) (result interface{}, err error)
+	CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error)
+	DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error)
 }
 
 // azureClient contains the Azure go-sdk Client.
 type azureClient struct {
-	groups resources.GroupsClient
+	groups *armresources.ResourceGroupsClient
 }
 
 var _ client = (*azureClient)(nil)
 
-// newClient creates a new VM client from subscription ID.
-func newClient(auth azure.Authorizer) *azureClient {
-	c := newGroupsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+// newClient creates a new resource groups client from subscription ID.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armresources.NewResourceGroupsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create resource groups client")
+	}
 	return &azureClient{
 		groups: c,
-	}
-}
-
-// newGroupsClient creates a new groups client from subscription ID.
-func newGroupsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) resources.GroupsClient {
-	groupsClient := resources.NewGroupsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&groupsClient.Client, authorizer)
-	return groupsClient
+	}, nil
 }
 
 // Get gets a resource group.
@@ -64,34 +62,41 @@ func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "groups.AzureClient.Get")
 	defer done()
 
-	return ac.groups.Get(ctx, spec.ResourceName())
+	resp, err := ac.groups.Get(ctx, spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.ResourceGroup, nil
 }
 
 // CreateOrUpdateAsync creates or updates a resource group.
-// Creating a resource group is not a long running operation, so we don't ever return a future.
-func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (result interface{}, future azureautorest.FutureAPI, err error) {
+// Creating a resource group is not a long running operation, so we don't ever return a poller.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "groups.AzureClient.CreateOrUpdate")
 	defer done()
 
-	group, ok := parameters.(resources.Group)
+	group, ok := parameters.(armresources.ResourceGroup)
 	if !ok {
-		return nil, nil, errors.Errorf("%T is not a resources.Group", parameters)
+		return nil, nil, errors.Errorf("%T is not an armresources.ResourceGroup", parameters)
 	}
 
-	result, err = ac.groups.CreateOrUpdate(ctx, spec.ResourceName(), group)
-	return result, nil, err
+	resp, err := ac.groups.CreateOrUpdate(ctx, spec.ResourceName(), group, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.ResourceGroup, nil, nil
 }
 
 // DeleteAsync deletes a resource group asynchronously. DeleteAsync sends a DELETE
-// request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
 // progress of the operation.
 //
 // NOTE: When you delete a resource group, all of its resources are also deleted.
-func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (future azureautorest.FutureAPI, err error) {
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "groups.AzureClient.Delete")
 	defer done()
 
-	deleteFuture, err := ac.groups.Delete(ctx, spec.ResourceName())
+	deletePoller, err := ac.groups.BeginDelete(ctx, spec.ResourceName(), &armresources.ResourceGroupsClientBeginDeleteOptions{ResumeToken: resumeToken})
 	if err != nil {
 		return nil, err
 	}
@@ -99,32 +104,12 @@ func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
 	defer cancel()
 
-	err = deleteFuture.WaitForCompletionRef(ctx, ac.groups.Client)
+	_, err = deletePoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
 	if err != nil {
-		// if an error occurs, return the future.
+		// if an error occurs, return the poller.
 		// this means the long-running operation didn't finish in the specified timeout.
-		return &deleteFuture, err
-	}
-	_, err = deleteFuture.Result(ac.groups)
-	// if the operation completed, return a nil future.
-	return nil, err
-}
-
-// IsDone returns true if the long-running operation has completed.
-func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (isDone bool, err error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "groups.AzureClient.IsDone")
-	defer done()
-
-	isDone, err = future.DoneWithContext(ctx, ac.groups)
-	if err != nil {
-		return false, errors.Wrap(err, "failed checking if the operation was complete")
+		return deletePoller, err
 	}
-
-	return isDone, nil
-}
-
-// Result fetches the result of a long-running operation future.
-func (ac *azureClient) Result(ctx context.Context, future azureautorest.FutureAPI, futureType string) (result interface{}, err error) {
-	// Result is a no-op for resource groups as only Delete operations return a future.
+	// if the operation completed, return a nil poller.
 	return nil, nil
 }
@@ -19,7 +19,7 @@ package groups
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -47,13 +47,16 @@ type GroupScope interface {
 }
 
 // New creates a new service.
-func New(scope GroupScope) *Service {
-	client := newClient(scope)
+func New(scope GroupScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:      scope,
 		client:     client,
-		Reconciler: async.New(scope, client, client),
-	}
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
 }
 
 // Name returns the service name.
@@ -61,6 +64,12 @@ func (s *Service) Name() string {
 	return ServiceName
 }
 
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the resource group.
+func (s *Service) RequiredActions() []string {
+	return []string{"Microsoft.Resources/subscriptions/resourceGroups/read"}
+}
+
 // Reconcile gets/creates/updates a resource group.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "groups.Service.Reconcile")
@@ -124,9 +133,9 @@ func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	group, ok := groupIface.(resources.Group)
+	group, ok := groupIface.(armresources.ResourceGroup)
 	if !ok {
-		return false, errors.Errorf("%T is not a resources.Group", groupIface)
+		return false, errors.Errorf("%T is not an armresources.ResourceGroup", groupIface)
 	}
 
 	tags := converters.MapToTags(group.Tags)
@@ -21,14 +21,14 @@ import (
 	"net/http"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups/mock_groups"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
@@ -39,19 +39,19 @@ var (
 		ClusterName:    "test-cluster",
 		AdditionalTags: map[string]string{"foo": "bar"},
 	}
-	internalError      = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
-	notFoundError      = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found")
-	sampleManagedGroup = resources.Group{
-		Name:       to.StringPtr("test-group"),
-		Location:   to.StringPtr("test-location"),
-		Properties: &resources.GroupProperties{},
-		Tags:       map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned")},
+	internalError      = test.NewResponseError(http.StatusInternalServerError)
+	notFoundError      = test.NewResponseError(http.StatusNotFound)
+	sampleManagedGroup = armresources.ResourceGroup{
+		Name:       to.Ptr("test-group"),
+		Location:   to.Ptr("test-location"),
+		Properties: &armresources.ResourceGroupProperties{},
+		Tags:       map[string]*string{"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.Ptr("owned")},
 	}
-	sampleBYOGroup = resources.Group{
-		Name:       to.StringPtr("test-group"),
-		Location:   to.StringPtr("test-location"),
-		Properties: &resources.GroupProperties{},
-		Tags:       map[string]*string{"foo": to.StringPtr("bar")},
+	sampleBYOGroup = armresources.ResourceGroup{
+		Name:       to.Ptr("test-group"),
+		Location:   to.Ptr("test-location"),
+		Properties: &armresources.ResourceGroupProperties{},
+		Tags:       map[string]*string{"foo": to.Ptr("bar")},
 	}
 )
 
@@ -79,7 +79,7 @@ func TestReconcileGroups(t *testing.T) {
 		},
 		{
 			name:          "create resource group fails",
-			expectedError: "#: Internal Server Error: StatusCode=500",
+			expectedError: internalError.Error(),
 			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.GroupSpec().Return(&fakeGroupSpec)
 				r.CreateResource(gomockinternal.AContext(), &fakeGroupSpec, ServiceName).Return(nil, internalError)
@@ -157,7 +157,7 @@ func TestDeleteGroups(t *testing.T) {
 			expectedError: "could not get resource group management state",
 			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.GroupSpec().AnyTimes().Return(&fakeGroupSpec)
-				m.Get(gomockinternal.AContext(), &fakeGroupSpec).Return(resources.Group{}, internalError)
+				m.Get(gomockinternal.AContext(), &fakeGroupSpec).Return(armresources.ResourceGroup{}, internalError)
 			},
 		},
 		{
@@ -165,20 +165,20 @@ func TestDeleteGroups(t *testing.T) {
 			expectedError: "",
 			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.GroupSpec().AnyTimes().Return(&fakeGroupSpec)
-				m.Get(gomockinternal.AContext(), &fakeGroupSpec).Return(resources.Group{}, notFoundError)
+				m.Get(gomockinternal.AContext(), &fakeGroupSpec).Return(armresources.ResourceGroup{}, notFoundError)
 				s.DeleteLongRunningOperationState("test-group", ServiceName)
 				s.UpdateDeleteStatus(infrav1.ResourceGroupReadyCondition, ServiceName, nil)
 			},
 		},
 		{
 			name:          "error occurs when deleting resource group",
-			expectedError: "#: Internal Server Error: StatusCode=500",
+			expectedError: internalError.Error(),
 			expect: func(s *mock_groups.MockGroupScopeMockRecorder, m *mock_groups.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.GroupSpec().AnyTimes().Return(&fakeGroupSpec)
 				m.Get(gomockinternal.AContext(), &fakeGroupSpec).Return(sampleManagedGroup, nil)
 				s.ClusterName().Return("test-cluster")
 				r.DeleteResource(gomockinternal.AContext(), &fakeGroupSpec, ServiceName).Return(internalError)
-				s.UpdateDeleteStatus(infrav1.ResourceGroupReadyCondition, ServiceName, gomockinternal.ErrStrEq("#: Internal Server Error: StatusCode=500"))
+				s.UpdateDeleteStatus(infrav1.ResourceGroupReadyCondition, ServiceName, gomockinternal.ErrStrEq(internalError.Error()))
 			},
 		},
 	}
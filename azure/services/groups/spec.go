@@ -17,8 +17,8 @@ limitations under the License.
 package groups
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 )
@@ -54,14 +54,14 @@ func (s *GroupSpec) Parameters(existing interface{}) (params interface{}, err er
 		// Note that rg tags are updated separately using tags service.
 		return nil, nil
 	}
-	return resources.Group{
-		Location: to.StringPtr(s.Location),
+	return armresources.ResourceGroup{
+		Location: to.Ptr(s.Location),
 		// User defined additional tags are created with the resource group and updated using tags service.
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: s.ClusterName,
 			Lifecycle:   infrav1.ResourceLifecycleOwned,
-			Name:        to.StringPtr(s.Name),
-			Role:        to.StringPtr(infrav1.CommonRole),
+			Name:        to.Ptr(s.Name),
+			Role:        to.Ptr(infrav1.CommonRole),
 			Additional:  s.AdditionalTags,
 		})),
 	}, nil
@@ -19,37 +19,41 @@ package identities
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/msi/mgmt/2018-11-30/msi"
-	"github.com/Azure/go-autorest/autorest"
-	azuresdk "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // AzureClient contains the Azure go-sdk Client.
 type AzureClient struct {
-	userAssignedIdentities msi.UserAssignedIdentitiesClient
+	userAssignedIdentities *armmsi.UserAssignedIdentitiesClient
 }
 
 // NewClient creates a new MSI client from auth info.
-func NewClient(auth azure.Authorizer) *AzureClient {
-	c := newUserAssignedIdentitiesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
-	return &AzureClient{c}
-}
-
-// newUserAssignedIdentitiesClient creates a new MSI client from subscription ID, base URI, and authorizer.
-func newUserAssignedIdentitiesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) msi.UserAssignedIdentitiesClient {
-	userAssignedIdentitiesClient := msi.NewUserAssignedIdentitiesClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&userAssignedIdentitiesClient.Client, authorizer)
-	return userAssignedIdentitiesClient
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armmsi.NewUserAssignedIdentitiesClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create user assigned identities client")
+	}
+	return &AzureClient{c}, nil
 }
 
 // Get returns a managed service identity.
-func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, name string) (msi.Identity, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, name string) (armmsi.Identity, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "identities.AzureClient.Get")
 	defer done()
 
-	return ac.userAssignedIdentities.Get(ctx, resourceGroupName, name)
+	resp, err := ac.userAssignedIdentities.Get(ctx, resourceGroupName, name, nil)
+	if err != nil {
+		return armmsi.Identity{}, err
+	}
+	return resp.Identity, nil
 }
 
 // GetClientID returns the client ID of a managed service identity, given its full URL identifier.
@@ -57,13 +61,16 @@ func (ac *AzureClient) GetClientID(ctx context.Context, providerID string) (stri
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "identities.GetClientID")
 	defer done()
 
-	parsed, err := azuresdk.ParseResourceID(providerID)
+	parsed, err := azure.ParseResourceID(providerID)
 	if err != nil {
 		return "", err
 	}
-	ident, err := ac.Get(ctx, parsed.ResourceGroup, parsed.ResourceName)
+	ident, err := ac.Get(ctx, parsed.ResourceGroupName, parsed.Name)
 	if err != nil {
 		return "", err
 	}
-	return ident.ClientID.String(), nil
+	if ident.Properties == nil {
+		return "", errors.Errorf("identity %s has no properties", providerID)
+	}
+	return pointer.StringDeref(ident.Properties.ClientID, ""), nil
 }
@@ -45,13 +45,16 @@ type Service struct {
 }
 
 // New creates a new service.
-func New(scope InboundNatScope) *Service {
-	client := newClient(scope)
+func New(scope InboundNatScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:      scope,
 		client:     client,
-		Reconciler: async.New(scope, client, client),
-	}
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
 }
 
 // Name returns the service name.
@@ -82,7 +85,9 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	portsInUse := make(map[int32]struct{})
 	for _, rule := range existingRules {
-		portsInUse[*rule.InboundNatRulePropertiesFormat.FrontendPort] = struct{}{} // Mark frontend port as in use
+		if rule.Properties != nil && rule.Properties.FrontendPort != nil {
+			portsInUse[*rule.Properties.FrontendPort] = struct{}{} // Mark frontend port as in use
+		}
 	}
 
 	specs := s.Scope.InboundNatSpecs(portsInUse)
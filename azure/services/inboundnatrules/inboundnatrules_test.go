@@ -18,12 +18,11 @@ package inboundnatrules
 
 import (
 	"context"
-	"net/http"
+	"errors"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"k8s.io/utils/pointer"
@@ -39,20 +38,20 @@ var (
 	fakeGroupName = "my-rg"
 
 	noPortsInUse      = getFakeExistingPortsInUse([]int{})
-	noExistingRules   = []network.InboundNatRule{}
-	fakeExistingRules = []network.InboundNatRule{
+	noExistingRules   = []armnetwork.InboundNatRule{}
+	fakeExistingRules = []armnetwork.InboundNatRule{
 		{
 			Name: pointer.StringPtr("other-machine-nat-rule"),
 			ID:   pointer.StringPtr("some-natrules-id"),
-			InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
-				FrontendPort: to.Int32Ptr(22),
+			Properties: &armnetwork.InboundNatRulePropertiesFormat{
+				FrontendPort: to.Ptr[int32](22),
 			},
 		},
 		{
 			Name: pointer.StringPtr("other-machine-nat-rule-2"),
 			ID:   pointer.StringPtr("some-natrules-id-2"),
-			InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
-				FrontendPort: to.Int32Ptr(2201),
+			Properties: &armnetwork.InboundNatRulePropertiesFormat{
+				FrontendPort: to.Ptr[int32](2201),
 			},
 		},
 	}
@@ -62,17 +61,17 @@ var (
 		Name:                      "my-machine-1",
 		LoadBalancerName:          "my-lb-1",
 		ResourceGroup:             fakeGroupName,
-		FrontendIPConfigurationID: to.StringPtr("frontend-ip-config-id-2"),
+		FrontendIPConfigurationID: to.Ptr("frontend-ip-config-id-2"),
 		PortsInUse:                noPortsInUse,
 	}
 	fakeNatSpec = InboundNatSpec{
 		Name:                      "my-machine-2",
 		LoadBalancerName:          "my-lb-2",
 		ResourceGroup:             fakeGroupName,
-		FrontendIPConfigurationID: to.StringPtr("frontend-ip-config-id-2"),
+		FrontendIPConfigurationID: to.Ptr("frontend-ip-config-id-2"),
 		PortsInUse:                somePortsInUse,
 	}
-	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+	internalError = errors.New("Internal Server Error")
 )
 
 func getFakeExistingPortsInUse(usedPorts []int) map[int32]struct{} {
@@ -147,19 +146,19 @@ func TestReconcileInboundNATRule(t *testing.T) {
 		},
 		{
 			name:          "fail to get existing rules",
-			expectedError: "failed to get existing NAT rules: #: Internal Server Error: StatusCode=500",
+			expectedError: "failed to get existing NAT rules: Internal Server Error",
 			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
 				m *mock_inboundnatrules.MockclientMockRecorder,
 				r *mock_async.MockReconcilerMockRecorder) {
 				s.ResourceGroup().AnyTimes().Return(fakeGroupName)
 				s.APIServerLBName().AnyTimes().Return("my-lb")
 				m.List(gomockinternal.AContext(), fakeGroupName, "my-lb").Return(nil, internalError)
-				s.UpdatePutStatus(infrav1.InboundNATRulesReadyCondition, serviceName, gomockinternal.ErrStrEq("failed to get existing NAT rules: #: Internal Server Error: StatusCode=500"))
+				s.UpdatePutStatus(infrav1.InboundNATRulesReadyCondition, serviceName, gomockinternal.ErrStrEq("failed to get existing NAT rules: Internal Server Error"))
 			},
 		},
 		{
 			name:          "fail to create NAT rule",
-			expectedError: "#: Internal Server Error: StatusCode=500",
+			expectedError: "Internal Server Error",
 			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
 				m *mock_inboundnatrules.MockclientMockRecorder,
 				r *mock_async.MockReconcilerMockRecorder) {
@@ -236,7 +235,7 @@ func TestDeleteNetworkInterface(t *testing.T) {
 		},
 		{
 			name:          "NAT rule deletion fails",
-			expectedError: "#: Internal Server Error: StatusCode=500",
+			expectedError: "Internal Server Error",
 			expect: func(s *mock_inboundnatrules.MockInboundNatScopeMockRecorder,
 				m *mock_inboundnatrules.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.InboundNatSpecs(noPortsInUse).Return([]azure.ResourceSpecGetter{&fakeNatSpecWithNoExisting})
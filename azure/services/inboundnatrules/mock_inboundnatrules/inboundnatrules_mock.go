@@ -23,7 +23,7 @@ package mock_inboundnatrules
 import (
 	reflect "reflect"
 
-	autorest "github.com/Azure/go-autorest/autorest"
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	gomock "github.com/golang/mock/gomock"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -81,18 +81,18 @@ func (mr *MockInboundNatScopeMockRecorder) AdditionalTags() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockInboundNatScope)(nil).AdditionalTags))
 }
 
-// Authorizer mocks base method.
-func (m *MockInboundNatScope) Authorizer() autorest.Authorizer {
+// AttachedACRs mocks base method.
+func (m *MockInboundNatScope) AttachedACRs() []string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authorizer")
-	ret0, _ := ret[0].(autorest.Authorizer)
+	ret := m.ctrl.Call(m, "AttachedACRs")
+	ret0, _ := ret[0].([]string)
 	return ret0
 }
 
-// Authorizer indicates an expected call of Authorizer.
-func (mr *MockInboundNatScopeMockRecorder) Authorizer() *gomock.Call {
+// AttachedACRs indicates an expected call of AttachedACRs.
+func (mr *MockInboundNatScopeMockRecorder) AttachedACRs() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockInboundNatScope)(nil).Authorizer))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachedACRs", reflect.TypeOf((*MockInboundNatScope)(nil).AttachedACRs))
 }
 
 // AvailabilitySetEnabled mocks base method.
@@ -123,6 +123,34 @@ func (mr *MockInboundNatScopeMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockInboundNatScope)(nil).BaseURI))
 }
 
+// BootstrapDataStorage mocks base method.
+func (m *MockInboundNatScope) BootstrapDataStorage() *v1beta1.BootstrapDataStorage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorage")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataStorage)
+	return ret0
+}
+
+// BootstrapDataStorage indicates an expected call of BootstrapDataStorage.
+func (mr *MockInboundNatScopeMockRecorder) BootstrapDataStorage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorage", reflect.TypeOf((*MockInboundNatScope)(nil).BootstrapDataStorage))
+}
+
+// BootstrapExtension mocks base method.
+func (m *MockInboundNatScope) BootstrapExtension() *v1beta1.BootstrapExtension {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapExtension")
+	ret0, _ := ret[0].(*v1beta1.BootstrapExtension)
+	return ret0
+}
+
+// BootstrapExtension indicates an expected call of BootstrapExtension.
+func (mr *MockInboundNatScopeMockRecorder) BootstrapExtension() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapExtension", reflect.TypeOf((*MockInboundNatScope)(nil).BootstrapExtension))
+}
+
 // ClientID mocks base method.
 func (m *MockInboundNatScope) ClientID() string {
 	m.ctrl.T.Helper()
@@ -193,6 +221,20 @@ func (mr *MockInboundNatScopeMockRecorder) ClusterName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockInboundNatScope)(nil).ClusterName))
 }
 
+// CrossSubscriptionAccess mocks base method.
+func (m *MockInboundNatScope) CrossSubscriptionAccess() v1beta1.CrossSubscriptionAccessPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CrossSubscriptionAccess")
+	ret0, _ := ret[0].(v1beta1.CrossSubscriptionAccessPolicy)
+	return ret0
+}
+
+// CrossSubscriptionAccess indicates an expected call of CrossSubscriptionAccess.
+func (mr *MockInboundNatScopeMockRecorder) CrossSubscriptionAccess() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CrossSubscriptionAccess", reflect.TypeOf((*MockInboundNatScope)(nil).CrossSubscriptionAccess))
+}
+
 // DeleteLongRunningOperationState mocks base method.
 func (m *MockInboundNatScope) DeleteLongRunningOperationState(arg0, arg1 string) {
 	m.ctrl.T.Helper()
@@ -205,6 +247,20 @@ func (mr *MockInboundNatScopeMockRecorder) DeleteLongRunningOperationState(arg0,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockInboundNatScope)(nil).DeleteLongRunningOperationState), arg0, arg1)
 }
 
+// ExtendedLocation mocks base method.
+func (m *MockInboundNatScope) ExtendedLocation() *v1beta1.ExtendedLocationSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendedLocation")
+	ret0, _ := ret[0].(*v1beta1.ExtendedLocationSpec)
+	return ret0
+}
+
+// ExtendedLocation indicates an expected call of ExtendedLocation.
+func (mr *MockInboundNatScopeMockRecorder) ExtendedLocation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendedLocation", reflect.TypeOf((*MockInboundNatScope)(nil).ExtendedLocation))
+}
+
 // FailureDomains mocks base method.
 func (m *MockInboundNatScope) FailureDomains() []string {
 	m.ctrl.T.Helper()
@@ -275,6 +331,48 @@ func (mr *MockInboundNatScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockInboundNatScope)(nil).Location))
 }
 
+// Monitoring mocks base method.
+func (m *MockInboundNatScope) Monitoring() *v1beta1.AzureMonitoring {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Monitoring")
+	ret0, _ := ret[0].(*v1beta1.AzureMonitoring)
+	return ret0
+}
+
+// Monitoring indicates an expected call of Monitoring.
+func (mr *MockInboundNatScopeMockRecorder) Monitoring() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Monitoring", reflect.TypeOf((*MockInboundNatScope)(nil).Monitoring))
+}
+
+// ProxyConfig mocks base method.
+func (m *MockInboundNatScope) ProxyConfig() *v1beta1.ProxyConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyConfig")
+	ret0, _ := ret[0].(*v1beta1.ProxyConfig)
+	return ret0
+}
+
+// ProxyConfig indicates an expected call of ProxyConfig.
+func (mr *MockInboundNatScopeMockRecorder) ProxyConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyConfig", reflect.TypeOf((*MockInboundNatScope)(nil).ProxyConfig))
+}
+
+// RegistryMirrors mocks base method.
+func (m *MockInboundNatScope) RegistryMirrors() []v1beta1.RegistryMirror {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryMirrors")
+	ret0, _ := ret[0].([]v1beta1.RegistryMirror)
+	return ret0
+}
+
+// RegistryMirrors indicates an expected call of RegistryMirrors.
+func (mr *MockInboundNatScopeMockRecorder) RegistryMirrors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryMirrors", reflect.TypeOf((*MockInboundNatScope)(nil).RegistryMirrors))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockInboundNatScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -289,6 +387,20 @@ func (mr *MockInboundNatScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockInboundNatScope)(nil).ResourceGroup))
 }
 
+// SecurityProfile mocks base method.
+func (m *MockInboundNatScope) SecurityProfile() *v1beta1.ClusterSecurityProfile {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecurityProfile")
+	ret0, _ := ret[0].(*v1beta1.ClusterSecurityProfile)
+	return ret0
+}
+
+// SecurityProfile indicates an expected call of SecurityProfile.
+func (mr *MockInboundNatScopeMockRecorder) SecurityProfile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityProfile", reflect.TypeOf((*MockInboundNatScope)(nil).SecurityProfile))
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockInboundNatScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -329,6 +441,20 @@ func (mr *MockInboundNatScopeMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockInboundNatScope)(nil).TenantID))
 }
 
+// Token mocks base method.
+func (m *MockInboundNatScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockInboundNatScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockInboundNatScope)(nil).Token))
+}
+
 // UpdateDeleteStatus mocks base method.
 func (m *MockInboundNatScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
 	m.ctrl.T.Helper()
@@ -17,8 +17,8 @@ limitations under the License.
 package inboundnatrules
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
 )
 
@@ -49,8 +49,8 @@ func (s *InboundNatSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the inbound NAT rule.
 func (s *InboundNatSpec) Parameters(existing interface{}) (parameters interface{}, err error) {
 	if existing != nil {
-		if _, ok := existing.(network.InboundNatRule); !ok {
-			return nil, errors.Errorf("%T is not a network.InboundNatRule", existing)
+		if _, ok := existing.(armnetwork.InboundNatRule); !ok {
+			return nil, errors.Errorf("%T is not an armnetwork.InboundNatRule", existing)
 		}
 
 		return nil, nil
@@ -65,16 +65,16 @@ func (s *InboundNatSpec) Parameters(existing interface{}) (parameters interface{
 		return nil, errors.Wrapf(err, "failed to find available SSH Frontend port for NAT Rule %s in load balancer %s", s.ResourceName(), s.OwnerResourceName())
 	}
 
-	rule := network.InboundNatRule{
-		Name: to.StringPtr(s.ResourceName()),
-		InboundNatRulePropertiesFormat: &network.InboundNatRulePropertiesFormat{
-			BackendPort:          to.Int32Ptr(22),
-			EnableFloatingIP:     to.BoolPtr(false),
-			IdleTimeoutInMinutes: to.Int32Ptr(4),
-			FrontendIPConfiguration: &network.SubResource{
+	rule := armnetwork.InboundNatRule{
+		Name: to.Ptr(s.ResourceName()),
+		Properties: &armnetwork.InboundNatRulePropertiesFormat{
+			BackendPort:          to.Ptr[int32](22),
+			EnableFloatingIP:     to.Ptr(false),
+			IdleTimeoutInMinutes: to.Ptr[int32](4),
+			FrontendIPConfiguration: &armnetwork.SubResource{
 				ID: s.FrontendIPConfigurationID,
 			},
-			Protocol:     network.TransportProtocolTCP,
+			Protocol:     to.Ptr(armnetwork.TransportProtocolTCP),
 			FrontendPort: &sshFrontendPort,
 		},
 	}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jitnetworkaccesspolicies
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/security/armsecurity"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, azure.ResourceSpecGetter) (result interface{}, err error)
+	CreateOrUpdateAsync(context.Context, azure.ResourceSpecGetter, string, interface{}) (result interface{}, poller azure.Poller, err error)
+	DeleteAsync(context.Context, azure.ResourceSpecGetter, string) (poller azure.Poller, err error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	policies *armsecurity.JitNetworkAccessPoliciesClient
+	location string
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new just-in-time network access policies client from subscription ID, for the policies of a
+// location.
+func newClient(auth azure.Authorizer, location string) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armsecurity.NewJitNetworkAccessPoliciesClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create just-in-time network access policies client")
+	}
+	return &azureClient{policies: c, location: location}, nil
+}
+
+// Get gets the specified just-in-time network access policy.
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "jitnetworkaccesspolicies.azureClient.Get")
+	defer done()
+
+	resp, err := ac.policies.Get(ctx, spec.ResourceGroupName(), ac.location, spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.JitNetworkAccessPolicy, nil
+}
+
+// CreateOrUpdateAsync creates or updates a just-in-time network access policy.
+// Policies are created synchronously, so we don't ever return a poller.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "jitnetworkaccesspolicies.azureClient.CreateOrUpdateAsync")
+	defer done()
+
+	policy, ok := parameters.(armsecurity.JitNetworkAccessPolicy)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not an armsecurity.JitNetworkAccessPolicy", parameters)
+	}
+
+	resp, err := ac.policies.CreateOrUpdate(ctx, spec.ResourceGroupName(), ac.location, spec.ResourceName(), policy, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.JitNetworkAccessPolicy, nil, nil
+}
+
+// DeleteAsync deletes a just-in-time network access policy.
+// Policies are deleted synchronously, so we don't ever return a poller.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "jitnetworkaccesspolicies.azureClient.DeleteAsync")
+	defer done()
+
+	_, err = ac.policies.Delete(ctx, spec.ResourceGroupName(), ac.location, spec.ResourceName(), nil)
+	return nil, err
+}
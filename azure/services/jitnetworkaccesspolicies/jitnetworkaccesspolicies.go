@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jitnetworkaccesspolicies
+
+import (
+	"context"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "jitnetworkaccesspolicies"
+
+// JITNetworkAccessPolicyScope defines the scope interface for a just-in-time network access policies service.
+type JITNetworkAccessPolicyScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	Location() string
+	JITNetworkAccessPolicySpecs() []azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope JITNetworkAccessPolicyScope
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope JITNetworkAccessPolicyScope) (*Service, error) {
+	client, err := newClient(scope, scope.Location())
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:      scope,
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile creates or updates the just-in-time network access policy of the virtual machine, so that Defender for
+// Cloud denies access to its management ports until it is requested and approved.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "jitnetworkaccesspolicies.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.JITNetworkAccessPolicySpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	// We go through the list of JITNetworkAccessPolicySpecs to reconcile each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+	var result error
+	for _, policySpec := range specs {
+		if _, err := s.CreateResource(ctx, policySpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.JITNetworkAccessPolicyReadyCondition, serviceName, result)
+	return result
+}
+
+// Delete deletes the just-in-time network access policy of the virtual machine before the virtual machine is deleted.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "jitnetworkaccesspolicies.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.JITNetworkAccessPolicySpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	var result error
+	for _, policySpec := range specs {
+		if err := s.DeleteResource(ctx, policySpec, serviceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdateDeleteStatus(infrav1.JITNetworkAccessPolicyReadyCondition, serviceName, result)
+	return result
+}
+
+// IsManaged always returns true as the just-in-time network access policies of the virtual machines are only created
+// by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jitnetworkaccesspolicies
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/jitnetworkaccesspolicies/mock_jitnetworkaccesspolicies"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeJITNetworkAccessPolicySpec = JITNetworkAccessPolicySpec{
+		Name:          "my-vm",
+		ResourceGroup: "my-rg",
+		VMID:          "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm",
+		Ports:         []infrav1.JustInTimeAccessPort{{Number: 22}},
+	}
+
+	internalError = errors.New("Internal Server Error")
+)
+
+func TestReconcileJITNetworkAccessPolicies(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_jitnetworkaccesspolicies.MockJITNetworkAccessPolicyScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the cluster doesn't protect its virtual machines",
+			expectedError: "",
+			expect: func(s *mock_jitnetworkaccesspolicies.MockJITNetworkAccessPolicyScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.JITNetworkAccessPolicySpecs().Return(nil)
+			},
+		},
+		{
+			name:          "create the policy of the virtual machine",
+			expectedError: "",
+			expect: func(s *mock_jitnetworkaccesspolicies.MockJITNetworkAccessPolicyScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.JITNetworkAccessPolicySpecs().Return([]azure.ResourceSpecGetter{&fakeJITNetworkAccessPolicySpec})
+				r.CreateResource(gomockinternal.AContext(), &fakeJITNetworkAccessPolicySpec, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.JITNetworkAccessPolicyReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to create the policy of the virtual machine",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_jitnetworkaccesspolicies.MockJITNetworkAccessPolicyScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.JITNetworkAccessPolicySpecs().Return([]azure.ResourceSpecGetter{&fakeJITNetworkAccessPolicySpec})
+				r.CreateResource(gomockinternal.AContext(), &fakeJITNetworkAccessPolicySpec, serviceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.JITNetworkAccessPolicyReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_jitnetworkaccesspolicies.NewMockJITNetworkAccessPolicyScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteJITNetworkAccessPolicies(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_jitnetworkaccesspolicies.MockJITNetworkAccessPolicyScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the cluster doesn't protect its virtual machines",
+			expectedError: "",
+			expect: func(s *mock_jitnetworkaccesspolicies.MockJITNetworkAccessPolicyScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.JITNetworkAccessPolicySpecs().Return(nil)
+			},
+		},
+		{
+			name:          "delete the policy of the virtual machine",
+			expectedError: "",
+			expect: func(s *mock_jitnetworkaccesspolicies.MockJITNetworkAccessPolicyScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.JITNetworkAccessPolicySpecs().Return([]azure.ResourceSpecGetter{&fakeJITNetworkAccessPolicySpec})
+				r.DeleteResource(gomockinternal.AContext(), &fakeJITNetworkAccessPolicySpec, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.JITNetworkAccessPolicyReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to delete the policy of the virtual machine",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_jitnetworkaccesspolicies.MockJITNetworkAccessPolicyScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.JITNetworkAccessPolicySpecs().Return([]azure.ResourceSpecGetter{&fakeJITNetworkAccessPolicySpec})
+				r.DeleteResource(gomockinternal.AContext(), &fakeJITNetworkAccessPolicySpec, serviceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.JITNetworkAccessPolicyReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_jitnetworkaccesspolicies.NewMockJITNetworkAccessPolicyScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jitnetworkaccesspolicies
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/security/armsecurity"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// policyKind is the kind of the just-in-time network access policies.
+const policyKind = "Basic"
+
+// JITNetworkAccessPolicySpec defines the specification for the just-in-time network access policy of a virtual machine.
+type JITNetworkAccessPolicySpec struct {
+	Name          string
+	ResourceGroup string
+	VMID          string
+	Ports         []infrav1.JustInTimeAccessPort
+}
+
+// ResourceName returns the name of the just-in-time network access policy.
+func (s *JITNetworkAccessPolicySpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group of the just-in-time network access policy.
+func (s *JITNetworkAccessPolicySpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for just-in-time network access policies.
+func (s *JITNetworkAccessPolicySpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the just-in-time network access policy of the virtual machine.
+func (s *JITNetworkAccessPolicySpec) Parameters(existing interface{}) (params interface{}, err error) {
+	rules := make([]*armsecurity.JitNetworkAccessPortRule, 0, len(s.Ports))
+	for _, port := range s.Ports {
+		rule := &armsecurity.JitNetworkAccessPortRule{
+			Number:                   to.Ptr(port.Number),
+			Protocol:                 to.Ptr(armsecurity.Protocol(port.GetProtocol())),
+			MaxRequestAccessDuration: to.Ptr(isoDuration(port.GetMaxRequestAccessDuration())),
+		}
+		// allowedSourceAddressPrefix and allowedSourceAddressPrefixes are mutually exclusive.
+		if len(port.AllowedSourceAddressPrefixes) == 0 {
+			rule.AllowedSourceAddressPrefix = to.Ptr("*")
+		} else {
+			rule.AllowedSourceAddressPrefixes = to.SliceOfPtrs(port.AllowedSourceAddressPrefixes...)
+		}
+		rules = append(rules, rule)
+	}
+
+	if existing != nil {
+		existingPolicy, ok := existing.(armsecurity.JitNetworkAccessPolicy)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armsecurity.JitNetworkAccessPolicy", existing)
+		}
+		if s.isUpToDate(existingPolicy, rules) {
+			// Skip update for the policy as it exists with expected values
+			return nil, nil
+		}
+	}
+
+	return armsecurity.JitNetworkAccessPolicy{
+		Kind: to.Ptr(policyKind),
+		Properties: &armsecurity.JitNetworkAccessPolicyProperties{
+			VirtualMachines: []*armsecurity.JitNetworkAccessPolicyVirtualMachine{
+				{
+					ID:    to.Ptr(s.VMID),
+					Ports: rules,
+				},
+			},
+		},
+	}, nil
+}
+
+// isUpToDate returns whether an existing policy protects the ports of the virtual machine with the expected rules.
+func (s *JITNetworkAccessPolicySpec) isUpToDate(existing armsecurity.JitNetworkAccessPolicy, rules []*armsecurity.JitNetworkAccessPortRule) bool {
+	if existing.Properties == nil || len(existing.Properties.VirtualMachines) != 1 || existing.Properties.VirtualMachines[0] == nil {
+		return false
+	}
+	vm := existing.Properties.VirtualMachines[0]
+	if !strings.EqualFold(pointer.StringDeref(vm.ID, ""), s.VMID) || len(vm.Ports) != len(rules) {
+		return false
+	}
+	for i, rule := range rules {
+		existingRule := vm.Ports[i]
+		if existingRule == nil ||
+			pointer.Int32Deref(existingRule.Number, 0) != pointer.Int32Deref(rule.Number, 0) ||
+			!strings.EqualFold(pointer.StringDeref((*string)(existingRule.Protocol), ""), pointer.StringDeref((*string)(rule.Protocol), "")) ||
+			!strings.EqualFold(pointer.StringDeref(existingRule.MaxRequestAccessDuration, ""), pointer.StringDeref(rule.MaxRequestAccessDuration, "")) ||
+			pointer.StringDeref(existingRule.AllowedSourceAddressPrefix, "") != pointer.StringDeref(rule.AllowedSourceAddressPrefix, "") ||
+			strings.Join(derefStrings(existingRule.AllowedSourceAddressPrefixes), ",") != strings.Join(derefStrings(rule.AllowedSourceAddressPrefixes), ",") {
+			return false
+		}
+	}
+	return true
+}
+
+// derefStrings returns the values of a slice of string pointers, skipping nil ones.
+func derefStrings(values []*string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != nil {
+			result = append(result, *value)
+		}
+	}
+	return result
+}
+
+// isoDuration returns a duration, in whole minutes, in the ISO 8601 format of the Defender for Cloud API, e.g. PT1H30M.
+func isoDuration(d time.Duration) string {
+	minutes := int64(d / time.Minute)
+	duration := "PT"
+	if hours := minutes / 60; hours > 0 {
+		duration += fmt.Sprintf("%dH", hours)
+	}
+	if minutes%60 > 0 || minutes == 0 {
+		duration += fmt.Sprintf("%dM", minutes%60)
+	}
+	return duration
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jitnetworkaccesspolicies
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/security/armsecurity"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+// fakeJITNetworkAccessPolicy returns the policy of the virtual machine protecting the given ports.
+func fakeJITNetworkAccessPolicy(rules ...*armsecurity.JitNetworkAccessPortRule) armsecurity.JitNetworkAccessPolicy {
+	return armsecurity.JitNetworkAccessPolicy{
+		Kind: to.Ptr("Basic"),
+		Properties: &armsecurity.JitNetworkAccessPolicyProperties{
+			VirtualMachines: []*armsecurity.JitNetworkAccessPolicyVirtualMachine{
+				{
+					ID:    to.Ptr(fakeJITNetworkAccessPolicySpec.VMID),
+					Ports: rules,
+				},
+			},
+		},
+	}
+}
+
+func TestJITNetworkAccessPolicySpecParameters(t *testing.T) {
+	sshRule := &armsecurity.JitNetworkAccessPortRule{
+		Number:                     to.Ptr[int32](22),
+		Protocol:                   to.Ptr(armsecurity.ProtocolTCP),
+		AllowedSourceAddressPrefix: to.Ptr("*"),
+		MaxRequestAccessDuration:   to.Ptr("PT3H"),
+	}
+	customSpec := fakeJITNetworkAccessPolicySpec
+	customSpec.Ports = []infrav1.JustInTimeAccessPort{
+		{
+			Number:                       3389,
+			Protocol:                     infrav1.JustInTimeAccessProtocolAll,
+			AllowedSourceAddressPrefixes: []string{"10.0.0.0/16", "192.168.0.3"},
+			MaxRequestAccessDuration:     &metav1.Duration{Duration: 90 * time.Minute},
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		spec     JITNetworkAccessPolicySpec
+		existing interface{}
+		expected interface{}
+	}{
+		{
+			name:     "create the policy of a new virtual machine",
+			spec:     fakeJITNetworkAccessPolicySpec,
+			expected: fakeJITNetworkAccessPolicy(sshRule),
+		},
+		{
+			name: "create a policy with custom ports",
+			spec: customSpec,
+			expected: fakeJITNetworkAccessPolicy(&armsecurity.JitNetworkAccessPortRule{
+				Number:                       to.Ptr[int32](3389),
+				Protocol:                     to.Ptr(armsecurity.ProtocolAll),
+				AllowedSourceAddressPrefixes: to.SliceOfPtrs("10.0.0.0/16", "192.168.0.3"),
+				MaxRequestAccessDuration:     to.Ptr("PT1H30M"),
+			}),
+		},
+		{
+			name:     "noop if the policy protects the ports of the virtual machine",
+			spec:     fakeJITNetworkAccessPolicySpec,
+			existing: fakeJITNetworkAccessPolicy(sshRule),
+		},
+		{
+			name:     "update the policy if the ports changed",
+			spec:     customSpec,
+			existing: fakeJITNetworkAccessPolicy(sshRule),
+			expected: fakeJITNetworkAccessPolicy(&armsecurity.JitNetworkAccessPortRule{
+				Number:                       to.Ptr[int32](3389),
+				Protocol:                     to.Ptr(armsecurity.ProtocolAll),
+				AllowedSourceAddressPrefixes: to.SliceOfPtrs("10.0.0.0/16", "192.168.0.3"),
+				MaxRequestAccessDuration:     to.Ptr("PT1H30M"),
+			}),
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			params, err := tc.spec.Parameters(tc.existing)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tc.expected == nil {
+				g.Expect(params).To(BeNil())
+			} else {
+				g.Expect(params).To(Equal(tc.expected))
+			}
+		})
+	}
+}
+
+func TestISODuration(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(isoDuration(3 * time.Hour)).To(Equal("PT3H"))
+	g.Expect(isoDuration(90 * time.Minute)).To(Equal("PT1H30M"))
+	g.Expect(isoDuration(5 * time.Minute)).To(Equal("PT5M"))
+	g.Expect(isoDuration(24 * time.Hour)).To(Equal("PT24H"))
+}
@@ -18,9 +18,14 @@ package loadbalancers
 
 import (
 	"context"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -43,16 +48,21 @@ type LBScope interface {
 // Service provides operations on Azure resources.
 type Service struct {
 	Scope LBScope
+	async.Getter
 	async.Reconciler
 }
 
 // New creates a new service.
-func New(scope LBScope) *Service {
-	client := newClient(scope)
+func New(scope LBScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:      scope,
-		Reconciler: async.New(scope, client, client),
-	}
+		Getter:     client,
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
 }
 
 // Name returns the service name.
@@ -60,6 +70,17 @@ func (s *Service) Name() string {
 	return serviceName
 }
 
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the load balancers.
+func (s *Service) RequiredActions() []string {
+	for _, spec := range s.Scope.LBSpecs() {
+		if !isExternal(spec) {
+			return []string{"Microsoft.Network/loadBalancers/read", "Microsoft.Network/loadBalancers/write"}
+		}
+	}
+	return nil
+}
+
 // Reconcile gets/creates/updates a load balancer.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "loadbalancers.Service.Reconcile")
@@ -78,7 +99,13 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
 	var result error
 	for _, lbSpec := range specs {
-		if _, err := s.CreateResource(ctx, lbSpec, serviceName); err != nil {
+		var err error
+		if isExternal(lbSpec) {
+			err = s.verifyExternalLB(ctx, lbSpec.(*LBSpec))
+		} else {
+			_, err = s.CreateResource(ctx, lbSpec, serviceName)
+		}
+		if err != nil {
 			if !azure.IsOperationNotDoneError(err) || result == nil {
 				result = err
 			}
@@ -107,6 +134,9 @@ func (s *Service) Delete(ctx context.Context) error {
 	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error deleting) -> operationNotDoneError (i.e. deleting in progress) -> no error (i.e. deleted)
 	var result error
 	for _, lbSpec := range specs {
+		if isExternal(lbSpec) {
+			continue
+		}
 		if err := s.DeleteResource(ctx, lbSpec, serviceName); err != nil {
 			if !azure.IsOperationNotDoneError(err) || result == nil {
 				result = err
@@ -118,7 +148,56 @@ func (s *Service) Delete(ctx context.Context) error {
 	return result
 }
 
-// IsManaged returns always returns true as CAPZ does not support BYO load balancers.
+// ExportResources adds the load balancers to an ARM template.
+func (s *Service) ExportResources(ctx context.Context, template *armtemplate.Template) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "loadbalancers.Service.ExportResources")
+	defer done()
+
+	for _, spec := range s.Scope.LBSpecs() {
+		if isExternal(spec) {
+			continue
+		}
+		if err := template.AddSpec(spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsManaged always returns true, as the service itself skips the external load balancers.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	return true, nil
 }
+
+// verifyExternalLB verifies that an external load balancer and its backend pool exist, as the load balancer is
+// managed outside of the provider and is never updated.
+func (s *Service) verifyExternalLB(ctx context.Context, spec *LBSpec) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "loadbalancers.Service.verifyExternalLB")
+	defer done()
+
+	result, err := s.Get(ctx, spec)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get external load balancer %s", spec.Name)
+	}
+	lb, ok := result.(armnetwork.LoadBalancer)
+	if !ok {
+		return errors.Errorf("%T is not an armnetwork.LoadBalancer", result)
+	}
+
+	if lb.Properties != nil {
+		for _, pool := range lb.Properties.BackendAddressPools {
+			if strings.EqualFold(pointer.StringDeref(pool.Name, ""), spec.BackendPoolName) {
+				log.V(4).Info("verified external load balancer", "loadBalancer", spec.Name, "backendPool", spec.BackendPoolName)
+				return nil
+			}
+		}
+	}
+	return errors.Errorf("backend pool %s of external load balancer %s does not exist", spec.BackendPoolName, spec.Name)
+}
+
+// isExternal returns true if the load balancer is managed outside of the provider.
+func isExternal(spec azure.ResourceSpecGetter) bool {
+	lbSpec, ok := spec.(*LBSpec)
+	return ok && lbSpec.External
+}
@@ -18,11 +18,13 @@ package loadbalancers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -33,6 +35,8 @@ import (
 )
 
 var (
+	notFoundError = &azcore.ResponseError{StatusCode: http.StatusNotFound}
+
 	fakePublicAPILBSpec = LBSpec{
 		Name:                 "my-publiclb",
 		ResourceGroup:        "my-rg",
@@ -44,7 +48,7 @@ var (
 		SKU:                  infrav1.SKUStandard,
 		SubnetName:           "my-cp-subnet",
 		BackendPoolName:      "my-publiclb-backendPool",
-		IdleTimeoutInMinutes: to.Int32Ptr(4),
+		IdleTimeoutInMinutes: to.Ptr[int32](4),
 		FrontendIPConfigs: []infrav1.FrontendIP{
 			{
 				Name: "my-publiclb-frontEnd",
@@ -68,7 +72,7 @@ var (
 		SKU:                  infrav1.SKUStandard,
 		SubnetName:           "my-cp-subnet",
 		BackendPoolName:      "my-private-lb-backendPool",
-		IdleTimeoutInMinutes: to.Int32Ptr(4),
+		IdleTimeoutInMinutes: to.Ptr[int32](4),
 		FrontendIPConfigs: []infrav1.FrontendIP{
 			{
 				Name: "my-private-lb-frontEnd",
@@ -90,7 +94,7 @@ var (
 		Type:                 infrav1.Public,
 		SKU:                  infrav1.SKUStandard,
 		BackendPoolName:      "my-cluster-outboundBackendPool",
-		IdleTimeoutInMinutes: to.Int32Ptr(30),
+		IdleTimeoutInMinutes: to.Ptr[int32](30),
 		FrontendIPConfigs: []infrav1.FrontendIP{
 			{
 				Name: "my-cluster-frontEnd",
@@ -101,7 +105,18 @@ var (
 		},
 	}
 
-	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+	fakeExternalAPILBSpec = LBSpec{
+		Name:            "shared-lb",
+		ResourceGroup:   "network-rg",
+		SubscriptionID:  "123",
+		ClusterName:     "my-cluster",
+		Role:            infrav1.APIServerRole,
+		Type:            infrav1.Public,
+		BackendPoolName: "my-cluster-pool",
+		External:        true,
+	}
+
+	internalError = errors.New("Internal Server Error")
 )
 
 func TestReconcileLoadBalancer(t *testing.T) {
@@ -119,7 +134,7 @@ func TestReconcileLoadBalancer(t *testing.T) {
 		},
 		{
 			name:          "fail to create a public LB",
-			expectedError: "#: Internal Server Error: StatusCode=500",
+			expectedError: "Internal Server Error",
 			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.LBSpecs().Return([]azure.ResourceSpecGetter{&fakePublicAPILBSpec})
 				r.CreateResource(gomockinternal.AContext(), &fakePublicAPILBSpec, serviceName).Return(nil, internalError)
@@ -195,6 +210,86 @@ func TestReconcileLoadBalancer(t *testing.T) {
 	}
 }
 
+func TestReconcileExternalLoadBalancer(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_loadbalancers.MockLBScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "verify external LB without updating it",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.LBSpecs().Return([]azure.ResourceSpecGetter{&fakeExternalAPILBSpec, &fakeNodeOutboundLBSpec})
+				g.Get(gomockinternal.AContext(), &fakeExternalAPILBSpec).Return(armnetwork.LoadBalancer{
+					Properties: &armnetwork.LoadBalancerPropertiesFormat{
+						BackendAddressPools: []*armnetwork.BackendAddressPool{
+							{Name: to.Ptr("other-pool")},
+							{Name: to.Ptr("my-cluster-pool")},
+						},
+					},
+				}, nil)
+				r.CreateResource(gomockinternal.AContext(), &fakeNodeOutboundLBSpec, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.LoadBalancersReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "external LB does not exist",
+			expectedError: "failed to get external load balancer shared-lb: " + notFoundError.Error(),
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.LBSpecs().Return([]azure.ResourceSpecGetter{&fakeExternalAPILBSpec})
+				g.Get(gomockinternal.AContext(), &fakeExternalAPILBSpec).Return(nil, notFoundError)
+				s.UpdatePutStatus(infrav1.LoadBalancersReadyCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name:          "backend pool of external LB does not exist",
+			expectedError: "backend pool my-cluster-pool of external load balancer shared-lb does not exist",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, g *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.LBSpecs().Return([]azure.ResourceSpecGetter{&fakeExternalAPILBSpec})
+				g.Get(gomockinternal.AContext(), &fakeExternalAPILBSpec).Return(armnetwork.LoadBalancer{
+					Properties: &armnetwork.LoadBalancerPropertiesFormat{
+						BackendAddressPools: []*armnetwork.BackendAddressPool{
+							{Name: to.Ptr("other-pool")},
+						},
+					},
+				}, nil)
+				s.UpdatePutStatus(infrav1.LoadBalancersReadyCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_loadbalancers.NewMockLBScope(mockCtrl)
+			getterMock := mock_async.NewMockGetter(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), getterMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Getter:     getterMock,
+				Reconciler: asyncMock,
+			}
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestDeleteLoadBalancer(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -228,9 +323,18 @@ func TestDeleteLoadBalancer(t *testing.T) {
 				s.UpdateDeleteStatus(infrav1.LoadBalancersReadyCondition, serviceName, nil)
 			},
 		},
+		{
+			name:          "external load balancer is not deleted",
+			expectedError: "",
+			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.LBSpecs().Return([]azure.ResourceSpecGetter{&fakeExternalAPILBSpec, &fakeNodeOutboundLBSpec})
+				r.DeleteResource(gomockinternal.AContext(), &fakeNodeOutboundLBSpec, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.LoadBalancersReadyCondition, serviceName, nil)
+			},
+		},
 		{
 			name:          "load balancer deletion fails",
-			expectedError: "#: Internal Server Error: StatusCode=500",
+			expectedError: "Internal Server Error",
 			expect: func(s *mock_loadbalancers.MockLBScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.LBSpecs().Return([]azure.ResourceSpecGetter{&fakePublicAPILBSpec})
 				r.DeleteResource(gomockinternal.AContext(), &fakePublicAPILBSpec, serviceName).Return(internalError)
@@ -17,9 +17,12 @@ limitations under the License.
 package loadbalancers
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
@@ -32,9 +35,11 @@ type LBSpec struct {
 	SubscriptionID       string
 	ClusterName          string
 	Location             string
+	ExtendedLocation     *infrav1.ExtendedLocationSpec
 	Role                 string
 	Type                 infrav1.LBType
 	SKU                  infrav1.SKU
+	Tier                 infrav1.SKUTier
 	VNetName             string
 	VNetResourceGroup    string
 	SubnetName           string
@@ -42,7 +47,20 @@ type LBSpec struct {
 	FrontendIPConfigs    []infrav1.FrontendIP
 	APIServerPort        int32
 	IdleTimeoutInMinutes *int32
+	EnableTCPReset       *bool
+	LoadDistribution     infrav1.LoadDistribution
 	AdditionalTags       map[string]string
+	// FrontendPort is the port of the frontend IP of an API server load balancer, which forwards to APIServerPort.
+	// Defaults to APIServerPort.
+	FrontendPort int32
+	// AliasFrontends are the additional frontends of an API server load balancer, which forward to APIServerPort too.
+	AliasFrontends []infrav1.APIServerAliasFrontend
+	// RegionalFrontendIPConfigIDs are the frontend IP configurations of the regional load balancers in the backend pool
+	// of a Global tier load balancer.
+	RegionalFrontendIPConfigIDs []string
+	// External is true if the load balancer is managed outside of the provider, which never creates, updates or
+	// deletes it.
+	External bool
 }
 
 // ResourceName returns the name of the load balancer.
@@ -62,61 +80,77 @@ func (s *LBSpec) OwnerResourceName() string {
 
 // Parameters returns the parameters for the load balancer.
 func (s *LBSpec) Parameters(existing interface{}) (parameters interface{}, err error) {
+	if s.External {
+		return nil, errors.Errorf("load balancer %s is external and cannot be updated", s.Name)
+	}
+
 	var (
 		etag                *string
-		frontendIDs         []network.SubResource
-		frontendIPConfigs   = make([]network.FrontendIPConfiguration, 0)
-		loadBalancingRules  = make([]network.LoadBalancingRule, 0)
-		backendAddressPools = make([]network.BackendAddressPool, 0)
-		outboundRules       = make([]network.OutboundRule, 0)
-		probes              = make([]network.Probe, 0)
+		frontendIDs         []*armnetwork.SubResource
+		frontendIPConfigs   = make([]*armnetwork.FrontendIPConfiguration, 0)
+		loadBalancingRules  = make([]*armnetwork.LoadBalancingRule, 0)
+		backendAddressPools = make([]*armnetwork.BackendAddressPool, 0)
+		outboundRules       = make([]*armnetwork.OutboundRule, 0)
+		probes              = make([]*armnetwork.Probe, 0)
 	)
 
 	if existing != nil {
-		existingLB, ok := existing.(network.LoadBalancer)
+		existingLB, ok := existing.(armnetwork.LoadBalancer)
 		if !ok {
-			return nil, errors.Errorf("%T is not a network.LoadBalancer", existing)
+			return nil, errors.Errorf("%T is not an armnetwork.LoadBalancer", existing)
 		}
 		// LB already exists
 		// We append the existing LB etag to the header to ensure we only apply the updates if the LB has not been modified.
 		etag = existingLB.Etag
 		update := false
+		existingProperties := existingLB.Properties
+		if existingProperties == nil {
+			existingProperties = &armnetwork.LoadBalancerPropertiesFormat{}
+		}
 
 		// merge existing LB properties with desired properties
-		frontendIPConfigs = *existingLB.FrontendIPConfigurations
+		frontendIPConfigs = existingProperties.FrontendIPConfigurations
 		wantedIPs, wantedFrontendIDs := getFrontendIPConfigs(*s)
 		for _, ip := range wantedIPs {
 			if !ipExists(frontendIPConfigs, ip) {
 				update = true
 				frontendIPConfigs = append(frontendIPConfigs, ip)
+			} else if updateGatewayLoadBalancer(frontendIPConfigs, ip) {
+				update = true
 			}
 		}
 
-		loadBalancingRules = *existingLB.LoadBalancingRules
+		loadBalancingRules = existingProperties.LoadBalancingRules
 		for _, rule := range getLoadBalancingRules(*s, wantedFrontendIDs) {
 			if !lbRuleExists(loadBalancingRules, rule) {
 				update = true
 				loadBalancingRules = append(loadBalancingRules, rule)
+			} else if updateLBRuleConnectionSettings(loadBalancingRules, rule) {
+				update = true
 			}
 		}
 
-		backendAddressPools = *existingLB.BackendAddressPools
+		backendAddressPools = existingProperties.BackendAddressPools
 		for _, pool := range getBackendAddressPools(*s) {
 			if !poolExists(backendAddressPools, pool) {
 				update = true
 				backendAddressPools = append(backendAddressPools, pool)
+			} else if s.Tier == infrav1.SKUTierGlobal && updateRegionalBackendAddresses(backendAddressPools, pool) {
+				update = true
 			}
 		}
 
-		outboundRules = *existingLB.OutboundRules
+		outboundRules = existingProperties.OutboundRules
 		for _, rule := range getOutboundRules(*s, wantedFrontendIDs) {
 			if !outboundRuleExists(outboundRules, rule) {
 				update = true
 				outboundRules = append(outboundRules, rule)
+			} else if updateOutboundRuleTCPReset(outboundRules, rule) {
+				update = true
 			}
 		}
 
-		probes = *existingLB.Probes
+		probes = existingProperties.Probes
 		for _, probe := range getProbes(*s) {
 			if !probeExists(probes, probe) {
 				update = true
@@ -136,175 +170,408 @@ func (s *LBSpec) Parameters(existing interface{}) (parameters interface{}, err e
 		probes = getProbes(*s)
 	}
 
-	lb := network.LoadBalancer{
-		Etag:     etag,
-		Sku:      &network.LoadBalancerSku{Name: converters.SKUtoSDK(s.SKU)},
-		Location: to.StringPtr(s.Location),
+	lb := armnetwork.LoadBalancer{
+		Etag:             etag,
+		SKU:              &armnetwork.LoadBalancerSKU{Name: converters.SKUtoSDK(s.SKU), Tier: converters.SKUTierToSDK(s.Tier)},
+		Location:         to.Ptr(s.Location),
+		ExtendedLocation: converters.ExtendedLocationToNetworkSDK(s.ExtendedLocation),
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: s.ClusterName,
 			Lifecycle:   infrav1.ResourceLifecycleOwned,
-			Role:        to.StringPtr(s.Role),
+			Role:        to.Ptr(s.Role),
 			Additional:  s.AdditionalTags,
 		})),
-		LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
-			FrontendIPConfigurations: &frontendIPConfigs,
-			BackendAddressPools:      &backendAddressPools,
-			OutboundRules:            &outboundRules,
-			Probes:                   &probes,
-			LoadBalancingRules:       &loadBalancingRules,
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: frontendIPConfigs,
+			BackendAddressPools:      backendAddressPools,
+			OutboundRules:            outboundRules,
+			Probes:                   probes,
+			LoadBalancingRules:       loadBalancingRules,
 		},
 	}
 
 	return lb, nil
 }
 
-func getFrontendIPConfigs(lbSpec LBSpec) ([]network.FrontendIPConfiguration, []network.SubResource) {
-	frontendIPConfigurations := make([]network.FrontendIPConfiguration, 0)
-	frontendIDs := make([]network.SubResource, 0)
+func getFrontendIPConfigs(lbSpec LBSpec) ([]*armnetwork.FrontendIPConfiguration, []*armnetwork.SubResource) {
+	frontendIPConfigurations := make([]*armnetwork.FrontendIPConfiguration, 0)
+	frontendIDs := make([]*armnetwork.SubResource, 0)
 	for _, ipConfig := range lbSpec.FrontendIPConfigs {
-		var properties network.FrontendIPConfigurationPropertiesFormat
-		if lbSpec.Type == infrav1.Internal {
-			properties = network.FrontendIPConfigurationPropertiesFormat{
-				PrivateIPAllocationMethod: network.IPAllocationMethodStatic,
-				Subnet: &network.Subnet{
-					ID: to.StringPtr(azure.SubnetID(lbSpec.SubscriptionID, lbSpec.VNetResourceGroup, lbSpec.VNetName, lbSpec.SubnetName)),
-				},
-				PrivateIPAddress: to.StringPtr(ipConfig.PrivateIPAddress),
-			}
-		} else {
-			properties = network.FrontendIPConfigurationPropertiesFormat{
-				PublicIPAddress: &network.PublicIPAddress{
-					ID: to.StringPtr(azure.PublicIPID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, ipConfig.PublicIP.Name)),
-				},
-			}
-		}
-		frontendIPConfigurations = append(frontendIPConfigurations, network.FrontendIPConfiguration{
-			FrontendIPConfigurationPropertiesFormat: &properties,
-			Name:                                    to.StringPtr(ipConfig.Name),
-		})
-		frontendIDs = append(frontendIDs, network.SubResource{
-			ID: to.StringPtr(azure.FrontendIPConfigID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, ipConfig.Name)),
+		frontendIPConfigurations = append(frontendIPConfigurations, getFrontendIPConfig(lbSpec, ipConfig))
+		frontendIDs = append(frontendIDs, &armnetwork.SubResource{
+			ID: to.Ptr(azure.FrontendIPConfigID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, ipConfig.Name)),
 		})
 	}
+	// Alias frontends with their own IP get their own frontend IP configuration, which is only used by their load
+	// balancing rule and not for outbound traffic.
+	for _, alias := range lbSpec.AliasFrontends {
+		if !alias.HasOwnIP() {
+			continue
+		}
+		frontendIPConfigurations = append(frontendIPConfigurations, getFrontendIPConfig(lbSpec, infrav1.FrontendIP{
+			Name:     alias.Name,
+			PublicIP: alias.PublicIP,
+			FrontendIPClass: infrav1.FrontendIPClass{
+				PrivateIPAddress: alias.PrivateIPAddress,
+			},
+		}))
+	}
 	return frontendIPConfigurations, frontendIDs
 }
 
-func getOutboundRules(lbSpec LBSpec, frontendIDs []network.SubResource) []network.OutboundRule {
+func getFrontendIPConfig(lbSpec LBSpec, ipConfig infrav1.FrontendIP) *armnetwork.FrontendIPConfiguration {
+	var properties armnetwork.FrontendIPConfigurationPropertiesFormat
 	if lbSpec.Type == infrav1.Internal {
-		return []network.OutboundRule{}
+		properties = armnetwork.FrontendIPConfigurationPropertiesFormat{
+			PrivateIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
+			Subnet: &armnetwork.Subnet{
+				ID: to.Ptr(azure.SubnetID(lbSpec.SubscriptionID, lbSpec.VNetResourceGroup, lbSpec.VNetName, lbSpec.SubnetName)),
+			},
+			PrivateIPAddress: to.Ptr(ipConfig.PrivateIPAddress),
+		}
+	} else {
+		properties = armnetwork.FrontendIPConfigurationPropertiesFormat{
+			PublicIPAddress: &armnetwork.PublicIPAddress{
+				ID: to.Ptr(azure.PublicIPID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, ipConfig.PublicIP.Name)),
+			},
+		}
+		if ipConfig.GatewayLoadBalancerID != "" {
+			properties.GatewayLoadBalancer = &armnetwork.SubResource{
+				ID: to.Ptr(ipConfig.GatewayLoadBalancerID),
+			}
+		}
+	}
+	return &armnetwork.FrontendIPConfiguration{
+		Properties: &properties,
+		Name:       to.Ptr(ipConfig.Name),
+	}
+}
+
+func getOutboundRules(lbSpec LBSpec, frontendIDs []*armnetwork.SubResource) []*armnetwork.OutboundRule {
+	// Cross-region load balancers don't support outbound rules, the regional load balancers provide outbound traffic.
+	if lbSpec.Type == infrav1.Internal || lbSpec.Tier == infrav1.SKUTierGlobal {
+		return []*armnetwork.OutboundRule{}
 	}
-	return []network.OutboundRule{
+	return []*armnetwork.OutboundRule{
 		{
-			Name: to.StringPtr(outboundNAT),
-			OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
-				Protocol:                 network.LoadBalancerOutboundRuleProtocolAll,
+			Name: to.Ptr(outboundNAT),
+			Properties: &armnetwork.OutboundRulePropertiesFormat{
+				Protocol:                 to.Ptr(armnetwork.LoadBalancerOutboundRuleProtocolAll),
 				IdleTimeoutInMinutes:     lbSpec.IdleTimeoutInMinutes,
-				FrontendIPConfigurations: &frontendIDs,
-				BackendAddressPool: &network.SubResource{
-					ID: to.StringPtr(azure.AddressPoolID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, lbSpec.BackendPoolName)),
+				EnableTCPReset:           lbSpec.EnableTCPReset,
+				FrontendIPConfigurations: frontendIDs,
+				BackendAddressPool: &armnetwork.SubResource{
+					ID: to.Ptr(azure.AddressPoolID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, lbSpec.BackendPoolName)),
 				},
 			},
 		},
 	}
 }
 
-func getLoadBalancingRules(lbSpec LBSpec, frontendIDs []network.SubResource) []network.LoadBalancingRule {
-	if lbSpec.Role == infrav1.APIServerRole {
-		// We disable outbound SNAT explicitly in the HTTPS LB rule and enable TCP and UDP outbound NAT with an outbound rule.
-		// For more information on Standard LB outbound connections see https://docs.microsoft.com/en-us/azure/load-balancer/load-balancer-outbound-connections.
-		var frontendIPConfig network.SubResource
+func getLoadBalancingRules(lbSpec LBSpec, frontendIDs []*armnetwork.SubResource) []*armnetwork.LoadBalancingRule {
+	if lbSpec.Role == infrav1.APIServerRole && lbSpec.Tier == infrav1.SKUTierGlobal {
+		// Cross-region load balancers forward to the frontend port of the regional load balancers, whose health probes
+		// determine which of them are healthy, and don't support idle timeouts, TCP resets or outbound SNAT settings.
+		frontendIPConfig := &armnetwork.SubResource{}
 		if len(frontendIDs) != 0 {
 			frontendIPConfig = frontendIDs[0]
 		}
-		return []network.LoadBalancingRule{
+		return []*armnetwork.LoadBalancingRule{
 			{
-				Name: to.StringPtr(lbRuleHTTPS),
-				LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
-					DisableOutboundSnat:     to.BoolPtr(true),
-					Protocol:                network.TransportProtocolTCP,
-					FrontendPort:            to.Int32Ptr(lbSpec.APIServerPort),
-					BackendPort:             to.Int32Ptr(lbSpec.APIServerPort),
-					IdleTimeoutInMinutes:    lbSpec.IdleTimeoutInMinutes,
-					EnableFloatingIP:        to.BoolPtr(false),
-					LoadDistribution:        network.LoadDistributionDefault,
-					FrontendIPConfiguration: &frontendIPConfig,
-					BackendAddressPool: &network.SubResource{
-						ID: to.StringPtr(azure.AddressPoolID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, lbSpec.BackendPoolName)),
-					},
-					Probe: &network.SubResource{
-						ID: to.StringPtr(azure.ProbeID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, tcpProbe)),
+				Name: to.Ptr(lbRuleHTTPS),
+				Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+					Protocol:                to.Ptr(armnetwork.TransportProtocolTCP),
+					FrontendPort:            to.Ptr[int32](getFrontendPort(lbSpec)),
+					BackendPort:             to.Ptr[int32](lbSpec.APIServerPort),
+					EnableFloatingIP:        to.Ptr(false),
+					LoadDistribution:        getLoadDistribution(lbSpec.LoadDistribution),
+					FrontendIPConfiguration: frontendIPConfig,
+					BackendAddressPool: &armnetwork.SubResource{
+						ID: to.Ptr(azure.AddressPoolID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, lbSpec.BackendPoolName)),
 					},
 				},
 			},
 		}
 	}
-	return []network.LoadBalancingRule{}
+	if lbSpec.Role == infrav1.APIServerRole {
+		// We disable outbound SNAT explicitly in the HTTPS LB rule and enable TCP and UDP outbound NAT with an outbound rule.
+		// For more information on Standard LB outbound connections see https://docs.microsoft.com/en-us/azure/load-balancer/load-balancer-outbound-connections.
+		frontendIPConfig := &armnetwork.SubResource{}
+		if len(frontendIDs) != 0 {
+			frontendIPConfig = frontendIDs[0]
+		}
+		rules := []*armnetwork.LoadBalancingRule{
+			getAPIServerLoadBalancingRule(lbSpec, lbRuleHTTPS, frontendIPConfig, getFrontendPort(lbSpec)),
+		}
+		// Alias frontends without their own IP listen on the frontend IP of the API server rule, on another port.
+		for _, alias := range lbSpec.AliasFrontends {
+			aliasFrontendIPConfig := frontendIPConfig
+			if alias.HasOwnIP() {
+				aliasFrontendIPConfig = &armnetwork.SubResource{
+					ID: to.Ptr(azure.FrontendIPConfigID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, alias.Name)),
+				}
+			}
+			rules = append(rules, getAPIServerLoadBalancingRule(lbSpec, lbRuleHTTPS+"-"+alias.Name, aliasFrontendIPConfig,
+				pointer.Int32Deref(alias.Port, getFrontendPort(lbSpec))))
+		}
+		return rules
+	}
+	return []*armnetwork.LoadBalancingRule{}
+}
+
+// getAPIServerLoadBalancingRule returns a load balancing rule forwarding the given frontend port of the given frontend
+// IP configuration to the API server port of the backend pool.
+func getAPIServerLoadBalancingRule(lbSpec LBSpec, name string, frontendIPConfig *armnetwork.SubResource, frontendPort int32) *armnetwork.LoadBalancingRule {
+	return &armnetwork.LoadBalancingRule{
+		Name: to.Ptr(name),
+		Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+			DisableOutboundSnat:     to.Ptr(true),
+			Protocol:                to.Ptr(armnetwork.TransportProtocolTCP),
+			FrontendPort:            to.Ptr[int32](frontendPort),
+			BackendPort:             to.Ptr[int32](lbSpec.APIServerPort),
+			IdleTimeoutInMinutes:    lbSpec.IdleTimeoutInMinutes,
+			EnableFloatingIP:        to.Ptr(false),
+			EnableTCPReset:          lbSpec.EnableTCPReset,
+			LoadDistribution:        getLoadDistribution(lbSpec.LoadDistribution),
+			FrontendIPConfiguration: frontendIPConfig,
+			BackendAddressPool: &armnetwork.SubResource{
+				ID: to.Ptr(azure.AddressPoolID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, lbSpec.BackendPoolName)),
+			},
+			Probe: &armnetwork.SubResource{
+				ID: to.Ptr(azure.ProbeID(lbSpec.SubscriptionID, lbSpec.ResourceGroup, lbSpec.Name, tcpProbe)),
+			},
+		},
+	}
+}
+
+// getFrontendPort returns the frontend port of the API server load balancing rule, which defaults to the API server port.
+func getFrontendPort(lbSpec LBSpec) int32 {
+	if lbSpec.FrontendPort != 0 {
+		return lbSpec.FrontendPort
+	}
+	return lbSpec.APIServerPort
+}
+
+// getLoadDistribution returns the load distribution of the load balancing rules, which defaults to Default.
+func getLoadDistribution(loadDistribution infrav1.LoadDistribution) *armnetwork.LoadDistribution {
+	if loadDistribution == "" {
+		return to.Ptr(armnetwork.LoadDistributionDefault)
+	}
+	return to.Ptr(armnetwork.LoadDistribution(loadDistribution))
 }
 
-func getBackendAddressPools(lbSpec LBSpec) []network.BackendAddressPool {
-	return []network.BackendAddressPool{
+func getBackendAddressPools(lbSpec LBSpec) []*armnetwork.BackendAddressPool {
+	if lbSpec.Tier == infrav1.SKUTierGlobal {
+		// The backends of cross-region load balancers are the frontend IP configurations of regional load balancers.
+		addresses := make([]*armnetwork.LoadBalancerBackendAddress, 0, len(lbSpec.RegionalFrontendIPConfigIDs))
+		for _, id := range lbSpec.RegionalFrontendIPConfigIDs {
+			addresses = append(addresses, &armnetwork.LoadBalancerBackendAddress{
+				Name: to.Ptr(regionalBackendAddressName(id)),
+				Properties: &armnetwork.LoadBalancerBackendAddressPropertiesFormat{
+					LoadBalancerFrontendIPConfiguration: &armnetwork.SubResource{ID: to.Ptr(id)},
+				},
+			})
+		}
+		return []*armnetwork.BackendAddressPool{
+			{
+				Name: to.Ptr(lbSpec.BackendPoolName),
+				Properties: &armnetwork.BackendAddressPoolPropertiesFormat{
+					LoadBalancerBackendAddresses: addresses,
+				},
+			},
+		}
+	}
+	return []*armnetwork.BackendAddressPool{
 		{
-			Name: to.StringPtr(lbSpec.BackendPoolName),
+			Name: to.Ptr(lbSpec.BackendPoolName),
 		},
 	}
 }
 
-func getProbes(lbSpec LBSpec) []network.Probe {
-	if lbSpec.Role == infrav1.APIServerRole {
-		return []network.Probe{
+func getProbes(lbSpec LBSpec) []*armnetwork.Probe {
+	if lbSpec.Role == infrav1.APIServerRole && lbSpec.Tier != infrav1.SKUTierGlobal {
+		return []*armnetwork.Probe{
 			{
-				Name: to.StringPtr(tcpProbe),
-				ProbePropertiesFormat: &network.ProbePropertiesFormat{
-					Protocol:          network.ProbeProtocolTCP,
-					Port:              to.Int32Ptr(lbSpec.APIServerPort),
-					IntervalInSeconds: to.Int32Ptr(15),
-					NumberOfProbes:    to.Int32Ptr(4),
+				Name: to.Ptr(tcpProbe),
+				Properties: &armnetwork.ProbePropertiesFormat{
+					Protocol:          to.Ptr(armnetwork.ProbeProtocolTCP),
+					Port:              to.Ptr[int32](lbSpec.APIServerPort),
+					IntervalInSeconds: to.Ptr[int32](15),
+					NumberOfProbes:    to.Ptr[int32](4),
 				},
 			},
 		}
 	}
-	return []network.Probe{}
+	return []*armnetwork.Probe{}
 }
 
-func probeExists(probes []network.Probe, probe network.Probe) bool {
+func probeExists(probes []*armnetwork.Probe, probe *armnetwork.Probe) bool {
 	for _, p := range probes {
-		if to.String(p.Name) == to.String(probe.Name) {
+		if pointer.StringDeref(p.Name, "") == pointer.StringDeref(probe.Name, "") {
 			return true
 		}
 	}
 	return false
 }
 
-func outboundRuleExists(rules []network.OutboundRule, rule network.OutboundRule) bool {
+func outboundRuleExists(rules []*armnetwork.OutboundRule, rule *armnetwork.OutboundRule) bool {
 	for _, r := range rules {
-		if to.String(r.Name) == to.String(rule.Name) {
+		if pointer.StringDeref(r.Name, "") == pointer.StringDeref(rule.Name, "") {
 			return true
 		}
 	}
 	return false
 }
 
-func poolExists(pools []network.BackendAddressPool, pool network.BackendAddressPool) bool {
+func poolExists(pools []*armnetwork.BackendAddressPool, pool *armnetwork.BackendAddressPool) bool {
 	for _, p := range pools {
-		if to.String(p.Name) == to.String(pool.Name) {
+		if pointer.StringDeref(p.Name, "") == pointer.StringDeref(pool.Name, "") {
 			return true
 		}
 	}
 	return false
 }
 
-func lbRuleExists(rules []network.LoadBalancingRule, rule network.LoadBalancingRule) bool {
+// regionalBackendAddressName returns the name of the backend address of a regional frontend IP configuration in the
+// backend pool of a cross-region load balancer, made of the names of the regional load balancer and of its frontend.
+func regionalBackendAddressName(frontendIPConfigID string) string {
+	parts := strings.Split(strings.TrimSuffix(frontendIPConfigID, "/"), "/")
+	if len(parts) < 3 {
+		return frontendIPConfigID
+	}
+	return parts[len(parts)-3] + "-" + parts[len(parts)-1]
+}
+
+// updateRegionalBackendAddresses applies the regional frontend IP configurations of the given backend pool of a
+// cross-region load balancer to the existing pool with the same name, and returns whether it was updated.
+func updateRegionalBackendAddresses(pools []*armnetwork.BackendAddressPool, pool *armnetwork.BackendAddressPool) bool {
+	var wanted []*armnetwork.LoadBalancerBackendAddress
+	if pool.Properties != nil {
+		wanted = pool.Properties.LoadBalancerBackendAddresses
+	}
+	for i, p := range pools {
+		if pointer.StringDeref(p.Name, "") != pointer.StringDeref(pool.Name, "") {
+			continue
+		}
+		var existing []*armnetwork.LoadBalancerBackendAddress
+		if p.Properties != nil {
+			existing = p.Properties.LoadBalancerBackendAddresses
+		}
+		if sameRegionalFrontends(existing, wanted) {
+			return false
+		}
+		if pools[i].Properties == nil {
+			pools[i].Properties = &armnetwork.BackendAddressPoolPropertiesFormat{}
+		}
+		pools[i].Properties.LoadBalancerBackendAddresses = wanted
+		return true
+	}
+	return false
+}
+
+// sameRegionalFrontends returns whether two lists of backend addresses reference the same regional frontend IP
+// configurations, in any order.
+func sameRegionalFrontends(a, b []*armnetwork.LoadBalancerBackendAddress) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	ids := make(map[string]int, len(a))
+	for _, address := range a {
+		ids[regionalFrontendID(address)]++
+	}
+	for _, address := range b {
+		id := regionalFrontendID(address)
+		if ids[id] == 0 {
+			return false
+		}
+		ids[id]--
+	}
+	return true
+}
+
+// regionalFrontendID returns the lower-cased ID of the regional frontend IP configuration of a backend address.
+func regionalFrontendID(address *armnetwork.LoadBalancerBackendAddress) string {
+	if address == nil || address.Properties == nil || address.Properties.LoadBalancerFrontendIPConfiguration == nil {
+		return ""
+	}
+	return strings.ToLower(pointer.StringDeref(address.Properties.LoadBalancerFrontendIPConfiguration.ID, ""))
+}
+
+// updateLBRuleConnectionSettings applies the TCP reset and load distribution of the given load balancing rule to the
+// existing rule with the same name, and returns whether it was updated.
+func updateLBRuleConnectionSettings(rules []*armnetwork.LoadBalancingRule, rule *armnetwork.LoadBalancingRule) bool {
+	for i, r := range rules {
+		if pointer.StringDeref(r.Name, "") != pointer.StringDeref(rule.Name, "") || r.Properties == nil {
+			continue
+		}
+		existingDistribution, wantedDistribution := r.Properties.LoadDistribution, rule.Properties.LoadDistribution
+		sameDistribution := existingDistribution == wantedDistribution ||
+			existingDistribution != nil && wantedDistribution != nil && *existingDistribution == *wantedDistribution
+		if pointer.BoolDeref(r.Properties.EnableTCPReset, false) == pointer.BoolDeref(rule.Properties.EnableTCPReset, false) && sameDistribution {
+			return false
+		}
+		rules[i].Properties.EnableTCPReset = rule.Properties.EnableTCPReset
+		rules[i].Properties.LoadDistribution = rule.Properties.LoadDistribution
+		return true
+	}
+	return false
+}
+
+// updateOutboundRuleTCPReset applies the TCP reset of the given outbound rule to the existing rule with the same name,
+// and returns whether it was updated.
+func updateOutboundRuleTCPReset(rules []*armnetwork.OutboundRule, rule *armnetwork.OutboundRule) bool {
+	for i, r := range rules {
+		if pointer.StringDeref(r.Name, "") != pointer.StringDeref(rule.Name, "") || r.Properties == nil {
+			continue
+		}
+		if pointer.BoolDeref(r.Properties.EnableTCPReset, false) == pointer.BoolDeref(rule.Properties.EnableTCPReset, false) {
+			return false
+		}
+		rules[i].Properties.EnableTCPReset = rule.Properties.EnableTCPReset
+		return true
+	}
+	return false
+}
+
+func lbRuleExists(rules []*armnetwork.LoadBalancingRule, rule *armnetwork.LoadBalancingRule) bool {
 	for _, r := range rules {
-		if to.String(r.Name) == to.String(rule.Name) {
+		if pointer.StringDeref(r.Name, "") == pointer.StringDeref(rule.Name, "") {
 			return true
 		}
 	}
 	return false
 }
 
-func ipExists(configs []network.FrontendIPConfiguration, config network.FrontendIPConfiguration) bool {
+// updateGatewayLoadBalancer chains the existing frontend IP configuration with the same name as the given one to the
+// Gateway Load Balancer of the given one, and returns whether it was updated.
+func updateGatewayLoadBalancer(configs []*armnetwork.FrontendIPConfiguration, config *armnetwork.FrontendIPConfiguration) bool {
+	var wanted *armnetwork.SubResource
+	if config.Properties != nil {
+		wanted = config.Properties.GatewayLoadBalancer
+	}
+	for i, ip := range configs {
+		if pointer.StringDeref(ip.Name, "") != pointer.StringDeref(config.Name, "") || ip.Properties == nil {
+			continue
+		}
+		var existingID, wantedID string
+		if ip.Properties.GatewayLoadBalancer != nil {
+			existingID = pointer.StringDeref(ip.Properties.GatewayLoadBalancer.ID, "")
+		}
+		if wanted != nil {
+			wantedID = pointer.StringDeref(wanted.ID, "")
+		}
+		if strings.EqualFold(existingID, wantedID) {
+			return false
+		}
+		configs[i].Properties.GatewayLoadBalancer = wanted
+		return true
+	}
+	return false
+}
+
+func ipExists(configs []*armnetwork.FrontendIPConfiguration, config *armnetwork.FrontendIPConfiguration) bool {
 	for _, ip := range configs {
-		if to.String(ip.Name) == to.String(config.Name) {
+		if pointer.StringDeref(ip.Name, "") == pointer.StringDeref(config.Name, "") {
 			return true
 		}
 	}
@@ -17,50 +17,111 @@ limitations under the License.
 package loadbalancers
 
 import (
+	"strings"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
-func getExistingLBWithMissingFrontendIPConfigs() network.LoadBalancer {
+func getExistingLBWithMissingFrontendIPConfigs() armnetwork.LoadBalancer {
 	existingLB := newSamplePublicAPIServerLB(false, true, true, true, true)
-	existingLB.FrontendIPConfigurations = &[]network.FrontendIPConfiguration{}
+	existingLB.Properties.FrontendIPConfigurations = []*armnetwork.FrontendIPConfiguration{}
 
 	return existingLB
 }
 
-func getExistingLBWithMissingBackendPool() network.LoadBalancer {
+func getExistingLBWithMissingBackendPool() armnetwork.LoadBalancer {
 	existingLB := newSamplePublicAPIServerLB(true, false, true, true, true)
-	existingLB.BackendAddressPools = &[]network.BackendAddressPool{}
+	existingLB.Properties.BackendAddressPools = []*armnetwork.BackendAddressPool{}
 
 	return existingLB
 }
 
-func getExistingLBWithMissingLBRules() network.LoadBalancer {
+func getExistingLBWithMissingLBRules() armnetwork.LoadBalancer {
 	existingLB := newSamplePublicAPIServerLB(true, true, false, true, true)
-	existingLB.LoadBalancingRules = &[]network.LoadBalancingRule{}
+	existingLB.Properties.LoadBalancingRules = []*armnetwork.LoadBalancingRule{}
 
 	return existingLB
 }
 
-func getExistingLBWithMissingProbes() network.LoadBalancer {
+func getExistingLBWithMissingProbes() armnetwork.LoadBalancer {
 	existingLB := newSamplePublicAPIServerLB(true, true, true, false, true)
-	existingLB.Probes = &[]network.Probe{}
+	existingLB.Properties.Probes = []*armnetwork.Probe{}
 
 	return existingLB
 }
 
-func getExistingLBWithMissingOutboundRules() network.LoadBalancer {
+func getExistingLBWithMissingOutboundRules() armnetwork.LoadBalancer {
 	existingLB := newSamplePublicAPIServerLB(true, true, true, true, false)
-	existingLB.OutboundRules = &[]network.OutboundRule{}
+	existingLB.Properties.OutboundRules = []*armnetwork.OutboundRule{}
 
 	return existingLB
 }
 
 func TestParameters(t *testing.T) {
+	gatewayLoadBalancerID := "/subscriptions/123/resourceGroups/nva-rg/providers/Microsoft.Network/loadBalancers/my-gwlb/frontendIPConfigurations/my-gwlb-frontEnd"
+	chainedNodeOutboundLBSpec := fakeNodeOutboundLBSpec
+	chainedNodeOutboundLBSpec.FrontendIPConfigs = []infrav1.FrontendIP{
+		{
+			Name: "my-cluster-frontEnd",
+			PublicIP: &infrav1.PublicIPSpec{
+				Name: "outbound-publicip",
+			},
+			GatewayLoadBalancerID: gatewayLoadBalancerID,
+		},
+	}
+
+	tcpResetAPILBSpec := fakePublicAPILBSpec
+	tcpResetAPILBSpec.EnableTCPReset = to.Ptr(true)
+	tcpResetAPILBSpec.LoadDistribution = infrav1.LoadDistributionSourceIP
+	tcpResetNodeOutboundLBSpec := fakeNodeOutboundLBSpec
+	tcpResetNodeOutboundLBSpec.EnableTCPReset = to.Ptr(true)
+
+	aliasAPILBSpec := fakePublicAPILBSpec
+	aliasAPILBSpec.FrontendPort = 443
+	aliasAPILBSpec.AliasFrontends = []infrav1.APIServerAliasFrontend{
+		{
+			Name: "corp",
+			Port: to.Ptr[int32](8443),
+		},
+		{
+			Name: "partner",
+			PublicIP: &infrav1.PublicIPSpec{
+				Name: "my-partner-publicip",
+			},
+		},
+	}
+
+	regionalFrontendID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd"
+	pairedFrontendID := "/subscriptions/123/resourceGroups/my-paired-rg/providers/Microsoft.Network/loadBalancers/my-paired-publiclb/frontendIPConfigurations/my-paired-publiclb-frontEnd"
+	globalAPILBSpec := LBSpec{
+		Name:           "my-globallb",
+		ResourceGroup:  "my-rg",
+		SubscriptionID: "123",
+		ClusterName:    "my-cluster",
+		Location:       "eastus2",
+		Role:           infrav1.APIServerRole,
+		Type:           infrav1.Public,
+		SKU:            infrav1.SKUStandard,
+		Tier:           infrav1.SKUTierGlobal,
+		FrontendIPConfigs: []infrav1.FrontendIP{
+			{
+				Name: "my-globallb-frontEnd",
+				PublicIP: &infrav1.PublicIPSpec{
+					Name: "my-global-publicip",
+				},
+			},
+		},
+		BackendPoolName:             "my-globallb-backendPool",
+		APIServerPort:               6443,
+		RegionalFrontendIPConfigIDs: []string{regionalFrontendID},
+	}
+	pairedGlobalAPILBSpec := globalAPILBSpec
+	pairedGlobalAPILBSpec.RegionalFrontendIPConfigIDs = []string{regionalFrontendID, pairedFrontendID}
+
 	testcases := []struct {
 		name          string
 		spec          *LBSpec
@@ -77,6 +138,15 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "external API load balancer is never updated",
+			spec:     &fakeExternalAPILBSpec,
+			existing: armnetwork.LoadBalancer{},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "load balancer shared-lb is external and cannot be updated",
+		},
 		{
 			name:     "internal API load balancer with all expected values",
 			spec:     &fakeInternalAPILBSpec,
@@ -95,13 +165,166 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "node outbound load balancer chained to a gateway load balancer",
+			spec:     &chainedNodeOutboundLBSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				frontendIPs := result.(armnetwork.LoadBalancer).Properties.FrontendIPConfigurations
+				g.Expect(frontendIPs).To(HaveLen(1))
+				g.Expect(frontendIPs[0].Properties.GatewayLoadBalancer).To(Equal(&armnetwork.SubResource{ID: to.Ptr(gatewayLoadBalancerID)}))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "existing node outbound load balancer is chained to a gateway load balancer",
+			spec:     &chainedNodeOutboundLBSpec,
+			existing: newDefaultNodeOutboundLB(),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				frontendIPs := result.(armnetwork.LoadBalancer).Properties.FrontendIPConfigurations
+				g.Expect(frontendIPs).To(HaveLen(1))
+				g.Expect(frontendIPs[0].Properties.GatewayLoadBalancer).To(Equal(&armnetwork.SubResource{ID: to.Ptr(gatewayLoadBalancerID)}))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "existing node outbound load balancer is unchained from its gateway load balancer",
+			spec:     &fakeNodeOutboundLBSpec,
+			existing: newChainedNodeOutboundLB(gatewayLoadBalancerID),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				g.Expect(result.(armnetwork.LoadBalancer)).To(Equal(newDefaultNodeOutboundLB()))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "new API load balancer with TCP reset and session persistence",
+			spec:     &tcpResetAPILBSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				rules := result.(armnetwork.LoadBalancer).Properties.LoadBalancingRules
+				g.Expect(rules).To(HaveLen(1))
+				g.Expect(rules[0].Properties.EnableTCPReset).To(Equal(to.Ptr(true)))
+				g.Expect(rules[0].Properties.LoadDistribution).To(Equal(to.Ptr(armnetwork.LoadDistributionSourceIP)))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "existing API load balancer rule is updated with TCP reset and session persistence",
+			spec:     &tcpResetAPILBSpec,
+			existing: newSamplePublicAPIServerLB(false, false, false, false, false),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				rules := result.(armnetwork.LoadBalancer).Properties.LoadBalancingRules
+				g.Expect(rules).To(HaveLen(1))
+				g.Expect(rules[0].Properties.EnableTCPReset).To(Equal(to.Ptr(true)))
+				g.Expect(rules[0].Properties.LoadDistribution).To(Equal(to.Ptr(armnetwork.LoadDistributionSourceIP)))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "existing node outbound load balancer rule is updated with TCP reset",
+			spec:     &tcpResetNodeOutboundLBSpec,
+			existing: newDefaultNodeOutboundLB(),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				rules := result.(armnetwork.LoadBalancer).Properties.OutboundRules
+				g.Expect(rules).To(HaveLen(1))
+				g.Expect(rules[0].Properties.EnableTCPReset).To(Equal(to.Ptr(true)))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "new API load balancer with frontend port and alias frontends",
+			spec:     &aliasAPILBSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				primaryFrontendID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd"
+				partnerFrontendID := "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/partner"
+
+				frontends := lb.Properties.FrontendIPConfigurations
+				g.Expect(frontends).To(HaveLen(2))
+				g.Expect(frontends[1].Name).To(Equal(to.Ptr("partner")))
+				g.Expect(frontends[1].Properties.PublicIPAddress.ID).To(Equal(to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-partner-publicip")))
+
+				rules := lb.Properties.LoadBalancingRules
+				g.Expect(rules).To(HaveLen(3))
+				for i, want := range []struct {
+					name         string
+					frontendID   string
+					frontendPort int32
+				}{
+					{name: "LBRuleHTTPS", frontendID: primaryFrontendID, frontendPort: 443},
+					{name: "LBRuleHTTPS-corp", frontendID: primaryFrontendID, frontendPort: 8443},
+					{name: "LBRuleHTTPS-partner", frontendID: partnerFrontendID, frontendPort: 443},
+				} {
+					g.Expect(rules[i].Name).To(Equal(to.Ptr(want.name)))
+					g.Expect(rules[i].Properties.FrontendIPConfiguration.ID).To(Equal(to.Ptr(want.frontendID)))
+					g.Expect(rules[i].Properties.FrontendPort).To(Equal(to.Ptr[int32](want.frontendPort)))
+					g.Expect(rules[i].Properties.BackendPort).To(Equal(to.Ptr[int32](6443)))
+				}
+
+				// The alias frontends are not used for outbound traffic, and the probe stays on the API server port.
+				outboundRules := lb.Properties.OutboundRules
+				g.Expect(outboundRules).To(HaveLen(1))
+				g.Expect(outboundRules[0].Properties.FrontendIPConfigurations).To(Equal([]*armnetwork.SubResource{{ID: to.Ptr(primaryFrontendID)}}))
+				g.Expect(lb.Properties.Probes[0].Properties.Port).To(Equal(to.Ptr[int32](6443)))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "existing API load balancer is updated with alias frontends",
+			spec:     &aliasAPILBSpec,
+			existing: newSamplePublicAPIServerLB(false, false, false, false, false),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				lb := result.(armnetwork.LoadBalancer)
+				g.Expect(lb.Properties.FrontendIPConfigurations).To(HaveLen(2))
+				g.Expect(lb.Properties.LoadBalancingRules).To(HaveLen(3))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "new cross-region API load balancer",
+			spec:     &globalAPILBSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				g.Expect(result.(armnetwork.LoadBalancer)).To(Equal(newGlobalAPIServerLB(regionalFrontendID)))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "cross-region API load balancer exists with all expected values",
+			spec:     &globalAPILBSpec,
+			existing: newGlobalAPIServerLB(strings.ToUpper(regionalFrontendID)),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name:     "existing cross-region API load balancer is updated with the frontend of a paired region",
+			spec:     &pairedGlobalAPILBSpec,
+			existing: newGlobalAPIServerLB(regionalFrontendID),
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				g.Expect(result.(armnetwork.LoadBalancer)).To(Equal(newGlobalAPIServerLB(regionalFrontendID, pairedFrontendID)))
+			},
+			expectedError: "",
+		},
 		{
 			name:     "load balancer exists with missing frontend IP configs",
 			spec:     &fakePublicAPILBSpec,
 			existing: getExistingLBWithMissingFrontendIPConfigs(),
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.LoadBalancer{}))
-				g.Expect(result.(network.LoadBalancer)).To(Equal(newSamplePublicAPIServerLB(false, true, true, true, true)))
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				g.Expect(result.(armnetwork.LoadBalancer)).To(Equal(newSamplePublicAPIServerLB(false, true, true, true, true)))
 			},
 			expectedError: "",
 		},
@@ -110,8 +333,8 @@ func TestParameters(t *testing.T) {
 			spec:     &fakePublicAPILBSpec,
 			existing: getExistingLBWithMissingBackendPool(),
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.LoadBalancer{}))
-				g.Expect(result.(network.LoadBalancer)).To(Equal(newSamplePublicAPIServerLB(true, false, true, true, true)))
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				g.Expect(result.(armnetwork.LoadBalancer)).To(Equal(newSamplePublicAPIServerLB(true, false, true, true, true)))
 			},
 			expectedError: "",
 		},
@@ -120,8 +343,8 @@ func TestParameters(t *testing.T) {
 			spec:     &fakePublicAPILBSpec,
 			existing: getExistingLBWithMissingLBRules(),
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.LoadBalancer{}))
-				g.Expect(result.(network.LoadBalancer)).To(Equal(newSamplePublicAPIServerLB(true, true, false, true, true)))
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				g.Expect(result.(armnetwork.LoadBalancer)).To(Equal(newSamplePublicAPIServerLB(true, true, false, true, true)))
 			},
 			expectedError: "",
 		},
@@ -130,8 +353,8 @@ func TestParameters(t *testing.T) {
 			spec:     &fakePublicAPILBSpec,
 			existing: getExistingLBWithMissingProbes(),
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.LoadBalancer{}))
-				g.Expect(result.(network.LoadBalancer)).To(Equal(newSamplePublicAPIServerLB(true, true, true, false, true)))
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				g.Expect(result.(armnetwork.LoadBalancer)).To(Equal(newSamplePublicAPIServerLB(true, true, true, false, true)))
 			},
 			expectedError: "",
 		},
@@ -140,8 +363,8 @@ func TestParameters(t *testing.T) {
 			spec:     &fakePublicAPILBSpec,
 			existing: getExistingLBWithMissingOutboundRules(),
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.LoadBalancer{}))
-				g.Expect(result.(network.LoadBalancer)).To(Equal(newSamplePublicAPIServerLB(true, true, true, true, false)))
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.LoadBalancer{}))
+				g.Expect(result.(armnetwork.LoadBalancer)).To(Equal(newSamplePublicAPIServerLB(true, true, true, true, false)))
 			},
 			expectedError: "",
 		},
@@ -164,42 +387,108 @@ func TestParameters(t *testing.T) {
 	}
 }
 
-func newDefaultNodeOutboundLB() network.LoadBalancer {
-	return network.LoadBalancer{
+func newGlobalAPIServerLB(regionalFrontendIDs ...string) armnetwork.LoadBalancer {
+	addresses := make([]*armnetwork.LoadBalancerBackendAddress, 0, len(regionalFrontendIDs))
+	for _, id := range regionalFrontendIDs {
+		addresses = append(addresses, &armnetwork.LoadBalancerBackendAddress{
+			Name: to.Ptr(regionalBackendAddressName(id)),
+			Properties: &armnetwork.LoadBalancerBackendAddressPropertiesFormat{
+				LoadBalancerFrontendIPConfiguration: &armnetwork.SubResource{ID: to.Ptr(id)},
+			},
+		})
+	}
+	return armnetwork.LoadBalancer{
+		SKU:      &armnetwork.LoadBalancerSKU{Name: to.Ptr(armnetwork.LoadBalancerSKUNameStandard), Tier: to.Ptr(armnetwork.LoadBalancerSKUTierGlobal)},
+		Location: to.Ptr("eastus2"),
+		Tags: map[string]*string{
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+			"sigs.k8s.io_cluster-api-provider-azure_role":               to.Ptr(infrav1.APIServerRole),
+		},
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{
+				{
+					Name: to.Ptr("my-globallb-frontEnd"),
+					Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+						PublicIPAddress: &armnetwork.PublicIPAddress{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-global-publicip"),
+						},
+					},
+				},
+			},
+			BackendAddressPools: []*armnetwork.BackendAddressPool{
+				{
+					Name: to.Ptr("my-globallb-backendPool"),
+					Properties: &armnetwork.BackendAddressPoolPropertiesFormat{
+						LoadBalancerBackendAddresses: addresses,
+					},
+				},
+			},
+			LoadBalancingRules: []*armnetwork.LoadBalancingRule{
+				{
+					Name: to.Ptr(lbRuleHTTPS),
+					Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+						Protocol:         to.Ptr(armnetwork.TransportProtocolTCP),
+						FrontendPort:     to.Ptr[int32](6443),
+						BackendPort:      to.Ptr[int32](6443),
+						EnableFloatingIP: to.Ptr(false),
+						LoadDistribution: to.Ptr(armnetwork.LoadDistributionDefault),
+						FrontendIPConfiguration: &armnetwork.SubResource{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-globallb/frontendIPConfigurations/my-globallb-frontEnd"),
+						},
+						BackendAddressPool: &armnetwork.SubResource{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-globallb/backendAddressPools/my-globallb-backendPool"),
+						},
+					},
+				},
+			},
+			OutboundRules: []*armnetwork.OutboundRule{},
+			Probes:        []*armnetwork.Probe{},
+		},
+	}
+}
+
+func newChainedNodeOutboundLB(gatewayLoadBalancerID string) armnetwork.LoadBalancer {
+	lb := newDefaultNodeOutboundLB()
+	lb.Properties.FrontendIPConfigurations[0].Properties.GatewayLoadBalancer = &armnetwork.SubResource{ID: to.Ptr(gatewayLoadBalancerID)}
+	return lb
+}
+
+func newDefaultNodeOutboundLB() armnetwork.LoadBalancer {
+	return armnetwork.LoadBalancer{
 		Tags: map[string]*string{
-			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
-			"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr(infrav1.NodeOutboundRole),
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+			"sigs.k8s.io_cluster-api-provider-azure_role":               to.Ptr(infrav1.NodeOutboundRole),
 		},
-		Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
-		Location: to.StringPtr("my-location"),
-		LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
-			FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+		SKU:      &armnetwork.LoadBalancerSKU{Name: to.Ptr(armnetwork.LoadBalancerSKUNameStandard)},
+		Location: to.Ptr("my-location"),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{
 				{
-					Name: to.StringPtr("my-cluster-frontEnd"),
-					FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
-						PublicIPAddress: &network.PublicIPAddress{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/outbound-publicip")},
+					Name: to.Ptr("my-cluster-frontEnd"),
+					Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+						PublicIPAddress: &armnetwork.PublicIPAddress{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/outbound-publicip")},
 					},
 				},
 			},
-			BackendAddressPools: &[]network.BackendAddressPool{
+			BackendAddressPools: []*armnetwork.BackendAddressPool{
 				{
-					Name: to.StringPtr("my-cluster-outboundBackendPool"),
+					Name: to.Ptr("my-cluster-outboundBackendPool"),
 				},
 			},
-			LoadBalancingRules: &[]network.LoadBalancingRule{},
-			Probes:             &[]network.Probe{},
-			OutboundRules: &[]network.OutboundRule{
+			LoadBalancingRules: []*armnetwork.LoadBalancingRule{},
+			Probes:             []*armnetwork.Probe{},
+			OutboundRules: []*armnetwork.OutboundRule{
 				{
-					Name: to.StringPtr("OutboundNATAllProtocols"),
-					OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
-						FrontendIPConfigurations: &[]network.SubResource{
-							{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster/frontendIPConfigurations/my-cluster-frontEnd")},
+					Name: to.Ptr("OutboundNATAllProtocols"),
+					Properties: &armnetwork.OutboundRulePropertiesFormat{
+						FrontendIPConfigurations: []*armnetwork.SubResource{
+							{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster/frontendIPConfigurations/my-cluster-frontEnd")},
 						},
-						BackendAddressPool: &network.SubResource{
-							ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster/backendAddressPools/my-cluster-outboundBackendPool"),
+						BackendAddressPool: &armnetwork.SubResource{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-cluster/backendAddressPools/my-cluster-outboundBackendPool"),
 						},
-						Protocol:             network.LoadBalancerOutboundRuleProtocolAll,
-						IdleTimeoutInMinutes: to.Int32Ptr(30),
+						Protocol:             to.Ptr(armnetwork.LoadBalancerOutboundRuleProtocolAll),
+						IdleTimeoutInMinutes: to.Ptr[int32](30),
 					},
 				},
 			},
@@ -207,101 +496,101 @@ func newDefaultNodeOutboundLB() network.LoadBalancer {
 	}
 }
 
-func newSamplePublicAPIServerLB(verifyFrontendIP bool, verifyBackendAddressPools bool, verifyLBRules bool, verifyProbes bool, verifyOutboundRules bool) network.LoadBalancer {
-	var subnet *network.Subnet
-	var backendAddressPoolProps *network.BackendAddressPoolPropertiesFormat
-	enableFloatingIP := to.BoolPtr(false)
-	numProbes := to.Int32Ptr(4)
-	idleTimeout := to.Int32Ptr(4)
+func newSamplePublicAPIServerLB(verifyFrontendIP bool, verifyBackendAddressPools bool, verifyLBRules bool, verifyProbes bool, verifyOutboundRules bool) armnetwork.LoadBalancer {
+	var subnet *armnetwork.Subnet
+	var backendAddressPoolProps *armnetwork.BackendAddressPoolPropertiesFormat
+	enableFloatingIP := to.Ptr(false)
+	numProbes := to.Ptr[int32](4)
+	idleTimeout := to.Ptr[int32](4)
 
 	if verifyFrontendIP {
-		subnet = &network.Subnet{
-			Name: to.StringPtr("fake-test-subnet"),
+		subnet = &armnetwork.Subnet{
+			Name: to.Ptr("fake-test-subnet"),
 		}
 	}
 	if verifyBackendAddressPools {
-		backendAddressPoolProps = &network.BackendAddressPoolPropertiesFormat{
-			Location: to.StringPtr("fake-test-location"),
+		backendAddressPoolProps = &armnetwork.BackendAddressPoolPropertiesFormat{
+			Location: to.Ptr("fake-test-location"),
 		}
 	}
 	if verifyLBRules {
-		enableFloatingIP = to.BoolPtr(true)
+		enableFloatingIP = to.Ptr(true)
 	}
 	if verifyProbes {
-		numProbes = to.Int32Ptr(999)
+		numProbes = to.Ptr[int32](999)
 	}
 	if verifyOutboundRules {
-		idleTimeout = to.Int32Ptr(1000)
+		idleTimeout = to.Ptr[int32](1000)
 	}
 
-	return network.LoadBalancer{
+	return armnetwork.LoadBalancer{
 		Tags: map[string]*string{
-			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
-			"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr(infrav1.APIServerRole),
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+			"sigs.k8s.io_cluster-api-provider-azure_role":               to.Ptr(infrav1.APIServerRole),
 		},
-		Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
-		Location: to.StringPtr("my-location"),
-		LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
-			FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+		SKU:      &armnetwork.LoadBalancerSKU{Name: to.Ptr(armnetwork.LoadBalancerSKUNameStandard)},
+		Location: to.Ptr("my-location"),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{
 				{
-					Name: to.StringPtr("my-publiclb-frontEnd"),
-					FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
-						PublicIPAddress: &network.PublicIPAddress{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-publicip")},
+					Name: to.Ptr("my-publiclb-frontEnd"),
+					Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+						PublicIPAddress: &armnetwork.PublicIPAddress{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/publicIPAddresses/my-publicip")},
 						Subnet:          subnet, // Add to verify that FrontendIPConfigurations aren't overwritten on update
 					},
 				},
 			},
-			BackendAddressPools: &[]network.BackendAddressPool{
+			BackendAddressPools: []*armnetwork.BackendAddressPool{
 				{
-					Name:                               to.StringPtr("my-publiclb-backendPool"),
-					BackendAddressPoolPropertiesFormat: backendAddressPoolProps, // Add to verify that BackendAddressPools aren't overwritten on update
+					Name:       to.Ptr("my-publiclb-backendPool"),
+					Properties: backendAddressPoolProps, // Add to verify that BackendAddressPools aren't overwritten on update
 				},
 			},
-			LoadBalancingRules: &[]network.LoadBalancingRule{
+			LoadBalancingRules: []*armnetwork.LoadBalancingRule{
 				{
-					Name: to.StringPtr(lbRuleHTTPS),
-					LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
-						DisableOutboundSnat:  to.BoolPtr(true),
-						Protocol:             network.TransportProtocolTCP,
-						FrontendPort:         to.Int32Ptr(6443),
-						BackendPort:          to.Int32Ptr(6443),
-						IdleTimeoutInMinutes: to.Int32Ptr(4),
+					Name: to.Ptr(lbRuleHTTPS),
+					Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+						DisableOutboundSnat:  to.Ptr(true),
+						Protocol:             to.Ptr(armnetwork.TransportProtocolTCP),
+						FrontendPort:         to.Ptr[int32](6443),
+						BackendPort:          to.Ptr[int32](6443),
+						IdleTimeoutInMinutes: to.Ptr[int32](4),
 						EnableFloatingIP:     enableFloatingIP, // Add to verify that LoadBalancingRules aren't overwritten on update
-						LoadDistribution:     network.LoadDistributionDefault,
-						FrontendIPConfiguration: &network.SubResource{
-							ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd"),
+						LoadDistribution:     to.Ptr(armnetwork.LoadDistributionDefault),
+						FrontendIPConfiguration: &armnetwork.SubResource{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd"),
 						},
-						BackendAddressPool: &network.SubResource{
-							ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool"),
+						BackendAddressPool: &armnetwork.SubResource{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool"),
 						},
-						Probe: &network.SubResource{
-							ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/probes/TCPProbe"),
+						Probe: &armnetwork.SubResource{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/probes/TCPProbe"),
 						},
 					},
 				},
 			},
-			Probes: &[]network.Probe{
+			Probes: []*armnetwork.Probe{
 				{
-					Name: to.StringPtr(tcpProbe),
-					ProbePropertiesFormat: &network.ProbePropertiesFormat{
-						Protocol:          network.ProbeProtocolTCP,
-						Port:              to.Int32Ptr(6443),
-						IntervalInSeconds: to.Int32Ptr(15),
+					Name: to.Ptr(tcpProbe),
+					Properties: &armnetwork.ProbePropertiesFormat{
+						Protocol:          to.Ptr(armnetwork.ProbeProtocolTCP),
+						Port:              to.Ptr[int32](6443),
+						IntervalInSeconds: to.Ptr[int32](15),
 						NumberOfProbes:    numProbes, // Add to verify that Probes aren't overwritten on update
 					},
 				},
 			},
-			OutboundRules: &[]network.OutboundRule{
+			OutboundRules: []*armnetwork.OutboundRule{
 				{
-					Name: to.StringPtr("OutboundNATAllProtocols"),
-					OutboundRulePropertiesFormat: &network.OutboundRulePropertiesFormat{
-						FrontendIPConfigurations: &[]network.SubResource{
-							{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd")},
+					Name: to.Ptr("OutboundNATAllProtocols"),
+					Properties: &armnetwork.OutboundRulePropertiesFormat{
+						FrontendIPConfigurations: []*armnetwork.SubResource{
+							{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/frontendIPConfigurations/my-publiclb-frontEnd")},
 						},
-						BackendAddressPool: &network.SubResource{
-							ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool"),
+						BackendAddressPool: &armnetwork.SubResource{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-publiclb/backendAddressPools/my-publiclb-backendPool"),
 						},
-						Protocol:             network.LoadBalancerOutboundRuleProtocolAll,
+						Protocol:             to.Ptr(armnetwork.LoadBalancerOutboundRuleProtocolAll),
 						IdleTimeoutInMinutes: idleTimeout, // Add to verify that OutboundRules aren't overwritten on update
 					},
 				},
@@ -310,64 +599,64 @@ func newSamplePublicAPIServerLB(verifyFrontendIP bool, verifyBackendAddressPools
 	}
 }
 
-func newDefaultInternalAPIServerLB() network.LoadBalancer {
-	return network.LoadBalancer{
+func newDefaultInternalAPIServerLB() armnetwork.LoadBalancer {
+	return armnetwork.LoadBalancer{
 		Tags: map[string]*string{
-			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
-			"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr(infrav1.APIServerRole),
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+			"sigs.k8s.io_cluster-api-provider-azure_role":               to.Ptr(infrav1.APIServerRole),
 		},
-		Sku:      &network.LoadBalancerSku{Name: network.LoadBalancerSkuNameStandard},
-		Location: to.StringPtr("my-location"),
-		LoadBalancerPropertiesFormat: &network.LoadBalancerPropertiesFormat{
-			FrontendIPConfigurations: &[]network.FrontendIPConfiguration{
+		SKU:      &armnetwork.LoadBalancerSKU{Name: to.Ptr(armnetwork.LoadBalancerSKUNameStandard)},
+		Location: to.Ptr("my-location"),
+		Properties: &armnetwork.LoadBalancerPropertiesFormat{
+			FrontendIPConfigurations: []*armnetwork.FrontendIPConfiguration{
 				{
-					Name: to.StringPtr("my-private-lb-frontEnd"),
-					FrontendIPConfigurationPropertiesFormat: &network.FrontendIPConfigurationPropertiesFormat{
-						PrivateIPAllocationMethod: network.IPAllocationMethodStatic,
-						Subnet: &network.Subnet{
-							ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-cp-subnet"),
+					Name: to.Ptr("my-private-lb-frontEnd"),
+					Properties: &armnetwork.FrontendIPConfigurationPropertiesFormat{
+						PrivateIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
+						Subnet: &armnetwork.Subnet{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-cp-subnet"),
 						},
-						PrivateIPAddress: to.StringPtr("10.0.0.10"),
+						PrivateIPAddress: to.Ptr("10.0.0.10"),
 					},
 				},
 			},
-			BackendAddressPools: &[]network.BackendAddressPool{
+			BackendAddressPools: []*armnetwork.BackendAddressPool{
 				{
-					Name: to.StringPtr("my-private-lb-backendPool"),
+					Name: to.Ptr("my-private-lb-backendPool"),
 				},
 			},
-			LoadBalancingRules: &[]network.LoadBalancingRule{
+			LoadBalancingRules: []*armnetwork.LoadBalancingRule{
 				{
-					Name: to.StringPtr(lbRuleHTTPS),
-					LoadBalancingRulePropertiesFormat: &network.LoadBalancingRulePropertiesFormat{
-						DisableOutboundSnat:  to.BoolPtr(true),
-						Protocol:             network.TransportProtocolTCP,
-						FrontendPort:         to.Int32Ptr(6443),
-						BackendPort:          to.Int32Ptr(6443),
-						IdleTimeoutInMinutes: to.Int32Ptr(4),
-						EnableFloatingIP:     to.BoolPtr(false),
-						LoadDistribution:     network.LoadDistributionDefault,
-						FrontendIPConfiguration: &network.SubResource{
-							ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-private-lb/frontendIPConfigurations/my-private-lb-frontEnd"),
+					Name: to.Ptr(lbRuleHTTPS),
+					Properties: &armnetwork.LoadBalancingRulePropertiesFormat{
+						DisableOutboundSnat:  to.Ptr(true),
+						Protocol:             to.Ptr(armnetwork.TransportProtocolTCP),
+						FrontendPort:         to.Ptr[int32](6443),
+						BackendPort:          to.Ptr[int32](6443),
+						IdleTimeoutInMinutes: to.Ptr[int32](4),
+						EnableFloatingIP:     to.Ptr(false),
+						LoadDistribution:     to.Ptr(armnetwork.LoadDistributionDefault),
+						FrontendIPConfiguration: &armnetwork.SubResource{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-private-lb/frontendIPConfigurations/my-private-lb-frontEnd"),
 						},
-						BackendAddressPool: &network.SubResource{
-							ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-private-lb/backendAddressPools/my-private-lb-backendPool"),
+						BackendAddressPool: &armnetwork.SubResource{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-private-lb/backendAddressPools/my-private-lb-backendPool"),
 						},
-						Probe: &network.SubResource{
-							ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-private-lb/probes/TCPProbe"),
+						Probe: &armnetwork.SubResource{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-private-lb/probes/TCPProbe"),
 						},
 					},
 				},
 			},
-			OutboundRules: &[]network.OutboundRule{},
-			Probes: &[]network.Probe{
+			OutboundRules: []*armnetwork.OutboundRule{},
+			Probes: []*armnetwork.Probe{
 				{
-					Name: to.StringPtr(tcpProbe),
-					ProbePropertiesFormat: &network.ProbePropertiesFormat{
-						Protocol:          network.ProbeProtocolTCP,
-						Port:              to.Int32Ptr(6443),
-						IntervalInSeconds: to.Int32Ptr(15),
-						NumberOfProbes:    to.Int32Ptr(4),
+					Name: to.Ptr(tcpProbe),
+					Properties: &armnetwork.ProbePropertiesFormat{
+						Protocol:          to.Ptr(armnetwork.ProbeProtocolTCP),
+						Port:              to.Ptr[int32](6443),
+						IntervalInSeconds: to.Ptr[int32](15),
+						NumberOfProbes:    to.Ptr[int32](4),
 					},
 				},
 			},
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loganalyticsworkspaces
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/operationalinsights/armoperationalinsights"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	workspaces *armoperationalinsights.WorkspacesClient
+}
+
+// newClient creates a new Log Analytics workspaces client from subscription ID.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armoperationalinsights.NewWorkspacesClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Log Analytics workspaces client")
+	}
+	return &azureClient{c}, nil
+}
+
+// Get gets the specified Log Analytics workspace.
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "loganalyticsworkspaces.azureClient.Get")
+	defer done()
+
+	resp, err := ac.workspaces.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Workspace, nil
+}
+
+// CreateOrUpdateAsync creates or updates a Log Analytics workspace asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "loganalyticsworkspaces.azureClient.CreateOrUpdateAsync")
+	defer done()
+
+	workspace, ok := parameters.(armoperationalinsights.Workspace)
+	if !ok && parameters != nil {
+		return nil, nil, errors.Errorf("%T is not an armoperationalinsights.Workspace", parameters)
+	}
+
+	opts := &armoperationalinsights.WorkspacesClientBeginCreateOrUpdateOptions{ResumeToken: resumeToken}
+	createPoller, err := ac.workspaces.BeginCreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), workspace, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	resp, err := createPoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, createPoller, err
+	}
+
+	// if the operation completed, return a nil poller
+	return resp.Workspace, nil, nil
+}
+
+// DeleteAsync deletes a Log Analytics workspace asynchronously. DeleteAsync sends a DELETE
+// request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "loganalyticsworkspaces.azureClient.DeleteAsync")
+	defer done()
+
+	// The workspace is soft-deleted, so that it can be recovered with its data during the recovery period of the service.
+	opts := &armoperationalinsights.WorkspacesClientBeginDeleteOptions{ResumeToken: resumeToken}
+	deletePoller, err := ac.workspaces.BeginDelete(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	_, err = deletePoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return deletePoller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, nil
+}
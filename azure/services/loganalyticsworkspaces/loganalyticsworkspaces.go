@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loganalyticsworkspaces
+
+import (
+	"context"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "loganalyticsworkspaces"
+
+// LogAnalyticsWorkspaceScope defines the scope interface for a Log Analytics workspaces service.
+type LogAnalyticsWorkspaceScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	LogAnalyticsWorkspaceSpec() azure.ResourceSpecGetter
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope LogAnalyticsWorkspaceScope
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope LogAnalyticsWorkspaceScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:      scope,
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the Log Analytics workspace.
+func (s *Service) RequiredActions() []string {
+	if s.Scope.LogAnalyticsWorkspaceSpec() == nil {
+		return nil
+	}
+	return []string{"Microsoft.OperationalInsights/workspaces/read", "Microsoft.OperationalInsights/workspaces/write"}
+}
+
+// Reconcile creates or updates the Log Analytics workspace of the cluster.
+// The workspace is only created when the cluster is onboarded to Azure Monitor without an existing workspace.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "loganalyticsworkspaces.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.LogAnalyticsWorkspaceSpec()
+	if spec == nil {
+		return nil
+	}
+
+	_, err := s.CreateResource(ctx, spec, serviceName)
+	s.Scope.UpdatePutStatus(infrav1.LogAnalyticsWorkspaceReadyCondition, serviceName, err)
+	return err
+}
+
+// Delete deletes the Log Analytics workspace of the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "loganalyticsworkspaces.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	spec := s.Scope.LogAnalyticsWorkspaceSpec()
+	if spec == nil {
+		return nil
+	}
+
+	err := s.DeleteResource(ctx, spec, serviceName)
+	s.Scope.UpdateDeleteStatus(infrav1.LogAnalyticsWorkspaceReadyCondition, serviceName, err)
+	return err
+}
+
+// IsManaged always returns true as the scope only describes the workspace when it is created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
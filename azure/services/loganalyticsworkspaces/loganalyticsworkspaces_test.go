@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loganalyticsworkspaces
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loganalyticsworkspaces/mock_loganalyticsworkspaces"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeWorkspaceSpec = LogAnalyticsWorkspaceSpec{
+		Name:          "my-cluster-logs",
+		ResourceGroup: "my-rg",
+		Location:      "westus",
+		ClusterName:   "my-cluster",
+	}
+
+	internalError = errors.New("Internal Server Error")
+)
+
+func TestReconcileLogAnalyticsWorkspace(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_loganalyticsworkspaces.MockLogAnalyticsWorkspaceScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the cluster doesn't need a workspace",
+			expectedError: "",
+			expect: func(s *mock_loganalyticsworkspaces.MockLogAnalyticsWorkspaceScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.LogAnalyticsWorkspaceSpec().Return(nil)
+			},
+		},
+		{
+			name:          "create the workspace of the cluster",
+			expectedError: "",
+			expect: func(s *mock_loganalyticsworkspaces.MockLogAnalyticsWorkspaceScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.LogAnalyticsWorkspaceSpec().Return(&fakeWorkspaceSpec)
+				r.CreateResource(gomockinternal.AContext(), &fakeWorkspaceSpec, serviceName).Return(nil, nil)
+				s.UpdatePutStatus(infrav1.LogAnalyticsWorkspaceReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to create the workspace of the cluster",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_loganalyticsworkspaces.MockLogAnalyticsWorkspaceScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.LogAnalyticsWorkspaceSpec().Return(&fakeWorkspaceSpec)
+				r.CreateResource(gomockinternal.AContext(), &fakeWorkspaceSpec, serviceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.LogAnalyticsWorkspaceReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_loganalyticsworkspaces.NewMockLogAnalyticsWorkspaceScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeleteLogAnalyticsWorkspace(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_loganalyticsworkspaces.MockLogAnalyticsWorkspaceScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the cluster doesn't need a workspace",
+			expectedError: "",
+			expect: func(s *mock_loganalyticsworkspaces.MockLogAnalyticsWorkspaceScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.LogAnalyticsWorkspaceSpec().Return(nil)
+			},
+		},
+		{
+			name:          "delete the workspace of the cluster",
+			expectedError: "",
+			expect: func(s *mock_loganalyticsworkspaces.MockLogAnalyticsWorkspaceScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.LogAnalyticsWorkspaceSpec().Return(&fakeWorkspaceSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeWorkspaceSpec, serviceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.LogAnalyticsWorkspaceReadyCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fail to delete the workspace of the cluster",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_loganalyticsworkspaces.MockLogAnalyticsWorkspaceScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.LogAnalyticsWorkspaceSpec().Return(&fakeWorkspaceSpec)
+				r.DeleteResource(gomockinternal.AContext(), &fakeWorkspaceSpec, serviceName).Return(internalError)
+				s.UpdateDeleteStatus(infrav1.LogAnalyticsWorkspaceReadyCondition, serviceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_loganalyticsworkspaces.NewMockLogAnalyticsWorkspaceScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loganalyticsworkspaces
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/operationalinsights/armoperationalinsights"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// defaultRetentionInDays is the number of days the workspace retains the data when the cluster doesn't set it.
+const defaultRetentionInDays = 30
+
+// LogAnalyticsWorkspaceSpec defines the specification for the Log Analytics workspace created for a cluster.
+type LogAnalyticsWorkspaceSpec struct {
+	Name            string
+	ResourceGroup   string
+	Location        string
+	RetentionInDays *int32
+	ClusterName     string
+	AdditionalTags  infrav1.Tags
+}
+
+// ResourceName returns the name of the Log Analytics workspace.
+func (s *LogAnalyticsWorkspaceSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *LogAnalyticsWorkspaceSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for Log Analytics workspaces.
+func (s *LogAnalyticsWorkspaceSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the Log Analytics workspace.
+func (s *LogAnalyticsWorkspaceSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	retentionInDays := pointer.Int32Deref(s.RetentionInDays, 0)
+	if retentionInDays == 0 {
+		retentionInDays = defaultRetentionInDays
+	}
+
+	if existing != nil {
+		existingWorkspace, ok := existing.(armoperationalinsights.Workspace)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armoperationalinsights.Workspace", existing)
+		}
+
+		if existingWorkspace.Properties != nil && pointer.Int32Deref(existingWorkspace.Properties.RetentionInDays, 0) == retentionInDays {
+			// Skip update for the workspace as it exists with expected values
+			return nil, nil
+		}
+	}
+
+	return armoperationalinsights.Workspace{
+		Location: to.Ptr(s.Location),
+		Properties: &armoperationalinsights.WorkspaceProperties{
+			SKU: &armoperationalinsights.WorkspaceSKU{
+				Name: to.Ptr(armoperationalinsights.WorkspaceSKUNameEnumPerGB2018),
+			},
+			RetentionInDays: to.Ptr(retentionInDays),
+		},
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.Ptr(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+	}, nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loganalyticsworkspaces
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/operationalinsights/armoperationalinsights"
+	. "github.com/onsi/gomega"
+)
+
+func TestParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := fakeWorkspaceSpec
+	params, err := spec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	workspace, ok := params.(armoperationalinsights.Workspace)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(*workspace.Location).To(Equal("westus"))
+	g.Expect(*workspace.Properties.SKU.Name).To(Equal(armoperationalinsights.WorkspaceSKUNameEnumPerGB2018))
+	g.Expect(*workspace.Properties.RetentionInDays).To(Equal(int32(defaultRetentionInDays)))
+	g.Expect(workspace.Tags).To(HaveKeyWithValue("sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster", to.Ptr("owned")))
+
+	// An existing workspace with the same retention is left untouched.
+	params, err = spec.Parameters(workspace)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).To(BeNil())
+
+	// A changed retention updates the existing workspace.
+	changed := spec
+	changed.RetentionInDays = to.Ptr[int32](90)
+	params, err = changed.Parameters(workspace)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*params.(armoperationalinsights.Workspace).Properties.RetentionInDays).To(Equal(int32(90)))
+}
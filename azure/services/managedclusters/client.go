@@ -18,14 +18,16 @@ package managedclusters
 
 import (
 	"context"
-	"encoding/json"
+	"net/http"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
-	"github.com/Azure/go-autorest/autorest"
-	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
 	"github.com/pkg/errors"
-	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -33,25 +35,36 @@ import (
 // CredentialGetter is a helper interface for getting managed cluster credentials.
 type CredentialGetter interface {
 	GetCredentials(context.Context, string, string) ([]byte, error)
+	GetUserCredentials(context.Context, string, string) ([]byte, error)
+	GetAADToken(context.Context) (string, error)
 }
 
+// aksAADServerAppID is the application ID of the AAD server application of AKS, which the AAD tokens accepted by the
+// API server of the clusters with AKS-managed AAD are issued for.
+const aksAADServerAppID = "6dae42f8-4368-4678-94ff-3960e28e3630"
+
 // azureClient contains the Azure go-sdk Client.
 type azureClient struct {
-	managedclusters containerservice.ManagedClustersClient
+	managedclusters *armcontainerservice.ManagedClustersClient
+	credential      azcore.TokenCredential
 }
 
 // newClient creates a new managed cluster client from an authorizer.
-func newClient(auth azure.Authorizer) *azureClient {
-	return &azureClient{
-		managedclusters: newManagedClustersClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armcontainerservice.NewManagedClustersClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create managed clusters client")
 	}
+	return &azureClient{managedclusters: c, credential: auth.Token()}, nil
 }
 
-// newManagedClustersClient creates a new managed clusters client from subscription ID.
-func newManagedClustersClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) containerservice.ManagedClustersClient {
-	managedClustersClient := containerservice.NewManagedClustersClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&managedClustersClient.Client, authorizer)
-	return managedClustersClient
+// NewGetter creates a new managed cluster getter from an authorizer.
+func NewGetter(auth azure.Authorizer) (async.Getter, error) {
+	return newClient(auth)
 }
 
 // Get gets a managed cluster.
@@ -59,7 +72,11 @@ func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureClient.Get")
 	defer done()
 
-	return ac.managedclusters.Get(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	resp, err := ac.managedclusters.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.ManagedCluster, nil
 }
 
 // GetCredentials fetches the admin kubeconfig for a managed cluster.
@@ -67,45 +84,77 @@ func (ac *azureClient) GetCredentials(ctx context.Context, resourceGroupName, na
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureClient.GetCredentials")
 	defer done()
 
-	credentialList, err := ac.managedclusters.ListClusterAdminCredentials(ctx, resourceGroupName, name, "")
+	resp, err := ac.managedclusters.ListClusterAdminCredentials(ctx, resourceGroupName, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return kubeconfigFromCredentials(resp.CredentialResults)
+}
+
+// GetUserCredentials fetches the user kubeconfig for a managed cluster, which authenticates with kubelogin.
+func (ac *azureClient) GetUserCredentials(ctx context.Context, resourceGroupName, name string) ([]byte, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureClient.GetUserCredentials")
+	defer done()
+
+	opts := &armcontainerservice.ManagedClustersClientListClusterUserCredentialsOptions{Format: to.Ptr(armcontainerservice.FormatExec)}
+	resp, err := ac.managedclusters.ListClusterUserCredentials(ctx, resourceGroupName, name, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	if credentialList.Kubeconfigs == nil || len(*credentialList.Kubeconfigs) < 1 {
-		return nil, errors.New("no kubeconfigs available for the managed cluster cluster")
+	return kubeconfigFromCredentials(resp.CredentialResults)
+}
+
+// GetAADToken fetches an AAD token of the identity of the client for the API server of a managed cluster with
+// AKS-managed AAD.
+func (ac *azureClient) GetAADToken(ctx context.Context) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureClient.GetAADToken")
+	defer done()
+
+	token, err := ac.credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{aksAADServerAppID + "/.default"}})
+	if err != nil {
+		return "", err
+	}
+
+	return token.Token, nil
+}
+
+// kubeconfigFromCredentials returns the first kubeconfig of a list of credentials.
+func kubeconfigFromCredentials(credentialList armcontainerservice.CredentialResults) ([]byte, error) {
+	if len(credentialList.Kubeconfigs) < 1 || credentialList.Kubeconfigs[0] == nil {
+		return nil, errors.New("no kubeconfigs available for the managed cluster")
 	}
 
-	return *(*credentialList.Kubeconfigs)[0].Value, nil
+	return credentialList.Kubeconfigs[0].Value, nil
 }
 
 // CreateOrUpdateAsync creates or updates a managed cluster.
-// It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// It sends a PUT request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
 // progress of the operation.
-func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (result interface{}, future azureautorest.FutureAPI, err error) {
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureClient.CreateOrUpdate")
 	defer done()
 
-	managedcluster, ok := parameters.(containerservice.ManagedCluster)
-	if !ok {
-		return nil, nil, errors.Errorf("%T is not a containerservice.ManagedCluster", parameters)
-	}
-
-	preparer, err := ac.managedclusters.CreateOrUpdatePreparer(ctx, spec.ResourceGroupName(), spec.ResourceName(), managedcluster)
-	if err != nil {
-		return nil, nil, errors.Wrap(err, "failed to prepare operation")
+	managedcluster, ok := parameters.(armcontainerservice.ManagedCluster)
+	if !ok && parameters != nil {
+		return nil, nil, errors.Errorf("%T is not an armcontainerservice.ManagedCluster", parameters)
 	}
 
 	headerSpec, ok := spec.(azure.ResourceSpecGetterWithHeaders)
 	if !ok {
 		return nil, nil, errors.Errorf("%T is not a azure.ResourceSpecGetterWithHeaders", spec)
 	}
-
-	for key, value := range headerSpec.CustomHeaders() {
-		preparer.Header.Add(key, value)
+	if customHeaders := headerSpec.CustomHeaders(); len(customHeaders) > 0 {
+		headers := http.Header{}
+		for key, value := range customHeaders {
+			headers.Add(key, value)
+		}
+		ctx = policy.WithHTTPHeader(ctx, headers)
 	}
 
-	createFuture, err := ac.managedclusters.CreateOrUpdateSender(preparer)
+	opts := &armcontainerservice.ManagedClustersClientBeginCreateOrUpdateOptions{ResumeToken: resumeToken}
+	createPoller, err := ac.managedclusters.BeginCreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), managedcluster, opts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -113,26 +162,26 @@ func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
 	defer cancel()
 
-	err = createFuture.WaitForCompletionRef(ctx, ac.managedclusters.Client)
+	resp, err := createPoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
 	if err != nil {
-		// if an error occurs, return the future.
+		// if an error occurs, return the poller.
 		// this means the long-running operation didn't finish in the specified timeout.
-		return nil, &createFuture, err
+		return nil, createPoller, err
 	}
 
-	result, err = createFuture.Result(ac.managedclusters)
-	// if the operation completed, return a nil future
-	return result, nil, err
+	// if the operation completed, return a nil poller
+	return resp.ManagedCluster, nil, nil
 }
 
 // DeleteAsync deletes a managed cluster asynchronously. DeleteAsync sends a DELETE
-// request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
 // progress of the operation.
-func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (future azureautorest.FutureAPI, err error) {
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureClient.DeleteAsync")
 	defer done()
 
-	deleteFuture, err := ac.managedclusters.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	opts := &armcontainerservice.ManagedClustersClientBeginDeleteOptions{ResumeToken: resumeToken}
+	deletePoller, err := ac.managedclusters.BeginDelete(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -140,59 +189,13 @@ func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
 	defer cancel()
 
-	err = deleteFuture.WaitForCompletionRef(ctx, ac.managedclusters.Client)
+	_, err = deletePoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
 	if err != nil {
-		// if an error occurs, return the future.
+		// if an error occurs, return the poller.
 		// this means the long-running operation didn't finish in the specified timeout.
-		return &deleteFuture, err
-	}
-	_, err = deleteFuture.Result(ac.managedclusters)
-	// if the operation completed, return a nil future.
-	return nil, err
-}
-
-// IsDone returns true if the long-running operation has completed.
-func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (bool, error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureClient.IsDone")
-	defer done()
-
-	isDone, err := future.DoneWithContext(ctx, ac.managedclusters)
-	if err != nil {
-		return false, errors.Wrap(err, "failed checking if the operation was complete")
+		return deletePoller, err
 	}
 
-	return isDone, nil
-}
-
-// Result fetches the result of a long-running operation future.
-func (ac *azureClient) Result(ctx context.Context, future azureautorest.FutureAPI, futureType string) (result interface{}, err error) {
-	_, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.azureClient.Result")
-	defer done()
-
-	if future == nil {
-		return nil, errors.Errorf("cannot get result from nil future")
-	}
-
-	switch futureType {
-	case infrav1.PutFuture:
-		// Marshal and Unmarshal the future to put it into the correct future type so we can access the Result function.
-		// Unfortunately the FutureAPI can't be casted directly to ManagedClustersCreateOrUpdateFuture because it is a azureautorest.Future, which doesn't implement the Result function. See PR #1686 for discussion on alternatives.
-		// It was converted back to a generic azureautorest.Future from the CAPZ infrav1.Future type stored in Status: https://github.com/kubernetes-sigs/cluster-api-provider-azure/blob/main/azure/converters/futures.go#L49.
-		var createFuture *containerservice.ManagedClustersCreateOrUpdateFuture
-		jsonData, err := future.MarshalJSON()
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to marshal future")
-		}
-		if err := json.Unmarshal(jsonData, &createFuture); err != nil {
-			return nil, errors.Wrap(err, "failed to unmarshal future data")
-		}
-		return createFuture.Result(ac.managedclusters)
-
-	case infrav1.DeleteFuture:
-		// Delete does not return a result managed cluster.
-		return nil, nil
-
-	default:
-		return nil, errors.Errorf("unknown future type %q", futureType)
-	}
+	// if the operation completed, return a nil poller.
+	return nil, nil
 }
@@ -19,13 +19,16 @@ package managedclusters
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -39,9 +42,14 @@ type ManagedClusterScope interface {
 	azure.AsyncStatusUpdater
 	ManagedClusterSpec(context.Context) azure.ResourceSpecGetter
 	SetControlPlaneEndpoint(clusterv1.APIEndpoint)
+	SetManagedClusterStatus(*infrav1exp.ManagedClusterStatus)
 	MakeEmptyKubeConfigSecret() corev1.Secret
 	GetKubeConfigData() []byte
 	SetKubeConfigData([]byte)
+	MakeEmptyUserKubeConfigSecret() corev1.Secret
+	GetUserKubeConfigData() []byte
+	SetUserKubeConfigData([]byte)
+	UserKubeconfigCredentials() infrav1exp.KubeconfigCredentialsType
 }
 
 // Service provides operations on azure resources.
@@ -52,13 +60,16 @@ type Service struct {
 }
 
 // New creates a new service.
-func New(scope ManagedClusterScope) *Service {
-	client := newClient(scope)
+func New(scope ManagedClusterScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:            scope,
-		Reconciler:       async.New(scope, client, client),
+		Reconciler:       async.NewPollerService(scope, client, client),
 		CredentialGetter: client,
-	}
+	}, nil
 }
 
 // Name returns the service name.
@@ -81,29 +92,83 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	result, resultErr := s.CreateResource(ctx, managedClusterSpec, serviceName)
 	if resultErr == nil {
-		managedCluster, ok := result.(containerservice.ManagedCluster)
+		managedCluster, ok := result.(armcontainerservice.ManagedCluster)
 		if !ok {
-			return errors.Errorf("%T is not a containerservice.ManagedCluster", result)
+			return errors.Errorf("%T is not an armcontainerservice.ManagedCluster", result)
 		}
 		// Update control plane endpoint.
 		endpoint := clusterv1.APIEndpoint{
-			Host: to.String(managedCluster.ManagedClusterProperties.Fqdn),
+			Host: pointer.StringDeref(managedCluster.Properties.Fqdn, ""),
 			Port: 443,
 		}
 		s.Scope.SetControlPlaneEndpoint(endpoint)
 
+		// Update the state of the managed cluster reported by Azure.
+		s.Scope.SetManagedClusterStatus(converters.SDKToManagedClusterStatus(managedCluster))
+
 		// Update kubeconfig data
-		// Always fetch credentials in case of rotation
-		kubeConfigData, err := s.GetCredentials(ctx, managedClusterSpec.ResourceGroupName(), managedClusterSpec.ResourceName())
-		if err != nil {
-			return errors.Wrap(err, "failed to get credentials for managed cluster")
+		// Always fetch credentials in case of rotation.
+		if err := s.reconcileCredentials(ctx, managedClusterSpec, pointer.BoolDeref(managedCluster.Properties.DisableLocalAccounts, false)); err != nil {
+			return err
 		}
-		s.Scope.SetKubeConfigData(kubeConfigData)
 	}
 	s.Scope.UpdatePutStatus(infrav1.ManagedClusterRunningCondition, serviceName, resultErr)
 	return resultErr
 }
 
+// reconcileCredentials fetches the kubeconfig used by Cluster API and the kubeconfig of the users of the cluster. The
+// admin credentials can't be fetched once the local accounts of the cluster are disabled, and the user credentials
+// then rely on kubelogin, which the Cluster API controllers can't run, so their kubeconfig authenticates with an AAD
+// token of the identity managing the cluster instead, which is renewed at every reconciliation.
+func (s *Service) reconcileCredentials(ctx context.Context, managedClusterSpec azure.ResourceSpecGetter, localAccountsDisabled bool) error {
+	var adminKubeConfigData, userKubeConfigData []byte
+	var err error
+	if !localAccountsDisabled {
+		adminKubeConfigData, err = s.GetCredentials(ctx, managedClusterSpec.ResourceGroupName(), managedClusterSpec.ResourceName())
+		if err != nil {
+			return errors.Wrap(err, "failed to get credentials for managed cluster")
+		}
+	}
+	if localAccountsDisabled || s.Scope.UserKubeconfigCredentials() == infrav1exp.KubeconfigCredentialsTypeUser {
+		userKubeConfigData, err = s.GetUserCredentials(ctx, managedClusterSpec.ResourceGroupName(), managedClusterSpec.ResourceName())
+		if err != nil {
+			return errors.Wrap(err, "failed to get user credentials for managed cluster")
+		}
+	} else {
+		userKubeConfigData = adminKubeConfigData
+	}
+
+	kubeConfigData := adminKubeConfigData
+	if localAccountsDisabled {
+		token, err := s.GetAADToken(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to get AAD token for managed cluster")
+		}
+		kubeConfigData, err = kubeconfigWithToken(userKubeConfigData, token)
+		if err != nil {
+			return errors.Wrap(err, "failed to set AAD token in user credentials for managed cluster")
+		}
+	}
+
+	s.Scope.SetKubeConfigData(kubeConfigData)
+	s.Scope.SetUserKubeConfigData(userKubeConfigData)
+	return nil
+}
+
+// kubeconfigWithToken returns a kubeconfig whose users authenticate with the given bearer token instead of their
+// exec plugin.
+func kubeconfigWithToken(kubeconfig []byte, token string) ([]byte, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	for _, authInfo := range config.AuthInfos {
+		authInfo.Exec = nil
+		authInfo.Token = token
+	}
+	return clientcmd.Write(*config)
+}
+
 // Delete deletes the managed cluster.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.Service.Delete")
@@ -122,7 +187,7 @@ func (s *Service) Delete(ctx context.Context) error {
 	return err
 }
 
-// IsManaged returns always returns true as CAPZ does not support BYO managed cluster.
+// IsManaged always returns true as CAPZ does not support BYO managed cluster.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	return true, nil
 }
@@ -21,19 +21,73 @@ import (
 	"errors"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters/mock_managedclusters"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 var fakeManagedClusterSpec = &ManagedClusterSpec{Name: "my-managedcluster", ResourceGroup: "my-rg"}
 
+// fakeUserKubeconfig is a user kubeconfig of a cluster with AKS-managed AAD, as returned by AKS.
+var fakeUserKubeconfig = []byte(`apiVersion: v1
+clusters:
+- cluster:
+    certificate-authority-data: Y2E=
+    server: https://my-managedcluster-fqdn:443
+  name: my-managedcluster
+contexts:
+- context:
+    cluster: my-managedcluster
+    user: clusterUser_my-rg_my-managedcluster
+  name: my-managedcluster
+current-context: my-managedcluster
+kind: Config
+preferences: {}
+users:
+- name: clusterUser_my-rg_my-managedcluster
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      args:
+      - get-token
+      - --login
+      - azurecli
+      - --server-id
+      - 6dae42f8-4368-4678-94ff-3960e28e3630
+      command: kubelogin
+      env: null
+      provideClusterInfo: false
+`)
+
+// fakeTokenKubeconfig is fakeUserKubeconfig authenticating with an AAD token instead of kubelogin.
+var fakeTokenKubeconfig = []byte(`apiVersion: v1
+clusters:
+- cluster:
+    certificate-authority-data: Y2E=
+    server: https://my-managedcluster-fqdn:443
+  name: my-managedcluster
+contexts:
+- context:
+    cluster: my-managedcluster
+    user: clusterUser_my-rg_my-managedcluster
+  name: my-managedcluster
+current-context: my-managedcluster
+kind: Config
+preferences: {}
+users:
+- name: clusterUser_my-rg_my-managedcluster
+  user:
+    token: my-token
+`)
+
 func TestReconcile(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -61,8 +115,8 @@ func TestReconcile(t *testing.T) {
 			expectedError: "",
 			expect: func(m *mock_managedclusters.MockCredentialGetterMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.ManagedClusterSpec(gomockinternal.AContext()).Return(fakeManagedClusterSpec)
-				r.CreateResource(gomockinternal.AContext(), fakeManagedClusterSpec, serviceName).Return(containerservice.ManagedCluster{
-					ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				r.CreateResource(gomockinternal.AContext(), fakeManagedClusterSpec, serviceName).Return(armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
 						Fqdn:              pointer.String("my-managedcluster-fqdn"),
 						ProvisioningState: pointer.String("Succeeded"),
 					},
@@ -71,18 +125,102 @@ func TestReconcile(t *testing.T) {
 					Host: "my-managedcluster-fqdn",
 					Port: 443,
 				})
+				s.SetManagedClusterStatus(&infrav1exp.ManagedClusterStatus{
+					ProvisioningState: "Succeeded",
+					FQDN:              "my-managedcluster-fqdn",
+				})
 				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return([]byte("credentials"), nil)
+				s.UserKubeconfigCredentials().Return(infrav1exp.KubeconfigCredentialsTypeAdmin)
 				s.SetKubeConfigData([]byte("credentials"))
+				s.SetUserKubeConfigData([]byte("credentials"))
+				s.UpdatePutStatus(infrav1.ManagedClusterRunningCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "create managed cluster with user kubeconfig credentials succeeds",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockCredentialGetterMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ManagedClusterSpec(gomockinternal.AContext()).Return(fakeManagedClusterSpec)
+				r.CreateResource(gomockinternal.AContext(), fakeManagedClusterSpec, serviceName).Return(armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						Fqdn:              pointer.String("my-managedcluster-fqdn"),
+						ProvisioningState: pointer.String("Succeeded"),
+					},
+				}, nil)
+				s.SetControlPlaneEndpoint(clusterv1.APIEndpoint{
+					Host: "my-managedcluster-fqdn",
+					Port: 443,
+				})
+				s.SetManagedClusterStatus(&infrav1exp.ManagedClusterStatus{
+					ProvisioningState: "Succeeded",
+					FQDN:              "my-managedcluster-fqdn",
+				})
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return([]byte("credentials"), nil)
+				s.UserKubeconfigCredentials().Return(infrav1exp.KubeconfigCredentialsTypeUser)
+				m.GetUserCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(fakeUserKubeconfig, nil)
+				s.SetKubeConfigData([]byte("credentials"))
+				s.SetUserKubeConfigData(fakeUserKubeconfig)
+				s.UpdatePutStatus(infrav1.ManagedClusterRunningCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "create managed cluster with local accounts disabled succeeds",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockCredentialGetterMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ManagedClusterSpec(gomockinternal.AContext()).Return(fakeManagedClusterSpec)
+				r.CreateResource(gomockinternal.AContext(), fakeManagedClusterSpec, serviceName).Return(armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						Fqdn:                 pointer.String("my-managedcluster-fqdn"),
+						ProvisioningState:    pointer.String("Succeeded"),
+						DisableLocalAccounts: pointer.Bool(true),
+					},
+				}, nil)
+				s.SetControlPlaneEndpoint(clusterv1.APIEndpoint{
+					Host: "my-managedcluster-fqdn",
+					Port: 443,
+				})
+				s.SetManagedClusterStatus(&infrav1exp.ManagedClusterStatus{
+					ProvisioningState: "Succeeded",
+					FQDN:              "my-managedcluster-fqdn",
+				})
+				m.GetUserCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(fakeUserKubeconfig, nil)
+				m.GetAADToken(gomockinternal.AContext()).Return("my-token", nil)
+				s.SetKubeConfigData(fakeTokenKubeconfig)
+				s.SetUserKubeConfigData(fakeUserKubeconfig)
 				s.UpdatePutStatus(infrav1.ManagedClusterRunningCondition, serviceName, nil)
 			},
 		},
+		{
+			name:          "fail to get AAD token for managed cluster with local accounts disabled",
+			expectedError: "failed to get AAD token for managed cluster: unauthorized",
+			expect: func(m *mock_managedclusters.MockCredentialGetterMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.ManagedClusterSpec(gomockinternal.AContext()).Return(fakeManagedClusterSpec)
+				r.CreateResource(gomockinternal.AContext(), fakeManagedClusterSpec, serviceName).Return(armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						Fqdn:                 pointer.String("my-managedcluster-fqdn"),
+						ProvisioningState:    pointer.String("Succeeded"),
+						DisableLocalAccounts: pointer.Bool(true),
+					},
+				}, nil)
+				s.SetControlPlaneEndpoint(clusterv1.APIEndpoint{
+					Host: "my-managedcluster-fqdn",
+					Port: 443,
+				})
+				s.SetManagedClusterStatus(&infrav1exp.ManagedClusterStatus{
+					ProvisioningState: "Succeeded",
+					FQDN:              "my-managedcluster-fqdn",
+				})
+				m.GetUserCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(fakeUserKubeconfig, nil)
+				m.GetAADToken(gomockinternal.AContext()).Return("", errors.New("unauthorized"))
+			},
+		},
 		{
 			name:          "fail to get managed cluster credentials",
 			expectedError: "failed to get credentials for managed cluster: internal server error",
 			expect: func(m *mock_managedclusters.MockCredentialGetterMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.ManagedClusterSpec(gomockinternal.AContext()).Return(fakeManagedClusterSpec)
-				r.CreateResource(gomockinternal.AContext(), fakeManagedClusterSpec, serviceName).Return(containerservice.ManagedCluster{
-					ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				r.CreateResource(gomockinternal.AContext(), fakeManagedClusterSpec, serviceName).Return(armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
 						Fqdn:              pointer.String("my-managedcluster-fqdn"),
 						ProvisioningState: pointer.String("Succeeded"),
 					},
@@ -91,6 +229,10 @@ func TestReconcile(t *testing.T) {
 					Host: "my-managedcluster-fqdn",
 					Port: 443,
 				})
+				s.SetManagedClusterStatus(&infrav1exp.ManagedClusterStatus{
+					ProvisioningState: "Succeeded",
+					FQDN:              "my-managedcluster-fqdn",
+				})
 				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return([]byte(""), errors.New("internal server error"))
 			},
 		},
@@ -186,3 +328,27 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestKubeconfigWithToken(t *testing.T) {
+	g := NewWithT(t)
+
+	kubeconfig, err := kubeconfigWithToken(fakeUserKubeconfig, "my-token")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The Cluster API controllers build their clients from this kubeconfig, so it must not rely on any exec plugin.
+	config, err := clientcmd.Load(kubeconfig)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(config.AuthInfos).To(HaveLen(1))
+	for _, authInfo := range config.AuthInfos {
+		g.Expect(authInfo.Exec).To(BeNil())
+		g.Expect(authInfo.Token).To(Equal("my-token"))
+	}
+	restConfig, err := clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}).ClientConfig()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(restConfig.Host).To(Equal("https://my-managedcluster-fqdn:443"))
+	g.Expect(restConfig.BearerToken).To(Equal("my-token"))
+	g.Expect(restConfig.ExecProvider).To(BeNil())
+
+	_, err = kubeconfigWithToken([]byte("not a kubeconfig"), "my-token")
+	g.Expect(err).To(HaveOccurred())
+}
@@ -24,12 +24,13 @@ import (
 	context "context"
 	reflect "reflect"
 
-	autorest "github.com/Azure/go-autorest/autorest"
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	gomock "github.com/golang/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
-	v1beta10 "sigs.k8s.io/cluster-api/api/v1beta1"
+	v1beta10 "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	v1beta11 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // MockManagedClusterScope is a mock of ManagedClusterScope interface.
@@ -55,20 +56,6 @@ func (m *MockManagedClusterScope) EXPECT() *MockManagedClusterScopeMockRecorder
 	return m.recorder
 }
 
-// Authorizer mocks base method.
-func (m *MockManagedClusterScope) Authorizer() autorest.Authorizer {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authorizer")
-	ret0, _ := ret[0].(autorest.Authorizer)
-	return ret0
-}
-
-// Authorizer indicates an expected call of Authorizer.
-func (mr *MockManagedClusterScopeMockRecorder) Authorizer() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockManagedClusterScope)(nil).Authorizer))
-}
-
 // BaseURI mocks base method.
 func (m *MockManagedClusterScope) BaseURI() string {
 	m.ctrl.T.Helper()
@@ -165,6 +152,20 @@ func (mr *MockManagedClusterScopeMockRecorder) GetLongRunningOperationState(arg0
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongRunningOperationState", reflect.TypeOf((*MockManagedClusterScope)(nil).GetLongRunningOperationState), arg0, arg1)
 }
 
+// GetUserKubeConfigData mocks base method.
+func (m *MockManagedClusterScope) GetUserKubeConfigData() []byte {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserKubeConfigData")
+	ret0, _ := ret[0].([]byte)
+	return ret0
+}
+
+// GetUserKubeConfigData indicates an expected call of GetUserKubeConfigData.
+func (mr *MockManagedClusterScopeMockRecorder) GetUserKubeConfigData() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserKubeConfigData", reflect.TypeOf((*MockManagedClusterScope)(nil).GetUserKubeConfigData))
+}
+
 // HashKey mocks base method.
 func (m *MockManagedClusterScope) HashKey() string {
 	m.ctrl.T.Helper()
@@ -193,6 +194,20 @@ func (mr *MockManagedClusterScopeMockRecorder) MakeEmptyKubeConfigSecret() *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeEmptyKubeConfigSecret", reflect.TypeOf((*MockManagedClusterScope)(nil).MakeEmptyKubeConfigSecret))
 }
 
+// MakeEmptyUserKubeConfigSecret mocks base method.
+func (m *MockManagedClusterScope) MakeEmptyUserKubeConfigSecret() v1.Secret {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MakeEmptyUserKubeConfigSecret")
+	ret0, _ := ret[0].(v1.Secret)
+	return ret0
+}
+
+// MakeEmptyUserKubeConfigSecret indicates an expected call of MakeEmptyUserKubeConfigSecret.
+func (mr *MockManagedClusterScopeMockRecorder) MakeEmptyUserKubeConfigSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeEmptyUserKubeConfigSecret", reflect.TypeOf((*MockManagedClusterScope)(nil).MakeEmptyUserKubeConfigSecret))
+}
+
 // ManagedClusterSpec mocks base method.
 func (m *MockManagedClusterScope) ManagedClusterSpec(arg0 context.Context) azure.ResourceSpecGetter {
 	m.ctrl.T.Helper()
@@ -208,7 +223,7 @@ func (mr *MockManagedClusterScopeMockRecorder) ManagedClusterSpec(arg0 interface
 }
 
 // SetControlPlaneEndpoint mocks base method.
-func (m *MockManagedClusterScope) SetControlPlaneEndpoint(arg0 v1beta10.APIEndpoint) {
+func (m *MockManagedClusterScope) SetControlPlaneEndpoint(arg0 v1beta11.APIEndpoint) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "SetControlPlaneEndpoint", arg0)
 }
@@ -243,6 +258,30 @@ func (mr *MockManagedClusterScopeMockRecorder) SetLongRunningOperationState(arg0
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockManagedClusterScope)(nil).SetLongRunningOperationState), arg0)
 }
 
+// SetManagedClusterStatus mocks base method.
+func (m *MockManagedClusterScope) SetManagedClusterStatus(arg0 *v1beta10.ManagedClusterStatus) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetManagedClusterStatus", arg0)
+}
+
+// SetManagedClusterStatus indicates an expected call of SetManagedClusterStatus.
+func (mr *MockManagedClusterScopeMockRecorder) SetManagedClusterStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetManagedClusterStatus", reflect.TypeOf((*MockManagedClusterScope)(nil).SetManagedClusterStatus), arg0)
+}
+
+// SetUserKubeConfigData mocks base method.
+func (m *MockManagedClusterScope) SetUserKubeConfigData(arg0 []byte) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetUserKubeConfigData", arg0)
+}
+
+// SetUserKubeConfigData indicates an expected call of SetUserKubeConfigData.
+func (mr *MockManagedClusterScopeMockRecorder) SetUserKubeConfigData(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserKubeConfigData", reflect.TypeOf((*MockManagedClusterScope)(nil).SetUserKubeConfigData), arg0)
+}
+
 // SubscriptionID mocks base method.
 func (m *MockManagedClusterScope) SubscriptionID() string {
 	m.ctrl.T.Helper()
@@ -271,8 +310,22 @@ func (mr *MockManagedClusterScopeMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockManagedClusterScope)(nil).TenantID))
 }
 
+// Token mocks base method.
+func (m *MockManagedClusterScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockManagedClusterScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockManagedClusterScope)(nil).Token))
+}
+
 // UpdateDeleteStatus mocks base method.
-func (m *MockManagedClusterScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+func (m *MockManagedClusterScope) UpdateDeleteStatus(arg0 v1beta11.ConditionType, arg1 string, arg2 error) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "UpdateDeleteStatus", arg0, arg1, arg2)
 }
@@ -284,7 +337,7 @@ func (mr *MockManagedClusterScopeMockRecorder) UpdateDeleteStatus(arg0, arg1, ar
 }
 
 // UpdatePatchStatus mocks base method.
-func (m *MockManagedClusterScope) UpdatePatchStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+func (m *MockManagedClusterScope) UpdatePatchStatus(arg0 v1beta11.ConditionType, arg1 string, arg2 error) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "UpdatePatchStatus", arg0, arg1, arg2)
 }
@@ -296,7 +349,7 @@ func (mr *MockManagedClusterScopeMockRecorder) UpdatePatchStatus(arg0, arg1, arg
 }
 
 // UpdatePutStatus mocks base method.
-func (m *MockManagedClusterScope) UpdatePutStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+func (m *MockManagedClusterScope) UpdatePutStatus(arg0 v1beta11.ConditionType, arg1 string, arg2 error) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "UpdatePutStatus", arg0, arg1, arg2)
 }
@@ -306,3 +359,17 @@ func (mr *MockManagedClusterScopeMockRecorder) UpdatePutStatus(arg0, arg1, arg2
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePutStatus", reflect.TypeOf((*MockManagedClusterScope)(nil).UpdatePutStatus), arg0, arg1, arg2)
 }
+
+// UserKubeconfigCredentials mocks base method.
+func (m *MockManagedClusterScope) UserKubeconfigCredentials() v1beta10.KubeconfigCredentialsType {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserKubeconfigCredentials")
+	ret0, _ := ret[0].(v1beta10.KubeconfigCredentialsType)
+	return ret0
+}
+
+// UserKubeconfigCredentials indicates an expected call of UserKubeconfigCredentials.
+func (mr *MockManagedClusterScopeMockRecorder) UserKubeconfigCredentials() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserKubeconfigCredentials", reflect.TypeOf((*MockManagedClusterScope)(nil).UserKubeconfigCredentials))
+}
@@ -22,15 +22,24 @@ import (
 	"net"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 )
 
+// KubeletIdentityKey is the key of the kubelet identity in the identity profile of a managed cluster.
+const KubeletIdentityKey = "kubeletidentity"
+
+// APIServerSubnetDelegation is the service the API server subnet of a cluster with API server VNet integration must be
+// delegated to.
+const APIServerSubnetDelegation = "Microsoft.ContainerService/managedClusters"
+
 // ManagedClusterSpec contains properties to create a managed cluster.
 type ManagedClusterSpec struct {
 	// Name is the name of this AKS Cluster.
@@ -81,18 +90,45 @@ type ManagedClusterSpec struct {
 	// AddonProfiles are the profiles of managed cluster add-on.
 	AddonProfiles []AddonProfile
 
+	// Identity is the identity of the AKS control plane. Defaults to a system-assigned identity.
+	Identity *Identity
+
+	// KubeletUserAssignedIdentity is the resource ID of the user-assigned identity of the kubelets.
+	KubeletUserAssignedIdentity string
+
 	// AADProfile is Azure Active Directory configuration to integrate with AKS, for aad authentication.
 	AADProfile *AADProfile
 
+	// DisableLocalAccounts disables the local accounts of the cluster.
+	DisableLocalAccounts *bool
+
 	// SKU is the SKU of the AKS to be provisioned.
 	SKU *SKU
 
 	// LoadBalancerProfile is the profile of the cluster load balancer.
 	LoadBalancerProfile *LoadBalancerProfile
 
+	// OutboundType is the routing method of the egress traffic of the cluster. Defaults to loadBalancer.
+	OutboundType string
+
+	// NatGatewayProfile is the profile of the NAT gateway created by AKS when OutboundType is managedNATGateway.
+	NatGatewayProfile *NatGatewayProfile
+
 	// APIServerAccessProfile is the access profile for AKS API server.
 	APIServerAccessProfile *APIServerAccessProfile
 
+	// DefenderProfile enables Microsoft Defender for Containers on the cluster.
+	DefenderProfile *DefenderProfile
+
+	// ImageCleaner configures the removal of the unused vulnerable images from the nodes of the cluster.
+	ImageCleaner *ImageCleaner
+
+	// WorkloadAutoScalerProfile configures the AKS-managed components scaling the workloads of the cluster.
+	WorkloadAutoScalerProfile *WorkloadAutoScalerProfile
+
+	// HTTPProxyConfig is the HTTP proxy configuration of the nodes of the cluster.
+	HTTPProxyConfig *HTTPProxyConfig
+
 	// Headers is the list of headers to add to the HTTP requests to update this resource.
 	Headers map[string]string
 }
@@ -109,6 +145,15 @@ type AADProfile struct {
 	AdminGroupObjectIDs []string
 }
 
+// Identity is the identity of the AKS control plane.
+type Identity struct {
+	// Type is the type of identity, SystemAssigned or UserAssigned.
+	Type string
+
+	// UserAssignedIdentityResourceID is the resource ID of the user-assigned identity.
+	UserAssignedIdentityResourceID string
+}
+
 // AddonProfile is the profile of a managed cluster add-on.
 type AddonProfile struct {
 	Name    string
@@ -145,6 +190,15 @@ type LoadBalancerProfile struct {
 	IdleTimeoutInMinutes *int32
 }
 
+// NatGatewayProfile is the profile of the NAT gateway created by AKS.
+type NatGatewayProfile struct {
+	// ManagedOutboundIPs is the desired number of outbound public IPs created by AKS for the NAT gateway.
+	ManagedOutboundIPs *int32
+
+	// IdleTimeoutInMinutes is the desired outbound flow idle timeout in minutes.
+	IdleTimeoutInMinutes *int32
+}
+
 // APIServerAccessProfile is the access profile for AKS API server.
 type APIServerAccessProfile struct {
 	// AuthorizedIPRanges are the authorized IP Ranges to kubernetes API server.
@@ -155,6 +209,48 @@ type APIServerAccessProfile struct {
 	PrivateDNSZone *string
 	// EnablePrivateClusterPublicFQDN defines whether to create additional public FQDN for private cluster or not.
 	EnablePrivateClusterPublicFQDN *bool
+	// EnableVnetIntegration defines whether to project the API server into the subnet identified by SubnetID.
+	EnableVnetIntegration *bool
+	// SubnetID is the resource ID of the subnet the API server is projected into.
+	SubnetID *string
+}
+
+// DefenderProfile enables Microsoft Defender for Containers on an AKS cluster.
+type DefenderProfile struct {
+	// LogAnalyticsWorkspaceID is the resource ID of the Log Analytics workspace Defender sends its security events to.
+	LogAnalyticsWorkspaceID string
+}
+
+// ImageCleaner removes the unused vulnerable images from the nodes of an AKS cluster.
+type ImageCleaner struct {
+	// Enabled defines whether the image cleaner runs on the nodes of the cluster.
+	Enabled bool
+	// IntervalHours is the interval between two scans of the images of the nodes. AKS defaults it when nil.
+	IntervalHours *int32
+}
+
+// WorkloadAutoScalerProfile is the configuration of the AKS-managed components scaling the workloads of an AKS cluster.
+type WorkloadAutoScalerProfile struct {
+	// Keda defines whether to enable KEDA, the Kubernetes event-driven autoscaler.
+	Keda bool
+	// VerticalPodAutoscaler defines whether to enable the vertical pod autoscaler.
+	VerticalPodAutoscaler bool
+}
+
+// HTTPProxyConfig is the HTTP proxy configuration of the nodes of an AKS cluster.
+type HTTPProxyConfig struct {
+	// HTTPProxy is the endpoint of the proxy server for HTTP requests.
+	HTTPProxy string
+
+	// HTTPSProxy is the endpoint of the proxy server for HTTPS requests.
+	HTTPSProxy string
+
+	// NoProxy are the endpoints which are reached without going through the proxy servers.
+	NoProxy []string
+
+	// GetTrustedCA is a function that returns the PEM encoded CA certificate used to connect to the proxy servers, if
+	// any. It is only called when the cluster is created, as the HTTP proxy configuration can't be updated.
+	GetTrustedCA func() ([]byte, error)
 }
 
 var _ azure.ResourceSpecGetterWithHeaders = (*ManagedClusterSpec)(nil)
@@ -185,49 +281,57 @@ func (s *ManagedClusterSpec) Parameters(existing interface{}) (params interface{
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to decode SSHPublicKey")
 	}
-	managedCluster := containerservice.ManagedCluster{
-		Identity: &containerservice.ManagedClusterIdentity{
-			Type: containerservice.ResourceIdentityTypeSystemAssigned,
+	managedCluster := armcontainerservice.ManagedCluster{
+		Identity: &armcontainerservice.ManagedClusterIdentity{
+			Type: to.Ptr(armcontainerservice.ResourceIdentityTypeSystemAssigned),
 		},
-		Location: &s.Location,
-		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
-			NodeResourceGroup: &s.NodeResourceGroup,
-			EnableRBAC:        to.BoolPtr(true),
-			DNSPrefix:         &s.Name,
-			KubernetesVersion: &s.Version,
-			LinuxProfile: &containerservice.LinuxProfile{
-				AdminUsername: to.StringPtr(azure.DefaultAKSUserName),
-				SSH: &containerservice.SSHConfiguration{
-					PublicKeys: &[]containerservice.SSHPublicKey{
+		Location: to.Ptr(s.Location),
+		Properties: &armcontainerservice.ManagedClusterProperties{
+			NodeResourceGroup: to.Ptr(s.NodeResourceGroup),
+			EnableRBAC:        to.Ptr(true),
+			DNSPrefix:         to.Ptr(s.Name),
+			KubernetesVersion: to.Ptr(s.Version),
+			LinuxProfile: &armcontainerservice.LinuxProfile{
+				AdminUsername: to.Ptr(azure.DefaultAKSUserName),
+				SSH: &armcontainerservice.SSHConfiguration{
+					PublicKeys: []*armcontainerservice.SSHPublicKey{
 						{
-							KeyData: to.StringPtr(string(decodedSSHPublicKey)),
+							KeyData: to.Ptr(string(decodedSSHPublicKey)),
 						},
 					},
 				},
 			},
-			ServicePrincipalProfile: &containerservice.ManagedClusterServicePrincipalProfile{
-				ClientID: to.StringPtr("msi"),
-			},
-			AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{},
-			NetworkProfile: &containerservice.NetworkProfile{
-				NetworkPlugin:   containerservice.NetworkPlugin(s.NetworkPlugin),
-				LoadBalancerSku: containerservice.LoadBalancerSku(s.LoadBalancerSKU),
-				NetworkPolicy:   containerservice.NetworkPolicy(s.NetworkPolicy),
+			ServicePrincipalProfile: &armcontainerservice.ManagedClusterServicePrincipalProfile{
+				ClientID: to.Ptr("msi"),
 			},
+			AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{},
+			NetworkProfile:    &armcontainerservice.NetworkProfile{},
 		},
 	}
 
-	if tags := *to.StringMapPtr(s.Tags); len(tags) != 0 {
+	if tags := converters.TagsToMap(s.Tags); len(tags) != 0 {
 		managedCluster.Tags = tags
 	}
 
+	if s.NetworkPlugin != "" {
+		managedCluster.Properties.NetworkProfile.NetworkPlugin = to.Ptr(armcontainerservice.NetworkPlugin(s.NetworkPlugin))
+	}
+
+	if s.LoadBalancerSKU != "" {
+		managedCluster.Properties.NetworkProfile.LoadBalancerSKU = to.Ptr(armcontainerservice.LoadBalancerSKU(s.LoadBalancerSKU))
+	}
+
+	if s.NetworkPolicy != "" {
+		managedCluster.Properties.NetworkProfile.NetworkPolicy = to.Ptr(armcontainerservice.NetworkPolicy(s.NetworkPolicy))
+	}
+
 	if s.PodCIDR != "" {
-		managedCluster.NetworkProfile.PodCidr = &s.PodCIDR
+		managedCluster.Properties.NetworkProfile.PodCidr = to.Ptr(s.PodCIDR)
 	}
 
 	if s.ServiceCIDR != "" {
 		if s.DNSServiceIP == nil {
-			managedCluster.NetworkProfile.ServiceCidr = &s.ServiceCIDR
+			managedCluster.Properties.NetworkProfile.ServiceCidr = to.Ptr(s.ServiceCIDR)
 			ip, _, err := net.ParseCIDR(s.ServiceCIDR)
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse service cidr: %w", err)
@@ -238,94 +342,155 @@ func (s *ManagedClusterSpec) Parameters(existing interface{}) (params interface{
 			// https://golang.org/src/net/ip.go#L48
 			ip[15] = byte(10)
 			dnsIP := ip.String()
-			managedCluster.NetworkProfile.DNSServiceIP = &dnsIP
+			managedCluster.Properties.NetworkProfile.DNSServiceIP = &dnsIP
 		} else {
-			managedCluster.NetworkProfile.DNSServiceIP = s.DNSServiceIP
+			managedCluster.Properties.NetworkProfile.DNSServiceIP = s.DNSServiceIP
+		}
+	}
+
+	if s.Identity != nil && s.Identity.Type == string(armcontainerservice.ResourceIdentityTypeUserAssigned) {
+		managedCluster.Identity = &armcontainerservice.ManagedClusterIdentity{
+			Type: to.Ptr(armcontainerservice.ResourceIdentityTypeUserAssigned),
+			UserAssignedIdentities: map[string]*armcontainerservice.ManagedServiceIdentityUserAssignedIdentitiesValue{
+				s.Identity.UserAssignedIdentityResourceID: {},
+			},
+		}
+	}
+
+	if s.KubeletUserAssignedIdentity != "" {
+		managedCluster.Properties.IdentityProfile = map[string]*armcontainerservice.UserAssignedIdentity{
+			KubeletIdentityKey: {
+				ResourceID: to.Ptr(s.KubeletUserAssignedIdentity),
+			},
 		}
 	}
 
 	if s.AADProfile != nil {
-		managedCluster.AadProfile = &containerservice.ManagedClusterAADProfile{
-			Managed:             &s.AADProfile.Managed,
-			EnableAzureRBAC:     &s.AADProfile.EnableAzureRBAC,
-			AdminGroupObjectIDs: &s.AADProfile.AdminGroupObjectIDs,
+		managedCluster.Properties.AADProfile = &armcontainerservice.ManagedClusterAADProfile{
+			Managed:             to.Ptr(s.AADProfile.Managed),
+			EnableAzureRBAC:     to.Ptr(s.AADProfile.EnableAzureRBAC),
+			AdminGroupObjectIDs: to.SliceOfPtrs(s.AADProfile.AdminGroupObjectIDs...),
 		}
 	}
+	managedCluster.Properties.DisableLocalAccounts = s.DisableLocalAccounts
 
 	for i := range s.AddonProfiles {
-		if managedCluster.AddonProfiles == nil {
-			managedCluster.AddonProfiles = map[string]*containerservice.ManagedClusterAddonProfile{}
+		if managedCluster.Properties.AddonProfiles == nil {
+			managedCluster.Properties.AddonProfiles = map[string]*armcontainerservice.ManagedClusterAddonProfile{}
 		}
 		item := s.AddonProfiles[i]
-		addonProfile := &containerservice.ManagedClusterAddonProfile{
-			Enabled: &item.Enabled,
+		addonProfile := &armcontainerservice.ManagedClusterAddonProfile{
+			Enabled: to.Ptr(item.Enabled),
 		}
 		if item.Config != nil {
-			addonProfile.Config = *to.StringMapPtr(item.Config)
+			addonProfile.Config = converters.TagsToMap(item.Config)
 		}
-		managedCluster.AddonProfiles[item.Name] = addonProfile
+		managedCluster.Properties.AddonProfiles[item.Name] = addonProfile
 	}
 
 	if s.SKU != nil {
-		tierName := containerservice.ManagedClusterSKUTier(s.SKU.Tier)
-		managedCluster.Sku = &containerservice.ManagedClusterSKU{
-			Name: containerservice.ManagedClusterSKUNameBasic,
-			Tier: tierName,
+		managedCluster.SKU = &armcontainerservice.ManagedClusterSKU{
+			Name: to.Ptr(armcontainerservice.ManagedClusterSKUNameBase),
+			Tier: to.Ptr(skuTier(s.SKU.Tier)),
 		}
 	}
 
 	if s.LoadBalancerProfile != nil {
-		managedCluster.NetworkProfile.LoadBalancerProfile = &containerservice.ManagedClusterLoadBalancerProfile{
+		managedCluster.Properties.NetworkProfile.LoadBalancerProfile = &armcontainerservice.ManagedClusterLoadBalancerProfile{
 			AllocatedOutboundPorts: s.LoadBalancerProfile.AllocatedOutboundPorts,
 			IdleTimeoutInMinutes:   s.LoadBalancerProfile.IdleTimeoutInMinutes,
 		}
 		if s.LoadBalancerProfile.ManagedOutboundIPs != nil {
-			managedCluster.NetworkProfile.LoadBalancerProfile.ManagedOutboundIPs = &containerservice.ManagedClusterLoadBalancerProfileManagedOutboundIPs{Count: s.LoadBalancerProfile.ManagedOutboundIPs}
+			managedCluster.Properties.NetworkProfile.LoadBalancerProfile.ManagedOutboundIPs = &armcontainerservice.ManagedClusterLoadBalancerProfileManagedOutboundIPs{Count: s.LoadBalancerProfile.ManagedOutboundIPs}
 		}
 		if len(s.LoadBalancerProfile.OutboundIPPrefixes) > 0 {
-			managedCluster.NetworkProfile.LoadBalancerProfile.OutboundIPPrefixes = &containerservice.ManagedClusterLoadBalancerProfileOutboundIPPrefixes{
+			managedCluster.Properties.NetworkProfile.LoadBalancerProfile.OutboundIPPrefixes = &armcontainerservice.ManagedClusterLoadBalancerProfileOutboundIPPrefixes{
 				PublicIPPrefixes: convertToResourceReferences(s.LoadBalancerProfile.OutboundIPPrefixes),
 			}
 		}
 		if len(s.LoadBalancerProfile.OutboundIPs) > 0 {
-			managedCluster.NetworkProfile.LoadBalancerProfile.OutboundIPs = &containerservice.ManagedClusterLoadBalancerProfileOutboundIPs{
+			managedCluster.Properties.NetworkProfile.LoadBalancerProfile.OutboundIPs = &armcontainerservice.ManagedClusterLoadBalancerProfileOutboundIPs{
 				PublicIPs: convertToResourceReferences(s.LoadBalancerProfile.OutboundIPs),
 			}
 		}
 	}
 
+	if s.OutboundType != "" {
+		managedCluster.Properties.NetworkProfile.OutboundType = to.Ptr(armcontainerservice.OutboundType(s.OutboundType))
+	}
+
+	if s.NatGatewayProfile != nil {
+		managedCluster.Properties.NetworkProfile.NatGatewayProfile = &armcontainerservice.ManagedClusterNATGatewayProfile{
+			IdleTimeoutInMinutes: s.NatGatewayProfile.IdleTimeoutInMinutes,
+		}
+		if s.NatGatewayProfile.ManagedOutboundIPs != nil {
+			managedCluster.Properties.NetworkProfile.NatGatewayProfile.ManagedOutboundIPProfile = &armcontainerservice.ManagedClusterManagedOutboundIPProfile{
+				Count: s.NatGatewayProfile.ManagedOutboundIPs,
+			}
+		}
+	}
+
 	if s.APIServerAccessProfile != nil {
-		managedCluster.APIServerAccessProfile = &containerservice.ManagedClusterAPIServerAccessProfile{
-			AuthorizedIPRanges:             &s.APIServerAccessProfile.AuthorizedIPRanges,
+		managedCluster.Properties.APIServerAccessProfile = &armcontainerservice.ManagedClusterAPIServerAccessProfile{
+			AuthorizedIPRanges:             to.SliceOfPtrs(s.APIServerAccessProfile.AuthorizedIPRanges...),
 			EnablePrivateCluster:           s.APIServerAccessProfile.EnablePrivateCluster,
 			PrivateDNSZone:                 s.APIServerAccessProfile.PrivateDNSZone,
 			EnablePrivateClusterPublicFQDN: s.APIServerAccessProfile.EnablePrivateClusterPublicFQDN,
+			EnableVnetIntegration:          s.APIServerAccessProfile.EnableVnetIntegration,
+			SubnetID:                       s.APIServerAccessProfile.SubnetID,
+		}
+	}
+
+	if s.DefenderProfile != nil || s.ImageCleaner != nil {
+		managedCluster.Properties.SecurityProfile = &armcontainerservice.ManagedClusterSecurityProfile{}
+	}
+
+	if s.DefenderProfile != nil {
+		managedCluster.Properties.SecurityProfile.Defender = &armcontainerservice.ManagedClusterSecurityProfileDefender{
+			LogAnalyticsWorkspaceResourceID: to.Ptr(s.DefenderProfile.LogAnalyticsWorkspaceID),
+			SecurityMonitoring: &armcontainerservice.ManagedClusterSecurityProfileDefenderSecurityMonitoring{
+				Enabled: to.Ptr(true),
+			},
+		}
+	}
+
+	if s.ImageCleaner != nil {
+		managedCluster.Properties.SecurityProfile.ImageCleaner = &armcontainerservice.ManagedClusterSecurityProfileImageCleaner{
+			Enabled:       to.Ptr(s.ImageCleaner.Enabled),
+			IntervalHours: s.ImageCleaner.IntervalHours,
+		}
+	}
+
+	if s.WorkloadAutoScalerProfile != nil {
+		managedCluster.Properties.WorkloadAutoScalerProfile = &armcontainerservice.ManagedClusterWorkloadAutoScalerProfile{
+			Keda: &armcontainerservice.ManagedClusterWorkloadAutoScalerProfileKeda{
+				Enabled: to.Ptr(s.WorkloadAutoScalerProfile.Keda),
+			},
+			VerticalPodAutoscaler: &armcontainerservice.ManagedClusterWorkloadAutoScalerProfileVerticalPodAutoscaler{
+				Enabled: to.Ptr(s.WorkloadAutoScalerProfile.VerticalPodAutoscaler),
+			},
 		}
 	}
 
 	if existing != nil {
-		existingMC, ok := existing.(containerservice.ManagedCluster)
+		existingMC, ok := existing.(armcontainerservice.ManagedCluster)
 		if !ok {
-			return nil, fmt.Errorf("%T is not a containerservice.ManagedCluster", existing)
+			return nil, fmt.Errorf("%T is not an armcontainerservice.ManagedCluster", existing)
+		}
+		if existingMC.Properties == nil {
+			existingMC.Properties = &armcontainerservice.ManagedClusterProperties{}
 		}
-		ps := *existingMC.ManagedClusterProperties.ProvisioningState
+		ps := pointer.StringDeref(existingMC.Properties.ProvisioningState, "")
 		if ps != string(infrav1.Canceled) && ps != string(infrav1.Failed) && ps != string(infrav1.Succeeded) {
 			return nil, azure.WithTransientError(errors.Errorf("Unable to update existing managed cluster in non-terminal state. Managed cluster must be in one of the following provisioning states: Canceled, Failed, or Succeeded. Actual state: %s", ps), 20*time.Second)
 		}
 
-		// Normalize the LoadBalancerProfile so the diff below doesn't get thrown off by AKS added properties.
-		if managedCluster.NetworkProfile.LoadBalancerProfile == nil {
-			// If our LoadBalancerProfile generated by the spec is nil, then don't worry about what AKS has added.
-			existingMC.NetworkProfile.LoadBalancerProfile = nil
-		} else {
-			// If our LoadBalancerProfile generated by the spec is not nil, then remove the effective outbound IPs from
-			// AKS.
-			existingMC.NetworkProfile.LoadBalancerProfile.EffectiveOutboundIPs = nil
-		}
-
 		// Avoid changing agent pool profiles through AMCP and just use the existing agent pool profiles
 		// AgentPool changes are managed through AMMP.
-		managedCluster.AgentPoolProfiles = existingMC.AgentPoolProfiles
+		managedCluster.Properties.AgentPoolProfiles = existingMC.Properties.AgentPoolProfiles
+
+		// The HTTP proxy configuration can only be set when the cluster is created, so the trusted CA isn't read again.
+		managedCluster.Properties.HTTPProxyConfig = existingMC.Properties.HTTPProxyConfig
 
 		diff := computeDiffOfNormalizedClusters(managedCluster, existingMC)
 		if diff == "" {
@@ -340,88 +505,238 @@ func (s *ManagedClusterSpec) Parameters(existing interface{}) (params interface{
 
 		for i := range agentPoolSpecs {
 			profile := converters.AgentPoolToManagedClusterAgentPoolProfile(agentPoolSpecs[i])
-			*managedCluster.AgentPoolProfiles = append(*managedCluster.AgentPoolProfiles, profile)
+			managedCluster.Properties.AgentPoolProfiles = append(managedCluster.Properties.AgentPoolProfiles, &profile)
+		}
+
+		if s.HTTPProxyConfig != nil {
+			managedCluster.Properties.HTTPProxyConfig, err = s.HTTPProxyConfig.parameters()
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get HTTP proxy configuration for managed cluster %s", s.Name)
+			}
 		}
 	}
 
 	return managedCluster, nil
 }
 
-func convertToResourceReferences(resources []string) *[]containerservice.ResourceReference {
-	resourceReferences := make([]containerservice.ResourceReference, len(resources))
+// skuTier returns the AKS SKU tier of a managed cluster SKU tier. The Paid tier of AzureManagedControlPlanes is the
+// Standard tier of the AKS API.
+func skuTier(tier string) armcontainerservice.ManagedClusterSKUTier {
+	if tier == string(infrav1exp.PaidManagedControlPlaneTier) {
+		return armcontainerservice.ManagedClusterSKUTierStandard
+	}
+	return armcontainerservice.ManagedClusterSKUTier(tier)
+}
+
+// parameters returns the HTTP proxy configuration of a new managed cluster. The trusted CA is base64 encoded, as
+// expected by AKS.
+func (c *HTTPProxyConfig) parameters() (*armcontainerservice.ManagedClusterHTTPProxyConfig, error) {
+	httpProxyConfig := &armcontainerservice.ManagedClusterHTTPProxyConfig{}
+	if c.HTTPProxy != "" {
+		httpProxyConfig.HTTPProxy = to.Ptr(c.HTTPProxy)
+	}
+	if c.HTTPSProxy != "" {
+		httpProxyConfig.HTTPSProxy = to.Ptr(c.HTTPSProxy)
+	}
+	if len(c.NoProxy) > 0 {
+		httpProxyConfig.NoProxy = to.SliceOfPtrs(c.NoProxy...)
+	}
+	if c.GetTrustedCA != nil {
+		trustedCA, err := c.GetTrustedCA()
+		if err != nil {
+			return nil, err
+		}
+		httpProxyConfig.TrustedCa = to.Ptr(base64.StdEncoding.EncodeToString(trustedCA))
+	}
+	return httpProxyConfig, nil
+}
+
+// normalizeLoadBalancerProfile returns the properties of an existing load balancer profile which are set by the
+// desired one.
+func normalizeLoadBalancerProfile(desired, existing *armcontainerservice.ManagedClusterLoadBalancerProfile) *armcontainerservice.ManagedClusterLoadBalancerProfile {
+	normalized := &armcontainerservice.ManagedClusterLoadBalancerProfile{}
+	if existing == nil {
+		return normalized
+	}
+	if desired.ManagedOutboundIPs != nil && existing.ManagedOutboundIPs != nil {
+		normalized.ManagedOutboundIPs = &armcontainerservice.ManagedClusterLoadBalancerProfileManagedOutboundIPs{
+			Count: existing.ManagedOutboundIPs.Count,
+		}
+	}
+	if desired.OutboundIPPrefixes != nil {
+		normalized.OutboundIPPrefixes = existing.OutboundIPPrefixes
+	}
+	if desired.OutboundIPs != nil {
+		normalized.OutboundIPs = existing.OutboundIPs
+	}
+	if desired.AllocatedOutboundPorts != nil {
+		normalized.AllocatedOutboundPorts = existing.AllocatedOutboundPorts
+	}
+	if desired.IdleTimeoutInMinutes != nil {
+		normalized.IdleTimeoutInMinutes = existing.IdleTimeoutInMinutes
+	}
+	return normalized
+}
+
+// normalizeStrings returns nil for an empty list of strings, as AKS may return them either as nil or empty.
+func normalizeStrings(values []*string) []*string {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+func convertToResourceReferences(resources []string) []*armcontainerservice.ResourceReference {
+	resourceReferences := make([]*armcontainerservice.ResourceReference, len(resources))
 	for i := range resources {
-		resourceReferences[i] = containerservice.ResourceReference{ID: &resources[i]}
+		resourceReferences[i] = &armcontainerservice.ResourceReference{ID: to.Ptr(resources[i])}
 	}
-	return &resourceReferences
+	return resourceReferences
 }
 
-func computeDiffOfNormalizedClusters(managedCluster containerservice.ManagedCluster, existingMC containerservice.ManagedCluster) string {
+func computeDiffOfNormalizedClusters(managedCluster armcontainerservice.ManagedCluster, existingMC armcontainerservice.ManagedCluster) string {
 	// Normalize properties for the desired (CR spec) and existing managed
 	// cluster, so that we check only those fields that were specified in
 	// the initial CreateOrUpdate request and that can be modified.
 	// Without comparing to normalized properties, we would always get a
 	// difference in desired and existing, which would result in sending
 	// unnecessary Azure API requests.
-	propertiesNormalized := &containerservice.ManagedClusterProperties{
-		KubernetesVersion: managedCluster.ManagedClusterProperties.KubernetesVersion,
-		NetworkProfile:    &containerservice.NetworkProfile{},
+	desired := managedCluster.Properties
+	existing := existingMC.Properties
+
+	propertiesNormalized := &armcontainerservice.ManagedClusterProperties{
+		KubernetesVersion: desired.KubernetesVersion,
+		NetworkProfile:    &armcontainerservice.NetworkProfile{},
+	}
+
+	existingMCPropertiesNormalized := &armcontainerservice.ManagedClusterProperties{
+		KubernetesVersion: existing.KubernetesVersion,
+		NetworkProfile:    &armcontainerservice.NetworkProfile{},
+	}
+
+	if desired.AADProfile != nil {
+		propertiesNormalized.AADProfile = &armcontainerservice.ManagedClusterAADProfile{
+			Managed:             desired.AADProfile.Managed,
+			EnableAzureRBAC:     desired.AADProfile.EnableAzureRBAC,
+			AdminGroupObjectIDs: normalizeStrings(desired.AADProfile.AdminGroupObjectIDs),
+		}
 	}
 
-	existingMCPropertiesNormalized := &containerservice.ManagedClusterProperties{
-		KubernetesVersion: existingMC.ManagedClusterProperties.KubernetesVersion,
-		NetworkProfile:    &containerservice.NetworkProfile{},
+	if existing.AADProfile != nil {
+		existingMCPropertiesNormalized.AADProfile = &armcontainerservice.ManagedClusterAADProfile{
+			Managed:             existing.AADProfile.Managed,
+			EnableAzureRBAC:     existing.AADProfile.EnableAzureRBAC,
+			AdminGroupObjectIDs: normalizeStrings(existing.AADProfile.AdminGroupObjectIDs),
+		}
+	}
+
+	// DisableLocalAccounts is only compared when set by the spec, as AKS returns it as false when it isn't set.
+	if desired.DisableLocalAccounts != nil {
+		propertiesNormalized.DisableLocalAccounts = desired.DisableLocalAccounts
+		existingMCPropertiesNormalized.DisableLocalAccounts = to.Ptr(pointer.BoolDeref(existing.DisableLocalAccounts, false))
 	}
 
-	if managedCluster.AadProfile != nil {
-		propertiesNormalized.AadProfile = &containerservice.ManagedClusterAADProfile{
-			Managed:             managedCluster.AadProfile.Managed,
-			EnableAzureRBAC:     managedCluster.AadProfile.EnableAzureRBAC,
-			AdminGroupObjectIDs: managedCluster.AadProfile.AdminGroupObjectIDs,
+	// The LoadBalancerProfile is only compared when set by the spec, and only on the properties it sets, so the diff
+	// doesn't get thrown off by the defaults and the effective outbound IPs AKS adds.
+	if desired.NetworkProfile != nil && desired.NetworkProfile.LoadBalancerProfile != nil {
+		propertiesNormalized.NetworkProfile.LoadBalancerProfile = desired.NetworkProfile.LoadBalancerProfile
+		var existingProfile *armcontainerservice.ManagedClusterLoadBalancerProfile
+		if existing.NetworkProfile != nil {
+			existingProfile = existing.NetworkProfile.LoadBalancerProfile
 		}
+		existingMCPropertiesNormalized.NetworkProfile.LoadBalancerProfile = normalizeLoadBalancerProfile(desired.NetworkProfile.LoadBalancerProfile, existingProfile)
 	}
 
-	if existingMC.AadProfile != nil {
-		existingMCPropertiesNormalized.AadProfile = &containerservice.ManagedClusterAADProfile{
-			Managed:             existingMC.AadProfile.Managed,
-			EnableAzureRBAC:     existingMC.AadProfile.EnableAzureRBAC,
-			AdminGroupObjectIDs: existingMC.AadProfile.AdminGroupObjectIDs,
+	// The NAT gateway profile is only compared when set by the spec, as AKS returns its defaults and effective outbound
+	// IPs otherwise. Unset properties are left to their existing values.
+	if desired.NetworkProfile != nil && desired.NetworkProfile.NatGatewayProfile != nil {
+		propertiesNormalized.NetworkProfile.NatGatewayProfile = desired.NetworkProfile.NatGatewayProfile
+		existingMCPropertiesNormalized.NetworkProfile.NatGatewayProfile = &armcontainerservice.ManagedClusterNATGatewayProfile{}
+		if existing.NetworkProfile != nil && existing.NetworkProfile.NatGatewayProfile != nil {
+			existingProfile := existing.NetworkProfile.NatGatewayProfile
+			if desired.NetworkProfile.NatGatewayProfile.IdleTimeoutInMinutes != nil {
+				existingMCPropertiesNormalized.NetworkProfile.NatGatewayProfile.IdleTimeoutInMinutes = existingProfile.IdleTimeoutInMinutes
+			}
+			if desired.NetworkProfile.NatGatewayProfile.ManagedOutboundIPProfile != nil {
+				existingMCPropertiesNormalized.NetworkProfile.NatGatewayProfile.ManagedOutboundIPProfile = existingProfile.ManagedOutboundIPProfile
+			}
 		}
 	}
 
-	if managedCluster.NetworkProfile != nil {
-		propertiesNormalized.NetworkProfile.LoadBalancerProfile = managedCluster.NetworkProfile.LoadBalancerProfile
+	if desired.APIServerAccessProfile != nil {
+		propertiesNormalized.APIServerAccessProfile = &armcontainerservice.ManagedClusterAPIServerAccessProfile{
+			AuthorizedIPRanges: normalizeStrings(desired.APIServerAccessProfile.AuthorizedIPRanges),
+		}
 	}
 
-	if existingMC.NetworkProfile != nil {
-		existingMCPropertiesNormalized.NetworkProfile.LoadBalancerProfile = existingMC.NetworkProfile.LoadBalancerProfile
+	if existing.APIServerAccessProfile != nil {
+		existingMCPropertiesNormalized.APIServerAccessProfile = &armcontainerservice.ManagedClusterAPIServerAccessProfile{
+			AuthorizedIPRanges: normalizeStrings(existing.APIServerAccessProfile.AuthorizedIPRanges),
+		}
 	}
 
-	if managedCluster.APIServerAccessProfile != nil {
-		propertiesNormalized.APIServerAccessProfile = &containerservice.ManagedClusterAPIServerAccessProfile{
-			AuthorizedIPRanges: managedCluster.APIServerAccessProfile.AuthorizedIPRanges,
+	// Defender is only compared when enabled by the spec, as AKS doesn't return the same security profile whether it
+	// was never enabled or explicitly disabled. The image cleaner is only compared when set by the spec, and its
+	// interval only when set too, as AKS returns its default otherwise.
+	if desired.SecurityProfile != nil {
+		propertiesNormalized.SecurityProfile = &armcontainerservice.ManagedClusterSecurityProfile{}
+		existingMCPropertiesNormalized.SecurityProfile = &armcontainerservice.ManagedClusterSecurityProfile{}
+		existingProfile := existing.SecurityProfile
+		if existingProfile == nil {
+			existingProfile = &armcontainerservice.ManagedClusterSecurityProfile{}
+		}
+		if desired.SecurityProfile.Defender != nil {
+			propertiesNormalized.SecurityProfile.Defender = desired.SecurityProfile.Defender
+			existingMCPropertiesNormalized.SecurityProfile.Defender = existingProfile.Defender
+		}
+		if desiredImageCleaner := desired.SecurityProfile.ImageCleaner; desiredImageCleaner != nil {
+			propertiesNormalized.SecurityProfile.ImageCleaner = desiredImageCleaner
+			existingImageCleaner := &armcontainerservice.ManagedClusterSecurityProfileImageCleaner{Enabled: to.Ptr(false)}
+			if existingProfile.ImageCleaner != nil {
+				existingImageCleaner.Enabled = to.Ptr(pointer.BoolDeref(existingProfile.ImageCleaner.Enabled, false))
+				if desiredImageCleaner.IntervalHours != nil {
+					existingImageCleaner.IntervalHours = existingProfile.ImageCleaner.IntervalHours
+				}
+			}
+			existingMCPropertiesNormalized.SecurityProfile.ImageCleaner = existingImageCleaner
 		}
 	}
 
-	if existingMC.APIServerAccessProfile != nil {
-		existingMCPropertiesNormalized.APIServerAccessProfile = &containerservice.ManagedClusterAPIServerAccessProfile{
-			AuthorizedIPRanges: existingMC.APIServerAccessProfile.AuthorizedIPRanges,
+	// The workload autoscalers are only compared when set by the spec. AKS omits the ones which were never enabled.
+	if desired.WorkloadAutoScalerProfile != nil {
+		propertiesNormalized.WorkloadAutoScalerProfile = desired.WorkloadAutoScalerProfile
+		existingMCPropertiesNormalized.WorkloadAutoScalerProfile = &armcontainerservice.ManagedClusterWorkloadAutoScalerProfile{
+			Keda: &armcontainerservice.ManagedClusterWorkloadAutoScalerProfileKeda{
+				Enabled: to.Ptr(false),
+			},
+			VerticalPodAutoscaler: &armcontainerservice.ManagedClusterWorkloadAutoScalerProfileVerticalPodAutoscaler{
+				Enabled: to.Ptr(false),
+			},
+		}
+		if existingProfile := existing.WorkloadAutoScalerProfile; existingProfile != nil {
+			if existingProfile.Keda != nil {
+				existingMCPropertiesNormalized.WorkloadAutoScalerProfile.Keda.Enabled = to.Ptr(pointer.BoolDeref(existingProfile.Keda.Enabled, false))
+			}
+			if existingProfile.VerticalPodAutoscaler != nil {
+				existingMCPropertiesNormalized.WorkloadAutoScalerProfile.VerticalPodAutoscaler.Enabled = to.Ptr(pointer.BoolDeref(existingProfile.VerticalPodAutoscaler.Enabled, false))
+			}
 		}
 	}
 
-	clusterNormalized := &containerservice.ManagedCluster{
-		ManagedClusterProperties: propertiesNormalized,
-		Tags:                     managedCluster.Tags,
+	clusterNormalized := &armcontainerservice.ManagedCluster{
+		Properties: propertiesNormalized,
+		Tags:       managedCluster.Tags,
 	}
-	existingMCClusterNormalized := &containerservice.ManagedCluster{
-		ManagedClusterProperties: existingMCPropertiesNormalized,
-		Tags:                     existingMC.Tags,
+	existingMCClusterNormalized := &armcontainerservice.ManagedCluster{
+		Properties: existingMCPropertiesNormalized,
+		Tags:       existingMC.Tags,
 	}
 
-	if managedCluster.Sku != nil {
-		clusterNormalized.Sku = managedCluster.Sku
+	if managedCluster.SKU != nil {
+		clusterNormalized.SKU = managedCluster.SKU
 	}
-	if existingMC.Sku != nil {
-		existingMCClusterNormalized.Sku = existingMC.Sku
+	if existingMC.SKU != nil {
+		existingMCClusterNormalized.SKU = existingMC.SKU
 	}
 
 	diff := cmp.Diff(clusterNormalized, existingMCClusterNormalized)
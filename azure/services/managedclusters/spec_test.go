@@ -17,10 +17,11 @@ limitations under the License.
 package managedclusters
 
 import (
+	"errors"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -39,9 +40,9 @@ func TestParameters(t *testing.T) {
 	}{
 		{
 			name: "managedcluster in non-terminal provisioning state",
-			existing: containerservice.ManagedCluster{
-				ManagedClusterProperties: &containerservice.ManagedClusterProperties{
-					ProvisioningState: to.StringPtr("Deleting"),
+			existing: armcontainerservice.ManagedCluster{
+				Properties: &armcontainerservice.ManagedClusterProperties{
+					ProvisioningState: to.Ptr("Deleting"),
 				},
 			},
 			spec: &ManagedClusterSpec{
@@ -80,16 +81,16 @@ func TestParameters(t *testing.T) {
 							Replicas:          int32(4),
 							Cluster:           "test-managedcluster",
 							SKU:               "test_SKU",
-							Version:           to.StringPtr("v1.22.0"),
+							Version:           to.Ptr("v1.22.0"),
 							VnetSubnetID:      "fake/subnet/id",
-							MaxPods:           to.Int32Ptr(int32(32)),
+							MaxPods:           to.Ptr[int32](32),
 							AvailabilityZones: []string{"1", "2"},
 						},
 					}, nil
 				},
 			},
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(containerservice.ManagedCluster{}))
+				g.Expect(result).To(BeAssignableToTypeOf(armcontainerservice.ManagedCluster{}))
 				g.Expect(gomockinternal.DiffEq(result).Matches(getSampleManagedCluster())).To(BeTrue(), cmp.Diff(result, getSampleManagedCluster()))
 			},
 		},
@@ -124,8 +125,625 @@ func TestParameters(t *testing.T) {
 				LoadBalancerSKU: "Standard",
 			},
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(containerservice.ManagedCluster{}))
-				g.Expect(result.(containerservice.ManagedCluster).KubernetesVersion).To(Equal(to.StringPtr("v1.22.99")))
+				g.Expect(result).To(BeAssignableToTypeOf(armcontainerservice.ManagedCluster{}))
+				g.Expect(result.(armcontainerservice.ManagedCluster).Properties.KubernetesVersion).To(Equal(to.Ptr("v1.22.99")))
+			},
+		},
+		{
+			name:     "managedcluster exists and the Paid SKU tier is set",
+			existing: getExistingCluster(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				SKU: &SKU{
+					Tier: string(infrav1exp.PaidManagedControlPlaneTier),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcontainerservice.ManagedCluster{}))
+				g.Expect(result.(armcontainerservice.ManagedCluster).SKU).To(Equal(&armcontainerservice.ManagedClusterSKU{
+					Name: to.Ptr(armcontainerservice.ManagedClusterSKUNameBase),
+					Tier: to.Ptr(armcontainerservice.ManagedClusterSKUTierStandard),
+				}))
+			},
+		},
+		{
+			name:     "managedcluster exists and Defender is enabled",
+			existing: getExistingCluster(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				DefenderProfile: &DefenderProfile{
+					LogAnalyticsWorkspaceID: "/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.OperationalInsights/workspaces/test-workspace",
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcontainerservice.ManagedCluster{}))
+				g.Expect(result.(armcontainerservice.ManagedCluster).Properties.SecurityProfile).To(Equal(&armcontainerservice.ManagedClusterSecurityProfile{
+					Defender: &armcontainerservice.ManagedClusterSecurityProfileDefender{
+						LogAnalyticsWorkspaceResourceID: to.Ptr("/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.OperationalInsights/workspaces/test-workspace"),
+						SecurityMonitoring: &armcontainerservice.ManagedClusterSecurityProfileDefenderSecurityMonitoring{
+							Enabled: to.Ptr(true),
+						},
+					},
+				}))
+			},
+		},
+		{
+			name: "managedcluster exists with Defender enabled, no update needed",
+			existing: func() armcontainerservice.ManagedCluster {
+				mc := getExistingCluster()
+				mc.Properties.SecurityProfile = &armcontainerservice.ManagedClusterSecurityProfile{
+					Defender: &armcontainerservice.ManagedClusterSecurityProfileDefender{
+						LogAnalyticsWorkspaceResourceID: to.Ptr("/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.OperationalInsights/workspaces/test-workspace"),
+						SecurityMonitoring: &armcontainerservice.ManagedClusterSecurityProfileDefenderSecurityMonitoring{
+							Enabled: to.Ptr(true),
+						},
+					},
+				}
+				return mc
+			}(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				DefenderProfile: &DefenderProfile{
+					LogAnalyticsWorkspaceID: "/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.OperationalInsights/workspaces/test-workspace",
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name:     "managedcluster with workload autoscalers and the image cleaner does not exist",
+			existing: nil,
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Version:       "v1.22.0",
+				DefenderProfile: &DefenderProfile{
+					LogAnalyticsWorkspaceID: "/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.OperationalInsights/workspaces/test-workspace",
+				},
+				ImageCleaner: &ImageCleaner{
+					Enabled:       true,
+					IntervalHours: to.Ptr[int32](48),
+				},
+				WorkloadAutoScalerProfile: &WorkloadAutoScalerProfile{
+					Keda: true,
+				},
+				GetAllAgentPools: func() ([]azure.AgentPoolSpec, error) {
+					return []azure.AgentPoolSpec{}, nil
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcontainerservice.ManagedCluster{}))
+				mc := result.(armcontainerservice.ManagedCluster)
+				g.Expect(mc.Properties.SecurityProfile).To(Equal(&armcontainerservice.ManagedClusterSecurityProfile{
+					Defender: &armcontainerservice.ManagedClusterSecurityProfileDefender{
+						LogAnalyticsWorkspaceResourceID: to.Ptr("/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.OperationalInsights/workspaces/test-workspace"),
+						SecurityMonitoring: &armcontainerservice.ManagedClusterSecurityProfileDefenderSecurityMonitoring{
+							Enabled: to.Ptr(true),
+						},
+					},
+					ImageCleaner: &armcontainerservice.ManagedClusterSecurityProfileImageCleaner{
+						Enabled:       to.Ptr(true),
+						IntervalHours: to.Ptr[int32](48),
+					},
+				}))
+				g.Expect(mc.Properties.WorkloadAutoScalerProfile).To(Equal(&armcontainerservice.ManagedClusterWorkloadAutoScalerProfile{
+					Keda: &armcontainerservice.ManagedClusterWorkloadAutoScalerProfileKeda{
+						Enabled: to.Ptr(true),
+					},
+					VerticalPodAutoscaler: &armcontainerservice.ManagedClusterWorkloadAutoScalerProfileVerticalPodAutoscaler{
+						Enabled: to.Ptr(false),
+					},
+				}))
+			},
+		},
+		{
+			name: "managedcluster exists with workload autoscalers and the image cleaner, no update needed",
+			existing: func() armcontainerservice.ManagedCluster {
+				mc := getExistingCluster()
+				mc.Properties.SecurityProfile = &armcontainerservice.ManagedClusterSecurityProfile{
+					ImageCleaner: &armcontainerservice.ManagedClusterSecurityProfileImageCleaner{
+						Enabled:       to.Ptr(true),
+						IntervalHours: to.Ptr[int32](168),
+					},
+				}
+				mc.Properties.WorkloadAutoScalerProfile = &armcontainerservice.ManagedClusterWorkloadAutoScalerProfile{
+					Keda: &armcontainerservice.ManagedClusterWorkloadAutoScalerProfileKeda{
+						Enabled: to.Ptr(true),
+					},
+				}
+				return mc
+			}(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				ImageCleaner: &ImageCleaner{
+					Enabled: true,
+				},
+				WorkloadAutoScalerProfile: &WorkloadAutoScalerProfile{
+					Keda: true,
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "managedcluster exists and its workload autoscalers and image cleaner need to be updated",
+			existing: func() armcontainerservice.ManagedCluster {
+				mc := getExistingCluster()
+				mc.Properties.SecurityProfile = &armcontainerservice.ManagedClusterSecurityProfile{
+					ImageCleaner: &armcontainerservice.ManagedClusterSecurityProfileImageCleaner{
+						Enabled:       to.Ptr(true),
+						IntervalHours: to.Ptr[int32](168),
+					},
+				}
+				mc.Properties.WorkloadAutoScalerProfile = &armcontainerservice.ManagedClusterWorkloadAutoScalerProfile{
+					Keda: &armcontainerservice.ManagedClusterWorkloadAutoScalerProfileKeda{
+						Enabled: to.Ptr(true),
+					},
+				}
+				return mc
+			}(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				ImageCleaner: &ImageCleaner{
+					Enabled:       true,
+					IntervalHours: to.Ptr[int32](24),
+				},
+				WorkloadAutoScalerProfile: &WorkloadAutoScalerProfile{
+					VerticalPodAutoscaler: true,
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcontainerservice.ManagedCluster{}))
+				mc := result.(armcontainerservice.ManagedCluster)
+				g.Expect(mc.Properties.SecurityProfile.ImageCleaner).To(Equal(&armcontainerservice.ManagedClusterSecurityProfileImageCleaner{
+					Enabled:       to.Ptr(true),
+					IntervalHours: to.Ptr[int32](24),
+				}))
+				g.Expect(mc.Properties.WorkloadAutoScalerProfile).To(Equal(&armcontainerservice.ManagedClusterWorkloadAutoScalerProfile{
+					Keda: &armcontainerservice.ManagedClusterWorkloadAutoScalerProfileKeda{
+						Enabled: to.Ptr(false),
+					},
+					VerticalPodAutoscaler: &armcontainerservice.ManagedClusterWorkloadAutoScalerProfileVerticalPodAutoscaler{
+						Enabled: to.Ptr(true),
+					},
+				}))
+			},
+		},
+		{
+			name:     "managedcluster with user-assigned identities does not exist",
+			existing: nil,
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Version:       "v1.22.0",
+				Identity: &Identity{
+					Type:                           "UserAssigned",
+					UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+				},
+				KubeletUserAssignedIdentity: "/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/kubelet",
+				GetAllAgentPools: func() ([]azure.AgentPoolSpec, error) {
+					return []azure.AgentPoolSpec{}, nil
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcontainerservice.ManagedCluster{}))
+				mc := result.(armcontainerservice.ManagedCluster)
+				g.Expect(mc.Identity).To(Equal(&armcontainerservice.ManagedClusterIdentity{
+					Type: to.Ptr(armcontainerservice.ResourceIdentityTypeUserAssigned),
+					UserAssignedIdentities: map[string]*armcontainerservice.ManagedServiceIdentityUserAssignedIdentitiesValue{
+						"/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane": {},
+					},
+				}))
+				g.Expect(mc.Properties.IdentityProfile).To(Equal(map[string]*armcontainerservice.UserAssignedIdentity{
+					"kubeletidentity": {
+						ResourceID: to.Ptr("/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/kubelet"),
+					},
+				}))
+			},
+		},
+		{
+			name:     "managedcluster with API server VNet integration does not exist",
+			existing: nil,
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Version:       "v1.22.0",
+				APIServerAccessProfile: &APIServerAccessProfile{
+					EnableVnetIntegration: to.Ptr(true),
+					SubnetID:              to.Ptr("/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.Network/virtualNetworks/test-vnet/subnets/apiserver"),
+				},
+				GetAllAgentPools: func() ([]azure.AgentPoolSpec, error) {
+					return []azure.AgentPoolSpec{}, nil
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcontainerservice.ManagedCluster{}))
+				mc := result.(armcontainerservice.ManagedCluster)
+				g.Expect(mc.Properties.APIServerAccessProfile).To(Equal(&armcontainerservice.ManagedClusterAPIServerAccessProfile{
+					AuthorizedIPRanges:    []*string{},
+					EnableVnetIntegration: to.Ptr(true),
+					SubnetID:              to.Ptr("/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.Network/virtualNetworks/test-vnet/subnets/apiserver"),
+				}))
+			},
+		},
+		{
+			name:     "managedcluster with a managed NAT gateway does not exist",
+			existing: nil,
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Version:       "v1.22.0",
+				OutboundType:  "managedNATGateway",
+				NatGatewayProfile: &NatGatewayProfile{
+					ManagedOutboundIPs:   to.Ptr[int32](2),
+					IdleTimeoutInMinutes: to.Ptr[int32](10),
+				},
+				GetAllAgentPools: func() ([]azure.AgentPoolSpec, error) {
+					return []azure.AgentPoolSpec{}, nil
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcontainerservice.ManagedCluster{}))
+				networkProfile := result.(armcontainerservice.ManagedCluster).Properties.NetworkProfile
+				g.Expect(networkProfile.OutboundType).To(Equal(to.Ptr(armcontainerservice.OutboundTypeManagedNATGateway)))
+				g.Expect(networkProfile.NatGatewayProfile).To(Equal(&armcontainerservice.ManagedClusterNATGatewayProfile{
+					ManagedOutboundIPProfile: &armcontainerservice.ManagedClusterManagedOutboundIPProfile{
+						Count: to.Ptr[int32](2),
+					},
+					IdleTimeoutInMinutes: to.Ptr[int32](10),
+				}))
+			},
+		},
+		{
+			name: "managedcluster exists with a managed NAT gateway, no update needed",
+			existing: func() armcontainerservice.ManagedCluster {
+				mc := getExistingCluster()
+				mc.Properties.NetworkProfile = &armcontainerservice.NetworkProfile{
+					OutboundType: to.Ptr(armcontainerservice.OutboundTypeManagedNATGateway),
+					NatGatewayProfile: &armcontainerservice.ManagedClusterNATGatewayProfile{
+						ManagedOutboundIPProfile: &armcontainerservice.ManagedClusterManagedOutboundIPProfile{
+							Count: to.Ptr[int32](2),
+						},
+						EffectiveOutboundIPs: []*armcontainerservice.ResourceReference{
+							{ID: to.Ptr("/subscriptions/123/resourceGroups/test-node-rg/providers/Microsoft.Network/publicIPAddresses/test-ip")},
+						},
+						IdleTimeoutInMinutes: to.Ptr[int32](4),
+					},
+				}
+				return mc
+			}(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				OutboundType:    "managedNATGateway",
+				NatGatewayProfile: &NatGatewayProfile{
+					ManagedOutboundIPs: to.Ptr[int32](2),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "managedcluster exists with a managed NAT gateway and an update is needed",
+			existing: func() armcontainerservice.ManagedCluster {
+				mc := getExistingCluster()
+				mc.Properties.NetworkProfile = &armcontainerservice.NetworkProfile{
+					OutboundType: to.Ptr(armcontainerservice.OutboundTypeManagedNATGateway),
+					NatGatewayProfile: &armcontainerservice.ManagedClusterNATGatewayProfile{
+						ManagedOutboundIPProfile: &armcontainerservice.ManagedClusterManagedOutboundIPProfile{
+							Count: to.Ptr[int32](1),
+						},
+						EffectiveOutboundIPs: []*armcontainerservice.ResourceReference{
+							{ID: to.Ptr("/subscriptions/123/resourceGroups/test-node-rg/providers/Microsoft.Network/publicIPAddresses/test-ip")},
+						},
+						IdleTimeoutInMinutes: to.Ptr[int32](4),
+					},
+				}
+				return mc
+			}(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				OutboundType:    "managedNATGateway",
+				NatGatewayProfile: &NatGatewayProfile{
+					ManagedOutboundIPs: to.Ptr[int32](2),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcontainerservice.ManagedCluster{}))
+				g.Expect(result.(armcontainerservice.ManagedCluster).Properties.NetworkProfile.NatGatewayProfile.ManagedOutboundIPProfile.Count).To(Equal(to.Ptr[int32](2)))
+			},
+		},
+		{
+			name: "managedcluster exists with a load balancer profile, no update needed",
+			existing: func() armcontainerservice.ManagedCluster {
+				mc := getExistingCluster()
+				mc.Properties.NetworkProfile = &armcontainerservice.NetworkProfile{
+					LoadBalancerSKU: to.Ptr(armcontainerservice.LoadBalancerSKUStandard),
+					LoadBalancerProfile: &armcontainerservice.ManagedClusterLoadBalancerProfile{
+						ManagedOutboundIPs: &armcontainerservice.ManagedClusterLoadBalancerProfileManagedOutboundIPs{
+							Count:     to.Ptr[int32](2),
+							CountIPv6: to.Ptr[int32](0),
+						},
+						EffectiveOutboundIPs: []*armcontainerservice.ResourceReference{
+							{ID: to.Ptr("/subscriptions/123/resourceGroups/test-node-rg/providers/Microsoft.Network/publicIPAddresses/test-ip")},
+						},
+						AllocatedOutboundPorts:              to.Ptr[int32](1024),
+						IdleTimeoutInMinutes:                to.Ptr[int32](30),
+						EnableMultipleStandardLoadBalancers: to.Ptr(false),
+					},
+				}
+				return mc
+			}(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				LoadBalancerProfile: &LoadBalancerProfile{
+					ManagedOutboundIPs:     to.Ptr[int32](2),
+					AllocatedOutboundPorts: to.Ptr[int32](1024),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "managedcluster exists with a load balancer profile and an update is needed",
+			existing: func() armcontainerservice.ManagedCluster {
+				mc := getExistingCluster()
+				mc.Properties.NetworkProfile = &armcontainerservice.NetworkProfile{
+					LoadBalancerSKU: to.Ptr(armcontainerservice.LoadBalancerSKUStandard),
+					LoadBalancerProfile: &armcontainerservice.ManagedClusterLoadBalancerProfile{
+						ManagedOutboundIPs: &armcontainerservice.ManagedClusterLoadBalancerProfileManagedOutboundIPs{
+							Count:     to.Ptr[int32](2),
+							CountIPv6: to.Ptr[int32](0),
+						},
+						EffectiveOutboundIPs: []*armcontainerservice.ResourceReference{
+							{ID: to.Ptr("/subscriptions/123/resourceGroups/test-node-rg/providers/Microsoft.Network/publicIPAddresses/test-ip")},
+						},
+						AllocatedOutboundPorts:              to.Ptr[int32](0),
+						IdleTimeoutInMinutes:                to.Ptr[int32](30),
+						EnableMultipleStandardLoadBalancers: to.Ptr(false),
+					},
+				}
+				return mc
+			}(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				LoadBalancerProfile: &LoadBalancerProfile{
+					ManagedOutboundIPs:     to.Ptr[int32](2),
+					AllocatedOutboundPorts: to.Ptr[int32](1024),
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcontainerservice.ManagedCluster{}))
+				g.Expect(result.(armcontainerservice.ManagedCluster).Properties.NetworkProfile.LoadBalancerProfile.AllocatedOutboundPorts).To(Equal(to.Ptr[int32](1024)))
+			},
+		},
+		{
+			name: "managedcluster exists with local accounts enabled, no update needed",
+			existing: func() armcontainerservice.ManagedCluster {
+				mc := getExistingCluster()
+				return mc
+			}(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:              "v1.22.0",
+				LoadBalancerSKU:      "Standard",
+				DisableLocalAccounts: to.Ptr(false),
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "managedcluster exists and its local accounts need to be disabled",
+			existing: func() armcontainerservice.ManagedCluster {
+				mc := getExistingCluster()
+				mc.Properties.DisableLocalAccounts = to.Ptr(false)
+				return mc
+			}(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:              "v1.22.0",
+				LoadBalancerSKU:      "Standard",
+				DisableLocalAccounts: to.Ptr(true),
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcontainerservice.ManagedCluster{}))
+				g.Expect(result.(armcontainerservice.ManagedCluster).Properties.DisableLocalAccounts).To(Equal(to.Ptr(true)))
+			},
+		},
+		{
+			name:     "managedcluster with an HTTP proxy does not exist",
+			existing: nil,
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Version:       "v1.22.0",
+				HTTPProxyConfig: &HTTPProxyConfig{
+					HTTPSProxy: "http://proxy.example.com:3128/",
+					NoProxy:    []string{"example.com"},
+					GetTrustedCA: func() ([]byte, error) {
+						return []byte("-----BEGIN CERTIFICATE-----"), nil
+					},
+				},
+				GetAllAgentPools: func() ([]azure.AgentPoolSpec, error) {
+					return []azure.AgentPoolSpec{}, nil
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcontainerservice.ManagedCluster{}))
+				g.Expect(result.(armcontainerservice.ManagedCluster).Properties.HTTPProxyConfig).To(Equal(&armcontainerservice.ManagedClusterHTTPProxyConfig{
+					HTTPSProxy: to.Ptr("http://proxy.example.com:3128/"),
+					NoProxy:    []*string{to.Ptr("example.com")},
+					TrustedCa:  to.Ptr("LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0t"),
+				}))
+			},
+		},
+		{
+			name:     "managedcluster with an HTTP proxy does not exist and its trusted CA can't be read",
+			existing: nil,
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Version:       "v1.22.0",
+				HTTPProxyConfig: &HTTPProxyConfig{
+					HTTPSProxy: "http://proxy.example.com:3128/",
+					GetTrustedCA: func() ([]byte, error) {
+						return nil, errors.New("failed to get CA certificates Secret default/proxy-ca")
+					},
+				},
+				GetAllAgentPools: func() ([]azure.AgentPoolSpec, error) {
+					return []azure.AgentPoolSpec{}, nil
+				},
+			},
+			expectedError: "failed to get HTTP proxy configuration for managed cluster test-managedcluster: failed to get CA certificates Secret default/proxy-ca",
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "managedcluster exists with an HTTP proxy, no update needed",
+			existing: func() armcontainerservice.ManagedCluster {
+				mc := getExistingCluster()
+				mc.Properties.HTTPProxyConfig = &armcontainerservice.ManagedClusterHTTPProxyConfig{
+					HTTPSProxy: to.Ptr("http://proxy.example.com:3128/"),
+					TrustedCa:  to.Ptr("LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0t"),
+				}
+				return mc
+			}(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.0",
+				LoadBalancerSKU: "Standard",
+				HTTPProxyConfig: &HTTPProxyConfig{
+					HTTPSProxy: "http://proxy.example.com:3128/",
+					GetTrustedCA: func() ([]byte, error) {
+						return nil, errors.New("the trusted CA must only be read when the cluster is created")
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "managedcluster exists with an HTTP proxy and an update is needed",
+			existing: func() armcontainerservice.ManagedCluster {
+				mc := getExistingCluster()
+				mc.Properties.HTTPProxyConfig = &armcontainerservice.ManagedClusterHTTPProxyConfig{
+					HTTPSProxy: to.Ptr("http://proxy.example.com:3128/"),
+					TrustedCa:  to.Ptr("LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0t"),
+				}
+				return mc
+			}(),
+			spec: &ManagedClusterSpec{
+				Name:          "test-managedcluster",
+				ResourceGroup: "test-rg",
+				Location:      "test-location",
+				Tags: map[string]string{
+					"test-tag": "test-value",
+				},
+				Version:         "v1.22.99",
+				LoadBalancerSKU: "Standard",
+				HTTPProxyConfig: &HTTPProxyConfig{
+					HTTPSProxy: "http://proxy.example.com:3128/",
+					GetTrustedCA: func() ([]byte, error) {
+						return nil, errors.New("the trusted CA must only be read when the cluster is created")
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcontainerservice.ManagedCluster{}))
+				g.Expect(result.(armcontainerservice.ManagedCluster).Properties.HTTPProxyConfig).To(Equal(&armcontainerservice.ManagedClusterHTTPProxyConfig{
+					HTTPSProxy: to.Ptr("http://proxy.example.com:3128/"),
+					TrustedCa:  to.Ptr("LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0t"),
+				}))
 			},
 		},
 	}
@@ -147,61 +765,61 @@ func TestParameters(t *testing.T) {
 	}
 }
 
-func getExistingCluster() containerservice.ManagedCluster {
+func getExistingCluster() armcontainerservice.ManagedCluster {
 	mc := getSampleManagedCluster()
-	mc.ProvisioningState = to.StringPtr("Succeeded")
-	mc.ID = to.StringPtr("test-id")
+	mc.Properties.ProvisioningState = to.Ptr("Succeeded")
+	mc.ID = to.Ptr("test-id")
 	return mc
 }
 
-func getSampleManagedCluster() containerservice.ManagedCluster {
-	return containerservice.ManagedCluster{
-		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
-			KubernetesVersion: to.StringPtr("v1.22.0"),
-			DNSPrefix:         to.StringPtr("test-managedcluster"),
-			AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{
-				converters.AgentPoolToManagedClusterAgentPoolProfile(azure.AgentPoolSpec{
+func getSampleManagedCluster() armcontainerservice.ManagedCluster {
+	return armcontainerservice.ManagedCluster{
+		Properties: &armcontainerservice.ManagedClusterProperties{
+			KubernetesVersion: to.Ptr("v1.22.0"),
+			DNSPrefix:         to.Ptr("test-managedcluster"),
+			AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+				to.Ptr(converters.AgentPoolToManagedClusterAgentPoolProfile(azure.AgentPoolSpec{
 					Name:          "test-agentpool-0",
 					Mode:          string(infrav1exp.NodePoolModeSystem),
 					ResourceGroup: "test-rg",
 					Replicas:      int32(2),
-				}),
-				converters.AgentPoolToManagedClusterAgentPoolProfile(azure.AgentPoolSpec{
+				})),
+				to.Ptr(converters.AgentPoolToManagedClusterAgentPoolProfile(azure.AgentPoolSpec{
 					Name:              "test-agentpool-1",
 					Mode:              string(infrav1exp.NodePoolModeUser),
 					ResourceGroup:     "test-rg",
 					Replicas:          int32(4),
 					Cluster:           "test-managedcluster",
 					SKU:               "test_SKU",
-					Version:           to.StringPtr("v1.22.0"),
+					Version:           to.Ptr("v1.22.0"),
 					VnetSubnetID:      "fake/subnet/id",
-					MaxPods:           to.Int32Ptr(int32(32)),
+					MaxPods:           to.Ptr[int32](32),
 					AvailabilityZones: []string{"1", "2"},
-				}),
+				})),
 			},
-			LinuxProfile: &containerservice.LinuxProfile{
-				AdminUsername: to.StringPtr(azure.DefaultAKSUserName),
-				SSH: &containerservice.SSHConfiguration{
-					PublicKeys: &[]containerservice.SSHPublicKey{
+			LinuxProfile: &armcontainerservice.LinuxProfile{
+				AdminUsername: to.Ptr(azure.DefaultAKSUserName),
+				SSH: &armcontainerservice.SSHConfiguration{
+					PublicKeys: []*armcontainerservice.SSHPublicKey{
 						{
-							KeyData: to.StringPtr(""),
+							KeyData: to.Ptr(""),
 						},
 					},
 				},
 			},
-			ServicePrincipalProfile: &containerservice.ManagedClusterServicePrincipalProfile{ClientID: to.StringPtr("msi")},
-			NodeResourceGroup:       to.StringPtr("test-node-rg"),
-			EnableRBAC:              to.BoolPtr(true),
-			NetworkProfile: &containerservice.NetworkProfile{
-				LoadBalancerSku: containerservice.LoadBalancerSku("Standard"),
+			ServicePrincipalProfile: &armcontainerservice.ManagedClusterServicePrincipalProfile{ClientID: to.Ptr("msi")},
+			NodeResourceGroup:       to.Ptr("test-node-rg"),
+			EnableRBAC:              to.Ptr(true),
+			NetworkProfile: &armcontainerservice.NetworkProfile{
+				LoadBalancerSKU: to.Ptr(armcontainerservice.LoadBalancerSKU("Standard")),
 			},
 		},
-		Identity: &containerservice.ManagedClusterIdentity{
-			Type: containerservice.ResourceIdentityTypeSystemAssigned,
+		Identity: &armcontainerservice.ManagedClusterIdentity{
+			Type: to.Ptr(armcontainerservice.ResourceIdentityTypeSystemAssigned),
 		},
-		Location: to.StringPtr("test-location"),
+		Location: to.Ptr("test-location"),
 		Tags: map[string]*string{
-			"test-tag": to.StringPtr("test-value"),
+			"test-tag": to.Ptr("test-value"),
 		},
 	}
 }
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// Client wraps go-sdk.
+type Client interface {
+	List(ctx context.Context, resourceID, metricName, aggregation, interval string, start, end time.Time) ([]armmonitor.MetricValue, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	metrics *armmonitor.MetricsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new Azure Monitor metrics client from subscription ID.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armmonitor.NewMetricsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create metrics client")
+	}
+	return &AzureClient{c}, nil
+}
+
+// List returns the values of a platform metric of a resource between start and end, aggregated over intervals of the
+// given ISO 8601 duration, e.g. PT1H. Values are returned in chronological order.
+func (ac *AzureClient) List(ctx context.Context, resourceID, metricName, aggregation, interval string, start, end time.Time) ([]armmonitor.MetricValue, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "metrics.AzureClient.List")
+	defer done()
+
+	timespan := fmt.Sprintf("%s/%s", start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339))
+	resp, err := ac.metrics.List(ctx, resourceID, &armmonitor.MetricsClientListOptions{
+		Timespan:    to.Ptr(timespan),
+		Interval:    to.Ptr(interval),
+		Metricnames: to.Ptr(metricName),
+		Aggregation: to.Ptr(aggregation),
+		ResultType:  to.Ptr(armmonitor.ResultTypeData),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not list metric %s", metricName)
+	}
+
+	var values []armmonitor.MetricValue
+	for _, metric := range resp.Value {
+		if metric == nil {
+			continue
+		}
+		for _, series := range metric.Timeseries {
+			if series == nil {
+				continue
+			}
+			for _, value := range series.Data {
+				if value != nil {
+					values = append(values, *value)
+				}
+			}
+		}
+	}
+
+	return values, nil
+}
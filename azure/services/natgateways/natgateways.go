@@ -19,10 +19,12 @@ package natgateways
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aso"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -45,12 +47,15 @@ type Service struct {
 }
 
 // New creates a new service.
-func New(scope NatGatewayScope) *Service {
-	client := newClient(scope)
+func New(scope NatGatewayScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:      scope,
-		Reconciler: async.New(scope, client, client),
-	}
+		Reconciler: aso.NewReconciler(scope, client, client),
+	}, nil
 }
 
 // Name returns the service name.
@@ -58,6 +63,15 @@ func (s *Service) Name() string {
 	return serviceName
 }
 
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the NAT gateways.
+func (s *Service) RequiredActions() []string {
+	if !s.Scope.IsVnetManaged() || len(s.Scope.NatGatewaySpecs()) == 0 {
+		return nil
+	}
+	return []string{"Microsoft.Network/natGateways/read", "Microsoft.Network/natGateways/write"}
+}
+
 // Reconcile gets/creates/updates a NAT gateway.
 // Only when the NAT gateway 'Name' property is defined we create the NAT gateway: it's opt-in.
 func (s *Service) Reconcile(ctx context.Context) error {
@@ -91,10 +105,10 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			}
 		}
 		if err == nil {
-			natGateway, ok := result.(network.NatGateway)
+			natGateway, ok := result.(armnetwork.NatGateway)
 			if !ok {
 				// Return out of loop since this would be an unexpected fatal error
-				resultingErr = errors.Errorf("created resource %T is not a network.NatGateway", result)
+				resultingErr = errors.Errorf("created resource %T is not an armnetwork.NatGateway", result)
 				break
 			}
 
@@ -142,6 +156,24 @@ func (s *Service) Delete(ctx context.Context) error {
 	return resultingErr
 }
 
+// ExportResources adds the NAT gateways, if the virtual network is managed, to an ARM template.
+func (s *Service) ExportResources(ctx context.Context, template *armtemplate.Template) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "natgateways.Service.ExportResources")
+	defer done()
+
+	if !s.Scope.IsVnetManaged() {
+		return nil
+	}
+
+	for _, spec := range s.Scope.NatGatewaySpecs() {
+		if err := template.AddSpec(spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // IsManaged returns true if the NAT gateways' lifecycles are managed.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	_, _, done := tele.StartSpanWithLogger(ctx, "natgateways.Service.IsManaged")
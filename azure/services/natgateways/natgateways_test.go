@@ -18,12 +18,11 @@ package natgateways
 
 import (
 	"context"
-	"net/http"
+	"errors"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -48,8 +47,8 @@ var (
 		ClusterName:    "my-cluster",
 		NatGatewayIP:   infrav1.PublicIPSpec{Name: "pip-node-subnet"},
 	}
-	natGateway1 = network.NatGateway{
-		ID: to.StringPtr("/subscriptions/my-sub/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/my-node-natgateway-1"),
+	natGateway1 = armnetwork.NatGateway{
+		ID: to.Ptr("/subscriptions/my-sub/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/my-node-natgateway-1"),
 	}
 	customVNetTags = infrav1.Tags{
 		"Name": "my-vnet",
@@ -61,7 +60,7 @@ var (
 		"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": "owned",
 		"sigs.k8s.io_cluster-api-provider-azure_role":                 "common",
 	}
-	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+	internalError = errors.New("Internal Server Error")
 )
 
 func TestReconcileNatGateways(t *testing.T) {
@@ -103,7 +102,7 @@ func TestReconcileNatGateways(t *testing.T) {
 		{
 			name:          "fail to create a NAT gateway",
 			tags:          ownedVNetTags,
-			expectedError: "#: Internal Server Error: StatusCode=500",
+			expectedError: "Internal Server Error",
 			expect: func(s *mock_natgateways.MockNatGatewayScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.IsVnetManaged().Return(true)
 				s.NatGatewaySpecs().Return([]azure.ResourceSpecGetter{&natGatewaySpec1})
@@ -114,12 +113,12 @@ func TestReconcileNatGateways(t *testing.T) {
 		{
 			name:          "result is not a NAT gateway",
 			tags:          ownedVNetTags,
-			expectedError: "created resource string is not a network.NatGateway",
+			expectedError: "created resource string is not an armnetwork.NatGateway",
 			expect: func(s *mock_natgateways.MockNatGatewayScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.IsVnetManaged().Return(true)
 				s.NatGatewaySpecs().Return([]azure.ResourceSpecGetter{&natGatewaySpec1})
 				r.CreateResource(gomockinternal.AContext(), &natGatewaySpec1, serviceName).Return("not a nat gateway", nil)
-				s.UpdatePutStatus(infrav1.NATGatewaysReadyCondition, serviceName, gomockinternal.ErrStrEq("created resource string is not a network.NatGateway"))
+				s.UpdatePutStatus(infrav1.NATGatewaysReadyCondition, serviceName, gomockinternal.ErrStrEq("created resource string is not an armnetwork.NatGateway"))
 			},
 		},
 	}
@@ -190,7 +189,7 @@ func TestDeleteNatGateway(t *testing.T) {
 		{
 			name:          "NAT gateway deletion fails",
 			tags:          ownedVNetTags,
-			expectedError: "#: Internal Server Error: StatusCode=500",
+			expectedError: "Internal Server Error",
 			expect: func(s *mock_natgateways.MockNatGatewayScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.IsVnetManaged().Return(true)
 				s.NatGatewaySpecs().Return([]azure.ResourceSpecGetter{&natGatewaySpec1})
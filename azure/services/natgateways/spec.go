@@ -17,9 +17,8 @@ limitations under the License.
 package natgateways
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	autorest "github.com/Azure/go-autorest/autorest/azure"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -55,9 +54,9 @@ func (s *NatGatewaySpec) OwnerResourceName() string {
 // Parameters returns the parameters for the NAT gateway.
 func (s *NatGatewaySpec) Parameters(existing interface{}) (params interface{}, err error) {
 	if existing != nil {
-		existingNatGateway, ok := existing.(network.NatGateway)
+		existingNatGateway, ok := existing.(armnetwork.NatGateway)
 		if !ok {
-			return nil, errors.Errorf("%T is not a network.NatGateway", existing)
+			return nil, errors.Errorf("%T is not an armnetwork.NatGateway", existing)
 		}
 
 		if hasPublicIP(existingNatGateway, s.NatGatewayIP.Name) {
@@ -66,21 +65,21 @@ func (s *NatGatewaySpec) Parameters(existing interface{}) (params interface{}, e
 		}
 	}
 
-	natGatewayToCreate := network.NatGateway{
-		Name:     to.StringPtr(s.Name),
-		Location: to.StringPtr(s.Location),
-		Sku:      &network.NatGatewaySku{Name: network.NatGatewaySkuNameStandard},
-		NatGatewayPropertiesFormat: &network.NatGatewayPropertiesFormat{
-			PublicIPAddresses: &[]network.SubResource{
+	natGatewayToCreate := armnetwork.NatGateway{
+		Name:     to.Ptr(s.Name),
+		Location: to.Ptr(s.Location),
+		SKU:      &armnetwork.NatGatewaySKU{Name: to.Ptr(armnetwork.NatGatewaySKUNameStandard)},
+		Properties: &armnetwork.NatGatewayPropertiesFormat{
+			PublicIPAddresses: []*armnetwork.SubResource{
 				{
-					ID: to.StringPtr(azure.PublicIPID(s.SubscriptionID, s.ResourceGroupName(), s.NatGatewayIP.Name)),
+					ID: to.Ptr(azure.PublicIPID(s.SubscriptionID, s.ResourceGroupName(), s.NatGatewayIP.Name)),
 				},
 			},
 		},
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: s.ClusterName,
 			Lifecycle:   infrav1.ResourceLifecycleOwned,
-			Name:        to.StringPtr(s.Name),
+			Name:        to.Ptr(s.Name),
 			Additional:  s.AdditionalTags,
 		})),
 	}
@@ -88,18 +87,18 @@ func (s *NatGatewaySpec) Parameters(existing interface{}) (params interface{}, e
 	return natGatewayToCreate, nil
 }
 
-func hasPublicIP(natGateway network.NatGateway, publicIPName string) bool {
+func hasPublicIP(natGateway armnetwork.NatGateway, publicIPName string) bool {
 	// We must have a non-nil, non-"empty" PublicIPAddresses
-	if !(natGateway.PublicIPAddresses != nil && len(*natGateway.PublicIPAddresses) > 0) {
+	if natGateway.Properties == nil || len(natGateway.Properties.PublicIPAddresses) == 0 {
 		return false
 	}
 
-	for _, publicIP := range *natGateway.PublicIPAddresses {
-		resource, err := autorest.ParseResourceID(*publicIP.ID)
+	for _, publicIP := range natGateway.Properties.PublicIPAddresses {
+		resource, err := azure.ParseResourceID(*publicIP.ID)
 		if err != nil {
 			continue
 		}
-		if resource.ResourceName == publicIPName {
+		if resource.Name == publicIPName {
 			return true
 		}
 	}
@@ -21,6 +21,7 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
@@ -44,13 +45,16 @@ type Service struct {
 }
 
 // New creates a new service.
-func New(scope NICScope, skuCache *resourceskus.Cache) *Service {
-	Client := NewClient(scope)
+func New(scope NICScope, skuCache *resourceskus.Cache) (*Service, error) {
+	Client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:            scope,
-		Reconciler:       async.New(scope, Client, Client),
+		Reconciler:       async.NewPollerService(scope, Client, Client),
 		resourceSKUCache: skuCache,
-	}
+	}, nil
 }
 
 // Name returns the service name.
@@ -116,6 +120,20 @@ func (s *Service) Delete(ctx context.Context) error {
 	return result
 }
 
+// ExportResources adds the network interfaces to an ARM template.
+func (s *Service) ExportResources(ctx context.Context, template *armtemplate.Template) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "networkinterfaces.Service.ExportResources")
+	defer done()
+
+	for _, spec := range s.Scope.NICSpecs() {
+		if err := template.AddSpec(spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // IsManaged returns always returns true as CAPZ does not support BYO network interfaces.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	return true, nil
@@ -18,11 +18,10 @@ package networkinterfaces
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net/http"
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest"
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
@@ -70,7 +69,7 @@ var (
 		SKU:                   &fakeSku,
 		IPConfigs:             []IPConfig{{}, {}},
 	}
-	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+	internalError = errors.New("Internal Server Error")
 )
 
 func TestReconcileNetworkInterface(t *testing.T) {
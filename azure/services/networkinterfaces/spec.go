@@ -19,10 +19,12 @@ package networkinterfaces
 import (
 	"strconv"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 )
 
@@ -31,6 +33,7 @@ type NICSpec struct {
 	Name                      string
 	ResourceGroup             string
 	Location                  string
+	ExtendedLocation          *infrav1.ExtendedLocationSpec
 	SubscriptionID            string
 	MachineName               string
 	SubnetName                string
@@ -42,6 +45,7 @@ type NICSpec struct {
 	PublicLBNATRuleName       string
 	InternalLBName            string
 	InternalLBAddressPoolName string
+	ExternalLBAddressPoolID   string
 	PublicIPName              string
 	AcceleratedNetworking     *bool
 	IPv6Enabled               bool
@@ -49,6 +53,10 @@ type NICSpec struct {
 	SKU                       *resourceskus.SKU
 	IPConfigs                 []IPConfig
 	Primary                   *bool
+	InternalDNSNameLabel      string
+	DNSServers                []string
+	ClusterName               string
+	AdditionalTags            infrav1.Tags
 }
 
 // IPConfig defines the specification for an IP address configuration.
@@ -76,53 +84,59 @@ func (s *NICSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the network interface.
 func (s *NICSpec) Parameters(existing interface{}) (parameters interface{}, err error) {
 	if existing != nil {
-		if _, ok := existing.(network.Interface); !ok {
-			return nil, errors.Errorf("%T is not a network.Interface", existing)
+		if _, ok := existing.(armnetwork.Interface); !ok {
+			return nil, errors.Errorf("%T is not an armnetwork.Interface", existing)
 		}
 		// network interface already exists
 		return nil, nil
 	}
 
-	nicConfig := &network.InterfaceIPConfigurationPropertiesFormat{}
+	nicConfig := &armnetwork.InterfaceIPConfigurationPropertiesFormat{}
 
-	subnet := &network.Subnet{
-		ID: to.StringPtr(azure.SubnetID(s.SubscriptionID, s.VNetResourceGroup, s.VNetName, s.SubnetName)),
+	subnet := &armnetwork.Subnet{
+		ID: to.Ptr(azure.SubnetID(s.SubscriptionID, s.VNetResourceGroup, s.VNetName, s.SubnetName)),
 	}
 	nicConfig.Subnet = subnet
 
-	nicConfig.PrivateIPAllocationMethod = network.IPAllocationMethodDynamic
+	nicConfig.PrivateIPAllocationMethod = to.Ptr(armnetwork.IPAllocationMethodDynamic)
 	if s.StaticIPAddress != "" {
-		nicConfig.PrivateIPAllocationMethod = network.IPAllocationMethodStatic
-		nicConfig.PrivateIPAddress = to.StringPtr(s.StaticIPAddress)
+		nicConfig.PrivateIPAllocationMethod = to.Ptr(armnetwork.IPAllocationMethodStatic)
+		nicConfig.PrivateIPAddress = to.Ptr(s.StaticIPAddress)
 	}
 
-	backendAddressPools := []network.BackendAddressPool{}
+	backendAddressPools := []*armnetwork.BackendAddressPool{}
 	if s.PublicLBName != "" {
 		if s.PublicLBAddressPoolName != "" {
 			backendAddressPools = append(backendAddressPools,
-				network.BackendAddressPool{
-					ID: to.StringPtr(azure.AddressPoolID(s.SubscriptionID, s.ResourceGroup, s.PublicLBName, s.PublicLBAddressPoolName)),
+				&armnetwork.BackendAddressPool{
+					ID: to.Ptr(azure.AddressPoolID(s.SubscriptionID, s.ResourceGroup, s.PublicLBName, s.PublicLBAddressPoolName)),
 				})
 		}
 		if s.PublicLBNATRuleName != "" {
-			nicConfig.LoadBalancerInboundNatRules = &[]network.InboundNatRule{
+			nicConfig.LoadBalancerInboundNatRules = []*armnetwork.InboundNatRule{
 				{
-					ID: to.StringPtr(azure.NATRuleID(s.SubscriptionID, s.ResourceGroup, s.PublicLBName, s.PublicLBNATRuleName)),
+					ID: to.Ptr(azure.NATRuleID(s.SubscriptionID, s.ResourceGroup, s.PublicLBName, s.PublicLBNATRuleName)),
 				},
 			}
 		}
 	}
 	if s.InternalLBName != "" && s.InternalLBAddressPoolName != "" {
 		backendAddressPools = append(backendAddressPools,
-			network.BackendAddressPool{
-				ID: to.StringPtr(azure.AddressPoolID(s.SubscriptionID, s.ResourceGroup, s.InternalLBName, s.InternalLBAddressPoolName)),
+			&armnetwork.BackendAddressPool{
+				ID: to.Ptr(azure.AddressPoolID(s.SubscriptionID, s.ResourceGroup, s.InternalLBName, s.InternalLBAddressPoolName)),
 			})
 	}
-	nicConfig.LoadBalancerBackendAddressPools = &backendAddressPools
+	if s.ExternalLBAddressPoolID != "" {
+		backendAddressPools = append(backendAddressPools,
+			&armnetwork.BackendAddressPool{
+				ID: to.Ptr(s.ExternalLBAddressPoolID),
+			})
+	}
+	nicConfig.LoadBalancerBackendAddressPools = backendAddressPools
 
 	if s.PublicIPName != "" {
-		nicConfig.PublicIPAddress = &network.PublicIPAddress{
-			ID: to.StringPtr(azure.PublicIPID(s.SubscriptionID, s.ResourceGroup, s.PublicIPName)),
+		nicConfig.PublicIPAddress = &armnetwork.PublicIPAddress{
+			ID: to.Ptr(azure.PublicIPID(s.SubscriptionID, s.ResourceGroup, s.PublicIPName)),
 		}
 	}
 
@@ -136,61 +150,80 @@ func (s *NICSpec) Parameters(existing interface{}) (parameters interface{}, err
 		s.AcceleratedNetworking = &accelNet
 	}
 
-	ipConfigurations := []network.InterfaceIPConfiguration{
+	ipConfigurations := []*armnetwork.InterfaceIPConfiguration{
 		{
-			Name:                                     to.StringPtr("pipConfig"),
-			InterfaceIPConfigurationPropertiesFormat: nicConfig,
+			Name:       to.Ptr("pipConfig"),
+			Properties: nicConfig,
 		},
 	}
 
 	for i, c := range s.IPConfigs {
-		newIPConfigPropertiesFormat := &network.InterfaceIPConfigurationPropertiesFormat{}
+		newIPConfigPropertiesFormat := &armnetwork.InterfaceIPConfigurationPropertiesFormat{}
 		newIPConfigPropertiesFormat.Subnet = subnet
-		config := network.InterfaceIPConfiguration{
-			Name:                                     to.StringPtr(s.Name + "-" + strconv.Itoa(i)),
-			InterfaceIPConfigurationPropertiesFormat: newIPConfigPropertiesFormat,
+		config := &armnetwork.InterfaceIPConfiguration{
+			Name:       to.Ptr(s.Name + "-" + strconv.Itoa(i)),
+			Properties: newIPConfigPropertiesFormat,
 		}
 		if c.PrivateIP == "" {
-			config.InterfaceIPConfigurationPropertiesFormat.PrivateIPAllocationMethod = network.IPAllocationMethodDynamic
+			config.Properties.PrivateIPAllocationMethod = to.Ptr(armnetwork.IPAllocationMethodDynamic)
 		} else {
-			config.InterfaceIPConfigurationPropertiesFormat.PrivateIPAllocationMethod = network.IPAllocationMethodStatic
-			config.InterfaceIPConfigurationPropertiesFormat.PrivateIPAddress = &c.PrivateIP
+			config.Properties.PrivateIPAllocationMethod = to.Ptr(armnetwork.IPAllocationMethodStatic)
+			config.Properties.PrivateIPAddress = to.Ptr(c.PrivateIP)
 		}
 
 		if c.PublicIP && c.PublicIPAddress == "" {
-			config.InterfaceIPConfigurationPropertiesFormat.PublicIPAddress = &network.PublicIPAddress{
-				PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
-					PublicIPAllocationMethod: network.IPAllocationMethodStatic,
-					IPAddress:                &c.PublicIPAddress,
+			config.Properties.PublicIPAddress = &armnetwork.PublicIPAddress{
+				Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+					PublicIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
+					IPAddress:                to.Ptr(c.PublicIPAddress),
 				}}
 		} else if c.PublicIP {
-			config.InterfaceIPConfigurationPropertiesFormat.PublicIPAddress = &network.PublicIPAddress{
-				PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
-					PublicIPAllocationMethod: network.IPAllocationMethodDynamic}}
+			config.Properties.PublicIPAddress = &armnetwork.PublicIPAddress{
+				Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+					PublicIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodDynamic)}}
 		}
-		config.InterfaceIPConfigurationPropertiesFormat.Primary = to.BoolPtr(false)
+		config.Properties.Primary = to.Ptr(false)
 		ipConfigurations = append(ipConfigurations, config)
 	}
 	if s.IPv6Enabled {
-		ipv6Config := network.InterfaceIPConfiguration{
-			Name: to.StringPtr("ipConfigv6"),
-			InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-				PrivateIPAddressVersion: "IPv6",
-				Primary:                 to.BoolPtr(false),
-				Subnet:                  &network.Subnet{ID: subnet.ID},
+		ipv6Config := &armnetwork.InterfaceIPConfiguration{
+			Name: to.Ptr("ipConfigv6"),
+			Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+				PrivateIPAddressVersion: to.Ptr(armnetwork.IPVersionIPv6),
+				Primary:                 to.Ptr(false),
+				Subnet:                  &armnetwork.Subnet{ID: subnet.ID},
 			},
 		}
 
 		ipConfigurations = append(ipConfigurations, ipv6Config)
 	}
-	ipConfigurations[0].InterfaceIPConfigurationPropertiesFormat.Primary = to.BoolPtr(true)
+	ipConfigurations[0].Properties.Primary = to.Ptr(true)
+
+	var dnsSettings *armnetwork.InterfaceDNSSettings
+	if s.InternalDNSNameLabel != "" || len(s.DNSServers) > 0 {
+		dnsSettings = &armnetwork.InterfaceDNSSettings{}
+		if s.InternalDNSNameLabel != "" {
+			dnsSettings.InternalDNSNameLabel = to.Ptr(s.InternalDNSNameLabel)
+		}
+		if len(s.DNSServers) > 0 {
+			dnsSettings.DNSServers = to.SliceOfPtrs(s.DNSServers...)
+		}
+	}
 
-	return network.Interface{
-		Location: to.StringPtr(s.Location),
-		InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+	return armnetwork.Interface{
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.Ptr(s.Name),
+			Additional:  s.AdditionalTags,
+		})),
+		Location:         to.Ptr(s.Location),
+		ExtendedLocation: converters.ExtendedLocationToNetworkSDK(s.ExtendedLocation),
+		Properties: &armnetwork.InterfacePropertiesFormat{
 			EnableAcceleratedNetworking: s.AcceleratedNetworking,
-			IPConfigurations:            &ipConfigurations,
-			EnableIPForwarding:          to.BoolPtr(s.EnableIPForwarding),
+			IPConfigurations:            ipConfigurations,
+			EnableIPForwarding:          to.Ptr(s.EnableIPForwarding),
+			DNSSettings:                 dnsSettings,
 		},
 	}, nil
 }
@@ -19,16 +19,18 @@ package networkinterfaces
 import (
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 )
 
 var (
 	fakeMissingSKUNICSpec = NICSpec{
 		Name:                  "my-net-interface",
+		ClusterName:           "my-cluster",
 		ResourceGroup:         "my-rg",
 		Location:              "fake-location",
 		SubscriptionID:        "123",
@@ -40,26 +42,25 @@ var (
 		AcceleratedNetworking: nil,
 	}
 	fakeSku = resourceskus.SKU{
-		Name: to.StringPtr("Standard_D2v2"),
-		Kind: to.StringPtr(string(resourceskus.VirtualMachines)),
-		Locations: &[]string{
-			"fake-location",
-		},
-		LocationInfo: &[]compute.ResourceSkuLocationInfo{
+		Name:      to.Ptr("Standard_D2v2"),
+		Kind:      to.Ptr(string(resourceskus.VirtualMachines)),
+		Locations: []*string{to.Ptr("fake-location")},
+		LocationInfo: []*armcompute.ResourceSKULocationInfo{
 			{
-				Location: to.StringPtr("fake-location"),
-				Zones:    &[]string{"1"},
+				Location: to.Ptr("fake-location"),
+				Zones:    []*string{to.Ptr("1")},
 			},
 		},
-		Capabilities: &[]compute.ResourceSkuCapabilities{
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
 			{
-				Name:  to.StringPtr(resourceskus.AcceleratedNetworking),
-				Value: to.StringPtr(string(resourceskus.CapabilitySupported)),
+				Name:  to.Ptr(resourceskus.AcceleratedNetworking),
+				Value: to.Ptr(string(resourceskus.CapabilitySupported)),
 			},
 		},
 	}
 	fakeStaticPrivateIPNICSpec = NICSpec{
 		Name:                    "my-net-interface",
+		ClusterName:             "my-cluster",
 		ResourceGroup:           "my-rg",
 		Location:                "fake-location",
 		SubscriptionID:          "123",
@@ -76,6 +77,7 @@ var (
 
 	fakeDynamicPrivateIPNICSpec = NICSpec{
 		Name:                    "my-net-interface",
+		ClusterName:             "my-cluster",
 		ResourceGroup:           "my-rg",
 		Location:                "fake-location",
 		SubscriptionID:          "123",
@@ -91,6 +93,7 @@ var (
 
 	fakeControlPlaneNICSpec = NICSpec{
 		Name:                      "my-net-interface",
+		ClusterName:               "my-cluster",
 		ResourceGroup:             "my-rg",
 		Location:                  "fake-location",
 		SubscriptionID:            "123",
@@ -109,6 +112,7 @@ var (
 
 	fakeAcceleratedNetworkingNICSpec = NICSpec{
 		Name:                  "my-net-interface",
+		ClusterName:           "my-cluster",
 		ResourceGroup:         "my-rg",
 		Location:              "fake-location",
 		SubscriptionID:        "123",
@@ -123,6 +127,7 @@ var (
 
 	fakeNonAcceleratedNetworkingNICSpec = NICSpec{
 		Name:                  "my-net-interface",
+		ClusterName:           "my-cluster",
 		ResourceGroup:         "my-rg",
 		Location:              "fake-location",
 		SubscriptionID:        "123",
@@ -131,11 +136,12 @@ var (
 		VNetName:              "my-vnet",
 		VNetResourceGroup:     "my-rg",
 		PublicLBName:          "my-public-lb",
-		AcceleratedNetworking: to.BoolPtr(false),
+		AcceleratedNetworking: to.Ptr(false),
 	}
 
 	fakeIpv6NICSpec = NICSpec{
 		Name:                  "my-net-interface",
+		ClusterName:           "my-cluster",
 		ResourceGroup:         "my-rg",
 		Location:              "fake-location",
 		SubscriptionID:        "123",
@@ -151,6 +157,7 @@ var (
 	}
 	fakeDefaultIPconfigNICSpec = NICSpec{
 		Name:                  "my-net-interface",
+		ClusterName:           "my-cluster",
 		ResourceGroup:         "my-rg",
 		Location:              "fake-location",
 		SubscriptionID:        "123",
@@ -167,6 +174,7 @@ var (
 	}
 	fakeOneIPconfigNICSpec = NICSpec{
 		Name:                  "my-net-interface",
+		ClusterName:           "my-cluster",
 		ResourceGroup:         "my-rg",
 		Location:              "fake-location",
 		SubscriptionID:        "123",
@@ -183,6 +191,7 @@ var (
 	}
 	fakeTwoIPconfigNICSpec = NICSpec{
 		Name:                  "my-net-interface",
+		ClusterName:           "my-cluster",
 		ResourceGroup:         "my-rg",
 		Location:              "fake-location",
 		SubscriptionID:        "123",
@@ -197,9 +206,46 @@ var (
 		EnableIPForwarding:    true,
 		IPConfigs:             []IPConfig{{}, {}},
 	}
+	fakeDNSSettingsNICSpec = NICSpec{
+		Name:                  "my-net-interface",
+		ClusterName:           "my-cluster",
+		ResourceGroup:         "my-rg",
+		Location:              "fake-location",
+		SubscriptionID:        "123",
+		MachineName:           "azure-test1",
+		SubnetName:            "my-subnet",
+		VNetName:              "my-vnet",
+		VNetResourceGroup:     "my-rg",
+		AcceleratedNetworking: nil,
+		SKU:                   &fakeSku,
+		InternalDNSNameLabel:  "win-node-1",
+		DNSServers:            []string{"10.0.0.4", "10.0.0.5"},
+	}
+	fakeTaggedNICSpec = NICSpec{
+		Name:                  "my-net-interface",
+		ClusterName:           "my-cluster",
+		ResourceGroup:         "my-rg",
+		Location:              "fake-location",
+		SubscriptionID:        "123",
+		MachineName:           "azure-test1",
+		SubnetName:            "my-subnet",
+		VNetName:              "my-vnet",
+		VNetResourceGroup:     "my-rg",
+		AcceleratedNetworking: nil,
+		SKU:                   &fakeSku,
+		AdditionalTags:        infrav1.Tags{"cost-center": "networking"},
+	}
 )
 
 func TestParameters(t *testing.T) {
+	externalLBNICSpec := fakeControlPlaneNICSpec
+	externalLBNICSpec.PublicLBName = ""
+	externalLBNICSpec.PublicLBAddressPoolName = ""
+	externalLBNICSpec.PublicLBNATRuleName = ""
+	externalLBNICSpec.InternalLBName = ""
+	externalLBNICSpec.InternalLBAddressPoolName = ""
+	externalLBNICSpec.ExternalLBAddressPoolID = "/subscriptions/123/resourceGroups/network-rg/providers/Microsoft.Network/loadBalancers/shared-lb/backendAddressPools/my-cluster-pool"
+
 	testcases := []struct {
 		name          string
 		spec          *NICSpec
@@ -221,22 +267,26 @@ func TestParameters(t *testing.T) {
 			spec:     &fakeStaticPrivateIPNICSpec,
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.Interface{}))
-				g.Expect(result.(network.Interface)).To(Equal(network.Interface{
-					Location: to.StringPtr("fake-location"),
-					InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface)).To(Equal(armnetwork.Interface{
+					Tags: map[string]*string{
+						"Name": to.Ptr("my-net-interface"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+					},
+					Location: to.Ptr("fake-location"),
+					Properties: &armnetwork.InterfacePropertiesFormat{
 						Primary:                     nil,
-						EnableAcceleratedNetworking: to.BoolPtr(true),
-						EnableIPForwarding:          to.BoolPtr(false),
-						IPConfigurations: &[]network.InterfaceIPConfiguration{
+						EnableAcceleratedNetworking: to.Ptr(true),
+						EnableIPForwarding:          to.Ptr(false),
+						IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
 							{
-								Name: to.StringPtr("pipConfig"),
-								InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-									Primary:                         to.BoolPtr(true),
-									LoadBalancerBackendAddressPools: &[]network.BackendAddressPool{{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-public-lb/backendAddressPools/cluster-name-outboundBackendPool")}},
-									PrivateIPAllocationMethod:       network.IPAllocationMethodStatic,
-									PrivateIPAddress:                to.StringPtr("fake.static.ip"),
-									Subnet:                          &network.Subnet{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
+								Name: to.Ptr("pipConfig"),
+								Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+									Primary:                         to.Ptr(true),
+									LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-public-lb/backendAddressPools/cluster-name-outboundBackendPool")}},
+									PrivateIPAllocationMethod:       to.Ptr(armnetwork.IPAllocationMethodStatic),
+									PrivateIPAddress:                to.Ptr("fake.static.ip"),
+									Subnet:                          &armnetwork.Subnet{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
 								},
 							},
 						},
@@ -250,21 +300,25 @@ func TestParameters(t *testing.T) {
 			spec:     &fakeDynamicPrivateIPNICSpec,
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.Interface{}))
-				g.Expect(result.(network.Interface)).To(Equal(network.Interface{
-					Location: to.StringPtr("fake-location"),
-					InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
-						EnableAcceleratedNetworking: to.BoolPtr(true),
-						EnableIPForwarding:          to.BoolPtr(false),
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface)).To(Equal(armnetwork.Interface{
+					Tags: map[string]*string{
+						"Name": to.Ptr("my-net-interface"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+					},
+					Location: to.Ptr("fake-location"),
+					Properties: &armnetwork.InterfacePropertiesFormat{
+						EnableAcceleratedNetworking: to.Ptr(true),
+						EnableIPForwarding:          to.Ptr(false),
 						Primary:                     nil,
-						IPConfigurations: &[]network.InterfaceIPConfiguration{
+						IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
 							{
-								Name: to.StringPtr("pipConfig"),
-								InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-									Primary:                         to.BoolPtr(true),
-									LoadBalancerBackendAddressPools: &[]network.BackendAddressPool{{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-public-lb/backendAddressPools/cluster-name-outboundBackendPool")}},
-									PrivateIPAllocationMethod:       network.IPAllocationMethodDynamic,
-									Subnet:                          &network.Subnet{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
+								Name: to.Ptr("pipConfig"),
+								Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+									Primary:                         to.Ptr(true),
+									LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-public-lb/backendAddressPools/cluster-name-outboundBackendPool")}},
+									PrivateIPAllocationMethod:       to.Ptr(armnetwork.IPAllocationMethodDynamic),
+									Subnet:                          &armnetwork.Subnet{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
 								},
 							},
 						},
@@ -278,24 +332,28 @@ func TestParameters(t *testing.T) {
 			spec:     &fakeControlPlaneNICSpec,
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.Interface{}))
-				g.Expect(result.(network.Interface)).To(Equal(network.Interface{
-					Location: to.StringPtr("fake-location"),
-					InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
-						EnableAcceleratedNetworking: to.BoolPtr(true),
-						EnableIPForwarding:          to.BoolPtr(false),
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface)).To(Equal(armnetwork.Interface{
+					Tags: map[string]*string{
+						"Name": to.Ptr("my-net-interface"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+					},
+					Location: to.Ptr("fake-location"),
+					Properties: &armnetwork.InterfacePropertiesFormat{
+						EnableAcceleratedNetworking: to.Ptr(true),
+						EnableIPForwarding:          to.Ptr(false),
 						Primary:                     nil,
-						IPConfigurations: &[]network.InterfaceIPConfiguration{
+						IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
 							{
-								Name: to.StringPtr("pipConfig"),
-								InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-									Primary:                     to.BoolPtr(true),
-									Subnet:                      &network.Subnet{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
-									PrivateIPAllocationMethod:   network.IPAllocationMethodDynamic,
-									LoadBalancerInboundNatRules: &[]network.InboundNatRule{{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-public-lb/inboundNatRules/azure-test1")}},
-									LoadBalancerBackendAddressPools: &[]network.BackendAddressPool{
-										{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-public-lb/backendAddressPools/my-public-lb-backendPool")},
-										{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-internal-lb/backendAddressPools/my-internal-lb-backendPool")}},
+								Name: to.Ptr("pipConfig"),
+								Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+									Primary:                     to.Ptr(true),
+									Subnet:                      &armnetwork.Subnet{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
+									PrivateIPAllocationMethod:   to.Ptr(armnetwork.IPAllocationMethodDynamic),
+									LoadBalancerInboundNatRules: []*armnetwork.InboundNatRule{{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-public-lb/inboundNatRules/azure-test1")}},
+									LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{
+										{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-public-lb/backendAddressPools/my-public-lb-backendPool")},
+										{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/my-internal-lb/backendAddressPools/my-internal-lb-backendPool")}},
 								},
 							},
 						},
@@ -304,26 +362,44 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "get parameters for control plane network interface of an external load balancer",
+			spec:     &externalLBNICSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				ipConfig := result.(armnetwork.Interface).Properties.IPConfigurations[0]
+				g.Expect(ipConfig.Properties.LoadBalancerInboundNatRules).To(BeNil())
+				g.Expect(ipConfig.Properties.LoadBalancerBackendAddressPools).To(Equal([]*armnetwork.BackendAddressPool{
+					{ID: to.Ptr("/subscriptions/123/resourceGroups/network-rg/providers/Microsoft.Network/loadBalancers/shared-lb/backendAddressPools/my-cluster-pool")},
+				}))
+			},
+			expectedError: "",
+		},
 		{
 			name:     "get parameters for network interface with accelerated networking",
 			spec:     &fakeAcceleratedNetworkingNICSpec,
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.Interface{}))
-				g.Expect(result.(network.Interface)).To(Equal(network.Interface{
-					Location: to.StringPtr("fake-location"),
-					InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface)).To(Equal(armnetwork.Interface{
+					Tags: map[string]*string{
+						"Name": to.Ptr("my-net-interface"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+					},
+					Location: to.Ptr("fake-location"),
+					Properties: &armnetwork.InterfacePropertiesFormat{
 						Primary:                     nil,
-						EnableAcceleratedNetworking: to.BoolPtr(true),
-						EnableIPForwarding:          to.BoolPtr(false),
-						IPConfigurations: &[]network.InterfaceIPConfiguration{
+						EnableAcceleratedNetworking: to.Ptr(true),
+						EnableIPForwarding:          to.Ptr(false),
+						IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
 							{
-								Name: to.StringPtr("pipConfig"),
-								InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-									Primary:                         to.BoolPtr(true),
-									Subnet:                          &network.Subnet{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
-									PrivateIPAllocationMethod:       network.IPAllocationMethodDynamic,
-									LoadBalancerBackendAddressPools: &[]network.BackendAddressPool{},
+								Name: to.Ptr("pipConfig"),
+								Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+									Primary:                         to.Ptr(true),
+									Subnet:                          &armnetwork.Subnet{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
+									PrivateIPAllocationMethod:       to.Ptr(armnetwork.IPAllocationMethodDynamic),
+									LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{},
 								},
 							},
 						},
@@ -337,21 +413,25 @@ func TestParameters(t *testing.T) {
 			spec:     &fakeNonAcceleratedNetworkingNICSpec,
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.Interface{}))
-				g.Expect(result.(network.Interface)).To(Equal(network.Interface{
-					Location: to.StringPtr("fake-location"),
-					InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface)).To(Equal(armnetwork.Interface{
+					Tags: map[string]*string{
+						"Name": to.Ptr("my-net-interface"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+					},
+					Location: to.Ptr("fake-location"),
+					Properties: &armnetwork.InterfacePropertiesFormat{
 						Primary:                     nil,
-						EnableAcceleratedNetworking: to.BoolPtr(false),
-						EnableIPForwarding:          to.BoolPtr(false),
-						IPConfigurations: &[]network.InterfaceIPConfiguration{
+						EnableAcceleratedNetworking: to.Ptr(false),
+						EnableIPForwarding:          to.Ptr(false),
+						IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
 							{
-								Name: to.StringPtr("pipConfig"),
-								InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-									Primary:                         to.BoolPtr(true),
-									Subnet:                          &network.Subnet{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
-									PrivateIPAllocationMethod:       network.IPAllocationMethodDynamic,
-									LoadBalancerBackendAddressPools: &[]network.BackendAddressPool{},
+								Name: to.Ptr("pipConfig"),
+								Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+									Primary:                         to.Ptr(true),
+									Subnet:                          &armnetwork.Subnet{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
+									PrivateIPAllocationMethod:       to.Ptr(armnetwork.IPAllocationMethodDynamic),
+									LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{},
 								},
 							},
 						},
@@ -365,29 +445,33 @@ func TestParameters(t *testing.T) {
 			spec:     &fakeIpv6NICSpec,
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.Interface{}))
-				g.Expect(result.(network.Interface)).To(Equal(network.Interface{
-					Location: to.StringPtr("fake-location"),
-					InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface)).To(Equal(armnetwork.Interface{
+					Tags: map[string]*string{
+						"Name": to.Ptr("my-net-interface"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+					},
+					Location: to.Ptr("fake-location"),
+					Properties: &armnetwork.InterfacePropertiesFormat{
 						Primary:                     nil,
-						EnableAcceleratedNetworking: to.BoolPtr(true),
-						EnableIPForwarding:          to.BoolPtr(true),
-						IPConfigurations: &[]network.InterfaceIPConfiguration{
+						EnableAcceleratedNetworking: to.Ptr(true),
+						EnableIPForwarding:          to.Ptr(true),
+						IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
 							{
-								Name: to.StringPtr("pipConfig"),
-								InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-									Primary:                         to.BoolPtr(true),
-									Subnet:                          &network.Subnet{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
-									PrivateIPAllocationMethod:       network.IPAllocationMethodDynamic,
-									LoadBalancerBackendAddressPools: &[]network.BackendAddressPool{},
+								Name: to.Ptr("pipConfig"),
+								Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+									Primary:                         to.Ptr(true),
+									Subnet:                          &armnetwork.Subnet{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
+									PrivateIPAllocationMethod:       to.Ptr(armnetwork.IPAllocationMethodDynamic),
+									LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{},
 								},
 							},
 							{
-								Name: to.StringPtr("ipConfigv6"),
-								InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-									Subnet:                  &network.Subnet{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
-									Primary:                 to.BoolPtr(false),
-									PrivateIPAddressVersion: "IPv6",
+								Name: to.Ptr("ipConfigv6"),
+								Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+									Subnet:                  &armnetwork.Subnet{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
+									Primary:                 to.Ptr(false),
+									PrivateIPAddressVersion: to.Ptr(armnetwork.IPVersionIPv6),
 								},
 							},
 						},
@@ -401,21 +485,25 @@ func TestParameters(t *testing.T) {
 			spec:     &fakeDefaultIPconfigNICSpec,
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.Interface{}))
-				g.Expect(result.(network.Interface)).To(Equal(network.Interface{
-					Location: to.StringPtr("fake-location"),
-					InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface)).To(Equal(armnetwork.Interface{
+					Tags: map[string]*string{
+						"Name": to.Ptr("my-net-interface"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+					},
+					Location: to.Ptr("fake-location"),
+					Properties: &armnetwork.InterfacePropertiesFormat{
 						Primary:                     nil,
-						EnableAcceleratedNetworking: to.BoolPtr(true),
-						EnableIPForwarding:          to.BoolPtr(true),
-						IPConfigurations: &[]network.InterfaceIPConfiguration{
+						EnableAcceleratedNetworking: to.Ptr(true),
+						EnableIPForwarding:          to.Ptr(true),
+						IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
 							{
-								Name: to.StringPtr("pipConfig"),
-								InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-									Primary:                         to.BoolPtr(true),
-									Subnet:                          &network.Subnet{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
-									PrivateIPAllocationMethod:       network.IPAllocationMethodDynamic,
-									LoadBalancerBackendAddressPools: &[]network.BackendAddressPool{},
+								Name: to.Ptr("pipConfig"),
+								Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+									Primary:                         to.Ptr(true),
+									Subnet:                          &armnetwork.Subnet{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
+									PrivateIPAllocationMethod:       to.Ptr(armnetwork.IPAllocationMethodDynamic),
+									LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{},
 								},
 							},
 						},
@@ -429,29 +517,33 @@ func TestParameters(t *testing.T) {
 			spec:     &fakeOneIPconfigNICSpec,
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.Interface{}))
-				g.Expect(result.(network.Interface)).To(Equal(network.Interface{
-					Location: to.StringPtr("fake-location"),
-					InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface)).To(Equal(armnetwork.Interface{
+					Tags: map[string]*string{
+						"Name": to.Ptr("my-net-interface"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+					},
+					Location: to.Ptr("fake-location"),
+					Properties: &armnetwork.InterfacePropertiesFormat{
 						Primary:                     nil,
-						EnableAcceleratedNetworking: to.BoolPtr(true),
-						EnableIPForwarding:          to.BoolPtr(true),
-						IPConfigurations: &[]network.InterfaceIPConfiguration{
+						EnableAcceleratedNetworking: to.Ptr(true),
+						EnableIPForwarding:          to.Ptr(true),
+						IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
 							{
-								Name: to.StringPtr("pipConfig"),
-								InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-									Primary:                         to.BoolPtr(true),
-									Subnet:                          &network.Subnet{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
-									PrivateIPAllocationMethod:       network.IPAllocationMethodDynamic,
-									LoadBalancerBackendAddressPools: &[]network.BackendAddressPool{},
+								Name: to.Ptr("pipConfig"),
+								Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+									Primary:                         to.Ptr(true),
+									Subnet:                          &armnetwork.Subnet{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
+									PrivateIPAllocationMethod:       to.Ptr(armnetwork.IPAllocationMethodDynamic),
+									LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{},
 								},
 							},
 							{
-								Name: to.StringPtr("my-net-interface-0"),
-								InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-									Primary:                         to.BoolPtr(false),
-									Subnet:                          &network.Subnet{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
-									PrivateIPAllocationMethod:       network.IPAllocationMethodDynamic,
+								Name: to.Ptr("my-net-interface-0"),
+								Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+									Primary:                         to.Ptr(false),
+									Subnet:                          &armnetwork.Subnet{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
+									PrivateIPAllocationMethod:       to.Ptr(armnetwork.IPAllocationMethodDynamic),
 									LoadBalancerBackendAddressPools: nil,
 								},
 							},
@@ -466,38 +558,42 @@ func TestParameters(t *testing.T) {
 			spec:     &fakeTwoIPconfigNICSpec,
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.Interface{}))
-				g.Expect(result.(network.Interface)).To(Equal(network.Interface{
-					Location: to.StringPtr("fake-location"),
-					InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface)).To(Equal(armnetwork.Interface{
+					Tags: map[string]*string{
+						"Name": to.Ptr("my-net-interface"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+					},
+					Location: to.Ptr("fake-location"),
+					Properties: &armnetwork.InterfacePropertiesFormat{
 						Primary:                     nil,
-						EnableAcceleratedNetworking: to.BoolPtr(true),
-						EnableIPForwarding:          to.BoolPtr(true),
-						IPConfigurations: &[]network.InterfaceIPConfiguration{
+						EnableAcceleratedNetworking: to.Ptr(true),
+						EnableIPForwarding:          to.Ptr(true),
+						IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
 							{
-								Name: to.StringPtr("pipConfig"),
-								InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-									Primary:                         to.BoolPtr(true),
-									Subnet:                          &network.Subnet{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
-									PrivateIPAllocationMethod:       network.IPAllocationMethodDynamic,
-									LoadBalancerBackendAddressPools: &[]network.BackendAddressPool{},
+								Name: to.Ptr("pipConfig"),
+								Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+									Primary:                         to.Ptr(true),
+									Subnet:                          &armnetwork.Subnet{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
+									PrivateIPAllocationMethod:       to.Ptr(armnetwork.IPAllocationMethodDynamic),
+									LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{},
 								},
 							},
 							{
-								Name: to.StringPtr("my-net-interface-0"),
-								InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-									Primary:                         to.BoolPtr(false),
-									Subnet:                          &network.Subnet{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
-									PrivateIPAllocationMethod:       network.IPAllocationMethodDynamic,
+								Name: to.Ptr("my-net-interface-0"),
+								Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+									Primary:                         to.Ptr(false),
+									Subnet:                          &armnetwork.Subnet{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
+									PrivateIPAllocationMethod:       to.Ptr(armnetwork.IPAllocationMethodDynamic),
 									LoadBalancerBackendAddressPools: nil,
 								},
 							},
 							{
-								Name: to.StringPtr("my-net-interface-1"),
-								InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-									Primary:                         to.BoolPtr(false),
-									Subnet:                          &network.Subnet{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
-									PrivateIPAllocationMethod:       network.IPAllocationMethodDynamic,
+								Name: to.Ptr("my-net-interface-1"),
+								Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+									Primary:                         to.Ptr(false),
+									Subnet:                          &armnetwork.Subnet{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
+									PrivateIPAllocationMethod:       to.Ptr(armnetwork.IPAllocationMethodDynamic),
 									LoadBalancerBackendAddressPools: nil,
 								},
 							},
@@ -507,6 +603,56 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "get parameters for network interface with DNS settings",
+			spec:     &fakeDNSSettingsNICSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface)).To(Equal(armnetwork.Interface{
+					Tags: map[string]*string{
+						"Name": to.Ptr("my-net-interface"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+					},
+					Location: to.Ptr("fake-location"),
+					Properties: &armnetwork.InterfacePropertiesFormat{
+						Primary:                     nil,
+						EnableAcceleratedNetworking: to.Ptr(true),
+						EnableIPForwarding:          to.Ptr(false),
+						DNSSettings: &armnetwork.InterfaceDNSSettings{
+							InternalDNSNameLabel: to.Ptr("win-node-1"),
+							DNSServers:           []*string{to.Ptr("10.0.0.4"), to.Ptr("10.0.0.5")},
+						},
+						IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+							{
+								Name: to.Ptr("pipConfig"),
+								Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+									Primary:                         to.Ptr(true),
+									Subnet:                          &armnetwork.Subnet{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet")},
+									PrivateIPAllocationMethod:       to.Ptr(armnetwork.IPAllocationMethodDynamic),
+									LoadBalancerBackendAddressPools: []*armnetwork.BackendAddressPool{},
+								},
+							},
+						},
+					},
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name:     "get parameters for network interface with additional tags",
+			spec:     &fakeTaggedNICSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.Interface{}))
+				g.Expect(result.(armnetwork.Interface).Tags).To(Equal(map[string]*string{
+					"Name":        to.Ptr("my-net-interface"),
+					"cost-center": to.Ptr("networking"),
+					"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+				}))
+			},
+			expectedError: "",
+		},
 	}
 	for _, tc := range testcases {
 		tc := tc
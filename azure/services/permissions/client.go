@@ -0,0 +1,236 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissions
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// permissionsAPIVersion is the API version of the permissions operations of the SDK.
+	permissionsAPIVersion = "2022-04-01"
+
+	// authorizationModuleName and authorizationModuleVersion identify the SDK module in the requests sent without an
+	// operation of the SDK.
+	authorizationModuleName    = "armauthorization"
+	authorizationModuleVersion = "v2.2.0"
+)
+
+// Client wraps go-sdk.
+type Client interface {
+	ListPermissions(context.Context, string) ([]armauthorization.Permission, error)
+	ListResourceGroupPermissions(context.Context, string) ([]armauthorization.Permission, error)
+	ListSubscriptionPermissions(context.Context) ([]armauthorization.Permission, error)
+	CreateRoleAssignment(context.Context, string, string, string, string) error
+	PrincipalID(context.Context) (string, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	auth azure.Authorizer
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new permissions client.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	return &AzureClient{
+		auth: auth,
+	}
+}
+
+// ListPermissions lists the permissions of the cluster identity on a resource, which may be in another subscription
+// than the cluster.
+func (ac *AzureClient) ListPermissions(ctx context.Context, id string) ([]armauthorization.Permission, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "permissions.AzureClient.ListPermissions")
+	defer done()
+
+	resourceID, err := azure.ParseResourceID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The parent resource path holds the types and names of the parents of the resource within its provider, such as
+	// "galleries/my-gallery" for a gallery image.
+	var parents []string
+	for p := resourceID.Parent; p != nil && len(p.ResourceType.Types) > 0 && strings.EqualFold(p.ResourceType.Namespace, resourceID.ResourceType.Namespace); p = p.Parent {
+		parents = append([]string{p.ResourceType.Types[len(p.ResourceType.Types)-1], p.Name}, parents...)
+	}
+
+	c, err := ac.permissionsClient(resourceID.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	pager := c.NewListForResourcePager(resourceID.ResourceGroupName, resourceID.ResourceType.Namespace,
+		strings.Join(parents, "/"), resourceID.ResourceType.Types[len(resourceID.ResourceType.Types)-1], resourceID.Name, nil)
+	return azure.ListPager(ctx, "permissions on "+id, pager, func(page armauthorization.PermissionsClientListForResourceResponse) []*armauthorization.Permission {
+		return page.Value
+	})
+}
+
+// ListResourceGroupPermissions lists the permissions of the cluster identity on a resource group of the cluster
+// subscription.
+func (ac *AzureClient) ListResourceGroupPermissions(ctx context.Context, resourceGroup string) ([]armauthorization.Permission, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "permissions.AzureClient.ListResourceGroupPermissions")
+	defer done()
+
+	c, err := ac.permissionsClient(ac.auth.SubscriptionID())
+	if err != nil {
+		return nil, err
+	}
+	pager := c.NewListForResourceGroupPager(resourceGroup, nil)
+	return azure.ListPager(ctx, "permissions on resource group "+resourceGroup, pager, func(page armauthorization.PermissionsClientListForResourceGroupResponse) []*armauthorization.Permission {
+		return page.Value
+	})
+}
+
+// ListSubscriptionPermissions lists the permissions of the cluster identity on the cluster subscription. The SDK has
+// no operation for it, so the request is sent directly to the subscription-level permissions endpoint.
+func (ac *AzureClient) ListSubscriptionPermissions(ctx context.Context) ([]armauthorization.Permission, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "permissions.AzureClient.ListSubscriptionPermissions")
+	defer done()
+
+	opts, err := azure.ARMClientOptions(ac.auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := arm.NewClient(authorizationModuleName, authorizationModuleVersion, ac.auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ARM client")
+	}
+
+	subscriptionID := ac.auth.SubscriptionID()
+	req, err := runtime.NewRequest(ctx, http.MethodGet, runtime.JoinPaths(c.Endpoint(),
+		"/subscriptions/"+url.PathEscape(subscriptionID)+"/providers/Microsoft.Authorization/permissions"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to prepare the request listing permissions on the subscription")
+	}
+	query := req.Raw().URL.Query()
+	query.Set("api-version", permissionsAPIVersion)
+	req.Raw().URL.RawQuery = query.Encode()
+	req.Raw().Header["Accept"] = []string{"application/json"}
+
+	resp, err := c.Pipeline().Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list permissions on subscription %s", subscriptionID)
+	}
+	if !runtime.HasStatusCode(resp, http.StatusOK) {
+		return nil, errors.Wrapf(runtime.NewResponseError(resp), "failed to list permissions on subscription %s", subscriptionID)
+	}
+	// The response of the subscription-level endpoint has the same schema as the resource group-level one.
+	var result armauthorization.PermissionGetResult
+	if err := runtime.UnmarshalAsJSON(resp, &result); err != nil {
+		return nil, errors.Wrapf(err, "failed to list permissions on subscription %s", subscriptionID)
+	}
+	var permissions []armauthorization.Permission
+	for _, permission := range result.Value {
+		if permission != nil {
+			permissions = append(permissions, *permission)
+		}
+	}
+	return permissions, nil
+}
+
+// CreateRoleAssignment assigns a role on a scope, which may be in another subscription than the cluster, to a
+// principal. An existing role assignment isn't an error.
+func (ac *AzureClient) CreateRoleAssignment(ctx context.Context, scope, name, roleDefinitionID, principalID string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "permissions.AzureClient.CreateRoleAssignment")
+	defer done()
+
+	opts, err := azure.ARMClientOptions(ac.auth.CloudEnvironment())
+	if err != nil {
+		return errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armauthorization.NewRoleAssignmentsClient(ac.auth.SubscriptionID(), ac.auth.Token(), opts)
+	if err != nil {
+		return errors.Wrap(err, "failed to create role assignments client")
+	}
+	_, err = c.Create(ctx, scope, name, armauthorization.RoleAssignmentCreateParameters{
+		Properties: &armauthorization.RoleAssignmentProperties{
+			PrincipalID:      to.Ptr(principalID),
+			RoleDefinitionID: to.Ptr(roleDefinitionID),
+		},
+	}, nil)
+	if err != nil && !azure.ResourceConflict(err) {
+		return errors.Wrapf(err, "failed to assign role %s on %s", roleDefinitionID, scope)
+	}
+	return nil
+}
+
+// permissionsClient returns a permissions client of a subscription.
+func (ac *AzureClient) permissionsClient(subscriptionID string) (*armauthorization.PermissionsClient, error) {
+	opts, err := azure.ARMClientOptions(ac.auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armauthorization.NewPermissionsClient(subscriptionID, ac.auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create permissions client")
+	}
+	return c, nil
+}
+
+// PrincipalID returns the object ID of the cluster identity, read from the oid claim of its Azure Resource Manager
+// access token.
+func (ac *AzureClient) PrincipalID(ctx context.Context) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "permissions.AzureClient.PrincipalID")
+	defer done()
+
+	token, err := ac.auth.Token().GetToken(ctx, policy.TokenRequestOptions{
+		Scopes: []string{strings.TrimSuffix(ac.auth.BaseURI(), "/") + "/.default"},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get an access token for the cluster identity")
+	}
+	return principalIDFromToken(token.Token)
+}
+
+// principalIDFromToken returns the oid claim of a JWT access token.
+func principalIDFromToken(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("access token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode the access token claims")
+	}
+	var claims struct {
+		OID string `json:"oid"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal the access token claims")
+	}
+	if claims.OID == "" {
+		return "", errors.New("access token has no oid claim")
+	}
+	return claims.OID, nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../client.go
+
+// Package mock_permissions is a generated GoMock package.
+package mock_permissions
+
+import (
+	context "context"
+	reflect "reflect"
+
+	armauthorization "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// CreateRoleAssignment mocks base method.
+func (m *MockClient) CreateRoleAssignment(arg0 context.Context, arg1, arg2, arg3, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRoleAssignment", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRoleAssignment indicates an expected call of CreateRoleAssignment.
+func (mr *MockClientMockRecorder) CreateRoleAssignment(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRoleAssignment", reflect.TypeOf((*MockClient)(nil).CreateRoleAssignment), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ListPermissions mocks base method.
+func (m *MockClient) ListPermissions(arg0 context.Context, arg1 string) ([]armauthorization.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPermissions", arg0, arg1)
+	ret0, _ := ret[0].([]armauthorization.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPermissions indicates an expected call of ListPermissions.
+func (mr *MockClientMockRecorder) ListPermissions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPermissions", reflect.TypeOf((*MockClient)(nil).ListPermissions), arg0, arg1)
+}
+
+// ListResourceGroupPermissions mocks base method.
+func (m *MockClient) ListResourceGroupPermissions(arg0 context.Context, arg1 string) ([]armauthorization.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResourceGroupPermissions", arg0, arg1)
+	ret0, _ := ret[0].([]armauthorization.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResourceGroupPermissions indicates an expected call of ListResourceGroupPermissions.
+func (mr *MockClientMockRecorder) ListResourceGroupPermissions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceGroupPermissions", reflect.TypeOf((*MockClient)(nil).ListResourceGroupPermissions), arg0, arg1)
+}
+
+// ListSubscriptionPermissions mocks base method.
+func (m *MockClient) ListSubscriptionPermissions(arg0 context.Context) ([]armauthorization.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptionPermissions", arg0)
+	ret0, _ := ret[0].([]armauthorization.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptionPermissions indicates an expected call of ListSubscriptionPermissions.
+func (mr *MockClientMockRecorder) ListSubscriptionPermissions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptionPermissions", reflect.TypeOf((*MockClient)(nil).ListSubscriptionPermissions), arg0)
+}
+
+// PrincipalID mocks base method.
+func (m *MockClient) PrincipalID(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrincipalID", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PrincipalID indicates an expected call of PrincipalID.
+func (mr *MockClientMockRecorder) PrincipalID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrincipalID", reflect.TypeOf((*MockClient)(nil).PrincipalID), arg0)
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissions
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	serviceName = "permissions"
+
+	// resourceGroupsWriteAction is the action the cluster identity must be allowed to perform on the subscription to
+	// create the resource group of the cluster.
+	resourceGroupsWriteAction = "Microsoft.Resources/subscriptions/resourceGroups/write"
+)
+
+// AccessScope defines the scope interface for a permissions service.
+type AccessScope interface {
+	azure.Authorizer
+	CrossSubscriptionAccess() infrav1.CrossSubscriptionAccessPolicy
+	CrossSubscriptionResources() []azure.CrossSubscriptionResource
+}
+
+// Service checks or grants the access of the cluster identity to the resources of a machine which are in another
+// subscription than the cluster, before the machine is created.
+type Service struct {
+	Scope AccessScope
+	Client
+}
+
+// New creates a new service.
+func New(scope AccessScope) *Service {
+	return &Service{
+		Scope:  scope,
+		Client: NewClient(scope),
+	}
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile grants the Reader role on the resources of the machine in other subscriptions to the cluster identity if
+// the cross-subscription access policy of the cluster is Assign, or checks the cluster identity is allowed to use them.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "permissions.Service.Reconcile")
+	defer done()
+
+	resources := s.Scope.CrossSubscriptionResources()
+	if len(resources) == 0 {
+		return nil
+	}
+
+	if s.Scope.CrossSubscriptionAccess() == infrav1.CrossSubscriptionAccessAssign {
+		return s.assignReaderRole(ctx, resources)
+	}
+
+	missing, err := MissingPermissions(ctx, s.Client, resources)
+	if err != nil {
+		return errors.Wrap(err, "failed to check the access of the cluster identity to the resources in other subscriptions")
+	}
+	if len(missing) > 0 {
+		return errors.New(strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// assignReaderRole grants the Reader role on resources to the cluster identity.
+func (s *Service) assignReaderRole(ctx context.Context, resources []azure.CrossSubscriptionResource) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "permissions.Service.assignReaderRole")
+	defer done()
+
+	principalID, err := s.Client.PrincipalID(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the principal ID of the cluster identity")
+	}
+
+	for _, resource := range resources {
+		roleDefinitionID := azure.GenerateReaderRoleDefinitionID(resource.SubscriptionID)
+		name := azure.GenerateRoleAssignmentName(resource.ID, roleDefinitionID, principalID)
+		if err := s.Client.CreateRoleAssignment(ctx, resource.ID, name, roleDefinitionID, principalID); err != nil {
+			return err
+		}
+		log.V(2).Info("assigned the Reader role to the cluster identity", tele.LogKeyResourceID, resource.ID)
+	}
+	return nil
+}
+
+// Delete is a no-op as the role assignments on resources in other subscriptions may be shared by other machines.
+func (s *Service) Delete(ctx context.Context) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "permissions.Service.Delete")
+	defer done()
+
+	return nil
+}
+
+// IsManaged always returns true as the access to the resources in other subscriptions is always handled by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
+
+// MissingPermissions checks the cluster identity is allowed to perform the action each resource requires, and returns
+// the missing permissions.
+func MissingPermissions(ctx context.Context, client Client, resources []azure.CrossSubscriptionResource) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "permissions.MissingPermissions")
+	defer done()
+
+	var missing []string
+	for _, resource := range resources {
+		permissions, err := client.ListPermissions(ctx, resource.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !Allows(permissions, resource.Action) {
+			missing = append(missing, fmt.Sprintf("the cluster identity is missing permission %s on %s", resource.Action, resource.ID))
+		}
+	}
+	return missing, nil
+}
+
+// MissingResourceGroupActions checks the cluster identity is allowed to perform actions on a resource group of the
+// cluster subscription, and returns the actions it isn't allowed to perform. When the resource group doesn't exist yet,
+// the actions, and the creation of the resource group, are checked on the subscription instead.
+func MissingResourceGroupActions(ctx context.Context, client Client, resourceGroup string, actions []string) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "permissions.MissingResourceGroupActions")
+	defer done()
+
+	permissions, err := client.ListResourceGroupPermissions(ctx, resourceGroup)
+	if azure.ResourceNotFound(err) {
+		actions = append([]string{resourceGroupsWriteAction}, actions...)
+		permissions, err = client.ListSubscriptionPermissions(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, action := range actions {
+		if !Allows(permissions, action) {
+			missing = append(missing, action)
+		}
+	}
+	return missing, nil
+}
+
+// Allows returns true if permissions allow an action: the action matches one of their actions and none of the not
+// actions of the same permission. Actions are case insensitive and may contain wildcards, such as
+// "Microsoft.Compute/*/read".
+func Allows(permissions []armauthorization.Permission, action string) bool {
+	for _, permission := range permissions {
+		if !matchesAny(permission.Actions, action) {
+			continue
+		}
+		if matchesAny(permission.NotActions, action) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// matchesAny returns true if an action matches one of the given action patterns.
+func matchesAny(patterns []*string, action string) bool {
+	for _, pattern := range patterns {
+		if pattern == nil {
+			continue
+		}
+		expr := "(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(*pattern), `\*`, ".*") + "$"
+		if matched, err := regexp.MatchString(expr, action); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,333 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissions
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/permissions/mock_permissions"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeGalleryImage = azure.CrossSubscriptionResource{
+		ID:             "/subscriptions/456/resourceGroups/images-rg/providers/Microsoft.Compute/galleries/my-gallery/images/my-image",
+		SubscriptionID: "456",
+		Action:         azure.GalleryImageVersionsReadAction,
+	}
+	fakeDiskEncryptionSet = azure.CrossSubscriptionResource{
+		ID:             "/subscriptions/789/resourceGroups/keys-rg/providers/Microsoft.Compute/diskEncryptionSets/my-des",
+		SubscriptionID: "789",
+		Action:         azure.DiskEncryptionSetsReadAction,
+	}
+
+	internalError = errors.New("Internal Server Error")
+)
+
+// fakePermissions returns permissions allowing the given actions.
+func fakePermissions(actions ...string) []armauthorization.Permission {
+	return []armauthorization.Permission{{Actions: to.SliceOfPtrs(actions...)}}
+}
+
+func TestReconcilePermissions(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_permissions.MockAccessScopeMockRecorder, m *mock_permissions.MockClientMockRecorder)
+	}{
+		{
+			name:          "noop if no resources are in other subscriptions",
+			expectedError: "",
+			expect: func(s *mock_permissions.MockAccessScopeMockRecorder, m *mock_permissions.MockClientMockRecorder) {
+				s.CrossSubscriptionResources().Return(nil)
+			},
+		},
+		{
+			name:          "cluster identity is allowed to use the resources",
+			expectedError: "",
+			expect: func(s *mock_permissions.MockAccessScopeMockRecorder, m *mock_permissions.MockClientMockRecorder) {
+				s.CrossSubscriptionResources().Return([]azure.CrossSubscriptionResource{fakeGalleryImage, fakeDiskEncryptionSet})
+				s.CrossSubscriptionAccess().Return(infrav1.CrossSubscriptionAccessValidate)
+				m.ListPermissions(gomockinternal.AContext(), fakeGalleryImage.ID).Return(fakePermissions("Microsoft.Compute/galleries/*/read"), nil)
+				m.ListPermissions(gomockinternal.AContext(), fakeDiskEncryptionSet.ID).Return(fakePermissions("*"), nil)
+			},
+		},
+		{
+			name:          "cluster identity is missing permissions",
+			expectedError: "the cluster identity is missing permission Microsoft.Compute/diskEncryptionSets/read on /subscriptions/789/resourceGroups/keys-rg/providers/Microsoft.Compute/diskEncryptionSets/my-des",
+			expect: func(s *mock_permissions.MockAccessScopeMockRecorder, m *mock_permissions.MockClientMockRecorder) {
+				s.CrossSubscriptionResources().Return([]azure.CrossSubscriptionResource{fakeGalleryImage, fakeDiskEncryptionSet})
+				s.CrossSubscriptionAccess().Return(infrav1.CrossSubscriptionAccessValidate)
+				m.ListPermissions(gomockinternal.AContext(), fakeGalleryImage.ID).Return(fakePermissions(azure.GalleryImageVersionsReadAction), nil)
+				m.ListPermissions(gomockinternal.AContext(), fakeDiskEncryptionSet.ID).Return(fakePermissions("Microsoft.Compute/disks/read"), nil)
+			},
+		},
+		{
+			name:          "fail to list permissions",
+			expectedError: "failed to check the access of the cluster identity to the resources in other subscriptions: Internal Server Error",
+			expect: func(s *mock_permissions.MockAccessScopeMockRecorder, m *mock_permissions.MockClientMockRecorder) {
+				s.CrossSubscriptionResources().Return([]azure.CrossSubscriptionResource{fakeGalleryImage})
+				s.CrossSubscriptionAccess().Return(infrav1.CrossSubscriptionAccessValidate)
+				m.ListPermissions(gomockinternal.AContext(), fakeGalleryImage.ID).Return(nil, internalError)
+			},
+		},
+		{
+			name:          "assign the Reader role on the resources",
+			expectedError: "",
+			expect: func(s *mock_permissions.MockAccessScopeMockRecorder, m *mock_permissions.MockClientMockRecorder) {
+				s.CrossSubscriptionResources().Return([]azure.CrossSubscriptionResource{fakeGalleryImage, fakeDiskEncryptionSet})
+				s.CrossSubscriptionAccess().Return(infrav1.CrossSubscriptionAccessAssign)
+				m.PrincipalID(gomockinternal.AContext()).Return("my-principal", nil)
+				imageRole := azure.GenerateReaderRoleDefinitionID("456")
+				desRole := azure.GenerateReaderRoleDefinitionID("789")
+				gomock.InOrder(
+					m.CreateRoleAssignment(gomockinternal.AContext(), fakeGalleryImage.ID,
+						azure.GenerateRoleAssignmentName(fakeGalleryImage.ID, imageRole, "my-principal"), imageRole, "my-principal").Return(nil),
+					m.CreateRoleAssignment(gomockinternal.AContext(), fakeDiskEncryptionSet.ID,
+						azure.GenerateRoleAssignmentName(fakeDiskEncryptionSet.ID, desRole, "my-principal"), desRole, "my-principal").Return(nil),
+				)
+			},
+		},
+		{
+			name:          "fail to assign the Reader role",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_permissions.MockAccessScopeMockRecorder, m *mock_permissions.MockClientMockRecorder) {
+				s.CrossSubscriptionResources().Return([]azure.CrossSubscriptionResource{fakeGalleryImage})
+				s.CrossSubscriptionAccess().Return(infrav1.CrossSubscriptionAccessAssign)
+				m.PrincipalID(gomockinternal.AContext()).Return("my-principal", nil)
+				m.CreateRoleAssignment(gomockinternal.AContext(), fakeGalleryImage.ID, gomock.Any(), gomock.Any(), "my-principal").Return(internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_permissions.NewMockAccessScope(mockCtrl)
+			clientMock := mock_permissions.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestMissingResourceGroupActions(t *testing.T) {
+	notFound := &azcore.ResponseError{StatusCode: http.StatusNotFound}
+	actions := []string{"Microsoft.Network/virtualNetworks/read", "Microsoft.Network/virtualNetworks/write"}
+
+	testcases := []struct {
+		name          string
+		expected      []string
+		expectedError string
+		expect        func(m *mock_permissions.MockClientMockRecorder)
+	}{
+		{
+			name:     "cluster identity is allowed to perform the actions on the resource group",
+			expected: nil,
+			expect: func(m *mock_permissions.MockClientMockRecorder) {
+				m.ListResourceGroupPermissions(gomockinternal.AContext(), "my-rg").Return(fakePermissions("Microsoft.Network/virtualNetworks/*"), nil)
+			},
+		},
+		{
+			name:     "cluster identity is missing actions on the resource group",
+			expected: []string{"Microsoft.Network/virtualNetworks/write"},
+			expect: func(m *mock_permissions.MockClientMockRecorder) {
+				m.ListResourceGroupPermissions(gomockinternal.AContext(), "my-rg").Return(fakePermissions("*/read"), nil)
+			},
+		},
+		{
+			name:     "actions and the creation of the resource group are checked on the subscription before the resource group exists",
+			expected: []string{"Microsoft.Resources/subscriptions/resourceGroups/write"},
+			expect: func(m *mock_permissions.MockClientMockRecorder) {
+				m.ListResourceGroupPermissions(gomockinternal.AContext(), "my-rg").Return(nil, notFound)
+				m.ListSubscriptionPermissions(gomockinternal.AContext()).Return(fakePermissions("Microsoft.Network/*"), nil)
+			},
+		},
+		{
+			name:          "fail to list permissions",
+			expectedError: "Internal Server Error",
+			expect: func(m *mock_permissions.MockClientMockRecorder) {
+				m.ListResourceGroupPermissions(gomockinternal.AContext(), "my-rg").Return(nil, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_permissions.NewMockClient(mockCtrl)
+
+			tc.expect(clientMock.EXPECT())
+
+			missing, err := MissingResourceGroupActions(context.TODO(), clientMock, "my-rg", actions)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(missing).To(Equal(tc.expected))
+			}
+		})
+	}
+}
+
+func TestAllows(t *testing.T) {
+	testcases := []struct {
+		name        string
+		permissions []armauthorization.Permission
+		action      string
+		expected    bool
+	}{
+		{
+			name:        "no permissions",
+			permissions: nil,
+			action:      azure.GalleryImageVersionsReadAction,
+			expected:    false,
+		},
+		{
+			name:        "exact action",
+			permissions: fakePermissions(azure.GalleryImageVersionsReadAction),
+			action:      azure.GalleryImageVersionsReadAction,
+			expected:    true,
+		},
+		{
+			name:        "actions are case insensitive",
+			permissions: fakePermissions("microsoft.compute/galleries/images/versions/READ"),
+			action:      azure.GalleryImageVersionsReadAction,
+			expected:    true,
+		},
+		{
+			name:        "wildcard action",
+			permissions: fakePermissions("Microsoft.Compute/*/read"),
+			action:      azure.DiskEncryptionSetsReadAction,
+			expected:    true,
+		},
+		{
+			name:        "other action",
+			permissions: fakePermissions("Microsoft.Compute/disks/*"),
+			action:      azure.DiskEncryptionSetsReadAction,
+			expected:    false,
+		},
+		{
+			name: "action excluded by a not action",
+			permissions: []armauthorization.Permission{{
+				Actions:    to.SliceOfPtrs("*"),
+				NotActions: to.SliceOfPtrs("Microsoft.Compute/diskEncryptionSets/*"),
+			}},
+			action:   azure.DiskEncryptionSetsReadAction,
+			expected: false,
+		},
+		{
+			name: "action excluded from one permission but allowed by another",
+			permissions: []armauthorization.Permission{
+				{
+					Actions:    to.SliceOfPtrs("*"),
+					NotActions: to.SliceOfPtrs("Microsoft.Compute/diskEncryptionSets/*"),
+				},
+				{
+					Actions: to.SliceOfPtrs("Microsoft.Compute/diskEncryptionSets/read"),
+				},
+			},
+			action:   azure.DiskEncryptionSetsReadAction,
+			expected: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(Allows(tc.permissions, tc.action)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestPrincipalIDFromToken(t *testing.T) {
+	encode := func(claims string) string {
+		return "header." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".signature"
+	}
+
+	testcases := []struct {
+		name          string
+		token         string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "token with an oid claim",
+			token:    encode(`{"aud":"https://management.azure.com/","oid":"my-principal"}`),
+			expected: "my-principal",
+		},
+		{
+			name:          "token without an oid claim",
+			token:         encode(`{"aud":"https://management.azure.com/"}`),
+			expectedError: "access token has no oid claim",
+		},
+		{
+			name:          "opaque token",
+			token:         "opaque",
+			expectedError: "access token is not a JWT",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			principalID, err := principalIDFromToken(tc.token)
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(principalID).To(Equal(tc.expected))
+			}
+		})
+	}
+}
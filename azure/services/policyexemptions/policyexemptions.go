@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policyexemptions
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// ServiceName is the name of the policy exemptions service.
+const ServiceName = "policyexemptions"
+
+// PolicyExemptionScope defines the scope interface for a policy exemptions service.
+type PolicyExemptionScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	ResourceGroup() string
+	PolicyExemptionSpecs() []azure.ResourceSpecGetter
+	AnnotationJSON(string) (map[string]interface{}, error)
+	UpdateAnnotationJSON(string, map[string]interface{}) error
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope PolicyExemptionScope
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope PolicyExemptionScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:      scope,
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return ServiceName
+}
+
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile its policy exemptions. Creating them also requires the
+// Microsoft.Authorization/policyAssignments/exempt/action on the policy assignments, which aren't in the resource group.
+func (s *Service) RequiredActions() []string {
+	if len(s.Scope.PolicyExemptionSpecs()) == 0 {
+		return nil
+	}
+	return []string{
+		"Microsoft.Authorization/policyExemptions/read",
+		"Microsoft.Authorization/policyExemptions/write",
+		"Microsoft.Authorization/policyExemptions/delete",
+	}
+}
+
+// Reconcile creates or updates the policy exemptions of the resource group of the cluster, and deletes the ones which
+// were removed from the spec of the cluster.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "policyexemptions.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.PolicyExemptionSpecs()
+	lastApplied, err := s.Scope.AnnotationJSON(azure.PolicyExemptionsLastAppliedAnnotation)
+	if err != nil {
+		return err
+	}
+	if len(specs) == 0 && len(lastApplied) == 0 {
+		return nil
+	}
+
+	// We go through the list of PolicyExemptionSpecs to reconcile each one, independently of the result of the previous one.
+	// If multiple errors occur, we return the most pressing one.
+	//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+	var result error
+	applied := make(map[string]interface{}, len(specs))
+	for _, spec := range specs {
+		id, err := resourceID(spec)
+		if err != nil {
+			return err
+		}
+		applied[spec.ResourceName()] = id
+		if _, err := s.CreateResource(ctx, spec, ServiceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	// The policy exemptions removed from the spec are deleted, and kept in the annotation until they are.
+	for name := range lastApplied {
+		if _, ok := applied[name]; ok {
+			continue
+		}
+		spec := &PolicyExemptionSpec{
+			Name:           name,
+			ResourceGroup:  s.Scope.ResourceGroup(),
+			SubscriptionID: s.Scope.SubscriptionID(),
+		}
+		if err := s.DeleteResource(ctx, spec, ServiceName); err != nil {
+			applied[name] = lastApplied[name]
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(applied, lastApplied) {
+		if err := s.Scope.UpdateAnnotationJSON(azure.PolicyExemptionsLastAppliedAnnotation, applied); err != nil {
+			return errors.Wrap(err, "failed to record the policy exemptions of the resource group")
+		}
+	}
+
+	s.Scope.UpdatePutStatus(infrav1.PolicyExemptionsReadyCondition, ServiceName, result)
+	return result
+}
+
+// Delete deletes the policy exemptions of the resource group of the cluster.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "policyexemptions.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	specs := s.Scope.PolicyExemptionSpecs()
+	if len(specs) == 0 {
+		return nil
+	}
+
+	var result error
+	for _, spec := range specs {
+		if err := s.DeleteResource(ctx, spec, ServiceName); err != nil {
+			if !azure.IsOperationNotDoneError(err) || result == nil {
+				result = err
+			}
+		}
+	}
+
+	s.Scope.UpdateDeleteStatus(infrav1.PolicyExemptionsReadyCondition, ServiceName, result)
+	return result
+}
+
+// IsManaged always returns true as the policy exemptions of the resource group are only created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
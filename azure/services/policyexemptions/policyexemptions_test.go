@@ -0,0 +1,230 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policyexemptions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/policyexemptions/mock_policyexemptions"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakePublicIPExemptionSpec = PolicyExemptionSpec{
+		Name:               "allow-public-ip",
+		ResourceGroup:      "my-rg",
+		SubscriptionID:     "123",
+		ClusterName:        "my-cluster",
+		PolicyAssignmentID: "/providers/Microsoft.Management/managementGroups/my-mg/providers/Microsoft.Authorization/policyAssignments/deny-public-ip",
+		Category:           infrav1.PolicyExemptionCategoryWaiver,
+		Justification:      "The API server load balancer needs a public IP.",
+	}
+	fakeTagsExemptionSpec = PolicyExemptionSpec{
+		Name:               "allow-missing-tags",
+		ResourceGroup:      "my-rg",
+		SubscriptionID:     "123",
+		ClusterName:        "my-cluster",
+		PolicyAssignmentID: "/subscriptions/123/providers/Microsoft.Authorization/policyAssignments/require-tags",
+		Category:           infrav1.PolicyExemptionCategoryMitigated,
+		Justification:      "The resources are tagged by CAPZ after they are created.",
+	}
+	fakeRemovedExemptionSpec = PolicyExemptionSpec{
+		Name:           "allow-removed",
+		ResourceGroup:  "my-rg",
+		SubscriptionID: "123",
+	}
+	internalError = errors.New("Internal Server Error")
+	notDoneError  = azure.NewOperationNotDoneError(&infrav1.Future{})
+)
+
+func TestReconcilePolicyExemptions(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_policyexemptions.MockPolicyExemptionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the cluster has no policy exemptions",
+			expectedError: "",
+			expect: func(s *mock_policyexemptions.MockPolicyExemptionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.PolicyExemptionSpecs().Return(nil)
+				s.AnnotationJSON(azure.PolicyExemptionsLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+			},
+		},
+		{
+			name:          "create the policy exemptions and record them",
+			expectedError: "",
+			expect: func(s *mock_policyexemptions.MockPolicyExemptionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.PolicyExemptionSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPExemptionSpec, &fakeTagsExemptionSpec})
+				s.AnnotationJSON(azure.PolicyExemptionsLastAppliedAnnotation).Return(map[string]interface{}{}, nil)
+				r.CreateResource(gomockinternal.AContext(), &fakePublicIPExemptionSpec, ServiceName).Return(nil, nil)
+				r.CreateResource(gomockinternal.AContext(), &fakeTagsExemptionSpec, ServiceName).Return(nil, nil)
+				s.UpdateAnnotationJSON(azure.PolicyExemptionsLastAppliedAnnotation, map[string]interface{}{
+					"allow-public-ip":    "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Authorization/policyExemptions/allow-public-ip",
+					"allow-missing-tags": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Authorization/policyExemptions/allow-missing-tags",
+				}).Return(nil)
+				s.UpdatePutStatus(infrav1.PolicyExemptionsReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "delete the policy exemptions removed from the spec",
+			expectedError: "",
+			expect: func(s *mock_policyexemptions.MockPolicyExemptionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.PolicyExemptionSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPExemptionSpec})
+				s.AnnotationJSON(azure.PolicyExemptionsLastAppliedAnnotation).Return(map[string]interface{}{
+					"allow-public-ip": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Authorization/policyExemptions/allow-public-ip",
+					"allow-removed":   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Authorization/policyExemptions/allow-removed",
+				}, nil)
+				s.ResourceGroup().Return("my-rg")
+				s.SubscriptionID().Return("123")
+				r.CreateResource(gomockinternal.AContext(), &fakePublicIPExemptionSpec, ServiceName).Return(nil, nil)
+				r.DeleteResource(gomockinternal.AContext(), &fakeRemovedExemptionSpec, ServiceName).Return(nil)
+				s.UpdateAnnotationJSON(azure.PolicyExemptionsLastAppliedAnnotation, map[string]interface{}{
+					"allow-public-ip": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Authorization/policyExemptions/allow-public-ip",
+				}).Return(nil)
+				s.UpdatePutStatus(infrav1.PolicyExemptionsReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "keep recording the policy exemptions which fail to be deleted",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_policyexemptions.MockPolicyExemptionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.PolicyExemptionSpecs().Return(nil)
+				s.AnnotationJSON(azure.PolicyExemptionsLastAppliedAnnotation).Return(map[string]interface{}{
+					"allow-removed": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Authorization/policyExemptions/allow-removed",
+				}, nil)
+				s.ResourceGroup().Return("my-rg")
+				s.SubscriptionID().Return("123")
+				r.DeleteResource(gomockinternal.AContext(), &fakeRemovedExemptionSpec, ServiceName).Return(internalError)
+				s.UpdatePutStatus(infrav1.PolicyExemptionsReadyCondition, ServiceName, internalError)
+			},
+		},
+		{
+			name:          "return the error creating a policy exemption over the one still being created",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_policyexemptions.MockPolicyExemptionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.PolicyExemptionSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPExemptionSpec, &fakeTagsExemptionSpec})
+				s.AnnotationJSON(azure.PolicyExemptionsLastAppliedAnnotation).Return(map[string]interface{}{
+					"allow-public-ip":    "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Authorization/policyExemptions/allow-public-ip",
+					"allow-missing-tags": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Authorization/policyExemptions/allow-missing-tags",
+				}, nil)
+				r.CreateResource(gomockinternal.AContext(), &fakePublicIPExemptionSpec, ServiceName).Return(nil, notDoneError)
+				r.CreateResource(gomockinternal.AContext(), &fakeTagsExemptionSpec, ServiceName).Return(nil, internalError)
+				s.UpdatePutStatus(infrav1.PolicyExemptionsReadyCondition, ServiceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_policyexemptions.NewMockPolicyExemptionScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDeletePolicyExemptions(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_policyexemptions.MockPolicyExemptionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if the cluster has no policy exemptions",
+			expectedError: "",
+			expect: func(s *mock_policyexemptions.MockPolicyExemptionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.PolicyExemptionSpecs().Return(nil)
+			},
+		},
+		{
+			name:          "delete the policy exemptions",
+			expectedError: "",
+			expect: func(s *mock_policyexemptions.MockPolicyExemptionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.PolicyExemptionSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPExemptionSpec, &fakeTagsExemptionSpec})
+				r.DeleteResource(gomockinternal.AContext(), &fakePublicIPExemptionSpec, ServiceName).Return(nil)
+				r.DeleteResource(gomockinternal.AContext(), &fakeTagsExemptionSpec, ServiceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.PolicyExemptionsReadyCondition, ServiceName, nil)
+			},
+		},
+		{
+			name:          "fail to delete a policy exemption",
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_policyexemptions.MockPolicyExemptionScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.PolicyExemptionSpecs().Return([]azure.ResourceSpecGetter{&fakePublicIPExemptionSpec, &fakeTagsExemptionSpec})
+				r.DeleteResource(gomockinternal.AContext(), &fakePublicIPExemptionSpec, ServiceName).Return(internalError)
+				r.DeleteResource(gomockinternal.AContext(), &fakeTagsExemptionSpec, ServiceName).Return(nil)
+				s.UpdateDeleteStatus(infrav1.PolicyExemptionsReadyCondition, ServiceName, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_policyexemptions.NewMockPolicyExemptionScope(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
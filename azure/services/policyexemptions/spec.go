@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policyexemptions
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// PolicyExemptionSpec defines the specification for a policy exemption of the resource group of a cluster.
+type PolicyExemptionSpec struct {
+	Name                         string
+	ResourceGroup                string
+	SubscriptionID               string
+	ClusterName                  string
+	PolicyAssignmentID           string
+	PolicyDefinitionReferenceIDs []string
+	Category                     infrav1.PolicyExemptionCategory
+	Justification                string
+	Metadata                     map[string]string
+	ExpiresOn                    *metav1.Time
+}
+
+// ResourceName returns the name of the policy exemption.
+func (s *PolicyExemptionSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group the policy exemption applies to.
+func (s *PolicyExemptionSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for policy exemptions.
+func (s *PolicyExemptionSpec) OwnerResourceName() string {
+	return ""
+}
+
+// ResourceID returns the resource ID of the policy exemption.
+func (s *PolicyExemptionSpec) ResourceID() string {
+	return azure.PolicyExemptionID(s.SubscriptionID, s.ResourceGroup, s.Name)
+}
+
+// Parameters returns the parameters for the policy exemption.
+func (s *PolicyExemptionSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	// The metadata of the exemption records the cluster it was created for, along with the justification metadata.
+	metadata := map[string]interface{}{
+		infrav1.ClusterTagKey(s.ClusterName): string(infrav1.ResourceLifecycleOwned),
+	}
+	for k, v := range s.Metadata {
+		metadata[k] = v
+	}
+	properties := map[string]interface{}{
+		"policyAssignmentId": s.PolicyAssignmentID,
+		"exemptionCategory":  string(s.Category),
+		"description":        s.Justification,
+		"metadata":           metadata,
+	}
+	if len(s.PolicyDefinitionReferenceIDs) > 0 {
+		properties["policyDefinitionReferenceIds"] = s.PolicyDefinitionReferenceIDs
+	}
+	if s.ExpiresOn != nil {
+		properties["expiresOn"] = s.ExpiresOn.UTC().Format(time.RFC3339)
+	}
+
+	if existing != nil {
+		existingExemption, ok := existing.(armresources.GenericResource)
+		if !ok {
+			return nil, errors.Errorf("%T is not an armarmresources.GenericResource", existing)
+		}
+		if existingProperties, ok := existingExemption.Properties.(map[string]interface{}); ok && s.isUpToDate(existingProperties, metadata) {
+			// Skip update for the policy exemption as it exists with expected values
+			return nil, nil
+		}
+	}
+
+	return armresources.GenericResource{
+		Properties: properties,
+	}, nil
+}
+
+// isUpToDate returns whether the properties of an existing policy exemption match the spec.
+func (s *PolicyExemptionSpec) isUpToDate(properties map[string]interface{}, metadata map[string]interface{}) bool {
+	assignmentID, _ := properties["policyAssignmentId"].(string)
+	category, _ := properties["exemptionCategory"].(string)
+	description, _ := properties["description"].(string)
+	if !strings.EqualFold(assignmentID, s.PolicyAssignmentID) || !strings.EqualFold(category, string(s.Category)) || description != s.Justification {
+		return false
+	}
+
+	existingMetadata, _ := properties["metadata"].(map[string]interface{})
+	if !reflect.DeepEqual(existingMetadata, metadata) {
+		return false
+	}
+
+	var referenceIDs []string
+	existingReferenceIDs, _ := properties["policyDefinitionReferenceIds"].([]interface{})
+	for _, id := range existingReferenceIDs {
+		if idString, ok := id.(string); ok {
+			referenceIDs = append(referenceIDs, idString)
+		}
+	}
+	if !sameStrings(referenceIDs, s.PolicyDefinitionReferenceIDs) {
+		return false
+	}
+
+	expiresOn, _ := properties["expiresOn"].(string)
+	if s.ExpiresOn == nil {
+		return expiresOn == ""
+	}
+	existingExpiresOn, err := time.Parse(time.RFC3339, expiresOn)
+	return err == nil && existingExpiresOn.Equal(s.ExpiresOn.Time)
+}
+
+// sameStrings returns whether two lists of strings have the same elements, in any order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
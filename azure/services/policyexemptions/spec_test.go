@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policyexemptions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestResourceID(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(fakePublicIPExemptionSpec.ResourceID()).To(Equal("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Authorization/policyExemptions/allow-public-ip"))
+}
+
+func TestPolicyExemptionParameters(t *testing.T) {
+	spec := PolicyExemptionSpec{
+		Name:                         "allow-public-ip",
+		ResourceGroup:                "my-rg",
+		SubscriptionID:               "123",
+		ClusterName:                  "my-cluster",
+		PolicyAssignmentID:           "/providers/Microsoft.Management/managementGroups/my-mg/providers/Microsoft.Authorization/policyAssignments/network",
+		PolicyDefinitionReferenceIDs: []string{"denyPublicIP", "denyPublicLB"},
+		Category:                     infrav1.PolicyExemptionCategoryWaiver,
+		Justification:                "The API server load balancer needs a public IP.",
+		Metadata:                     map[string]string{"ticket": "SEC-1234"},
+		ExpiresOn:                    &metav1.Time{Time: time.Date(2023, time.January, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	wantProperties := map[string]interface{}{
+		"policyAssignmentId":           "/providers/Microsoft.Management/managementGroups/my-mg/providers/Microsoft.Authorization/policyAssignments/network",
+		"policyDefinitionReferenceIds": []string{"denyPublicIP", "denyPublicLB"},
+		"exemptionCategory":            "Waiver",
+		"description":                  "The API server load balancer needs a public IP.",
+		"metadata": map[string]interface{}{
+			"ticket": "SEC-1234",
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+		},
+		"expiresOn": "2023-01-31T00:00:00Z",
+	}
+
+	testcases := []struct {
+		name     string
+		existing interface{}
+		expect   func(g *WithT, result interface{})
+	}{
+		{
+			name:     "new policy exemption",
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armresources.GenericResource{Properties: wantProperties}))
+			},
+		},
+		{
+			name: "existing policy exemption with the same properties",
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"policyAssignmentId":           "/providers/Microsoft.Management/managementGroups/my-mg/providers/Microsoft.Authorization/policyAssignments/NETWORK",
+					"policyDefinitionReferenceIds": []interface{}{"denyPublicLB", "denyPublicIP"},
+					"exemptionCategory":            "waiver",
+					"description":                  "The API server load balancer needs a public IP.",
+					"metadata": map[string]interface{}{
+						"ticket": "SEC-1234",
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+					},
+					"expiresOn": "2023-01-31T00:00:00+00:00",
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+		},
+		{
+			name: "existing policy exemption with another justification",
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"policyAssignmentId":           "/providers/Microsoft.Management/managementGroups/my-mg/providers/Microsoft.Authorization/policyAssignments/network",
+					"policyDefinitionReferenceIds": []interface{}{"denyPublicIP", "denyPublicLB"},
+					"exemptionCategory":            "Waiver",
+					"description":                  "Temporary.",
+					"metadata": map[string]interface{}{
+						"ticket": "SEC-1234",
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+					},
+					"expiresOn": "2023-01-31T00:00:00Z",
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armresources.GenericResource{Properties: wantProperties}))
+			},
+		},
+		{
+			name: "existing policy exemption without an expiration",
+			existing: armresources.GenericResource{
+				Properties: map[string]interface{}{
+					"policyAssignmentId":           "/providers/Microsoft.Management/managementGroups/my-mg/providers/Microsoft.Authorization/policyAssignments/network",
+					"policyDefinitionReferenceIds": []interface{}{"denyPublicIP", "denyPublicLB"},
+					"exemptionCategory":            "Waiver",
+					"description":                  "The API server load balancer needs a public IP.",
+					"metadata": map[string]interface{}{
+						"ticket": "SEC-1234",
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(armresources.GenericResource{Properties: wantProperties}))
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+
+			result, err := spec.Parameters(tc.existing)
+			g.Expect(err).NotTo(HaveOccurred())
+			tc.expect(g, result)
+		})
+	}
+
+	g := NewWithT(t)
+	_, err := spec.Parameters("not a resource")
+	g.Expect(err).To(MatchError("string is not an armarmresources.GenericResource"))
+}
@@ -17,8 +17,8 @@ limitations under the License.
 package privatedns
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -55,21 +55,21 @@ func (s LinkSpec) ResourceGroupName() string {
 // Parameters returns the parameters for the virtual network link.
 func (s LinkSpec) Parameters(existing interface{}) (params interface{}, err error) {
 	if existing != nil {
-		_, ok := existing.(privatedns.VirtualNetworkLink)
+		_, ok := existing.(armprivatedns.VirtualNetworkLink)
 		if !ok {
-			return nil, errors.Errorf("%T is not a privatedns.VirtualNetworkLink", existing)
+			return nil, errors.Errorf("%T is not an armprivatedns.VirtualNetworkLink", existing)
 		}
 		return nil, nil
 	}
 
-	return privatedns.VirtualNetworkLink{
-		VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
-			VirtualNetwork: &privatedns.SubResource{
-				ID: to.StringPtr(azure.VNetID(s.SubscriptionID, s.VNetResourceGroup, s.VNetName)),
+	return armprivatedns.VirtualNetworkLink{
+		Properties: &armprivatedns.VirtualNetworkLinkProperties{
+			VirtualNetwork: &armprivatedns.SubResource{
+				ID: to.Ptr(azure.VNetID(s.SubscriptionID, s.VNetResourceGroup, s.VNetName)),
 			},
-			RegistrationEnabled: to.BoolPtr(false),
+			RegistrationEnabled: to.Ptr(false),
 		},
-		Location: to.StringPtr(azure.Global),
+		Location: to.Ptr(azure.Global),
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: s.ClusterName,
 			Lifecycle:   infrav1.ResourceLifecycleOwned,
@@ -19,8 +19,8 @@ package privatedns
 import (
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 )
@@ -66,16 +66,16 @@ func TestLinkSpec_Parameters(t *testing.T) {
 			expectedError: "",
 			spec:          linkSpec,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(Equal(privatedns.VirtualNetworkLink{
-					VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
-						VirtualNetwork: &privatedns.SubResource{
-							ID: to.StringPtr("/subscriptions/123/resourceGroups/my-vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"),
+				g.Expect(result).To(Equal(armprivatedns.VirtualNetworkLink{
+					Properties: &armprivatedns.VirtualNetworkLinkProperties{
+						VirtualNetwork: &armprivatedns.SubResource{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/my-vnet-rg/providers/Microsoft.Network/virtualNetworks/my-vnet"),
 						},
-						RegistrationEnabled: to.BoolPtr(false),
+						RegistrationEnabled: to.Ptr(false),
 					},
-					Location: to.StringPtr(azure.Global),
+					Location: to.Ptr(azure.Global),
 					Tags: map[string]*string{
-						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
 					},
 				}))
 			},
@@ -84,8 +84,8 @@ func TestLinkSpec_Parameters(t *testing.T) {
 			name:          "existing managed private virtual network link",
 			expectedError: "",
 			spec:          linkSpec,
-			existing: privatedns.VirtualNetworkLink{Tags: map[string]*string{
-				"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+			existing: armprivatedns.VirtualNetworkLink{Tags: map[string]*string{
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
 			}},
 			expect: func(g *WithT, result interface{}) {
 				g.Expect(result).To(BeNil())
@@ -95,16 +95,16 @@ func TestLinkSpec_Parameters(t *testing.T) {
 			name:          "existing unmanaged private dns zone",
 			expectedError: "",
 			spec:          linkSpec,
-			existing:      privatedns.VirtualNetworkLink{},
+			existing:      armprivatedns.VirtualNetworkLink{},
 			expect: func(g *WithT, result interface{}) {
 				g.Expect(result).To(BeNil())
 			},
 		},
 		{
 			name:          "type cast error",
-			expectedError: "string is not a privatedns.VirtualNetworkLink",
+			expectedError: "string is not an armprivatedns.VirtualNetworkLink",
 			spec:          linkSpec,
-			existing:      "I'm not privatedns.VirtualNetworkLink",
+			existing:      "I'm not armprivatedns.VirtualNetworkLink",
 		},
 	}
 
@@ -19,10 +19,11 @@ package privatedns
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
@@ -50,18 +51,27 @@ type Service struct {
 }
 
 // New creates a new private dns service.
-func New(scope Scope) *Service {
-	zoneClient := newPrivateZonesClient(scope)
-	vnetLinkClient := newVirtualNetworkLinksClient(scope)
-	recordSetsClient := newRecordSetsClient(scope)
+func New(scope Scope) (*Service, error) {
+	zoneClient, err := newPrivateZonesClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	vnetLinkClient, err := newVirtualNetworkLinksClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	recordSetsClient, err := newRecordSetsClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:              scope,
 		zoneGetter:         zoneClient,
 		vnetLinkGetter:     vnetLinkClient,
-		zoneReconciler:     async.New(scope, zoneClient, zoneClient),
-		vnetLinkReconciler: async.New(scope, vnetLinkClient, vnetLinkClient),
-		recordReconciler:   async.New(scope, recordSetsClient, recordSetsClient),
-	}
+		zoneReconciler:     async.NewPollerService(scope, zoneClient, zoneClient),
+		vnetLinkReconciler: async.NewPollerService(scope, vnetLinkClient, vnetLinkClient),
+		recordReconciler:   async.NewPollerService(scope, recordSetsClient, recordSetsClient),
+	}, nil
 }
 
 // Name returns the service name.
@@ -69,6 +79,20 @@ func (s *Service) Name() string {
 	return serviceName
 }
 
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the private DNS zone, links and records.
+func (s *Service) RequiredActions() []string {
+	if zoneSpec, _, _ := s.Scope.PrivateDNSSpec(); zoneSpec == nil {
+		return nil
+	}
+	return []string{
+		"Microsoft.Network/privateDnsZones/read",
+		"Microsoft.Network/privateDnsZones/write",
+		"Microsoft.Network/privateDnsZones/virtualNetworkLinks/write",
+		"Microsoft.Network/privateDnsZones/A/write",
+	}
+}
+
 // Reconcile creates or updates the private zone, links it to the vnet, and creates DNS records.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "privatedns.Service.Reconcile")
@@ -141,15 +165,34 @@ func (s *Service) isVnetLinkManaged(ctx context.Context, spec azure.ResourceSpec
 		return false, err
 	}
 
-	link, ok := result.(privatedns.VirtualNetworkLink)
+	link, ok := result.(armprivatedns.VirtualNetworkLink)
 	if !ok {
-		return false, errors.Errorf("%T is not a privatedns.VirtualNetworkLink", link)
+		return false, errors.Errorf("%T is not an armprivatedns.VirtualNetworkLink", link)
 	}
 
 	tags := converters.MapToTags(link.Tags)
 	return tags.HasOwned(s.Scope.ClusterName()), nil
 }
 
+// ExportResources adds the private DNS zone with its links and records to an ARM template.
+func (s *Service) ExportResources(ctx context.Context, template *armtemplate.Template) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "privatedns.Service.ExportResources")
+	defer done()
+
+	zoneSpec, links, records := s.Scope.PrivateDNSSpec()
+	if zoneSpec == nil {
+		return nil
+	}
+
+	for _, spec := range append(append([]azure.ResourceSpecGetter{zoneSpec}, links...), records...) {
+		if err := template.AddSpec(spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // IsManaged returns true if the private DNS has an owned tag with the cluster name as value,
 // meaning that the DNS lifecycle is managed.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
@@ -162,9 +205,9 @@ func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	zone, ok := result.(privatedns.PrivateZone)
+	zone, ok := result.(armprivatedns.PrivateZone)
 	if !ok {
-		return false, errors.Errorf("%T is not a privatedns.PrivateZone", zone)
+		return false, errors.Errorf("%T is not an armprivatedns.PrivateZone", zone)
 	}
 
 	tags := converters.MapToTags(zone.Tags)
@@ -18,11 +18,12 @@ package privatedns
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
@@ -80,21 +81,21 @@ var (
 		ResourceGroup: resourceGroup,
 	}
 
-	fakeAzurePrivateZoneManaged = privatedns.PrivateZone{Tags: map[string]*string{
-		"sigs.k8s.io_cluster-api-provider-azure_cluster_" + clusterName: to.StringPtr("owned"),
+	fakeAzurePrivateZoneManaged = armprivatedns.PrivateZone{Tags: map[string]*string{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_" + clusterName: to.Ptr("owned"),
 	}}
 
-	fakeAzurePrivateZoneUnmanaged = privatedns.PrivateZone{}
+	fakeAzurePrivateZoneUnmanaged = armprivatedns.PrivateZone{}
 
-	fakeAzureVnetLinkManaged = privatedns.VirtualNetworkLink{Tags: map[string]*string{
-		"sigs.k8s.io_cluster-api-provider-azure_cluster_" + clusterName: to.StringPtr("owned"),
+	fakeAzureVnetLinkManaged = armprivatedns.VirtualNetworkLink{Tags: map[string]*string{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_" + clusterName: to.Ptr("owned"),
 	}}
 
-	fakeAzureVnetLinkUnmanaged = privatedns.VirtualNetworkLink{}
+	fakeAzureVnetLinkUnmanaged = armprivatedns.VirtualNetworkLink{}
 
 	notDoneError  = azure.NewOperationNotDoneError(&infrav1.Future{Type: "resourceType", ResourceGroup: resourceGroup, Name: "resourceName"})
 	errFake       = errors.New("this is an error")
-	notFoundError = autorest.DetailedError{StatusCode: 404}
+	notFoundError = &azcore.ResponseError{StatusCode: http.StatusNotFound}
 )
 
 func TestReconcilePrivateDNS(t *testing.T) {
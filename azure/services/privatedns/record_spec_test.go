@@ -19,8 +19,8 @@ package privatedns
 import (
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
@@ -67,12 +67,12 @@ func TestRecordSpec_Parameters(t *testing.T) {
 			expectedError: "",
 			spec:          recordSpec,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(Equal(privatedns.RecordSet{
-					RecordSetProperties: &privatedns.RecordSetProperties{
-						TTL: to.Int64Ptr(300),
-						ARecords: &[]privatedns.ARecord{
+				g.Expect(result).To(Equal(armprivatedns.RecordSet{
+					Properties: &armprivatedns.RecordSetProperties{
+						TTL: to.Ptr[int64](300),
+						ARecords: []*armprivatedns.ARecord{
 							{
-								Ipv4Address: to.StringPtr("10.0.0.8"),
+								IPv4Address: to.Ptr("10.0.0.8"),
 							},
 						},
 					},
@@ -84,12 +84,12 @@ func TestRecordSpec_Parameters(t *testing.T) {
 			expectedError: "",
 			spec:          recordSpecIpv6,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(Equal(privatedns.RecordSet{
-					RecordSetProperties: &privatedns.RecordSetProperties{
-						TTL: to.Int64Ptr(300),
-						AaaaRecords: &[]privatedns.AaaaRecord{
+				g.Expect(result).To(Equal(armprivatedns.RecordSet{
+					Properties: &armprivatedns.RecordSetProperties{
+						TTL: to.Ptr[int64](300),
+						AaaaRecords: []*armprivatedns.AaaaRecord{
 							{
-								Ipv6Address: to.StringPtr("2603:1030:805:2::b"),
+								IPv6Address: to.Ptr("2603:1030:805:2::b"),
 							},
 						},
 					},
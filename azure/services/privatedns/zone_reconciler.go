@@ -38,10 +38,10 @@ func (s *Service) reconcileZone(ctx context.Context, zoneSpec azure.ResourceSpec
 	}
 
 	if !managed {
-		log.V(1).Info("Skipping reconciliation of unmanaged private DNS zone", "private DNS", zoneSpec.ResourceName())
+		log.V(1).Info("Skipping reconciliation of unmanaged private DNS zone", tele.LogKeyResource, zoneSpec.ResourceName())
 		// TODO: Remove this log in future release. This is only required because older clusters created before https://github.com/kubernetes-sigs/cluster-api-provider-azure/pull/1791 will not have capz ownership tags.
 		log.V(1).Info("Tag the DNS manually from azure to manage it with capz."+
-			"Please see https://capz.sigs.k8s.io/topics/custom-dns.html#manage-dns-via-capz-tool", "private DNS", zoneSpec.ResourceName())
+			"Please see https://capz.sigs.k8s.io/topics/custom-dns.html#manage-dns-via-capz-tool", tele.LogKeyResource, zoneSpec.ResourceName())
 		return managed, nil
 	}
 
@@ -65,7 +65,7 @@ func (s *Service) deleteZone(ctx context.Context, zoneSpec azure.ResourceSpecGet
 	}
 
 	if !isManaged {
-		log.V(1).Info("Skipping deletion of unmanaged private DNS zone", "private DNS", zoneSpec.ResourceName())
+		log.V(1).Info("Skipping deletion of unmanaged private DNS zone", tele.LogKeyResource, zoneSpec.ResourceName())
 		return managed, nil
 	}
 
@@ -17,8 +17,8 @@ limitations under the License.
 package privatedns
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -51,15 +51,15 @@ func (s ZoneSpec) ResourceGroupName() string {
 // Parameters returns the parameters for the private dns zone.
 func (s ZoneSpec) Parameters(existing interface{}) (params interface{}, err error) {
 	if existing != nil {
-		_, ok := existing.(privatedns.PrivateZone)
+		_, ok := existing.(armprivatedns.PrivateZone)
 		if !ok {
-			return nil, errors.Errorf("%T is not a privatedns.PrivateZone", existing)
+			return nil, errors.Errorf("%T is not an armprivatedns.PrivateZone", existing)
 		}
 		return nil, nil
 	}
 
-	return privatedns.PrivateZone{
-		Location: to.StringPtr(azure.Global),
+	return armprivatedns.PrivateZone{
+		Location: to.Ptr(azure.Global),
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: s.ClusterName,
 			Lifecycle:   infrav1.ResourceLifecycleOwned,
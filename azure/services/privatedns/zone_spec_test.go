@@ -19,8 +19,8 @@ package privatedns
 import (
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/privatedns/armprivatedns"
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 )
@@ -62,10 +62,10 @@ func TestZoneSpec_Parameters(t *testing.T) {
 			expectedError: "",
 			spec:          zoneSpec,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(Equal(privatedns.PrivateZone{
-					Location: to.StringPtr(azure.Global),
+				g.Expect(result).To(Equal(armprivatedns.PrivateZone{
+					Location: to.Ptr(azure.Global),
 					Tags: map[string]*string{
-						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
 					},
 				}))
 			},
@@ -74,8 +74,8 @@ func TestZoneSpec_Parameters(t *testing.T) {
 			name:          "existing managed private dns zone",
 			expectedError: "",
 			spec:          zoneSpec,
-			existing: privatedns.PrivateZone{Tags: map[string]*string{
-				"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+			existing: armprivatedns.PrivateZone{Tags: map[string]*string{
+				"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
 			}},
 			expect: func(g *WithT, result interface{}) {
 				g.Expect(result).To(BeNil())
@@ -85,16 +85,16 @@ func TestZoneSpec_Parameters(t *testing.T) {
 			name:          "existing unmanaged private dns zone",
 			expectedError: "",
 			spec:          zoneSpec,
-			existing:      privatedns.PrivateZone{},
+			existing:      armprivatedns.PrivateZone{},
 			expect: func(g *WithT, result interface{}) {
 				g.Expect(result).To(BeNil())
 			},
 		},
 		{
 			name:          "type cast error",
-			expectedError: "string is not a privatedns.PrivateZone",
+			expectedError: "string is not an armprivatedns.PrivateZone",
 			spec:          zoneSpec,
-			existing:      "I'm not privatedns.PrivateZone",
+			existing:      "I'm not armprivatedns.PrivateZone",
 		},
 	}
 
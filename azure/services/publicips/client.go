@@ -19,61 +19,63 @@ package publicips
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // Client wraps go-sdk.
 type Client interface {
-	Get(context.Context, string, string) (network.PublicIPAddress, error)
-	CreateOrUpdate(context.Context, string, string, network.PublicIPAddress) error
+	Get(context.Context, string, string) (armnetwork.PublicIPAddress, error)
+	CreateOrUpdate(context.Context, string, string, armnetwork.PublicIPAddress) error
 	Delete(context.Context, string, string) error
 }
 
 // AzureClient contains the Azure go-sdk Client.
 type AzureClient struct {
-	publicips network.PublicIPAddressesClient
+	publicips *armnetwork.PublicIPAddressesClient
 }
 
 var _ Client = &AzureClient{}
 
 // NewClient creates a new public IP client from subscription ID.
-func NewClient(auth azure.Authorizer) *AzureClient {
-	c := newPublicIPAddressesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
-	return &AzureClient{c}
-}
-
-// newPublicIPAddressesClient creates a new public IP client from subscription ID.
-func newPublicIPAddressesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) network.PublicIPAddressesClient {
-	publicIPsClient := network.NewPublicIPAddressesClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&publicIPsClient.Client, authorizer)
-	return publicIPsClient
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armnetwork.NewPublicIPAddressesClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create public IP addresses client")
+	}
+	return &AzureClient{c}, nil
 }
 
 // Get gets the specified public IP address in a specified resource group.
-func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, ipName string) (network.PublicIPAddress, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, ipName string) (armnetwork.PublicIPAddress, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "publicips.AzureClient.Get")
 	defer done()
 
-	return ac.publicips.Get(ctx, resourceGroupName, ipName, "")
+	resp, err := ac.publicips.Get(ctx, resourceGroupName, ipName, nil)
+	if err != nil {
+		return armnetwork.PublicIPAddress{}, err
+	}
+	return resp.PublicIPAddress, nil
 }
 
 // CreateOrUpdate creates or updates a static or dynamic public IP address.
-func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, ipName string, ip network.PublicIPAddress) error {
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, ipName string, ip armnetwork.PublicIPAddress) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "publicips.AzureClient.CreateOrUpdate")
 	defer done()
 
-	future, err := ac.publicips.CreateOrUpdate(ctx, resourceGroupName, ipName, ip)
-	if err != nil {
-		return err
-	}
-	err = future.WaitForCompletionRef(ctx, ac.publicips.Client)
+	poller, err := ac.publicips.BeginCreateOrUpdate(ctx, resourceGroupName, ipName, ip, nil)
 	if err != nil {
 		return err
 	}
-	_, err = future.Result(ac.publicips)
+	_, err = poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
 	return err
 }
 
@@ -82,14 +84,10 @@ func (ac *AzureClient) Delete(ctx context.Context, resourceGroupName, ipName str
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "publicips.AzureClient.Delete")
 	defer done()
 
-	future, err := ac.publicips.Delete(ctx, resourceGroupName, ipName)
-	if err != nil {
-		return err
-	}
-	err = future.WaitForCompletionRef(ctx, ac.publicips.Client)
+	poller, err := ac.publicips.BeginDelete(ctx, resourceGroupName, ipName, nil)
 	if err != nil {
 		return err
 	}
-	_, err = future.Result(ac.publicips)
+	_, err = poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
 	return err
 }
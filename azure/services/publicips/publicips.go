@@ -20,11 +20,12 @@ import (
 	"context"
 	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -44,11 +45,15 @@ type Service struct {
 }
 
 // New creates a new service.
-func New(scope PublicIPScope) *Service {
+func New(scope PublicIPScope) (*Service, error) {
+	client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:  scope,
-		Client: NewClient(scope),
-	}
+		Client: client,
+	}, nil
 }
 
 // Name returns the service name.
@@ -56,62 +61,147 @@ func (s *Service) Name() string {
 	return serviceName
 }
 
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the public IPs.
+func (s *Service) RequiredActions() []string {
+	if len(s.Scope.PublicIPSpecs()) == 0 {
+		return nil
+	}
+	return []string{"Microsoft.Network/publicIPAddresses/read", "Microsoft.Network/publicIPAddresses/write"}
+}
+
 // Reconcile gets/creates/updates a public ip.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "publicips.Service.Reconcile")
 	defer done()
 
 	for _, ip := range s.Scope.PublicIPSpecs() {
-		log.V(2).Info("creating public IP", "public ip", ip.Name)
+		log.V(2).Info("creating public IP", tele.LogKeyResource, ip.Name)
 
-		// only set DNS properties if there is a DNS name specified
-		addressVersion := network.IPVersionIPv4
-		if ip.IsIPv6 {
-			addressVersion = network.IPVersionIPv6
+		params, err := s.parameters(ip)
+		if err != nil {
+			return err
 		}
 
-		// only set DNS properties if there is a DNS name specified
-		var dnsSettings *network.PublicIPAddressDNSSettings
-		if ip.DNSName != "" {
-			dnsSettings = &network.PublicIPAddressDNSSettings{
-				DomainNameLabel: to.StringPtr(strings.Split(ip.DNSName, ".")[0]),
-				Fqdn:            to.StringPtr(ip.DNSName),
-			}
+		if err := s.Client.CreateOrUpdate(ctx, s.Scope.ResourceGroup(), ip.Name, params); err != nil {
+			return errors.Wrap(err, "cannot create public IP")
 		}
 
-		err := s.Client.CreateOrUpdate(
-			ctx,
-			s.Scope.ResourceGroup(),
-			ip.Name,
-			network.PublicIPAddress{
-				Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
-					ClusterName: s.Scope.ClusterName(),
-					Lifecycle:   infrav1.ResourceLifecycleOwned,
-					Name:        to.StringPtr(ip.Name),
-					Additional:  s.Scope.AdditionalTags(),
-				})),
-				Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
-				Name:     to.StringPtr(ip.Name),
-				Location: to.StringPtr(s.Scope.Location()),
-				PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
-					PublicIPAddressVersion:   addressVersion,
-					PublicIPAllocationMethod: network.IPAllocationMethodStatic,
-					DNSSettings:              dnsSettings,
-				},
-				Zones: to.StringSlicePtr(s.Scope.FailureDomains()),
-			},
-		)
+		if tracker, ok := s.Scope.(azure.ResourceTracker); ok {
+			tracker.SetResourceStatus(azure.PublicIPID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), ip.Name), "Microsoft.Network/publicIPAddresses", serviceName)
+		}
+		log.V(2).Info("successfully created public IP", tele.LogKeyResource, ip.Name)
+	}
 
+	return nil
+}
+
+// ExportResources adds the public IPs to an ARM template.
+func (s *Service) ExportResources(ctx context.Context, template *armtemplate.Template) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "publicips.Service.ExportResources")
+	defer done()
+
+	for _, ip := range s.Scope.PublicIPSpecs() {
+		params, err := s.parameters(ip)
 		if err != nil {
-			return errors.Wrap(err, "cannot create public IP")
+			return err
+		}
+		if err := template.AddResource("Microsoft.Network/publicIPAddresses", armtemplate.NetworkAPIVersion, ip.Name, params); err != nil {
+			return err
 		}
-
-		log.V(2).Info("successfully created public IP", "public ip", ip.Name)
 	}
 
 	return nil
 }
 
+// parameters returns the desired parameters of a public IP.
+func (s *Service) parameters(ip azure.PublicIPSpec) (armnetwork.PublicIPAddress, error) {
+	addressVersion := armnetwork.IPVersionIPv4
+	if ip.IsIPv6 {
+		addressVersion = armnetwork.IPVersionIPv6
+	}
+
+	zones, err := s.getZones(ip)
+	if err != nil {
+		return armnetwork.PublicIPAddress{}, err
+	}
+
+	// only set DNS properties if there is a DNS name specified
+	var dnsSettings *armnetwork.PublicIPAddressDNSSettings
+	if ip.DNSName != "" {
+		dnsSettings = &armnetwork.PublicIPAddressDNSSettings{
+			DomainNameLabel: to.Ptr(strings.Split(ip.DNSName, ".")[0]),
+			Fqdn:            to.Ptr(ip.DNSName),
+		}
+	}
+
+	sku := &armnetwork.PublicIPAddressSKU{Name: to.Ptr(armnetwork.PublicIPAddressSKUNameStandard)}
+	location := s.Scope.Location()
+	extendedLocation := converters.ExtendedLocationToNetworkSDK(s.Scope.ExtendedLocation())
+	if ip.Tier == infrav1.SKUTierGlobal {
+		// Global tier public IPs live in the home region of their cross-region load balancer.
+		sku.Tier = to.Ptr(armnetwork.PublicIPAddressSKUTierGlobal)
+		location = ip.Location
+		extendedLocation = nil
+	}
+
+	// the public IP of a machine is deleted by Azure along with its virtual machine.
+	var deleteOption *armnetwork.DeleteOptions
+	if ip.DeleteWithVM {
+		deleteOption = to.Ptr(armnetwork.DeleteOptionsDelete)
+	}
+
+	additionalTags := make(infrav1.Tags)
+	additionalTags.Merge(s.Scope.AdditionalTags())
+	additionalTags.Merge(ip.AdditionalTags)
+
+	return armnetwork.PublicIPAddress{
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.Scope.ClusterName(),
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.Ptr(ip.Name),
+			Additional:  additionalTags,
+		})),
+		SKU:              sku,
+		Name:             to.Ptr(ip.Name),
+		Location:         to.Ptr(location),
+		ExtendedLocation: extendedLocation,
+		Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+			PublicIPAddressVersion:   to.Ptr(addressVersion),
+			PublicIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
+			DNSSettings:              dnsSettings,
+			DeleteOption:             deleteOption,
+		},
+		Zones: zones,
+	}, nil
+}
+
+// getZones returns the availability zones of a public IP, after checking the location of the cluster supports them.
+// Public IPs without explicit availability are zone-redundant in locations with availability zones, and Global tier
+// public IPs have no zones.
+func (s *Service) getZones(ip azure.PublicIPSpec) ([]*string, error) {
+	if ip.Tier == infrav1.SKUTierGlobal {
+		return nil, nil
+	}
+	failureDomains := s.Scope.FailureDomains()
+	switch ip.Availability {
+	case infrav1.PublicIPAvailabilityNoZone:
+		return nil, nil
+	case infrav1.PublicIPAvailabilityZoneRedundant:
+		if len(failureDomains) == 0 {
+			return nil, errors.Errorf("public IP %s can't be zone-redundant as location %s has no availability zones", ip.Name, s.Scope.Location())
+		}
+	case infrav1.PublicIPAvailabilityZonal:
+		for _, fd := range failureDomains {
+			if fd == ip.Zone {
+				return []*string{to.Ptr(ip.Zone)}, nil
+			}
+		}
+		return nil, errors.Errorf("public IP %s can't be in zone %s as it isn't an availability zone of location %s", ip.Name, ip.Zone, s.Scope.Location())
+	}
+	return to.SliceOfPtrs(failureDomains...), nil
+}
+
 // Delete deletes the public IP with the provided scope.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "publicips.Service.Delete")
@@ -124,21 +214,24 @@ func (s *Service) Delete(ctx context.Context) error {
 		}
 
 		if !managed {
-			log.V(2).Info("Skipping IP deletion for unmanaged public IP", "public ip", ip.Name)
+			log.V(2).Info("Skipping IP deletion for unmanaged public IP", tele.LogKeyResource, ip.Name)
 			continue
 		}
 
-		log.V(2).Info("deleting public IP", "public ip", ip.Name)
+		log.V(2).Info("deleting public IP", tele.LogKeyResource, ip.Name)
 		err = s.Client.Delete(ctx, s.Scope.ResourceGroup(), ip.Name)
-		if err != nil && azure.ResourceNotFound(err) {
-			// already deleted
-			continue
+		if err != nil && !azure.ResourceNotFound(err) {
+			return errors.Wrapf(err, "failed to delete public IP %s in resource group %s", ip.Name, s.Scope.ResourceGroup())
+		}
+		if tracker, ok := s.Scope.(azure.ResourceTracker); ok {
+			tracker.DeleteResourceStatus(ip.Name, serviceName)
 		}
 		if err != nil {
-			return errors.Wrapf(err, "failed to delete public IP %s in resource group %s", ip.Name, s.Scope.ResourceGroup())
+			// already deleted
+			continue
 		}
 
-		log.V(2).Info("deleted public IP", "public ip", ip.Name)
+		log.V(2).Info("deleted public IP", tele.LogKeyResource, ip.Name)
 	}
 	return nil
 }
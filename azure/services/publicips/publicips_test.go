@@ -21,19 +21,25 @@ import (
 	"net/http"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"k8s.io/client-go/kubernetes/scheme"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips/mock_publicips"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+var (
+	internalError = test.NewResponseError(http.StatusInternalServerError)
+	notFoundError = test.NewResponseError(http.StatusNotFound)
+)
+
 func init() {
 	_ = clusterv1.AddToScheme(scheme.Scheme)
 }
@@ -70,82 +76,287 @@ func TestReconcilePublicIP(t *testing.T) {
 				s.ClusterName().AnyTimes().Return("my-cluster")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
 				s.Location().AnyTimes().Return("testlocation")
+				s.ExtendedLocation().AnyTimes().Return(nil)
 				s.FailureDomains().AnyTimes().Return([]string{"1,2,3"})
 				gomock.InOrder(
-					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip", gomockinternal.DiffEq(network.PublicIPAddress{
-						Name:     to.StringPtr("my-publicip"),
-						Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
-						Location: to.StringPtr("testlocation"),
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip", gomockinternal.DiffEq(armnetwork.PublicIPAddress{
+						Name:     to.Ptr("my-publicip"),
+						SKU:      &armnetwork.PublicIPAddressSKU{Name: to.Ptr(armnetwork.PublicIPAddressSKUNameStandard)},
+						Location: to.Ptr("testlocation"),
 						Tags: map[string]*string{
-							"Name": to.StringPtr("my-publicip"),
-							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+							"Name": to.Ptr("my-publicip"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
 						},
-						PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
-							PublicIPAddressVersion:   network.IPVersionIPv4,
-							PublicIPAllocationMethod: network.IPAllocationMethodStatic,
-							DNSSettings: &network.PublicIPAddressDNSSettings{
-								DomainNameLabel: to.StringPtr("fakedns"),
-								Fqdn:            to.StringPtr("fakedns.mydomain.io"),
+						Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+							PublicIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv4),
+							PublicIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
+							DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
+								DomainNameLabel: to.Ptr("fakedns"),
+								Fqdn:            to.Ptr("fakedns.mydomain.io"),
 							},
 						},
-						Zones: to.StringSlicePtr([]string{"1,2,3"}),
+						Zones: to.SliceOfPtrs("1,2,3"),
 					})).Times(1),
-					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip-2", gomockinternal.DiffEq(network.PublicIPAddress{
-						Name:     to.StringPtr("my-publicip-2"),
-						Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
-						Location: to.StringPtr("testlocation"),
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip-2", gomockinternal.DiffEq(armnetwork.PublicIPAddress{
+						Name:     to.Ptr("my-publicip-2"),
+						SKU:      &armnetwork.PublicIPAddressSKU{Name: to.Ptr(armnetwork.PublicIPAddressSKUNameStandard)},
+						Location: to.Ptr("testlocation"),
 						Tags: map[string]*string{
-							"Name": to.StringPtr("my-publicip-2"),
-							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+							"Name": to.Ptr("my-publicip-2"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
 						},
-						PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
-							PublicIPAddressVersion:   network.IPVersionIPv4,
-							PublicIPAllocationMethod: network.IPAllocationMethodStatic,
-							DNSSettings: &network.PublicIPAddressDNSSettings{
-								DomainNameLabel: to.StringPtr("fakedns2-52959"),
-								Fqdn:            to.StringPtr("fakedns2-52959.uksouth.cloudapp.azure.com"),
+						Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+							PublicIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv4),
+							PublicIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
+							DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
+								DomainNameLabel: to.Ptr("fakedns2-52959"),
+								Fqdn:            to.Ptr("fakedns2-52959.uksouth.cloudapp.azure.com"),
 							},
 						},
-						Zones: to.StringSlicePtr([]string{"1,2,3"}),
+						Zones: to.SliceOfPtrs("1,2,3"),
 					})).Times(1),
-					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip-3", gomockinternal.DiffEq(network.PublicIPAddress{
-						Name:     to.StringPtr("my-publicip-3"),
-						Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
-						Location: to.StringPtr("testlocation"),
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip-3", gomockinternal.DiffEq(armnetwork.PublicIPAddress{
+						Name:     to.Ptr("my-publicip-3"),
+						SKU:      &armnetwork.PublicIPAddressSKU{Name: to.Ptr(armnetwork.PublicIPAddressSKUNameStandard)},
+						Location: to.Ptr("testlocation"),
 						Tags: map[string]*string{
-							"Name": to.StringPtr("my-publicip-3"),
-							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+							"Name": to.Ptr("my-publicip-3"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
 						},
-						PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
-							PublicIPAddressVersion:   network.IPVersionIPv4,
-							PublicIPAllocationMethod: network.IPAllocationMethodStatic,
+						Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+							PublicIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv4),
+							PublicIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
 						},
-						Zones: to.StringSlicePtr([]string{"1,2,3"}),
+						Zones: to.SliceOfPtrs("1,2,3"),
 					})).Times(1),
-					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip-ipv6", gomockinternal.DiffEq(network.PublicIPAddress{
-						Name:     to.StringPtr("my-publicip-ipv6"),
-						Sku:      &network.PublicIPAddressSku{Name: network.PublicIPAddressSkuNameStandard},
-						Location: to.StringPtr("testlocation"),
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip-ipv6", gomockinternal.DiffEq(armnetwork.PublicIPAddress{
+						Name:     to.Ptr("my-publicip-ipv6"),
+						SKU:      &armnetwork.PublicIPAddressSKU{Name: to.Ptr(armnetwork.PublicIPAddressSKUNameStandard)},
+						Location: to.Ptr("testlocation"),
 						Tags: map[string]*string{
-							"Name": to.StringPtr("my-publicip-ipv6"),
-							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
+							"Name": to.Ptr("my-publicip-ipv6"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
 						},
-						PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
-							PublicIPAddressVersion:   network.IPVersionIPv6,
-							PublicIPAllocationMethod: network.IPAllocationMethodStatic,
-							DNSSettings: &network.PublicIPAddressDNSSettings{
-								DomainNameLabel: to.StringPtr("fakename"),
-								Fqdn:            to.StringPtr("fakename.mydomain.io"),
+						Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+							PublicIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv6),
+							PublicIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
+							DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
+								DomainNameLabel: to.Ptr("fakename"),
+								Fqdn:            to.Ptr("fakename.mydomain.io"),
 							},
 						},
-						Zones: to.StringSlicePtr([]string{"1,2,3"}),
+						Zones: to.SliceOfPtrs("1,2,3"),
+					})).Times(1),
+				)
+			},
+		},
+		{
+			name:          "can create zonal, zone-redundant and no-zone public IPs",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:         "my-publicip-zonal",
+						Availability: infrav1.PublicIPAvailabilityZonal,
+						Zone:         "2",
+					},
+					{
+						Name:         "my-publicip-redundant",
+						Availability: infrav1.PublicIPAvailabilityZoneRedundant,
+					},
+					{
+						Name:         "my-publicip-nozone",
+						Availability: infrav1.PublicIPAvailabilityNoZone,
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Location().AnyTimes().Return("testlocation")
+				s.ExtendedLocation().AnyTimes().Return(nil)
+				s.FailureDomains().AnyTimes().Return([]string{"1", "2", "3"})
+				gomock.InOrder(
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip-zonal", gomockinternal.DiffEq(armnetwork.PublicIPAddress{
+						Name:     to.Ptr("my-publicip-zonal"),
+						SKU:      &armnetwork.PublicIPAddressSKU{Name: to.Ptr(armnetwork.PublicIPAddressSKUNameStandard)},
+						Location: to.Ptr("testlocation"),
+						Tags: map[string]*string{
+							"Name": to.Ptr("my-publicip-zonal"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+						},
+						Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+							PublicIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv4),
+							PublicIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
+						},
+						Zones: to.SliceOfPtrs("2"),
+					})).Times(1),
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip-redundant", gomockinternal.DiffEq(armnetwork.PublicIPAddress{
+						Name:     to.Ptr("my-publicip-redundant"),
+						SKU:      &armnetwork.PublicIPAddressSKU{Name: to.Ptr(armnetwork.PublicIPAddressSKUNameStandard)},
+						Location: to.Ptr("testlocation"),
+						Tags: map[string]*string{
+							"Name": to.Ptr("my-publicip-redundant"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+						},
+						Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+							PublicIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv4),
+							PublicIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
+						},
+						Zones: to.SliceOfPtrs("1", "2", "3"),
+					})).Times(1),
+					m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip-nozone", gomockinternal.DiffEq(armnetwork.PublicIPAddress{
+						Name:     to.Ptr("my-publicip-nozone"),
+						SKU:      &armnetwork.PublicIPAddressSKU{Name: to.Ptr(armnetwork.PublicIPAddressSKUNameStandard)},
+						Location: to.Ptr("testlocation"),
+						Tags: map[string]*string{
+							"Name": to.Ptr("my-publicip-nozone"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+						},
+						Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+							PublicIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv4),
+							PublicIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
+						},
 					})).Times(1),
 				)
 			},
 		},
+		{
+			name:          "can create a global tier public IP in the home region of its cross-region load balancer",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:     "my-publicip-global",
+						DNSName:  "fakedns-global.mydomain.io",
+						Tier:     infrav1.SKUTierGlobal,
+						Location: "eastus2",
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Location().AnyTimes().Return("testlocation")
+				s.ExtendedLocation().AnyTimes().Return(&infrav1.ExtendedLocationSpec{Name: "edge", Type: "EdgeZone"})
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip-global", gomockinternal.DiffEq(armnetwork.PublicIPAddress{
+					Name:     to.Ptr("my-publicip-global"),
+					SKU:      &armnetwork.PublicIPAddressSKU{Name: to.Ptr(armnetwork.PublicIPAddressSKUNameStandard), Tier: to.Ptr(armnetwork.PublicIPAddressSKUTierGlobal)},
+					Location: to.Ptr("eastus2"),
+					Tags: map[string]*string{
+						"Name": to.Ptr("my-publicip-global"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+					},
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						PublicIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv4),
+						PublicIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
+						DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
+							DomainNameLabel: to.Ptr("fakedns-global"),
+							Fqdn:            to.Ptr("fakedns-global.mydomain.io"),
+						},
+					},
+				})).Times(1)
+			},
+		},
+		{
+			name:          "can create a public IP with additional tags",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:           "my-publicip",
+						AdditionalTags: infrav1.Tags{"costcenter": "network"},
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{"costcenter": "compute", "team": "platform"})
+				s.Location().AnyTimes().Return("testlocation")
+				s.ExtendedLocation().AnyTimes().Return(nil)
+				s.FailureDomains().AnyTimes().Return([]string{})
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip", gomockinternal.DiffEq(armnetwork.PublicIPAddress{
+					Name:     to.Ptr("my-publicip"),
+					SKU:      &armnetwork.PublicIPAddressSKU{Name: to.Ptr(armnetwork.PublicIPAddressSKUNameStandard)},
+					Location: to.Ptr("testlocation"),
+					Tags: map[string]*string{
+						"Name":       to.Ptr("my-publicip"),
+						"costcenter": to.Ptr("network"),
+						"team":       to.Ptr("platform"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+					},
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						PublicIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv4),
+						PublicIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
+					},
+					Zones: []*string{},
+				})).Times(1)
+			},
+		},
+		{
+			name:          "can create the public IP of a machine, deleted with its virtual machine",
+			expectedError: "",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:         "pip-my-vm",
+						DeleteWithVM: true,
+					},
+				})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+				s.Location().AnyTimes().Return("testlocation")
+				s.ExtendedLocation().AnyTimes().Return(nil)
+				s.FailureDomains().AnyTimes().Return([]string{})
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "pip-my-vm", gomockinternal.DiffEq(armnetwork.PublicIPAddress{
+					Name:     to.Ptr("pip-my-vm"),
+					SKU:      &armnetwork.PublicIPAddressSKU{Name: to.Ptr(armnetwork.PublicIPAddressSKUNameStandard)},
+					Location: to.Ptr("testlocation"),
+					Tags: map[string]*string{
+						"Name": to.Ptr("pip-my-vm"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+					},
+					Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+						PublicIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv4),
+						PublicIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
+						DeleteOption:             to.Ptr(armnetwork.DeleteOptionsDelete),
+					},
+					Zones: []*string{},
+				})).Times(1)
+			},
+		},
+		{
+			name:          "fail to create a zone-redundant public IP in a location without availability zones",
+			expectedError: "public IP my-publicip can't be zone-redundant as location testlocation has no availability zones",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:         "my-publicip",
+						Availability: infrav1.PublicIPAvailabilityZoneRedundant,
+					},
+				})
+				s.Location().AnyTimes().Return("testlocation")
+				s.ExtendedLocation().AnyTimes().Return(nil)
+				s.FailureDomains().Return([]string{})
+			},
+		},
+		{
+			name:          "fail to create a zonal public IP in a zone the location doesn't have",
+			expectedError: "public IP my-publicip can't be in zone 3 as it isn't an availability zone of location testlocation",
+			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
+				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
+					{
+						Name:         "my-publicip",
+						Availability: infrav1.PublicIPAvailabilityZonal,
+						Zone:         "3",
+					},
+				})
+				s.Location().AnyTimes().Return("testlocation")
+				s.ExtendedLocation().AnyTimes().Return(nil)
+				s.FailureDomains().Return([]string{"1", "2"})
+			},
+		},
 		{
 			name:          "fail to create a public IP",
-			expectedError: "cannot create public IP: #: Internal Server Error: StatusCode=500",
+			expectedError: "cannot create public IP: " + internalError.Error(),
 			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
 				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
 					{
@@ -157,8 +368,9 @@ func TestReconcilePublicIP(t *testing.T) {
 				s.ClusterName().AnyTimes().Return("my-cluster")
 				s.AdditionalTags().AnyTimes().Return(infrav1.Tags{})
 				s.Location().AnyTimes().Return("testlocation")
+				s.ExtendedLocation().AnyTimes().Return(nil)
 				s.FailureDomains().Times(1)
-				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip", gomock.AssignableToTypeOf(network.PublicIPAddress{})).Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-publicip", gomock.AssignableToTypeOf(armnetwork.PublicIPAddress{})).Return(internalError)
 			},
 		},
 	}
@@ -212,19 +424,19 @@ func TestDeletePublicIP(t *testing.T) {
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().AnyTimes().Return("my-cluster")
-				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
-					Name: to.StringPtr("my-publicip"),
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(armnetwork.PublicIPAddress{
+					Name: to.Ptr("my-publicip"),
 					Tags: map[string]*string{
-						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
-						"foo": to.StringPtr("bar"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+						"foo": to.Ptr("bar"),
 					},
 				}, nil)
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-publicip")
-				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip-2").Return(network.PublicIPAddress{
-					Name: to.StringPtr("my-publicip-2"),
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip-2").Return(armnetwork.PublicIPAddress{
+					Name: to.Ptr("my-publicip-2"),
 					Tags: map[string]*string{
-						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
-						"foo": to.StringPtr("buzz"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+						"foo": to.Ptr("buzz"),
 					},
 				}, nil)
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-publicip-2")
@@ -244,12 +456,12 @@ func TestDeletePublicIP(t *testing.T) {
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().AnyTimes().Return("my-cluster")
-				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
-				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip-2").Return(network.PublicIPAddress{
-					Name: to.StringPtr("my-public-ip-2"),
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(armnetwork.PublicIPAddress{}, notFoundError)
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip-2").Return(armnetwork.PublicIPAddress{
+					Name: to.Ptr("my-public-ip-2"),
 					Tags: map[string]*string{
-						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
-						"foo": to.StringPtr("buzz"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+						"foo": to.Ptr("buzz"),
 					},
 				}, nil)
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-publicip-2")
@@ -257,7 +469,7 @@ func TestDeletePublicIP(t *testing.T) {
 		},
 		{
 			name:          "public ip deletion fails",
-			expectedError: "failed to delete public IP my-publicip in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expectedError: "failed to delete public IP my-publicip in resource group my-rg: " + internalError.Error(),
 			expect: func(s *mock_publicips.MockPublicIPScopeMockRecorder, m *mock_publicips.MockClientMockRecorder) {
 				s.PublicIPSpecs().Return([]azure.PublicIPSpec{
 					{
@@ -266,15 +478,15 @@ func TestDeletePublicIP(t *testing.T) {
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().AnyTimes().Return("my-cluster")
-				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
-					Name: to.StringPtr("my-publicip"),
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(armnetwork.PublicIPAddress{
+					Name: to.Ptr("my-publicip"),
 					Tags: map[string]*string{
-						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
-						"foo": to.StringPtr("bar"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+						"foo": to.Ptr("bar"),
 					},
 				}, nil)
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-publicip").
-					Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+					Return(internalError)
 			},
 		},
 		{
@@ -291,17 +503,17 @@ func TestDeletePublicIP(t *testing.T) {
 				})
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.ClusterName().AnyTimes().Return("my-cluster")
-				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(network.PublicIPAddress{
-					Name: to.StringPtr("my-public-ip"),
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip").Return(armnetwork.PublicIPAddress{
+					Name: to.Ptr("my-public-ip"),
 					Tags: map[string]*string{
-						"foo": to.StringPtr("bar"),
+						"foo": to.Ptr("bar"),
 					},
 				}, nil)
-				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip-2").Return(network.PublicIPAddress{
-					Name: to.StringPtr("my-publicip-2"),
+				m.Get(gomockinternal.AContext(), "my-rg", "my-publicip-2").Return(armnetwork.PublicIPAddress{
+					Name: to.Ptr("my-publicip-2"),
 					Tags: map[string]*string{
-						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
-						"foo": to.StringPtr("buzz"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+						"foo": to.Ptr("buzz"),
 					},
 				}, nil)
 				m.Delete(gomockinternal.AContext(), "my-rg", "my-publicip-2")
@@ -337,3 +549,53 @@ func TestDeletePublicIP(t *testing.T) {
 		})
 	}
 }
+
+func TestExportPublicIPs(t *testing.T) {
+	g := NewWithT(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_publicips.NewMockPublicIPScope(mockCtrl)
+	clientMock := mock_publicips.NewMockClient(mockCtrl)
+
+	scopeMock.EXPECT().PublicIPSpecs().Return([]azure.PublicIPSpec{
+		{
+			Name:    "my-publicip",
+			DNSName: "fakedns.mydomain.io",
+		},
+	})
+	scopeMock.EXPECT().ClusterName().AnyTimes().Return("my-cluster")
+	scopeMock.EXPECT().AdditionalTags().AnyTimes().Return(infrav1.Tags{})
+	scopeMock.EXPECT().Location().AnyTimes().Return("testlocation")
+	scopeMock.EXPECT().ExtendedLocation().AnyTimes().Return(nil)
+	scopeMock.EXPECT().FailureDomains().AnyTimes().Return([]string{"1", "2", "3"})
+
+	s := &Service{
+		Scope:  scopeMock,
+		Client: clientMock,
+	}
+
+	template := armtemplate.New("my-rg")
+	g.Expect(s.ExportResources(context.TODO(), template)).To(Succeed())
+	g.Expect(template.Resources).To(HaveLen(1))
+	g.Expect(template.Resources[0]).To(Equal(armtemplate.Resource{
+		"type":       "Microsoft.Network/publicIPAddresses",
+		"apiVersion": armtemplate.NetworkAPIVersion,
+		"name":       "my-publicip",
+		"location":   "testlocation",
+		"sku":        map[string]interface{}{"name": "Standard"},
+		"tags": map[string]interface{}{
+			"Name": "my-publicip",
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": "owned",
+		},
+		"properties": map[string]interface{}{
+			"publicIPAddressVersion":   "IPv4",
+			"publicIPAllocationMethod": "Static",
+			"dnsSettings": map[string]interface{}{
+				"domainNameLabel": "fakedns",
+				"fqdn":            "fakedns.mydomain.io",
+			},
+		},
+		"zones": []interface{}{"1", "2", "3"},
+	}))
+}
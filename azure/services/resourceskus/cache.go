@@ -24,8 +24,9 @@ import (
 	"sync"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -44,7 +45,7 @@ type Cache struct {
 
 	// data is the cached sku information from Azure.
 	// synchronization required if data is cached across reconcile calls, (i.e., refreshed in background as Runnable via mgr.Add(...))
-	data []compute.ResourceSku
+	data []armcompute.ResourceSKU
 }
 
 // Cacher describes the ability to get and to add items to cache.
@@ -63,11 +64,15 @@ var (
 )
 
 // newCache instantiates a cache and initializes its contents.
-func newCache(auth azure.Authorizer, location string) *Cache {
+func newCache(auth azure.Authorizer, location string) (*Cache, error) {
+	client, err := NewClient(auth)
+	if err != nil {
+		return nil, err
+	}
 	return &Cache{
-		client:   NewClient(auth),
+		client:   client,
 		location: location,
-	}
+	}, nil
 }
 
 // GetCache either creates a new SKUs cache or returns an existing one based on the location + Authorizer HashKey().
@@ -87,13 +92,16 @@ func GetCache(auth azure.Authorizer, location string) (*Cache, error) {
 		return c.(*Cache), nil
 	}
 
-	c = newCache(auth, location)
+	c, err = newCache(auth, location)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating resourceSKUs cache")
+	}
 	_ = clientCache.Add(key, c)
 	return c.(*Cache), nil
 }
 
 // NewStaticCache initializes a cache with data and no ability to refresh. Used for testing.
-func NewStaticCache(data []compute.ResourceSku, location string) *Cache {
+func NewStaticCache(data []armcompute.ResourceSKU, location string) *Cache {
 	return &Cache{
 		data:     data,
 		location: location,
@@ -168,27 +176,35 @@ func (c *Cache) GetZones(ctx context.Context, location string) ([]string, error)
 		// Look for VMs only
 		if sku.ResourceType != nil && strings.EqualFold(*sku.ResourceType, string(VirtualMachines)) {
 			// find matching location
-			for _, locationInfo := range *sku.LocationInfo {
-				if strings.EqualFold(*locationInfo.Location, location) {
+			for _, locationInfo := range sku.LocationInfo {
+				if locationInfo != nil && strings.EqualFold(pointer.StringDeref(locationInfo.Location, ""), location) {
 					// Use map for easy deletion and iteration
 					availableZones := make(map[string]bool)
 
 					// add all zones
-					for _, zone := range *locationInfo.Zones {
-						availableZones[zone] = true
+					for _, zone := range locationInfo.Zones {
+						if zone != nil {
+							availableZones[*zone] = true
+						}
 					}
 
-					if sku.Restrictions != nil {
-						for _, restriction := range *sku.Restrictions {
-							// Can't deploy anything in this subscription in this location. Bail out.
-							if restriction.Type == compute.ResourceSkuRestrictionsTypeLocation {
-								availableZones = nil
-								break
-							}
+					for _, restriction := range sku.Restrictions {
+						if restriction == nil {
+							continue
+						}
+
+						// Can't deploy anything in this subscription in this location. Bail out.
+						if restriction.Type != nil && *restriction.Type == armcompute.ResourceSKURestrictionsTypeLocation {
+							availableZones = nil
+							break
+						}
 
-							// remove restricted zones
-							for _, restrictedZone := range *restriction.RestrictionInfo.Zones {
-								delete(availableZones, restrictedZone)
+						// remove restricted zones
+						if restriction.RestrictionInfo != nil {
+							for _, restrictedZone := range restriction.RestrictionInfo.Zones {
+								if restrictedZone != nil {
+									delete(availableZones, *restrictedZone)
+								}
 							}
 						}
 					}
@@ -229,27 +245,35 @@ func (c *Cache) GetZonesWithVMSize(ctx context.Context, size, location string) (
 	mapFn := func(sku SKU) {
 		if sku.Name != nil && strings.EqualFold(*sku.Name, size) && sku.ResourceType != nil && strings.EqualFold(*sku.ResourceType, string(VirtualMachines)) {
 			// find matching location
-			for _, locationInfo := range *sku.LocationInfo {
-				if strings.EqualFold(*locationInfo.Location, location) {
+			for _, locationInfo := range sku.LocationInfo {
+				if locationInfo != nil && strings.EqualFold(pointer.StringDeref(locationInfo.Location, ""), location) {
 					// Use map for easy deletion and iteration
 					availableZones := make(map[string]bool)
 
 					// add all zones
-					for _, zone := range *locationInfo.Zones {
-						availableZones[zone] = true
+					for _, zone := range locationInfo.Zones {
+						if zone != nil {
+							availableZones[*zone] = true
+						}
 					}
 
-					if sku.Restrictions != nil {
-						for _, restriction := range *sku.Restrictions {
-							// Can't deploy anything in this subscription in this location. Bail out.
-							if restriction.Type == compute.ResourceSkuRestrictionsTypeLocation {
-								availableZones = nil
-								break
-							}
+					for _, restriction := range sku.Restrictions {
+						if restriction == nil {
+							continue
+						}
+
+						// Can't deploy anything in this subscription in this location. Bail out.
+						if restriction.Type != nil && *restriction.Type == armcompute.ResourceSKURestrictionsTypeLocation {
+							availableZones = nil
+							break
+						}
 
-							// remove restricted zones
-							for _, restrictedZone := range *restriction.RestrictionInfo.Zones {
-								delete(availableZones, restrictedZone)
+						// remove restricted zones
+						if restriction.RestrictionInfo != nil {
+							for _, restrictedZone := range restriction.RestrictionInfo.Zones {
+								if restrictedZone != nil {
+									delete(availableZones, *restrictedZone)
+								}
 							}
 						}
 					}
@@ -20,8 +20,8 @@ import (
 	"context"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 )
@@ -31,21 +31,21 @@ func TestCacheGet(t *testing.T) {
 		sku          string
 		location     string
 		resourceType ResourceType
-		have         []compute.ResourceSku
+		have         []armcompute.ResourceSKU
 		err          string
 	}{
 		"should find": {
 			sku:          "foo",
 			location:     "test",
 			resourceType: "bar",
-			have: []compute.ResourceSku{
+			have: []armcompute.ResourceSKU{
 				{
-					Name:         to.StringPtr("other"),
-					ResourceType: to.StringPtr("baz"),
+					Name:         to.Ptr("other"),
+					ResourceType: to.Ptr("baz"),
 				},
 				{
-					Name:         to.StringPtr("foo"),
-					ResourceType: to.StringPtr("bar"),
+					Name:         to.Ptr("foo"),
+					ResourceType: to.Ptr("bar"),
 				},
 			},
 		},
@@ -53,9 +53,9 @@ func TestCacheGet(t *testing.T) {
 			sku:          "foo",
 			location:     "test",
 			resourceType: "bar",
-			have: []compute.ResourceSku{
+			have: []armcompute.ResourceSKU{
 				{
-					Name: to.StringPtr("other"),
+					Name: to.Ptr("other"),
 				},
 			},
 			err: "reconcile error that cannot be recovered occurred: resource sku with name 'foo' and category 'bar' not found in location 'test'. Object will not be requeued",
@@ -104,21 +104,19 @@ func TestCacheGet(t *testing.T) {
 
 func TestCacheGetZones(t *testing.T) {
 	cases := map[string]struct {
-		have []compute.ResourceSku
+		have []armcompute.ResourceSKU
 		want []string
 	}{
 		"should find 1 result": {
-			have: []compute.ResourceSku{
+			have: []armcompute.ResourceSKU{
 				{
-					Name:         to.StringPtr("foo"),
-					ResourceType: to.StringPtr(string(VirtualMachines)),
-					Locations: &[]string{
-						"baz",
-					},
-					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					Name:         to.Ptr("foo"),
+					ResourceType: to.Ptr(string(VirtualMachines)),
+					Locations:    []*string{to.Ptr("baz")},
+					LocationInfo: []*armcompute.ResourceSKULocationInfo{
 						{
-							Location: to.StringPtr("baz"),
-							Zones:    &[]string{"1"},
+							Location: to.Ptr("baz"),
+							Zones:    []*string{to.Ptr("1")},
 						},
 					},
 				},
@@ -126,30 +124,26 @@ func TestCacheGetZones(t *testing.T) {
 			want: []string{"1"},
 		},
 		"should find 2 results": {
-			have: []compute.ResourceSku{
+			have: []armcompute.ResourceSKU{
 				{
-					Name:         to.StringPtr("foo"),
-					ResourceType: to.StringPtr(string(VirtualMachines)),
-					Locations: &[]string{
-						"baz",
-					},
-					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					Name:         to.Ptr("foo"),
+					ResourceType: to.Ptr(string(VirtualMachines)),
+					Locations:    []*string{to.Ptr("baz")},
+					LocationInfo: []*armcompute.ResourceSKULocationInfo{
 						{
-							Location: to.StringPtr("baz"),
-							Zones:    &[]string{"1"},
+							Location: to.Ptr("baz"),
+							Zones:    []*string{to.Ptr("1")},
 						},
 					},
 				},
 				{
-					Name:         to.StringPtr("foo"),
-					ResourceType: to.StringPtr(string(VirtualMachines)),
-					Locations: &[]string{
-						"baz",
-					},
-					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					Name:         to.Ptr("foo"),
+					ResourceType: to.Ptr(string(VirtualMachines)),
+					Locations:    []*string{to.Ptr("baz")},
+					LocationInfo: []*armcompute.ResourceSKULocationInfo{
 						{
-							Location: to.StringPtr("baz"),
-							Zones:    &[]string{"2"},
+							Location: to.Ptr("baz"),
+							Zones:    []*string{to.Ptr("2")},
 						},
 					},
 				},
@@ -157,17 +151,15 @@ func TestCacheGetZones(t *testing.T) {
 			want: []string{"1", "2"},
 		},
 		"should not find due to location mismatch": {
-			have: []compute.ResourceSku{
+			have: []armcompute.ResourceSKU{
 				{
-					Name:         to.StringPtr("foo"),
-					ResourceType: to.StringPtr(string(VirtualMachines)),
-					Locations: &[]string{
-						"foobar",
-					},
-					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					Name:         to.Ptr("foo"),
+					ResourceType: to.Ptr(string(VirtualMachines)),
+					Locations:    []*string{to.Ptr("foobar")},
+					LocationInfo: []*armcompute.ResourceSKULocationInfo{
 						{
-							Location: to.StringPtr("foobar"),
-							Zones:    &[]string{"1"},
+							Location: to.Ptr("foobar"),
+							Zones:    []*string{to.Ptr("1")},
 						},
 					},
 				},
@@ -175,23 +167,21 @@ func TestCacheGetZones(t *testing.T) {
 			want: nil,
 		},
 		"should not find due to location restriction": {
-			have: []compute.ResourceSku{
+			have: []armcompute.ResourceSKU{
 				{
-					Name:         to.StringPtr("foo"),
-					ResourceType: to.StringPtr(string(VirtualMachines)),
-					Locations: &[]string{
-						"baz",
-					},
-					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					Name:         to.Ptr("foo"),
+					ResourceType: to.Ptr(string(VirtualMachines)),
+					Locations:    []*string{to.Ptr("baz")},
+					LocationInfo: []*armcompute.ResourceSKULocationInfo{
 						{
-							Location: to.StringPtr("baz"),
-							Zones:    &[]string{"1"},
+							Location: to.Ptr("baz"),
+							Zones:    []*string{to.Ptr("1")},
 						},
 					},
-					Restrictions: &[]compute.ResourceSkuRestrictions{
+					Restrictions: []*armcompute.ResourceSKURestrictions{
 						{
-							Type:   compute.ResourceSkuRestrictionsTypeLocation,
-							Values: &[]string{"baz"},
+							Type:   to.Ptr(armcompute.ResourceSKURestrictionsTypeLocation),
+							Values: []*string{to.Ptr("baz")},
 						},
 					},
 				},
@@ -199,24 +189,22 @@ func TestCacheGetZones(t *testing.T) {
 			want: nil,
 		},
 		"should not find due to zone restriction": {
-			have: []compute.ResourceSku{
+			have: []armcompute.ResourceSKU{
 				{
-					Name:         to.StringPtr("foo"),
-					ResourceType: to.StringPtr(string(VirtualMachines)),
-					Locations: &[]string{
-						"baz",
-					},
-					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					Name:         to.Ptr("foo"),
+					ResourceType: to.Ptr(string(VirtualMachines)),
+					Locations:    []*string{to.Ptr("baz")},
+					LocationInfo: []*armcompute.ResourceSKULocationInfo{
 						{
-							Location: to.StringPtr("baz"),
-							Zones:    &[]string{"1"},
+							Location: to.Ptr("baz"),
+							Zones:    []*string{to.Ptr("1")},
 						},
 					},
-					Restrictions: &[]compute.ResourceSkuRestrictions{
+					Restrictions: []*armcompute.ResourceSKURestrictions{
 						{
-							Type: compute.ResourceSkuRestrictionsTypeZone,
-							RestrictionInfo: &compute.ResourceSkuRestrictionInfo{
-								Zones: &[]string{"1"},
+							Type: to.Ptr(armcompute.ResourceSKURestrictionsTypeZone),
+							RestrictionInfo: &armcompute.ResourceSKURestrictionInfo{
+								Zones: []*string{to.Ptr("1")},
 							},
 						},
 					},
@@ -248,21 +236,19 @@ func TestCacheGetZones(t *testing.T) {
 
 func TestCacheGetZonesWithVMSize(t *testing.T) {
 	cases := map[string]struct {
-		have []compute.ResourceSku
+		have []armcompute.ResourceSKU
 		want []string
 	}{
 		"should find 1 result": {
-			have: []compute.ResourceSku{
+			have: []armcompute.ResourceSKU{
 				{
-					Name:         to.StringPtr("foo"),
-					ResourceType: to.StringPtr(string(VirtualMachines)),
-					Locations: &[]string{
-						"baz",
-					},
-					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					Name:         to.Ptr("foo"),
+					ResourceType: to.Ptr(string(VirtualMachines)),
+					Locations:    []*string{to.Ptr("baz")},
+					LocationInfo: []*armcompute.ResourceSKULocationInfo{
 						{
-							Location: to.StringPtr("baz"),
-							Zones:    &[]string{"1"},
+							Location: to.Ptr("baz"),
+							Zones:    []*string{to.Ptr("1")},
 						},
 					},
 				},
@@ -270,17 +256,15 @@ func TestCacheGetZonesWithVMSize(t *testing.T) {
 			want: []string{"1"},
 		},
 		"should find 2 results": {
-			have: []compute.ResourceSku{
+			have: []armcompute.ResourceSKU{
 				{
-					Name:         to.StringPtr("foo"),
-					ResourceType: to.StringPtr(string(VirtualMachines)),
-					Locations: &[]string{
-						"baz",
-					},
-					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					Name:         to.Ptr("foo"),
+					ResourceType: to.Ptr(string(VirtualMachines)),
+					Locations:    []*string{to.Ptr("baz")},
+					LocationInfo: []*armcompute.ResourceSKULocationInfo{
 						{
-							Location: to.StringPtr("baz"),
-							Zones:    &[]string{"1", "2"},
+							Location: to.Ptr("baz"),
+							Zones:    []*string{to.Ptr("1"), to.Ptr("2")},
 						},
 					},
 				},
@@ -288,17 +272,15 @@ func TestCacheGetZonesWithVMSize(t *testing.T) {
 			want: []string{"1", "2"},
 		},
 		"should not find due to size mismatch": {
-			have: []compute.ResourceSku{
+			have: []armcompute.ResourceSKU{
 				{
-					Name:         to.StringPtr("foobar"),
-					ResourceType: to.StringPtr(string(VirtualMachines)),
-					Locations: &[]string{
-						"baz",
-					},
-					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					Name:         to.Ptr("foobar"),
+					ResourceType: to.Ptr(string(VirtualMachines)),
+					Locations:    []*string{to.Ptr("baz")},
+					LocationInfo: []*armcompute.ResourceSKULocationInfo{
 						{
-							Location: to.StringPtr("baz"),
-							Zones:    &[]string{"1"},
+							Location: to.Ptr("baz"),
+							Zones:    []*string{to.Ptr("1")},
 						},
 					},
 				},
@@ -306,17 +288,15 @@ func TestCacheGetZonesWithVMSize(t *testing.T) {
 			want: nil,
 		},
 		"should not find due to location mismatch": {
-			have: []compute.ResourceSku{
+			have: []armcompute.ResourceSKU{
 				{
-					Name:         to.StringPtr("foo"),
-					ResourceType: to.StringPtr(string(VirtualMachines)),
-					Locations: &[]string{
-						"foobar",
-					},
-					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					Name:         to.Ptr("foo"),
+					ResourceType: to.Ptr(string(VirtualMachines)),
+					Locations:    []*string{to.Ptr("foobar")},
+					LocationInfo: []*armcompute.ResourceSKULocationInfo{
 						{
-							Location: to.StringPtr("foobar"),
-							Zones:    &[]string{"1"},
+							Location: to.Ptr("foobar"),
+							Zones:    []*string{to.Ptr("1")},
 						},
 					},
 				},
@@ -324,23 +304,21 @@ func TestCacheGetZonesWithVMSize(t *testing.T) {
 			want: nil,
 		},
 		"should not find due to location restriction": {
-			have: []compute.ResourceSku{
+			have: []armcompute.ResourceSKU{
 				{
-					Name:         to.StringPtr("foo"),
-					ResourceType: to.StringPtr(string(VirtualMachines)),
-					Locations: &[]string{
-						"baz",
-					},
-					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					Name:         to.Ptr("foo"),
+					ResourceType: to.Ptr(string(VirtualMachines)),
+					Locations:    []*string{to.Ptr("baz")},
+					LocationInfo: []*armcompute.ResourceSKULocationInfo{
 						{
-							Location: to.StringPtr("baz"),
-							Zones:    &[]string{"1"},
+							Location: to.Ptr("baz"),
+							Zones:    []*string{to.Ptr("1")},
 						},
 					},
-					Restrictions: &[]compute.ResourceSkuRestrictions{
+					Restrictions: []*armcompute.ResourceSKURestrictions{
 						{
-							Type:   compute.ResourceSkuRestrictionsTypeLocation,
-							Values: &[]string{"baz"},
+							Type:   to.Ptr(armcompute.ResourceSKURestrictionsTypeLocation),
+							Values: []*string{to.Ptr("baz")},
 						},
 					},
 				},
@@ -348,24 +326,22 @@ func TestCacheGetZonesWithVMSize(t *testing.T) {
 			want: nil,
 		},
 		"should not find due to zone restriction": {
-			have: []compute.ResourceSku{
+			have: []armcompute.ResourceSKU{
 				{
-					Name:         to.StringPtr("foo"),
-					ResourceType: to.StringPtr(string(VirtualMachines)),
-					Locations: &[]string{
-						"baz",
-					},
-					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					Name:         to.Ptr("foo"),
+					ResourceType: to.Ptr(string(VirtualMachines)),
+					Locations:    []*string{to.Ptr("baz")},
+					LocationInfo: []*armcompute.ResourceSKULocationInfo{
 						{
-							Location: to.StringPtr("baz"),
-							Zones:    &[]string{"1"},
+							Location: to.Ptr("baz"),
+							Zones:    []*string{to.Ptr("1")},
 						},
 					},
-					Restrictions: &[]compute.ResourceSkuRestrictions{
+					Restrictions: []*armcompute.ResourceSKURestrictions{
 						{
-							Type: compute.ResourceSkuRestrictionsTypeZone,
-							RestrictionInfo: &compute.ResourceSkuRestrictionInfo{
-								Zones: &[]string{"1"},
+							Type: to.Ptr(armcompute.ResourceSKURestrictionsTypeZone),
+							RestrictionInfo: &armcompute.ResourceSKURestrictionInfo{
+								Zones: []*string{to.Ptr("1")},
 							},
 						},
 					},
@@ -19,8 +19,8 @@ package resourceskus
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -28,47 +28,41 @@ import (
 
 // Client wraps go-sdk.
 type Client interface {
-	List(context.Context, string) ([]compute.ResourceSku, error)
+	List(context.Context, string) ([]armcompute.ResourceSKU, error)
 }
 
 // AzureClient contains the Azure go-sdk Client.
 type AzureClient struct {
-	skus compute.ResourceSkusClient
+	skus *armcompute.ResourceSKUsClient
 }
 
 var _ Client = &AzureClient{}
 
 // NewClient creates a new Resource SKUs client from subscription ID.
-func NewClient(auth azure.Authorizer) *AzureClient {
-	return &AzureClient{
-		skus: newResourceSkusClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
 	}
-}
-
-// newResourceSkusClient creates a new Resource SKUs client from subscription ID.
-func newResourceSkusClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.ResourceSkusClient {
-	c := compute.NewResourceSkusClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
-	return c
+	c, err := armcompute.NewResourceSKUsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create resource SKUs client")
+	}
+	return &AzureClient{
+		skus: c,
+	}, nil
 }
 
 // List returns all Resource SKUs available to the subscription.
-func (ac *AzureClient) List(ctx context.Context, filter string) ([]compute.ResourceSku, error) {
+func (ac *AzureClient) List(ctx context.Context, filter string) ([]armcompute.ResourceSKU, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "resourceskus.AzureClient.List")
 	defer done()
 
-	iter, err := ac.skus.ListComplete(ctx, filter, "true")
-	if err != nil {
-		return nil, errors.Wrap(err, "could not list resource skus")
-	}
-
-	var skus []compute.ResourceSku
-	for iter.NotDone() {
-		skus = append(skus, iter.Value())
-		if err := iter.NextWithContext(ctx); err != nil {
-			return skus, errors.Wrap(err, "could not iterate resource skus")
-		}
-	}
-
-	return skus, nil
+	pager := ac.skus.NewListPager(&armcompute.ResourceSKUsClientListOptions{
+		Filter:                   &filter,
+		IncludeExtendedLocations: to.Ptr("true"),
+	})
+	return azure.ListPager(ctx, "resource skus", pager, func(page armcompute.ResourceSKUsClientListResponse) []*armcompute.ResourceSKU {
+		return page.Value
+	})
 }
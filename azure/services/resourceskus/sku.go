@@ -20,12 +20,13 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
 )
 
 // SKU is a thin layer over the Azure resource SKU API to better introspect capabilities.
-type SKU compute.ResourceSku
+type SKU armcompute.ResourceSKU
 
 // ResourceType models available resource types as a set of known string constants.
 type ResourceType string
@@ -68,6 +69,20 @@ const (
 	MaximumPlatformFaultDomainCount = "MaximumPlatformFaultDomainCount"
 	// UltraSSDAvailable identifies the capability for the support of UltraSSD data disks.
 	UltraSSDAvailable = "UltraSSDAvailable"
+	// MaxWriteAcceleratorDisksAllowed identifies the capability for the number of disks which can have Write Accelerator enabled.
+	MaxWriteAcceleratorDisksAllowed = "MaxWriteAcceleratorDisksAllowed"
+	// UncachedDiskIOPS identifies the capability for the maximum IOPS of the uncached disks of a VM.
+	UncachedDiskIOPS = "UncachedDiskIOPS"
+	// HibernationSupported identifies the capability for the support of the hibernation of VMs.
+	HibernationSupported = "HibernationSupported"
+	// HyperVGenerations identifies the capability for the Hyper-V generations of the images a VM size can boot, e.g. "V1,V2".
+	HyperVGenerations = "HyperVGenerations"
+	// DiskControllerTypes identifies the capability for the disk controllers a VM size supports, e.g. "SCSI, NVMe".
+	DiskControllerTypes = "DiskControllerTypes"
+	// HyperVGenerationV1 is the value of the HyperVGenerations capability for generation 1 images.
+	HyperVGenerationV1 = "V1"
+	// HyperVGenerationV2 is the value of the HyperVGenerations capability for generation 2 images.
+	HyperVGenerationV2 = "V2"
 )
 
 // HasCapability return true for a capability which can be either
@@ -75,12 +90,10 @@ const (
 // "UltraSSDAvavailable" "EncryptionAtHostSupported",
 // "AcceleratedNetworkingEnabled", and "RdmaEnabled".
 func (s SKU) HasCapability(name string) bool {
-	if s.Capabilities != nil {
-		for _, capability := range *s.Capabilities {
-			if capability.Name != nil && *capability.Name == name {
-				if capability.Value != nil && strings.EqualFold(*capability.Value, string(CapabilitySupported)) {
-					return true
-				}
+	for _, capability := range s.Capabilities {
+		if capability != nil && capability.Name != nil && *capability.Name == name {
+			if capability.Value != nil && strings.EqualFold(*capability.Value, string(CapabilitySupported)) {
+				return true
 			}
 		}
 	}
@@ -96,12 +109,8 @@ func (s SKU) HasCapability(name string) bool {
 // "CombinedTempDiskAndCachedWriteBytesPerSecond", "UncachedDiskIOPS",
 // and "UncachedDiskBytesPerSecond".
 func (s SKU) HasCapabilityWithCapacity(name string, value int64) (bool, error) {
-	if s.Capabilities == nil {
-		return false, nil
-	}
-
-	for _, capability := range *s.Capabilities {
-		if capability.Name == nil || *capability.Name != name || capability.Value == nil {
+	for _, capability := range s.Capabilities {
+		if capability == nil || capability.Name == nil || *capability.Name != name || capability.Value == nil {
 			continue
 		}
 
@@ -121,40 +130,55 @@ func (s SKU) HasCapabilityWithCapacity(name string, value int64) (bool, error) {
 // GetCapability gets the value assigned to the given capability.
 // Eg. MaximumPlatformFaultDomainCount -> "3" will return "3" for the capability "MaximumPlatformFaultDomainCount".
 func (s SKU) GetCapability(name string) (string, bool) {
-	if s.Capabilities != nil {
-		for _, capability := range *s.Capabilities {
-			if capability.Name != nil && *capability.Name == name {
-				return *capability.Value, true
-			}
+	for _, capability := range s.Capabilities {
+		if capability != nil && capability.Name != nil && *capability.Name == name {
+			return pointer.StringDeref(capability.Value, ""), true
 		}
 	}
 	return "", false
 }
 
-// HasLocationCapability returns true if the provided resource supports the location capability.
-func (s SKU) HasLocationCapability(capabilityName, location, zone string) bool {
-	if s.LocationInfo == nil {
+// HasCapabilityValue returns true when the provided resource exposes a
+// capability whose value is a comma separated list containing the given
+// value. Examples include "HyperVGenerations" and "DiskControllerTypes".
+func (s SKU) HasCapabilityValue(name, value string) bool {
+	list, ok := s.GetCapability(name)
+	if !ok {
 		return false
 	}
+	for _, v := range strings.Split(list, ",") {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsHyperVGeneration returns true when the VM size can boot images of the given Hyper-V generation.
+// Sizes which don't expose the HyperVGenerations capability only support generation 1.
+func (s SKU) SupportsHyperVGeneration(generation string) bool {
+	if _, ok := s.GetCapability(HyperVGenerations); !ok {
+		return generation == HyperVGenerationV1
+	}
+	return s.HasCapabilityValue(HyperVGenerations, generation)
+}
 
-	for _, info := range *s.LocationInfo {
-		if info.Location == nil || *info.Location != location || info.ZoneDetails == nil {
+// HasLocationCapability returns true if the provided resource supports the location capability.
+func (s SKU) HasLocationCapability(capabilityName, location, zone string) bool {
+	for _, info := range s.LocationInfo {
+		if info == nil || info.Location == nil || *info.Location != location {
 			continue
 		}
 
-		for _, zoneDetail := range *info.ZoneDetails {
-			if zoneDetail.Capabilities == nil {
+		for _, zoneDetail := range info.ZoneDetails {
+			if zoneDetail == nil {
 				continue
 			}
 
-			for _, capability := range *zoneDetail.Capabilities {
-				if capability.Name != nil && *capability.Name == capabilityName {
-					if zoneDetail.Name == nil {
-						return false
-					}
-
-					for _, name := range *zoneDetail.Name {
-						if name == zone {
+			for _, capability := range zoneDetail.Capabilities {
+				if capability != nil && capability.Name != nil && *capability.Name == capabilityName {
+					for _, name := range zoneDetail.Name {
+						if name != nil && *name == zone {
 							return true
 						}
 					}
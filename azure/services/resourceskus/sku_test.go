@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceskus
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	. "github.com/onsi/gomega"
+)
+
+func TestSupportsHyperVGeneration(t *testing.T) {
+	cases := map[string]struct {
+		capabilities []*armcompute.ResourceSKUCapabilities
+		v1           bool
+		v2           bool
+	}{
+		"capability missing means generation 1 only": {
+			v1: true,
+		},
+		"generation 1 and 2": {
+			capabilities: []*armcompute.ResourceSKUCapabilities{
+				{Name: to.Ptr(HyperVGenerations), Value: to.Ptr("V1,V2")},
+			},
+			v1: true,
+			v2: true,
+		},
+		"generation 2 only": {
+			capabilities: []*armcompute.ResourceSKUCapabilities{
+				{Name: to.Ptr(HyperVGenerations), Value: to.Ptr("V2")},
+			},
+			v2: true,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			sku := SKU{Capabilities: tc.capabilities}
+			g.Expect(sku.SupportsHyperVGeneration(HyperVGenerationV1)).To(Equal(tc.v1))
+			g.Expect(sku.SupportsHyperVGeneration(HyperVGenerationV2)).To(Equal(tc.v2))
+		})
+	}
+}
+
+func TestHasCapabilityValue(t *testing.T) {
+	g := NewWithT(t)
+	sku := SKU{Capabilities: []*armcompute.ResourceSKUCapabilities{
+		{Name: to.Ptr(DiskControllerTypes), Value: to.Ptr("SCSI, NVMe")},
+	}}
+	g.Expect(sku.HasCapabilityValue(DiskControllerTypes, "NVMe")).To(BeTrue())
+	g.Expect(sku.HasCapabilityValue(DiskControllerTypes, "scsi")).To(BeTrue())
+	g.Expect(sku.HasCapabilityValue(DiskControllerTypes, "IDE")).To(BeFalse())
+	g.Expect(sku.HasCapabilityValue(HyperVGenerations, "V1")).To(BeFalse())
+}
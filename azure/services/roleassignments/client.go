@@ -19,9 +19,7 @@ package roleassignments
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/authorization/mgmt/authorization"
-	"github.com/Azure/go-autorest/autorest"
-	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -29,61 +27,52 @@ import (
 
 // azureClient contains the Azure go-sdk Client.
 type azureClient struct {
-	roleassignments authorization.RoleAssignmentsClient
+	roleassignments *armauthorization.RoleAssignmentsClient
 }
 
 // newClient creates a new role assignment client from subscription ID.
-func newClient(auth azure.Authorizer) *azureClient {
-	c := newRoleAssignmentClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
-	return &azureClient{c}
-}
-
-// newRoleAssignmentClient creates a role assignments client from subscription ID.
-func newRoleAssignmentClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) authorization.RoleAssignmentsClient {
-	roleClient := authorization.NewRoleAssignmentsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&roleClient.Client, authorizer)
-	return roleClient
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armauthorization.NewRoleAssignmentsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create role assignments client")
+	}
+	return &azureClient{c}, nil
 }
 
 // Get gets the specified role assignment by the role assignment name.
-func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (interface{}, error) {
-	ctx, span := tele.Tracer().Start(ctx, "roleassignments.AzureClient.Get")
-	defer span.End()
-	return ac.roleassignments.Get(ctx, spec.OwnerResourceName(), spec.ResourceName())
-}
-
-// CreateOrUpdateAsync creates a roleassignment.
-// Creating a roleassignment is not a long running operation, so we don't ever return a future.
-func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (interface{}, azureautorest.FutureAPI, error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "groups.AzureClient.CreateOrUpdate")
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.azureClient.Get")
 	defer done()
-	createParams, ok := parameters.(authorization.RoleAssignmentCreateParameters)
-	if !ok {
-		return nil, nil, errors.Errorf("%T is not a authorization.RoleAssignmentCreateParameters", parameters)
+
+	resp, err := ac.roleassignments.Get(ctx, spec.OwnerResourceName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
 	}
-	result, err := ac.roleassignments.Create(ctx, spec.OwnerResourceName(), spec.ResourceName(), createParams)
-	return result, nil, err
+	return resp.RoleAssignment, nil
 }
 
-// IsDone returns true if the long-running operation has completed.
-func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (bool, error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.AzureClient.IsDone")
+// CreateOrUpdateAsync creates a roleassignment.
+// Creating a roleassignment is not a long running operation, so we don't ever return a poller.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.azureClient.CreateOrUpdateAsync")
 	defer done()
 
-	isDone, err := future.DoneWithContext(ctx, ac.roleassignments)
+	createParams, ok := parameters.(armauthorization.RoleAssignmentCreateParameters)
+	if !ok {
+		return nil, nil, errors.Errorf("%T is not an armauthorization.RoleAssignmentCreateParameters", parameters)
+	}
+	resp, err := ac.roleassignments.Create(ctx, spec.OwnerResourceName(), spec.ResourceName(), createParams, nil)
 	if err != nil {
-		return false, errors.Wrap(err, "failed checking if the operation was complete")
+		return nil, nil, err
 	}
-	return isDone, nil
-}
-
-// Result fetches the result of a long-running operation future.
-func (ac *azureClient) Result(ctx context.Context, futureData azureautorest.FutureAPI, futureType string) (interface{}, error) {
-	// Result is a no-op for role assignment as only Delete operations return a future.
-	return nil, nil
+	return resp.RoleAssignment, nil, nil
 }
 
 // DeleteAsync is no-op for role assignments. It gets deleted as part of the VM deletion.
-func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (azureautorest.FutureAPI, error) {
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
 	return nil, nil
 }
@@ -19,10 +19,12 @@ package roleassignments
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
@@ -46,19 +48,36 @@ type RoleAssignmentScope interface {
 type Service struct {
 	Scope                 RoleAssignmentScope
 	virtualMachinesGetter async.Getter
+	managedClustersGetter async.Getter
 	async.Reconciler
 	virtualMachineScaleSetClient scalesets.Client
 }
 
 // New creates a new service.
-func New(scope RoleAssignmentScope) *Service {
-	client := newClient(scope)
+func New(scope RoleAssignmentScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	virtualMachinesClient, err := virtualmachines.NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	scaleSetsClient, err := scalesets.NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	managedClustersGetter, err := managedclusters.NewGetter(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:                        scope,
-		virtualMachinesGetter:        virtualmachines.NewClient(scope),
-		virtualMachineScaleSetClient: scalesets.NewClient(scope),
-		Reconciler:                   async.New(scope, client, client),
-	}
+		virtualMachinesGetter:        virtualMachinesClient,
+		managedClustersGetter:        managedClustersGetter,
+		virtualMachineScaleSetClient: scaleSetsClient,
+		Reconciler:                   async.NewPollerService(scope, client, client),
+	}, nil
 }
 
 // Name returns the service name.
@@ -96,9 +115,15 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			return errors.Wrap(err, "failed to assign role to system assigned identity")
 		}
 		principalID = ID
+	case azure.ManagedCluster:
+		ID, err := s.getKubeletPrincipalID(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to assign role to kubelet identity")
+		}
+		principalID = ID
 	default:
-		return errors.Errorf("unexpected resource type %q. Expected one of [%s, %s]", resourceType,
-			azure.VirtualMachine, azure.VirtualMachineScaleSet)
+		return errors.Errorf("unexpected resource type %q. Expected one of [%s, %s, %s]", resourceType,
+			azure.VirtualMachine, azure.VirtualMachineScaleSet, azure.ManagedCluster)
 	}
 
 	for _, roleAssignmentSpec := range s.Scope.RoleAssignmentSpecs(principalID) {
@@ -125,9 +150,9 @@ func (s *Service) getVMPrincipalID(ctx context.Context) (*string, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get principal ID for VM")
 	}
-	resultVM, ok := resultVMIface.(compute.VirtualMachine)
+	resultVM, ok := resultVMIface.(armcompute.VirtualMachine)
 	if !ok {
-		return nil, errors.Errorf("%T is not a compute.VirtualMachine", resultVMIface)
+		return nil, errors.Errorf("%T is not an armcompute.VirtualMachine", resultVMIface)
 	}
 	return resultVM.Identity.PrincipalID, nil
 }
@@ -144,6 +169,34 @@ func (s *Service) getVMSSPrincipalID(ctx context.Context) (*string, error) {
 	return resultVMSS.Identity.PrincipalID, nil
 }
 
+// getKubeletPrincipalID returns the principal ID of the kubelet identity of the managed cluster.
+func (s *Service) getKubeletPrincipalID(ctx context.Context) (*string, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.getKubeletPrincipalID")
+	defer done()
+	log.V(2).Info("fetching principal ID for kubelet identity")
+	spec := &managedclusters.ManagedClusterSpec{
+		Name:          s.Scope.Name(),
+		ResourceGroup: s.Scope.ResourceGroup(),
+	}
+
+	resultMCIface, err := s.managedClustersGetter.Get(ctx, spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get principal ID for kubelet identity")
+	}
+	resultMC, ok := resultMCIface.(armcontainerservice.ManagedCluster)
+	if !ok {
+		return nil, errors.Errorf("%T is not an armcontainerservice.ManagedCluster", resultMCIface)
+	}
+	if resultMC.Properties == nil {
+		return nil, errors.New("managed cluster has no kubelet identity")
+	}
+	kubeletIdentity, ok := resultMC.Properties.IdentityProfile[managedclusters.KubeletIdentityKey]
+	if !ok || kubeletIdentity == nil {
+		return nil, errors.New("managed cluster has no kubelet identity")
+	}
+	return kubeletIdentity.ObjectID, nil
+}
+
 // Delete is a no-op as the role assignments get deleted as part of VM deletion.
 func (s *Service) Delete(ctx context.Context) error {
 	_, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.Delete")
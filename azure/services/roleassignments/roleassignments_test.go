@@ -18,17 +18,18 @@ package roleassignments
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net/http"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments/mock_roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets/mock_scalesets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
@@ -45,7 +46,7 @@ var (
 		MachineName:   "test-vm",
 		ResourceGroup: "my-rg",
 		ResourceType:  azure.VirtualMachine,
-		PrincipalID:   to.StringPtr("fake-principal-id"),
+		PrincipalID:   to.Ptr("fake-principal-id"),
 	}
 	fakeRoleAssignment2 = RoleAssignmentSpec{
 		MachineName:   "test-vmss",
@@ -53,6 +54,17 @@ var (
 		ResourceType:  azure.VirtualMachineScaleSet,
 	}
 
+	fakeManagedClusterSpec = managedclusters.ManagedClusterSpec{
+		Name:          "test-aks",
+		ResourceGroup: "my-rg",
+	}
+	fakeRoleAssignment3 = RoleAssignmentSpec{
+		MachineName:   "test-aks",
+		ResourceGroup: "my-rg",
+		ResourceType:  azure.ManagedCluster,
+		PrincipalID:   &fakePrincipalID,
+	}
+
 	emptyRoleAssignmentSpec = RoleAssignmentSpec{}
 	fakeRoleAssignmentSpecs = []azure.ResourceSpecGetter{&fakeRoleAssignment1, &fakeRoleAssignment2, &emptyRoleAssignmentSpec}
 )
@@ -76,8 +88,8 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 				s.HasSystemAssignedIdentity().Return(true)
 				s.RoleAssignmentResourceType().Return("VirtualMachine")
 				s.RoleAssignmentSpecs(&fakePrincipalID).Return(fakeRoleAssignmentSpecs[:1])
-				m.Get(gomockinternal.AContext(), &fakeVMSpec).Return(compute.VirtualMachine{
-					Identity: &compute.VirtualMachineIdentity{
+				m.Get(gomockinternal.AContext(), &fakeVMSpec).Return(armcompute.VirtualMachine{
+					Identity: &armcompute.VirtualMachineIdentity{
 						PrincipalID: &fakePrincipalID,
 					},
 				}, nil)
@@ -86,7 +98,7 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 		},
 		{
 			name:          "error getting VM",
-			expectedError: "failed to assign role to system assigned identity: failed to get principal ID for VM: #: Internal Server Error: StatusCode=500",
+			expectedError: "failed to assign role to system assigned identity: failed to get principal ID for VM: Internal Server Error",
 			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder,
 				m *mock_async.MockGetterMockRecorder,
 				r *mock_async.MockReconcilerMockRecorder) {
@@ -95,12 +107,12 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 				s.Name().Return(fakeRoleAssignment1.MachineName)
 				s.HasSystemAssignedIdentity().Return(true)
 				s.RoleAssignmentResourceType().Return("VirtualMachine")
-				m.Get(gomockinternal.AContext(), &fakeVMSpec).Return(compute.VirtualMachine{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.Get(gomockinternal.AContext(), &fakeVMSpec).Return(armcompute.VirtualMachine{}, errors.New("Internal Server Error"))
 			},
 		},
 		{
 			name:          "return error when creating a role assignment",
-			expectedError: "cannot assign role to VirtualMachine system assigned identity: #: Internal Server Error: StatusCode=500",
+			expectedError: "cannot assign role to VirtualMachine system assigned identity: Internal Server Error",
 			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder,
 				m *mock_async.MockGetterMockRecorder,
 				r *mock_async.MockReconcilerMockRecorder) {
@@ -110,13 +122,13 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 				s.RoleAssignmentResourceType().Return("VirtualMachine")
 				s.HasSystemAssignedIdentity().Return(true)
 				s.RoleAssignmentSpecs(&fakePrincipalID).Return(fakeRoleAssignmentSpecs[0:1])
-				m.Get(gomockinternal.AContext(), &fakeVMSpec).Return(compute.VirtualMachine{
-					Identity: &compute.VirtualMachineIdentity{
+				m.Get(gomockinternal.AContext(), &fakeVMSpec).Return(armcompute.VirtualMachine{
+					Identity: &armcompute.VirtualMachineIdentity{
 						PrincipalID: &fakePrincipalID,
 					},
 				}, nil)
 				r.CreateResource(gomockinternal.AContext(), &fakeRoleAssignment1, serviceName).Return(&RoleAssignmentSpec{},
-					autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+					errors.New("Internal Server Error"))
 			},
 		},
 	}
@@ -169,8 +181,8 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 				s.RoleAssignmentResourceType().Return(azure.VirtualMachineScaleSet)
 				s.ResourceGroup().Return("my-rg")
 				s.Name().Return("test-vmss")
-				mvmss.Get(gomockinternal.AContext(), "my-rg", "test-vmss").Return(compute.VirtualMachineScaleSet{
-					Identity: &compute.VirtualMachineScaleSetIdentity{
+				mvmss.Get(gomockinternal.AContext(), "my-rg", "test-vmss").Return(armcompute.VirtualMachineScaleSet{
+					Identity: &armcompute.VirtualMachineScaleSetIdentity{
 						PrincipalID: &fakePrincipalID,
 					},
 				}, nil)
@@ -179,7 +191,7 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 		},
 		{
 			name:          "error getting VMSS",
-			expectedError: "failed to assign role to system assigned identity: failed to get principal ID for VMSS: #: Internal Server Error: StatusCode=500",
+			expectedError: "failed to assign role to system assigned identity: failed to get principal ID for VMSS: Internal Server Error",
 			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder,
 				r *mock_async.MockReconcilerMockRecorder,
 				mvmss *mock_scalesets.MockClientMockRecorder) {
@@ -187,13 +199,13 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 				s.ResourceGroup().Return("my-rg")
 				s.Name().Return("test-vmss")
 				s.HasSystemAssignedIdentity().Return(true)
-				mvmss.Get(gomockinternal.AContext(), "my-rg", "test-vmss").Return(compute.VirtualMachineScaleSet{},
-					autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				mvmss.Get(gomockinternal.AContext(), "my-rg", "test-vmss").Return(armcompute.VirtualMachineScaleSet{},
+					errors.New("Internal Server Error"))
 			},
 		},
 		{
 			name:          "return error when creating a role assignment",
-			expectedError: fmt.Sprintf("cannot assign role to %s system assigned identity: #: Internal Server Error: StatusCode=500", azure.VirtualMachineScaleSet),
+			expectedError: fmt.Sprintf("cannot assign role to %s system assigned identity: Internal Server Error", azure.VirtualMachineScaleSet),
 			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder,
 				r *mock_async.MockReconcilerMockRecorder,
 				mvmss *mock_scalesets.MockClientMockRecorder) {
@@ -202,13 +214,13 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 				s.RoleAssignmentResourceType().Return(azure.VirtualMachineScaleSet)
 				s.ResourceGroup().Return("my-rg")
 				s.Name().Return("test-vmss")
-				mvmss.Get(gomockinternal.AContext(), "my-rg", "test-vmss").Return(compute.VirtualMachineScaleSet{
-					Identity: &compute.VirtualMachineScaleSetIdentity{
+				mvmss.Get(gomockinternal.AContext(), "my-rg", "test-vmss").Return(armcompute.VirtualMachineScaleSet{
+					Identity: &armcompute.VirtualMachineScaleSetIdentity{
 						PrincipalID: &fakePrincipalID,
 					},
 				}, nil)
 				r.CreateResource(gomockinternal.AContext(), &fakeRoleAssignment2, serviceName).Return(&RoleAssignmentSpec{},
-					autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+					errors.New("Internal Server Error"))
 			},
 		},
 	}
@@ -242,3 +254,91 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileRoleAssignmentsManagedCluster(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expect        func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_async.MockGetterMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+		expectedError string
+	}{
+		{
+			name:          "create a role assignment",
+			expectedError: "",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder,
+				m *mock_async.MockGetterMockRecorder,
+				r *mock_async.MockReconcilerMockRecorder) {
+				s.HasSystemAssignedIdentity().Return(true)
+				s.RoleAssignmentResourceType().Return(azure.ManagedCluster)
+				s.ResourceGroup().Return("my-rg")
+				s.Name().Return("test-aks")
+				s.RoleAssignmentSpecs(&fakePrincipalID).Return([]azure.ResourceSpecGetter{&fakeRoleAssignment3})
+				m.Get(gomockinternal.AContext(), &fakeManagedClusterSpec).Return(armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						IdentityProfile: map[string]*armcontainerservice.UserAssignedIdentity{
+							managedclusters.KubeletIdentityKey: {ObjectID: &fakePrincipalID},
+						},
+					},
+				}, nil)
+				r.CreateResource(gomockinternal.AContext(), &fakeRoleAssignment3, serviceName).Return(&fakeRoleAssignment3, nil)
+			},
+		},
+		{
+			name:          "error getting managed cluster",
+			expectedError: "failed to assign role to kubelet identity: failed to get principal ID for kubelet identity: Internal Server Error",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder,
+				m *mock_async.MockGetterMockRecorder,
+				r *mock_async.MockReconcilerMockRecorder) {
+				s.HasSystemAssignedIdentity().Return(true)
+				s.RoleAssignmentResourceType().Return(azure.ManagedCluster)
+				s.ResourceGroup().Return("my-rg")
+				s.Name().Return("test-aks")
+				m.Get(gomockinternal.AContext(), &fakeManagedClusterSpec).Return(armcontainerservice.ManagedCluster{},
+					errors.New("Internal Server Error"))
+			},
+		},
+		{
+			name:          "managed cluster without kubelet identity",
+			expectedError: "failed to assign role to kubelet identity: managed cluster has no kubelet identity",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder,
+				m *mock_async.MockGetterMockRecorder,
+				r *mock_async.MockReconcilerMockRecorder) {
+				s.HasSystemAssignedIdentity().Return(true)
+				s.RoleAssignmentResourceType().Return(azure.ManagedCluster)
+				s.ResourceGroup().Return("my-rg")
+				s.Name().Return("test-aks")
+				m.Get(gomockinternal.AContext(), &fakeManagedClusterSpec).Return(armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{},
+				}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+			managedClustersGetterMock := mock_async.NewMockGetter(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), managedClustersGetterMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:                 scopeMock,
+				managedClustersGetter: managedClustersGetterMock,
+				Reconciler:            asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -17,8 +17,8 @@ limitations under the License.
 package roleassignments
 
 import (
-	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/authorization/mgmt/authorization"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
 	"github.com/pkg/errors"
 )
 
@@ -52,16 +52,16 @@ func (s *RoleAssignmentSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the RoleAssignmentSpec.
 func (s *RoleAssignmentSpec) Parameters(existing interface{}) (interface{}, error) {
 	if existing != nil {
-		if _, ok := existing.(authorization.RoleAssignment); !ok {
-			return nil, errors.Errorf("%T is not a authorization.RoleAssignment", existing)
+		if _, ok := existing.(armauthorization.RoleAssignment); !ok {
+			return nil, errors.Errorf("%T is not an armauthorization.RoleAssignment", existing)
 		}
 		// RoleAssignmentSpec already exists
 		return nil, nil
 	}
-	return authorization.RoleAssignmentCreateParameters{
-		Properties: &authorization.RoleAssignmentProperties{
+	return armauthorization.RoleAssignmentCreateParameters{
+		Properties: &armauthorization.RoleAssignmentProperties{
 			PrincipalID:      s.PrincipalID,
-			RoleDefinitionID: to.StringPtr(s.RoleDefinitionID),
+			RoleDefinitionID: to.Ptr(s.RoleDefinitionID),
 		},
 	}, nil
 }
@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roledefinitions
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// customRoleType is the role type of custom role definitions.
+const customRoleType = "CustomRole"
+
+// Client wraps go-sdk.
+type Client interface {
+	CreateOrUpdate(context.Context, string, RoleDefinition) error
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	roledefinitions *armauthorization.RoleDefinitionsClient
+}
+
+var _ Client = &AzureClient{}
+
+// NewClient creates a new role definitions client.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armauthorization.NewRoleDefinitionsClient(auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create role definitions client")
+	}
+	return &AzureClient{c}, nil
+}
+
+// CreateOrUpdate creates or updates a custom role definition at its first assignable scope.
+func (ac *AzureClient) CreateOrUpdate(ctx context.Context, id string, roleDefinition RoleDefinition) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "roledefinitions.AzureClient.CreateOrUpdate")
+	defer done()
+
+	if len(roleDefinition.AssignableScopes) == 0 {
+		return errors.Errorf("role definition %s has no assignable scopes", roleDefinition.Name)
+	}
+	_, err := ac.roledefinitions.CreateOrUpdate(ctx, roleDefinition.AssignableScopes[0], id, armauthorization.RoleDefinition{
+		Properties: &armauthorization.RoleDefinitionProperties{
+			RoleName:    to.Ptr(roleDefinition.Name),
+			Description: to.Ptr(roleDefinition.Description),
+			RoleType:    to.Ptr(customRoleType),
+			Permissions: []*armauthorization.Permission{{
+				Actions:    to.SliceOfPtrs(roleDefinition.Actions...),
+				NotActions: to.SliceOfPtrs(roleDefinition.NotActions...),
+			}},
+			AssignableScopes: to.SliceOfPtrs(roleDefinition.AssignableScopes...),
+		},
+	}, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create or update role definition %s", roleDefinition.Name)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roledefinitions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// baseActions are the actions the cluster identity performs whatever the services enabled by the AzureCluster: the
+// resource group of the cluster is created and deleted unless it exists beforehand, and the virtual machines and scale
+// sets of the machines and machine pools are created in it and joined to the network resources of the cluster.
+var baseActions = []string{
+	"Microsoft.Authorization/permissions/read",
+	"Microsoft.Compute/availabilitySets/delete",
+	"Microsoft.Compute/availabilitySets/read",
+	"Microsoft.Compute/availabilitySets/write",
+	"Microsoft.Compute/disks/delete",
+	"Microsoft.Compute/disks/read",
+	"Microsoft.Compute/disks/write",
+	"Microsoft.Compute/skus/read",
+	"Microsoft.Compute/virtualMachineScaleSets/delete",
+	"Microsoft.Compute/virtualMachineScaleSets/read",
+	"Microsoft.Compute/virtualMachineScaleSets/virtualMachines/delete",
+	"Microsoft.Compute/virtualMachineScaleSets/virtualMachines/networkInterfaces/ipConfigurations/publicIPAddresses/read",
+	"Microsoft.Compute/virtualMachineScaleSets/virtualMachines/networkInterfaces/read",
+	"Microsoft.Compute/virtualMachineScaleSets/virtualMachines/read",
+	"Microsoft.Compute/virtualMachineScaleSets/virtualMachines/write",
+	"Microsoft.Compute/virtualMachineScaleSets/write",
+	"Microsoft.Compute/virtualMachines/delete",
+	"Microsoft.Compute/virtualMachines/extensions/delete",
+	"Microsoft.Compute/virtualMachines/extensions/read",
+	"Microsoft.Compute/virtualMachines/extensions/write",
+	"Microsoft.Compute/virtualMachines/read",
+	"Microsoft.Compute/virtualMachines/write",
+	"Microsoft.Network/loadBalancers/backendAddressPools/join/action",
+	"Microsoft.Network/networkInterfaces/delete",
+	"Microsoft.Network/networkInterfaces/join/action",
+	"Microsoft.Network/networkInterfaces/read",
+	"Microsoft.Network/networkInterfaces/write",
+	"Microsoft.Network/networkSecurityGroups/join/action",
+	"Microsoft.Network/publicIPAddresses/join/action",
+	"Microsoft.Network/virtualNetworks/subnets/join/action",
+	"Microsoft.Resources/subscriptions/resourceGroups/delete",
+	"Microsoft.Resources/subscriptions/resourceGroups/read",
+	"Microsoft.Resources/subscriptions/resourceGroups/write",
+}
+
+// RoleDefinition is a custom role definition, in the format of the Azure CLI and Azure PowerShell, e.g.
+// "az role definition create --role-definition @role.json".
+type RoleDefinition struct {
+	Name             string   `json:"Name"`
+	IsCustom         bool     `json:"IsCustom"`
+	Description      string   `json:"Description"`
+	Actions          []string `json:"Actions"`
+	NotActions       []string `json:"NotActions"`
+	AssignableScopes []string `json:"AssignableScopes"`
+}
+
+// New returns the least-privilege role definition of the identity of a cluster: the base actions, the actions the
+// services of the cluster require, and the deletion of the resources the services write, since they delete them
+// along with the cluster. The role can be assigned on the subscription of the cluster or on any of its resource groups.
+func New(subscriptionID, namespace, clusterName string, serviceActions []string) RoleDefinition {
+	seen := make(map[string]struct{})
+	var actions []string
+	add := func(action string) {
+		if _, ok := seen[strings.ToLower(action)]; ok {
+			return
+		}
+		seen[strings.ToLower(action)] = struct{}{}
+		actions = append(actions, action)
+	}
+
+	for _, action := range baseActions {
+		add(action)
+	}
+	for _, action := range serviceActions {
+		add(action)
+		if strings.HasSuffix(action, "/write") {
+			add(strings.TrimSuffix(action, "/write") + "/delete")
+		}
+	}
+	sort.Strings(actions)
+
+	return RoleDefinition{
+		Name:             Name(namespace, clusterName),
+		IsCustom:         true,
+		Description:      fmt.Sprintf("Least-privilege role of the identity of AzureCluster %s/%s, generated by Cluster API Provider Azure.", namespace, clusterName),
+		Actions:          actions,
+		NotActions:       []string{},
+		AssignableScopes: []string{"/subscriptions/" + subscriptionID},
+	}
+}
+
+// Name returns the role name of the role definition of the identity of a cluster.
+func Name(namespace, clusterName string) string {
+	return fmt.Sprintf("capz-%s-%s", namespace, clusterName)
+}
+
+// ID returns the name, a GUID, of the role definition of the identity of a cluster in a subscription. It is stable so
+// that creating the role definition again updates it.
+func ID(subscriptionID, namespace, clusterName string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(strings.ToLower(subscriptionID+"/"+Name(namespace, clusterName)))).String()
+}
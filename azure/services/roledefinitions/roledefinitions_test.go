@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roledefinitions
+
+import (
+	"sort"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNew(t *testing.T) {
+	g := NewWithT(t)
+
+	roleDefinition := New("123", "default", "my-cluster", []string{
+		"Microsoft.Network/bastionHosts/read",
+		"Microsoft.Network/bastionHosts/write",
+		"Microsoft.Network/virtualNetworks/read",
+		"Microsoft.Compute/virtualMachines/write",
+	})
+
+	g.Expect(roleDefinition.Name).To(Equal("capz-default-my-cluster"))
+	g.Expect(roleDefinition.IsCustom).To(BeTrue())
+	g.Expect(roleDefinition.AssignableScopes).To(Equal([]string{"/subscriptions/123"}))
+	g.Expect(roleDefinition.NotActions).To(BeEmpty())
+
+	// The services can delete the resources they write.
+	g.Expect(roleDefinition.Actions).To(ContainElements(
+		"Microsoft.Network/bastionHosts/read",
+		"Microsoft.Network/bastionHosts/write",
+		"Microsoft.Network/bastionHosts/delete",
+		"Microsoft.Network/virtualNetworks/read",
+	))
+	g.Expect(roleDefinition.Actions).NotTo(ContainElement("Microsoft.Network/virtualNetworks/delete"))
+	// The machines are created whatever the services.
+	g.Expect(roleDefinition.Actions).To(ContainElements(baseActions))
+	// The actions are listed once, in order.
+	g.Expect(roleDefinition.Actions).To(HaveLen(len(baseActions) + 4))
+	g.Expect(roleDefinition.Actions).To(BeEquivalentTo(sorted(roleDefinition.Actions)))
+}
+
+func TestID(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(ID("123", "default", "my-cluster")).To(Equal(ID("123", "default", "my-cluster")))
+	g.Expect(ID("123", "default", "my-cluster")).NotTo(Equal(ID("456", "default", "my-cluster")))
+	g.Expect(ID("123", "default", "my-cluster")).NotTo(Equal(ID("123", "default", "other-cluster")))
+}
+
+// sorted returns a sorted copy of a list of actions.
+func sorted(actions []string) []string {
+	out := append([]string{}, actions...)
+	sort.Strings(out)
+	return out
+}
@@ -17,8 +17,8 @@ limitations under the License.
 package routetables
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
@@ -51,20 +51,20 @@ func (s *RouteTableSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the route table.
 func (s *RouteTableSpec) Parameters(existing interface{}) (params interface{}, err error) {
 	if existing != nil {
-		if _, ok := existing.(network.RouteTable); !ok {
-			return nil, errors.Errorf("%T is not a network.RouteTable", existing)
+		if _, ok := existing.(armnetwork.RouteTable); !ok {
+			return nil, errors.Errorf("%T is not an armnetwork.RouteTable", existing)
 		}
 		// route table already exists
 		// currently don't support specifying your own routes via spec.
 		return nil, nil
 	}
-	return network.RouteTable{
-		Location:                   to.StringPtr(s.Location),
-		RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{},
+	return armnetwork.RouteTable{
+		Location:   to.Ptr(s.Location),
+		Properties: &armnetwork.RouteTablePropertiesFormat{},
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: s.ClusterName,
 			Lifecycle:   infrav1.ResourceLifecycleOwned,
-			Name:        to.StringPtr(s.Name),
+			Name:        to.Ptr(s.Name),
 			Additional:  s.AdditionalTags,
 		})),
 	}, nil
@@ -18,157 +18,150 @@ package scalesets
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
-	"fmt"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest"
-	azureautorest "github.com/Azure/go-autorest/autorest/azure"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/inflight"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // Client wraps go-sdk.
 type Client interface {
-	List(context.Context, string) ([]compute.VirtualMachineScaleSet, error)
-	ListInstances(context.Context, string, string) ([]compute.VirtualMachineScaleSetVM, error)
-	Get(context.Context, string, string) (compute.VirtualMachineScaleSet, error)
-	CreateOrUpdateAsync(context.Context, string, string, compute.VirtualMachineScaleSet) (*infrav1.Future, error)
-	UpdateAsync(context.Context, string, string, compute.VirtualMachineScaleSetUpdate) (*infrav1.Future, error)
-	GetResultIfDone(ctx context.Context, future *infrav1.Future) (compute.VirtualMachineScaleSet, error)
+	List(context.Context, string) ([]armcompute.VirtualMachineScaleSet, error)
+	ListInstances(context.Context, string, string) ([]armcompute.VirtualMachineScaleSetVM, error)
+	Get(context.Context, string, string) (armcompute.VirtualMachineScaleSet, error)
+	CreateOrUpdateAsync(context.Context, string, string, armcompute.VirtualMachineScaleSet) (*infrav1.Future, error)
+	UpdateAsync(context.Context, string, string, armcompute.VirtualMachineScaleSetUpdate) (*infrav1.Future, error)
+	GetResultIfDone(ctx context.Context, future *infrav1.Future) (armcompute.VirtualMachineScaleSet, error)
 	UpdateInstances(context.Context, string, string, []string) error
+	StartInstances(context.Context, string, string, []string) error
+	DeallocateInstances(context.Context, string, string, []string) error
+	DeleteInstances(context.Context, string, string, []string) error
 	DeleteAsync(context.Context, string, string) (*infrav1.Future, error)
+	GetGalleryImageVersion(context.Context, string, string, string, string, string) (armcompute.GalleryImageVersion, error)
 }
 
-type (
-	// AzureClient contains the Azure go-sdk Client.
-	AzureClient struct {
-		scalesetvms compute.VirtualMachineScaleSetVMsClient
-		scalesets   compute.VirtualMachineScaleSetsClient
-	}
-
-	genericScaleSetFuture interface {
-		DoneWithContext(ctx context.Context, sender autorest.Sender) (done bool, err error)
-		Result(client compute.VirtualMachineScaleSetsClient) (vmss compute.VirtualMachineScaleSet, err error)
-	}
-
-	genericScaleSetFutureImpl struct {
-		azureautorest.FutureAPI
-		result func(client compute.VirtualMachineScaleSetsClient) (vmss compute.VirtualMachineScaleSet, err error)
-	}
-
-	deleteResultAdapter struct {
-		compute.VirtualMachineScaleSetsDeleteFuture
-	}
-)
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	scalesetvms *armcompute.VirtualMachineScaleSetVMsClient
+	scalesets   *armcompute.VirtualMachineScaleSetsClient
+	auth        azure.Authorizer
+}
 
 var _ Client = &AzureClient{}
 
 // NewClient creates a new VMSS client from subscription ID.
-func NewClient(auth azure.Authorizer) *AzureClient {
-	return &AzureClient{
-		scalesetvms: newVirtualMachineScaleSetVMsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
-		scalesets:   newVirtualMachineScaleSetsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
 	}
-}
-
-// newVirtualMachineScaleSetVMsClient creates a new vmss VM client from subscription ID.
-func newVirtualMachineScaleSetVMsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachineScaleSetVMsClient {
-	c := compute.NewVirtualMachineScaleSetVMsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
-	return c
-}
-
-// newVirtualMachineScaleSetsClient creates a new vmss client from subscription ID.
-func newVirtualMachineScaleSetsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachineScaleSetsClient {
-	c := compute.NewVirtualMachineScaleSetsClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
-	return c
+	if baseURI := auth.BaseURI(); baseURI != "" {
+		// Honor the base URI of the authorizer, which is regional for the scale sets of managed machine pools.
+		opts.Cloud.Services = map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {
+				Audience: opts.Cloud.Services[cloud.ResourceManager].Audience,
+				Endpoint: baseURI,
+			},
+		}
+	}
+	scalesetvms, err := armcompute.NewVirtualMachineScaleSetVMsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create vmss VMs client")
+	}
+	scalesets, err := armcompute.NewVirtualMachineScaleSetsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create vmss client")
+	}
+	return &AzureClient{
+		scalesetvms: scalesetvms,
+		scalesets:   scalesets,
+		auth:        auth,
+	}, nil
 }
 
 // ListInstances retrieves information about the model views of a virtual machine scale set.
-func (ac *AzureClient) ListInstances(ctx context.Context, resourceGroupName, vmssName string) ([]compute.VirtualMachineScaleSetVM, error) {
+func (ac *AzureClient) ListInstances(ctx context.Context, resourceGroupName, vmssName string) ([]armcompute.VirtualMachineScaleSetVM, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.ListInstances")
 	defer done()
 
-	itr, err := ac.scalesetvms.ListComplete(ctx, resourceGroupName, vmssName, "", "", "")
-	if err != nil {
-		return nil, err
-	}
-
-	var instances []compute.VirtualMachineScaleSetVM
-	for ; itr.NotDone(); err = itr.NextWithContext(ctx) {
-		if err != nil {
-			return nil, fmt.Errorf("failed to iterate vm scale set vms [%w]", err)
-		}
-		vm := itr.Value()
-		instances = append(instances, vm)
-	}
-	return instances, nil
+	// expand the instance view to get the power state and fault domain of the instances.
+	pager := ac.scalesetvms.NewListPager(resourceGroupName, vmssName, &armcompute.VirtualMachineScaleSetVMsClientListOptions{
+		Expand: to.Ptr(string(armcompute.InstanceViewTypesInstanceView)),
+	})
+	return azure.ListPager(ctx, "instances of scale set "+vmssName, pager, func(page armcompute.VirtualMachineScaleSetVMsClientListResponse) []*armcompute.VirtualMachineScaleSetVM {
+		return page.Value
+	})
 }
 
 // List returns all scale sets in a resource group.
-func (ac *AzureClient) List(ctx context.Context, resourceGroupName string) ([]compute.VirtualMachineScaleSet, error) {
+func (ac *AzureClient) List(ctx context.Context, resourceGroupName string) ([]armcompute.VirtualMachineScaleSet, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.List")
 	defer done()
 
-	itr, err := ac.scalesets.ListComplete(ctx, resourceGroupName)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to list scalesets in the resource group")
-	}
-
-	var instances []compute.VirtualMachineScaleSet
-	for ; itr.NotDone(); err = itr.NextWithContext(ctx) {
-		if err != nil {
-			return nil, fmt.Errorf("failed to iterate vm scale sets [%w]", err)
-		}
-		vm := itr.Value()
-		instances = append(instances, vm)
-	}
-	return instances, nil
+	pager := ac.scalesets.NewListPager(resourceGroupName, nil)
+	return azure.ListPager(ctx, "scale sets in resource group "+resourceGroupName, pager, func(page armcompute.VirtualMachineScaleSetsClientListResponse) []*armcompute.VirtualMachineScaleSet {
+		return page.Value
+	})
 }
 
 // Get retrieves information about the model view of a virtual machine scale set.
-func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vmssName string) (compute.VirtualMachineScaleSet, error) {
+func (ac *AzureClient) Get(ctx context.Context, resourceGroupName, vmssName string) (armcompute.VirtualMachineScaleSet, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.Get")
 	defer done()
 
-	return ac.scalesets.Get(ctx, resourceGroupName, vmssName, "")
+	resp, err := ac.scalesets.Get(ctx, resourceGroupName, vmssName, nil)
+	if err != nil {
+		return armcompute.VirtualMachineScaleSet{}, err
+	}
+	return resp.VirtualMachineScaleSet, nil
 }
 
-// CreateOrUpdateAsync the operation to create or update a virtual machine scale set without waiting for the operation
-// to complete.
-func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, vmssName string, vmss compute.VirtualMachineScaleSet) (*infrav1.Future, error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.CreateOrUpdateAsync")
+// GetGalleryImageVersion retrieves an Azure Compute Gallery image version with its replication status. The gallery may
+// be in another subscription than the scale set.
+func (ac *AzureClient) GetGalleryImageVersion(ctx context.Context, subscriptionID, resourceGroupName, galleryName, imageName, versionName string) (armcompute.GalleryImageVersion, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.GetGalleryImageVersion")
 	defer done()
 
-	future, err := ac.scalesets.CreateOrUpdate(ctx, resourceGroupName, vmssName, vmss)
+	opts, err := azure.ARMClientOptions(ac.auth.CloudEnvironment())
 	if err != nil {
-		return nil, err
+		return armcompute.GalleryImageVersion{}, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armcompute.NewGalleryImageVersionsClient(subscriptionID, ac.auth.Token(), opts)
+	if err != nil {
+		return armcompute.GalleryImageVersion{}, errors.Wrap(err, "failed to create gallery image versions client")
 	}
+	resp, err := c.Get(ctx, resourceGroupName, galleryName, imageName, versionName, &armcompute.GalleryImageVersionsClientGetOptions{
+		Expand: to.Ptr(armcompute.ReplicationStatusTypesReplicationStatus),
+	})
+	if err != nil {
+		return armcompute.GalleryImageVersion{}, err
+	}
+	return resp.GalleryImageVersion, nil
+}
 
-	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
-	defer cancel()
+// CreateOrUpdateAsync the operation to create or update a virtual machine scale set without waiting for the operation
+// to complete.
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupName, vmssName string, vmss armcompute.VirtualMachineScaleSet) (*infrav1.Future, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.CreateOrUpdateAsync")
+	defer done()
 
-	err = future.WaitForCompletionRef(ctx, ac.scalesets.Client)
+	poller, err := ac.scalesets.BeginCreateOrUpdate(ctx, resourceGroupName, vmssName, vmss, nil)
 	if err != nil {
-		// if an error occurs, return the future.
-		// this means the long-running operation didn't finish in the specified timeout.
-		return converters.SDKToFuture(&future, infrav1.PutFuture, serviceName, vmssName, resourceGroupName)
+		return nil, err
 	}
 
 	// todo: this returns the result VMSS, we should use it
-	_, err = future.Result(ac.scalesets)
-
-	// if the operation completed, return a nil future.
-	return nil, err
+	return pollUntilDoneOrFuture(ctx, poller, infrav1.PutFuture, vmssName, resourceGroupName)
 }
 
 // UpdateAsync update a VM scale set without waiting for the result of the operation. UpdateAsync sends a PATCH
@@ -178,107 +171,103 @@ func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, resourceGroupNam
 // Parameters:
 //   resourceGroupName - the name of the resource group.
 //   vmssName - the name of the VM scale set to create or update. parameters - the scale set object.
-func (ac *AzureClient) UpdateAsync(ctx context.Context, resourceGroupName, vmssName string, parameters compute.VirtualMachineScaleSetUpdate) (*infrav1.Future, error) {
+func (ac *AzureClient) UpdateAsync(ctx context.Context, resourceGroupName, vmssName string, parameters armcompute.VirtualMachineScaleSetUpdate) (*infrav1.Future, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.UpdateAsync")
 	defer done()
 
-	future, err := ac.scalesets.Update(ctx, resourceGroupName, vmssName, parameters)
+	poller, err := ac.scalesets.BeginUpdate(ctx, resourceGroupName, vmssName, parameters, nil)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed updating vmss named %q", vmssName)
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
-	defer cancel()
-
-	err = future.WaitForCompletionRef(ctx, ac.scalesets.Client)
-	if err != nil {
-		// if an error occurs, return the future.
-		// this means the long-running operation didn't finish in the specified timeout.
-		return converters.SDKToFuture(&future, infrav1.PatchFuture, serviceName, vmssName, resourceGroupName)
-	}
 	// todo: this returns the result VMSS, we should use it
-	_, err = future.Result(ac.scalesets)
-
-	// if the operation completed, return a nil future.
-	return nil, err
+	return pollUntilDoneOrFuture(ctx, poller, infrav1.PatchFuture, vmssName, resourceGroupName)
 }
 
 // GetResultIfDone fetches the result of a long-running operation future if it is done.
-func (ac *AzureClient) GetResultIfDone(ctx context.Context, future *infrav1.Future) (compute.VirtualMachineScaleSet, error) {
-	var genericFuture genericScaleSetFuture
-	futureData, err := base64.URLEncoding.DecodeString(future.Data)
+func (ac *AzureClient) GetResultIfDone(ctx context.Context, future *infrav1.Future) (armcompute.VirtualMachineScaleSet, error) {
+	resumeToken, err := converters.FutureToResumeToken(*future)
 	if err != nil {
-		return compute.VirtualMachineScaleSet{}, errors.Wrap(err, "failed to base64 decode future data")
+		return armcompute.VirtualMachineScaleSet{}, err
 	}
 
+	// The scale set of the operation is part of the resume token, so it doesn't need to be passed again.
 	switch future.Type {
 	case infrav1.PatchFuture:
-		var future compute.VirtualMachineScaleSetsUpdateFuture
-		if err := json.Unmarshal(futureData, &future); err != nil {
-			return compute.VirtualMachineScaleSet{}, errors.Wrap(err, "failed to unmarshal future data")
-		}
-
-		genericFuture = &genericScaleSetFutureImpl{
-			FutureAPI: &future,
-			result:    future.Result,
+		poller, err := ac.scalesets.BeginUpdate(ctx, future.ResourceGroup, future.Name, armcompute.VirtualMachineScaleSetUpdate{},
+			&armcompute.VirtualMachineScaleSetsClientBeginUpdateOptions{ResumeToken: resumeToken})
+		if err != nil {
+			return armcompute.VirtualMachineScaleSet{}, errors.Wrap(err, "failed to resume the operation")
 		}
+		resp, err := resultIfDone(ctx, future, poller)
+		return resp.VirtualMachineScaleSet, err
 	case infrav1.PutFuture:
-		var future compute.VirtualMachineScaleSetsCreateOrUpdateFuture
-		if err := json.Unmarshal(futureData, &future); err != nil {
-			return compute.VirtualMachineScaleSet{}, errors.Wrap(err, "failed to unmarshal future data")
-		}
-
-		genericFuture = &genericScaleSetFutureImpl{
-			FutureAPI: &future,
-			result:    future.Result,
+		poller, err := ac.scalesets.BeginCreateOrUpdate(ctx, future.ResourceGroup, future.Name, armcompute.VirtualMachineScaleSet{},
+			&armcompute.VirtualMachineScaleSetsClientBeginCreateOrUpdateOptions{ResumeToken: resumeToken})
+		if err != nil {
+			return armcompute.VirtualMachineScaleSet{}, errors.Wrap(err, "failed to resume the operation")
 		}
+		resp, err := resultIfDone(ctx, future, poller)
+		return resp.VirtualMachineScaleSet, err
 	case infrav1.DeleteFuture:
-		var future compute.VirtualMachineScaleSetsDeleteFuture
-		if err := json.Unmarshal(futureData, &future); err != nil {
-			return compute.VirtualMachineScaleSet{}, errors.Wrap(err, "failed to unmarshal future data")
-		}
-
-		genericFuture = &deleteResultAdapter{
-			VirtualMachineScaleSetsDeleteFuture: future,
+		poller, err := ac.scalesets.BeginDelete(ctx, future.ResourceGroup, future.Name,
+			&armcompute.VirtualMachineScaleSetsClientBeginDeleteOptions{ResumeToken: resumeToken})
+		if err != nil {
+			return armcompute.VirtualMachineScaleSet{}, errors.Wrap(err, "failed to resume the operation")
 		}
+		// The only thing we care about is if the delete was successful. If it wasn't, an error will be returned.
+		_, err = resultIfDone(ctx, future, poller)
+		return armcompute.VirtualMachineScaleSet{}, err
 	default:
-		return compute.VirtualMachineScaleSet{}, errors.Errorf("unknown future type %q", future.Type)
+		return armcompute.VirtualMachineScaleSet{}, errors.Errorf("unknown future type %q", future.Type)
 	}
+}
 
-	done, err := genericFuture.DoneWithContext(ctx, ac.scalesets)
-	if err != nil {
-		return compute.VirtualMachineScaleSet{}, errors.Wrap(err, "failed checking if the operation was complete")
-	}
+// UpdateInstances updates instances of a VM scale set to the latest model of the scale set.
+// It does not wait for the instances to be updated, their state is observed by the following reconciliations.
+func (ac *AzureClient) UpdateInstances(ctx context.Context, resourceGroupName, vmssName string, instanceIDs []string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.UpdateInstances")
+	defer done()
 
-	if !done {
-		return compute.VirtualMachineScaleSet{}, azure.WithTransientError(azure.NewOperationNotDoneError(future), 15*time.Second)
-	}
+	_, err := ac.scalesets.BeginUpdateInstances(ctx, resourceGroupName, vmssName, armcompute.VirtualMachineScaleSetVMInstanceRequiredIDs{
+		InstanceIDs: to.SliceOfPtrs(instanceIDs...),
+	}, nil)
+	return err
+}
 
-	vmss, err := genericFuture.Result(ac.scalesets)
-	if err != nil {
-		return vmss, errors.Wrap(err, "failed fetching the result of operation for vmss")
-	}
+// StartInstances starts stopped or deallocated instances of a VM scale set.
+// It does not wait for the instances to be running, their power state is observed by the following reconciliations.
+func (ac *AzureClient) StartInstances(ctx context.Context, resourceGroupName, vmssName string, instanceIDs []string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.StartInstances")
+	defer done()
 
-	return vmss, nil
+	_, err := ac.scalesets.BeginStart(ctx, resourceGroupName, vmssName, &armcompute.VirtualMachineScaleSetsClientBeginStartOptions{
+		VMInstanceIDs: &armcompute.VirtualMachineScaleSetVMInstanceIDs{InstanceIDs: to.SliceOfPtrs(instanceIDs...)},
+	})
+	return err
 }
 
-// UpdateInstances update instances of a VM scale set.
-func (ac *AzureClient) UpdateInstances(ctx context.Context, resourceGroupName, vmssName string, instanceIDs []string) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.UpdateInstances")
+// DeallocateInstances stops instances of a VM scale set and releases their compute resources, keeping their disks.
+// It does not wait for the instances to be deallocated, their power state is observed by the following reconciliations.
+func (ac *AzureClient) DeallocateInstances(ctx context.Context, resourceGroupName, vmssName string, instanceIDs []string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.DeallocateInstances")
 	defer done()
 
-	params := compute.VirtualMachineScaleSetVMInstanceRequiredIDs{
-		InstanceIds: &instanceIDs,
-	}
-	future, err := ac.scalesets.UpdateInstances(ctx, resourceGroupName, vmssName, params)
-	if err != nil {
-		return err
-	}
-	err = future.WaitForCompletionRef(ctx, ac.scalesets.Client)
-	if err != nil {
-		return err
-	}
-	_, err = future.Result(ac.scalesets)
+	_, err := ac.scalesets.BeginDeallocate(ctx, resourceGroupName, vmssName, &armcompute.VirtualMachineScaleSetsClientBeginDeallocateOptions{
+		VMInstanceIDs: &armcompute.VirtualMachineScaleSetVMInstanceIDs{InstanceIDs: to.SliceOfPtrs(instanceIDs...)},
+	})
+	return err
+}
+
+// DeleteInstances deletes instances of a VM scale set, lowering its capacity.
+// It does not wait for the instances to be deleted, they are removed from the scale set by the following reconciliations.
+func (ac *AzureClient) DeleteInstances(ctx context.Context, resourceGroupName, vmssName string, instanceIDs []string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.DeleteInstances")
+	defer done()
+
+	_, err := ac.scalesets.BeginDeleteInstances(ctx, resourceGroupName, vmssName, armcompute.VirtualMachineScaleSetVMInstanceRequiredIDs{
+		InstanceIDs: to.SliceOfPtrs(instanceIDs...),
+	}, nil)
 	return err
 }
 
@@ -293,34 +282,56 @@ func (ac *AzureClient) DeleteAsync(ctx context.Context, resourceGroupName, vmssN
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.AzureClient.DeleteAsync")
 	defer done()
 
-	future, err := ac.scalesets.Delete(ctx, resourceGroupName, vmssName, to.BoolPtr(false))
+	poller, err := ac.scalesets.BeginDelete(ctx, resourceGroupName, vmssName, &armcompute.VirtualMachineScaleSetsClientBeginDeleteOptions{
+		ForceDeletion: to.Ptr(false),
+	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed deleting vmss named %q", vmssName)
 	}
 
+	return pollUntilDoneOrFuture(ctx, poller, infrav1.DeleteFuture, vmssName, resourceGroupName)
+}
+
+// pollUntilDoneOrFuture waits for a long-running operation to complete within the default Azure call timeout. If the
+// operation doesn't finish in time, it returns a Future storing the resume token of the poller instead of an error.
+func pollUntilDoneOrFuture[T any](ctx context.Context, poller *runtime.Poller[T], futureType, vmssName, resourceGroupName string) (*infrav1.Future, error) {
 	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
 	defer cancel()
 
-	err = future.WaitForCompletionRef(ctx, ac.scalesets.Client)
-	if err != nil {
+	if _, err := poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency}); err != nil {
+		if poller.Done() {
+			// the operation completed and failed.
+			return nil, err
+		}
 		// if an error occurs, return the future.
 		// this means the long-running operation didn't finish in the specified timeout.
-		return converters.SDKToFuture(&future, infrav1.DeleteFuture, serviceName, vmssName, resourceGroupName)
+		resumeToken, err := poller.ResumeToken()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get resume token of the operation")
+		}
+		return converters.ResumeTokenToFuture(resumeToken, futureType, serviceName, vmssName, resourceGroupName), nil
 	}
-	_, err = future.Result(ac.scalesets)
 
 	// if the operation completed, return a nil future.
-	return nil, err
+	return nil, nil
 }
 
-// Result wraps the delete result so that we can treat it generically. The only thing we care about is if the delete
-// was successful. If it wasn't, an error will be returned.
-func (da *deleteResultAdapter) Result(client compute.VirtualMachineScaleSetsClient) (compute.VirtualMachineScaleSet, error) {
-	_, err := da.VirtualMachineScaleSetsDeleteFuture.Result(client)
-	return compute.VirtualMachineScaleSet{}, err
-}
+// resultIfDone polls a long-running operation resumed from a future once, and returns its result if it is done.
+func resultIfDone[T any](ctx context.Context, future *infrav1.Future, poller *runtime.Poller[T]) (T, error) {
+	var result T
+	if _, err := poller.Poll(ctx); err != nil {
+		return result, errors.Wrap(err, "failed checking if the operation was complete")
+	}
+
+	if !poller.Done() {
+		inflight.WaitingOn(ctx, *future)
+		return result, azure.WithTransientError(azure.NewOperationNotDoneError(future), 15*time.Second)
+	}
+
+	result, err := poller.Result(ctx)
+	if err != nil {
+		return result, errors.Wrap(err, "failed fetching the result of operation for vmss")
+	}
 
-// Result returns the Result so that we can treat it generically.
-func (g *genericScaleSetFutureImpl) Result(client compute.VirtualMachineScaleSetsClient) (compute.VirtualMachineScaleSet, error) {
-	return g.result(client)
+	return result, nil
 }
@@ -23,8 +23,9 @@ package mock_scalesets
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
-	autorest "github.com/Azure/go-autorest/autorest"
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	gomock "github.com/golang/mock/gomock"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -68,18 +69,18 @@ func (mr *MockScaleSetScopeMockRecorder) AdditionalTags() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockScaleSetScope)(nil).AdditionalTags))
 }
 
-// Authorizer mocks base method.
-func (m *MockScaleSetScope) Authorizer() autorest.Authorizer {
+// AttachedACRs mocks base method.
+func (m *MockScaleSetScope) AttachedACRs() []string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authorizer")
-	ret0, _ := ret[0].(autorest.Authorizer)
+	ret := m.ctrl.Call(m, "AttachedACRs")
+	ret0, _ := ret[0].([]string)
 	return ret0
 }
 
-// Authorizer indicates an expected call of Authorizer.
-func (mr *MockScaleSetScopeMockRecorder) Authorizer() *gomock.Call {
+// AttachedACRs indicates an expected call of AttachedACRs.
+func (mr *MockScaleSetScopeMockRecorder) AttachedACRs() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockScaleSetScope)(nil).Authorizer))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachedACRs", reflect.TypeOf((*MockScaleSetScope)(nil).AttachedACRs))
 }
 
 // AvailabilitySetEnabled mocks base method.
@@ -96,6 +97,20 @@ func (mr *MockScaleSetScopeMockRecorder) AvailabilitySetEnabled() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AvailabilitySetEnabled", reflect.TypeOf((*MockScaleSetScope)(nil).AvailabilitySetEnabled))
 }
 
+// BackOffZones mocks base method.
+func (m *MockScaleSetScope) BackOffZones(arg0 context.Context, arg1 []string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BackOffZones", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BackOffZones indicates an expected call of BackOffZones.
+func (mr *MockScaleSetScopeMockRecorder) BackOffZones(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackOffZones", reflect.TypeOf((*MockScaleSetScope)(nil).BackOffZones), arg0, arg1, arg2)
+}
+
 // BaseURI mocks base method.
 func (m *MockScaleSetScope) BaseURI() string {
 	m.ctrl.T.Helper()
@@ -110,6 +125,34 @@ func (mr *MockScaleSetScopeMockRecorder) BaseURI() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockScaleSetScope)(nil).BaseURI))
 }
 
+// BootstrapDataStorage mocks base method.
+func (m *MockScaleSetScope) BootstrapDataStorage() *v1beta1.BootstrapDataStorage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapDataStorage")
+	ret0, _ := ret[0].(*v1beta1.BootstrapDataStorage)
+	return ret0
+}
+
+// BootstrapDataStorage indicates an expected call of BootstrapDataStorage.
+func (mr *MockScaleSetScopeMockRecorder) BootstrapDataStorage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapDataStorage", reflect.TypeOf((*MockScaleSetScope)(nil).BootstrapDataStorage))
+}
+
+// BootstrapExtension mocks base method.
+func (m *MockScaleSetScope) BootstrapExtension() *v1beta1.BootstrapExtension {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BootstrapExtension")
+	ret0, _ := ret[0].(*v1beta1.BootstrapExtension)
+	return ret0
+}
+
+// BootstrapExtension indicates an expected call of BootstrapExtension.
+func (mr *MockScaleSetScopeMockRecorder) BootstrapExtension() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BootstrapExtension", reflect.TypeOf((*MockScaleSetScope)(nil).BootstrapExtension))
+}
+
 // ClientID mocks base method.
 func (m *MockScaleSetScope) ClientID() string {
 	m.ctrl.T.Helper()
@@ -180,6 +223,20 @@ func (mr *MockScaleSetScopeMockRecorder) ClusterName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockScaleSetScope)(nil).ClusterName))
 }
 
+// CrossSubscriptionAccess mocks base method.
+func (m *MockScaleSetScope) CrossSubscriptionAccess() v1beta1.CrossSubscriptionAccessPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CrossSubscriptionAccess")
+	ret0, _ := ret[0].(v1beta1.CrossSubscriptionAccessPolicy)
+	return ret0
+}
+
+// CrossSubscriptionAccess indicates an expected call of CrossSubscriptionAccess.
+func (mr *MockScaleSetScopeMockRecorder) CrossSubscriptionAccess() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CrossSubscriptionAccess", reflect.TypeOf((*MockScaleSetScope)(nil).CrossSubscriptionAccess))
+}
+
 // DeleteLongRunningOperationState mocks base method.
 func (m *MockScaleSetScope) DeleteLongRunningOperationState(arg0, arg1 string) {
 	m.ctrl.T.Helper()
@@ -192,6 +249,34 @@ func (mr *MockScaleSetScopeMockRecorder) DeleteLongRunningOperationState(arg0, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockScaleSetScope)(nil).DeleteLongRunningOperationState), arg0, arg1)
 }
 
+// DesiredPowerState mocks base method.
+func (m *MockScaleSetScope) DesiredPowerState() v1beta1.PowerState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DesiredPowerState")
+	ret0, _ := ret[0].(v1beta1.PowerState)
+	return ret0
+}
+
+// DesiredPowerState indicates an expected call of DesiredPowerState.
+func (mr *MockScaleSetScopeMockRecorder) DesiredPowerState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DesiredPowerState", reflect.TypeOf((*MockScaleSetScope)(nil).DesiredPowerState))
+}
+
+// ExtendedLocation mocks base method.
+func (m *MockScaleSetScope) ExtendedLocation() *v1beta1.ExtendedLocationSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendedLocation")
+	ret0, _ := ret[0].(*v1beta1.ExtendedLocationSpec)
+	return ret0
+}
+
+// ExtendedLocation indicates an expected call of ExtendedLocation.
+func (mr *MockScaleSetScopeMockRecorder) ExtendedLocation() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendedLocation", reflect.TypeOf((*MockScaleSetScope)(nil).ExtendedLocation))
+}
+
 // FailureDomains mocks base method.
 func (m *MockScaleSetScope) FailureDomains() []string {
 	m.ctrl.T.Helper()
@@ -206,6 +291,20 @@ func (mr *MockScaleSetScopeMockRecorder) FailureDomains() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailureDomains", reflect.TypeOf((*MockScaleSetScope)(nil).FailureDomains))
 }
 
+// FallBackPlacement mocks base method.
+func (m *MockScaleSetScope) FallBackPlacement(arg0 context.Context, arg1 error) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FallBackPlacement", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// FallBackPlacement indicates an expected call of FallBackPlacement.
+func (mr *MockScaleSetScopeMockRecorder) FallBackPlacement(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FallBackPlacement", reflect.TypeOf((*MockScaleSetScope)(nil).FallBackPlacement), arg0, arg1)
+}
+
 // GetAnnotation mocks base method.
 func (m *MockScaleSetScope) GetAnnotation(arg0 string) (string, bool) {
 	m.ctrl.T.Helper()
@@ -250,6 +349,36 @@ func (mr *MockScaleSetScopeMockRecorder) GetLongRunningOperationState(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongRunningOperationState", reflect.TypeOf((*MockScaleSetScope)(nil).GetLongRunningOperationState), arg0, arg1)
 }
 
+// GetSSHPublicKey mocks base method.
+func (m *MockScaleSetScope) GetSSHPublicKey(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSSHPublicKey", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSSHPublicKey indicates an expected call of GetSSHPublicKey.
+func (mr *MockScaleSetScopeMockRecorder) GetSSHPublicKey(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSSHPublicKey", reflect.TypeOf((*MockScaleSetScope)(nil).GetSSHPublicKey), arg0)
+}
+
+// GetUserData mocks base method.
+func (m *MockScaleSetScope) GetUserData(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserData", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserData indicates an expected call of GetUserData.
+func (mr *MockScaleSetScopeMockRecorder) GetUserData(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserData", reflect.TypeOf((*MockScaleSetScope)(nil).GetUserData), arg0)
+}
+
 // GetVMImage mocks base method.
 func (m *MockScaleSetScope) GetVMImage(arg0 context.Context) (*v1beta1.Image, error) {
 	m.ctrl.T.Helper()
@@ -308,6 +437,62 @@ func (mr *MockScaleSetScopeMockRecorder) MaxSurge() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxSurge", reflect.TypeOf((*MockScaleSetScope)(nil).MaxSurge))
 }
 
+// Monitoring mocks base method.
+func (m *MockScaleSetScope) Monitoring() *v1beta1.AzureMonitoring {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Monitoring")
+	ret0, _ := ret[0].(*v1beta1.AzureMonitoring)
+	return ret0
+}
+
+// Monitoring indicates an expected call of Monitoring.
+func (mr *MockScaleSetScopeMockRecorder) Monitoring() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Monitoring", reflect.TypeOf((*MockScaleSetScope)(nil).Monitoring))
+}
+
+// ProxyConfig mocks base method.
+func (m *MockScaleSetScope) ProxyConfig() *v1beta1.ProxyConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProxyConfig")
+	ret0, _ := ret[0].(*v1beta1.ProxyConfig)
+	return ret0
+}
+
+// ProxyConfig indicates an expected call of ProxyConfig.
+func (mr *MockScaleSetScopeMockRecorder) ProxyConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyConfig", reflect.TypeOf((*MockScaleSetScope)(nil).ProxyConfig))
+}
+
+// RegistryMirrors mocks base method.
+func (m *MockScaleSetScope) RegistryMirrors() []v1beta1.RegistryMirror {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegistryMirrors")
+	ret0, _ := ret[0].([]v1beta1.RegistryMirror)
+	return ret0
+}
+
+// RegistryMirrors indicates an expected call of RegistryMirrors.
+func (mr *MockScaleSetScopeMockRecorder) RegistryMirrors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegistryMirrors", reflect.TypeOf((*MockScaleSetScope)(nil).RegistryMirrors))
+}
+
+// ReplicaProviderIDs mocks base method.
+func (m *MockScaleSetScope) ReplicaProviderIDs() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplicaProviderIDs")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// ReplicaProviderIDs indicates an expected call of ReplicaProviderIDs.
+func (mr *MockScaleSetScopeMockRecorder) ReplicaProviderIDs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplicaProviderIDs", reflect.TypeOf((*MockScaleSetScope)(nil).ReplicaProviderIDs))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockScaleSetScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -348,6 +533,20 @@ func (mr *MockScaleSetScopeMockRecorder) ScaleSetSpec() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleSetSpec", reflect.TypeOf((*MockScaleSetScope)(nil).ScaleSetSpec))
 }
 
+// SecurityProfile mocks base method.
+func (m *MockScaleSetScope) SecurityProfile() *v1beta1.ClusterSecurityProfile {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SecurityProfile")
+	ret0, _ := ret[0].(*v1beta1.ClusterSecurityProfile)
+	return ret0
+}
+
+// SecurityProfile indicates an expected call of SecurityProfile.
+func (mr *MockScaleSetScopeMockRecorder) SecurityProfile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SecurityProfile", reflect.TypeOf((*MockScaleSetScope)(nil).SecurityProfile))
+}
+
 // SetAnnotation mocks base method.
 func (m *MockScaleSetScope) SetAnnotation(arg0, arg1 string) {
 	m.ctrl.T.Helper()
@@ -360,6 +559,30 @@ func (mr *MockScaleSetScopeMockRecorder) SetAnnotation(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAnnotation", reflect.TypeOf((*MockScaleSetScope)(nil).SetAnnotation), arg0, arg1)
 }
 
+// SetHibernated mocks base method.
+func (m *MockScaleSetScope) SetHibernated(arg0 bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetHibernated", arg0)
+}
+
+// SetHibernated indicates an expected call of SetHibernated.
+func (mr *MockScaleSetScopeMockRecorder) SetHibernated(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHibernated", reflect.TypeOf((*MockScaleSetScope)(nil).SetHibernated), arg0)
+}
+
+// SetImageReplicatedCondition mocks base method.
+func (m *MockScaleSetScope) SetImageReplicatedCondition(arg0 string, arg1 v1beta10.ConditionSeverity, arg2 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetImageReplicatedCondition", arg0, arg1, arg2)
+}
+
+// SetImageReplicatedCondition indicates an expected call of SetImageReplicatedCondition.
+func (mr *MockScaleSetScopeMockRecorder) SetImageReplicatedCondition(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageReplicatedCondition", reflect.TypeOf((*MockScaleSetScope)(nil).SetImageReplicatedCondition), arg0, arg1, arg2)
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockScaleSetScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -424,6 +647,20 @@ func (mr *MockScaleSetScopeMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockScaleSetScope)(nil).TenantID))
 }
 
+// Token mocks base method.
+func (m *MockScaleSetScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockScaleSetScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockScaleSetScope)(nil).Token))
+}
+
 // UpdateDeleteStatus mocks base method.
 func (m *MockScaleSetScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
 	m.ctrl.T.Helper()
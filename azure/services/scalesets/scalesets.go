@@ -20,29 +20,51 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"k8s.io/utils/pointer"
 	"strconv"
+	"strings"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/util/generators"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/slice"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 const serviceName = "scalesets"
 
+// placementFallbackRequeue is how long to wait before retrying with the next placement after an allocation failure.
+const placementFallbackRequeue = 5 * time.Second
+
+// imageReplicationRequeue is how long to wait before checking again the replication of a gallery image version.
+const imageReplicationRequeue = 30 * time.Second
+
+// powerStateRequeue is how long to wait before checking again the power state of the instances of a scale set started
+// after the hibernation of its cluster.
+const powerStateRequeue = 30 * time.Second
+
+// dataDiskUpdateRequeue is how long to wait before updating the scale set again after starting to update the data disks
+// of its instances.
+const dataDiskUpdateRequeue = 30 * time.Second
+
 type (
 	// ScaleSetScope defines the scope interface for a scale sets service.
 	ScaleSetScope interface {
 		azure.ClusterDescriber
 		azure.AsyncStatusUpdater
 		GetBootstrapData(context.Context) (string, error)
+		GetUserData(context.Context) (string, error)
+		GetSSHPublicKey(context.Context) (string, error)
 		GetVMImage(context.Context) (*infrav1.Image, error)
 		SaveVMImageToStatus(*infrav1.Image)
 		MaxSurge() (int, error)
@@ -53,6 +75,12 @@ type (
 		SetProviderID(string)
 		SetVMSSState(*azure.VMSS)
 		UpdateScaleSetReplicas(context.Context, *azure.VMSS) error
+		ReplicaProviderIDs() []string
+		FallBackPlacement(context.Context, error) bool
+		BackOffZones(context.Context, []string, time.Time) error
+		SetImageReplicatedCondition(string, clusterv1.ConditionSeverity, string)
+		DesiredPowerState() infrav1.PowerState
+		SetHibernated(bool)
 	}
 
 	// Service provides operations on Azure resources.
@@ -64,12 +92,16 @@ type (
 )
 
 // New creates a new service.
-func New(scope ScaleSetScope, skuCache *resourceskus.Cache) *Service {
+func New(scope ScaleSetScope, skuCache *resourceskus.Cache) (*Service, error) {
+	client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
-		Client:           NewClient(scope),
+		Client:           client,
 		Scope:            scope,
 		resourceSKUCache: skuCache,
-	}
+	}, nil
 }
 
 // Name returns the service name.
@@ -101,7 +133,11 @@ func (s *Service) Reconcile(ctx context.Context) (retErr error) {
 		// save the updated state of the VMSS for the MachinePoolScope to use for updating K8s state
 		if fetchedVMSS == nil {
 			fetchedVMSS, err = s.getVirtualMachineScaleSet(ctx, scaleSetSpec.Name)
-			if err != nil && !azure.ResourceNotFound(err) {
+			switch {
+			case converters.IsInvalidSDKData(err) && retErr == nil:
+				// the state of the scale set would be updated from data which can't be trusted.
+				retErr = errors.Wrap(err, "failed to get vmss in deferred update")
+			case err != nil && !azure.ResourceNotFound(err):
 				log.Error(err, "failed to get vmss in deferred update")
 			}
 		}
@@ -116,6 +152,9 @@ func (s *Service) Reconcile(ctx context.Context) (retErr error) {
 		fetchedVMSS, err = s.getVirtualMachineScaleSet(ctx, scaleSetSpec.Name)
 	} else {
 		fetchedVMSS, err = s.getVirtualMachineScaleSetIfDone(ctx, future)
+		if azure.AllocationFailure(err) {
+			return s.handleAllocationFailure(ctx, future, err)
+		}
 	}
 
 	switch {
@@ -126,25 +165,48 @@ func (s *Service) Reconcile(ctx context.Context) (retErr error) {
 		// HTTP(404) resource was not found, so we need to create it with a PUT
 		future, err = s.createVMSS(ctx)
 		if err != nil {
-			return errors.Wrap(err, "failed to start creating VMSS")
+			err = errors.Wrap(err, "failed to start creating VMSS")
+			if azure.AllocationFailure(err) {
+				return s.fallBackPlacement(ctx, err)
+			}
+			return err
 		}
 	case err == nil:
 		// HTTP(200)
-		if s.replicasManagedByAutoscaler() {
+		if s.replicasManagedByAutoscaler() && !capacityOutsideSchedule(scaleSetSpec, fetchedVMSS.Capacity) {
 			if fetchedVMSS.Capacity != s.Scope.ScaleSetSpec().Capacity {
 				err := s.Scope.UpdateScaleSetReplicas(ctx, fetchedVMSS)
 				if err != nil {
 					return errors.Wrap(err, "failed to update MachinePool Replicas")
 				}
-				log.Info("Updated MachinePool Replicas, requeing", "AzureMachinePool", scaleSetSpec.Name, "ScaleSetCapacity", scaleSetSpec.Capacity, "fetchedVMSSCapacity", fetchedVMSS.Capacity)
+				log.Info("Updated MachinePool Replicas, requeing", tele.LogKeyResource, scaleSetSpec.Name, "ScaleSetCapacity", scaleSetSpec.Capacity, "fetchedVMSSCapacity", fetchedVMSS.Capacity)
 				return nil
 			}
 		}
+		hibernated, err := s.reconcilePowerState(ctx, fetchedVMSS)
+		if err != nil {
+			return err
+		}
+		if hibernated {
+			// the instances of a hibernated cluster are kept deallocated, the scale set is updated once it resumes
+			break
+		}
+		// start warm instances before scaling out, so they are adopted as replicas before new instances are created
+		if err := s.reconcileWarmPool(ctx, fetchedVMSS); err != nil {
+			return errors.Wrap(err, "failed to reconcile the warm pool")
+		}
+		if err := s.updateDataDisksInPlace(ctx, fetchedVMSS); err != nil {
+			return err
+		}
 		// VMSS already exists and may have changes; update it with a PATCH
 		// we do this to avoid overwriting fields in networkProfile modified by cloud-provider
 		future, err = s.patchVMSSIfNeeded(ctx, fetchedVMSS)
 		if err != nil {
-			return errors.Wrap(err, "failed to start updating VMSS")
+			err = errors.Wrap(err, "failed to start updating VMSS")
+			if azure.AllocationFailure(err) {
+				return s.fallBackPlacement(ctx, err)
+			}
+			return err
 		}
 	}
 
@@ -152,6 +214,9 @@ func (s *Service) Reconcile(ctx context.Context) (retErr error) {
 	// running operation, getVirtualMachineScaleSetIfDone will return an azure.WithTransientError and requeue.
 	if future != nil {
 		fetchedVMSS, err = s.getVirtualMachineScaleSetIfDone(ctx, future)
+		if azure.AllocationFailure(err) {
+			return s.handleAllocationFailure(ctx, future, err)
+		}
 		if err != nil {
 			return errors.Wrapf(err, "failed to get VMSS %s after create or update", scaleSetSpec.Name)
 		}
@@ -166,8 +231,72 @@ func (s *Service) Reconcile(ctx context.Context) (retErr error) {
 	return nil
 }
 
-// replicasManagedByAutoscaler checks if the replica count of AzureMachinePool is managed by autoscaler.
+// fallBackPlacement moves the scale set to its next placement after a failure to allocate its VMs, and requeues to
+// retry with it. It returns err if the AzureMachinePool has no placement fallbacks.
+func (s *Service) fallBackPlacement(ctx context.Context, err error) error {
+	s.backOffZones(ctx, err)
+	if !s.Scope.FallBackPlacement(ctx, err) {
+		return err
+	}
+	return azure.WithTransientError(errors.Wrap(err, "falling back to the next placement"), placementFallbackRequeue)
+}
+
+// handleAllocationFailure handles a long-running operation on the scale set that failed to allocate its VMs. The
+// scale set moves to its next placement: a scale set that failed to be created is deleted, so that it is recreated in
+// the zones of the placement, while an existing scale set is updated with its VM size by the next reconciliation.
+func (s *Service) handleAllocationFailure(ctx context.Context, future *infrav1.Future, err error) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.handleAllocationFailure")
+	defer done()
+
+	err = errors.Wrapf(err, "failed to allocate the VMs of VMSS %s", future.Name)
+	s.backOffZones(ctx, err)
+	if !s.Scope.FallBackPlacement(ctx, err) {
+		return err
+	}
+	s.Scope.DeleteLongRunningOperationState(future.Name, serviceName)
+
+	if future.Type == infrav1.PutFuture {
+		log.V(2).Info("deleting VMSS that failed to be created, to recreate it with the next placement", tele.LogKeyResource, future.Name)
+		deleteFuture, derr := s.Client.DeleteAsync(ctx, future.ResourceGroup, future.Name)
+		if derr != nil && !azure.ResourceNotFound(derr) {
+			return errors.Wrapf(derr, "failed to delete VMSS %s after it failed to be created", future.Name)
+		}
+		if deleteFuture != nil {
+			// the scale set is deleted regardless of the maximum number of operations in flight, to free its placement
+			async.TrackOperation(serviceName, deleteFuture.ResourceGroup, deleteFuture.Name)
+			s.Scope.SetLongRunningOperationState(deleteFuture)
+		}
+	}
+
+	return azure.WithTransientError(errors.Wrap(err, "falling back to the next placement"), placementFallbackRequeue)
+}
+
+// backOffZones records the zones of the scale set on the MachinePool after a failure to allocate its VMs for lack of
+// capacity in its zones, so that the autoscaler prefers other node groups over retrying them. It must be called before
+// the scale set falls back to its next placement, which may change its zones.
+func (s *Service) backOffZones(ctx context.Context, err error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.backOffZones")
+	defer done()
+
+	if !azure.ZonalAllocationFailure(err) {
+		return
+	}
+	zones := s.Scope.ScaleSetSpec().FailureDomains
+	if len(zones) == 0 {
+		return
+	}
+	if berr := s.Scope.BackOffZones(ctx, zones, time.Now()); berr != nil {
+		log.Error(berr, "failed to back off the zones of the scale set", "zones", zones)
+	}
+}
+
+// replicasManagedByAutoscaler checks if the replica count of AzureMachinePool is managed by autoscaler, either the
+// cluster-autoscaler or the Azure Monitor autoscale setting of the scale set.
 func (s *Service) replicasManagedByAutoscaler() bool {
+	if s.Scope.ScaleSetSpec().Autoscaled {
+		return true
+	}
+
 	if value, _ := s.Scope.GetAnnotation(azure.ReplicasManagedByAutoscalerAnnotation); value == "true" {
 		return true
 	}
@@ -175,6 +304,208 @@ func (s *Service) replicasManagedByAutoscaler() bool {
 	return false
 }
 
+// capacityOutsideSchedule returns true if the capacity of a scale set is outside the bounds of the active window of
+// its scaling schedule, which then takes precedence over the autoscaler managing its replicas.
+func capacityOutsideSchedule(spec azure.ScaleSetSpec, capacity int64) bool {
+	return (spec.MinCapacity != nil && capacity < *spec.MinCapacity) || (spec.MaxCapacity != nil && capacity > *spec.MaxCapacity)
+}
+
+// reconcileWarmPool keeps the instances of a scale set that exceed its replicas deallocated, up to the size of the warm
+// pool. Warm instances are started when replicas are missing, and outdated warm instances are deleted so that the next
+// scale out replaces them with instances running the latest model.
+func (s *Service) reconcileWarmPool(ctx context.Context, vmss *azure.VMSS) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.reconcileWarmPool")
+	defer done()
+
+	spec := s.Scope.ScaleSetSpec()
+	if spec.WarmPoolSize == 0 {
+		return nil
+	}
+
+	replicaIDs := make(map[string]struct{})
+	for _, providerID := range s.Scope.ReplicaProviderIDs() {
+		replicaIDs[providerID] = struct{}{}
+	}
+
+	var (
+		replicas         int64
+		warm, candidates []azure.VMSSVM
+		toStart, toStop  []string
+		toDelete         []string
+	)
+	for _, instance := range vmss.Instances {
+		if _, ok := replicaIDs[instance.ProviderID()]; ok {
+			replicas++
+			continue
+		}
+
+		switch {
+		case instance.IsDeallocated() && !vmss.HasLatestModelApplied(instance):
+			toDelete = append(toDelete, instance.InstanceID)
+		case instance.IsDeallocated():
+			warm = append(warm, instance)
+		case instance.State == infrav1.Succeeded && instance.PowerState == infrav1.PowerStateRunning:
+			candidates = append(candidates, instance)
+		}
+	}
+
+	// running instances fill the missing replicas first, the oldest ones being adopted by the MachinePoolScope
+	azure.SortByInstanceID(candidates)
+	missing := spec.Capacity - spec.WarmPoolSize - replicas
+	adopted := minInt64(missing, int64(len(candidates)))
+	if adopted < 0 {
+		adopted = 0
+	}
+	missing -= adopted
+
+	azure.SortByInstanceID(warm)
+	for _, instance := range warm {
+		if missing <= 0 {
+			break
+		}
+		if instance.PowerState == infrav1.PowerStateDeallocating {
+			continue
+		}
+		toStart = append(toStart, instance.InstanceID)
+		missing--
+	}
+
+	// the surplus of running instances refills the warm pool
+	warmCount := int64(len(warm) - len(toStart))
+	for _, instance := range candidates[adopted:] {
+		if warmCount >= spec.WarmPoolSize {
+			break
+		}
+		toStop = append(toStop, instance.InstanceID)
+		warmCount++
+	}
+
+	if len(toStart) > 0 {
+		log.V(2).Info("starting warm instances", "instanceIDs", toStart)
+		if err := s.Client.StartInstances(ctx, s.Scope.ResourceGroup(), spec.Name, toStart); err != nil {
+			return errors.Wrap(err, "failed to start warm instances")
+		}
+	}
+
+	if len(toStop) > 0 {
+		log.V(2).Info("deallocating instances into the warm pool", "instanceIDs", toStop)
+		if err := s.Client.DeallocateInstances(ctx, s.Scope.ResourceGroup(), spec.Name, toStop); err != nil {
+			return errors.Wrap(err, "failed to deallocate instances into the warm pool")
+		}
+	}
+
+	if len(toDelete) > 0 {
+		log.V(2).Info("deleting outdated warm instances", "instanceIDs", toDelete)
+		if err := s.Client.DeleteInstances(ctx, s.Scope.ResourceGroup(), spec.Name, toDelete); err != nil {
+			return errors.Wrap(err, "failed to delete outdated warm instances")
+		}
+	}
+
+	return nil
+}
+
+// reconcilePowerState keeps the instances of a scale set in the power state required by the hibernation of its cluster.
+// The instances of a hibernated cluster are deallocated, in which case it returns true. When the cluster resumes, the
+// deallocated replicas are started, while the instances of the warm pool are kept deallocated, and it requeues until
+// they are all started.
+func (s *Service) reconcilePowerState(ctx context.Context, vmss *azure.VMSS) (bool, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.reconcilePowerState")
+	defer done()
+
+	spec := s.Scope.ScaleSetSpec()
+	switch s.Scope.DesiredPowerState() {
+	case infrav1.PowerStateDeallocated:
+		var toDeallocate []string
+		for _, instance := range vmss.Instances {
+			if !instance.IsDeallocated() {
+				toDeallocate = append(toDeallocate, instance.InstanceID)
+			}
+		}
+		if len(toDeallocate) > 0 {
+			log.V(2).Info("deallocating instances of the hibernated cluster", "instanceIDs", toDeallocate)
+			if err := s.Client.DeallocateInstances(ctx, s.Scope.ResourceGroup(), spec.Name, toDeallocate); err != nil {
+				return true, errors.Wrap(err, "failed to deallocate the instances of the hibernated cluster")
+			}
+		}
+		return true, nil
+	case infrav1.PowerStateRunning:
+		replicaIDs := make(map[string]struct{})
+		for _, providerID := range s.Scope.ReplicaProviderIDs() {
+			replicaIDs[providerID] = struct{}{}
+		}
+
+		var toStart []string
+		deallocating := false
+		for _, instance := range vmss.Instances {
+			if _, ok := replicaIDs[instance.ProviderID()]; !ok && spec.WarmPoolSize > 0 {
+				continue
+			}
+			switch instance.PowerState {
+			case infrav1.PowerStateDeallocating:
+				deallocating = true
+			case infrav1.PowerStateDeallocated, infrav1.PowerStateHibernated:
+				toStart = append(toStart, instance.InstanceID)
+			}
+		}
+		if len(toStart) == 0 && !deallocating {
+			s.Scope.SetHibernated(false)
+			return false, nil
+		}
+
+		if len(toStart) > 0 {
+			log.V(2).Info("starting instances of the resumed cluster", "instanceIDs", toStart)
+			if err := s.Client.StartInstances(ctx, s.Scope.ResourceGroup(), spec.Name, toStart); err != nil {
+				return false, errors.Wrap(err, "failed to start the instances of the resumed cluster")
+			}
+		}
+		return false, azure.WithTransientError(errors.Errorf("starting the instances of vmss %s", spec.Name), powerStateRequeue)
+	}
+
+	return false, nil
+}
+
+// updateDataDisksInPlace updates the instances whose data disks differ from the model of the scale set to the latest
+// model, which attaches the data disks added to the model and detaches the removed ones without replacing the
+// instances. Instances running an outdated image are left to the rolling update, as are all the instances when the
+// AzureMachinePool rolls out model updates. It requeues once the instances are being updated, so that the scale set
+// isn't updated concurrently.
+func (s *Service) updateDataDisksInPlace(ctx context.Context, vmss *azure.VMSS) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.updateDataDisksInPlace")
+	defer done()
+
+	spec := s.Scope.ScaleSetSpec()
+	if spec.RolloutModelUpdates || vmss.State != infrav1.Succeeded {
+		return nil
+	}
+
+	var toUpdate []string
+	for _, instance := range vmss.Instances {
+		if instance.State == infrav1.Succeeded && vmss.HasLatestModelApplied(instance) && vmss.HasDataDiskChanges(instance) {
+			toUpdate = append(toUpdate, instance.InstanceID)
+		}
+	}
+	if len(toUpdate) == 0 {
+		return nil
+	}
+
+	log.V(2).Info("updating the data disks of instances in place", "instanceIDs", toUpdate)
+	if err := s.Client.UpdateInstances(ctx, s.Scope.ResourceGroup(), spec.Name, toUpdate); err != nil {
+		if azure.ResourceConflict(err) {
+			return azure.WithTransientError(err, dataDiskUpdateRequeue)
+		}
+		return errors.Wrap(err, "failed to update the data disks of instances")
+	}
+
+	return azure.WithTransientError(errors.Errorf("updating the data disks of %d instances of vmss %s", len(toUpdate), spec.Name), dataDiskUpdateRequeue)
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
 // Delete deletes a scale set asynchronously. Delete sends a DELETE request to Azure and if accepted without error,
 // the VMSS will be considered deleted. The actual delete in Azure may take longer, but should eventually complete.
 func (s *Service) Delete(ctx context.Context) error {
@@ -201,7 +532,7 @@ func (s *Service) Delete(ctx context.Context) error {
 	future := s.Scope.GetLongRunningOperationState(vmssSpec.Name, serviceName)
 	if future != nil {
 		// if the operation is not complete this will return an error
-		_, err := s.GetResultIfDone(ctx, future)
+		_, err := s.getResultIfDone(ctx, future)
 		if err != nil {
 			return errors.Wrap(err, "failed to get result from future")
 		}
@@ -215,9 +546,13 @@ func (s *Service) Delete(ctx context.Context) error {
 	}
 
 	// no long running delete operation is active, so delete the ScaleSet
-	log.V(2).Info("deleting VMSS", "scale set", vmssSpec.Name)
+	if !async.AcquireOperation(serviceName, s.Scope.ResourceGroup(), vmssSpec.Name) {
+		return azure.WithTransientError(async.ErrTooManyOperations, reconciler.DefaultReconcilerRequeue)
+	}
+	log.V(2).Info("deleting VMSS", tele.LogKeyResource, vmssSpec.Name)
 	future, err = s.Client.DeleteAsync(ctx, s.Scope.ResourceGroup(), vmssSpec.Name)
 	if err != nil {
+		async.ReleaseOperation(serviceName, s.Scope.ResourceGroup(), vmssSpec.Name)
 		if azure.ResourceNotFound(err) {
 			// already deleted
 			return nil
@@ -228,12 +563,13 @@ func (s *Service) Delete(ctx context.Context) error {
 	s.Scope.SetLongRunningOperationState(future)
 	if future != nil {
 		// if future exists, check state of the future
-		if _, err = s.GetResultIfDone(ctx, future); err != nil {
+		if _, err = s.getResultIfDone(ctx, future); err != nil {
 			return errors.Wrap(err, "not done with long running operation, or failed to get result")
 		}
 	}
 
 	// future is either nil, or the result of the future is complete
+	async.ReleaseOperation(serviceName, s.Scope.ResourceGroup(), vmssSpec.Name)
 	s.Scope.DeleteLongRunningOperationState(vmssSpec.Name, serviceName)
 	// Note: we want to handle UpdateDeleteStatus when VMSSExtensions have an error when scalesets become an async service
 	s.Scope.UpdateDeleteStatus(infrav1.BootstrapSucceededCondition, serviceName, nil)
@@ -252,12 +588,20 @@ func (s *Service) createVMSS(ctx context.Context) (*infrav1.Future, error) {
 		return nil, errors.Wrap(err, "failed building VMSS from spec")
 	}
 
+	if err := s.waitForImageReplication(ctx, vmss.Properties.VirtualMachineProfile.StorageProfile.ImageReference); err != nil {
+		return nil, err
+	}
+
+	if !async.AcquireOperation(serviceName, s.Scope.ResourceGroup(), spec.Name) {
+		return nil, azure.WithTransientError(async.ErrTooManyOperations, reconciler.DefaultReconcilerRequeue)
+	}
 	future, err := s.Client.CreateOrUpdateAsync(ctx, s.Scope.ResourceGroup(), spec.Name, vmss)
 	if err != nil {
+		async.ReleaseOperation(serviceName, s.Scope.ResourceGroup(), spec.Name)
 		return nil, errors.Wrap(err, "cannot create VMSS")
 	}
 
-	log.V(2).Info("starting to create VMSS", "scale set", spec.Name)
+	log.V(2).Info("starting to create VMSS", tele.LogKeyResource, spec.Name)
 	s.Scope.SetLongRunningOperationState(future)
 	return future, err
 }
@@ -273,6 +617,9 @@ func (s *Service) patchVMSSIfNeeded(ctx context.Context, infraVMSS *azure.VMSS)
 		return nil, errors.Wrapf(err, "failed to generate scale set update parameters for %s", spec.Name)
 	}
 
+	// the zones of an existing scale set can't change, e.g. after falling back to a placement in other zones
+	vmss.Zones = to.SliceOfPtrs(infraVMSS.Zones...)
+
 	patch, err := getVMSSUpdateFromVMSS(vmss)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to generate vmss patch for %s", spec.Name)
@@ -283,12 +630,35 @@ func (s *Service) patchVMSSIfNeeded(ctx context.Context, infraVMSS *azure.VMSS)
 		return nil, errors.Wrap(err, "failed to calculate maxSurge")
 	}
 
-	hasModelChanges := hasModelModifyingDifferences(infraVMSS, vmss)
-	if maxSurge > 0 && (hasModelChanges || !infraVMSS.HasEnoughLatestModelOrNotMixedModel()) {
+	// roll the scale set to a new image only once it can be used in the location of the scale set
+	hasImageChanges, err := hasImageDifferences(infraVMSS, vmss)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compare the image of vmss %s", spec.Name)
+	}
+	if hasImageChanges {
+		if err := s.waitForImageReplication(ctx, vmss.Properties.VirtualMachineProfile.StorageProfile.ImageReference); err != nil {
+			return nil, err
+		}
+	}
+
+	hasModelChanges, err := hasModelModifyingDifferences(infraVMSS, vmss)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compare the model of vmss %s", spec.Name)
+	}
+	// instances whose data disks alone differ from the model are updated in place rather than replaced, so that only
+	// the other model changes need a surge
+	surgeForModelChanges := hasModelChanges
+	if hasModelChanges && !spec.RolloutModelUpdates {
+		surgeForModelChanges, err = hasModelModifyingDifferencesBesidesDataDisks(infraVMSS, vmss)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compare the model of vmss %s", spec.Name)
+		}
+	}
+	if maxSurge > 0 && (surgeForModelChanges || !infraVMSS.HasEnoughLatestModelOrNotMixedModel()) {
 		// surge capacity with the intention of lowering during instance reconciliation
 		surge := spec.Capacity + int64(maxSurge)
 		log.V(4).Info("surging...", "surge", surge)
-		patch.Sku.Capacity = to.Int64Ptr(surge)
+		patch.SKU.Capacity = to.Ptr[int64](surge)
 	}
 
 	if s.replicasManagedByAutoscaler() {
@@ -297,18 +667,22 @@ func (s *Service) patchVMSSIfNeeded(ctx context.Context, infraVMSS *azure.VMSS)
 
 	// If there are no model changes and no increase in the replica count, do not update the VMSS.
 	// Decreases in replica count is handled by deleting AzureMachinePoolMachine instances in the MachinePoolScope
-	if *patch.Sku.Capacity <= infraVMSS.Capacity && !hasModelChanges {
-		log.V(4).Info("nothing to update on vmss", "scale set", spec.Name, "newReplicas", *patch.Sku.Capacity, "oldReplicas", infraVMSS.Capacity, "hasChanges", hasModelChanges)
+	if *patch.SKU.Capacity <= infraVMSS.Capacity && !hasModelChanges {
+		log.V(4).Info("nothing to update on vmss", tele.LogKeyResource, spec.Name, "newReplicas", *patch.SKU.Capacity, "oldReplicas", infraVMSS.Capacity, "hasChanges", hasModelChanges)
 		return nil, nil
 	}
 
-	if s.replicasManagedByAutoscaler() {
-		patch.Sku.Capacity = nil
+	if s.replicasManagedByAutoscaler() && !capacityOutsideSchedule(spec, infraVMSS.Capacity) {
+		patch.SKU.Capacity = nil
 	}
 
-	log.V(4).Info("patching vmss", "scale set", spec.Name, "patch", patch)
+	if !async.AcquireOperation(serviceName, s.Scope.ResourceGroup(), spec.Name) {
+		return nil, azure.WithTransientError(async.ErrTooManyOperations, reconciler.DefaultReconcilerRequeue)
+	}
+	log.V(4).Info("patching vmss", tele.LogKeyResource, spec.Name, "patch", patch)
 	future, err := s.UpdateAsync(ctx, s.Scope.ResourceGroup(), spec.Name, patch)
 	if err != nil {
+		async.ReleaseOperation(serviceName, s.Scope.ResourceGroup(), spec.Name)
 		if azure.ResourceConflict(err) {
 			return nil, azure.WithTransientError(err, 30*time.Second)
 		}
@@ -316,13 +690,96 @@ func (s *Service) patchVMSSIfNeeded(ctx context.Context, infraVMSS *azure.VMSS)
 	}
 
 	s.Scope.SetLongRunningOperationState(future)
-	log.V(2).Info("successfully started to update vmss", "scale set", spec.Name)
+	log.V(2).Info("successfully started to update vmss", tele.LogKeyResource, spec.Name)
 	return future, err
 }
 
-func hasModelModifyingDifferences(infraVMSS *azure.VMSS, vmss compute.VirtualMachineScaleSet) bool {
-	other := converters.SDKToVMSS(vmss, []compute.VirtualMachineScaleSetVM{})
-	return infraVMSS.HasModelChanges(*other)
+func hasModelModifyingDifferences(infraVMSS *azure.VMSS, vmss armcompute.VirtualMachineScaleSet) (bool, error) {
+	other, err := converters.SDKToVMSS(vmss, []armcompute.VirtualMachineScaleSetVM{})
+	if err != nil {
+		return false, err
+	}
+	return infraVMSS.HasModelChanges(*other), nil
+}
+
+func hasModelModifyingDifferencesBesidesDataDisks(infraVMSS *azure.VMSS, vmss armcompute.VirtualMachineScaleSet) (bool, error) {
+	other, err := converters.SDKToVMSS(vmss, []armcompute.VirtualMachineScaleSetVM{})
+	if err != nil {
+		return false, err
+	}
+	other.DataDisks = infraVMSS.DataDisks
+	return infraVMSS.HasModelChanges(*other), nil
+}
+
+func hasImageDifferences(infraVMSS *azure.VMSS, vmss armcompute.VirtualMachineScaleSet) (bool, error) {
+	other, err := converters.SDKToVMSS(vmss, []armcompute.VirtualMachineScaleSetVM{})
+	if err != nil {
+		return false, err
+	}
+	return !cmp.Equal(infraVMSS.Image, other.Image), nil
+}
+
+// waitForImageReplication gates creating a scale set or rolling it to an Azure Compute Gallery image version until the
+// version has completed its replication to the location of the scale set, as its VMs fail to be created with the image
+// not found in the region otherwise. The replication status of a region whose replicas are stored in zone-redundant
+// storage completes once the version is replicated to all the zones of the region. Images other than private gallery
+// image versions, such as Marketplace images and community gallery images, aren't checked.
+func (s *Service) waitForImageReplication(ctx context.Context, imageRef *armcompute.ImageReference) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.waitForImageReplication")
+	defer done()
+
+	if imageRef == nil || imageRef.ID == nil {
+		return nil
+	}
+	resourceID, err := azure.ParseResourceID(*imageRef.ID)
+	if err != nil || !azure.IsResourceType(resourceID, azure.GalleryImageVersionResourceType) || strings.EqualFold(resourceID.Name, "latest") {
+		return nil
+	}
+
+	version, err := s.Client.GetGalleryImageVersion(ctx, resourceID.SubscriptionID, resourceID.ResourceGroupName, resourceID.Parent.Parent.Name, resourceID.Parent.Name, resourceID.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get gallery image version %s", *imageRef.ID)
+	}
+
+	location := s.Scope.Location()
+	status := regionalReplicationStatus(version, location)
+	var state armcompute.ReplicationState
+	if status != nil && status.State != nil {
+		state = *status.State
+	}
+	switch {
+	case status == nil:
+		err := errors.Errorf("gallery image version %s is not replicated to location %s", *imageRef.ID, location)
+		s.Scope.SetImageReplicatedCondition(infrav1.ImageNotReplicatedReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	case state == armcompute.ReplicationStateCompleted:
+		s.Scope.SetImageReplicatedCondition("", "", "")
+		return nil
+	case state == armcompute.ReplicationStateFailed:
+		err := errors.Errorf("gallery image version %s failed to replicate to location %s: %s", *imageRef.ID, location, pointer.StringDeref(status.Details, ""))
+		s.Scope.SetImageReplicatedCondition(infrav1.ImageReplicationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	default:
+		err := errors.Errorf("gallery image version %s is replicating to location %s (%d%%)", *imageRef.ID, location, pointer.Int32Deref(status.Progress, 0))
+		log.V(2).Info("waiting for the image replication", tele.LogKeyResourceID, *imageRef.ID, "location", location, "progress", pointer.Int32Deref(status.Progress, 0))
+		s.Scope.SetImageReplicatedCondition(infrav1.ImageReplicatingReason, clusterv1.ConditionSeverityInfo, err.Error())
+		return azure.WithTransientError(err, imageReplicationRequeue)
+	}
+}
+
+// regionalReplicationStatus returns the replication status of a gallery image version in a location, or nil if the
+// location isn't one of the target regions of the version. The regions of the replication status are display names,
+// such as "East US" for the "eastus" location.
+func regionalReplicationStatus(version armcompute.GalleryImageVersion, location string) *armcompute.RegionalReplicationStatus {
+	if version.Properties == nil || version.Properties.ReplicationStatus == nil {
+		return nil
+	}
+	for _, status := range version.Properties.ReplicationStatus.Summary {
+		if status != nil && strings.EqualFold(strings.ReplaceAll(pointer.StringDeref(status.Region, ""), " ", ""), location) {
+			return status
+		}
+	}
+	return nil
 }
 
 func (s *Service) validateSpec(ctx context.Context) error {
@@ -333,7 +790,13 @@ func (s *Service) validateSpec(ctx context.Context) error {
 
 	sku, err := s.resourceSKUCache.Get(ctx, spec.Size, resourceskus.VirtualMachines)
 	if err != nil {
-		return errors.Wrapf(err, "failed to get SKU %s in compute api", spec.Size)
+		err = errors.Wrapf(err, "failed to get SKU %s in compute api", spec.Size)
+		var reconcileErr azure.ReconcileError
+		if errors.As(err, &reconcileErr) && reconcileErr.IsTerminal() {
+			// the VM size isn't available in the location
+			return s.fallBackPlacement(ctx, err)
+		}
+		return err
 	}
 
 	// Checking if the requested VM size has at least 2 vCPUS
@@ -365,6 +828,10 @@ func (s *Service) validateSpec(ctx context.Context) error {
 		return azure.WithTerminalError(errors.Errorf("encryption at host is not supported for VM type %s", spec.Size))
 	}
 
+	if spec.AdditionalCapabilities != nil && pointer.BoolDeref(spec.AdditionalCapabilities.HibernationEnabled, false) && !sku.HasCapability(resourceskus.HibernationSupported) {
+		return azure.WithTerminalError(fmt.Errorf("vm size %s does not support hibernation. select a different vm size or disable hibernation", spec.Size))
+	}
+
 	// Fetch location and zone to check for their support of ultra disks.
 	location := s.Scope.Location()
 	zones, err := s.resourceSKUCache.GetZones(ctx, location)
@@ -379,7 +846,7 @@ func (s *Service) validateSpec(ctx context.Context) error {
 		// Check support for ultra disks as data disks.
 		for _, disks := range spec.DataDisks {
 			if disks.ManagedDisk != nil &&
-				disks.ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) &&
+				disks.ManagedDisk.StorageAccountType == string(armcompute.StorageAccountTypesUltraSSDLRS) &&
 				!hasLocationCapability {
 				return azure.WithTerminalError(err)
 			}
@@ -401,20 +868,20 @@ func (s *Service) validateSpec(ctx context.Context) error {
 
 	for _, az := range spec.FailureDomains {
 		if !slice.Contains(azsInLocation, az) {
-			return azure.WithTerminalError(errors.Errorf("availability zone %s is not available for VM type %s in location %s", az, spec.Size, s.Scope.Location()))
+			return s.fallBackPlacement(ctx, azure.WithTerminalError(errors.Errorf("availability zone %s is not available for VM type %s in location %s", az, spec.Size, s.Scope.Location())))
 		}
 	}
 
 	return nil
 }
 
-func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSetSpec) (compute.VirtualMachineScaleSet, error) {
+func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSetSpec) (armcompute.VirtualMachineScaleSet, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.buildVMSSFromSpec")
 	defer done()
 
 	sku, err := s.resourceSKUCache.Get(ctx, vmssSpec.Size, resourceskus.VirtualMachines)
 	if err != nil {
-		return compute.VirtualMachineScaleSet{}, errors.Wrapf(err, "failed to get find SKU %s in compute api", vmssSpec.Size)
+		return armcompute.VirtualMachineScaleSet{}, errors.Wrapf(err, "failed to get find SKU %s in compute api", vmssSpec.Size)
 	}
 
 	if vmssSpec.AcceleratedNetworking == nil {
@@ -425,168 +892,189 @@ func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSet
 
 	extensions, err := s.generateExtensions()
 	if err != nil {
-		return compute.VirtualMachineScaleSet{}, err
+		return armcompute.VirtualMachineScaleSet{}, err
 	}
 
 	storageProfile, err := s.generateStorageProfile(ctx, vmssSpec, sku)
 	if err != nil {
-		return compute.VirtualMachineScaleSet{}, err
+		return armcompute.VirtualMachineScaleSet{}, err
 	}
 
 	securityProfile, err := getSecurityProfile(vmssSpec, sku)
 	if err != nil {
-		return compute.VirtualMachineScaleSet{}, err
+		return armcompute.VirtualMachineScaleSet{}, err
 	}
 
 	priority, evictionPolicy, billingProfile, err := converters.GetSpotVMOptions(vmssSpec.SpotVMOptions)
 	if err != nil {
-		return compute.VirtualMachineScaleSet{}, errors.Wrapf(err, "failed to get Spot VM options")
+		return armcompute.VirtualMachineScaleSet{}, errors.Wrapf(err, "failed to get Spot VM options")
 	}
 
 	// Get the node outbound LB backend pool ID
-	var backendAddressPools []compute.SubResource
+	var backendAddressPools []*armcompute.SubResource
 	if vmssSpec.PublicLBName != "" {
 		if vmssSpec.PublicLBAddressPoolName != "" {
 			backendAddressPools = append(backendAddressPools,
-				compute.SubResource{
-					ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), vmssSpec.PublicLBName, vmssSpec.PublicLBAddressPoolName)),
+				&armcompute.SubResource{
+					ID: to.Ptr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), vmssSpec.PublicLBName, vmssSpec.PublicLBAddressPoolName)),
 				})
 		}
 	}
 
 	osProfile, err := s.generateOSProfile(ctx, vmssSpec)
 	if err != nil {
-		return compute.VirtualMachineScaleSet{}, err
+		return armcompute.VirtualMachineScaleSet{}, err
+	}
+
+	userData, err := s.Scope.GetUserData(ctx)
+	if err != nil {
+		return armcompute.VirtualMachineScaleSet{}, errors.Wrap(err, "failed to retrieve user data")
 	}
 
-	vmss := compute.VirtualMachineScaleSet{
-		Location: to.StringPtr(s.Scope.Location()),
-		Sku: &compute.Sku{
-			Name:     to.StringPtr(vmssSpec.Size),
-			Tier:     to.StringPtr("Standard"),
-			Capacity: to.Int64Ptr(vmssSpec.Capacity),
+	vmss := armcompute.VirtualMachineScaleSet{
+		Location:         to.Ptr(s.Scope.Location()),
+		ExtendedLocation: converters.ExtendedLocationToComputeSDK(s.Scope.ExtendedLocation()),
+		SKU: &armcompute.SKU{
+			Name:     to.Ptr(vmssSpec.Size),
+			Tier:     to.Ptr("Standard"),
+			Capacity: to.Ptr[int64](vmssSpec.Capacity),
 		},
-		Zones: to.StringSlicePtr(vmssSpec.FailureDomains),
-		Plan:  s.generateImagePlan(ctx),
-		VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
-			SinglePlacementGroup: to.BoolPtr(false),
-			UpgradePolicy: &compute.UpgradePolicy{
-				Mode: compute.UpgradeModeManual,
+		Plan: s.generateImagePlan(ctx),
+		Properties: &armcompute.VirtualMachineScaleSetProperties{
+			SinglePlacementGroup: to.Ptr(false),
+			UpgradePolicy: &armcompute.UpgradePolicy{
+				Mode: to.Ptr(armcompute.UpgradeModeManual),
 			},
-			Overprovision: to.BoolPtr(false),
-			VirtualMachineProfile: &compute.VirtualMachineScaleSetVMProfile{
-				NetworkProfile:  &compute.VirtualMachineScaleSetNetworkProfile{},
-				OsProfile:       osProfile,
+			Overprovision: to.Ptr(false),
+			VirtualMachineProfile: &armcompute.VirtualMachineScaleSetVMProfile{
+				NetworkProfile:  &armcompute.VirtualMachineScaleSetNetworkProfile{},
+				OSProfile:       osProfile,
 				StorageProfile:  storageProfile,
 				SecurityProfile: securityProfile,
-				DiagnosticsProfile: &compute.DiagnosticsProfile{
-					BootDiagnostics: &compute.BootDiagnostics{
-						Enabled: to.BoolPtr(true),
+				DiagnosticsProfile: &armcompute.DiagnosticsProfile{
+					BootDiagnostics: &armcompute.BootDiagnostics{
+						Enabled: to.Ptr(true),
 					},
 				},
 				Priority:       priority,
 				EvictionPolicy: evictionPolicy,
 				BillingProfile: billingProfile,
-				ExtensionProfile: &compute.VirtualMachineScaleSetExtensionProfile{
-					Extensions: &extensions,
+				ExtensionProfile: &armcompute.VirtualMachineScaleSetExtensionProfile{
+					Extensions: extensions,
 				},
 			},
 		},
 	}
 
+	if len(vmssSpec.FailureDomains) > 0 {
+		vmss.Zones = to.SliceOfPtrs(vmssSpec.FailureDomains...)
+	}
+
+	if userData != "" {
+		vmss.Properties.VirtualMachineProfile.UserData = to.Ptr(userData)
+	}
+
 	// Use custom NIC definitons in VMSS if set
 	if len(vmssSpec.NetworkInterfaces) > 0 {
-		nicConfigs := []compute.VirtualMachineScaleSetNetworkConfiguration{}
+		nicConfigs := []*armcompute.VirtualMachineScaleSetNetworkConfiguration{}
 		for i, n := range vmssSpec.NetworkInterfaces {
-			nicConfig := compute.VirtualMachineScaleSetNetworkConfiguration{}
-			nicConfig.VirtualMachineScaleSetNetworkConfigurationProperties = &compute.VirtualMachineScaleSetNetworkConfigurationProperties{}
-			nicConfig.Name = to.StringPtr(vmssSpec.Name + "-" + strconv.Itoa(i))
+			nicConfig := &armcompute.VirtualMachineScaleSetNetworkConfiguration{}
+			nicConfig.Properties = &armcompute.VirtualMachineScaleSetNetworkConfigurationProperties{}
+			nicConfig.Name = to.Ptr(vmssSpec.Name + "-" + strconv.Itoa(i))
 			if n.ID != "" {
 				nicConfig.ID = &n.ID
 			} else {
-				if to.Bool(n.AcceleratedNetworking) {
-					nicConfig.VirtualMachineScaleSetNetworkConfigurationProperties.EnableAcceleratedNetworking = to.BoolPtr(true)
+				if pointer.BoolDeref(n.AcceleratedNetworking, false) {
+					nicConfig.Properties.EnableAcceleratedNetworking = to.Ptr(true)
 				} else {
-					nicConfig.VirtualMachineScaleSetNetworkConfigurationProperties.EnableAcceleratedNetworking = to.BoolPtr(false)
+					nicConfig.Properties.EnableAcceleratedNetworking = to.Ptr(false)
+				}
+				if n.EnableIPForwarding != nil {
+					nicConfig.Properties.EnableIPForwarding = to.Ptr(*n.EnableIPForwarding)
+				}
+				if len(n.DNSServers) > 0 {
+					nicConfig.Properties.DNSSettings = &armcompute.VirtualMachineScaleSetNetworkConfigurationDNSSettings{
+						DNSServers: to.SliceOfPtrs(n.DNSServers...),
+					}
 				}
 				if n.PrivateIPConfigs == 0 && n.PublicIPConfigs == 0 {
-					nicConfig.VirtualMachineScaleSetNetworkConfigurationProperties.IPConfigurations = &[]compute.VirtualMachineScaleSetIPConfiguration{
+					nicConfig.Properties.IPConfigurations = []*armcompute.VirtualMachineScaleSetIPConfiguration{
 						{
-							Name: to.StringPtr(vmssSpec.Name + "-" + strconv.Itoa(i)),
-							VirtualMachineScaleSetIPConfigurationProperties: &compute.VirtualMachineScaleSetIPConfigurationProperties{
-								Subnet: &compute.APIEntityReference{
-									ID: to.StringPtr(azure.SubnetID(s.Scope.SubscriptionID(), vmssSpec.VNetResourceGroup, vmssSpec.VNetName, n.SubnetName)),
+							Name: to.Ptr(vmssSpec.Name + "-" + strconv.Itoa(i)),
+							Properties: &armcompute.VirtualMachineScaleSetIPConfigurationProperties{
+								Subnet: &armcompute.APIEntityReference{
+									ID: to.Ptr(azure.SubnetID(s.Scope.SubscriptionID(), vmssSpec.VNetResourceGroup, vmssSpec.VNetName, n.SubnetName)),
 								},
-								Primary:                         to.BoolPtr(true),
-								PrivateIPAddressVersion:         compute.IPVersionIPv4,
-								LoadBalancerBackendAddressPools: &backendAddressPools,
+								Primary:                         to.Ptr(true),
+								PrivateIPAddressVersion:         to.Ptr(armcompute.IPVersionIPv4),
+								LoadBalancerBackendAddressPools: backendAddressPools,
 							},
 						},
 					}
 				} else {
-					ipconfigs := []compute.VirtualMachineScaleSetIPConfiguration{}
+					ipconfigs := []*armcompute.VirtualMachineScaleSetIPConfiguration{}
 					for j := 0; j < n.PublicIPConfigs; j++ {
-						ipconfig := compute.VirtualMachineScaleSetIPConfiguration{
-							Name: to.StringPtr(fmt.Sprintf("public-ipConfig%v", j)),
-							VirtualMachineScaleSetIPConfigurationProperties: &compute.VirtualMachineScaleSetIPConfigurationProperties{
-								PrivateIPAddressVersion: compute.IPVersionIPv4,
-								Subnet: &compute.APIEntityReference{
-									ID: to.StringPtr(azure.SubnetID(s.Scope.SubscriptionID(), vmssSpec.VNetResourceGroup, vmssSpec.VNetName, n.SubnetName)),
+						ipconfig := &armcompute.VirtualMachineScaleSetIPConfiguration{
+							Name: to.Ptr(fmt.Sprintf("public-ipConfig%v", j)),
+							Properties: &armcompute.VirtualMachineScaleSetIPConfigurationProperties{
+								PrivateIPAddressVersion: to.Ptr(armcompute.IPVersionIPv4),
+								Subnet: &armcompute.APIEntityReference{
+									ID: to.Ptr(azure.SubnetID(s.Scope.SubscriptionID(), vmssSpec.VNetResourceGroup, vmssSpec.VNetName, n.SubnetName)),
 								},
-								PublicIPAddressConfiguration: &compute.VirtualMachineScaleSetPublicIPAddressConfiguration{},
+								PublicIPAddressConfiguration: &armcompute.VirtualMachineScaleSetPublicIPAddressConfiguration{},
 							},
 						}
-						ipconfig.Primary = to.BoolPtr(false)
-						ipconfig.Subnet = &compute.APIEntityReference{
-							ID: to.StringPtr(azure.SubnetID(s.Scope.SubscriptionID(), vmssSpec.VNetResourceGroup, vmssSpec.VNetName, n.SubnetName)),
+						ipconfig.Properties.Primary = to.Ptr(false)
+						ipconfig.Properties.Subnet = &armcompute.APIEntityReference{
+							ID: to.Ptr(azure.SubnetID(s.Scope.SubscriptionID(), vmssSpec.VNetResourceGroup, vmssSpec.VNetName, n.SubnetName)),
 						}
 						ipconfigs = append(ipconfigs, ipconfig)
 					}
 					// Create any remaining private IPConfigs
 					for j := 0; j < n.PrivateIPConfigs-n.PublicIPConfigs; j++ {
-						ipconfig := compute.VirtualMachineScaleSetIPConfiguration{
-							Name: to.StringPtr(fmt.Sprintf("private-ipConfig-%v", j)),
-							VirtualMachineScaleSetIPConfigurationProperties: &compute.VirtualMachineScaleSetIPConfigurationProperties{
-								PrivateIPAddressVersion: compute.IPVersionIPv4,
-								Subnet: &compute.APIEntityReference{
-									ID: to.StringPtr(azure.SubnetID(s.Scope.SubscriptionID(), vmssSpec.VNetResourceGroup, vmssSpec.VNetName, n.SubnetName)),
+						ipconfig := &armcompute.VirtualMachineScaleSetIPConfiguration{
+							Name: to.Ptr(fmt.Sprintf("private-ipConfig-%v", j)),
+							Properties: &armcompute.VirtualMachineScaleSetIPConfigurationProperties{
+								PrivateIPAddressVersion: to.Ptr(armcompute.IPVersionIPv4),
+								Subnet: &armcompute.APIEntityReference{
+									ID: to.Ptr(azure.SubnetID(s.Scope.SubscriptionID(), vmssSpec.VNetResourceGroup, vmssSpec.VNetName, n.SubnetName)),
 								},
 							},
 						}
-						ipconfig.Primary = to.BoolPtr(false)
-						ipconfig.Subnet = &compute.APIEntityReference{
-							ID: to.StringPtr(azure.SubnetID(s.Scope.SubscriptionID(), vmssSpec.VNetResourceGroup, vmssSpec.VNetName, n.SubnetName)),
+						ipconfig.Properties.Primary = to.Ptr(false)
+						ipconfig.Properties.Subnet = &armcompute.APIEntityReference{
+							ID: to.Ptr(azure.SubnetID(s.Scope.SubscriptionID(), vmssSpec.VNetResourceGroup, vmssSpec.VNetName, n.SubnetName)),
 						}
 						ipconfigs = append(ipconfigs, ipconfig)
 					}
 					if i == 0 {
-						ipconfigs[0].LoadBalancerBackendAddressPools = &backendAddressPools
+						ipconfigs[0].Properties.LoadBalancerBackendAddressPools = backendAddressPools
 					}
-					ipconfigs[0].Primary = to.BoolPtr(true)
-					nicConfig.VirtualMachineScaleSetNetworkConfigurationProperties.IPConfigurations = &ipconfigs
+					ipconfigs[0].Properties.Primary = to.Ptr(true)
+					nicConfig.Properties.IPConfigurations = ipconfigs
 				}
 			}
 			nicConfigs = append(nicConfigs, nicConfig)
 		}
-		nicConfigs[0].VirtualMachineScaleSetNetworkConfigurationProperties.Primary = to.BoolPtr(true)
-		vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations = &nicConfigs
+		nicConfigs[0].Properties.Primary = to.Ptr(true)
+		vmss.Properties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations = nicConfigs
 	} else {
 		// Set default interface configuration if no custom ones are specified
-		vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations = s.getVirtualMachineScaleSetDefaultNetworkConfiguration(vmssSpec)
+		vmss.Properties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations = s.getVirtualMachineScaleSetDefaultNetworkConfiguration(vmssSpec)
 	}
 
 	// Assign Identity to VMSS
 	if vmssSpec.Identity == infrav1.VMIdentitySystemAssigned {
-		vmss.Identity = &compute.VirtualMachineScaleSetIdentity{
-			Type: compute.ResourceIdentityTypeSystemAssigned,
+		vmss.Identity = &armcompute.VirtualMachineScaleSetIdentity{
+			Type: to.Ptr(armcompute.ResourceIdentityTypeSystemAssigned),
 		}
 	} else if vmssSpec.Identity == infrav1.VMIdentityUserAssigned {
 		userIdentitiesMap, err := converters.UserAssignedIdentitiesToVMSSSDK(vmssSpec.UserAssignedIdentities)
 		if err != nil {
 			return vmss, errors.Wrapf(err, "failed to assign identity %q", vmssSpec.Name)
 		}
-		vmss.Identity = &compute.VirtualMachineScaleSetIdentity{
-			Type:                   compute.ResourceIdentityTypeUserAssigned,
+		vmss.Identity = &armcompute.VirtualMachineScaleSetIdentity{
+			Type:                   to.Ptr(armcompute.ResourceIdentityTypeUserAssigned),
 			UserAssignedIdentities: userIdentitiesMap,
 		}
 	}
@@ -594,26 +1082,33 @@ func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSet
 	// Provisionally detect whether there is any Data Disk defined which uses UltraSSDs.
 	// If that's the case, enable the UltraSSD capability.
 	for _, dataDisk := range vmssSpec.DataDisks {
-		if dataDisk.ManagedDisk != nil && dataDisk.ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) {
-			vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{
-				UltraSSDEnabled: to.BoolPtr(true),
+		if dataDisk.ManagedDisk != nil && dataDisk.ManagedDisk.StorageAccountType == string(armcompute.StorageAccountTypesUltraSSDLRS) {
+			vmss.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{
+				UltraSSDEnabled: to.Ptr(true),
 			}
 		}
 	}
 
 	// Set Additional Capabilities if any is present on the spec.
 	if vmssSpec.AdditionalCapabilities != nil {
+		if vmss.Properties.AdditionalCapabilities == nil {
+			vmss.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{}
+		}
 		// Set UltraSSDEnabled if a specific value is set on the spec for it.
 		if vmssSpec.AdditionalCapabilities.UltraSSDEnabled != nil {
-			vmss.AdditionalCapabilities.UltraSSDEnabled = vmssSpec.AdditionalCapabilities.UltraSSDEnabled
+			vmss.Properties.AdditionalCapabilities.UltraSSDEnabled = vmssSpec.AdditionalCapabilities.UltraSSDEnabled
+		}
+		// Set HibernationEnabled if a specific value is set on the spec for it.
+		if vmssSpec.AdditionalCapabilities.HibernationEnabled != nil {
+			vmss.Properties.AdditionalCapabilities.HibernationEnabled = vmssSpec.AdditionalCapabilities.HibernationEnabled
 		}
 	}
 
 	if vmssSpec.TerminateNotificationTimeout != nil {
-		vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.ScheduledEventsProfile = &compute.ScheduledEventsProfile{
-			TerminateNotificationProfile: &compute.TerminateNotificationProfile{
-				NotBeforeTimeout: to.StringPtr(fmt.Sprintf("PT%dM", *vmssSpec.TerminateNotificationTimeout)),
-				Enable:           to.BoolPtr(true),
+		vmss.Properties.VirtualMachineProfile.ScheduledEventsProfile = &armcompute.ScheduledEventsProfile{
+			TerminateNotificationProfile: &armcompute.TerminateNotificationProfile{
+				NotBeforeTimeout: to.Ptr(fmt.Sprintf("PT%dM", *vmssSpec.TerminateNotificationTimeout)),
+				Enable:           to.Ptr(true),
 			},
 		}
 	}
@@ -621,40 +1116,43 @@ func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSet
 	tags := infrav1.Build(infrav1.BuildParams{
 		ClusterName: s.Scope.ClusterName(),
 		Lifecycle:   infrav1.ResourceLifecycleOwned,
-		Name:        to.StringPtr(vmssSpec.Name),
-		Role:        to.StringPtr(infrav1.Node),
+		Name:        to.Ptr(vmssSpec.Name),
+		Role:        to.Ptr(infrav1.Node),
 		Additional:  s.Scope.AdditionalTags(),
 	})
+	if vmssSpec.BootstrapDataSASExpiry != "" {
+		tags[infrav1.NameAzureBootstrapDataSASExpiry] = vmssSpec.BootstrapDataSASExpiry
+	}
 
 	vmss.Tags = converters.TagsToMap(tags)
 	return vmss, nil
 }
 
-func (s *Service) getVirtualMachineScaleSetDefaultNetworkConfiguration(vmssSpec azure.ScaleSetSpec) *[]compute.VirtualMachineScaleSetNetworkConfiguration {
-	var backendAddressPools []compute.SubResource
+func (s *Service) getVirtualMachineScaleSetDefaultNetworkConfiguration(vmssSpec azure.ScaleSetSpec) []*armcompute.VirtualMachineScaleSetNetworkConfiguration {
+	var backendAddressPools []*armcompute.SubResource
 	if vmssSpec.PublicLBName != "" {
 		if vmssSpec.PublicLBAddressPoolName != "" {
 			backendAddressPools = append(backendAddressPools,
-				compute.SubResource{
-					ID: to.StringPtr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), vmssSpec.PublicLBName, vmssSpec.PublicLBAddressPoolName)),
+				&armcompute.SubResource{
+					ID: to.Ptr(azure.AddressPoolID(s.Scope.SubscriptionID(), s.Scope.ResourceGroup(), vmssSpec.PublicLBName, vmssSpec.PublicLBAddressPoolName)),
 				})
 		}
 	}
-	return &[]compute.VirtualMachineScaleSetNetworkConfiguration{{
-		Name: to.StringPtr(vmssSpec.Name),
-		VirtualMachineScaleSetNetworkConfigurationProperties: &compute.VirtualMachineScaleSetNetworkConfigurationProperties{
-			Primary:            to.BoolPtr(true),
-			EnableIPForwarding: to.BoolPtr(true),
-			IPConfigurations: &[]compute.VirtualMachineScaleSetIPConfiguration{
+	return []*armcompute.VirtualMachineScaleSetNetworkConfiguration{{
+		Name: to.Ptr(vmssSpec.Name),
+		Properties: &armcompute.VirtualMachineScaleSetNetworkConfigurationProperties{
+			Primary:            to.Ptr(true),
+			EnableIPForwarding: to.Ptr(true),
+			IPConfigurations: []*armcompute.VirtualMachineScaleSetIPConfiguration{
 				{
-					Name: to.StringPtr(vmssSpec.Name),
-					VirtualMachineScaleSetIPConfigurationProperties: &compute.VirtualMachineScaleSetIPConfigurationProperties{
-						Subnet: &compute.APIEntityReference{
-							ID: to.StringPtr(azure.SubnetID(s.Scope.SubscriptionID(), vmssSpec.VNetResourceGroup, vmssSpec.VNetName, vmssSpec.SubnetName)),
+					Name: to.Ptr(vmssSpec.Name),
+					Properties: &armcompute.VirtualMachineScaleSetIPConfigurationProperties{
+						Subnet: &armcompute.APIEntityReference{
+							ID: to.Ptr(azure.SubnetID(s.Scope.SubscriptionID(), vmssSpec.VNetResourceGroup, vmssSpec.VNetName, vmssSpec.SubnetName)),
 						},
-						Primary:                         to.BoolPtr(true),
-						PrivateIPAddressVersion:         compute.IPVersionIPv4,
-						LoadBalancerBackendAddressPools: &backendAddressPools,
+						Primary:                         to.Ptr(true),
+						PrivateIPAddressVersion:         to.Ptr(armcompute.IPVersionIPv4),
+						LoadBalancerBackendAddressPools: backendAddressPools,
 					},
 				},
 			},
@@ -678,7 +1176,7 @@ func (s *Service) getVirtualMachineScaleSet(ctx context.Context, vmssName string
 		return nil, errors.Wrap(err, "failed to list instances")
 	}
 
-	return converters.SDKToVMSS(vmss, vmssInstances), nil
+	return converters.SDKToVMSS(vmss, vmssInstances)
 }
 
 // getVirtualMachineScaleSetIfDone gets a Virtual Machine Scale Set and its instances from Azure if the future is completed.
@@ -686,7 +1184,7 @@ func (s *Service) getVirtualMachineScaleSetIfDone(ctx context.Context, future *i
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.getVirtualMachineScaleSetIfDone")
 	defer done()
 
-	vmss, err := s.GetResultIfDone(ctx, future)
+	vmss, err := s.getResultIfDone(ctx, future)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get result from future")
 	}
@@ -696,81 +1194,123 @@ func (s *Service) getVirtualMachineScaleSetIfDone(ctx context.Context, future *i
 		return nil, errors.Wrap(err, "failed to list instances")
 	}
 
-	return converters.SDKToVMSS(vmss, vmssInstances), nil
+	return converters.SDKToVMSS(vmss, vmssInstances)
 }
 
-func (s *Service) generateExtensions() ([]compute.VirtualMachineScaleSetExtension, error) {
-	extensions := make([]compute.VirtualMachineScaleSetExtension, len(s.Scope.VMSSExtensionSpecs()))
+// getResultIfDone returns the result of the long-running operation of a future once it is done, and counts the operation
+// against the maximum number of long-running operations in flight until then.
+func (s *Service) getResultIfDone(ctx context.Context, future *infrav1.Future) (armcompute.VirtualMachineScaleSet, error) {
+	vmss, err := s.GetResultIfDone(ctx, future)
+	if azure.IsOperationNotDoneError(err) {
+		async.TrackOperation(serviceName, future.ResourceGroup, future.Name)
+	} else {
+		async.ReleaseOperation(serviceName, future.ResourceGroup, future.Name)
+	}
+	return vmss, err
+}
+
+func (s *Service) generateExtensions() ([]*armcompute.VirtualMachineScaleSetExtension, error) {
+	extensions := make([]*armcompute.VirtualMachineScaleSetExtension, len(s.Scope.VMSSExtensionSpecs()))
 	for i, extensionSpec := range s.Scope.VMSSExtensionSpecs() {
 		extensionSpec := extensionSpec
 		parameters, err := extensionSpec.Parameters(nil)
 		if err != nil {
 			return nil, err
 		}
-		vmssextension, ok := parameters.(compute.VirtualMachineScaleSetExtension)
+		vmssextension, ok := parameters.(armcompute.VirtualMachineScaleSetExtension)
 		if !ok {
-			return nil, errors.Errorf("%T is not a compute.VirtualMachineScaleSetExtension", parameters)
+			return nil, errors.Errorf("%T is not an armcompute.VirtualMachineScaleSetExtension", parameters)
 		}
-		extensions[i] = vmssextension
+		extensions[i] = &vmssextension
 	}
 
 	return extensions, nil
 }
 
-// generateStorageProfile generates a pointer to a compute.VirtualMachineScaleSetStorageProfile which can utilized for VM creation.
-func (s *Service) generateStorageProfile(ctx context.Context, vmssSpec azure.ScaleSetSpec, sku resourceskus.SKU) (*compute.VirtualMachineScaleSetStorageProfile, error) {
+// generateStorageProfile generates a pointer to an armcompute.VirtualMachineScaleSetStorageProfile which can utilized for VM creation.
+func (s *Service) generateStorageProfile(ctx context.Context, vmssSpec azure.ScaleSetSpec, sku resourceskus.SKU) (*armcompute.VirtualMachineScaleSetStorageProfile, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.generateStorageProfile")
 	defer done()
 
-	storageProfile := &compute.VirtualMachineScaleSetStorageProfile{
-		OsDisk: &compute.VirtualMachineScaleSetOSDisk{
-			OsType:       compute.OperatingSystemTypes(vmssSpec.OSDisk.OSType),
-			CreateOption: compute.DiskCreateOptionTypesFromImage,
-			DiskSizeGB:   vmssSpec.OSDisk.DiskSizeGB,
+	storageProfile := &armcompute.VirtualMachineScaleSetStorageProfile{
+		OSDisk: &armcompute.VirtualMachineScaleSetOSDisk{
+			OSType:                  to.Ptr(armcompute.OperatingSystemTypes(vmssSpec.OSDisk.OSType)),
+			CreateOption:            to.Ptr(armcompute.DiskCreateOptionTypesFromImage),
+			DiskSizeGB:              vmssSpec.OSDisk.DiskSizeGB,
+			WriteAcceleratorEnabled: vmssSpec.OSDisk.WriteAcceleratorEnabled,
 		},
 	}
 
+	if vmssSpec.OSDisk.CachingType != "" {
+		storageProfile.OSDisk.Caching = to.Ptr(armcompute.CachingTypes(vmssSpec.OSDisk.CachingType))
+	}
+
 	// enable ephemeral OS
 	if vmssSpec.OSDisk.DiffDiskSettings != nil {
 		if !sku.HasCapability(resourceskus.EphemeralOSDisk) {
 			return nil, fmt.Errorf("vm size %s does not support ephemeral os. select a different vm size or disable ephemeral os", vmssSpec.Size)
 		}
 
-		storageProfile.OsDisk.DiffDiskSettings = &compute.DiffDiskSettings{
-			Option: compute.DiffDiskOptions(vmssSpec.OSDisk.DiffDiskSettings.Option),
+		storageProfile.OSDisk.DiffDiskSettings = &armcompute.DiffDiskSettings{
+			Option: to.Ptr(armcompute.DiffDiskOptions(vmssSpec.OSDisk.DiffDiskSettings.Option)),
 		}
 	}
 
 	if vmssSpec.OSDisk.ManagedDisk != nil {
-		storageProfile.OsDisk.ManagedDisk = &compute.VirtualMachineScaleSetManagedDiskParameters{}
+		storageProfile.OSDisk.ManagedDisk = &armcompute.VirtualMachineScaleSetManagedDiskParameters{}
 		if vmssSpec.OSDisk.ManagedDisk.StorageAccountType != "" {
-			storageProfile.OsDisk.ManagedDisk.StorageAccountType = compute.StorageAccountTypes(vmssSpec.OSDisk.ManagedDisk.StorageAccountType)
+			storageProfile.OSDisk.ManagedDisk.StorageAccountType = to.Ptr(armcompute.StorageAccountTypes(vmssSpec.OSDisk.ManagedDisk.StorageAccountType))
 		}
 		if vmssSpec.OSDisk.ManagedDisk.DiskEncryptionSet != nil {
-			storageProfile.OsDisk.ManagedDisk.DiskEncryptionSet = &compute.DiskEncryptionSetParameters{ID: to.StringPtr(vmssSpec.OSDisk.ManagedDisk.DiskEncryptionSet.ID)}
+			storageProfile.OSDisk.ManagedDisk.DiskEncryptionSet = &armcompute.DiskEncryptionSetParameters{ID: to.Ptr(vmssSpec.OSDisk.ManagedDisk.DiskEncryptionSet.ID)}
 		}
 	}
 
-	dataDisks := make([]compute.VirtualMachineScaleSetDataDisk, len(vmssSpec.DataDisks))
+	dataDisks := make([]*armcompute.VirtualMachineScaleSetDataDisk, len(vmssSpec.DataDisks))
 	for i, disk := range vmssSpec.DataDisks {
-		dataDisks[i] = compute.VirtualMachineScaleSetDataDisk{
-			CreateOption: compute.DiskCreateOptionTypesEmpty,
-			DiskSizeGB:   to.Int32Ptr(disk.DiskSizeGB),
-			Lun:          disk.Lun,
-			Name:         to.StringPtr(azure.GenerateDataDiskName(vmssSpec.Name, disk.NameSuffix)),
+		dataDisks[i] = &armcompute.VirtualMachineScaleSetDataDisk{
+			CreateOption:            to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+			DiskSizeGB:              to.Ptr[int32](disk.DiskSizeGB),
+			Lun:                     disk.Lun,
+			Name:                    to.Ptr(azure.GenerateDataDiskName(vmssSpec.Name, disk.NameSuffix)),
+			WriteAcceleratorEnabled: disk.WriteAcceleratorEnabled,
+		}
+
+		if disk.CachingType != "" {
+			dataDisks[i].Caching = to.Ptr(armcompute.CachingTypes(disk.CachingType))
 		}
 
 		if disk.ManagedDisk != nil {
-			dataDisks[i].ManagedDisk = &compute.VirtualMachineScaleSetManagedDiskParameters{
-				StorageAccountType: compute.StorageAccountTypes(disk.ManagedDisk.StorageAccountType),
+			dataDisks[i].ManagedDisk = &armcompute.VirtualMachineScaleSetManagedDiskParameters{
+				StorageAccountType: to.Ptr(armcompute.StorageAccountTypes(disk.ManagedDisk.StorageAccountType)),
 			}
 
 			if disk.ManagedDisk.DiskEncryptionSet != nil {
-				dataDisks[i].ManagedDisk.DiskEncryptionSet = &compute.DiskEncryptionSetParameters{ID: to.StringPtr(disk.ManagedDisk.DiskEncryptionSet.ID)}
+				dataDisks[i].ManagedDisk.DiskEncryptionSet = &armcompute.DiskEncryptionSetParameters{ID: to.Ptr(disk.ManagedDisk.DiskEncryptionSet.ID)}
 			}
 		}
 	}
-	storageProfile.DataDisks = &dataDisks
+	storageProfile.DataDisks = dataDisks
+
+	// check the support for write accelerator based on vm size
+	var writeAcceleratedDisks int64
+	if vmssSpec.OSDisk.WriteAcceleratorEnabled != nil && *vmssSpec.OSDisk.WriteAcceleratorEnabled {
+		writeAcceleratedDisks++
+	}
+	for _, disk := range vmssSpec.DataDisks {
+		if disk.WriteAcceleratorEnabled != nil && *disk.WriteAcceleratorEnabled {
+			writeAcceleratedDisks++
+		}
+	}
+	if writeAcceleratedDisks > 0 {
+		writeAcceleratorCapability, err := sku.HasCapabilityWithCapacity(resourceskus.MaxWriteAcceleratorDisksAllowed, writeAcceleratedDisks)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to validate the write accelerator capability")
+		}
+		if !writeAcceleratorCapability {
+			return nil, fmt.Errorf("vm size %s does not support write accelerator on %d disks. select a different vm size or disable write accelerator", vmssSpec.Size, writeAcceleratedDisks)
+		}
+	}
 
 	image, err := s.Scope.GetVMImage(ctx)
 	if err != nil {
@@ -789,8 +1329,17 @@ func (s *Service) generateStorageProfile(ctx context.Context, vmssSpec azure.Sca
 	return storageProfile, nil
 }
 
-func (s *Service) generateOSProfile(ctx context.Context, vmssSpec azure.ScaleSetSpec) (*compute.VirtualMachineScaleSetOSProfile, error) {
-	sshKey, err := base64.StdEncoding.DecodeString(vmssSpec.SSHKeyData)
+func (s *Service) generateOSProfile(ctx context.Context, vmssSpec azure.ScaleSetSpec) (*armcompute.VirtualMachineScaleSetOSProfile, error) {
+	sshKeyData := vmssSpec.SSHKeyData
+	if sshKeyData == "" {
+		// Scale sets without an SSH public key use the public key of the cluster.
+		var err error
+		sshKeyData, err = s.Scope.GetSSHPublicKey(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to retrieve ssh public key")
+		}
+	}
+	sshKey, err := base64.StdEncoding.DecodeString(sshKeyData)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to decode ssh public key")
 	}
@@ -799,14 +1348,14 @@ func (s *Service) generateOSProfile(ctx context.Context, vmssSpec azure.ScaleSet
 		return nil, errors.Wrap(err, "failed to retrieve bootstrap data")
 	}
 
-	osProfile := &compute.VirtualMachineScaleSetOSProfile{
-		ComputerNamePrefix: to.StringPtr(vmssSpec.Name),
-		AdminUsername:      to.StringPtr(azure.DefaultUserName),
-		CustomData:         to.StringPtr(bootstrapData),
+	osProfile := &armcompute.VirtualMachineScaleSetOSProfile{
+		ComputerNamePrefix: to.Ptr(vmssSpec.Name),
+		AdminUsername:      to.Ptr(azure.DefaultUserName),
+		CustomData:         to.Ptr(bootstrapData),
 	}
 
 	switch vmssSpec.OSDisk.OSType {
-	case string(compute.OperatingSystemTypesWindows):
+	case string(armcompute.OperatingSystemTypesWindows):
 		// Cloudbase-init is used to generate a password.
 		// https://cloudbase-init.readthedocs.io/en/latest/plugins.html#setting-password-main
 		//
@@ -814,20 +1363,31 @@ func (s *Service) generateOSProfile(ctx context.Context, vmssSpec azure.ScaleSet
 		// but the password on the VM will NOT be the same as created here.
 		// Access is provided via SSH public key that is set during deployment
 		// Azure also provides a way to reset user passwords in the case of need.
-		osProfile.AdminPassword = to.StringPtr(generators.SudoRandomPassword(123))
-		osProfile.WindowsConfiguration = &compute.WindowsConfiguration{
-			EnableAutomaticUpdates: to.BoolPtr(false),
+		osProfile.AdminPassword = to.Ptr(generators.SudoRandomPassword(123))
+		osProfile.WindowsConfiguration = &armcompute.WindowsConfiguration{
+			EnableAutomaticUpdates: to.Ptr(false),
 		}
 	default:
-		osProfile.LinuxConfiguration = &compute.LinuxConfiguration{
-			DisablePasswordAuthentication: to.BoolPtr(true),
-			SSH: &compute.SSHConfiguration{
-				PublicKeys: &[]compute.SSHPublicKey{
-					{
-						Path:    to.StringPtr(fmt.Sprintf("/home/%s/.ssh/authorized_keys", azure.DefaultUserName)),
-						KeyData: to.StringPtr(string(sshKey)),
-					},
-				},
+		publicKeys := []*armcompute.SSHPublicKey{
+			{
+				Path:    to.Ptr(fmt.Sprintf("/home/%s/.ssh/authorized_keys", azure.DefaultUserName)),
+				KeyData: to.Ptr(string(sshKey)),
+			},
+		}
+		for i, keyData := range vmssSpec.AdditionalSSHKeyData {
+			key, err := base64.StdEncoding.DecodeString(keyData)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to decode additional ssh public key %d", i)
+			}
+			publicKeys = append(publicKeys, &armcompute.SSHPublicKey{
+				Path:    to.Ptr(fmt.Sprintf("/home/%s/.ssh/authorized_keys", azure.DefaultUserName)),
+				KeyData: to.Ptr(string(key)),
+			})
+		}
+		osProfile.LinuxConfiguration = &armcompute.LinuxConfiguration{
+			DisablePasswordAuthentication: to.Ptr(true),
+			SSH: &armcompute.SSHConfiguration{
+				PublicKeys: publicKeys,
 			},
 		}
 	}
@@ -835,7 +1395,7 @@ func (s *Service) generateOSProfile(ctx context.Context, vmssSpec azure.ScaleSet
 	return osProfile, nil
 }
 
-func (s *Service) generateImagePlan(ctx context.Context) *compute.Plan {
+func (s *Service) generateImagePlan(ctx context.Context) *armcompute.Plan {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.generateImagePlan")
 	defer done()
 
@@ -846,7 +1406,7 @@ func (s *Service) generateImagePlan(ctx context.Context) *compute.Plan {
 	}
 
 	if image.SharedGallery != nil && image.SharedGallery.Publisher != nil && image.SharedGallery.SKU != nil && image.SharedGallery.Offer != nil {
-		return &compute.Plan{
+		return &armcompute.Plan{
 			Publisher: image.SharedGallery.Publisher,
 			Name:      image.SharedGallery.SKU,
 			Product:   image.SharedGallery.Offer,
@@ -861,30 +1421,30 @@ func (s *Service) generateImagePlan(ctx context.Context) *compute.Plan {
 		return nil
 	}
 
-	return &compute.Plan{
-		Publisher: to.StringPtr(image.Marketplace.Publisher),
-		Name:      to.StringPtr(image.Marketplace.SKU),
-		Product:   to.StringPtr(image.Marketplace.Offer),
+	return &armcompute.Plan{
+		Publisher: to.Ptr(image.Marketplace.Publisher),
+		Name:      to.Ptr(image.Marketplace.SKU),
+		Product:   to.Ptr(image.Marketplace.Offer),
 	}
 }
 
-func getVMSSUpdateFromVMSS(vmss compute.VirtualMachineScaleSet) (compute.VirtualMachineScaleSetUpdate, error) {
+func getVMSSUpdateFromVMSS(vmss armcompute.VirtualMachineScaleSet) (armcompute.VirtualMachineScaleSetUpdate, error) {
 	jsonData, err := vmss.MarshalJSON()
 	if err != nil {
-		return compute.VirtualMachineScaleSetUpdate{}, err
+		return armcompute.VirtualMachineScaleSetUpdate{}, err
 	}
 
-	var update compute.VirtualMachineScaleSetUpdate
+	var update armcompute.VirtualMachineScaleSetUpdate
 	if err := update.UnmarshalJSON(jsonData); err != nil {
 		return update, err
 	}
 
 	// wipe out network profile, so updates won't conflict with Cloud Provider updates
-	update.VirtualMachineProfile.NetworkProfile = nil
+	update.Properties.VirtualMachineProfile.NetworkProfile = nil
 	return update, nil
 }
 
-func getSecurityProfile(vmssSpec azure.ScaleSetSpec, sku resourceskus.SKU) (*compute.SecurityProfile, error) {
+func getSecurityProfile(vmssSpec azure.ScaleSetSpec, sku resourceskus.SKU) (*armcompute.SecurityProfile, error) {
 	if vmssSpec.SecurityProfile == nil {
 		return nil, nil
 	}
@@ -893,8 +1453,8 @@ func getSecurityProfile(vmssSpec azure.ScaleSetSpec, sku resourceskus.SKU) (*com
 		return nil, azure.WithTerminalError(errors.Errorf("encryption at host is not supported for VM type %s", vmssSpec.Size))
 	}
 
-	return &compute.SecurityProfile{
-		EncryptionAtHost: to.BoolPtr(*vmssSpec.SecurityProfile.EncryptionAtHost),
+	return &armcompute.SecurityProfile{
+		EncryptionAtHost: to.Ptr(*vmssSpec.SecurityProfile.EncryptionAtHost),
 	}, nil
 }
 
@@ -18,12 +18,13 @@ package scalesets
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -31,9 +32,11 @@ import (
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets/mock_scalesets"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
@@ -43,6 +46,8 @@ const (
 	defaultVMSSName       = "my-vmss"
 )
 
+var notFoundError = &azcore.ResponseError{StatusCode: http.StatusNotFound}
+
 func init() {
 	_ = clusterv1.AddToScheme(scheme.Scheme)
 }
@@ -59,10 +64,10 @@ func TestGetExistingVMSS(t *testing.T) {
 			name:          "scale set not found",
 			vmssName:      "my-vmss",
 			result:        &azure.VMSS{},
-			expectedError: "failed to get existing vmss: #: Not found: StatusCode=404",
+			expectedError: "failed to get existing vmss: " + notFoundError.Error(),
 			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
 				s.ResourceGroup().AnyTimes().Return("my-rg")
-				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss").Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss").Return(armcompute.VirtualMachineScaleSet{}, notFoundError)
 			},
 		},
 		{
@@ -79,38 +84,39 @@ func TestGetExistingVMSS(t *testing.T) {
 				Zones:    []string{"1", "3"},
 				Instances: []azure.VMSSVM{
 					{
-						ID:         "my-vm-id",
-						InstanceID: "my-vm-1",
-						Name:       "instance-000001",
-						State:      "Succeeded",
+						ID:                 "my-vm-id",
+						InstanceID:         "my-vm-1",
+						Name:               "instance-000001",
+						State:              "Succeeded",
+						LatestModelApplied: true,
 					},
 				},
 			},
 			expectedError: "",
 			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
 				s.ResourceGroup().AnyTimes().Return("my-rg")
-				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss").Return(compute.VirtualMachineScaleSet{
-					ID:   to.StringPtr("my-id"),
-					Name: to.StringPtr("my-vmss"),
-					Sku: &compute.Sku{
-						Capacity: to.Int64Ptr(1),
-						Name:     to.StringPtr("Standard_D2"),
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss").Return(armcompute.VirtualMachineScaleSet{
+					ID:   to.Ptr("my-id"),
+					Name: to.Ptr("my-vmss"),
+					SKU: &armcompute.SKU{
+						Capacity: to.Ptr[int64](1),
+						Name:     to.Ptr("Standard_D2"),
 					},
-					VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
-						SinglePlacementGroup: to.BoolPtr(false),
-						ProvisioningState:    to.StringPtr("Succeeded"),
+					Properties: &armcompute.VirtualMachineScaleSetProperties{
+						SinglePlacementGroup: to.Ptr(false),
+						ProvisioningState:    to.Ptr("Succeeded"),
 					},
-					Zones: &[]string{"1", "3"},
+					Zones: []*string{to.Ptr("1"), to.Ptr("3")},
 				}, nil)
-				m.ListInstances(gomock.Any(), "my-rg", "my-vmss").Return([]compute.VirtualMachineScaleSetVM{
+				m.ListInstances(gomock.Any(), "my-rg", "my-vmss").Return([]armcompute.VirtualMachineScaleSetVM{
 					{
-						ID:         to.StringPtr("my-vm-id"),
-						InstanceID: to.StringPtr("my-vm-1"),
-						Name:       to.StringPtr("my-vm"),
-						VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
-							ProvisioningState: to.StringPtr("Succeeded"),
-							OsProfile: &compute.OSProfile{
-								ComputerName: to.StringPtr("instance-000001"),
+						ID:         to.Ptr("my-vm-id"),
+						InstanceID: to.Ptr("my-vm-1"),
+						Name:       to.Ptr("my-vm"),
+						Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+							ProvisioningState: to.Ptr("Succeeded"),
+							OSProfile: &armcompute.OSProfile{
+								ComputerName: to.Ptr("instance-000001"),
 							},
 						},
 					},
@@ -121,18 +127,40 @@ func TestGetExistingVMSS(t *testing.T) {
 			name:          "list instances fails",
 			vmssName:      "my-vmss",
 			result:        &azure.VMSS{},
-			expectedError: "failed to list instances: #: Not found: StatusCode=404",
+			expectedError: "failed to list instances: " + notFoundError.Error(),
 			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
 				s.ResourceGroup().AnyTimes().Return("my-rg")
-				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss").Return(compute.VirtualMachineScaleSet{
-					ID:   to.StringPtr("my-id"),
-					Name: to.StringPtr("my-vmss"),
-					VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
-						SinglePlacementGroup: to.BoolPtr(false),
-						ProvisioningState:    to.StringPtr("Succeeded"),
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss").Return(armcompute.VirtualMachineScaleSet{
+					ID:   to.Ptr("my-id"),
+					Name: to.Ptr("my-vmss"),
+					Properties: &armcompute.VirtualMachineScaleSetProperties{
+						SinglePlacementGroup: to.Ptr(false),
+						ProvisioningState:    to.Ptr("Succeeded"),
 					},
 				}, nil)
-				m.ListInstances(gomockinternal.AContext(), "my-rg", "my-vmss").Return([]compute.VirtualMachineScaleSetVM{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.ListInstances(gomockinternal.AContext(), "my-rg", "my-vmss").Return([]armcompute.VirtualMachineScaleSetVM{}, notFoundError)
+			},
+		},
+		{
+			name:          "malformed image ID",
+			vmssName:      "my-vmss",
+			result:        &azure.VMSS{},
+			expectedError: "failed to convert the image of scale set my-vmss: invalid Azure data: image reference ID \"my-image\" is not a resource ID",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss").Return(armcompute.VirtualMachineScaleSet{
+					ID:   to.Ptr("my-id"),
+					Name: to.Ptr("my-vmss"),
+					Properties: &armcompute.VirtualMachineScaleSetProperties{
+						ProvisioningState: to.Ptr("Succeeded"),
+						VirtualMachineProfile: &armcompute.VirtualMachineScaleSetVMProfile{
+							StorageProfile: &armcompute.VirtualMachineScaleSetStorageProfile{
+								ImageReference: &armcompute.ImageReference{ID: to.Ptr("my-image")},
+							},
+						},
+					},
+				}, nil)
+				m.ListInstances(gomockinternal.AContext(), "my-rg", "my-vmss").Return([]armcompute.VirtualMachineScaleSetVM{}, nil)
 			},
 		},
 	}
@@ -196,7 +224,7 @@ func TestReconcileVMSS(t *testing.T) {
 				defaultSpec.DataDisks = append(defaultSpec.DataDisks, infrav1.DataDisk{
 					NameSuffix: "my_disk_with_ultra_disks",
 					DiskSizeGB: 128,
-					Lun:        to.Int32Ptr(3),
+					Lun:        to.Ptr[int32](3),
 					ManagedDisk: &infrav1.ManagedDiskParameters{
 						StorageAccountType: "UltraSSD_LRS",
 					},
@@ -204,7 +232,61 @@ func TestReconcileVMSS(t *testing.T) {
 				s.ScaleSetSpec().Return(defaultSpec).AnyTimes()
 				setupDefaultVMSSStartCreatingExpectations(s, m)
 				vmss := newDefaultVMSS("VM_SIZE")
-				vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				vmss.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
+					Return(putFuture, nil)
+				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
+			},
+		},
+		{
+			name:          "should start creating a vmss tagged with the SAS expiry of its bootstrap data",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Ptr[int32](3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				spec.BootstrapDataSASExpiry = "2022-06-15T09:05:00Z"
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				setupDefaultVMSSStartCreatingExpectations(s, m)
+				vmss := newDefaultVMSS("VM_SIZE")
+				vmss.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				vmss.Tags[infrav1.NameAzureBootstrapDataSASExpiry] = to.Ptr("2022-06-15T09:05:00Z")
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
+					Return(putFuture, nil)
+				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
+			},
+		},
+		{
+			name:          "should start creating a vmss with the cluster ssh public key and additional keys",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Ptr[int32](3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				spec.SSHKeyData = ""
+				spec.AdditionalSSHKeyData = []string{"b3RoZXJrZXkK"}
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				s.GetSSHPublicKey(gomockinternal.AContext()).Return("ZmFrZXNzaGtleQo=", nil)
+				setupDefaultVMSSStartCreatingExpectations(s, m)
+				vmss := newDefaultVMSS("VM_SIZE")
+				vmss.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				sshConfig := vmss.Properties.VirtualMachineProfile.OSProfile.LinuxConfiguration.SSH
+				sshConfig.PublicKeys = append(sshConfig.PublicKeys, &armcompute.SSHPublicKey{
+					Path:    to.Ptr("/home/capi/.ssh/authorized_keys"),
+					KeyData: to.Ptr("otherkey\n"),
+				})
 				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
 					Return(putFuture, nil)
 				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
@@ -215,6 +297,14 @@ func TestReconcileVMSS(t *testing.T) {
 			expectedError: "",
 			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
 				defaultSpec := newDefaultVMSSSpec()
+				defaultSpec.DataDisks = append(defaultSpec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Ptr[int32](3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
 				s.ScaleSetSpec().Return(defaultSpec).AnyTimes()
 				createdVMSS := newDefaultVMSS("VM_SIZE")
 				instances := newDefaultInstances()
@@ -231,6 +321,14 @@ func TestReconcileVMSS(t *testing.T) {
 			expectedError: "",
 			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
 				defaultSpec := newWindowsVMSSSpec()
+				defaultSpec.DataDisks = append(defaultSpec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Ptr[int32](3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
 				s.ScaleSetSpec().Return(defaultSpec).AnyTimes()
 				createdVMSS := newDefaultWindowsVMSS()
 				instances := newDefaultInstances()
@@ -251,14 +349,51 @@ func TestReconcileVMSS(t *testing.T) {
 				s.ScaleSetSpec().Return(spec).AnyTimes()
 				setupDefaultVMSSStartCreatingExpectations(s, m)
 				vmss := newDefaultVMSS("VM_SIZE_AN")
-				netConfigs := vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations
-				(*netConfigs)[0].EnableAcceleratedNetworking = to.BoolPtr(true)
-				vmss.Sku.Name = to.StringPtr(spec.Size)
+				netConfigs := vmss.Properties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations
+				netConfigs[0].Properties.EnableAcceleratedNetworking = to.Ptr(true)
+				vmss.SKU.Name = to.Ptr(spec.Size)
 				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
 					Return(putFuture, nil)
 				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE_AN"), putFuture)
 			},
 		},
+		{
+			name:          "should start creating vmss with custom networking and IP forwarding",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Ptr[int32](3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				spec.NetworkInterfaces = []infrav1.AzureNetworkInterface{
+					{
+						SubnetName:            "my-subnet",
+						PrivateIPConfigs:      1,
+						AcceleratedNetworking: pointer.Bool(true),
+						EnableIPForwarding:    pointer.Bool(true),
+					},
+				}
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				setupDefaultVMSSStartCreatingExpectations(s, m)
+				vmss := newDefaultVMSS("VM_SIZE")
+				vmss.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				netConfigs := vmss.Properties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations
+				netConfigs[0].Name = to.Ptr("my-vmss-0")
+				netConfigs[0].Properties.EnableIPForwarding = to.Ptr(true)
+				nic1IPConfigs := netConfigs[0].Properties.IPConfigurations
+				nic1IPConfigs[0].Name = to.Ptr("private-ipConfig-0")
+				nic1IPConfigs[0].Properties.PrivateIPAddressVersion = to.Ptr(armcompute.IPVersionIPv4)
+				netConfigs[0].Properties.EnableAcceleratedNetworking = to.Ptr(true)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
+					Return(putFuture, nil)
+				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
+			},
+		},
 		{
 			name:          "should start creating vmss with custom networking when specified",
 			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
@@ -267,7 +402,7 @@ func TestReconcileVMSS(t *testing.T) {
 				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
 					NameSuffix: "my_disk_with_ultra_disks",
 					DiskSizeGB: 128,
-					Lun:        to.Int32Ptr(3),
+					Lun:        to.Ptr[int32](3),
 					ManagedDisk: &infrav1.ManagedDiskParameters{
 						StorageAccountType: "UltraSSD_LRS",
 					},
@@ -287,42 +422,42 @@ func TestReconcileVMSS(t *testing.T) {
 				s.ScaleSetSpec().Return(spec).AnyTimes()
 				setupDefaultVMSSStartCreatingExpectations(s, m)
 				vmss := newDefaultVMSS("VM_SIZE")
-				vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
-				netConfigs := vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations
-				(*netConfigs)[0].Name = to.StringPtr("my-vmss-0")
-				(*netConfigs)[0].EnableIPForwarding = nil
-				nic1IPConfigs := (*netConfigs)[0].IPConfigurations
-				(*nic1IPConfigs)[0].Name = to.StringPtr("private-ipConfig-0")
-				(*nic1IPConfigs)[0].PrivateIPAddressVersion = compute.IPVersionIPv4
-				(*netConfigs)[0].EnableAcceleratedNetworking = to.BoolPtr(true)
-				vmssIPConfigs := []compute.VirtualMachineScaleSetIPConfiguration{
+				vmss.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				netConfigs := vmss.Properties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations
+				netConfigs[0].Name = to.Ptr("my-vmss-0")
+				netConfigs[0].Properties.EnableIPForwarding = nil
+				nic1IPConfigs := netConfigs[0].Properties.IPConfigurations
+				nic1IPConfigs[0].Name = to.Ptr("private-ipConfig-0")
+				nic1IPConfigs[0].Properties.PrivateIPAddressVersion = to.Ptr(armcompute.IPVersionIPv4)
+				netConfigs[0].Properties.EnableAcceleratedNetworking = to.Ptr(true)
+				vmssIPConfigs := []*armcompute.VirtualMachineScaleSetIPConfiguration{
 					{
-						Name: to.StringPtr("private-ipConfig-0"),
-						VirtualMachineScaleSetIPConfigurationProperties: &compute.VirtualMachineScaleSetIPConfigurationProperties{
-							Primary:                 to.BoolPtr(true),
-							PrivateIPAddressVersion: compute.IPVersionIPv4,
-							Subnet: &compute.APIEntityReference{
-								ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/subnet2"),
+						Name: to.Ptr("private-ipConfig-0"),
+						Properties: &armcompute.VirtualMachineScaleSetIPConfigurationProperties{
+							Primary:                 to.Ptr(true),
+							PrivateIPAddressVersion: to.Ptr(armcompute.IPVersionIPv4),
+							Subnet: &armcompute.APIEntityReference{
+								ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/subnet2"),
 							},
 						},
 					},
 					{
-						Name: to.StringPtr("private-ipConfig-1"),
-						VirtualMachineScaleSetIPConfigurationProperties: &compute.VirtualMachineScaleSetIPConfigurationProperties{
-							Primary:                 to.BoolPtr(false),
-							PrivateIPAddressVersion: compute.IPVersionIPv4,
-							Subnet: &compute.APIEntityReference{
-								ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/subnet2"),
+						Name: to.Ptr("private-ipConfig-1"),
+						Properties: &armcompute.VirtualMachineScaleSetIPConfigurationProperties{
+							Primary:                 to.Ptr(false),
+							PrivateIPAddressVersion: to.Ptr(armcompute.IPVersionIPv4),
+							Subnet: &armcompute.APIEntityReference{
+								ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/subnet2"),
 							},
 						},
 					},
 				}
-				*netConfigs = append(*netConfigs, compute.VirtualMachineScaleSetNetworkConfiguration{
-					Name: to.StringPtr("my-vmss-1"),
-					VirtualMachineScaleSetNetworkConfigurationProperties: &compute.VirtualMachineScaleSetNetworkConfigurationProperties{
+				vmss.Properties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations = append(netConfigs, &armcompute.VirtualMachineScaleSetNetworkConfiguration{
+					Name: to.Ptr("my-vmss-1"),
+					Properties: &armcompute.VirtualMachineScaleSetNetworkConfigurationProperties{
 						Primary:                     nil,
-						EnableAcceleratedNetworking: to.BoolPtr(true),
-						IPConfigurations:            &vmssIPConfigs,
+						EnableAcceleratedNetworking: to.Ptr(true),
+						IPConfigurations:            vmssIPConfigs,
 					},
 				})
 				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
@@ -339,7 +474,7 @@ func TestReconcileVMSS(t *testing.T) {
 				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
 					NameSuffix: "my_disk_with_ultra_disks",
 					DiskSizeGB: 128,
-					Lun:        to.Int32Ptr(3),
+					Lun:        to.Ptr[int32](3),
 					ManagedDisk: &infrav1.ManagedDiskParameters{
 						StorageAccountType: "UltraSSD_LRS",
 					},
@@ -347,9 +482,9 @@ func TestReconcileVMSS(t *testing.T) {
 				s.ScaleSetSpec().Return(spec).AnyTimes()
 				setupDefaultVMSSStartCreatingExpectations(s, m)
 				vmss := newDefaultVMSS("VM_SIZE")
-				vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
-				vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.Priority = compute.VirtualMachinePriorityTypesSpot
-				vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.EvictionPolicy = compute.VirtualMachineEvictionPolicyTypesDeallocate
+				vmss.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				vmss.Properties.VirtualMachineProfile.Priority = to.Ptr(armcompute.VirtualMachinePriorityTypesSpot)
+				vmss.Properties.VirtualMachineProfile.EvictionPolicy = to.Ptr(armcompute.VirtualMachineEvictionPolicyTypesDeallocate)
 				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
 					Return(putFuture, nil)
 				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
@@ -367,7 +502,7 @@ func TestReconcileVMSS(t *testing.T) {
 				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
 					NameSuffix: "my_disk_with_ultra_disks",
 					DiskSizeGB: 128,
-					Lun:        to.Int32Ptr(3),
+					Lun:        to.Ptr[int32](3),
 					ManagedDisk: &infrav1.ManagedDiskParameters{
 						StorageAccountType: "UltraSSD_LRS",
 					},
@@ -375,12 +510,12 @@ func TestReconcileVMSS(t *testing.T) {
 				s.ScaleSetSpec().Return(spec).AnyTimes()
 				setupDefaultVMSSStartCreatingExpectations(s, m)
 				vmss := newDefaultVMSS("VM_SIZE")
-				vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.Priority = compute.VirtualMachinePriorityTypesSpot
-				vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.BillingProfile = &compute.BillingProfile{
-					MaxPrice: to.Float64Ptr(0.001),
+				vmss.Properties.VirtualMachineProfile.Priority = to.Ptr(armcompute.VirtualMachinePriorityTypesSpot)
+				vmss.Properties.VirtualMachineProfile.BillingProfile = &armcompute.BillingProfile{
+					MaxPrice: to.Ptr[float64](0.001),
 				}
-				vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
-				vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.EvictionPolicy = compute.VirtualMachineEvictionPolicyTypesDeallocate
+				vmss.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				vmss.Properties.VirtualMachineProfile.EvictionPolicy = to.Ptr(armcompute.VirtualMachineEvictionPolicyTypesDeallocate)
 				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
 					Return(putFuture, nil)
 				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
@@ -397,7 +532,7 @@ func TestReconcileVMSS(t *testing.T) {
 				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
 					NameSuffix: "my_disk_with_ultra_disks",
 					DiskSizeGB: 128,
-					Lun:        to.Int32Ptr(3),
+					Lun:        to.Ptr[int32](3),
 					ManagedDisk: &infrav1.ManagedDiskParameters{
 						StorageAccountType: "UltraSSD_LRS",
 					},
@@ -405,12 +540,12 @@ func TestReconcileVMSS(t *testing.T) {
 				s.ScaleSetSpec().Return(spec).AnyTimes()
 				setupDefaultVMSSStartCreatingExpectations(s, m)
 				vmss := newDefaultVMSS("VM_SIZE")
-				vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
-				osdisk := vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.StorageProfile.OsDisk
-				osdisk.ManagedDisk = &compute.VirtualMachineScaleSetManagedDiskParameters{
-					StorageAccountType: "Premium_LRS",
-					DiskEncryptionSet: &compute.DiskEncryptionSetParameters{
-						ID: to.StringPtr("my-diskencryptionset-id"),
+				vmss.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				osdisk := vmss.Properties.VirtualMachineProfile.StorageProfile.OSDisk
+				osdisk.ManagedDisk = &armcompute.VirtualMachineScaleSetManagedDiskParameters{
+					StorageAccountType: to.Ptr(armcompute.StorageAccountTypesPremiumLRS),
+					DiskEncryptionSet: &armcompute.DiskEncryptionSetParameters{
+						ID: to.Ptr("my-diskencryptionset-id"),
 					},
 				}
 				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
@@ -426,7 +561,7 @@ func TestReconcileVMSS(t *testing.T) {
 				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
 					NameSuffix: "my_disk_with_ultra_disks",
 					DiskSizeGB: 128,
-					Lun:        to.Int32Ptr(3),
+					Lun:        to.Ptr[int32](3),
 					ManagedDisk: &infrav1.ManagedDiskParameters{
 						StorageAccountType: "UltraSSD_LRS",
 					},
@@ -440,10 +575,10 @@ func TestReconcileVMSS(t *testing.T) {
 				s.ScaleSetSpec().Return(spec).AnyTimes()
 				setupDefaultVMSSStartCreatingExpectations(s, m)
 				vmss := newDefaultVMSS("VM_SIZE")
-				vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
-				vmss.Identity = &compute.VirtualMachineScaleSetIdentity{
-					Type: compute.ResourceIdentityTypeUserAssigned,
-					UserAssignedIdentities: map[string]*compute.VirtualMachineScaleSetIdentityUserAssignedIdentitiesValue{
+				vmss.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				vmss.Identity = &armcompute.VirtualMachineScaleSetIdentity{
+					Type: to.Ptr(armcompute.ResourceIdentityTypeUserAssigned),
+					UserAssignedIdentities: map[string]*armcompute.UserAssignedIdentitiesValue{
 						"/subscriptions/123/resourcegroups/456/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id1": {},
 					},
 				}
@@ -458,19 +593,41 @@ func TestReconcileVMSS(t *testing.T) {
 			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
 				spec := newDefaultVMSSSpec()
 				spec.Size = "VM_SIZE_EAH"
-				spec.SecurityProfile = &infrav1.SecurityProfile{EncryptionAtHost: to.BoolPtr(true)}
+				spec.SecurityProfile = &infrav1.SecurityProfile{EncryptionAtHost: to.Ptr(true)}
 				s.ScaleSetSpec().Return(spec).AnyTimes()
 				setupDefaultVMSSStartCreatingExpectations(s, m)
 				vmss := newDefaultVMSS("VM_SIZE_EAH")
-				vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.SecurityProfile = &compute.SecurityProfile{
-					EncryptionAtHost: to.BoolPtr(true),
+				vmss.Properties.VirtualMachineProfile.SecurityProfile = &armcompute.SecurityProfile{
+					EncryptionAtHost: to.Ptr(true),
 				}
-				vmss.Sku.Name = to.StringPtr(spec.Size)
+				vmss.SKU.Name = to.Ptr(spec.Size)
 				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
 					Return(putFuture, nil)
 				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE_EAH"), putFuture)
 			},
 		},
+		{
+			name:          "should start creating a vmss with disk caching and write accelerator",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.Size = "VM_SIZE_WA"
+				spec.OSDisk.CachingType = "ReadOnly"
+				spec.DataDisks[0].CachingType = "None"
+				spec.DataDisks[0].WriteAcceleratorEnabled = to.Ptr(true)
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				setupDefaultVMSSStartCreatingExpectations(s, m)
+				vmss := newDefaultVMSS("VM_SIZE_WA")
+				storageProfile := vmss.Properties.VirtualMachineProfile.StorageProfile
+				storageProfile.OSDisk.Caching = to.Ptr(armcompute.CachingTypesReadOnly)
+				storageProfile.DataDisks[0].Caching = to.Ptr(armcompute.CachingTypesNone)
+				storageProfile.DataDisks[0].WriteAcceleratorEnabled = to.Ptr(true)
+				vmss.SKU.Name = to.Ptr(spec.Size)
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
+					Return(putFuture, nil)
+				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE_WA"), putFuture)
+			},
+		},
 		{
 			name:          "creating a vmss with encryption at host enabled for unsupported VM type fails",
 			expectedError: "reconcile error that cannot be recovered occurred: encryption at host is not supported for VM type VM_SIZE. Object will not be requeued",
@@ -480,7 +637,7 @@ func TestReconcileVMSS(t *testing.T) {
 					Size:            "VM_SIZE",
 					Capacity:        2,
 					SSHKeyData:      "ZmFrZXNzaGtleQo=",
-					SecurityProfile: &infrav1.SecurityProfile{EncryptionAtHost: to.BoolPtr(true)},
+					SecurityProfile: &infrav1.SecurityProfile{EncryptionAtHost: to.Ptr(true)},
 				})
 			},
 		},
@@ -493,7 +650,7 @@ func TestReconcileVMSS(t *testing.T) {
 				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
 					NameSuffix: "my_disk_with_ultra_disks",
 					DiskSizeGB: 128,
-					Lun:        to.Int32Ptr(3),
+					Lun:        to.Ptr[int32](3),
 					ManagedDisk: &infrav1.ManagedDiskParameters{
 						StorageAccountType: "UltraSSD_LRS",
 					},
@@ -502,32 +659,55 @@ func TestReconcileVMSS(t *testing.T) {
 
 				setupDefaultVMSSUpdateExpectations(s)
 				existingVMSS := newDefaultExistingVMSS("VM_SIZE")
-				existingVMSS.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
-				existingVMSS.Sku.Capacity = to.Int64Ptr(2)
-				existingVMSS.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				existingVMSS.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				existingVMSS.SKU.Capacity = to.Ptr[int64](2)
+				existingVMSS.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
 				instances := newDefaultInstances()
 				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(existingVMSS, nil)
 				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
 
 				clone := newDefaultExistingVMSS("VM_SIZE")
-				clone.Sku.Capacity = to.Int64Ptr(3)
-				clone.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				clone.SKU.Capacity = to.Ptr[int64](3)
+				clone.Properties.AdditionalCapabilities = &armcompute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
 
 				patchVMSS, err := getVMSSUpdateFromVMSS(clone)
 				g.Expect(err).NotTo(HaveOccurred())
-				patchVMSS.VirtualMachineProfile.StorageProfile.ImageReference.Version = to.StringPtr("2.0")
-				patchVMSS.VirtualMachineProfile.NetworkProfile = nil
+				patchVMSS.Properties.VirtualMachineProfile.StorageProfile.ImageReference.Version = to.Ptr("2.0")
+				patchVMSS.Properties.VirtualMachineProfile.NetworkProfile = nil
 				s.GetAnnotation(azure.ReplicasManagedByAutoscalerAnnotation).Return("", false)
 				s.GetAnnotation(azure.ReplicasManagedByAutoscalerAnnotation).Return("", false)
 				s.GetAnnotation(azure.ReplicasManagedByAutoscalerAnnotation).Return("", false)
 				m.UpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(patchVMSS)).
 					Return(patchFuture, nil)
 				s.SetLongRunningOperationState(patchFuture)
-				m.GetResultIfDone(gomockinternal.AContext(), patchFuture).Return(compute.VirtualMachineScaleSet{}, azure.NewOperationNotDoneError(patchFuture))
+				m.GetResultIfDone(gomockinternal.AContext(), patchFuture).Return(armcompute.VirtualMachineScaleSet{}, azure.NewOperationNotDoneError(patchFuture))
 				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(clone, nil)
 				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
 			},
 		},
+		{
+			name:          "should update the MachinePool replicas to the capacity of an autoscaled scale set",
+			expectedError: "",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.Capacity = 2
+				spec.Autoscaled = true
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				s.Location().AnyTimes().Return("test-location")
+				s.ExtendedLocation().AnyTimes().Return(nil)
+				s.ResourceGroup().AnyTimes().Return(defaultResourceGroup)
+				s.GetLongRunningOperationState(defaultVMSSName, serviceName).Return(nil)
+
+				existingVMSS := newDefaultExistingVMSS("VM_SIZE")
+				existingVMSS.SKU.Capacity = to.Ptr[int64](3)
+				instances := newDefaultInstances()
+				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(existingVMSS, nil)
+				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
+				s.UpdateScaleSetReplicas(gomockinternal.AContext(), gomock.Any()).Return(nil)
+				s.SetProviderID(azure.ProviderIDPrefix + "vmss-id")
+				s.SetVMSSState(gomock.Any())
+			},
+		},
 		{
 			name:          "less than 2 vCPUs",
 			expectedError: "reconcile error that cannot be recovered occurred: vm size should be bigger or equal to at least 2 vCPUs. Object will not be requeued",
@@ -562,19 +742,20 @@ func TestReconcileVMSS(t *testing.T) {
 					Capacity:   2,
 					SSHKeyData: "ZmFrZXNzaGtleQo=",
 				})
+				s.FallBackPlacement(gomockinternal.AContext(), gomock.Any()).Return(false)
 			},
 		},
 		{
 			name:          "fails with internal error",
-			expectedError: "failed to start creating VMSS: cannot create VMSS: #: Internal error: StatusCode=500",
+			expectedError: "failed to start creating VMSS: cannot create VMSS: Internal error",
 			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
 				spec := newDefaultVMSSSpec()
 				s.ScaleSetSpec().Return(spec).AnyTimes()
 				setupDefaultVMSSStartCreatingExpectations(s, m)
-				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomock.AssignableToTypeOf(compute.VirtualMachineScaleSet{})).
-					Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal error"))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomock.AssignableToTypeOf(armcompute.VirtualMachineScaleSet{})).
+					Return(nil, errors.New("Internal error"))
 				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).
-					Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+					Return(armcompute.VirtualMachineScaleSet{}, &azcore.ResponseError{StatusCode: http.StatusNotFound})
 			},
 		},
 		{
@@ -595,6 +776,7 @@ func TestReconcileVMSS(t *testing.T) {
 					},
 				})
 				s.Location().AnyTimes().Return("test-location")
+				s.ExtendedLocation().AnyTimes().Return(nil)
 			},
 		},
 		{
@@ -607,10 +789,11 @@ func TestReconcileVMSS(t *testing.T) {
 					Capacity:   2,
 					SSHKeyData: "ZmFrZXNzaGtleQo=",
 					AdditionalCapabilities: &infrav1.AdditionalCapabilities{
-						UltraSSDEnabled: to.BoolPtr(true),
+						UltraSSDEnabled: to.Ptr(true),
 					},
 				})
 				s.Location().AnyTimes().Return("test-location")
+				s.ExtendedLocation().AnyTimes().Return(nil)
 			},
 		},
 		{
@@ -630,10 +813,11 @@ func TestReconcileVMSS(t *testing.T) {
 						},
 					},
 					AdditionalCapabilities: &infrav1.AdditionalCapabilities{
-						UltraSSDEnabled: to.BoolPtr(false),
+						UltraSSDEnabled: to.Ptr(false),
 					},
 				})
 				s.Location().AnyTimes().Return("test-location")
+				s.ExtendedLocation().AnyTimes().Return(nil)
 			},
 		},
 	}
@@ -668,6 +852,230 @@ func TestReconcileVMSS(t *testing.T) {
 	}
 }
 
+func TestReconcileVMSSAllocationFailure(t *testing.T) {
+	var (
+		putFuture = &infrav1.Future{
+			Type:          infrav1.PutFuture,
+			ResourceGroup: defaultResourceGroup,
+			Name:          defaultVMSSName,
+		}
+		patchFuture = &infrav1.Future{
+			Type:          infrav1.PatchFuture,
+			ResourceGroup: defaultResourceGroup,
+			Name:          defaultVMSSName,
+		}
+		deleteFuture = &infrav1.Future{
+			Type:          infrav1.DeleteFuture,
+			ResourceGroup: defaultResourceGroup,
+			Name:          defaultVMSSName,
+		}
+		allocationFailure = &azcore.ResponseError{ErrorCode: "ZonalAllocationFailed"}
+	)
+
+	testcases := []struct {
+		name          string
+		expect        func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:          "deletes a scale set that failed to be created to recreate it with the next placement",
+			expectedError: "falling back to the next placement: failed to allocate the VMs of VMSS my-vmss",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ScaleSetSpec().Return(newDefaultVMSSSpec()).AnyTimes()
+				s.Location().Return("test-location").AnyTimes()
+				s.ResourceGroup().Return(defaultResourceGroup).AnyTimes()
+				s.GetLongRunningOperationState(defaultVMSSName, serviceName).Return(putFuture)
+				m.GetResultIfDone(gomockinternal.AContext(), putFuture).Return(armcompute.VirtualMachineScaleSet{}, allocationFailure)
+				s.BackOffZones(gomockinternal.AContext(), []string{"1", "3"}, gomock.Any()).Return(nil)
+				s.FallBackPlacement(gomockinternal.AContext(), gomock.Any()).Return(true)
+				s.DeleteLongRunningOperationState(defaultVMSSName, serviceName)
+				m.DeleteAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(deleteFuture, nil)
+				s.SetLongRunningOperationState(deleteFuture)
+				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(armcompute.VirtualMachineScaleSet{}, notFoundError)
+			},
+		},
+		{
+			name:          "keeps an existing scale set that failed to scale out",
+			expectedError: "falling back to the next placement: failed to allocate the VMs of VMSS my-vmss",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ScaleSetSpec().Return(newDefaultVMSSSpec()).AnyTimes()
+				s.Location().Return("test-location").AnyTimes()
+				s.ResourceGroup().Return(defaultResourceGroup).AnyTimes()
+				s.GetLongRunningOperationState(defaultVMSSName, serviceName).Return(patchFuture)
+				m.GetResultIfDone(gomockinternal.AContext(), patchFuture).Return(armcompute.VirtualMachineScaleSet{}, allocationFailure)
+				s.BackOffZones(gomockinternal.AContext(), []string{"1", "3"}, gomock.Any()).Return(nil)
+				s.FallBackPlacement(gomockinternal.AContext(), gomock.Any()).Return(true)
+				s.DeleteLongRunningOperationState(defaultVMSSName, serviceName)
+				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(armcompute.VirtualMachineScaleSet{}, notFoundError)
+			},
+		},
+		{
+			name:          "returns the allocation failure without placement fallbacks",
+			expectedError: "failed to allocate the VMs of VMSS my-vmss: failed to get result from future: " + allocationFailure.Error(),
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ScaleSetSpec().Return(newDefaultVMSSSpec()).AnyTimes()
+				s.Location().Return("test-location").AnyTimes()
+				s.ResourceGroup().Return(defaultResourceGroup).AnyTimes()
+				s.GetLongRunningOperationState(defaultVMSSName, serviceName).Return(putFuture)
+				m.GetResultIfDone(gomockinternal.AContext(), putFuture).Return(armcompute.VirtualMachineScaleSet{}, allocationFailure)
+				s.BackOffZones(gomockinternal.AContext(), []string{"1", "3"}, gomock.Any()).Return(nil)
+				s.FallBackPlacement(gomockinternal.AContext(), gomock.Any()).Return(false)
+				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(armcompute.VirtualMachineScaleSet{}, notFoundError)
+			},
+		},
+		{
+			name:          "falls back when the VM size isn't available in the location",
+			expectedError: "falling back to the next placement: failed to get SKU INVALID_VM_SIZE in compute api",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.Size = "INVALID_VM_SIZE"
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				s.FallBackPlacement(gomockinternal.AContext(), gomock.Any()).Return(true)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_scalesets.NewMockScaleSetScope(mockCtrl)
+			clientMock := mock_scalesets.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:            scopeMock,
+				Client:           clientMock,
+				resourceSKUCache: resourceskus.NewStaticCache(getFakeSkus(), "test-location"),
+			}
+
+			err := s.Reconcile(context.TODO())
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+		})
+	}
+}
+
+func TestWaitForImageReplication(t *testing.T) {
+	const galleryImageID = "/subscriptions/456/resourceGroups/gallery-rg/providers/Microsoft.Compute/galleries/my-gallery/images/my-image/versions/1.2.3"
+
+	replicationStatus := func(statuses ...*armcompute.RegionalReplicationStatus) armcompute.GalleryImageVersion {
+		return armcompute.GalleryImageVersion{
+			Properties: &armcompute.GalleryImageVersionProperties{
+				ReplicationStatus: &armcompute.ReplicationStatus{
+					Summary: statuses,
+				},
+			},
+		}
+	}
+
+	testcases := []struct {
+		name          string
+		imageRef      *armcompute.ImageReference
+		expect        func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name: "doesn't check Marketplace images",
+			imageRef: &armcompute.ImageReference{
+				Publisher: to.Ptr("fake-publisher"),
+				Offer:     to.Ptr("my-offer"),
+				SKU:       to.Ptr("sku-id"),
+				Version:   to.Ptr("1.0"),
+			},
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {},
+		},
+		{
+			name: "doesn't check community gallery images",
+			imageRef: &armcompute.ImageReference{
+				CommunityGalleryImageID: to.Ptr("/CommunityGalleries/my-gallery/Images/my-image/Versions/1.2.3"),
+			},
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {},
+		},
+		{
+			name:     "marks the image replicated once the replication to the location is completed",
+			imageRef: &armcompute.ImageReference{ID: to.Ptr(galleryImageID)},
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.Location().Return("eastus")
+				m.GetGalleryImageVersion(gomockinternal.AContext(), "456", "gallery-rg", "my-gallery", "my-image", "1.2.3").Return(replicationStatus(
+					&armcompute.RegionalReplicationStatus{Region: to.Ptr("West US"), State: to.Ptr(armcompute.ReplicationStateReplicating)},
+					&armcompute.RegionalReplicationStatus{Region: to.Ptr("East US"), State: to.Ptr(armcompute.ReplicationStateCompleted), Progress: to.Ptr[int32](100)},
+				), nil)
+				s.SetImageReplicatedCondition("", clusterv1.ConditionSeverity(""), "")
+			},
+		},
+		{
+			name:          "requeues while the image is replicating to the location",
+			imageRef:      &armcompute.ImageReference{ID: to.Ptr(galleryImageID)},
+			expectedError: "is replicating to location eastus (40%)",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.Location().Return("eastus")
+				m.GetGalleryImageVersion(gomockinternal.AContext(), "456", "gallery-rg", "my-gallery", "my-image", "1.2.3").Return(replicationStatus(
+					&armcompute.RegionalReplicationStatus{Region: to.Ptr("East US"), State: to.Ptr(armcompute.ReplicationStateReplicating), Progress: to.Ptr[int32](40)},
+				), nil)
+				s.SetImageReplicatedCondition(infrav1.ImageReplicatingReason, clusterv1.ConditionSeverityInfo, gomock.Any())
+			},
+		},
+		{
+			name:          "fails when the replication to the location failed",
+			imageRef:      &armcompute.ImageReference{ID: to.Ptr(galleryImageID)},
+			expectedError: "failed to replicate to location eastus: quota exceeded",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.Location().Return("eastus")
+				m.GetGalleryImageVersion(gomockinternal.AContext(), "456", "gallery-rg", "my-gallery", "my-image", "1.2.3").Return(replicationStatus(
+					&armcompute.RegionalReplicationStatus{Region: to.Ptr("East US"), State: to.Ptr(armcompute.ReplicationStateFailed), Details: to.Ptr("quota exceeded")},
+				), nil)
+				s.SetImageReplicatedCondition(infrav1.ImageReplicationFailedReason, clusterv1.ConditionSeverityError, gomock.Any())
+			},
+		},
+		{
+			name:          "fails when the location isn't a target region of the image",
+			imageRef:      &armcompute.ImageReference{ID: to.Ptr(galleryImageID)},
+			expectedError: "is not replicated to location eastus",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.Location().Return("eastus")
+				m.GetGalleryImageVersion(gomockinternal.AContext(), "456", "gallery-rg", "my-gallery", "my-image", "1.2.3").Return(replicationStatus(
+					&armcompute.RegionalReplicationStatus{Region: to.Ptr("West US"), State: to.Ptr(armcompute.ReplicationStateCompleted)},
+				), nil)
+				s.SetImageReplicatedCondition(infrav1.ImageNotReplicatedReason, clusterv1.ConditionSeverityError, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_scalesets.NewMockScaleSetScope(mockCtrl)
+			clientMock := mock_scalesets.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.waitForImageReplication(context.TODO(), tc.imageRef)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestDeleteVMSS(t *testing.T) {
 	const (
 		resourceGroup = "my-rg"
@@ -691,9 +1099,9 @@ func TestDeleteVMSS(t *testing.T) {
 				s.ResourceGroup().AnyTimes().Return("my-existing-rg")
 				future := &infrav1.Future{}
 				s.GetLongRunningOperationState("my-existing-vmss", serviceName).Return(future)
-				m.GetResultIfDone(gomockinternal.AContext(), future).Return(compute.VirtualMachineScaleSet{}, nil)
+				m.GetResultIfDone(gomockinternal.AContext(), future).Return(armcompute.VirtualMachineScaleSet{}, nil)
 				m.Get(gomockinternal.AContext(), "my-existing-rg", "my-existing-vmss").
-					Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+					Return(armcompute.VirtualMachineScaleSet{}, &azcore.ResponseError{StatusCode: http.StatusNotFound})
 				s.DeleteLongRunningOperationState("my-existing-vmss", serviceName)
 				s.UpdateDeleteStatus(infrav1.BootstrapSucceededCondition, serviceName, nil)
 			},
@@ -710,14 +1118,14 @@ func TestDeleteVMSS(t *testing.T) {
 				s.ResourceGroup().AnyTimes().Return(resourceGroup)
 				s.GetLongRunningOperationState(name, serviceName).Return(nil)
 				m.DeleteAsync(gomockinternal.AContext(), resourceGroup, name).
-					Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+					Return(nil, &azcore.ResponseError{StatusCode: http.StatusNotFound})
 				m.Get(gomockinternal.AContext(), resourceGroup, name).
-					Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+					Return(armcompute.VirtualMachineScaleSet{}, &azcore.ResponseError{StatusCode: http.StatusNotFound})
 			},
 		},
 		{
 			name:          "vmss deletion fails",
-			expectedError: "failed to delete VMSS my-vmss in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expectedError: "failed to delete VMSS my-vmss in resource group my-rg: Internal Server Error",
 			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
 				s.ScaleSetSpec().Return(azure.ScaleSetSpec{
 					Name:     name,
@@ -727,7 +1135,7 @@ func TestDeleteVMSS(t *testing.T) {
 				s.ResourceGroup().AnyTimes().Return(resourceGroup)
 				s.GetLongRunningOperationState(name, serviceName).Return(nil)
 				m.DeleteAsync(gomockinternal.AContext(), resourceGroup, name).
-					Return(nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+					Return(nil, errors.New("Internal Server Error"))
 				m.Get(gomockinternal.AContext(), resourceGroup, name).
 					Return(newDefaultVMSS("VM_SIZE"), nil)
 				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(newDefaultInstances(), nil).AnyTimes()
@@ -764,61 +1172,538 @@ func TestDeleteVMSS(t *testing.T) {
 	}
 }
 
-func getFakeSkus() []compute.ResourceSku {
-	return []compute.ResourceSku{
+func TestDeleteVMSSOperationLimit(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	scopeMock := mock_scalesets.NewMockScaleSetScope(mockCtrl)
+	clientMock := mock_scalesets.NewMockClient(mockCtrl)
+	s := &Service{
+		Scope:  scopeMock,
+		Client: clientMock,
+	}
+
+	async.SetMaxConcurrentOperations(1)
+	defer async.SetMaxConcurrentOperations(0)
+	g.Expect(async.AcquireOperation("virtualmachine", defaultResourceGroup, "my-vm")).To(BeTrue())
+
+	// the scale set isn't deleted while the maximum number of operations is in flight
+	scopeMock.EXPECT().ScaleSetSpec().Return(azure.ScaleSetSpec{Name: defaultVMSSName}).AnyTimes()
+	scopeMock.EXPECT().ResourceGroup().Return(defaultResourceGroup).AnyTimes()
+	scopeMock.EXPECT().GetLongRunningOperationState(defaultVMSSName, serviceName).Return(nil)
+	clientMock.EXPECT().Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).
+		Return(armcompute.VirtualMachineScaleSet{}, &azcore.ResponseError{StatusCode: http.StatusNotFound}).AnyTimes()
+	g.Expect(s.Delete(context.TODO())).To(MatchError("maximum number of in-flight long-running operations reached, retrying later. Object will be requeued after 15s"))
+
+	// an ongoing deletion counts against the limit until it is done
+	async.ReleaseOperation("virtualmachine", defaultResourceGroup, "my-vm")
+	future := &infrav1.Future{Type: infrav1.DeleteFuture, ServiceName: serviceName, Name: defaultVMSSName, ResourceGroup: defaultResourceGroup}
+	scopeMock.EXPECT().GetLongRunningOperationState(defaultVMSSName, serviceName).Return(future).Times(2)
+	clientMock.EXPECT().GetResultIfDone(gomockinternal.AContext(), future).
+		Return(armcompute.VirtualMachineScaleSet{}, azure.WithTransientError(azure.NewOperationNotDoneError(future), reconciler.DefaultReconcilerRequeue))
+	g.Expect(s.Delete(context.TODO())).NotTo(Succeed())
+	g.Expect(async.AcquireOperation("virtualmachine", defaultResourceGroup, "my-vm")).To(BeFalse())
+
+	clientMock.EXPECT().GetResultIfDone(gomockinternal.AContext(), future).Return(armcompute.VirtualMachineScaleSet{}, nil)
+	scopeMock.EXPECT().DeleteLongRunningOperationState(defaultVMSSName, serviceName)
+	scopeMock.EXPECT().UpdateDeleteStatus(infrav1.BootstrapSucceededCondition, serviceName, nil)
+	g.Expect(s.Delete(context.TODO())).To(Succeed())
+	g.Expect(async.AcquireOperation("virtualmachine", defaultResourceGroup, "my-vm")).To(BeTrue())
+	async.ReleaseOperation("virtualmachine", defaultResourceGroup, "my-vm")
+}
+
+func TestCapacityOutsideSchedule(t *testing.T) {
+	testcases := []struct {
+		name     string
+		spec     azure.ScaleSetSpec
+		capacity int64
+		expected bool
+	}{
+		{
+			name:     "no scaling schedule window",
+			spec:     azure.ScaleSetSpec{},
+			capacity: 5,
+			expected: false,
+		},
+		{
+			name:     "within the window",
+			spec:     azure.ScaleSetSpec{MinCapacity: to.Ptr[int64](1), MaxCapacity: to.Ptr[int64](5)},
+			capacity: 5,
+			expected: false,
+		},
+		{
+			name:     "below the window",
+			spec:     azure.ScaleSetSpec{MinCapacity: to.Ptr[int64](2), MaxCapacity: to.Ptr[int64](5)},
+			capacity: 1,
+			expected: true,
+		},
+		{
+			name:     "above the window",
+			spec:     azure.ScaleSetSpec{MinCapacity: to.Ptr[int64](0), MaxCapacity: to.Ptr[int64](0)},
+			capacity: 3,
+			expected: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(capacityOutsideSchedule(tc.spec, tc.capacity)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestReconcileWarmPool(t *testing.T) {
+	image := infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{Version: "1.0.0"}}
+	outdatedImage := infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{Version: "0.9.0"}}
+	instance := func(id string, powerState infrav1.PowerState, image infrav1.Image) azure.VMSSVM {
+		return azure.VMSSVM{
+			ID:         "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss/virtualMachines/" + id,
+			InstanceID: id,
+			Image:      image,
+			State:      infrav1.Succeeded,
+			PowerState: powerState,
+		}
+	}
+	providerID := func(id string) string {
+		return instance(id, infrav1.PowerStateRunning, image).ProviderID()
+	}
+
+	testcases := []struct {
+		name          string
+		warmPoolSize  int64
+		replicas      int64
+		instances     []azure.VMSSVM
+		expectedError string
+		expect        func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder)
+	}{
+		{
+			name:     "does nothing without a warm pool",
+			replicas: 1,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.PowerStateRunning, image),
+				instance("1", infrav1.PowerStateRunning, image),
+			},
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {},
+		},
+		{
+			name:         "deallocates surplus instances into the warm pool",
+			warmPoolSize: 2,
+			replicas:     1,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.PowerStateRunning, image),
+				instance("1", infrav1.PowerStateRunning, image),
+				instance("2", infrav1.PowerStateRunning, image),
+			},
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ReplicaProviderIDs().Return([]string{providerID("0")})
+				m.DeallocateInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, []string{"1", "2"})
+			},
+		},
+		{
+			name:         "starts warm instances when replicas are missing",
+			warmPoolSize: 2,
+			replicas:     2,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.PowerStateRunning, image),
+				instance("1", infrav1.PowerStateDeallocated, image),
+				instance("2", infrav1.PowerStateDeallocated, image),
+			},
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ReplicaProviderIDs().Return([]string{providerID("0")})
+				m.StartInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, []string{"1"})
+			},
+		},
+		{
+			name:         "adopts running instances before starting warm instances",
+			warmPoolSize: 1,
+			replicas:     2,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.PowerStateRunning, image),
+				instance("1", infrav1.PowerStateDeallocated, image),
+				instance("2", infrav1.PowerStateRunning, image),
+			},
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ReplicaProviderIDs().Return([]string{providerID("0")})
+			},
+		},
+		{
+			name:         "deletes outdated warm instances",
+			warmPoolSize: 1,
+			replicas:     1,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.PowerStateRunning, image),
+				instance("1", infrav1.PowerStateDeallocated, outdatedImage),
+			},
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ReplicaProviderIDs().Return([]string{providerID("0")})
+				m.DeleteInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, []string{"1"})
+			},
+		},
+		{
+			name:         "fails to deallocate instances",
+			warmPoolSize: 1,
+			replicas:     1,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.PowerStateRunning, image),
+				instance("1", infrav1.PowerStateRunning, image),
+			},
+			expectedError: "failed to deallocate instances into the warm pool: Internal Server Error",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ReplicaProviderIDs().Return([]string{providerID("0")})
+				m.DeallocateInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, []string{"1"}).
+					Return(errors.New("Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_scalesets.NewMockScaleSetScope(mockCtrl)
+			clientMock := mock_scalesets.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().ScaleSetSpec().Return(azure.ScaleSetSpec{
+				Name:         defaultVMSSName,
+				Capacity:     tc.replicas + tc.warmPoolSize,
+				WarmPoolSize: tc.warmPoolSize,
+			}).AnyTimes()
+			scopeMock.EXPECT().ResourceGroup().Return(defaultResourceGroup).AnyTimes()
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.reconcileWarmPool(context.TODO(), &azure.VMSS{Image: image, Instances: tc.instances})
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestReconcilePowerState(t *testing.T) {
+	instance := func(id string, powerState infrav1.PowerState) azure.VMSSVM {
+		return azure.VMSSVM{
+			ID:         "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/my-vmss/virtualMachines/" + id,
+			InstanceID: id,
+			State:      infrav1.Succeeded,
+			PowerState: powerState,
+		}
+	}
+	providerID := func(id string) string {
+		return instance(id, infrav1.PowerStateRunning).ProviderID()
+	}
+
+	testcases := []struct {
+		name               string
+		desiredPowerState  infrav1.PowerState
+		warmPoolSize       int64
+		instances          []azure.VMSSVM
+		expectedHibernated bool
+		expectedError      string
+		expect             func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder)
+	}{
+		{
+			name:      "does nothing when the cluster is not hibernated",
+			instances: []azure.VMSSVM{instance("0", infrav1.PowerStateDeallocated)},
+			expect:    func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {},
+		},
+		{
+			name:              "deallocates the instances of a hibernated cluster",
+			desiredPowerState: infrav1.PowerStateDeallocated,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.PowerStateRunning),
+				instance("1", infrav1.PowerStateDeallocating),
+				instance("2", infrav1.PowerStateStarting),
+			},
+			expectedHibernated: true,
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				m.DeallocateInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, []string{"0", "2"})
+			},
+		},
 		{
-			Name:         to.StringPtr("VM_SIZE"),
-			ResourceType: to.StringPtr(string(resourceskus.VirtualMachines)),
-			Kind:         to.StringPtr(string(resourceskus.VirtualMachines)),
-			Locations: &[]string{
-				"test-location",
+			name:               "leaves the deallocated instances of a hibernated cluster",
+			desiredPowerState:  infrav1.PowerStateDeallocated,
+			instances:          []azure.VMSSVM{instance("0", infrav1.PowerStateDeallocated)},
+			expectedHibernated: true,
+			expect:             func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {},
+		},
+		{
+			name:              "starts the instances of a resumed cluster",
+			desiredPowerState: infrav1.PowerStateRunning,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.PowerStateDeallocated),
+				instance("1", infrav1.PowerStateRunning),
+			},
+			expectedError: "starting the instances of vmss my-vmss. Object will be requeued after 30s",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ReplicaProviderIDs().Return([]string{providerID("0"), providerID("1")})
+				m.StartInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, []string{"0"})
 			},
-			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+		},
+		{
+			name:              "keeps the warm pool of a resumed cluster deallocated",
+			desiredPowerState: infrav1.PowerStateRunning,
+			warmPoolSize:      1,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.PowerStateDeallocated),
+				instance("1", infrav1.PowerStateDeallocated),
+			},
+			expectedError: "starting the instances of vmss my-vmss. Object will be requeued after 30s",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ReplicaProviderIDs().Return([]string{providerID("0")})
+				m.StartInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, []string{"0"})
+			},
+		},
+		{
+			name:              "waits for the instances still being deallocated before starting them",
+			desiredPowerState: infrav1.PowerStateRunning,
+			instances:         []azure.VMSSVM{instance("0", infrav1.PowerStateDeallocating)},
+			expectedError:     "starting the instances of vmss my-vmss. Object will be requeued after 30s",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ReplicaProviderIDs().Return([]string{providerID("0")})
+			},
+		},
+		{
+			name:              "unmarks the scale set once the instances of a resumed cluster are started",
+			desiredPowerState: infrav1.PowerStateRunning,
+			instances:         []azure.VMSSVM{instance("0", infrav1.PowerStateStarting)},
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ReplicaProviderIDs().Return([]string{providerID("0")})
+				s.SetHibernated(false)
+			},
+		},
+		{
+			name:               "fails to deallocate the instances of a hibernated cluster",
+			desiredPowerState:  infrav1.PowerStateDeallocated,
+			instances:          []azure.VMSSVM{instance("0", infrav1.PowerStateRunning)},
+			expectedHibernated: true,
+			expectedError:      "failed to deallocate the instances of the hibernated cluster: Internal Server Error",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				m.DeallocateInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, []string{"0"}).
+					Return(errors.New("Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_scalesets.NewMockScaleSetScope(mockCtrl)
+			clientMock := mock_scalesets.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().ScaleSetSpec().Return(azure.ScaleSetSpec{
+				Name:         defaultVMSSName,
+				WarmPoolSize: tc.warmPoolSize,
+			}).AnyTimes()
+			scopeMock.EXPECT().ResourceGroup().Return(defaultResourceGroup).AnyTimes()
+			scopeMock.EXPECT().DesiredPowerState().Return(tc.desiredPowerState)
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			hibernated, err := s.reconcilePowerState(context.TODO(), &azure.VMSS{Instances: tc.instances})
+			g.Expect(hibernated).To(Equal(tc.expectedHibernated))
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestUpdateDataDisksInPlace(t *testing.T) {
+	image := infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{Version: "1.0.0"}}
+	outdatedImage := infrav1.Image{Marketplace: &infrav1.AzureMarketplaceImage{Version: "0.9.0"}}
+	dataDisks := []azure.VMSSDataDisk{{Lun: 0, DiskSizeGB: 128}, {Lun: 1, DiskSizeGB: 64}}
+	instance := func(id string, state infrav1.ProvisioningState, image infrav1.Image, dataDisks ...azure.VMSSDataDisk) azure.VMSSVM {
+		return azure.VMSSVM{
+			InstanceID: id,
+			Image:      image,
+			State:      state,
+			DataDisks:  dataDisks,
+		}
+	}
+
+	testcases := []struct {
+		name                string
+		rolloutModelUpdates bool
+		vmssState           infrav1.ProvisioningState
+		instances           []azure.VMSSVM
+		expectedError       string
+		expect              func(m *mock_scalesets.MockClientMockRecorder)
+	}{
+		{
+			name:      "does nothing when the instances have the data disks of the model",
+			vmssState: infrav1.Succeeded,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.Succeeded, image, dataDisks...),
+				instance("1", infrav1.Succeeded, image, dataDisks...),
+			},
+			expect: func(m *mock_scalesets.MockClientMockRecorder) {},
+		},
+		{
+			name:      "updates the instances missing data disks or with removed data disks",
+			vmssState: infrav1.Succeeded,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.Succeeded, image, dataDisks[0]),
+				instance("1", infrav1.Succeeded, image, dataDisks...),
+				instance("2", infrav1.Succeeded, image, append(dataDisks, azure.VMSSDataDisk{Lun: 2, DiskSizeGB: 32})...),
+			},
+			expectedError: "updating the data disks of 2 instances of vmss my-vmss. Object will be requeued after 30s",
+			expect: func(m *mock_scalesets.MockClientMockRecorder) {
+				m.UpdateInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, []string{"0", "2"})
+			},
+		},
+		{
+			name:      "leaves the instances being updated or running an outdated image",
+			vmssState: infrav1.Succeeded,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.Updating, image, dataDisks[0]),
+				instance("1", infrav1.Succeeded, outdatedImage, dataDisks[0]),
+			},
+			expect: func(m *mock_scalesets.MockClientMockRecorder) {},
+		},
+		{
+			name:                "leaves the instances to the rolling update when rolling out model updates",
+			rolloutModelUpdates: true,
+			vmssState:           infrav1.Succeeded,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.Succeeded, image, dataDisks[0]),
+			},
+			expect: func(m *mock_scalesets.MockClientMockRecorder) {},
+		},
+		{
+			name:      "waits for the scale set to be updated",
+			vmssState: infrav1.Updating,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.Succeeded, image, dataDisks[0]),
+			},
+			expect: func(m *mock_scalesets.MockClientMockRecorder) {},
+		},
+		{
+			name:      "fails to update the instances",
+			vmssState: infrav1.Succeeded,
+			instances: []azure.VMSSVM{
+				instance("0", infrav1.Succeeded, image, dataDisks[0]),
+			},
+			expectedError: "failed to update the data disks of instances: Internal Server Error",
+			expect: func(m *mock_scalesets.MockClientMockRecorder) {
+				m.UpdateInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, []string{"0"}).
+					Return(errors.New("Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_scalesets.NewMockScaleSetScope(mockCtrl)
+			clientMock := mock_scalesets.NewMockClient(mockCtrl)
+
+			scopeMock.EXPECT().ScaleSetSpec().Return(azure.ScaleSetSpec{
+				Name:                defaultVMSSName,
+				RolloutModelUpdates: tc.rolloutModelUpdates,
+			}).AnyTimes()
+			scopeMock.EXPECT().ResourceGroup().Return(defaultResourceGroup).AnyTimes()
+			tc.expect(clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.updateDataDisksInPlace(context.TODO(), &azure.VMSS{
+				State:     tc.vmssState,
+				Image:     image,
+				DataDisks: dataDisks,
+				Instances: tc.instances,
+			})
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func getFakeSkus() []armcompute.ResourceSKU {
+	return []armcompute.ResourceSKU{
+		{
+			Name:         to.Ptr("VM_SIZE"),
+			ResourceType: to.Ptr(string(resourceskus.VirtualMachines)),
+			Kind:         to.Ptr(string(resourceskus.VirtualMachines)),
+			Locations:    []*string{to.Ptr("test-location")},
+			LocationInfo: []*armcompute.ResourceSKULocationInfo{
 				{
-					Location: to.StringPtr("test-location"),
-					Zones:    &[]string{"1", "3"},
-					ZoneDetails: &[]compute.ResourceSkuZoneDetails{
+					Location: to.Ptr("test-location"),
+					Zones:    []*string{to.Ptr("1"), to.Ptr("3")},
+					ZoneDetails: []*armcompute.ResourceSKUZoneDetails{
 						{
-							Capabilities: &[]compute.ResourceSkuCapabilities{
+							Capabilities: []*armcompute.ResourceSKUCapabilities{
 								{
 									Name:  pointer.String("UltraSSDAvailable"),
 									Value: pointer.String("True"),
 								},
 							},
-							Name: &[]string{"1", "3"},
+							Name: []*string{to.Ptr("1"), to.Ptr("3")},
 						},
 					},
 				},
 			},
-			Capabilities: &[]compute.ResourceSkuCapabilities{
+			Capabilities: []*armcompute.ResourceSKUCapabilities{
 				{
-					Name:  to.StringPtr(resourceskus.AcceleratedNetworking),
-					Value: to.StringPtr(string(resourceskus.CapabilityUnsupported)),
+					Name:  to.Ptr(resourceskus.AcceleratedNetworking),
+					Value: to.Ptr(string(resourceskus.CapabilityUnsupported)),
 				},
 				{
-					Name:  to.StringPtr(resourceskus.VCPUs),
-					Value: to.StringPtr("4"),
+					Name:  to.Ptr(resourceskus.VCPUs),
+					Value: to.Ptr("4"),
 				},
 				{
-					Name:  to.StringPtr(resourceskus.MemoryGB),
-					Value: to.StringPtr("4"),
+					Name:  to.Ptr(resourceskus.MemoryGB),
+					Value: to.Ptr("4"),
 				},
 			},
 		},
 		{
-			Name:         to.StringPtr("VM_SIZE_AN"),
-			ResourceType: to.StringPtr(string(resourceskus.VirtualMachines)),
-			Kind:         to.StringPtr(string(resourceskus.VirtualMachines)),
-			Locations: &[]string{
-				"test-location",
-			},
-			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+			Name:         to.Ptr("VM_SIZE_AN"),
+			ResourceType: to.Ptr(string(resourceskus.VirtualMachines)),
+			Kind:         to.Ptr(string(resourceskus.VirtualMachines)),
+			Locations:    []*string{to.Ptr("test-location")},
+			LocationInfo: []*armcompute.ResourceSKULocationInfo{
 				{
-					Location: to.StringPtr("test-location"),
-					Zones:    &[]string{"1", "3"},
-					// ZoneDetails: &[]compute.ResourceSkuZoneDetails{
+					Location: to.Ptr("test-location"),
+					Zones:    []*string{to.Ptr("1"), to.Ptr("3")},
+					// ZoneDetails: []*armcompute.ResourceSKUZoneDetails{
 					//    {
-					//        	Capabilities: &[]compute.ResourceSkuCapabilities{
+					//        	Capabilities: []*armcompute.ResourceSKUCapabilities{
 					//        		{
 					//        			Name:  pointer.String("UltraSSDAvailable"),
 					//        			Value: pointer.String("True"),
@@ -828,91 +1713,85 @@ func getFakeSkus() []compute.ResourceSku {
 					//    },
 				},
 			},
-			Capabilities: &[]compute.ResourceSkuCapabilities{
+			Capabilities: []*armcompute.ResourceSKUCapabilities{
 				{
-					Name:  to.StringPtr(resourceskus.AcceleratedNetworking),
-					Value: to.StringPtr(string(resourceskus.CapabilitySupported)),
+					Name:  to.Ptr(resourceskus.AcceleratedNetworking),
+					Value: to.Ptr(string(resourceskus.CapabilitySupported)),
 				},
 				{
-					Name:  to.StringPtr(resourceskus.VCPUs),
-					Value: to.StringPtr("4"),
+					Name:  to.Ptr(resourceskus.VCPUs),
+					Value: to.Ptr("4"),
 				},
 				{
-					Name:  to.StringPtr(resourceskus.MemoryGB),
-					Value: to.StringPtr("6"),
+					Name:  to.Ptr(resourceskus.MemoryGB),
+					Value: to.Ptr("6"),
 				},
 			},
 		},
 		{
-			Name:         to.StringPtr("VM_SIZE_1_CPU"),
-			ResourceType: to.StringPtr(string(resourceskus.VirtualMachines)),
-			Kind:         to.StringPtr(string(resourceskus.VirtualMachines)),
-			Locations: &[]string{
-				"test-location",
-			},
-			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+			Name:         to.Ptr("VM_SIZE_1_CPU"),
+			ResourceType: to.Ptr(string(resourceskus.VirtualMachines)),
+			Kind:         to.Ptr(string(resourceskus.VirtualMachines)),
+			Locations:    []*string{to.Ptr("test-location")},
+			LocationInfo: []*armcompute.ResourceSKULocationInfo{
 				{
-					Location: to.StringPtr("test-location"),
-					Zones:    &[]string{"1", "3"},
+					Location: to.Ptr("test-location"),
+					Zones:    []*string{to.Ptr("1"), to.Ptr("3")},
 				},
 			},
-			Capabilities: &[]compute.ResourceSkuCapabilities{
+			Capabilities: []*armcompute.ResourceSKUCapabilities{
 				{
-					Name:  to.StringPtr(resourceskus.AcceleratedNetworking),
-					Value: to.StringPtr(string(resourceskus.CapabilityUnsupported)),
+					Name:  to.Ptr(resourceskus.AcceleratedNetworking),
+					Value: to.Ptr(string(resourceskus.CapabilityUnsupported)),
 				},
 				{
-					Name:  to.StringPtr(resourceskus.VCPUs),
-					Value: to.StringPtr("1"),
+					Name:  to.Ptr(resourceskus.VCPUs),
+					Value: to.Ptr("1"),
 				},
 				{
-					Name:  to.StringPtr(resourceskus.MemoryGB),
-					Value: to.StringPtr("4"),
+					Name:  to.Ptr(resourceskus.MemoryGB),
+					Value: to.Ptr("4"),
 				},
 			},
 		},
 		{
-			Name:         to.StringPtr("VM_SIZE_1_MEM"),
-			ResourceType: to.StringPtr(string(resourceskus.VirtualMachines)),
-			Kind:         to.StringPtr(string(resourceskus.VirtualMachines)),
-			Locations: &[]string{
-				"test-location",
-			},
-			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+			Name:         to.Ptr("VM_SIZE_1_MEM"),
+			ResourceType: to.Ptr(string(resourceskus.VirtualMachines)),
+			Kind:         to.Ptr(string(resourceskus.VirtualMachines)),
+			Locations:    []*string{to.Ptr("test-location")},
+			LocationInfo: []*armcompute.ResourceSKULocationInfo{
 				{
-					Location: to.StringPtr("test-location"),
-					Zones:    &[]string{"1", "3"},
+					Location: to.Ptr("test-location"),
+					Zones:    []*string{to.Ptr("1"), to.Ptr("3")},
 				},
 			},
-			Capabilities: &[]compute.ResourceSkuCapabilities{
+			Capabilities: []*armcompute.ResourceSKUCapabilities{
 				{
-					Name:  to.StringPtr(resourceskus.AcceleratedNetworking),
-					Value: to.StringPtr(string(resourceskus.CapabilityUnsupported)),
+					Name:  to.Ptr(resourceskus.AcceleratedNetworking),
+					Value: to.Ptr(string(resourceskus.CapabilityUnsupported)),
 				},
 				{
-					Name:  to.StringPtr(resourceskus.VCPUs),
-					Value: to.StringPtr("2"),
+					Name:  to.Ptr(resourceskus.VCPUs),
+					Value: to.Ptr("2"),
 				},
 				{
-					Name:  to.StringPtr(resourceskus.MemoryGB),
-					Value: to.StringPtr("1"),
+					Name:  to.Ptr(resourceskus.MemoryGB),
+					Value: to.Ptr("1"),
 				},
 			},
 		},
 		{
-			Name:         to.StringPtr("VM_SIZE_EAH"),
-			ResourceType: to.StringPtr(string(resourceskus.VirtualMachines)),
-			Kind:         to.StringPtr(string(resourceskus.VirtualMachines)),
-			Locations: &[]string{
-				"test-location",
-			},
-			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+			Name:         to.Ptr("VM_SIZE_EAH"),
+			ResourceType: to.Ptr(string(resourceskus.VirtualMachines)),
+			Kind:         to.Ptr(string(resourceskus.VirtualMachines)),
+			Locations:    []*string{to.Ptr("test-location")},
+			LocationInfo: []*armcompute.ResourceSKULocationInfo{
 				{
-					Location: to.StringPtr("test-location"),
-					Zones:    &[]string{"1", "3"},
-					//  ZoneDetails: &[]compute.ResourceSkuZoneDetails{
+					Location: to.Ptr("test-location"),
+					Zones:    []*string{to.Ptr("1"), to.Ptr("3")},
+					//  ZoneDetails: []*armcompute.ResourceSKUZoneDetails{
 					//	    {
-					//    		Capabilities: &[]compute.ResourceSkuCapabilities{
+					//    		Capabilities: []*armcompute.ResourceSKUCapabilities{
 					//    			{
 					//    				Name:  pointer.String("UltraSSDAvailable"),
 					//    				Value: pointer.String("True"),
@@ -922,46 +1801,70 @@ func getFakeSkus() []compute.ResourceSku {
 					//  },
 				},
 			},
-			Capabilities: &[]compute.ResourceSkuCapabilities{
+			Capabilities: []*armcompute.ResourceSKUCapabilities{
 				{
-					Name:  to.StringPtr(resourceskus.VCPUs),
-					Value: to.StringPtr("4"),
+					Name:  to.Ptr(resourceskus.VCPUs),
+					Value: to.Ptr("4"),
 				},
 				{
-					Name:  to.StringPtr(resourceskus.MemoryGB),
-					Value: to.StringPtr("8"),
+					Name:  to.Ptr(resourceskus.MemoryGB),
+					Value: to.Ptr("8"),
 				},
 				{
-					Name:  to.StringPtr(resourceskus.EncryptionAtHost),
-					Value: to.StringPtr(string(resourceskus.CapabilitySupported)),
+					Name:  to.Ptr(resourceskus.EncryptionAtHost),
+					Value: to.Ptr(string(resourceskus.CapabilitySupported)),
 				},
 			},
 		},
 		{
-			Name:         to.StringPtr("VM_SIZE_USSD"),
-			ResourceType: to.StringPtr(string(resourceskus.VirtualMachines)),
-			Kind:         to.StringPtr(string(resourceskus.VirtualMachines)),
-			Locations: &[]string{
-				"test-location",
+			Name:         to.Ptr("VM_SIZE_WA"),
+			ResourceType: to.Ptr(string(resourceskus.VirtualMachines)),
+			Kind:         to.Ptr(string(resourceskus.VirtualMachines)),
+			Locations:    []*string{to.Ptr("test-location")},
+			LocationInfo: []*armcompute.ResourceSKULocationInfo{
+				{
+					Location: to.Ptr("test-location"),
+					Zones:    []*string{to.Ptr("1"), to.Ptr("3")},
+				},
 			},
-			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+			Capabilities: []*armcompute.ResourceSKUCapabilities{
+				{
+					Name:  to.Ptr(resourceskus.VCPUs),
+					Value: to.Ptr("8"),
+				},
 				{
-					Location: to.StringPtr("test-location"),
-					Zones:    &[]string{"1", "3"},
+					Name:  to.Ptr(resourceskus.MemoryGB),
+					Value: to.Ptr("218"),
+				},
+				{
+					Name:  to.Ptr(resourceskus.MaxWriteAcceleratorDisksAllowed),
+					Value: to.Ptr("1"),
 				},
 			},
-			Capabilities: &[]compute.ResourceSkuCapabilities{
+		},
+		{
+			Name:         to.Ptr("VM_SIZE_USSD"),
+			ResourceType: to.Ptr(string(resourceskus.VirtualMachines)),
+			Kind:         to.Ptr(string(resourceskus.VirtualMachines)),
+			Locations:    []*string{to.Ptr("test-location")},
+			LocationInfo: []*armcompute.ResourceSKULocationInfo{
+				{
+					Location: to.Ptr("test-location"),
+					Zones:    []*string{to.Ptr("1"), to.Ptr("3")},
+				},
+			},
+			Capabilities: []*armcompute.ResourceSKUCapabilities{
 				{
-					Name:  to.StringPtr(resourceskus.AcceleratedNetworking),
-					Value: to.StringPtr(string(resourceskus.CapabilitySupported)),
+					Name:  to.Ptr(resourceskus.AcceleratedNetworking),
+					Value: to.Ptr(string(resourceskus.CapabilitySupported)),
 				},
 				{
-					Name:  to.StringPtr(resourceskus.VCPUs),
-					Value: to.StringPtr("4"),
+					Name:  to.Ptr(resourceskus.VCPUs),
+					Value: to.Ptr("4"),
 				},
 				{
-					Name:  to.StringPtr(resourceskus.MemoryGB),
-					Value: to.StringPtr("6"),
+					Name:  to.Ptr(resourceskus.MemoryGB),
+					Value: to.Ptr("6"),
 				},
 			},
 		},
@@ -976,7 +1879,7 @@ func newDefaultVMSSSpec() azure.ScaleSetSpec {
 		SSHKeyData: "ZmFrZXNzaGtleQo=",
 		OSDisk: infrav1.OSDisk{
 			OSType:     "Linux",
-			DiskSizeGB: to.Int32Ptr(120),
+			DiskSizeGB: to.Ptr[int32](120),
 			ManagedDisk: &infrav1.ManagedDiskParameters{
 				StorageAccountType: "Premium_LRS",
 			},
@@ -985,12 +1888,12 @@ func newDefaultVMSSSpec() azure.ScaleSetSpec {
 			{
 				NameSuffix: "my_disk",
 				DiskSizeGB: 128,
-				Lun:        to.Int32Ptr(0),
+				Lun:        to.Ptr[int32](0),
 			},
 			{
 				NameSuffix: "my_disk_with_managed_disk",
 				DiskSizeGB: 128,
-				Lun:        to.Int32Ptr(1),
+				Lun:        to.Ptr[int32](1),
 				ManagedDisk: &infrav1.ManagedDiskParameters{
 					StorageAccountType: "Standard_LRS",
 				},
@@ -998,7 +1901,7 @@ func newDefaultVMSSSpec() azure.ScaleSetSpec {
 			{
 				NameSuffix: "managed_disk_with_encryption",
 				DiskSizeGB: 128,
-				Lun:        to.Int32Ptr(2),
+				Lun:        to.Ptr[int32](2),
 				ManagedDisk: &infrav1.ManagedDiskParameters{
 					StorageAccountType: "Standard_LRS",
 					DiskEncryptionSet: &infrav1.DiskEncryptionSetParameters{
@@ -1013,7 +1916,7 @@ func newDefaultVMSSSpec() azure.ScaleSetSpec {
 		PublicLBName:                 "capz-lb",
 		PublicLBAddressPoolName:      "backendPool",
 		AcceleratedNetworking:        nil,
-		TerminateNotificationTimeout: to.IntPtr(7),
+		TerminateNotificationTimeout: to.Ptr(7),
 		FailureDomains:               []string{"1", "3"},
 	}
 }
@@ -1024,106 +1927,106 @@ func newWindowsVMSSSpec() azure.ScaleSetSpec {
 	return vmss
 }
 
-func newDefaultExistingVMSS(vmSize string) compute.VirtualMachineScaleSet {
+func newDefaultExistingVMSS(vmSize string) armcompute.VirtualMachineScaleSet {
 	vmss := newDefaultVMSS(vmSize)
-	vmss.ID = to.StringPtr("vmss-id")
+	vmss.ID = to.Ptr("vmss-id")
 	return vmss
 }
 
-func newDefaultWindowsVMSS() compute.VirtualMachineScaleSet {
+func newDefaultWindowsVMSS() armcompute.VirtualMachineScaleSet {
 	vmss := newDefaultVMSS("VM_SIZE")
-	vmss.VirtualMachineScaleSetProperties.VirtualMachineProfile.StorageProfile.OsDisk.OsType = compute.OperatingSystemTypesWindows
-	vmss.VirtualMachineProfile.OsProfile.LinuxConfiguration = nil
-	vmss.VirtualMachineProfile.OsProfile.WindowsConfiguration = &compute.WindowsConfiguration{
-		EnableAutomaticUpdates: to.BoolPtr(false),
+	vmss.Properties.VirtualMachineProfile.StorageProfile.OSDisk.OSType = to.Ptr(armcompute.OperatingSystemTypesWindows)
+	vmss.Properties.VirtualMachineProfile.OSProfile.LinuxConfiguration = nil
+	vmss.Properties.VirtualMachineProfile.OSProfile.WindowsConfiguration = &armcompute.WindowsConfiguration{
+		EnableAutomaticUpdates: to.Ptr(false),
 	}
 	return vmss
 }
 
-func newDefaultVMSS(vmSize string) compute.VirtualMachineScaleSet {
+func newDefaultVMSS(vmSize string) armcompute.VirtualMachineScaleSet {
 	dataDisk := fetchDataDiskBasedOnSize(vmSize)
-	return compute.VirtualMachineScaleSet{
-		Location: to.StringPtr("test-location"),
+	return armcompute.VirtualMachineScaleSet{
+		Location: to.Ptr("test-location"),
 		Tags: map[string]*string{
-			"Name": to.StringPtr(defaultVMSSName),
-			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
-			"sigs.k8s.io_cluster-api-provider-azure_role":               to.StringPtr("node"),
-		},
-		Sku: &compute.Sku{
-			Name:     to.StringPtr(vmSize),
-			Tier:     to.StringPtr("Standard"),
-			Capacity: to.Int64Ptr(2),
-		},
-		Zones: &[]string{"1", "3"},
-		VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
-			SinglePlacementGroup: to.BoolPtr(false),
-			UpgradePolicy: &compute.UpgradePolicy{
-				Mode: compute.UpgradeModeManual,
-			},
-			Overprovision: to.BoolPtr(false),
-			VirtualMachineProfile: &compute.VirtualMachineScaleSetVMProfile{
-				OsProfile: &compute.VirtualMachineScaleSetOSProfile{
-					ComputerNamePrefix: to.StringPtr(defaultVMSSName),
-					AdminUsername:      to.StringPtr(azure.DefaultUserName),
-					CustomData:         to.StringPtr("fake-bootstrap-data"),
-					LinuxConfiguration: &compute.LinuxConfiguration{
-						SSH: &compute.SSHConfiguration{
-							PublicKeys: &[]compute.SSHPublicKey{
+			"Name": to.Ptr(defaultVMSSName),
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+			"sigs.k8s.io_cluster-api-provider-azure_role":               to.Ptr("node"),
+		},
+		SKU: &armcompute.SKU{
+			Name:     to.Ptr(vmSize),
+			Tier:     to.Ptr("Standard"),
+			Capacity: to.Ptr[int64](2),
+		},
+		Zones: []*string{to.Ptr("1"), to.Ptr("3")},
+		Properties: &armcompute.VirtualMachineScaleSetProperties{
+			SinglePlacementGroup: to.Ptr(false),
+			UpgradePolicy: &armcompute.UpgradePolicy{
+				Mode: to.Ptr(armcompute.UpgradeModeManual),
+			},
+			Overprovision: to.Ptr(false),
+			VirtualMachineProfile: &armcompute.VirtualMachineScaleSetVMProfile{
+				OSProfile: &armcompute.VirtualMachineScaleSetOSProfile{
+					ComputerNamePrefix: to.Ptr(defaultVMSSName),
+					AdminUsername:      to.Ptr(azure.DefaultUserName),
+					CustomData:         to.Ptr("fake-bootstrap-data"),
+					LinuxConfiguration: &armcompute.LinuxConfiguration{
+						SSH: &armcompute.SSHConfiguration{
+							PublicKeys: []*armcompute.SSHPublicKey{
 								{
-									Path:    to.StringPtr("/home/capi/.ssh/authorized_keys"),
-									KeyData: to.StringPtr("fakesshkey\n"),
+									Path:    to.Ptr("/home/capi/.ssh/authorized_keys"),
+									KeyData: to.Ptr("fakesshkey\n"),
 								},
 							},
 						},
-						DisablePasswordAuthentication: to.BoolPtr(true),
+						DisablePasswordAuthentication: to.Ptr(true),
 					},
 				},
-				ScheduledEventsProfile: &compute.ScheduledEventsProfile{
-					TerminateNotificationProfile: &compute.TerminateNotificationProfile{
-						NotBeforeTimeout: to.StringPtr("PT7M"),
-						Enable:           to.BoolPtr(true),
+				ScheduledEventsProfile: &armcompute.ScheduledEventsProfile{
+					TerminateNotificationProfile: &armcompute.TerminateNotificationProfile{
+						NotBeforeTimeout: to.Ptr("PT7M"),
+						Enable:           to.Ptr(true),
 					},
 				},
-				StorageProfile: &compute.VirtualMachineScaleSetStorageProfile{
-					ImageReference: &compute.ImageReference{
-						Publisher: to.StringPtr("fake-publisher"),
-						Offer:     to.StringPtr("my-offer"),
-						Sku:       to.StringPtr("sku-id"),
-						Version:   to.StringPtr("1.0"),
+				StorageProfile: &armcompute.VirtualMachineScaleSetStorageProfile{
+					ImageReference: &armcompute.ImageReference{
+						Publisher: to.Ptr("fake-publisher"),
+						Offer:     to.Ptr("my-offer"),
+						SKU:       to.Ptr("sku-id"),
+						Version:   to.Ptr("1.0"),
 					},
-					OsDisk: &compute.VirtualMachineScaleSetOSDisk{
-						OsType:       "Linux",
-						CreateOption: "FromImage",
-						DiskSizeGB:   to.Int32Ptr(120),
-						ManagedDisk: &compute.VirtualMachineScaleSetManagedDiskParameters{
-							StorageAccountType: "Premium_LRS",
+					OSDisk: &armcompute.VirtualMachineScaleSetOSDisk{
+						OSType:       to.Ptr(armcompute.OperatingSystemTypesLinux),
+						CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesFromImage),
+						DiskSizeGB:   to.Ptr[int32](120),
+						ManagedDisk: &armcompute.VirtualMachineScaleSetManagedDiskParameters{
+							StorageAccountType: to.Ptr(armcompute.StorageAccountTypesPremiumLRS),
 						},
 					},
 					DataDisks: dataDisk,
 				},
-				DiagnosticsProfile: &compute.DiagnosticsProfile{
-					BootDiagnostics: &compute.BootDiagnostics{
-						Enabled: to.BoolPtr(true),
+				DiagnosticsProfile: &armcompute.DiagnosticsProfile{
+					BootDiagnostics: &armcompute.BootDiagnostics{
+						Enabled: to.Ptr(true),
 					},
 				},
-				NetworkProfile: &compute.VirtualMachineScaleSetNetworkProfile{
-					NetworkInterfaceConfigurations: &[]compute.VirtualMachineScaleSetNetworkConfiguration{
+				NetworkProfile: &armcompute.VirtualMachineScaleSetNetworkProfile{
+					NetworkInterfaceConfigurations: []*armcompute.VirtualMachineScaleSetNetworkConfiguration{
 						{
-							Name: to.StringPtr("my-vmss"),
-							VirtualMachineScaleSetNetworkConfigurationProperties: &compute.VirtualMachineScaleSetNetworkConfigurationProperties{
-								Primary:                     to.BoolPtr(true),
-								EnableAcceleratedNetworking: to.BoolPtr(false),
-								EnableIPForwarding:          to.BoolPtr(true),
-								IPConfigurations: &[]compute.VirtualMachineScaleSetIPConfiguration{
+							Name: to.Ptr("my-vmss"),
+							Properties: &armcompute.VirtualMachineScaleSetNetworkConfigurationProperties{
+								Primary:                     to.Ptr(true),
+								EnableAcceleratedNetworking: to.Ptr(false),
+								EnableIPForwarding:          to.Ptr(true),
+								IPConfigurations: []*armcompute.VirtualMachineScaleSetIPConfiguration{
 									{
-										Name: to.StringPtr("my-vmss"),
-										VirtualMachineScaleSetIPConfigurationProperties: &compute.VirtualMachineScaleSetIPConfigurationProperties{
-											Subnet: &compute.APIEntityReference{
-												ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet"),
+										Name: to.Ptr("my-vmss"),
+										Properties: &armcompute.VirtualMachineScaleSetIPConfigurationProperties{
+											Subnet: &armcompute.APIEntityReference{
+												ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet"),
 											},
-											Primary:                         to.BoolPtr(true),
-											PrivateIPAddressVersion:         compute.IPVersionIPv4,
-											LoadBalancerBackendAddressPools: &[]compute.SubResource{{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/capz-lb/backendAddressPools/backendPool")}},
+											Primary:                         to.Ptr(true),
+											PrivateIPAddressVersion:         to.Ptr(armcompute.IPVersionIPv4),
+											LoadBalancerBackendAddressPools: []*armcompute.SubResource{{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/loadBalancers/capz-lb/backendAddressPools/backendPool")}},
 										},
 									},
 								},
@@ -1131,14 +2034,14 @@ func newDefaultVMSS(vmSize string) compute.VirtualMachineScaleSet {
 						},
 					},
 				},
-				ExtensionProfile: &compute.VirtualMachineScaleSetExtensionProfile{
-					Extensions: &[]compute.VirtualMachineScaleSetExtension{
+				ExtensionProfile: &armcompute.VirtualMachineScaleSetExtensionProfile{
+					Extensions: []*armcompute.VirtualMachineScaleSetExtension{
 						{
-							Name: to.StringPtr("someExtension"),
-							VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
-								Publisher:          to.StringPtr("somePublisher"),
-								Type:               to.StringPtr("someExtension"),
-								TypeHandlerVersion: to.StringPtr("someVersion"),
+							Name: to.Ptr("someExtension"),
+							Properties: &armcompute.VirtualMachineScaleSetExtensionProperties{
+								Publisher:          to.Ptr("somePublisher"),
+								Type:               to.Ptr("someExtension"),
+								TypeHandlerVersion: to.Ptr("someVersion"),
 								ProtectedSettings: map[string]string{
 									"commandToExecute": "echo hello",
 								},
@@ -1151,73 +2054,73 @@ func newDefaultVMSS(vmSize string) compute.VirtualMachineScaleSet {
 	}
 }
 
-func fetchDataDiskBasedOnSize(vmSize string) *[]compute.VirtualMachineScaleSetDataDisk {
-	var dataDisk *[]compute.VirtualMachineScaleSetDataDisk
+func fetchDataDiskBasedOnSize(vmSize string) []*armcompute.VirtualMachineScaleSetDataDisk {
+	var dataDisk []*armcompute.VirtualMachineScaleSetDataDisk
 	if vmSize == "VM_SIZE" {
-		dataDisk = &[]compute.VirtualMachineScaleSetDataDisk{
+		dataDisk = []*armcompute.VirtualMachineScaleSetDataDisk{
 			{
-				Lun:          to.Int32Ptr(0),
-				Name:         to.StringPtr("my-vmss_my_disk"),
-				CreateOption: "Empty",
-				DiskSizeGB:   to.Int32Ptr(128),
+				Lun:          to.Ptr[int32](0),
+				Name:         to.Ptr("my-vmss_my_disk"),
+				CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+				DiskSizeGB:   to.Ptr[int32](128),
 			},
 			{
-				Lun:          to.Int32Ptr(1),
-				Name:         to.StringPtr("my-vmss_my_disk_with_managed_disk"),
-				CreateOption: "Empty",
-				DiskSizeGB:   to.Int32Ptr(128),
-				ManagedDisk: &compute.VirtualMachineScaleSetManagedDiskParameters{
-					StorageAccountType: "Standard_LRS",
+				Lun:          to.Ptr[int32](1),
+				Name:         to.Ptr("my-vmss_my_disk_with_managed_disk"),
+				CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+				DiskSizeGB:   to.Ptr[int32](128),
+				ManagedDisk: &armcompute.VirtualMachineScaleSetManagedDiskParameters{
+					StorageAccountType: to.Ptr(armcompute.StorageAccountTypesStandardLRS),
 				},
 			},
 			{
-				Lun:          to.Int32Ptr(2),
-				Name:         to.StringPtr("my-vmss_managed_disk_with_encryption"),
-				CreateOption: "Empty",
-				DiskSizeGB:   to.Int32Ptr(128),
-				ManagedDisk: &compute.VirtualMachineScaleSetManagedDiskParameters{
-					StorageAccountType: "Standard_LRS",
-					DiskEncryptionSet: &compute.DiskEncryptionSetParameters{
-						ID: to.StringPtr("encryption_id"),
+				Lun:          to.Ptr[int32](2),
+				Name:         to.Ptr("my-vmss_managed_disk_with_encryption"),
+				CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+				DiskSizeGB:   to.Ptr[int32](128),
+				ManagedDisk: &armcompute.VirtualMachineScaleSetManagedDiskParameters{
+					StorageAccountType: to.Ptr(armcompute.StorageAccountTypesStandardLRS),
+					DiskEncryptionSet: &armcompute.DiskEncryptionSetParameters{
+						ID: to.Ptr("encryption_id"),
 					},
 				},
 			},
 			{
-				Lun:          to.Int32Ptr(3),
-				Name:         to.StringPtr("my-vmss_my_disk_with_ultra_disks"),
-				CreateOption: "Empty",
-				DiskSizeGB:   to.Int32Ptr(128),
-				ManagedDisk: &compute.VirtualMachineScaleSetManagedDiskParameters{
-					StorageAccountType: "UltraSSD_LRS",
+				Lun:          to.Ptr[int32](3),
+				Name:         to.Ptr("my-vmss_my_disk_with_ultra_disks"),
+				CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+				DiskSizeGB:   to.Ptr[int32](128),
+				ManagedDisk: &armcompute.VirtualMachineScaleSetManagedDiskParameters{
+					StorageAccountType: to.Ptr(armcompute.StorageAccountTypesUltraSSDLRS),
 				},
 			},
 		}
 	} else {
-		dataDisk = &[]compute.VirtualMachineScaleSetDataDisk{
+		dataDisk = []*armcompute.VirtualMachineScaleSetDataDisk{
 			{
-				Lun:          to.Int32Ptr(0),
-				Name:         to.StringPtr("my-vmss_my_disk"),
-				CreateOption: "Empty",
-				DiskSizeGB:   to.Int32Ptr(128),
+				Lun:          to.Ptr[int32](0),
+				Name:         to.Ptr("my-vmss_my_disk"),
+				CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+				DiskSizeGB:   to.Ptr[int32](128),
 			},
 			{
-				Lun:          to.Int32Ptr(1),
-				Name:         to.StringPtr("my-vmss_my_disk_with_managed_disk"),
-				CreateOption: "Empty",
-				DiskSizeGB:   to.Int32Ptr(128),
-				ManagedDisk: &compute.VirtualMachineScaleSetManagedDiskParameters{
-					StorageAccountType: "Standard_LRS",
+				Lun:          to.Ptr[int32](1),
+				Name:         to.Ptr("my-vmss_my_disk_with_managed_disk"),
+				CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+				DiskSizeGB:   to.Ptr[int32](128),
+				ManagedDisk: &armcompute.VirtualMachineScaleSetManagedDiskParameters{
+					StorageAccountType: to.Ptr(armcompute.StorageAccountTypesStandardLRS),
 				},
 			},
 			{
-				Lun:          to.Int32Ptr(2),
-				Name:         to.StringPtr("my-vmss_managed_disk_with_encryption"),
-				CreateOption: "Empty",
-				DiskSizeGB:   to.Int32Ptr(128),
-				ManagedDisk: &compute.VirtualMachineScaleSetManagedDiskParameters{
-					StorageAccountType: "Standard_LRS",
-					DiskEncryptionSet: &compute.DiskEncryptionSetParameters{
-						ID: to.StringPtr("encryption_id"),
+				Lun:          to.Ptr[int32](2),
+				Name:         to.Ptr("my-vmss_managed_disk_with_encryption"),
+				CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+				DiskSizeGB:   to.Ptr[int32](128),
+				ManagedDisk: &armcompute.VirtualMachineScaleSetManagedDiskParameters{
+					StorageAccountType: to.Ptr(armcompute.StorageAccountTypesStandardLRS),
+					DiskEncryptionSet: &armcompute.DiskEncryptionSetParameters{
+						ID: to.Ptr("encryption_id"),
 					},
 				},
 			},
@@ -1226,52 +2129,63 @@ func fetchDataDiskBasedOnSize(vmSize string) *[]compute.VirtualMachineScaleSetDa
 	return dataDisk
 }
 
-func newDefaultInstances() []compute.VirtualMachineScaleSetVM {
-	return []compute.VirtualMachineScaleSetVM{
+func newDefaultInstances() []armcompute.VirtualMachineScaleSetVM {
+	return []armcompute.VirtualMachineScaleSetVM{
 		{
-			ID:         to.StringPtr("my-vm-id"),
-			InstanceID: to.StringPtr("my-vm-1"),
-			Name:       to.StringPtr("my-vm"),
-			VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
-				ProvisioningState: to.StringPtr("Succeeded"),
-				OsProfile: &compute.OSProfile{
-					ComputerName: to.StringPtr("instance-000001"),
+			ID:         to.Ptr("my-vm-id"),
+			InstanceID: to.Ptr("my-vm-1"),
+			Name:       to.Ptr("my-vm"),
+			Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+				ProvisioningState: to.Ptr("Succeeded"),
+				OSProfile: &armcompute.OSProfile{
+					ComputerName: to.Ptr("instance-000001"),
 				},
-				StorageProfile: &compute.StorageProfile{
-					ImageReference: &compute.ImageReference{
-						Publisher: to.StringPtr("fake-publisher"),
-						Offer:     to.StringPtr("my-offer"),
-						Sku:       to.StringPtr("sku-id"),
-						Version:   to.StringPtr("1.0"),
+				StorageProfile: &armcompute.StorageProfile{
+					ImageReference: &armcompute.ImageReference{
+						Publisher: to.Ptr("fake-publisher"),
+						Offer:     to.Ptr("my-offer"),
+						SKU:       to.Ptr("sku-id"),
+						Version:   to.Ptr("1.0"),
 					},
+					DataDisks: newDefaultInstanceDataDisks(),
 				},
 			},
 		},
 		{
-			ID:         to.StringPtr("my-vm-id"),
-			InstanceID: to.StringPtr("my-vm-2"),
-			Name:       to.StringPtr("my-vm"),
-			VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
-				ProvisioningState: to.StringPtr("Succeeded"),
-				OsProfile: &compute.OSProfile{
-					ComputerName: to.StringPtr("instance-000002"),
+			ID:         to.Ptr("my-vm-id"),
+			InstanceID: to.Ptr("my-vm-2"),
+			Name:       to.Ptr("my-vm"),
+			Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+				ProvisioningState: to.Ptr("Succeeded"),
+				OSProfile: &armcompute.OSProfile{
+					ComputerName: to.Ptr("instance-000002"),
 				},
-				StorageProfile: &compute.StorageProfile{
-					ImageReference: &compute.ImageReference{
-						Publisher: to.StringPtr("fake-publisher"),
-						Offer:     to.StringPtr("my-offer"),
-						Sku:       to.StringPtr("sku-id"),
-						Version:   to.StringPtr("1.0"),
+				StorageProfile: &armcompute.StorageProfile{
+					ImageReference: &armcompute.ImageReference{
+						Publisher: to.Ptr("fake-publisher"),
+						Offer:     to.Ptr("my-offer"),
+						SKU:       to.Ptr("sku-id"),
+						Version:   to.Ptr("1.0"),
 					},
+					DataDisks: newDefaultInstanceDataDisks(),
 				},
 			},
 		},
 	}
 }
 
-func setupDefaultVMSSInProgressOperationDoneExpectations(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder, createdVMSS compute.VirtualMachineScaleSet, instances []compute.VirtualMachineScaleSetVM) {
-	createdVMSS.ID = to.StringPtr("vmss-id")
-	createdVMSS.ProvisioningState = to.StringPtr(string(infrav1.Succeeded))
+// newDefaultInstanceDataDisks returns the data disks of the instances of the scale set of newDefaultVMSS("VM_SIZE").
+func newDefaultInstanceDataDisks() []*armcompute.DataDisk {
+	var dataDisks []*armcompute.DataDisk
+	for lun := int32(0); lun < 4; lun++ {
+		dataDisks = append(dataDisks, &armcompute.DataDisk{Lun: to.Ptr[int32](lun), DiskSizeGB: to.Ptr[int32](128)})
+	}
+	return dataDisks
+}
+
+func setupDefaultVMSSInProgressOperationDoneExpectations(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder, createdVMSS armcompute.VirtualMachineScaleSet, instances []armcompute.VirtualMachineScaleSetVM) {
+	createdVMSS.ID = to.Ptr("vmss-id")
+	createdVMSS.Properties.ProvisioningState = to.Ptr(string(infrav1.Succeeded))
 	setupDefaultVMSSExpectations(s)
 	future := &infrav1.Future{
 		Type:          infrav1.PutFuture,
@@ -1291,12 +2205,12 @@ func setupDefaultVMSSStartCreatingExpectations(s *mock_scalesets.MockScaleSetSco
 	setupDefaultVMSSExpectations(s)
 	s.GetLongRunningOperationState(defaultVMSSName, serviceName).Return(nil)
 	m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).
-		Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+		Return(armcompute.VirtualMachineScaleSet{}, &azcore.ResponseError{StatusCode: http.StatusNotFound})
 }
 
-func setupCreatingSucceededExpectations(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder, vmss compute.VirtualMachineScaleSet, future *infrav1.Future) {
+func setupCreatingSucceededExpectations(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder, vmss armcompute.VirtualMachineScaleSet, future *infrav1.Future) {
 	s.SetLongRunningOperationState(future)
-	m.GetResultIfDone(gomockinternal.AContext(), future).Return(compute.VirtualMachineScaleSet{}, azure.NewOperationNotDoneError(future))
+	m.GetResultIfDone(gomockinternal.AContext(), future).Return(armcompute.VirtualMachineScaleSet{}, azure.NewOperationNotDoneError(future))
 	m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(vmss, nil)
 	m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(newDefaultInstances(), nil).AnyTimes()
 	s.SetVMSSState(gomock.Any())
@@ -1340,8 +2254,11 @@ func setupVMSSExpectationsWithoutVMImage(s *mock_scalesets.MockScaleSetScopeMock
 	s.ResourceGroup().AnyTimes().Return(defaultResourceGroup)
 	s.AdditionalTags()
 	s.Location().AnyTimes().Return("test-location")
+	s.ExtendedLocation().AnyTimes().Return(nil)
 	s.ClusterName().Return("my-cluster")
 	s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
+	s.GetUserData(gomockinternal.AContext()).Return("", nil)
+	s.DesiredPowerState().Return(infrav1.PowerState("")).AnyTimes()
 	s.VMSSExtensionSpecs().Return([]azure.ResourceSpecGetter{
 		&VMSSExtensionSpec{
 			ExtensionSpec: azure.ExtensionSpec{
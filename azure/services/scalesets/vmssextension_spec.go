@@ -17,8 +17,8 @@ limitations under the License.
 package scalesets
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 )
@@ -47,22 +47,27 @@ func (s *VMSSExtensionSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the VMSS extension.
 func (s *VMSSExtensionSpec) Parameters(existing interface{}) (interface{}, error) {
 	if existing != nil {
-		_, ok := existing.(compute.VirtualMachineScaleSetExtension)
+		_, ok := existing.(armcompute.VirtualMachineScaleSetExtension)
 		if !ok {
-			return nil, errors.Errorf("%T is not a compute.VirtualMachineScaleSetExtension", existing)
+			return nil, errors.Errorf("%T is not an armcompute.VirtualMachineScaleSetExtension", existing)
 		}
 
 		// VMSS extension already exists, nothing to update.
 		return nil, nil
 	}
 
-	return compute.VirtualMachineScaleSetExtension{
-		Name: to.StringPtr(s.Name),
-		VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
-			Publisher:          to.StringPtr(s.Publisher),
-			Type:               to.StringPtr(s.Name),
-			TypeHandlerVersion: to.StringPtr(s.Version),
-			Settings:           nil,
+	var settings interface{}
+	if len(s.Settings) > 0 {
+		settings = s.Settings
+	}
+
+	return armcompute.VirtualMachineScaleSetExtension{
+		Name: to.Ptr(s.Name),
+		Properties: &armcompute.VirtualMachineScaleSetExtensionProperties{
+			Publisher:          to.Ptr(s.Publisher),
+			Type:               to.Ptr(s.Name),
+			TypeHandlerVersion: to.Ptr(s.Version),
+			Settings:           settings,
 			ProtectedSettings:  s.ProtectedSettings,
 		},
 	}, nil
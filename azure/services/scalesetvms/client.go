@@ -18,114 +18,149 @@ package scalesetvms
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/inflight"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
 // client wraps go-sdk.
 type client interface {
-	Get(context.Context, string, string, string) (compute.VirtualMachineScaleSetVM, error)
-	GetResultIfDone(ctx context.Context, future *infrav1.Future) (compute.VirtualMachineScaleSetVM, error)
+	Get(context.Context, string, string, string) (armcompute.VirtualMachineScaleSetVM, error)
+	GetResultIfDone(ctx context.Context, future *infrav1.Future) (armcompute.VirtualMachineScaleSetVM, error)
 	DeleteAsync(context.Context, string, string, string) (*infrav1.Future, error)
+	ListNetworkInterfaces(context.Context, string, string, string) ([]armnetwork.Interface, error)
+	ListPublicIPAddresses(context.Context, string, string, string, string, string) ([]armnetwork.PublicIPAddress, error)
 }
 
-type (
-	// azureClient contains the Azure go-sdk Client.
-	azureClient struct {
-		scalesetvms compute.VirtualMachineScaleSetVMsClient
-	}
-
-	genericScaleSetVMFuture interface {
-		DoneWithContext(ctx context.Context, sender autorest.Sender) (done bool, err error)
-		Result(client compute.VirtualMachineScaleSetVMsClient) (vmss compute.VirtualMachineScaleSetVM, err error)
-	}
-
-	deleteFutureAdapter struct {
-		compute.VirtualMachineScaleSetVMsDeleteFuture
-	}
-)
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	scalesetvms *armcompute.VirtualMachineScaleSetVMsClient
+	interfaces  *armnetwork.InterfacesClient
+	publicIPs   *armnetwork.PublicIPAddressesClient
+}
 
 var _ client = &azureClient{}
 
 // newClient creates a new VMSS client from subscription ID.
-func newClient(auth azure.Authorizer) *azureClient {
-	return &azureClient{
-		scalesetvms: newVirtualMachineScaleSetVMsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	scalesetvms, err := newVirtualMachineScaleSetVMsClient(auth)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
 	}
+	interfaces, err := armnetwork.NewInterfacesClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create network interfaces client")
+	}
+	publicIPs, err := armnetwork.NewPublicIPAddressesClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create public IP addresses client")
+	}
+	return &azureClient{
+		scalesetvms: scalesetvms,
+		interfaces:  interfaces,
+		publicIPs:   publicIPs,
+	}, nil
 }
 
 // newVirtualMachineScaleSetVMsClient creates a new vmss VM client from subscription ID.
-func newVirtualMachineScaleSetVMsClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachineScaleSetVMsClient {
-	c := compute.NewVirtualMachineScaleSetVMsClientWithBaseURI(baseURI, subscriptionID)
-	c.Authorizer = authorizer
-	c.RetryAttempts = 1
-	_ = c.AddToUserAgent(azure.UserAgent()) // intentionally ignore error as it doesn't matter
-	return c
+func newVirtualMachineScaleSetVMsClient(auth azure.Authorizer) (*armcompute.VirtualMachineScaleSetVMsClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armcompute.NewVirtualMachineScaleSetVMsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create vmss VMs client")
+	}
+	return c, nil
 }
 
-// Get retrieves the Virtual Machine Scale Set Virtual Machine.
-func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmssName, instanceID string) (compute.VirtualMachineScaleSetVM, error) {
+// Get retrieves the Virtual Machine Scale Set Virtual Machine, including its instance view.
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmssName, instanceID string) (armcompute.VirtualMachineScaleSetVM, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.Get")
 	defer done()
 
-	return ac.scalesetvms.Get(ctx, resourceGroupName, vmssName, instanceID, "")
+	opts := &armcompute.VirtualMachineScaleSetVMsClientGetOptions{Expand: to.Ptr(armcompute.InstanceViewTypesInstanceView)}
+	resp, err := ac.scalesetvms.Get(ctx, resourceGroupName, vmssName, instanceID, opts)
+	if err != nil {
+		return armcompute.VirtualMachineScaleSetVM{}, err
+	}
+	return resp.VirtualMachineScaleSetVM, nil
+}
+
+// ListNetworkInterfaces retrieves the network interfaces of the Virtual Machine Scale Set Virtual Machine.
+func (ac *azureClient) ListNetworkInterfaces(ctx context.Context, resourceGroupName, vmssName, instanceID string) ([]armnetwork.Interface, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.ListNetworkInterfaces")
+	defer done()
+
+	pager := ac.interfaces.NewListVirtualMachineScaleSetVMNetworkInterfacesPager(resourceGroupName, vmssName, instanceID, nil)
+	return azure.ListPager(ctx, "network interfaces of scale set instance "+instanceID, pager, func(page armnetwork.InterfacesClientListVirtualMachineScaleSetVMNetworkInterfacesResponse) []*armnetwork.Interface {
+		return page.Value
+	})
+}
+
+// ListPublicIPAddresses retrieves the public IPs of an IP configuration of a network interface of the Virtual Machine
+// Scale Set Virtual Machine.
+func (ac *azureClient) ListPublicIPAddresses(ctx context.Context, resourceGroupName, vmssName, instanceID, nicName, ipConfigName string) ([]armnetwork.PublicIPAddress, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.ListPublicIPAddresses")
+	defer done()
+
+	pager := ac.publicIPs.NewListVirtualMachineScaleSetVMPublicIPAddressesPager(resourceGroupName, vmssName, instanceID, nicName, ipConfigName, nil)
+	return azure.ListPager(ctx, "public IP addresses of scale set instance "+instanceID, pager, func(page armnetwork.PublicIPAddressesClientListVirtualMachineScaleSetVMPublicIPAddressesResponse) []*armnetwork.PublicIPAddress {
+		return page.Value
+	})
 }
 
 // GetResultIfDone fetches the result of a long-running operation future if it is done.
-func (ac *azureClient) GetResultIfDone(ctx context.Context, future *infrav1.Future) (compute.VirtualMachineScaleSetVM, error) {
+func (ac *azureClient) GetResultIfDone(ctx context.Context, future *infrav1.Future) (armcompute.VirtualMachineScaleSetVM, error) {
 	ctx, _, spanDone := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.GetResultIfDone")
 	defer spanDone()
 
-	var genericFuture genericScaleSetVMFuture
-	futureData, err := base64.URLEncoding.DecodeString(future.Data)
-	if err != nil {
-		return compute.VirtualMachineScaleSetVM{}, errors.Wrapf(err, "failed to base64 decode future data")
+	if future.Type != infrav1.DeleteFuture {
+		return armcompute.VirtualMachineScaleSetVM{}, errors.Errorf("unknown future type %q", future.Type)
 	}
-
-	switch future.Type {
-	case infrav1.DeleteFuture:
-		var future compute.VirtualMachineScaleSetVMsDeleteFuture
-		if err := json.Unmarshal(futureData, &future); err != nil {
-			return compute.VirtualMachineScaleSetVM{}, errors.Wrap(err, "failed to unmarshal future data")
-		}
-
-		genericFuture = &deleteFutureAdapter{
-			VirtualMachineScaleSetVMsDeleteFuture: future,
-		}
-	default:
-		return compute.VirtualMachineScaleSetVM{}, errors.Errorf("unknown future type %q", future.Type)
+	resumeToken, err := converters.FutureToResumeToken(*future)
+	if err != nil {
+		return armcompute.VirtualMachineScaleSetVM{}, err
 	}
 
-	done, err := genericFuture.DoneWithContext(ctx, ac.scalesetvms)
+	// The instance to delete is part of the resume token, so it doesn't need to be passed again.
+	opts := &armcompute.VirtualMachineScaleSetVMsClientBeginDeleteOptions{ResumeToken: resumeToken}
+	poller, err := ac.scalesetvms.BeginDelete(ctx, future.ResourceGroup, "", future.Name, opts)
 	if err != nil {
-		return compute.VirtualMachineScaleSetVM{}, errors.Wrapf(err, "failed checking if the operation was complete")
+		return armcompute.VirtualMachineScaleSetVM{}, errors.Wrap(err, "failed to resume the operation")
+	}
+	if _, err := poller.Poll(ctx); err != nil {
+		return armcompute.VirtualMachineScaleSetVM{}, errors.Wrapf(err, "failed checking if the operation was complete")
 	}
 
-	if !done {
-		return compute.VirtualMachineScaleSetVM{}, azure.WithTransientError(azure.NewOperationNotDoneError(future), 15*time.Second)
+	if !poller.Done() {
+		inflight.WaitingOn(ctx, *future)
+		return armcompute.VirtualMachineScaleSetVM{}, azure.WithTransientError(azure.NewOperationNotDoneError(future), 15*time.Second)
 	}
 
-	vm, err := genericFuture.Result(ac.scalesetvms)
-	if err != nil {
-		return vm, errors.Wrapf(err, "failed fetching the result of operation for vmss")
+	if _, err := poller.Result(ctx); err != nil {
+		return armcompute.VirtualMachineScaleSetVM{}, errors.Wrapf(err, "failed fetching the result of operation for vmss")
 	}
 
-	return vm, nil
+	return armcompute.VirtualMachineScaleSetVM{}, nil
 }
 
 // DeleteAsync is the operation to delete a virtual machine scale set instance asynchronously. DeleteAsync sends a DELETE
 // request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
-// progress of the operation.
+// progress of the operation. A nil Future is returned if the instance was deleted right away.
 //
 // Parameters:
 //   resourceGroupName - the name of the resource group.
@@ -135,17 +170,19 @@ func (ac *azureClient) DeleteAsync(ctx context.Context, resourceGroupName, vmssN
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.DeleteAsync")
 	defer done()
 
-	future, err := ac.scalesetvms.Delete(ctx, resourceGroupName, vmssName, instanceID, to.BoolPtr(false))
+	opts := &armcompute.VirtualMachineScaleSetVMsClientBeginDeleteOptions{ForceDeletion: to.Ptr(false)}
+	poller, err := ac.scalesetvms.BeginDelete(ctx, resourceGroupName, vmssName, instanceID, opts)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed deleting vmss named %q", vmssName)
 	}
+	if poller.Done() {
+		_, err := poller.Result(ctx)
+		return nil, err
+	}
 
-	return converters.SDKToFuture(&future, infrav1.DeleteFuture, serviceName, instanceID, resourceGroupName)
-}
-
-// Result wraps the delete result so that we can treat it generically. The only thing we care about is if the delete
-// was successful. If it wasn't, an error will be returned.
-func (da *deleteFutureAdapter) Result(client compute.VirtualMachineScaleSetVMsClient) (compute.VirtualMachineScaleSetVM, error) {
-	_, err := da.VirtualMachineScaleSetVMsDeleteFuture.Result(client)
-	return compute.VirtualMachineScaleSetVM{}, err
+	resumeToken, err := poller.ResumeToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get resume token of the operation")
+	}
+	return converters.ResumeTokenToFuture(resumeToken, infrav1.DeleteFuture, serviceName, instanceID, resourceGroupName), nil
 }
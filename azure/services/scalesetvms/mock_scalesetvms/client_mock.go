@@ -24,8 +24,8 @@ import (
 	context "context"
 	reflect "reflect"
 
-	compute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	autorest "github.com/Azure/go-autorest/autorest"
+	armcompute "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	armnetwork "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	gomock "github.com/golang/mock/gomock"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
@@ -69,10 +69,10 @@ func (mr *MockclientMockRecorder) DeleteAsync(arg0, arg1, arg2, arg3 interface{}
 }
 
 // Get mocks base method.
-func (m *Mockclient) Get(arg0 context.Context, arg1, arg2, arg3 string) (compute.VirtualMachineScaleSetVM, error) {
+func (m *Mockclient) Get(arg0 context.Context, arg1, arg2, arg3 string) (armcompute.VirtualMachineScaleSetVM, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(compute.VirtualMachineScaleSetVM)
+	ret0, _ := ret[0].(armcompute.VirtualMachineScaleSetVM)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -84,10 +84,10 @@ func (mr *MockclientMockRecorder) Get(arg0, arg1, arg2, arg3 interface{}) *gomoc
 }
 
 // GetResultIfDone mocks base method.
-func (m *Mockclient) GetResultIfDone(ctx context.Context, future *v1beta1.Future) (compute.VirtualMachineScaleSetVM, error) {
+func (m *Mockclient) GetResultIfDone(ctx context.Context, future *v1beta1.Future) (armcompute.VirtualMachineScaleSetVM, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetResultIfDone", ctx, future)
-	ret0, _ := ret[0].(compute.VirtualMachineScaleSetVM)
+	ret0, _ := ret[0].(armcompute.VirtualMachineScaleSetVM)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -98,55 +98,32 @@ func (mr *MockclientMockRecorder) GetResultIfDone(ctx, future interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResultIfDone", reflect.TypeOf((*Mockclient)(nil).GetResultIfDone), ctx, future)
 }
 
-// MockgenericScaleSetVMFuture is a mock of genericScaleSetVMFuture interface.
-type MockgenericScaleSetVMFuture struct {
-	ctrl     *gomock.Controller
-	recorder *MockgenericScaleSetVMFutureMockRecorder
-}
-
-// MockgenericScaleSetVMFutureMockRecorder is the mock recorder for MockgenericScaleSetVMFuture.
-type MockgenericScaleSetVMFutureMockRecorder struct {
-	mock *MockgenericScaleSetVMFuture
-}
-
-// NewMockgenericScaleSetVMFuture creates a new mock instance.
-func NewMockgenericScaleSetVMFuture(ctrl *gomock.Controller) *MockgenericScaleSetVMFuture {
-	mock := &MockgenericScaleSetVMFuture{ctrl: ctrl}
-	mock.recorder = &MockgenericScaleSetVMFutureMockRecorder{mock}
-	return mock
-}
-
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockgenericScaleSetVMFuture) EXPECT() *MockgenericScaleSetVMFutureMockRecorder {
-	return m.recorder
-}
-
-// DoneWithContext mocks base method.
-func (m *MockgenericScaleSetVMFuture) DoneWithContext(ctx context.Context, sender autorest.Sender) (bool, error) {
+// ListNetworkInterfaces mocks base method.
+func (m *Mockclient) ListNetworkInterfaces(arg0 context.Context, arg1, arg2, arg3 string) ([]armnetwork.Interface, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DoneWithContext", ctx, sender)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "ListNetworkInterfaces", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]armnetwork.Interface)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DoneWithContext indicates an expected call of DoneWithContext.
-func (mr *MockgenericScaleSetVMFutureMockRecorder) DoneWithContext(ctx, sender interface{}) *gomock.Call {
+// ListNetworkInterfaces indicates an expected call of ListNetworkInterfaces.
+func (mr *MockclientMockRecorder) ListNetworkInterfaces(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoneWithContext", reflect.TypeOf((*MockgenericScaleSetVMFuture)(nil).DoneWithContext), ctx, sender)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNetworkInterfaces", reflect.TypeOf((*Mockclient)(nil).ListNetworkInterfaces), arg0, arg1, arg2, arg3)
 }
 
-// Result mocks base method.
-func (m *MockgenericScaleSetVMFuture) Result(client compute.VirtualMachineScaleSetVMsClient) (compute.VirtualMachineScaleSetVM, error) {
+// ListPublicIPAddresses mocks base method.
+func (m *Mockclient) ListPublicIPAddresses(arg0 context.Context, arg1, arg2, arg3, arg4, arg5 string) ([]armnetwork.PublicIPAddress, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Result", client)
-	ret0, _ := ret[0].(compute.VirtualMachineScaleSetVM)
+	ret := m.ctrl.Call(m, "ListPublicIPAddresses", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].([]armnetwork.PublicIPAddress)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Result indicates an expected call of Result.
-func (mr *MockgenericScaleSetVMFutureMockRecorder) Result(client interface{}) *gomock.Call {
+// ListPublicIPAddresses indicates an expected call of ListPublicIPAddresses.
+func (mr *MockclientMockRecorder) ListPublicIPAddresses(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Result", reflect.TypeOf((*MockgenericScaleSetVMFuture)(nil).Result), client)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublicIPAddresses", reflect.TypeOf((*Mockclient)(nil).ListPublicIPAddresses), arg0, arg1, arg2, arg3, arg4, arg5)
 }
@@ -21,6 +21,8 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
@@ -47,11 +49,15 @@ type (
 )
 
 // NewService creates a new service.
-func NewService(scope ScaleSetVMScope) *Service {
+func NewService(scope ScaleSetVMScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
-		Client: newClient(scope),
+		Client: client,
 		Scope:  scope,
-	}
+	}, nil
 }
 
 // Name returns the service name.
@@ -79,12 +85,64 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed getting instance")
 	}
 
-	s.Scope.SetVMSSVM(converters.SDKToVMSSVM(instance))
+	vmssVM, err := converters.SDKToVMSSVM(instance)
+	if err != nil {
+		return err
+	}
+	addresses, err := s.getAddresses(ctx, resourceGroup, vmssName, instanceID, vmssVM.Name)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch instance addresses")
+	}
+	vmssVM.Addresses = addresses
+
+	s.Scope.SetVMSSVM(vmssVM)
 	return nil
 }
 
+// getAddresses returns the computer name of the instance, and the addresses of all the IP configurations of all its
+// network interfaces.
+func (s *Service) getAddresses(ctx context.Context, resourceGroup, vmssName, instanceID, computerName string) ([]corev1.NodeAddress, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.Service.getAddresses")
+	defer done()
+
+	var addresses []corev1.NodeAddress
+	if computerName != "" {
+		addresses = append(addresses, corev1.NodeAddress{
+			Type:    corev1.NodeInternalDNS,
+			Address: computerName,
+		})
+	}
+
+	nics, err := s.Client.ListNetworkInterfaces(ctx, resourceGroup, vmssName, instanceID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list network interfaces")
+	}
+	for _, nic := range nics {
+		addresses = append(addresses, converters.SDKNetworkInterfaceToNodeAddresses(nic)...)
+
+		if nic.Properties == nil {
+			continue
+		}
+		for _, ipConfig := range nic.Properties.IPConfigurations {
+			if ipConfig == nil || ipConfig.Properties == nil || ipConfig.Properties.PublicIPAddress == nil {
+				continue
+			}
+			// The public IPs of the instances are only listed by their IP configuration.
+			publicIPs, err := s.Client.ListPublicIPAddresses(ctx, resourceGroup, vmssName, instanceID, pointer.StringDeref(nic.Name, ""), pointer.StringDeref(ipConfig.Name, ""))
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to list public IP addresses")
+			}
+			for _, publicIP := range publicIPs {
+				addresses = append(addresses, converters.SDKPublicIPToNodeAddresses(publicIP)...)
+			}
+		}
+	}
+
+	return converters.UniqueNodeAddresses(addresses), nil
+}
+
 // Delete deletes a scaleset instance asynchronously returning a future which encapsulates the long-running operation.
-func (s *Service) Delete(ctx context.Context) error {
+func (s *Service) Delete(ctx context.Context) (retErr error) {
 	var (
 		resourceGroup = s.Scope.ResourceGroup()
 		vmssName      = s.Scope.ScaleSetName()
@@ -101,9 +159,16 @@ func (s *Service) Delete(ctx context.Context) error {
 	defer done()
 
 	defer func() {
-		if instance, err := s.Client.Get(ctx, resourceGroup, vmssName, instanceID); err == nil && instance.VirtualMachineScaleSetVMProperties != nil {
-			log.V(4).Info("updating vmss vm state", "state", instance.ProvisioningState)
-			s.Scope.SetVMSSVM(converters.SDKToVMSSVM(instance))
+		if instance, err := s.Client.Get(ctx, resourceGroup, vmssName, instanceID); err == nil && instance.Properties != nil {
+			log.V(4).Info("updating vmss vm state", "state", pointer.StringDeref(instance.Properties.ProvisioningState, ""))
+			vmssVM, err := converters.SDKToVMSSVM(instance)
+			if err != nil {
+				if retErr == nil {
+					retErr = errors.Wrap(err, "failed to update vmss vm state")
+				}
+				return
+			}
+			s.Scope.SetVMSSVM(vmssVM)
 		}
 	}()
 
@@ -114,6 +179,12 @@ func (s *Service) Delete(ctx context.Context) error {
 			return azure.WithTransientError(errors.New("attempting to delete, non-delete operation in progress"), 30*time.Second)
 		}
 
+		if _, err := converters.FutureToResumeToken(*future); err != nil {
+			// Reset the future data to avoid getting stuck in a bad loop, e.g. with a future stored by the track 1 SDK.
+			s.Scope.DeleteLongRunningOperationState(instanceID, serviceName)
+			return errors.Wrap(err, "could not decode future data, resetting long-running operation state")
+		}
+
 		log.V(4).Info("checking if the instance is done deleting")
 		if _, err := s.Client.GetResultIfDone(ctx, future); err != nil {
 			// fetch instance to update status
@@ -135,6 +206,10 @@ func (s *Service) Delete(ctx context.Context) error {
 		}
 		return errors.Wrapf(err, "failed to delete instance %s/%s", vmssName, instanceID)
 	}
+	if future == nil {
+		// the instance was deleted right away
+		return nil
+	}
 
 	s.Scope.SetLongRunningOperationState(future)
 
@@ -22,12 +22,14 @@ import (
 	"testing"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -42,8 +44,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
+const fakeDeleteResumeToken = `{"type":"VirtualMachineScaleSetVMsClientDeleteResponse","token":{"state":"InProgress"}}`
+
 var (
-	autorest404 = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found")
+	autorest404 = &azcore.ResponseError{StatusCode: http.StatusNotFound}
 )
 
 func TestNewService(t *testing.T) {
@@ -58,9 +62,6 @@ func TestNewService(t *testing.T) {
 	}
 	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build()
 	s, err := scope.NewClusterScope(context.Background(), scope.ClusterScopeParams{
-		AzureClients: scope.AzureClients{
-			Authorizer: autorest.NullAuthorizer{},
-		},
 		Client:  client,
 		Cluster: cluster,
 		AzureCluster: &infrav1.AzureCluster{
@@ -86,7 +87,8 @@ func TestNewService(t *testing.T) {
 		ClusterScope:            s,
 	})
 	g.Expect(err).NotTo(HaveOccurred())
-	actual := NewService(mpms)
+	actual, err := NewService(mpms)
+	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(actual).NotTo(BeNil())
 }
 
@@ -103,12 +105,98 @@ func TestService_Reconcile(t *testing.T) {
 				s.ResourceGroup().Return("rg")
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
-				vm := compute.VirtualMachineScaleSetVM{
-					InstanceID: to.StringPtr("0"),
+				vm := armcompute.VirtualMachineScaleSetVM{
+					InstanceID: to.Ptr("0"),
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				m.ListNetworkInterfaces(gomock2.AContext(), "rg", "scaleset", "0").Return(nil, nil)
+				vmssVM, _ := converters.SDKToVMSSVM(vm)
+				vmssVM.Addresses = []corev1.NodeAddress{}
+				s.SetVMSSVM(vmssVM)
+			},
+		},
+		{
+			Name: "should set the addresses of all the network interfaces of the instance",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				vm := armcompute.VirtualMachineScaleSetVM{
+					InstanceID: to.Ptr("0"),
+					Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+						OSProfile: &armcompute.OSProfile{
+							ComputerName: to.Ptr("scaleset000000"),
+						},
+					},
 				}
 				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
-				s.SetVMSSVM(converters.SDKToVMSSVM(vm))
+				m.ListNetworkInterfaces(gomock2.AContext(), "rg", "scaleset", "0").Return([]armnetwork.Interface{
+					{
+						Name: to.Ptr("nic-0"),
+						Properties: &armnetwork.InterfacePropertiesFormat{
+							IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+								{
+									Name: to.Ptr("ipconfig1"),
+									Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+										PrivateIPAddress: to.Ptr("10.0.0.4"),
+										PublicIPAddress:  &armnetwork.PublicIPAddress{ID: to.Ptr("pip-id")},
+									},
+								},
+								{
+									Name: to.Ptr("ipconfig2"),
+									Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+										PrivateIPAddress: to.Ptr("10.0.0.5"),
+									},
+								},
+							},
+						},
+					},
+					{
+						Name: to.Ptr("nic-1"),
+						Properties: &armnetwork.InterfacePropertiesFormat{
+							IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+								{
+									Name: to.Ptr("ipconfig1"),
+									Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+										PrivateIPAddress: to.Ptr("10.1.0.4"),
+									},
+								},
+							},
+						},
+					},
+				}, nil)
+				m.ListPublicIPAddresses(gomock2.AContext(), "rg", "scaleset", "0", "nic-0", "ipconfig1").Return([]armnetwork.PublicIPAddress{
+					{
+						Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+							IPAddress: to.Ptr("20.1.2.3"),
+							DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
+								Fqdn: to.Ptr("scaleset000000.westus2.cloudapp.azure.com"),
+							},
+						},
+					},
+				}, nil)
+				vmssVM, _ := converters.SDKToVMSSVM(vm)
+				vmssVM.Addresses = []corev1.NodeAddress{
+					{Type: corev1.NodeInternalDNS, Address: "scaleset000000"},
+					{Type: corev1.NodeInternalIP, Address: "10.0.0.4"},
+					{Type: corev1.NodeInternalIP, Address: "10.0.0.5"},
+					{Type: corev1.NodeExternalIP, Address: "20.1.2.3"},
+					{Type: corev1.NodeExternalDNS, Address: "scaleset000000.westus2.cloudapp.azure.com"},
+					{Type: corev1.NodeInternalIP, Address: "10.1.0.4"},
+				}
+				s.SetVMSSVM(vmssVM)
+			},
+		},
+		{
+			Name: "if listing the network interfaces fails, then should respond with error",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(armcompute.VirtualMachineScaleSetVM{InstanceID: to.Ptr("0")}, nil)
+				m.ListNetworkInterfaces(gomock2.AContext(), "rg", "scaleset", "0").Return(nil, errors.New("boom"))
 			},
+			Err: errors.Wrap(errors.Wrap(errors.New("boom"), "failed to list network interfaces"), "failed to fetch instance addresses"),
 		},
 		{
 			Name: "if 404, then should respond with transient error",
@@ -116,7 +204,7 @@ func TestService_Reconcile(t *testing.T) {
 				s.ResourceGroup().Return("rg")
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
-				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, autorest404)
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(armcompute.VirtualMachineScaleSetVM{}, autorest404)
 			},
 			Err:        azure.WithTransientError(errors.New("instance does not exist yet"), 30*time.Second),
 			CheckIsErr: true,
@@ -127,7 +215,7 @@ func TestService_Reconcile(t *testing.T) {
 				s.ResourceGroup().Return("rg")
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
-				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, errors.New("boom"))
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(armcompute.VirtualMachineScaleSetVM{}, errors.New("boom"))
 			},
 			Err: errors.Wrap(errors.New("boom"), "failed getting instance"),
 		},
@@ -143,12 +231,10 @@ func TestService_Reconcile(t *testing.T) {
 			)
 			defer mockCtrl.Finish()
 
-			scopeMock.EXPECT().SubscriptionID().Return("subID")
-			scopeMock.EXPECT().BaseURI().Return("https://localhost/")
-			scopeMock.EXPECT().Authorizer().Return(nil)
-
-			service := NewService(scopeMock)
-			service.Client = clientMock
+			service := &Service{
+				Client: clientMock,
+				Scope:  scopeMock,
+			}
 			c.Setup(scopeMock.EXPECT(), clientMock.EXPECT())
 
 			if err := service.Reconcile(context.TODO()); c.Err == nil {
@@ -183,8 +269,8 @@ func TestService_Delete(t *testing.T) {
 				}
 				m.DeleteAsync(gomock2.AContext(), "rg", "scaleset", "0").Return(future, nil)
 				s.SetLongRunningOperationState(future)
-				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, azure.WithTransientError(azure.NewOperationNotDoneError(future), 15*time.Second))
-				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(armcompute.VirtualMachineScaleSetVM{}, azure.WithTransientError(azure.NewOperationNotDoneError(future), 15*time.Second))
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(armcompute.VirtualMachineScaleSetVM{}, nil)
 			},
 			CheckIsErr: true,
 			Err: errors.Wrap(azure.WithTransientError(azure.NewOperationNotDoneError(&infrav1.Future{
@@ -197,15 +283,28 @@ func TestService_Delete(t *testing.T) {
 				s.ResourceGroup().Return("rg")
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
-				future := &infrav1.Future{
-					Type: infrav1.DeleteFuture,
-				}
+				future := converters.ResumeTokenToFuture(fakeDeleteResumeToken, infrav1.DeleteFuture, serviceName, "0", "rg")
 				s.GetLongRunningOperationState("0", serviceName).Return(future)
-				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, nil)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(armcompute.VirtualMachineScaleSetVM{}, nil)
 				s.DeleteLongRunningOperationState("0", serviceName)
-				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(armcompute.VirtualMachineScaleSetVM{}, nil)
 			},
 		},
+		{
+			Name: "should reset the long running operation state when the future can't be resumed",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				s.GetLongRunningOperationState("0", serviceName).Return(&infrav1.Future{
+					Type: infrav1.DeleteFuture,
+					Data: "eyJtZXRob2QiOiJERUxFVEUifQ==",
+				})
+				s.DeleteLongRunningOperationState("0", serviceName)
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(armcompute.VirtualMachineScaleSetVM{}, nil)
+			},
+			Err: errors.New("could not decode future data, resetting long-running operation state: future data is not a resume token"),
+		},
 		{
 			Name: "should not error when deleting, but resource is 404",
 			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
@@ -214,7 +313,7 @@ func TestService_Delete(t *testing.T) {
 				s.ScaleSetName().Return("scaleset")
 				s.GetLongRunningOperationState("0", serviceName).Return(nil)
 				m.DeleteAsync(gomock2.AContext(), "rg", "scaleset", "0").Return(nil, autorest404)
-				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(armcompute.VirtualMachineScaleSetVM{}, nil)
 			},
 		},
 		{
@@ -225,7 +324,7 @@ func TestService_Delete(t *testing.T) {
 				s.ScaleSetName().Return("scaleset")
 				s.GetLongRunningOperationState("0", serviceName).Return(nil)
 				m.DeleteAsync(gomock2.AContext(), "rg", "scaleset", "0").Return(nil, errors.New("boom"))
-				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(armcompute.VirtualMachineScaleSetVM{}, nil)
 			},
 			Err: errors.Wrap(errors.New("boom"), "failed to delete instance scaleset/0"),
 		},
@@ -235,12 +334,10 @@ func TestService_Delete(t *testing.T) {
 				s.ResourceGroup().Return("rg")
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
-				future := &infrav1.Future{
-					Type: infrav1.DeleteFuture,
-				}
+				future := converters.ResumeTokenToFuture(fakeDeleteResumeToken, infrav1.DeleteFuture, serviceName, "0", "rg")
 				s.GetLongRunningOperationState("0", serviceName).Return(future)
-				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, errors.New("boom"))
-				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(armcompute.VirtualMachineScaleSetVM{}, errors.New("boom"))
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(armcompute.VirtualMachineScaleSetVM{}, nil)
 			},
 			Err: errors.Wrap(errors.New("boom"), "failed to get result of long running operation"),
 		},
@@ -256,12 +353,10 @@ func TestService_Delete(t *testing.T) {
 			)
 			defer mockCtrl.Finish()
 
-			scopeMock.EXPECT().SubscriptionID().Return("subID")
-			scopeMock.EXPECT().BaseURI().Return("https://localhost/")
-			scopeMock.EXPECT().Authorizer().Return(nil)
-
-			service := NewService(scopeMock)
-			service.Client = clientMock
+			service := &Service{
+				Client: clientMock,
+				Scope:  scopeMock,
+			}
 			c.Setup(scopeMock.EXPECT(), clientMock.EXPECT())
 
 			if err := service.Delete(context.TODO()); c.Err == nil {
@@ -22,6 +22,7 @@ import (
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -44,12 +45,15 @@ type Service struct {
 }
 
 // New creates a new service.
-func New(scope NSGScope) *Service {
-	client := newClient(scope)
+func New(scope NSGScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:      scope,
-		Reconciler: async.New(scope, client, client),
-	}
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
 }
 
 // Name returns the service name.
@@ -57,6 +61,15 @@ func (s *Service) Name() string {
 	return serviceName
 }
 
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the network security groups.
+func (s *Service) RequiredActions() []string {
+	if !s.Scope.IsVnetManaged() || len(s.Scope.NSGSpecs()) == 0 {
+		return nil
+	}
+	return []string{"Microsoft.Network/networkSecurityGroups/read", "Microsoft.Network/networkSecurityGroups/write"}
+}
+
 // Reconcile gets/creates/updates network security groups.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "securitygroups.Service.Reconcile")
@@ -133,6 +146,24 @@ func (s *Service) Delete(ctx context.Context) error {
 	return result
 }
 
+// ExportResources adds the network security groups, if the virtual network is managed, to an ARM template.
+func (s *Service) ExportResources(ctx context.Context, template *armtemplate.Template) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "securitygroups.Service.ExportResources")
+	defer done()
+
+	if !s.Scope.IsVnetManaged() {
+		return nil
+	}
+
+	for _, spec := range s.Scope.NSGSpecs() {
+		if err := template.AddSpec(spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // IsManaged returns true if the security groups' lifecycles are managed.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	_, _, done := tele.StartSpanWithLogger(ctx, "securitygroups.Service.IsManaged")
@@ -20,8 +20,8 @@ import (
 	"context"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
@@ -44,10 +44,10 @@ var (
 				Priority:         2200,
 				Protocol:         infrav1.SecurityGroupProtocolTCP,
 				Direction:        infrav1.SecurityRuleDirectionInbound,
-				Source:           to.StringPtr("*"),
-				SourcePorts:      to.StringPtr("*"),
-				Destination:      to.StringPtr("*"),
-				DestinationPorts: to.StringPtr("22"),
+				Source:           to.Ptr("*"),
+				SourcePorts:      to.Ptr("*"),
+				Destination:      to.Ptr("*"),
+				DestinationPorts: to.Ptr("22"),
 			},
 			{
 				Name:             "other_rule",
@@ -55,10 +55,10 @@ var (
 				Priority:         500,
 				Protocol:         infrav1.SecurityGroupProtocolTCP,
 				Direction:        infrav1.SecurityRuleDirectionInbound,
-				Source:           to.StringPtr("*"),
-				SourcePorts:      to.StringPtr("*"),
-				Destination:      to.StringPtr("*"),
-				DestinationPorts: to.StringPtr("80"),
+				Source:           to.Ptr("*"),
+				SourcePorts:      to.Ptr("*"),
+				Destination:      to.Ptr("*"),
+				DestinationPorts: to.Ptr("80"),
 			},
 		},
 		ResourceGroup: "test-group",
@@ -247,43 +247,43 @@ func TestDeleteSecurityGroups(t *testing.T) {
 }
 
 var (
-	ruleA = network.SecurityRule{
-		Name: to.StringPtr("A"),
-		SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
-			Description:              to.StringPtr("this is rule A"),
-			Protocol:                 network.SecurityRuleProtocolTCP,
-			DestinationPortRange:     to.StringPtr("*"),
-			SourcePortRange:          to.StringPtr("*"),
-			DestinationAddressPrefix: to.StringPtr("*"),
-			SourceAddressPrefix:      to.StringPtr("*"),
-			Priority:                 to.Int32Ptr(100),
-			Direction:                network.SecurityRuleDirectionInbound,
+	ruleA = &armnetwork.SecurityRule{
+		Name: to.Ptr("A"),
+		Properties: &armnetwork.SecurityRulePropertiesFormat{
+			Description:              to.Ptr("this is rule A"),
+			Protocol:                 to.Ptr(armnetwork.SecurityRuleProtocolTCP),
+			DestinationPortRange:     to.Ptr("*"),
+			SourcePortRange:          to.Ptr("*"),
+			DestinationAddressPrefix: to.Ptr("*"),
+			SourceAddressPrefix:      to.Ptr("*"),
+			Priority:                 to.Ptr[int32](100),
+			Direction:                to.Ptr(armnetwork.SecurityRuleDirectionInbound),
 		},
 	}
-	ruleB = network.SecurityRule{
-		Name: to.StringPtr("B"),
-		SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
-			Description:              to.StringPtr("this is rule B"),
-			Protocol:                 network.SecurityRuleProtocolTCP,
-			DestinationPortRange:     to.StringPtr("*"),
-			SourcePortRange:          to.StringPtr("*"),
-			DestinationAddressPrefix: to.StringPtr("*"),
-			SourceAddressPrefix:      to.StringPtr("*"),
-			Priority:                 to.Int32Ptr(100),
-			Direction:                network.SecurityRuleDirectionOutbound,
+	ruleB = &armnetwork.SecurityRule{
+		Name: to.Ptr("B"),
+		Properties: &armnetwork.SecurityRulePropertiesFormat{
+			Description:              to.Ptr("this is rule B"),
+			Protocol:                 to.Ptr(armnetwork.SecurityRuleProtocolTCP),
+			DestinationPortRange:     to.Ptr("*"),
+			SourcePortRange:          to.Ptr("*"),
+			DestinationAddressPrefix: to.Ptr("*"),
+			SourceAddressPrefix:      to.Ptr("*"),
+			Priority:                 to.Ptr[int32](100),
+			Direction:                to.Ptr(armnetwork.SecurityRuleDirectionOutbound),
 		},
 	}
-	ruleBModified = network.SecurityRule{
-		Name: to.StringPtr("B"),
-		SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
-			Description:              to.StringPtr("this is rule B"),
-			Protocol:                 network.SecurityRuleProtocolTCP,
-			DestinationPortRange:     to.StringPtr("80"),
-			SourcePortRange:          to.StringPtr("*"),
-			DestinationAddressPrefix: to.StringPtr("*"),
-			SourceAddressPrefix:      to.StringPtr("*"),
-			Priority:                 to.Int32Ptr(100),
-			Direction:                network.SecurityRuleDirectionOutbound,
+	ruleBModified = &armnetwork.SecurityRule{
+		Name: to.Ptr("B"),
+		Properties: &armnetwork.SecurityRulePropertiesFormat{
+			Description:              to.Ptr("this is rule B"),
+			Protocol:                 to.Ptr(armnetwork.SecurityRuleProtocolTCP),
+			DestinationPortRange:     to.Ptr("80"),
+			SourcePortRange:          to.Ptr("*"),
+			DestinationAddressPrefix: to.Ptr("*"),
+			SourceAddressPrefix:      to.Ptr("*"),
+			Priority:                 to.Ptr[int32](100),
+			Direction:                to.Ptr(armnetwork.SecurityRuleDirectionOutbound),
 		},
 	}
 )
@@ -19,9 +19,10 @@ package securitygroups
 import (
 	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 )
@@ -53,20 +54,22 @@ func (s *NSGSpec) OwnerResourceName() string {
 
 // Parameters returns the parameters for the security group.
 func (s *NSGSpec) Parameters(existing interface{}) (interface{}, error) {
-	securityRules := make([]network.SecurityRule, 0)
+	securityRules := make([]*armnetwork.SecurityRule, 0)
 	var etag *string
 
 	if existing != nil {
-		existingNSG, ok := existing.(network.SecurityGroup)
+		existingNSG, ok := existing.(armnetwork.SecurityGroup)
 		if !ok {
-			return nil, errors.Errorf("%T is not a network.SecurityGroup", existing)
+			return nil, errors.Errorf("%T is not an armnetwork.SecurityGroup", existing)
 		}
 		// security group already exists
 		// We append the existing NSG etag to the header to ensure we only apply the updates if the NSG has not been modified.
 		etag = existingNSG.Etag
 		// Check if the expected rules are present
 		update := false
-		securityRules = *existingNSG.SecurityRules
+		if existingNSG.Properties != nil {
+			securityRules = existingNSG.Properties.SecurityRules
+		}
 		for _, rule := range s.SecurityRules {
 			sdkRule := converters.SecurityRuleToSDK(rule)
 			if !ruleExists(securityRules, sdkRule) {
@@ -85,38 +88,42 @@ func (s *NSGSpec) Parameters(existing interface{}) (interface{}, error) {
 		}
 	}
 
-	return network.SecurityGroup{
-		Location: to.StringPtr(s.Location),
-		SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
-			SecurityRules: &securityRules,
+	return armnetwork.SecurityGroup{
+		Location: to.Ptr(s.Location),
+		Properties: &armnetwork.SecurityGroupPropertiesFormat{
+			SecurityRules: securityRules,
 		},
 		Etag: etag,
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: s.ClusterName,
 			Lifecycle:   infrav1.ResourceLifecycleOwned,
-			Name:        to.StringPtr(s.Name),
+			Name:        to.Ptr(s.Name),
 			Additional:  s.AdditionalTags,
 		})),
 	}, nil
 }
 
 // TODO: review this logic and make sure it is what we want. It seems incorrect to skip rules that don't have a certain protocol, etc.
-func ruleExists(rules []network.SecurityRule, rule network.SecurityRule) bool {
+func ruleExists(rules []*armnetwork.SecurityRule, rule *armnetwork.SecurityRule) bool {
 	for _, existingRule := range rules {
-		if !strings.EqualFold(to.String(existingRule.Name), to.String(rule.Name)) {
+		if existingRule == nil || existingRule.Properties == nil {
+			continue
+		}
+		if !strings.EqualFold(pointer.StringDeref(existingRule.Name, ""), pointer.StringDeref(rule.Name, "")) {
 			continue
 		}
-		if !strings.EqualFold(to.String(existingRule.DestinationPortRange), to.String(rule.DestinationPortRange)) {
+		if !strings.EqualFold(pointer.StringDeref(existingRule.Properties.DestinationPortRange, ""), pointer.StringDeref(rule.Properties.DestinationPortRange, "")) {
 			continue
 		}
-		if existingRule.Protocol != network.SecurityRuleProtocolTCP &&
-			existingRule.Access != network.SecurityRuleAccessAllow &&
-			existingRule.Direction != network.SecurityRuleDirectionInbound {
+		protocol, access, direction := existingRule.Properties.Protocol, existingRule.Properties.Access, existingRule.Properties.Direction
+		if (protocol == nil || *protocol != armnetwork.SecurityRuleProtocolTCP) &&
+			(access == nil || *access != armnetwork.SecurityRuleAccessAllow) &&
+			(direction == nil || *direction != armnetwork.SecurityRuleDirectionInbound) {
 			continue
 		}
-		if !strings.EqualFold(to.String(existingRule.SourcePortRange), "*") &&
-			!strings.EqualFold(to.String(existingRule.SourceAddressPrefix), "*") &&
-			!strings.EqualFold(to.String(existingRule.DestinationAddressPrefix), "*") {
+		if !strings.EqualFold(pointer.StringDeref(existingRule.Properties.SourcePortRange, ""), "*") &&
+			!strings.EqualFold(pointer.StringDeref(existingRule.Properties.SourceAddressPrefix, ""), "*") &&
+			!strings.EqualFold(pointer.StringDeref(existingRule.Properties.DestinationAddressPrefix, ""), "*") {
 			continue
 		}
 		return true
@@ -19,8 +19,8 @@ package securitygroups
 import (
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
@@ -33,10 +33,10 @@ var (
 		Priority:         2200,
 		Protocol:         infrav1.SecurityGroupProtocolTCP,
 		Direction:        infrav1.SecurityRuleDirectionInbound,
-		Source:           to.StringPtr("*"),
-		SourcePorts:      to.StringPtr("*"),
-		Destination:      to.StringPtr("*"),
-		DestinationPorts: to.StringPtr("22"),
+		Source:           to.Ptr("*"),
+		SourcePorts:      to.Ptr("*"),
+		Destination:      to.Ptr("*"),
+		DestinationPorts: to.Ptr("22"),
 	}
 	otherRule = infrav1.SecurityRule{
 		Name:             "other_rule",
@@ -44,10 +44,10 @@ var (
 		Priority:         500,
 		Protocol:         infrav1.SecurityGroupProtocolTCP,
 		Direction:        infrav1.SecurityRuleDirectionInbound,
-		Source:           to.StringPtr("*"),
-		SourcePorts:      to.StringPtr("*"),
-		Destination:      to.StringPtr("*"),
-		DestinationPorts: to.StringPtr("80"),
+		Source:           to.Ptr("*"),
+		SourcePorts:      to.Ptr("*"),
+		Destination:      to.Ptr("*"),
+		DestinationPorts: to.Ptr("80"),
 	}
 	customRule = infrav1.SecurityRule{
 		Name:             "custom_rule",
@@ -55,10 +55,10 @@ var (
 		Priority:         501,
 		Protocol:         infrav1.SecurityGroupProtocolTCP,
 		Direction:        infrav1.SecurityRuleDirectionOutbound,
-		Source:           to.StringPtr("*"),
-		SourcePorts:      to.StringPtr("*"),
-		Destination:      to.StringPtr("*"),
-		DestinationPorts: to.StringPtr("80"),
+		Source:           to.Ptr("*"),
+		SourcePorts:      to.Ptr("*"),
+		Destination:      to.Ptr("*"),
+		DestinationPorts: to.Ptr("80"),
 	}
 )
 
@@ -82,10 +82,10 @@ func TestParameters(t *testing.T) {
 				ResourceGroup: "test-group",
 				ClusterName:   "my-cluster",
 			},
-			existing: network.SecurityGroup{
-				Name: to.StringPtr("test-nsg"),
-				SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
-					SecurityRules: &[]network.SecurityRule{
+			existing: armnetwork.SecurityGroup{
+				Name: to.Ptr("test-nsg"),
+				Properties: &armnetwork.SecurityGroupPropertiesFormat{
+					SecurityRules: []*armnetwork.SecurityRule{
 						converters.SecurityRuleToSDK(sshRule),
 						converters.SecurityRuleToSDK(otherRule),
 					},
@@ -107,32 +107,32 @@ func TestParameters(t *testing.T) {
 				ResourceGroup: "test-group",
 				ClusterName:   "my-cluster",
 			},
-			existing: network.SecurityGroup{
-				Name:     to.StringPtr("test-nsg"),
-				Location: to.StringPtr("test-location"),
-				Etag:     to.StringPtr("fake-etag"),
-				SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
-					SecurityRules: &[]network.SecurityRule{
+			existing: armnetwork.SecurityGroup{
+				Name:     to.Ptr("test-nsg"),
+				Location: to.Ptr("test-location"),
+				Etag:     to.Ptr("fake-etag"),
+				Properties: &armnetwork.SecurityGroupPropertiesFormat{
+					SecurityRules: []*armnetwork.SecurityRule{
 						converters.SecurityRuleToSDK(sshRule),
 						converters.SecurityRuleToSDK(customRule),
 					},
 				},
 			},
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.SecurityGroup{}))
-				g.Expect(result).To(Equal(network.SecurityGroup{
-					Location: to.StringPtr("test-location"),
-					Etag:     to.StringPtr("fake-etag"),
-					SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
-						SecurityRules: &[]network.SecurityRule{
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.SecurityGroup{}))
+				g.Expect(result).To(Equal(armnetwork.SecurityGroup{
+					Location: to.Ptr("test-location"),
+					Etag:     to.Ptr("fake-etag"),
+					Properties: &armnetwork.SecurityGroupPropertiesFormat{
+						SecurityRules: []*armnetwork.SecurityRule{
 							converters.SecurityRuleToSDK(sshRule),
 							converters.SecurityRuleToSDK(customRule),
 							converters.SecurityRuleToSDK(otherRule),
 						},
 					},
 					Tags: map[string]*string{
-						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
-						"Name": to.StringPtr("test-nsg"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+						"Name": to.Ptr("test-nsg"),
 					},
 				}))
 			},
@@ -151,18 +151,18 @@ func TestParameters(t *testing.T) {
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(network.SecurityGroup{}))
-				g.Expect(result).To(Equal(network.SecurityGroup{
-					SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
-						SecurityRules: &[]network.SecurityRule{
+				g.Expect(result).To(BeAssignableToTypeOf(armnetwork.SecurityGroup{}))
+				g.Expect(result).To(Equal(armnetwork.SecurityGroup{
+					Properties: &armnetwork.SecurityGroupPropertiesFormat{
+						SecurityRules: []*armnetwork.SecurityRule{
 							converters.SecurityRuleToSDK(sshRule),
 							converters.SecurityRuleToSDK(otherRule),
 						},
 					},
-					Location: to.StringPtr("test-location"),
+					Location: to.Ptr("test-location"),
 					Tags: map[string]*string{
-						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.StringPtr("owned"),
-						"Name": to.StringPtr("test-nsg"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_my-cluster": to.Ptr("owned"),
+						"Name": to.Ptr("test-nsg"),
 					},
 				}))
 			},
@@ -190,25 +190,25 @@ func TestParameters(t *testing.T) {
 func TestRuleExists(t *testing.T) {
 	testcases := []struct {
 		name     string
-		rules    []network.SecurityRule
-		rule     network.SecurityRule
+		rules    []*armnetwork.SecurityRule
+		rule     *armnetwork.SecurityRule
 		expected bool
 	}{
 		{
 			name:     "rule doesn't exitst",
-			rules:    []network.SecurityRule{ruleA},
+			rules:    []*armnetwork.SecurityRule{ruleA},
 			rule:     ruleB,
 			expected: false,
 		},
 		{
 			name:     "rule exists",
-			rules:    []network.SecurityRule{ruleA, ruleB},
+			rules:    []*armnetwork.SecurityRule{ruleA, ruleB},
 			rule:     ruleB,
 			expected: true,
 		},
 		{
 			name:     "rule exists but has been modified",
-			rules:    []network.SecurityRule{ruleA, ruleB},
+			rules:    []*armnetwork.SecurityRule{ruleA, ruleB},
 			rule:     ruleBModified,
 			expected: false,
 		},
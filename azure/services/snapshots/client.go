@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshots
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	List(context.Context, string) (result []armcompute.Snapshot, err error)
+	Get(context.Context, azure.ResourceSpecGetter) (result interface{}, err error)
+	CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error)
+	DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	snapshots *armcompute.SnapshotsClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new snapshots client from subscription ID.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armcompute.NewSnapshotsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create snapshots client")
+	}
+	return &azureClient{
+		snapshots: c,
+	}, nil
+}
+
+// Get gets the specified snapshot.
+func (ac *azureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (result interface{}, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "snapshots.azureClient.Get")
+	defer done()
+
+	resp, err := ac.snapshots.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Snapshot, nil
+}
+
+// List returns all snapshots in a resource group.
+func (ac *azureClient) List(ctx context.Context, resourceGroupName string) (result []armcompute.Snapshot, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "snapshots.azureClient.List")
+	defer done()
+
+	pager := ac.snapshots.NewListByResourceGroupPager(resourceGroupName, nil)
+	return azure.ListPager(ctx, "snapshots in resource group "+resourceGroupName, pager, func(page armcompute.SnapshotsClientListByResourceGroupResponse) []*armcompute.Snapshot {
+		return page.Value
+	})
+}
+
+// CreateOrUpdateAsync creates or updates a snapshot asynchronously.
+// It sends a PUT request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "snapshots.azureClient.CreateOrUpdateAsync")
+	defer done()
+
+	snapshot, ok := parameters.(armcompute.Snapshot)
+	if !ok && parameters != nil {
+		return nil, nil, errors.Errorf("%T is not an armcompute.Snapshot", parameters)
+	}
+
+	opts := &armcompute.SnapshotsClientBeginCreateOrUpdateOptions{ResumeToken: resumeToken}
+	createPoller, err := ac.snapshots.BeginCreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), snapshot, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	resp, err := createPoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, createPoller, err
+	}
+
+	// if the operation completed, return a nil poller
+	return resp.Snapshot, nil, nil
+}
+
+// DeleteAsync deletes a snapshot asynchronously. DeleteAsync sends a DELETE
+// request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
+// progress of the operation.
+func (ac *azureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "snapshots.azureClient.DeleteAsync")
+	defer done()
+
+	opts := &armcompute.SnapshotsClientBeginDeleteOptions{ResumeToken: resumeToken}
+	deletePoller, err := ac.snapshots.BeginDelete(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	_, err = deletePoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
+	if err != nil {
+		// if an error occurs, return the poller.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return deletePoller, err
+	}
+
+	// if the operation completed, return a nil poller.
+	return nil, nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshots
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "snapshots"
+
+// SnapshotScope defines the scope interface for a snapshots service.
+type SnapshotScope interface {
+	azure.ClusterDescriber
+	azure.AsyncStatusUpdater
+	SnapshotSpecs() []azure.ResourceSpecGetter
+	SnapshotSourceDiskNames() []string
+	SnapshotRetain() int
+	SetDiskSnapshotsTaken()
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope SnapshotScope
+	client
+	async.Reconciler
+}
+
+// New creates a new service.
+func New(scope SnapshotScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:      scope,
+		client:     client,
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile takes the snapshots of the disks which are due, and deletes the oldest snapshots of each disk beyond the
+// number of snapshots to retain.
+func (s *Service) Reconcile(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "snapshots.Service.Reconcile")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	if specs := s.Scope.SnapshotSpecs(); len(specs) > 0 {
+		// We go through the list of SnapshotSpecs to create each one, independently of the result of the previous one.
+		// If multiple errors occur, we return the most pressing one.
+		//  Order of precedence (highest -> lowest) is: error that is not an operationNotDoneError (i.e. error creating) -> operationNotDoneError (i.e. creating in progress) -> no error (i.e. created)
+		var result error
+		for _, snapshotSpec := range specs {
+			if _, err := s.CreateResource(ctx, snapshotSpec, serviceName); err != nil {
+				if !azure.IsOperationNotDoneError(err) || result == nil {
+					result = err
+				}
+			}
+		}
+		s.Scope.UpdatePutStatus(infrav1.DiskSnapshotsReadyCondition, serviceName, result)
+		if result != nil {
+			return result
+		}
+		s.Scope.SetDiskSnapshotsTaken()
+	}
+
+	return s.pruneSnapshots(ctx)
+}
+
+// pruneSnapshots deletes the oldest snapshots of each disk beyond the number of snapshots to retain.
+func (s *Service) pruneSnapshots(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "snapshots.Service.pruneSnapshots")
+	defer done()
+
+	sourceDisks := s.Scope.SnapshotSourceDiskNames()
+	if len(sourceDisks) == 0 {
+		return nil
+	}
+
+	existing, err := s.client.List(ctx, s.Scope.ResourceGroup())
+	if err != nil {
+		return errors.Wrap(err, "failed to list snapshots")
+	}
+
+	snapshotsBySource := make(map[string][]armcompute.Snapshot)
+	for _, snapshot := range existing {
+		tags := converters.MapToTags(snapshot.Tags)
+		if !tags.HasOwned(s.Scope.ClusterName()) {
+			continue
+		}
+		source := tags[infrav1.NameAzureSnapshotSourceDisk]
+		snapshotsBySource[source] = append(snapshotsBySource[source], snapshot)
+	}
+
+	retain := s.Scope.SnapshotRetain()
+	var result error
+	for _, source := range sourceDisks {
+		snapshots := snapshotsBySource[source]
+		if len(snapshots) <= retain {
+			continue
+		}
+		sort.Slice(snapshots, func(i, j int) bool {
+			return snapshotTime(snapshots[i]).After(snapshotTime(snapshots[j]))
+		})
+		for _, snapshot := range snapshots[retain:] {
+			log.V(2).Info("deleting expired snapshot", tele.LogKeyResourceID, pointer.StringDeref(snapshot.ID, ""), "disk", source)
+			spec := &SnapshotSpec{
+				Name:          pointer.StringDeref(snapshot.Name, ""),
+				ResourceGroup: s.Scope.ResourceGroup(),
+			}
+			if err := s.DeleteResource(ctx, spec, serviceName); err != nil {
+				if !azure.IsOperationNotDoneError(err) || result == nil {
+					result = err
+				}
+			}
+		}
+	}
+	return result
+}
+
+// snapshotTime returns the time a snapshot was created, or the current time if it is not known yet so that snapshots
+// being created are never the oldest ones.
+func snapshotTime(snapshot armcompute.Snapshot) time.Time {
+	if snapshot.Properties == nil || snapshot.Properties.TimeCreated == nil {
+		return time.Now()
+	}
+	return *snapshot.Properties.TimeCreated
+}
+
+// Delete is a no-op as snapshots are retained after their machine is deleted, so that it can be recovered from them.
+func (s *Service) Delete(ctx context.Context) error {
+	return nil
+}
+
+// IsManaged always returns true as the snapshots are always created by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
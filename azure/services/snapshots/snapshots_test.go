@@ -0,0 +1,210 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshots
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/snapshots/mock_snapshots"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeSnapshotSpec1 = SnapshotSpec{
+		Name:           "my-vm_OSDisk-20220101000000",
+		ResourceGroup:  "my-rg",
+		Location:       "westus",
+		ClusterName:    "my-cluster",
+		SourceDiskName: "my-vm_OSDisk",
+		SourceDiskID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-vm_OSDisk",
+	}
+	fakeSnapshotSpec2 = SnapshotSpec{
+		Name:           "my-vm_etcddisk-20220101000000",
+		ResourceGroup:  "my-rg",
+		Location:       "westus",
+		ClusterName:    "my-cluster",
+		SourceDiskName: "my-vm_etcddisk",
+		SourceDiskID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-vm_etcddisk",
+	}
+
+	internalError = test.NewResponseError(http.StatusInternalServerError)
+)
+
+// fakeSnapshot returns a snapshot of the cluster taken from the given disk at the given time.
+func fakeSnapshot(name, sourceDisk string, created time.Time) armcompute.Snapshot {
+	return armcompute.Snapshot{
+		Name: to.Ptr(name),
+		Tags: map[string]*string{
+			infrav1.ClusterTagKey("my-cluster"): to.Ptr(string(infrav1.ResourceLifecycleOwned)),
+			infrav1.NameAzureSnapshotSourceDisk: to.Ptr(sourceDisk),
+		},
+		Properties: &armcompute.SnapshotProperties{
+			TimeCreated: &created,
+		},
+	}
+}
+
+func TestReconcileSnapshots(t *testing.T) {
+	now := time.Now()
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_snapshots.MockSnapshotScopeMockRecorder, m *mock_snapshots.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "noop if no snapshots are due or retained",
+			expectedError: "",
+			expect: func(s *mock_snapshots.MockSnapshotScopeMockRecorder, m *mock_snapshots.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.SnapshotSpecs().Return(nil)
+				s.SnapshotSourceDiskNames().Return(nil)
+			},
+		},
+		{
+			name:          "take the snapshots which are due",
+			expectedError: "",
+			expect: func(s *mock_snapshots.MockSnapshotScopeMockRecorder, m *mock_snapshots.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.SnapshotSpecs().Return([]azure.ResourceSpecGetter{&fakeSnapshotSpec1, &fakeSnapshotSpec2})
+				gomock.InOrder(
+					r.CreateResource(gomockinternal.AContext(), &fakeSnapshotSpec1, serviceName).Return(nil, nil),
+					r.CreateResource(gomockinternal.AContext(), &fakeSnapshotSpec2, serviceName).Return(nil, nil),
+					s.UpdatePutStatus(infrav1.DiskSnapshotsReadyCondition, serviceName, nil),
+					s.SetDiskSnapshotsTaken(),
+				)
+				s.SnapshotSourceDiskNames().Return([]string{"my-vm_OSDisk", "my-vm_etcddisk"})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.SnapshotRetain().Return(3)
+				m.List(gomockinternal.AContext(), "my-rg").Return([]armcompute.Snapshot{
+					fakeSnapshot("my-vm_OSDisk-20220101000000", "my-vm_OSDisk", now),
+					fakeSnapshot("my-vm_etcddisk-20220101000000", "my-vm_etcddisk", now),
+				}, nil)
+			},
+		},
+		{
+			name:          "do not record the snapshots as taken while they are being created",
+			expectedError: internalError.Error(),
+			expect: func(s *mock_snapshots.MockSnapshotScopeMockRecorder, m *mock_snapshots.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.SnapshotSpecs().Return([]azure.ResourceSpecGetter{&fakeSnapshotSpec1, &fakeSnapshotSpec2})
+				gomock.InOrder(
+					r.CreateResource(gomockinternal.AContext(), &fakeSnapshotSpec1, serviceName).Return(nil, internalError),
+					r.CreateResource(gomockinternal.AContext(), &fakeSnapshotSpec2, serviceName).Return(nil, nil),
+					s.UpdatePutStatus(infrav1.DiskSnapshotsReadyCondition, serviceName, internalError),
+				)
+			},
+		},
+		{
+			name:          "delete the oldest snapshots of each disk beyond the number to retain",
+			expectedError: "",
+			expect: func(s *mock_snapshots.MockSnapshotScopeMockRecorder, m *mock_snapshots.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.SnapshotSpecs().Return(nil)
+				s.SnapshotSourceDiskNames().Return([]string{"my-vm_OSDisk"})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ClusterName().AnyTimes().Return("my-cluster")
+				s.SnapshotRetain().Return(2)
+				other := fakeSnapshot("other-vm_OSDisk-1", "other-vm_OSDisk", now.Add(-72*time.Hour))
+				unowned := fakeSnapshot("my-vm_OSDisk-backup", "my-vm_OSDisk", now.Add(-96*time.Hour))
+				unowned.Tags = nil
+				m.List(gomockinternal.AContext(), "my-rg").Return([]armcompute.Snapshot{
+					fakeSnapshot("my-vm_OSDisk-2", "my-vm_OSDisk", now.Add(-48*time.Hour)),
+					fakeSnapshot("my-vm_OSDisk-1", "my-vm_OSDisk", now.Add(-72*time.Hour)),
+					fakeSnapshot("my-vm_OSDisk-4", "my-vm_OSDisk", now),
+					fakeSnapshot("my-vm_OSDisk-3", "my-vm_OSDisk", now.Add(-24*time.Hour)),
+					other,
+					unowned,
+				}, nil)
+				gomock.InOrder(
+					r.DeleteResource(gomockinternal.AContext(), &SnapshotSpec{Name: "my-vm_OSDisk-2", ResourceGroup: "my-rg"}, serviceName).Return(nil),
+					r.DeleteResource(gomockinternal.AContext(), &SnapshotSpec{Name: "my-vm_OSDisk-1", ResourceGroup: "my-rg"}, serviceName).Return(nil),
+				)
+			},
+		},
+		{
+			name:          "fail to list the snapshots",
+			expectedError: "failed to list snapshots: " + internalError.Error(),
+			expect: func(s *mock_snapshots.MockSnapshotScopeMockRecorder, m *mock_snapshots.MockclientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.SnapshotSpecs().Return(nil)
+				s.SnapshotSourceDiskNames().Return([]string{"my-vm_OSDisk"})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.List(gomockinternal.AContext(), "my-rg").Return(nil, internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_snapshots.NewMockSnapshotScope(mockCtrl)
+			clientMock := mock_snapshots.NewMockclient(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				client:     clientMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestSnapshotSpecParameters(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := fakeSnapshotSpec1
+	spec.AdditionalTags = infrav1.Tags{"team": "storage"}
+	params, err := spec.Parameters(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	snapshot, ok := params.(armcompute.Snapshot)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(snapshot.Location).To(Equal(to.Ptr("westus")))
+	g.Expect(snapshot.Tags).To(HaveKeyWithValue(infrav1.NameAzureSnapshotSourceDisk, to.Ptr("my-vm_OSDisk")))
+	g.Expect(snapshot.Tags).To(HaveKeyWithValue("team", to.Ptr("storage")))
+	g.Expect(snapshot.Tags).To(HaveKeyWithValue(infrav1.ClusterTagKey("my-cluster"), to.Ptr(string(infrav1.ResourceLifecycleOwned))))
+	g.Expect(snapshot.Properties.CreationData.CreateOption).To(Equal(to.Ptr(armcompute.DiskCreateOptionCopy)))
+	g.Expect(snapshot.Properties.CreationData.SourceResourceID).To(Equal(to.Ptr(fakeSnapshotSpec1.SourceDiskID)))
+	g.Expect(snapshot.Properties.Incremental).To(Equal(to.Ptr(true)))
+
+	params, err = spec.Parameters(armcompute.Snapshot{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(params).To(BeNil())
+}
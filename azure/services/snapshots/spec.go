@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshots
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+)
+
+// SnapshotSpec defines the specification for a snapshot of a disk.
+type SnapshotSpec struct {
+	Name           string
+	ResourceGroup  string
+	Location       string
+	ClusterName    string
+	SourceDiskName string
+	SourceDiskID   string
+	AdditionalTags infrav1.Tags
+}
+
+// ResourceName returns the name of the snapshot.
+func (s *SnapshotSpec) ResourceName() string {
+	return s.Name
+}
+
+// ResourceGroupName returns the name of the resource group.
+func (s *SnapshotSpec) ResourceGroupName() string {
+	return s.ResourceGroup
+}
+
+// OwnerResourceName is a no-op for snapshots.
+func (s *SnapshotSpec) OwnerResourceName() string {
+	return ""
+}
+
+// Parameters returns the parameters for the snapshot.
+func (s *SnapshotSpec) Parameters(existing interface{}) (params interface{}, err error) {
+	if existing != nil {
+		if _, ok := existing.(armcompute.Snapshot); !ok {
+			return nil, errors.Errorf("%T is not an armcompute.Snapshot", existing)
+		}
+		// snapshot already exists
+		return nil, nil
+	}
+
+	additionalTags := infrav1.Tags{}
+	additionalTags.Merge(s.AdditionalTags)
+	additionalTags[infrav1.NameAzureSnapshotSourceDisk] = s.SourceDiskName
+
+	return armcompute.Snapshot{
+		Location: to.Ptr(s.Location),
+		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
+			ClusterName: s.ClusterName,
+			Lifecycle:   infrav1.ResourceLifecycleOwned,
+			Name:        to.Ptr(s.Name),
+			Additional:  additionalTags,
+		})),
+		Properties: &armcompute.SnapshotProperties{
+			CreationData: &armcompute.CreationData{
+				CreateOption:     to.Ptr(armcompute.DiskCreateOptionCopy),
+				SourceResourceID: to.Ptr(s.SourceDiskID),
+			},
+			// Incremental snapshots only store the changes since the previous snapshot of the disk.
+			Incremental: to.Ptr(true),
+		},
+	}, nil
+}
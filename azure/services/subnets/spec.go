@@ -17,8 +17,10 @@ limitations under the License.
 package subnets
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -37,6 +39,8 @@ type SubnetSpec struct {
 	SecurityGroupName string
 	Role              infrav1.SubnetRole
 	NatGatewayName    string
+	// Delegation is the service the subnet is delegated to, e.g. Microsoft.Network/dnsResolvers.
+	Delegation string
 }
 
 // ResourceName returns the name of the subnet.
@@ -57,9 +61,9 @@ func (s *SubnetSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the subnet.
 func (s *SubnetSpec) Parameters(existing interface{}) (parameters interface{}, err error) {
 	if existing != nil {
-		_, ok := existing.(network.Subnet)
+		_, ok := existing.(armnetwork.Subnet)
 		if !ok {
-			return nil, errors.Errorf("%T is not a network.Subnet", existing)
+			return nil, errors.Errorf("%T is not an armnetwork.Subnet", existing)
 		}
 
 		return nil, nil
@@ -69,36 +73,48 @@ func (s *SubnetSpec) Parameters(existing interface{}) (parameters interface{}, e
 		// TODO: change this to terminal error once we add support for handling them
 		return nil, errors.Errorf("custom vnet was provided but subnet %s is missing", s.Name)
 	}
-	subnetProperties := network.SubnetPropertiesFormat{
-		AddressPrefixes: &s.CIDRs,
+	subnetProperties := armnetwork.SubnetPropertiesFormat{
+		AddressPrefixes: to.SliceOfPtrs(s.CIDRs...),
 	}
 
 	// workaround needed to avoid SubscriptionNotRegisteredForFeature for feature Microsoft.Network/AllowMultipleAddressPrefixesOnSubnet.
 	if len(s.CIDRs) == 1 {
-		subnetProperties = network.SubnetPropertiesFormat{
-			AddressPrefix: &s.CIDRs[0],
+		subnetProperties = armnetwork.SubnetPropertiesFormat{
+			AddressPrefix: to.Ptr(s.CIDRs[0]),
 		}
 	}
 
 	if s.RouteTableName != "" {
-		subnetProperties.RouteTable = &network.RouteTable{
-			ID: to.StringPtr(azure.RouteTableID(s.SubscriptionID, s.ResourceGroup, s.RouteTableName)),
+		subnetProperties.RouteTable = &armnetwork.RouteTable{
+			ID: to.Ptr(azure.RouteTableID(s.SubscriptionID, s.ResourceGroup, s.RouteTableName)),
 		}
 	}
 
 	if s.NatGatewayName != "" {
-		subnetProperties.NatGateway = &network.SubResource{
-			ID: to.StringPtr(azure.NatGatewayID(s.SubscriptionID, s.ResourceGroup, s.NatGatewayName)),
+		subnetProperties.NatGateway = &armnetwork.SubResource{
+			ID: to.Ptr(azure.NatGatewayID(s.SubscriptionID, s.ResourceGroup, s.NatGatewayName)),
 		}
 	}
 
 	if s.SecurityGroupName != "" {
-		subnetProperties.NetworkSecurityGroup = &network.SecurityGroup{
-			ID: to.StringPtr(azure.SecurityGroupID(s.SubscriptionID, s.ResourceGroup, s.SecurityGroupName)),
+		subnetProperties.NetworkSecurityGroup = &armnetwork.SecurityGroup{
+			ID: to.Ptr(azure.SecurityGroupID(s.SubscriptionID, s.ResourceGroup, s.SecurityGroupName)),
+		}
+	}
+
+	if s.Delegation != "" {
+		subnetProperties.Delegations = []*armnetwork.Delegation{
+			{
+				// Delegation names can't contain slashes.
+				Name: to.Ptr(strings.ReplaceAll(s.Delegation, "/", ".")),
+				Properties: &armnetwork.ServiceDelegationPropertiesFormat{
+					ServiceName: to.Ptr(s.Delegation),
+				},
+			},
 		}
 	}
 
-	return network.Subnet{
-		SubnetPropertiesFormat: &subnetProperties,
+	return armnetwork.Subnet{
+		Properties: &subnetProperties,
 	}, nil
 }
@@ -19,8 +19,8 @@ package subnets
 import (
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
@@ -40,12 +40,12 @@ var (
 		Role:              infrav1.SubnetNode,
 	}
 
-	fakeSubnetOneCidrParams = network.Subnet{
-		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
-			AddressPrefix:        to.StringPtr("10.0.0.0/16"),
-			RouteTable:           &network.RouteTable{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-subnet_route_table")},
-			NetworkSecurityGroup: &network.SecurityGroup{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-sg")},
-			NatGateway:           &network.SubResource{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/my-nat-gateway")},
+	fakeSubnetOneCidrParams = armnetwork.Subnet{
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefix:        to.Ptr("10.0.0.0/16"),
+			RouteTable:           &armnetwork.RouteTable{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-subnet_route_table")},
+			NetworkSecurityGroup: &armnetwork.SecurityGroup{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-sg")},
+			NatGateway:           &armnetwork.SubResource{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/my-nat-gateway")},
 		},
 	}
 
@@ -63,16 +63,41 @@ var (
 		Role:              infrav1.SubnetNode,
 	}
 
-	fakeSubnetMultipleCidrParams = network.Subnet{
-		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
-			AddressPrefixes: &[]string{
-				"10.0.0.0/16",
-				"10.1.0.0/16",
-				"10.2.0.0/16",
+	fakeSubnetMultipleCidrParams = armnetwork.Subnet{
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefixes: []*string{
+				to.Ptr("10.0.0.0/16"),
+				to.Ptr("10.1.0.0/16"),
+				to.Ptr("10.2.0.0/16"),
+			},
+			RouteTable:           &armnetwork.RouteTable{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-subnet_route_table")},
+			NetworkSecurityGroup: &armnetwork.SecurityGroup{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-sg")},
+			NatGateway:           &armnetwork.SubResource{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/my-nat-gateway")},
+		},
+	}
+
+	fakeDelegatedSubnetSpec = SubnetSpec{
+		Name:              "my-dns-resolver-subnet",
+		ResourceGroup:     "my-rg",
+		SubscriptionID:    "123",
+		CIDRs:             []string{"10.255.255.208/28"},
+		IsVNetManaged:     true,
+		VNetName:          "my-vnet",
+		VNetResourceGroup: "my-rg",
+		Delegation:        "Microsoft.Network/dnsResolvers",
+	}
+
+	fakeDelegatedSubnetParams = armnetwork.Subnet{
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefix: to.Ptr("10.255.255.208/28"),
+			Delegations: []*armnetwork.Delegation{
+				{
+					Name: to.Ptr("Microsoft.Network.dnsResolvers"),
+					Properties: &armnetwork.ServiceDelegationPropertiesFormat{
+						ServiceName: to.Ptr("Microsoft.Network/dnsResolvers"),
+					},
+				},
 			},
-			RouteTable:           &network.RouteTable{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-subnet_route_table")},
-			NetworkSecurityGroup: &network.SecurityGroup{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-sg")},
-			NatGateway:           &network.SubResource{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/natGateways/my-nat-gateway")},
 		},
 	}
 
@@ -89,16 +114,16 @@ var (
 		Role:              infrav1.SubnetNode,
 	}
 
-	fakeIpv6SubnetNotManaged = network.Subnet{
-		ID:   to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-ipv6-subnet"),
-		Name: to.StringPtr("my-ipv6-subnet"),
-		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
-			AddressPrefixes: &[]string{
-				"10.0.0.0/16",
-				"2001:1234:5678:9abd::/64",
+	fakeIpv6SubnetNotManaged = armnetwork.Subnet{
+		ID:   to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-ipv6-subnet"),
+		Name: to.Ptr("my-ipv6-subnet"),
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefixes: []*string{
+				to.Ptr("10.0.0.0/16"),
+				to.Ptr("2001:1234:5678:9abd::/64"),
 			},
-			RouteTable:           &network.RouteTable{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-subnet_route_table")},
-			NetworkSecurityGroup: &network.SecurityGroup{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-sg")},
+			RouteTable:           &armnetwork.RouteTable{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-subnet_route_table")},
+			NetworkSecurityGroup: &armnetwork.SecurityGroup{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-sg")},
 		},
 	}
 )
@@ -129,6 +154,15 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:     "get parameters for subnet delegated to a service",
+			spec:     &fakeDelegatedSubnetSpec,
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(Equal(fakeDelegatedSubnetParams))
+			},
+			expectedError: "",
+		},
 		{
 			name:     "error vnet is not managed but subnet is missing",
 			spec:     &fakeSubnetSpecNotManaged,
@@ -19,12 +19,14 @@ package subnets
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aso"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -49,12 +51,15 @@ type Service struct {
 }
 
 // New creates a new service.
-func New(scope SubnetScope) *Service {
-	Client := NewClient(scope)
+func New(scope SubnetScope) (*Service, error) {
+	Client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:      scope,
-		Reconciler: async.New(scope, Client, Client),
-	}
+		Reconciler: aso.NewReconciler(scope, Client, Client),
+	}, nil
 }
 
 // Name returns the service name.
@@ -62,6 +67,15 @@ func (s *Service) Name() string {
 	return serviceName
 }
 
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the subnets.
+func (s *Service) RequiredActions() []string {
+	if !s.Scope.IsVnetManaged() || len(s.Scope.SubnetSpecs()) == 0 {
+		return nil
+	}
+	return []string{"Microsoft.Network/virtualNetworks/subnets/read", "Microsoft.Network/virtualNetworks/subnets/write"}
+}
+
 // Reconcile gets/creates/updates a subnet.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "subnets.Service.Reconcile")
@@ -86,11 +100,11 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				resultErr = err
 			}
 		} else {
-			subnet, ok := result.(network.Subnet)
+			subnet, ok := result.(armnetwork.Subnet)
 			if !ok {
-				return errors.Errorf("%T is not a network.Subnet", result)
+				return errors.Errorf("%T is not an armnetwork.Subnet", result)
 			}
-			s.Scope.UpdateSubnetID(subnetSpec.ResourceName(), to.String(subnet.ID))
+			s.Scope.UpdateSubnetID(subnetSpec.ResourceName(), pointer.StringDeref(subnet.ID, ""))
 			s.Scope.UpdateSubnetCIDRs(subnetSpec.ResourceName(), converters.GetSubnetAddresses(subnet))
 		}
 	}
@@ -138,6 +152,20 @@ func (s *Service) Delete(ctx context.Context) error {
 	return result
 }
 
+// ExportResources adds the subnets to an ARM template.
+func (s *Service) ExportResources(ctx context.Context, template *armtemplate.Template) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "subnets.Service.ExportResources")
+	defer done()
+
+	for _, spec := range s.Scope.SubnetSpecs() {
+		if err := template.AddSpec(spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // IsManaged returns true if the route tables' lifecycles are managed.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	_, _, done := tele.StartSpanWithLogger(ctx, "subnets.Service.IsManaged")
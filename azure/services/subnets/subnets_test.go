@@ -18,12 +18,10 @@ package subnets
 
 import (
 	"context"
-	"net/http"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
@@ -48,18 +46,18 @@ var (
 		Role:              infrav1.SubnetNode,
 	}
 
-	fakeSubnet1 = network.Subnet{
-		ID:   to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet-1"),
-		Name: to.StringPtr("my-subnet-1"),
-		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
-			AddressPrefix: to.StringPtr("10.0.0.0/16"),
-			RouteTable: &network.RouteTable{
-				ID:   to.StringPtr("rt-id"),
-				Name: to.StringPtr("my-subnet_route_table"),
+	fakeSubnet1 = armnetwork.Subnet{
+		ID:   to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet-1"),
+		Name: to.Ptr("my-subnet-1"),
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefix: to.Ptr("10.0.0.0/16"),
+			RouteTable: &armnetwork.RouteTable{
+				ID:   to.Ptr("rt-id"),
+				Name: to.Ptr("my-subnet_route_table"),
 			},
-			NetworkSecurityGroup: &network.SecurityGroup{
-				ID:   to.StringPtr("sg-id-1"),
-				Name: to.StringPtr("my-sg-1"),
+			NetworkSecurityGroup: &armnetwork.SecurityGroup{
+				ID:   to.Ptr("sg-id-1"),
+				Name: to.Ptr("my-sg-1"),
 			},
 		},
 	}
@@ -77,18 +75,18 @@ var (
 		Role:              infrav1.SubnetNode,
 	}
 
-	fakeSubnet2 = network.Subnet{
-		ID:   to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet-2"),
-		Name: to.StringPtr("my-subnet-2"),
-		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
-			AddressPrefix: to.StringPtr("10.2.0.0/16"),
-			RouteTable: &network.RouteTable{
-				ID:   to.StringPtr("rt-id"),
-				Name: to.StringPtr("my-subnet_route_table"),
+	fakeSubnet2 = armnetwork.Subnet{
+		ID:   to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet-2"),
+		Name: to.Ptr("my-subnet-2"),
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefix: to.Ptr("10.2.0.0/16"),
+			RouteTable: &armnetwork.RouteTable{
+				ID:   to.Ptr("rt-id"),
+				Name: to.Ptr("my-subnet_route_table"),
 			},
-			NetworkSecurityGroup: &network.SecurityGroup{
-				ID:   to.StringPtr("sg-id-2"),
-				Name: to.StringPtr("my-sg-2"),
+			NetworkSecurityGroup: &armnetwork.SecurityGroup{
+				ID:   to.Ptr("sg-id-2"),
+				Name: to.Ptr("my-sg-2"),
 			},
 		},
 	}
@@ -105,18 +103,18 @@ var (
 		SecurityGroupName: "my-sg-1",
 		Role:              infrav1.SubnetNode,
 	}
-	fakeSubnetNotManaged = network.Subnet{
-		ID:   to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet-1"),
-		Name: to.StringPtr("my-subnet-1"),
-		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
-			AddressPrefix: to.StringPtr("10.0.0.0/16"),
-			RouteTable: &network.RouteTable{
-				ID:   to.StringPtr("rt-id"),
-				Name: to.StringPtr("my-subnet_route_table"),
+	fakeSubnetNotManaged = armnetwork.Subnet{
+		ID:   to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet-1"),
+		Name: to.Ptr("my-subnet-1"),
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefix: to.Ptr("10.0.0.0/16"),
+			RouteTable: &armnetwork.RouteTable{
+				ID:   to.Ptr("rt-id"),
+				Name: to.Ptr("my-subnet_route_table"),
 			},
-			NetworkSecurityGroup: &network.SecurityGroup{
-				ID:   to.StringPtr("sg-id-1"),
-				Name: to.StringPtr("my-sg-1"),
+			NetworkSecurityGroup: &armnetwork.SecurityGroup{
+				ID:   to.Ptr("sg-id-1"),
+				Name: to.Ptr("my-sg-1"),
 			},
 		},
 	}
@@ -147,16 +145,16 @@ var (
 		Role:              infrav1.SubnetNode,
 	}
 
-	fakeIpv6Subnet = network.Subnet{
-		ID:   to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-ipv6-subnet"),
-		Name: to.StringPtr("my-ipv6-subnet"),
-		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
-			AddressPrefixes: &[]string{
-				"10.0.0.0/16",
-				"2001:1234:5678:9abd::/64",
+	fakeIpv6Subnet = armnetwork.Subnet{
+		ID:   to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-ipv6-subnet"),
+		Name: to.Ptr("my-ipv6-subnet"),
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefixes: []*string{
+				to.Ptr("10.0.0.0/16"),
+				to.Ptr("2001:1234:5678:9abd::/64"),
 			},
-			RouteTable:           &network.RouteTable{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-subnet_route_table")},
-			NetworkSecurityGroup: &network.SecurityGroup{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-sg")},
+			RouteTable:           &armnetwork.RouteTable{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-subnet_route_table")},
+			NetworkSecurityGroup: &armnetwork.SecurityGroup{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-sg")},
 		},
 	}
 
@@ -173,22 +171,22 @@ var (
 		Role:              infrav1.SubnetNode,
 	}
 
-	fakeIpv6SubnetCP = network.Subnet{
-		ID:   to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-ipv6-subnet-cp"),
-		Name: to.StringPtr("my-ipv6-subnet-cp"),
-		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
-			AddressPrefixes: &[]string{
-				"10.2.0.0/16",
-				"2001:1234:5678:9abc::/64",
+	fakeIpv6SubnetCP = armnetwork.Subnet{
+		ID:   to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-ipv6-subnet-cp"),
+		Name: to.Ptr("my-ipv6-subnet-cp"),
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			AddressPrefixes: []*string{
+				to.Ptr("10.2.0.0/16"),
+				to.Ptr("2001:1234:5678:9abc::/64"),
 			},
-			RouteTable:           &network.RouteTable{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-subnet_route_table")},
-			NetworkSecurityGroup: &network.SecurityGroup{ID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-sg")},
+			RouteTable:           &armnetwork.RouteTable{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/routeTables/my-subnet_route_table")},
+			NetworkSecurityGroup: &armnetwork.SecurityGroup{ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkSecurityGroups/my-sg")},
 		},
 	}
 
 	notASubnet    = "not a subnet"
-	notASubnetErr = errors.Errorf("%T is not a network.Subnet", notASubnet)
-	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+	notASubnetErr = errors.Errorf("%T is not an armnetwork.Subnet", notASubnet)
+	internalError = errors.New("Internal Server Error")
 )
 
 func TestReconcileSubnets(t *testing.T) {
@@ -211,8 +209,8 @@ func TestReconcileSubnets(t *testing.T) {
 				s.SubnetSpecs().Return([]azure.ResourceSpecGetter{&fakeSubnetSpec1})
 
 				r.CreateResource(gomockinternal.AContext(), &fakeSubnetSpec1, serviceName).Return(fakeSubnet1, nil)
-				s.UpdateSubnetID(fakeSubnetSpec1.Name, to.String(fakeSubnet1.ID))
-				s.UpdateSubnetCIDRs(fakeSubnetSpec1.Name, []string{to.String(fakeSubnet1.AddressPrefix)})
+				s.UpdateSubnetID(fakeSubnetSpec1.Name, *fakeSubnet1.ID)
+				s.UpdateSubnetCIDRs(fakeSubnetSpec1.Name, []string{*fakeSubnet1.Properties.AddressPrefix})
 
 				s.IsVnetManaged().AnyTimes().Return(true)
 				s.UpdatePutStatus(infrav1.SubnetsReadyCondition, serviceName, nil)
@@ -225,12 +223,12 @@ func TestReconcileSubnets(t *testing.T) {
 				s.SubnetSpecs().Return([]azure.ResourceSpecGetter{&fakeSubnetSpec1, &fakeSubnetSpec2})
 
 				r.CreateResource(gomockinternal.AContext(), &fakeSubnetSpec1, serviceName).Return(fakeSubnet1, nil)
-				s.UpdateSubnetID(fakeSubnetSpec1.Name, to.String(fakeSubnet1.ID))
-				s.UpdateSubnetCIDRs(fakeSubnetSpec1.Name, []string{to.String(fakeSubnet1.AddressPrefix)})
+				s.UpdateSubnetID(fakeSubnetSpec1.Name, *fakeSubnet1.ID)
+				s.UpdateSubnetCIDRs(fakeSubnetSpec1.Name, []string{*fakeSubnet1.Properties.AddressPrefix})
 
 				r.CreateResource(gomockinternal.AContext(), &fakeSubnetSpec2, serviceName).Return(fakeSubnet2, nil)
-				s.UpdateSubnetID(fakeSubnetSpec2.Name, to.String(fakeSubnet2.ID))
-				s.UpdateSubnetCIDRs(fakeSubnetSpec2.Name, []string{to.String(fakeSubnet2.AddressPrefix)})
+				s.UpdateSubnetID(fakeSubnetSpec2.Name, *fakeSubnet2.ID)
+				s.UpdateSubnetCIDRs(fakeSubnetSpec2.Name, []string{*fakeSubnet2.Properties.AddressPrefix})
 
 				s.IsVnetManaged().AnyTimes().Return(true)
 				s.UpdatePutStatus(infrav1.SubnetsReadyCondition, serviceName, nil)
@@ -243,8 +241,8 @@ func TestReconcileSubnets(t *testing.T) {
 				s.SubnetSpecs().Return([]azure.ResourceSpecGetter{&fakeSubnetSpecNotManaged})
 
 				r.CreateResource(gomockinternal.AContext(), &fakeSubnetSpecNotManaged, serviceName).Return(fakeSubnetNotManaged, nil)
-				s.UpdateSubnetID(fakeSubnetSpecNotManaged.Name, to.String(fakeSubnetNotManaged.ID))
-				s.UpdateSubnetCIDRs(fakeSubnetSpecNotManaged.Name, []string{to.String(fakeSubnetNotManaged.AddressPrefix)})
+				s.UpdateSubnetID(fakeSubnetSpecNotManaged.Name, *fakeSubnetNotManaged.ID)
+				s.UpdateSubnetCIDRs(fakeSubnetSpecNotManaged.Name, []string{*fakeSubnetNotManaged.Properties.AddressPrefix})
 
 				s.IsVnetManaged().AnyTimes().Return(false)
 			},
@@ -256,8 +254,8 @@ func TestReconcileSubnets(t *testing.T) {
 				s.SubnetSpecs().Return([]azure.ResourceSpecGetter{&fakeIpv6SubnetSpec})
 
 				r.CreateResource(gomockinternal.AContext(), &fakeIpv6SubnetSpec, serviceName).Return(fakeIpv6Subnet, nil)
-				s.UpdateSubnetID(fakeIpv6SubnetSpec.Name, to.String(fakeIpv6Subnet.ID))
-				s.UpdateSubnetCIDRs(fakeIpv6SubnetSpec.Name, to.StringSlice(fakeIpv6Subnet.AddressPrefixes))
+				s.UpdateSubnetID(fakeIpv6SubnetSpec.Name, *fakeIpv6Subnet.ID)
+				s.UpdateSubnetCIDRs(fakeIpv6SubnetSpec.Name, fakeIpv6SubnetSpec.CIDRs)
 
 				s.IsVnetManaged().AnyTimes().Return(true)
 				s.UpdatePutStatus(infrav1.SubnetsReadyCondition, serviceName, nil)
@@ -270,12 +268,12 @@ func TestReconcileSubnets(t *testing.T) {
 				s.SubnetSpecs().Return([]azure.ResourceSpecGetter{&fakeIpv6SubnetSpec, &fakeIpv6SubnetSpecCP})
 
 				r.CreateResource(gomockinternal.AContext(), &fakeIpv6SubnetSpec, serviceName).Return(fakeIpv6Subnet, nil)
-				s.UpdateSubnetID(fakeIpv6SubnetSpec.Name, to.String(fakeIpv6Subnet.ID))
-				s.UpdateSubnetCIDRs(fakeIpv6SubnetSpec.Name, to.StringSlice(fakeIpv6Subnet.AddressPrefixes))
+				s.UpdateSubnetID(fakeIpv6SubnetSpec.Name, *fakeIpv6Subnet.ID)
+				s.UpdateSubnetCIDRs(fakeIpv6SubnetSpec.Name, fakeIpv6SubnetSpec.CIDRs)
 
 				r.CreateResource(gomockinternal.AContext(), &fakeIpv6SubnetSpecCP, serviceName).Return(fakeIpv6SubnetCP, nil)
-				s.UpdateSubnetID(fakeIpv6SubnetSpecCP.Name, to.String(fakeIpv6SubnetCP.ID))
-				s.UpdateSubnetCIDRs(fakeIpv6SubnetSpecCP.Name, to.StringSlice(fakeIpv6SubnetCP.AddressPrefixes))
+				s.UpdateSubnetID(fakeIpv6SubnetSpecCP.Name, *fakeIpv6SubnetCP.ID)
+				s.UpdateSubnetCIDRs(fakeIpv6SubnetSpecCP.Name, fakeIpv6SubnetSpecCP.CIDRs)
 
 				s.IsVnetManaged().AnyTimes().Return(true)
 				s.UpdatePutStatus(infrav1.SubnetsReadyCondition, serviceName, nil)
@@ -283,7 +281,7 @@ func TestReconcileSubnets(t *testing.T) {
 		},
 		{
 			name:          "fail to create subnet",
-			expectedError: "#: Internal Server Error: StatusCode=500",
+			expectedError: "Internal Server Error",
 			expect: func(s *mock_subnets.MockSubnetScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.SubnetSpecs().Return([]azure.ResourceSpecGetter{&fakeSubnetSpec1})
 				r.CreateResource(gomockinternal.AContext(), &fakeSubnetSpec1, serviceName).Return(nil, internalError)
@@ -302,14 +300,14 @@ func TestReconcileSubnets(t *testing.T) {
 		},
 		{
 			name:          "fail to create subnets",
-			expectedError: "#: Internal Server Error: StatusCode=500",
+			expectedError: "Internal Server Error",
 			expect: func(s *mock_subnets.MockSubnetScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.SubnetSpecs().Return([]azure.ResourceSpecGetter{&fakeSubnetSpec1, &fakeSubnetSpec2})
 				r.CreateResource(gomockinternal.AContext(), &fakeSubnetSpec1, serviceName).Return(nil, internalError)
 
 				r.CreateResource(gomockinternal.AContext(), &fakeSubnetSpec2, serviceName).Return(fakeSubnet2, nil)
-				s.UpdateSubnetID(fakeSubnetSpec2.Name, to.String(fakeSubnet2.ID))
-				s.UpdateSubnetCIDRs(fakeSubnetSpec2.Name, []string{to.String(fakeSubnet2.AddressPrefix)})
+				s.UpdateSubnetID(fakeSubnetSpec2.Name, *fakeSubnet2.ID)
+				s.UpdateSubnetCIDRs(fakeSubnetSpec2.Name, []string{*fakeSubnet2.Properties.AddressPrefix})
 
 				s.IsVnetManaged().AnyTimes().Return(true)
 				s.UpdatePutStatus(infrav1.SubnetsReadyCondition, serviceName, internalError)
@@ -391,7 +389,7 @@ func TestDeleteSubnets(t *testing.T) {
 		},
 		{
 			name:          "fail delete subnet",
-			expectedError: "#: Internal Server Error: StatusCode=500",
+			expectedError: "Internal Server Error",
 			expect: func(s *mock_subnets.MockSubnetScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.IsVnetManaged().AnyTimes().Return(true)
 				s.SubnetSpecs().Return([]azure.ResourceSpecGetter{&fakeSubnetSpec1})
@@ -23,7 +23,7 @@ package mock_tags
 import (
 	reflect "reflect"
 
-	autorest "github.com/Azure/go-autorest/autorest"
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	gomock "github.com/golang/mock/gomock"
 	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
 )
@@ -66,20 +66,6 @@ func (mr *MockTagScopeMockRecorder) AnnotationJSON(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnnotationJSON", reflect.TypeOf((*MockTagScope)(nil).AnnotationJSON), arg0)
 }
 
-// Authorizer mocks base method.
-func (m *MockTagScope) Authorizer() autorest.Authorizer {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authorizer")
-	ret0, _ := ret[0].(autorest.Authorizer)
-	return ret0
-}
-
-// Authorizer indicates an expected call of Authorizer.
-func (mr *MockTagScopeMockRecorder) Authorizer() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockTagScope)(nil).Authorizer))
-}
-
 // BaseURI mocks base method.
 func (m *MockTagScope) BaseURI() string {
 	m.ctrl.T.Helper()
@@ -206,6 +192,20 @@ func (mr *MockTagScopeMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockTagScope)(nil).TenantID))
 }
 
+// Token mocks base method.
+func (m *MockTagScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockTagScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockTagScope)(nil).Token))
+}
+
 // UpdateAnnotationJSON mocks base method.
 func (m *MockTagScope) UpdateAnnotationJSON(arg0 string, arg1 map[string]interface{}) error {
 	m.ctrl.T.Helper()
@@ -19,8 +19,8 @@ package tags
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
@@ -45,11 +45,15 @@ type Service struct {
 }
 
 // New creates a new service.
-func New(scope TagScope) *Service {
+func New(scope TagScope) (*Service, error) {
+	cli, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:  scope,
-		client: newClient(scope),
-	}
+		client: cli,
+	}, nil
 }
 
 // Name returns the service name.
@@ -57,6 +61,12 @@ func (s *Service) Name() string {
 	return serviceName
 }
 
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the tags.
+func (s *Service) RequiredActions() []string {
+	return []string{"Microsoft.Resources/tags/read", "Microsoft.Resources/tags/write"}
+}
+
 // Reconcile ensures tags are correct.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "tags.Service.Reconcile")
@@ -72,7 +82,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			tags = existingTags.Properties.Tags
 		}
 
-		if !s.isResourceManaged(tags) {
+		if !tagsSpec.Owned && !s.isResourceManaged(tags) {
 			log.V(4).Info("Skipping tags reconcile for not managed resource")
 			continue
 		}
@@ -87,10 +97,10 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			if len(createdOrUpdated) > 0 {
 				createdOrUpdatedTags := make(map[string]*string)
 				for k, v := range createdOrUpdated {
-					createdOrUpdatedTags[k] = to.StringPtr(v)
+					createdOrUpdatedTags[k] = to.Ptr(v)
 				}
 
-				if _, err := s.client.UpdateAtScope(ctx, tagsSpec.Scope, resources.TagsPatchResource{Operation: "Merge", Properties: &resources.Tags{Tags: createdOrUpdatedTags}}); err != nil {
+				if _, err := s.client.UpdateAtScope(ctx, tagsSpec.Scope, armresources.TagsPatchResource{Operation: to.Ptr(armresources.TagsPatchOperationMerge), Properties: &armresources.Tags{Tags: createdOrUpdatedTags}}); err != nil {
 					return errors.Wrap(err, "cannot update tags")
 				}
 			}
@@ -98,10 +108,10 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			if len(deleted) > 0 {
 				deletedTags := make(map[string]*string)
 				for k, v := range deleted {
-					deletedTags[k] = to.StringPtr(v)
+					deletedTags[k] = to.Ptr(v)
 				}
 
-				if _, err := s.client.UpdateAtScope(ctx, tagsSpec.Scope, resources.TagsPatchResource{Operation: "Delete", Properties: &resources.Tags{Tags: deletedTags}}); err != nil {
+				if _, err := s.client.UpdateAtScope(ctx, tagsSpec.Scope, armresources.TagsPatchResource{Operation: to.Ptr(armresources.TagsPatchOperationDelete), Properties: &armresources.Tags{Tags: deletedTags}}); err != nil {
 					return errors.Wrap(err, "cannot update tags")
 				}
 			}
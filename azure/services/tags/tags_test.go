@@ -18,12 +18,11 @@ package tags
 
 import (
 	"context"
-	"net/http"
+	"errors"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -60,35 +59,35 @@ func TestReconcileTags(t *testing.T) {
 							Annotation: "my-annotation-2",
 						},
 					}),
-					m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(resources.TagsResource{Properties: &resources.Tags{
+					m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(armresources.TagsResource{Properties: &armresources.Tags{
 						Tags: map[string]*string{
-							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
-							"externalSystemTag": to.StringPtr("randomValue"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.Ptr("owned"),
+							"externalSystemTag": to.Ptr("randomValue"),
 						},
 					}}, nil),
 					s.AnnotationJSON("my-annotation"),
-					m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", resources.TagsPatchResource{
-						Operation: "Merge",
-						Properties: &resources.Tags{
+					m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", armresources.TagsPatchResource{
+						Operation: to.Ptr(armresources.TagsPatchOperationMerge),
+						Properties: &armresources.Tags{
 							Tags: map[string]*string{
-								"foo":   to.StringPtr("bar"),
-								"thing": to.StringPtr("stuff"),
+								"foo":   to.Ptr("bar"),
+								"thing": to.Ptr("stuff"),
 							},
 						},
 					}),
 					s.UpdateAnnotationJSON("my-annotation", map[string]interface{}{"foo": "bar", "thing": "stuff"}),
-					m.GetAtScope(gomockinternal.AContext(), "/sub/123/other/scope").Return(resources.TagsResource{Properties: &resources.Tags{
+					m.GetAtScope(gomockinternal.AContext(), "/sub/123/other/scope").Return(armresources.TagsResource{Properties: &armresources.Tags{
 						Tags: map[string]*string{
-							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
-							"externalSystem2Tag": to.StringPtr("randomValue2"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.Ptr("owned"),
+							"externalSystem2Tag": to.Ptr("randomValue2"),
 						},
 					}}, nil),
 					s.AnnotationJSON("my-annotation-2"),
-					m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/other/scope", resources.TagsPatchResource{
-						Operation: "Merge",
-						Properties: &resources.Tags{
+					m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/other/scope", armresources.TagsPatchResource{
+						Operation: to.Ptr(armresources.TagsPatchOperationMerge),
+						Properties: &armresources.Tags{
 							Tags: map[string]*string{
-								"tag1": to.StringPtr("value1"),
+								"tag1": to.Ptr("value1"),
 							},
 						},
 					}),
@@ -111,7 +110,37 @@ func TestReconcileTags(t *testing.T) {
 						Annotation: "my-annotation",
 					},
 				})
-				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(resources.TagsResource{}, nil)
+				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(armresources.TagsResource{}, nil)
+			},
+		},
+		{
+			name:          "create tags for resources created along with an owned resource",
+			expectedError: "",
+			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
+				s.ClusterName().AnyTimes().Return("test-cluster")
+				gomock.InOrder(
+					s.TagsSpecs().Return([]azure.TagsSpec{
+						{
+							Scope: "/sub/123/fake/disk",
+							Tags: map[string]string{
+								"costcenter": "storage",
+							},
+							Annotation: "my-annotation",
+							Owned:      true,
+						},
+					}),
+					m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/disk").Return(armresources.TagsResource{}, nil),
+					s.AnnotationJSON("my-annotation"),
+					m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/disk", armresources.TagsPatchResource{
+						Operation: to.Ptr(armresources.TagsPatchOperationMerge),
+						Properties: &armresources.Tags{
+							Tags: map[string]*string{
+								"costcenter": to.Ptr("storage"),
+							},
+						},
+					}),
+					s.UpdateAnnotationJSON("my-annotation", map[string]interface{}{"costcenter": "storage"}),
+				)
 			},
 		},
 		{
@@ -129,19 +158,19 @@ func TestReconcileTags(t *testing.T) {
 							Annotation: "my-annotation",
 						},
 					}),
-					m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(resources.TagsResource{Properties: &resources.Tags{
+					m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(armresources.TagsResource{Properties: &armresources.Tags{
 						Tags: map[string]*string{
-							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
-							"foo":   to.StringPtr("bar"),
-							"thing": to.StringPtr("stuff"),
+							"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.Ptr("owned"),
+							"foo":   to.Ptr("bar"),
+							"thing": to.Ptr("stuff"),
 						},
 					}}, nil),
 					s.AnnotationJSON("my-annotation").Return(map[string]interface{}{"foo": "bar", "thing": "stuff"}, nil),
-					m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", resources.TagsPatchResource{
-						Operation: "Delete",
-						Properties: &resources.Tags{
+					m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", armresources.TagsPatchResource{
+						Operation: to.Ptr(armresources.TagsPatchOperationDelete),
+						Properties: &armresources.Tags{
 							Tags: map[string]*string{
-								"thing": to.StringPtr("stuff"),
+								"thing": to.Ptr("stuff"),
 							},
 						},
 					}),
@@ -151,7 +180,7 @@ func TestReconcileTags(t *testing.T) {
 		},
 		{
 			name:          "error getting existing tags",
-			expectedError: "failed to get existing tags: #: Internal Server Error: StatusCode=500",
+			expectedError: "failed to get existing tags: Internal Server Error",
 			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
 				s.ClusterName().AnyTimes().Return("test-cluster")
 				s.TagsSpecs().Return([]azure.TagsSpec{
@@ -164,12 +193,12 @@ func TestReconcileTags(t *testing.T) {
 						Annotation: "my-annotation",
 					},
 				})
-				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(resources.TagsResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(armresources.TagsResource{}, errors.New("Internal Server Error"))
 			},
 		},
 		{
 			name:          "error updating tags",
-			expectedError: "cannot update tags: #: Internal Server Error: StatusCode=500",
+			expectedError: "cannot update tags: Internal Server Error",
 			expect: func(s *mock_tags.MockTagScopeMockRecorder, m *mock_tags.MockclientMockRecorder) {
 				s.ClusterName().AnyTimes().Return("test-cluster")
 				s.TagsSpecs().Return([]azure.TagsSpec{
@@ -181,20 +210,20 @@ func TestReconcileTags(t *testing.T) {
 						Annotation: "my-annotation",
 					},
 				})
-				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(resources.TagsResource{Properties: &resources.Tags{
+				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(armresources.TagsResource{Properties: &armresources.Tags{
 					Tags: map[string]*string{
-						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.Ptr("owned"),
 					},
 				}}, nil)
 				s.AnnotationJSON("my-annotation")
-				m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", resources.TagsPatchResource{
-					Operation: "Merge",
-					Properties: &resources.Tags{
+				m.UpdateAtScope(gomockinternal.AContext(), "/sub/123/fake/scope", armresources.TagsPatchResource{
+					Operation: to.Ptr(armresources.TagsPatchOperationMerge),
+					Properties: &armresources.Tags{
 						Tags: map[string]*string{
-							"key": to.StringPtr("value"),
+							"key": to.Ptr("value"),
 						},
 					},
-				}).Return(resources.TagsResource{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				}).Return(armresources.TagsResource{}, errors.New("Internal Server Error"))
 			},
 		},
 		{
@@ -211,10 +240,10 @@ func TestReconcileTags(t *testing.T) {
 						Annotation: "my-annotation",
 					},
 				})
-				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(resources.TagsResource{Properties: &resources.Tags{
+				m.GetAtScope(gomockinternal.AContext(), "/sub/123/fake/scope").Return(armresources.TagsResource{Properties: &armresources.Tags{
 					Tags: map[string]*string{
-						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
-						"key": to.StringPtr("value"),
+						"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.Ptr("owned"),
+						"key": to.Ptr("value"),
 					},
 				}}, nil)
 				s.AnnotationJSON("my-annotation").Return(map[string]interface{}{"key": "value"}, nil)
@@ -270,7 +299,7 @@ func TestTagsChanged(t *testing.T) {
 				"foo": "hello",
 			},
 			currentTags: map[string]*string{
-				"foo": to.StringPtr("hello"),
+				"foo": to.Ptr("hello"),
 			},
 			expectedResult:           false,
 			expectedCreatedOrUpdated: map[string]string{},
@@ -286,7 +315,7 @@ func TestTagsChanged(t *testing.T) {
 				"foo": "goodbye",
 			},
 			currentTags: map[string]*string{
-				"foo": to.StringPtr("hello"),
+				"foo": to.Ptr("hello"),
 			},
 			expectedResult: true,
 			expectedCreatedOrUpdated: map[string]string{
@@ -302,7 +331,7 @@ func TestTagsChanged(t *testing.T) {
 			},
 			desiredTags: map[string]string{},
 			currentTags: map[string]*string{
-				"foo": to.StringPtr("hello"),
+				"foo": to.Ptr("hello"),
 			},
 			expectedResult:           true,
 			expectedCreatedOrUpdated: map[string]string{},
@@ -319,7 +348,7 @@ func TestTagsChanged(t *testing.T) {
 				"bar": "welcome",
 			},
 			currentTags: map[string]*string{
-				"foo": to.StringPtr("hello"),
+				"foo": to.Ptr("hello"),
 			},
 			expectedResult: true,
 			expectedCreatedOrUpdated: map[string]string{
@@ -338,7 +367,7 @@ func TestTagsChanged(t *testing.T) {
 				"bar": "welcome",
 			},
 			currentTags: map[string]*string{
-				"foo": to.StringPtr("hello"),
+				"foo": to.Ptr("hello"),
 			},
 			expectedResult: true,
 			expectedCreatedOrUpdated: map[string]string{
@@ -361,7 +390,7 @@ func TestTagsChanged(t *testing.T) {
 				"bar": "welcome",
 			},
 			currentTags: map[string]*string{
-				"foo": to.StringPtr("hello"),
+				"foo": to.Ptr("hello"),
 			},
 			expectedResult: true,
 			expectedCreatedOrUpdated: map[string]string{
@@ -383,8 +412,8 @@ func TestTagsChanged(t *testing.T) {
 				"bar": "welcome",
 			},
 			currentTags: map[string]*string{
-				"foo": to.StringPtr("hello"),
-				"bar": to.StringPtr("random"),
+				"foo": to.Ptr("hello"),
+				"bar": to.Ptr("random"),
 			},
 			expectedResult: true,
 			expectedCreatedOrUpdated: map[string]string{
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tombstones
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// client wraps go-sdk.
+type client interface {
+	Get(context.Context, string, string) (armcompute.VirtualMachine, error)
+	Deallocate(context.Context, string, string) error
+	MergeTags(context.Context, string, map[string]*string) error
+}
+
+// azureClient contains the Azure go-sdk Clients.
+type azureClient struct {
+	virtualmachines *armcompute.VirtualMachinesClient
+	tags            *armresources.TagsClient
+}
+
+var _ client = (*azureClient)(nil)
+
+// newClient creates a new tombstones client from subscription ID.
+func newClient(auth azure.Authorizer) (*azureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	virtualmachines, err := armcompute.NewVirtualMachinesClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create virtual machines client")
+	}
+	tags, err := armresources.NewTagsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create tags client")
+	}
+	return &azureClient{
+		virtualmachines: virtualmachines,
+		tags:            tags,
+	}, nil
+}
+
+// Get retrieves a virtual machine with its instance view.
+func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmName string) (armcompute.VirtualMachine, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "tombstones.azureClient.Get")
+	defer done()
+
+	resp, err := ac.virtualmachines.Get(ctx, resourceGroupName, vmName, &armcompute.VirtualMachinesClientGetOptions{
+		Expand: to.Ptr(armcompute.InstanceViewTypesInstanceView),
+	})
+	if err != nil {
+		return armcompute.VirtualMachine{}, err
+	}
+	return resp.VirtualMachine, nil
+}
+
+// Deallocate stops a virtual machine and releases its compute resources, keeping its disks and network interfaces.
+// It does not wait for the virtual machine to be deallocated.
+func (ac *azureClient) Deallocate(ctx context.Context, resourceGroupName, vmName string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "tombstones.azureClient.Deallocate")
+	defer done()
+
+	_, err := ac.virtualmachines.BeginDeallocate(ctx, resourceGroupName, vmName, nil)
+	return err
+}
+
+// MergeTags merges the given tags into the tags of the resource with the given ID.
+func (ac *azureClient) MergeTags(ctx context.Context, id string, tags map[string]*string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "tombstones.azureClient.MergeTags")
+	defer done()
+
+	_, err := ac.tags.UpdateAtScope(ctx, id, armresources.TagsPatchResource{
+		Operation:  to.Ptr(armresources.TagsPatchOperationMerge),
+		Properties: &armresources.Tags{Tags: tags},
+	}, nil)
+	return err
+}
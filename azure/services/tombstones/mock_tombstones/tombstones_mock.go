@@ -0,0 +1,232 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../tombstones.go
+
+// Package mock_tombstones is a generated GoMock package.
+package mock_tombstones
+
+import (
+	reflect "reflect"
+	time "time"
+
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	gomock "github.com/golang/mock/gomock"
+	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// MockTombstoneScope is a mock of TombstoneScope interface.
+type MockTombstoneScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockTombstoneScopeMockRecorder
+}
+
+// MockTombstoneScopeMockRecorder is the mock recorder for MockTombstoneScope.
+type MockTombstoneScopeMockRecorder struct {
+	mock *MockTombstoneScope
+}
+
+// NewMockTombstoneScope creates a new mock instance.
+func NewMockTombstoneScope(ctrl *gomock.Controller) *MockTombstoneScope {
+	mock := &MockTombstoneScope{ctrl: ctrl}
+	mock.recorder = &MockTombstoneScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTombstoneScope) EXPECT() *MockTombstoneScopeMockRecorder {
+	return m.recorder
+}
+
+// BaseURI mocks base method.
+func (m *MockTombstoneScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockTombstoneScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockTombstoneScope)(nil).BaseURI))
+}
+
+// ClientID mocks base method.
+func (m *MockTombstoneScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockTombstoneScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockTombstoneScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockTombstoneScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockTombstoneScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockTombstoneScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockTombstoneScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockTombstoneScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockTombstoneScope)(nil).CloudEnvironment))
+}
+
+// HashKey mocks base method.
+func (m *MockTombstoneScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockTombstoneScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockTombstoneScope)(nil).HashKey))
+}
+
+// SetTombstoneExpired mocks base method.
+func (m *MockTombstoneScope) SetTombstoneExpired() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTombstoneExpired")
+}
+
+// SetTombstoneExpired indicates an expected call of SetTombstoneExpired.
+func (mr *MockTombstoneScopeMockRecorder) SetTombstoneExpired() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTombstoneExpired", reflect.TypeOf((*MockTombstoneScope)(nil).SetTombstoneExpired))
+}
+
+// SetTombstoned mocks base method.
+func (m *MockTombstoneScope) SetTombstoned(deleteAfter time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTombstoned", deleteAfter)
+}
+
+// SetTombstoned indicates an expected call of SetTombstoned.
+func (mr *MockTombstoneScopeMockRecorder) SetTombstoned(deleteAfter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTombstoned", reflect.TypeOf((*MockTombstoneScope)(nil).SetTombstoned), deleteAfter)
+}
+
+// SubscriptionID mocks base method.
+func (m *MockTombstoneScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockTombstoneScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockTombstoneScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockTombstoneScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockTombstoneScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockTombstoneScope)(nil).TenantID))
+}
+
+// Token mocks base method.
+func (m *MockTombstoneScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockTombstoneScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockTombstoneScope)(nil).Token))
+}
+
+// TombstoneExpired mocks base method.
+func (m *MockTombstoneScope) TombstoneExpired() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TombstoneExpired")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// TombstoneExpired indicates an expected call of TombstoneExpired.
+func (mr *MockTombstoneScopeMockRecorder) TombstoneExpired() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TombstoneExpired", reflect.TypeOf((*MockTombstoneScope)(nil).TombstoneExpired))
+}
+
+// TombstonePolicy mocks base method.
+func (m *MockTombstoneScope) TombstonePolicy() *v1beta1.TombstonePolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TombstonePolicy")
+	ret0, _ := ret[0].(*v1beta1.TombstonePolicy)
+	return ret0
+}
+
+// TombstonePolicy indicates an expected call of TombstonePolicy.
+func (mr *MockTombstoneScopeMockRecorder) TombstonePolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TombstonePolicy", reflect.TypeOf((*MockTombstoneScope)(nil).TombstonePolicy))
+}
+
+// VMSpec mocks base method.
+func (m *MockTombstoneScope) VMSpec() azure.ResourceSpecGetter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VMSpec")
+	ret0, _ := ret[0].(azure.ResourceSpecGetter)
+	return ret0
+}
+
+// VMSpec indicates an expected call of VMSpec.
+func (mr *MockTombstoneScopeMockRecorder) VMSpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMSpec", reflect.TypeOf((*MockTombstoneScope)(nil).VMSpec))
+}
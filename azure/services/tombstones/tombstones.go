@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tombstones
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "tombstones"
+
+// TombstoneScope defines the scope interface for a tombstones service.
+type TombstoneScope interface {
+	azure.Authorizer
+	VMSpec() azure.ResourceSpecGetter
+	TombstonePolicy() *infrav1.TombstonePolicy
+	TombstoneExpired() bool
+	SetTombstoned(deleteAfter time.Time)
+	SetTombstoneExpired()
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope TombstoneScope
+	client
+}
+
+// New creates a new service.
+func New(scope TombstoneScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Scope:  scope,
+		client: client,
+	}, nil
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return serviceName
+}
+
+// Reconcile is a no-op, virtual machines are only tombstoned when they are deleted.
+func (s *Service) Reconcile(ctx context.Context) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "tombstones.Service.Reconcile")
+	defer done()
+
+	return nil
+}
+
+// Delete tags the virtual machine as tombstoned, and deallocates it if the tombstone policy requires it, then waits
+// for the grace period of the tombstone policy to pass before the virtual machine and its resources are deleted.
+func (s *Service) Delete(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "tombstones.Service.Delete")
+	defer done()
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
+	defer cancel()
+
+	policy := s.Scope.TombstonePolicy()
+	vmSpec := s.Scope.VMSpec()
+	if policy == nil || vmSpec == nil || s.Scope.TombstoneExpired() {
+		return nil
+	}
+
+	vm, err := s.client.Get(ctx, vmSpec.ResourceGroupName(), vmSpec.ResourceName())
+	if azure.ResourceNotFound(err) {
+		// There is nothing left to recover.
+		s.Scope.SetTombstoneExpired()
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to get virtual machine %s", vmSpec.ResourceName())
+	}
+
+	// The tombstone tag records when the virtual machine was tombstoned. Operators can postpone the deletion by
+	// updating it, and an invalid value is replaced rather than blocking the deletion.
+	tombstonedAt, err := time.Parse(time.RFC3339, pointer.StringDeref(vm.Tags[infrav1.NameAzureTombstone], ""))
+	if err != nil {
+		tombstonedAt = time.Now().UTC()
+		log.V(2).Info("tombstoning virtual machine", tele.LogKeyResource, vmSpec.ResourceName(), "gracePeriod", policy.GracePeriod.Duration)
+		if err := s.client.MergeTags(ctx, pointer.StringDeref(vm.ID, ""), map[string]*string{
+			infrav1.NameAzureTombstone: to.Ptr(tombstonedAt.Format(time.RFC3339)),
+		}); err != nil {
+			return errors.Wrapf(err, "failed to tag virtual machine %s as tombstoned", vmSpec.ResourceName())
+		}
+	}
+
+	deleteAfter := tombstonedAt.Add(policy.GracePeriod.Duration)
+	remaining := time.Until(deleteAfter)
+	if remaining <= 0 {
+		s.Scope.SetTombstoneExpired()
+		return nil
+	}
+
+	if policy.Deallocate {
+		infraVM, err := converters.SDKToVM(vm)
+		if err != nil {
+			return err
+		}
+		if infraVM.PowerState != infrav1.PowerStateDeallocating && infraVM.PowerState != infrav1.PowerStateDeallocated && infraVM.PowerState != infrav1.PowerStateHibernated {
+			log.V(2).Info("deallocating tombstoned virtual machine", tele.LogKeyResource, vmSpec.ResourceName(), "powerState", infraVM.PowerState)
+			if err := s.client.Deallocate(ctx, vmSpec.ResourceGroupName(), vmSpec.ResourceName()); err != nil {
+				return errors.Wrapf(err, "failed to deallocate tombstoned virtual machine %s", vmSpec.ResourceName())
+			}
+		}
+	}
+
+	s.Scope.SetTombstoned(deleteAfter)
+	// Requeue on the next full second after the grace period passes.
+	remaining = remaining.Truncate(time.Second) + time.Second
+	return azure.WithTransientError(errors.Errorf("virtual machine %s is tombstoned, waiting %s before deleting it", vmSpec.ResourceName(), remaining), remaining)
+}
+
+// IsManaged always returns true as the virtual machine of the machine is always managed by CAPZ.
+func (s *Service) IsManaged(ctx context.Context) (bool, error) {
+	return true, nil
+}
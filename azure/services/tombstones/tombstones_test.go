@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tombstones
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tombstones/mock_tombstones"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeVMSpec = virtualmachines.VMSpec{
+		Name:          "my-vm",
+		ResourceGroup: "my-rg",
+	}
+	fakeVMID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm"
+
+	fakePolicy           = infrav1.TombstonePolicy{GracePeriod: metav1.Duration{Duration: time.Hour}}
+	fakeDeallocatePolicy = infrav1.TombstonePolicy{GracePeriod: metav1.Duration{Duration: time.Hour}, Deallocate: true}
+
+	notFoundError = &azcore.ResponseError{StatusCode: http.StatusNotFound}
+	internalError = errors.New("Internal Server Error")
+)
+
+func fakeVM(tombstonedAt *time.Time, powerState string) armcompute.VirtualMachine {
+	vm := armcompute.VirtualMachine{
+		ID:   to.Ptr(fakeVMID),
+		Tags: map[string]*string{},
+		Properties: &armcompute.VirtualMachineProperties{
+			InstanceView: &armcompute.VirtualMachineInstanceView{
+				Statuses: []*armcompute.InstanceViewStatus{{Code: to.Ptr("PowerState/" + powerState)}},
+			},
+		},
+	}
+	if tombstonedAt != nil {
+		vm.Tags[infrav1.NameAzureTombstone] = to.Ptr(tombstonedAt.Format(time.RFC3339))
+	}
+	return vm
+}
+
+func TestDeleteTombstones(t *testing.T) {
+	justNow := time.Now().Add(-time.Minute)
+	longAgo := time.Now().Add(-2 * time.Hour)
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_tombstones.MockTombstoneScopeMockRecorder, c *mock_tombstones.MockclientMockRecorder)
+	}{
+		{
+			name:          "noop if no tombstone policy is set",
+			expectedError: "",
+			expect: func(s *mock_tombstones.MockTombstoneScopeMockRecorder, c *mock_tombstones.MockclientMockRecorder) {
+				s.TombstonePolicy().Return(nil)
+				s.VMSpec().Return(&fakeVMSpec)
+			},
+		},
+		{
+			name:          "noop if the tombstone already expired",
+			expectedError: "",
+			expect: func(s *mock_tombstones.MockTombstoneScopeMockRecorder, c *mock_tombstones.MockclientMockRecorder) {
+				s.TombstonePolicy().Return(&fakePolicy)
+				s.VMSpec().Return(&fakeVMSpec)
+				s.TombstoneExpired().Return(true)
+			},
+		},
+		{
+			name:          "noop if the virtual machine doesn't exist",
+			expectedError: "",
+			expect: func(s *mock_tombstones.MockTombstoneScopeMockRecorder, c *mock_tombstones.MockclientMockRecorder) {
+				s.TombstonePolicy().Return(&fakePolicy)
+				s.VMSpec().Return(&fakeVMSpec)
+				s.TombstoneExpired().Return(false)
+				c.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(armcompute.VirtualMachine{}, notFoundError)
+				s.SetTombstoneExpired()
+			},
+		},
+		{
+			name:          "tag the virtual machine as tombstoned and wait for the grace period",
+			expectedError: "virtual machine my-vm is tombstoned, waiting 1h0m0s before deleting it. Object will be requeued after 1h0m0s",
+			expect: func(s *mock_tombstones.MockTombstoneScopeMockRecorder, c *mock_tombstones.MockclientMockRecorder) {
+				s.TombstonePolicy().Return(&fakePolicy)
+				s.VMSpec().Return(&fakeVMSpec)
+				s.TombstoneExpired().Return(false)
+				c.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(fakeVM(nil, "running"), nil)
+				c.MergeTags(gomockinternal.AContext(), fakeVMID, gomock.Any()).Return(nil)
+				s.SetTombstoned(gomock.Any())
+			},
+		},
+		{
+			name:          "deallocate a tombstoned virtual machine",
+			expectedError: "virtual machine my-vm is tombstoned",
+			expect: func(s *mock_tombstones.MockTombstoneScopeMockRecorder, c *mock_tombstones.MockclientMockRecorder) {
+				s.TombstonePolicy().Return(&fakeDeallocatePolicy)
+				s.VMSpec().Return(&fakeVMSpec)
+				s.TombstoneExpired().Return(false)
+				c.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(fakeVM(&justNow, "running"), nil)
+				c.Deallocate(gomockinternal.AContext(), "my-rg", "my-vm").Return(nil)
+				s.SetTombstoned(gomock.Any())
+			},
+		},
+		{
+			name:          "don't deallocate a virtual machine twice",
+			expectedError: "virtual machine my-vm is tombstoned",
+			expect: func(s *mock_tombstones.MockTombstoneScopeMockRecorder, c *mock_tombstones.MockclientMockRecorder) {
+				s.TombstonePolicy().Return(&fakeDeallocatePolicy)
+				s.VMSpec().Return(&fakeVMSpec)
+				s.TombstoneExpired().Return(false)
+				c.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(fakeVM(&justNow, "deallocated"), nil)
+				s.SetTombstoned(gomock.Any())
+			},
+		},
+		{
+			name:          "mark the tombstone expired once the grace period passed",
+			expectedError: "",
+			expect: func(s *mock_tombstones.MockTombstoneScopeMockRecorder, c *mock_tombstones.MockclientMockRecorder) {
+				s.TombstonePolicy().Return(&fakePolicy)
+				s.VMSpec().Return(&fakeVMSpec)
+				s.TombstoneExpired().Return(false)
+				c.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(fakeVM(&longAgo, "running"), nil)
+				s.SetTombstoneExpired()
+			},
+		},
+		{
+			name:          "fail to tag the virtual machine",
+			expectedError: "failed to tag virtual machine my-vm as tombstoned: Internal Server Error",
+			expect: func(s *mock_tombstones.MockTombstoneScopeMockRecorder, c *mock_tombstones.MockclientMockRecorder) {
+				s.TombstonePolicy().Return(&fakePolicy)
+				s.VMSpec().Return(&fakeVMSpec)
+				s.TombstoneExpired().Return(false)
+				c.Get(gomockinternal.AContext(), "my-rg", "my-vm").Return(fakeVM(nil, "running"), nil)
+				c.MergeTags(gomockinternal.AContext(), fakeVMID, gomock.Any()).Return(internalError)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_tombstones.NewMockTombstoneScope(mockCtrl)
+			clientMock := mock_tombstones.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
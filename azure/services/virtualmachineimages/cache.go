@@ -21,7 +21,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/cache/ttllru"
@@ -36,10 +36,20 @@ type Key struct {
 	sku       string
 }
 
-// Cache stores VM image list resources.
+// communityGalleryKey contains the fields necessary to locate a community gallery image version.
+type communityGalleryKey struct {
+	location string
+	gallery  string
+	name     string
+	version  string
+}
+
+// Cache stores VM image list resources and community gallery images.
 type Cache struct {
-	client Client
-	data   map[Key]compute.ListVirtualMachineImageResource
+	client          Client
+	mu              sync.Mutex
+	data            map[Key]armcompute.VirtualMachineImagesClientListResponse
+	communityImages map[communityGalleryKey]*armcompute.CommunityGalleryImage
 }
 
 // Cacher allows getting items from and adding them to a cache.
@@ -55,10 +65,14 @@ var (
 )
 
 // newCache instantiates a cache.
-func newCache(auth azure.Authorizer) *Cache {
-	return &Cache{
-		client: NewClient(auth),
+func newCache(auth azure.Authorizer) (*Cache, error) {
+	client, err := NewClient(auth)
+	if err != nil {
+		return nil, err
 	}
+	return &Cache{
+		client: client,
+	}, nil
 }
 
 // GetCache either creates a new VM images cache or returns the existing one.
@@ -77,9 +91,12 @@ func GetCache(auth azure.Authorizer) (*Cache, error) {
 		return c.(*Cache), nil
 	}
 
-	c = newCache(auth)
-	_ = clientCache.Add(key, c)
-	return c.(*Cache), nil
+	newC, err := newCache(auth)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating VM images cache")
+	}
+	_ = clientCache.Add(key, newC)
+	return newC, nil
 }
 
 // refresh fetches a VM image list resource from Azure and stores it in the cache.
@@ -92,20 +109,18 @@ func (c *Cache) refresh(ctx context.Context, key Key) error {
 		return errors.Wrap(err, "failed to refresh VM images cache")
 	}
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.data[key] = data
 
 	return nil
 }
 
 // Get returns a VM image list resource in a location given a publisher, offer, and sku.
-func (c *Cache) Get(ctx context.Context, location, publisher, offer, sku string) (compute.ListVirtualMachineImageResource, error) {
+func (c *Cache) Get(ctx context.Context, location, publisher, offer, sku string) (armcompute.VirtualMachineImagesClientListResponse, error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachineimages.Cache.Get")
 	defer done()
 
-	if c.data == nil {
-		c.data = make(map[Key]compute.ListVirtualMachineImageResource)
-	}
-
 	key := Key{
 		location:  location,
 		publisher: publisher,
@@ -113,14 +128,80 @@ func (c *Cache) Get(ctx context.Context, location, publisher, offer, sku string)
 		sku:       sku,
 	}
 
-	if _, ok := c.data[key]; !ok {
+	c.mu.Lock()
+	if c.data == nil {
+		c.data = make(map[Key]armcompute.VirtualMachineImagesClientListResponse)
+	}
+	_, ok := c.data[key]
+	c.mu.Unlock()
+	if !ok {
 		log.Info("VM images cache miss", "location", key.location, "publisher", key.publisher, "offer", key.offer, "sku", key.sku)
 		if err := c.refresh(ctx, key); err != nil {
-			return compute.ListVirtualMachineImageResource{}, err
+			return armcompute.VirtualMachineImagesClientListResponse{}, err
 		}
 	} else {
 		log.Info("VM images cache hit", "location", key.location, "publisher", key.publisher, "offer", key.offer, "sku", key.sku)
 	}
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.data[key], nil
 }
+
+// GetCommunityGalleryImage returns the definition of an image of a community gallery if the given version of it is
+// published in a location, or nil if it isn't. Both outcomes are cached.
+func (c *Cache) GetCommunityGalleryImage(ctx context.Context, location, gallery, name, version string) (*armcompute.CommunityGalleryImage, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachineimages.Cache.GetCommunityGalleryImage")
+	defer done()
+
+	key := communityGalleryKey{
+		location: location,
+		gallery:  gallery,
+		name:     name,
+		version:  version,
+	}
+
+	c.mu.Lock()
+	image, ok := c.communityImages[key]
+	c.mu.Unlock()
+	if ok {
+		log.Info("Community gallery images cache hit", "location", key.location, "gallery", key.gallery, "name", key.name, "version", key.version)
+		return image, nil
+	}
+
+	log.Info("Community gallery images cache miss", "location", key.location, "gallery", key.gallery, "name", key.name, "version", key.version)
+	image, err := c.getCommunityGalleryImage(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.communityImages == nil {
+		c.communityImages = make(map[communityGalleryKey]*armcompute.CommunityGalleryImage)
+	}
+	c.communityImages[key] = image
+
+	return image, nil
+}
+
+// getCommunityGalleryImage fetches the definition of a community gallery image from Azure, or returns nil if the
+// image or its version doesn't exist in the location.
+func (c *Cache) getCommunityGalleryImage(ctx context.Context, key communityGalleryKey) (*armcompute.CommunityGalleryImage, error) {
+	if _, err := c.client.GetCommunityGalleryImageVersion(ctx, key.location, key.gallery, key.name, key.version); err != nil {
+		if azure.ResourceNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get community gallery image version")
+	}
+
+	resp, err := c.client.GetCommunityGalleryImage(ctx, key.location, key.gallery, key.name)
+	if err != nil {
+		if azure.ResourceNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get community gallery image")
+	}
+
+	return &resp.CommunityGalleryImage, nil
+}
@@ -18,14 +18,17 @@ package virtualmachineimages
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages/mock_virtualmachineimages"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 )
 
 func TestCacheGet(t *testing.T) {
@@ -34,22 +37,22 @@ func TestCacheGet(t *testing.T) {
 		publisher     string
 		offer         string
 		sku           string
-		have          compute.ListVirtualMachineImageResource
+		have          armcompute.VirtualMachineImagesClientListResponse
 		expectedError error
 	}{
 		"should find": {
 			location: "test", publisher: "foo", offer: "bar", sku: "baz",
-			have: compute.ListVirtualMachineImageResource{
-				Value: &[]compute.VirtualMachineImageResource{
-					{Name: to.StringPtr("foo")},
+			have: armcompute.VirtualMachineImagesClientListResponse{
+				VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{
+					{Name: to.Ptr("foo")},
 				},
 			},
 			expectedError: nil,
 		},
 		"should not find": {
 			location: "test", publisher: "foo", offer: "bar", sku: "baz",
-			have: compute.ListVirtualMachineImageResource{
-				Value: &[]compute.VirtualMachineImageResource{},
+			have: armcompute.VirtualMachineImagesClientListResponse{
+				VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{},
 			},
 			expectedError: errors.New("failed to refresh VM images cache"),
 		},
@@ -78,3 +81,60 @@ func TestCacheGet(t *testing.T) {
 		})
 	}
 }
+
+func TestCacheGetCommunityGalleryImage(t *testing.T) {
+	cases := map[string]struct {
+		expect        func(m *mock_virtualmachineimages.MockClientMockRecorder)
+		expectedImage *armcompute.CommunityGalleryImage
+		expectedError string
+	}{
+		"should find": {
+			expect: func(m *mock_virtualmachineimages.MockClientMockRecorder) {
+				m.GetCommunityGalleryImageVersion(gomockinternal.AContext(), "test", "gallery", "image", "1.25.3").Return(armcompute.CommunityGalleryImageVersionsClientGetResponse{}, nil)
+				m.GetCommunityGalleryImage(gomockinternal.AContext(), "test", "gallery", "image").Return(armcompute.CommunityGalleryImagesClientGetResponse{
+					CommunityGalleryImage: armcompute.CommunityGalleryImage{Name: to.Ptr("image")},
+				}, nil)
+			},
+			expectedImage: &armcompute.CommunityGalleryImage{Name: to.Ptr("image")},
+		},
+		"should not find a missing version": {
+			expect: func(m *mock_virtualmachineimages.MockClientMockRecorder) {
+				m.GetCommunityGalleryImageVersion(gomockinternal.AContext(), "test", "gallery", "image", "1.25.3").Return(armcompute.CommunityGalleryImageVersionsClientGetResponse{}, test.NewResponseError(http.StatusNotFound))
+			},
+		},
+		"should return other errors": {
+			expect: func(m *mock_virtualmachineimages.MockClientMockRecorder) {
+				m.GetCommunityGalleryImageVersion(gomockinternal.AContext(), "test", "gallery", "image", "1.25.3").Return(armcompute.CommunityGalleryImageVersionsClientGetResponse{}, test.NewResponseError(http.StatusForbidden))
+			},
+			expectedError: "failed to get community gallery image version",
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockClient := mock_virtualmachineimages.NewMockClient(mockCtrl)
+			tc.expect(mockClient.EXPECT())
+			c := &Cache{client: mockClient}
+
+			g := NewWithT(t)
+			image, err := c.GetCommunityGalleryImage(context.Background(), "test", "gallery", "image", "1.25.3")
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.expectedError))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(image).To(Equal(tc.expectedImage))
+
+			// Both outcomes are cached, so the client isn't called again.
+			image, err = c.GetCommunityGalleryImage(context.Background(), "test", "gallery", "image", "1.25.3")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(image).To(Equal(tc.expectedImage))
+		})
+	}
+}
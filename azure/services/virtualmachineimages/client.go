@@ -19,45 +19,74 @@ package virtualmachineimages
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
-// Client is an interface for listing VM images.
+// Client is an interface for listing VM images and getting community gallery images.
 type Client interface {
-	List(ctx context.Context, location, publisher, offer, sku string) (compute.ListVirtualMachineImageResource, error)
+	List(ctx context.Context, location, publisher, offer, sku string) (armcompute.VirtualMachineImagesClientListResponse, error)
+	GetCommunityGalleryImage(ctx context.Context, location, gallery, name string) (armcompute.CommunityGalleryImagesClientGetResponse, error)
+	GetCommunityGalleryImageVersion(ctx context.Context, location, gallery, name, version string) (armcompute.CommunityGalleryImageVersionsClientGetResponse, error)
 }
 
 // AzureClient contains the Azure go-sdk Client.
 type AzureClient struct {
-	images compute.VirtualMachineImagesClient
+	images                 *armcompute.VirtualMachineImagesClient
+	communityImages        *armcompute.CommunityGalleryImagesClient
+	communityImageVersions *armcompute.CommunityGalleryImageVersionsClient
 }
 
 var _ Client = (*AzureClient)(nil)
 
 // NewClient creates a new VM images client from auth info.
-func NewClient(auth azure.Authorizer) *AzureClient {
-	return &AzureClient{
-		images: newVirtualMachineImagesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
 	}
-}
-
-// newVirtualMachineImagesClient creates a new VM images client from subscription ID, base URI and authorizer.
-func newVirtualMachineImagesClient(subscriptionID, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachineImagesClient {
-	c := compute.NewVirtualMachineImagesClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
-	return c
+	c, err := armcompute.NewVirtualMachineImagesClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create VM images client")
+	}
+	communityImages, err := armcompute.NewCommunityGalleryImagesClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create community gallery images client")
+	}
+	communityImageVersions, err := armcompute.NewCommunityGalleryImageVersionsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create community gallery image versions client")
+	}
+	return &AzureClient{
+		images:                 c,
+		communityImages:        communityImages,
+		communityImageVersions: communityImageVersions,
+	}, nil
 }
 
 // List returns a VM image list resource.
-func (ac *AzureClient) List(ctx context.Context, location, publisher, offer, sku string) (compute.ListVirtualMachineImageResource, error) {
+func (ac *AzureClient) List(ctx context.Context, location, publisher, offer, sku string) (armcompute.VirtualMachineImagesClientListResponse, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachineimages.AzureClient.List")
 	defer done()
 
 	// See https://docs.microsoft.com/en-us/odata/concepts/queryoptions-overview for how to use these query options.
-	expand, orderby := "", ""
-	var top *int32
-	return ac.images.List(ctx, location, publisher, offer, sku, expand, top, orderby)
+	return ac.images.List(ctx, location, publisher, offer, sku, nil)
+}
+
+// GetCommunityGalleryImage returns an image definition of a community gallery.
+func (ac *AzureClient) GetCommunityGalleryImage(ctx context.Context, location, gallery, name string) (armcompute.CommunityGalleryImagesClientGetResponse, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachineimages.AzureClient.GetCommunityGalleryImage")
+	defer done()
+
+	return ac.communityImages.Get(ctx, location, gallery, name, nil)
+}
+
+// GetCommunityGalleryImageVersion returns a version of an image definition of a community gallery.
+func (ac *AzureClient) GetCommunityGalleryImageVersion(ctx context.Context, location, gallery, name, version string) (armcompute.CommunityGalleryImageVersionsClientGetResponse, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachineimages.AzureClient.GetCommunityGalleryImageVersion")
+	defer done()
+
+	return ac.communityImageVersions.Get(ctx, location, gallery, name, version, nil)
 }
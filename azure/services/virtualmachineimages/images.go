@@ -22,6 +22,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -33,14 +34,20 @@ import (
 type Service struct {
 	Client
 	azure.Authorizer
+	// HyperVGeneration is the Hyper-V generation of the default images, V1 if empty.
+	HyperVGeneration armcompute.HyperVGenerationTypes
 }
 
 // New creates a new VM Images service.
-func New(auth azure.Authorizer) *Service {
+func New(auth azure.Authorizer) (*Service, error) {
+	client, err := NewClient(auth)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
-		Client:     NewClient(auth),
+		Client:     client,
 		Authorizer: auth,
-	}
+	}, nil
 }
 
 // GetDefaultUbuntuImage returns the default image spec for Ubuntu.
@@ -51,6 +58,14 @@ func (s *Service) GetDefaultUbuntuImage(ctx context.Context, location, k8sVersio
 	}
 
 	osVersion := getUbuntuOSVersion(v.Major, v.Minor, v.Patch)
+	galleryImage, err := s.getDefaultCommunityGalleryImage(ctx, location, fmt.Sprintf("ubun2-%s", osVersion), v)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get default image")
+	}
+	if galleryImage != nil {
+		return galleryImage, nil
+	}
+
 	publisher, offer := azure.DefaultImagePublisherID, azure.DefaultImageOfferID
 	skuID, version, err := s.getSKUAndVersion(
 		ctx, location, publisher, offer, k8sVersion, fmt.Sprintf("ubuntu-%s", osVersion))
@@ -72,6 +87,47 @@ func (s *Service) GetDefaultUbuntuImage(ctx context.Context, location, k8sVersio
 	return defaultImage, nil
 }
 
+// GetDefaultAzureLinuxImage returns the default image spec for Azure Linux.
+func (s *Service) GetDefaultAzureLinuxImage(ctx context.Context, location, k8sVersion string) (*infrav1.Image, error) {
+	v, err := semver.ParseTolerant(k8sVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse Kubernetes version \"%s\"", k8sVersion)
+	}
+
+	// Azure Linux reference images are only published with the SKU naming that doesn't contain the Kubernetes version.
+	if k8sVersionInSKUName(v.Major, v.Minor, v.Patch) {
+		return nil, errors.Errorf("no Azure Linux reference image available for Kubernetes version \"%s\"", k8sVersion)
+	}
+
+	galleryImage, err := s.getDefaultCommunityGalleryImage(ctx, location, azure.DefaultAzureLinuxOsAndVersion, v)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get default image")
+	}
+	if galleryImage != nil {
+		return galleryImage, nil
+	}
+
+	publisher, offer := azure.DefaultImagePublisherID, azure.DefaultImageOfferID
+	skuID, version, err := s.getSKUAndVersion(
+		ctx, location, publisher, offer, k8sVersion, azure.DefaultAzureLinuxOsAndVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get default image")
+	}
+
+	defaultImage := &infrav1.Image{
+		Marketplace: &infrav1.AzureMarketplaceImage{
+			ImagePlan: infrav1.ImagePlan{
+				Publisher: publisher,
+				Offer:     offer,
+				SKU:       skuID,
+			},
+			Version: version,
+		},
+	}
+
+	return defaultImage, nil
+}
+
 // GetDefaultWindowsImage returns the default image spec for Windows.
 func (s *Service) GetDefaultWindowsImage(ctx context.Context, location, k8sVersion, runtime, osAndVersion string) (*infrav1.Image, error) {
 	v122 := semver.MustParse("1.22.0")
@@ -86,7 +142,7 @@ func (s *Service) GetDefaultWindowsImage(ctx context.Context, location, k8sVersi
 	}
 
 	if osAndVersion == "" {
-		osAndVersion = azure.DefaultWindowsOsAndVersion
+		osAndVersion = getWindowsOSVersion(v.Major, v.Minor)
 	}
 
 	// Starting with 1.22 we default to containerd for Windows unless the runtime flag is set.
@@ -94,6 +150,14 @@ func (s *Service) GetDefaultWindowsImage(ctx context.Context, location, k8sVersi
 		osAndVersion += "-containerd"
 	}
 
+	galleryImage, err := s.getDefaultCommunityGalleryImage(ctx, location, strings.Replace(osAndVersion, "windows-", "win-", 1), v)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get default image")
+	}
+	if galleryImage != nil {
+		return galleryImage, nil
+	}
+
 	publisher, offer := azure.DefaultImagePublisherID, azure.DefaultWindowsImageOfferID
 	skuID, version, err := s.getSKUAndVersion(
 		ctx, location, publisher, offer, k8sVersion, osAndVersion)
@@ -115,6 +179,57 @@ func (s *Service) GetDefaultWindowsImage(ctx context.Context, location, k8sVersi
 	return defaultImage, nil
 }
 
+// getDefaultCommunityGalleryImage returns the reference image of the community gallery for the given OS and version of
+// Kubernetes, e.g. "ubun2-2204", or nil if that gallery has no such image in the location for the Hyper-V generation of
+// the service, in which case the reference image is picked from the Azure Marketplace instead.
+func (s *Service) getDefaultCommunityGalleryImage(ctx context.Context, location, osAndVersion string, k8sVersion semver.Version) (*infrav1.Image, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachineimages.Service.getDefaultCommunityGalleryImage")
+	defer done()
+
+	// Community galleries are only available in the public cloud, and only have images for the Kubernetes versions
+	// released after the SKUs stopped containing the Kubernetes version.
+	if k8sVersionInSKUName(k8sVersion.Major, k8sVersion.Minor, k8sVersion.Patch) || s.CloudEnvironment() != azure.PublicCloud.Name {
+		return nil, nil
+	}
+
+	gallery, name := azure.DefaultPublicGalleryName, azure.DefaultGalleryImagePrefix+osAndVersion
+	version := fmt.Sprintf("%d.%d.%d", k8sVersion.Major, k8sVersion.Minor, k8sVersion.Patch)
+
+	imageCache, err := GetCache(s.Authorizer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get image cache")
+	}
+	imageCache.client = s.Client
+
+	image, err := imageCache.GetCommunityGalleryImage(ctx, location, gallery, name, version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get community gallery image \"%s\" version \"%s\"", name, version)
+	}
+	if image == nil {
+		log.Info("No community gallery image found, falling back to the Azure Marketplace", "location", location, "gallery", gallery, "name", name, "version", version)
+		return nil, nil
+	}
+
+	generation := armcompute.HyperVGenerationTypesV1
+	if s.HyperVGeneration != "" {
+		generation = s.HyperVGeneration
+	}
+	if image.Properties != nil && image.Properties.HyperVGeneration != nil && string(*image.Properties.HyperVGeneration) != string(generation) {
+		log.Info("Community gallery image has another Hyper-V generation, falling back to the Azure Marketplace", "location", location, "gallery", gallery, "name", name, "hyperVGeneration", generation)
+		return nil, nil
+	}
+
+	log.Info("Found community gallery image", "location", location, "gallery", gallery, "name", name, "version", version)
+
+	return &infrav1.Image{
+		ComputeGallery: &infrav1.AzureComputeGalleryImage{
+			Gallery: gallery,
+			Name:    name,
+			Version: version,
+		},
+	}, nil
+}
+
 // getSKUAndVersion gets the SKU ID and version of the image to use for the provided version of Kubernetes.
 // note: osAndVersion is expected to be in the format of {os}-{version} (ex: ubuntu-2004 or windows-2022)
 func (s *Service) getSKUAndVersion(ctx context.Context, location, publisher, offer, k8sVersion, osAndVersion string) (string, string, error) {
@@ -128,34 +243,40 @@ func (s *Service) getSKUAndVersion(ctx context.Context, location, publisher, off
 		return "", "", errors.Wrapf(err, "unable to parse Kubernetes version \"%s\" in spec, expected valid SemVer string", k8sVersion)
 	}
 
-	// Old SKUs before 1.21.12, 1.22.9, or 1.23.6 are named like "k8s-1dot21dot2-ubuntu-2004".
+	// Old SKUs before 1.21.12, 1.22.9, or 1.23.6 are named like "k8s-1dot21dot2-ubuntu-2004" and are generation 1 only.
 	if k8sVersionInSKUName(v.Major, v.Minor, v.Patch) {
+		if s.HyperVGeneration == armcompute.HyperVGenerationTypesV2 {
+			return "", "", errors.Errorf("no HyperVGeneration V2 reference image available for Kubernetes version \"%s\"", k8sVersion)
+		}
 		return fmt.Sprintf("k8s-%ddot%ddot%d-%s", v.Major, v.Minor, v.Patch, osAndVersion), azure.LatestVersion, nil
 	}
 
-	// New SKUs don't contain the Kubernetes version and are named like "ubuntu-2004-gen1".
+	// New SKUs don't contain the Kubernetes version and are named like "ubuntu-2004-gen1" or "ubuntu-2004-gen2".
 	sku := fmt.Sprintf("%s-gen1", osAndVersion)
+	if s.HyperVGeneration == armcompute.HyperVGenerationTypesV2 {
+		sku = fmt.Sprintf("%s-gen2", osAndVersion)
+	}
 
 	imageCache, err := GetCache(s.Authorizer)
-	imageCache.client = s.Client
 	if err != nil {
 		return "", "", errors.Wrap(err, "failed to get image cache")
 	}
+	imageCache.client = s.Client
 
 	listVMImagesResource, err := imageCache.Get(ctx, location, publisher, offer, sku)
 	if err != nil {
 		return "", "", errors.Wrapf(err, "unable to list VM images for publisher \"%s\" offer \"%s\" sku \"%s\"", publisher, offer, sku)
 	}
 
-	vmImages := listVMImagesResource.Value
-	if vmImages == nil || len(*vmImages) == 0 {
+	vmImages := listVMImagesResource.VirtualMachineImageResourceArray
+	if len(vmImages) == 0 {
 		return "", "", errors.Errorf("no VM images found for publisher \"%s\" offer \"%s\" sku \"%s\"", publisher, offer, sku)
 	}
 
 	// Sort the VM image names descending, so more recent dates sort first.
 	// (The date is encoded into the end of the name, for example "124.0.20220512").
 	names := []string{}
-	for _, vmImage := range *vmImages {
+	for _, vmImage := range vmImages {
 		names = append(names, *vmImage.Name)
 	}
 	sort.Sort(sort.Reverse(sort.StringSlice(names)))
@@ -180,6 +301,11 @@ func (s *Service) getSKUAndVersion(ctx context.Context, location, publisher, off
 
 // getUbuntuOSVersion returns the default Ubuntu OS version for the given Kubernetes version.
 func getUbuntuOSVersion(major, minor, patch uint64) string {
+	// Reference images for k8s 1.25 and later are built on Ubuntu 22.04 LTS.
+	if major > 1 || (major == 1 && minor >= 25) {
+		return "2204"
+	}
+
 	// Default to Ubuntu 20.04 LTS, except for k8s versions which have only 18.04 reference images.
 	osVersion := "2004"
 	if (major == 1 && minor == 21 && patch < 2) ||
@@ -192,6 +318,15 @@ func getUbuntuOSVersion(major, minor, patch uint64) string {
 	return osVersion
 }
 
+// getWindowsOSVersion returns the default Windows Server version for the given Kubernetes version.
+func getWindowsOSVersion(major, minor uint64) string {
+	// Reference images for k8s 1.25 and later default to Windows Server 2022.
+	if major > 1 || (major == 1 && minor >= 25) {
+		return azure.DefaultWindows2022OsAndVersion
+	}
+	return azure.DefaultWindowsOsAndVersion
+}
+
 // k8sVersionInSKUName returns true if the k8s version is in the SKU name (the older style of naming).
 func k8sVersionInSKUName(major, minor, patch uint64) bool {
 	return (major == 1 && minor < 21) ||
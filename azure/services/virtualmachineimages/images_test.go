@@ -18,16 +18,20 @@ package virtualmachineimages
 
 import (
 	"context"
+	"net/http"
 	"strings"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/blang/semver"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachineimages/mock_virtualmachineimages"
+	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
 )
 
 func TestGetDefaultUbuntuImage(t *testing.T) {
@@ -35,7 +39,7 @@ func TestGetDefaultUbuntuImage(t *testing.T) {
 		k8sVersion      string
 		expectedSKU     string
 		expectedVersion string
-		versions        compute.ListVirtualMachineImageResource
+		versions        armcompute.VirtualMachineImagesClientListResponse
 	}{
 		{
 			k8sVersion:      "v1.15.6",
@@ -86,11 +90,11 @@ func TestGetDefaultUbuntuImage(t *testing.T) {
 			k8sVersion:      "v1.21.13",
 			expectedSKU:     "ubuntu-2004-gen1",
 			expectedVersion: "121.13.20220613",
-			versions: compute.ListVirtualMachineImageResource{
-				Value: &[]compute.VirtualMachineImageResource{
-					{Name: to.StringPtr("121.13.20220526")},
-					{Name: to.StringPtr("121.13.20220613")},
-					{Name: to.StringPtr("121.13.20220524")},
+			versions: armcompute.VirtualMachineImagesClientListResponse{
+				VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{
+					{Name: to.Ptr("121.13.20220526")},
+					{Name: to.Ptr("121.13.20220613")},
+					{Name: to.Ptr("121.13.20220524")},
 				},
 			},
 		},
@@ -108,10 +112,10 @@ func TestGetDefaultUbuntuImage(t *testing.T) {
 			k8sVersion:      "v1.22.10",
 			expectedSKU:     "ubuntu-2004-gen1",
 			expectedVersion: "122.10.20220613",
-			versions: compute.ListVirtualMachineImageResource{
-				Value: &[]compute.VirtualMachineImageResource{
-					{Name: to.StringPtr("122.10.20220524")},
-					{Name: to.StringPtr("122.10.20220613")},
+			versions: armcompute.VirtualMachineImagesClientListResponse{
+				VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{
+					{Name: to.Ptr("122.10.20220524")},
+					{Name: to.Ptr("122.10.20220613")},
 				},
 			},
 		},
@@ -124,12 +128,12 @@ func TestGetDefaultUbuntuImage(t *testing.T) {
 			k8sVersion:      "v1.23.7",
 			expectedSKU:     "ubuntu-2004-gen1",
 			expectedVersion: "123.7.20231231",
-			versions: compute.ListVirtualMachineImageResource{
-				Value: &[]compute.VirtualMachineImageResource{
-					{Name: to.StringPtr("123.7.20221124")},
-					{Name: to.StringPtr("123.7.20220524")},
-					{Name: to.StringPtr("123.7.20231231")},
-					{Name: to.StringPtr("123.7.20220818")},
+			versions: armcompute.VirtualMachineImagesClientListResponse{
+				VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{
+					{Name: to.Ptr("123.7.20221124")},
+					{Name: to.Ptr("123.7.20220524")},
+					{Name: to.Ptr("123.7.20231231")},
+					{Name: to.Ptr("123.7.20220818")},
 				},
 			},
 		},
@@ -137,9 +141,20 @@ func TestGetDefaultUbuntuImage(t *testing.T) {
 			k8sVersion:      "v1.24.0",
 			expectedSKU:     "ubuntu-2004-gen1",
 			expectedVersion: "124.0.20220512",
-			versions: compute.ListVirtualMachineImageResource{
-				Value: &[]compute.VirtualMachineImageResource{
-					{Name: to.StringPtr("124.0.20220512")},
+			versions: armcompute.VirtualMachineImagesClientListResponse{
+				VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{
+					{Name: to.Ptr("124.0.20220512")},
+				},
+			},
+		},
+		{
+			k8sVersion:      "v1.25.2",
+			expectedSKU:     "ubuntu-2204-gen1",
+			expectedVersion: "125.2.20221012",
+			versions: armcompute.VirtualMachineImagesClientListResponse{
+				VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{
+					{Name: to.Ptr("125.2.20220930")},
+					{Name: to.Ptr("125.2.20221012")},
 				},
 			},
 		},
@@ -155,13 +170,13 @@ func TestGetDefaultUbuntuImage(t *testing.T) {
 			defer mockCtrl.Finish()
 			mockAuth := mock_azure.NewMockAuthorizer(mockCtrl)
 			mockAuth.EXPECT().HashKey().Return(t.Name()).AnyTimes()
-			mockAuth.EXPECT().Authorizer().AnyTimes()
-			mockAuth.EXPECT().BaseURI().AnyTimes()
+			mockAuth.EXPECT().Token().AnyTimes()
+			mockAuth.EXPECT().CloudEnvironment().AnyTimes()
 			mockAuth.EXPECT().SubscriptionID().AnyTimes()
 			mockClient := mock_virtualmachineimages.NewMockClient(mockCtrl)
 			svc := Service{Client: mockClient, Authorizer: mockAuth}
 
-			if test.versions.Value != nil {
+			if test.versions.VirtualMachineImageResourceArray != nil {
 				mockClient.EXPECT().
 					List(gomock.Any(), location, azure.DefaultImagePublisherID, azure.DefaultImageOfferID, gomock.Any()).
 					Return(test.versions, nil)
@@ -176,6 +191,305 @@ func TestGetDefaultUbuntuImage(t *testing.T) {
 	}
 }
 
+func TestGetDefaultAzureLinuxImage(t *testing.T) {
+	tests := []struct {
+		name            string
+		k8sVersion      string
+		expectedSKU     string
+		expectedVersion string
+		expectedErr     string
+		versions        armcompute.VirtualMachineImagesClientListResponse
+	}{
+		{
+			name:            "new SKU naming",
+			k8sVersion:      "v1.24.6",
+			expectedSKU:     "azurelinux-2-gen1",
+			expectedVersion: "124.6.20221012",
+			versions: armcompute.VirtualMachineImagesClientListResponse{
+				VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{
+					{Name: to.Ptr("124.6.20220930")},
+					{Name: to.Ptr("124.6.20221012")},
+				},
+			},
+		},
+		{
+			name:        "old SKU naming is not supported",
+			k8sVersion:  "v1.22.9",
+			expectedErr: "no Azure Linux reference image available for Kubernetes version \"v1.22.9\"",
+		},
+	}
+
+	location := "westus3"
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockAuth := mock_azure.NewMockAuthorizer(mockCtrl)
+			mockAuth.EXPECT().HashKey().Return(t.Name()).AnyTimes()
+			mockAuth.EXPECT().Token().AnyTimes()
+			mockAuth.EXPECT().CloudEnvironment().AnyTimes()
+			mockAuth.EXPECT().SubscriptionID().AnyTimes()
+			mockClient := mock_virtualmachineimages.NewMockClient(mockCtrl)
+			svc := Service{Client: mockClient, Authorizer: mockAuth}
+
+			if test.versions.VirtualMachineImageResourceArray != nil {
+				mockClient.EXPECT().
+					List(gomock.Any(), location, azure.DefaultImagePublisherID, azure.DefaultImageOfferID, test.expectedSKU).
+					Return(test.versions, nil)
+			}
+			image, err := svc.GetDefaultAzureLinuxImage(context.TODO(), location, test.k8sVersion)
+
+			g := NewWithT(t)
+			if test.expectedErr != "" {
+				g.Expect(err).To(MatchError(test.expectedErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(image.Marketplace.Version).To(Equal(test.expectedVersion))
+			g.Expect(image.Marketplace.SKU).To(Equal(test.expectedSKU))
+		})
+	}
+}
+
+func TestGetDefaultImageHyperVGeneration(t *testing.T) {
+	tests := []struct {
+		name             string
+		k8sVersion       string
+		hyperVGeneration armcompute.HyperVGenerationTypes
+		expectedSKU      string
+		expectedErr      string
+	}{
+		{
+			name:        "generation 1 by default",
+			k8sVersion:  "v1.24.6",
+			expectedSKU: "ubuntu-2004-gen1",
+		},
+		{
+			name:             "generation 2",
+			k8sVersion:       "v1.24.6",
+			hyperVGeneration: armcompute.HyperVGenerationTypesV2,
+			expectedSKU:      "ubuntu-2004-gen2",
+		},
+		{
+			name:             "old SKU naming has no generation 2 images",
+			k8sVersion:       "v1.22.9",
+			hyperVGeneration: armcompute.HyperVGenerationTypesV2,
+			expectedErr:      "failed to get default image: no HyperVGeneration V2 reference image available for Kubernetes version \"v1.22.9\"",
+		},
+	}
+
+	location := "westus3"
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockAuth := mock_azure.NewMockAuthorizer(mockCtrl)
+			mockAuth.EXPECT().HashKey().Return(t.Name()).AnyTimes()
+			mockAuth.EXPECT().Token().AnyTimes()
+			mockAuth.EXPECT().CloudEnvironment().AnyTimes()
+			mockAuth.EXPECT().SubscriptionID().AnyTimes()
+			mockClient := mock_virtualmachineimages.NewMockClient(mockCtrl)
+			svc := Service{Client: mockClient, Authorizer: mockAuth, HyperVGeneration: test.hyperVGeneration}
+
+			if test.expectedSKU != "" {
+				mockClient.EXPECT().
+					List(gomock.Any(), location, azure.DefaultImagePublisherID, azure.DefaultImageOfferID, test.expectedSKU).
+					Return(armcompute.VirtualMachineImagesClientListResponse{
+						VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{
+							{Name: to.Ptr("124.6.20221012")},
+						},
+					}, nil)
+			}
+			image, err := svc.GetDefaultUbuntuImage(context.TODO(), location, test.k8sVersion)
+
+			g := NewWithT(t)
+			if test.expectedErr != "" {
+				g.Expect(err).To(MatchError(test.expectedErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(image.Marketplace.SKU).To(Equal(test.expectedSKU))
+		})
+	}
+}
+
+func TestGetDefaultImageCommunityGallery(t *testing.T) {
+	gen1, gen2 := armcompute.HyperVGenerationV1, armcompute.HyperVGenerationV2
+	notFound := test.NewResponseError(http.StatusNotFound)
+
+	tests := []struct {
+		name             string
+		cloud            string
+		hyperVGeneration armcompute.HyperVGenerationTypes
+		getImage         func(svc *Service) (*infrav1.Image, error)
+		galleryImage     string
+		galleryVersion   string
+		versionErr       error
+		imageGeneration  *armcompute.HyperVGeneration
+		expectedImage    *infrav1.Image
+		expectedSKU      string
+		expectedErr      string
+	}{
+		{
+			name:  "Ubuntu image from the community gallery",
+			cloud: azure.PublicCloud.Name,
+			getImage: func(svc *Service) (*infrav1.Image, error) {
+				return svc.GetDefaultUbuntuImage(context.TODO(), "westus3", "v1.25.3")
+			},
+			galleryImage:    "capi-ubun2-2204",
+			galleryVersion:  "1.25.3",
+			imageGeneration: &gen1,
+			expectedImage: &infrav1.Image{ComputeGallery: &infrav1.AzureComputeGalleryImage{
+				Gallery: azure.DefaultPublicGalleryName, Name: "capi-ubun2-2204", Version: "1.25.3",
+			}},
+		},
+		{
+			name:  "Azure Linux image from the community gallery",
+			cloud: azure.PublicCloud.Name,
+			getImage: func(svc *Service) (*infrav1.Image, error) {
+				return svc.GetDefaultAzureLinuxImage(context.TODO(), "westus3", "v1.24.6")
+			},
+			galleryImage:    "capi-azurelinux-2",
+			galleryVersion:  "1.24.6",
+			imageGeneration: &gen1,
+			expectedImage: &infrav1.Image{ComputeGallery: &infrav1.AzureComputeGalleryImage{
+				Gallery: azure.DefaultPublicGalleryName, Name: "capi-azurelinux-2", Version: "1.24.6",
+			}},
+		},
+		{
+			name:  "Windows image from the community gallery",
+			cloud: azure.PublicCloud.Name,
+			getImage: func(svc *Service) (*infrav1.Image, error) {
+				return svc.GetDefaultWindowsImage(context.TODO(), "westus3", "v1.25.3", "", "")
+			},
+			galleryImage:    "capi-win-2022-containerd",
+			galleryVersion:  "1.25.3",
+			imageGeneration: &gen1,
+			expectedImage: &infrav1.Image{ComputeGallery: &infrav1.AzureComputeGalleryImage{
+				Gallery: azure.DefaultPublicGalleryName, Name: "capi-win-2022-containerd", Version: "1.25.3",
+			}},
+		},
+		{
+			name:             "generation 2 image from the community gallery",
+			cloud:            azure.PublicCloud.Name,
+			hyperVGeneration: armcompute.HyperVGenerationTypesV2,
+			getImage: func(svc *Service) (*infrav1.Image, error) {
+				return svc.GetDefaultUbuntuImage(context.TODO(), "westus3", "v1.25.3")
+			},
+			galleryImage:    "capi-ubun2-2204",
+			galleryVersion:  "1.25.3",
+			imageGeneration: &gen2,
+			expectedImage: &infrav1.Image{ComputeGallery: &infrav1.AzureComputeGalleryImage{
+				Gallery: azure.DefaultPublicGalleryName, Name: "capi-ubun2-2204", Version: "1.25.3",
+			}},
+		},
+		{
+			name:  "falls back to the marketplace when the version isn't in the community gallery",
+			cloud: azure.PublicCloud.Name,
+			getImage: func(svc *Service) (*infrav1.Image, error) {
+				return svc.GetDefaultUbuntuImage(context.TODO(), "westus3", "v1.25.3")
+			},
+			galleryImage:   "capi-ubun2-2204",
+			galleryVersion: "1.25.3",
+			versionErr:     notFound,
+			expectedSKU:    "ubuntu-2204-gen1",
+		},
+		{
+			name:             "falls back to the marketplace when the community gallery image has another generation",
+			cloud:            azure.PublicCloud.Name,
+			hyperVGeneration: armcompute.HyperVGenerationTypesV2,
+			getImage: func(svc *Service) (*infrav1.Image, error) {
+				return svc.GetDefaultUbuntuImage(context.TODO(), "westus3", "v1.25.3")
+			},
+			galleryImage:    "capi-ubun2-2204",
+			galleryVersion:  "1.25.3",
+			imageGeneration: &gen1,
+			expectedSKU:     "ubuntu-2204-gen2",
+		},
+		{
+			name:  "doesn't use the community gallery outside of the public cloud",
+			cloud: azure.USGovernmentCloud.Name,
+			getImage: func(svc *Service) (*infrav1.Image, error) {
+				return svc.GetDefaultUbuntuImage(context.TODO(), "westus3", "v1.25.3")
+			},
+			expectedSKU: "ubuntu-2204-gen1",
+		},
+		{
+			name:  "returns community gallery errors",
+			cloud: azure.PublicCloud.Name,
+			getImage: func(svc *Service) (*infrav1.Image, error) {
+				return svc.GetDefaultUbuntuImage(context.TODO(), "westus3", "v1.25.3")
+			},
+			galleryImage:   "capi-ubun2-2204",
+			galleryVersion: "1.25.3",
+			versionErr:     test.NewResponseError(http.StatusInternalServerError),
+			expectedErr:    "failed to get default image: unable to get community gallery image \"capi-ubun2-2204\" version \"1.25.3\"",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockAuth := mock_azure.NewMockAuthorizer(mockCtrl)
+			mockAuth.EXPECT().HashKey().Return(t.Name()).AnyTimes()
+			mockAuth.EXPECT().Token().AnyTimes()
+			mockAuth.EXPECT().CloudEnvironment().Return(test.cloud).AnyTimes()
+			mockAuth.EXPECT().SubscriptionID().AnyTimes()
+			mockClient := mock_virtualmachineimages.NewMockClient(mockCtrl)
+			svc := &Service{Client: mockClient, Authorizer: mockAuth, HyperVGeneration: test.hyperVGeneration}
+
+			if test.galleryImage != "" {
+				mockClient.EXPECT().
+					GetCommunityGalleryImageVersion(gomock.Any(), "westus3", azure.DefaultPublicGalleryName, test.galleryImage, test.galleryVersion).
+					Return(armcompute.CommunityGalleryImageVersionsClientGetResponse{}, test.versionErr)
+				if test.versionErr == nil {
+					mockClient.EXPECT().
+						GetCommunityGalleryImage(gomock.Any(), "westus3", azure.DefaultPublicGalleryName, test.galleryImage).
+						Return(armcompute.CommunityGalleryImagesClientGetResponse{
+							CommunityGalleryImage: armcompute.CommunityGalleryImage{
+								Properties: &armcompute.CommunityGalleryImageProperties{HyperVGeneration: test.imageGeneration},
+							},
+						}, nil)
+				}
+			}
+			if test.expectedSKU != "" {
+				mockClient.EXPECT().
+					List(gomock.Any(), "westus3", azure.DefaultImagePublisherID, azure.DefaultImageOfferID, test.expectedSKU).
+					Return(armcompute.VirtualMachineImagesClientListResponse{
+						VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{
+							{Name: to.Ptr("125.3.20221012")},
+						},
+					}, nil)
+			}
+
+			g := NewWithT(t)
+			image, err := test.getImage(svc)
+			if test.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(HavePrefix(test.expectedErr))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if test.expectedImage != nil {
+				g.Expect(image).To(Equal(test.expectedImage))
+			} else {
+				g.Expect(image.Marketplace.SKU).To(Equal(test.expectedSKU))
+				g.Expect(image.Marketplace.Version).To(Equal("125.3.20221012"))
+			}
+		})
+	}
+}
+
 func TestGetDefaultWindowsImage(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -279,7 +593,7 @@ func TestGetDefaultImageSKUID(t *testing.T) {
 		expectedSKU     string
 		expectedVersion string
 		expectedError   bool
-		versions        compute.ListVirtualMachineImageResource
+		versions        armcompute.VirtualMachineImagesClientListResponse
 	}{
 		{
 			k8sVersion:      "v1.14.9",
@@ -362,19 +676,19 @@ func TestGetDefaultImageSKUID(t *testing.T) {
 			expectedVersion: "121.13.20300524",
 			expectedError:   false,
 			osAndVersion:    "windows-2022",
-			versions: compute.ListVirtualMachineImageResource{
-				Value: &[]compute.VirtualMachineImageResource{
-					{Name: to.StringPtr("121.13.20220524")},
-					{Name: to.StringPtr("124.0.20220512")},
-					{Name: to.StringPtr("121.13.20220524")},
-					{Name: to.StringPtr("123.13.20220524")},
-					{Name: to.StringPtr("121.13.20220619")},
-					{Name: to.StringPtr("121.13.20300524")},
-					{Name: to.StringPtr("121.14.20220524")},
-					{Name: to.StringPtr("121.12.20220524")},
-					{Name: to.StringPtr("121.13.20220101")},
-					{Name: to.StringPtr("121.13.20231231")},
-					{Name: to.StringPtr("121.13.19991231")},
+			versions: armcompute.VirtualMachineImagesClientListResponse{
+				VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{
+					{Name: to.Ptr("121.13.20220524")},
+					{Name: to.Ptr("124.0.20220512")},
+					{Name: to.Ptr("121.13.20220524")},
+					{Name: to.Ptr("123.13.20220524")},
+					{Name: to.Ptr("121.13.20220619")},
+					{Name: to.Ptr("121.13.20300524")},
+					{Name: to.Ptr("121.14.20220524")},
+					{Name: to.Ptr("121.12.20220524")},
+					{Name: to.Ptr("121.13.20220101")},
+					{Name: to.Ptr("121.13.20231231")},
+					{Name: to.Ptr("121.13.19991231")},
 				},
 			},
 		},
@@ -398,9 +712,9 @@ func TestGetDefaultImageSKUID(t *testing.T) {
 			expectedVersion: "123.13.20220524",
 			expectedError:   false,
 			osAndVersion:    "ubuntu-2004",
-			versions: compute.ListVirtualMachineImageResource{
-				Value: &[]compute.VirtualMachineImageResource{
-					{Name: to.StringPtr("123.13.20220524")},
+			versions: armcompute.VirtualMachineImagesClientListResponse{
+				VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{
+					{Name: to.Ptr("123.13.20220524")},
 				},
 			},
 		},
@@ -408,8 +722,8 @@ func TestGetDefaultImageSKUID(t *testing.T) {
 			k8sVersion:    "v1.23.13",
 			expectedError: true,
 			osAndVersion:  "ubuntu-2004",
-			versions: compute.ListVirtualMachineImageResource{
-				Value: &[]compute.VirtualMachineImageResource{},
+			versions: armcompute.VirtualMachineImagesClientListResponse{
+				VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{},
 			},
 		},
 		{
@@ -418,9 +732,9 @@ func TestGetDefaultImageSKUID(t *testing.T) {
 			expectedVersion: "124.0.20220512",
 			expectedError:   false,
 			osAndVersion:    "ubuntu-2004",
-			versions: compute.ListVirtualMachineImageResource{
-				Value: &[]compute.VirtualMachineImageResource{
-					{Name: to.StringPtr("124.0.20220512")},
+			versions: armcompute.VirtualMachineImagesClientListResponse{
+				VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{
+					{Name: to.Ptr("124.0.20220512")},
 				},
 			},
 		},
@@ -430,9 +744,9 @@ func TestGetDefaultImageSKUID(t *testing.T) {
 			expectedVersion: "124.0.20220606",
 			expectedError:   false,
 			osAndVersion:    "windows-2022-containerd",
-			versions: compute.ListVirtualMachineImageResource{
-				Value: &[]compute.VirtualMachineImageResource{
-					{Name: to.StringPtr("124.0.20220606")},
+			versions: armcompute.VirtualMachineImagesClientListResponse{
+				VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{
+					{Name: to.Ptr("124.0.20220606")},
 				},
 			},
 		},
@@ -440,9 +754,9 @@ func TestGetDefaultImageSKUID(t *testing.T) {
 			k8sVersion:    "v1.24.1",
 			expectedError: true,
 			osAndVersion:  "windows-2022-containerd",
-			versions: compute.ListVirtualMachineImageResource{
-				Value: &[]compute.VirtualMachineImageResource{
-					{Name: to.StringPtr("124.0.20220606")},
+			versions: armcompute.VirtualMachineImagesClientListResponse{
+				VirtualMachineImageResourceArray: []*armcompute.VirtualMachineImageResource{
+					{Name: to.Ptr("124.0.20220606")},
 				},
 			},
 		},
@@ -458,8 +772,8 @@ func TestGetDefaultImageSKUID(t *testing.T) {
 			defer mockCtrl.Finish()
 			mockAuth := mock_azure.NewMockAuthorizer(mockCtrl)
 			mockAuth.EXPECT().HashKey().Return(t.Name()).AnyTimes()
-			mockAuth.EXPECT().Authorizer().AnyTimes()
-			mockAuth.EXPECT().BaseURI().AnyTimes()
+			mockAuth.EXPECT().Token().AnyTimes()
+			mockAuth.EXPECT().CloudEnvironment().AnyTimes()
 			mockAuth.EXPECT().SubscriptionID().AnyTimes()
 			mockClient := mock_virtualmachineimages.NewMockClient(mockCtrl)
 			svc := Service{Client: mockClient, Authorizer: mockAuth}
@@ -468,7 +782,7 @@ func TestGetDefaultImageSKUID(t *testing.T) {
 			if strings.HasPrefix(test.osAndVersion, "windows") {
 				offer = azure.DefaultWindowsImageOfferID
 			}
-			if test.versions.Value != nil {
+			if test.versions.VirtualMachineImageResourceArray != nil {
 				mockClient.EXPECT().
 					List(gomock.Any(), location, azure.DefaultImagePublisherID, offer, gomock.Any()).
 					Return(test.versions, nil)
@@ -487,3 +801,36 @@ func TestGetDefaultImageSKUID(t *testing.T) {
 		})
 	}
 }
+
+func TestGetWindowsOSVersion(t *testing.T) {
+	tests := []struct {
+		k8sVersion string
+		expected   string
+	}{
+		{
+			k8sVersion: "v1.21.4",
+			expected:   "windows-2019",
+		},
+		{
+			k8sVersion: "v1.24.6",
+			expected:   "windows-2019",
+		},
+		{
+			k8sVersion: "v1.25.0",
+			expected:   "windows-2022",
+		},
+		{
+			k8sVersion: "v1.26.1",
+			expected:   "windows-2022",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.k8sVersion, func(t *testing.T) {
+			g := NewWithT(t)
+			v := semver.MustParse(strings.TrimPrefix(test.k8sVersion, "v"))
+			g.Expect(getWindowsOSVersion(v.Major, v.Minor)).To(Equal(test.expected))
+		})
+	}
+}
@@ -24,7 +24,7 @@ import (
 	context "context"
 	reflect "reflect"
 
-	compute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	armcompute "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -51,11 +51,41 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// GetCommunityGalleryImage mocks base method.
+func (m *MockClient) GetCommunityGalleryImage(ctx context.Context, location, gallery, name string) (armcompute.CommunityGalleryImagesClientGetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommunityGalleryImage", ctx, location, gallery, name)
+	ret0, _ := ret[0].(armcompute.CommunityGalleryImagesClientGetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommunityGalleryImage indicates an expected call of GetCommunityGalleryImage.
+func (mr *MockClientMockRecorder) GetCommunityGalleryImage(ctx, location, gallery, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommunityGalleryImage", reflect.TypeOf((*MockClient)(nil).GetCommunityGalleryImage), ctx, location, gallery, name)
+}
+
+// GetCommunityGalleryImageVersion mocks base method.
+func (m *MockClient) GetCommunityGalleryImageVersion(ctx context.Context, location, gallery, name, version string) (armcompute.CommunityGalleryImageVersionsClientGetResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommunityGalleryImageVersion", ctx, location, gallery, name, version)
+	ret0, _ := ret[0].(armcompute.CommunityGalleryImageVersionsClientGetResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommunityGalleryImageVersion indicates an expected call of GetCommunityGalleryImageVersion.
+func (mr *MockClientMockRecorder) GetCommunityGalleryImageVersion(ctx, location, gallery, name, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommunityGalleryImageVersion", reflect.TypeOf((*MockClient)(nil).GetCommunityGalleryImageVersion), ctx, location, gallery, name, version)
+}
+
 // List mocks base method.
-func (m *MockClient) List(ctx context.Context, location, publisher, offer, sku string) (compute.ListVirtualMachineImageResource, error) {
+func (m *MockClient) List(ctx context.Context, location, publisher, offer, sku string) (armcompute.VirtualMachineImagesClientListResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "List", ctx, location, publisher, offer, sku)
-	ret0, _ := ret[0].(compute.ListVirtualMachineImageResource)
+	ret0, _ := ret[0].(armcompute.VirtualMachineImagesClientListResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
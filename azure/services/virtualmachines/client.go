@@ -18,35 +18,43 @@ package virtualmachines
 
 import (
 	"context"
-	"encoding/json"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest"
-	azureautorest "github.com/Azure/go-autorest/autorest/azure"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
-	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// Client wraps go-sdk.
+type Client interface {
+	Start(ctx context.Context, resourceGroupName, vmName string) error
+	Deallocate(ctx context.Context, resourceGroupName, vmName string) error
+	Hibernate(ctx context.Context, resourceGroupName, vmName string) error
+	UpdateDataDiskDeleteOptions(ctx context.Context, resourceGroupName, vmName string, deleteOptions map[int32]armcompute.DiskDeleteOptionTypes) (bool, error)
+}
+
 // AzureClient contains the Azure go-sdk Client.
 type AzureClient struct {
-	virtualmachines compute.VirtualMachinesClient
+	virtualmachines *armcompute.VirtualMachinesClient
 }
 
-// NewClient creates a new VM client from subscription ID.
-func NewClient(auth azure.Authorizer) *AzureClient {
-	c := newVirtualMachinesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
-	return &AzureClient{c}
-}
+var _ Client = &AzureClient{}
 
-// newVirtualMachinesClient creates a new VM client from subscription ID.
-func newVirtualMachinesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachinesClient {
-	vmClient := compute.NewVirtualMachinesClientWithBaseURI(baseURI, subscriptionID)
-	azure.SetAutoRestClientDefaults(&vmClient.Client, authorizer)
-	return vmClient
+// NewClient creates a new VM client from subscription ID.
+func NewClient(auth azure.Authorizer) (*AzureClient, error) {
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+	c, err := armcompute.NewVirtualMachinesClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create virtual machines client")
+	}
+	return &AzureClient{c}, nil
 }
 
 // Get retrieves information about the model view or the instance view of a virtual machine.
@@ -54,22 +62,101 @@ func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Get")
 	defer done()
 
-	return ac.virtualmachines.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), "")
+	opts := &armcompute.VirtualMachinesClientGetOptions{Expand: to.Ptr(armcompute.InstanceViewTypesInstanceView)}
+	resp, err := ac.virtualmachines.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
+	if err != nil {
+		return nil, err
+	}
+	return resp.VirtualMachine, nil
+}
+
+// Start starts a stopped or deallocated virtual machine.
+// It does not wait for the virtual machine to be running, the power state is observed by the following reconciliations.
+func (ac *AzureClient) Start(ctx context.Context, resourceGroupName, vmName string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Start")
+	defer done()
+
+	_, err := ac.virtualmachines.BeginStart(ctx, resourceGroupName, vmName, nil)
+	return err
+}
+
+// Deallocate stops a virtual machine and releases its compute resources, keeping its disks and network interfaces.
+// It does not wait for the virtual machine to be deallocated, the power state is observed by the following reconciliations.
+func (ac *AzureClient) Deallocate(ctx context.Context, resourceGroupName, vmName string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Deallocate")
+	defer done()
+
+	_, err := ac.virtualmachines.BeginDeallocate(ctx, resourceGroupName, vmName, nil)
+	return err
+}
+
+// Hibernate deallocates a virtual machine with hibernation, saving its memory state to its OS disk so that it resumes
+// with it when started. It does not wait for the virtual machine to be hibernated, the power state is observed by the
+// following reconciliations.
+func (ac *AzureClient) Hibernate(ctx context.Context, resourceGroupName, vmName string) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Hibernate")
+	defer done()
+
+	_, err := ac.virtualmachines.BeginDeallocate(ctx, resourceGroupName, vmName, &armcompute.VirtualMachinesClientBeginDeallocateOptions{Hibernate: to.Ptr(true)})
+	return err
+}
+
+// UpdateDataDiskDeleteOptions sets the delete options of the data disks of a virtual machine, by LUN, when they differ
+// from the ones of the virtual machine. It returns whether the virtual machine was updated, without waiting for the
+// update to complete.
+func (ac *AzureClient) UpdateDataDiskDeleteOptions(ctx context.Context, resourceGroupName, vmName string, deleteOptions map[int32]armcompute.DiskDeleteOptionTypes) (bool, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.UpdateDataDiskDeleteOptions")
+	defer done()
+
+	resp, err := ac.virtualmachines.Get(ctx, resourceGroupName, vmName, nil)
+	if err != nil {
+		return false, err
+	}
+	vm := resp.VirtualMachine
+	if vm.Properties == nil || vm.Properties.StorageProfile == nil || vm.Properties.StorageProfile.DataDisks == nil {
+		return false, nil
+	}
+
+	dataDisks := vm.Properties.StorageProfile.DataDisks
+	updated := false
+	for _, disk := range dataDisks {
+		if disk == nil || disk.Lun == nil {
+			continue
+		}
+		deleteOption, ok := deleteOptions[*disk.Lun]
+		if !ok || (disk.DeleteOption != nil && *disk.DeleteOption == deleteOption) {
+			continue
+		}
+		disk.DeleteOption = to.Ptr(deleteOption)
+		updated = true
+	}
+	if !updated {
+		return false, nil
+	}
+
+	// the data disks of the update replace the ones of the virtual machine.
+	_, err = ac.virtualmachines.BeginUpdate(ctx, resourceGroupName, vmName, armcompute.VirtualMachineUpdate{
+		Properties: &armcompute.VirtualMachineProperties{
+			StorageProfile: &armcompute.StorageProfile{DataDisks: dataDisks},
+		},
+	}, nil)
+	return true, err
 }
 
 // CreateOrUpdateAsync creates or updates a virtual machine asynchronously.
-// It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// It sends a PUT request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
 // progress of the operation.
-func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (result interface{}, future azureautorest.FutureAPI, err error) {
+func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string, parameters interface{}) (result interface{}, poller azure.Poller, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.CreateOrUpdate")
 	defer done()
 
-	vm, ok := parameters.(compute.VirtualMachine)
-	if !ok {
-		return nil, nil, errors.Errorf("%T is not a compute.VirtualMachine", parameters)
+	vm, ok := parameters.(armcompute.VirtualMachine)
+	if !ok && parameters != nil {
+		return nil, nil, errors.Errorf("%T is not an armcompute.VirtualMachine", parameters)
 	}
 
-	createFuture, err := ac.virtualmachines.CreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), vm)
+	opts := &armcompute.VirtualMachinesClientBeginCreateOrUpdateOptions{ResumeToken: resumeToken}
+	createPoller, err := ac.virtualmachines.BeginCreateOrUpdate(ctx, spec.ResourceGroupName(), spec.ResourceName(), vm, opts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -77,26 +164,26 @@ func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
 	defer cancel()
 
-	err = createFuture.WaitForCompletionRef(ctx, ac.virtualmachines.Client)
+	resp, err := createPoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
 	if err != nil {
-		// if an error occurs, return the future.
+		// if an error occurs, return the poller.
 		// this means the long-running operation didn't finish in the specified timeout.
-		return nil, &createFuture, err
+		return nil, createPoller, err
 	}
-	result, err = createFuture.Result(ac.virtualmachines)
-	// if the operation completed, return a nil future
-	return result, nil, err
+
+	// if the operation completed, return a nil poller
+	return resp.VirtualMachine, nil, nil
 }
 
 // DeleteAsync deletes a virtual machine asynchronously. DeleteAsync sends a DELETE
-// request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// request to Azure and if accepted without error, the func will return a poller which can be used to track the ongoing
 // progress of the operation.
-func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter) (future azureautorest.FutureAPI, err error) {
+func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecGetter, resumeToken string) (poller azure.Poller, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Delete")
 	defer done()
 
-	forceDelete := to.BoolPtr(true)
-	deleteFuture, err := ac.virtualmachines.Delete(ctx, spec.ResourceGroupName(), spec.ResourceName(), forceDelete)
+	opts := &armcompute.VirtualMachinesClientBeginDeleteOptions{ForceDeletion: to.Ptr(true), ResumeToken: resumeToken}
+	deletePoller, err := ac.virtualmachines.BeginDelete(ctx, spec.ResourceGroupName(), spec.ResourceName(), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -104,73 +191,13 @@ func (ac *AzureClient) DeleteAsync(ctx context.Context, spec azure.ResourceSpecG
 	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
 	defer cancel()
 
-	err = deleteFuture.WaitForCompletionRef(ctx, ac.virtualmachines.Client)
+	_, err = deletePoller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{Frequency: async.DefaultPollerFrequency})
 	if err != nil {
-		// if an error occurs, return the future.
+		// if an error occurs, return the poller.
 		// this means the long-running operation didn't finish in the specified timeout.
-		return &deleteFuture, err
-	}
-	_, err = deleteFuture.Result(ac.virtualmachines)
-	// if the operation completed, return a nil future.
-	return nil, err
-}
-
-// IsDone returns true if the long-running operation has completed.
-func (ac *AzureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (isDone bool, err error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.IsDone")
-	defer done()
-
-	isDone, err = future.DoneWithContext(ctx, ac.virtualmachines)
-	if err != nil {
-		return false, errors.Wrap(err, "failed checking if the operation was complete")
-	}
-
-	return isDone, nil
-}
-
-// Result fetches the result of a long-running operation future.
-func (ac *AzureClient) Result(ctx context.Context, future azureautorest.FutureAPI, futureType string) (result interface{}, err error) {
-	_, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Result")
-	defer done()
-
-	if future == nil {
-		return nil, errors.Errorf("cannot get result from nil future")
+		return deletePoller, err
 	}
 
-	switch futureType {
-	case infrav1.PatchFuture:
-		// Marshal and Unmarshal the future to put it into the correct future type so we can access the Result function.
-		// Unfortunately the FutureAPI can't be casted directly to VirtualMachinesUpdateFuture because it is a azureautorest.Future, which doesn't implement the Result function. See PR #1686 for discussion on alternatives.
-		// It was converted back to a generic azureautorest.Future from the CAPZ infrav1.Future type stored in Status: https://github.com/kubernetes-sigs/cluster-api-provider-azure/blob/main/azure/converters/futures.go#L49.
-		var updateFuture *compute.VirtualMachinesUpdateFuture
-		jsonData, err := future.MarshalJSON()
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to marshal future")
-		}
-		if err := json.Unmarshal(jsonData, &updateFuture); err != nil {
-			return nil, errors.Wrap(err, "failed to unmarshal future data")
-		}
-		return updateFuture.Result(ac.virtualmachines)
-
-	case infrav1.PutFuture:
-		// Marshal and Unmarshal the future to put it into the correct future type so we can access the Result function.
-		// Unfortunately the FutureAPI can't be casted directly to VirtualMachinesCreateOrUpdateFuture because it is a azureautorest.Future, which doesn't implement the Result function. See PR #1686 for discussion on alternatives.
-		// It was converted back to a generic azureautorest.Future from the CAPZ infrav1.Future type stored in Status: https://github.com/kubernetes-sigs/cluster-api-provider-azure/blob/main/azure/converters/futures.go#L49.
-		var createFuture *compute.VirtualMachinesCreateOrUpdateFuture
-		jsonData, err := future.MarshalJSON()
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to marshal future")
-		}
-		if err := json.Unmarshal(jsonData, &createFuture); err != nil {
-			return nil, errors.Wrap(err, "failed to unmarshal future data")
-		}
-		return createFuture.Result(ac.virtualmachines)
-
-	case infrav1.DeleteFuture:
-		// Delete does not return a result VM.
-		return nil, nil
-
-	default:
-		return nil, errors.Errorf("unknown future type %q", futureType)
-	}
+	// if the operation completed, return a nil poller.
+	return nil, nil
 }
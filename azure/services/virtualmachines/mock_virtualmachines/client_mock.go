@@ -19,3 +19,91 @@ limitations under the License.
 
 // Package mock_virtualmachines is a generated GoMock package.
 package mock_virtualmachines
+
+import (
+	context "context"
+	reflect "reflect"
+
+	armcompute "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// Deallocate mocks base method.
+func (m *MockClient) Deallocate(ctx context.Context, resourceGroupName, vmName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deallocate", ctx, resourceGroupName, vmName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Deallocate indicates an expected call of Deallocate.
+func (mr *MockClientMockRecorder) Deallocate(ctx, resourceGroupName, vmName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deallocate", reflect.TypeOf((*MockClient)(nil).Deallocate), ctx, resourceGroupName, vmName)
+}
+
+// Hibernate mocks base method.
+func (m *MockClient) Hibernate(ctx context.Context, resourceGroupName, vmName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hibernate", ctx, resourceGroupName, vmName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Hibernate indicates an expected call of Hibernate.
+func (mr *MockClientMockRecorder) Hibernate(ctx, resourceGroupName, vmName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hibernate", reflect.TypeOf((*MockClient)(nil).Hibernate), ctx, resourceGroupName, vmName)
+}
+
+// Start mocks base method.
+func (m *MockClient) Start(ctx context.Context, resourceGroupName, vmName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx, resourceGroupName, vmName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockClientMockRecorder) Start(ctx, resourceGroupName, vmName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockClient)(nil).Start), ctx, resourceGroupName, vmName)
+}
+
+// UpdateDataDiskDeleteOptions mocks base method.
+func (m *MockClient) UpdateDataDiskDeleteOptions(ctx context.Context, resourceGroupName, vmName string, deleteOptions map[int32]armcompute.DiskDeleteOptionTypes) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDataDiskDeleteOptions", ctx, resourceGroupName, vmName, deleteOptions)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDataDiskDeleteOptions indicates an expected call of UpdateDataDiskDeleteOptions.
+func (mr *MockClientMockRecorder) UpdateDataDiskDeleteOptions(ctx, resourceGroupName, vmName, deleteOptions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDataDiskDeleteOptions", reflect.TypeOf((*MockClient)(nil).UpdateDataDiskDeleteOptions), ctx, resourceGroupName, vmName, deleteOptions)
+}
@@ -23,7 +23,7 @@ package mock_virtualmachines
 import (
 	reflect "reflect"
 
-	autorest "github.com/Azure/go-autorest/autorest"
+	azcore "github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	gomock "github.com/golang/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -54,20 +54,6 @@ func (m *MockVMScope) EXPECT() *MockVMScopeMockRecorder {
 	return m.recorder
 }
 
-// Authorizer mocks base method.
-func (m *MockVMScope) Authorizer() autorest.Authorizer {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authorizer")
-	ret0, _ := ret[0].(autorest.Authorizer)
-	return ret0
-}
-
-// Authorizer indicates an expected call of Authorizer.
-func (mr *MockVMScopeMockRecorder) Authorizer() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockVMScope)(nil).Authorizer))
-}
-
 // BaseURI mocks base method.
 func (m *MockVMScope) BaseURI() string {
 	m.ctrl.T.Helper()
@@ -136,6 +122,20 @@ func (mr *MockVMScopeMockRecorder) DeleteLongRunningOperationState(arg0, arg1 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockVMScope)(nil).DeleteLongRunningOperationState), arg0, arg1)
 }
 
+// DesiredPowerState mocks base method.
+func (m *MockVMScope) DesiredPowerState() v1beta1.PowerState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DesiredPowerState")
+	ret0, _ := ret[0].(v1beta1.PowerState)
+	return ret0
+}
+
+// DesiredPowerState indicates an expected call of DesiredPowerState.
+func (mr *MockVMScopeMockRecorder) DesiredPowerState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DesiredPowerState", reflect.TypeOf((*MockVMScope)(nil).DesiredPowerState))
+}
+
 // GetLongRunningOperationState mocks base method.
 func (m *MockVMScope) GetLongRunningOperationState(arg0, arg1 string) *v1beta1.Future {
 	m.ctrl.T.Helper()
@@ -200,6 +200,30 @@ func (mr *MockVMScopeMockRecorder) SetLongRunningOperationState(arg0 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockVMScope)(nil).SetLongRunningOperationState), arg0)
 }
 
+// SetPlacement mocks base method.
+func (m *MockVMScope) SetPlacement(arg0 *v1beta1.VMPlacement) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPlacement", arg0)
+}
+
+// SetPlacement indicates an expected call of SetPlacement.
+func (mr *MockVMScopeMockRecorder) SetPlacement(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPlacement", reflect.TypeOf((*MockVMScope)(nil).SetPlacement), arg0)
+}
+
+// SetPowerState mocks base method.
+func (m *MockVMScope) SetPowerState(arg0 v1beta1.PowerState) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPowerState", arg0)
+}
+
+// SetPowerState indicates an expected call of SetPowerState.
+func (mr *MockVMScopeMockRecorder) SetPowerState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPowerState", reflect.TypeOf((*MockVMScope)(nil).SetPowerState), arg0)
+}
+
 // SetProviderID mocks base method.
 func (m *MockVMScope) SetProviderID(arg0 string) {
 	m.ctrl.T.Helper()
@@ -252,6 +276,20 @@ func (mr *MockVMScopeMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockVMScope)(nil).TenantID))
 }
 
+// Token mocks base method.
+func (m *MockVMScope) Token() azcore.TokenCredential {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token")
+	ret0, _ := ret[0].(azcore.TokenCredential)
+	return ret0
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockVMScopeMockRecorder) Token() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockVMScope)(nil).Token))
+}
+
 // UpdateDeleteStatus mocks base method.
 func (m *MockVMScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
 	m.ctrl.T.Helper()
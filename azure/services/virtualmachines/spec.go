@@ -19,9 +19,10 @@ package virtualmachines
 import (
 	"encoding/base64"
 	"fmt"
+	"k8s.io/utils/pointer"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -35,24 +36,30 @@ type VMSpec struct {
 	Name                   string
 	ResourceGroup          string
 	Location               string
+	ExtendedLocation       *infrav1.ExtendedLocationSpec
 	ClusterName            string
 	Role                   string
 	NICIDs                 []string
 	SSHKeyData             string
+	AdditionalSSHKeyData   []string
 	Size                   string
 	AvailabilitySetID      string
 	Zone                   string
 	Identity               infrav1.VMIdentity
 	OSDisk                 infrav1.OSDisk
+	OSDiskName             string
+	OSDiskID               string
 	DataDisks              []infrav1.DataDisk
 	UserAssignedIdentities []infrav1.UserAssignedIdentity
 	SpotVMOptions          *infrav1.SpotVMOptions
 	SecurityProfile        *infrav1.SecurityProfile
 	AdditionalTags         infrav1.Tags
 	AdditionalCapabilities *infrav1.AdditionalCapabilities
+	DiskControllerType     infrav1.DiskControllerType
 	SKU                    resourceskus.SKU
 	Image                  *infrav1.Image
 	BootstrapData          string
+	UserData               string
 	ProviderID             string
 }
 
@@ -74,8 +81,8 @@ func (s *VMSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the virtual machine.
 func (s *VMSpec) Parameters(existing interface{}) (params interface{}, err error) {
 	if existing != nil {
-		if _, ok := existing.(compute.VirtualMachine); !ok {
-			return nil, errors.Errorf("%T is not a compute.VirtualMachine", existing)
+		if _, ok := existing.(armcompute.VirtualMachine); !ok {
+			return nil, errors.Errorf("%T is not an armcompute.VirtualMachine", existing)
 		}
 		// vm already exists
 		return nil, nil
@@ -86,6 +93,10 @@ func (s *VMSpec) Parameters(existing interface{}) (params interface{}, err error
 		return nil, azure.VMDeletedError{ProviderID: s.ProviderID}
 	}
 
+	if s.AdditionalCapabilities != nil && pointer.BoolDeref(s.AdditionalCapabilities.HibernationEnabled, false) && !s.SKU.HasCapability(resourceskus.HibernationSupported) {
+		return nil, azure.WithTerminalError(fmt.Errorf("vm size %s does not support hibernation. select a different vm size or disable hibernation", s.Size))
+	}
+
 	storageProfile, err := s.generateStorageProfile()
 	if err != nil {
 		return nil, err
@@ -96,9 +107,13 @@ func (s *VMSpec) Parameters(existing interface{}) (params interface{}, err error
 		return nil, err
 	}
 
-	osProfile, err := s.generateOSProfile()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to generate OS Profile")
+	// An OS disk created from a snapshot is already provisioned.
+	var osProfile *armcompute.OSProfile
+	if s.OSDisk.SourceSnapshotID == "" {
+		osProfile, err = s.generateOSProfile()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate OS Profile")
+		}
 	}
 
 	priority, evictionPolicy, billingProfile, err := converters.GetSpotVMOptions(s.SpotVMOptions)
@@ -111,54 +126,79 @@ func (s *VMSpec) Parameters(existing interface{}) (params interface{}, err error
 		return nil, errors.Wrap(err, "failed to generate VM identity")
 	}
 
-	return compute.VirtualMachine{
-		Plan:     converters.ImageToPlan(s.Image),
-		Location: to.StringPtr(s.Location),
+	return armcompute.VirtualMachine{
+		Plan:             converters.ImageToPlan(s.Image),
+		Location:         to.Ptr(s.Location),
+		ExtendedLocation: converters.ExtendedLocationToComputeSDK(s.ExtendedLocation),
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: s.ClusterName,
 			Lifecycle:   infrav1.ResourceLifecycleOwned,
-			Name:        to.StringPtr(s.Name),
-			Role:        to.StringPtr(s.Role),
+			Name:        to.Ptr(s.Name),
+			Role:        to.Ptr(s.Role),
 			Additional:  s.AdditionalTags,
 		})),
-		VirtualMachineProperties: &compute.VirtualMachineProperties{
+		Properties: &armcompute.VirtualMachineProperties{
 			AdditionalCapabilities: s.generateAdditionalCapabilities(),
 			AvailabilitySet:        s.getAvailabilitySet(),
-			HardwareProfile: &compute.HardwareProfile{
-				VMSize: compute.VirtualMachineSizeTypes(s.Size),
+			HardwareProfile: &armcompute.HardwareProfile{
+				VMSize: to.Ptr(armcompute.VirtualMachineSizeTypes(s.Size)),
 			},
 			StorageProfile:  storageProfile,
 			SecurityProfile: securityProfile,
-			OsProfile:       osProfile,
-			NetworkProfile: &compute.NetworkProfile{
+			OSProfile:       osProfile,
+			NetworkProfile: &armcompute.NetworkProfile{
 				NetworkInterfaces: s.generateNICRefs(),
 			},
 			Priority:       priority,
 			EvictionPolicy: evictionPolicy,
 			BillingProfile: billingProfile,
-			DiagnosticsProfile: &compute.DiagnosticsProfile{
-				BootDiagnostics: &compute.BootDiagnostics{
-					Enabled: to.BoolPtr(true),
+			DiagnosticsProfile: &armcompute.DiagnosticsProfile{
+				BootDiagnostics: &armcompute.BootDiagnostics{
+					Enabled: to.Ptr(true),
 				},
 			},
+			UserData: s.generateUserData(),
 		},
 		Identity: identity,
 		Zones:    s.getZones(),
 	}, nil
 }
 
-// generateStorageProfile generates a pointer to a compute.StorageProfile which can utilized for VM creation.
-func (s *VMSpec) generateStorageProfile() (*compute.StorageProfile, error) {
-	storageProfile := &compute.StorageProfile{
-		OsDisk: &compute.OSDisk{
-			Name:         to.StringPtr(azure.GenerateOSDiskName(s.Name)),
-			OsType:       compute.OperatingSystemTypes(s.OSDisk.OSType),
-			CreateOption: compute.DiskCreateOptionTypesFromImage,
-			DiskSizeGB:   s.OSDisk.DiskSizeGB,
-			Caching:      compute.CachingTypes(s.OSDisk.CachingType),
+// validateDiskControllerType checks that the VM size supports the requested disk controller. Sizes which
+// don't expose the DiskControllerTypes capability only support SCSI.
+func (s *VMSpec) validateDiskControllerType() error {
+	switch s.DiskControllerType {
+	case "":
+		return nil
+	case infrav1.DiskControllerTypeSCSI:
+		if _, ok := s.SKU.GetCapability(resourceskus.DiskControllerTypes); !ok || s.SKU.HasCapabilityValue(resourceskus.DiskControllerTypes, string(infrav1.DiskControllerTypeSCSI)) {
+			return nil
+		}
+	default:
+		if s.SKU.HasCapabilityValue(resourceskus.DiskControllerTypes, string(s.DiskControllerType)) {
+			return nil
+		}
+	}
+	return azure.WithTerminalError(fmt.Errorf("vm size %s does not support the %s disk controller. select a different vm size or disk controller type", s.Size, s.DiskControllerType))
+}
+
+// generateStorageProfile generates a pointer to an armcompute.StorageProfile which can utilized for VM creation.
+func (s *VMSpec) generateStorageProfile() (*armcompute.StorageProfile, error) {
+	storageProfile := &armcompute.StorageProfile{
+		OSDisk: &armcompute.OSDisk{
+			Name:                    to.Ptr(s.OSDiskName),
+			OSType:                  to.Ptr(armcompute.OperatingSystemTypes(s.OSDisk.OSType)),
+			CreateOption:            to.Ptr(armcompute.DiskCreateOptionTypesFromImage),
+			DeleteOption:            to.Ptr(armcompute.DiskDeleteOptionTypesDelete),
+			DiskSizeGB:              s.OSDisk.DiskSizeGB,
+			WriteAcceleratorEnabled: s.OSDisk.WriteAcceleratorEnabled,
 		},
 	}
 
+	if s.OSDisk.CachingType != "" {
+		storageProfile.OSDisk.Caching = to.Ptr(armcompute.CachingTypes(s.OSDisk.CachingType))
+	}
+
 	// Checking if the requested VM size has at least 2 vCPUS
 	vCPUCapability, err := s.SKU.HasCapabilityWithCapacity(resourceskus.VCPUs, resourceskus.MinimumVCPUS)
 	if err != nil {
@@ -183,47 +223,88 @@ func (s *VMSpec) generateStorageProfile() (*compute.StorageProfile, error) {
 			return nil, azure.WithTerminalError(fmt.Errorf("vm size %s does not support ephemeral os. select a different vm size or disable ephemeral os", s.Size))
 		}
 
-		storageProfile.OsDisk.DiffDiskSettings = &compute.DiffDiskSettings{
-			Option: compute.DiffDiskOptions(s.OSDisk.DiffDiskSettings.Option),
+		storageProfile.OSDisk.DiffDiskSettings = &armcompute.DiffDiskSettings{
+			Option: to.Ptr(armcompute.DiffDiskOptions(s.OSDisk.DiffDiskSettings.Option)),
 		}
 	}
 
+	if err := s.validateDiskControllerType(); err != nil {
+		return nil, err
+	}
+	if s.DiskControllerType != "" {
+		storageProfile.DiskControllerType = to.Ptr(armcompute.DiskControllerTypes(s.DiskControllerType))
+	}
+
 	if s.OSDisk.ManagedDisk != nil {
-		storageProfile.OsDisk.ManagedDisk = &compute.ManagedDiskParameters{}
+		storageProfile.OSDisk.ManagedDisk = &armcompute.ManagedDiskParameters{}
 		if s.OSDisk.ManagedDisk.StorageAccountType != "" {
-			storageProfile.OsDisk.ManagedDisk.StorageAccountType = compute.StorageAccountTypes(s.OSDisk.ManagedDisk.StorageAccountType)
+			storageProfile.OSDisk.ManagedDisk.StorageAccountType = to.Ptr(armcompute.StorageAccountTypes(s.OSDisk.ManagedDisk.StorageAccountType))
 		}
 		if s.OSDisk.ManagedDisk.DiskEncryptionSet != nil {
-			storageProfile.OsDisk.ManagedDisk.DiskEncryptionSet = &compute.DiskEncryptionSetParameters{ID: to.StringPtr(s.OSDisk.ManagedDisk.DiskEncryptionSet.ID)}
+			storageProfile.OSDisk.ManagedDisk.DiskEncryptionSet = &armcompute.DiskEncryptionSetParameters{ID: to.Ptr(s.OSDisk.ManagedDisk.DiskEncryptionSet.ID)}
 		}
 	}
 
-	dataDisks := make([]compute.DataDisk, len(s.DataDisks))
+	dataDisks := make([]*armcompute.DataDisk, len(s.DataDisks))
 	for i, disk := range s.DataDisks {
-		dataDisks[i] = compute.DataDisk{
-			CreateOption: compute.DiskCreateOptionTypesEmpty,
-			DiskSizeGB:   to.Int32Ptr(disk.DiskSizeGB),
-			Lun:          disk.Lun,
-			Name:         to.StringPtr(azure.GenerateDataDiskName(s.Name, disk.NameSuffix)),
-			Caching:      compute.CachingTypes(disk.CachingType),
+		dataDisks[i] = &armcompute.DataDisk{
+			CreateOption:            to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+			DiskSizeGB:              to.Ptr[int32](disk.DiskSizeGB),
+			Lun:                     disk.Lun,
+			Name:                    to.Ptr(azure.GenerateDataDiskName(s.Name, disk.NameSuffix)),
+			WriteAcceleratorEnabled: disk.WriteAcceleratorEnabled,
+			DeleteOption:            to.Ptr(armcompute.DiskDeleteOptionTypes(disk.GetDeletePolicy())),
+		}
+		if disk.CachingType != "" {
+			dataDisks[i].Caching = to.Ptr(armcompute.CachingTypes(disk.CachingType))
+		}
+
+		if disk.ExistingDiskID != "" {
+			dataDisks[i].CreateOption = to.Ptr(armcompute.DiskCreateOptionTypesAttach)
+			dataDisks[i].DiskSizeGB = nil
+			dataDisks[i].Name = nil
+			dataDisks[i].ManagedDisk = &armcompute.ManagedDiskParameters{
+				ID: to.Ptr(disk.ExistingDiskID),
+			}
 		}
 
 		if disk.ManagedDisk != nil {
-			dataDisks[i].ManagedDisk = &compute.ManagedDiskParameters{
-				StorageAccountType: compute.StorageAccountTypes(disk.ManagedDisk.StorageAccountType),
+			dataDisks[i].ManagedDisk = &armcompute.ManagedDiskParameters{
+				StorageAccountType: to.Ptr(armcompute.StorageAccountTypes(disk.ManagedDisk.StorageAccountType)),
 			}
 
 			if disk.ManagedDisk.DiskEncryptionSet != nil {
-				dataDisks[i].ManagedDisk.DiskEncryptionSet = &compute.DiskEncryptionSetParameters{ID: to.StringPtr(disk.ManagedDisk.DiskEncryptionSet.ID)}
+				dataDisks[i].ManagedDisk.DiskEncryptionSet = &armcompute.DiskEncryptionSetParameters{ID: to.Ptr(disk.ManagedDisk.DiskEncryptionSet.ID)}
 			}
 
 			// check the support for ultra disks based on location and vm size
-			if disk.ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) && !s.SKU.HasLocationCapability(resourceskus.UltraSSDAvailable, s.Location, s.Zone) {
+			if disk.ManagedDisk.StorageAccountType == string(armcompute.StorageAccountTypesUltraSSDLRS) && !s.SKU.HasLocationCapability(resourceskus.UltraSSDAvailable, s.Location, s.Zone) {
 				return nil, azure.WithTerminalError(fmt.Errorf("vm size %s does not support ultra disks in location %s. select a different vm size or disable ultra disks", s.Size, s.Location))
 			}
 		}
 	}
-	storageProfile.DataDisks = &dataDisks
+	storageProfile.DataDisks = dataDisks
+
+	// check the support for write accelerator based on vm size
+	if writeAcceleratedDisks := s.writeAcceleratedDiskCount(); writeAcceleratedDisks > 0 {
+		writeAcceleratorCapability, err := s.SKU.HasCapabilityWithCapacity(resourceskus.MaxWriteAcceleratorDisksAllowed, writeAcceleratedDisks)
+		if err != nil {
+			return nil, azure.WithTerminalError(errors.Wrap(err, "failed to validate the write accelerator capability"))
+		}
+		if !writeAcceleratorCapability {
+			return nil, azure.WithTerminalError(fmt.Errorf("vm size %s does not support write accelerator on %d disks. select a different vm size or disable write accelerator", s.Size, writeAcceleratedDisks))
+		}
+	}
+
+	// Attach the OS disk created from a snapshot by the disks service.
+	if s.OSDisk.SourceSnapshotID != "" {
+		storageProfile.OSDisk.CreateOption = to.Ptr(armcompute.DiskCreateOptionTypesAttach)
+		storageProfile.OSDisk.DiskSizeGB = nil
+		storageProfile.OSDisk.ManagedDisk = &armcompute.ManagedDiskParameters{
+			ID: to.Ptr(s.OSDiskID),
+		}
+		return storageProfile, nil
+	}
 
 	imageRef, err := converters.ImageToSDK(s.Image)
 	if err != nil {
@@ -235,20 +316,34 @@ func (s *VMSpec) generateStorageProfile() (*compute.StorageProfile, error) {
 	return storageProfile, nil
 }
 
-func (s *VMSpec) generateOSProfile() (*compute.OSProfile, error) {
+// writeAcceleratedDiskCount returns the number of disks of the VM with write accelerator enabled.
+func (s *VMSpec) writeAcceleratedDiskCount() int64 {
+	var count int64
+	if s.OSDisk.WriteAcceleratorEnabled != nil && *s.OSDisk.WriteAcceleratorEnabled {
+		count++
+	}
+	for _, disk := range s.DataDisks {
+		if disk.WriteAcceleratorEnabled != nil && *disk.WriteAcceleratorEnabled {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *VMSpec) generateOSProfile() (*armcompute.OSProfile, error) {
 	sshKey, err := base64.StdEncoding.DecodeString(s.SSHKeyData)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to decode ssh public key")
 	}
 
-	osProfile := &compute.OSProfile{
-		ComputerName:  to.StringPtr(s.Name),
-		AdminUsername: to.StringPtr(azure.DefaultUserName),
-		CustomData:    to.StringPtr(s.BootstrapData),
+	osProfile := &armcompute.OSProfile{
+		ComputerName:  to.Ptr(s.Name),
+		AdminUsername: to.Ptr(azure.DefaultUserName),
+		CustomData:    to.Ptr(s.BootstrapData),
 	}
 
 	switch s.OSDisk.OSType {
-	case string(compute.OperatingSystemTypesWindows):
+	case string(armcompute.OperatingSystemTypesWindows):
 		// Cloudbase-init is used to generate a password.
 		// https://cloudbase-init.readthedocs.io/en/latest/plugins.html#setting-password-main
 		//
@@ -256,20 +351,31 @@ func (s *VMSpec) generateOSProfile() (*compute.OSProfile, error) {
 		// but the password on the VM will NOT be the same as created here.
 		// Access is provided via SSH public key that is set during deployment
 		// Azure also provides a way to reset user passwords in the case of need.
-		osProfile.AdminPassword = to.StringPtr(generators.SudoRandomPassword(123))
-		osProfile.WindowsConfiguration = &compute.WindowsConfiguration{
-			EnableAutomaticUpdates: to.BoolPtr(false),
+		osProfile.AdminPassword = to.Ptr(generators.SudoRandomPassword(123))
+		osProfile.WindowsConfiguration = &armcompute.WindowsConfiguration{
+			EnableAutomaticUpdates: to.Ptr(false),
 		}
 	default:
-		osProfile.LinuxConfiguration = &compute.LinuxConfiguration{
-			DisablePasswordAuthentication: to.BoolPtr(true),
-			SSH: &compute.SSHConfiguration{
-				PublicKeys: &[]compute.SSHPublicKey{
-					{
-						Path:    to.StringPtr(fmt.Sprintf("/home/%s/.ssh/authorized_keys", azure.DefaultUserName)),
-						KeyData: to.StringPtr(string(sshKey)),
-					},
-				},
+		publicKeys := []*armcompute.SSHPublicKey{
+			{
+				Path:    to.Ptr(fmt.Sprintf("/home/%s/.ssh/authorized_keys", azure.DefaultUserName)),
+				KeyData: to.Ptr(string(sshKey)),
+			},
+		}
+		for i, keyData := range s.AdditionalSSHKeyData {
+			key, err := base64.StdEncoding.DecodeString(keyData)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to decode additional ssh public key %d", i)
+			}
+			publicKeys = append(publicKeys, &armcompute.SSHPublicKey{
+				Path:    to.Ptr(fmt.Sprintf("/home/%s/.ssh/authorized_keys", azure.DefaultUserName)),
+				KeyData: to.Ptr(string(key)),
+			})
+		}
+		osProfile.LinuxConfiguration = &armcompute.LinuxConfiguration{
+			DisablePasswordAuthentication: to.Ptr(true),
+			SSH: &armcompute.SSHConfiguration{
+				PublicKeys: publicKeys,
 			},
 		}
 	}
@@ -277,7 +383,7 @@ func (s *VMSpec) generateOSProfile() (*compute.OSProfile, error) {
 	return osProfile, nil
 }
 
-func (s *VMSpec) generateSecurityProfile() (*compute.SecurityProfile, error) {
+func (s *VMSpec) generateSecurityProfile() (*armcompute.SecurityProfile, error) {
 	if s.SecurityProfile == nil {
 		return nil, nil
 	}
@@ -286,34 +392,35 @@ func (s *VMSpec) generateSecurityProfile() (*compute.SecurityProfile, error) {
 		return nil, azure.WithTerminalError(errors.Errorf("encryption at host is not supported for VM type %s", s.Size))
 	}
 
-	return &compute.SecurityProfile{
+	return &armcompute.SecurityProfile{
 		EncryptionAtHost: s.SecurityProfile.EncryptionAtHost,
 	}, nil
 }
 
-func (s *VMSpec) generateNICRefs() *[]compute.NetworkInterfaceReference {
-	nicRefs := make([]compute.NetworkInterfaceReference, len(s.NICIDs))
+func (s *VMSpec) generateNICRefs() []*armcompute.NetworkInterfaceReference {
+	nicRefs := make([]*armcompute.NetworkInterfaceReference, len(s.NICIDs))
 	for i, id := range s.NICIDs {
 		primary := i == 0
-		nicRefs[i] = compute.NetworkInterfaceReference{
-			ID: to.StringPtr(id),
-			NetworkInterfaceReferenceProperties: &compute.NetworkInterfaceReferenceProperties{
-				Primary: to.BoolPtr(primary),
+		nicRefs[i] = &armcompute.NetworkInterfaceReference{
+			ID: to.Ptr(id),
+			Properties: &armcompute.NetworkInterfaceReferenceProperties{
+				Primary:      to.Ptr(primary),
+				DeleteOption: to.Ptr(armcompute.DeleteOptionsDelete),
 			},
 		}
 	}
-	return &nicRefs
+	return nicRefs
 }
 
-func (s *VMSpec) generateAdditionalCapabilities() *compute.AdditionalCapabilities {
-	var capabilities *compute.AdditionalCapabilities
+func (s *VMSpec) generateAdditionalCapabilities() *armcompute.AdditionalCapabilities {
+	var capabilities *armcompute.AdditionalCapabilities
 
 	// Provisionally detect whether there is any Data Disk defined which uses UltraSSDs.
 	// If that's the case, enable the UltraSSD capability.
 	for _, dataDisk := range s.DataDisks {
-		if dataDisk.ManagedDisk != nil && dataDisk.ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) {
-			capabilities = &compute.AdditionalCapabilities{
-				UltraSSDEnabled: to.BoolPtr(true),
+		if dataDisk.ManagedDisk != nil && dataDisk.ManagedDisk.StorageAccountType == string(armcompute.StorageAccountTypesUltraSSDLRS) {
+			capabilities = &armcompute.AdditionalCapabilities{
+				UltraSSDEnabled: to.Ptr(true),
 			}
 			break
 		}
@@ -322,29 +429,41 @@ func (s *VMSpec) generateAdditionalCapabilities() *compute.AdditionalCapabilitie
 	// Set Additional Capabilities if any is present on the spec.
 	if s.AdditionalCapabilities != nil {
 		if capabilities == nil {
-			capabilities = &compute.AdditionalCapabilities{}
+			capabilities = &armcompute.AdditionalCapabilities{}
 		}
 		// Set UltraSSDEnabled if a specific value is set on the spec for it.
 		if s.AdditionalCapabilities.UltraSSDEnabled != nil {
 			capabilities.UltraSSDEnabled = s.AdditionalCapabilities.UltraSSDEnabled
 		}
+		// Set HibernationEnabled if a specific value is set on the spec for it.
+		if s.AdditionalCapabilities.HibernationEnabled != nil {
+			capabilities.HibernationEnabled = s.AdditionalCapabilities.HibernationEnabled
+		}
 	}
 
 	return capabilities
 }
 
-func (s *VMSpec) getAvailabilitySet() *compute.SubResource {
-	var as *compute.SubResource
+func (s *VMSpec) getAvailabilitySet() *armcompute.SubResource {
+	var as *armcompute.SubResource
 	if s.AvailabilitySetID != "" {
-		as = &compute.SubResource{ID: &s.AvailabilitySetID}
+		as = &armcompute.SubResource{ID: &s.AvailabilitySetID}
 	}
 	return as
 }
 
-func (s *VMSpec) getZones() *[]string {
-	var zones *[]string
+func (s *VMSpec) getZones() []*string {
+	var zones []*string
 	if s.Zone != "" {
-		zones = &[]string{s.Zone}
+		zones = []*string{to.Ptr(s.Zone)}
 	}
 	return zones
 }
+
+// generateUserData returns the user data of the VM, or nil if it has none.
+func (s *VMSpec) generateUserData() *string {
+	if s.UserData == "" {
+		return nil
+	}
+	return to.Ptr(s.UserData)
+}
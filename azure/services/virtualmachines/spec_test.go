@@ -19,9 +19,9 @@ package virtualmachines
 import (
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -32,134 +32,166 @@ import (
 
 var (
 	validSKU = resourceskus.SKU{
-		Name: to.StringPtr("Standard_D2v3"),
-		Kind: to.StringPtr(string(resourceskus.VirtualMachines)),
-		Locations: &[]string{
-			"test-location",
-		},
-		Capabilities: &[]compute.ResourceSkuCapabilities{
+		Name:      to.Ptr("Standard_D2v3"),
+		Kind:      to.Ptr(string(resourceskus.VirtualMachines)),
+		Locations: []*string{to.Ptr("test-location")},
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
 			{
-				Name:  to.StringPtr(resourceskus.VCPUs),
-				Value: to.StringPtr("2"),
+				Name:  to.Ptr(resourceskus.VCPUs),
+				Value: to.Ptr("2"),
 			},
 			{
-				Name:  to.StringPtr(resourceskus.MemoryGB),
-				Value: to.StringPtr("4"),
+				Name:  to.Ptr(resourceskus.MemoryGB),
+				Value: to.Ptr("4"),
 			},
 		},
 	}
 
 	validSKUWithEncryptionAtHost = resourceskus.SKU{
-		Name: to.StringPtr("Standard_D2v3"),
-		Kind: to.StringPtr(string(resourceskus.VirtualMachines)),
-		Locations: &[]string{
-			"test-location",
-		},
-		Capabilities: &[]compute.ResourceSkuCapabilities{
+		Name:      to.Ptr("Standard_D2v3"),
+		Kind:      to.Ptr(string(resourceskus.VirtualMachines)),
+		Locations: []*string{to.Ptr("test-location")},
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
 			{
-				Name:  to.StringPtr(resourceskus.VCPUs),
-				Value: to.StringPtr("2"),
+				Name:  to.Ptr(resourceskus.VCPUs),
+				Value: to.Ptr("2"),
 			},
 			{
-				Name:  to.StringPtr(resourceskus.MemoryGB),
-				Value: to.StringPtr("4"),
+				Name:  to.Ptr(resourceskus.MemoryGB),
+				Value: to.Ptr("4"),
 			},
 			{
-				Name:  to.StringPtr(resourceskus.EncryptionAtHost),
-				Value: to.StringPtr(string(resourceskus.CapabilitySupported)),
+				Name:  to.Ptr(resourceskus.EncryptionAtHost),
+				Value: to.Ptr(string(resourceskus.CapabilitySupported)),
 			},
 		},
 	}
 
 	validSKUWithEphemeralOS = resourceskus.SKU{
-		Name: to.StringPtr("Standard_D2v3"),
-		Kind: to.StringPtr(string(resourceskus.VirtualMachines)),
-		Locations: &[]string{
-			"test-location",
-		},
-		Capabilities: &[]compute.ResourceSkuCapabilities{
+		Name:      to.Ptr("Standard_D2v3"),
+		Kind:      to.Ptr(string(resourceskus.VirtualMachines)),
+		Locations: []*string{to.Ptr("test-location")},
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
 			{
-				Name:  to.StringPtr(resourceskus.VCPUs),
-				Value: to.StringPtr("2"),
+				Name:  to.Ptr(resourceskus.VCPUs),
+				Value: to.Ptr("2"),
 			},
 			{
-				Name:  to.StringPtr(resourceskus.MemoryGB),
-				Value: to.StringPtr("4"),
+				Name:  to.Ptr(resourceskus.MemoryGB),
+				Value: to.Ptr("4"),
 			},
 			{
-				Name:  to.StringPtr(resourceskus.EphemeralOSDisk),
-				Value: to.StringPtr("True"),
+				Name:  to.Ptr(resourceskus.EphemeralOSDisk),
+				Value: to.Ptr("True"),
 			},
 		},
 	}
 
 	validSKUWithUltraSSD = resourceskus.SKU{
-		Name: to.StringPtr("Standard_D2v3"),
-		Kind: to.StringPtr(string(resourceskus.VirtualMachines)),
-		Locations: &[]string{
-			"test-location",
-		},
-		LocationInfo: &[]compute.ResourceSkuLocationInfo{
+		Name:      to.Ptr("Standard_D2v3"),
+		Kind:      to.Ptr(string(resourceskus.VirtualMachines)),
+		Locations: []*string{to.Ptr("test-location")},
+		LocationInfo: []*armcompute.ResourceSKULocationInfo{
 			{
-				Location: to.StringPtr("test-location"),
-				Zones:    &[]string{"1"},
-				ZoneDetails: &[]compute.ResourceSkuZoneDetails{
+				Location: to.Ptr("test-location"),
+				Zones:    []*string{to.Ptr("1")},
+				ZoneDetails: []*armcompute.ResourceSKUZoneDetails{
 					{
-						Capabilities: &[]compute.ResourceSkuCapabilities{
+						Capabilities: []*armcompute.ResourceSKUCapabilities{
 							{
-								Name:  to.StringPtr("UltraSSDAvailable"),
-								Value: to.StringPtr("True"),
+								Name:  to.Ptr("UltraSSDAvailable"),
+								Value: to.Ptr("True"),
 							},
 						},
-						Name: &[]string{"1"},
+						Name: []*string{to.Ptr("1")},
 					},
 				},
 			},
 		},
-		Capabilities: &[]compute.ResourceSkuCapabilities{
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
 			{
-				Name:  to.StringPtr(resourceskus.VCPUs),
-				Value: to.StringPtr("2"),
+				Name:  to.Ptr(resourceskus.VCPUs),
+				Value: to.Ptr("2"),
 			},
 			{
-				Name:  to.StringPtr(resourceskus.MemoryGB),
-				Value: to.StringPtr("4"),
+				Name:  to.Ptr(resourceskus.MemoryGB),
+				Value: to.Ptr("4"),
 			},
 		},
 	}
 
-	invalidCPUSKU = resourceskus.SKU{
-		Name: to.StringPtr("Standard_D2v3"),
-		Kind: to.StringPtr(string(resourceskus.VirtualMachines)),
-		Locations: &[]string{
-			"test-location",
+	validSKUWithWriteAccelerator = resourceskus.SKU{
+		Name:      to.Ptr("Standard_M8ms"),
+		Kind:      to.Ptr(string(resourceskus.VirtualMachines)),
+		Locations: []*string{to.Ptr("test-location")},
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
+			{
+				Name:  to.Ptr(resourceskus.VCPUs),
+				Value: to.Ptr("8"),
+			},
+			{
+				Name:  to.Ptr(resourceskus.MemoryGB),
+				Value: to.Ptr("218"),
+			},
+			{
+				Name:  to.Ptr(resourceskus.MaxWriteAcceleratorDisksAllowed),
+				Value: to.Ptr("1"),
+			},
 		},
-		Capabilities: &[]compute.ResourceSkuCapabilities{
+	}
+
+	invalidCPUSKU = resourceskus.SKU{
+		Name:      to.Ptr("Standard_D2v3"),
+		Kind:      to.Ptr(string(resourceskus.VirtualMachines)),
+		Locations: []*string{to.Ptr("test-location")},
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
 			{
-				Name:  to.StringPtr(resourceskus.VCPUs),
-				Value: to.StringPtr("1"),
+				Name:  to.Ptr(resourceskus.VCPUs),
+				Value: to.Ptr("1"),
 			},
 			{
-				Name:  to.StringPtr(resourceskus.MemoryGB),
-				Value: to.StringPtr("4"),
+				Name:  to.Ptr(resourceskus.MemoryGB),
+				Value: to.Ptr("4"),
 			},
 		},
 	}
 
 	invalidMemSKU = resourceskus.SKU{
-		Name: to.StringPtr("Standard_D2v3"),
-		Kind: to.StringPtr(string(resourceskus.VirtualMachines)),
-		Locations: &[]string{
-			"test-location",
+		Name:      to.Ptr("Standard_D2v3"),
+		Kind:      to.Ptr(string(resourceskus.VirtualMachines)),
+		Locations: []*string{to.Ptr("test-location")},
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
+			{
+				Name:  to.Ptr(resourceskus.VCPUs),
+				Value: to.Ptr("2"),
+			},
+			{
+				Name:  to.Ptr(resourceskus.MemoryGB),
+				Value: to.Ptr("1"),
+			},
 		},
-		Capabilities: &[]compute.ResourceSkuCapabilities{
+	}
+
+	validSKUWithNVMe = resourceskus.SKU{
+		Name:      to.Ptr("Standard_E2bds_v5"),
+		Kind:      to.Ptr(string(resourceskus.VirtualMachines)),
+		Locations: []*string{to.Ptr("test-location")},
+		Capabilities: []*armcompute.ResourceSKUCapabilities{
+			{
+				Name:  to.Ptr(resourceskus.VCPUs),
+				Value: to.Ptr("2"),
+			},
 			{
-				Name:  to.StringPtr(resourceskus.VCPUs),
-				Value: to.StringPtr("2"),
+				Name:  to.Ptr(resourceskus.MemoryGB),
+				Value: to.Ptr("16"),
 			},
 			{
-				Name:  to.StringPtr(resourceskus.MemoryGB),
-				Value: to.StringPtr("1"),
+				Name:  to.Ptr(resourceskus.HyperVGenerations),
+				Value: to.Ptr("V2"),
+			},
+			{
+				Name:  to.Ptr(resourceskus.DiskControllerTypes),
+				Value: to.Ptr("SCSI, NVMe"),
 			},
 		},
 	}
@@ -176,16 +208,16 @@ func TestParameters(t *testing.T) {
 		{
 			name:     "fails if existing is not a VirtualMachine",
 			spec:     &VMSpec{},
-			existing: network.VirtualNetwork{},
+			existing: armnetwork.VirtualNetwork{},
 			expect: func(g *WithT, result interface{}) {
 				g.Expect(result).To(BeNil())
 			},
-			expectedError: "network.VirtualNetwork is not a compute.VirtualMachine",
+			expectedError: "armnetwork.VirtualNetwork is not an armcompute.VirtualMachine",
 		},
 		{
 			name:     "returns nil if vm already exists",
 			spec:     &VMSpec{},
-			existing: compute.VirtualMachine{},
+			existing: armcompute.VirtualMachine{},
 			expect: func(g *WithT, result interface{}) {
 				g.Expect(result).To(BeNil())
 			},
@@ -211,15 +243,44 @@ func TestParameters(t *testing.T) {
 				SSHKeyData: "fakesshpublickey",
 				Size:       "Standard_D2v3",
 				Zone:       "1",
-				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:      &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				Identity:   infrav1.VMIdentitySystemAssigned,
 				SKU:        validSKU,
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).Identity.Type).To(Equal(compute.ResourceIdentityTypeSystemAssigned))
-				g.Expect(result.(compute.VirtualMachine).Identity.UserAssignedIdentities).To(BeEmpty())
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Identity.Type).To(Equal(to.Ptr(armcompute.ResourceIdentityTypeSystemAssigned)))
+				g.Expect(result.(armcompute.VirtualMachine).Identity.UserAssignedIdentities).To(BeEmpty())
+			},
+			expectedError: "",
+		},
+		{
+			name: "deletes the network interfaces and disks with the vm, except detached data disks",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic", "my-nic-1"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Image:      &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				DataDisks: []infrav1.DataDisk{
+					{NameSuffix: "etcddisk", DiskSizeGB: 128, Lun: to.Ptr[int32](0)},
+					{NameSuffix: "datadisk", DiskSizeGB: 128, Lun: to.Ptr[int32](1), DeletePolicy: infrav1.DiskDeletePolicyDetach},
+				},
+				SKU: validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				vm := result.(armcompute.VirtualMachine)
+				for _, nic := range vm.Properties.NetworkProfile.NetworkInterfaces {
+					g.Expect(nic.Properties.DeleteOption).To(Equal(to.Ptr(armcompute.DeleteOptionsDelete)))
+				}
+				g.Expect(vm.Properties.StorageProfile.OSDisk.DeleteOption).To(Equal(to.Ptr(armcompute.DiskDeleteOptionTypesDelete)))
+				dataDisks := vm.Properties.StorageProfile.DataDisks
+				g.Expect(dataDisks[0].DeleteOption).To(Equal(to.Ptr(armcompute.DiskDeleteOptionTypesDelete)))
+				g.Expect(dataDisks[1].DeleteOption).To(Equal(to.Ptr(armcompute.DiskDeleteOptionTypesDetach)))
 			},
 			expectedError: "",
 		},
@@ -232,19 +293,78 @@ func TestParameters(t *testing.T) {
 				SSHKeyData:             "fakesshpublickey",
 				Size:                   "Standard_D2v3",
 				Zone:                   "1",
-				Image:                  &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:                  &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				Identity:               infrav1.VMIdentityUserAssigned,
 				UserAssignedIdentities: []infrav1.UserAssignedIdentity{{ProviderID: "my-user-id"}},
 				SKU:                    validSKU,
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).Identity.Type).To(Equal(compute.ResourceIdentityTypeUserAssigned))
-				g.Expect(result.(compute.VirtualMachine).Identity.UserAssignedIdentities).To(Equal(map[string]*compute.VirtualMachineIdentityUserAssignedIdentitiesValue{"my-user-id": {}}))
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Identity.Type).To(Equal(to.Ptr(armcompute.ResourceIdentityTypeUserAssigned)))
+				g.Expect(result.(armcompute.VirtualMachine).Identity.UserAssignedIdentities).To(Equal(map[string]*armcompute.UserAssignedIdentitiesValue{"my-user-id": {}}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a vm with user data",
+			spec: &VMSpec{
+				Name:          "my-vm",
+				Role:          infrav1.Node,
+				NICIDs:        []string{"my-nic"},
+				SSHKeyData:    "fakesshpublickey",
+				Size:          "Standard_D2v3",
+				Image:         &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				SKU:           validSKU,
+				BootstrapData: "fake-bootstrap-data",
+				UserData:      "fake-user-data",
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.OSProfile.CustomData).To(Equal(to.Ptr("fake-bootstrap-data")))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.UserData).To(Equal(to.Ptr("fake-user-data")))
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a vm with additional ssh public keys",
+			spec: &VMSpec{
+				Name:                 "my-vm",
+				Role:                 infrav1.Node,
+				NICIDs:               []string{"my-nic"},
+				SSHKeyData:           "fakesshpublickey",
+				AdditionalSSHKeyData: []string{"b3RoZXJrZXk="},
+				Size:                 "Standard_D2v3",
+				Image:                &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				SKU:                  validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				publicKeys := result.(armcompute.VirtualMachine).Properties.OSProfile.LinuxConfiguration.SSH.PublicKeys
+				g.Expect(publicKeys).To(HaveLen(2))
+				g.Expect(publicKeys[1].KeyData).To(Equal(to.Ptr("otherkey")))
+				g.Expect(publicKeys[1].Path).To(Equal(publicKeys[0].Path))
+			},
+			expectedError: "",
+		},
+		{
+			name: "cannot create a vm with an additional ssh public key that is not base64 encoded",
+			spec: &VMSpec{
+				Name:                 "my-vm",
+				Role:                 infrav1.Node,
+				NICIDs:               []string{"my-nic"},
+				SSHKeyData:           "fakesshpublickey",
+				AdditionalSSHKeyData: []string{"not base64"},
+				Size:                 "Standard_D2v3",
+				Image:                &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				SKU:                  validSKU,
+			},
+			existing:      nil,
+			expect:        func(g *WithT, result interface{}) {},
+			expectedError: "failed to generate OS Profile: failed to decode additional ssh public key 0: illegal base64 data at input byte 3",
+		},
 		{
 			name: "can create a spot vm",
 			spec: &VMSpec{
@@ -254,16 +374,38 @@ func TestParameters(t *testing.T) {
 				SSHKeyData:    "fakesshpublickey",
 				Size:          "Standard_D2v3",
 				Zone:          "1",
-				Image:         &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:         &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				SpotVMOptions: &infrav1.SpotVMOptions{},
 				SKU:           validSKU,
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).Priority).To(Equal(compute.VirtualMachinePriorityTypesSpot))
-				g.Expect(result.(compute.VirtualMachine).EvictionPolicy).To(Equal(compute.VirtualMachineEvictionPolicyTypesDeallocate))
-				g.Expect(result.(compute.VirtualMachine).BillingProfile).To(BeNil())
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.Priority).To(Equal(to.Ptr(armcompute.VirtualMachinePriorityTypesSpot)))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.EvictionPolicy).To(Equal(to.Ptr(armcompute.VirtualMachineEvictionPolicyTypesDeallocate)))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.BillingProfile).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a vm in an edge zone",
+			spec: &VMSpec{
+				Name:             "my-vm",
+				Role:             infrav1.Node,
+				NICIDs:           []string{"my-nic"},
+				SSHKeyData:       "fakesshpublickey",
+				Size:             "Standard_D2v3",
+				ExtendedLocation: &infrav1.ExtendedLocationSpec{Name: "attatlanta1", Type: infrav1.ExtendedLocationTypeEdgeZone},
+				Image:            &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				SKU:              validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).ExtendedLocation).To(Equal(&armcompute.ExtendedLocation{
+					Name: to.Ptr("attatlanta1"),
+					Type: to.Ptr(armcompute.ExtendedLocationTypesEdgeZone),
+				}))
 			},
 			expectedError: "",
 		},
@@ -276,10 +418,10 @@ func TestParameters(t *testing.T) {
 				SSHKeyData: "fakesshpublickey",
 				Size:       "Standard_D2v3",
 				Zone:       "1",
-				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:      &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				OSDisk: infrav1.OSDisk{
 					OSType:     "Windows",
-					DiskSizeGB: to.Int32Ptr(128),
+					DiskSizeGB: to.Ptr[int32](128),
 					ManagedDisk: &infrav1.ManagedDiskParameters{
 						StorageAccountType: "Premium_LRS",
 					},
@@ -288,11 +430,11 @@ func TestParameters(t *testing.T) {
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).VirtualMachineProperties.StorageProfile.OsDisk.OsType).To(Equal(compute.OperatingSystemTypesWindows))
-				g.Expect(*result.(compute.VirtualMachine).VirtualMachineProperties.OsProfile.AdminPassword).Should(HaveLen(123))
-				g.Expect(*result.(compute.VirtualMachine).VirtualMachineProperties.OsProfile.AdminUsername).Should(Equal("capi"))
-				g.Expect(*result.(compute.VirtualMachine).VirtualMachineProperties.OsProfile.WindowsConfiguration.EnableAutomaticUpdates).Should(Equal(false))
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.StorageProfile.OSDisk.OSType).To(Equal(to.Ptr(armcompute.OperatingSystemTypesWindows)))
+				g.Expect(*result.(armcompute.VirtualMachine).Properties.OSProfile.AdminPassword).Should(HaveLen(123))
+				g.Expect(*result.(armcompute.VirtualMachine).Properties.OSProfile.AdminUsername).Should(Equal("capi"))
+				g.Expect(*result.(armcompute.VirtualMachine).Properties.OSProfile.WindowsConfiguration.EnableAutomaticUpdates).Should(Equal(false))
 			},
 			expectedError: "",
 		},
@@ -305,7 +447,7 @@ func TestParameters(t *testing.T) {
 				SSHKeyData: "fakesshpublickey",
 				Size:       "Standard_D2v3",
 				Zone:       "1",
-				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:      &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				OSDisk: infrav1.OSDisk{
 					ManagedDisk: &infrav1.ManagedDiskParameters{
 						StorageAccountType: "Premium_LRS",
@@ -318,8 +460,8 @@ func TestParameters(t *testing.T) {
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).VirtualMachineProperties.StorageProfile.OsDisk.ManagedDisk.DiskEncryptionSet.ID).To(Equal(to.StringPtr("my-diskencryptionset-id")))
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.StorageProfile.OSDisk.ManagedDisk.DiskEncryptionSet.ID).To(Equal(to.Ptr("my-diskencryptionset-id")))
 			},
 			expectedError: "",
 		},
@@ -332,14 +474,14 @@ func TestParameters(t *testing.T) {
 				SSHKeyData:      "fakesshpublickey",
 				Size:            "Standard_D2v3",
 				Zone:            "1",
-				Image:           &infrav1.Image{ID: to.StringPtr("fake-image-id")},
-				SecurityProfile: &infrav1.SecurityProfile{EncryptionAtHost: to.BoolPtr(true)},
+				Image:           &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				SecurityProfile: &infrav1.SecurityProfile{EncryptionAtHost: to.Ptr(true)},
 				SKU:             validSKUWithEncryptionAtHost,
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(*result.(compute.VirtualMachine).VirtualMachineProperties.SecurityProfile.EncryptionAtHost).To(Equal(true))
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(*result.(armcompute.VirtualMachine).Properties.SecurityProfile.EncryptionAtHost).To(Equal(true))
 			},
 			expectedError: "",
 		},
@@ -353,14 +495,14 @@ func TestParameters(t *testing.T) {
 				Size:              "Standard_D2v3",
 				AvailabilitySetID: "fake-availability-set-id",
 				Zone:              "",
-				Image:             &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:             &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				SKU:               validSKU,
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).Zones).To(BeNil())
-				g.Expect(result.(compute.VirtualMachine).AvailabilitySet.ID).To(Equal(to.StringPtr("fake-availability-set-id")))
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Zones).To(BeNil())
+				g.Expect(result.(armcompute.VirtualMachine).Properties.AvailabilitySet.ID).To(Equal(to.Ptr("fake-availability-set-id")))
 			},
 			expectedError: "",
 		},
@@ -374,21 +516,21 @@ func TestParameters(t *testing.T) {
 				Size:       "Standard_D2v3",
 				OSDisk: infrav1.OSDisk{
 					OSType:     "Linux",
-					DiskSizeGB: to.Int32Ptr(128),
+					DiskSizeGB: to.Ptr[int32](128),
 					ManagedDisk: &infrav1.ManagedDiskParameters{
 						StorageAccountType: "Premium_LRS",
 					},
 					DiffDiskSettings: &infrav1.DiffDiskSettings{
-						Option: string(compute.DiffDiskOptionsLocal),
+						Option: string(armcompute.DiffDiskOptionsLocal),
 					},
 				},
-				Image: &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image: &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				SKU:   validSKUWithEphemeralOS,
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).StorageProfile.OsDisk.DiffDiskSettings.Option).To(Equal(compute.DiffDiskOptionsLocal))
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.StorageProfile.OSDisk.DiffDiskSettings.Option).To(Equal(to.Ptr(armcompute.DiffDiskOptionsLocal)))
 			},
 			expectedError: "",
 		},
@@ -402,8 +544,8 @@ func TestParameters(t *testing.T) {
 				Size:              "Standard_D2v3",
 				AvailabilitySetID: "fake-availability-set-id",
 				Zone:              "",
-				Image:             &infrav1.Image{ID: to.StringPtr("fake-image-id")},
-				SecurityProfile:   &infrav1.SecurityProfile{EncryptionAtHost: to.BoolPtr(true)},
+				Image:             &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				SecurityProfile:   &infrav1.SecurityProfile{EncryptionAtHost: to.Ptr(true)},
 				SKU:               validSKU,
 			},
 			existing: nil,
@@ -422,15 +564,15 @@ func TestParameters(t *testing.T) {
 				Size:       "Standard_D2v3",
 				OSDisk: infrav1.OSDisk{
 					OSType:     "Linux",
-					DiskSizeGB: to.Int32Ptr(128),
+					DiskSizeGB: to.Ptr[int32](128),
 					ManagedDisk: &infrav1.ManagedDiskParameters{
 						StorageAccountType: "Premium_LRS",
 					},
 					DiffDiskSettings: &infrav1.DiffDiskSettings{
-						Option: string(compute.DiffDiskOptionsLocal),
+						Option: string(armcompute.DiffDiskOptionsLocal),
 					},
 				},
-				Image: &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image: &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				SKU:   validSKU,
 			},
 			existing: nil,
@@ -439,6 +581,278 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "reconcile error that cannot be recovered occurred: vm size Standard_D2v3 does not support ephemeral os. select a different vm size or disable ephemeral os. Object will not be requeued",
 		},
+		{
+			name: "can create a vm with hibernation enabled",
+			spec: &VMSpec{
+				Name:                   "my-vm",
+				Role:                   infrav1.Node,
+				NICIDs:                 []string{"my-nic"},
+				SSHKeyData:             "fakesshpublickey",
+				Size:                   "Standard_D2v3",
+				Image:                  &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				AdditionalCapabilities: &infrav1.AdditionalCapabilities{HibernationEnabled: to.Ptr(true)},
+				SKU: resourceskus.SKU{
+					Capabilities: []*armcompute.ResourceSKUCapabilities{
+						{Name: to.Ptr(resourceskus.VCPUs), Value: to.Ptr("2")},
+						{Name: to.Ptr(resourceskus.MemoryGB), Value: to.Ptr("4")},
+						{Name: to.Ptr(resourceskus.HibernationSupported), Value: to.Ptr("True")},
+					},
+				},
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.AdditionalCapabilities.HibernationEnabled).To(Equal(to.Ptr(true)))
+			},
+			expectedError: "",
+		},
+		{
+			name: "cannot create vm with hibernation enabled if the vm size does not support it",
+			spec: &VMSpec{
+				Name:                   "my-vm",
+				Role:                   infrav1.Node,
+				NICIDs:                 []string{"my-nic"},
+				SSHKeyData:             "fakesshpublickey",
+				Size:                   "Standard_D2v3",
+				Image:                  &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				AdditionalCapabilities: &infrav1.AdditionalCapabilities{HibernationEnabled: to.Ptr(true)},
+				SKU:                    validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: vm size Standard_D2v3 does not support hibernation. select a different vm size or disable hibernation. Object will not be requeued",
+		},
+		{
+			name: "can create a vm with an NVMe disk controller",
+			spec: &VMSpec{
+				Name:               "my-vm",
+				Role:               infrav1.Node,
+				NICIDs:             []string{"my-nic"},
+				SSHKeyData:         "fakesshpublickey",
+				Size:               "Standard_E2bds_v5",
+				DiskControllerType: infrav1.DiskControllerTypeNVMe,
+				Image:              &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				SKU:                validSKUWithNVMe,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "cannot create vm with an NVMe disk controller if the vm size does not support it",
+			spec: &VMSpec{
+				Name:               "my-vm",
+				Role:               infrav1.Node,
+				NICIDs:             []string{"my-nic"},
+				SSHKeyData:         "fakesshpublickey",
+				Size:               "Standard_D2v3",
+				DiskControllerType: infrav1.DiskControllerTypeNVMe,
+				Image:              &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				SKU:                validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: vm size Standard_D2v3 does not support the NVMe disk controller. select a different vm size or disk controller type. Object will not be requeued",
+		},
+		{
+			name: "can create a vm with a SCSI disk controller if the vm size does not list its disk controllers",
+			spec: &VMSpec{
+				Name:               "my-vm",
+				Role:               infrav1.Node,
+				NICIDs:             []string{"my-nic"},
+				SSHKeyData:         "fakesshpublickey",
+				Size:               "Standard_D2v3",
+				DiskControllerType: infrav1.DiskControllerTypeSCSI,
+				Image:              &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				SKU:                validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a vm with write accelerator enabled",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_M8ms",
+				OSDisk: infrav1.OSDisk{
+					OSType: "Linux",
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+					CachingType: "ReadOnly",
+				},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "etcddisk",
+						DiskSizeGB: 256,
+						Lun:        to.Ptr[int32](0),
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+						},
+						CachingType:             "None",
+						WriteAcceleratorEnabled: to.Ptr(true),
+					},
+				},
+				Image: &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				SKU:   validSKUWithWriteAccelerator,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				storageProfile := result.(armcompute.VirtualMachine).Properties.StorageProfile
+				g.Expect(storageProfile.OSDisk.Caching).To(Equal(to.Ptr(armcompute.CachingTypesReadOnly)))
+				g.Expect(storageProfile.OSDisk.WriteAcceleratorEnabled).To(BeNil())
+				g.Expect(storageProfile.DataDisks[0].Caching).To(Equal(to.Ptr(armcompute.CachingTypesNone)))
+				g.Expect(storageProfile.DataDisks[0].WriteAcceleratorEnabled).To(Equal(to.Ptr(true)))
+			},
+			expectedError: "",
+		},
+		{
+			name: "cannot create vm with write accelerator enabled on more disks than the vm size supports",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_M8ms",
+				OSDisk: infrav1.OSDisk{
+					OSType: "Linux",
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+					CachingType:             "None",
+					WriteAcceleratorEnabled: to.Ptr(true),
+				},
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "etcddisk",
+						DiskSizeGB: 256,
+						Lun:        to.Ptr[int32](0),
+						ManagedDisk: &infrav1.ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+						},
+						CachingType:             "None",
+						WriteAcceleratorEnabled: to.Ptr(true),
+					},
+				},
+				Image: &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				SKU:   validSKUWithWriteAccelerator,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: vm size Standard_M8ms does not support write accelerator on 2 disks. select a different vm size or disable write accelerator. Object will not be requeued",
+		},
+		{
+			name: "cannot create vm with write accelerator enabled if the vm size does not support it",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				OSDisk: infrav1.OSDisk{
+					OSType: "Linux",
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "Premium_LRS",
+					},
+					CachingType:             "None",
+					WriteAcceleratorEnabled: to.Ptr(true),
+				},
+				Image: &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				SKU:   validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "reconcile error that cannot be recovered occurred: vm size Standard_D2v3 does not support write accelerator on 1 disks. select a different vm size or disable write accelerator. Object will not be requeued",
+		},
+		{
+			name: "can create a vm with an existing data disk",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix:     "etcddisk",
+						ExistingDiskID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-disk",
+						Lun:            to.Ptr[int32](0),
+						CachingType:    "None",
+					},
+				},
+				Image: &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				SKU:   validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.StorageProfile.DataDisks).To(Equal([]*armcompute.DataDisk{
+					{
+						CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesAttach),
+						DeleteOption: to.Ptr(armcompute.DiskDeleteOptionTypesDetach),
+						Lun:          to.Ptr[int32](0),
+						Caching:      to.Ptr(armcompute.CachingTypesNone),
+						ManagedDisk: &armcompute.ManagedDiskParameters{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-disk"),
+						},
+					},
+				}))
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a vm with an OS disk created from a snapshot",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				OSDisk: infrav1.OSDisk{
+					OSType:           "Linux",
+					DiskSizeGB:       to.Ptr[int32](128),
+					SourceSnapshotID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/snapshots/my-snapshot",
+				},
+				OSDiskName: "my-vm_OSDisk",
+				OSDiskID:   "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-vm_OSDisk",
+				Image:      &infrav1.Image{ID: to.Ptr("fake-image-id")},
+				SKU:        validSKU,
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				vm := result.(armcompute.VirtualMachine)
+				g.Expect(vm.Properties.OSProfile).To(BeNil())
+				g.Expect(vm.Properties.StorageProfile.ImageReference).To(BeNil())
+				g.Expect(*vm.Properties.StorageProfile.OSDisk).To(Equal(armcompute.OSDisk{
+					Name:         to.Ptr("my-vm_OSDisk"),
+					OSType:       to.Ptr(armcompute.OperatingSystemTypesLinux),
+					CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesAttach),
+					DeleteOption: to.Ptr(armcompute.DiskDeleteOptionTypesDelete),
+					ManagedDisk: &armcompute.ManagedDiskParameters{
+						ID: to.Ptr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-vm_OSDisk"),
+					},
+				}))
+			},
+			expectedError: "",
+		},
 		{
 			name: "cannot create vm if vCPU is less than 2",
 			spec: &VMSpec{
@@ -447,7 +861,7 @@ func TestParameters(t *testing.T) {
 				NICIDs:     []string{"my-nic"},
 				SSHKeyData: "fakesshpublickey",
 				Size:       "Standard_D2v3",
-				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:      &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				SKU:        invalidCPUSKU,
 			},
 			existing: nil,
@@ -464,7 +878,7 @@ func TestParameters(t *testing.T) {
 				NICIDs:     []string{"my-nic"},
 				SSHKeyData: "fakesshpublickey",
 				Size:       "Standard_D2v3",
-				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:      &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				SKU:        invalidMemSKU,
 			},
 			existing: nil,
@@ -496,14 +910,14 @@ func TestParameters(t *testing.T) {
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).StorageProfile.ImageReference.Offer).To(Equal(to.StringPtr("my-offer")))
-				g.Expect(result.(compute.VirtualMachine).StorageProfile.ImageReference.Publisher).To(Equal(to.StringPtr("fake-publisher")))
-				g.Expect(result.(compute.VirtualMachine).StorageProfile.ImageReference.Sku).To(Equal(to.StringPtr("sku-id")))
-				g.Expect(result.(compute.VirtualMachine).StorageProfile.ImageReference.Version).To(Equal(to.StringPtr("1.0")))
-				g.Expect(result.(compute.VirtualMachine).Plan.Name).To(Equal(to.StringPtr("sku-id")))
-				g.Expect(result.(compute.VirtualMachine).Plan.Publisher).To(Equal(to.StringPtr("fake-publisher")))
-				g.Expect(result.(compute.VirtualMachine).Plan.Product).To(Equal(to.StringPtr("my-offer")))
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.StorageProfile.ImageReference.Offer).To(Equal(to.Ptr("my-offer")))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.StorageProfile.ImageReference.Publisher).To(Equal(to.Ptr("fake-publisher")))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.StorageProfile.ImageReference.SKU).To(Equal(to.Ptr("sku-id")))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.StorageProfile.ImageReference.Version).To(Equal(to.Ptr("1.0")))
+				g.Expect(result.(armcompute.VirtualMachine).Plan.Name).To(Equal(to.Ptr("sku-id")))
+				g.Expect(result.(armcompute.VirtualMachine).Plan.Publisher).To(Equal(to.Ptr("fake-publisher")))
+				g.Expect(result.(armcompute.VirtualMachine).Plan.Product).To(Equal(to.Ptr("my-offer")))
 			},
 			expectedError: "",
 		},
@@ -522,20 +936,20 @@ func TestParameters(t *testing.T) {
 						Gallery:        "fake-gallery",
 						Name:           "fake-name",
 						Version:        "1.0",
-						Publisher:      to.StringPtr("fake-publisher"),
-						Offer:          to.StringPtr("my-offer"),
-						SKU:            to.StringPtr("sku-id"),
+						Publisher:      to.Ptr("fake-publisher"),
+						Offer:          to.Ptr("my-offer"),
+						SKU:            to.Ptr("sku-id"),
 					},
 				},
 				SKU: validSKU,
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).StorageProfile.ImageReference.ID).To(Equal(to.StringPtr("/subscriptions/fake-sub-id/resourceGroups/fake-rg/providers/Microsoft.Compute/galleries/fake-gallery/images/fake-name/versions/1.0")))
-				g.Expect(result.(compute.VirtualMachine).Plan.Name).To(Equal(to.StringPtr("sku-id")))
-				g.Expect(result.(compute.VirtualMachine).Plan.Publisher).To(Equal(to.StringPtr("fake-publisher")))
-				g.Expect(result.(compute.VirtualMachine).Plan.Product).To(Equal(to.StringPtr("my-offer")))
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.StorageProfile.ImageReference.ID).To(Equal(to.Ptr("/subscriptions/fake-sub-id/resourceGroups/fake-rg/providers/Microsoft.Compute/galleries/fake-gallery/images/fake-name/versions/1.0")))
+				g.Expect(result.(armcompute.VirtualMachine).Plan.Name).To(Equal(to.Ptr("sku-id")))
+				g.Expect(result.(armcompute.VirtualMachine).Plan.Publisher).To(Equal(to.Ptr("fake-publisher")))
+				g.Expect(result.(armcompute.VirtualMachine).Plan.Product).To(Equal(to.Ptr("my-offer")))
 			},
 			expectedError: "",
 		},
@@ -549,17 +963,17 @@ func TestParameters(t *testing.T) {
 				Size:       "Standard_D2v3",
 				Location:   "test-location",
 				Zone:       "1",
-				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:      &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				DataDisks: []infrav1.DataDisk{
 					{
 						NameSuffix: "mydisk",
 						DiskSizeGB: 64,
-						Lun:        to.Int32Ptr(0),
+						Lun:        to.Ptr[int32](0),
 					},
 					{
 						NameSuffix: "myDiskWithUltraDisk",
 						DiskSizeGB: 128,
-						Lun:        to.Int32Ptr(1),
+						Lun:        to.Ptr[int32](1),
 						ManagedDisk: &infrav1.ManagedDiskParameters{
 							StorageAccountType: "UltraSSD_LRS",
 						},
@@ -567,7 +981,7 @@ func TestParameters(t *testing.T) {
 					{
 						NameSuffix: "myDiskWithManagedDisk",
 						DiskSizeGB: 128,
-						Lun:        to.Int32Ptr(2),
+						Lun:        to.Ptr[int32](2),
 						ManagedDisk: &infrav1.ManagedDiskParameters{
 							StorageAccountType: "Premium_LRS",
 						},
@@ -575,7 +989,7 @@ func TestParameters(t *testing.T) {
 					{
 						NameSuffix: "managedDiskWithEncryption",
 						DiskSizeGB: 128,
-						Lun:        to.Int32Ptr(3),
+						Lun:        to.Ptr[int32](3),
 						ManagedDisk: &infrav1.ManagedDiskParameters{
 							StorageAccountType: "Premium_LRS",
 							DiskEncryptionSet: &infrav1.DiskEncryptionSetParameters{
@@ -588,47 +1002,51 @@ func TestParameters(t *testing.T) {
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).AdditionalCapabilities.UltraSSDEnabled).To(Equal(to.BoolPtr(true)))
-				expectedDataDisks := &[]compute.DataDisk{
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.AdditionalCapabilities.UltraSSDEnabled).To(Equal(to.Ptr(true)))
+				expectedDataDisks := []*armcompute.DataDisk{
 					{
-						Lun:          to.Int32Ptr(0),
-						Name:         to.StringPtr("my-ultra-ssd-vm_mydisk"),
-						CreateOption: "Empty",
-						DiskSizeGB:   to.Int32Ptr(64),
+						Lun:          to.Ptr[int32](0),
+						Name:         to.Ptr("my-ultra-ssd-vm_mydisk"),
+						CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+						DeleteOption: to.Ptr(armcompute.DiskDeleteOptionTypesDelete),
+						DiskSizeGB:   to.Ptr[int32](64),
 					},
 					{
-						Lun:          to.Int32Ptr(1),
-						Name:         to.StringPtr("my-ultra-ssd-vm_myDiskWithUltraDisk"),
-						CreateOption: "Empty",
-						DiskSizeGB:   to.Int32Ptr(128),
-						ManagedDisk: &compute.ManagedDiskParameters{
-							StorageAccountType: "UltraSSD_LRS",
+						Lun:          to.Ptr[int32](1),
+						Name:         to.Ptr("my-ultra-ssd-vm_myDiskWithUltraDisk"),
+						CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+						DeleteOption: to.Ptr(armcompute.DiskDeleteOptionTypesDelete),
+						DiskSizeGB:   to.Ptr[int32](128),
+						ManagedDisk: &armcompute.ManagedDiskParameters{
+							StorageAccountType: to.Ptr(armcompute.StorageAccountTypes("UltraSSD_LRS")),
 						},
 					},
 					{
-						Lun:          to.Int32Ptr(2),
-						Name:         to.StringPtr("my-ultra-ssd-vm_myDiskWithManagedDisk"),
-						CreateOption: "Empty",
-						DiskSizeGB:   to.Int32Ptr(128),
-						ManagedDisk: &compute.ManagedDiskParameters{
-							StorageAccountType: "Premium_LRS",
+						Lun:          to.Ptr[int32](2),
+						Name:         to.Ptr("my-ultra-ssd-vm_myDiskWithManagedDisk"),
+						CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+						DeleteOption: to.Ptr(armcompute.DiskDeleteOptionTypesDelete),
+						DiskSizeGB:   to.Ptr[int32](128),
+						ManagedDisk: &armcompute.ManagedDiskParameters{
+							StorageAccountType: to.Ptr(armcompute.StorageAccountTypes("Premium_LRS")),
 						},
 					},
 					{
-						Lun:          to.Int32Ptr(3),
-						Name:         to.StringPtr("my-ultra-ssd-vm_managedDiskWithEncryption"),
-						CreateOption: "Empty",
-						DiskSizeGB:   to.Int32Ptr(128),
-						ManagedDisk: &compute.ManagedDiskParameters{
-							StorageAccountType: "Premium_LRS",
-							DiskEncryptionSet: &compute.DiskEncryptionSetParameters{
-								ID: to.StringPtr("my_id"),
+						Lun:          to.Ptr[int32](3),
+						Name:         to.Ptr("my-ultra-ssd-vm_managedDiskWithEncryption"),
+						CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+						DeleteOption: to.Ptr(armcompute.DiskDeleteOptionTypesDelete),
+						DiskSizeGB:   to.Ptr[int32](128),
+						ManagedDisk: &armcompute.ManagedDiskParameters{
+							StorageAccountType: to.Ptr(armcompute.StorageAccountTypes("Premium_LRS")),
+							DiskEncryptionSet: &armcompute.DiskEncryptionSetParameters{
+								ID: to.Ptr("my_id"),
 							},
 						},
 					},
 				}
-				g.Expect(gomockinternal.DiffEq(expectedDataDisks).Matches(result.(compute.VirtualMachine).StorageProfile.DataDisks)).To(BeTrue(), cmp.Diff(expectedDataDisks, result.(compute.VirtualMachine).StorageProfile.DataDisks))
+				g.Expect(gomockinternal.DiffEq(expectedDataDisks).Matches(result.(armcompute.VirtualMachine).Properties.StorageProfile.DataDisks)).To(BeTrue(), cmp.Diff(expectedDataDisks, result.(armcompute.VirtualMachine).Properties.StorageProfile.DataDisks))
 			},
 			expectedError: "",
 		},
@@ -642,12 +1060,12 @@ func TestParameters(t *testing.T) {
 				Size:       "Standard_D2v3",
 				Location:   "test-location",
 				Zone:       "1",
-				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:      &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				DataDisks: []infrav1.DataDisk{
 					{
 						NameSuffix: "myDiskWithUltraDisk",
 						DiskSizeGB: 128,
-						Lun:        to.Int32Ptr(1),
+						Lun:        to.Ptr[int32](1),
 						ManagedDisk: &infrav1.ManagedDiskParameters{
 							StorageAccountType: "UltraSSD_LRS",
 						},
@@ -671,15 +1089,15 @@ func TestParameters(t *testing.T) {
 				Size:       "Standard_D2v3",
 				Location:   "test-location",
 				Zone:       "1",
-				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:      &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				AdditionalCapabilities: &infrav1.AdditionalCapabilities{
-					UltraSSDEnabled: to.BoolPtr(false),
+					UltraSSDEnabled: to.Ptr(false),
 				},
 				DataDisks: []infrav1.DataDisk{
 					{
 						NameSuffix: "myDiskWithUltraDisk",
 						DiskSizeGB: 128,
-						Lun:        to.Int32Ptr(1),
+						Lun:        to.Ptr[int32](1),
 						ManagedDisk: &infrav1.ManagedDiskParameters{
 							StorageAccountType: "UltraSSD_LRS",
 						},
@@ -689,20 +1107,21 @@ func TestParameters(t *testing.T) {
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).AdditionalCapabilities.UltraSSDEnabled).To(Equal(to.BoolPtr(false)))
-				expectedDataDisks := &[]compute.DataDisk{
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.AdditionalCapabilities.UltraSSDEnabled).To(Equal(to.Ptr(false)))
+				expectedDataDisks := []*armcompute.DataDisk{
 					{
-						Lun:          to.Int32Ptr(1),
-						Name:         to.StringPtr("my-ultra-ssd-vm_myDiskWithUltraDisk"),
-						CreateOption: "Empty",
-						DiskSizeGB:   to.Int32Ptr(128),
-						ManagedDisk: &compute.ManagedDiskParameters{
-							StorageAccountType: "UltraSSD_LRS",
+						Lun:          to.Ptr[int32](1),
+						Name:         to.Ptr("my-ultra-ssd-vm_myDiskWithUltraDisk"),
+						CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+						DeleteOption: to.Ptr(armcompute.DiskDeleteOptionTypesDelete),
+						DiskSizeGB:   to.Ptr[int32](128),
+						ManagedDisk: &armcompute.ManagedDiskParameters{
+							StorageAccountType: to.Ptr(armcompute.StorageAccountTypes("UltraSSD_LRS")),
 						},
 					},
 				}
-				g.Expect(gomockinternal.DiffEq(expectedDataDisks).Matches(result.(compute.VirtualMachine).StorageProfile.DataDisks)).To(BeTrue(), cmp.Diff(expectedDataDisks, result.(compute.VirtualMachine).StorageProfile.DataDisks))
+				g.Expect(gomockinternal.DiffEq(expectedDataDisks).Matches(result.(armcompute.VirtualMachine).Properties.StorageProfile.DataDisks)).To(BeTrue(), cmp.Diff(expectedDataDisks, result.(armcompute.VirtualMachine).Properties.StorageProfile.DataDisks))
 			},
 			expectedError: "",
 		},
@@ -716,12 +1135,12 @@ func TestParameters(t *testing.T) {
 				Size:       "Standard_D2v3",
 				Location:   "test-location",
 				Zone:       "1",
-				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:      &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				DataDisks: []infrav1.DataDisk{
 					{
 						NameSuffix: "myDiskWithUltraDisk",
 						DiskSizeGB: 128,
-						Lun:        to.Int32Ptr(1),
+						Lun:        to.Ptr[int32](1),
 						ManagedDisk: &infrav1.ManagedDiskParameters{
 							StorageAccountType: "UltraSSD_LRS",
 						},
@@ -731,20 +1150,21 @@ func TestParameters(t *testing.T) {
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).AdditionalCapabilities.UltraSSDEnabled).To(Equal(to.BoolPtr(true)))
-				expectedDataDisks := &[]compute.DataDisk{
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.AdditionalCapabilities.UltraSSDEnabled).To(Equal(to.Ptr(true)))
+				expectedDataDisks := []*armcompute.DataDisk{
 					{
-						Lun:          to.Int32Ptr(1),
-						Name:         to.StringPtr("my-ultra-ssd-vm_myDiskWithUltraDisk"),
-						CreateOption: "Empty",
-						DiskSizeGB:   to.Int32Ptr(128),
-						ManagedDisk: &compute.ManagedDiskParameters{
-							StorageAccountType: "UltraSSD_LRS",
+						Lun:          to.Ptr[int32](1),
+						Name:         to.Ptr("my-ultra-ssd-vm_myDiskWithUltraDisk"),
+						CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+						DeleteOption: to.Ptr(armcompute.DiskDeleteOptionTypesDelete),
+						DiskSizeGB:   to.Ptr[int32](128),
+						ManagedDisk: &armcompute.ManagedDiskParameters{
+							StorageAccountType: to.Ptr(armcompute.StorageAccountTypes("UltraSSD_LRS")),
 						},
 					},
 				}
-				g.Expect(gomockinternal.DiffEq(expectedDataDisks).Matches(result.(compute.VirtualMachine).StorageProfile.DataDisks)).To(BeTrue(), cmp.Diff(expectedDataDisks, result.(compute.VirtualMachine).StorageProfile.DataDisks))
+				g.Expect(gomockinternal.DiffEq(expectedDataDisks).Matches(result.(armcompute.VirtualMachine).Properties.StorageProfile.DataDisks)).To(BeTrue(), cmp.Diff(expectedDataDisks, result.(armcompute.VirtualMachine).Properties.StorageProfile.DataDisks))
 			},
 			expectedError: "",
 		},
@@ -758,15 +1178,15 @@ func TestParameters(t *testing.T) {
 				Size:       "Standard_D2v3",
 				Location:   "test-location",
 				Zone:       "1",
-				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:      &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				AdditionalCapabilities: &infrav1.AdditionalCapabilities{
-					UltraSSDEnabled: to.BoolPtr(true),
+					UltraSSDEnabled: to.Ptr(true),
 				},
 				DataDisks: []infrav1.DataDisk{
 					{
 						NameSuffix: "myDiskWithUltraDisk",
 						DiskSizeGB: 128,
-						Lun:        to.Int32Ptr(1),
+						Lun:        to.Ptr[int32](1),
 						ManagedDisk: &infrav1.ManagedDiskParameters{
 							StorageAccountType: "UltraSSD_LRS",
 						},
@@ -776,20 +1196,21 @@ func TestParameters(t *testing.T) {
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).AdditionalCapabilities.UltraSSDEnabled).To(Equal(to.BoolPtr(true)))
-				expectedDataDisks := &[]compute.DataDisk{
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.AdditionalCapabilities.UltraSSDEnabled).To(Equal(to.Ptr(true)))
+				expectedDataDisks := []*armcompute.DataDisk{
 					{
-						Lun:          to.Int32Ptr(1),
-						Name:         to.StringPtr("my-ultra-ssd-vm_myDiskWithUltraDisk"),
-						CreateOption: "Empty",
-						DiskSizeGB:   to.Int32Ptr(128),
-						ManagedDisk: &compute.ManagedDiskParameters{
-							StorageAccountType: "UltraSSD_LRS",
+						Lun:          to.Ptr[int32](1),
+						Name:         to.Ptr("my-ultra-ssd-vm_myDiskWithUltraDisk"),
+						CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+						DeleteOption: to.Ptr(armcompute.DiskDeleteOptionTypesDelete),
+						DiskSizeGB:   to.Ptr[int32](128),
+						ManagedDisk: &armcompute.ManagedDiskParameters{
+							StorageAccountType: to.Ptr(armcompute.StorageAccountTypes("UltraSSD_LRS")),
 						},
 					},
 				}
-				g.Expect(gomockinternal.DiffEq(expectedDataDisks).Matches(result.(compute.VirtualMachine).StorageProfile.DataDisks)).To(BeTrue(), cmp.Diff(expectedDataDisks, result.(compute.VirtualMachine).StorageProfile.DataDisks))
+				g.Expect(gomockinternal.DiffEq(expectedDataDisks).Matches(result.(armcompute.VirtualMachine).Properties.StorageProfile.DataDisks)).To(BeTrue(), cmp.Diff(expectedDataDisks, result.(armcompute.VirtualMachine).Properties.StorageProfile.DataDisks))
 			},
 			expectedError: "",
 		},
@@ -803,16 +1224,16 @@ func TestParameters(t *testing.T) {
 				Size:       "Standard_D2v3",
 				Location:   "test-location",
 				Zone:       "1",
-				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:      &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				AdditionalCapabilities: &infrav1.AdditionalCapabilities{
-					UltraSSDEnabled: to.BoolPtr(true),
+					UltraSSDEnabled: to.Ptr(true),
 				},
 				SKU: validSKUWithUltraSSD,
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).AdditionalCapabilities.UltraSSDEnabled).To(Equal(to.BoolPtr(true)))
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.AdditionalCapabilities.UltraSSDEnabled).To(Equal(to.Ptr(true)))
 			},
 			expectedError: "",
 		},
@@ -826,16 +1247,16 @@ func TestParameters(t *testing.T) {
 				Size:       "Standard_D2v3",
 				Location:   "test-location",
 				Zone:       "1",
-				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Image:      &infrav1.Image{ID: to.Ptr("fake-image-id")},
 				AdditionalCapabilities: &infrav1.AdditionalCapabilities{
-					UltraSSDEnabled: to.BoolPtr(false),
+					UltraSSDEnabled: to.Ptr(false),
 				},
 				SKU: validSKUWithUltraSSD,
 			},
 			existing: nil,
 			expect: func(g *WithT, result interface{}) {
-				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
-				g.Expect(result.(compute.VirtualMachine).AdditionalCapabilities.UltraSSDEnabled).To(Equal(to.BoolPtr(false)))
+				g.Expect(result).To(BeAssignableToTypeOf(armcompute.VirtualMachine{}))
+				g.Expect(result.(armcompute.VirtualMachine).Properties.AdditionalCapabilities.UltraSSDEnabled).To(Equal(to.Ptr(false)))
 			},
 			expectedError: "",
 		},
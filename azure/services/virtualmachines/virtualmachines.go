@@ -18,15 +18,16 @@ package virtualmachines
 
 import (
 	"context"
-	"strings"
+	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
@@ -37,6 +38,10 @@ import (
 
 const serviceName = "virtualmachine"
 
+// deleteOptionsUpdateRequeue is how long to wait before deleting a virtual machine after updating the delete options
+// of its data disks.
+const deleteOptionsUpdateRequeue = 10 * time.Second
+
 // VMScope defines the scope interface for a virtual machines service.
 type VMScope interface {
 	azure.Authorizer
@@ -46,25 +51,41 @@ type VMScope interface {
 	SetProviderID(string)
 	SetAddresses([]corev1.NodeAddress)
 	SetVMState(infrav1.ProvisioningState)
+	SetPowerState(infrav1.PowerState)
+	SetPlacement(*infrav1.VMPlacement)
+	DesiredPowerState() infrav1.PowerState
 }
 
 // Service provides operations on Azure resources.
 type Service struct {
 	Scope VMScope
 	async.Reconciler
+	client           Client
 	interfacesGetter async.Getter
 	publicIPsClient  publicips.Client
 }
 
 // New creates a new service.
-func New(scope VMScope) *Service {
-	Client := NewClient(scope)
+func New(scope VMScope) (*Service, error) {
+	Client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	interfacesClient, err := networkinterfaces.NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
+	publicIPsClient, err := publicips.NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:            scope,
-		interfacesGetter: networkinterfaces.NewClient(scope),
-		publicIPsClient:  publicips.NewClient(scope),
-		Reconciler:       async.New(scope, Client, Client),
-	}
+		client:           Client,
+		interfacesGetter: interfacesClient,
+		publicIPsClient:  publicIPsClient,
+		Reconciler:       async.NewPollerService(scope, Client, Client),
+	}, nil
 }
 
 // Name returns the service name.
@@ -74,7 +95,7 @@ func (s *Service) Name() string {
 
 // Reconcile gets/creates/updates a virtual machine.
 func (s *Service) Reconcile(ctx context.Context) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.Reconcile")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.Reconcile")
 	defer done()
 
 	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureServiceReconcileTimeout)
@@ -90,9 +111,9 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	// Set the DiskReady condition here since the disk gets created with the VM.
 	s.Scope.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, err)
 	if err == nil && result != nil {
-		vm, ok := result.(compute.VirtualMachine)
+		vm, ok := result.(armcompute.VirtualMachine)
 		if !ok {
-			return errors.Errorf("%T is not a compute.VirtualMachine", result)
+			return errors.Errorf("%T is not an armcompute.VirtualMachine", result)
 		}
 		infraVM, err := converters.SDKToVM(vm)
 		if err != nil {
@@ -108,10 +129,56 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 		s.Scope.SetAddresses(addresses)
 		s.Scope.SetVMState(infraVM.State)
+
+		// The placement is only known when the VM was read with its instance view, not right after its creation.
+		if infraVM.Placement != nil {
+			s.Scope.SetPlacement(infraVM.Placement)
+		}
+
+		// The power state is only known when the VM was read with its instance view, not right after its creation.
+		if infraVM.PowerState != "" {
+			powerState := infraVM.PowerState
+			switch desiredPowerState := s.Scope.DesiredPowerState(); {
+			case desiredPowerState == infrav1.PowerStateRunning && isStopped(powerState):
+				log.V(2).Info("starting virtual machine", tele.LogKeyResource, vmSpec.ResourceName(), "powerState", powerState)
+				if err := s.client.Start(ctx, vmSpec.ResourceGroupName(), vmSpec.ResourceName()); err != nil {
+					return errors.Wrap(err, "failed to start virtual machine")
+				}
+				powerState = infrav1.PowerStateStarting
+			case desiredPowerState == infrav1.PowerStateDeallocated && !isDeallocated(powerState):
+				log.V(2).Info("deallocating virtual machine", tele.LogKeyResource, vmSpec.ResourceName(), "powerState", powerState)
+				if err := s.client.Deallocate(ctx, vmSpec.ResourceGroupName(), vmSpec.ResourceName()); err != nil {
+					return errors.Wrap(err, "failed to deallocate virtual machine")
+				}
+				powerState = infrav1.PowerStateDeallocating
+			case desiredPowerState == infrav1.PowerStateHibernated && !isDeallocated(powerState):
+				log.V(2).Info("hibernating virtual machine", tele.LogKeyResource, vmSpec.ResourceName(), "powerState", powerState)
+				if err := s.client.Hibernate(ctx, vmSpec.ResourceGroupName(), vmSpec.ResourceName()); err != nil {
+					return errors.Wrap(err, "failed to hibernate virtual machine")
+				}
+				powerState = infrav1.PowerStateDeallocating
+			}
+			s.Scope.SetPowerState(powerState)
+		}
 	}
 	return err
 }
 
+// isStopped returns true if the virtual machine is stopped or deallocated, or on its way to be.
+func isStopped(powerState infrav1.PowerState) bool {
+	switch powerState {
+	case infrav1.PowerStateStopping, infrav1.PowerStateStopped, infrav1.PowerStateDeallocating, infrav1.PowerStateDeallocated, infrav1.PowerStateHibernated:
+		return true
+	default:
+		return false
+	}
+}
+
+// isDeallocated returns true if the virtual machine is deallocated or hibernated, or on its way to be.
+func isDeallocated(powerState infrav1.PowerState) bool {
+	return powerState == infrav1.PowerStateDeallocating || powerState == infrav1.PowerStateDeallocated || powerState == infrav1.PowerStateHibernated
+}
+
 // Delete deletes the virtual machine with the provided name.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.Delete")
@@ -125,6 +192,12 @@ func (s *Service) Delete(ctx context.Context) error {
 		return nil
 	}
 
+	if err := s.updateDataDiskDeleteOptions(ctx, vmSpec); err != nil {
+		s.Scope.SetVMState(infrav1.Deleting)
+		s.Scope.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, err)
+		return err
+	}
+
 	err := s.DeleteResource(ctx, vmSpec, serviceName)
 	if err != nil {
 		s.Scope.SetVMState(infrav1.Deleting)
@@ -135,27 +208,63 @@ func (s *Service) Delete(ctx context.Context) error {
 	return err
 }
 
-func (s *Service) getAddresses(ctx context.Context, vm compute.VirtualMachine, rgName string) ([]corev1.NodeAddress, error) {
+// updateDataDiskDeleteOptions updates the delete options the virtual machine was created with to the delete policies
+// of its data disks, which can change after it was created, so that Azure doesn't delete a data disk to retain along
+// with the virtual machine. It requeues until the update is done.
+func (s *Service) updateDataDiskDeleteOptions(ctx context.Context, spec azure.ResourceSpecGetter) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.updateDataDiskDeleteOptions")
+	defer done()
+
+	vmSpec, ok := spec.(*VMSpec)
+	if !ok || len(vmSpec.DataDisks) == 0 {
+		return nil
+	}
+
+	deleteOptions := make(map[int32]armcompute.DiskDeleteOptionTypes, len(vmSpec.DataDisks))
+	for _, disk := range vmSpec.DataDisks {
+		if disk.Lun != nil {
+			deleteOptions[*disk.Lun] = armcompute.DiskDeleteOptionTypes(disk.GetDeletePolicy())
+		}
+	}
+
+	updated, err := s.client.UpdateDataDiskDeleteOptions(ctx, vmSpec.ResourceGroupName(), vmSpec.ResourceName(), deleteOptions)
+	switch {
+	case azure.ResourceNotFound(err):
+		return nil
+	case err != nil:
+		return errors.Wrapf(err, "failed to update the delete options of the data disks of virtual machine %s", vmSpec.Name)
+	case updated:
+		log.V(2).Info("updated the delete options of the data disks before deleting the virtual machine", tele.LogKeyResource, vmSpec.Name)
+		return azure.WithTransientError(errors.Errorf("updating the delete options of the data disks of virtual machine %s", vmSpec.Name), deleteOptionsUpdateRequeue)
+	}
+	return nil
+}
+
+func (s *Service) getAddresses(ctx context.Context, vm armcompute.VirtualMachine, rgName string) ([]corev1.NodeAddress, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.getAddresses")
 	defer done()
 
 	addresses := []corev1.NodeAddress{
 		{
 			Type:    corev1.NodeInternalDNS,
-			Address: to.String(vm.Name),
+			Address: pointer.StringDeref(vm.Name, ""),
 		},
 	}
-	if vm.NetworkProfile.NetworkInterfaces == nil {
+	if vm.Properties == nil || vm.Properties.NetworkProfile == nil {
 		return addresses, nil
 	}
-	for _, nicRef := range *vm.NetworkProfile.NetworkInterfaces {
-		// The full ID includes the name at the very end. Split the string and pull the last element
+	for _, nicRef := range vm.Properties.NetworkProfile.NetworkInterfaces {
+		// The full ID includes the name at the very end.
 		// Ex: /subscriptions/$SUB/resourceGroups/$RG/providers/Microsoft.Network/networkInterfaces/$NICNAME
 		// We'll check to see if ID is nil and bail early if we don't have it
-		if nicRef.ID == nil {
+		if nicRef == nil || nicRef.ID == nil {
 			continue
 		}
-		nicName := getResourceNameByID(to.String(nicRef.ID))
+		nicID, err := azure.ParseResourceID(pointer.StringDeref(nicRef.ID, ""))
+		if err != nil {
+			return addresses, errors.Wrap(err, "failed to parse network interface ID")
+		}
+		nicName := nicID.Name
 
 		// Fetch nic and append its addresses
 		existingNic, err := s.interfacesGetter.Get(ctx, &networkinterfaces.NICSpec{
@@ -166,64 +275,64 @@ func (s *Service) getAddresses(ctx context.Context, vm compute.VirtualMachine, r
 			return addresses, err
 		}
 
-		nic, ok := existingNic.(network.Interface)
+		nic, ok := existingNic.(armnetwork.Interface)
 		if !ok {
-			return nil, errors.Errorf("%T is not a network.Interface", existingNic)
+			return nil, errors.Errorf("%T is not an armnetwork.Interface", existingNic)
 		}
 
-		if nic.IPConfigurations == nil {
+		addresses = append(addresses, converters.SDKNetworkInterfaceToNodeAddresses(nic)...)
+
+		if nic.Properties == nil {
 			continue
 		}
-		for _, ipConfig := range *nic.IPConfigurations {
-			if ipConfig.PrivateIPAddress != nil {
-				addresses = append(addresses,
-					corev1.NodeAddress{
-						Type:    corev1.NodeInternalIP,
-						Address: to.String(ipConfig.PrivateIPAddress),
-					},
-				)
-			}
-
-			if ipConfig.PublicIPAddress == nil {
+		for _, ipConfig := range nic.Properties.IPConfigurations {
+			if ipConfig == nil || ipConfig.Properties == nil || ipConfig.Properties.PublicIPAddress == nil {
 				continue
 			}
 			// ID is the only field populated in PublicIPAddress sub-resource.
 			// Thus, we have to go fetch the publicIP with the name.
-			publicIPName := getResourceNameByID(to.String(ipConfig.PublicIPAddress.ID))
-			publicNodeAddress, err := s.getPublicIPAddress(ctx, publicIPName, rgName)
+			publicIP, err := azure.ParseResourceID(pointer.StringDeref(ipConfig.Properties.PublicIPAddress.ID, ""))
+			if err != nil {
+				return addresses, errors.Wrap(err, "failed to parse public IP address ID")
+			}
+			publicNodeAddresses, err := s.getPublicIPAddresses(ctx, publicIP.Name, rgName)
 			if err != nil {
 				return addresses, err
 			}
-			addresses = append(addresses, publicNodeAddress)
+			addresses = append(addresses, publicNodeAddresses...)
 		}
 	}
 
-	return addresses, nil
+	return converters.UniqueNodeAddresses(addresses), nil
 }
 
-// getPublicIPAddress will fetch a public ip address resource by name and return a nodeaddresss representation.
-func (s *Service) getPublicIPAddress(ctx context.Context, publicIPAddressName string, rgName string) (corev1.NodeAddress, error) {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.getPublicIPAddress")
+// getPublicIPAddresses will fetch a public ip address resource by name and return its nodeaddress representations.
+func (s *Service) getPublicIPAddresses(ctx context.Context, publicIPAddressName string, rgName string) ([]corev1.NodeAddress, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.getPublicIPAddresses")
 	defer done()
 
-	retAddress := corev1.NodeAddress{}
 	publicIP, err := s.publicIPsClient.Get(ctx, rgName, publicIPAddressName)
 	if err != nil {
-		return retAddress, err
+		return nil, err
 	}
-	retAddress.Type = corev1.NodeExternalIP
-	retAddress.Address = to.String(publicIP.IPAddress)
 
-	return retAddress, nil
+	return converters.SDKPublicIPToNodeAddresses(publicIP), nil
 }
 
-// getResourceNameById takes a resource ID like
-// `/subscriptions/$SUB/resourceGroups/$RG/providers/Microsoft.Network/networkInterfaces/$NICNAME`
-// and parses out the string after the last slash.
-func getResourceNameByID(resourceID string) string {
-	explodedResourceID := strings.Split(resourceID, "/")
-	resourceName := explodedResourceID[len(explodedResourceID)-1]
-	return resourceName
+// ExportResources adds the virtual machine to an ARM template.
+func (s *Service) ExportResources(ctx context.Context, template *armtemplate.Template) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.ExportResources")
+	defer done()
+
+	vmSpec, ok := s.Scope.VMSpec().(*VMSpec)
+	if !ok {
+		return nil
+	}
+
+	// The template describes the virtual machine to create, even once it was created.
+	spec := *vmSpec
+	spec.ProviderID = ""
+	return template.AddSpec(&spec)
 }
 
 // IsManaged returns always returns true as CAPZ does not support BYO VM.
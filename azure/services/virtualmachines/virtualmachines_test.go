@@ -18,13 +18,14 @@ package virtualmachines
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -49,18 +50,18 @@ var (
 		AvailabilitySetID: "availability-set",
 		Identity:          infrav1.VMIdentitySystemAssigned,
 		AdditionalTags:    map[string]string{"foo": "bar"},
-		Image:             &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+		Image:             &infrav1.Image{ID: to.Ptr("fake-image-id")},
 		BootstrapData:     "fake data",
 	}
-	fakeExistingVM = compute.VirtualMachine{
-		ID:   to.StringPtr("test-vm-id"),
-		Name: to.StringPtr("test-vm-name"),
-		VirtualMachineProperties: &compute.VirtualMachineProperties{
-			ProvisioningState: to.StringPtr("Succeeded"),
-			NetworkProfile: &compute.NetworkProfile{
-				NetworkInterfaces: &[]compute.NetworkInterfaceReference{
+	fakeExistingVM = armcompute.VirtualMachine{
+		ID:   to.Ptr("test-vm-id"),
+		Name: to.Ptr("test-vm-name"),
+		Properties: &armcompute.VirtualMachineProperties{
+			ProvisioningState: to.Ptr("Succeeded"),
+			NetworkProfile: &armcompute.NetworkProfile{
+				NetworkInterfaces: []*armcompute.NetworkInterfaceReference{
 					{
-						ID: to.StringPtr("/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.Network/networkInterfaces/nic-1"),
+						ID: to.Ptr("/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.Network/networkInterfaces/nic-1"),
 					},
 				},
 			},
@@ -70,23 +71,26 @@ var (
 		Name:          "nic-1",
 		ResourceGroup: "test-group",
 	}
-	fakeNetworkInterface = network.Interface{
-		InterfacePropertiesFormat: &network.InterfacePropertiesFormat{
-			IPConfigurations: &[]network.InterfaceIPConfiguration{
+	fakeNetworkInterface = armnetwork.Interface{
+		Properties: &armnetwork.InterfacePropertiesFormat{
+			IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
 				{
-					InterfaceIPConfigurationPropertiesFormat: &network.InterfaceIPConfigurationPropertiesFormat{
-						PrivateIPAddress: to.StringPtr("10.0.0.5"),
-						PublicIPAddress: &network.PublicIPAddress{
-							ID: to.StringPtr("/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.Network/publicIPAddresses/pip-1"),
+					Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+						PrivateIPAddress: to.Ptr("10.0.0.5"),
+						PublicIPAddress: &armnetwork.PublicIPAddress{
+							ID: to.Ptr("/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.Network/publicIPAddresses/pip-1"),
 						},
 					},
 				},
 			},
 		},
 	}
-	fakePublicIPs = network.PublicIPAddress{
-		PublicIPAddressPropertiesFormat: &network.PublicIPAddressPropertiesFormat{
-			IPAddress: to.StringPtr("10.0.0.6"),
+	fakePublicIPs = armnetwork.PublicIPAddress{
+		Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+			IPAddress: to.Ptr("10.0.0.6"),
+			DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
+				Fqdn: to.Ptr("test-vm.test-location.cloudapp.azure.com"),
+			},
 		},
 	}
 	fakeNodeAddresses = []corev1.NodeAddress{
@@ -102,27 +106,31 @@ var (
 			Type:    corev1.NodeExternalIP,
 			Address: "10.0.0.6",
 		},
+		{
+			Type:    corev1.NodeExternalDNS,
+			Address: "test-vm.test-location.cloudapp.azure.com",
+		},
 	}
-	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+	internalError = errors.New("Internal Server Error")
 )
 
 func TestReconcileVM(t *testing.T) {
 	testcases := []struct {
 		name          string
 		expectedError string
-		expect        func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+		expect        func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_virtualmachines.MockClientMockRecorder)
 	}{
 		{
 			name:          "noop if no vm spec is found",
 			expectedError: "",
-			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
 				s.VMSpec().Return(nil)
 			},
 		},
 		{
 			name:          "create vm succeeds",
 			expectedError: "",
-			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
 				s.VMSpec().Return(&fakeVMSpec)
 				r.CreateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(fakeExistingVM, nil)
 				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, nil)
@@ -135,10 +143,156 @@ func TestReconcileVM(t *testing.T) {
 				s.SetVMState(infrav1.Succeeded)
 			},
 		},
+		{
+			name:          "stopped vm reports its power state",
+			expectedError: "",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.VMSpec().Return(&fakeVMSpec)
+				r.CreateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(withPowerState(fakeExistingVM, "PowerState/deallocated"), nil)
+				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, nil)
+				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, nil)
+				s.SetProviderID("azure://test-vm-id")
+				s.SetAnnotation("cluster-api-provider-azure", "true")
+				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(fakeNetworkInterface, nil)
+				mpip.Get(gomockinternal.AContext(), "test-group", "pip-1").Return(fakePublicIPs, nil)
+				s.SetAddresses(fakeNodeAddresses)
+				s.SetVMState(infrav1.Succeeded)
+				s.SetPlacement(&infrav1.VMPlacement{})
+				s.DesiredPowerState().Return(infrav1.PowerState(""))
+				s.SetPowerState(infrav1.PowerStateDeallocated)
+			},
+		},
+		{
+			name:          "stopped vm is started when its desired power state is running",
+			expectedError: "",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.VMSpec().Return(&fakeVMSpec)
+				r.CreateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(withPowerState(fakeExistingVM, "PowerState/stopped"), nil)
+				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, nil)
+				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, nil)
+				s.SetProviderID("azure://test-vm-id")
+				s.SetAnnotation("cluster-api-provider-azure", "true")
+				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(fakeNetworkInterface, nil)
+				mpip.Get(gomockinternal.AContext(), "test-group", "pip-1").Return(fakePublicIPs, nil)
+				s.SetAddresses(fakeNodeAddresses)
+				s.SetVMState(infrav1.Succeeded)
+				s.SetPlacement(&infrav1.VMPlacement{})
+				s.DesiredPowerState().Return(infrav1.PowerStateRunning)
+				m.Start(gomockinternal.AContext(), "test-group", "test-vm").Return(nil)
+				s.SetPowerState(infrav1.PowerStateStarting)
+			},
+		},
+		{
+			name:          "running vm is not started again",
+			expectedError: "",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.VMSpec().Return(&fakeVMSpec)
+				r.CreateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(withPowerState(fakeExistingVM, "PowerState/running"), nil)
+				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, nil)
+				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, nil)
+				s.SetProviderID("azure://test-vm-id")
+				s.SetAnnotation("cluster-api-provider-azure", "true")
+				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(fakeNetworkInterface, nil)
+				mpip.Get(gomockinternal.AContext(), "test-group", "pip-1").Return(fakePublicIPs, nil)
+				s.SetAddresses(fakeNodeAddresses)
+				s.SetVMState(infrav1.Succeeded)
+				s.SetPlacement(&infrav1.VMPlacement{})
+				s.DesiredPowerState().Return(infrav1.PowerState(""))
+				s.SetPowerState(infrav1.PowerStateRunning)
+			},
+		},
+		{
+			name:          "running vm is deallocated when its desired power state is deallocated",
+			expectedError: "",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.VMSpec().Return(&fakeVMSpec)
+				r.CreateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(withPowerState(fakeExistingVM, "PowerState/running"), nil)
+				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, nil)
+				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, nil)
+				s.SetProviderID("azure://test-vm-id")
+				s.SetAnnotation("cluster-api-provider-azure", "true")
+				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(fakeNetworkInterface, nil)
+				mpip.Get(gomockinternal.AContext(), "test-group", "pip-1").Return(fakePublicIPs, nil)
+				s.SetAddresses(fakeNodeAddresses)
+				s.SetVMState(infrav1.Succeeded)
+				s.SetPlacement(&infrav1.VMPlacement{})
+				s.DesiredPowerState().Return(infrav1.PowerStateDeallocated)
+				m.Deallocate(gomockinternal.AContext(), "test-group", "test-vm").Return(nil)
+				s.SetPowerState(infrav1.PowerStateDeallocating)
+			},
+		},
+		{
+			name:          "deallocated vm is left as is when its desired power state is deallocated",
+			expectedError: "",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.VMSpec().Return(&fakeVMSpec)
+				r.CreateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(withPowerState(fakeExistingVM, "PowerState/deallocated"), nil)
+				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, nil)
+				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, nil)
+				s.SetProviderID("azure://test-vm-id")
+				s.SetAnnotation("cluster-api-provider-azure", "true")
+				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(fakeNetworkInterface, nil)
+				mpip.Get(gomockinternal.AContext(), "test-group", "pip-1").Return(fakePublicIPs, nil)
+				s.SetAddresses(fakeNodeAddresses)
+				s.SetVMState(infrav1.Succeeded)
+				s.SetPlacement(&infrav1.VMPlacement{})
+				s.DesiredPowerState().Return(infrav1.PowerStateDeallocated)
+				s.SetPowerState(infrav1.PowerStateDeallocated)
+			},
+		},
+		{
+			name:          "running vm is hibernated when its desired power state is hibernated",
+			expectedError: "",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.VMSpec().Return(&fakeVMSpec)
+				r.CreateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(withPowerState(fakeExistingVM, "PowerState/running"), nil)
+				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, nil)
+				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, nil)
+				s.SetProviderID("azure://test-vm-id")
+				s.SetAnnotation("cluster-api-provider-azure", "true")
+				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(fakeNetworkInterface, nil)
+				mpip.Get(gomockinternal.AContext(), "test-group", "pip-1").Return(fakePublicIPs, nil)
+				s.SetAddresses(fakeNodeAddresses)
+				s.SetVMState(infrav1.Succeeded)
+				s.SetPlacement(&infrav1.VMPlacement{})
+				s.DesiredPowerState().Return(infrav1.PowerStateHibernated)
+				m.Hibernate(gomockinternal.AContext(), "test-group", "test-vm").Return(nil)
+				s.SetPowerState(infrav1.PowerStateDeallocating)
+			},
+		},
+		{
+			name:          "vm read with its instance view reports its placement",
+			expectedError: "",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				vm := withPowerState(fakeExistingVM, "PowerState/running")
+				vm.Zones = []*string{to.Ptr("2")}
+				vm.Properties.InstanceView.PlatformFaultDomain = to.Ptr[int32](0)
+				vm.Properties.InstanceView.PlatformUpdateDomain = to.Ptr[int32](3)
+				vm.Properties.ProximityPlacementGroup = &armcompute.SubResource{ID: to.Ptr("ppg-id")}
+				s.VMSpec().Return(&fakeVMSpec)
+				r.CreateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(vm, nil)
+				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, nil)
+				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, nil)
+				s.SetProviderID("azure://test-vm-id")
+				s.SetAnnotation("cluster-api-provider-azure", "true")
+				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(fakeNetworkInterface, nil)
+				mpip.Get(gomockinternal.AContext(), "test-group", "pip-1").Return(fakePublicIPs, nil)
+				s.SetAddresses(fakeNodeAddresses)
+				s.SetVMState(infrav1.Succeeded)
+				s.SetPlacement(&infrav1.VMPlacement{
+					FaultDomain:               to.Ptr[int32](0),
+					UpdateDomain:              to.Ptr[int32](3),
+					AvailabilityZone:          "2",
+					ProximityPlacementGroupID: "ppg-id",
+				})
+				s.DesiredPowerState().Return(infrav1.PowerState(""))
+				s.SetPowerState(infrav1.PowerStateRunning)
+			},
+		},
 		{
 			name:          "creating vm fails",
-			expectedError: "#: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expectedError: "Internal Server Error",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
 				s.VMSpec().Return(&fakeVMSpec)
 				r.CreateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(nil, internalError)
 				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, internalError)
@@ -147,21 +301,21 @@ func TestReconcileVM(t *testing.T) {
 		},
 		{
 			name:          "create vm succeeds but failed to get network interfaces",
-			expectedError: "failed to fetch VM addresses: #: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expectedError: "failed to fetch VM addresses: Internal Server Error",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
 				s.VMSpec().Return(&fakeVMSpec)
 				r.CreateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(fakeExistingVM, nil)
 				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, nil)
 				s.UpdatePutStatus(infrav1.DisksReadyCondition, serviceName, nil)
 				s.SetProviderID("azure://test-vm-id")
 				s.SetAnnotation("cluster-api-provider-azure", "true")
-				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(network.Interface{}, internalError)
+				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(armnetwork.Interface{}, internalError)
 			},
 		},
 		{
 			name:          "create vm succeeds but failed to get public IPs",
-			expectedError: "failed to fetch VM addresses: #: Internal Server Error: StatusCode=500",
-			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+			expectedError: "failed to fetch VM addresses: Internal Server Error",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, mnic *mock_async.MockGetterMockRecorder, mpip *mock_publicips.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
 				s.VMSpec().Return(&fakeVMSpec)
 				r.CreateResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(fakeExistingVM, nil)
 				s.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, nil)
@@ -169,7 +323,7 @@ func TestReconcileVM(t *testing.T) {
 				s.SetProviderID("azure://test-vm-id")
 				s.SetAnnotation("cluster-api-provider-azure", "true")
 				mnic.Get(gomockinternal.AContext(), &fakeNetworkInterfaceGetterSpec).Return(fakeNetworkInterface, nil)
-				mpip.Get(gomockinternal.AContext(), "test-group", "pip-1").Return(network.PublicIPAddress{}, internalError)
+				mpip.Get(gomockinternal.AContext(), "test-group", "pip-1").Return(armnetwork.PublicIPAddress{}, internalError)
 			},
 		},
 	}
@@ -186,11 +340,13 @@ func TestReconcileVM(t *testing.T) {
 			interfaceMock := mock_async.NewMockGetter(mockCtrl)
 			publicIPMock := mock_publicips.NewMockClient(mockCtrl)
 			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+			clientMock := mock_virtualmachines.NewMockClient(mockCtrl)
 
-			tc.expect(scopeMock.EXPECT(), interfaceMock.EXPECT(), publicIPMock.EXPECT(), asyncMock.EXPECT())
+			tc.expect(scopeMock.EXPECT(), interfaceMock.EXPECT(), publicIPMock.EXPECT(), asyncMock.EXPECT(), clientMock.EXPECT())
 
 			s := &Service{
 				Scope:            scopeMock,
+				client:           clientMock,
 				interfacesGetter: interfaceMock,
 				publicIPsClient:  publicIPMock,
 				Reconciler:       asyncMock,
@@ -232,7 +388,7 @@ func TestDeleteVM(t *testing.T) {
 		},
 		{
 			name:          "error occurs when deleting vm",
-			expectedError: "#: Internal Server Error: StatusCode=500",
+			expectedError: "Internal Server Error",
 			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
 				s.VMSpec().AnyTimes().Return(&fakeVMSpec)
 				r.DeleteResource(gomockinternal.AContext(), &fakeVMSpec, serviceName).Return(internalError)
@@ -279,3 +435,104 @@ func TestDeleteVM(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteVMDataDiskDeleteOptions(t *testing.T) {
+	vmSpec := fakeVMSpec
+	vmSpec.DataDisks = []infrav1.DataDisk{
+		{NameSuffix: "etcddisk", Lun: to.Ptr[int32](0)},
+		{NameSuffix: "datadisk", Lun: to.Ptr[int32](1), DeletePolicy: infrav1.DiskDeletePolicyDetach},
+	}
+	deleteOptions := map[int32]armcompute.DiskDeleteOptionTypes{
+		0: armcompute.DiskDeleteOptionTypesDelete,
+		1: armcompute.DiskDeleteOptionTypesDetach,
+	}
+	notFound := &azcore.ResponseError{StatusCode: http.StatusNotFound}
+
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder)
+	}{
+		{
+			name:          "requeues after updating the delete options of the data disks",
+			expectedError: "updating the delete options of the data disks of virtual machine test-vm. Object will be requeued after 10s",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().Return(&vmSpec)
+				m.UpdateDataDiskDeleteOptions(gomockinternal.AContext(), "test-group", "test-vm", deleteOptions).Return(true, nil)
+				s.SetVMState(infrav1.Deleting)
+				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, gomock.Any())
+			},
+		},
+		{
+			name: "deletes the vm once the delete options of the data disks are up to date",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().Return(&vmSpec)
+				m.UpdateDataDiskDeleteOptions(gomockinternal.AContext(), "test-group", "test-vm", deleteOptions).Return(false, nil)
+				r.DeleteResource(gomockinternal.AContext(), &vmSpec, serviceName).Return(nil)
+				s.SetVMState(infrav1.Deleted)
+				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, nil)
+			},
+		},
+		{
+			name: "deletes the vm if it doesn't exist",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().Return(&vmSpec)
+				m.UpdateDataDiskDeleteOptions(gomockinternal.AContext(), "test-group", "test-vm", deleteOptions).Return(false, notFound)
+				r.DeleteResource(gomockinternal.AContext(), &vmSpec, serviceName).Return(nil)
+				s.SetVMState(infrav1.Deleted)
+				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, nil)
+			},
+		},
+		{
+			name:          "fails to update the delete options of the data disks",
+			expectedError: "failed to update the delete options of the data disks of virtual machine test-vm: Internal Server Error",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder, r *mock_async.MockReconcilerMockRecorder) {
+				s.VMSpec().Return(&vmSpec)
+				m.UpdateDataDiskDeleteOptions(gomockinternal.AContext(), "test-group", "test-vm", deleteOptions).Return(false, internalError)
+				s.SetVMState(infrav1.Deleting)
+				s.UpdateDeleteStatus(infrav1.VMRunningCondition, serviceName, gomock.Any())
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_virtualmachines.NewMockVMScope(mockCtrl)
+			clientMock := mock_virtualmachines.NewMockClient(mockCtrl)
+			asyncMock := mock_async.NewMockReconciler(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), asyncMock.EXPECT())
+
+			s := &Service{
+				Scope:      scopeMock,
+				client:     clientMock,
+				Reconciler: asyncMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+// withPowerState returns a copy of the VM with an instance view reporting the given power state status code.
+func withPowerState(vm armcompute.VirtualMachine, code string) armcompute.VirtualMachine {
+	properties := *vm.Properties
+	properties.InstanceView = &armcompute.VirtualMachineInstanceView{
+		Statuses: []*armcompute.InstanceViewStatus{
+			{Code: to.Ptr("ProvisioningState/succeeded")},
+			{Code: to.Ptr(code)},
+		},
+	}
+	vm.Properties = &properties
+	return vm
+}
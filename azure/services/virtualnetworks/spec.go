@@ -17,20 +17,21 @@ limitations under the License.
 package virtualnetworks
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 )
 
 // VNetSpec defines the specification for a Virtual Network.
 type VNetSpec struct {
-	ResourceGroup  string
-	Name           string
-	CIDRs          []string
-	Location       string
-	ClusterName    string
-	AdditionalTags infrav1.Tags
+	ResourceGroup    string
+	Name             string
+	CIDRs            []string
+	Location         string
+	ExtendedLocation *infrav1.ExtendedLocationSpec
+	ClusterName      string
+	AdditionalTags   infrav1.Tags
 }
 
 // ResourceName returns the name of the vnet.
@@ -54,18 +55,19 @@ func (s *VNetSpec) Parameters(existing interface{}) (interface{}, error) {
 		// vnet already exists, nothing to update.
 		return nil, nil
 	}
-	return network.VirtualNetwork{
+	return armnetwork.VirtualNetwork{
 		Tags: converters.TagsToMap(infrav1.Build(infrav1.BuildParams{
 			ClusterName: s.ClusterName,
 			Lifecycle:   infrav1.ResourceLifecycleOwned,
-			Name:        to.StringPtr(s.Name),
-			Role:        to.StringPtr(infrav1.CommonRole),
+			Name:        to.Ptr(s.Name),
+			Role:        to.Ptr(infrav1.CommonRole),
 			Additional:  s.AdditionalTags,
 		})),
-		Location: to.StringPtr(s.Location),
-		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
-			AddressSpace: &network.AddressSpace{
-				AddressPrefixes: &s.CIDRs,
+		Location:         to.Ptr(s.Location),
+		ExtendedLocation: converters.ExtendedLocationToNetworkSDK(s.ExtendedLocation),
+		Properties: &armnetwork.VirtualNetworkPropertiesFormat{
+			AddressSpace: &armnetwork.AddressSpace{
+				AddressPrefixes: to.SliceOfPtrs(s.CIDRs...),
 			},
 		},
 	}, nil
@@ -19,12 +19,14 @@ package virtualnetworks
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aso"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -51,13 +53,16 @@ type Service struct {
 }
 
 // New creates a new service.
-func New(scope VNetScope) *Service {
-	client := newClient(scope)
+func New(scope VNetScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:      scope,
 		Getter:     client,
-		Reconciler: async.New(scope, client, client),
-	}
+		Reconciler: aso.NewReconciler(scope, client, client),
+	}, nil
 }
 
 // Name returns the service name.
@@ -65,6 +70,15 @@ func (s *Service) Name() string {
 	return serviceName
 }
 
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the virtual network.
+func (s *Service) RequiredActions() []string {
+	if !s.Scope.IsVnetManaged() {
+		return nil
+	}
+	return []string{"Microsoft.Network/virtualNetworks/read", "Microsoft.Network/virtualNetworks/write"}
+}
+
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.Service.Reconcile")
 	defer done()
@@ -79,26 +93,30 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	result, err := s.CreateResource(ctx, vnetSpec, serviceName)
 	if err == nil && result != nil {
-		existingVnet, ok := result.(network.VirtualNetwork)
+		existingVnet, ok := result.(armnetwork.VirtualNetwork)
 		if !ok {
-			return errors.Errorf("%T is not a network.VirtualNetwork", result)
+			return errors.Errorf("%T is not an armnetwork.VirtualNetwork", result)
 		}
 		vnet := s.Scope.Vnet()
-		vnet.ID = to.String(existingVnet.ID)
+		vnet.ID = pointer.StringDeref(existingVnet.ID, "")
 		vnet.Tags = converters.MapToTags(existingVnet.Tags)
 
 		var prefixes []string
-		if existingVnet.VirtualNetworkPropertiesFormat != nil && existingVnet.VirtualNetworkPropertiesFormat.AddressSpace != nil {
-			prefixes = to.StringSlice(existingVnet.VirtualNetworkPropertiesFormat.AddressSpace.AddressPrefixes)
+		if existingVnet.Properties != nil && existingVnet.Properties.AddressSpace != nil {
+			for _, prefix := range existingVnet.Properties.AddressSpace.AddressPrefixes {
+				prefixes = append(prefixes, pointer.StringDeref(prefix, ""))
+			}
 		}
 		vnet.CIDRBlocks = prefixes
 
 		// Update the subnet CIDRs if they already exist.
 		// This makes sure the subnet CIDRs are up to date and there are no validation errors when updating the VNet.
 		// Subnets that are not part of this cluster spec are silently ignored.
-		if existingVnet.Subnets != nil {
-			for _, subnet := range *existingVnet.Subnets {
-				s.Scope.UpdateSubnetCIDRs(to.String(subnet.Name), converters.GetSubnetAddresses(subnet))
+		if existingVnet.Properties != nil {
+			for _, subnet := range existingVnet.Properties.Subnets {
+				if subnet != nil {
+					s.Scope.UpdateSubnetCIDRs(pointer.StringDeref(subnet.Name, ""), converters.GetSubnetAddresses(*subnet))
+				}
 			}
 		}
 	}
@@ -144,6 +162,22 @@ func (s *Service) Delete(ctx context.Context) error {
 	return err
 }
 
+// ExportResources adds the virtual network, if it is managed, to an ARM template.
+func (s *Service) ExportResources(ctx context.Context, template *armtemplate.Template) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "virtualnetworks.Service.ExportResources")
+	defer done()
+
+	if !s.Scope.IsVnetManaged() {
+		return nil
+	}
+
+	if spec := s.Scope.VNetSpec(); spec != nil {
+		return template.AddSpec(spec)
+	}
+
+	return nil
+}
+
 // IsManaged returns true if the virtual network has an owned tag with the cluster name as value,
 // meaning that the vnet's lifecycle is managed.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
@@ -159,9 +193,9 @@ func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	vnet, ok := vnetIface.(network.VirtualNetwork)
+	vnet, ok := vnetIface.(armnetwork.VirtualNetwork)
 	if !ok {
-		return false, errors.Errorf("%T is not a network.VirtualNetwork", vnetIface)
+		return false, errors.Errorf("%T is not an armnetwork.VirtualNetwork", vnetIface)
 	}
 	tags := converters.MapToTags(vnet.Tags)
 	return tags.HasOwned(s.Scope.ClusterName()), nil
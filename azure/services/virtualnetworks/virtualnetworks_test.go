@@ -18,12 +18,11 @@ package virtualnetworks
 
 import (
 	"context"
-	"net/http"
+	"errors"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -41,45 +40,45 @@ var (
 		ClusterName:    "test-cluster",
 		AdditionalTags: map[string]string{"foo": "bar"},
 	}
-	managedVnet = network.VirtualNetwork{
-		ID:   to.StringPtr("/subscriptions/subscription/resourceGroups/test-group/providers/Microsoft.Network/virtualNetworks/test-vnet"),
-		Name: to.StringPtr("test-vnet"),
+	managedVnet = armnetwork.VirtualNetwork{
+		ID:   to.Ptr("/subscriptions/subscription/resourceGroups/test-group/providers/Microsoft.Network/virtualNetworks/test-vnet"),
+		Name: to.Ptr("test-vnet"),
 		Tags: map[string]*string{
-			"foo": to.StringPtr("bar"),
-			"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.StringPtr("owned"),
+			"foo": to.Ptr("bar"),
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_test-cluster": to.Ptr("owned"),
 		},
 	}
-	customVnet = network.VirtualNetwork{
-		ID:   to.StringPtr("/subscriptions/subscription/resourceGroups/test-group/providers/Microsoft.Network/virtualNetworks/test-vnet"),
-		Name: to.StringPtr("test-vnet"),
+	customVnet = armnetwork.VirtualNetwork{
+		ID:   to.Ptr("/subscriptions/subscription/resourceGroups/test-group/providers/Microsoft.Network/virtualNetworks/test-vnet"),
+		Name: to.Ptr("test-vnet"),
 		Tags: map[string]*string{
-			"foo":       to.StringPtr("bar"),
-			"something": to.StringPtr("else"),
+			"foo":       to.Ptr("bar"),
+			"something": to.Ptr("else"),
 		},
-		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
-			AddressSpace: &network.AddressSpace{
-				AddressPrefixes: &[]string{"fake-cidr"},
+		Properties: &armnetwork.VirtualNetworkPropertiesFormat{
+			AddressSpace: &armnetwork.AddressSpace{
+				AddressPrefixes: []*string{to.Ptr("fake-cidr")},
 			},
-			Subnets: &[]network.Subnet{
+			Subnets: []*armnetwork.Subnet{
 				{
-					Name: to.StringPtr("test-subnet"),
-					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
-						AddressPrefix: to.StringPtr("subnet-cidr"),
+					Name: to.Ptr("test-subnet"),
+					Properties: &armnetwork.SubnetPropertiesFormat{
+						AddressPrefix: to.Ptr("subnet-cidr"),
 					},
 				},
 				{
-					Name: to.StringPtr("test-subnet-2"),
-					SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
-						AddressPrefixes: &[]string{
-							"subnet-cidr-1",
-							"subnet-cidr-2",
+					Name: to.Ptr("test-subnet-2"),
+					Properties: &armnetwork.SubnetPropertiesFormat{
+						AddressPrefixes: []*string{
+							to.Ptr("subnet-cidr-1"),
+							to.Ptr("subnet-cidr-2"),
 						},
 					},
 				},
 			},
 		},
 	}
-	internalError = autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error")
+	internalError = errors.New("Internal Server Error")
 )
 
 func TestReconcileVnet(t *testing.T) {
@@ -285,7 +284,7 @@ func TestIsVnetManaged(t *testing.T) {
 			expectedError: internalError.Error(),
 			expect: func(s *mock_virtualnetworks.MockVNetScopeMockRecorder, m *mock_async.MockGetterMockRecorder) {
 				s.VNetSpec().Return(&fakeVNetSpec)
-				m.Get(gomockinternal.AContext(), &fakeVNetSpec).Return(network.VirtualNetwork{}, internalError)
+				m.Get(gomockinternal.AContext(), &fakeVNetSpec).Return(armnetwork.VirtualNetwork{}, internalError)
 			},
 		},
 	}
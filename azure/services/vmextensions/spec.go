@@ -17,8 +17,8 @@ limitations under the License.
 package vmextensions
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 )
@@ -48,23 +48,28 @@ func (s *VMExtensionSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the VM extension.
 func (s *VMExtensionSpec) Parameters(existing interface{}) (interface{}, error) {
 	if existing != nil {
-		_, ok := existing.(compute.VirtualMachineExtension)
+		_, ok := existing.(armcompute.VirtualMachineExtension)
 		if !ok {
-			return nil, errors.Errorf("%T is not a compute.VirtualMachineExtension", existing)
+			return nil, errors.Errorf("%T is not an armcompute.VirtualMachineExtension", existing)
 		}
 
 		// VM extension already exists, nothing to update.
 		return nil, nil
 	}
 
-	return compute.VirtualMachineExtension{
-		VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
-			Publisher:          to.StringPtr(s.Publisher),
-			Type:               to.StringPtr(s.Name),
-			TypeHandlerVersion: to.StringPtr(s.Version),
-			Settings:           nil,
+	var settings interface{}
+	if len(s.Settings) > 0 {
+		settings = s.Settings
+	}
+
+	return armcompute.VirtualMachineExtension{
+		Properties: &armcompute.VirtualMachineExtensionProperties{
+			Publisher:          to.Ptr(s.Publisher),
+			Type:               to.Ptr(s.Name),
+			TypeHandlerVersion: to.Ptr(s.Version),
+			Settings:           settings,
 			ProtectedSettings:  s.ProtectedSettings,
 		},
-		Location: to.StringPtr(s.Location),
+		Location: to.Ptr(s.Location),
 	}, nil
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -43,12 +44,15 @@ type Service struct {
 }
 
 // New creates a new vm extension service.
-func New(scope VMExtensionScope) *Service {
-	client := newClient(scope)
+func New(scope VMExtensionScope) (*Service, error) {
+	client, err := newClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:      scope,
-		Reconciler: async.New(scope, client, client),
-	}
+		Reconciler: async.NewPollerService(scope, client, client),
+	}, nil
 }
 
 // Name returns the service name.
@@ -97,6 +101,20 @@ func (s *Service) Delete(_ context.Context) error {
 	return nil
 }
 
+// ExportResources adds the VM extensions to an ARM template.
+func (s *Service) ExportResources(ctx context.Context, template *armtemplate.Template) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "vmextensions.Service.ExportResources")
+	defer done()
+
+	for _, spec := range s.Scope.VMExtensionSpecs() {
+		if err := template.AddSpec(spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // IsManaged returns always returns true as CAPZ does not support BYO VM extension.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	return true, nil
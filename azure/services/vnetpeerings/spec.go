@@ -17,8 +17,8 @@ limitations under the License.
 package vnetpeerings
 
 import (
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 )
@@ -51,20 +51,20 @@ func (s *VnetPeeringSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the virtual network peering.
 func (s *VnetPeeringSpec) Parameters(existing interface{}) (params interface{}, err error) {
 	if existing != nil {
-		if _, ok := existing.(network.VirtualNetworkPeering); !ok {
-			return nil, errors.Errorf("%T is not a network.VnetPeering", existing)
+		if _, ok := existing.(armnetwork.VirtualNetworkPeering); !ok {
+			return nil, errors.Errorf("%T is not an armnetwork.VirtualNetworkPeering", existing)
 		}
 		// virtual network peering already exists
 		return nil, nil
 	}
 	vnetID := azure.VNetID(s.SubscriptionID, s.RemoteResourceGroup, s.RemoteVnetName)
-	peeringProperties := network.VirtualNetworkPeeringPropertiesFormat{
-		RemoteVirtualNetwork: &network.SubResource{
-			ID: to.StringPtr(vnetID),
+	peeringProperties := armnetwork.VirtualNetworkPeeringPropertiesFormat{
+		RemoteVirtualNetwork: &armnetwork.SubResource{
+			ID: to.Ptr(vnetID),
 		},
 	}
-	return network.VirtualNetworkPeering{
-		Name:                                  to.StringPtr(s.PeeringName),
-		VirtualNetworkPeeringPropertiesFormat: &peeringProperties,
+	return armnetwork.VirtualNetworkPeering{
+		Name:       to.Ptr(s.PeeringName),
+		Properties: &peeringProperties,
 	}, nil
 }
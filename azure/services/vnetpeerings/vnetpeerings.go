@@ -21,6 +21,8 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/aso"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -42,12 +44,15 @@ type Service struct {
 }
 
 // New creates a new service.
-func New(scope VnetPeeringScope) *Service {
-	Client := NewClient(scope)
+func New(scope VnetPeeringScope) (*Service, error) {
+	Client, err := NewClient(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &Service{
 		Scope:      scope,
-		Reconciler: async.New(scope, Client, Client),
-	}
+		Reconciler: aso.NewReconciler(scope, Client, Client),
+	}, nil
 }
 
 // Name returns the service name.
@@ -55,6 +60,18 @@ func (s *Service) Name() string {
 	return serviceName
 }
 
+// RequiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster to reconcile the virtual network peerings.
+func (s *Service) RequiredActions() []string {
+	if len(s.Scope.VnetPeeringSpecs()) == 0 {
+		return nil
+	}
+	return []string{
+		"Microsoft.Network/virtualNetworks/virtualNetworkPeerings/read",
+		"Microsoft.Network/virtualNetworks/virtualNetworkPeerings/write",
+	}
+}
+
 // Reconcile gets/creates/updates a peering.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "vnetpeerings.Service.Reconcile")
@@ -112,6 +129,20 @@ func (s *Service) Delete(ctx context.Context) error {
 	return result
 }
 
+// ExportResources adds the virtual network peerings to an ARM template.
+func (s *Service) ExportResources(ctx context.Context, template *armtemplate.Template) error {
+	_, _, done := tele.StartSpanWithLogger(ctx, "vnetpeerings.Service.ExportResources")
+	defer done()
+
+	for _, spec := range s.Scope.VnetPeeringSpecs() {
+		if err := template.AddSpec(spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // IsManaged returns always returns true as CAPZ does not support BYO VNet peering.
 func (s *Service) IsManaged(ctx context.Context) (bool, error) {
 	return true, nil
@@ -18,16 +18,30 @@ package azure
 
 import (
 	"reflect"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
 // PublicIPSpec defines the specification for a Public IP.
 type PublicIPSpec struct {
-	Name    string
-	DNSName string
-	IsIPv6  bool
+	Name         string
+	DNSName      string
+	IsIPv6       bool
+	Availability infrav1.PublicIPAvailability
+	Zone         string
+	// Tier is the tier of the public IP, Regional if empty.
+	Tier infrav1.SKUTier
+	// Location is the location of a Global tier public IP, which is the home region of its cross-region load balancer.
+	Location string
+	// AdditionalTags are added to the additional tags of the scope, overriding the tags with the same keys.
+	AdditionalTags infrav1.Tags
+	// DeleteWithVM deletes the public IP when the virtual machine using it is deleted.
+	DeleteWithVM bool
 }
 
 // RoleAssignmentSpec defines the specification for a Role Assignment.
@@ -48,6 +62,9 @@ const (
 
 	// VirtualMachineScaleSet ...
 	VirtualMachineScaleSet = "VirtualMachineScaleSet"
+
+	// ManagedCluster ...
+	ManagedCluster = "ManagedCluster"
 )
 
 // AgentPoolSpec contains agent pool specification details.
@@ -116,6 +133,7 @@ type ScaleSetSpec struct {
 	Size                         string
 	Capacity                     int64
 	SSHKeyData                   string
+	AdditionalSSHKeyData         []string
 	OSDisk                       infrav1.OSDisk
 	DataDisks                    []infrav1.DataDisk
 	SubnetName                   string
@@ -132,6 +150,22 @@ type ScaleSetSpec struct {
 	AdditionalCapabilities       *infrav1.AdditionalCapabilities
 	FailureDomains               []string
 	NetworkInterfaces            []infrav1.AzureNetworkInterface
+	// Autoscaled is true when the capacity of the scale set is managed by an Azure Monitor autoscale setting.
+	Autoscaled bool
+	// RolloutModelUpdates is true when the instances not running the latest model are replaced, rather than updated
+	// in place when only their data disks differ from the model.
+	RolloutModelUpdates bool
+	// WarmPoolSize is the number of deallocated instances kept in the scale set in addition to its replicas. It is
+	// included in Capacity.
+	WarmPoolSize int64
+	// MinCapacity and MaxCapacity are the bounds of the active window of the scaling schedule, if any. The capacity set
+	// by an autoscaler is only followed within them.
+	MinCapacity *int64
+	MaxCapacity *int64
+	// BootstrapDataSASExpiry is the expiry time of the SAS URL the custom data of the scale set includes when its
+	// bootstrap data is delivered through storage. It's tagged on the scale set, so that renewing the URL updates the
+	// model of the scale set.
+	BootstrapDataSASExpiry string
 }
 
 // TagsSpec defines the specification for a set of tags.
@@ -142,6 +176,21 @@ type TagsSpec struct {
 	// The last applied tags are used to find out which tags are being managed by CAPZ
 	// and if any has to be deleted by comparing it with the new desired tags
 	Annotation string
+	// Owned is true when the resource is known to be created by CAPZ, so that its tags are reconciled even though it
+	// doesn't have the owned tag of the cluster, e.g. the OS disk created along with a VM.
+	Owned bool
+}
+
+// BootstrapDataSpec defines the specification for delivering the bootstrap data of a machine through a storage blob.
+type BootstrapDataSpec struct {
+	BlobName           string
+	StorageAccountName string
+	ResourceGroup      string
+	ContainerName      string
+	SASDuration        time.Duration
+	OSType             string
+	// BootstrapData is the base64 encoded bootstrap data of the machine.
+	BootstrapData string
 }
 
 // ExtensionSpec defines the specification for a VM or VMSS extension.
@@ -151,38 +200,85 @@ type ExtensionSpec struct {
 	Publisher         string
 	Version           string
 	ProtectedSettings map[string]string
+	Settings          map[string]interface{}
 }
 
 type (
 	// VMSSVM defines a VM in a virtual machine scale set.
 	VMSSVM struct {
-		ID               string                    `json:"id,omitempty"`
-		InstanceID       string                    `json:"instanceID,omitempty"`
-		Image            infrav1.Image             `json:"image,omitempty"`
-		Name             string                    `json:"name,omitempty"`
-		AvailabilityZone string                    `json:"availabilityZone,omitempty"`
-		State            infrav1.ProvisioningState `json:"vmState,omitempty"`
+		ID                 string                    `json:"id,omitempty"`
+		InstanceID         string                    `json:"instanceID,omitempty"`
+		Image              infrav1.Image             `json:"image,omitempty"`
+		Name               string                    `json:"name,omitempty"`
+		AvailabilityZone   string                    `json:"availabilityZone,omitempty"`
+		State              infrav1.ProvisioningState `json:"vmState,omitempty"`
+		LatestModelApplied bool                      `json:"latestModelApplied,omitempty"`
+		PowerState         infrav1.PowerState        `json:"powerState,omitempty"`
+		FaultDomain        *int32                    `json:"faultDomain,omitempty"`
+		ImageVersion       string                    `json:"imageVersion,omitempty"`
+		ProtectFromScaleIn bool                      `json:"protectFromScaleIn,omitempty"`
+		InstanceView       *infrav1.VMInstanceView   `json:"instanceView,omitempty"`
+		Addresses          []corev1.NodeAddress      `json:"addresses,omitempty"`
+		Plan               *infrav1.ImagePlan        `json:"plan,omitempty"`
+		BootDiagnostics    *BootDiagnostics          `json:"bootDiagnostics,omitempty"`
+		Extensions         []VMSSExtension           `json:"extensions,omitempty"`
+		DataDisks          []VMSSDataDisk            `json:"dataDisks,omitempty"`
 	}
 
 	// VMSS defines a virtual machine scale set.
 	VMSS struct {
-		ID        string                    `json:"id,omitempty"`
-		Name      string                    `json:"name,omitempty"`
-		Sku       string                    `json:"sku,omitempty"`
-		Capacity  int64                     `json:"capacity,omitempty"`
-		Zones     []string                  `json:"zones,omitempty"`
-		Image     infrav1.Image             `json:"image,omitempty"`
-		State     infrav1.ProvisioningState `json:"vmState,omitempty"`
-		Identity  infrav1.VMIdentity        `json:"identity,omitempty"`
-		Tags      infrav1.Tags              `json:"tags,omitempty"`
-		Instances []VMSSVM                  `json:"instances,omitempty"`
+		ID       string                    `json:"id,omitempty"`
+		Name     string                    `json:"name,omitempty"`
+		Sku      string                    `json:"sku,omitempty"`
+		Capacity int64                     `json:"capacity,omitempty"`
+		Zones    []string                  `json:"zones,omitempty"`
+		Image    infrav1.Image             `json:"image,omitempty"`
+		Plan     *infrav1.ImagePlan        `json:"plan,omitempty"`
+		State    infrav1.ProvisioningState `json:"vmState,omitempty"`
+		Identity infrav1.VMIdentity        `json:"identity,omitempty"`
+		// UserAssignedIdentities are the lowercase IDs of the user-assigned identities of the scale set, sorted.
+		UserAssignedIdentities []string         `json:"userAssignedIdentities,omitempty"`
+		BootDiagnostics        *BootDiagnostics `json:"bootDiagnostics,omitempty"`
+		// Extensions are the extensions of the model of the scale set, sorted by name.
+		Extensions []VMSSExtension `json:"extensions,omitempty"`
+		// DataDisks are the data disks of the model of the scale set, sorted by LUN.
+		DataDisks []VMSSDataDisk `json:"dataDisks,omitempty"`
+		Tags      infrav1.Tags   `json:"tags,omitempty"`
+		Instances []VMSSVM       `json:"instances,omitempty"`
+	}
+
+	// BootDiagnostics defines the boot diagnostics of a virtual machine.
+	BootDiagnostics struct {
+		Enabled bool `json:"enabled,omitempty"`
+		// StorageURI is the URI of the storage account storing the boot diagnostics, empty for a managed storage account.
+		StorageURI string `json:"storageURI,omitempty"`
+	}
+
+	// VMSSExtension defines an extension of a virtual machine scale set, or of one of its VMs.
+	VMSSExtension struct {
+		Name      string `json:"name,omitempty"`
+		Publisher string `json:"publisher,omitempty"`
+		Type      string `json:"type,omitempty"`
+		Version   string `json:"version,omitempty"`
+	}
+
+	// VMSSDataDisk defines a data disk of a virtual machine scale set, or of one of its VMs. The data disks of the
+	// instances are named by Azure, so they are identified by their LUN.
+	VMSSDataDisk struct {
+		Lun        int32 `json:"lun"`
+		DiskSizeGB int32 `json:"diskSizeGB,omitempty"`
 	}
 )
 
 // HasModelChanges returns true if the spec fields which will mutate the Azure VMSS model are different.
 func (vmss VMSS) HasModelChanges(other VMSS) bool {
 	equal := cmp.Equal(vmss.Image, other.Image) &&
+		cmp.Equal(vmss.Plan, other.Plan) &&
 		cmp.Equal(vmss.Identity, other.Identity) &&
+		cmp.Equal(vmss.UserAssignedIdentities, other.UserAssignedIdentities) &&
+		cmp.Equal(vmss.BootDiagnostics, other.BootDiagnostics) &&
+		cmp.Equal(vmss.Extensions, other.Extensions) &&
+		cmp.Equal(vmss.DataDisks, other.DataDisks) &&
 		cmp.Equal(vmss.Zones, other.Zones) &&
 		cmp.Equal(vmss.Tags, other.Tags) &&
 		cmp.Equal(vmss.Sku, other.Sku)
@@ -204,6 +300,28 @@ func (vm VMSSVM) ProviderID() string {
 	return ProviderIDPrefix + vm.ID
 }
 
+// IsDeallocated returns true if the VMSS instance is deallocated or hibernated, or on its way to be.
+func (vm VMSSVM) IsDeallocated() bool {
+	switch vm.PowerState {
+	case infrav1.PowerStateDeallocating, infrav1.PowerStateDeallocated, infrav1.PowerStateHibernated:
+		return true
+	default:
+		return false
+	}
+}
+
+// SortByInstanceID sorts VMSS instances by ascending instance ID, which is from the oldest to the most recent instance.
+func SortByInstanceID(instances []VMSSVM) {
+	sort.SliceStable(instances, func(i, j int) bool {
+		a, errA := strconv.Atoi(instances[i].InstanceID)
+		b, errB := strconv.Atoi(instances[j].InstanceID)
+		if errA != nil || errB != nil {
+			return instances[i].InstanceID < instances[j].InstanceID
+		}
+		return a < b
+	})
+}
+
 // HasLatestModelAppliedToAll returns true if all VMSS instance have the latest model applied.
 func (vmss VMSS) HasLatestModelAppliedToAll() bool {
 	for _, instance := range vmss.Instances {
@@ -231,6 +349,25 @@ func (vmss VMSS) HasEnoughLatestModelOrNotMixedModel() bool {
 	return counter == vmss.Capacity
 }
 
+// OutdatedInstanceCount returns the number of VMSS instances running an outdated image or, as reported by Azure,
+// an outdated VMSS model.
+func (vmss VMSS) OutdatedInstanceCount() int32 {
+	var count int32
+	for _, instance := range vmss.Instances {
+		if !vmss.HasLatestModelApplied(instance) || !instance.LatestModelApplied {
+			count++
+		}
+	}
+
+	return count
+}
+
+// HasDataDiskChanges returns true if data disks were added to or removed from the VMSS model since the VMSS instance
+// was last updated to it.
+func (vmss VMSS) HasDataDiskChanges(vm VMSSVM) bool {
+	return !cmp.Equal(vm.DataDisks, vmss.DataDisks)
+}
+
 // HasLatestModelApplied returns true if the VMSS instance matches the VMSS image reference.
 func (vmss VMSS) HasLatestModelApplied(vm VMSSVM) bool {
 	// if the images match, then the VM is of the same model
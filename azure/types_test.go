@@ -19,7 +19,7 @@ package azure
 import (
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
@@ -107,7 +107,7 @@ func TestVMSS_HasModelChanges(t *testing.T) {
 			Factory: func() (VMSS, VMSS) {
 				l := getDefaultVMSSForModelTesting()
 				l.Image = infrav1.Image{
-					ID: to.StringPtr("foo"),
+					ID: to.Ptr("foo"),
 				}
 				r := getDefaultVMSSForModelTesting()
 				return r, l
@@ -124,6 +124,69 @@ func TestVMSS_HasModelChanges(t *testing.T) {
 			},
 			HasModelChanges: true,
 		},
+		{
+			Name: "with different plan",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.Plan = &infrav1.ImagePlan{Publisher: "foo", Offer: "bar", SKU: "baz"}
+				r := getDefaultVMSSForModelTesting()
+				return r, l
+			},
+			HasModelChanges: true,
+		},
+		{
+			Name: "with different user-assigned identities",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.Identity = infrav1.VMIdentityUserAssigned
+				l.UserAssignedIdentities = []string{"/subscriptions/123/resourcegroups/foo/providers/microsoft.managedidentity/userassignedidentities/bar"}
+				r := getDefaultVMSSForModelTesting()
+				r.Identity = infrav1.VMIdentityUserAssigned
+				r.UserAssignedIdentities = []string{"/subscriptions/123/resourcegroups/foo/providers/microsoft.managedidentity/userassignedidentities/baz"}
+				return r, l
+			},
+			HasModelChanges: true,
+		},
+		{
+			Name: "with boot diagnostics disabled",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.BootDiagnostics = &BootDiagnostics{}
+				r := getDefaultVMSSForModelTesting()
+				return r, l
+			},
+			HasModelChanges: true,
+		},
+		{
+			Name: "with different extension version",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.Extensions[0].Version = "2.0"
+				r := getDefaultVMSSForModelTesting()
+				return r, l
+			},
+			HasModelChanges: true,
+		},
+		{
+			Name: "with a removed extension",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.Extensions = nil
+				r := getDefaultVMSSForModelTesting()
+				return r, l
+			},
+			HasModelChanges: true,
+		},
+		{
+			Name: "with an added data disk",
+			Factory: func() (VMSS, VMSS) {
+				l := getDefaultVMSSForModelTesting()
+				l.DataDisks = append(l.DataDisks, VMSSDataDisk{Lun: 1, DiskSizeGB: 64})
+				r := getDefaultVMSSForModelTesting()
+				return r, l
+			},
+			HasModelChanges: true,
+		},
 		{
 			Name: "with different Tags",
 			Factory: func() (VMSS, VMSS) {
@@ -148,6 +211,27 @@ func TestVMSS_HasModelChanges(t *testing.T) {
 	}
 }
 
+func TestVMSS_OutdatedInstanceCount(t *testing.T) {
+	g := NewWithT(t)
+
+	vmss := getDefaultVMSSForModelTesting()
+	outdatedImage := infrav1.Image{
+		Marketplace: &infrav1.AzureMarketplaceImage{
+			Version: "bar",
+		},
+	}
+	vmss.Instances = []VMSSVM{
+		{InstanceID: "0", Image: vmss.Image, LatestModelApplied: true},
+		{InstanceID: "1", Image: outdatedImage, LatestModelApplied: true},
+		{InstanceID: "2", Image: vmss.Image, LatestModelApplied: false},
+	}
+	g.Expect(vmss.OutdatedInstanceCount()).To(Equal(int32(2)))
+	g.Expect(vmss.HasLatestModelAppliedToAll()).To(BeFalse())
+
+	vmss.Instances = vmss.Instances[:1]
+	g.Expect(vmss.OutdatedInstanceCount()).To(BeZero())
+}
+
 func getDefaultVMSSForModelTesting() VMSS {
 	return VMSS{
 		Zones: []string{"0", "1"},
@@ -156,8 +240,15 @@ func getDefaultVMSSForModelTesting() VMSS {
 				Version: "foo",
 			},
 		},
-		Sku:      "reallyBigVM",
-		Identity: infrav1.VMIdentitySystemAssigned,
+		Sku:             "reallyBigVM",
+		Identity:        infrav1.VMIdentitySystemAssigned,
+		BootDiagnostics: &BootDiagnostics{Enabled: true},
+		Extensions: []VMSSExtension{
+			{Name: "someExtension", Publisher: "somePublisher", Type: "someExtension", Version: "1.0"},
+		},
+		DataDisks: []VMSSDataDisk{
+			{Lun: 0, DiskSizeGB: 256},
+		},
 		Tags: infrav1.Tags{
 			"foo": "baz",
 		},
@@ -0,0 +1,289 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// azureResource is an Azure resource tagged as owned by, or shared with, a cluster.
+type azureResource struct {
+	ResourceGroup     string
+	Name              string
+	Type              string
+	Lifecycle         string
+	Role              string
+	ProvisioningState string
+}
+
+// pendingOperation is a long-running operation recorded in the status of a CAPZ resource.
+type pendingOperation struct {
+	Kind   string
+	Object string
+	Future infrav1.Future
+	// State is the last known status of the operation, as recorded in the future.
+	State string
+}
+
+// clusterAuthorizer returns the scope of the infrastructure of the cluster, which holds the Azure credentials
+// CAPZ uses for it.
+func clusterAuthorizer(ctx context.Context, c client.Client, cluster *clusterv1.Cluster) (azure.Authorizer, error) {
+	infraRef := cluster.Spec.InfrastructureRef
+	if infraRef == nil {
+		return nil, errors.Errorf("Cluster %s/%s has no infrastructure reference", cluster.Namespace, cluster.Name)
+	}
+
+	switch infraRef.Kind {
+	case "AzureCluster":
+		azureCluster := &infrav1.AzureCluster{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: infraRef.Name}, azureCluster); err != nil {
+			return nil, errors.Wrapf(err, "failed to get AzureCluster %s/%s", cluster.Namespace, infraRef.Name)
+		}
+		return scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+			Client:       c,
+			Cluster:      cluster,
+			AzureCluster: azureCluster,
+		})
+	case "AzureManagedCluster":
+		controlPlaneRef := cluster.Spec.ControlPlaneRef
+		if controlPlaneRef == nil || controlPlaneRef.Kind != "AzureManagedControlPlane" {
+			return nil, errors.Errorf("Cluster %s/%s has no AzureManagedControlPlane reference", cluster.Namespace, cluster.Name)
+		}
+		controlPlane := &infrav1exp.AzureManagedControlPlane{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: controlPlaneRef.Name}, controlPlane); err != nil {
+			return nil, errors.Wrapf(err, "failed to get AzureManagedControlPlane %s/%s", cluster.Namespace, controlPlaneRef.Name)
+		}
+		return scope.NewManagedControlPlaneScope(ctx, scope.ManagedControlPlaneScopeParams{
+			Client:       c,
+			Cluster:      cluster,
+			ControlPlane: controlPlane,
+		})
+	default:
+		return nil, errors.Errorf("unsupported infrastructure kind %q", infraRef.Kind)
+	}
+}
+
+// listAzureResources lists the resource groups and resources carrying the ownership tag of the cluster.
+func listAzureResources(ctx context.Context, auth azure.Authorizer, clusterName string) ([]azureResource, error) {
+	filter := fmt.Sprintf("tagName eq '%s'", infrav1.ClusterTagKey(clusterName))
+	var result []azureResource
+
+	opts, err := azure.ARMClientOptions(auth.CloudEnvironment())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get client options")
+	}
+
+	groupsClient, err := armresources.NewResourceGroupsClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create resource groups client")
+	}
+	groupsPager := groupsClient.NewListPager(&armresources.ResourceGroupsClientListOptions{Filter: &filter})
+	groups, err := azure.ListPager(ctx, "resource groups", groupsPager, func(page armresources.ResourceGroupsClientListResponse) []*armresources.ResourceGroup {
+		return page.Value
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		var provisioningState *string
+		if group.Properties != nil {
+			provisioningState = group.Properties.ProvisioningState
+		}
+		result = append(result, newAzureResource(clusterName, pointer.StringDeref(group.Name, ""), pointer.StringDeref(group.Name, ""), pointer.StringDeref(group.Type, ""), group.Tags, provisioningState))
+	}
+
+	resourcesClient, err := armresources.NewClient(auth.SubscriptionID(), auth.Token(), opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create resources client")
+	}
+	resourcesPager := resourcesClient.NewListPager(&armresources.ClientListOptions{Filter: &filter, Expand: to.Ptr("provisioningState")})
+	resources, err := azure.ListPager(ctx, "resources", resourcesPager, func(page armresources.ClientListResponse) []*armresources.GenericResourceExpanded {
+		return page.Value
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range resources {
+		var resourceGroup string
+		if id, err := azure.ParseResourceID(pointer.StringDeref(resource.ID, "")); err == nil {
+			resourceGroup = id.ResourceGroupName
+		}
+		result = append(result, newAzureResource(clusterName, resourceGroup, pointer.StringDeref(resource.Name, ""), pointer.StringDeref(resource.Type, ""), resource.Tags, resource.ProvisioningState))
+	}
+
+	return result, nil
+}
+
+// newAzureResource describes an Azure resource from its tags.
+func newAzureResource(clusterName, resourceGroup, name, resourceType string, tags map[string]*string, provisioningState *string) azureResource {
+	capzTags := converters.MapToTags(tags)
+	return azureResource{
+		ResourceGroup:     resourceGroup,
+		Name:              name,
+		Type:              resourceType,
+		Lifecycle:         capzTags[infrav1.ClusterTagKey(clusterName)],
+		Role:              capzTags.GetRole(),
+		ProvisioningState: pointer.StringDeref(provisioningState, ""),
+	}
+}
+
+// listPendingOperations lists the long-running operations recorded in the status of the CAPZ resources of the cluster.
+func listPendingOperations(ctx context.Context, c client.Client, cluster *clusterv1.Cluster) ([]pendingOperation, error) {
+	var getters []futures.Getter
+
+	if ref := cluster.Spec.InfrastructureRef; ref != nil && ref.Kind == "AzureCluster" {
+		azureCluster := &infrav1.AzureCluster{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: ref.Name}, azureCluster); err != nil {
+			return nil, errors.Wrapf(err, "failed to get AzureCluster %s/%s", cluster.Namespace, ref.Name)
+		}
+		getters = append(getters, azureCluster)
+	}
+	if ref := cluster.Spec.ControlPlaneRef; ref != nil && ref.Kind == "AzureManagedControlPlane" {
+		controlPlane := &infrav1exp.AzureManagedControlPlane{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: ref.Name}, controlPlane); err != nil {
+			return nil, errors.Wrapf(err, "failed to get AzureManagedControlPlane %s/%s", cluster.Namespace, ref.Name)
+		}
+		getters = append(getters, controlPlane)
+	}
+
+	listOptions := []client.ListOption{
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name},
+	}
+
+	machines := &infrav1.AzureMachineList{}
+	if err := c.List(ctx, machines, listOptions...); err != nil {
+		return nil, errors.Wrap(err, "failed to list AzureMachines")
+	}
+	for i := range machines.Items {
+		getters = append(getters, &machines.Items[i])
+	}
+
+	machinePools := &infrav1exp.AzureMachinePoolList{}
+	if err := c.List(ctx, machinePools, listOptions...); err != nil {
+		return nil, errors.Wrap(err, "failed to list AzureMachinePools")
+	}
+	for i := range machinePools.Items {
+		getters = append(getters, &machinePools.Items[i])
+	}
+
+	machinePoolMachines := &infrav1exp.AzureMachinePoolMachineList{}
+	if err := c.List(ctx, machinePoolMachines, listOptions...); err != nil {
+		return nil, errors.Wrap(err, "failed to list AzureMachinePoolMachines")
+	}
+	for i := range machinePoolMachines.Items {
+		getters = append(getters, &machinePoolMachines.Items[i])
+	}
+
+	managedMachinePools := &infrav1exp.AzureManagedMachinePoolList{}
+	if err := c.List(ctx, managedMachinePools, listOptions...); err != nil {
+		return nil, errors.Wrap(err, "failed to list AzureManagedMachinePools")
+	}
+	for i := range managedMachinePools.Items {
+		getters = append(getters, &managedMachinePools.Items[i])
+	}
+
+	var operations []pendingOperation
+	for _, getter := range getters {
+		gvk, err := apiutil.GVKForObject(getter, scheme)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get kind")
+		}
+		for _, future := range getter.GetFutures() {
+			operations = append(operations, pendingOperation{
+				Kind:   gvk.Kind,
+				Object: getter.GetName(),
+				Future: future,
+				State:  futureState(future),
+			})
+		}
+	}
+	return operations, nil
+}
+
+// futureState returns the last known status of a future, without polling Azure.
+func futureState(future infrav1.Future) string {
+	resumeToken, err := converters.FutureToResumeToken(future)
+	if err != nil {
+		return fmt.Sprintf("unknown (%v)", err)
+	}
+	// The state of the poller is stored in the token, see the azcore runtime package.
+	var token struct {
+		Token struct {
+			State string `json:"state"`
+		} `json:"token"`
+	}
+	if err := json.Unmarshal([]byte(resumeToken), &token); err != nil {
+		return fmt.Sprintf("unknown (%v)", err)
+	}
+	return token.Token.State
+}
+
+// printAzureResources writes a table of the Azure resources, sorted by resource group and name.
+func printAzureResources(w io.Writer, azureResources []azureResource) {
+	if len(azureResources) == 0 {
+		fmt.Fprintln(w, "No Azure resources tagged with the cluster name.")
+		return
+	}
+	sort.SliceStable(azureResources, func(i, j int) bool {
+		if azureResources[i].ResourceGroup != azureResources[j].ResourceGroup {
+			return azureResources[i].ResourceGroup < azureResources[j].ResourceGroup
+		}
+		return azureResources[i].Name < azureResources[j].Name
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "RESOURCE GROUP\tNAME\tTYPE\tLIFECYCLE\tROLE\tPROVISIONING STATE")
+	for _, r := range azureResources {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", r.ResourceGroup, r.Name, r.Type, r.Lifecycle, r.Role, r.ProvisioningState)
+	}
+	_ = tw.Flush()
+}
+
+// printPendingOperations writes a table of the pending long-running operations.
+func printPendingOperations(w io.Writer, operations []pendingOperation) {
+	if len(operations) == 0 {
+		fmt.Fprintln(w, "No pending long-running operations.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tOBJECT\tSERVICE\tOPERATION\tRESOURCE GROUP\tRESOURCE\tSTATE")
+	for _, op := range operations {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", op.Kind, op.Object, op.Future.ServiceName, op.Future.Type, op.Future.ResourceGroup, op.Future.Name, op.State)
+	}
+	_ = tw.Flush()
+}
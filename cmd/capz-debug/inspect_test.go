@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var (
+	// inProgressFuture is a base64 encoded resume token of a DELETE poller whose last known state is InProgress.
+	inProgressFuture = infrav1.Future{
+		Type:          infrav1.DeleteFuture,
+		ServiceName:   "virtualmachines",
+		Name:          "my-vm",
+		ResourceGroup: "my-rg",
+		Data:          "eyJ0eXBlIjoiVmlydHVhbE1hY2hpbmVzQ2xpZW50RGVsZXRlUmVzcG9uc2UiLCJ0b2tlbiI6eyJzdGF0ZSI6IkluUHJvZ3Jlc3MifX0=",
+	}
+	// inProgressResumeFuture is a base64 encoded resume token of a DELETE poller whose last known state is InProgress.
+	inProgressResumeFuture = infrav1.Future{
+		Type:          infrav1.DeleteFuture,
+		ServiceName:   "disks",
+		Name:          "my-vm_OSDisk",
+		ResourceGroup: "my-rg",
+		Data:          "eyJ0eXBlIjoiRGlza3NDbGllbnREZWxldGVSZXNwb25zZSIsInRva2VuIjp7InN0YXRlIjoiSW5Qcm9ncmVzcyJ9fQ==",
+	}
+	invalidFuture = infrav1.Future{
+		Type:          infrav1.PutFuture,
+		ServiceName:   "agentpools",
+		Name:          "pool0",
+		ResourceGroup: "my-rg",
+		Data:          "this is not b64 encoded",
+	}
+)
+
+func TestListPendingOperations(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster *clusterv1.Cluster
+		objects []runtime.Object
+		expect  []pendingOperation
+	}{
+		{
+			name: "self-managed cluster",
+			cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{Kind: "AzureCluster", Name: "my-azure-cluster"},
+				},
+			},
+			objects: []runtime.Object{
+				&infrav1.AzureCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-azure-cluster", Namespace: "default"},
+				},
+				&infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-machine",
+						Namespace: "default",
+						Labels:    map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+					},
+					Status: infrav1.AzureMachineStatus{
+						LongRunningOperationStates: infrav1.Futures{inProgressFuture, inProgressResumeFuture},
+					},
+				},
+				&infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "other-machine",
+						Namespace: "default",
+						Labels:    map[string]string{clusterv1.ClusterLabelName: "other-cluster"},
+					},
+					Status: infrav1.AzureMachineStatus{
+						LongRunningOperationStates: infrav1.Futures{inProgressFuture},
+					},
+				},
+			},
+			expect: []pendingOperation{
+				{Kind: "AzureMachine", Object: "my-machine", Future: inProgressFuture, State: "InProgress"},
+				{Kind: "AzureMachine", Object: "my-machine", Future: inProgressResumeFuture, State: "InProgress"},
+			},
+		},
+		{
+			name: "managed cluster",
+			cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{Kind: "AzureManagedCluster", Name: "my-cluster"},
+					ControlPlaneRef:   &corev1.ObjectReference{Kind: "AzureManagedControlPlane", Name: "my-cluster"},
+				},
+			},
+			objects: []runtime.Object{
+				&infrav1exp.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+					Status: infrav1exp.AzureManagedControlPlaneStatus{
+						LongRunningOperationStates: infrav1.Futures{inProgressFuture},
+					},
+				},
+				&infrav1exp.AzureManagedMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pool0",
+						Namespace: "default",
+						Labels:    map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+					},
+					Status: infrav1exp.AzureManagedMachinePoolStatus{
+						LongRunningOperationStates: infrav1.Futures{invalidFuture},
+					},
+				},
+			},
+			expect: []pendingOperation{
+				{Kind: "AzureManagedControlPlane", Object: "my-cluster", Future: inProgressFuture, State: "InProgress"},
+				{Kind: "AzureManagedMachinePool", Object: "pool0", Future: invalidFuture, State: "unknown (failed to base64 decode future data: illegal base64 data at input byte 4)"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tc.objects...).Build()
+
+			operations, err := listPendingOperations(context.TODO(), c, tc.cluster)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(operations).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestNewAzureResource(t *testing.T) {
+	g := NewWithT(t)
+
+	tags := map[string]*string{
+		infrav1.ClusterTagKey("my-cluster"): to.Ptr(string(infrav1.ResourceLifecycleOwned)),
+		infrav1.NameAzureClusterAPIRole:     to.Ptr(infrav1.Node),
+	}
+	resource := newAzureResource("my-cluster", "my-rg", "my-vm", "Microsoft.Compute/virtualMachines", tags, to.Ptr("Updating"))
+	g.Expect(resource).To(Equal(azureResource{
+		ResourceGroup:     "my-rg",
+		Name:              "my-vm",
+		Type:              "Microsoft.Compute/virtualMachines",
+		Lifecycle:         "owned",
+		Role:              "node",
+		ProvisioningState: "Updating",
+	}))
+}
+
+func TestPrintPendingOperations(t *testing.T) {
+	g := NewWithT(t)
+
+	var buf bytes.Buffer
+	printPendingOperations(&buf, nil)
+	g.Expect(buf.String()).To(Equal("No pending long-running operations.\n"))
+
+	buf.Reset()
+	printPendingOperations(&buf, []pendingOperation{
+		{Kind: "AzureMachine", Object: "my-machine", Future: inProgressFuture, State: "InProgress"},
+	})
+	g.Expect(buf.String()).To(Equal(
+		"KIND          OBJECT      SERVICE          OPERATION  RESOURCE GROUP  RESOURCE  STATE\n" +
+			"AzureMachine  my-machine  virtualmachines  DELETE     my-rg           my-vm     InProgress\n"))
+}
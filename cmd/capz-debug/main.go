@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// capz-debug lists the Azure resources owned by a workload cluster and the long-running operations
+// CAPZ is waiting on, to help troubleshooting clusters that do not converge.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+	_ = infrav1exp.AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+	_ = clusterv1exp.AddToScheme(scheme)
+}
+
+func main() {
+	var (
+		namespace string
+		skipAzure bool
+	)
+	fs := pflag.CommandLine
+	fs.StringVarP(&namespace, "namespace", "n", "default", "Namespace of the Cluster.")
+	fs.BoolVar(&skipAzure, "skip-azure", false, "Only report the pending operations recorded in the status, without listing the Azure resources.")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] CLUSTER_NAME\n\nFlags:\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	// Registers the --kubeconfig flag of controller-runtime.
+	fs.AddGoFlagSet(flag.CommandLine)
+	pflag.Parse()
+
+	if pflag.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(context.Background(), pflag.Arg(0), namespace, skipAzure); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, clusterName, namespace string, skipAzure bool) error {
+	restConfig, err := config.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to load kubeconfig")
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return errors.Wrap(err, "failed to create client")
+	}
+
+	cluster := &clusterv1.Cluster{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName}, cluster); err != nil {
+		return errors.Wrapf(err, "failed to get Cluster %s/%s", namespace, clusterName)
+	}
+
+	if !skipAzure {
+		auth, err := clusterAuthorizer(ctx, c, cluster)
+		if err != nil {
+			return err
+		}
+		azureResources, err := listAzureResources(ctx, auth, clusterName)
+		if err != nil {
+			return err
+		}
+		printAzureResources(os.Stdout, azureResources)
+		fmt.Fprintln(os.Stdout)
+	}
+
+	operations, err := listPendingOperations(ctx, c, cluster)
+	if err != nil {
+		return err
+	}
+	printPendingOperations(os.Stdout, operations)
+	return nil
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/addons"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// workloadClusterClientGetter returns a client for the workload cluster.
+type workloadClusterClientGetter func(ctx context.Context, c client.Client, cluster client.ObjectKey) (client.Client, error)
+
+// AzureClusterAddonsReconciler installs and updates the workload cluster addons of AzureCluster objects.
+type AzureClusterAddonsReconciler struct {
+	client.Client
+	Recorder         record.EventRecorder
+	ReconcileTimeout time.Duration
+	WatchFilterValue string
+	Catalog          *addons.Catalog
+
+	getWorkloadClusterClient workloadClusterClientGetter
+	controlPlaneTracker      external.ObjectTracker
+}
+
+// SetupWithManager initializes this controller with a manager.
+func (r *AzureClusterAddonsReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	_, log, done := tele.StartSpanWithLogger(ctx,
+		"controllers.AzureClusterAddonsReconciler.SetupWithManager",
+	)
+	defer done()
+
+	if r.getWorkloadClusterClient == nil {
+		r.getWorkloadClusterClient = func(ctx context.Context, c client.Client, cluster client.ObjectKey) (client.Client, error) {
+			return remote.NewClusterClient(ctx, "capz-addons", c, cluster)
+		}
+	}
+
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.AzureCluster{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(log, r.WatchFilterValue)).
+		// watch for the control plane becoming initialized and for Kubernetes version changes of clusters using ClusterClass
+		Watches(
+			&source.Kind{Type: &clusterv1.Cluster{}},
+			handler.EnqueueRequestsFromMapFunc(util.ClusterToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("AzureCluster"))),
+		).
+		Build(r)
+	if err != nil {
+		return errors.Wrap(err, "error creating controller")
+	}
+
+	// the watch for Kubernetes version changes of control planes is added once the kind of a control plane is known
+	r.controlPlaneTracker = external.ObjectTracker{Controller: c}
+
+	return nil
+}
+
+// +kubebuilder:rbac:groups=controlplane.cluster.x-k8s.io,resources=*,verbs=get;list;watch
+
+// Reconcile installs and updates the addons enabled on an AzureCluster in its workload cluster.
+func (r *AzureClusterAddonsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultedLoopTimeout(r.ReconcileTimeout))
+	defer cancel()
+
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureClusterAddonsReconciler.Reconcile",
+		tele.KVP("namespace", req.Namespace),
+		tele.KVP("name", req.Name),
+		tele.KVP("kind", "AzureCluster"),
+	)
+	defer done()
+
+	// Fetch the AzureCluster instance
+	azureCluster := &infrav1.AzureCluster{}
+	err := r.Get(ctx, req.NamespacedName, azureCluster)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("object was not found")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if azureCluster.Spec.Addons == nil || !azureCluster.ObjectMeta.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	// Fetch the Cluster.
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, azureCluster.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cluster == nil {
+		log.Info("Cluster Controller has not yet set OwnerRef")
+		return reconcile.Result{}, nil
+	}
+
+	log = log.WithValues(tele.LogKeyClusterName, cluster.Name)
+	ctx = tele.WithLogValues(ctx, tele.LogKeyClusterName, cluster.Name)
+
+	// Return early if the object or Cluster is paused.
+	if annotations.IsPaused(cluster, azureCluster) {
+		log.Info("AzureCluster or linked Cluster is marked as paused. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(azureCluster, r.Client)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to init patch helper")
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, azureCluster, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			infrav1.AddonsReadyCondition,
+		}}); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	if !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+		log.Info("Control plane is not initialized yet")
+		conditions.MarkFalse(azureCluster, infrav1.AddonsReadyCondition, infrav1.WaitingForControlPlaneReason, clusterv1.ConditionSeverityInfo, "")
+		return reconcile.Result{}, nil
+	}
+
+	kubernetesVersion, err := r.kubernetesVersion(ctx, log, cluster)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	workloadClient, err := r.getWorkloadClusterClient(ctx, r.Client, util.ObjectKey(cluster))
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to create workload cluster client")
+	}
+
+	for _, addon := range enabledAddons(azureCluster.Spec.Addons) {
+		version, err := r.reconcileAddon(ctx, workloadClient, addon, cluster.Name, kubernetesVersion)
+		if err != nil {
+			r.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "AddonInstallFailed", "failed to install addon %s: %s", addon, err.Error())
+			conditions.MarkFalse(azureCluster, infrav1.AddonsReadyCondition, infrav1.AddonsInstallFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			return reconcile.Result{}, err
+		}
+		log.V(4).Info("addon is up to date", "addon", addon, "version", version)
+	}
+
+	conditions.MarkTrue(azureCluster, infrav1.AddonsReadyCondition)
+	return reconcile.Result{}, nil
+}
+
+// reconcileAddon applies the manifest of an addon matching the given Kubernetes version to the workload cluster.
+func (r *AzureClusterAddonsReconciler) reconcileAddon(ctx context.Context, workloadClient client.Client, addon addons.Addon, clusterName, kubernetesVersion string) (string, error) {
+	version, manifest, err := r.Catalog.Manifest(addon, kubernetesVersion)
+	if err != nil {
+		return "", err
+	}
+
+	objs, err := addons.Objects(manifest, clusterName)
+	if err != nil {
+		return "", err
+	}
+
+	for i := range objs {
+		obj := &objs[i]
+		if err := applyObject(ctx, workloadClient, obj); err != nil {
+			return "", errors.Wrapf(err, "failed to apply %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+		}
+	}
+
+	return version, nil
+}
+
+// kubernetesVersion returns the Kubernetes version of the cluster, as set in its topology or its control plane.
+func (r *AzureClusterAddonsReconciler) kubernetesVersion(ctx context.Context, log logr.Logger, cluster *clusterv1.Cluster) (string, error) {
+	if cluster.Spec.Topology != nil && cluster.Spec.Topology.Version != "" {
+		return cluster.Spec.Topology.Version, nil
+	}
+
+	if cluster.Spec.ControlPlaneRef == nil {
+		return "", errors.New("cluster has no control plane reference to read the Kubernetes version from")
+	}
+
+	controlPlane, err := external.Get(ctx, r.Client, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get control plane")
+	}
+
+	// the version of the control plane changes without the Cluster changing.
+	if err := r.controlPlaneTracker.Watch(log, controlPlane,
+		handler.EnqueueRequestsFromMapFunc(r.controlPlaneToAzureCluster(ctx)),
+		controlPlaneVersionChanged(),
+	); err != nil {
+		return "", err
+	}
+
+	version, found, err := unstructured.NestedString(controlPlane.Object, "spec", "version")
+	if err != nil || !found || version == "" {
+		return "", fmt.Errorf("control plane %s has no spec.version", controlPlane.GetName())
+	}
+
+	return version, nil
+}
+
+// controlPlaneToAzureCluster returns a handler.MapFunc mapping a control plane to the AzureCluster of its Cluster.
+func (r *AzureClusterAddonsReconciler) controlPlaneToAzureCluster(ctx context.Context) handler.MapFunc {
+	clusterToAzureCluster := util.ClusterToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("AzureCluster"))
+	return func(o client.Object) []reconcile.Request {
+		ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureClusterAddonsReconciler.controlPlaneToAzureCluster")
+		defer done()
+
+		clusterName, ok := o.GetLabels()[clusterv1.ClusterLabelName]
+		if !ok {
+			return nil
+		}
+
+		cluster := &clusterv1.Cluster{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: o.GetNamespace(), Name: clusterName}, cluster); err != nil {
+			log.Error(err, "failed to get Cluster of control plane", "controlPlane", o.GetName())
+			return nil
+		}
+
+		return clusterToAzureCluster(cluster)
+	}
+}
+
+// controlPlaneVersionChanged returns a predicate filtering the updates of a control plane to those changing its
+// Kubernetes version.
+func controlPlaneVersionChanged() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldObj, okOld := e.ObjectOld.(*unstructured.Unstructured)
+			newObj, okNew := e.ObjectNew.(*unstructured.Unstructured)
+			if !okOld || !okNew {
+				return false
+			}
+			oldVersion, _, _ := unstructured.NestedString(oldObj.Object, "spec", "version")
+			newVersion, _, _ := unstructured.NestedString(newObj.Object, "spec", "version")
+			return oldVersion != newVersion
+		},
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// applyObject creates the object in the workload cluster, or updates it if it already exists.
+func applyObject(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+	if apierrors.IsNotFound(err) {
+		return c.Create(ctx, obj)
+	}
+	if err != nil {
+		return err
+	}
+
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return c.Update(ctx, obj)
+}
+
+// enabledAddons returns the addons enabled in the spec.
+func enabledAddons(spec *infrav1.AddonsSpec) []addons.Addon {
+	var enabled []addons.Addon
+	if spec.CloudProviderAzure {
+		enabled = append(enabled, addons.CloudProviderAzure)
+	}
+	if spec.AzureDiskCSIDriver {
+		enabled = append(enabled, addons.AzureDiskCSIDriver)
+	}
+	if spec.AzureFileCSIDriver {
+		enabled = append(enabled, addons.AzureFileCSIDriver)
+	}
+	return enabled
+}
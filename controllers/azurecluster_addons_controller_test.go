@@ -0,0 +1,276 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/addons"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const testCloudProviderManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cloud-controller-manager
+  namespace: kube-system
+data:
+  version: v1.23
+`
+
+func TestAzureClusterAddonsReconciler(t *testing.T) {
+	scheme, err := newScheme()
+	if err != nil {
+		t.Error(err)
+	}
+
+	root := t.TempDir()
+	dir := filepath.Join(root, string(addons.CloudProviderAzure))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "v1.23.yaml"), []byte(testCloudProviderManifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	newCluster := func(initialized bool) *clusterv1.Cluster {
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-cluster",
+				Namespace: "default",
+			},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{
+					APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+					Kind:       "AzureCluster",
+					Name:       "my-azure-cluster",
+				},
+				Topology: &clusterv1.Topology{
+					Version: "v1.23.5",
+				},
+			},
+		}
+		if initialized {
+			conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+		}
+		return cluster
+	}
+
+	newAzureCluster := func(addonsSpec *infrav1.AddonsSpec) *infrav1.AzureCluster {
+		return &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-azure-cluster",
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: "cluster.x-k8s.io/v1beta1",
+						Kind:       "Cluster",
+						Name:       "my-cluster",
+					},
+				},
+			},
+			Spec: infrav1.AzureClusterSpec{
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+					SubscriptionID: "123",
+					Addons:         addonsSpec,
+				},
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		objects       []runtime.Object
+		wantErr       bool
+		wantCondition *clusterv1.Condition
+		wantInstalled bool
+	}{
+		"should install enabled addons": {
+			objects: []runtime.Object{
+				newCluster(true),
+				newAzureCluster(&infrav1.AddonsSpec{CloudProviderAzure: true}),
+			},
+			wantCondition: conditions.TrueCondition(infrav1.AddonsReadyCondition),
+			wantInstalled: true,
+		},
+		"should wait for the control plane to be initialized": {
+			objects: []runtime.Object{
+				newCluster(false),
+				newAzureCluster(&infrav1.AddonsSpec{CloudProviderAzure: true}),
+			},
+			wantCondition: conditions.FalseCondition(infrav1.AddonsReadyCondition, infrav1.WaitingForControlPlaneReason, clusterv1.ConditionSeverityInfo, ""),
+		},
+		"should do nothing when addons are not set": {
+			objects: []runtime.Object{
+				newCluster(true),
+				newAzureCluster(nil),
+			},
+		},
+		"missing manifest should fail": {
+			objects: []runtime.Object{
+				newCluster(true),
+				newAzureCluster(&infrav1.AddonsSpec{AzureDiskCSIDriver: true}),
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tc.objects...).Build()
+			workloadClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+			reconciler := &AzureClusterAddonsReconciler{
+				Client:   c,
+				Recorder: record.NewFakeRecorder(128),
+				Catalog:  addons.NewCatalog(root),
+				getWorkloadClusterClient: func(_ context.Context, _ client.Client, _ client.ObjectKey) (client.Client, error) {
+					return workloadClient, nil
+				},
+			}
+
+			_, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: "default",
+					Name:      "my-azure-cluster",
+				},
+			})
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			azureCluster := &infrav1.AzureCluster{}
+			g.Expect(c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "my-azure-cluster"}, azureCluster)).To(Succeed())
+			condition := conditions.Get(azureCluster, infrav1.AddonsReadyCondition)
+			if tc.wantCondition == nil && !tc.wantErr {
+				g.Expect(condition).To(BeNil())
+			} else if tc.wantCondition != nil {
+				g.Expect(condition).NotTo(BeNil())
+				g.Expect(condition.Status).To(Equal(tc.wantCondition.Status))
+				g.Expect(condition.Reason).To(Equal(tc.wantCondition.Reason))
+			}
+
+			cm := &corev1.ConfigMap{}
+			err = workloadClient.Get(context.Background(), types.NamespacedName{Namespace: "kube-system", Name: "cloud-controller-manager"}, cm)
+			if tc.wantInstalled {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(cm.Data).To(HaveKeyWithValue("version", "v1.23"))
+			} else {
+				g.Expect(err).To(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureClusterAddonsReconciler_controlPlaneToAzureCluster(t *testing.T) {
+	g := NewWithT(t)
+	scheme, err := newScheme()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-cluster",
+			Namespace: "default",
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				Kind:       "AzureCluster",
+				Name:       "my-azure-cluster",
+			},
+		},
+	}
+	reconciler := &AzureClusterAddonsReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cluster).Build(),
+	}
+	mapFunc := reconciler.controlPlaneToAzureCluster(context.Background())
+
+	controlPlane := newControlPlane("v1.23.5")
+	g.Expect(mapFunc(controlPlane)).To(ConsistOf(reconcile.Request{
+		NamespacedName: types.NamespacedName{Namespace: "default", Name: "my-azure-cluster"},
+	}))
+
+	controlPlane.SetLabels(nil)
+	g.Expect(mapFunc(controlPlane)).To(BeEmpty())
+
+	controlPlane.SetLabels(map[string]string{clusterv1.ClusterLabelName: "other-cluster"})
+	g.Expect(mapFunc(controlPlane)).To(BeEmpty())
+}
+
+func TestControlPlaneVersionChanged(t *testing.T) {
+	cases := map[string]struct {
+		oldVersion string
+		newVersion string
+		want       bool
+	}{
+		"version upgrade": {
+			oldVersion: "v1.23.5",
+			newVersion: "v1.24.1",
+			want:       true,
+		},
+		"same version": {
+			oldVersion: "v1.23.5",
+			newVersion: "v1.23.5",
+			want:       false,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(controlPlaneVersionChanged().Update(event.UpdateEvent{
+				ObjectOld: newControlPlane(tc.oldVersion),
+				ObjectNew: newControlPlane(tc.newVersion),
+			})).To(Equal(tc.want))
+		})
+	}
+}
+
+func newControlPlane(version string) *unstructured.Unstructured {
+	controlPlane := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"version": version,
+		},
+	}}
+	controlPlane.SetAPIVersion("controlplane.cluster.x-k8s.io/v1beta1")
+	controlPlane.SetKind("KubeadmControlPlane")
+	controlPlane.SetNamespace("default")
+	controlPlane.SetName("my-control-plane")
+	controlPlane.SetLabels(map[string]string{clusterv1.ClusterLabelName: "my-cluster"})
+	return controlPlane
+}
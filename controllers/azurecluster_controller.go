@@ -28,8 +28,9 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
-	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/inflight"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
@@ -37,8 +38,10 @@ import (
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -51,6 +54,11 @@ type AzureClusterReconciler struct {
 	ReconcileTimeout          time.Duration
 	WatchFilterValue          string
 	createAzureClusterService azureClusterServiceCreator
+
+	// ResourceNotifications triggers reconciles of the AzureClusters affected by out-of-band changes to Azure resources.
+	ResourceNotifications <-chan event.GenericEvent
+	// EventGridWebhookURL is the URL Event Grid delivers the resource notifications of cluster resource groups to.
+	EventGridWebhookURL string
 }
 
 type azureClusterServiceCreator func(clusterScope *scope.ClusterScope) (*azureClusterService, error)
@@ -77,14 +85,14 @@ func (acr *AzureClusterReconciler) SetupWithManager(ctx context.Context, mgr ctr
 	)
 	defer done()
 
-	var r reconcile.Reconciler = acr
+	var r reconcile.Reconciler = inflight.NewReconciler("AzureCluster", acr)
 	if options.Cache != nil {
-		r = coalescing.NewReconciler(acr, options.Cache, log)
+		r = coalescing.NewReconciler(r, options.Cache, log)
 	}
 
 	c, err := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options.Options).
-		For(&infrav1.AzureCluster{}).
+		For(&infrav1.AzureCluster{}, builder.WithPredicates(ResyncWithoutSyncPeriodOverride(log))).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(log, acr.WatchFilterValue)).
 		WithEventFilter(predicates.ResourceIsNotExternallyManaged(log)).
 		Build(r)
@@ -102,6 +110,25 @@ func (acr *AzureClusterReconciler) SetupWithManager(ctx context.Context, mgr ctr
 		return errors.Wrap(err, "failed adding a watch for ready clusters")
 	}
 
+	// Add a watch on the Secrets of the cluster identities to refresh the credentials when they're rotated.
+	if err = c.Watch(
+		&source.Kind{Type: &corev1.Secret{}},
+		handler.EnqueueRequestsFromMapFunc(ClientSecretToAzureClustersMapper(ctx, acr.Client, log)),
+	); err != nil {
+		return errors.Wrap(err, "failed adding a watch for cluster identity secrets")
+	}
+
+	if acr.ResourceNotifications != nil {
+		// Add a watch on resource notifications for out-of-band changes to Azure resources.
+		if err := c.Watch(
+			&source.Channel{Source: acr.ResourceNotifications},
+			&handler.EnqueueRequestForObject{},
+			predicates.ResourceNotPausedAndHasFilterLabel(log, acr.WatchFilterValue),
+		); err != nil {
+			return errors.Wrap(err, "failed adding a watch for resource notifications")
+		}
+	}
+
 	return nil
 }
 
@@ -111,12 +138,12 @@ func (acr *AzureClusterReconciler) SetupWithManager(ctx context.Context, mgr ctr
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinetemplates;azuremachinetemplates/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusteridentities;azureclusteridentities/status,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=list;
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=network.azure.com,resources=virtualnetworks;virtualnetworkssubnets;virtualnetworksvirtualnetworkpeerings;natgateways,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile idempotently gets, creates, and updates a cluster.
 func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
-	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultedLoopTimeout(acr.ReconcileTimeout))
-	defer cancel()
-
 	ctx, log, done := tele.StartSpanWithLogger(
 		ctx,
 		"controllers.AzureClusterReconciler.Reconcile",
@@ -138,6 +165,10 @@ func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return reconcile.Result{}, err
 	}
 
+	// The reconcile timeout can be overridden per AzureCluster, so it is only applied once the AzureCluster is fetched.
+	ctx, cancel := context.WithTimeout(ctx, clusterReconcileTimeout(azureCluster, acr.ReconcileTimeout))
+	defer cancel()
+
 	// Fetch the Cluster.
 	cluster, err := util.GetOwnerCluster(ctx, acr.Client, azureCluster.ObjectMeta)
 	if err != nil {
@@ -149,7 +180,8 @@ func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return reconcile.Result{}, nil
 	}
 
-	log = log.WithValues("cluster", cluster.Name)
+	log = log.WithValues(tele.LogKeyClusterName, cluster.Name)
+	ctx = tele.WithLogValues(ctx, tele.LogKeyClusterName, cluster.Name)
 
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, azureCluster) {
@@ -158,14 +190,23 @@ func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, nil
 	}
 
+	// Return early if the AzureCluster is in an edge zone and the EdgeZone feature isn't enabled for the Cluster,
+	// unless the object is being deleted.
+	if azureCluster.Spec.ExtendedLocation != nil && azureCluster.DeletionTimestamp.IsZero() &&
+		!feature.EnabledForCluster(feature.EdgeZone, cluster.Annotations) {
+		acr.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "FeatureNotEnabled", "EdgeZone feature is not enabled for the linked Cluster. Won't reconcile")
+		log.Info("EdgeZone feature is not enabled for the linked Cluster. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
 	if azureCluster.Spec.IdentityRef != nil {
 		identity, err := GetClusterIdentityFromRef(ctx, acr.Client, azureCluster.Namespace, azureCluster.Spec.IdentityRef)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
-		if !scope.IsClusterNamespaceAllowed(ctx, acr.Client, identity.Spec.AllowedNamespaces, azureCluster.Namespace) {
+		if !scope.IsClusterNamespaceAllowedByIdentity(ctx, acr.Client, identity, azureCluster.Namespace) {
 			conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.NamespaceNotAllowedByIdentity, clusterv1.ConditionSeverityError, "")
-			return reconcile.Result{}, errors.New("AzureClusterIdentity list of allowed namespaces doesn't include current cluster namespace")
+			return reconcile.Result{}, errors.New("AzureClusterIdentity allowed namespaces don't include, or denied namespaces include, current cluster namespace")
 		}
 	} else {
 		log.Info(fmt.Sprintf("WARNING, %s", deprecatedManagerCredsWarning))
@@ -177,6 +218,8 @@ func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		Client:       acr.Client,
 		Cluster:      cluster,
 		AzureCluster: azureCluster,
+
+		EventGridWebhookURL: acr.EventGridWebhookURL,
 	})
 	if err != nil {
 		err = errors.Wrap(err, "failed to create scope")
@@ -191,13 +234,48 @@ func (acr *AzureClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 	}()
 
+	acr.recordIdentityUsage(ctx, clusterScope)
+
 	// Handle deleted clusters
 	if !azureCluster.DeletionTimestamp.IsZero() {
 		return acr.reconcileDelete(ctx, clusterScope)
 	}
 
 	// Handle non-deleted clusters
-	return acr.reconcileNormal(ctx, clusterScope)
+	result, err := acr.reconcileNormal(ctx, clusterScope)
+	if syncPeriod, ok := durationAnnotation(azureCluster, infrav1.SyncPeriodAnnotation); ok && err == nil && result.IsZero() {
+		// Periodic resyncs are filtered out for clusters overriding their sync period, so requeue them instead.
+		result.RequeueAfter = syncPeriod
+	}
+	return result, err
+}
+
+// recordIdentityUsage records the identity and the token audience the AzureCluster is reconciled with, and emits an
+// audit event when the identity is first bound to the AzureCluster or changes.
+func (acr *AzureClusterReconciler) recordIdentityUsage(ctx context.Context, clusterScope *scope.ClusterScope) {
+	_, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureClusterReconciler.recordIdentityUsage")
+	defer done()
+
+	previous, recorded := clusterScope.RecordIdentityUsage()
+	identity, audience := clusterScope.Identity(), clusterScope.ResourceManagerEndpoint
+	switch {
+	case !recorded:
+		log.Info("AzureCluster identity bound", "identity", identity, "tokenAudience", audience)
+		acr.Recorder.Eventf(clusterScope.AzureCluster, corev1.EventTypeNormal, "AzureClusterIdentityBound",
+			"AzureCluster is reconciled with %s, with token audience %s", describeIdentity(identity), audience)
+	case previous != identity:
+		log.Info("AzureCluster identity changed", "previous", previous, "identity", identity, "tokenAudience", audience)
+		acr.Recorder.Eventf(clusterScope.AzureCluster, corev1.EventTypeNormal, "AzureClusterIdentityChanged",
+			"AzureCluster is reconciled with %s instead of %s, with token audience %s", describeIdentity(identity), describeIdentity(previous), audience)
+	}
+}
+
+// describeIdentity describes the identity recorded on an AzureCluster for events.
+func describeIdentity(identity string) string {
+	if identity == "" {
+		return "the manager environment credentials"
+	}
+	return fmt.Sprintf("AzureClusterIdentity %s", identity)
 }
 
 func (acr *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
@@ -219,13 +297,35 @@ func (acr *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 		return reconcile.Result{}, errors.Wrap(err, "failed to create a new AzureClusterReconciler")
 	}
 
+	// The ARM template describes the resources before they are reconciled, so that it can be reviewed beforehand.
+	if clusterScope.ARMTemplateExportRequested() {
+		if err := acs.ExportARMTemplate(ctx); err != nil {
+			acr.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "ARMTemplateExportFailed", errors.Wrap(err, "failed to export ARM template").Error())
+			log.Error(err, "failed to export ARM template")
+		}
+	}
+
+	if clusterScope.RoleDefinitionExportRequested() {
+		if err := acs.ExportRoleDefinition(ctx); err != nil {
+			acr.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "RoleDefinitionExportFailed", errors.Wrap(err, "failed to export role definition").Error())
+			log.Error(err, "failed to export role definition")
+		}
+	}
+
+	// Machines without an SSH public key use the key pair generated for the cluster.
+	if err := clusterScope.ReconcileSSHKeyPair(ctx); err != nil {
+		wrappedErr := errors.Wrap(err, "failed to reconcile cluster SSH key pair")
+		acr.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "SSHKeyPairReconcileFailed", wrappedErr.Error())
+		return reconcile.Result{}, wrappedErr
+	}
+
 	if err := acs.Reconcile(ctx); err != nil {
 		// Handle terminal & transient errors
 		var reconcileError azure.ReconcileError
 		if errors.As(err, &reconcileError) {
 			if reconcileError.IsTerminal() {
 				acr.Recorder.Eventf(clusterScope.AzureCluster, corev1.EventTypeWarning, "ReconcileErrror", errors.Wrapf(err, "failed to reconcile AzureCluster").Error())
-				log.Error(err, "failed to reconcile AzureCluster", "name", clusterScope.ClusterName())
+				log.Error(err, "failed to reconcile AzureCluster", tele.LogKeyClusterName, clusterScope.ClusterName())
 				conditions.MarkFalse(azureCluster, infrav1.NetworkInfrastructureReadyCondition, infrav1.FailedReason, clusterv1.ConditionSeverityError, "")
 				return reconcile.Result{}, nil
 			}
@@ -250,7 +350,7 @@ func (acr *AzureClusterReconciler) reconcileNormal(ctx context.Context, clusterS
 		azureCluster.Spec.ControlPlaneEndpoint.Host = clusterScope.APIServerHost()
 	}
 	if azureCluster.Spec.ControlPlaneEndpoint.Port == 0 {
-		azureCluster.Spec.ControlPlaneEndpoint.Port = clusterScope.APIServerPort()
+		azureCluster.Spec.ControlPlaneEndpoint.Port = clusterScope.APIServerFrontendPort()
 	}
 
 	// No errors, so mark us ready so the Cluster API Cluster Controller can pull it
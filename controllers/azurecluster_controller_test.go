@@ -18,11 +18,15 @@ package controllers
 
 import (
 	"context"
+	"testing"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/internal/test"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -51,3 +55,34 @@ var _ = Describe("AzureClusterReconciler", func() {
 		})
 	})
 })
+
+func TestRecordIdentityUsage(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := record.NewFakeRecorder(10)
+	acr := &AzureClusterReconciler{Recorder: recorder}
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		Spec: infrav1.AzureClusterSpec{
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				IdentityRef: &corev1.ObjectReference{Name: "my-identity"},
+			},
+		},
+	}
+	clusterScope := &scope.ClusterScope{
+		AzureClients: scope.AzureClients{ResourceManagerEndpoint: "https://management.azure.com/"},
+		AzureCluster: azureCluster,
+	}
+
+	acr.recordIdentityUsage(context.Background(), clusterScope)
+	g.Expect(recorder.Events).To(Receive(Equal("Normal AzureClusterIdentityBound AzureCluster is reconciled with AzureClusterIdentity default/my-identity, with token audience https://management.azure.com/")))
+
+	// No event is emitted while the identity doesn't change.
+	acr.recordIdentityUsage(context.Background(), clusterScope)
+	g.Expect(recorder.Events).NotTo(Receive())
+
+	azureCluster.Spec.IdentityRef = &corev1.ObjectReference{Name: "other-identity", Namespace: "identities"}
+	acr.recordIdentityUsage(context.Background(), clusterScope)
+	g.Expect(recorder.Events).To(Receive(Equal("Normal AzureClusterIdentityChanged AzureCluster is reconciled with AzureClusterIdentity identities/other-identity instead of AzureClusterIdentity default/my-identity, with token audience https://management.azure.com/")))
+	g.Expect(azureCluster.Annotations).To(HaveKeyWithValue(infrav1.IdentityAnnotation, "identities/other-identity"))
+}
@@ -18,17 +18,28 @@ package controllers
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bastionhosts"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/datacollection"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsresolvers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/eventsubscriptions"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loadbalancers"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loganalyticsworkspaces"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/natgateways"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/permissions"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/policyexemptions"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/privatedns"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roledefinitions"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/routetables"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/securitygroups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
@@ -37,15 +48,22 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vnetpeerings"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
+// identityPermissionsRequeue is how long to wait before checking the permissions of the cluster identity again when
+// some are missing.
+const identityPermissionsRequeue = 2 * time.Minute
+
 // azureClusterService is the reconciler called by the AzureCluster controller.
 type azureClusterService struct {
 	scope *scope.ClusterScope
 	// services is the list of services that are reconciled by this controller.
 	// The order of the services is important as it determines the order in which the services are reconciled.
-	services []azure.ServiceReconciler
-	skuCache *resourceskus.Cache
+	services              []azure.ServiceReconciler
+	skuCache              *resourceskus.Cache
+	permissionsClient     permissions.Client
+	roleDefinitionsClient roledefinitions.Client
 }
 
 // newAzureClusterService populates all the services based on input scope.
@@ -54,23 +72,102 @@ func newAzureClusterService(scope *scope.ClusterScope) (*azureClusterService, er
 	if err != nil {
 		return nil, errors.Wrap(err, "failed creating a NewCache")
 	}
+	groupsSvc, err := groups.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating groups service")
+	}
+	policyExemptionsSvc, err := policyexemptions.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating policy exemptions service")
+	}
+	dnsResolversSvc, err := dnsresolvers.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating DNS resolvers service")
+	}
+	tagsSvc, err := tags.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating tags service")
+	}
+	virtualNetworksSvc, err := virtualnetworks.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating virtual networks service")
+	}
+	securityGroupsSvc, err := securitygroups.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating security groups service")
+	}
+	routeTablesSvc, err := routetables.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating route tables service")
+	}
+	publicIPsSvc, err := publicips.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating public IPs service")
+	}
+	natGatewaysSvc, err := natgateways.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating NAT gateways service")
+	}
+	subnetsSvc, err := subnets.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating subnets service")
+	}
+	vnetPeeringsSvc, err := vnetpeerings.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating VNet peerings service")
+	}
+	loadBalancersSvc, err := loadbalancers.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating load balancers service")
+	}
+	privateDNSSvc, err := privatedns.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating private DNS service")
+	}
+	bastionHostsSvc, err := bastionhosts.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating bastion hosts service")
+	}
+	dataCollectionSvc, err := datacollection.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating data collection service")
+	}
+	logAnalyticsWorkspacesSvc, err := loganalyticsworkspaces.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating Log Analytics workspaces service")
+	}
+	eventSubscriptionsSvc, err := eventsubscriptions.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating event subscriptions service")
+	}
+	roleDefinitionsClient, err := roledefinitions.NewClient(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating role definitions client")
+	}
 	return &azureClusterService{
 		scope: scope,
 		services: []azure.ServiceReconciler{
-			groups.New(scope),
-			virtualnetworks.New(scope),
-			securitygroups.New(scope),
-			routetables.New(scope),
-			publicips.New(scope),
-			natgateways.New(scope),
-			subnets.New(scope),
-			vnetpeerings.New(scope),
-			loadbalancers.New(scope),
-			privatedns.New(scope),
-			bastionhosts.New(scope),
-			tags.New(scope),
+			groupsSvc,
+			policyExemptionsSvc,
+			eventSubscriptionsSvc,
+			logAnalyticsWorkspacesSvc,
+			dataCollectionSvc,
+			virtualNetworksSvc,
+			securityGroupsSvc,
+			routeTablesSvc,
+			publicIPsSvc,
+			natGatewaysSvc,
+			subnetsSvc,
+			vnetPeeringsSvc,
+			dnsResolversSvc,
+			loadBalancersSvc,
+			privateDNSSvc,
+			bastionHostsSvc,
+			tagsSvc,
 		},
-		skuCache: skuCache,
+		skuCache:              skuCache,
+		permissionsClient:     permissions.NewClient(scope),
+		roleDefinitionsClient: roleDefinitionsClient,
 	}, nil
 }
 
@@ -79,20 +176,125 @@ func (s *azureClusterService) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.Reconcile")
 	defer done()
 
+	if err := s.setDefaults(ctx); err != nil {
+		return err
+	}
+
+	if err := s.checkPermissions(ctx); err != nil {
+		return err
+	}
+
+	for _, service := range s.services {
+		if err := service.Reconcile(ctx); err != nil {
+			return errors.Wrapf(err, "failed to reconcile AzureCluster service %s", service.Name())
+		}
+	}
+
+	return nil
+}
+
+// ExportARMTemplate exports the desired state of the resources of the services as an ARM template.
+func (s *azureClusterService) ExportARMTemplate(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.ExportARMTemplate")
+	defer done()
+
+	if err := s.setDefaults(ctx); err != nil {
+		return err
+	}
+
+	template := armtemplate.New(s.scope.ResourceGroup())
+	for _, service := range s.services {
+		if exporter, ok := service.(azure.ResourceExporter); ok {
+			if err := exporter.ExportResources(ctx, template); err != nil {
+				return errors.Wrapf(err, "failed to export the resources of AzureCluster service %s", service.Name())
+			}
+		}
+	}
+
+	return s.scope.SaveARMTemplate(ctx, template)
+}
+
+// setDefaults sets the values of the AzureCluster the desired state of the services depends on.
+func (s *azureClusterService) setDefaults(ctx context.Context) error {
 	if err := s.setFailureDomainsForLocation(ctx); err != nil {
 		return errors.Wrap(err, "failed to get availability zones")
 	}
 
 	s.scope.SetDNSName()
 	s.scope.SetControlPlaneSecurityRules()
+	return nil
+}
 
+// checkPermissions checks the cluster identity is allowed to perform the actions the services need on the resource
+// group of the cluster before any of them creates resources, and reports the missing permissions in the
+// IdentityPermissionsReady condition. A failure to list the permissions doesn't block the reconciliation of the services.
+func (s *azureClusterService) checkPermissions(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.checkPermissions")
+	defer done()
+
+	actions := s.requiredActions()
+	if len(actions) == 0 {
+		return nil
+	}
+
+	missing, err := permissions.MissingResourceGroupActions(ctx, s.permissionsClient, s.scope.ResourceGroup(), actions)
+	if err != nil {
+		log.Error(err, "failed to check the permissions of the cluster identity")
+		conditions.MarkFalse(s.scope.AzureCluster, infrav1.IdentityPermissionsReadyCondition, infrav1.PermissionsCheckFailedReason,
+			clusterv1.ConditionSeverityWarning, "failed to list the permissions of the cluster identity: %s", err.Error())
+		return nil
+	}
+	if len(missing) > 0 {
+		err := errors.Errorf("the cluster identity is missing permissions on resource group %s: %s", s.scope.ResourceGroup(), strings.Join(missing, ", "))
+		conditions.MarkFalse(s.scope.AzureCluster, infrav1.IdentityPermissionsReadyCondition, infrav1.MissingPermissionsReason,
+			clusterv1.ConditionSeverityError, "%s", err.Error())
+		return azure.WithTransientError(err, identityPermissionsRequeue)
+	}
+
+	conditions.MarkTrue(s.scope.AzureCluster, infrav1.IdentityPermissionsReadyCondition)
+	return nil
+}
+
+// requiredActions returns the actions the cluster identity must be allowed to perform on the resource group of the
+// cluster for the services to reconcile their resources.
+func (s *azureClusterService) requiredActions() []string {
+	var actions []string
+	seen := make(map[string]struct{})
 	for _, service := range s.services {
-		if err := service.Reconcile(ctx); err != nil {
-			return errors.Wrapf(err, "failed to reconcile AzureCluster service %s", service.Name())
+		requirer, ok := service.(azure.PermissionRequirer)
+		if !ok {
+			continue
+		}
+		for _, action := range requirer.RequiredActions() {
+			if _, ok := seen[action]; !ok {
+				seen[action] = struct{}{}
+				actions = append(actions, action)
+			}
 		}
 	}
+	return actions
+}
 
-	return nil
+// ExportRoleDefinition exports the least-privilege custom role definition of the cluster identity, with the actions
+// the enabled services perform, and creates it in Azure if requested.
+func (s *azureClusterService) ExportRoleDefinition(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.azureClusterService.ExportRoleDefinition")
+	defer done()
+
+	if err := s.setDefaults(ctx); err != nil {
+		return err
+	}
+
+	roleDefinition := roledefinitions.New(s.scope.SubscriptionID(), s.scope.Namespace(), s.scope.ClusterName(), s.requiredActions())
+	if s.scope.RoleDefinitionCreationRequested() {
+		id := roledefinitions.ID(s.scope.SubscriptionID(), s.scope.Namespace(), s.scope.ClusterName())
+		if err := s.roleDefinitionsClient.CreateOrUpdate(ctx, id, roleDefinition); err != nil {
+			return err
+		}
+		log.V(2).Info("created role definition", "name", roleDefinition.Name, tele.LogKeyResourceID, id)
+	}
+
+	return s.scope.SaveRoleDefinition(ctx, roleDefinition)
 }
 
 // Delete reconciles all the services in a predetermined order.
@@ -114,6 +316,13 @@ func (s *azureClusterService) Delete(ctx context.Context) error {
 		return errors.Wrap(err, "failed to determine if the AzureCluster resource group is managed")
 	}
 	if managed {
+		// The link of a DNS forwarding ruleset can live outside of the resource group of the cluster, so it is deleted
+		// before the resource group.
+		if dnsResolverSvc, err := s.getService(dnsresolvers.ServiceName); err == nil {
+			if err := dnsResolverSvc.Delete(ctx); err != nil {
+				return errors.Wrap(err, "failed to delete DNS private resolver")
+			}
+		}
 		// if the resource group is managed, we delete the entire resource group directly.
 		if err := groupSvc.Delete(ctx); err != nil {
 			return errors.Wrap(err, "failed to delete resource group")
@@ -142,7 +351,12 @@ func (s *azureClusterService) getService(name string) (azure.ServiceReconciler,
 
 // setFailureDomainsForLocation sets the AzureCluster Status failure domains based on which Azure Availability Zones are available in the cluster location.
 // Note that this is not done in a webhook as it requires API calls to fetch the availability zones.
+// Clusters in an extended location have no failure domains, as extended locations have no availability zones.
 func (s *azureClusterService) setFailureDomainsForLocation(ctx context.Context) error {
+	if s.scope.ExtendedLocation() != nil {
+		return nil
+	}
+
 	zones, err := s.skuCache.GetZones(ctx, s.scope.Location())
 	if err != nil {
 		return errors.Wrapf(err, "failed to get zones for location %s", s.scope.Location())
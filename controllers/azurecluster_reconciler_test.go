@@ -21,17 +21,22 @@ import (
 	"errors"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/mock_azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/dnsresolvers"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/permissions/mock_permissions"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestAzureClusterServiceReconcile(t *testing.T) {
@@ -83,7 +88,7 @@ func TestAzureClusterServiceReconcile(t *testing.T) {
 					svcTwoMock,
 					svcThreeMock,
 				},
-				skuCache: resourceskus.NewStaticCache([]compute.ResourceSku{}, ""),
+				skuCache: resourceskus.NewStaticCache([]armcompute.ResourceSKU{}, ""),
 			}
 
 			err := s.Reconcile(context.TODO())
@@ -97,6 +102,96 @@ func TestAzureClusterServiceReconcile(t *testing.T) {
 	}
 }
 
+// permissionRequirerService is a service requiring permissions on the resource group of the cluster.
+type permissionRequirerService struct {
+	azure.ServiceReconciler
+	actions []string
+}
+
+func (s permissionRequirerService) RequiredActions() []string {
+	return s.actions
+}
+
+func TestAzureClusterServiceCheckPermissions(t *testing.T) {
+	cases := map[string]struct {
+		expectedError  string
+		expectedReason string
+		expect         func(m *mock_permissions.MockClientMockRecorder)
+	}{
+		"cluster identity is allowed to perform the actions": {
+			expectedError:  "",
+			expectedReason: "",
+			expect: func(m *mock_permissions.MockClientMockRecorder) {
+				m.ListResourceGroupPermissions(gomockinternal.AContext(), "my-rg").Return([]armauthorization.Permission{
+					{Actions: to.SliceOfPtrs("Microsoft.Network/*")},
+				}, nil)
+			},
+		},
+		"cluster identity is missing permissions": {
+			expectedError:  "the cluster identity is missing permissions on resource group my-rg: Microsoft.Network/loadBalancers/write",
+			expectedReason: infrav1.MissingPermissionsReason,
+			expect: func(m *mock_permissions.MockClientMockRecorder) {
+				m.ListResourceGroupPermissions(gomockinternal.AContext(), "my-rg").Return([]armauthorization.Permission{
+					{Actions: to.SliceOfPtrs("Microsoft.Network/*/read", "Microsoft.Network/virtualNetworks/write")},
+				}, nil)
+			},
+		},
+		"failure to list the permissions doesn't block the services": {
+			expectedError:  "",
+			expectedReason: infrav1.PermissionsCheckFailedReason,
+			expect: func(m *mock_permissions.MockClientMockRecorder) {
+				m.ListResourceGroupPermissions(gomockinternal.AContext(), "my-rg").Return(nil, errors.New("some error happened"))
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			permissionsMock := mock_permissions.NewMockClient(mockCtrl)
+
+			tc.expect(permissionsMock.EXPECT())
+
+			s := &azureClusterService{
+				scope: &scope.ClusterScope{
+					Cluster: &clusterv1.Cluster{},
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{ResourceGroup: "my-rg"},
+					},
+				},
+				services: []azure.ServiceReconciler{
+					mock_azure.NewMockServiceReconciler(mockCtrl),
+					permissionRequirerService{actions: []string{"Microsoft.Network/virtualNetworks/read", "Microsoft.Network/virtualNetworks/write"}},
+					permissionRequirerService{actions: []string{"Microsoft.Network/virtualNetworks/read", "Microsoft.Network/loadBalancers/write"}},
+					permissionRequirerService{},
+				},
+				permissionsClient: permissionsMock,
+			}
+
+			err := s.checkPermissions(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedError)))
+				var reconcileError azure.ReconcileError
+				g.Expect(errors.As(err, &reconcileError)).To(BeTrue())
+				g.Expect(reconcileError.IsTransient()).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			if tc.expectedReason != "" {
+				g.Expect(conditions.IsFalse(s.scope.AzureCluster, infrav1.IdentityPermissionsReadyCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(s.scope.AzureCluster, infrav1.IdentityPermissionsReadyCondition)).To(Equal(tc.expectedReason))
+			} else {
+				g.Expect(conditions.IsTrue(s.scope.AzureCluster, infrav1.IdentityPermissionsReadyCondition)).To(BeTrue())
+			}
+		})
+	}
+}
+
 func TestAzureClusterServiceDelete(t *testing.T) {
 	cases := map[string]struct {
 		expectedError string
@@ -108,9 +203,38 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 				gomock.InOrder(
 					grp.Name().Return(groups.ServiceName),
 					grp.IsManaged(gomockinternal.AContext()).Return(true, nil),
+					grp.Name().Return(groups.ServiceName),
+					one.Name().Return("one"),
+					two.Name().Return("two"),
+					three.Name().Return("three"),
+					grp.Delete(gomockinternal.AContext()).Return(nil))
+			},
+		},
+		"DNS private resolver is deleted before the resource group": {
+			expectedError: "",
+			expect: func(grp *mock_azure.MockServiceReconcilerMockRecorder, one *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder, three *mock_azure.MockServiceReconcilerMockRecorder) {
+				gomock.InOrder(
+					grp.Name().Return(groups.ServiceName),
+					grp.IsManaged(gomockinternal.AContext()).Return(true, nil),
+					grp.Name().Return(groups.ServiceName),
+					one.Name().Return("one"),
+					two.Name().Return(dnsresolvers.ServiceName),
+					two.Delete(gomockinternal.AContext()).Return(nil),
 					grp.Delete(gomockinternal.AContext()).Return(nil))
 			},
 		},
+		"DNS private resolver delete fails": {
+			expectedError: "failed to delete DNS private resolver: internal error",
+			expect: func(grp *mock_azure.MockServiceReconcilerMockRecorder, one *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder, three *mock_azure.MockServiceReconcilerMockRecorder) {
+				gomock.InOrder(
+					grp.Name().Return(groups.ServiceName),
+					grp.IsManaged(gomockinternal.AContext()).Return(true, nil),
+					grp.Name().Return(groups.ServiceName),
+					one.Name().Return("one"),
+					two.Name().Return(dnsresolvers.ServiceName),
+					two.Delete(gomockinternal.AContext()).Return(errors.New("internal error")))
+			},
+		},
 		"Error when checking if resource group is managed": {
 			expectedError: "failed to determine if the AzureCluster resource group is managed: an error happened",
 			expect: func(grp *mock_azure.MockServiceReconcilerMockRecorder, one *mock_azure.MockServiceReconcilerMockRecorder, two *mock_azure.MockServiceReconcilerMockRecorder, three *mock_azure.MockServiceReconcilerMockRecorder) {
@@ -125,6 +249,10 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 				gomock.InOrder(
 					grp.Name().Return(groups.ServiceName),
 					grp.IsManaged(gomockinternal.AContext()).Return(true, nil),
+					grp.Name().Return(groups.ServiceName),
+					one.Name().Return("one"),
+					two.Name().Return("two"),
+					three.Name().Return("three"),
 					grp.Delete(gomockinternal.AContext()).Return(errors.New("internal error")))
 			},
 		},
@@ -178,7 +306,7 @@ func TestAzureClusterServiceDelete(t *testing.T) {
 					svcTwoMock,
 					svcThreeMock,
 				},
-				skuCache: resourceskus.NewStaticCache([]compute.ResourceSku{}, ""),
+				skuCache: resourceskus.NewStaticCache([]armcompute.ResourceSKU{}, ""),
 			}
 
 			err := s.Delete(context.TODO())
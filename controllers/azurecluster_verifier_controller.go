@@ -0,0 +1,363 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// connectivityProbeName is the name of the pod probing the connectivity of the nodes in the workload cluster.
+	connectivityProbeName = "capz-connectivity-probe"
+	// connectivityProbeDNSContainer is the name of the probe container resolving a name through the DNS of the virtual network.
+	connectivityProbeDNSContainer = "dns"
+	// connectivityProbeEgressContainer is the name of the probe container reaching the internet.
+	connectivityProbeEgressContainer = "egress"
+	// connectivityProbeTimeout is how long the probe may take to complete, e.g. while pulling its image.
+	connectivityProbeTimeout = 5 * time.Minute
+	// connectivityProbePollInterval is how often a running probe is checked for completion.
+	connectivityProbePollInterval = 15 * time.Second
+	// apiServerRetryInterval is how often the API server is checked while it doesn't answer.
+	apiServerRetryInterval = time.Minute
+	// apiServerCheckTimeout is how long the API server may take to answer.
+	apiServerCheckTimeout = 10 * time.Second
+	// defaultVerificationInterval is how often the connectivity of a cluster is verified again when no interval is set.
+	defaultVerificationInterval = 30 * time.Minute
+)
+
+// apiServerChecker returns an error if the API server of the workload cluster isn't ready.
+type apiServerChecker func(ctx context.Context, c client.Client, cluster client.ObjectKey) error
+
+// AzureClusterVerifierReconciler verifies the connectivity of AzureClusters once their infrastructure is ready: that the
+// API server answers through its load balancer, and that the nodes resolve names through the DNS of the virtual network
+// and reach the internet.
+type AzureClusterVerifierReconciler struct {
+	client.Client
+	Recorder         record.EventRecorder
+	ReconcileTimeout time.Duration
+	WatchFilterValue string
+	// ProbeImage is the image of the connectivity probe run in the workload cluster. It must provide nslookup and wget.
+	ProbeImage string
+	// EgressURL is the URL the connectivity probe downloads to verify the egress of the nodes.
+	EgressURL string
+	// VerificationInterval is how often the connectivity of a cluster is verified again, 30 minutes by default.
+	VerificationInterval time.Duration
+
+	getWorkloadClusterClient workloadClusterClientGetter
+	checkAPIServer           apiServerChecker
+}
+
+// SetupWithManager initializes this controller with a manager.
+func (r *AzureClusterVerifierReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	_, log, done := tele.StartSpanWithLogger(ctx,
+		"controllers.AzureClusterVerifierReconciler.SetupWithManager",
+	)
+	defer done()
+
+	if r.getWorkloadClusterClient == nil {
+		r.getWorkloadClusterClient = func(ctx context.Context, c client.Client, cluster client.ObjectKey) (client.Client, error) {
+			return remote.NewClusterClient(ctx, "capz-verifier", c, cluster)
+		}
+	}
+	if r.checkAPIServer == nil {
+		r.checkAPIServer = checkAPIServerReady
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.AzureCluster{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(log, r.WatchFilterValue)).
+		// watch for the control plane becoming initialized
+		Watches(
+			&source.Kind{Type: &clusterv1.Cluster{}},
+			handler.EnqueueRequestsFromMapFunc(util.ClusterToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("AzureCluster"))),
+		).
+		Complete(r)
+}
+
+// Reconcile verifies the connectivity of an AzureCluster whose infrastructure is ready, and reports the results as
+// conditions.
+func (r *AzureClusterVerifierReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultedLoopTimeout(r.ReconcileTimeout))
+	defer cancel()
+
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureClusterVerifierReconciler.Reconcile",
+		tele.KVP("namespace", req.Namespace),
+		tele.KVP("name", req.Name),
+		tele.KVP("kind", "AzureCluster"),
+	)
+	defer done()
+
+	// Fetch the AzureCluster instance
+	azureCluster := &infrav1.AzureCluster{}
+	err := r.Get(ctx, req.NamespacedName, azureCluster)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("object was not found")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Infrastructure "Ready" only means that the Azure resources were created, verify that they work.
+	if !azureCluster.Status.Ready || !azureCluster.ObjectMeta.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	// Fetch the Cluster.
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, azureCluster.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cluster == nil {
+		log.Info("Cluster Controller has not yet set OwnerRef")
+		return reconcile.Result{}, nil
+	}
+
+	log = log.WithValues(tele.LogKeyClusterName, cluster.Name)
+	ctx = tele.WithLogValues(ctx, tele.LogKeyClusterName, cluster.Name)
+
+	// Return early if the object or Cluster is paused.
+	if annotations.IsPaused(cluster, azureCluster) {
+		log.Info("AzureCluster or linked Cluster is marked as paused. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(azureCluster, r.Client)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to init patch helper")
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, azureCluster, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			infrav1.APIServerAvailableCondition,
+			infrav1.DNSResolutionAvailableCondition,
+			infrav1.EgressAvailableCondition,
+		}}); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	if !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+		log.Info("Control plane is not initialized yet")
+		for _, condition := range []clusterv1.ConditionType{infrav1.APIServerAvailableCondition, infrav1.DNSResolutionAvailableCondition, infrav1.EgressAvailableCondition} {
+			conditions.MarkFalse(azureCluster, condition, infrav1.WaitingForControlPlaneReason, clusterv1.ConditionSeverityInfo, "")
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.checkAPIServer(ctx, r.Client, util.ObjectKey(cluster)); err != nil {
+		log.V(2).Info("API server is not available", "error", err.Error())
+		conditions.MarkFalse(azureCluster, infrav1.APIServerAvailableCondition, infrav1.APIServerUnavailableReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return reconcile.Result{RequeueAfter: apiServerRetryInterval}, nil
+	}
+	conditions.MarkTrue(azureCluster, infrav1.APIServerAvailableCondition)
+
+	workloadClient, err := r.getWorkloadClusterClient(ctx, r.Client, util.ObjectKey(cluster))
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to create workload cluster client")
+	}
+
+	return r.reconcileProbe(ctx, workloadClient, azureCluster)
+}
+
+// reconcileProbe runs the connectivity probe in the workload cluster, and reports its results once it completed.
+func (r *AzureClusterVerifierReconciler) reconcileProbe(ctx context.Context, workloadClient client.Client, azureCluster *infrav1.AzureCluster) (reconcile.Result, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureClusterVerifierReconciler.reconcileProbe")
+	defer done()
+
+	probe := &corev1.Pod{}
+	err := workloadClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceSystem, Name: connectivityProbeName}, probe)
+	if apierrors.IsNotFound(err) {
+		log.V(2).Info("starting connectivity probe")
+		if err := workloadClient.Create(ctx, r.connectivityProbe(azureCluster)); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to create connectivity probe")
+		}
+		for _, condition := range []clusterv1.ConditionType{infrav1.DNSResolutionAvailableCondition, infrav1.EgressAvailableCondition} {
+			if !conditions.Has(azureCluster, condition) || conditions.GetReason(azureCluster, condition) == infrav1.WaitingForControlPlaneReason {
+				conditions.MarkFalse(azureCluster, condition, infrav1.ProbeRunningReason, clusterv1.ConditionSeverityInfo, "")
+			}
+		}
+		return reconcile.Result{RequeueAfter: connectivityProbePollInterval}, nil
+	}
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to get connectivity probe")
+	}
+
+	age := time.Since(probe.CreationTimestamp.Time)
+	if probe.Status.Phase != corev1.PodSucceeded && probe.Status.Phase != corev1.PodFailed {
+		if age < connectivityProbeTimeout {
+			return reconcile.Result{RequeueAfter: connectivityProbePollInterval}, nil
+		}
+		message := fmt.Sprintf("connectivity probe did not complete within %s: %s", connectivityProbeTimeout, probeWaitingMessage(probe))
+		r.Recorder.Event(azureCluster, corev1.EventTypeWarning, "ConnectivityProbeFailed", message)
+		for _, condition := range []clusterv1.ConditionType{infrav1.DNSResolutionAvailableCondition, infrav1.EgressAvailableCondition} {
+			conditions.MarkFalse(azureCluster, condition, infrav1.ProbeFailedReason, clusterv1.ConditionSeverityWarning, message)
+		}
+		return r.restartProbe(ctx, workloadClient, probe, 0)
+	}
+
+	r.reportProbeResult(azureCluster, probe, connectivityProbeDNSContainer, infrav1.DNSResolutionAvailableCondition, infrav1.DNSResolutionFailedReason)
+	r.reportProbeResult(azureCluster, probe, connectivityProbeEgressContainer, infrav1.EgressAvailableCondition, infrav1.EgressFailedReason)
+
+	interval := r.VerificationInterval
+	if interval <= 0 {
+		interval = defaultVerificationInterval
+	}
+	return r.restartProbe(ctx, workloadClient, probe, interval-age)
+}
+
+// restartProbe deletes the connectivity probe after the given delay, so that the connectivity is verified again.
+func (r *AzureClusterVerifierReconciler) restartProbe(ctx context.Context, workloadClient client.Client, probe *corev1.Pod, after time.Duration) (reconcile.Result, error) {
+	if after > 0 {
+		return reconcile.Result{RequeueAfter: after}, nil
+	}
+	if err := workloadClient.Delete(ctx, probe); err != nil && !apierrors.IsNotFound(err) {
+		return reconcile.Result{}, errors.Wrap(err, "failed to delete connectivity probe")
+	}
+	return reconcile.Result{RequeueAfter: connectivityProbePollInterval}, nil
+}
+
+// reportProbeResult sets a condition from the termination state of a container of the completed connectivity probe.
+func (r *AzureClusterVerifierReconciler) reportProbeResult(azureCluster *infrav1.AzureCluster, probe *corev1.Pod, container string, condition clusterv1.ConditionType, failedReason string) {
+	for _, status := range probe.Status.ContainerStatuses {
+		if status.Name != container {
+			continue
+		}
+		if terminated := status.State.Terminated; terminated != nil {
+			if terminated.ExitCode == 0 {
+				conditions.MarkTrue(azureCluster, condition)
+				return
+			}
+			message := strings.TrimSpace(terminated.Message)
+			if message == "" {
+				message = fmt.Sprintf("exited with code %d", terminated.ExitCode)
+			}
+			conditions.MarkFalse(azureCluster, condition, failedReason, clusterv1.ConditionSeverityWarning, message)
+			return
+		}
+	}
+	conditions.MarkFalse(azureCluster, condition, infrav1.ProbeFailedReason, clusterv1.ConditionSeverityWarning,
+		"connectivity probe container %s did not run", container)
+}
+
+// connectivityProbe returns the pod probing the connectivity of a node of the workload cluster. It runs on the host
+// network with the DNS configuration of the node, so that it only depends on the virtual network, and tolerates all
+// taints, so that it runs before the CNI is installed.
+func (r *AzureClusterVerifierReconciler) connectivityProbe(azureCluster *infrav1.AzureCluster) *corev1.Pod {
+	dnsName := azureCluster.Spec.ControlPlaneEndpoint.Host
+	if net.ParseIP(dnsName) != nil {
+		// The API server has no name to resolve, resolve the host of the egress URL instead.
+		if u, err := url.Parse(r.EgressURL); err == nil {
+			dnsName = u.Hostname()
+		}
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      connectivityProbeName,
+			Namespace: metav1.NamespaceSystem,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":       connectivityProbeName,
+				"app.kubernetes.io/managed-by": "cluster-api-provider-azure",
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			HostNetwork:   true,
+			DNSPolicy:     corev1.DNSDefault,
+			NodeSelector:  map[string]string{corev1.LabelOSStable: "linux"},
+			Tolerations:   []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			Containers: []corev1.Container{
+				{
+					Name:                     connectivityProbeDNSContainer,
+					Image:                    r.ProbeImage,
+					Command:                  []string{"nslookup", dnsName},
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+				},
+				{
+					Name:                     connectivityProbeEgressContainer,
+					Image:                    r.ProbeImage,
+					Command:                  []string{"wget", "-q", "-T", "10", "-O", "/dev/null", r.EgressURL},
+					TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+				},
+			},
+		},
+	}
+}
+
+// probeWaitingMessage describes why the containers of a connectivity probe are not running, e.g. the failure to pull
+// the probe image.
+func probeWaitingMessage(probe *corev1.Pod) string {
+	for _, status := range probe.Status.ContainerStatuses {
+		if waiting := status.State.Waiting; waiting != nil && waiting.Reason != "" {
+			return strings.TrimSpace(fmt.Sprintf("%s %s", waiting.Reason, waiting.Message))
+		}
+	}
+	for _, condition := range probe.Status.Conditions {
+		if condition.Status == corev1.ConditionFalse && condition.Message != "" {
+			return condition.Message
+		}
+	}
+	return fmt.Sprintf("pod is %s", probe.Status.Phase)
+}
+
+// checkAPIServerReady queries the readiness endpoint of the API server of the workload cluster through its control
+// plane endpoint.
+func checkAPIServerReady(ctx context.Context, c client.Client, cluster client.ObjectKey) error {
+	restConfig, err := remote.RESTConfig(ctx, "capz-verifier", c, cluster)
+	if err != nil {
+		return err
+	}
+	restConfig.Timeout = apiServerCheckTimeout
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	if _, err := clientset.Discovery().RESTClient().Get().AbsPath("/readyz").DoRaw(ctx); err != nil {
+		return errors.Wrap(err, "API server is not ready")
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAzureClusterVerifierReconciler(t *testing.T) {
+	scheme, err := newScheme()
+	if err != nil {
+		t.Error(err)
+	}
+
+	newCluster := func(initialized bool) *clusterv1.Cluster {
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-cluster",
+				Namespace: "default",
+			},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{
+					APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+					Kind:       "AzureCluster",
+					Name:       "my-azure-cluster",
+				},
+			},
+		}
+		if initialized {
+			conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+		}
+		return cluster
+	}
+
+	newAzureCluster := func(ready bool) *infrav1.AzureCluster {
+		return &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-azure-cluster",
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: "cluster.x-k8s.io/v1beta1",
+						Kind:       "Cluster",
+						Name:       "my-cluster",
+					},
+				},
+			},
+			Spec: infrav1.AzureClusterSpec{
+				ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "my-cluster.eastus.cloudapp.azure.com", Port: 6443},
+			},
+			Status: infrav1.AzureClusterStatus{
+				Ready: ready,
+			},
+		}
+	}
+
+	newProbe := func(age time.Duration, phase corev1.PodPhase, statuses ...corev1.ContainerStatus) *corev1.Pod {
+		probe := (&AzureClusterVerifierReconciler{ProbeImage: "busybox"}).connectivityProbe(newAzureCluster(true))
+		probe.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+		probe.Status = corev1.PodStatus{
+			Phase:             phase,
+			ContainerStatuses: statuses,
+		}
+		return probe
+	}
+
+	terminated := func(name string, exitCode int32, message string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name:  name,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: message}},
+		}
+	}
+
+	cases := map[string]struct {
+		objects         []runtime.Object
+		workloadObjects []runtime.Object
+		apiServerErr    error
+		wantConditions  map[clusterv1.ConditionType]string
+		wantProbe       bool
+		wantRequeue     bool
+	}{
+		"should do nothing until the infrastructure is ready": {
+			objects: []runtime.Object{
+				newCluster(true),
+				newAzureCluster(false),
+			},
+		},
+		"should wait for the control plane to be initialized": {
+			objects: []runtime.Object{
+				newCluster(false),
+				newAzureCluster(true),
+			},
+			wantConditions: map[clusterv1.ConditionType]string{
+				infrav1.APIServerAvailableCondition:     infrav1.WaitingForControlPlaneReason,
+				infrav1.DNSResolutionAvailableCondition: infrav1.WaitingForControlPlaneReason,
+				infrav1.EgressAvailableCondition:        infrav1.WaitingForControlPlaneReason,
+			},
+		},
+		"should report an unavailable API server": {
+			objects: []runtime.Object{
+				newCluster(true),
+				newAzureCluster(true),
+			},
+			apiServerErr: errors.New("connection refused"),
+			wantConditions: map[clusterv1.ConditionType]string{
+				infrav1.APIServerAvailableCondition: infrav1.APIServerUnavailableReason,
+			},
+			wantRequeue: true,
+		},
+		"should start the connectivity probe": {
+			objects: []runtime.Object{
+				newCluster(true),
+				newAzureCluster(true),
+			},
+			wantConditions: map[clusterv1.ConditionType]string{
+				infrav1.APIServerAvailableCondition:     "",
+				infrav1.DNSResolutionAvailableCondition: infrav1.ProbeRunningReason,
+				infrav1.EgressAvailableCondition:        infrav1.ProbeRunningReason,
+			},
+			wantProbe:   true,
+			wantRequeue: true,
+		},
+		"should report the results of the connectivity probe": {
+			objects: []runtime.Object{
+				newCluster(true),
+				newAzureCluster(true),
+			},
+			workloadObjects: []runtime.Object{
+				newProbe(time.Minute, corev1.PodFailed,
+					terminated(connectivityProbeDNSContainer, 0, ""),
+					terminated(connectivityProbeEgressContainer, 1, "wget: download timed out")),
+			},
+			wantConditions: map[clusterv1.ConditionType]string{
+				infrav1.APIServerAvailableCondition:     "",
+				infrav1.DNSResolutionAvailableCondition: "",
+				infrav1.EgressAvailableCondition:        infrav1.EgressFailedReason,
+			},
+			wantProbe:   true,
+			wantRequeue: true,
+		},
+		"should restart a connectivity probe older than the verification interval": {
+			objects: []runtime.Object{
+				newCluster(true),
+				newAzureCluster(true),
+			},
+			workloadObjects: []runtime.Object{
+				newProbe(time.Hour, corev1.PodSucceeded,
+					terminated(connectivityProbeDNSContainer, 0, ""),
+					terminated(connectivityProbeEgressContainer, 0, "")),
+			},
+			wantConditions: map[clusterv1.ConditionType]string{
+				infrav1.APIServerAvailableCondition:     "",
+				infrav1.DNSResolutionAvailableCondition: "",
+				infrav1.EgressAvailableCondition:        "",
+			},
+			wantRequeue: true,
+		},
+		"should fail a connectivity probe that does not complete": {
+			objects: []runtime.Object{
+				newCluster(true),
+				newAzureCluster(true),
+			},
+			workloadObjects: []runtime.Object{
+				newProbe(10*time.Minute, corev1.PodPending, corev1.ContainerStatus{
+					Name:  connectivityProbeDNSContainer,
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+				}),
+			},
+			wantConditions: map[clusterv1.ConditionType]string{
+				infrav1.APIServerAvailableCondition:     "",
+				infrav1.DNSResolutionAvailableCondition: infrav1.ProbeFailedReason,
+				infrav1.EgressAvailableCondition:        infrav1.ProbeFailedReason,
+			},
+			wantRequeue: true,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tc.objects...).Build()
+			workloadClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tc.workloadObjects...).Build()
+
+			reconciler := &AzureClusterVerifierReconciler{
+				Client:               c,
+				Recorder:             record.NewFakeRecorder(128),
+				ProbeImage:           "busybox",
+				EgressURL:            "https://mcr.microsoft.com/v2/",
+				VerificationInterval: 30 * time.Minute,
+				getWorkloadClusterClient: func(_ context.Context, _ client.Client, _ client.ObjectKey) (client.Client, error) {
+					return workloadClient, nil
+				},
+				checkAPIServer: func(_ context.Context, _ client.Client, _ client.ObjectKey) error {
+					return tc.apiServerErr
+				},
+			}
+
+			result, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: "default",
+					Name:      "my-azure-cluster",
+				},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.RequeueAfter > 0).To(Equal(tc.wantRequeue))
+
+			azureCluster := &infrav1.AzureCluster{}
+			g.Expect(c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "my-azure-cluster"}, azureCluster)).To(Succeed())
+			for _, conditionType := range []clusterv1.ConditionType{infrav1.APIServerAvailableCondition, infrav1.DNSResolutionAvailableCondition, infrav1.EgressAvailableCondition} {
+				condition := conditions.Get(azureCluster, conditionType)
+				reason, ok := tc.wantConditions[conditionType]
+				if !ok {
+					g.Expect(condition).To(BeNil(), "condition %s", conditionType)
+					continue
+				}
+				g.Expect(condition).NotTo(BeNil(), "condition %s", conditionType)
+				g.Expect(condition.Reason).To(Equal(reason), "condition %s", conditionType)
+				if reason == "" {
+					g.Expect(condition.Status).To(Equal(corev1.ConditionTrue), "condition %s", conditionType)
+				} else {
+					g.Expect(condition.Status).To(Equal(corev1.ConditionFalse), "condition %s", conditionType)
+				}
+			}
+
+			probe := &corev1.Pod{}
+			err = workloadClient.Get(context.Background(), types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: connectivityProbeName}, probe)
+			if tc.wantProbe {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(probe.Spec.HostNetwork).To(BeTrue())
+				g.Expect(probe.Spec.Containers).To(HaveLen(2))
+				g.Expect(probe.Spec.Containers[0].Command).To(Equal([]string{"nslookup", "my-cluster.eastus.cloudapp.azure.com"}))
+			} else {
+				g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+	}
+}
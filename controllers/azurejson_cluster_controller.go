@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// AzureJSONClusterReconciler reconciles the cloud provider config secret of AzureCluster objects.
+type AzureJSONClusterReconciler struct {
+	client.Client
+	Recorder         record.EventRecorder
+	ReconcileTimeout time.Duration
+	WatchFilterValue string
+}
+
+// SetupWithManager initializes this controller with a manager.
+func (r *AzureJSONClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	_, log, done := tele.StartSpanWithLogger(ctx,
+		"controllers.AzureJSONClusterReconciler.SetupWithManager",
+	)
+	defer done()
+
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.AzureCluster{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(log, r.WatchFilterValue)).
+		Owns(&corev1.Secret{}).
+		Build(r)
+	if err != nil {
+		return errors.Wrap(err, "error creating controller")
+	}
+
+	// Add a watch on the cluster identities so that a change of credentials reaches the cloud provider config.
+	if err = c.Watch(
+		&source.Kind{Type: &infrav1.AzureClusterIdentity{}},
+		handler.EnqueueRequestsFromMapFunc(AzureClusterIdentityToAzureClustersMapper(ctx, r.Client, log)),
+	); err != nil {
+		return errors.Wrap(err, "failed adding a watch for cluster identities")
+	}
+
+	// Add a watch on the Secrets of the cluster identities to refresh the cloud provider config when they're rotated.
+	if err = c.Watch(
+		&source.Kind{Type: &corev1.Secret{}},
+		handler.EnqueueRequestsFromMapFunc(ClientSecretToAzureClustersMapper(ctx, r.Client, log)),
+	); err != nil {
+		return errors.Wrap(err, "failed adding a watch for cluster identity secrets")
+	}
+
+	return nil
+}
+
+// Reconcile reconciles the cloud provider config secret for an AzureCluster, keeping it in sync with
+// the network and identity settings of the cluster.
+func (r *AzureJSONClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultedLoopTimeout(r.ReconcileTimeout))
+	defer cancel()
+
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureJSONClusterReconciler.Reconcile",
+		tele.KVP("namespace", req.Namespace),
+		tele.KVP("name", req.Name),
+		tele.KVP("kind", "AzureCluster"),
+	)
+	defer done()
+
+	// Fetch the AzureCluster instance
+	azureCluster := &infrav1.AzureCluster{}
+	err := r.Get(ctx, req.NamespacedName, azureCluster)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("object was not found")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Don't recreate the secret while the cluster is going away.
+	if !azureCluster.ObjectMeta.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	// Fetch the Cluster.
+	cluster, err := util.GetOwnerCluster(ctx, r.Client, azureCluster.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if cluster == nil {
+		log.Info("Cluster Controller has not yet set OwnerRef")
+		return reconcile.Result{}, nil
+	}
+
+	log = log.WithValues(tele.LogKeyClusterName, cluster.Name)
+	ctx = tele.WithLogValues(ctx, tele.LogKeyClusterName, cluster.Name)
+
+	// Return early if the object or Cluster is paused.
+	if annotations.IsPaused(cluster, azureCluster) {
+		log.Info("AzureCluster or linked Cluster is marked as paused. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	// Create the scope.
+	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+		Client:       r.Client,
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+	})
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to create scope")
+	}
+
+	apiVersion, kind := infrav1.GroupVersion.WithKind("AzureCluster").ToAPIVersionAndKind()
+	owner := metav1.OwnerReference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Name:       azureCluster.GetName(),
+		UID:        azureCluster.GetUID(),
+	}
+
+	identityType := infrav1.VMIdentityNone
+	userAssignedIdentityIfExists := ""
+	if identity := azureCluster.Spec.CloudProviderIdentity; identity != nil && identity.Type != "" {
+		identityType = identity.Type
+		userAssignedIdentityIfExists = identity.UserAssignedIdentityClientID
+		if identity.Type == infrav1.CloudProviderWorkloadIdentity {
+			userAssignedIdentityIfExists = identity.WorkloadIdentityClientID
+		}
+	}
+
+	newSecret, err := GetCloudProviderSecret(
+		clusterScope,
+		azureCluster.Namespace,
+		azureCluster.Name,
+		owner,
+		identityType,
+		userAssignedIdentityIfExists,
+	)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to create cloud provider config")
+	}
+	newSecret.Name = azure.GenerateCloudProviderSecretName(azureCluster.Name)
+
+	if err := reconcileAzureSecret(ctx, r.Client, owner, newSecret, clusterScope.ClusterName()); err != nil {
+		r.Recorder.Eventf(azureCluster, corev1.EventTypeWarning, "Error reconciling cloud provider secret for AzureCluster", err.Error())
+		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile azure secret")
+	}
+
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAzureJSONClusterReconciler(t *testing.T) {
+	scheme, err := newScheme()
+	if err != nil {
+		t.Error(err)
+	}
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-cluster",
+		},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				Kind:       "AzureCluster",
+				Name:       "my-azure-cluster",
+			},
+		},
+	}
+
+	newAzureCluster := func(identity *infrav1.CloudProviderIdentity) *infrav1.AzureCluster {
+		return &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-azure-cluster",
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: "cluster.x-k8s.io/v1beta1",
+						Kind:       "Cluster",
+						Name:       "my-cluster",
+					},
+				},
+			},
+			Spec: infrav1.AzureClusterSpec{
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+					SubscriptionID:        "123",
+					CloudProviderIdentity: identity,
+				},
+				NetworkSpec: infrav1.NetworkSpec{
+					Subnets: infrav1.Subnets{
+						{
+							SubnetClassSpec: infrav1.SubnetClassSpec{
+								Role: infrav1.SubnetNode,
+							},
+							Name: "node",
+						},
+					},
+				},
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		objects      []runtime.Object
+		fail         bool
+		err          string
+		wantManaged  bool
+		wantIdentity string
+		wantWorkload string
+	}{
+		"should reconcile normally": {
+			objects: []runtime.Object{
+				cluster,
+				newAzureCluster(nil),
+			},
+		},
+		"should use user-assigned identity": {
+			objects: []runtime.Object{
+				cluster,
+				newAzureCluster(&infrav1.CloudProviderIdentity{
+					Type:                         infrav1.VMIdentityUserAssigned,
+					UserAssignedIdentityClientID: "my-client-id",
+				}),
+			},
+			wantManaged:  true,
+			wantIdentity: "my-client-id",
+		},
+		"user-assigned identity without client ID should return error": {
+			objects: []runtime.Object{
+				cluster,
+				newAzureCluster(&infrav1.CloudProviderIdentity{
+					Type: infrav1.VMIdentityUserAssigned,
+				}),
+			},
+			fail: true,
+			err:  "failed to create cloud provider config: expected a non-empty userIdentityID",
+		},
+		"should use workload identity": {
+			objects: []runtime.Object{
+				cluster,
+				newAzureCluster(&infrav1.CloudProviderIdentity{
+					Type:                     infrav1.CloudProviderWorkloadIdentity,
+					WorkloadIdentityClientID: "my-workload-client-id",
+				}),
+			},
+			wantWorkload: "my-workload-client-id",
+		},
+		"workload identity without client ID should return error": {
+			objects: []runtime.Object{
+				cluster,
+				newAzureCluster(&infrav1.CloudProviderIdentity{
+					Type: infrav1.CloudProviderWorkloadIdentity,
+				}),
+			},
+			fail: true,
+			err:  "failed to create cloud provider config: expected a non-empty client ID for workload identity",
+		},
+	}
+
+	os.Setenv("AZURE_CLIENT_ID", "fooClient")
+	os.Setenv("AZURE_CLIENT_SECRET", "fooSecret")
+	os.Setenv("AZURE_TENANT_ID", "fooTenant")
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tc.objects...).Build()
+
+			reconciler := &AzureJSONClusterReconciler{
+				Client:   client,
+				Recorder: record.NewFakeRecorder(128),
+			}
+
+			_, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: "",
+					Name:      "my-azure-cluster",
+				},
+			})
+			if tc.fail {
+				if diff := cmp.Diff(tc.err, err.Error()); diff != "" {
+					t.Error(diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected success, but got error: %s", err.Error())
+			}
+
+			secret := &corev1.Secret{}
+			if err := client.Get(context.Background(), types.NamespacedName{Name: "my-azure-cluster-cloud-config"}, secret); err != nil {
+				t.Fatalf("expected cloud provider secret to exist: %s", err.Error())
+			}
+			config := &CloudProviderConfig{}
+			if err := json.Unmarshal(secret.Data["worker-node-azure.json"], config); err != nil {
+				t.Fatal(err)
+			}
+			if config.SubnetName != "node" {
+				t.Errorf("expected subnet name %q, got %q", "node", config.SubnetName)
+			}
+			if config.UseManagedIdentityExtension != tc.wantManaged {
+				t.Errorf("expected useManagedIdentityExtension %t, got %t", tc.wantManaged, config.UseManagedIdentityExtension)
+			}
+			if config.UserAssignedIdentityID != tc.wantIdentity {
+				t.Errorf("expected userAssignedIdentityID %q, got %q", tc.wantIdentity, config.UserAssignedIdentityID)
+			}
+			if tc.wantWorkload != "" {
+				if !config.UseFederatedWorkloadIdentityExtension {
+					t.Error("expected useFederatedWorkloadIdentityExtension to be set")
+				}
+				if config.AadClientID != tc.wantWorkload {
+					t.Errorf("expected aadClientId %q, got %q", tc.wantWorkload, config.AadClientID)
+				}
+				if config.AadClientSecret != "" {
+					t.Errorf("expected no aadClientSecret, got %q", config.AadClientSecret)
+				}
+				if config.AADFederatedTokenFile != azure.DefaultFederatedTokenFile {
+					t.Errorf("expected aadFederatedTokenFile %q, got %q", azure.DefaultFederatedTokenFile, config.AADFederatedTokenFile)
+				}
+			}
+		})
+	}
+}
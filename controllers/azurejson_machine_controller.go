@@ -136,7 +136,8 @@ func (r *AzureJSONMachineReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return reconcile.Result{}, nil
 	}
 
-	log = log.WithValues("cluster", cluster.Name)
+	log = log.WithValues(tele.LogKeyClusterName, cluster.Name)
+	ctx = tele.WithLogValues(ctx, tele.LogKeyClusterName, cluster.Name)
 
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, azureMachine) {
@@ -186,7 +187,10 @@ func (r *AzureJSONMachineReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	userAssignedIdentityIfExists := ""
 	if len(azureMachine.Spec.UserAssignedIdentities) > 0 {
 		// TODO: remove this ClientID lookup code when the fixed cloud-provider-azure is default
-		idsClient := identities.NewClient(clusterScope)
+		idsClient, err := identities.NewClient(clusterScope)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to create identities client")
+		}
 		userAssignedIdentityIfExists, err = idsClient.GetClientID(
 			ctx, azureMachine.Spec.UserAssignedIdentities[0].ProviderID)
 		if err != nil {
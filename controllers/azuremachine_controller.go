@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
@@ -28,7 +29,9 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/inflight"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -40,6 +43,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -52,6 +56,9 @@ type AzureMachineReconciler struct {
 	ReconcileTimeout          time.Duration
 	WatchFilterValue          string
 	createAzureMachineService azureMachineServiceCreator
+
+	// ResourceNotifications triggers reconciles of the AzureMachines affected by out-of-band changes to Azure resources.
+	ResourceNotifications <-chan event.GenericEvent
 }
 
 type azureMachineServiceCreator func(machineScope *scope.MachineScope) (*azureMachineService, error)
@@ -78,9 +85,9 @@ func (amr *AzureMachineReconciler) SetupWithManager(ctx context.Context, mgr ctr
 	)
 	defer done()
 
-	var r reconcile.Reconciler = amr
+	var r reconcile.Reconciler = inflight.NewReconciler("AzureMachine", amr)
 	if options.Cache != nil {
-		r = coalescing.NewReconciler(amr, options.Cache, log)
+		r = coalescing.NewReconciler(r, options.Cache, log)
 	}
 
 	// create mapper to transform incoming AzureClusters into AzureMachine requests
@@ -123,6 +130,17 @@ func (amr *AzureMachineReconciler) SetupWithManager(ctx context.Context, mgr ctr
 		return errors.Wrap(err, "failed adding a watch for ready clusters")
 	}
 
+	if amr.ResourceNotifications != nil {
+		// Add a watch on resource notifications for out-of-band changes to Azure resources.
+		if err := c.Watch(
+			&source.Channel{Source: amr.ResourceNotifications},
+			&handler.EnqueueRequestForObject{},
+			predicates.ResourceNotPausedAndHasFilterLabel(log, amr.WatchFilterValue),
+		); err != nil {
+			return errors.Wrap(err, "failed adding a watch for resource notifications")
+		}
+	}
+
 	return nil
 }
 
@@ -131,6 +149,7 @@ func (amr *AzureMachineReconciler) SetupWithManager(ctx context.Context, mgr ctr
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
 
 // Reconcile idempotently gets, creates, and updates a machine.
 func (amr *AzureMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -177,7 +196,8 @@ func (amr *AzureMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return reconcile.Result{}, nil
 	}
 
-	log = log.WithValues("cluster", cluster.Name)
+	log = log.WithValues(tele.LogKeyClusterName, cluster.Name)
+	ctx = tele.WithLogValues(ctx, tele.LogKeyClusterName, cluster.Name)
 
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, azureMachine) {
@@ -185,6 +205,15 @@ func (amr *AzureMachineReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, nil
 	}
 
+	// Return early if the AzureMachine runs Windows and the Windows feature isn't enabled for the Cluster, unless the
+	// object is being deleted.
+	if azureMachine.Spec.OSDisk.OSType == azure.WindowsOS && azureMachine.DeletionTimestamp.IsZero() &&
+		!feature.EnabledForCluster(feature.Windows, cluster.Annotations) {
+		amr.Recorder.Eventf(azureMachine, corev1.EventTypeWarning, "FeatureNotEnabled", "Windows feature is not enabled for the linked Cluster. Won't reconcile")
+		log.Info("Windows feature is not enabled for the linked Cluster. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
 	log = log.WithValues("AzureCluster", cluster.Spec.InfrastructureRef.Name)
 	azureClusterName := client.ObjectKey{
 		Namespace: azureMachine.Namespace,
@@ -268,6 +297,17 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 		return reconcile.Result{}, nil
 	}
 
+	// Clusters using user-defined routing for egress must not have any public IPs.
+	if machineScope.AzureMachine.Spec.AllocatePublicIP && clusterScope.AzureCluster.Spec.NetworkSpec.IsUserDefinedRouting() {
+		err := errors.New("allocatePublicIP cannot be used when the cluster outboundType is UserDefinedRouting")
+		amr.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "InvalidConfiguration", err.Error())
+		log.Error(err, "Invalid AzureMachine configuration")
+		machineScope.SetFailureReason(capierrors.InvalidConfigurationMachineError)
+		machineScope.SetFailureMessage(err)
+		machineScope.SetNotReady()
+		return reconcile.Result{}, nil
+	}
+
 	var reconcileError azure.ReconcileError
 
 	// Initialize the cache to be used by the AzureMachine services.
@@ -284,11 +324,30 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 		return reconcile.Result{}, errors.Wrap(err, "failed to init machine scope cache")
 	}
 
+	machineScope.SetEtcdDataDiskCondition()
+
+	waitForControlPlane, err := amr.reconcileHibernation(ctx, machineScope, clusterScope)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile cluster hibernation")
+	}
+
+	if err := amr.reconcileControlPlaneZone(ctx, machineScope, clusterScope); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to select the availability zone of the control plane machine")
+	}
+
 	ams, err := amr.createAzureMachineService(machineScope)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to create azure machine service")
 	}
 
+	// The ARM template describes the resources before they are reconciled, so that it can be reviewed beforehand.
+	if machineScope.ARMTemplateExportRequested() {
+		if err := ams.ExportARMTemplate(ctx); err != nil {
+			amr.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeWarning, "ARMTemplateExportFailed", errors.Wrap(err, "failed to export ARM template").Error())
+			log.Error(err, "failed to export ARM template")
+		}
+	}
+
 	if err := ams.Reconcile(ctx); err != nil {
 		// This means that a VM was created and managed by this controller, but is not present anymore.
 		// In this case, we mark it as failed and leave it to MHC for remediation
@@ -326,11 +385,183 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile AzureMachine")
 	}
 
-	machineScope.SetReady()
+	// A provisioned VM can still be stopped or deallocated out of band, report it instead of a running VM.
+	ready := true
+	switch powerState := machineScope.PowerState(); powerState {
+	case infrav1.PowerStateStopping, infrav1.PowerStateStopped, infrav1.PowerStateDeallocating, infrav1.PowerStateDeallocated, infrav1.PowerStateHibernated:
+		log.V(2).Info("virtual machine is not running", "powerState", powerState)
+		ready = false
+		if machineScope.DesiredPowerState() == infrav1.PowerStateHibernated {
+			conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, infrav1.VMSuspendedReason, clusterv1.ConditionSeverityInfo, "virtual machine is %s", powerState)
+			break
+		}
+		if machineScope.IsHibernated() {
+			conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, infrav1.VMHibernatedReason, clusterv1.ConditionSeverityInfo, "virtual machine is %s", powerState)
+			if waitForControlPlane {
+				log.V(2).Info("waiting for the control plane to resume before starting the virtual machine")
+				return reconcile.Result{RequeueAfter: reconciler.DefaultReconcilerRequeue}, nil
+			}
+			break
+		}
+		conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, infrav1.VMStoppedReason, clusterv1.ConditionSeverityWarning, "virtual machine is %s", powerState)
+	case infrav1.PowerStateRunning:
+		if !clusterScope.IsHibernated() {
+			machineScope.SetHibernated(false)
+		}
+	case infrav1.PowerStateStarting:
+		conditions.MarkFalse(machineScope.AzureMachine, infrav1.VMRunningCondition, infrav1.VMStartingReason, clusterv1.ConditionSeverityInfo, "")
+		machineScope.SetReady()
+		return reconcile.Result{RequeueAfter: reconciler.DefaultReconcilerRequeue}, nil
+	}
+
+	if ready {
+		machineScope.SetReady()
+	} else {
+		machineScope.SetNotReady()
+	}
+
+	// Reconcile again when the next scheduled snapshots of the disks are due.
+	if next := machineScope.NextDiskSnapshotTime(); next != nil {
+		if requeueAfter := time.Until(*next); requeueAfter > 0 {
+			return reconcile.Result{RequeueAfter: requeueAfter}, nil
+		}
+	}
 
 	return reconcile.Result{}, nil
 }
 
+// reconcileHibernation sets the power state of the AzureMachine VM required by the hibernation of its cluster. The VMs
+// of a hibernated cluster are deallocated. When the cluster resumes, the control plane VMs are started first and the
+// worker VMs are kept deallocated until then, in which case it returns true.
+func (amr *AzureMachineReconciler) reconcileHibernation(ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (bool, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachineReconciler.reconcileHibernation")
+	defer done()
+
+	if clusterScope.IsHibernated() {
+		machineScope.SetHibernated(true)
+		machineScope.SetClusterPowerState(infrav1.PowerStateDeallocated)
+		return false, nil
+	}
+
+	if !machineScope.IsHibernated() {
+		return false, nil
+	}
+
+	if !machineScope.IsControlPlane() {
+		controlPlaneMachine, err := HibernatedControlPlaneMachine(ctx, amr.Client, machineScope.AzureMachine.Namespace, clusterScope.ClusterName())
+		if err != nil {
+			return false, err
+		}
+		if controlPlaneMachine != "" {
+			log.V(4).Info("control plane machine has not resumed yet", "azureMachine", controlPlaneMachine)
+			machineScope.SetClusterPowerState(infrav1.PowerStateDeallocated)
+			return true, nil
+		}
+	}
+
+	machineScope.SetClusterPowerState(infrav1.PowerStateRunning)
+	return false, nil
+}
+
+// reconcileControlPlaneZone places a control plane AzureMachine without a failure domain in the control plane failure
+// domain of the cluster with the fewest control plane machines, when the cluster spreads them automatically. The zone is
+// recorded in the failure domain of the AzureMachine so that it is kept until the VM is created in it.
+func (amr *AzureMachineReconciler) reconcileControlPlaneZone(ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachineReconciler.reconcileControlPlaneZone")
+	defer done()
+
+	if !machineScope.IsControlPlane() || clusterScope.AzureCluster.Spec.ControlPlaneZoneSpreading != infrav1.ControlPlaneZoneSpreadingAutomatic {
+		return nil
+	}
+	// An explicit failure domain takes precedence, and the zone of an existing VM can't change.
+	if machineScope.AvailabilityZone() != "" || machineScope.ProviderID() != "" {
+		return nil
+	}
+	failureDomains := clusterScope.AzureCluster.Status.FailureDomains.FilterControlPlane()
+	if len(failureDomains) == 0 {
+		return nil
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := amr.Client.List(ctx, machines,
+		client.InNamespace(machineScope.AzureMachine.Namespace),
+		client.MatchingLabels{clusterv1.ClusterLabelName: clusterScope.ClusterName()},
+		client.HasLabels{clusterv1.MachineControlPlaneLabelName},
+	); err != nil {
+		return errors.Wrap(err, "failed to list control plane Machines")
+	}
+	azureMachines := &infrav1.AzureMachineList{}
+	if err := amr.Client.List(ctx, azureMachines,
+		client.InNamespace(machineScope.AzureMachine.Namespace),
+		client.MatchingLabels{clusterv1.ClusterLabelName: clusterScope.ClusterName()},
+		client.HasLabels{clusterv1.MachineControlPlaneLabelName},
+	); err != nil {
+		return errors.Wrap(err, "failed to list control plane AzureMachines")
+	}
+
+	zones := controlPlaneZones(machineScope.Machine.Name, machines.Items, azureMachines.Items)
+	zone := leastUsedFailureDomain(failureDomains, zones)
+	log.V(2).Info("placing control plane machine in the failure domain with the fewest control plane machines", "failureDomain", zone)
+	machineScope.AzureMachine.Spec.FailureDomain = &zone
+	amr.Recorder.Eventf(machineScope.AzureMachine, corev1.EventTypeNormal, "FailureDomainSelected", "Placed in failure domain %s", zone)
+
+	return nil
+}
+
+// controlPlaneZones returns the zones of the control plane machines of a cluster other than the given one and than the
+// ones being deleted: the failure domain of the Machine, else the zone the VM of its AzureMachine was placed in, else
+// the failure domain of the AzureMachine.
+func controlPlaneZones(machineName string, machines []clusterv1.Machine, azureMachines []infrav1.AzureMachine) []string {
+	azureMachinesByName := make(map[string]infrav1.AzureMachine, len(azureMachines))
+	for _, azureMachine := range azureMachines {
+		azureMachinesByName[azureMachine.Name] = azureMachine
+	}
+
+	var zones []string
+	for _, machine := range machines {
+		if machine.Name == machineName || !machine.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if machine.Spec.FailureDomain != nil {
+			zones = append(zones, *machine.Spec.FailureDomain)
+			continue
+		}
+		azureMachine, ok := azureMachinesByName[machine.Spec.InfrastructureRef.Name]
+		if !ok {
+			continue
+		}
+		if azureMachine.Status.Placement != nil && azureMachine.Status.Placement.AvailabilityZone != "" {
+			zones = append(zones, azureMachine.Status.Placement.AvailabilityZone)
+		} else if azureMachine.Spec.FailureDomain != nil {
+			zones = append(zones, *azureMachine.Spec.FailureDomain)
+		}
+	}
+	return zones
+}
+
+// leastUsedFailureDomain returns the failure domain with the fewest of the given zones, the first one in alphabetical
+// order on ties, so that successive machines are placed round-robin.
+func leastUsedFailureDomain(failureDomains clusterv1.FailureDomains, zones []string) string {
+	counts := make(map[string]int, len(failureDomains))
+	for _, zone := range zones {
+		counts[zone]++
+	}
+
+	ids := make([]string, 0, len(failureDomains))
+	for id := range failureDomains {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var selected string
+	for _, id := range ids {
+		if selected == "" || counts[id] < counts[selected] {
+			selected = id
+		}
+	}
+	return selected
+}
+
 func (amr *AzureMachineReconciler) reconcileDelete(ctx context.Context, machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachineReconciler.reconcileDelete")
 	defer done()
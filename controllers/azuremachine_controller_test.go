@@ -20,7 +20,7 @@ import (
 	"context"
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -164,9 +164,6 @@ func TestConditions(t *testing.T) {
 			reconciler := NewAzureMachineReconciler(client, recorder, reconciler.DefaultLoopTimeout, "")
 
 			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
-				AzureClients: scope.AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Client:       client,
 				Cluster:      cluster,
 				AzureCluster: azureCluster,
@@ -199,3 +196,183 @@ func conditionsMatch(i, j clusterv1.Condition) bool {
 		i.Reason == j.Reason &&
 		i.Severity == j.Severity
 }
+
+func TestLeastUsedFailureDomain(t *testing.T) {
+	failureDomains := clusterv1.FailureDomains{
+		"1": clusterv1.FailureDomainSpec{ControlPlane: true},
+		"2": clusterv1.FailureDomainSpec{ControlPlane: true},
+		"3": clusterv1.FailureDomainSpec{ControlPlane: true},
+	}
+
+	testcases := []struct {
+		name  string
+		zones []string
+		want  string
+	}{
+		{
+			name: "first failure domain without machines",
+			want: "1",
+		},
+		{
+			name:  "round-robin on ties",
+			zones: []string{"1"},
+			want:  "2",
+		},
+		{
+			name:  "failure domain with the fewest machines",
+			zones: []string{"1", "1", "2", "3", "3"},
+			want:  "2",
+		},
+		{
+			name:  "zones out of the failure domains are ignored",
+			zones: []string{"1", "2", "3", "4"},
+			want:  "1",
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(leastUsedFailureDomain(failureDomains, tc.zones)).To(Equal(tc.want))
+		})
+	}
+}
+
+func TestControlPlaneZones(t *testing.T) {
+	g := NewWithT(t)
+	now := metav1.Now()
+	machine := func(name, failureDomain string) clusterv1.Machine {
+		m := clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: clusterv1.MachineSpec{
+				InfrastructureRef: corev1.ObjectReference{Name: "azure-" + name},
+			},
+		}
+		if failureDomain != "" {
+			m.Spec.FailureDomain = &failureDomain
+		}
+		return m
+	}
+	deleting := machine("deleting", "3")
+	deleting.DeletionTimestamp = &now
+	machines := []clusterv1.Machine{
+		machine("self", ""),
+		machine("explicit", "1"),
+		machine("placed", ""),
+		machine("selected", ""),
+		machine("regional", ""),
+		deleting,
+	}
+	azureMachines := []infrav1.AzureMachine{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "azure-placed"},
+			Status:     infrav1.AzureMachineStatus{Placement: &infrav1.VMPlacement{AvailabilityZone: "2"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "azure-selected"},
+			Spec:       infrav1.AzureMachineSpec{FailureDomain: to.Ptr("3")},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "azure-regional"},
+		},
+	}
+
+	g.Expect(controlPlaneZones("self", machines, azureMachines)).To(Equal([]string{"1", "2", "3"}))
+}
+
+func TestReconcileControlPlaneZone(t *testing.T) {
+	g := NewWithT(t)
+	scheme := setupScheme(g)
+
+	controlPlaneLabels := map[string]string{
+		clusterv1.ClusterLabelName:             "my-cluster",
+		clusterv1.MachineControlPlaneLabelName: "",
+	}
+	existingMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default", Labels: controlPlaneLabels},
+		Spec: clusterv1.MachineSpec{
+			ClusterName:   "my-cluster",
+			FailureDomain: to.Ptr("1"),
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		spreading     infrav1.ControlPlaneZoneSpreadingPolicy
+		role          map[string]string
+		failureDomain *string
+		want          *string
+	}{
+		{
+			name:      "control plane machine is placed in the least used failure domain",
+			spreading: infrav1.ControlPlaneZoneSpreadingAutomatic,
+			role:      controlPlaneLabels,
+			want:      to.Ptr("2"),
+		},
+		{
+			name:          "explicit failure domain is kept",
+			spreading:     infrav1.ControlPlaneZoneSpreadingAutomatic,
+			role:          controlPlaneLabels,
+			failureDomain: to.Ptr("1"),
+			want:          to.Ptr("1"),
+		},
+		{
+			name:      "control plane machine is not placed without automatic spreading",
+			spreading: infrav1.ControlPlaneZoneSpreadingExplicit,
+			role:      controlPlaneLabels,
+		},
+		{
+			name:      "worker machine is not placed",
+			spreading: infrav1.ControlPlaneZoneSpreadingAutomatic,
+			role:      map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"}}
+			azureCluster := &infrav1.AzureCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+				Spec: infrav1.AzureClusterSpec{
+					AzureClusterClassSpec:     infrav1.AzureClusterClassSpec{SubscriptionID: "123"},
+					ControlPlaneZoneSpreading: tc.spreading,
+				},
+				Status: infrav1.AzureClusterStatus{
+					FailureDomains: clusterv1.FailureDomains{
+						"1": clusterv1.FailureDomainSpec{ControlPlane: true},
+						"2": clusterv1.FailureDomainSpec{ControlPlane: true},
+					},
+				},
+			}
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default", Labels: tc.role},
+				Spec:       clusterv1.MachineSpec{ClusterName: "my-cluster"},
+			}
+			azureMachine := &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default", Labels: tc.role},
+				Spec:       infrav1.AzureMachineSpec{FailureDomain: tc.failureDomain},
+			}
+			client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(cluster, azureCluster, existingMachine, machine, azureMachine).Build()
+
+			clusterScope, err := scope.NewClusterScope(context.TODO(), scope.ClusterScopeParams{
+				Client:       client,
+				Cluster:      cluster,
+				AzureCluster: azureCluster,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+				Client:       client,
+				ClusterScope: clusterScope,
+				Machine:      machine,
+				AzureMachine: azureMachine,
+				Cache:        &scope.MachineCache{},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			reconciler := NewAzureMachineReconciler(client, record.NewFakeRecorder(10), reconciler.DefaultLoopTimeout, "")
+			g.Expect(reconciler.reconcileControlPlaneZone(context.TODO(), machineScope, clusterScope)).To(Succeed())
+			g.Expect(machineScope.AzureMachine.Spec.FailureDomain).To(Equal(tc.want))
+		})
+	}
+}
@@ -21,15 +21,25 @@ import (
 
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/availabilitysets"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/backendaddresses"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/backendpooldrain"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/backupprotection"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bootstrapdata"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/datacollectionruleassociations"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inboundnatrules"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/jitnetworkaccesspolicies"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/permissions"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/publicips"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/snapshots"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tombstones"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -50,19 +60,98 @@ func newAzureMachineService(machineScope *scope.MachineScope) (*azureMachineServ
 	if err != nil {
 		return nil, errors.Wrap(err, "failed creating a NewCache")
 	}
+	tagsSvc, err := tags.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating tags service")
+	}
+	availabilitySetsSvc, err := availabilitysets.New(machineScope, cache)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating availability sets service")
+	}
+	snapshotsSvc, err := snapshots.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating snapshots service")
+	}
+	disksSvc, err := disks.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating disks service")
+	}
+	bootstrapDataSvc, err := bootstrapdata.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating bootstrap data service")
+	}
+	virtualMachinesSvc, err := virtualmachines.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating virtual machines service")
+	}
+	roleAssignmentsSvc, err := roleassignments.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating role assignments service")
+	}
+	vmExtensionsSvc, err := vmextensions.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating VM extensions service")
+	}
+	tombstonesSvc, err := tombstones.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating tombstones service")
+	}
+	publicIPsSvc, err := publicips.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating public IPs service")
+	}
+	inboundNATRulesSvc, err := inboundnatrules.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating inbound NAT rules service")
+	}
+	networkInterfacesSvc, err := networkinterfaces.New(machineScope, cache)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating network interfaces service")
+	}
+	backendPoolDrainSvc, err := backendpooldrain.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating backend pool drain service")
+	}
+	backendAddressesSvc, err := backendaddresses.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating backend addresses service")
+	}
+	dataCollectionRuleAssociationsSvc, err := datacollectionruleassociations.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating data collection rule associations service")
+	}
+	backupProtectionSvc, err := backupprotection.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating backup protection service")
+	}
+	jitNetworkAccessPoliciesSvc, err := jitnetworkaccesspolicies.New(machineScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating just-in-time network access policies service")
+	}
 
 	return &azureMachineService{
 		scope: machineScope,
 		services: []azure.ServiceReconciler{
-			publicips.New(machineScope),
-			inboundnatrules.New(machineScope),
-			networkinterfaces.New(machineScope, cache),
-			availabilitysets.New(machineScope, cache),
-			disks.New(machineScope),
-			virtualmachines.New(machineScope),
-			roleassignments.New(machineScope),
-			vmextensions.New(machineScope),
-			tags.New(machineScope),
+			permissions.New(machineScope),
+			publicIPsSvc,
+			inboundNATRulesSvc,
+			networkInterfacesSvc,
+			availabilitySetsSvc,
+			disksSvc,
+			bootstrapDataSvc,
+			virtualMachinesSvc,
+			backendPoolDrainSvc,
+			backendAddressesSvc,
+			roleAssignmentsSvc,
+			vmExtensionsSvc,
+			dataCollectionRuleAssociationsSvc,
+			tagsSvc,
+			snapshotsSvc,
+			backupProtectionSvc,
+			jitNetworkAccessPoliciesSvc,
+			// tombstones is last so that, as services are deleted in reverse order, the virtual machine is tombstoned
+			// before any of its resources is deleted.
+			tombstonesSvc,
 		},
 		skuCache: cache,
 	}, nil
@@ -86,6 +175,27 @@ func (s *azureMachineService) Reconcile(ctx context.Context) error {
 	return nil
 }
 
+// ExportARMTemplate exports the desired state of the resources of the services as an ARM template.
+func (s *azureMachineService) ExportARMTemplate(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureMachineService.ExportARMTemplate")
+	defer done()
+
+	if err := s.scope.SetSubnetName(); err != nil {
+		return errors.Wrap(err, "failed defaulting subnet name")
+	}
+
+	template := armtemplate.New(s.scope.ResourceGroup())
+	for _, service := range s.services {
+		if exporter, ok := service.(azure.ResourceExporter); ok {
+			if err := exporter.ExportResources(ctx, template); err != nil {
+				return errors.Wrapf(err, "failed to export the resources of AzureMachine service %s", service.Name())
+			}
+		}
+	}
+
+	return s.scope.SaveARMTemplate(ctx, template)
+}
+
 // Delete deletes all the services in a predetermined order.
 func (s *azureMachineService) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureMachineService.Delete")
@@ -0,0 +1,187 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/permissions"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-crosssubscriptionaccess,mutating=false,failurePolicy=ignore,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachines;azuremachinepools,versions=v1beta1,name=crosssubscriptionaccess.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// CrossSubscriptionAccessValidator rejects the AzureMachines and AzureMachinePools using Azure Compute Galleries or
+// disk encryption sets in another subscription than their cluster which the cluster identity isn't allowed to use,
+// unless the cross-subscription access policy of the cluster is Assign. It also rejects the machines using network
+// interfaces or existing disks in another subscription than their cluster, which Azure doesn't allow.
+type CrossSubscriptionAccessValidator struct {
+	Client  client.Client
+	decoder *admission.Decoder
+
+	// newPermissionsClient returns the permissions client of the identity of a cluster, and the subscription of the
+	// cluster.
+	newPermissionsClient func(context.Context, client.Client, *clusterv1.Cluster, *infrav1.AzureCluster) (permissions.Client, string, error)
+}
+
+// NewCrossSubscriptionAccessWebhook creates a new Webhook checking the access of the cluster identity to the
+// resources of the machines in other subscriptions.
+func NewCrossSubscriptionAccessWebhook(c client.Client) *admission.Webhook {
+	return &admission.Webhook{
+		Handler: &CrossSubscriptionAccessValidator{
+			Client:               c,
+			newPermissionsClient: newClusterPermissionsClient,
+		},
+	}
+}
+
+var _ admission.DecoderInjector = &CrossSubscriptionAccessValidator{}
+
+// InjectDecoder injects the decoder into a CrossSubscriptionAccessValidator.
+func (v *CrossSubscriptionAccessValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle handles admission requests.
+func (v *CrossSubscriptionAccessValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	var (
+		namespace, clusterName string
+		image                  *infrav1.Image
+		osDisk                 infrav1.OSDisk
+		dataDisks              []infrav1.DataDisk
+		networkInterfaces      []infrav1.AzureNetworkInterface
+	)
+	switch req.Kind.Kind {
+	case "AzureMachine":
+		m := &infrav1.AzureMachine{}
+		if err := v.decoder.Decode(req, m); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		namespace, clusterName = m.Namespace, m.Labels[clusterv1.ClusterLabelName]
+		image, osDisk, dataDisks = m.Spec.Image, m.Spec.OSDisk, m.Spec.DataDisks
+		networkInterfaces = m.Spec.NetworkInterfaces
+	case "AzureMachinePool":
+		amp := &infrav1exp.AzureMachinePool{}
+		if err := v.decoder.Decode(req, amp); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		namespace, clusterName = amp.Namespace, amp.Labels[clusterv1.ClusterLabelName]
+		image, osDisk, dataDisks = amp.Spec.Template.Image, amp.Spec.Template.OSDisk, amp.Spec.Template.DataDisks
+		networkInterfaces = amp.Spec.Template.NetworkInterfaces
+	default:
+		return admission.Allowed("")
+	}
+
+	// Without any gallery image, disk encryption set, existing network interface or existing disk, there is nothing
+	// to check and no need for Azure credentials.
+	hasCrossSubscriptionResources := len(azure.CrossSubscriptionResources("", image, osDisk, dataDisks)) > 0
+	if clusterName == "" || (!hasCrossSubscriptionResources && len(azure.SubscriptionBoundResources("", networkInterfaces, dataDisks)) == 0) {
+		return admission.Allowed("")
+	}
+
+	cluster, azureCluster, err := v.clusters(ctx, namespace, clusterName)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if azureCluster == nil {
+		return admission.Allowed("")
+	}
+
+	// The network interfaces and the disks of a VM can't be in another subscription whatever the access policy.
+	if azureCluster.Spec.SubscriptionID != "" {
+		if ids := azure.SubscriptionBoundResources(azureCluster.Spec.SubscriptionID, networkInterfaces, dataDisks); len(ids) > 0 {
+			return admission.Denied(strings.Join(ids, ", ") + " must be in the subscription " +
+				azureCluster.Spec.SubscriptionID + " of the cluster")
+		}
+	}
+
+	if !hasCrossSubscriptionResources || azureCluster.Spec.CrossSubscriptionAccess == infrav1.CrossSubscriptionAccessAssign {
+		return admission.Allowed("")
+	}
+
+	permissionsClient, subscriptionID, err := v.newPermissionsClient(ctx, v.Client, cluster, azureCluster)
+	if err != nil {
+		return admission.Allowed("").WithWarnings("failed to check the access of the cluster identity to the resources in other subscriptions: " + err.Error())
+	}
+	resources := azure.CrossSubscriptionResources(subscriptionID, image, osDisk, dataDisks)
+	if len(resources) == 0 {
+		return admission.Allowed("")
+	}
+	missing, err := permissions.MissingPermissions(ctx, permissionsClient, resources)
+	if err != nil {
+		return admission.Allowed("").WithWarnings("failed to check the access of the cluster identity to the resources in other subscriptions: " + err.Error())
+	}
+	if len(missing) > 0 {
+		return admission.Denied(strings.Join(missing, ", ") +
+			`; grant the permissions, or set the crossSubscriptionAccess of the AzureCluster to "Assign"`)
+	}
+	return admission.Allowed("")
+}
+
+// clusters returns the Cluster and the AzureCluster of a machine, or a nil AzureCluster if the cluster isn't an
+// AzureCluster or doesn't exist yet.
+func (v *CrossSubscriptionAccessValidator) clusters(ctx context.Context, namespace, clusterName string) (*clusterv1.Cluster, *infrav1.AzureCluster, error) {
+	cluster := &clusterv1.Cluster{}
+	if err := v.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	ref := cluster.Spec.InfrastructureRef
+	if ref == nil || ref.Kind != "AzureCluster" {
+		return cluster, nil, nil
+	}
+	azureCluster := &infrav1.AzureCluster{}
+	if err := v.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, azureCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return cluster, nil, nil
+		}
+		return nil, nil, err
+	}
+	return cluster, azureCluster, nil
+}
+
+// newClusterPermissionsClient returns the permissions client of the identity of a cluster, and the subscription of
+// the cluster.
+func newClusterPermissionsClient(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, azureCluster *infrav1.AzureCluster) (permissions.Client, string, error) {
+	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+		Client:       c,
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return permissions.NewClient(clusterScope), clusterScope.SubscriptionID(), nil
+}
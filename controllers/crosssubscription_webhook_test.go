@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/permissions"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestCrossSubscriptionAccessValidatorSubscriptionBoundResources(t *testing.T) {
+	const (
+		localNIC    = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-nic"
+		foreignNIC  = "/subscriptions/456/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-nic"
+		foreignDisk = "/subscriptions/456/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-disk"
+	)
+	g := NewWithT(t)
+	scheme := setupScheme(g)
+	g.Expect(infrav1exp.AddToScheme(scheme)).To(Succeed())
+
+	newCluster := func(name string) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{Kind: "AzureCluster", Name: name},
+			},
+		}
+	}
+	pinned := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "pinned", Namespace: "default"},
+		Spec:       infrav1.AzureClusterSpec{AzureClusterClassSpec: infrav1.AzureClusterClassSpec{SubscriptionID: "123"}},
+	}
+	unpinned := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "unpinned", Namespace: "default"},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithRuntimeObjects(newCluster("pinned"), pinned, newCluster("unpinned"), unpinned).Build()
+	decoder, err := admission.NewDecoder(scheme)
+	g.Expect(err).NotTo(HaveOccurred())
+	validator := &CrossSubscriptionAccessValidator{
+		Client:  fakeClient,
+		decoder: decoder,
+		newPermissionsClient: func(context.Context, client.Client, *clusterv1.Cluster, *infrav1.AzureCluster) (permissions.Client, string, error) {
+			return nil, "", errors.New("unexpected call")
+		},
+	}
+
+	newAzureMachine := func(clusterName string, nics ...infrav1.AzureNetworkInterface) *infrav1.AzureMachine {
+		return &infrav1.AzureMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-machine",
+				Namespace: "default",
+				Labels:    map[string]string{clusterv1.ClusterLabelName: clusterName},
+			},
+			Spec: infrav1.AzureMachineSpec{NetworkInterfaces: nics},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		obj     runtime.Object
+		kind    string
+		allowed bool
+	}{
+		{
+			name:    "AzureMachine with a network interface in the cluster subscription",
+			obj:     newAzureMachine("pinned", infrav1.AzureNetworkInterface{ID: localNIC}),
+			kind:    "AzureMachine",
+			allowed: true,
+		},
+		{
+			name:    "AzureMachine with a network interface in another subscription",
+			obj:     newAzureMachine("pinned", infrav1.AzureNetworkInterface{ID: foreignNIC}),
+			kind:    "AzureMachine",
+			allowed: false,
+		},
+		{
+			name:    "AzureMachine of a cluster without subscription",
+			obj:     newAzureMachine("unpinned", infrav1.AzureNetworkInterface{ID: foreignNIC}),
+			kind:    "AzureMachine",
+			allowed: true,
+		},
+		{
+			name: "AzureMachinePool with an existing disk in another subscription",
+			obj: &infrav1exp.AzureMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-machine-pool",
+					Namespace: "default",
+					Labels:    map[string]string{clusterv1.ClusterLabelName: "pinned"},
+				},
+				Spec: infrav1exp.AzureMachinePoolSpec{
+					Template: infrav1exp.AzureMachinePoolMachineTemplate{
+						DataDisks: []infrav1.DataDisk{{NameSuffix: "data", ExistingDiskID: foreignDisk}},
+					},
+				},
+			},
+			kind:    "AzureMachinePool",
+			allowed: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			raw, err := json.Marshal(tc.obj)
+			g.Expect(err).NotTo(HaveOccurred())
+			resp := validator.Handle(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Kind:      metav1.GroupVersionKind{Group: infrav1.GroupVersion.Group, Version: "v1beta1", Kind: tc.kind},
+					Object:    runtime.RawExtension{Raw: raw},
+				},
+			})
+			g.Expect(resp.Allowed).To(Equal(tc.allowed))
+		})
+	}
+}
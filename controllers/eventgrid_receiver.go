@@ -0,0 +1,321 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/eventsubscriptions"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+const (
+	// subscriptionValidationEventType is the type of the event Event Grid sends to validate a webhook endpoint.
+	subscriptionValidationEventType = "Microsoft.EventGrid.SubscriptionValidationEvent"
+
+	// eventGridWebhookTokenParam is the query parameter carrying the shared secret of the Event Grid webhook.
+	eventGridWebhookTokenParam = "token"
+
+	// minEventGridTokenLength is the minimum length of the shared secret of the Event Grid webhook.
+	minEventGridTokenLength = 32
+
+	// maxEventGridRequestSize bounds the size of the event batches accepted by the receiver.
+	maxEventGridRequestSize = 1 << 20
+
+	eventGridEventBufferSize = 100
+)
+
+// eventGridEvent is an event delivered using the Event Grid schema.
+type eventGridEvent struct {
+	ID        string          `json:"id"`
+	Subject   string          `json:"subject"`
+	EventType string          `json:"eventType"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// EventGridReceiver receives the Event Grid resource notifications of cluster resource groups and
+// triggers reconciles of the AzureClusters and AzureMachines affected by out-of-band changes.
+type EventGridReceiver struct {
+	Client      client.Client
+	Log         logr.Logger
+	BindAddress string
+
+	// ClusterEvents and MachineEvents are consumed by the AzureCluster and AzureMachine controllers.
+	ClusterEvents chan event.GenericEvent
+	MachineEvents chan event.GenericEvent
+
+	token           string
+	subscriptionURL string
+	certWatcher     *certwatcher.CertWatcher
+}
+
+// NewEventGridReceiver returns a receiver serving HTTPS on bindAddress for the events Event Grid delivers to
+// webhookURL. Only the requests carrying the shared secret read from tokenFile, e.g. a mounted Secret, are accepted.
+// The serving certificate is read from the tls.crt and tls.key files of certDir, and reloaded when they change.
+func NewEventGridReceiver(c client.Client, log logr.Logger, bindAddress, webhookURL, tokenFile, certDir string) (*EventGridReceiver, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse Event Grid webhook URL")
+	}
+	if u.Scheme != "https" {
+		return nil, errors.Errorf("Event Grid webhook URL %q must use https", u.Redacted())
+	}
+	query := u.Query()
+	if query.Has(eventGridWebhookTokenParam) {
+		return nil, errors.Errorf("Event Grid webhook URL %q must not have a %s query parameter, the token is read from the token file",
+			u.Redacted(), eventGridWebhookTokenParam)
+	}
+
+	token, err := readEventGridToken(tokenFile)
+	if err != nil {
+		return nil, err
+	}
+	query.Set(eventGridWebhookTokenParam, token)
+	u.RawQuery = query.Encode()
+
+	certWatcher, err := certwatcher.New(filepath.Join(certDir, "tls.crt"), filepath.Join(certDir, "tls.key"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load Event Grid receiver serving certificate")
+	}
+
+	return &EventGridReceiver{
+		Client:          c,
+		Log:             log,
+		BindAddress:     bindAddress,
+		ClusterEvents:   make(chan event.GenericEvent, eventGridEventBufferSize),
+		MachineEvents:   make(chan event.GenericEvent, eventGridEventBufferSize),
+		token:           token,
+		subscriptionURL: u.String(),
+		certWatcher:     certWatcher,
+	}, nil
+}
+
+// readEventGridToken reads the shared secret of the Event Grid webhook from a file.
+func readEventGridToken(tokenFile string) (string, error) {
+	if tokenFile == "" {
+		return "", errors.New("an Event Grid webhook token file is required")
+	}
+	data, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read Event Grid webhook token file")
+	}
+	token := strings.TrimSpace(string(data))
+	if len(token) < minEventGridTokenLength {
+		return "", errors.Errorf("Event Grid webhook token must be at least %d characters long", minEventGridTokenLength)
+	}
+	return token, nil
+}
+
+// SubscriptionURL returns the URL event subscriptions deliver to, i.e. the webhook URL with the token of the receiver.
+func (r *EventGridReceiver) SubscriptionURL() string {
+	return r.subscriptionURL
+}
+
+// Start serves the receiver until the context is cancelled.
+func (r *EventGridReceiver) Start(ctx context.Context) error {
+	go func() {
+		if err := r.certWatcher.Start(ctx); err != nil {
+			r.Log.Error(err, "failed to watch Event Grid receiver serving certificate")
+		}
+	}()
+
+	srv := &http.Server{
+		Addr:              r.BindAddress,
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig: &tls.Config{
+			GetCertificate: r.certWatcher.GetCertificate,
+			MinVersion:     tls.VersionTLS12,
+		},
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			r.Log.Error(err, "failed to shut down Event Grid receiver")
+		}
+	}()
+
+	r.Log.Info("starting Event Grid receiver", "address", r.BindAddress)
+	if err := srv.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, "failed to serve Event Grid receiver")
+	}
+	return nil
+}
+
+// NeedLeaderElection ensures events are only received by the manager running the controllers.
+func (r *EventGridReceiver) NeedLeaderElection() bool {
+	return true
+}
+
+// ServeHTTP handles a batch of Event Grid events.
+func (r *EventGridReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(req.URL.Query().Get(eventGridWebhookTokenParam)), []byte(r.token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxEventGridRequestSize))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	var events []eventGridEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		http.Error(w, "failed to decode events", http.StatusBadRequest)
+		return
+	}
+
+	for _, e := range events {
+		switch e.EventType {
+		case subscriptionValidationEventType:
+			r.validateSubscription(w, e)
+			return
+		case eventsubscriptions.ResourceDeleteSuccessEventType:
+			if err := r.enqueueAffected(req.Context(), e.Subject); err != nil {
+				r.Log.Error(err, "failed to handle Event Grid event", "id", e.ID, "subject", e.Subject)
+				// Event Grid retries the delivery of the whole batch.
+				http.Error(w, "failed to handle event", http.StatusServiceUnavailable)
+				return
+			}
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// validateSubscription completes the validation handshake of a new event subscription by echoing its validation code.
+func (r *EventGridReceiver) validateSubscription(w http.ResponseWriter, e eventGridEvent) {
+	var data struct {
+		ValidationCode string `json:"validationCode"`
+	}
+	if err := json.Unmarshal(e.Data, &data); err != nil || data.ValidationCode == "" {
+		http.Error(w, "invalid subscription validation event", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{"validationResponse": data.ValidationCode}); err != nil {
+		r.Log.Error(err, "failed to write subscription validation response")
+	}
+}
+
+// enqueueAffected triggers reconciles of the AzureClusters owning the resource group of the resource
+// and of the AzureMachines whose name is part of the resource name.
+func (r *EventGridReceiver) enqueueAffected(ctx context.Context, resourceID string) error {
+	subscriptionID, resourceGroup, resourceName, ok := parseResourceID(resourceID)
+	if !ok {
+		return nil
+	}
+
+	clusters := &infrav1.AzureClusterList{}
+	if err := r.Client.List(ctx, clusters); err != nil {
+		return errors.Wrap(err, "failed to list AzureClusters")
+	}
+
+	for i := range clusters.Items {
+		azureCluster := &clusters.Items[i]
+		if !strings.EqualFold(azureCluster.Spec.ResourceGroup, resourceGroup) {
+			continue
+		}
+		if azureCluster.Spec.SubscriptionID != "" && !strings.EqualFold(azureCluster.Spec.SubscriptionID, subscriptionID) {
+			continue
+		}
+
+		r.Log.V(4).Info("resource deleted out of band", tele.LogKeyResourceID, resourceID, "namespace", azureCluster.Namespace, "azureCluster", azureCluster.Name)
+		if err := send(ctx, r.ClusterEvents, azureCluster); err != nil {
+			return err
+		}
+
+		clusterName, ok := GetOwnerClusterName(azureCluster.ObjectMeta)
+		if !ok || resourceName == "" {
+			continue
+		}
+		machines := &infrav1.AzureMachineList{}
+		if err := r.Client.List(ctx, machines, client.InNamespace(azureCluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: clusterName}); err != nil {
+			return errors.Wrap(err, "failed to list AzureMachines")
+		}
+		for j := range machines.Items {
+			if !ownsResource(&machines.Items[j], resourceName) {
+				continue
+			}
+			if err := send(ctx, r.MachineEvents, &machines.Items[j]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ownsResource returns true if a resource is the VM of an AzureMachine or one of the resources created along with it.
+// Names are matched exactly, as the name of a machine is a prefix of the names of others, e.g. foo-1 and foo-10.
+func ownsResource(azureMachine *infrav1.AzureMachine, resourceName string) bool {
+	for _, name := range scope.AzureMachineResourceNames(azureMachine) {
+		if strings.EqualFold(name, resourceName) {
+			return true
+		}
+	}
+	return false
+}
+
+// send queues a generic event for obj, giving up when the context is done.
+func send(ctx context.Context, events chan<- event.GenericEvent, obj client.Object) error {
+	select {
+	case events <- event.GenericEvent{Object: obj}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseResourceID returns the subscription, resource group and name of an Azure resource ID.
+// The name is empty when the ID refers to the resource group itself.
+func parseResourceID(id string) (subscriptionID, resourceGroup, name string, ok bool) {
+	resourceID, err := azure.ParseResourceID(id)
+	if err != nil || resourceID.SubscriptionID == "" || resourceID.ResourceGroupName == "" {
+		return "", "", "", false
+	}
+	if !azure.IsResourceType(resourceID, arm.ResourceGroupResourceType.String()) {
+		name = resourceID.Name
+	}
+	return resourceID.SubscriptionID, resourceID.ResourceGroupName, name, true
+}
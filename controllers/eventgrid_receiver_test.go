@@ -0,0 +1,334 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	certutil "k8s.io/client-go/util/cert"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// eventGridTestToken is the shared secret of the Event Grid webhook in the tests.
+const eventGridTestToken = "0123456789abcdef0123456789abcdef"
+
+func TestNewEventGridReceiver(t *testing.T) {
+	tokenFile, certDir := writeEventGridReceiverFiles(t, eventGridTestToken)
+	shortTokenFile, _ := writeEventGridReceiverFiles(t, "secret")
+
+	tests := []struct {
+		name                    string
+		webhookURL              string
+		tokenFile               string
+		certDir                 string
+		expectedSubscriptionURL string
+		expectedErr             string
+	}{
+		{
+			name:                    "valid configuration",
+			webhookURL:              "https://capz.example.com/eventgrid",
+			tokenFile:               tokenFile,
+			certDir:                 certDir,
+			expectedSubscriptionURL: "https://capz.example.com/eventgrid?token=" + eventGridTestToken,
+		},
+		{
+			name:        "http webhook URL",
+			webhookURL:  "http://capz.example.com/eventgrid",
+			tokenFile:   tokenFile,
+			certDir:     certDir,
+			expectedErr: "must use https",
+		},
+		{
+			name:        "token in the webhook URL",
+			webhookURL:  "https://capz.example.com/eventgrid?token=secret",
+			tokenFile:   tokenFile,
+			certDir:     certDir,
+			expectedErr: "must not have a token query parameter",
+		},
+		{
+			name:        "no token file",
+			webhookURL:  "https://capz.example.com/eventgrid",
+			certDir:     certDir,
+			expectedErr: "an Event Grid webhook token file is required",
+		},
+		{
+			name:        "missing token file",
+			webhookURL:  "https://capz.example.com/eventgrid",
+			tokenFile:   filepath.Join(t.TempDir(), "token"),
+			certDir:     certDir,
+			expectedErr: "failed to read Event Grid webhook token file",
+		},
+		{
+			name:        "short token",
+			webhookURL:  "https://capz.example.com/eventgrid",
+			tokenFile:   shortTokenFile,
+			certDir:     certDir,
+			expectedErr: "must be at least 32 characters long",
+		},
+		{
+			name:        "missing serving certificate",
+			webhookURL:  "https://capz.example.com/eventgrid",
+			tokenFile:   tokenFile,
+			certDir:     t.TempDir(),
+			expectedErr: "failed to load Event Grid receiver serving certificate",
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r, err := NewEventGridReceiver(nil, logr.Discard(), ":9445", tc.webhookURL, tc.tokenFile, tc.certDir)
+			if tc.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(r.token).To(Equal(eventGridTestToken))
+			g.Expect(r.SubscriptionURL()).To(Equal(tc.expectedSubscriptionURL))
+		})
+	}
+}
+
+// writeEventGridReceiverFiles writes a token file and a self-signed serving certificate for an Event Grid receiver.
+func writeEventGridReceiverFiles(t *testing.T, token string) (tokenFile, certDir string) {
+	t.Helper()
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	tokenFile = filepath.Join(dir, "token")
+	g.Expect(os.WriteFile(tokenFile, []byte(token+"\n"), 0o600)).To(Succeed())
+
+	certDir = filepath.Join(dir, "certs")
+	g.Expect(os.Mkdir(certDir, 0o700)).To(Succeed())
+	crt, key, err := certutil.GenerateSelfSignedCertKey("capz.example.com", nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(os.WriteFile(filepath.Join(certDir, "tls.crt"), crt, 0o600)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(certDir, "tls.key"), key, 0o600)).To(Succeed())
+	return tokenFile, certDir
+}
+
+func TestEventGridReceiver_ServeHTTP(t *testing.T) {
+	const nicID = "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/networkInterfaces/my-machine-nic"
+
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-azure-cluster",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "my-cluster"},
+			},
+		},
+		Spec: infrav1.AzureClusterSpec{
+			ResourceGroup: "My-RG",
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				SubscriptionID: "123",
+			},
+		},
+	}
+	otherCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+		Spec:       infrav1.AzureClusterSpec{ResourceGroup: "other-rg"},
+	}
+	machine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-machine",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+		},
+	}
+	otherMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "another-machine",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+		},
+	}
+
+	prefixedMachine := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-machine-1",
+			Namespace: "default",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "my-cluster"},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		query            string
+		body             string
+		expectedStatus   int
+		expectedBody     string
+		expectedClusters []string
+		expectedMachines []string
+	}{
+		{
+			name:           "rejects requests without the token",
+			body:           `[]`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "rejects requests with another token",
+			query:          "?token=0123456789abcdef0123456789abcdeF",
+			body:           `[]`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "rejects malformed events",
+			query:          "?token=" + eventGridTestToken,
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "answers the subscription validation handshake",
+			query:          "?token=" + eventGridTestToken,
+			body:           `[{"id":"1","eventType":"Microsoft.EventGrid.SubscriptionValidationEvent","data":{"validationCode":"abc"}}]`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"validationResponse":"abc"}`,
+		},
+		{
+			name:             "enqueues the cluster and machine of a deleted NIC",
+			query:            "?token=" + eventGridTestToken,
+			body:             `[{"id":"1","eventType":"Microsoft.Resources.ResourceDeleteSuccess","subject":"` + nicID + `"}]`,
+			expectedStatus:   http.StatusOK,
+			expectedClusters: []string{"my-azure-cluster"},
+			expectedMachines: []string{"my-machine"},
+		},
+		{
+			name:             "enqueues only the machine whose name matches the deleted VM exactly",
+			query:            "?token=" + eventGridTestToken,
+			body:             `[{"id":"1","eventType":"Microsoft.Resources.ResourceDeleteSuccess","subject":"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-machine-1"}]`,
+			expectedStatus:   http.StatusOK,
+			expectedClusters: []string{"my-azure-cluster"},
+			expectedMachines: []string{"my-machine-1"},
+		},
+		{
+			name:             "ignores resources of machines that don't exist",
+			query:            "?token=" + eventGridTestToken,
+			body:             `[{"id":"1","eventType":"Microsoft.Resources.ResourceDeleteSuccess","subject":"/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/disks/my-machine-10_OSDisk"}]`,
+			expectedStatus:   http.StatusOK,
+			expectedClusters: []string{"my-azure-cluster"},
+		},
+		{
+			name:           "ignores other event types",
+			query:          "?token=" + eventGridTestToken,
+			body:           `[{"id":"1","eventType":"Microsoft.Resources.ResourceWriteSuccess","subject":"` + nicID + `"}]`,
+			expectedStatus: http.StatusOK,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			c := fake.NewClientBuilder().WithScheme(setupScheme(g)).WithObjects(azureCluster, otherCluster, machine, otherMachine, prefixedMachine).Build()
+			tokenFile, certDir := writeEventGridReceiverFiles(t, eventGridTestToken)
+			r, err := NewEventGridReceiver(c, logr.Discard(), ":9445", "https://capz.example.com/eventgrid", tokenFile, certDir)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			req := httptest.NewRequest(http.MethodPost, "/eventgrid"+tc.query, strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			g.Expect(rec.Code).To(Equal(tc.expectedStatus))
+			if tc.expectedBody != "" {
+				g.Expect(rec.Body.String()).To(MatchJSON(tc.expectedBody))
+			}
+			close(r.ClusterEvents)
+			close(r.MachineEvents)
+			g.Expect(eventNames(r.ClusterEvents)).To(ConsistOf(tc.expectedClusters))
+			g.Expect(eventNames(r.MachineEvents)).To(ConsistOf(tc.expectedMachines))
+		})
+	}
+}
+
+func TestEventGridReceiver_Start(t *testing.T) {
+	g := NewWithT(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	address := listener.Addr().String()
+	g.Expect(listener.Close()).To(Succeed())
+
+	tokenFile, certDir := writeEventGridReceiverFiles(t, eventGridTestToken)
+	r, err := NewEventGridReceiver(nil, logr.Discard(), address, "https://capz.example.com/eventgrid", tokenFile, certDir)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- r.Start(ctx)
+	}()
+	defer func() {
+		cancel()
+		g.Expect(<-done).To(Succeed())
+	}()
+
+	//nolint:gosec // the test receiver serves a self-signed certificate.
+	httpsClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	g.Eventually(func() (int, error) {
+		resp, err := httpsClient.Post("https://"+address+"/eventgrid?token="+eventGridTestToken, "application/json", strings.NewReader(`[]`))
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode, nil
+	}, 10*time.Second, 100*time.Millisecond).Should(Equal(http.StatusOK))
+
+	resp, err := http.Post("http://"+address+"/eventgrid?token="+eventGridTestToken, "application/json", strings.NewReader(`[]`))
+	g.Expect(err).NotTo(HaveOccurred())
+	defer resp.Body.Close()
+	g.Expect(resp.StatusCode).To(Equal(http.StatusBadRequest), "plain HTTP requests must be refused")
+}
+
+func eventNames(events <-chan event.GenericEvent) []string {
+	names := []string{}
+	for e := range events {
+		names = append(names, client.ObjectKeyFromObject(e.Object).Name)
+	}
+	return names
+}
+
+func TestParseResourceID(t *testing.T) {
+	g := NewWithT(t)
+
+	sub, rg, name, ok := parseResourceID("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/my-vm")
+	g.Expect(ok).To(BeTrue())
+	g.Expect([]string{sub, rg, name}).To(Equal([]string{"123", "my-rg", "my-vm"}))
+
+	_, rg, name, ok = parseResourceID("/subscriptions/123/resourceGroups/my-rg")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(rg).To(Equal("my-rg"))
+	g.Expect(name).To(BeEmpty())
+
+	_, _, _, ok = parseResourceID("/subscriptions/123")
+	g.Expect(ok).To(BeFalse())
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
@@ -46,7 +47,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
 const (
@@ -189,6 +192,11 @@ func GetCloudProviderSecret(d azure.ClusterScoper, namespace, name string, owner
 			return nil, errors.New("expected a non-empty userIdentityID")
 		}
 		controlPlaneConfig, workerNodeConfig = userAssignedIdentityCloudProviderConfig(d, userIdentityID)
+	case infrav1.CloudProviderWorkloadIdentity:
+		if len(userIdentityID) < 1 {
+			return nil, errors.New("expected a non-empty client ID for workload identity")
+		}
+		controlPlaneConfig, workerNodeConfig = workloadIdentityCloudProviderConfig(d, userIdentityID)
 	case infrav1.VMIdentityNone:
 		controlPlaneConfig, workerNodeConfig = newCloudProviderConfig(d)
 	}
@@ -236,6 +244,21 @@ func userAssignedIdentityCloudProviderConfig(d azure.ClusterScoper, identityID s
 	return controlPlaneConfig, workerConfig
 }
 
+// workloadIdentityCloudProviderConfig returns the cloud provider configs authenticating with the service account token
+// projected by the Azure AD workload identity webhook, exchanged for a token of the given client ID.
+func workloadIdentityCloudProviderConfig(d azure.ClusterScoper, clientID string) (*CloudProviderConfig, *CloudProviderConfig) {
+	controlPlaneConfig, workerConfig := newCloudProviderConfig(d)
+	controlPlaneConfig.AadClientID = clientID
+	controlPlaneConfig.AadClientSecret = ""
+	controlPlaneConfig.UseFederatedWorkloadIdentityExtension = true
+	controlPlaneConfig.AADFederatedTokenFile = azure.DefaultFederatedTokenFile
+	workerConfig.AadClientID = clientID
+	workerConfig.AadClientSecret = ""
+	workerConfig.UseFederatedWorkloadIdentityExtension = true
+	workerConfig.AADFederatedTokenFile = azure.DefaultFederatedTokenFile
+	return controlPlaneConfig, workerConfig
+}
+
 func newCloudProviderConfig(d azure.ClusterScoper) (controlPlaneConfig *CloudProviderConfig, workerConfig *CloudProviderConfig) {
 	subnet := getOneNodeSubnet(d)
 	return (&CloudProviderConfig{
@@ -352,25 +375,27 @@ func toCloudProviderRateLimitConfig(source infrav1.RateLimitConfig) *RateLimitCo
 
 // CloudProviderConfig is an abbreviated version of the same struct in k/k.
 type CloudProviderConfig struct {
-	Cloud                        string `json:"cloud"`
-	TenantID                     string `json:"tenantId"`
-	SubscriptionID               string `json:"subscriptionId"`
-	AadClientID                  string `json:"aadClientId,omitempty"`
-	AadClientSecret              string `json:"aadClientSecret,omitempty"`
-	ResourceGroup                string `json:"resourceGroup"`
-	SecurityGroupName            string `json:"securityGroupName"`
-	SecurityGroupResourceGroup   string `json:"securityGroupResourceGroup"`
-	Location                     string `json:"location"`
-	VMType                       string `json:"vmType"`
-	VnetName                     string `json:"vnetName"`
-	VnetResourceGroup            string `json:"vnetResourceGroup"`
-	SubnetName                   string `json:"subnetName"`
-	RouteTableName               string `json:"routeTableName"`
-	LoadBalancerSku              string `json:"loadBalancerSku"`
-	MaximumLoadBalancerRuleCount int    `json:"maximumLoadBalancerRuleCount"`
-	UseManagedIdentityExtension  bool   `json:"useManagedIdentityExtension"`
-	UseInstanceMetadata          bool   `json:"useInstanceMetadata"`
-	UserAssignedIdentityID       string `json:"userAssignedIdentityID,omitempty"`
+	Cloud                                 string `json:"cloud"`
+	TenantID                              string `json:"tenantId"`
+	SubscriptionID                        string `json:"subscriptionId"`
+	AadClientID                           string `json:"aadClientId,omitempty"`
+	AadClientSecret                       string `json:"aadClientSecret,omitempty"`
+	AADFederatedTokenFile                 string `json:"aadFederatedTokenFile,omitempty"`
+	ResourceGroup                         string `json:"resourceGroup"`
+	SecurityGroupName                     string `json:"securityGroupName"`
+	SecurityGroupResourceGroup            string `json:"securityGroupResourceGroup"`
+	Location                              string `json:"location"`
+	VMType                                string `json:"vmType"`
+	VnetName                              string `json:"vnetName"`
+	VnetResourceGroup                     string `json:"vnetResourceGroup"`
+	SubnetName                            string `json:"subnetName"`
+	RouteTableName                        string `json:"routeTableName"`
+	LoadBalancerSku                       string `json:"loadBalancerSku"`
+	MaximumLoadBalancerRuleCount          int    `json:"maximumLoadBalancerRuleCount"`
+	UseManagedIdentityExtension           bool   `json:"useManagedIdentityExtension"`
+	UseFederatedWorkloadIdentityExtension bool   `json:"useFederatedWorkloadIdentityExtension,omitempty"`
+	UseInstanceMetadata                   bool   `json:"useInstanceMetadata"`
+	UserAssignedIdentityID                string `json:"userAssignedIdentityID,omitempty"`
 	CloudProviderRateLimitConfig
 	BackOffConfig
 }
@@ -574,13 +599,36 @@ func ShouldDeleteIndividualResources(ctx context.Context, clusterScope *scope.Cl
 	if clusterScope.Cluster.DeletionTimestamp.IsZero() {
 		return true
 	}
-	grpSvc := groups.New(clusterScope)
+	grpSvc, err := groups.New(clusterScope)
+	if err != nil {
+		return true
+	}
 	managed, err := grpSvc.IsManaged(ctx)
 	// Since this is a best effort attempt to speed up delete, we don't fail the delete if we can't get the RG status.
 	// Instead, take the long way and delete all resources one by one.
 	return err != nil || !managed
 }
 
+// HibernatedControlPlaneMachine returns the name of a control plane AzureMachine of a cluster that was deallocated by
+// the hibernation of the cluster and has not resumed yet, if any. Worker VMs are only started once it returns no name,
+// so that their kubelets register with a running API server.
+func HibernatedControlPlaneMachine(ctx context.Context, c client.Client, namespace, clusterName string) (string, error) {
+	controlPlaneMachines := &infrav1.AzureMachineList{}
+	if err := c.List(ctx, controlPlaneMachines,
+		client.InNamespace(namespace),
+		client.MatchingLabels{clusterv1.ClusterLabelName: clusterName},
+		client.HasLabels{clusterv1.MachineControlPlaneLabelName},
+	); err != nil {
+		return "", errors.Wrap(err, "failed to list control plane AzureMachines")
+	}
+	for _, controlPlaneMachine := range controlPlaneMachines.Items {
+		if _, ok := controlPlaneMachine.Annotations[infrav1.HibernatedAnnotation]; ok {
+			return controlPlaneMachine.Name, nil
+		}
+	}
+	return "", nil
+}
+
 // GetClusterIdentityFromRef returns the AzureClusterIdentity referenced by the AzureCluster.
 func GetClusterIdentityFromRef(ctx context.Context, c client.Client, azureClusterNamespace string, ref *corev1.ObjectReference) (*infrav1.AzureClusterIdentity, error) {
 	identity := &infrav1.AzureClusterIdentity{}
@@ -597,3 +645,136 @@ func GetClusterIdentityFromRef(ctx context.Context, c client.Client, azureCluste
 	}
 	return nil, nil
 }
+
+// ClientSecretToAzureClustersMapper creates a mapping handler to transform a Secret into the AzureClusters whose
+// AzureClusterIdentity references it, so that their credentials are rebuilt as soon as the client secret or
+// certificate of the identity is rotated.
+func ClientSecretToAzureClustersMapper(ctx context.Context, c client.Client, log logr.Logger) handler.MapFunc {
+	return func(o client.Object) []ctrl.Request {
+		ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultMappingTimeout)
+		defer cancel()
+
+		identities, err := ClusterIdentitiesUsingSecret(ctx, c, o)
+		if err != nil {
+			log.Error(err, "failed to list the AzureClusterIdentities using the Secret", "Secret", o.GetName(), "Namespace", o.GetNamespace())
+			return nil
+		}
+		if len(identities) == 0 {
+			return nil
+		}
+
+		azClusterList := &infrav1.AzureClusterList{}
+		if err := c.List(ctx, azClusterList); err != nil {
+			log.Error(err, "failed to list AzureClusters")
+			return nil
+		}
+
+		var results []ctrl.Request
+		for _, azCluster := range azClusterList.Items {
+			if _, ok := identities[IdentityRefKey(azCluster.Namespace, azCluster.Spec.IdentityRef)]; ok {
+				results = append(results, ctrl.Request{
+					NamespacedName: client.ObjectKey{Namespace: azCluster.Namespace, Name: azCluster.Name},
+				})
+			}
+		}
+		return results
+	}
+}
+
+// AzureClusterIdentityToAzureClustersMapper creates a mapping handler to transform an AzureClusterIdentity into the
+// AzureClusters referencing it.
+func AzureClusterIdentityToAzureClustersMapper(ctx context.Context, c client.Client, log logr.Logger) handler.MapFunc {
+	return func(o client.Object) []ctrl.Request {
+		ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultMappingTimeout)
+		defer cancel()
+
+		identity := client.ObjectKey{Namespace: o.GetNamespace(), Name: o.GetName()}
+
+		azClusterList := &infrav1.AzureClusterList{}
+		if err := c.List(ctx, azClusterList); err != nil {
+			log.Error(err, "failed to list AzureClusters")
+			return nil
+		}
+
+		var results []ctrl.Request
+		for _, azCluster := range azClusterList.Items {
+			if IdentityRefKey(azCluster.Namespace, azCluster.Spec.IdentityRef) == identity {
+				results = append(results, ctrl.Request{
+					NamespacedName: client.ObjectKey{Namespace: azCluster.Namespace, Name: azCluster.Name},
+				})
+			}
+		}
+		return results
+	}
+}
+
+// ClusterIdentitiesUsingSecret returns the keys of the AzureClusterIdentities referencing a Secret as their client
+// secret or certificate.
+func ClusterIdentitiesUsingSecret(ctx context.Context, c client.Client, secret client.Object) (map[client.ObjectKey]struct{}, error) {
+	identityList := &infrav1.AzureClusterIdentityList{}
+	if err := c.List(ctx, identityList); err != nil {
+		return nil, err
+	}
+
+	identities := make(map[client.ObjectKey]struct{})
+	for _, identity := range identityList.Items {
+		ref := identity.Spec.ClientSecret
+		if ref.Name == secret.GetName() && ref.Namespace == secret.GetNamespace() {
+			identities[client.ObjectKey{Namespace: identity.Namespace, Name: identity.Name}] = struct{}{}
+		}
+	}
+	return identities, nil
+}
+
+// IdentityRefKey returns the key of the AzureClusterIdentity referenced by a cluster in a namespace, or an empty key if
+// the cluster doesn't reference any identity.
+func IdentityRefKey(clusterNamespace string, ref *corev1.ObjectReference) client.ObjectKey {
+	if ref == nil {
+		return client.ObjectKey{}
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = clusterNamespace
+	}
+	return client.ObjectKey{Namespace: namespace, Name: ref.Name}
+}
+
+// durationAnnotation returns the duration set by an annotation of an object, if it is set to a valid positive duration.
+func durationAnnotation(obj metav1.Object, annotation string) (time.Duration, bool) {
+	value, ok := obj.GetAnnotations()[annotation]
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// clusterReconcileTimeout returns the timeout of a reconciliation of an AzureCluster, which can be overridden by its
+// reconcile timeout annotation.
+func clusterReconcileTimeout(azureCluster *infrav1.AzureCluster, timeout time.Duration) time.Duration {
+	if d, ok := durationAnnotation(azureCluster, infrav1.ReconcileTimeoutAnnotation); ok {
+		return d
+	}
+	return reconciler.DefaultedLoopTimeout(timeout)
+}
+
+// ResyncWithoutSyncPeriodOverride filters out the periodic resync events of objects overriding their sync period with the
+// sync period annotation, as those are requeued after their own sync period instead.
+func ResyncWithoutSyncPeriodOverride(logger logr.Logger) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld.GetResourceVersion() != e.ObjectNew.GetResourceVersion() {
+				return true
+			}
+			if _, ok := durationAnnotation(e.ObjectNew, infrav1.SyncPeriodAnnotation); ok {
+				logger.V(6).Info("skipping resync of object overriding its sync period", "namespace", e.ObjectNew.GetNamespace(),
+					"name", e.ObjectNew.GetName())
+				return false
+			}
+			return true
+		},
+	}
+}
@@ -21,9 +21,8 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
@@ -37,8 +36,11 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/internal/test/mock_log"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func TestAzureClusterToAzureMachinesMapper(t *testing.T) {
@@ -79,6 +81,82 @@ func TestAzureClusterToAzureMachinesMapper(t *testing.T) {
 	g.Expect(requests).To(HaveLen(2))
 }
 
+func TestClientSecretToAzureClustersMapper(t *testing.T) {
+	g := NewWithT(t)
+	scheme := setupScheme(g)
+	newIdentity := func(namespace, name, secretNamespace, secretName string) *infrav1.AzureClusterIdentity {
+		return &infrav1.AzureClusterIdentity{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: infrav1.AzureClusterIdentitySpec{
+				ClientSecret: corev1.SecretReference{Name: secretName, Namespace: secretNamespace},
+			},
+		}
+	}
+	newAzureCluster := func(namespace, name string, ref *corev1.ObjectReference) *infrav1.AzureCluster {
+		return &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: infrav1.AzureClusterSpec{
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{IdentityRef: ref},
+			},
+		}
+	}
+	initObjects := []runtime.Object{
+		newIdentity("default", "rotated", "capz-system", "rotated-secret"),
+		newIdentity("default", "other", "capz-system", "other-secret"),
+		// The identity ref defaults to the namespace of the AzureCluster.
+		newAzureCluster("default", "implicit-namespace", &corev1.ObjectReference{Name: "rotated"}),
+		newAzureCluster("other", "explicit-namespace", &corev1.ObjectReference{Name: "rotated", Namespace: "default"}),
+		newAzureCluster("other", "wrong-namespace", &corev1.ObjectReference{Name: "rotated"}),
+		newAzureCluster("default", "other-identity", &corev1.ObjectReference{Name: "other"}),
+		newAzureCluster("default", "no-identity", nil),
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+	mapper := ClientSecretToAzureClustersMapper(context.Background(), client, logr.Discard())
+
+	requests := mapper(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "rotated-secret", Namespace: "capz-system"}})
+	g.Expect(requests).To(ConsistOf(
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "implicit-namespace"}},
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "other", Name: "explicit-namespace"}},
+	))
+
+	requests = mapper(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "rotated-secret", Namespace: "default"}})
+	g.Expect(requests).To(BeEmpty())
+}
+
+func TestAzureClusterIdentityToAzureClustersMapper(t *testing.T) {
+	g := NewWithT(t)
+	scheme := setupScheme(g)
+	newAzureCluster := func(namespace, name string, ref *corev1.ObjectReference) *infrav1.AzureCluster {
+		return &infrav1.AzureCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: infrav1.AzureClusterSpec{
+				AzureClusterClassSpec: infrav1.AzureClusterClassSpec{IdentityRef: ref},
+			},
+		}
+	}
+	initObjects := []runtime.Object{
+		// The identity ref defaults to the namespace of the AzureCluster.
+		newAzureCluster("default", "implicit-namespace", &corev1.ObjectReference{Name: "changed"}),
+		newAzureCluster("other", "explicit-namespace", &corev1.ObjectReference{Name: "changed", Namespace: "default"}),
+		newAzureCluster("other", "wrong-namespace", &corev1.ObjectReference{Name: "changed"}),
+		newAzureCluster("default", "other-identity", &corev1.ObjectReference{Name: "other"}),
+		newAzureCluster("default", "no-identity", nil),
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+	mapper := AzureClusterIdentityToAzureClustersMapper(context.Background(), client, logr.Discard())
+
+	requests := mapper(&infrav1.AzureClusterIdentity{ObjectMeta: metav1.ObjectMeta{Name: "changed", Namespace: "default"}})
+	g.Expect(requests).To(ConsistOf(
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "implicit-namespace"}},
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "other", Name: "explicit-namespace"}},
+	))
+
+	requests = mapper(&infrav1.AzureClusterIdentity{ObjectMeta: metav1.ObjectMeta{Name: "unused", Namespace: "default"}})
+	g.Expect(requests).To(BeEmpty())
+}
+
 func TestGetCloudProviderConfig(t *testing.T) {
 	g := NewWithT(t)
 	scheme := runtime.NewScheme()
@@ -145,9 +223,9 @@ func TestGetCloudProviderConfig(t *testing.T) {
 		},
 	}
 
-	os.Setenv(auth.ClientID, "fooClient")
-	os.Setenv(auth.ClientSecret, "fooSecret")
-	os.Setenv(auth.TenantID, "fooTenant")
+	os.Setenv("AZURE_CLIENT_ID", "fooClient")
+	os.Setenv("AZURE_CLIENT_SECRET", "fooSecret")
+	os.Setenv("AZURE_TENANT_ID", "fooTenant")
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -155,9 +233,6 @@ func TestGetCloudProviderConfig(t *testing.T) {
 			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
 
 			clusterScope, err := scope.NewClusterScope(context.Background(), scope.ClusterScopeParams{
-				AzureClients: scope.AzureClients{
-					Authorizer: autorest.NullAuthorizer{},
-				},
 				Cluster:      tc.cluster,
 				AzureCluster: tc.azureCluster,
 				Client:       fakeClient,
@@ -181,6 +256,90 @@ func TestGetCloudProviderConfig(t *testing.T) {
 	}
 }
 
+func TestClusterReconcileTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		timeout     time.Duration
+		expected    time.Duration
+	}{
+		{
+			name:     "defaulted timeout",
+			expected: reconciler.DefaultLoopTimeout,
+		},
+		{
+			name:     "controller timeout",
+			timeout:  30 * time.Minute,
+			expected: 30 * time.Minute,
+		},
+		{
+			name:        "annotation overrides the controller timeout",
+			annotations: map[string]string{infrav1.ReconcileTimeoutAnnotation: "3h"},
+			timeout:     30 * time.Minute,
+			expected:    3 * time.Hour,
+		},
+		{
+			name:        "invalid annotation is ignored",
+			annotations: map[string]string{infrav1.ReconcileTimeoutAnnotation: "three hours"},
+			timeout:     30 * time.Minute,
+			expected:    30 * time.Minute,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			azureCluster := &infrav1.AzureCluster{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			g.Expect(clusterReconcileTimeout(azureCluster, tc.timeout)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestResyncWithoutSyncPeriodOverride(t *testing.T) {
+	newCluster := func(resourceVersion string, annotations map[string]string) *infrav1.AzureCluster {
+		return &infrav1.AzureCluster{ObjectMeta: metav1.ObjectMeta{
+			Name:            "my-cluster",
+			ResourceVersion: resourceVersion,
+			Annotations:     annotations,
+		}}
+	}
+	syncPeriod := map[string]string{infrav1.SyncPeriodAnnotation: "1h"}
+
+	tests := []struct {
+		name     string
+		old      *infrav1.AzureCluster
+		new      *infrav1.AzureCluster
+		expected bool
+	}{
+		{
+			name:     "resync without sync period override",
+			old:      newCluster("1", nil),
+			new:      newCluster("1", nil),
+			expected: true,
+		},
+		{
+			name:     "resync with sync period override",
+			old:      newCluster("1", syncPeriod),
+			new:      newCluster("1", syncPeriod),
+			expected: false,
+		},
+		{
+			name:     "update with sync period override",
+			old:      newCluster("1", syncPeriod),
+			new:      newCluster("2", syncPeriod),
+			expected: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			p := ResyncWithoutSyncPeriodOverride(logr.Discard())
+			g.Expect(p.Update(event.UpdateEvent{ObjectOld: tc.old, ObjectNew: tc.new})).To(Equal(tc.expected))
+		})
+	}
+}
+
 func TestReconcileAzureSecret(t *testing.T) {
 	g := NewWithT(t)
 
@@ -265,9 +424,6 @@ func TestReconcileAzureSecret(t *testing.T) {
 	kubeclient := fake.NewClientBuilder().WithScheme(scheme).Build()
 
 	clusterScope, err := scope.NewClusterScope(context.Background(), scope.ClusterScopeParams{
-		AzureClients: scope.AzureClients{
-			Authorizer: autorest.NullAuthorizer{},
-		},
 		Cluster:      cluster,
 		AzureCluster: azureCluster,
 		Client:       kubeclient,
@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-identitynamespace,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azureclusters;azuremanagedcontrolplanes,versions=v1beta1,name=identitynamespace.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// IdentityNamespaceValidator rejects the AzureClusters and AzureManagedControlPlanes referencing an
+// AzureClusterIdentity which doesn't allow, or denies, their namespace.
+type IdentityNamespaceValidator struct {
+	Client  client.Client
+	decoder *admission.Decoder
+}
+
+// NewIdentityNamespaceWebhook creates a new Webhook checking the namespaces of the clusters are allowed by their
+// identity.
+func NewIdentityNamespaceWebhook(c client.Client) *admission.Webhook {
+	return &admission.Webhook{
+		Handler: &IdentityNamespaceValidator{
+			Client: c,
+		},
+	}
+}
+
+var _ admission.DecoderInjector = &IdentityNamespaceValidator{}
+
+// InjectDecoder injects the decoder into an IdentityNamespaceValidator.
+func (v *IdentityNamespaceValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle handles admission requests.
+func (v *IdentityNamespaceValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	var (
+		namespace string
+		ref       *corev1.ObjectReference
+	)
+	switch req.Kind.Kind {
+	case "AzureCluster":
+		c := &infrav1.AzureCluster{}
+		if err := v.decoder.Decode(req, c); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		namespace, ref = c.Namespace, c.Spec.IdentityRef
+	case "AzureManagedControlPlane":
+		cp := &infrav1exp.AzureManagedControlPlane{}
+		if err := v.decoder.Decode(req, cp); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		namespace, ref = cp.Namespace, cp.Spec.IdentityRef
+	default:
+		return admission.Allowed("")
+	}
+
+	if ref == nil {
+		return admission.Allowed("")
+	}
+
+	identity, err := GetClusterIdentityFromRef(ctx, v.Client, namespace, ref)
+	if err != nil {
+		// The identity may be created after the cluster, in which case the controllers check the namespace.
+		if apierrors.IsNotFound(err) {
+			return admission.Allowed("")
+		}
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if !scope.IsClusterNamespaceAllowedByIdentity(ctx, v.Client, identity, namespace) {
+		return admission.Denied(fmt.Sprintf("AzureClusterIdentity %s/%s doesn't allow namespace %s", identity.Namespace, identity.Name, namespace))
+	}
+	return admission.Allowed("")
+}
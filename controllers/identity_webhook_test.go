@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestIdentityNamespaceValidator(t *testing.T) {
+	g := NewWithT(t)
+	scheme := setupScheme(g)
+	g.Expect(infrav1exp.AddToScheme(scheme)).To(Succeed())
+
+	identity := &infrav1.AzureClusterIdentity{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared-identity", Namespace: "default"},
+		Spec: infrav1.AzureClusterIdentitySpec{
+			AllowedNamespaces: &infrav1.AllowedNamespaces{},
+			DeniedNamespaces: &infrav1.DeniedNamespaces{
+				NamespaceList: []string{"team-b"},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(identity).Build()
+	decoder, err := admission.NewDecoder(scheme)
+	g.Expect(err).NotTo(HaveOccurred())
+	validator := &IdentityNamespaceValidator{Client: fakeClient, decoder: decoder}
+
+	tests := []struct {
+		name    string
+		obj     runtime.Object
+		kind    string
+		allowed bool
+	}{
+		{
+			name:    "AzureCluster without identity",
+			obj:     &infrav1.AzureCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "team-b"}},
+			kind:    "AzureCluster",
+			allowed: true,
+		},
+		{
+			name:    "AzureCluster in an allowed namespace",
+			obj:     newAzureClusterWithIdentity("team-a", &corev1.ObjectReference{Name: "shared-identity", Namespace: "default"}),
+			kind:    "AzureCluster",
+			allowed: true,
+		},
+		{
+			name:    "AzureCluster in a denied namespace",
+			obj:     newAzureClusterWithIdentity("team-b", &corev1.ObjectReference{Name: "shared-identity", Namespace: "default"}),
+			kind:    "AzureCluster",
+			allowed: false,
+		},
+		{
+			name:    "AzureCluster with an identity which doesn't exist yet",
+			obj:     newAzureClusterWithIdentity("team-b", &corev1.ObjectReference{Name: "missing-identity", Namespace: "default"}),
+			kind:    "AzureCluster",
+			allowed: true,
+		},
+		{
+			name: "AzureManagedControlPlane in a denied namespace",
+			obj: &infrav1exp.AzureManagedControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-control-plane", Namespace: "team-b"},
+				Spec: infrav1exp.AzureManagedControlPlaneSpec{
+					IdentityRef: &corev1.ObjectReference{Name: "shared-identity", Namespace: "default"},
+				},
+			},
+			kind:    "AzureManagedControlPlane",
+			allowed: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			raw, err := json.Marshal(tc.obj)
+			g.Expect(err).NotTo(HaveOccurred())
+			resp := validator.Handle(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Kind:      metav1.GroupVersionKind{Group: infrav1.GroupVersion.Group, Version: "v1beta1", Kind: tc.kind},
+					Object:    runtime.RawExtension{Raw: raw},
+				},
+			})
+			g.Expect(resp.Allowed).To(Equal(tc.allowed))
+		})
+	}
+}
+
+func newAzureClusterWithIdentity(namespace string, ref *corev1.ObjectReference) *infrav1.AzureCluster {
+	return &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace},
+		Spec: infrav1.AzureClusterSpec{
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{IdentityRef: ref},
+		},
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// storageVersionMigrationPageSize is the number of objects listed at once during a migration.
+	storageVersionMigrationPageSize = 500
+
+	// storageVersionMigrationRetryInterval is how long to wait before retrying a failed migration, e.g. while the
+	// conversion webhook isn't serving yet.
+	storageVersionMigrationRetryInterval = 30 * time.Second
+)
+
+// storageVersionMigratedResources are the resources of the infrastructure CRDs installed by CAPZ, which are the only
+// ones the migrator is allowed to update.
+var storageVersionMigratedResources = map[string]bool{
+	"azureclusteridentities":    true,
+	"azureclusters":             true,
+	"azureclustertemplates":     true,
+	"azuremachinepoolmachines":  true,
+	"azuremachinepools":         true,
+	"azuremachines":             true,
+	"azuremachinetemplates":     true,
+	"azuremanagedclusters":      true,
+	"azuremanagedcontrolplanes": true,
+	"azuremanagedmachinepools":  true,
+}
+
+// StorageVersionMigrator migrates the objects of the infrastructure CRDs stored in an older API version than the
+// storage version of their CRD. Each object is rewritten, which makes the API server store it in the storage
+// version, then the older versions are removed from the storedVersions of the CRD so that they can be dropped from
+// the CRD in a later release without breaking existing clusters.
+type StorageVersionMigrator struct {
+	Client    client.Client
+	APIReader client.Reader
+	Log       logr.Logger
+}
+
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,verbs=patch;update
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azureclusteridentities;azureclusters;azureclustertemplates;azuremachinepoolmachines;azuremachinepools;azuremachines;azuremachinetemplates;azuremanagedclusters;azuremanagedcontrolplanes;azuremanagedmachinepools,verbs=get;list;update
+
+// Start migrates the infrastructure CRDs once, retrying until the migration succeeds or ctx is done.
+func (m *StorageVersionMigrator) Start(ctx context.Context) error {
+	err := wait.PollImmediateUntil(storageVersionMigrationRetryInterval, func() (bool, error) {
+		if err := m.Migrate(ctx); err != nil {
+			m.Log.Error(err, "failed to migrate storage versions, retrying", "interval", storageVersionMigrationRetryInterval)
+			return false, nil
+		}
+		return true, nil
+	}, ctx.Done())
+	if err != nil && ctx.Err() == nil {
+		return errors.Wrap(err, "failed to migrate storage versions")
+	}
+	return nil
+}
+
+// NeedLeaderElection ensures a single manager migrates the objects.
+func (m *StorageVersionMigrator) NeedLeaderElection() bool {
+	return true
+}
+
+// Migrate migrates the objects of each infrastructure CRD with stored versions other than its storage version.
+func (m *StorageVersionMigrator) Migrate(ctx context.Context) error {
+	crds := &apiextensionsv1.CustomResourceDefinitionList{}
+	if err := m.APIReader.List(ctx, crds); err != nil {
+		return errors.Wrap(err, "failed to list CustomResourceDefinitions")
+	}
+
+	var errs []error
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		if crd.Spec.Group != infrav1.GroupVersion.Group || !storageVersionMigratedResources[crd.Spec.Names.Plural] {
+			continue
+		}
+		if err := m.migrateCRD(ctx, crd); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to migrate %s", crd.Name))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// migrateCRD rewrites the objects of a CRD and sets its stored versions to its storage version.
+func (m *StorageVersionMigrator) migrateCRD(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) error {
+	storageVersion := storageVersion(crd)
+	if storageVersion == "" {
+		return errors.New("no storage version")
+	}
+	if len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == storageVersion {
+		return nil
+	}
+
+	log := m.Log.WithValues("crd", crd.Name, "storedVersions", crd.Status.StoredVersions, "storageVersion", storageVersion)
+	log.Info("migrating objects to the storage version")
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{Group: crd.Spec.Group, Version: storageVersion, Kind: crd.Spec.Names.ListKind})
+	count := 0
+	for {
+		if err := m.APIReader.List(ctx, list, client.Limit(storageVersionMigrationPageSize), client.Continue(list.GetContinue())); err != nil {
+			if apierrors.IsResourceExpired(err) && list.GetContinue() != "" {
+				// The continue token expired before the whole list was read, e.g. after a compaction on a busy
+				// cluster. Restart from the first page, updating the objects already migrated again is harmless.
+				log.Info("continue token expired, restarting the migration from the first page", "migrated", count)
+				list.SetContinue("")
+				count = 0
+				continue
+			}
+			return errors.Wrap(err, "failed to list objects")
+		}
+		for i := range list.Items {
+			// An update without changes is enough for the API server to store the object in the storage version.
+			// Objects updated or deleted concurrently don't need to be migrated anymore.
+			if err := m.Client.Update(ctx, &list.Items[i]); err != nil && !apierrors.IsConflict(err) && !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to migrate %s/%s", list.Items[i].GetNamespace(), list.Items[i].GetName())
+			}
+			count++
+		}
+		if list.GetContinue() == "" {
+			break
+		}
+	}
+
+	patch := client.MergeFrom(crd.DeepCopy())
+	crd.Status.StoredVersions = []string{storageVersion}
+	if err := m.Client.Status().Patch(ctx, crd, patch); err != nil {
+		return errors.Wrap(err, "failed to update stored versions")
+	}
+
+	log.Info("migrated objects to the storage version", "count", count)
+	return nil
+}
+
+// storageVersion returns the version a CRD stores its objects in.
+func storageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStorageVersionMigrator_Migrate(t *testing.T) {
+	g := NewWithT(t)
+
+	azureMachines := newStorageVersionTestCRD("azuremachines", infrav1.GroupVersion.Group, "AzureMachineList", "v1alpha4", "v1beta1")
+	azureClusters := newStorageVersionTestCRD("azureclusters", infrav1.GroupVersion.Group, "AzureClusterList", "v1beta1")
+	notInstalled := newStorageVersionTestCRD("azureserviceprincipals", infrav1.GroupVersion.Group, "AzureServicePrincipalList", "v1alpha4", "v1beta1")
+	otherGroup := newStorageVersionTestCRD("widgets", "example.com", "WidgetList", "v1alpha4", "v1beta1")
+	azureMachine := &infrav1.AzureMachine{ObjectMeta: metav1.ObjectMeta{Name: "my-machine", Namespace: "default"}}
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(azureMachines, azureClusters, notInstalled, otherGroup, azureMachine).Build()
+
+	before := &infrav1.AzureMachine{}
+	g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(azureMachine), before)).To(Succeed())
+
+	m := &StorageVersionMigrator{Client: c, APIReader: c, Log: logr.Discard()}
+	g.Expect(m.Migrate(context.TODO())).To(Succeed())
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(azureMachines), crd)).To(Succeed())
+	g.Expect(crd.Status.StoredVersions).To(Equal([]string{"v1beta1"}))
+	g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(notInstalled), crd)).To(Succeed())
+	g.Expect(crd.Status.StoredVersions).To(Equal([]string{"v1alpha4", "v1beta1"}))
+	g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(otherGroup), crd)).To(Succeed())
+	g.Expect(crd.Status.StoredVersions).To(Equal([]string{"v1alpha4", "v1beta1"}))
+
+	after := &infrav1.AzureMachine{}
+	g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(azureMachine), after)).To(Succeed())
+	g.Expect(after.ResourceVersion).NotTo(Equal(before.ResourceVersion))
+}
+
+func TestStorageVersionMigrator_MigrateRestartsOnExpiredContinueToken(t *testing.T) {
+	g := NewWithT(t)
+
+	azureMachines := newStorageVersionTestCRD("azuremachines", infrav1.GroupVersion.Group, "AzureMachineList", "v1alpha4", "v1beta1")
+	azureMachine := &infrav1.AzureMachine{ObjectMeta: metav1.ObjectMeta{Name: "my-machine", Namespace: "default"}}
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(azureMachines, azureMachine).Build()
+	reader := &expiringContinueReader{Reader: c}
+
+	m := &StorageVersionMigrator{Client: c, APIReader: reader, Log: logr.Discard()}
+	g.Expect(m.Migrate(context.TODO())).To(Succeed())
+	g.Expect(reader.expired).To(BeTrue())
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(azureMachines), crd)).To(Succeed())
+	g.Expect(crd.Status.StoredVersions).To(Equal([]string{"v1beta1"}))
+}
+
+// expiringContinueReader returns a continue token with the first page of objects, then fails with 410 Gone once when
+// that token is used.
+type expiringContinueReader struct {
+	client.Reader
+	listed  bool
+	expired bool
+}
+
+func (r *expiringContinueReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if _, ok := list.(*unstructured.UnstructuredList); !ok {
+		return r.Reader.List(ctx, list, opts...)
+	}
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	switch {
+	case listOpts.Continue != "":
+		r.expired = true
+		return apierrors.NewResourceExpired("continue token expired")
+	case !r.listed:
+		r.listed = true
+		if err := r.Reader.List(ctx, list, opts...); err != nil {
+			return err
+		}
+		list.(*unstructured.UnstructuredList).SetContinue("next-page")
+		return nil
+	default:
+		return r.Reader.List(ctx, list, opts...)
+	}
+}
+
+func newStorageVersionTestCRD(plural, group, listKind string, storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: plural + "." + group},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: plural, ListKind: listKind},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha4", Served: true},
+				{Name: "v1beta1", Served: true, Storage: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+	}
+}
@@ -47,6 +47,30 @@ func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.Template.SubnetName = restored.Spec.Template.SubnetName
+	dst.Spec.Template.OSDisk.Distro = restored.Spec.Template.OSDisk.Distro
+	dst.Spec.Template.UserData = restored.Spec.Template.UserData
+	dst.Spec.Template.AdditionalCapabilities = restored.Spec.Template.AdditionalCapabilities
+	dst.Spec.Template.AdditionalSSHPublicKeys = restored.Spec.Template.AdditionalSSHPublicKeys
+	dst.Spec.WarmPool = restored.Spec.WarmPool
+	dst.Spec.Prescale = restored.Spec.Prescale
+	dst.Status.Prescale = restored.Status.Prescale
+	dst.Spec.ScalingSchedule = restored.Spec.ScalingSchedule
+	dst.Status.ScalingSchedule = restored.Status.ScalingSchedule
+	dst.Spec.PlacementFallbacks = restored.Spec.PlacementFallbacks
+	dst.Status.Placement = restored.Status.Placement
+	dst.Spec.Template.MultiInstanceGPU = restored.Spec.Template.MultiInstanceGPU
+	dst.Spec.Template.LocalStorage = restored.Spec.Template.LocalStorage
+	dst.Spec.Template.FileStorage = restored.Spec.Template.FileStorage
+	dst.Spec.Template.CACertificates = restored.Spec.Template.CACertificates
+	dst.Spec.Template.OSDisk.WriteAcceleratorEnabled = restored.Spec.Template.OSDisk.WriteAcceleratorEnabled
+	dst.Spec.Template.OSDisk.SourceSnapshotID = restored.Spec.Template.OSDisk.SourceSnapshotID
+	if len(dst.Spec.Template.DataDisks) == len(restored.Spec.Template.DataDisks) {
+		for i := range dst.Spec.Template.DataDisks {
+			dst.Spec.Template.DataDisks[i].WriteAcceleratorEnabled = restored.Spec.Template.DataDisks[i].WriteAcceleratorEnabled
+			dst.Spec.Template.DataDisks[i].ExistingDiskID = restored.Spec.Template.DataDisks[i].ExistingDiskID
+			dst.Spec.Template.DataDisks[i].DeletePolicy = restored.Spec.Template.DataDisks[i].DeletePolicy
+		}
+	}
 
 	dst.Spec.Strategy.Type = restored.Spec.Strategy.Type
 	if restored.Spec.Strategy.RollingUpdate != nil {
@@ -56,6 +80,7 @@ func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error {
 		}
 
 		dst.Spec.Strategy.RollingUpdate.DeletePolicy = restored.Spec.Strategy.RollingUpdate.DeletePolicy
+		dst.Spec.Strategy.RollingUpdate.RolloutModelUpdates = restored.Spec.Strategy.RollingUpdate.RolloutModelUpdates
 	}
 
 	if restored.Spec.NodeDrainTimeout != nil {
@@ -65,6 +90,21 @@ func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error {
 	if restored.Status.Image != nil {
 		dst.Status.Image = restored.Status.Image
 	}
+	dst.Spec.Replicas = restored.Spec.Replicas
+	dst.Spec.Autoscale = restored.Spec.Autoscale
+	dst.Status.Selector = restored.Status.Selector
+	dst.Status.OutdatedReplicas = restored.Status.OutdatedReplicas
+	if len(dst.Status.Instances) == len(restored.Status.Instances) {
+		for i, instance := range dst.Status.Instances {
+			if instance == nil || restored.Status.Instances[i] == nil {
+				continue
+			}
+			instance.PowerState = restored.Status.Instances[i].PowerState
+			instance.FaultDomain = restored.Status.Instances[i].FaultDomain
+			instance.ImageVersion = restored.Status.Instances[i].ImageVersion
+			instance.ProtectedFromScaleIn = restored.Status.Instances[i].ProtectedFromScaleIn
+		}
+	}
 
 	if restored.Spec.Template.Image != nil && restored.Spec.Template.Image.SharedGallery != nil {
 		dst.Spec.Template.Image.SharedGallery.Offer = restored.Spec.Template.Image.SharedGallery.Offer
@@ -123,6 +163,18 @@ func Convert_v1beta1_AzureMachinePoolStatus_To_v1alpha3_AzureMachinePoolStatus(i
 			return err
 		}
 	}
+	if in.Instances != nil {
+		out.Instances = make([]*AzureMachinePoolInstanceStatus, len(in.Instances))
+		for i := range in.Instances {
+			if in.Instances[i] == nil {
+				continue
+			}
+			out.Instances[i] = &AzureMachinePoolInstanceStatus{}
+			if err := Convert_v1beta1_AzureMachinePoolInstanceStatus_To_v1alpha3_AzureMachinePoolInstanceStatus(in.Instances[i], out.Instances[i], s); err != nil {
+				return err
+			}
+		}
+	}
 	return autoConvert_v1beta1_AzureMachinePoolStatus_To_v1alpha3_AzureMachinePoolStatus(in, out, s)
 }
 
@@ -134,6 +186,18 @@ func Convert_v1alpha3_AzureMachinePoolStatus_To_v1beta1_AzureMachinePoolStatus(i
 		}
 		out.LongRunningOperationStates = []infrav1beta1.Future{f}
 	}
+	if in.Instances != nil {
+		out.Instances = make([]*expv1beta1.AzureMachinePoolInstanceStatus, len(in.Instances))
+		for i := range in.Instances {
+			if in.Instances[i] == nil {
+				continue
+			}
+			out.Instances[i] = &expv1beta1.AzureMachinePoolInstanceStatus{}
+			if err := Convert_v1alpha3_AzureMachinePoolInstanceStatus_To_v1beta1_AzureMachinePoolInstanceStatus(in.Instances[i], out.Instances[i], s); err != nil {
+				return err
+			}
+		}
+	}
 	return autoConvert_v1alpha3_AzureMachinePoolStatus_To_v1beta1_AzureMachinePoolStatus(in, out, s)
 }
 
@@ -148,3 +212,8 @@ func (dst *AzureMachinePoolList) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*expv1beta1.AzureMachinePoolList)
 	return Convert_v1beta1_AzureMachinePoolList_To_v1alpha3_AzureMachinePoolList(src, dst, nil)
 }
+
+// Convert_v1beta1_AzureMachinePoolInstanceStatus_To_v1alpha3_AzureMachinePoolInstanceStatus converts from the Hub version (v1beta1) of the AzureMachinePoolInstanceStatus to this version.
+func Convert_v1beta1_AzureMachinePoolInstanceStatus_To_v1alpha3_AzureMachinePoolInstanceStatus(in *expv1beta1.AzureMachinePoolInstanceStatus, out *AzureMachinePoolInstanceStatus, s convert.Scope) error {
+	return autoConvert_v1beta1_AzureMachinePoolInstanceStatus_To_v1alpha3_AzureMachinePoolInstanceStatus(in, out, s)
+}
@@ -124,6 +124,7 @@ type (
 
 		// Instances is the VM instance status for each VM in the VMSS
 		// +optional
+		// +k8s:conversion-gen=false
 		Instances []*AzureMachinePoolInstanceStatus `json:"instances,omitempty"`
 
 		// Version is the Kubernetes version for the current VMSS model
@@ -41,9 +41,24 @@ func (src *AzureManagedControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.LoadBalancerProfile = restored.Spec.LoadBalancerProfile
 	dst.Spec.APIServerAccessProfile = restored.Spec.APIServerAccessProfile
 	dst.Spec.AddonProfiles = restored.Spec.AddonProfiles
+	dst.Spec.Monitoring = restored.Spec.Monitoring
+	dst.Spec.SecurityProfile = restored.Spec.SecurityProfile
+	dst.Spec.AttachedACRs = restored.Spec.AttachedACRs
+	dst.Spec.Identity = restored.Spec.Identity
+	dst.Spec.KubeletUserAssignedIdentity = restored.Spec.KubeletUserAssignedIdentity
+	dst.Spec.FleetsMember = restored.Spec.FleetsMember
+	dst.Spec.NodeResourceGroupTags = restored.Spec.NodeResourceGroupTags
+	dst.Spec.HTTPProxyConfig = restored.Spec.HTTPProxyConfig
+	dst.Spec.OutboundType = restored.Spec.OutboundType
+	dst.Spec.NatGatewayProfile = restored.Spec.NatGatewayProfile
+	dst.Spec.DisableLocalAccounts = restored.Spec.DisableLocalAccounts
+	dst.Spec.UserKubeconfigCredentials = restored.Spec.UserKubeconfigCredentials
+	dst.Spec.WorkloadAutoScalerProfile = restored.Spec.WorkloadAutoScalerProfile
+	dst.Spec.ImageCleaner = restored.Spec.ImageCleaner
 
 	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
 	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.ManagedCluster = restored.Status.ManagedCluster
 
 	return nil
 }
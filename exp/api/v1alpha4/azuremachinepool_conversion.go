@@ -49,6 +49,50 @@ func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Status.Image.ComputeGallery = restored.Status.Image.ComputeGallery
 	}
 
+	dst.Spec.Template.OSDisk.Distro = restored.Spec.Template.OSDisk.Distro
+	dst.Spec.Template.UserData = restored.Spec.Template.UserData
+	dst.Spec.Template.AdditionalCapabilities = restored.Spec.Template.AdditionalCapabilities
+	dst.Spec.Template.AdditionalSSHPublicKeys = restored.Spec.Template.AdditionalSSHPublicKeys
+	dst.Spec.WarmPool = restored.Spec.WarmPool
+	dst.Spec.Prescale = restored.Spec.Prescale
+	dst.Status.Prescale = restored.Status.Prescale
+	dst.Spec.ScalingSchedule = restored.Spec.ScalingSchedule
+	dst.Status.ScalingSchedule = restored.Status.ScalingSchedule
+	dst.Spec.PlacementFallbacks = restored.Spec.PlacementFallbacks
+	dst.Status.Placement = restored.Status.Placement
+	dst.Spec.Template.MultiInstanceGPU = restored.Spec.Template.MultiInstanceGPU
+	dst.Spec.Template.LocalStorage = restored.Spec.Template.LocalStorage
+	dst.Spec.Template.FileStorage = restored.Spec.Template.FileStorage
+	dst.Spec.Template.CACertificates = restored.Spec.Template.CACertificates
+	dst.Spec.Template.OSDisk.WriteAcceleratorEnabled = restored.Spec.Template.OSDisk.WriteAcceleratorEnabled
+	dst.Spec.Template.OSDisk.SourceSnapshotID = restored.Spec.Template.OSDisk.SourceSnapshotID
+	if len(dst.Spec.Template.DataDisks) == len(restored.Spec.Template.DataDisks) {
+		for i := range dst.Spec.Template.DataDisks {
+			dst.Spec.Template.DataDisks[i].WriteAcceleratorEnabled = restored.Spec.Template.DataDisks[i].WriteAcceleratorEnabled
+			dst.Spec.Template.DataDisks[i].ExistingDiskID = restored.Spec.Template.DataDisks[i].ExistingDiskID
+			dst.Spec.Template.DataDisks[i].DeletePolicy = restored.Spec.Template.DataDisks[i].DeletePolicy
+		}
+	}
+
+	if restored.Spec.Strategy.RollingUpdate != nil && dst.Spec.Strategy.RollingUpdate != nil {
+		dst.Spec.Strategy.RollingUpdate.RolloutModelUpdates = restored.Spec.Strategy.RollingUpdate.RolloutModelUpdates
+	}
+	dst.Spec.Replicas = restored.Spec.Replicas
+	dst.Spec.Autoscale = restored.Spec.Autoscale
+	dst.Status.Selector = restored.Status.Selector
+	dst.Status.OutdatedReplicas = restored.Status.OutdatedReplicas
+	if len(dst.Status.Instances) == len(restored.Status.Instances) {
+		for i, instance := range dst.Status.Instances {
+			if instance == nil || restored.Status.Instances[i] == nil {
+				continue
+			}
+			instance.PowerState = restored.Status.Instances[i].PowerState
+			instance.FaultDomain = restored.Status.Instances[i].FaultDomain
+			instance.ImageVersion = restored.Status.Instances[i].ImageVersion
+			instance.ProtectedFromScaleIn = restored.Status.Instances[i].ProtectedFromScaleIn
+		}
+	}
+
 	return nil
 }
 
@@ -82,3 +126,52 @@ func (dst *AzureMachinePoolList) ConvertFrom(srcRaw conversion.Hub) error {
 func Convert_v1beta1_AzureMachinePoolMachineTemplate_To_v1alpha4_AzureMachinePoolMachineTemplate(in *expv1beta1.AzureMachinePoolMachineTemplate, out *AzureMachinePoolMachineTemplate, s apiMachineryConversion.Scope) error {
 	return autoConvert_v1beta1_AzureMachinePoolMachineTemplate_To_v1alpha4_AzureMachinePoolMachineTemplate(in, out, s)
 }
+
+// Convert_v1beta1_AzureMachinePoolSpec_To_v1alpha4_AzureMachinePoolSpec converts from the Hub version (v1beta1) of the AzureMachinePoolSpec to this version.
+func Convert_v1beta1_AzureMachinePoolSpec_To_v1alpha4_AzureMachinePoolSpec(in *expv1beta1.AzureMachinePoolSpec, out *AzureMachinePoolSpec, s apiMachineryConversion.Scope) error {
+	return autoConvert_v1beta1_AzureMachinePoolSpec_To_v1alpha4_AzureMachinePoolSpec(in, out, s)
+}
+
+// Convert_v1beta1_MachineRollingUpdateDeployment_To_v1alpha4_MachineRollingUpdateDeployment converts from the Hub version (v1beta1) of the MachineRollingUpdateDeployment to this version.
+func Convert_v1beta1_MachineRollingUpdateDeployment_To_v1alpha4_MachineRollingUpdateDeployment(in *expv1beta1.MachineRollingUpdateDeployment, out *MachineRollingUpdateDeployment, s apiMachineryConversion.Scope) error {
+	return autoConvert_v1beta1_MachineRollingUpdateDeployment_To_v1alpha4_MachineRollingUpdateDeployment(in, out, s)
+}
+
+// Convert_v1beta1_AzureMachinePoolStatus_To_v1alpha4_AzureMachinePoolStatus converts from the Hub version (v1beta1) of the AzureMachinePoolStatus to this version.
+func Convert_v1beta1_AzureMachinePoolStatus_To_v1alpha4_AzureMachinePoolStatus(in *expv1beta1.AzureMachinePoolStatus, out *AzureMachinePoolStatus, s apiMachineryConversion.Scope) error {
+	if in.Instances != nil {
+		out.Instances = make([]*AzureMachinePoolInstanceStatus, len(in.Instances))
+		for i := range in.Instances {
+			if in.Instances[i] == nil {
+				continue
+			}
+			out.Instances[i] = &AzureMachinePoolInstanceStatus{}
+			if err := Convert_v1beta1_AzureMachinePoolInstanceStatus_To_v1alpha4_AzureMachinePoolInstanceStatus(in.Instances[i], out.Instances[i], s); err != nil {
+				return err
+			}
+		}
+	}
+	return autoConvert_v1beta1_AzureMachinePoolStatus_To_v1alpha4_AzureMachinePoolStatus(in, out, s)
+}
+
+// Convert_v1alpha4_AzureMachinePoolStatus_To_v1beta1_AzureMachinePoolStatus converts this AzureMachinePoolStatus to the Hub version (v1beta1).
+func Convert_v1alpha4_AzureMachinePoolStatus_To_v1beta1_AzureMachinePoolStatus(in *AzureMachinePoolStatus, out *expv1beta1.AzureMachinePoolStatus, s apiMachineryConversion.Scope) error {
+	if in.Instances != nil {
+		out.Instances = make([]*expv1beta1.AzureMachinePoolInstanceStatus, len(in.Instances))
+		for i := range in.Instances {
+			if in.Instances[i] == nil {
+				continue
+			}
+			out.Instances[i] = &expv1beta1.AzureMachinePoolInstanceStatus{}
+			if err := Convert_v1alpha4_AzureMachinePoolInstanceStatus_To_v1beta1_AzureMachinePoolInstanceStatus(in.Instances[i], out.Instances[i], s); err != nil {
+				return err
+			}
+		}
+	}
+	return autoConvert_v1alpha4_AzureMachinePoolStatus_To_v1beta1_AzureMachinePoolStatus(in, out, s)
+}
+
+// Convert_v1beta1_AzureMachinePoolInstanceStatus_To_v1alpha4_AzureMachinePoolInstanceStatus converts from the Hub version (v1beta1) of the AzureMachinePoolInstanceStatus to this version.
+func Convert_v1beta1_AzureMachinePoolInstanceStatus_To_v1alpha4_AzureMachinePoolInstanceStatus(in *expv1beta1.AzureMachinePoolInstanceStatus, out *AzureMachinePoolInstanceStatus, s apiMachineryConversion.Scope) error {
+	return autoConvert_v1beta1_AzureMachinePoolInstanceStatus_To_v1alpha4_AzureMachinePoolInstanceStatus(in, out, s)
+}
@@ -224,6 +224,7 @@ type (
 
 		// Instances is the VM instance status for each VM in the VMSS
 		// +optional
+		// +k8s:conversion-gen=false
 		Instances []*AzureMachinePoolInstanceStatus `json:"instances,omitempty"`
 
 		// Image is the current image used in the AzureMachinePool. When the spec image is nil, this image is populated
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha4
 
 import (
+	apiMachineryConversion "k8s.io/apimachinery/pkg/conversion"
 	expv1beta1 "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
@@ -33,6 +34,9 @@ func (src *AzureMachinePoolMachine) ConvertTo(dstRaw conversion.Hub) error {
 		return err
 	}
 	dst.Spec = restored.Spec
+	dst.Status.ProtectedFromScaleIn = restored.Status.ProtectedFromScaleIn
+	dst.Status.InstanceView = restored.Status.InstanceView
+	dst.Status.Addresses = restored.Status.Addresses
 
 	return nil
 }
@@ -40,7 +44,12 @@ func (src *AzureMachinePoolMachine) ConvertTo(dstRaw conversion.Hub) error {
 // ConvertFrom converts from the Hub version (v1beta1) to this version.
 func (dst *AzureMachinePoolMachine) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*expv1beta1.AzureMachinePoolMachine)
-	return Convert_v1beta1_AzureMachinePoolMachine_To_v1alpha4_AzureMachinePoolMachine(src, dst, nil)
+	if err := Convert_v1beta1_AzureMachinePoolMachine_To_v1alpha4_AzureMachinePoolMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion.
+	return utilconversion.MarshalData(src, dst)
 }
 
 // ConvertTo converts this AzureMachinePoolMachineList to the Hub version (v1beta1).
@@ -54,3 +63,8 @@ func (dst *AzureMachinePoolMachineList) ConvertFrom(srcRaw conversion.Hub) error
 	src := srcRaw.(*expv1beta1.AzureMachinePoolMachineList)
 	return Convert_v1beta1_AzureMachinePoolMachineList_To_v1alpha4_AzureMachinePoolMachineList(src, dst, nil)
 }
+
+// Convert_v1beta1_AzureMachinePoolMachineStatus_To_v1alpha4_AzureMachinePoolMachineStatus converts from the Hub version (v1beta1) of the AzureMachinePoolMachineStatus to this version.
+func Convert_v1beta1_AzureMachinePoolMachineStatus_To_v1alpha4_AzureMachinePoolMachineStatus(in *expv1beta1.AzureMachinePoolMachineStatus, out *AzureMachinePoolMachineStatus, s apiMachineryConversion.Scope) error {
+	return autoConvert_v1beta1_AzureMachinePoolMachineStatus_To_v1alpha4_AzureMachinePoolMachineStatus(in, out, s)
+}
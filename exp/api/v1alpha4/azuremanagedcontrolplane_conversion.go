@@ -37,7 +37,29 @@ func (src *AzureManagedControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.AddonProfiles = restored.Spec.AddonProfiles
+	dst.Spec.Monitoring = restored.Spec.Monitoring
+	dst.Spec.SecurityProfile = restored.Spec.SecurityProfile
+	dst.Spec.AttachedACRs = restored.Spec.AttachedACRs
+	dst.Spec.Identity = restored.Spec.Identity
+	dst.Spec.KubeletUserAssignedIdentity = restored.Spec.KubeletUserAssignedIdentity
+	dst.Spec.FleetsMember = restored.Spec.FleetsMember
+	dst.Spec.NodeResourceGroupTags = restored.Spec.NodeResourceGroupTags
+	dst.Spec.HTTPProxyConfig = restored.Spec.HTTPProxyConfig
+	dst.Spec.OutboundType = restored.Spec.OutboundType
+	dst.Spec.NatGatewayProfile = restored.Spec.NatGatewayProfile
+	dst.Spec.DisableLocalAccounts = restored.Spec.DisableLocalAccounts
+	dst.Spec.UserKubeconfigCredentials = restored.Spec.UserKubeconfigCredentials
+	dst.Spec.WorkloadAutoScalerProfile = restored.Spec.WorkloadAutoScalerProfile
+	dst.Spec.ImageCleaner = restored.Spec.ImageCleaner
+	if restored.Spec.APIServerAccessProfile != nil {
+		if dst.Spec.APIServerAccessProfile == nil {
+			dst.Spec.APIServerAccessProfile = &expv1beta1.APIServerAccessProfile{}
+		}
+		dst.Spec.APIServerAccessProfile.EnableVnetIntegration = restored.Spec.APIServerAccessProfile.EnableVnetIntegration
+		dst.Spec.APIServerAccessProfile.Subnet = restored.Spec.APIServerAccessProfile.Subnet
+	}
 	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.ManagedCluster = restored.Status.ManagedCluster
 
 	return nil
 }
@@ -63,6 +85,11 @@ func Convert_v1beta1_AzureManagedControlPlaneStatus_To_v1alpha4_AzureManagedCont
 	return autoConvert_v1beta1_AzureManagedControlPlaneStatus_To_v1alpha4_AzureManagedControlPlaneStatus(in, out, s)
 }
 
+// Convert_v1beta1_APIServerAccessProfile_To_v1alpha4_APIServerAccessProfile is an autogenerated conversion function.
+func Convert_v1beta1_APIServerAccessProfile_To_v1alpha4_APIServerAccessProfile(in *expv1beta1.APIServerAccessProfile, out *APIServerAccessProfile, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_APIServerAccessProfile_To_v1alpha4_APIServerAccessProfile(in, out, s)
+}
+
 // ConvertTo converts this AzureManagedControlPlaneList to the Hub version (v1beta1).
 func (src *AzureManagedControlPlaneList) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*expv1beta1.AzureManagedControlPlaneList)
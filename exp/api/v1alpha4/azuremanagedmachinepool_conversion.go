@@ -48,6 +48,8 @@ func (src *AzureManagedMachinePool) ConvertTo(dstRaw conversion.Hub) error {
 
 	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
 	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.ReadyReplicas = restored.Status.ReadyReplicas
+	dst.Status.AgentPool = restored.Status.AgentPool
 
 	return nil
 }
@@ -24,26 +24,6 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
-func TestAzureMachinePool_SetDefaultSSHPublicKey(t *testing.T) {
-	g := NewWithT(t)
-
-	type test struct {
-		amp *AzureMachinePool
-	}
-
-	existingPublicKey := "testpublickey"
-	publicKeyExistTest := test{amp: createMachinePoolWithSSHPublicKey(existingPublicKey)}
-	publicKeyNotExistTest := test{amp: createMachinePoolWithSSHPublicKey("")}
-
-	err := publicKeyExistTest.amp.SetDefaultSSHPublicKey()
-	g.Expect(err).To(BeNil())
-	g.Expect(publicKeyExistTest.amp.Spec.Template.SSHPublicKey).To(Equal(existingPublicKey))
-
-	err = publicKeyNotExistTest.amp.SetDefaultSSHPublicKey()
-	g.Expect(err).To(BeNil())
-	g.Expect(publicKeyNotExistTest.amp.Spec.Template.SSHPublicKey).NotTo(BeEmpty())
-}
-
 func TestAzureMachinePool_SetIdentityDefaults(t *testing.T) {
 	g := NewWithT(t)
 
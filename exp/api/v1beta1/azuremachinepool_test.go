@@ -19,8 +19,9 @@ package v1beta1_test
 import (
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
@@ -88,7 +89,7 @@ func TestAzureMachinePool_Validate(t *testing.T) {
 				return &exp.AzureMachinePool{
 					Spec: exp.AzureMachinePoolSpec{
 						Template: exp.AzureMachinePoolMachineTemplate{
-							TerminateNotificationTimeout: to.IntPtr(7),
+							TerminateNotificationTimeout: to.Ptr(7),
 						},
 					},
 				}
@@ -103,7 +104,7 @@ func TestAzureMachinePool_Validate(t *testing.T) {
 				return &exp.AzureMachinePool{
 					Spec: exp.AzureMachinePoolSpec{
 						Template: exp.AzureMachinePoolMachineTemplate{
-							TerminateNotificationTimeout: to.IntPtr(20),
+							TerminateNotificationTimeout: to.Ptr(20),
 						},
 					},
 				}
@@ -119,7 +120,7 @@ func TestAzureMachinePool_Validate(t *testing.T) {
 				return &exp.AzureMachinePool{
 					Spec: exp.AzureMachinePoolSpec{
 						Template: exp.AzureMachinePoolMachineTemplate{
-							TerminateNotificationTimeout: to.IntPtr(3),
+							TerminateNotificationTimeout: to.Ptr(3),
 						},
 					},
 				}
@@ -129,6 +130,118 @@ func TestAzureMachinePool_Validate(t *testing.T) {
 				g.Expect(actual.Error()).To(gomega.ContainSubstring("minimum timeout 5 is allowed for TerminateNotificationTimeout"))
 			},
 		},
+		{
+			Name: "HasValidAutoscale",
+			Factory: func(_ *gomega.GomegaWithT) *exp.AzureMachinePool {
+				return &exp.AzureMachinePool{
+					Spec: exp.AzureMachinePoolSpec{
+						Autoscale: &exp.AzureMachinePoolAutoscale{
+							MinReplicas:     1,
+							MaxReplicas:     10,
+							DefaultReplicas: to.Ptr[int32](3),
+							Rules: []exp.AutoscaleRule{
+								{
+									MetricName: "Percentage CPU",
+									Operator:   "GreaterThan",
+									Threshold:  resource.MustParse("75"),
+									Direction:  "Increase",
+								},
+							},
+						},
+					},
+				}
+			},
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).NotTo(gomega.HaveOccurred())
+			},
+		},
+		{
+			Name: "HasAutoscaleWithMinReplicasGreaterThanMaxReplicas",
+			Factory: func(_ *gomega.GomegaWithT) *exp.AzureMachinePool {
+				return &exp.AzureMachinePool{
+					Spec: exp.AzureMachinePoolSpec{
+						Autoscale: &exp.AzureMachinePoolAutoscale{
+							MinReplicas: 5,
+							MaxReplicas: 2,
+							Rules: []exp.AutoscaleRule{
+								{
+									MetricName: "Percentage CPU",
+									Operator:   "GreaterThan",
+									Threshold:  resource.MustParse("75"),
+									Direction:  "Increase",
+								},
+							},
+						},
+					},
+				}
+			},
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).To(gomega.HaveOccurred())
+				g.Expect(actual.Error()).To(gomega.ContainSubstring("must not be greater than maxReplicas"))
+			},
+		},
+		{
+			Name: "HasAutoscaleWithDefaultReplicasOutOfRange",
+			Factory: func(_ *gomega.GomegaWithT) *exp.AzureMachinePool {
+				return &exp.AzureMachinePool{
+					Spec: exp.AzureMachinePoolSpec{
+						Autoscale: &exp.AzureMachinePoolAutoscale{
+							MinReplicas:     1,
+							MaxReplicas:     3,
+							DefaultReplicas: to.Ptr[int32](4),
+						},
+					},
+				}
+			},
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).To(gomega.HaveOccurred())
+				g.Expect(actual.Error()).To(gomega.ContainSubstring("must be between minReplicas and maxReplicas"))
+				g.Expect(actual.Error()).To(gomega.ContainSubstring("at least one rule is required"))
+			},
+		},
+		{
+			Name: "HasValidWarmPool",
+			Factory: func(_ *gomega.GomegaWithT) *exp.AzureMachinePool {
+				return &exp.AzureMachinePool{
+					Spec: exp.AzureMachinePoolSpec{
+						WarmPool: &exp.AzureMachinePoolWarmPool{
+							Size: 2,
+						},
+					},
+				}
+			},
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).NotTo(gomega.HaveOccurred())
+			},
+		},
+		{
+			Name: "HasWarmPoolWithAutoscale",
+			Factory: func(_ *gomega.GomegaWithT) *exp.AzureMachinePool {
+				return &exp.AzureMachinePool{
+					Spec: exp.AzureMachinePoolSpec{
+						WarmPool: &exp.AzureMachinePoolWarmPool{
+							Size: 2,
+						},
+						Autoscale: &exp.AzureMachinePoolAutoscale{
+							MinReplicas: 1,
+							MaxReplicas: 3,
+							Rules: []exp.AutoscaleRule{
+								{
+									MetricName: "Percentage CPU",
+									Operator:   "GreaterThan",
+									Threshold:  resource.MustParse("75"),
+									Direction:  "Increase",
+								},
+							},
+						},
+					},
+				}
+			},
+			Expect: func(g *gomega.GomegaWithT, actual error) {
+				g.Expect(actual).To(gomega.HaveOccurred())
+				g.Expect(actual.Error()).To(gomega.ContainSubstring("a warm pool can't be used with an autoscale setting"))
+			},
+		},
 	}
 
 	for _, c := range cases {
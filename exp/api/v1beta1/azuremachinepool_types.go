@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -28,6 +29,10 @@ const (
 	// MachinePoolNameLabel indicates the AzureMachinePool name the AzureMachinePoolMachine belongs.
 	MachinePoolNameLabel = "azuremachinepool.infrastructure.cluster.x-k8s.io/machine-pool"
 
+	// HibernatedAnnotation is set on an AzureMachinePool whose instances were deallocated because its cluster is
+	// hibernated, so that they are started again when the cluster resumes.
+	HibernatedAnnotation = "azuremachinepool.infrastructure.cluster.x-k8s.io/hibernated"
+
 	// RollingUpdateAzureMachinePoolDeploymentStrategyType replaces AzureMachinePoolMachines with older models with
 	// AzureMachinePoolMachines based on the latest model.
 	// i.e. gradually scale down the old AzureMachinePoolMachines and scale up the new ones.
@@ -62,8 +67,15 @@ type (
 		// +optional
 		DataDisks []infrav1.DataDisk `json:"dataDisks,omitempty"`
 
-		// SSHPublicKey is the SSH public key string base64 encoded to add to a Virtual Machine
-		SSHPublicKey string `json:"sshPublicKey"`
+		// SSHPublicKey is the SSH public key string base64 encoded to add to a Virtual Machine. If omitted, the public key
+		// of the SSH key pair generated for the cluster is added, whose private key is stored in the <cluster-name>-ssh Secret.
+		// +optional
+		SSHPublicKey string `json:"sshPublicKey,omitempty"`
+
+		// AdditionalSSHPublicKeys are SSH public key strings base64 encoded to authorize on the Virtual Machines in
+		// addition to SSHPublicKey, e.g. the keys of the members of a team.
+		// +optional
+		AdditionalSSHPublicKeys []string `json:"additionalSSHPublicKeys,omitempty"`
 
 		// AcceleratedNetworking enables or disables Azure accelerated networking. If omitted, it will be set based on
 		// whether the requested VMSize supports accelerated networking.
@@ -84,6 +96,11 @@ type (
 		// +optional
 		SpotVMOptions *infrav1.SpotVMOptions `json:"spotVMOptions,omitempty"`
 
+		// AdditionalCapabilities specifies additional capabilities enabled or disabled on the virtual machines of the
+		// scale set, e.g. hibernation.
+		// +optional
+		AdditionalCapabilities *infrav1.AdditionalCapabilities `json:"additionalCapabilities,omitempty"`
+
 		// SubnetName selects the Subnet where the VMSS will be placed
 		// +optional
 		SubnetName string `json:"subnetName,omitempty"`
@@ -91,6 +108,36 @@ type (
 		// NetworkInterfaces to attach to the to a virtual machine.
 		// +optional
 		NetworkInterfaces []infrav1.AzureNetworkInterface `json:"networkInterfaces,omitempty"`
+
+		// UserData is passed to the virtual machines of the scale set through their userData property, separately from the
+		// bootstrap data passed as customData. It can be read from the Azure Instance Metadata Service and is not part of the
+		// bootstrap secret.
+		// +optional
+		UserData *infrav1.UserData `json:"userData,omitempty"`
+
+		// MultiInstanceGPU partitions the GPUs of the virtual machines of the scale set into GPU instances every time
+		// they boot, through a systemd unit written by cloud-init merged with the bootstrap data. It requires a Linux VM
+		// size with MIG capable GPUs and the NVIDIA driver in the image.
+		// +optional
+		MultiInstanceGPU *infrav1.MultiInstanceGPU `json:"multiInstanceGPU,omitempty"`
+
+		// LocalStorage formats and mounts the local temp or NVMe disks of the virtual machines of the scale set before
+		// they are bootstrapped, through cloud-init merged with the bootstrap data. It is only supported on Linux machines
+		// bootstrapped with cloud-init.
+		// +optional
+		LocalStorage *infrav1.LocalStorage `json:"localStorage,omitempty"`
+
+		// FileStorage installs the packages and loads the kernel modules the Azure Files and Azure Blob storage CSI
+		// drivers need to mount volumes, through cloud-init merged with the bootstrap data. It is only supported on
+		// Linux machines bootstrapped with cloud-init.
+		// +optional
+		FileStorage *infrav1.FileStorage `json:"fileStorage,omitempty"`
+
+		// CACertificates are additional CA certificates added to the trust store of the virtual machines of the scale
+		// set before they are bootstrapped, through cloud-init merged with the bootstrap data. It is only supported on
+		// Linux machines bootstrapped with cloud-init.
+		// +optional
+		CACertificates *infrav1.CACertificates `json:"caCertificates,omitempty"`
 	}
 
 	// AzureMachinePoolSpec defines the desired state of AzureMachinePool.
@@ -147,6 +194,225 @@ type (
 		// NOTE: NodeDrainTimeout is different from `kubectl drain --timeout`
 		// +optional
 		NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
+
+		// Replicas is the desired number of instances of the scale set, usually set through the scale subresource of
+		// the AzureMachinePool, e.g. with kubectl scale. When set, the owning MachinePool is scaled to it. Instances
+		// protected from scale-in are not deleted to lower the number of instances.
+		// +kubebuilder:validation:Minimum=0
+		// +optional
+		Replicas *int32 `json:"replicas,omitempty"`
+
+		// Autoscale declares an Azure Monitor autoscale setting for the scale set, which scales it on metrics such as the
+		// CPU usage of its instances or the depth of a queue, for clusters that don't run the cluster-autoscaler. The
+		// replicas of the MachinePool then follow the capacity of the scale set.
+		// +optional
+		Autoscale *AzureMachinePoolAutoscale `json:"autoscale,omitempty"`
+
+		// WarmPool keeps pre-provisioned, deallocated instances in the scale set in addition to its replicas. On
+		// scale-out, warm instances are started instead of provisioning new instances from scratch, and the warm pool is
+		// refilled in the background. It can't be used with Autoscale.
+		// +optional
+		WarmPool *AzureMachinePoolWarmPool `json:"warmPool,omitempty"`
+
+		// Prescale raises the replicas of the AzureMachinePool ahead of recurring daily peaks, declared as schedules or
+		// learned from the Azure Monitor metrics history of the scale set, and lowers them back once the peaks are over.
+		// It requires the experimental MachinePoolPrescaling feature and can't be used with Autoscale.
+		// +optional
+		Prescale *AzureMachinePoolPrescale `json:"prescale,omitempty"`
+
+		// ScalingSchedule bounds the replicas of the AzureMachinePool by recurring time windows, e.g. to scale a
+		// development cluster to zero at night and on weekends and back up on weekday mornings. A window starts when the
+		// schedule of an entry fires and lasts until the schedule of another entry fires. It can't be used with
+		// Autoscale.
+		// +optional
+		ScalingSchedule []ScalingScheduleEntry `json:"scalingSchedule,omitempty"`
+
+		// PlacementFallbacks are the VM sizes and availability zones the scale set falls back to, in order, when its VMs
+		// can't be allocated, e.g. because the VM size isn't available or has no capacity in its zones. The scale set
+		// moves to the next fallback on each allocation failure, and back to the VM size of the template and the
+		// failure domains of the MachinePool after the last one.
+		// +optional
+		PlacementFallbacks []AzureMachinePoolPlacement `json:"placementFallbacks,omitempty"`
+	}
+
+	// AzureMachinePoolPlacement is a VM size and availability zones the scale set of an AzureMachinePool can use.
+	AzureMachinePoolPlacement struct {
+		// VMSize is the size of the VMs. Defaults to the VM size of the template.
+		// +optional
+		VMSize string `json:"vmSize,omitempty"`
+
+		// Zones are the availability zones of the scale set. Defaults to the failure domains of the MachinePool. The zones
+		// of an existing scale set can't change, so they are only used when the scale set is created, or recreated after
+		// it failed to be created.
+		// +optional
+		Zones []string `json:"zones,omitempty"`
+	}
+
+	// ScalingScheduleEntry starts a window bounding the replicas of an AzureMachinePool.
+	ScalingScheduleEntry struct {
+		// Name identifies the entry.
+		// +kubebuilder:validation:MinLength=1
+		Name string `json:"name"`
+
+		// Schedule is the cron expression of when the window starts, made of the minute, hour, day of month, month and
+		// day of week fields, e.g. "0 7 * * 1-5" for weekday mornings.
+		Schedule string `json:"schedule"`
+
+		// TimeZone is the IANA name of the time zone of the schedule, e.g. "Europe/Paris". Defaults to UTC.
+		// +optional
+		TimeZone string `json:"timeZone,omitempty"`
+
+		// MinReplicas is the minimum number of replicas during the window.
+		// +kubebuilder:validation:Minimum=0
+		MinReplicas int32 `json:"minReplicas"`
+
+		// MaxReplicas is the maximum number of replicas during the window.
+		// +kubebuilder:validation:Minimum=0
+		MaxReplicas int32 `json:"maxReplicas"`
+	}
+
+	// AzureMachinePoolPrescale defines how an AzureMachinePool is pre-scaled before recurring daily peaks.
+	AzureMachinePoolPrescale struct {
+		// TimeZone is the IANA name of the time zone of the schedules and of the learned daily peaks, e.g.
+		// "Europe/Paris". Defaults to UTC.
+		// +optional
+		TimeZone string `json:"timeZone,omitempty"`
+
+		// LeadTime is how long before a peak the replicas are raised, so that the new machines are ready when it starts.
+		// Defaults to 15 minutes.
+		// +optional
+		LeadTime *metav1.Duration `json:"leadTime,omitempty"`
+
+		// Schedules declare recurring daily peaks.
+		// +optional
+		Schedules []PrescaleSchedule `json:"schedules,omitempty"`
+
+		// Learning learns the recurring daily peaks from the CPU usage history of the scale set.
+		// +optional
+		Learning *PrescaleLearning `json:"learning,omitempty"`
+	}
+
+	// PrescaleSchedule declares a recurring daily peak.
+	PrescaleSchedule struct {
+		// Start is the time of the day the peak starts, formatted as HH:MM.
+		// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+		Start string `json:"start"`
+
+		// Duration is how long the peak lasts.
+		Duration metav1.Duration `json:"duration"`
+
+		// Replicas is the minimum number of replicas during the peak.
+		// +kubebuilder:validation:Minimum=1
+		Replicas int32 `json:"replicas"`
+	}
+
+	// PrescaleLearning defines how the recurring daily peaks of an AzureMachinePool are learned from the "Percentage
+	// CPU" metric of its scale set.
+	PrescaleLearning struct {
+		// TargetCPUPercentage is the average CPU usage of the replicas the learned number of replicas aims for.
+		// Defaults to 60.
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=100
+		// +optional
+		TargetCPUPercentage *int32 `json:"targetCPUPercentage,omitempty"`
+
+		// HistoryDays is the number of days of metrics history analyzed. Defaults to 7.
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=30
+		// +optional
+		HistoryDays *int32 `json:"historyDays,omitempty"`
+
+		// MaxReplicas caps the learned number of replicas.
+		// +kubebuilder:validation:Minimum=1
+		MaxReplicas int32 `json:"maxReplicas"`
+	}
+
+	// AzureMachinePoolWarmPool defines the warm pool of the scale set of an AzureMachinePool.
+	AzureMachinePoolWarmPool struct {
+		// Size is the number of deallocated instances kept ready to be started.
+		// +kubebuilder:validation:Minimum=1
+		Size int32 `json:"size"`
+	}
+
+	// AzureMachinePoolAutoscale defines the autoscale profile of the scale set of an AzureMachinePool.
+	AzureMachinePoolAutoscale struct {
+		// MinReplicas is the minimum number of instances of the scale set.
+		// +kubebuilder:validation:Minimum=0
+		MinReplicas int32 `json:"minReplicas"`
+
+		// MaxReplicas is the maximum number of instances of the scale set.
+		// +kubebuilder:validation:Minimum=1
+		MaxReplicas int32 `json:"maxReplicas"`
+
+		// DefaultReplicas is the number of instances of the scale set when the metrics of the rules are not available.
+		// Defaults to MinReplicas.
+		// +optional
+		DefaultReplicas *int32 `json:"defaultReplicas,omitempty"`
+
+		// Rules scale the scale set in or out when a metric crosses a threshold.
+		// +kubebuilder:validation:MinItems=1
+		// +kubebuilder:validation:MaxItems=10
+		Rules []AutoscaleRule `json:"rules"`
+	}
+
+	// AutoscaleRule changes the number of instances of a scale set when a metric crosses a threshold.
+	AutoscaleRule struct {
+		// MetricName is the name of the metric the rule monitors, e.g. "Percentage CPU" for the scale set or
+		// "ApproximateMessageCount" for a Service Bus queue.
+		MetricName string `json:"metricName"`
+
+		// MetricNamespace is the namespace of the metric the rule monitors. Defaults to the namespace of the resource
+		// emitting the metric.
+		// +optional
+		MetricNamespace string `json:"metricNamespace,omitempty"`
+
+		// MetricResourceID is the ID of the resource emitting the metric, e.g. a Service Bus namespace to scale on the
+		// depth of its queues. Defaults to the scale set.
+		// +optional
+		MetricResourceID string `json:"metricResourceID,omitempty"`
+
+		// Statistic is how the metric of the instances of the scale set is combined.
+		// +kubebuilder:validation:Enum=Average;Min;Max;Sum
+		// +kubebuilder:default=Average
+		// +optional
+		Statistic string `json:"statistic,omitempty"`
+
+		// TimeAggregation is how the metric is combined over the time window.
+		// +kubebuilder:validation:Enum=Average;Minimum;Maximum;Total;Count;Last
+		// +kubebuilder:default=Average
+		// +optional
+		TimeAggregation string `json:"timeAggregation,omitempty"`
+
+		// TimeWindow is the range of time, in minutes, over which the metric is aggregated.
+		// +kubebuilder:validation:Minimum=5
+		// +kubebuilder:validation:Maximum=720
+		// +kubebuilder:default=10
+		// +optional
+		TimeWindow int32 `json:"timeWindow,omitempty"`
+
+		// Operator compares the aggregated metric to the threshold.
+		// +kubebuilder:validation:Enum=GreaterThan;GreaterThanOrEqual;LessThan;LessThanOrEqual
+		Operator string `json:"operator"`
+
+		// Threshold of the metric that triggers the scale action.
+		Threshold resource.Quantity `json:"threshold"`
+
+		// Direction is whether the rule scales the scale set out or in.
+		// +kubebuilder:validation:Enum=Increase;Decrease
+		Direction string `json:"direction"`
+
+		// ChangeCount is the number of instances added or removed by the scale action.
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:default=1
+		// +optional
+		ChangeCount int32 `json:"changeCount,omitempty"`
+
+		// Cooldown is the time, in minutes, to wait after a scale action before the rule triggers again.
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=10080
+		// +kubebuilder:default=5
+		// +optional
+		Cooldown int32 `json:"cooldown,omitempty"`
 	}
 
 	// AzureMachinePoolDeploymentStrategyType is the type of deployment strategy employed to rollout a new version of
@@ -214,6 +480,12 @@ type (
 		// +kubebuilder:validation:Enum=Random;Newest;Oldest
 		// +kubebuilder:default:=Oldest
 		DeletePolicy AzureMachinePoolDeletePolicyType `json:"deletePolicy,omitempty"`
+
+		// RolloutModelUpdates also replaces the machines that run the latest image but, as reported by Azure, not the
+		// latest VMSS model, e.g. after an extension update. By default, only machines running an outdated image are
+		// replaced.
+		// +optional
+		RolloutModelUpdates bool `json:"rolloutModelUpdates,omitempty"`
 	}
 
 	// AzureMachinePoolStatus defines the observed state of AzureMachinePool.
@@ -226,10 +498,21 @@ type (
 		// +optional
 		Replicas int32 `json:"replicas"`
 
+		// Selector is the label selector of the AzureMachinePoolMachines of the AzureMachinePool, in string form, used
+		// by the scale subresource.
+		// +optional
+		Selector string `json:"selector,omitempty"`
+
 		// Instances is the VM instance status for each VM in the VMSS
 		// +optional
+		// +k8s:conversion-gen=false
 		Instances []*AzureMachinePoolInstanceStatus `json:"instances,omitempty"`
 
+		// OutdatedReplicas is the number of VMSS instances that do not run the latest VMSS model, either because they
+		// run an outdated image or because Azure reports that they do not have the latest model applied.
+		// +optional
+		OutdatedReplicas int32 `json:"outdatedReplicas,omitempty"`
+
 		// Image is the current image used in the AzureMachinePool. When the spec image is nil, this image is populated
 		// with the details of the defaulted Azure Marketplace "capi" offer.
 		// +optional
@@ -289,6 +572,83 @@ type (
 		// next reconciliation loop.
 		// +optional
 		LongRunningOperationStates infrav1.Futures `json:"longRunningOperationStates,omitempty"`
+
+		// Prescale reports the daily peaks learned for the AzureMachinePool and whether it is pre-scaled.
+		// +optional
+		Prescale *AzureMachinePoolPrescaleStatus `json:"prescale,omitempty"`
+
+		// ScalingSchedule reports the active window of the scaling schedule of the AzureMachinePool.
+		// +optional
+		ScalingSchedule *AzureMachinePoolScalingScheduleStatus `json:"scalingSchedule,omitempty"`
+
+		// Placement reports the VM size and availability zones used by the scale set after an allocation failure.
+		// +optional
+		Placement *AzureMachinePoolPlacementStatus `json:"placement,omitempty"`
+	}
+
+	// AzureMachinePoolPlacementStatus reports the placement used by the scale set of an AzureMachinePool.
+	AzureMachinePoolPlacementStatus struct {
+		// Fallback is the index of the placement fallback in use. It is unset when the VM size of the template and the
+		// failure domains of the MachinePool are used.
+		// +optional
+		Fallback *int32 `json:"fallback,omitempty"`
+
+		// VMSize is the VM size used by the scale set.
+		// +optional
+		VMSize string `json:"vmSize,omitempty"`
+
+		// Zones are the availability zones used by the scale set.
+		// +optional
+		Zones []string `json:"zones,omitempty"`
+
+		// LastAllocationFailure is the allocation failure that made the scale set move to this placement.
+		// +optional
+		LastAllocationFailure string `json:"lastAllocationFailure,omitempty"`
+
+		// LastTransitionTime is when the scale set moved to this placement.
+		// +optional
+		LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	}
+
+	// AzureMachinePoolScalingScheduleStatus reports the active window of the scaling schedule of an AzureMachinePool.
+	AzureMachinePoolScalingScheduleStatus struct {
+		// ActiveEntry is the name of the scaling schedule entry whose window is active.
+		// +optional
+		ActiveEntry string `json:"activeEntry,omitempty"`
+
+		// WindowStartTime is when the active window started.
+		// +optional
+		WindowStartTime *metav1.Time `json:"windowStartTime,omitempty"`
+
+		// AutoscalerSizesOverridden is true when the bounds of the window are set as the cluster-autoscaler node group
+		// size of the MachinePool, because its replicas are managed by an autoscaler.
+		// +optional
+		AutoscalerSizesOverridden bool `json:"autoscalerSizesOverridden,omitempty"`
+
+		// OriginalAutoscalerSizes are the cluster-autoscaler node group size annotations of the MachinePool before they
+		// were overridden, restored once no window is active.
+		// +optional
+		OriginalAutoscalerSizes map[string]string `json:"originalAutoscalerSizes,omitempty"`
+	}
+
+	// AzureMachinePoolPrescaleStatus reports the state of the pre-scaling of an AzureMachinePool.
+	AzureMachinePoolPrescaleStatus struct {
+		// LearnedReplicas is the number of replicas learned for each hour of the day, from midnight in the time zone of
+		// the pre-scaling.
+		// +optional
+		LearnedReplicas []int32 `json:"learnedReplicas,omitempty"`
+
+		// LastLearningTime is when the metrics history of the scale set was last analyzed.
+		// +optional
+		LastLearningTime *metav1.Time `json:"lastLearningTime,omitempty"`
+
+		// Replicas is the number of replicas the AzureMachinePool is pre-scaled to, if it is pre-scaled.
+		// +optional
+		Replicas *int32 `json:"replicas,omitempty"`
+
+		// BaseReplicas is the number of replicas before pre-scaling, restored once the peak is over.
+		// +optional
+		BaseReplicas *int32 `json:"baseReplicas,omitempty"`
 	}
 
 	// AzureMachinePoolInstanceStatus provides status information for each instance in the VMSS.
@@ -317,10 +677,29 @@ type (
 		// the image version the VM is running. If the instance is not running the latest model, it means the instance
 		// may not be running the version of Kubernetes the Machine Pool has specified and needs to be updated.
 		LatestModelApplied bool `json:"latestModelApplied"`
+
+		// PowerState is the power state of the VM Instance, e.g. Running or Deallocated.
+		// +optional
+		PowerState infrav1.PowerState `json:"powerState,omitempty"`
+
+		// FaultDomain is the platform fault domain of the VM Instance.
+		// +optional
+		FaultDomain *int32 `json:"faultDomain,omitempty"`
+
+		// ImageVersion is the version of the image the VM Instance was created from, resolved when the image
+		// version is "latest".
+		// +optional
+		ImageVersion string `json:"imageVersion,omitempty"`
+
+		// ProtectedFromScaleIn is true when the VM Instance is protected from scale-in, so it is not deleted when the
+		// AzureMachinePool is scaled down.
+		// +optional
+		ProtectedFromScaleIn bool `json:"protectedFromScaleIn,omitempty"`
 	}
 
 	// +kubebuilder:object:root=true
 	// +kubebuilder:subresource:status
+	// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
 	// +kubebuilder:resource:path=azuremachinepools,scope=Namespaced,categories=cluster-api,shortName=amp
 	// +kubebuilder:storageversion
 	// +kubebuilder:printcolumn:name="Replicas",type="string",JSONPath=".status.replicas",description="AzureMachinePool replicas count"
@@ -20,13 +20,17 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/util/cron"
+	webhookutil "sigs.k8s.io/cluster-api-provider-azure/util/webhook"
 	capifeature "sigs.k8s.io/cluster-api/feature"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -34,6 +38,9 @@ import (
 
 // SetupWebhookWithManager sets up and registers the webhook with the manager.
 func (amp *AzureMachinePool) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if err := webhookutil.RegisterValidatingWebhookWithWarnings(mgr, amp); err != nil {
+		return err
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(amp).
 		Complete()
@@ -45,9 +52,6 @@ var _ webhook.Defaulter = &AzureMachinePool{}
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
 func (amp *AzureMachinePool) Default() {
-	if err := amp.SetDefaultSSHPublicKey(); err != nil {
-		ctrl.Log.WithName("AzureMachinePoolLogger").Error(err, "SetDefaultSshPublicKey failed")
-	}
 	amp.SetIdentityDefaults()
 }
 
@@ -89,6 +93,22 @@ func (amp *AzureMachinePool) Validate(old runtime.Object) error {
 		amp.ValidateStrategy(),
 		amp.ValidateSystemAssignedIdentity(old),
 		amp.ValidateNetwork,
+		amp.ValidateOSDistro,
+		amp.ValidateUserData,
+		amp.ValidateHibernation,
+		amp.ValidateMultiInstanceGPU,
+		amp.ValidateLocalStorage,
+		amp.ValidateFileStorage,
+		amp.ValidateCACertificates,
+		amp.ValidateWriteAccelerator,
+		amp.ValidateDataDisks,
+		amp.ValidateDataDisksUpdate(old),
+		amp.ValidateSourceSnapshot,
+		amp.ValidateAutoscale,
+		amp.ValidateWarmPool,
+		amp.ValidatePrescale,
+		amp.ValidateScalingSchedule,
+		amp.ValidatePlacementFallbacks,
 	}
 
 	var errs []error
@@ -106,6 +126,282 @@ func (amp *AzureMachinePool) ValidateNetwork() error {
 	if (amp.Spec.Template.NetworkInterfaces != nil) && len(amp.Spec.Template.NetworkInterfaces) > 0 && amp.Spec.Template.SubnetName != "" {
 		return errors.New("cannot set both NetworkInterfaces and machine SubnetName")
 	}
+	fldPath := field.NewPath("spec", "template", "networkInterfaces")
+	for i, nic := range amp.Spec.Template.NetworkInterfaces {
+		// Scale set network interfaces don't support an internal DNS name label, which would also be shared by all instances.
+		if nic.InternalDNSNameLabel != "" {
+			return field.Forbidden(fldPath.Index(i).Child("internalDNSNameLabel"), "internalDNSNameLabel can't be set for machine pools")
+		}
+	}
+	if errs := infrav1.ValidateNetwork(amp.Spec.Template.SubnetName, amp.Spec.Template.NetworkInterfaces, fldPath); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	return nil
+}
+
+// ValidateOSDistro of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateOSDistro() error {
+	if amp.Spec.Template.OSDisk.Distro != "" && amp.Spec.Template.OSDisk.OSType == azure.WindowsOS {
+		return field.Forbidden(field.NewPath("spec", "template", "osDisk", "distro"), "distro can only be set for Linux machines")
+	}
+	return nil
+}
+
+// ValidateUserData of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateUserData() error {
+	if errs := infrav1.ValidateUserData(amp.Spec.Template.UserData, field.NewPath("spec", "template", "userData")); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	return nil
+}
+
+// ValidateHibernation of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateHibernation() error {
+	template := amp.Spec.Template
+	if errs := infrav1.ValidateHibernation("", template.AdditionalCapabilities, template.SpotVMOptions, field.NewPath("spec", "template", "desiredPowerState")); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	return nil
+}
+
+// ValidateMultiInstanceGPU of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateMultiInstanceGPU() error {
+	template := amp.Spec.Template
+	if errs := infrav1.ValidateMultiInstanceGPU(template.MultiInstanceGPU, template.VMSize, template.OSDisk, field.NewPath("spec", "template", "multiInstanceGPU")); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	return nil
+}
+
+// ValidateLocalStorage of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateLocalStorage() error {
+	if errs := infrav1.ValidateLocalStorage(amp.Spec.Template.LocalStorage, amp.Spec.Template.OSDisk, field.NewPath("spec", "template", "localStorage")); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	return nil
+}
+
+// ValidateFileStorage of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateFileStorage() error {
+	if errs := infrav1.ValidateFileStorage(amp.Spec.Template.FileStorage, amp.Spec.Template.OSDisk, field.NewPath("spec", "template", "fileStorage")); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	return nil
+}
+
+// ValidateCACertificates of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateCACertificates() error {
+	if errs := infrav1.ValidateCACertificates(amp.Spec.Template.CACertificates, amp.Spec.Template.OSDisk, field.NewPath("spec", "template", "caCertificates")); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	return nil
+}
+
+// ValidateWriteAccelerator of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateWriteAccelerator() error {
+	template := amp.Spec.Template
+	fldPath := field.NewPath("spec", "template")
+
+	allErrs := infrav1.ValidateWriteAccelerator(template.OSDisk.WriteAcceleratorEnabled, template.OSDisk.CachingType, template.OSDisk.ManagedDisk, fldPath.Child("osDisk"))
+	if template.OSDisk.DiffDiskSettings != nil && template.OSDisk.WriteAcceleratorEnabled != nil && *template.OSDisk.WriteAcceleratorEnabled {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("osDisk", "writeAcceleratorEnabled"), "write accelerator is not supported on ephemeral OS disks"))
+	}
+	for i, disk := range template.DataDisks {
+		allErrs = append(allErrs, infrav1.ValidateWriteAccelerator(disk.WriteAcceleratorEnabled, disk.CachingType, disk.ManagedDisk, fldPath.Child("dataDisks").Index(i))...)
+	}
+	if len(allErrs) > 0 {
+		return allErrs.ToAggregate()
+	}
+	return nil
+}
+
+// ValidateDataDisks of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateDataDisks() error {
+	var allErrs field.ErrorList
+	for i, disk := range amp.Spec.Template.DataDisks {
+		fldPath := field.NewPath("spec", "template", "dataDisks").Index(i)
+		if disk.ExistingDiskID != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("existingDiskID"), "existing disks can't be attached to the instances of a scale set"))
+		}
+		if disk.DeletePolicy != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("deletePolicy"), "the data disks of a scale set are deleted with its instances"))
+		}
+	}
+	if len(allErrs) > 0 {
+		return allErrs.ToAggregate()
+	}
+	return nil
+}
+
+// ValidateDataDisksUpdate validates that the data disks of an AzureMachinePool are only added or removed, as the data
+// disks of the instances are updated in place by their LUN.
+func (amp *AzureMachinePool) ValidateDataDisksUpdate(old runtime.Object) func() error {
+	return func() error {
+		if old == nil {
+			return nil
+		}
+		oldMachinePool, ok := old.(*AzureMachinePool)
+		if !ok {
+			return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+				"AzureMachinePool", reflect.TypeOf(old))
+		}
+
+		oldDisks := make(map[int32]infrav1.DataDisk, len(oldMachinePool.Spec.Template.DataDisks))
+		for _, disk := range oldMachinePool.Spec.Template.DataDisks {
+			if disk.Lun != nil {
+				oldDisks[*disk.Lun] = disk
+			}
+		}
+
+		var allErrs field.ErrorList
+		for i, disk := range amp.Spec.Template.DataDisks {
+			if disk.Lun == nil {
+				continue
+			}
+			if oldDisk, ok := oldDisks[*disk.Lun]; ok && !reflect.DeepEqual(oldDisk, disk) {
+				allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "dataDisks").Index(i),
+					"data disks can be added or removed, but not changed"))
+			}
+		}
+		if len(allErrs) > 0 {
+			return allErrs.ToAggregate()
+		}
+		return nil
+	}
+}
+
+// ValidateSourceSnapshot of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateSourceSnapshot() error {
+	if amp.Spec.Template.OSDisk.SourceSnapshotID != "" {
+		return field.Forbidden(field.NewPath("spec", "template", "osDisk", "sourceSnapshotID"), "the OS disks of a scale set can't be created from a snapshot")
+	}
+	return nil
+}
+
+// ValidateAutoscale validates the autoscale profile of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateAutoscale() error {
+	autoscale := amp.Spec.Autoscale
+	if autoscale == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "autoscale")
+	if autoscale.MinReplicas > autoscale.MaxReplicas {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minReplicas"), autoscale.MinReplicas, "must not be greater than maxReplicas"))
+	}
+	if autoscale.DefaultReplicas != nil && (*autoscale.DefaultReplicas < autoscale.MinReplicas || *autoscale.DefaultReplicas > autoscale.MaxReplicas) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("defaultReplicas"), *autoscale.DefaultReplicas, "must be between minReplicas and maxReplicas"))
+	}
+	if len(autoscale.Rules) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("rules"), "at least one rule is required"))
+	}
+	if len(allErrs) > 0 {
+		return allErrs.ToAggregate()
+	}
+	return nil
+}
+
+// ValidateWarmPool of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateWarmPool() error {
+	if amp.Spec.WarmPool != nil && amp.Spec.Autoscale != nil {
+		return field.Forbidden(field.NewPath("spec", "warmPool"), "a warm pool can't be used with an autoscale setting, which manages the instances of the scale set itself")
+	}
+	return nil
+}
+
+// ValidatePrescale of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidatePrescale() error {
+	prescale := amp.Spec.Prescale
+	if prescale == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "prescale")
+	if !feature.Gates.Enabled(feature.MachinePoolPrescaling) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "can be set only if the MachinePoolPrescaling feature flag is enabled"))
+	}
+	if amp.Spec.Autoscale != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "pre-scaling can't be used with an autoscale setting, which manages the instances of the scale set itself"))
+	}
+	if len(prescale.Schedules) == 0 && prescale.Learning == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of schedules or learning is required"))
+	}
+	if _, err := time.LoadLocation(prescale.TimeZone); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeZone"), prescale.TimeZone, "must be an IANA time zone name"))
+	}
+	if prescale.LeadTime != nil && prescale.LeadTime.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("leadTime"), prescale.LeadTime.Duration.String(), "must not be negative"))
+	}
+	for i, schedule := range prescale.Schedules {
+		if _, err := time.Parse("15:04", schedule.Start); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("schedules").Index(i).Child("start"), schedule.Start, "must be formatted as HH:MM"))
+		}
+		if schedule.Duration.Duration <= 0 || schedule.Duration.Duration > 24*time.Hour {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("schedules").Index(i).Child("duration"), schedule.Duration.Duration.String(), "must be greater than 0 and at most 24h"))
+		}
+	}
+	if len(allErrs) > 0 {
+		return allErrs.ToAggregate()
+	}
+	return nil
+}
+
+// ValidateScalingSchedule of an AzureMachinePool.
+func (amp *AzureMachinePool) ValidateScalingSchedule() error {
+	if len(amp.Spec.ScalingSchedule) == 0 {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "scalingSchedule")
+	if amp.Spec.Autoscale != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "a scaling schedule can't be used with an autoscale setting, which manages the instances of the scale set itself"))
+	}
+
+	names := make(map[string]struct{}, len(amp.Spec.ScalingSchedule))
+	for i, entry := range amp.Spec.ScalingSchedule {
+		entryPath := fldPath.Index(i)
+		if _, ok := names[entry.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(entryPath.Child("name"), entry.Name))
+		}
+		names[entry.Name] = struct{}{}
+		if _, err := cron.Parse(entry.Schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(entryPath.Child("schedule"), entry.Schedule, err.Error()))
+		}
+		if _, err := time.LoadLocation(entry.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(entryPath.Child("timeZone"), entry.TimeZone, "must be an IANA time zone name"))
+		}
+		if entry.MinReplicas > entry.MaxReplicas {
+			allErrs = append(allErrs, field.Invalid(entryPath.Child("minReplicas"), entry.MinReplicas, "must not be greater than maxReplicas"))
+		}
+	}
+	if len(allErrs) > 0 {
+		return allErrs.ToAggregate()
+	}
+	return nil
+}
+
+// ValidatePlacementFallbacks validates the placements the scale set of an AzureMachinePool falls back to.
+func (amp *AzureMachinePool) ValidatePlacementFallbacks() error {
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "placementFallbacks")
+	seen := make(map[string]struct{}, len(amp.Spec.PlacementFallbacks))
+	for i, fallback := range amp.Spec.PlacementFallbacks {
+		if fallback.VMSize == "" && len(fallback.Zones) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i), "at least one of vmSize and zones must be set"))
+			continue
+		}
+		key := fmt.Sprintf("%s/%v", fallback.VMSize, fallback.Zones)
+		if _, ok := seen[key]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), fallback))
+		}
+		seen[key] = struct{}{}
+	}
+	if len(allErrs) > 0 {
+		return allErrs.ToAggregate()
+	}
 	return nil
 }
 
@@ -138,14 +434,16 @@ func (amp *AzureMachinePool) ValidateTerminateNotificationTimeout() error {
 	return nil
 }
 
-// ValidateSSHKey validates an SSHKey.
+// ValidateSSHKey validates the SSH public keys of the machine template.
 func (amp *AzureMachinePool) ValidateSSHKey() error {
+	var allErrs field.ErrorList
 	if amp.Spec.Template.SSHPublicKey != "" {
 		sshKey := amp.Spec.Template.SSHPublicKey
-		if errs := infrav1.ValidateSSHKey(sshKey, field.NewPath("sshKey")); len(errs) > 0 {
-			agg := kerrors.NewAggregate(errs.ToAggregate().Errors())
-			return agg
-		}
+		allErrs = append(allErrs, infrav1.ValidateSSHKey(sshKey, field.NewPath("sshKey"))...)
+	}
+	allErrs = append(allErrs, infrav1.ValidateAdditionalSSHKeys(amp.Spec.Template.AdditionalSSHPublicKeys, field.NewPath("additionalSSHPublicKeys"))...)
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
 	}
 
 	return nil
@@ -199,3 +497,20 @@ func (amp *AzureMachinePool) ValidateSystemAssignedIdentity(old runtime.Object)
 		return nil
 	}
 }
+
+var _ webhookutil.Warner = &AzureMachinePool{}
+
+// WarningsOnCreate implements webhookutil.Warner so admission warnings are returned for the type.
+func (amp *AzureMachinePool) WarningsOnCreate() []string {
+	return amp.warnings()
+}
+
+// WarningsOnUpdate implements webhookutil.Warner so admission warnings are returned for the type.
+func (amp *AzureMachinePool) WarningsOnUpdate(_ runtime.Object) []string {
+	return amp.warnings()
+}
+
+// warnings returns the admission warnings for deprecated fields and risky configurations of the AzureMachinePool.
+func (amp *AzureMachinePool) warnings() []string {
+	return infrav1.ImageWarnings(amp.Spec.Template.Image, field.NewPath("spec", "template", "image"))
+}
@@ -21,11 +21,13 @@ import (
 	"crypto/rsa"
 	"encoding/base64"
 	"testing"
+	"time"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	guuid "github.com/google/uuid"
 	. "github.com/onsi/gomega"
 	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	utilfeature "k8s.io/component-base/featuregate/testing"
@@ -57,32 +59,32 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 	}{
 		{
 			name:    "azuremachinepool with marketplace image - full",
-			amp:     createMachinePoolWithMarketPlaceImage("PUB1234", "OFFER1234", "SKU1234", "1.0.0", to.IntPtr(10)),
+			amp:     createMachinePoolWithMarketPlaceImage("PUB1234", "OFFER1234", "SKU1234", "1.0.0", to.Ptr(10)),
 			wantErr: false,
 		},
 		{
 			name:    "azuremachinepool with marketplace image - missing publisher",
-			amp:     createMachinePoolWithMarketPlaceImage("", "OFFER1234", "SKU1234", "1.0.0", to.IntPtr(10)),
+			amp:     createMachinePoolWithMarketPlaceImage("", "OFFER1234", "SKU1234", "1.0.0", to.Ptr(10)),
 			wantErr: true,
 		},
 		{
 			name:    "azuremachinepool with shared gallery image - full",
-			amp:     createMachinePoolWithSharedImage("SUB123", "RG123", "NAME123", "GALLERY1", "1.0.0", to.IntPtr(10)),
+			amp:     createMachinePoolWithSharedImage("SUB123", "RG123", "NAME123", "GALLERY1", "1.0.0", to.Ptr(10)),
 			wantErr: false,
 		},
 		{
 			name:    "azuremachinepool with marketplace image - missing subscription",
-			amp:     createMachinePoolWithSharedImage("", "RG123", "NAME123", "GALLERY1", "1.0.0", to.IntPtr(10)),
+			amp:     createMachinePoolWithSharedImage("", "RG123", "NAME123", "GALLERY1", "1.0.0", to.Ptr(10)),
 			wantErr: true,
 		},
 		{
 			name:    "azuremachinepool with image by - with id",
-			amp:     createMachinePoolWithImageByID("ID123", to.IntPtr(10)),
+			amp:     createMachinePoolWithImageByID("ID123", to.Ptr(10)),
 			wantErr: false,
 		},
 		{
 			name:    "azuremachinepool with image by - without id",
-			amp:     createMachinePoolWithImageByID("", to.IntPtr(10)),
+			amp:     createMachinePoolWithImageByID("", to.Ptr(10)),
 			wantErr: true,
 		},
 		{
@@ -97,7 +99,7 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 		},
 		{
 			name:    "azuremachinepool with wrong terminate notification",
-			amp:     createMachinePoolWithSharedImage("SUB123", "RG123", "NAME123", "GALLERY1", "1.0.0", to.IntPtr(35)),
+			amp:     createMachinePoolWithSharedImage("SUB123", "RG123", "NAME123", "GALLERY1", "1.0.0", to.Ptr(35)),
 			wantErr: true,
 		},
 		{
@@ -157,6 +159,31 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 			amp:     createMachinePoolWithNetworkConfig("", []infrav1.AzureNetworkInterface{{SubnetName: "testSubnet"}}),
 			wantErr: false,
 		},
+		{
+			name:    "azuremachinepool with DNS servers",
+			amp:     createMachinePoolWithNetworkConfig("", []infrav1.AzureNetworkInterface{{SubnetName: "testSubnet", DNSServers: []string{"10.0.0.4"}}}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with invalid DNS server",
+			amp:     createMachinePoolWithNetworkConfig("", []infrav1.AzureNetworkInterface{{SubnetName: "testSubnet", DNSServers: []string{"dns.example.com"}}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with internal DNS name label",
+			amp:     createMachinePoolWithNetworkConfig("", []infrav1.AzureNetworkInterface{{SubnetName: "testSubnet", InternalDNSNameLabel: "win-node"}}),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with Azure Linux distro",
+			amp:     createMachinePoolWithOSDisk(infrav1.OSDisk{OSType: "Linux", Distro: infrav1.OSDistroAzureLinux}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with distro set on Windows",
+			amp:     createMachinePoolWithOSDisk(infrav1.OSDisk{OSType: "Windows", Distro: infrav1.OSDistroAzureLinux}),
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -178,8 +205,9 @@ func TestAzureMachinePool_ValidateUpdate(t *testing.T) {
 	g := NewWithT(t)
 
 	var (
-		zero = intstr.FromInt(0)
-		one  = intstr.FromInt(1)
+		zero     = intstr.FromInt(0)
+		one      = intstr.FromInt(1)
+		dataDisk = infrav1.DataDisk{NameSuffix: "data", DiskSizeGB: 256, Lun: to.Ptr[int32](0)}
 	)
 
 	tests := []struct {
@@ -254,6 +282,24 @@ func TestAzureMachinePool_ValidateUpdate(t *testing.T) {
 			amp:     createMachinePoolWithNetworkConfig("subnet", []infrav1.AzureNetworkInterface{{SubnetName: "testSubnet2"}}),
 			wantErr: true,
 		},
+		{
+			name:    "azuremachinepool with an added data disk",
+			oldAMP:  createMachinePoolWithDataDisks(dataDisk),
+			amp:     createMachinePoolWithDataDisks(dataDisk, infrav1.DataDisk{NameSuffix: "logs", DiskSizeGB: 64, Lun: to.Ptr[int32](1)}),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with a removed data disk",
+			oldAMP:  createMachinePoolWithDataDisks(dataDisk),
+			amp:     createMachinePoolWithDataDisks(),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with a changed data disk",
+			oldAMP:  createMachinePoolWithDataDisks(dataDisk),
+			amp:     createMachinePoolWithDataDisks(infrav1.DataDisk{NameSuffix: "data", DiskSizeGB: 512, Lun: to.Ptr[int32](0)}),
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -267,6 +313,196 @@ func TestAzureMachinePool_ValidateUpdate(t *testing.T) {
 	}
 }
 
+func TestAzureMachinePool_ValidatePrescale(t *testing.T) {
+	schedules := []PrescaleSchedule{
+		{Start: "08:30", Duration: metav1.Duration{Duration: 3 * time.Hour}, Replicas: 5},
+	}
+
+	tests := []struct {
+		name           string
+		prescale       *AzureMachinePoolPrescale
+		autoscale      *AzureMachinePoolAutoscale
+		featureEnabled bool
+		wantErr        string
+	}{
+		{
+			name:           "valid schedules",
+			prescale:       &AzureMachinePoolPrescale{TimeZone: "Europe/Paris", Schedules: schedules},
+			featureEnabled: true,
+		},
+		{
+			name:           "valid learning",
+			prescale:       &AzureMachinePoolPrescale{Learning: &PrescaleLearning{MaxReplicas: 10}},
+			featureEnabled: true,
+		},
+		{
+			name:     "feature disabled",
+			prescale: &AzureMachinePoolPrescale{Schedules: schedules},
+			wantErr:  "can be set only if the MachinePoolPrescaling feature flag is enabled",
+		},
+		{
+			name:           "neither schedules nor learning",
+			prescale:       &AzureMachinePoolPrescale{},
+			featureEnabled: true,
+			wantErr:        "at least one of schedules or learning is required",
+		},
+		{
+			name:           "invalid time zone",
+			prescale:       &AzureMachinePoolPrescale{TimeZone: "Mars/Olympus", Schedules: schedules},
+			featureEnabled: true,
+			wantErr:        "must be an IANA time zone name",
+		},
+		{
+			name: "invalid schedule",
+			prescale: &AzureMachinePoolPrescale{Schedules: []PrescaleSchedule{
+				{Start: "8h30", Duration: metav1.Duration{Duration: 25 * time.Hour}, Replicas: 5},
+			}},
+			featureEnabled: true,
+			wantErr:        "must be formatted as HH:MM",
+		},
+		{
+			name:           "with autoscale",
+			prescale:       &AzureMachinePoolPrescale{Schedules: schedules},
+			autoscale:      &AzureMachinePoolAutoscale{MinReplicas: 1, MaxReplicas: 3},
+			featureEnabled: true,
+			wantErr:        "pre-scaling can't be used with an autoscale setting",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachinePoolPrescaling, tc.featureEnabled)()
+			g := NewWithT(t)
+			amp := &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Prescale:  tc.prescale,
+					Autoscale: tc.autoscale,
+				},
+			}
+			err := amp.ValidatePrescale()
+			if tc.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePool_ValidateScalingSchedule(t *testing.T) {
+	tests := []struct {
+		name      string
+		schedule  []ScalingScheduleEntry
+		autoscale *AzureMachinePoolAutoscale
+		wantErr   string
+	}{
+		{
+			name: "valid scaling schedule",
+			schedule: []ScalingScheduleEntry{
+				{Name: "workday", Schedule: "0 7 * * 1-5", TimeZone: "Europe/Paris", MinReplicas: 1, MaxReplicas: 5},
+				{Name: "night", Schedule: "0 20 * * 1-5", TimeZone: "Europe/Paris", MinReplicas: 0, MaxReplicas: 0},
+			},
+		},
+		{
+			name: "duplicate names",
+			schedule: []ScalingScheduleEntry{
+				{Name: "workday", Schedule: "0 7 * * 1-5", MaxReplicas: 5},
+				{Name: "workday", Schedule: "0 20 * * 1-5"},
+			},
+			wantErr: "Duplicate value",
+		},
+		{
+			name:     "invalid schedule",
+			schedule: []ScalingScheduleEntry{{Name: "workday", Schedule: "0 7 * *", MaxReplicas: 5}},
+			wantErr:  "expected 5 fields",
+		},
+		{
+			name:     "invalid time zone",
+			schedule: []ScalingScheduleEntry{{Name: "workday", Schedule: "0 7 * * *", TimeZone: "Mars/Olympus", MaxReplicas: 5}},
+			wantErr:  "must be an IANA time zone name",
+		},
+		{
+			name:     "min replicas greater than max replicas",
+			schedule: []ScalingScheduleEntry{{Name: "workday", Schedule: "0 7 * * *", MinReplicas: 6, MaxReplicas: 5}},
+			wantErr:  "must not be greater than maxReplicas",
+		},
+		{
+			name:      "with autoscale",
+			schedule:  []ScalingScheduleEntry{{Name: "workday", Schedule: "0 7 * * *", MaxReplicas: 5}},
+			autoscale: &AzureMachinePoolAutoscale{MinReplicas: 1, MaxReplicas: 3},
+			wantErr:   "a scaling schedule can't be used with an autoscale setting",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					ScalingSchedule: tc.schedule,
+					Autoscale:       tc.autoscale,
+				},
+			}
+			err := amp.ValidateScalingSchedule()
+			if tc.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePool_ValidatePlacementFallbacks(t *testing.T) {
+	tests := []struct {
+		name      string
+		fallbacks []AzureMachinePoolPlacement
+		wantErr   string
+	}{
+		{
+			name: "no fallbacks",
+		},
+		{
+			name: "valid fallbacks",
+			fallbacks: []AzureMachinePoolPlacement{
+				{VMSize: "Standard_D4s_v4"},
+				{Zones: []string{"1"}},
+				{VMSize: "Standard_D4as_v5", Zones: []string{"2", "3"}},
+			},
+		},
+		{
+			name:      "empty fallback",
+			fallbacks: []AzureMachinePoolPlacement{{}},
+			wantErr:   "at least one of vmSize and zones must be set",
+		},
+		{
+			name: "duplicate fallbacks",
+			fallbacks: []AzureMachinePoolPlacement{
+				{VMSize: "Standard_D4s_v4", Zones: []string{"1"}},
+				{VMSize: "Standard_D4s_v4", Zones: []string{"1"}},
+			},
+			wantErr: "Duplicate value",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					PlacementFallbacks: tc.fallbacks,
+				},
+			}
+			err := amp.ValidatePlacementFallbacks()
+			if tc.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tc.wantErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestAzureMachinePool_Default(t *testing.T) {
 	// NOTE: AzureMachinePool is behind MachinePool feature gate flag; the web hook
 	// must prevent creating new objects in case the feature flag is disabled.
@@ -304,8 +540,9 @@ func TestAzureMachinePool_Default(t *testing.T) {
 	publicKeyExistTest.amp.Default()
 	g.Expect(publicKeyExistTest.amp.Spec.Template.SSHPublicKey).To(Equal(existingPublicKey))
 
+	// Machine pools without an SSH public key use the public key of the cluster.
 	publicKeyNotExistTest.amp.Default()
-	g.Expect(publicKeyNotExistTest.amp.Spec.Template.SSHPublicKey).NotTo(BeEmpty())
+	g.Expect(publicKeyNotExistTest.amp.Spec.Template.SSHPublicKey).To(BeEmpty())
 }
 
 func createMachinePoolWithMarketPlaceImage(publisher, offer, sku, version string, terminateNotificationTimeout *int) *AzureMachinePool {
@@ -364,6 +601,26 @@ func createMachinePoolWithNetworkConfig(subnetName string, interfaces []infrav1.
 	}
 }
 
+func createMachinePoolWithDataDisks(dataDisks ...infrav1.DataDisk) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				DataDisks: dataDisks,
+			},
+		},
+	}
+}
+
+func createMachinePoolWithOSDisk(osDisk infrav1.OSDisk) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				OSDisk: osDisk,
+			},
+		},
+	}
+}
+
 func createMachinePoolWithImageByID(imageID string, terminateNotificationTimeout *int) *AzureMachinePool {
 	image := infrav1.Image{
 		ID: &imageID,
@@ -92,6 +92,21 @@ type (
 		// may not be running the version of Kubernetes the Machine Pool has specified and needs to be updated.
 		LatestModelApplied bool `json:"latestModelApplied"`
 
+		// ProtectedFromScaleIn is true when the VMSS instance is protected from scale-in, so it is not selected for
+		// deletion when the AzureMachinePool is scaled down.
+		// +optional
+		ProtectedFromScaleIn bool `json:"protectedFromScaleIn,omitempty"`
+
+		// InstanceView is the health of the virtual machine instance reported by Azure: the provisioning states of its
+		// extensions and disks, and the availability of its boot diagnostics.
+		// +optional
+		InstanceView *infrav1.VMInstanceView `json:"instanceView,omitempty"`
+
+		// Addresses contains the computer name of the VMSS instance, and the private IPs, public IPs and DNS names of
+		// all the IP configurations of its network interfaces.
+		// +optional
+		Addresses []corev1.NodeAddress `json:"addresses,omitempty"`
+
 		// Ready is true when the provider resource is ready.
 		// +optional
 		Ready bool `json:"ready"`
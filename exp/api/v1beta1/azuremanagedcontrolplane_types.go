@@ -46,6 +46,11 @@ type AzureManagedControlPlaneSpec struct {
 	// +optional
 	NodeResourceGroupName string `json:"nodeResourceGroupName,omitempty"`
 
+	// NodeResourceGroupTags is an optional set of tags to add to the node resource group. AKS creates the node resource
+	// group, so its tags are reconciled once the cluster exists, and only the tags set here are updated or removed.
+	// +optional
+	NodeResourceGroupTags infrav1.Tags `json:"nodeResourceGroupTags,omitempty"`
+
 	// VirtualNetwork describes the vnet for the AKS cluster. Will be created if it does not exist.
 	// +optional
 	VirtualNetwork ManagedControlPlaneVirtualNetwork `json:"virtualNetwork,omitempty"`
@@ -93,10 +98,41 @@ type AzureManagedControlPlaneSpec struct {
 	// +optional
 	IdentityRef *corev1.ObjectReference `json:"identityRef,omitempty"`
 
+	// Identity is the identity of the AKS control plane. Defaults to a system-assigned identity.
+	// +optional
+	Identity *Identity `json:"identity,omitempty"`
+
+	// KubeletUserAssignedIdentity is the resource ID of the user-assigned identity of the kubelets, used to pull images
+	// and access Azure resources from the nodes. Requires a user-assigned control plane identity, which must be allowed
+	// to assign it. Defaults to an identity created by AKS in the node resource group.
+	// +optional
+	KubeletUserAssignedIdentity string `json:"kubeletUserAssignedIdentity,omitempty"`
+
+	// AttachedACRs are the resource IDs of the Azure Container Registries the nodes of the cluster pull images from.
+	// The AcrPull role is granted on each registry to the kubelet identity, like az aks update --attach-acr does.
+	// +optional
+	AttachedACRs []string `json:"attachedACRs,omitempty"`
+
 	// AadProfile is Azure Active Directory configuration to integrate with AKS for aad authentication.
 	// +optional
 	AADProfile *AADProfile `json:"aadProfile,omitempty"`
 
+	// DisableLocalAccounts disables the local accounts of the cluster, so it can only be accessed with AAD identities.
+	// It requires AADProfile to be set. When the local accounts are disabled, the <cluster>-kubeconfig secret used by
+	// Cluster API authenticates with an AAD token of the identity managing the cluster, given by IdentityRef, instead of
+	// the admin credentials, so that identity must be granted access to the cluster, for example through a group of
+	// AADProfile.AdminGroupObjectIDs.
+	// +optional
+	DisableLocalAccounts *bool `json:"disableLocalAccounts,omitempty"`
+
+	// UserKubeconfigCredentials is the type of the credentials of the kubeconfig written to the <cluster>-user-kubeconfig
+	// secret for the users of the cluster: Admin for the credentials of the local admin account, or User for the
+	// credentials of whoever runs kubectl, which require kubelogin when AAD is enabled. Defaults to User when
+	// DisableLocalAccounts is true, Admin otherwise. Admin can't be set when DisableLocalAccounts is true.
+	// +kubebuilder:validation:Enum=Admin;User
+	// +optional
+	UserKubeconfigCredentials *KubeconfigCredentialsType `json:"userKubeconfigCredentials,omitempty"`
+
 	// AddonProfiles are the profiles of managed cluster add-on.
 	// +optional
 	AddonProfiles []AddonProfile `json:"addonProfiles,omitempty"`
@@ -109,9 +145,75 @@ type AzureManagedControlPlaneSpec struct {
 	// +optional
 	LoadBalancerProfile *LoadBalancerProfile `json:"loadBalancerProfile,omitempty"`
 
+	// OutboundType is how the egress traffic of the nodes leaves the cluster: through the load balancer of the cluster,
+	// a NAT gateway created by AKS, a NAT gateway associated to the subnet of the nodes, or the routes of the route
+	// table associated to the subnet of the nodes. Defaults to loadBalancer. Immutable.
+	// +kubebuilder:validation:Enum=loadBalancer;managedNATGateway;userAssignedNATGateway;userDefinedRouting
+	// +optional
+	OutboundType *ManagedControlPlaneOutboundType `json:"outboundType,omitempty"`
+
+	// NatGatewayProfile is the profile of the NAT gateway created by AKS when OutboundType is managedNATGateway.
+	// +optional
+	NatGatewayProfile *NatGatewayProfile `json:"natGatewayProfile,omitempty"`
+
 	// APIServerAccessProfile is the access profile for AKS API server.
 	// +optional
 	APIServerAccessProfile *APIServerAccessProfile `json:"apiServerAccessProfile,omitempty"`
+
+	// Monitoring onboards the cluster to Container Insights: the omsagent add-on is enabled and sends the metrics and
+	// the logs of the cluster to a Log Analytics workspace.
+	// +optional
+	Monitoring *infrav1.AzureMonitoring `json:"monitoring,omitempty"`
+
+	// SecurityProfile specifies the security settings of the cluster. Enabling Defender turns on Microsoft Defender
+	// for Containers, which requires either Monitoring or a Log Analytics workspace for Defender.
+	// +optional
+	SecurityProfile *infrav1.ClusterSecurityProfile `json:"securityProfile,omitempty"`
+
+	// WorkloadAutoScalerProfile enables the AKS-managed components scaling the workloads of the cluster.
+	// +optional
+	WorkloadAutoScalerProfile *WorkloadAutoScalerProfile `json:"workloadAutoScalerProfile,omitempty"`
+
+	// ImageCleaner periodically removes the unused images with known vulnerabilities from the nodes of the cluster.
+	// +optional
+	ImageCleaner *ImageCleaner `json:"imageCleaner,omitempty"`
+
+	// FleetsMember registers the cluster as a member of an Azure Kubernetes Fleet Manager hub, so that the update runs
+	// and the placement policies of the fleet apply to it.
+	// +optional
+	FleetsMember *FleetsMember `json:"fleetsMember,omitempty"`
+
+	// HTTPProxyConfig configures the HTTP proxy servers the nodes of the cluster use to reach the internet, for clusters
+	// behind a corporate proxy. Immutable.
+	// +optional
+	HTTPProxyConfig *HTTPProxyConfig `json:"httpProxyConfig,omitempty"`
+}
+
+// WorkloadAutoScalerProfile - AKS-managed components scaling the workloads of the cluster. Each of them is
+// uninstalled when disabled.
+type WorkloadAutoScalerProfile struct {
+	// Keda - Whether to enable KEDA, the Kubernetes event-driven autoscaler, which scales the workloads according to
+	// the events of external sources such as queues.
+	// +optional
+	Keda bool `json:"keda,omitempty"`
+
+	// VerticalPodAutoscaler - Whether to enable the vertical pod autoscaler, which adjusts the resource requests of
+	// the pods according to their usage.
+	// +optional
+	VerticalPodAutoscaler bool `json:"verticalPodAutoscaler,omitempty"`
+}
+
+// ImageCleaner - Removal of the unused images with known vulnerabilities from the nodes of the cluster.
+type ImageCleaner struct {
+	// Enabled - Whether the image cleaner runs on the nodes of the cluster. Disabling it uninstalls it.
+	Enabled bool `json:"enabled"`
+
+	// IntervalHours - Interval between two scans of the images of the nodes, in hours. Defaults to 168, a week. It can
+	// only be set when Enabled is true.
+	// +kubebuilder:validation:Minimum=24
+	// +kubebuilder:validation:Maximum=2160
+	// +optional
+	IntervalHours *int32 `json:"intervalHours,omitempty"`
 }
 
 // AADProfile - AAD integration managed by AKS.
@@ -125,6 +227,91 @@ type AADProfile struct {
 	AdminGroupObjectIDs []string `json:"adminGroupObjectIDs"`
 }
 
+// ManagedControlPlaneIdentityType - Type of the identity of the AKS control plane.
+type ManagedControlPlaneIdentityType string
+
+const (
+	// ManagedControlPlaneIdentityTypeSystemAssigned is an identity created by AKS and deleted with the cluster.
+	ManagedControlPlaneIdentityTypeSystemAssigned ManagedControlPlaneIdentityType = "SystemAssigned"
+	// ManagedControlPlaneIdentityTypeUserAssigned is an existing identity, which can be granted permissions before the
+	// cluster is created.
+	ManagedControlPlaneIdentityTypeUserAssigned ManagedControlPlaneIdentityType = "UserAssigned"
+)
+
+// KubeconfigCredentialsType - Type of the credentials of a kubeconfig of an AKS cluster.
+type KubeconfigCredentialsType string
+
+const (
+	// KubeconfigCredentialsTypeAdmin is the client certificate of the local admin account of the cluster.
+	KubeconfigCredentialsTypeAdmin KubeconfigCredentialsType = "Admin"
+	// KubeconfigCredentialsTypeUser is the credentials of the user running kubectl: a kubelogin exec plugin when AAD is
+	// enabled, the client certificate of the local user account otherwise.
+	KubeconfigCredentialsTypeUser KubeconfigCredentialsType = "User"
+)
+
+// ManagedControlPlaneOutboundType - Routing method of the egress traffic of an AKS cluster.
+type ManagedControlPlaneOutboundType string
+
+const (
+	// ManagedControlPlaneOutboundTypeLoadBalancer routes the egress traffic through the load balancer of the cluster.
+	ManagedControlPlaneOutboundTypeLoadBalancer ManagedControlPlaneOutboundType = "loadBalancer"
+	// ManagedControlPlaneOutboundTypeManagedNATGateway routes the egress traffic through a NAT gateway created by AKS.
+	ManagedControlPlaneOutboundTypeManagedNATGateway ManagedControlPlaneOutboundType = "managedNATGateway"
+	// ManagedControlPlaneOutboundTypeUserAssignedNATGateway routes the egress traffic through the NAT gateway
+	// associated to the subnet of the nodes.
+	ManagedControlPlaneOutboundTypeUserAssignedNATGateway ManagedControlPlaneOutboundType = "userAssignedNATGateway"
+	// ManagedControlPlaneOutboundTypeUserDefinedRouting routes the egress traffic following the routes of the route
+	// table associated to the subnet of the nodes, e.g. to a firewall.
+	ManagedControlPlaneOutboundTypeUserDefinedRouting ManagedControlPlaneOutboundType = "userDefinedRouting"
+)
+
+// Identity - Identity of the AKS control plane.
+type Identity struct {
+	// Type - The type of identity of the control plane.
+	// +kubebuilder:validation:Enum=SystemAssigned;UserAssigned
+	Type ManagedControlPlaneIdentityType `json:"type"`
+
+	// UserAssignedIdentityResourceID - The resource ID of the user-assigned identity of the control plane. Required
+	// when Type is UserAssigned.
+	// +optional
+	UserAssignedIdentityResourceID string `json:"userAssignedIdentityResourceID,omitempty"`
+}
+
+// FleetsMember - Membership of the cluster in an Azure Kubernetes Fleet Manager hub.
+type FleetsMember struct {
+	// FleetResourceID - The resource ID of the fleet the cluster joins.
+	FleetResourceID string `json:"fleetResourceID"`
+
+	// Name - The name of the member in the fleet. Defaults to the name of the cluster.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Group - The update group of the member, used by the update runs of the fleet to stage upgrades.
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// HTTPProxyConfig - HTTP proxy configuration of the nodes of the cluster.
+type HTTPProxyConfig struct {
+	// HTTPProxy - The endpoint of the proxy server for HTTP requests, e.g. "http://proxy.example.com:3128/".
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy - The endpoint of the proxy server for HTTPS requests.
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy - The hosts, domains and CIDR blocks which are reached without going through the proxy servers. AKS adds
+	// the addresses the cluster needs itself, like the ones of the API server and of the cluster networks.
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+
+	// TrustedCA - References a key of a Secret, in the namespace of the control plane, holding the PEM encoded CA
+	// certificate the nodes trust to connect to the proxy servers. The Secret is only read when the cluster is created.
+	// +optional
+	TrustedCA *infrav1.CACertificatesReference `json:"trustedCA,omitempty"`
+}
+
 type AddonProfile struct {
 	// Name- The name of managed cluster add-on.
 	Name string `json:"name"`
@@ -173,7 +360,7 @@ type LoadBalancerProfile struct {
 	// +optional
 	OutboundIPs []string `json:"outboundIPs,omitempty"`
 
-	// AllocatedOutboundPorts - Desired number of allocated SNAT ports per VM. Allowed values must be in the range of 0 to 64000 (inclusive). The default value is 0 which results in Azure dynamically allocating ports.
+	// AllocatedOutboundPorts - Desired number of allocated SNAT ports per VM. Allowed values must be in the range of 0 to 64000 (inclusive) and a multiple of 8. The default value is 0 which results in Azure dynamically allocating ports.
 	// +optional
 	AllocatedOutboundPorts *int32 `json:"allocatedOutboundPorts,omitempty"`
 
@@ -182,6 +369,19 @@ type LoadBalancerProfile struct {
 	IdleTimeoutInMinutes *int32 `json:"idleTimeoutInMinutes,omitempty"`
 }
 
+// NatGatewayProfile - Profile of the NAT gateway created by AKS for the egress traffic of the cluster.
+type NatGatewayProfile struct {
+	// ManagedOutboundIPs - Desired number of outbound public IPs created by AKS for the NAT gateway. Allowed values must
+	// be in the range of 1 to 16 (inclusive). The default value is 1.
+	// +optional
+	ManagedOutboundIPs *int32 `json:"managedOutboundIPs,omitempty"`
+
+	// IdleTimeoutInMinutes - Desired outbound flow idle timeout in minutes. Allowed values must be in the range of 4 to
+	// 120 (inclusive). The default value is 4 minutes.
+	// +optional
+	IdleTimeoutInMinutes *int32 `json:"idleTimeoutInMinutes,omitempty"`
+}
+
 // APIServerAccessProfile - access profile for AKS API server.
 type APIServerAccessProfile struct {
 	// AuthorizedIPRanges - Authorized IP Ranges to kubernetes API server.
@@ -197,6 +397,16 @@ type APIServerAccessProfile struct {
 	// EnablePrivateClusterPublicFQDN - Whether to create additional public FQDN for private cluster or not.
 	// +optional
 	EnablePrivateClusterPublicFQDN *bool `json:"enablePrivateClusterPublicFQDN,omitempty"`
+	// EnableVnetIntegration - Whether to project the API server into Subnet, so that the nodes reach it through a
+	// private IP of the virtual network of the cluster rather than a tunnel. Requires Subnet and a user-assigned
+	// control plane identity. Immutable.
+	// +optional
+	EnableVnetIntegration *bool `json:"enableVnetIntegration,omitempty"`
+	// Subnet - Subnet of the virtual network of the cluster the API server is projected into when
+	// EnableVnetIntegration is true. It is created with the node subnet and delegated to AKS, so it must not be used
+	// by anything else. It must be at least a /28. Immutable.
+	// +optional
+	Subnet *ManagedControlPlaneSubnet `json:"subnet,omitempty"`
 }
 
 // ManagedControlPlaneVirtualNetwork describes a virtual network required to provision AKS clusters.
@@ -233,6 +443,49 @@ type AzureManagedControlPlaneStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates infrav1.Futures `json:"longRunningOperationStates,omitempty"`
+
+	// ManagedCluster is the state of the AKS cluster reported by Azure at the last reconciliation.
+	// +optional
+	ManagedCluster *ManagedClusterStatus `json:"managedCluster,omitempty"`
+}
+
+// ManagedClusterStatus defines the state of an AKS cluster reported by Azure.
+type ManagedClusterStatus struct {
+	// ProvisioningState is the provisioning state of the cluster.
+	// +optional
+	ProvisioningState string `json:"provisioningState,omitempty"`
+
+	// PowerState is the power state of the cluster, Running or Stopped.
+	// +optional
+	PowerState string `json:"powerState,omitempty"`
+
+	// KubernetesVersion is the Kubernetes version of the control plane.
+	// +optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// FQDN is the FQDN of the API server.
+	// +optional
+	FQDN string `json:"fqdn,omitempty"`
+
+	// PrivateFQDN is the FQDN of the API server in the private DNS zone of a private cluster.
+	// +optional
+	PrivateFQDN string `json:"privateFQDN,omitempty"`
+
+	// IdentityPrincipalID is the principal ID of the system-assigned identity of the control plane.
+	// +optional
+	IdentityPrincipalID string `json:"identityPrincipalID,omitempty"`
+
+	// KubeletIdentityClientID is the client ID of the identity of the kubelets.
+	// +optional
+	KubeletIdentityClientID string `json:"kubeletIdentityClientID,omitempty"`
+
+	// KubeletIdentityObjectID is the object ID of the identity of the kubelets.
+	// +optional
+	KubeletIdentityObjectID string `json:"kubeletIdentityObjectID,omitempty"`
+
+	// AgentPools are the states of the agent pools of the cluster.
+	// +optional
+	AgentPools []AgentPoolStatus `json:"agentPools,omitempty"`
 }
 
 // +kubebuilder:object:root=true
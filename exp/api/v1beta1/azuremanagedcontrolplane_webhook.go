@@ -21,10 +21,12 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"reflect"
 	"regexp"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -35,7 +37,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-var kubeSemver = regexp.MustCompile(`^v(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)([-0-9a-zA-Z_\.+]*)?$`)
+var (
+	kubeSemver             = regexp.MustCompile(`^v(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)([-0-9a-zA-Z_\.+]*)?$`)
+	nodeResourceGroupRegex = regexp.MustCompile(`^[-\w\._\(\)]*[-\w_\(\)]$`)
+)
+
+// maxNodeResourceGroupNameLength is the maximum length of the name of the node resource group of an AKS cluster.
+const maxNodeResourceGroupNameLength = 80
 
 // SetupWebhookWithManager sets up and registers the webhook with the manager.
 func (m *AzureManagedControlPlane) SetupWebhookWithManager(mgr ctrl.Manager) error {
@@ -80,7 +88,8 @@ func (m *AzureManagedControlPlane) Default(_ client.Client) {
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
 func (m *AzureManagedControlPlane) ValidateCreate(client client.Client) error {
-	return m.Validate(client)
+	// The subscription and the node resource group are immutable, so they are only validated for new control planes.
+	return m.validate(client, m.validateSubscriptionID, m.validateNodeResourceGroupName)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
@@ -232,6 +241,42 @@ func (m *AzureManagedControlPlane) ValidateUpdate(oldRaw runtime.Object, client
 		}
 	}
 
+	if !reflect.DeepEqual(m.Spec.Identity, old.Spec.Identity) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "Identity"),
+				m.Spec.Identity,
+				"field is immutable"))
+	}
+
+	if m.Spec.KubeletUserAssignedIdentity != old.Spec.KubeletUserAssignedIdentity {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "KubeletUserAssignedIdentity"),
+				m.Spec.KubeletUserAssignedIdentity,
+				"field is immutable"))
+	}
+
+	if m.outboundType() != old.outboundType() {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "OutboundType"),
+				m.outboundType(),
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(m.Spec.HTTPProxyConfig, old.Spec.HTTPProxyConfig) {
+		// The HTTP proxy configuration isn't echoed back, as it may hold the credentials of the proxy servers.
+		allErrs = append(allErrs,
+			field.Forbidden(
+				field.NewPath("Spec", "HTTPProxyConfig"),
+				"field is immutable"))
+	}
+
+	if errs := m.validateFleetsMemberUpdate(old); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	if errs := m.validateAPIServerAccessProfileUpdate(old); len(errs) > 0 {
 		allErrs = append(allErrs, errs...)
 	}
@@ -250,6 +295,11 @@ func (m *AzureManagedControlPlane) ValidateDelete(_ client.Client) error {
 
 // Validate the Azure Machine Pool and return an aggregate error.
 func (m *AzureManagedControlPlane) Validate(cli client.Client) error {
+	return m.validate(cli)
+}
+
+// validate runs the validators of the control plane followed by the given extra validators.
+func (m *AzureManagedControlPlane) validate(cli client.Client, extraValidators ...func(client client.Client) error) error {
 	validators := []func(client client.Client) error{
 		m.validateVersion,
 		m.validateDNSServiceIP,
@@ -257,7 +307,17 @@ func (m *AzureManagedControlPlane) Validate(cli client.Client) error {
 		m.validateLoadBalancerProfile,
 		m.validateAPIServerAccessProfile,
 		m.validateManagedClusterNetwork,
-	}
+		m.validateMonitoring,
+		m.validateSecurityProfile,
+		m.validateImageCleaner,
+		m.validateIdentity,
+		m.validateAttachedACRs,
+		m.validateFleetsMember,
+		m.validateHTTPProxyConfig,
+		m.validateOutboundType,
+		m.validateDisableLocalAccounts,
+	}
+	validators = append(validators, extraValidators...)
 
 	var errs []error
 	for _, validator := range validators {
@@ -269,6 +329,35 @@ func (m *AzureManagedControlPlane) Validate(cli client.Client) error {
 	return kerrors.NewAggregate(errs)
 }
 
+// validateSubscriptionID validates the subscription is set when the control plane uses an identity.
+func (m *AzureManagedControlPlane) validateSubscriptionID(_ client.Client) error {
+	if allErrs := infrav1.ValidateSubscriptionID(m.Spec.SubscriptionID, m.Spec.IdentityRef, field.NewPath("Spec", "SubscriptionID")); len(allErrs) > 0 {
+		return allErrs.ToAggregate()
+	}
+	return nil
+}
+
+// validateNodeResourceGroupName validates the name of the node resource group, which AKS creates along with the cluster.
+func (m *AzureManagedControlPlane) validateNodeResourceGroupName(_ client.Client) error {
+	fldPath := field.NewPath("Spec", "NodeResourceGroupName")
+	name := m.Spec.NodeResourceGroupName
+	if name == "" {
+		// The name is defaulted from the names of the resource group and the cluster.
+		return nil
+	}
+	if len(name) > maxNodeResourceGroupNameLength {
+		return field.TooLong(fldPath, name, maxNodeResourceGroupNameLength)
+	}
+	if !nodeResourceGroupRegex.MatchString(name) {
+		return field.Invalid(fldPath, name, fmt.Sprintf("must match regex %s", nodeResourceGroupRegex))
+	}
+	if strings.EqualFold(name, m.Spec.ResourceGroupName) {
+		return field.Invalid(fldPath, name, "must be different from the resource group of the cluster")
+	}
+
+	return nil
+}
+
 // validateDNSServiceIP validates the DNSServiceIP.
 func (m *AzureManagedControlPlane) validateDNSServiceIP(_ client.Client) error {
 	if m.Spec.DNSServiceIP != nil {
@@ -317,6 +406,8 @@ func (m *AzureManagedControlPlane) validateLoadBalancerProfile(_ client.Client)
 		if m.Spec.LoadBalancerProfile.AllocatedOutboundPorts != nil {
 			if *m.Spec.LoadBalancerProfile.AllocatedOutboundPorts < 0 || *m.Spec.LoadBalancerProfile.AllocatedOutboundPorts > 64000 {
 				allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "LoadBalancerProfile", "AllocatedOutboundPorts"), *m.Spec.LoadBalancerProfile.AllocatedOutboundPorts, "value should be in between 0 and 64000"))
+			} else if *m.Spec.LoadBalancerProfile.AllocatedOutboundPorts%8 != 0 {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "LoadBalancerProfile", "AllocatedOutboundPorts"), *m.Spec.LoadBalancerProfile.AllocatedOutboundPorts, "value should be a multiple of 8"))
 			}
 		}
 
@@ -359,6 +450,7 @@ func (m *AzureManagedControlPlane) validateAPIServerAccessProfile(_ client.Clien
 				allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "APIServerAccessProfile", "AuthorizedIPRanges"), ipRange, "invalid CIDR format"))
 			}
 		}
+		allErrs = append(allErrs, m.validateAPIServerVnetIntegration()...)
 		if len(allErrs) > 0 {
 			return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
 		}
@@ -366,6 +458,317 @@ func (m *AzureManagedControlPlane) validateAPIServerAccessProfile(_ client.Clien
 	return nil
 }
 
+// validateAPIServerVnetIntegration validates the subnet the API server is projected into. AKS can only be granted
+// access to the subnet before the cluster is created through a user-assigned identity.
+func (m *AzureManagedControlPlane) validateAPIServerVnetIntegration() field.ErrorList {
+	var allErrs field.ErrorList
+
+	profile := m.Spec.APIServerAccessProfile
+	fldPath := field.NewPath("Spec", "APIServerAccessProfile")
+	enabled := profile.EnableVnetIntegration != nil && *profile.EnableVnetIntegration
+	if !enabled {
+		if profile.Subnet != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("Subnet"), "can be set only when Spec.APIServerAccessProfile.EnableVnetIntegration is true"))
+		}
+		return allErrs
+	}
+
+	if m.Spec.Identity == nil || m.Spec.Identity.Type != ManagedControlPlaneIdentityTypeUserAssigned {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("EnableVnetIntegration"), "requires Spec.Identity.Type to be UserAssigned"))
+	}
+
+	subnet := profile.Subnet
+	if subnet == nil {
+		return append(allErrs, field.Required(fldPath.Child("Subnet"), "must be set when Spec.APIServerAccessProfile.EnableVnetIntegration is true"))
+	}
+	if subnet.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("Subnet", "Name"), "must be set"))
+	} else if subnet.Name == m.Spec.VirtualNetwork.Subnet.Name {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("Subnet", "Name"), subnet.Name, "must differ from Spec.VirtualNetwork.Subnet.Name"))
+	}
+
+	_, cidr, err := net.ParseCIDR(subnet.CIDRBlock)
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath.Child("Subnet", "CIDRBlock"), subnet.CIDRBlock, "invalid CIDR format"))
+	}
+	if ones, _ := cidr.Mask.Size(); ones > 28 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("Subnet", "CIDRBlock"), subnet.CIDRBlock, "must be at least a /28"))
+	}
+	if _, vnetCIDR, err := net.ParseCIDR(m.Spec.VirtualNetwork.CIDRBlock); err == nil && !cidrContains(vnetCIDR, cidr) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("Subnet", "CIDRBlock"), subnet.CIDRBlock, "must be within Spec.VirtualNetwork.CIDRBlock"))
+	}
+	if _, nodeCIDR, err := net.ParseCIDR(m.Spec.VirtualNetwork.Subnet.CIDRBlock); err == nil && (nodeCIDR.Contains(cidr.IP) || cidr.Contains(nodeCIDR.IP)) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("Subnet", "CIDRBlock"), subnet.CIDRBlock, "must not overlap Spec.VirtualNetwork.Subnet.CIDRBlock"))
+	}
+
+	return allErrs
+}
+
+// cidrContains returns true if the inner CIDR is within the outer CIDR.
+func cidrContains(outer, inner *net.IPNet) bool {
+	outerOnes, _ := outer.Mask.Size()
+	innerOnes, _ := inner.Mask.Size()
+	return outer.Contains(inner.IP) && innerOnes >= outerOnes
+}
+
+// validateMonitoring validates the Container Insights onboarding of the cluster.
+func (m *AzureManagedControlPlane) validateMonitoring(_ client.Client) error {
+	if errs := infrav1.ValidateMonitoring(m.Spec.Monitoring, field.NewPath("Spec", "Monitoring")); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// validateSecurityProfile validates the Defender for Containers settings of the cluster. Just-in-time network access
+// only protects virtual machines, so it isn't supported by the scale sets of the node pools.
+func (m *AzureManagedControlPlane) validateSecurityProfile(_ client.Client) error {
+	if m.Spec.SecurityProfile == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if m.Spec.SecurityProfile.JustInTimeAccess != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("Spec", "SecurityProfile", "JustInTimeAccess"),
+			"is not supported by managed clusters"))
+	}
+	if m.Spec.SecurityProfile.Defender != nil {
+		fldPath := field.NewPath("Spec", "SecurityProfile", "Defender")
+		if id := m.Spec.SecurityProfile.Defender.LogAnalyticsWorkspaceID; id != "" {
+			allErrs = append(allErrs, infrav1.ValidateLogAnalyticsWorkspaceID(id, fldPath.Child("LogAnalyticsWorkspaceID"))...)
+		} else if m.Spec.Monitoring == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("LogAnalyticsWorkspaceID"), "must be set when Spec.Monitoring is not"))
+		}
+	}
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// validateImageCleaner validates that the scan interval of the image cleaner is only set when it is enabled.
+func (m *AzureManagedControlPlane) validateImageCleaner(_ client.Client) error {
+	if imageCleaner := m.Spec.ImageCleaner; imageCleaner != nil && !imageCleaner.Enabled && imageCleaner.IntervalHours != nil {
+		return field.Forbidden(field.NewPath("Spec", "ImageCleaner", "IntervalHours"), "can only be set when Spec.ImageCleaner.Enabled is true")
+	}
+
+	return nil
+}
+
+// validateIdentity validates the control plane and kubelet identities of the cluster.
+func (m *AzureManagedControlPlane) validateIdentity(_ client.Client) error {
+	var allErrs field.ErrorList
+
+	userAssigned := false
+	if identity := m.Spec.Identity; identity != nil {
+		fldPath := field.NewPath("Spec", "Identity", "UserAssignedIdentityResourceID")
+		switch {
+		case identity.Type == ManagedControlPlaneIdentityTypeUserAssigned && identity.UserAssignedIdentityResourceID == "":
+			allErrs = append(allErrs, field.Required(fldPath, "must be set when Spec.Identity.Type is UserAssigned"))
+		case identity.Type == ManagedControlPlaneIdentityTypeUserAssigned:
+			userAssigned = true
+			allErrs = append(allErrs, validateUserAssignedIdentityResourceID(identity.UserAssignedIdentityResourceID, fldPath)...)
+		case identity.UserAssignedIdentityResourceID != "":
+			allErrs = append(allErrs, field.Forbidden(fldPath, "can be set only when Spec.Identity.Type is UserAssigned"))
+		}
+	}
+
+	if id := m.Spec.KubeletUserAssignedIdentity; id != "" {
+		fldPath := field.NewPath("Spec", "KubeletUserAssignedIdentity")
+		if !userAssigned {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "requires Spec.Identity.Type to be UserAssigned"))
+		}
+		allErrs = append(allErrs, validateUserAssignedIdentityResourceID(id, fldPath)...)
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// validateAttachedACRs validates the Azure Container Registries attached to the cluster.
+func (m *AzureManagedControlPlane) validateAttachedACRs(_ client.Client) error {
+	if errs := infrav1.ValidateAttachedACRs(m.Spec.AttachedACRs, field.NewPath("Spec", "AttachedACRs")); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// validateDisableLocalAccounts validates that the local accounts of the cluster are disabled only with AAD integration,
+// and that the user kubeconfig doesn't rely on them then.
+func (m *AzureManagedControlPlane) validateDisableLocalAccounts(_ client.Client) error {
+	if m.Spec.DisableLocalAccounts == nil || !*m.Spec.DisableLocalAccounts {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if m.Spec.AADProfile == nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("Spec", "DisableLocalAccounts"), "requires Spec.AADProfile to be set"))
+	}
+	if credentials := m.Spec.UserKubeconfigCredentials; credentials != nil && *credentials == KubeconfigCredentialsTypeAdmin {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("Spec", "UserKubeconfigCredentials"),
+			"can't be Admin when Spec.DisableLocalAccounts is true, as the admin credentials are disabled"))
+	}
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// validateFleetsMember validates the fleet the cluster joins.
+func (m *AzureManagedControlPlane) validateFleetsMember(_ client.Client) error {
+	if m.Spec.FleetsMember == nil {
+		return nil
+	}
+
+	id := m.Spec.FleetsMember.FleetResourceID
+	resource, err := arm.ParseResourceID(id)
+	if err != nil || !strings.EqualFold(resource.ResourceType.Namespace, "Microsoft.ContainerService") || !strings.EqualFold(resource.ResourceType.Type, "fleets") {
+		return field.Invalid(field.NewPath("Spec", "FleetsMember", "FleetResourceID"), id, "must be the resource ID of a fleet")
+	}
+
+	return nil
+}
+
+// outboundType returns the outbound type of the cluster, which defaults to loadBalancer.
+func (m *AzureManagedControlPlane) outboundType() ManagedControlPlaneOutboundType {
+	if m.Spec.OutboundType == nil {
+		return ManagedControlPlaneOutboundTypeLoadBalancer
+	}
+	return *m.Spec.OutboundType
+}
+
+// validateOutboundType validates the outbound type of the cluster, and the profile of the managed NAT gateway.
+func (m *AzureManagedControlPlane) validateOutboundType(_ client.Client) error {
+	var allErrs field.ErrorList
+
+	outboundType := m.outboundType()
+	if outboundType != ManagedControlPlaneOutboundTypeLoadBalancer && m.Spec.LoadBalancerProfile != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("Spec", "LoadBalancerProfile"), "requires Spec.OutboundType to be loadBalancer"))
+	}
+	if (outboundType == ManagedControlPlaneOutboundTypeManagedNATGateway || outboundType == ManagedControlPlaneOutboundTypeUserAssignedNATGateway) &&
+		m.Spec.LoadBalancerSKU != nil && *m.Spec.LoadBalancerSKU != "Standard" {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("Spec", "OutboundType"), "requires Spec.LoadBalancerSKU to be Standard"))
+	}
+
+	if profile := m.Spec.NatGatewayProfile; profile != nil {
+		fldPath := field.NewPath("Spec", "NatGatewayProfile")
+		if outboundType != ManagedControlPlaneOutboundTypeManagedNATGateway {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "requires Spec.OutboundType to be managedNATGateway"))
+		}
+		if profile.ManagedOutboundIPs != nil && (*profile.ManagedOutboundIPs < 1 || *profile.ManagedOutboundIPs > 16) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ManagedOutboundIPs"), *profile.ManagedOutboundIPs, "value should be in between 1 and 16"))
+		}
+		if profile.IdleTimeoutInMinutes != nil && (*profile.IdleTimeoutInMinutes < 4 || *profile.IdleTimeoutInMinutes > 120) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("IdleTimeoutInMinutes"), *profile.IdleTimeoutInMinutes, "value should be in between 4 and 120"))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// validateHTTPProxyConfig validates the HTTP proxy configuration of the nodes.
+func (m *AzureManagedControlPlane) validateHTTPProxyConfig(_ client.Client) error {
+	httpProxyConfig := m.Spec.HTTPProxyConfig
+	if httpProxyConfig == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "HTTPProxyConfig")
+	if httpProxyConfig.HTTPProxy == "" && httpProxyConfig.HTTPSProxy == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of HTTPProxy or HTTPSProxy must be set"))
+	}
+	allErrs = append(allErrs, validateProxyEndpoint(httpProxyConfig.HTTPProxy, fldPath.Child("HTTPProxy"))...)
+	allErrs = append(allErrs, validateProxyEndpoint(httpProxyConfig.HTTPSProxy, fldPath.Child("HTTPSProxy"))...)
+	for i, noProxy := range httpProxyConfig.NoProxy {
+		if strings.TrimSpace(noProxy) == "" || strings.Contains(noProxy, ",") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("NoProxy").Index(i), noProxy, "must be a single, non-empty host, domain or CIDR block"))
+		}
+	}
+	if trustedCA := httpProxyConfig.TrustedCA; trustedCA != nil && trustedCA.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("TrustedCA", "Name"), "the name of the Secret must be set"))
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// validateProxyEndpoint validates the endpoint of a proxy server, if set.
+func validateProxyEndpoint(endpoint string, fldPath *field.Path) field.ErrorList {
+	if endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		// The endpoint isn't echoed back, as it may hold the credentials of the proxy server.
+		return field.ErrorList{field.Invalid(fldPath, "", "must be an http or https URL")}
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return field.ErrorList{field.Invalid(fldPath, u.Redacted(), "must be an http or https URL")}
+	}
+	return nil
+}
+
+// validateFleetsMemberUpdate validates a FleetsMember update.
+func (m *AzureManagedControlPlane) validateFleetsMemberUpdate(old *AzureManagedControlPlane) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// A cluster can join a fleet after its creation, but it can't leave it or move to another fleet.
+	if old.Spec.FleetsMember == nil {
+		return allErrs
+	}
+
+	if m.Spec.FleetsMember == nil {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "FleetsMember"),
+				m.Spec.FleetsMember,
+				"field cannot be removed"))
+		return allErrs
+	}
+
+	if m.Spec.FleetsMember.FleetResourceID != old.Spec.FleetsMember.FleetResourceID {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "FleetsMember", "FleetResourceID"),
+				m.Spec.FleetsMember.FleetResourceID,
+				"field is immutable"))
+	}
+
+	if m.Spec.FleetsMember.Name != old.Spec.FleetsMember.Name {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "FleetsMember", "Name"),
+				m.Spec.FleetsMember.Name,
+				"field is immutable"))
+	}
+
+	return allErrs
+}
+
+// validateUserAssignedIdentityResourceID validates the resource ID of a user-assigned identity.
+func validateUserAssignedIdentityResourceID(id string, fldPath *field.Path) field.ErrorList {
+	resource, err := arm.ParseResourceID(id)
+	if err != nil || !strings.EqualFold(resource.ResourceType.Namespace, "Microsoft.ManagedIdentity") || !strings.EqualFold(resource.ResourceType.Type, "userAssignedIdentities") {
+		return field.ErrorList{field.Invalid(fldPath, id, "must be the resource ID of a user-assigned identity")}
+	}
+
+	return nil
+}
+
 // validateManagedClusterNetwork validates the Cluster network values.
 func (m *AzureManagedControlPlane) validateManagedClusterNetwork(cli client.Client) error {
 	ctx := context.Background()
@@ -442,6 +845,8 @@ func (m *AzureManagedControlPlane) validateAPIServerAccessProfileUpdate(old *Azu
 			EnablePrivateCluster:           m.Spec.APIServerAccessProfile.EnablePrivateCluster,
 			PrivateDNSZone:                 m.Spec.APIServerAccessProfile.PrivateDNSZone,
 			EnablePrivateClusterPublicFQDN: m.Spec.APIServerAccessProfile.EnablePrivateClusterPublicFQDN,
+			EnableVnetIntegration:          m.Spec.APIServerAccessProfile.EnableVnetIntegration,
+			Subnet:                         m.Spec.APIServerAccessProfile.Subnet,
 		}
 	}
 	if old.Spec.APIServerAccessProfile != nil {
@@ -449,6 +854,8 @@ func (m *AzureManagedControlPlane) validateAPIServerAccessProfileUpdate(old *Azu
 			EnablePrivateCluster:           old.Spec.APIServerAccessProfile.EnablePrivateCluster,
 			PrivateDNSZone:                 old.Spec.APIServerAccessProfile.PrivateDNSZone,
 			EnablePrivateClusterPublicFQDN: old.Spec.APIServerAccessProfile.EnablePrivateClusterPublicFQDN,
+			EnableVnetIntegration:          old.Spec.APIServerAccessProfile.EnableVnetIntegration,
+			Subnet:                         old.Spec.APIServerAccessProfile.Subnet,
 		}
 	}
 
@@ -17,12 +17,15 @@ limitations under the License.
 package v1beta1
 
 import (
+	"strings"
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 )
 
 func TestDefaultingWebhook(t *testing.T) {
@@ -163,9 +166,9 @@ func TestValidatingWebhook(t *testing.T) {
 				Spec: AzureManagedControlPlaneSpec{
 					Version: "v1.21.2",
 					LoadBalancerProfile: &LoadBalancerProfile{
-						ManagedOutboundIPs:     to.Int32Ptr(10),
-						AllocatedOutboundPorts: to.Int32Ptr(1000),
-						IdleTimeoutInMinutes:   to.Int32Ptr(60),
+						ManagedOutboundIPs:     to.Ptr[int32](10),
+						AllocatedOutboundPorts: to.Ptr[int32](1000),
+						IdleTimeoutInMinutes:   to.Ptr[int32](60),
 					},
 				},
 			},
@@ -177,7 +180,7 @@ func TestValidatingWebhook(t *testing.T) {
 				Spec: AzureManagedControlPlaneSpec{
 					Version: "v1.21.2",
 					LoadBalancerProfile: &LoadBalancerProfile{
-						ManagedOutboundIPs: to.Int32Ptr(200),
+						ManagedOutboundIPs: to.Ptr[int32](200),
 					},
 				},
 			},
@@ -189,49 +192,478 @@ func TestValidatingWebhook(t *testing.T) {
 				Spec: AzureManagedControlPlaneSpec{
 					Version: "v1.21.2",
 					LoadBalancerProfile: &LoadBalancerProfile{
-						AllocatedOutboundPorts: to.Int32Ptr(80000),
+						AllocatedOutboundPorts: to.Ptr[int32](80000),
 					},
 				},
 			},
 			expectErr: true,
 		},
+		{
+			name: "LoadBalancerProfile.AllocatedOutboundPorts not a multiple of 8",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					LoadBalancerProfile: &LoadBalancerProfile{
+						AllocatedOutboundPorts: to.Ptr[int32](100),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "DisableLocalAccounts without AADProfile",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:              "v1.21.2",
+					DisableLocalAccounts: to.Ptr(true),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "DisableLocalAccounts with AADProfile",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:              "v1.21.2",
+					DisableLocalAccounts: to.Ptr(true),
+					AADProfile: &AADProfile{
+						Managed:             true,
+						AdminGroupObjectIDs: []string{"00000000-0000-0000-0000-000000000000"},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "DisableLocalAccounts with admin user kubeconfig credentials",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:                   "v1.21.2",
+					DisableLocalAccounts:      to.Ptr(true),
+					UserKubeconfigCredentials: kubeconfigCredentialsTypePtr(KubeconfigCredentialsTypeAdmin),
+					AADProfile: &AADProfile{
+						Managed:             true,
+						AdminGroupObjectIDs: []string{"00000000-0000-0000-0000-000000000000"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "user kubeconfig credentials with local accounts",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:                   "v1.21.2",
+					UserKubeconfigCredentials: kubeconfigCredentialsTypePtr(KubeconfigCredentialsTypeUser),
+				},
+			},
+			expectErr: false,
+		},
 		{
 			name: "Invalid LoadBalancerProfile.IdleTimeoutInMinutes",
 			amcp: AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
 					Version: "v1.21.2",
 					LoadBalancerProfile: &LoadBalancerProfile{
-						IdleTimeoutInMinutes: to.Int32Ptr(600),
+						IdleTimeoutInMinutes: to.Ptr[int32](600),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "LoadBalancerProfile must specify at most one of ManagedOutboundIPs, OutboundIPPrefixes and OutboundIPs",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					LoadBalancerProfile: &LoadBalancerProfile{
+						ManagedOutboundIPs: to.Ptr[int32](1),
+						OutboundIPs: []string{
+							"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/foo-bar/providers/Microsoft.Network/publicIPAddresses/my-public-ip",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Invalid CIDR for AuthorizedIPRanges",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					APIServerAccessProfile: &APIServerAccessProfile{
+						AuthorizedIPRanges: []string{"1.2.3.400/32"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "user-assigned identities",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					Identity: &Identity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+					},
+					KubeletUserAssignedIdentity: "/subscriptions/123/resourceGroups/identities/providers/Microsoft.ManagedIdentity/userAssignedIdentities/kubelet",
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "user-assigned identity without resource ID",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					Identity: &Identity{
+						Type: ManagedControlPlaneIdentityTypeUserAssigned,
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "system-assigned identity with resource ID",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					Identity: &Identity{
+						Type:                           ManagedControlPlaneIdentityTypeSystemAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "kubelet identity with system-assigned identity",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:                     "v1.21.2",
+					KubeletUserAssignedIdentity: "/subscriptions/123/resourceGroups/identities/providers/Microsoft.ManagedIdentity/userAssignedIdentities/kubelet",
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid kubelet identity",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					Identity: &Identity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+					},
+					KubeletUserAssignedIdentity: "kubelet",
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "attached registries",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:      "v1.21.2",
+					AttachedACRs: []string{"/subscriptions/123/resourceGroups/registries/providers/Microsoft.ContainerRegistry/registries/shared"},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid attached registry",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:      "v1.21.2",
+					AttachedACRs: []string{"shared.azurecr.io"},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "fleet member",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					FleetsMember: &FleetsMember{
+						FleetResourceID: "/subscriptions/123/resourceGroups/fleets/providers/Microsoft.ContainerService/fleets/my-fleet",
+						Group:           "canary",
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "managed NAT gateway",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:      "v1.21.2",
+					OutboundType: outboundTypePtr(ManagedControlPlaneOutboundTypeManagedNATGateway),
+					NatGatewayProfile: &NatGatewayProfile{
+						ManagedOutboundIPs:   to.Ptr[int32](2),
+						IdleTimeoutInMinutes: to.Ptr[int32](10),
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "managed NAT gateway with too many outbound IPs",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:      "v1.21.2",
+					OutboundType: outboundTypePtr(ManagedControlPlaneOutboundTypeManagedNATGateway),
+					NatGatewayProfile: &NatGatewayProfile{
+						ManagedOutboundIPs: to.Ptr[int32](17),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "NAT gateway profile without a managed NAT gateway",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:      "v1.21.2",
+					OutboundType: outboundTypePtr(ManagedControlPlaneOutboundTypeUserAssignedNATGateway),
+					NatGatewayProfile: &NatGatewayProfile{
+						IdleTimeoutInMinutes: to.Ptr[int32](10),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "user-defined routing with a load balancer profile",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:      "v1.21.2",
+					OutboundType: outboundTypePtr(ManagedControlPlaneOutboundTypeUserDefinedRouting),
+					LoadBalancerProfile: &LoadBalancerProfile{
+						ManagedOutboundIPs: to.Ptr[int32](2),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "NAT gateway with a Basic load balancer",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:         "v1.21.2",
+					OutboundType:    outboundTypePtr(ManagedControlPlaneOutboundTypeManagedNATGateway),
+					LoadBalancerSKU: to.Ptr("Basic"),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "HTTP proxy",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					HTTPProxyConfig: &HTTPProxyConfig{
+						HTTPProxy:  "http://proxy.example.com:3128/",
+						HTTPSProxy: "https://proxy.example.com:3129/",
+						NoProxy:    []string{"localhost", "example.com", "10.0.0.0/8"},
+						TrustedCA:  &infrav1.CACertificatesReference{Name: "proxy-ca"},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "HTTP proxy without endpoints",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					HTTPProxyConfig: &HTTPProxyConfig{
+						NoProxy: []string{"localhost"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "HTTP proxy with an invalid endpoint",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					HTTPProxyConfig: &HTTPProxyConfig{
+						HTTPSProxy: "proxy.example.com:3128",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "HTTP proxy with a list of endpoints in noProxy",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					HTTPProxyConfig: &HTTPProxyConfig{
+						HTTPSProxy: "http://proxy.example.com:3128/",
+						NoProxy:    []string{"localhost,example.com"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "HTTP proxy with a trusted CA without a Secret name",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					HTTPProxyConfig: &HTTPProxyConfig{
+						HTTPSProxy: "http://proxy.example.com:3128/",
+						TrustedCA:  &infrav1.CACertificatesReference{Key: "ca.crt"},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid fleet",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					FleetsMember: &FleetsMember{
+						FleetResourceID: "/subscriptions/123/resourceGroups/fleets/providers/Microsoft.ContainerService/managedClusters/my-fleet",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Defender with monitoring",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:    "v1.21.2",
+					Monitoring: &infrav1.AzureMonitoring{},
+					SecurityProfile: &infrav1.ClusterSecurityProfile{
+						Defender: &infrav1.DefenderProfile{},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Defender with a workspace",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					SecurityProfile: &infrav1.ClusterSecurityProfile{
+						Defender: &infrav1.DefenderProfile{
+							LogAnalyticsWorkspaceID: "/subscriptions/123/resourceGroups/security/providers/Microsoft.OperationalInsights/workspaces/defender",
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Defender without a workspace",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					SecurityProfile: &infrav1.ClusterSecurityProfile{
+						Defender: &infrav1.DefenderProfile{},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Defender with an invalid workspace",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					SecurityProfile: &infrav1.ClusterSecurityProfile{
+						Defender: &infrav1.DefenderProfile{
+							LogAnalyticsWorkspaceID: "defender",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "just-in-time access",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					SecurityProfile: &infrav1.ClusterSecurityProfile{
+						JustInTimeAccess: &infrav1.JustInTimeAccess{},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "image cleaner with an interval",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					ImageCleaner: &ImageCleaner{
+						Enabled:       true,
+						IntervalHours: pointer.Int32Ptr(48),
 					},
 				},
 			},
+			expectErr: false,
+		},
+		{
+			name: "disabled image cleaner with an interval",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					ImageCleaner: &ImageCleaner{
+						Enabled:       false,
+						IntervalHours: pointer.Int32Ptr(48),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:      "API server VNet integration",
+			amcp:      *apiServerVnetIntegrationControlPlane(true, &ManagedControlPlaneSubnet{Name: "apiserver", CIDRBlock: "10.241.0.0/28"}, ManagedControlPlaneIdentityTypeUserAssigned),
+			expectErr: false,
+		},
+		{
+			name:      "API server subnet without VNet integration",
+			amcp:      *apiServerVnetIntegrationControlPlane(false, &ManagedControlPlaneSubnet{Name: "apiserver", CIDRBlock: "10.241.0.0/28"}, ManagedControlPlaneIdentityTypeUserAssigned),
+			expectErr: true,
+		},
+		{
+			name:      "API server VNet integration without subnet",
+			amcp:      *apiServerVnetIntegrationControlPlane(true, nil, ManagedControlPlaneIdentityTypeUserAssigned),
+			expectErr: true,
+		},
+		{
+			name:      "API server VNet integration with system-assigned identity",
+			amcp:      *apiServerVnetIntegrationControlPlane(true, &ManagedControlPlaneSubnet{Name: "apiserver", CIDRBlock: "10.241.0.0/28"}, ManagedControlPlaneIdentityTypeSystemAssigned),
+			expectErr: true,
+		},
+		{
+			name:      "API server subnet named like the node subnet",
+			amcp:      *apiServerVnetIntegrationControlPlane(true, &ManagedControlPlaneSubnet{Name: "default", CIDRBlock: "10.241.0.0/28"}, ManagedControlPlaneIdentityTypeUserAssigned),
+			expectErr: true,
+		},
+		{
+			name:      "API server subnet smaller than a /28",
+			amcp:      *apiServerVnetIntegrationControlPlane(true, &ManagedControlPlaneSubnet{Name: "apiserver", CIDRBlock: "10.241.0.0/29"}, ManagedControlPlaneIdentityTypeUserAssigned),
 			expectErr: true,
 		},
 		{
-			name: "LoadBalancerProfile must specify at most one of ManagedOutboundIPs, OutboundIPPrefixes and OutboundIPs",
-			amcp: AzureManagedControlPlane{
-				Spec: AzureManagedControlPlaneSpec{
-					Version: "v1.21.2",
-					LoadBalancerProfile: &LoadBalancerProfile{
-						ManagedOutboundIPs: to.Int32Ptr(1),
-						OutboundIPs: []string{
-							"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/foo-bar/providers/Microsoft.Network/publicIPAddresses/my-public-ip",
-						},
-					},
-				},
-			},
+			name:      "API server subnet outside the virtual network",
+			amcp:      *apiServerVnetIntegrationControlPlane(true, &ManagedControlPlaneSubnet{Name: "apiserver", CIDRBlock: "192.168.0.0/28"}, ManagedControlPlaneIdentityTypeUserAssigned),
 			expectErr: true,
 		},
 		{
-			name: "Invalid CIDR for AuthorizedIPRanges",
-			amcp: AzureManagedControlPlane{
-				Spec: AzureManagedControlPlaneSpec{
-					Version: "v1.21.2",
-					APIServerAccessProfile: &APIServerAccessProfile{
-						AuthorizedIPRanges: []string{"1.2.3.400/32"},
-					},
-				},
-			},
+			name:      "API server subnet overlapping the node subnet",
+			amcp:      *apiServerVnetIntegrationControlPlane(true, &ManagedControlPlaneSubnet{Name: "apiserver", CIDRBlock: "10.240.0.0/28"}, ManagedControlPlaneIdentityTypeUserAssigned),
 			expectErr: true,
 		},
 	}
@@ -263,7 +695,7 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 			name: "all valid",
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					DNSServiceIP: to.Ptr("192.168.0.0"),
 					Version:      "v1.18.0",
 					SSHPublicKey: generateSSHPublicKey(true),
 					AADProfile: &AADProfile{
@@ -300,6 +732,94 @@ func TestAzureManagedControlPlane_ValidateCreate(t *testing.T) {
 			wantErr:  true,
 			errorLen: 1,
 		},
+		{
+			name: "identity with subscription",
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:        "v1.18.0",
+					SSHPublicKey:   generateSSHPublicKey(true),
+					SubscriptionID: "00000000-0000-0000-0000-000000000000",
+					IdentityRef:    &corev1.ObjectReference{Name: "identity", Namespace: "default"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "identity without subscription",
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:      "v1.18.0",
+					SSHPublicKey: generateSSHPublicKey(true),
+					IdentityRef:  &corev1.ObjectReference{Name: "identity", Namespace: "default"},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "custom node resource group",
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:               "v1.18.0",
+					SSHPublicKey:          generateSSHPublicKey(true),
+					ResourceGroupName:     "my-rg",
+					NodeResourceGroupName: "my-rg-nodes",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "node resource group ending with a period",
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:               "v1.18.0",
+					SSHPublicKey:          generateSSHPublicKey(true),
+					ResourceGroupName:     "my-rg",
+					NodeResourceGroupName: "my-rg-nodes.",
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "node resource group with invalid characters",
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:               "v1.18.0",
+					SSHPublicKey:          generateSSHPublicKey(true),
+					ResourceGroupName:     "my-rg",
+					NodeResourceGroupName: "my-rg/nodes",
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "node resource group name too long",
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:               "v1.18.0",
+					SSHPublicKey:          generateSSHPublicKey(true),
+					ResourceGroupName:     "my-rg",
+					NodeResourceGroupName: strings.Repeat("a", 81),
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "node resource group same as the resource group of the cluster",
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:               "v1.18.0",
+					SSHPublicKey:          generateSSHPublicKey(true),
+					ResourceGroupName:     "my-rg",
+					NodeResourceGroupName: "MY-RG",
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -351,32 +871,263 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			name: "AzureManagedControlPlane SubscriptionID is immutable",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:   to.StringPtr("192.168.0.0"),
+					DNSServiceIP:   to.Ptr("192.168.0.0"),
 					SubscriptionID: "212ec1q8",
 					Version:        "v1.18.0",
 				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:   to.StringPtr("192.168.0.0"),
+					DNSServiceIP:   to.Ptr("192.168.0.0"),
 					SubscriptionID: "212ec1q9",
 					Version:        "v1.18.0",
 				},
 			},
 			wantErr: true,
 		},
+		{
+			name: "AzureManagedControlPlane Identity is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					Identity: &Identity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane KubeletUserAssignedIdentity is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					Identity: &Identity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+					},
+					KubeletUserAssignedIdentity: "/subscriptions/123/resourceGroups/identities/providers/Microsoft.ManagedIdentity/userAssignedIdentities/kubelet-1",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					Identity: &Identity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+					},
+					KubeletUserAssignedIdentity: "/subscriptions/123/resourceGroups/identities/providers/Microsoft.ManagedIdentity/userAssignedIdentities/kubelet-2",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane FleetsMember can be added",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					FleetsMember: &FleetsMember{
+						FleetResourceID: "/subscriptions/123/resourceGroups/fleets/providers/Microsoft.ContainerService/fleets/my-fleet",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "AzureManagedControlPlane FleetsMember cannot be removed",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					FleetsMember: &FleetsMember{
+						FleetResourceID: "/subscriptions/123/resourceGroups/fleets/providers/Microsoft.ContainerService/fleets/my-fleet",
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane OutboundType is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					OutboundType: outboundTypePtr(ManagedControlPlaneOutboundTypeUserDefinedRouting),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane OutboundType defaults to loadBalancer",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					OutboundType: outboundTypePtr(ManagedControlPlaneOutboundTypeLoadBalancer),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "AzureManagedControlPlane NatGatewayProfile is mutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					OutboundType: outboundTypePtr(ManagedControlPlaneOutboundTypeManagedNATGateway),
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					OutboundType: outboundTypePtr(ManagedControlPlaneOutboundTypeManagedNATGateway),
+					NatGatewayProfile: &NatGatewayProfile{
+						ManagedOutboundIPs: to.Ptr[int32](4),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "AzureManagedControlPlane WorkloadAutoScalerProfile and ImageCleaner are mutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					WorkloadAutoScalerProfile: &WorkloadAutoScalerProfile{
+						Keda:                  true,
+						VerticalPodAutoscaler: true,
+					},
+					ImageCleaner: &ImageCleaner{
+						Enabled: true,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "AzureManagedControlPlane HTTPProxyConfig is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					HTTPProxyConfig: &HTTPProxyConfig{
+						HTTPSProxy: "http://proxy.example.com:3128/",
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					HTTPProxyConfig: &HTTPProxyConfig{
+						HTTPSProxy: "http://proxy.example.com:3128/",
+						NoProxy:    []string{"example.com"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane FleetsMember FleetResourceID is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					FleetsMember: &FleetsMember{
+						FleetResourceID: "/subscriptions/123/resourceGroups/fleets/providers/Microsoft.ContainerService/fleets/fleet-1",
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					FleetsMember: &FleetsMember{
+						FleetResourceID: "/subscriptions/123/resourceGroups/fleets/providers/Microsoft.ContainerService/fleets/fleet-2",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane FleetsMember Group is mutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					FleetsMember: &FleetsMember{
+						FleetResourceID: "/subscriptions/123/resourceGroups/fleets/providers/Microsoft.ContainerService/fleets/my-fleet",
+						Group:           "canary",
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.Ptr("192.168.0.0"),
+					Version:      "v1.18.0",
+					FleetsMember: &FleetsMember{
+						FleetResourceID: "/subscriptions/123/resourceGroups/fleets/providers/Microsoft.ContainerService/fleets/my-fleet",
+						Group:           "production",
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "AzureManagedControlPlane ResourceGroupName is immutable",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:      to.StringPtr("192.168.0.0"),
+					DNSServiceIP:      to.Ptr("192.168.0.0"),
 					ResourceGroupName: "hello-1",
 					Version:           "v1.18.0",
 				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:      to.StringPtr("192.168.0.0"),
+					DNSServiceIP:      to.Ptr("192.168.0.0"),
 					ResourceGroupName: "hello-2",
 					Version:           "v1.18.0",
 				},
@@ -387,14 +1138,14 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			name: "AzureManagedControlPlane NodeResourceGroupName is immutable",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:          to.StringPtr("192.168.0.0"),
+					DNSServiceIP:          to.Ptr("192.168.0.0"),
 					NodeResourceGroupName: "hello-1",
 					Version:               "v1.18.0",
 				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:          to.StringPtr("192.168.0.0"),
+					DNSServiceIP:          to.Ptr("192.168.0.0"),
 					NodeResourceGroupName: "hello-2",
 					Version:               "v1.18.0",
 				},
@@ -405,14 +1156,14 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			name: "AzureManagedControlPlane Location is immutable",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					DNSServiceIP: to.Ptr("192.168.0.0"),
 					Location:     "westeurope",
 					Version:      "v1.18.0",
 				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					DNSServiceIP: to.Ptr("192.168.0.0"),
 					Location:     "eastus",
 					Version:      "v1.18.0",
 				},
@@ -423,14 +1174,14 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			name: "AzureManagedControlPlane SSHPublicKey is immutable",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					DNSServiceIP: to.Ptr("192.168.0.0"),
 					SSHPublicKey: generateSSHPublicKey(true),
 					Version:      "v1.18.0",
 				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					DNSServiceIP: to.Ptr("192.168.0.0"),
 					SSHPublicKey: generateSSHPublicKey(true),
 					Version:      "v1.18.0",
 				},
@@ -441,13 +1192,13 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			name: "AzureManagedControlPlane DNSServiceIP is immutable",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					DNSServiceIP: to.Ptr("192.168.0.0"),
 					Version:      "v1.18.0",
 				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.1"),
+					DNSServiceIP: to.Ptr("192.168.0.1"),
 					Version:      "v1.18.0",
 				},
 			},
@@ -457,7 +1208,7 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			name: "AzureManagedControlPlane DNSServiceIP is immutable, unsetting is not allowed",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					DNSServiceIP: to.Ptr("192.168.0.0"),
 					Version:      "v1.18.0",
 				},
 			},
@@ -472,15 +1223,15 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			name: "AzureManagedControlPlane NetworkPlugin is immutable",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:  to.StringPtr("192.168.0.0"),
-					NetworkPlugin: to.StringPtr("azure"),
+					DNSServiceIP:  to.Ptr("192.168.0.0"),
+					NetworkPlugin: to.Ptr("azure"),
 					Version:       "v1.18.0",
 				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:  to.StringPtr("192.168.0.0"),
-					NetworkPlugin: to.StringPtr("kubenet"),
+					DNSServiceIP:  to.Ptr("192.168.0.0"),
+					NetworkPlugin: to.Ptr("kubenet"),
 					Version:       "v1.18.0",
 				},
 			},
@@ -490,14 +1241,14 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			name: "AzureManagedControlPlane NetworkPlugin is immutable, unsetting is not allowed",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:  to.StringPtr("192.168.0.0"),
-					NetworkPlugin: to.StringPtr("azure"),
+					DNSServiceIP:  to.Ptr("192.168.0.0"),
+					NetworkPlugin: to.Ptr("azure"),
 					Version:       "v1.18.0",
 				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					DNSServiceIP: to.Ptr("192.168.0.0"),
 					Version:      "v1.18.0",
 				},
 			},
@@ -507,15 +1258,15 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			name: "AzureManagedControlPlane NetworkPolicy is immutable",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:  to.StringPtr("192.168.0.0"),
-					NetworkPolicy: to.StringPtr("azure"),
+					DNSServiceIP:  to.Ptr("192.168.0.0"),
+					NetworkPolicy: to.Ptr("azure"),
 					Version:       "v1.18.0",
 				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:  to.StringPtr("192.168.0.0"),
-					NetworkPolicy: to.StringPtr("calico"),
+					DNSServiceIP:  to.Ptr("192.168.0.0"),
+					NetworkPolicy: to.Ptr("calico"),
 					Version:       "v1.18.0",
 				},
 			},
@@ -525,14 +1276,14 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			name: "AzureManagedControlPlane NetworkPolicy is immutable, unsetting is not allowed",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:  to.StringPtr("192.168.0.0"),
-					NetworkPolicy: to.StringPtr("azure"),
+					DNSServiceIP:  to.Ptr("192.168.0.0"),
+					NetworkPolicy: to.Ptr("azure"),
 					Version:       "v1.18.0",
 				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					DNSServiceIP: to.Ptr("192.168.0.0"),
 					Version:      "v1.18.0",
 				},
 			},
@@ -542,15 +1293,15 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			name: "AzureManagedControlPlane LoadBalancerSKU is immutable",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:    to.StringPtr("192.168.0.0"),
-					LoadBalancerSKU: to.StringPtr("Standard"),
+					DNSServiceIP:    to.Ptr("192.168.0.0"),
+					LoadBalancerSKU: to.Ptr("Standard"),
 					Version:         "v1.18.0",
 				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:    to.StringPtr("192.168.0.0"),
-					LoadBalancerSKU: to.StringPtr("Basic"),
+					DNSServiceIP:    to.Ptr("192.168.0.0"),
+					LoadBalancerSKU: to.Ptr("Basic"),
 					Version:         "v1.18.0",
 				},
 			},
@@ -560,14 +1311,14 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			name: "AzureManagedControlPlane LoadBalancerSKU is immutable, unsetting is not allowed",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP:    to.StringPtr("192.168.0.0"),
-					LoadBalancerSKU: to.StringPtr("Standard"),
+					DNSServiceIP:    to.Ptr("192.168.0.0"),
+					LoadBalancerSKU: to.Ptr("Standard"),
 					Version:         "v1.18.0",
 				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					DNSServiceIP: to.Ptr("192.168.0.0"),
 					Version:      "v1.18.0",
 				},
 			},
@@ -688,32 +1439,38 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			name: "AzureManagedControlPlane EnablePrivateCluster is immutable",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					DNSServiceIP: to.Ptr("192.168.0.0"),
 					Version:      "v1.18.0",
 				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					DNSServiceIP: to.Ptr("192.168.0.0"),
 					Version:      "v1.18.0",
 					APIServerAccessProfile: &APIServerAccessProfile{
-						EnablePrivateCluster: to.BoolPtr(true),
+						EnablePrivateCluster: to.Ptr(true),
 					},
 				},
 			},
 			wantErr: true,
 		},
+		{
+			name:    "AzureManagedControlPlane API server subnet is immutable",
+			oldAMCP: apiServerVnetIntegrationControlPlane(true, &ManagedControlPlaneSubnet{Name: "apiserver", CIDRBlock: "10.241.0.0/28"}, ManagedControlPlaneIdentityTypeUserAssigned),
+			amcp:    apiServerVnetIntegrationControlPlane(true, &ManagedControlPlaneSubnet{Name: "apiserver", CIDRBlock: "10.242.0.0/28"}, ManagedControlPlaneIdentityTypeUserAssigned),
+			wantErr: true,
+		},
 		{
 			name: "AzureManagedControlPlane AuthorizedIPRanges is mutable",
 			oldAMCP: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					DNSServiceIP: to.Ptr("192.168.0.0"),
 					Version:      "v1.18.0",
 				},
 			},
 			amcp: &AzureManagedControlPlane{
 				Spec: AzureManagedControlPlaneSpec{
-					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					DNSServiceIP: to.Ptr("192.168.0.0"),
 					Version:      "v1.18.0",
 					APIServerAccessProfile: &APIServerAccessProfile{
 						AuthorizedIPRanges: []string{"192.168.0.1/32"},
@@ -739,8 +1496,45 @@ func createAzureManagedControlPlane(serviceIP, version, sshKey string) *AzureMan
 	return &AzureManagedControlPlane{
 		Spec: AzureManagedControlPlaneSpec{
 			SSHPublicKey: sshKey,
-			DNSServiceIP: to.StringPtr(serviceIP),
+			DNSServiceIP: to.Ptr(serviceIP),
 			Version:      version,
 		},
 	}
 }
+
+// apiServerVnetIntegrationControlPlane returns a control plane projecting its API server into the given subnet of its
+// virtual network.
+func apiServerVnetIntegrationControlPlane(enabled bool, subnet *ManagedControlPlaneSubnet, identityType ManagedControlPlaneIdentityType) *AzureManagedControlPlane {
+	amcp := AzureManagedControlPlane{
+		Spec: AzureManagedControlPlaneSpec{
+			Version: "v1.24.0",
+			VirtualNetwork: ManagedControlPlaneVirtualNetwork{
+				Name:      "vnet",
+				CIDRBlock: "10.0.0.0/8",
+				Subnet: ManagedControlPlaneSubnet{
+					Name:      "default",
+					CIDRBlock: "10.240.0.0/16",
+				},
+			},
+			Identity: &Identity{
+				Type: identityType,
+			},
+			APIServerAccessProfile: &APIServerAccessProfile{
+				EnableVnetIntegration: to.Ptr(enabled),
+				Subnet:                subnet,
+			},
+		},
+	}
+	if identityType == ManagedControlPlaneIdentityTypeUserAssigned {
+		amcp.Spec.Identity.UserAssignedIdentityResourceID = "/subscriptions/123/resourceGroups/identities/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane"
+	}
+	return &amcp
+}
+
+func outboundTypePtr(outboundType ManagedControlPlaneOutboundType) *ManagedControlPlaneOutboundType {
+	return &outboundType
+}
+
+func kubeconfigCredentialsTypePtr(credentials KubeconfigCredentialsType) *KubeconfigCredentialsType {
+	return &credentials
+}
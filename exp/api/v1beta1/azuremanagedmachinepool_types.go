@@ -132,6 +132,10 @@ type AzureManagedMachinePoolStatus struct {
 	// +optional
 	Replicas int32 `json:"replicas"`
 
+	// ReadyReplicas is the most recently observed number of replicas which have been provisioned successfully.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
 	// Any transient errors that occur during the reconciliation of Machines
 	// can be added as events to the Machine object and/or logged in the
 	// controller's output.
@@ -152,6 +156,37 @@ type AzureManagedMachinePoolStatus struct {
 	// next reconciliation loop.
 	// +optional
 	LongRunningOperationStates infrav1.Futures `json:"longRunningOperationStates,omitempty"`
+
+	// AgentPool is the state of the AKS agent pool reported by Azure at the last reconciliation.
+	// +optional
+	AgentPool *AgentPoolStatus `json:"agentPool,omitempty"`
+}
+
+// AgentPoolStatus defines the state of an AKS agent pool reported by Azure.
+type AgentPoolStatus struct {
+	// Name is the name of the agent pool.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// ProvisioningState is the provisioning state of the agent pool.
+	// +optional
+	ProvisioningState string `json:"provisioningState,omitempty"`
+
+	// PowerState is the power state of the agent pool, Running or Stopped.
+	// +optional
+	PowerState string `json:"powerState,omitempty"`
+
+	// Count is the number of nodes of the agent pool.
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+
+	// KubernetesVersion is the Kubernetes version of the nodes of the agent pool.
+	// +optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// NodeImageVersion is the version of the node image of the agent pool.
+	// +optional
+	NodeImageVersion string `json:"nodeImageVersion,omitempty"`
 }
 
 // +kubebuilder:object:root=true
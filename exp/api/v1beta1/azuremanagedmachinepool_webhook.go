@@ -21,12 +21,13 @@ import (
 	"fmt"
 	"reflect"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/maps"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -47,7 +48,7 @@ func (m *AzureManagedMachinePool) Default(client client.Client) {
 	}
 
 	if m.Spec.OSType == nil {
-		m.Spec.OSType = to.StringPtr(DefaultOSType)
+		m.Spec.OSType = to.Ptr(DefaultOSType)
 	}
 }
 
@@ -103,14 +104,6 @@ func (m *AzureManagedMachinePool) ValidateUpdate(oldRaw runtime.Object, client c
 		}
 	}
 
-	if !reflect.DeepEqual(m.Spec.Taints, old.Spec.Taints) {
-		allErrs = append(allErrs,
-			field.Invalid(
-				field.NewPath("Spec", "Taints"),
-				m.Spec.Taints,
-				"field is immutable"))
-	}
-
 	// custom headers are immutable
 	oldCustomHeaders := maps.FilterByKeyPrefix(old.ObjectMeta.Annotations, azure.CustomHeaderPrefix)
 	newCustomHeaders := maps.FilterByKeyPrefix(m.ObjectMeta.Annotations, azure.CustomHeaderPrefix)
@@ -161,7 +154,7 @@ func (m *AzureManagedMachinePool) ValidateUpdate(oldRaw runtime.Object, client c
 
 	if old.Spec.OsDiskType != nil {
 		// Prevent OSDiskType modification if it was already set to some value
-		if m.Spec.OsDiskType == nil || to.String(m.Spec.OsDiskType) == "" {
+		if m.Spec.OsDiskType == nil || pointer.StringDeref(m.Spec.OsDiskType, "") == "" {
 			// unsetting the field is not allowed
 			allErrs = append(allErrs,
 				field.Invalid(
@@ -264,7 +257,7 @@ func (m *AzureManagedMachinePool) validateLastSystemNodePool(cli client.Client)
 
 func (m *AzureManagedMachinePool) validateMaxPods() error {
 	if m.Spec.MaxPods != nil {
-		if to.Int32(m.Spec.MaxPods) < 10 || to.Int32(m.Spec.MaxPods) > 250 {
+		if pointer.Int32Deref(m.Spec.MaxPods, 0) < 10 || pointer.Int32Deref(m.Spec.MaxPods, 0) > 250 {
 			return field.Invalid(
 				field.NewPath("Spec", "MaxPods"),
 				m.Spec.MaxPods,
@@ -19,8 +19,8 @@ package v1beta1
 import (
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -38,7 +38,7 @@ func TestAzureManagedMachinePoolDefaultingWebhook(t *testing.T) {
 		Spec: AzureManagedMachinePoolSpec{
 			Mode:         "System",
 			SKU:          "StandardD2S_V3",
-			OSDiskSizeGB: to.Int32Ptr(512),
+			OSDiskSizeGB: to.Ptr[int32](512),
 		},
 	}
 	var client client.Client
@@ -86,14 +86,14 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:         "System",
 					SKU:          "StandardD2S_V3",
-					OSDiskSizeGB: to.Int32Ptr(512),
+					OSDiskSizeGB: to.Ptr[int32](512),
 				},
 			},
 			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:         "System",
 					SKU:          "StandardD2S_V4",
-					OSDiskSizeGB: to.Int32Ptr(512),
+					OSDiskSizeGB: to.Ptr[int32](512),
 				},
 			},
 			wantErr: true,
@@ -104,14 +104,14 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:         "System",
 					SKU:          "StandardD2S_V3",
-					OSDiskSizeGB: to.Int32Ptr(512),
+					OSDiskSizeGB: to.Ptr[int32](512),
 				},
 			},
 			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:         "System",
 					SKU:          "StandardD2S_V3",
-					OSDiskSizeGB: to.Int32Ptr(1024),
+					OSDiskSizeGB: to.Ptr[int32](1024),
 				},
 			},
 			wantErr: true,
@@ -122,7 +122,7 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:              "System",
 					SKU:               "StandardD2S_V3",
-					OSDiskSizeGB:      to.Int32Ptr(512),
+					OSDiskSizeGB:      to.Ptr[int32](512),
 					AvailabilityZones: []string{"1", "2", "3"},
 				},
 			},
@@ -130,25 +130,48 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:         "System",
 					SKU:          "StandardD2S_V3",
-					OSDiskSizeGB: to.Int32Ptr(512),
+					OSDiskSizeGB: to.Ptr[int32](512),
 				},
 			},
 			wantErr: true,
 		},
+		{
+			name: "Can change NodeLabels and Taints of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:         "System",
+					SKU:          "StandardD2S_V3",
+					OSDiskSizeGB: to.Ptr[int32](512),
+					NodeLabels:   map[string]string{"dedicated": "kafka"},
+					Taints: Taints{
+						{Key: "dedicated", Value: "kafka", Effect: TaintEffect("NoSchedule")},
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:         "System",
+					SKU:          "StandardD2S_V3",
+					OSDiskSizeGB: to.Ptr[int32](512),
+					NodeLabels:   map[string]string{"dedicated": "redis"},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Cannot remove AvailabilityZones after creating agentpool",
 			new: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:         "System",
 					SKU:          "StandardD2S_V3",
-					OSDiskSizeGB: to.Int32Ptr(512),
+					OSDiskSizeGB: to.Ptr[int32](512),
 				},
 			},
 			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:              "System",
 					SKU:               "StandardD2S_V3",
-					OSDiskSizeGB:      to.Int32Ptr(512),
+					OSDiskSizeGB:      to.Ptr[int32](512),
 					AvailabilityZones: []string{"1", "2", "3"},
 				},
 			},
@@ -160,7 +183,7 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:              "System",
 					SKU:               "StandardD2S_V3",
-					OSDiskSizeGB:      to.Int32Ptr(512),
+					OSDiskSizeGB:      to.Ptr[int32](512),
 					AvailabilityZones: []string{"1", "2"},
 				},
 			},
@@ -168,7 +191,7 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:              "System",
 					SKU:               "StandardD2S_V3",
-					OSDiskSizeGB:      to.Int32Ptr(512),
+					OSDiskSizeGB:      to.Ptr[int32](512),
 					AvailabilityZones: []string{"1", "2", "3"},
 				},
 			},
@@ -180,7 +203,7 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:              "System",
 					SKU:               "StandardD2S_V3",
-					OSDiskSizeGB:      to.Int32Ptr(512),
+					OSDiskSizeGB:      to.Ptr[int32](512),
 					AvailabilityZones: []string{"1", "3", "2"},
 				},
 			},
@@ -188,7 +211,7 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:              "System",
 					SKU:               "StandardD2S_V3",
-					OSDiskSizeGB:      to.Int32Ptr(512),
+					OSDiskSizeGB:      to.Ptr[int32](512),
 					AvailabilityZones: []string{"1", "2", "3"},
 				},
 			},
@@ -200,16 +223,16 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:         "System",
 					SKU:          "StandardD2S_V3",
-					OSDiskSizeGB: to.Int32Ptr(512),
-					MaxPods:      to.Int32Ptr(24),
+					OSDiskSizeGB: to.Ptr[int32](512),
+					MaxPods:      to.Ptr[int32](24),
 				},
 			},
 			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:         "System",
 					SKU:          "StandardD2S_V3",
-					OSDiskSizeGB: to.Int32Ptr(512),
-					MaxPods:      to.Int32Ptr(25),
+					OSDiskSizeGB: to.Ptr[int32](512),
+					MaxPods:      to.Ptr[int32](25),
 				},
 			},
 			wantErr: true,
@@ -220,16 +243,16 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:         "System",
 					SKU:          "StandardD2S_V3",
-					OSDiskSizeGB: to.Int32Ptr(512),
-					MaxPods:      to.Int32Ptr(30),
+					OSDiskSizeGB: to.Ptr[int32](512),
+					MaxPods:      to.Ptr[int32](30),
 				},
 			},
 			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:         "System",
 					SKU:          "StandardD2S_V3",
-					OSDiskSizeGB: to.Int32Ptr(512),
-					MaxPods:      to.Int32Ptr(30),
+					OSDiskSizeGB: to.Ptr[int32](512),
+					MaxPods:      to.Ptr[int32](30),
 				},
 			},
 			wantErr: false,
@@ -240,18 +263,18 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:         "System",
 					SKU:          "StandardD2S_V3",
-					OSDiskSizeGB: to.Int32Ptr(512),
-					MaxPods:      to.Int32Ptr(24),
-					OsDiskType:   to.StringPtr(string(containerservice.OSDiskTypeEphemeral)),
+					OSDiskSizeGB: to.Ptr[int32](512),
+					MaxPods:      to.Ptr[int32](24),
+					OsDiskType:   to.Ptr(string(armcontainerservice.OSDiskTypeEphemeral)),
 				},
 			},
 			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:         "System",
 					SKU:          "StandardD2S_V3",
-					OSDiskSizeGB: to.Int32Ptr(512),
-					MaxPods:      to.Int32Ptr(24),
-					OsDiskType:   to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+					OSDiskSizeGB: to.Ptr[int32](512),
+					MaxPods:      to.Ptr[int32](24),
+					OsDiskType:   to.Ptr(string(armcontainerservice.OSDiskTypeManaged)),
 				},
 			},
 			wantErr: true,
@@ -343,18 +366,18 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:         "System",
 					SKU:          "StandardD2S_V3",
-					OSDiskSizeGB: to.Int32Ptr(512),
-					MaxPods:      to.Int32Ptr(30),
-					OsDiskType:   to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+					OSDiskSizeGB: to.Ptr[int32](512),
+					MaxPods:      to.Ptr[int32](30),
+					OsDiskType:   to.Ptr(string(armcontainerservice.OSDiskTypeManaged)),
 				},
 			},
 			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:         "System",
 					SKU:          "StandardD2S_V3",
-					OSDiskSizeGB: to.Int32Ptr(512),
-					MaxPods:      to.Int32Ptr(30),
-					OsDiskType:   to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+					OSDiskSizeGB: to.Ptr[int32](512),
+					MaxPods:      to.Ptr[int32](30),
+					OsDiskType:   to.Ptr(string(armcontainerservice.OSDiskTypeManaged)),
 				},
 			},
 			wantErr: false,
@@ -363,12 +386,12 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 			name: "Unexpected error, value EnableUltraSSD is unchanged",
 			new: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
-					EnableUltraSSD: to.BoolPtr(true),
+					EnableUltraSSD: to.Ptr(true),
 				},
 			},
 			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
-					EnableUltraSSD: to.BoolPtr(true),
+					EnableUltraSSD: to.Ptr(true),
 				},
 			},
 			wantErr: false,
@@ -377,12 +400,12 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 			name: "Unexpected error, value EnableUltraSSD is unchanged",
 			new: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
-					EnableUltraSSD: to.BoolPtr(false),
+					EnableUltraSSD: to.Ptr(false),
 				},
 			},
 			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
-					EnableUltraSSD: to.BoolPtr(false),
+					EnableUltraSSD: to.Ptr(false),
 				},
 			},
 			wantErr: false,
@@ -391,12 +414,12 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 			name: "EnableUltraSSD feature is immutable and currently enabled on this agentpool",
 			new: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
-					EnableUltraSSD: to.BoolPtr(false),
+					EnableUltraSSD: to.Ptr(false),
 				},
 			},
 			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
-					EnableUltraSSD: to.BoolPtr(true),
+					EnableUltraSSD: to.Ptr(true),
 				},
 			},
 			wantErr: true,
@@ -405,12 +428,12 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 			name: "EnableUltraSSD feature is immutable and currently disabled on this agentpool",
 			new: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
-					EnableUltraSSD: to.BoolPtr(true),
+					EnableUltraSSD: to.Ptr(true),
 				},
 			},
 			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
-					EnableUltraSSD: to.BoolPtr(false),
+					EnableUltraSSD: to.Ptr(false),
 				},
 			},
 			wantErr: true,
@@ -424,7 +447,7 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 			},
 			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
-					EnableUltraSSD: to.BoolPtr(false),
+					EnableUltraSSD: to.Ptr(false),
 				},
 			},
 			wantErr: true,
@@ -438,7 +461,7 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 			},
 			old: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
-					EnableUltraSSD: to.BoolPtr(true),
+					EnableUltraSSD: to.Ptr(true),
 				},
 			},
 			wantErr: true,
@@ -470,8 +493,8 @@ func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
 			name: "valid",
 			ammp: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
-					MaxPods:    to.Int32Ptr(30),
-					OsDiskType: to.StringPtr(string(containerservice.OSDiskTypeEphemeral)),
+					MaxPods:    to.Ptr[int32](30),
+					OsDiskType: to.Ptr(string(armcontainerservice.OSDiskTypeEphemeral)),
 				},
 			},
 			wantErr: false,
@@ -480,8 +503,8 @@ func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
 			name: "another valid permutation",
 			ammp: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
-					MaxPods:    to.Int32Ptr(249),
-					OsDiskType: to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+					MaxPods:    to.Ptr[int32](249),
+					OsDiskType: to.Ptr(string(armcontainerservice.OSDiskTypeManaged)),
 				},
 			},
 			wantErr: false,
@@ -497,7 +520,7 @@ func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
 			name: "too many MaxPods",
 			ammp: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
-					MaxPods: to.Int32Ptr(251),
+					MaxPods: to.Ptr[int32](251),
 				},
 			},
 			wantErr:  true,
@@ -507,7 +530,7 @@ func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
 			name: "too few MaxPods",
 			ammp: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
-					MaxPods: to.Int32Ptr(9),
+					MaxPods: to.Ptr[int32](9),
 				},
 			},
 			wantErr:  true,
@@ -518,7 +541,7 @@ func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
 			ammp: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:   "System",
-					OSType: to.StringPtr(azure.WindowsOS),
+					OSType: to.Ptr(azure.WindowsOS),
 				},
 			},
 			wantErr:  true,
@@ -529,7 +552,7 @@ func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
 			ammp: &AzureManagedMachinePool{
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:   "User",
-					OSType: to.StringPtr(azure.WindowsOS),
+					OSType: to.Ptr(azure.WindowsOS),
 				},
 			},
 			wantErr: false,
@@ -542,7 +565,7 @@ func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
 				},
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:   "User",
-					OSType: to.StringPtr(azure.WindowsOS),
+					OSType: to.Ptr(azure.WindowsOS),
 				},
 			},
 			wantErr: false,
@@ -555,7 +578,7 @@ func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
 				},
 				Spec: AzureManagedMachinePoolSpec{
 					Mode:   "User",
-					OSType: to.StringPtr(azure.WindowsOS),
+					OSType: to.Ptr(azure.WindowsOS),
 				},
 			},
 			wantErr:  true,
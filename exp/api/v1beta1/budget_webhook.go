@@ -0,0 +1,379 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	admissionv1 "k8s.io/api/admission/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:verbs=create,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-budget,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachines,versions=v1beta1,name=budget.azuremachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-budget,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepools;azuremachinepools/scale,versions=v1beta1,name=budget.azuremachinepool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+// The webhook of the MachinePools ignores its failures, as it intercepts every MachinePool of the management cluster,
+// including those of other infrastructure providers and the patches of CAPI itself, which must not be blocked while
+// CAPZ is unavailable. The webhooks of the AzureMachines and AzureMachinePools fail closed.
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-budget,mutating=false,failurePolicy=ignore,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=machinepools;machinepools/scale,versions=v1beta1,name=budget.machinepool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// BudgetValidator rejects the AzureMachines and the machine pool scale-ups that would make a cluster exceed the budget
+// guardrail of its AzureCluster.
+// +kubebuilder:object:generate=false
+type BudgetValidator struct {
+	Client  client.Client
+	decoder *admission.Decoder
+}
+
+// NewBudgetWebhook creates a new Webhook enforcing the budget guardrails of AzureClusters.
+func NewBudgetWebhook(c client.Client) *admission.Webhook {
+	return &admission.Webhook{
+		Handler: &BudgetValidator{Client: c},
+	}
+}
+
+var _ admission.DecoderInjector = &BudgetValidator{}
+
+// InjectDecoder injects the decoder into a BudgetValidator.
+func (v *BudgetValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle handles admission requests.
+func (v *BudgetValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	var err error
+	switch req.Kind.Kind {
+	case "Scale":
+		err = v.validateScale(ctx, req)
+	case "AzureMachine":
+		err = v.validateAzureMachine(ctx, req)
+	case "AzureMachinePool":
+		err = v.validateAzureMachinePool(ctx, req)
+	case "MachinePool":
+		err = v.validateMachinePool(ctx, req)
+	}
+	if err != nil {
+		if apierrors.IsForbidden(err) {
+			status := err.(apierrors.APIStatus).Status()
+			return admission.Response{
+				AdmissionResponse: admissionv1.AdmissionResponse{
+					Allowed: false,
+					Result:  &status,
+				},
+			}
+		}
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.Allowed("")
+}
+
+// validateAzureMachine checks that the cluster of a new AzureMachine has room for its VM.
+func (v *BudgetValidator) validateAzureMachine(ctx context.Context, req admission.Request) error {
+	m := &infrav1.AzureMachine{}
+	if err := v.decoder.Decode(req, m); err != nil {
+		return err
+	}
+
+	clusterName, ok := m.Labels[clusterv1.ClusterLabelName]
+	if !ok {
+		return nil
+	}
+	azureCluster, err := v.budgetAzureCluster(ctx, m.Namespace, clusterName)
+	if err != nil || azureCluster == nil {
+		return err
+	}
+
+	usage, err := v.clusterUsage(ctx, azureCluster.Spec.Budget, m.Namespace, clusterName, "")
+	if err != nil {
+		return err
+	}
+	return checkBudget(azureCluster, usage, infrav1.GroupVersion.WithResource("azuremachines").GroupResource(), m.Name,
+		m.Spec.VMSize, 1)
+}
+
+// validateAzureMachinePool checks that the cluster of an AzureMachinePool has room for its VMs when it is created,
+// scaled up, or its VM size changes.
+func (v *BudgetValidator) validateAzureMachinePool(ctx context.Context, req admission.Request) error {
+	amp := &AzureMachinePool{}
+	if err := v.decoder.Decode(req, amp); err != nil {
+		return err
+	}
+
+	var old *AzureMachinePool
+	if req.Operation == admissionv1.Update {
+		old = &AzureMachinePool{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return err
+		}
+	}
+	return v.checkAzureMachinePool(ctx, amp, old)
+}
+
+// validateMachinePool checks that the cluster of a MachinePool backed by an AzureMachinePool has room for its VMs
+// when it is created or scaled up.
+func (v *BudgetValidator) validateMachinePool(ctx context.Context, req admission.Request) error {
+	mp := &expv1.MachinePool{}
+	if err := v.decoder.Decode(req, mp); err != nil {
+		return err
+	}
+
+	var oldReplicas *int32
+	if req.Operation == admissionv1.Update {
+		old := &expv1.MachinePool{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return err
+		}
+		oldReplicas = to.Ptr[int32](pointer.Int32Deref(old.Spec.Replicas, 0))
+	}
+	return v.checkMachinePool(ctx, mp, pointer.Int32Deref(mp.Spec.Replicas, 0), oldReplicas)
+}
+
+// validateScale checks that the cluster of a MachinePool or an AzureMachinePool scaled through its scale subresource,
+// e.g. by the cluster autoscaler, has room for the new VMs.
+func (v *BudgetValidator) validateScale(ctx context.Context, req admission.Request) error {
+	if req.Operation != admissionv1.Update {
+		return nil
+	}
+	scale := &autoscalingv1.Scale{}
+	if err := v.decoder.Decode(req, scale); err != nil {
+		return err
+	}
+	old := &autoscalingv1.Scale{}
+	if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+		return err
+	}
+
+	key := client.ObjectKey{Namespace: req.Namespace, Name: req.Name}
+	switch req.Resource.Resource {
+	case "machinepools":
+		mp := &expv1.MachinePool{}
+		if err := v.Client.Get(ctx, key, mp); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		return v.checkMachinePool(ctx, mp, scale.Spec.Replicas, &old.Spec.Replicas)
+	case "azuremachinepools":
+		amp := &AzureMachinePool{}
+		if err := v.Client.Get(ctx, key, amp); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		oldAMP := amp.DeepCopy()
+		amp.Spec.Replicas = &scale.Spec.Replicas
+		oldAMP.Spec.Replicas = &old.Spec.Replicas
+		return v.checkAzureMachinePool(ctx, amp, oldAMP)
+	}
+	return nil
+}
+
+// checkAzureMachinePool checks that the cluster of an AzureMachinePool has room for its VMs, unless it uses less of
+// the budget than its old version.
+func (v *BudgetValidator) checkAzureMachinePool(ctx context.Context, amp, old *AzureMachinePool) error {
+	clusterName, ok := amp.Labels[clusterv1.ClusterLabelName]
+	if !ok {
+		return nil
+	}
+	azureCluster, err := v.budgetAzureCluster(ctx, amp.Namespace, clusterName)
+	if err != nil || azureCluster == nil {
+		return err
+	}
+	budget := azureCluster.Spec.Budget
+
+	machinePoolReplicas, err := v.machinePoolReplicas(ctx, amp.Namespace, clusterName)
+	if err != nil {
+		return err
+	}
+	replicas := azureMachinePoolReplicas(amp, machinePoolReplicas)
+	if old != nil && !budgetIncreased(budget, old.Spec.Template.VMSize, azureMachinePoolReplicas(old, machinePoolReplicas), amp.Spec.Template.VMSize, replicas) {
+		return nil
+	}
+
+	usage, err := v.clusterUsage(ctx, budget, amp.Namespace, clusterName, amp.Name)
+	if err != nil {
+		return err
+	}
+	return checkBudget(azureCluster, usage, GroupVersion.WithResource("azuremachinepools").GroupResource(), amp.Name,
+		amp.Spec.Template.VMSize, replicas)
+}
+
+// checkMachinePool checks that the cluster of a MachinePool backed by an AzureMachinePool has room for replicas VMs,
+// unless it is scaled down from oldReplicas.
+func (v *BudgetValidator) checkMachinePool(ctx context.Context, mp *expv1.MachinePool, replicas int32, oldReplicas *int32) error {
+	ref := mp.Spec.Template.Spec.InfrastructureRef
+	if ref.Kind != "AzureMachinePool" || ref.GroupVersionKind().Group != GroupVersion.Group {
+		return nil
+	}
+	if oldReplicas != nil && replicas <= *oldReplicas {
+		return nil
+	}
+
+	azureCluster, err := v.budgetAzureCluster(ctx, mp.Namespace, mp.Spec.ClusterName)
+	if err != nil || azureCluster == nil {
+		return err
+	}
+
+	amp := &AzureMachinePool{}
+	if err := v.Client.Get(ctx, client.ObjectKey{Namespace: mp.Namespace, Name: ref.Name}, amp); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	usage, err := v.clusterUsage(ctx, azureCluster.Spec.Budget, mp.Namespace, mp.Spec.ClusterName, amp.Name)
+	if err != nil {
+		return err
+	}
+	return checkBudget(azureCluster, usage, expv1.GroupVersion.WithResource("machinepools").GroupResource(), mp.Name,
+		amp.Spec.Template.VMSize, int64(replicas))
+}
+
+// budgetAzureCluster returns the AzureCluster of a cluster if it has a budget guardrail, or nil.
+func (v *BudgetValidator) budgetAzureCluster(ctx context.Context, namespace, clusterName string) (*infrav1.AzureCluster, error) {
+	cluster := &clusterv1.Cluster{}
+	if err := v.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterName}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	ref := cluster.Spec.InfrastructureRef
+	if ref == nil || ref.Kind != "AzureCluster" {
+		return nil, nil
+	}
+	azureCluster := &infrav1.AzureCluster{}
+	if err := v.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, azureCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if azureCluster.Spec.Budget == nil {
+		return nil, nil
+	}
+	return azureCluster, nil
+}
+
+// machinePoolReplicas returns the replicas of the MachinePools of a cluster by the name of their AzureMachinePool.
+func (v *BudgetValidator) machinePoolReplicas(ctx context.Context, namespace, clusterName string) (map[string]int32, error) {
+	mpList := &expv1.MachinePoolList{}
+	if err := v.Client.List(ctx, mpList, client.InNamespace(namespace), client.MatchingLabels{clusterv1.ClusterLabelName: clusterName}); err != nil {
+		return nil, err
+	}
+
+	replicas := make(map[string]int32, len(mpList.Items))
+	for _, mp := range mpList.Items {
+		if ref := mp.Spec.Template.Spec.InfrastructureRef; ref.Kind == "AzureMachinePool" {
+			replicas[ref.Name] = pointer.Int32Deref(mp.Spec.Replicas, 0)
+		}
+	}
+	return replicas, nil
+}
+
+// azureMachinePoolReplicas returns the replicas of an AzureMachinePool: the replicas set through its scale subresource,
+// which its MachinePool is scaled to, or else the replicas of its MachinePool.
+func azureMachinePoolReplicas(amp *AzureMachinePool, machinePoolReplicas map[string]int32) int64 {
+	if amp.Spec.Replicas != nil {
+		return int64(*amp.Spec.Replicas)
+	}
+	return int64(machinePoolReplicas[amp.Name])
+}
+
+// clusterUsage returns the usage of the AzureMachines and the AzureMachinePools of a cluster, except the
+// AzureMachinePool being validated.
+func (v *BudgetValidator) clusterUsage(ctx context.Context, budget *infrav1.BudgetGuardrail, namespace, clusterName, excludedPool string) (infrav1.BudgetUsage, error) {
+	var total infrav1.BudgetUsage
+	labels := client.MatchingLabels{clusterv1.ClusterLabelName: clusterName}
+
+	machineList := &infrav1.AzureMachineList{}
+	if err := v.Client.List(ctx, machineList, client.InNamespace(namespace), labels); err != nil {
+		return total, err
+	}
+	for _, m := range machineList.Items {
+		if !m.DeletionTimestamp.IsZero() {
+			continue
+		}
+		usage, err := budget.Usage(m.Spec.VMSize, 1)
+		if err != nil {
+			return total, apierrors.NewForbidden(infrav1.GroupVersion.WithResource("azuremachines").GroupResource(), m.Name, err)
+		}
+		total = total.Add(usage)
+	}
+
+	machinePoolReplicas, err := v.machinePoolReplicas(ctx, namespace, clusterName)
+	if err != nil {
+		return total, err
+	}
+	ampList := &AzureMachinePoolList{}
+	if err := v.Client.List(ctx, ampList, client.InNamespace(namespace), labels); err != nil {
+		return total, err
+	}
+	for i := range ampList.Items {
+		amp := &ampList.Items[i]
+		if amp.Name == excludedPool || !amp.DeletionTimestamp.IsZero() {
+			continue
+		}
+		usage, err := budget.Usage(amp.Spec.Template.VMSize, azureMachinePoolReplicas(amp, machinePoolReplicas))
+		if err != nil {
+			return total, apierrors.NewForbidden(GroupVersion.WithResource("azuremachinepools").GroupResource(), amp.Name, err)
+		}
+		total = total.Add(usage)
+	}
+
+	return total, nil
+}
+
+// budgetIncreased returns true if count VMs of a VM size use more of the budget than oldCount VMs of the old VM size.
+// Usages that can't be computed are considered increased, so that they are reported.
+func budgetIncreased(budget *infrav1.BudgetGuardrail, oldVMSize string, oldCount int64, vmSize string, count int64) bool {
+	oldUsage, err := budget.Usage(oldVMSize, oldCount)
+	if err != nil {
+		return true
+	}
+	usage, err := budget.Usage(vmSize, count)
+	if err != nil {
+		return true
+	}
+	return usage.VCPUs > oldUsage.VCPUs || usage.MicroHourlyCost > oldUsage.MicroHourlyCost
+}
+
+// checkBudget returns a Forbidden error if adding count VMs of a VM size to the usage of a cluster exceeds the budget
+// guardrail of its AzureCluster.
+func checkBudget(azureCluster *infrav1.AzureCluster, usage infrav1.BudgetUsage, gr schema.GroupResource, name, vmSize string, count int64) error {
+	budget := azureCluster.Spec.Budget
+	added, err := budget.Usage(vmSize, count)
+	if err == nil {
+		err = budget.Check(usage.Add(added))
+	}
+	if err != nil {
+		return apierrors.NewForbidden(gr, name, fmt.Errorf("adding %d VMs of size %s would exceed the budget of AzureCluster %s/%s: %w",
+			count, vmSize, azureCluster.Namespace, azureCluster.Name, err))
+	}
+	return nil
+}
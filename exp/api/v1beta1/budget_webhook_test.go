@@ -0,0 +1,263 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestBudgetValidator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+	_ = expv1.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+	_ = AddToScheme(scheme)
+
+	labels := map[string]string{clusterv1.ClusterLabelName: "my-cluster"}
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "default"},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{Kind: "AzureCluster", Name: "my-azure-cluster"},
+		},
+	}
+	azureCluster := &infrav1.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-azure-cluster", Namespace: "default"},
+		Spec: infrav1.AzureClusterSpec{
+			Budget: &infrav1.BudgetGuardrail{MaxVCPUs: pointer.Int64(16)},
+		},
+	}
+	controlPlane := &infrav1.AzureMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "control-plane", Namespace: "default", Labels: labels},
+		Spec:       infrav1.AzureMachineSpec{VMSize: "Standard_D4s_v3"},
+	}
+	amp := &AzureMachinePool{
+		TypeMeta:   metav1.TypeMeta{APIVersion: GroupVersion.String(), Kind: "AzureMachinePool"},
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default", Labels: labels},
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{VMSize: "Standard_D4s_v3"},
+		},
+	}
+	mp := &expv1.MachinePool{
+		TypeMeta:   metav1.TypeMeta{APIVersion: expv1.GroupVersion.String(), Kind: "MachinePool"},
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default", Labels: labels},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName: "my-cluster",
+			Replicas:    pointer.Int32(2),
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					ClusterName: "my-cluster",
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: GroupVersion.String(),
+						Kind:       "AzureMachinePool",
+						Name:       "pool",
+					},
+				},
+			},
+		},
+	}
+
+	newMachine := func(vmSize string) *infrav1.AzureMachine {
+		return &infrav1.AzureMachine{
+			TypeMeta:   metav1.TypeMeta{APIVersion: infrav1.GroupVersion.String(), Kind: "AzureMachine"},
+			ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default", Labels: labels},
+			Spec:       infrav1.AzureMachineSpec{VMSize: vmSize},
+		}
+	}
+	scaledMachinePool := func(replicas int32) *expv1.MachinePool {
+		scaled := mp.DeepCopy()
+		scaled.Spec.Replicas = pointer.Int32(replicas)
+		return scaled
+	}
+	scaledPool := amp.DeepCopy()
+	scaledPool.Spec.Replicas = pointer.Int32(3)
+	resizedPool := amp.DeepCopy()
+	resizedPool.Spec.Template.VMSize = "Standard_D8s_v3"
+	scale := func(replicas int32) *autoscalingv1.Scale {
+		return &autoscalingv1.Scale{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "autoscaling/v1", Kind: "Scale"},
+			ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: replicas},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		noBudget  bool
+		pool      *AzureMachinePool
+		operation admissionv1.Operation
+		kind      string
+		resource  string
+		obj       runtime.Object
+		old       runtime.Object
+		wantErr   string
+	}{
+		{
+			name:      "AzureMachine within budget",
+			operation: admissionv1.Create,
+			kind:      "AzureMachine",
+			obj:       newMachine("Standard_D4s_v3"),
+		},
+		{
+			name:      "AzureMachine exceeding the budget",
+			operation: admissionv1.Create,
+			kind:      "AzureMachine",
+			obj:       newMachine("Standard_D8s_v3"),
+			wantErr:   "adding 1 VMs of size Standard_D8s_v3 would exceed the budget of AzureCluster default/my-azure-cluster: the virtual machines of the cluster would have 20 vCPUs",
+		},
+		{
+			name:      "AzureMachine of a cluster without budget",
+			noBudget:  true,
+			operation: admissionv1.Create,
+			kind:      "AzureMachine",
+			obj:       newMachine("Standard_D8s_v3"),
+		},
+		{
+			name:      "AzureMachine counting the replicas of AzureMachinePools over those of their MachinePool",
+			pool:      scaledPool,
+			operation: admissionv1.Create,
+			kind:      "AzureMachine",
+			obj:       newMachine("Standard_D4s_v3"),
+			wantErr:   "would have 20 vCPUs",
+		},
+		{
+			name:      "AzureMachinePool VM size increased with replicas set through its scale subresource",
+			pool:      scaledPool,
+			operation: admissionv1.Update,
+			kind:      "AzureMachinePool",
+			obj: func() *AzureMachinePool {
+				resized := scaledPool.DeepCopy()
+				resized.Spec.Template.VMSize = "Standard_D8s_v3"
+				return resized
+			}(),
+			old:     scaledPool,
+			wantErr: "adding 3 VMs of size Standard_D8s_v3",
+		},
+		{
+			name:      "MachinePool scaled up within budget",
+			operation: admissionv1.Update,
+			kind:      "MachinePool",
+			obj:       scaledMachinePool(3),
+			old:       mp,
+		},
+		{
+			name:      "MachinePool scaled up past the budget",
+			operation: admissionv1.Update,
+			kind:      "MachinePool",
+			obj:       scaledMachinePool(4),
+			old:       mp,
+			wantErr:   "would have 20 vCPUs",
+		},
+		{
+			name:      "MachinePool scaled down over budget",
+			operation: admissionv1.Update,
+			kind:      "MachinePool",
+			obj:       scaledMachinePool(5),
+			old:       scaledMachinePool(6),
+		},
+		{
+			name:      "AzureMachinePool VM size increased past the budget",
+			operation: admissionv1.Update,
+			kind:      "AzureMachinePool",
+			obj:       resizedPool,
+			old:       amp,
+			wantErr:   "adding 2 VMs of size Standard_D8s_v3",
+		},
+		{
+			name:      "MachinePool scale subresource past the budget",
+			operation: admissionv1.Update,
+			kind:      "Scale",
+			resource:  "machinepools",
+			obj:       scale(4),
+			old:       scale(2),
+			wantErr:   "would have 20 vCPUs",
+		},
+		{
+			name:      "AzureMachinePool scale subresource within budget",
+			operation: admissionv1.Update,
+			kind:      "Scale",
+			resource:  "azuremachinepools",
+			obj:       scale(3),
+			old:       scale(2),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ac := azureCluster.DeepCopy()
+			if tc.noBudget {
+				ac.Spec.Budget = nil
+			}
+			pool := amp
+			if tc.pool != nil {
+				pool = tc.pool
+			}
+			objs := []client.Object{cluster.DeepCopy(), ac, controlPlane.DeepCopy(), pool.DeepCopy(), mp.DeepCopy()}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+			decoder, err := admission.NewDecoder(scheme)
+			g.Expect(err).NotTo(HaveOccurred())
+			v := &BudgetValidator{Client: c}
+			g.Expect(v.InjectDecoder(decoder)).To(Succeed())
+
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: tc.operation,
+				Kind:      metav1.GroupVersionKind{Kind: tc.kind},
+				Resource:  metav1.GroupVersionResource{Resource: tc.resource},
+				Name:      "pool",
+				Namespace: "default",
+				Object:    runtime.RawExtension{Raw: mustMarshal(g, tc.obj)},
+			}}
+			if tc.old != nil {
+				req.OldObject = runtime.RawExtension{Raw: mustMarshal(g, tc.old)}
+			}
+
+			resp := v.Handle(context.Background(), req)
+			if tc.wantErr != "" {
+				g.Expect(resp.Allowed).To(BeFalse())
+				g.Expect(resp.Result.Message).To(ContainSubstring(tc.wantErr))
+			} else {
+				g.Expect(resp.Allowed).To(BeTrue(), "%v", resp.Result)
+			}
+		})
+	}
+}
+
+func mustMarshal(g *WithT, obj runtime.Object) []byte {
+	raw, err := json.Marshal(obj)
+	g.Expect(err).NotTo(HaveOccurred())
+	return raw
+}
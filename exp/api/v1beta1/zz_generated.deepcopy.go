@@ -74,6 +74,16 @@ func (in *APIServerAccessProfile) DeepCopyInto(out *APIServerAccessProfile) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.EnableVnetIntegration != nil {
+		in, out := &in.EnableVnetIntegration, &out.EnableVnetIntegration
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Subnet != nil {
+		in, out := &in.Subnet, &out.Subnet
+		*out = new(ManagedControlPlaneSubnet)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerAccessProfile.
@@ -108,6 +118,42 @@ func (in *AddonProfile) DeepCopy() *AddonProfile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentPoolStatus) DeepCopyInto(out *AgentPoolStatus) {
+	*out = *in
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentPoolStatus.
+func (in *AgentPoolStatus) DeepCopy() *AgentPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscaleRule) DeepCopyInto(out *AutoscaleRule) {
+	*out = *in
+	out.Threshold = in.Threshold.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscaleRule.
+func (in *AutoscaleRule) DeepCopy() *AutoscaleRule {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscaleRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePool) DeepCopyInto(out *AzureMachinePool) {
 	*out = *in
@@ -135,6 +181,33 @@ func (in *AzureMachinePool) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolAutoscale) DeepCopyInto(out *AzureMachinePoolAutoscale) {
+	*out = *in
+	if in.DefaultReplicas != nil {
+		in, out := &in.DefaultReplicas, &out.DefaultReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]AutoscaleRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolAutoscale.
+func (in *AzureMachinePoolAutoscale) DeepCopy() *AzureMachinePoolAutoscale {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolAutoscale)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePoolDeploymentStrategy) DeepCopyInto(out *AzureMachinePoolDeploymentStrategy) {
 	*out = *in
@@ -163,6 +236,11 @@ func (in *AzureMachinePoolInstanceStatus) DeepCopyInto(out *AzureMachinePoolInst
 		*out = new(apiv1beta1.ProvisioningState)
 		**out = **in
 	}
+	if in.FaultDomain != nil {
+		in, out := &in.FaultDomain, &out.FaultDomain
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolInstanceStatus.
@@ -316,6 +394,16 @@ func (in *AzureMachinePoolMachineStatus) DeepCopyInto(out *AzureMachinePoolMachi
 		*out = make(apiv1beta1.Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.InstanceView != nil {
+		in, out := &in.InstanceView, &out.InstanceView
+		*out = new(apiv1beta1.VMInstanceView)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]corev1.NodeAddress, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolMachineStatus.
@@ -344,6 +432,11 @@ func (in *AzureMachinePoolMachineTemplate) DeepCopyInto(out *AzureMachinePoolMac
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AdditionalSSHPublicKeys != nil {
+		in, out := &in.AdditionalSSHPublicKeys, &out.AdditionalSSHPublicKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.AcceleratedNetworking != nil {
 		in, out := &in.AcceleratedNetworking, &out.AcceleratedNetworking
 		*out = new(bool)
@@ -364,6 +457,11 @@ func (in *AzureMachinePoolMachineTemplate) DeepCopyInto(out *AzureMachinePoolMac
 		*out = new(apiv1beta1.SpotVMOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AdditionalCapabilities != nil {
+		in, out := &in.AdditionalCapabilities, &out.AdditionalCapabilities
+		*out = new(apiv1beta1.AdditionalCapabilities)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.NetworkInterfaces != nil {
 		in, out := &in.NetworkInterfaces, &out.NetworkInterfaces
 		*out = make([]apiv1beta1.AzureNetworkInterface, len(*in))
@@ -371,6 +469,31 @@ func (in *AzureMachinePoolMachineTemplate) DeepCopyInto(out *AzureMachinePoolMac
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.UserData != nil {
+		in, out := &in.UserData, &out.UserData
+		*out = new(apiv1beta1.UserData)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MultiInstanceGPU != nil {
+		in, out := &in.MultiInstanceGPU, &out.MultiInstanceGPU
+		*out = new(apiv1beta1.MultiInstanceGPU)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LocalStorage != nil {
+		in, out := &in.LocalStorage, &out.LocalStorage
+		*out = new(apiv1beta1.LocalStorage)
+		**out = **in
+	}
+	if in.FileStorage != nil {
+		in, out := &in.FileStorage, &out.FileStorage
+		*out = new(apiv1beta1.FileStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CACertificates != nil {
+		in, out := &in.CACertificates, &out.CACertificates
+		*out = new(apiv1beta1.CACertificates)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolMachineTemplate.
@@ -383,6 +506,145 @@ func (in *AzureMachinePoolMachineTemplate) DeepCopy() *AzureMachinePoolMachineTe
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolPlacement) DeepCopyInto(out *AzureMachinePoolPlacement) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolPlacement.
+func (in *AzureMachinePoolPlacement) DeepCopy() *AzureMachinePoolPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolPlacementStatus) DeepCopyInto(out *AzureMachinePoolPlacementStatus) {
+	*out = *in
+	if in.Fallback != nil {
+		in, out := &in.Fallback, &out.Fallback
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolPlacementStatus.
+func (in *AzureMachinePoolPlacementStatus) DeepCopy() *AzureMachinePoolPlacementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolPlacementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolPrescale) DeepCopyInto(out *AzureMachinePoolPrescale) {
+	*out = *in
+	if in.LeadTime != nil {
+		in, out := &in.LeadTime, &out.LeadTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]PrescaleSchedule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Learning != nil {
+		in, out := &in.Learning, &out.Learning
+		*out = new(PrescaleLearning)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolPrescale.
+func (in *AzureMachinePoolPrescale) DeepCopy() *AzureMachinePoolPrescale {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolPrescale)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolPrescaleStatus) DeepCopyInto(out *AzureMachinePoolPrescaleStatus) {
+	*out = *in
+	if in.LearnedReplicas != nil {
+		in, out := &in.LearnedReplicas, &out.LearnedReplicas
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastLearningTime != nil {
+		in, out := &in.LastLearningTime, &out.LastLearningTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BaseReplicas != nil {
+		in, out := &in.BaseReplicas, &out.BaseReplicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolPrescaleStatus.
+func (in *AzureMachinePoolPrescaleStatus) DeepCopy() *AzureMachinePoolPrescaleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolPrescaleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolScalingScheduleStatus) DeepCopyInto(out *AzureMachinePoolScalingScheduleStatus) {
+	*out = *in
+	if in.WindowStartTime != nil {
+		in, out := &in.WindowStartTime, &out.WindowStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.OriginalAutoscalerSizes != nil {
+		in, out := &in.OriginalAutoscalerSizes, &out.OriginalAutoscalerSizes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolScalingScheduleStatus.
+func (in *AzureMachinePoolScalingScheduleStatus) DeepCopy() *AzureMachinePoolScalingScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolScalingScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePoolSpec) DeepCopyInto(out *AzureMachinePoolSpec) {
 	*out = *in
@@ -410,6 +672,38 @@ func (in *AzureMachinePoolSpec) DeepCopyInto(out *AzureMachinePoolSpec) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Autoscale != nil {
+		in, out := &in.Autoscale, &out.Autoscale
+		*out = new(AzureMachinePoolAutoscale)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WarmPool != nil {
+		in, out := &in.WarmPool, &out.WarmPool
+		*out = new(AzureMachinePoolWarmPool)
+		**out = **in
+	}
+	if in.Prescale != nil {
+		in, out := &in.Prescale, &out.Prescale
+		*out = new(AzureMachinePoolPrescale)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScalingSchedule != nil {
+		in, out := &in.ScalingSchedule, &out.ScalingSchedule
+		*out = make([]ScalingScheduleEntry, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlacementFallbacks != nil {
+		in, out := &in.PlacementFallbacks, &out.PlacementFallbacks
+		*out = make([]AzureMachinePoolPlacement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolSpec.
@@ -468,6 +762,21 @@ func (in *AzureMachinePoolStatus) DeepCopyInto(out *AzureMachinePoolStatus) {
 		*out = make(apiv1beta1.Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.Prescale != nil {
+		in, out := &in.Prescale, &out.Prescale
+		*out = new(AzureMachinePoolPrescaleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScalingSchedule != nil {
+		in, out := &in.ScalingSchedule, &out.ScalingSchedule
+		*out = new(AzureMachinePoolScalingScheduleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(AzureMachinePoolPlacementStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolStatus.
@@ -480,6 +789,21 @@ func (in *AzureMachinePoolStatus) DeepCopy() *AzureMachinePoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolWarmPool) DeepCopyInto(out *AzureMachinePoolWarmPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolWarmPool.
+func (in *AzureMachinePoolWarmPool) DeepCopy() *AzureMachinePoolWarmPool {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolWarmPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureManagedCluster) DeepCopyInto(out *AzureManagedCluster) {
 	*out = *in
@@ -632,6 +956,13 @@ func (in *AzureManagedControlPlaneList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureManagedControlPlaneSpec) DeepCopyInto(out *AzureManagedControlPlaneSpec) {
 	*out = *in
+	if in.NodeResourceGroupTags != nil {
+		in, out := &in.NodeResourceGroupTags, &out.NodeResourceGroupTags
+		*out = make(apiv1beta1.Tags, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	out.VirtualNetwork = in.VirtualNetwork
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
 	if in.AdditionalTags != nil {
@@ -666,11 +997,31 @@ func (in *AzureManagedControlPlaneSpec) DeepCopyInto(out *AzureManagedControlPla
 		*out = new(corev1.ObjectReference)
 		**out = **in
 	}
+	if in.Identity != nil {
+		in, out := &in.Identity, &out.Identity
+		*out = new(Identity)
+		**out = **in
+	}
+	if in.AttachedACRs != nil {
+		in, out := &in.AttachedACRs, &out.AttachedACRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.AADProfile != nil {
 		in, out := &in.AADProfile, &out.AADProfile
 		*out = new(AADProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DisableLocalAccounts != nil {
+		in, out := &in.DisableLocalAccounts, &out.DisableLocalAccounts
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UserKubeconfigCredentials != nil {
+		in, out := &in.UserKubeconfigCredentials, &out.UserKubeconfigCredentials
+		*out = new(KubeconfigCredentialsType)
+		**out = **in
+	}
 	if in.AddonProfiles != nil {
 		in, out := &in.AddonProfiles, &out.AddonProfiles
 		*out = make([]AddonProfile, len(*in))
@@ -688,11 +1039,51 @@ func (in *AzureManagedControlPlaneSpec) DeepCopyInto(out *AzureManagedControlPla
 		*out = new(LoadBalancerProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OutboundType != nil {
+		in, out := &in.OutboundType, &out.OutboundType
+		*out = new(ManagedControlPlaneOutboundType)
+		**out = **in
+	}
+	if in.NatGatewayProfile != nil {
+		in, out := &in.NatGatewayProfile, &out.NatGatewayProfile
+		*out = new(NatGatewayProfile)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.APIServerAccessProfile != nil {
 		in, out := &in.APIServerAccessProfile, &out.APIServerAccessProfile
 		*out = new(APIServerAccessProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(apiv1beta1.AzureMonitoring)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityProfile != nil {
+		in, out := &in.SecurityProfile, &out.SecurityProfile
+		*out = new(apiv1beta1.ClusterSecurityProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkloadAutoScalerProfile != nil {
+		in, out := &in.WorkloadAutoScalerProfile, &out.WorkloadAutoScalerProfile
+		*out = new(WorkloadAutoScalerProfile)
+		**out = **in
+	}
+	if in.ImageCleaner != nil {
+		in, out := &in.ImageCleaner, &out.ImageCleaner
+		*out = new(ImageCleaner)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FleetsMember != nil {
+		in, out := &in.FleetsMember, &out.FleetsMember
+		*out = new(FleetsMember)
+		**out = **in
+	}
+	if in.HTTPProxyConfig != nil {
+		in, out := &in.HTTPProxyConfig, &out.HTTPProxyConfig
+		*out = new(HTTPProxyConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedControlPlaneSpec.
@@ -720,6 +1111,11 @@ func (in *AzureManagedControlPlaneStatus) DeepCopyInto(out *AzureManagedControlP
 		*out = make(apiv1beta1.Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(ManagedClusterStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedControlPlaneStatus.
@@ -888,6 +1284,11 @@ func (in *AzureManagedMachinePoolStatus) DeepCopyInto(out *AzureManagedMachinePo
 		*out = make(apiv1beta1.Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.AgentPool != nil {
+		in, out := &in.AgentPool, &out.AgentPool
+		*out = new(AgentPoolStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedMachinePoolStatus.
@@ -900,6 +1301,81 @@ func (in *AzureManagedMachinePoolStatus) DeepCopy() *AzureManagedMachinePoolStat
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetsMember) DeepCopyInto(out *FleetsMember) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetsMember.
+func (in *FleetsMember) DeepCopy() *FleetsMember {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetsMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPProxyConfig) DeepCopyInto(out *HTTPProxyConfig) {
+	*out = *in
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TrustedCA != nil {
+		in, out := &in.TrustedCA, &out.TrustedCA
+		*out = new(apiv1beta1.CACertificatesReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPProxyConfig.
+func (in *HTTPProxyConfig) DeepCopy() *HTTPProxyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPProxyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Identity) DeepCopyInto(out *Identity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Identity.
+func (in *Identity) DeepCopy() *Identity {
+	if in == nil {
+		return nil
+	}
+	out := new(Identity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageCleaner) DeepCopyInto(out *ImageCleaner) {
+	*out = *in
+	if in.IntervalHours != nil {
+		in, out := &in.IntervalHours, &out.IntervalHours
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCleaner.
+func (in *ImageCleaner) DeepCopy() *ImageCleaner {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageCleaner)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadBalancerProfile) DeepCopyInto(out *LoadBalancerProfile) {
 	*out = *in
@@ -965,6 +1441,28 @@ func (in *MachineRollingUpdateDeployment) DeepCopy() *MachineRollingUpdateDeploy
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedClusterStatus) DeepCopyInto(out *ManagedClusterStatus) {
+	*out = *in
+	if in.AgentPools != nil {
+		in, out := &in.AgentPools, &out.AgentPools
+		*out = make([]AgentPoolStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterStatus.
+func (in *ManagedClusterStatus) DeepCopy() *ManagedClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedControlPlaneSubnet) DeepCopyInto(out *ManagedControlPlaneSubnet) {
 	*out = *in
@@ -1021,6 +1519,72 @@ func (in *ManagedMachinePoolScaling) DeepCopy() *ManagedMachinePoolScaling {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NatGatewayProfile) DeepCopyInto(out *NatGatewayProfile) {
+	*out = *in
+	if in.ManagedOutboundIPs != nil {
+		in, out := &in.ManagedOutboundIPs, &out.ManagedOutboundIPs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IdleTimeoutInMinutes != nil {
+		in, out := &in.IdleTimeoutInMinutes, &out.IdleTimeoutInMinutes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NatGatewayProfile.
+func (in *NatGatewayProfile) DeepCopy() *NatGatewayProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(NatGatewayProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrescaleLearning) DeepCopyInto(out *PrescaleLearning) {
+	*out = *in
+	if in.TargetCPUPercentage != nil {
+		in, out := &in.TargetCPUPercentage, &out.TargetCPUPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HistoryDays != nil {
+		in, out := &in.HistoryDays, &out.HistoryDays
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrescaleLearning.
+func (in *PrescaleLearning) DeepCopy() *PrescaleLearning {
+	if in == nil {
+		return nil
+	}
+	out := new(PrescaleLearning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrescaleSchedule) DeepCopyInto(out *PrescaleSchedule) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrescaleSchedule.
+func (in *PrescaleSchedule) DeepCopy() *PrescaleSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(PrescaleSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SKU) DeepCopyInto(out *SKU) {
 	*out = *in
@@ -1036,6 +1600,21 @@ func (in *SKU) DeepCopy() *SKU {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingScheduleEntry) DeepCopyInto(out *ScalingScheduleEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingScheduleEntry.
+func (in *ScalingScheduleEntry) DeepCopy() *ScalingScheduleEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingScheduleEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Taint) DeepCopyInto(out *Taint) {
 	*out = *in
@@ -1069,3 +1648,18 @@ func (in Taints) DeepCopy() Taints {
 	in.DeepCopyInto(out)
 	return *out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadAutoScalerProfile) DeepCopyInto(out *WorkloadAutoScalerProfile) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadAutoScalerProfile.
+func (in *WorkloadAutoScalerProfile) DeepCopy() *WorkloadAutoScalerProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadAutoScalerProfile)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -27,16 +27,21 @@ import (
 	"k8s.io/client-go/tools/record"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	infracontroller "sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/inflight"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	capifeature "sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -46,6 +51,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// scalingScheduleInterval is how often AzureMachinePools with a scaling schedule are reconciled to start its windows.
+const scalingScheduleInterval = time.Minute
+
 type (
 	// AzureMachinePoolReconciler reconciles an AzureMachinePool object.
 	AzureMachinePoolReconciler struct {
@@ -88,9 +96,9 @@ func (ampr *AzureMachinePoolReconciler) SetupWithManager(ctx context.Context, mg
 	)
 	defer done()
 
-	var r reconcile.Reconciler = ampr
+	var r reconcile.Reconciler = inflight.NewReconciler("AzureMachinePool", ampr)
 	if options.Cache != nil {
-		r = coalescing.NewReconciler(ampr, options.Cache, log)
+		r = coalescing.NewReconciler(r, options.Cache, log)
 	}
 
 	// create mapper to transform incoming AzureClusters into AzureMachinePool requests
@@ -150,8 +158,10 @@ func (ampr *AzureMachinePoolReconciler) SetupWithManager(ctx context.Context, mg
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepoolmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepoolmachines/status,verbs=get
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools,verbs=update;patch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 
 // Reconcile idempotently gets, creates, and updates a machine pool.
@@ -197,7 +207,8 @@ func (ampr *AzureMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.
 		return reconcile.Result{}, nil
 	}
 
-	logger = logger.WithValues("cluster", cluster.Name)
+	logger = logger.WithValues(tele.LogKeyClusterName, cluster.Name)
+	ctx = tele.WithLogValues(ctx, tele.LogKeyClusterName, cluster.Name)
 
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, azMachinePool) {
@@ -205,6 +216,20 @@ func (ampr *AzureMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.
 		return ctrl.Result{}, nil
 	}
 
+	// Return early if the MachinePool feature isn't enabled for the Cluster, unless the object is being deleted.
+	if azMachinePool.ObjectMeta.DeletionTimestamp.IsZero() && !feature.EnabledForCluster(capifeature.MachinePool, cluster.Annotations) {
+		logger.Info("MachinePool feature is not enabled for the linked Cluster. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	// Return early if the AzureMachinePool runs Windows and the Windows feature isn't enabled for the Cluster, unless
+	// the object is being deleted.
+	if azMachinePool.Spec.Template.OSDisk.OSType == azure.WindowsOS && azMachinePool.ObjectMeta.DeletionTimestamp.IsZero() &&
+		!feature.EnabledForCluster(feature.Windows, cluster.Annotations) {
+		logger.Info("Windows feature is not enabled for the linked Cluster. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
 	logger = logger.WithValues("AzureCluster", cluster.Spec.InfrastructureRef.Name)
 	azureClusterName := client.ObjectKey{
 		Namespace: azMachinePool.Namespace,
@@ -271,6 +296,16 @@ func (ampr *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, mac
 		return reconcile.Result{}, err
 	}
 
+	// Bound the replicas by the active window of the scaling schedule before scaling the MachinePool to them.
+	if err := machinePoolScope.ReconcileScalingSchedule(ctx, time.Now()); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile scaling schedule")
+	}
+
+	// Scale the MachinePool to the replicas set through the scale subresource of the AzureMachinePool.
+	if err := machinePoolScope.ReconcileReplicas(ctx); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile replicas")
+	}
+
 	if !clusterScope.Cluster.Status.InfrastructureReady {
 		log.Info("Cluster infrastructure is not ready yet")
 		return reconcile.Result{}, nil
@@ -282,12 +317,26 @@ func (ampr *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, mac
 		return reconcile.Result{}, nil
 	}
 
+	waitForControlPlane, err := ampr.reconcileHibernation(ctx, machinePoolScope, clusterScope)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile cluster hibernation")
+	}
+
+	// Initialize the cache to be used by the AzureMachinePool services.
+	if err := machinePoolScope.InitMachinePoolCache(ctx); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to init machine pool scope cache")
+	}
+
 	ams, err := ampr.createAzureMachinePoolService(machinePoolScope)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed creating a newAzureMachinePoolService")
 	}
 
 	if err := ams.Reconcile(ctx); err != nil {
+		if converters.IsInvalidSDKData(err) {
+			conditions.MarkFalse(machinePoolScope.AzureMachinePool, infrav1.ScaleSetRunningCondition, infrav1.InvalidAzureDataReason, clusterv1.ConditionSeverityError, err.Error())
+		}
+
 		// Handle transient and terminal errors
 		var reconcileError azure.ReconcileError
 		if errors.As(err, &reconcileError) {
@@ -322,15 +371,56 @@ func (ampr *AzureMachinePoolReconciler) reconcileNormal(ctx context.Context, mac
 		return reconcile.Result{}, errors.Wrap(err, "Scale set deleted, retry creating in next reconcile")
 	}
 
+	if waitForControlPlane {
+		log.V(2).Info("waiting for the control plane to resume before starting the instances of the scale set")
+		return reconcile.Result{RequeueAfter: reconciler.DefaultReconcilerRequeue}, nil
+	}
+
 	if machinePoolScope.NeedsRequeue() {
 		return reconcile.Result{
 			RequeueAfter: 30 * time.Second,
 		}, nil
 	}
 
+	// windows of the scaling schedule start on the minute
+	if len(machinePoolScope.AzureMachinePool.Spec.ScalingSchedule) > 0 {
+		return reconcile.Result{RequeueAfter: scalingScheduleInterval}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
 
+// reconcileHibernation sets the power state of the instances of the scale set required by the hibernation of its
+// cluster. The instances of a hibernated cluster are deallocated. When the cluster resumes, they are kept deallocated
+// until the control plane VMs are started, in which case it returns true.
+func (ampr *AzureMachinePoolReconciler) reconcileHibernation(ctx context.Context, machinePoolScope *scope.MachinePoolScope, clusterScope *scope.ClusterScope) (bool, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachinePoolReconciler.reconcileHibernation")
+	defer done()
+
+	if clusterScope.IsHibernated() {
+		machinePoolScope.SetHibernated(true)
+		machinePoolScope.SetClusterPowerState(infrav1.PowerStateDeallocated)
+		return false, nil
+	}
+
+	if !machinePoolScope.IsHibernated() {
+		return false, nil
+	}
+
+	controlPlaneMachine, err := infracontroller.HibernatedControlPlaneMachine(ctx, ampr.Client, machinePoolScope.AzureMachinePool.Namespace, clusterScope.ClusterName())
+	if err != nil {
+		return false, err
+	}
+	if controlPlaneMachine != "" {
+		log.V(4).Info("control plane machine has not resumed yet", "azureMachine", controlPlaneMachine)
+		machinePoolScope.SetClusterPowerState(infrav1.PowerStateDeallocated)
+		return true, nil
+	}
+
+	machinePoolScope.SetClusterPowerState(infrav1.PowerStateRunning)
+	return false, nil
+}
+
 func (ampr *AzureMachinePoolReconciler) reconcileDelete(ctx context.Context, machinePoolScope *scope.MachinePoolScope, clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachinePoolReconciler.reconcileDelete")
 	defer done()
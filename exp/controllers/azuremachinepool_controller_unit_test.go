@@ -19,7 +19,7 @@ package controllers
 import (
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -49,7 +49,8 @@ func Test_newAzureMachinePoolService(t *testing.T) {
 	clusterMock := mock_azure.NewMockClusterScoper(mockCtrl)
 	clusterMock.EXPECT().SubscriptionID().AnyTimes()
 	clusterMock.EXPECT().BaseURI().AnyTimes()
-	clusterMock.EXPECT().Authorizer().AnyTimes()
+	clusterMock.EXPECT().CloudEnvironment().AnyTimes()
+	clusterMock.EXPECT().Token().AnyTimes()
 	clusterMock.EXPECT().Location().Return(cluster.Spec.Location)
 	clusterMock.EXPECT().HashKey().Return("fakeCluster")
 
@@ -94,7 +95,7 @@ func newMachinePool(clusterName, poolName string) *clusterv1exp.MachinePool {
 			Namespace: "default",
 		},
 		Spec: clusterv1exp.MachinePoolSpec{
-			Replicas: to.Int32Ptr(2),
+			Replicas: to.Ptr[int32](2),
 		},
 	}
 }
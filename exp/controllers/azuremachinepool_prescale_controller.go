@@ -0,0 +1,383 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/metrics"
+	infracontroller "sigs.k8s.io/cluster-api-provider-azure/controllers"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// prescaleInterval is how often the pre-scaling of an AzureMachinePool is reconciled.
+	prescaleInterval = 5 * time.Minute
+	// prescaleLearningInterval is how often the metrics history of a scale set is analyzed again.
+	prescaleLearningInterval = 24 * time.Hour
+	// defaultPrescaleLeadTime is how long before a peak the replicas are raised when no lead time is set.
+	defaultPrescaleLeadTime = 15 * time.Minute
+	// defaultPrescaleTargetCPUPercentage is the average CPU usage the learned replicas aim for when no target is set.
+	defaultPrescaleTargetCPUPercentage = 60
+	// defaultPrescaleHistoryDays is the number of days of metrics history analyzed when no history is set.
+	defaultPrescaleHistoryDays = 7
+	// prescaleCPUMetric is the metric of the scale set the daily peaks are learned from.
+	prescaleCPUMetric = "Percentage CPU"
+)
+
+// AzureMachinePoolPrescaleReconciler raises the replicas of AzureMachinePools ahead of recurring daily peaks, declared
+// as schedules or learned from the Azure Monitor metrics history of their scale sets, and lowers them back once the
+// peaks are over. It scales through the replicas of the AzureMachinePool, like the scale subresource.
+type AzureMachinePoolPrescaleReconciler struct {
+	client.Client
+	Recorder         record.EventRecorder
+	ReconcileTimeout time.Duration
+	WatchFilterValue string
+
+	newMetricsClient func(azure.Authorizer) (metrics.Client, error)
+	now              func() time.Time
+}
+
+// SetupWithManager initializes this controller with a manager.
+func (r *AzureMachinePoolPrescaleReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	_, log, done := tele.StartSpanWithLogger(ctx,
+		"controllers.AzureMachinePoolPrescaleReconciler.SetupWithManager",
+	)
+	defer done()
+
+	if r.newMetricsClient == nil {
+		r.newMetricsClient = func(auth azure.Authorizer) (metrics.Client, error) {
+			return metrics.NewClient(auth)
+		}
+	}
+	if r.now == nil {
+		r.now = time.Now
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("azuremachinepoolprescale").
+		WithOptions(options).
+		For(&infrav1exp.AzureMachinePool{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(log, r.WatchFilterValue)).
+		Complete(r)
+}
+
+// Reconcile pre-scales an AzureMachinePool if a daily peak is about to start, and restores its replicas once the peak
+// is over.
+func (r *AzureMachinePoolPrescaleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultedLoopTimeout(r.ReconcileTimeout))
+	defer cancel()
+
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachinePoolPrescaleReconciler.Reconcile",
+		tele.KVP("namespace", req.Namespace),
+		tele.KVP("name", req.Name),
+		tele.KVP("kind", "AzureMachinePool"),
+	)
+	defer done()
+
+	azMachinePool := &infrav1exp.AzureMachinePool{}
+	err := r.Get(ctx, req.NamespacedName, azMachinePool)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if !azMachinePool.ObjectMeta.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+	if azMachinePool.Spec.Prescale == nil && azMachinePool.Status.Prescale == nil {
+		return reconcile.Result{}, nil
+	}
+
+	machinePool, err := infracontroller.GetOwnerMachinePool(ctx, r.Client, azMachinePool.ObjectMeta)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if machinePool == nil {
+		log.V(2).Info("MachinePool Controller has not yet set OwnerRef")
+		return reconcile.Result{}, nil
+	}
+
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machinePool.ObjectMeta)
+	if err != nil {
+		log.V(2).Info("MachinePool is missing cluster label or cluster does not exist")
+		return reconcile.Result{}, nil
+	}
+
+	if annotations.IsPaused(cluster, azMachinePool) {
+		log.V(2).Info("AzureMachinePool or linked Cluster is marked as paused. Won't reconcile")
+		return reconcile.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(azMachinePool, r.Client)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to init patch helper")
+	}
+	defer func() {
+		if err := patchHelper.Patch(ctx, azMachinePool); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	prescale := azMachinePool.Spec.Prescale
+	if prescale == nil {
+		// pre-scaling was disabled, lower the replicas back if they were raised
+		r.applyPrescale(azMachinePool, currentReplicas(azMachinePool, pointer.Int32Deref(machinePool.Spec.Replicas, 0)), 0)
+		azMachinePool.Status.Prescale = nil
+		return reconcile.Result{}, nil
+	}
+
+	if azMachinePool.Status.Prescale == nil {
+		azMachinePool.Status.Prescale = &infrav1exp.AzureMachinePoolPrescaleStatus{}
+	}
+
+	loc, err := time.LoadLocation(prescale.TimeZone)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to load time zone %s", prescale.TimeZone)
+	}
+	now := r.now()
+
+	if prescale.Learning != nil && needsLearning(azMachinePool.Status.Prescale, now) && azMachinePool.Spec.ProviderID != "" {
+		if err := r.learn(ctx, cluster, azMachinePool, loc, now); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to learn the daily peaks of the scale set")
+		}
+	}
+
+	floor := prescaleReplicas(prescale, azMachinePool.Status.Prescale.LearnedReplicas, loc, now)
+	r.applyPrescale(azMachinePool, currentReplicas(azMachinePool, pointer.Int32Deref(machinePool.Spec.Replicas, 0)), floor)
+
+	return reconcile.Result{RequeueAfter: prescaleInterval}, nil
+}
+
+// learn analyzes the metrics history of the scale set of an AzureMachinePool and saves the number of replicas learned
+// for each hour of the day to its status.
+func (r *AzureMachinePoolPrescaleReconciler) learn(ctx context.Context, cluster *clusterv1.Cluster, azMachinePool *infrav1exp.AzureMachinePool, loc *time.Location, now time.Time) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.AzureMachinePoolPrescaleReconciler.learn")
+	defer done()
+
+	azureCluster := &infrav1.AzureCluster{}
+	azureClusterName := client.ObjectKey{
+		Namespace: azMachinePool.Namespace,
+		Name:      cluster.Spec.InfrastructureRef.Name,
+	}
+	if err := r.Client.Get(ctx, azureClusterName, azureCluster); err != nil {
+		return errors.Wrap(err, "failed to get AzureCluster")
+	}
+
+	clusterScope, err := scope.NewClusterScope(ctx, scope.ClusterScopeParams{
+		Client:       r.Client,
+		Cluster:      cluster,
+		AzureCluster: azureCluster,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create cluster scope")
+	}
+
+	learning := azMachinePool.Spec.Prescale.Learning
+	historyDays := int32(defaultPrescaleHistoryDays)
+	if learning.HistoryDays != nil {
+		historyDays = *learning.HistoryDays
+	}
+	target := int32(defaultPrescaleTargetCPUPercentage)
+	if learning.TargetCPUPercentage != nil {
+		target = *learning.TargetCPUPercentage
+	}
+
+	resourceID := strings.TrimPrefix(azMachinePool.Spec.ProviderID, azure.ProviderIDPrefix)
+	start := now.Add(-time.Duration(historyDays) * 24 * time.Hour)
+	metricsClient, err := r.newMetricsClient(clusterScope)
+	if err != nil {
+		return errors.Wrap(err, "failed to create metrics client")
+	}
+	values, err := metricsClient.List(ctx, resourceID, prescaleCPUMetric, "Total", "PT1H", start, now)
+	if err != nil {
+		return err
+	}
+
+	status := azMachinePool.Status.Prescale
+	status.LearnedReplicas = learnDailyReplicas(values, loc, target, learning.MaxReplicas)
+	status.LastLearningTime = &metav1.Time{Time: now}
+	log.V(2).Info("learned the daily peaks of the scale set", "learnedReplicas", status.LearnedReplicas)
+	return nil
+}
+
+// applyPrescale raises the replicas of an AzureMachinePool to floor, remembering its replicas from before the peak,
+// and restores them once floor falls back. Replicas changed by someone else during the peak are left alone, and so are
+// replicas managed by the cluster autoscaler or a scaling schedule window.
+func (r *AzureMachinePoolPrescaleReconciler) applyPrescale(azMachinePool *infrav1exp.AzureMachinePool, current, floor int32) {
+	status := azMachinePool.Status.Prescale
+	if status == nil {
+		return
+	}
+
+	if owner := replicasOwner(azMachinePool); owner != "" {
+		if status.Replicas != nil {
+			// the replicas are no longer owned by the pre-scaling, leave them to the new owner
+			status.Replicas = nil
+			status.BaseReplicas = nil
+			r.Recorder.Eventf(azMachinePool, corev1.EventTypeNormal, "PrescaleDeferred", "Left the replicas to %s", owner)
+		}
+		return
+	}
+
+	switch {
+	case floor > current:
+		if status.Replicas == nil {
+			status.BaseReplicas = to.Ptr[int32](current)
+		}
+		azMachinePool.Spec.Replicas = to.Ptr[int32](floor)
+		status.Replicas = to.Ptr[int32](floor)
+		r.Recorder.Eventf(azMachinePool, corev1.EventTypeNormal, "Prescaled", "Raised the replicas from %d to %d ahead of a daily peak", current, floor)
+	case status.Replicas == nil:
+		// not pre-scaled
+	case current != *status.Replicas:
+		// the replicas were changed during the peak, they are no longer owned by the pre-scaling
+		status.Replicas = nil
+		status.BaseReplicas = nil
+	case floor < current:
+		replicas := floor
+		if status.BaseReplicas != nil && *status.BaseReplicas > replicas {
+			replicas = *status.BaseReplicas
+		}
+		azMachinePool.Spec.Replicas = to.Ptr[int32](replicas)
+		if status.BaseReplicas == nil || replicas == *status.BaseReplicas {
+			status.Replicas = nil
+			status.BaseReplicas = nil
+			r.Recorder.Eventf(azMachinePool, corev1.EventTypeNormal, "PrescaleEnded", "Restored the replicas to %d after a daily peak", replicas)
+		} else {
+			status.Replicas = to.Ptr[int32](replicas)
+		}
+	}
+}
+
+// replicasOwner returns what manages the replicas of an AzureMachinePool instead of the pre-scaling, if anything: the
+// cluster autoscaler, or the active window of its scaling schedule, which both set the replicas themselves.
+func replicasOwner(azMachinePool *infrav1exp.AzureMachinePool) string {
+	if azMachinePool.Annotations[azure.ReplicasManagedByAutoscalerAnnotation] == "true" {
+		return "the cluster autoscaler"
+	}
+	if schedule := azMachinePool.Status.ScalingSchedule; schedule != nil && schedule.ActiveEntry != "" {
+		return fmt.Sprintf("the scaling schedule window %s", schedule.ActiveEntry)
+	}
+	return ""
+}
+
+// currentReplicas returns the replicas of an AzureMachinePool, or those of its MachinePool when they are not set.
+func currentReplicas(azMachinePool *infrav1exp.AzureMachinePool, machinePoolReplicas int32) int32 {
+	if azMachinePool.Spec.Replicas != nil {
+		return *azMachinePool.Spec.Replicas
+	}
+	return machinePoolReplicas
+}
+
+// needsLearning returns true if the metrics history of the scale set was never analyzed or should be analyzed again.
+func needsLearning(status *infrav1exp.AzureMachinePoolPrescaleStatus, now time.Time) bool {
+	return status.LastLearningTime == nil || now.Sub(status.LastLearningTime.Time) >= prescaleLearningInterval
+}
+
+// prescaleReplicas returns the minimum number of replicas of an AzureMachinePool at now, which is the highest of the
+// declared and learned daily peaks that are ongoing or start within the lead time.
+func prescaleReplicas(prescale *infrav1exp.AzureMachinePoolPrescale, learned []int32, loc *time.Location, now time.Time) int32 {
+	leadTime := defaultPrescaleLeadTime
+	if prescale.LeadTime != nil {
+		leadTime = prescale.LeadTime.Duration
+	}
+
+	var floor int32
+	for _, t := range []time.Time{now.In(loc), now.Add(leadTime).In(loc)} {
+		for _, schedule := range prescale.Schedules {
+			if scheduleActive(schedule, t) && schedule.Replicas > floor {
+				floor = schedule.Replicas
+			}
+		}
+		if len(learned) == 24 && learned[t.Hour()] > floor {
+			floor = learned[t.Hour()]
+		}
+	}
+
+	return floor
+}
+
+// scheduleActive returns true if the daily peak of a schedule is ongoing at t, including peaks that started the day
+// before and last past midnight.
+func scheduleActive(schedule infrav1exp.PrescaleSchedule, t time.Time) bool {
+	start, err := time.Parse("15:04", schedule.Start)
+	if err != nil {
+		return false
+	}
+
+	today := time.Date(t.Year(), t.Month(), t.Day(), start.Hour(), start.Minute(), 0, 0, t.Location())
+	for _, begin := range []time.Time{today, today.AddDate(0, 0, -1)} {
+		if !t.Before(begin) && t.Before(begin.Add(schedule.Duration.Duration)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// learnDailyReplicas returns the number of replicas needed for each hour of the day in loc, the highest over the days
+// of the history, from the hourly totals of the "Percentage CPU" metric of a scale set. An hourly total sums a sample
+// per instance per minute, so dividing it by 60 gives the CPU usage of the scale set in percent of one instance.
+func learnDailyReplicas(values []armmonitor.MetricValue, loc *time.Location, targetCPUPercentage, maxReplicas int32) []int32 {
+	learned := make([]int32, 24)
+	for _, value := range values {
+		if value.Total == nil || value.TimeStamp == nil {
+			continue
+		}
+
+		replicas := int32(math.Ceil(*value.Total / 60 / float64(targetCPUPercentage)))
+		if replicas > maxReplicas {
+			replicas = maxReplicas
+		}
+
+		hour := value.TimeStamp.In(loc).Hour()
+		if replicas > learned[hour] {
+			learned[hour] = replicas
+		}
+	}
+
+	return learned
+}
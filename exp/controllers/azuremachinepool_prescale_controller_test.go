@@ -0,0 +1,213 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+)
+
+func TestPrescaleReplicas(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	learned := make([]int32, 24)
+	learned[14] = 6
+
+	prescale := &infrav1exp.AzureMachinePoolPrescale{
+		Schedules: []infrav1exp.PrescaleSchedule{
+			{Start: "08:30", Duration: metav1.Duration{Duration: 3 * time.Hour}, Replicas: 10},
+			{Start: "23:00", Duration: metav1.Duration{Duration: 2 * time.Hour}, Replicas: 4},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		learned  []int32
+		expected int32
+	}{
+		{
+			name:     "no peak",
+			now:      time.Date(2022, 6, 1, 6, 0, 0, 0, paris),
+			expected: 0,
+		},
+		{
+			name:     "within the lead time of a peak",
+			now:      time.Date(2022, 6, 1, 8, 20, 0, 0, paris),
+			expected: 10,
+		},
+		{
+			name:     "during a peak",
+			now:      time.Date(2022, 6, 1, 11, 0, 0, 0, paris),
+			expected: 10,
+		},
+		{
+			name:     "after a peak",
+			now:      time.Date(2022, 6, 1, 11, 30, 0, 0, paris),
+			expected: 0,
+		},
+		{
+			name:     "during a peak that started the day before",
+			now:      time.Date(2022, 6, 2, 0, 30, 0, 0, paris),
+			expected: 4,
+		},
+		{
+			name:     "within the lead time of a learned peak",
+			now:      time.Date(2022, 6, 1, 13, 50, 0, 0, paris),
+			learned:  learned,
+			expected: 6,
+		},
+		{
+			name:     "times are compared in the time zone of the pre-scaling",
+			now:      time.Date(2022, 6, 1, 7, 0, 0, 0, time.UTC),
+			expected: 10,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(prescaleReplicas(prescale, tc.learned, paris, tc.now)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestLearnDailyReplicas(t *testing.T) {
+	g := NewWithT(t)
+
+	value := func(hour int, day int, total float64) armmonitor.MetricValue {
+		return armmonitor.MetricValue{
+			TimeStamp: to.Ptr(time.Date(2022, 6, day, hour, 0, 0, 0, time.UTC)),
+			Total:     to.Ptr(total),
+		}
+	}
+	values := []armmonitor.MetricValue{
+		// 3 instances at 50% during an hour
+		value(9, 1, 3*60*50),
+		// 4 instances at 90% during an hour
+		value(9, 2, 4*60*90),
+		// 10 instances at 100% during an hour
+		value(12, 1, 10*60*100),
+		{TimeStamp: to.Ptr(time.Date(2022, 6, 1, 13, 0, 0, 0, time.UTC))},
+	}
+
+	learned := learnDailyReplicas(values, time.UTC, 60, 8)
+	g.Expect(learned).To(HaveLen(24))
+	g.Expect(learned[9]).To(Equal(int32(6)))
+	g.Expect(learned[12]).To(Equal(int32(8)))
+	g.Expect(learned[13]).To(Equal(int32(0)))
+}
+
+func TestApplyPrescale(t *testing.T) {
+	tests := []struct {
+		name             string
+		annotations      map[string]string
+		scalingSchedule  *infrav1exp.AzureMachinePoolScalingScheduleStatus
+		status           infrav1exp.AzureMachinePoolPrescaleStatus
+		current          int32
+		floor            int32
+		expectedReplicas *int32
+		expectedStatus   infrav1exp.AzureMachinePoolPrescaleStatus
+	}{
+		{
+			name:             "raises the replicas ahead of a peak",
+			current:          3,
+			floor:            10,
+			expectedReplicas: to.Ptr[int32](10),
+			expectedStatus:   infrav1exp.AzureMachinePoolPrescaleStatus{Replicas: to.Ptr[int32](10), BaseReplicas: to.Ptr[int32](3)},
+		},
+		{
+			name:    "leaves the replicas when they are above the peak",
+			current: 12,
+			floor:   10,
+		},
+		{
+			name:             "raises the replicas further during a peak",
+			status:           infrav1exp.AzureMachinePoolPrescaleStatus{Replicas: to.Ptr[int32](6), BaseReplicas: to.Ptr[int32](3)},
+			current:          6,
+			floor:            10,
+			expectedReplicas: to.Ptr[int32](10),
+			expectedStatus:   infrav1exp.AzureMachinePoolPrescaleStatus{Replicas: to.Ptr[int32](10), BaseReplicas: to.Ptr[int32](3)},
+		},
+		{
+			name:             "lowers the replicas while a peak winds down",
+			status:           infrav1exp.AzureMachinePoolPrescaleStatus{Replicas: to.Ptr[int32](10), BaseReplicas: to.Ptr[int32](3)},
+			current:          10,
+			floor:            6,
+			expectedReplicas: to.Ptr[int32](6),
+			expectedStatus:   infrav1exp.AzureMachinePoolPrescaleStatus{Replicas: to.Ptr[int32](6), BaseReplicas: to.Ptr[int32](3)},
+		},
+		{
+			name:             "restores the replicas after a peak",
+			status:           infrav1exp.AzureMachinePoolPrescaleStatus{Replicas: to.Ptr[int32](10), BaseReplicas: to.Ptr[int32](3)},
+			current:          10,
+			floor:            0,
+			expectedReplicas: to.Ptr[int32](3),
+		},
+		{
+			name:    "leaves the replicas changed during a peak",
+			status:  infrav1exp.AzureMachinePoolPrescaleStatus{Replicas: to.Ptr[int32](10), BaseReplicas: to.Ptr[int32](3)},
+			current: 15,
+			floor:   0,
+		},
+		{
+			name:        "leaves the replicas managed by the cluster autoscaler",
+			annotations: map[string]string{azure.ReplicasManagedByAutoscalerAnnotation: "true"},
+			current:     3,
+			floor:       10,
+		},
+		{
+			name:            "leaves the replicas managed by a scaling schedule window",
+			scalingSchedule: &infrav1exp.AzureMachinePoolScalingScheduleStatus{ActiveEntry: "workday"},
+			current:         3,
+			floor:           10,
+		},
+		{
+			name:        "hands over the replicas raised before the cluster autoscaler took over",
+			annotations: map[string]string{azure.ReplicasManagedByAutoscalerAnnotation: "true"},
+			status:      infrav1exp.AzureMachinePoolPrescaleStatus{Replicas: to.Ptr[int32](10), BaseReplicas: to.Ptr[int32](3)},
+			current:     10,
+			floor:       0,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			r := &AzureMachinePoolPrescaleReconciler{Recorder: record.NewFakeRecorder(10)}
+			status := tc.status
+			amp := &infrav1exp.AzureMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+				Status:     infrav1exp.AzureMachinePoolStatus{Prescale: &status, ScalingSchedule: tc.scalingSchedule},
+			}
+
+			r.applyPrescale(amp, tc.current, tc.floor)
+			g.Expect(amp.Spec.Replicas).To(Equal(tc.expectedReplicas))
+			g.Expect(*amp.Status.Prescale).To(Equal(tc.expectedStatus))
+		})
+	}
+}
@@ -22,6 +22,10 @@ import (
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/autoscalesettings"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/bootstrapdata"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/datacollectionruleassociations"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/permissions"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
@@ -41,12 +45,36 @@ func newAzureMachinePoolService(machinePoolScope *scope.MachinePoolScope) (*azur
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create a NewCache")
 	}
+	bootstrapDataSvc, err := bootstrapdata.New(machinePoolScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating bootstrap data service")
+	}
+	scaleSetsSvc, err := scalesets.New(machinePoolScope, cache)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating scale sets service")
+	}
+	autoscaleSettingsSvc, err := autoscalesettings.New(machinePoolScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating autoscale settings service")
+	}
+	roleAssignmentsSvc, err := roleassignments.New(machinePoolScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating role assignments service")
+	}
+	dataCollectionRuleAssociationsSvc, err := datacollectionruleassociations.New(machinePoolScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating data collection rule associations service")
+	}
 
 	return &azureMachinePoolService{
 		scope: machinePoolScope,
 		services: []azure.ServiceReconciler{
-			scalesets.New(machinePoolScope, cache),
-			roleassignments.New(machinePoolScope),
+			permissions.New(machinePoolScope),
+			bootstrapDataSvc,
+			scaleSetsSvc,
+			autoscaleSettingsSvc,
+			roleAssignmentsSvc,
+			dataCollectionRuleAssociationsSvc,
 		},
 		skuCache: cache,
 	}, nil
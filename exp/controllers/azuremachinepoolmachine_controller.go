@@ -28,16 +28,22 @@ import (
 	"k8s.io/client-go/tools/record"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesetvms"
 	infracontroller "sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/inflight"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	capifeature "sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -48,7 +54,7 @@ import (
 )
 
 type (
-	azureMachinePoolMachineReconcilerFactory func(*scope.MachinePoolMachineScope) azure.Reconciler
+	azureMachinePoolMachineReconcilerFactory func(*scope.MachinePoolMachineScope) (azure.Reconciler, error)
 
 	// AzureMachinePoolMachineController handles Kubernetes change events for AzureMachinePoolMachine resources.
 	AzureMachinePoolMachineController struct {
@@ -85,9 +91,9 @@ func (ampmr *AzureMachinePoolMachineController) SetupWithManager(ctx context.Con
 	)
 	defer done()
 
-	var r reconcile.Reconciler = ampmr
+	var r reconcile.Reconciler = inflight.NewReconciler("AzureMachinePoolMachine", ampmr)
 	if options.Cache != nil {
-		r = coalescing.NewReconciler(ampmr, options.Cache, log)
+		r = coalescing.NewReconciler(r, options.Cache, log)
 	}
 
 	c, err := ctrl.NewControllerManagedBy(mgr).
@@ -177,7 +183,8 @@ func (ampmr *AzureMachinePoolMachineController) Reconcile(ctx context.Context, r
 		return reconcile.Result{}, nil
 	}
 
-	logger = logger.WithValues("cluster", cluster.Name)
+	logger = logger.WithValues(tele.LogKeyClusterName, cluster.Name)
+	ctx = tele.WithLogValues(ctx, tele.LogKeyClusterName, cluster.Name)
 
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, machine) {
@@ -185,6 +192,12 @@ func (ampmr *AzureMachinePoolMachineController) Reconcile(ctx context.Context, r
 		return ctrl.Result{}, nil
 	}
 
+	// Return early if the MachinePool feature isn't enabled for the Cluster, unless the object is being deleted.
+	if machine.ObjectMeta.DeletionTimestamp.IsZero() && !feature.EnabledForCluster(capifeature.MachinePool, cluster.Annotations) {
+		logger.Info("MachinePool feature is not enabled for the linked Cluster. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
 	azureClusterName := client.ObjectKey{
 		Namespace: machine.Namespace,
 		Name:      cluster.Spec.InfrastructureRef.Name,
@@ -252,8 +265,15 @@ func (ampmr *AzureMachinePoolMachineController) reconcileNormal(ctx context.Cont
 		return reconcile.Result{}, nil
 	}
 
-	ampms := ampmr.reconcilerFactory(machineScope)
+	ampms, err := ampmr.reconcilerFactory(machineScope)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed creating a newAzureMachinePoolMachineReconciler")
+	}
 	if err := ampms.Reconcile(ctx); err != nil {
+		if converters.IsInvalidSDKData(err) {
+			conditions.MarkFalse(machineScope.AzureMachinePoolMachine, infrav1.VMRunningCondition, infrav1.InvalidAzureDataReason, clusterv1.ConditionSeverityError, err.Error())
+		}
+
 		// Handle transient and terminal errors
 		var reconcileError azure.ReconcileError
 		if errors.As(err, &reconcileError) {
@@ -315,7 +335,10 @@ func (ampmr *AzureMachinePoolMachineController) reconcileDelete(ctx context.Cont
 	// 2) after drained, delete the infrastructure
 	// 3) remove finalizer
 
-	ampms := ampmr.reconcilerFactory(machineScope)
+	ampms, err := ampmr.reconcilerFactory(machineScope)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed creating a newAzureMachinePoolMachineReconciler")
+	}
 	if err := ampms.Delete(ctx); err != nil {
 		// Handle transient and terminal errors
 		var reconcileError azure.ReconcileError
@@ -339,11 +362,15 @@ func (ampmr *AzureMachinePoolMachineController) reconcileDelete(ctx context.Cont
 	return reconcile.Result{}, nil
 }
 
-func newAzureMachinePoolMachineReconciler(scope *scope.MachinePoolMachineScope) azure.Reconciler {
+func newAzureMachinePoolMachineReconciler(scope *scope.MachinePoolMachineScope) (azure.Reconciler, error) {
+	scalesetVMsService, err := scalesetvms.NewService(scope)
+	if err != nil {
+		return nil, err
+	}
 	return &azureMachinePoolMachineReconciler{
 		Scope:              scope,
-		scalesetVMsService: scalesetvms.NewService(scope),
-	}
+		scalesetVMsService: scalesetVMsService,
+	}, nil
 }
 
 // Reconcile will reconcile the state of the Machine Pool Machine with the state of the Azure VMSS VM.
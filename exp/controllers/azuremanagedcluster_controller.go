@@ -26,7 +26,9 @@ import (
 	"k8s.io/client-go/tools/record"
 	infracontroller "sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/inflight"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -57,9 +59,9 @@ func (amcr *AzureManagedClusterReconciler) SetupWithManager(ctx context.Context,
 	)
 	defer done()
 
-	var r reconcile.Reconciler = amcr
+	var r reconcile.Reconciler = inflight.NewReconciler("AzureManagedCluster", amcr)
 	if options.Cache != nil {
-		r = coalescing.NewReconciler(amcr, options.Cache, log)
+		r = coalescing.NewReconciler(r, options.Cache, log)
 	}
 
 	azManagedCluster := &infrav1exp.AzureManagedCluster{}
@@ -140,7 +142,8 @@ func (amcr *AzureManagedClusterReconciler) Reconcile(ctx context.Context, req ct
 		Namespace: cluster.Namespace,
 	}
 
-	log = log.WithValues("cluster", cluster.Name)
+	log = log.WithValues(tele.LogKeyClusterName, cluster.Name)
+	ctx = tele.WithLogValues(ctx, tele.LogKeyClusterName, cluster.Name)
 
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, aksCluster) {
@@ -148,6 +151,12 @@ func (amcr *AzureManagedClusterReconciler) Reconcile(ctx context.Context, req ct
 		return ctrl.Result{}, nil
 	}
 
+	// Return early if the AKS feature isn't enabled for the Cluster, unless the object is being deleted.
+	if aksCluster.DeletionTimestamp.IsZero() && !feature.EnabledForCluster(feature.AKS, cluster.Annotations) {
+		log.Info("AKS feature is not enabled for the linked Cluster. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
 	if err := amcr.Get(ctx, controlPlaneRef, controlPlane); err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to get control plane ref")
 	}
@@ -30,7 +30,9 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	infracontroller "sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/inflight"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -63,9 +65,9 @@ func (amcpr *AzureManagedControlPlaneReconciler) SetupWithManager(ctx context.Co
 	)
 	defer done()
 
-	var r reconcile.Reconciler = amcpr
+	var r reconcile.Reconciler = inflight.NewReconciler("AzureManagedControlPlane", amcpr)
 	if options.Cache != nil {
-		r = coalescing.NewReconciler(amcpr, options.Cache, log)
+		r = coalescing.NewReconciler(r, options.Cache, log)
 	}
 
 	azManagedControlPlane := &infrav1exp.AzureManagedControlPlane{}
@@ -107,6 +109,14 @@ func (amcpr *AzureManagedControlPlaneReconciler) SetupWithManager(ctx context.Co
 		return errors.Wrap(err, "failed adding a watch for ready clusters")
 	}
 
+	// Add a watch on the Secrets of the cluster identities to refresh the credentials when they're rotated.
+	if err = c.Watch(
+		&source.Kind{Type: &corev1.Secret{}},
+		handler.EnqueueRequestsFromMapFunc(ClientSecretToAzureManagedControlPlanesMapper(ctx, amcpr.Client, log)),
+	); err != nil {
+		return errors.Wrap(err, "failed adding a watch for cluster identity secrets")
+	}
+
 	return nil
 }
 
@@ -146,7 +156,8 @@ func (amcpr *AzureManagedControlPlaneReconciler) Reconcile(ctx context.Context,
 		return reconcile.Result{}, nil
 	}
 
-	log = log.WithValues("cluster", cluster.Name)
+	log = log.WithValues(tele.LogKeyClusterName, cluster.Name)
+	ctx = tele.WithLogValues(ctx, tele.LogKeyClusterName, cluster.Name)
 
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, azureControlPlane) {
@@ -154,6 +165,12 @@ func (amcpr *AzureManagedControlPlaneReconciler) Reconcile(ctx context.Context,
 		return ctrl.Result{}, nil
 	}
 
+	// Return early if the AKS feature isn't enabled for the Cluster, unless the object is being deleted.
+	if azureControlPlane.DeletionTimestamp.IsZero() && !feature.EnabledForCluster(feature.AKS, cluster.Annotations) {
+		log.Info("AKS feature is not enabled for the linked Cluster. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
 	// Fetch all the ManagedMachinePools owned by this Cluster.
 	opt1 := client.InNamespace(azureControlPlane.Namespace)
 	opt2 := client.MatchingLabels(map[string]string{
@@ -185,8 +202,8 @@ func (amcpr *AzureManagedControlPlaneReconciler) Reconcile(ctx context.Context,
 		if err != nil {
 			return reconcile.Result{}, err
 		}
-		if !scope.IsClusterNamespaceAllowed(ctx, amcpr.Client, identity.Spec.AllowedNamespaces, azureControlPlane.Namespace) {
-			return reconcile.Result{}, errors.New("AzureClusterIdentity list of allowed namespaces doesn't include current azure managed control plane namespace")
+		if !scope.IsClusterNamespaceAllowedByIdentity(ctx, amcpr.Client, identity, azureControlPlane.Namespace) {
+			return reconcile.Result{}, errors.New("AzureClusterIdentity allowed namespaces don't include, or denied namespaces include, current azure managed control plane namespace")
 		}
 	} else {
 		warningMessage := ("You're using deprecated functionality: ")
@@ -236,7 +253,11 @@ func (amcpr *AzureManagedControlPlaneReconciler) reconcileNormal(ctx context.Con
 		return reconcile.Result{}, err
 	}
 
-	if err := newAzureManagedControlPlaneReconciler(scope).Reconcile(ctx); err != nil {
+	svc, err := newAzureManagedControlPlaneReconciler(scope)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to create azureManagedControlPlane service")
+	}
+	if err := svc.Reconcile(ctx); err != nil {
 		// Handle transient and terminal errors
 		log := log.WithValues("name", scope.ControlPlane.Name, "namespace", scope.ControlPlane.Namespace)
 		var reconcileError azure.ReconcileError
@@ -270,7 +291,11 @@ func (amcpr *AzureManagedControlPlaneReconciler) reconcileDelete(ctx context.Con
 
 	log.Info("Reconciling AzureManagedControlPlane delete")
 
-	if err := newAzureManagedControlPlaneReconciler(scope).Delete(ctx); err != nil {
+	svc, err := newAzureManagedControlPlaneReconciler(scope)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to create azureManagedControlPlane service")
+	}
+	if err := svc.Delete(ctx); err != nil {
 		return reconcile.Result{}, errors.Wrapf(err, "error deleting AzureManagedControlPlane %s/%s", scope.ControlPlane.Namespace, scope.ControlPlane.Name)
 	}
 
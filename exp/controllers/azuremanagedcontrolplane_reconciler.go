@@ -20,10 +20,14 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/fleetsmembers"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/loganalyticsworkspaces"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
@@ -41,18 +45,53 @@ type azureManagedControlPlaneService struct {
 }
 
 // newAzureManagedControlPlaneReconciler populates all the services based on input scope.
-func newAzureManagedControlPlaneReconciler(scope *scope.ManagedControlPlaneScope) *azureManagedControlPlaneService {
+func newAzureManagedControlPlaneReconciler(scope *scope.ManagedControlPlaneScope) (*azureManagedControlPlaneService, error) {
+	groupsSvc, err := groups.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating groups service")
+	}
+	tagsSvc, err := tags.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating tags service")
+	}
+	managedClustersSvc, err := managedclusters.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating managed clusters service")
+	}
+	roleAssignmentsSvc, err := roleassignments.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating role assignments service")
+	}
+	fleetsMembersSvc, err := fleetsmembers.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating fleets members service")
+	}
+	virtualNetworksSvc, err := virtualnetworks.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating virtual networks service")
+	}
+	subnetsSvc, err := subnets.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating subnets service")
+	}
+	logAnalyticsWorkspacesSvc, err := loganalyticsworkspaces.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating Log Analytics workspaces service")
+	}
 	return &azureManagedControlPlaneService{
 		kubeclient: scope.Client,
 		scope:      scope,
 		services: []azure.ServiceReconciler{
-			groups.New(scope),
-			virtualnetworks.New(scope),
-			subnets.New(scope),
-			managedclusters.New(scope),
-			tags.New(scope),
+			groupsSvc,
+			virtualNetworksSvc,
+			subnetsSvc,
+			logAnalyticsWorkspacesSvc,
+			managedClustersSvc,
+			roleAssignmentsSvc,
+			fleetsMembersSvc,
+			tagsSvc,
 		},
-	}
+	}, nil
 }
 
 // Reconcile reconciles all the services in a predetermined order.
@@ -89,23 +128,35 @@ func (r *azureManagedControlPlaneService) Delete(ctx context.Context) error {
 }
 
 func (r *azureManagedControlPlaneService) reconcileKubeconfig(ctx context.Context) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedControlPlaneService.reconcileKubeconfig")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedControlPlaneService.reconcileKubeconfig")
 	defer done()
 
 	kubeConfigData := r.scope.GetKubeConfigData()
 	if kubeConfigData == nil {
 		return nil
 	}
-	kubeConfigSecret := r.scope.MakeEmptyKubeConfigSecret()
 
 	// Always update credentials in case of rotation
-	if _, err := controllerutil.CreateOrUpdate(ctx, r.kubeclient, &kubeConfigSecret, func() error {
-		kubeConfigSecret.Data = map[string][]byte{
-			secret.KubeconfigDataName: kubeConfigData,
+	for _, kubeConfig := range []struct {
+		secret corev1.Secret
+		data   []byte
+	}{
+		{secret: r.scope.MakeEmptyKubeConfigSecret(), data: kubeConfigData},
+		{secret: r.scope.MakeEmptyUserKubeConfigSecret(), data: r.scope.GetUserKubeConfigData()},
+	} {
+		kubeConfig := kubeConfig
+		result, err := controllerutil.CreateOrUpdate(ctx, r.kubeclient, &kubeConfig.secret, func() error {
+			kubeConfig.secret.Data = map[string][]byte{
+				secret.KubeconfigDataName: kubeConfig.data,
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to reconcile kubeconfig secret %s for cluster", kubeConfig.secret.Name)
+		}
+		if result == controllerutil.OperationResultUpdated {
+			log.V(2).Info("updated kubeconfig secret with rotated credentials", "secret", kubeConfig.secret.Name)
 		}
-		return nil
-	}); err != nil {
-		return errors.Wrap(err, "failed to kubeconfig secret for cluster")
 	}
 
 	return nil
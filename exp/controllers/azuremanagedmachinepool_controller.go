@@ -29,7 +29,9 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	infracontroller "sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/feature"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/inflight"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -78,9 +80,9 @@ func (ammpr *AzureManagedMachinePoolReconciler) SetupWithManager(ctx context.Con
 	)
 	defer done()
 
-	var r reconcile.Reconciler = ammpr
+	var r reconcile.Reconciler = inflight.NewReconciler("AzureManagedMachinePool", ammpr)
 	if options.Cache != nil {
-		r = coalescing.NewReconciler(ammpr, options.Cache, log)
+		r = coalescing.NewReconciler(r, options.Cache, log)
 	}
 
 	azManagedMachinePool := &infrav1exp.AzureManagedMachinePool{}
@@ -169,7 +171,8 @@ func (ammpr *AzureManagedMachinePoolReconciler) Reconcile(ctx context.Context, r
 		return reconcile.Result{}, nil
 	}
 
-	log = log.WithValues("ownerCluster", ownerCluster.Name)
+	log = log.WithValues(tele.LogKeyClusterName, ownerCluster.Name)
+	ctx = tele.WithLogValues(ctx, tele.LogKeyClusterName, ownerCluster.Name)
 
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(ownerCluster, infraPool) {
@@ -177,6 +180,12 @@ func (ammpr *AzureManagedMachinePoolReconciler) Reconcile(ctx context.Context, r
 		return ctrl.Result{}, nil
 	}
 
+	// Return early if the AKS feature isn't enabled for the Cluster, unless the object is being deleted.
+	if infraPool.DeletionTimestamp.IsZero() && !feature.EnabledForCluster(feature.AKS, ownerCluster.Annotations) {
+		log.Info("AKS feature is not enabled for the linked Cluster. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
 	// Fetch the corresponding control plane which has all the interesting data.
 	controlPlane := &infrav1exp.AzureManagedControlPlane{}
 	controlPlaneName := client.ObjectKey{
@@ -22,8 +22,10 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/agentpools"
@@ -47,8 +49,8 @@ type (
 
 	// NodeLister is a service interface for returning generic lists.
 	NodeLister interface {
-		ListInstances(context.Context, string, string) ([]compute.VirtualMachineScaleSetVM, error)
-		List(context.Context, string) ([]compute.VirtualMachineScaleSet, error)
+		ListInstances(context.Context, string, string) ([]armcompute.VirtualMachineScaleSetVM, error)
+		List(context.Context, string) ([]armcompute.VirtualMachineScaleSet, error)
 	}
 )
 
@@ -82,11 +84,19 @@ func newAzureManagedMachinePoolService(scope *scope.ManagedMachinePoolScope) (*a
 		}
 		authorizer = regionalAuthorizer
 	}
+	agentPoolsSvc, err := agentpools.New(scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating agent pools service")
+	}
+	scaleSetsSvc, err := scalesets.NewClient(authorizer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating scale sets client")
+	}
 
 	return &azureManagedMachinePoolService{
 		scope:         scope,
-		agentPoolsSvc: agentpools.New(scope),
-		scaleSetsSvc:  scalesets.NewClient(authorizer),
+		agentPoolsSvc: agentPoolsSvc,
+		scaleSetsSvc:  scaleSetsSvc,
 	}, nil
 }
 
@@ -108,7 +118,7 @@ func (s *azureManagedMachinePoolService) Reconcile(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to list vmss in resource group %s", nodeResourceGroup)
 	}
 
-	var match *compute.VirtualMachineScaleSet
+	var match *armcompute.VirtualMachineScaleSet
 	for _, ss := range vmss {
 		ss := ss
 		if ss.Tags["poolName"] != nil && *ss.Tags["poolName"] == agentPoolName {
@@ -132,12 +142,18 @@ func (s *azureManagedMachinePoolService) Reconcile(ctx context.Context) error {
 	}
 
 	var providerIDs = make([]string, len(instances))
+	var readyReplicas int32
 	for i := 0; i < len(instances); i++ {
 		providerIDs[i] = strings.ToLower(azure.ProviderIDPrefix + *instances[i].ID)
+		if instances[i].Properties != nil &&
+			infrav1.ProvisioningState(pointer.StringDeref(instances[i].Properties.ProvisioningState, "")) == infrav1.Succeeded {
+			readyReplicas++
+		}
 	}
 
 	s.scope.SetAgentPoolProviderIDList(providerIDs)
 	s.scope.SetAgentPoolReplicas(int32(len(providerIDs)))
+	s.scope.SetAgentPoolReadyReplicas(readyReplicas)
 	s.scope.SetAgentPoolReady(true)
 
 	log.Info("reconciled managed machine pool successfully")
@@ -343,6 +343,41 @@ func AzureManagedControlPlaneToAzureManagedClusterMapper(ctx context.Context, c
 	}, nil
 }
 
+// ClientSecretToAzureManagedControlPlanesMapper creates a mapping handler to transform a Secret into the
+// AzureManagedControlPlanes whose AzureClusterIdentity references it, so that their credentials are rebuilt as soon as
+// the client secret or certificate of the identity is rotated.
+func ClientSecretToAzureManagedControlPlanesMapper(ctx context.Context, c client.Client, log logr.Logger) handler.MapFunc {
+	return func(o client.Object) []ctrl.Request {
+		ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultMappingTimeout)
+		defer cancel()
+
+		identities, err := controllers.ClusterIdentitiesUsingSecret(ctx, c, o)
+		if err != nil {
+			log.Error(err, "failed to list the AzureClusterIdentities using the Secret", "Secret", o.GetName(), "Namespace", o.GetNamespace())
+			return nil
+		}
+		if len(identities) == 0 {
+			return nil
+		}
+
+		controlPlaneList := &infrav1exp.AzureManagedControlPlaneList{}
+		if err := c.List(ctx, controlPlaneList); err != nil {
+			log.Error(err, "failed to list AzureManagedControlPlanes")
+			return nil
+		}
+
+		var results []ctrl.Request
+		for _, controlPlane := range controlPlaneList.Items {
+			if _, ok := identities[controllers.IdentityRefKey(controlPlane.Namespace, controlPlane.Spec.IdentityRef)]; ok {
+				results = append(results, ctrl.Request{
+					NamespacedName: types.NamespacedName{Namespace: controlPlane.Namespace, Name: controlPlane.Name},
+				})
+			}
+		}
+		return results
+	}
+}
+
 // MachinePoolToAzureManagedControlPlaneMapFunc returns a handler.MapFunc that watches for
 // MachinePool events and returns reconciliation requests for a control plane object.
 func MachinePoolToAzureManagedControlPlaneMapFunc(ctx context.Context, c client.Client, gvk schema.GroupVersionKind, log logr.Logger) handler.MapFunc {
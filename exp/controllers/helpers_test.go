@@ -20,7 +20,7 @@ import (
 	"context"
 	"testing"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
@@ -349,6 +349,36 @@ func TestAzureManagedClusterToAzureManagedControlPlaneMapper(t *testing.T) {
 	}))
 }
 
+func TestClientSecretToAzureManagedControlPlanesMapper(t *testing.T) {
+	g := NewWithT(t)
+	scheme := newScheme(g)
+	controlPlane := newAzureManagedControlPlane(cpName)
+	controlPlane.Spec.IdentityRef = &corev1.ObjectReference{Name: "my-identity"}
+
+	initObjects := []runtime.Object{
+		&infrav1.AzureClusterIdentity{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-identity", Namespace: controlPlane.Namespace},
+			Spec: infrav1.AzureClusterIdentitySpec{
+				ClientSecret: corev1.SecretReference{Name: "my-secret", Namespace: "capz-system"},
+			},
+		},
+		controlPlane,
+		newAzureManagedControlPlane("other-cp"),
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(initObjects...).Build()
+
+	mapper := ClientSecretToAzureManagedControlPlanesMapper(context.Background(), fakeClient, logr.Discard())
+	requests := mapper(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "my-secret", Namespace: "capz-system"}})
+	g.Expect(requests).To(Equal([]reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      cpName,
+				Namespace: controlPlane.Namespace,
+			},
+		},
+	}))
+}
+
 func TestAzureManagedControlPlaneToAzureManagedClusterMapper(t *testing.T) {
 	g := NewWithT(t)
 	scheme := newScheme(g)
@@ -746,7 +776,7 @@ func newAzureManagedMachinePool(clusterName, poolName, mode string) *infrav1exp.
 		Spec: infrav1exp.AzureManagedMachinePoolSpec{
 			Mode:         mode,
 			SKU:          "Standard_D2s_v3",
-			OSDiskSizeGB: to.Int32Ptr(512),
+			OSDiskSizeGB: to.Ptr[int32](512),
 		},
 	}
 }
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feature
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/component-base/featuregate"
+)
+
+// ClusterFeatureGatesAnnotation is the annotation of a Cluster listing the features the cluster opts in to, or out of,
+// in the format of the --feature-gates flag, e.g. "MachinePool=true,EdgeZone=false".
+const ClusterFeatureGatesAnnotation = "infrastructure.cluster.x-k8s.io/feature-gates"
+
+// clusterOptIn holds whether the features gated per cluster must be enabled explicitly by each cluster.
+var clusterOptIn = struct {
+	sync.RWMutex
+	required bool
+}{}
+
+// SetClusterOptInRequired sets whether the features gated per cluster are enabled only for the clusters enabling them
+// in their ClusterFeatureGatesAnnotation, rather than for all the clusters not disabling them.
+func SetClusterOptInRequired(required bool) {
+	clusterOptIn.Lock()
+	defer clusterOptIn.Unlock()
+	clusterOptIn.required = required
+}
+
+// EnabledForCluster returns whether a feature is enabled for a cluster, given the annotations of its Cluster.
+// A feature disabled for the manager is disabled for all the clusters. A feature enabled for the manager is enabled
+// for a cluster as set in its ClusterFeatureGatesAnnotation, if listed there, and unless cluster opt-in is required
+// otherwise. An annotation which can't be parsed disables all the features for the cluster.
+func EnabledForCluster(f featuregate.Feature, annotations map[string]string) bool {
+	if !Gates.Enabled(f) {
+		return false
+	}
+	gates, err := ParseClusterFeatureGates(annotations)
+	if err != nil {
+		return false
+	}
+	if enabled, ok := gates[f]; ok {
+		return enabled
+	}
+	clusterOptIn.RLock()
+	defer clusterOptIn.RUnlock()
+	return !clusterOptIn.required
+}
+
+// ParseClusterFeatureGates parses the ClusterFeatureGatesAnnotation of a Cluster.
+func ParseClusterFeatureGates(annotations map[string]string) (map[featuregate.Feature]bool, error) {
+	gates := map[featuregate.Feature]bool{}
+	value := strings.TrimSpace(annotations[ClusterFeatureGatesAnnotation])
+	if value == "" {
+		return gates, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("missing bool value for feature %q in annotation %s", strings.TrimSpace(pair), ClusterFeatureGatesAnnotation)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value of feature %q in annotation %s", strings.TrimSpace(kv[0]), ClusterFeatureGatesAnnotation)
+		}
+		gates[featuregate.Feature(strings.TrimSpace(kv[0]))] = enabled
+	}
+	return gates, nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package feature
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/component-base/featuregate"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+)
+
+func TestEnabledForCluster(t *testing.T) {
+	tests := []struct {
+		name               string
+		managerEnabled     bool
+		optInRequired      bool
+		clusterAnnotations map[string]string
+		expect             bool
+	}{
+		{
+			name:           "disabled for the manager",
+			managerEnabled: false,
+			clusterAnnotations: map[string]string{
+				ClusterFeatureGatesAnnotation: "EdgeZone=true",
+			},
+			expect: false,
+		},
+		{
+			name:           "enabled for the manager and not listed by the cluster",
+			managerEnabled: true,
+			expect:         true,
+		},
+		{
+			name:           "enabled for the manager and disabled by the cluster",
+			managerEnabled: true,
+			clusterAnnotations: map[string]string{
+				ClusterFeatureGatesAnnotation: "AKS=true, EdgeZone=false",
+			},
+			expect: false,
+		},
+		{
+			name:           "opt-in required and not listed by the cluster",
+			managerEnabled: true,
+			optInRequired:  true,
+			clusterAnnotations: map[string]string{
+				ClusterFeatureGatesAnnotation: "AKS=true",
+			},
+			expect: false,
+		},
+		{
+			name:           "opt-in required and enabled by the cluster",
+			managerEnabled: true,
+			optInRequired:  true,
+			clusterAnnotations: map[string]string{
+				ClusterFeatureGatesAnnotation: "AKS=true,EdgeZone=true",
+			},
+			expect: true,
+		},
+		{
+			name:           "invalid annotation",
+			managerEnabled: true,
+			clusterAnnotations: map[string]string{
+				ClusterFeatureGatesAnnotation: "EdgeZone",
+			},
+			expect: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			defer utilfeature.SetFeatureGateDuringTest(t, Gates, EdgeZone, tc.managerEnabled)()
+			SetClusterOptInRequired(tc.optInRequired)
+			defer SetClusterOptInRequired(false)
+
+			g.Expect(EnabledForCluster(EdgeZone, tc.clusterAnnotations)).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestEnabledForClusterWindows(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(EnabledForCluster(Windows, nil)).To(BeTrue())
+	g.Expect(EnabledForCluster(Windows, map[string]string{ClusterFeatureGatesAnnotation: "Windows=false"})).To(BeFalse())
+
+	SetClusterOptInRequired(true)
+	defer SetClusterOptInRequired(false)
+	g.Expect(EnabledForCluster(Windows, nil)).To(BeFalse())
+	g.Expect(EnabledForCluster(Windows, map[string]string{ClusterFeatureGatesAnnotation: "Windows=true"})).To(BeTrue())
+}
+
+func TestParseClusterFeatureGates(t *testing.T) {
+	g := NewWithT(t)
+
+	gates, err := ParseClusterFeatureGates(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gates).To(BeEmpty())
+
+	gates, err = ParseClusterFeatureGates(map[string]string{ClusterFeatureGatesAnnotation: "MachinePool=true,AKS=false"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gates).To(Equal(map[featuregate.Feature]bool{"MachinePool": true, "AKS": false}))
+
+	_, err = ParseClusterFeatureGates(map[string]string{ClusterFeatureGatesAnnotation: "MachinePool=yes"})
+	g.Expect(err).To(HaveOccurred())
+}
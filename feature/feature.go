@@ -34,6 +34,35 @@ const (
 	// owner: @alexeldeib
 	// alpha: v0.4
 	AKS featuregate.Feature = "AKS"
+
+	// ClusterAddons is the feature gate for installing workload cluster addons from AzureCluster.
+	// alpha: v1.3
+	ClusterAddons featuregate.Feature = "ClusterAddons"
+
+	// EventGridNotifications is the feature gate for reconciling AzureClusters and AzureMachines on Event Grid resource notifications.
+	// alpha: v1.3
+	EventGridNotifications featuregate.Feature = "EventGridNotifications"
+
+	// ConnectivityVerification is the feature gate for verifying the connectivity of AzureClusters once their infrastructure is ready.
+	// alpha: v1.3
+	ConnectivityVerification featuregate.Feature = "ConnectivityVerification"
+
+	// ASOBackend is the feature gate for reconciling the Azure resources of AzureClusters through Azure Service Operator resources.
+	// alpha: v1.3
+	ASOBackend featuregate.Feature = "ASOBackend"
+
+	// EdgeZone is the feature gate for creating AzureClusters in an extended location, e.g. an Azure Public MEC edge zone.
+	// alpha: v1.3
+	EdgeZone featuregate.Feature = "EdgeZone"
+
+	// MachinePoolPrescaling is the feature gate for pre-scaling AzureMachinePools before recurring daily peaks.
+	// alpha: v1.3
+	MachinePoolPrescaling featuregate.Feature = "MachinePoolPrescaling"
+
+	// Windows is the feature gate for AzureMachines and AzureMachinePools with a Windows OS disk. It is enabled by
+	// default, so that Windows machines work as before, and lets clusters opt in to, or out of, Windows machines.
+	// beta: v1.3
+	Windows featuregate.Feature = "Windows"
 )
 
 func init() {
@@ -44,5 +73,12 @@ func init() {
 // To add a new feature, define a key for it above and add it here.
 var defaultCAPZFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	// Every feature should be initiated here:
-	AKS: {Default: false, PreRelease: featuregate.Alpha},
+	AKS:                      {Default: false, PreRelease: featuregate.Alpha},
+	ClusterAddons:            {Default: false, PreRelease: featuregate.Alpha},
+	EventGridNotifications:   {Default: false, PreRelease: featuregate.Alpha},
+	ConnectivityVerification: {Default: false, PreRelease: featuregate.Alpha},
+	ASOBackend:               {Default: false, PreRelease: featuregate.Alpha},
+	EdgeZone:                 {Default: false, PreRelease: featuregate.Alpha},
+	MachinePoolPrescaling:    {Default: false, PreRelease: featuregate.Alpha},
+	Windows:                  {Default: true, PreRelease: featuregate.Beta},
 }
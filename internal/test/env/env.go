@@ -28,7 +28,7 @@ import (
 	goruntime "runtime"
 	"strings"
 
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/go-logr/logr"
 	"github.com/onsi/ginkgo"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -49,7 +49,7 @@ import (
 )
 
 var (
-	logger                 = record.NewLogger(record.WithThreshold(to.IntPtr(1)), record.WithWriter(ginkgo.GinkgoWriter))
+	logger                 = record.NewLogger(record.WithThreshold(to.Ptr(1)), record.WithWriter(ginkgo.GinkgoWriter))
 	scheme                 = runtime.NewScheme()
 	env                    *envtest.Environment
 	clusterAPIVersionRegex = regexp.MustCompile(`^(\W)sigs.k8s.io/cluster-api v(.+)`)
@@ -0,0 +1,511 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fixtures provides realistic Azure SDK payloads, modeled on the responses of the Azure APIs, for the tests of
+// the code converting them. Each function returns a new value, so tests can modify it freely.
+package fixtures
+
+import (
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+const (
+	// SubscriptionID is the ID of the subscription of the fixtures.
+	SubscriptionID = "00000000-0000-0000-0000-000000000000"
+	// ResourceGroup is the resource group of the fixtures.
+	ResourceGroup = "my-cluster-rg"
+	// Location is the location of the fixtures.
+	Location = "westeurope"
+	// ClusterName is the name of the cluster owning the fixtures.
+	ClusterName = "my-cluster"
+
+	resourceGroupID = "/subscriptions/" + SubscriptionID + "/resourceGroups/" + ResourceGroup
+	vmssID          = resourceGroupID + "/providers/Microsoft.Compute/virtualMachineScaleSets/my-cluster-mp-0"
+	vmID            = resourceGroupID + "/providers/Microsoft.Compute/virtualMachines/my-cluster-control-plane-x7k2p"
+	subnetID        = resourceGroupID + "/providers/Microsoft.Network/virtualNetworks/my-cluster-vnet/subnets/node-subnet"
+)
+
+// ownedTags returns the tags CAPZ sets on the resources it creates for the cluster.
+func ownedTags(role string) map[string]*string {
+	return map[string]*string{
+		infrav1.ClusterTagKey(ClusterName): to.Ptr(string(infrav1.ResourceLifecycleOwned)),
+		infrav1.NameAzureClusterAPIRole:    to.Ptr(role),
+		"Name":                             to.Ptr(ClusterName),
+	}
+}
+
+// MarketplaceImageReference returns the reference to a first party Marketplace image.
+func MarketplaceImageReference() *armcompute.ImageReference {
+	return &armcompute.ImageReference{
+		Publisher:    to.Ptr("cncf-upstream"),
+		Offer:        to.Ptr("capi"),
+		SKU:          to.Ptr("ubuntu-2004-gen1"),
+		Version:      to.Ptr("latest"),
+		ExactVersion: to.Ptr("123.3.20220524"),
+	}
+}
+
+// ThirdPartyImageReference returns the reference to a third party Marketplace image, which requires a plan.
+func ThirdPartyImageReference() *armcompute.ImageReference {
+	return &armcompute.ImageReference{
+		Publisher:    to.Ptr("kinvolk"),
+		Offer:        to.Ptr("flatcar-container-linux-free"),
+		SKU:          to.Ptr("stable-gen2"),
+		Version:      to.Ptr("3139.2.3"),
+		ExactVersion: to.Ptr("3139.2.3"),
+	}
+}
+
+// ThirdPartyImagePlan returns the plan of the image of ThirdPartyImageReference.
+func ThirdPartyImagePlan() *armcompute.Plan {
+	return &armcompute.Plan{
+		Name:      to.Ptr("stable-gen2"),
+		Product:   to.Ptr("flatcar-container-linux-free"),
+		Publisher: to.Ptr("kinvolk"),
+	}
+}
+
+// ComputeGalleryImageReference returns the reference to an image version of a private Azure Compute Gallery.
+func ComputeGalleryImageReference() *armcompute.ImageReference {
+	return &armcompute.ImageReference{
+		ID: to.Ptr(resourceGroupID + "/providers/Microsoft.Compute/galleries/capzGallery/images/capi-ubuntu-2004/versions/1.23.5"),
+	}
+}
+
+// CommunityGalleryImageReference returns the reference to an image version of a community gallery.
+func CommunityGalleryImageReference() *armcompute.ImageReference {
+	return &armcompute.ImageReference{
+		CommunityGalleryImageID: to.Ptr("/CommunityGalleries/capzPublic-1b6e4c8a-2b52-4f1e-9e8f-7f4b0c2a1e3d/Images/capi-ubuntu-2004/Versions/1.23.5"),
+	}
+}
+
+// SharedGalleryImageReference returns the reference to an image version of a gallery shared directly with the
+// subscription.
+func SharedGalleryImageReference() *armcompute.ImageReference {
+	return &armcompute.ImageReference{
+		SharedGalleryImageID: to.Ptr("/SharedGalleries/" + SubscriptionID + "-CAPZGALLERY/Images/capi-ubuntu-2004/Versions/1.23.5"),
+	}
+}
+
+// EmptyIDImageReference returns an image reference whose ID is set but empty.
+func EmptyIDImageReference() *armcompute.ImageReference {
+	return &armcompute.ImageReference{
+		ID: to.Ptr(""),
+	}
+}
+
+// MalformedIDImageReference returns an image reference whose ID isn't a resource ID.
+func MalformedIDImageReference() *armcompute.ImageReference {
+	return &armcompute.ImageReference{
+		ID: to.Ptr("capzGallery/capi-ubuntu-2004:1.23.5"),
+	}
+}
+
+// VMSSWithPlan returns a scale set of a machine pool running a third party Marketplace image, with the plan of the
+// image.
+func VMSSWithPlan() armcompute.VirtualMachineScaleSet {
+	return armcompute.VirtualMachineScaleSet{
+		ID:       to.Ptr(vmssID),
+		Name:     to.Ptr("my-cluster-mp-0"),
+		Type:     to.Ptr("Microsoft.Compute/virtualMachineScaleSets"),
+		Location: to.Ptr(Location),
+		Tags:     ownedTags(infrav1.Node),
+		SKU: &armcompute.SKU{
+			Name:     to.Ptr("Standard_D2s_v3"),
+			Tier:     to.Ptr("Standard"),
+			Capacity: to.Ptr[int64](2),
+		},
+		Plan:  ThirdPartyImagePlan(),
+		Zones: []*string{to.Ptr("1"), to.Ptr("2"), to.Ptr("3")},
+		Identity: &armcompute.VirtualMachineScaleSetIdentity{
+			Type: to.Ptr(armcompute.ResourceIdentityTypeUserAssigned),
+			UserAssignedIdentities: map[string]*armcompute.UserAssignedIdentitiesValue{
+				resourceGroupID + "/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-cluster-identity": {},
+			},
+		},
+		Properties: &armcompute.VirtualMachineScaleSetProperties{
+			ProvisioningState:    to.Ptr("Succeeded"),
+			SinglePlacementGroup: to.Ptr(false),
+			UpgradePolicy: &armcompute.UpgradePolicy{
+				Mode: to.Ptr(armcompute.UpgradeModeManual),
+			},
+			VirtualMachineProfile: &armcompute.VirtualMachineScaleSetVMProfile{
+				OSProfile: &armcompute.VirtualMachineScaleSetOSProfile{
+					ComputerNamePrefix: to.Ptr("my-cluster-mp-0"),
+					AdminUsername:      to.Ptr("capi"),
+				},
+				StorageProfile: &armcompute.VirtualMachineScaleSetStorageProfile{
+					ImageReference: ThirdPartyImageReference(),
+					OSDisk: &armcompute.VirtualMachineScaleSetOSDisk{
+						CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesFromImage),
+						DiskSizeGB:   to.Ptr[int32](128),
+						OSType:       to.Ptr(armcompute.OperatingSystemTypesLinux),
+					},
+					DataDisks: []*armcompute.VirtualMachineScaleSetDataDisk{
+						{
+							Name:         to.Ptr("my-cluster-mp-0_logs"),
+							Lun:          to.Ptr[int32](1),
+							CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+							DiskSizeGB:   to.Ptr[int32](64),
+						},
+						{
+							Name:         to.Ptr("my-cluster-mp-0_data"),
+							Lun:          to.Ptr[int32](0),
+							CreateOption: to.Ptr(armcompute.DiskCreateOptionTypesEmpty),
+							DiskSizeGB:   to.Ptr[int32](256),
+						},
+					},
+				},
+				DiagnosticsProfile: &armcompute.DiagnosticsProfile{
+					BootDiagnostics: &armcompute.BootDiagnostics{Enabled: to.Ptr(true)},
+				},
+				ExtensionProfile: &armcompute.VirtualMachineScaleSetExtensionProfile{
+					Extensions: []*armcompute.VirtualMachineScaleSetExtension{
+						{
+							Name: to.Ptr("someExtension"),
+							Properties: &armcompute.VirtualMachineScaleSetExtensionProperties{
+								Publisher:          to.Ptr("somePublisher"),
+								Type:               to.Ptr("someExtension"),
+								TypeHandlerVersion: to.Ptr("1.0"),
+							},
+						},
+						{
+							Name: to.Ptr("CAPZ.Linux.Bootstrapping"),
+							Properties: &armcompute.VirtualMachineScaleSetExtensionProperties{
+								Publisher:          to.Ptr("Microsoft.Azure.ContainerUpstream"),
+								Type:               to.Ptr("linux-bootstrapping"),
+								TypeHandlerVersion: to.Ptr("1.0"),
+							},
+						},
+					},
+				},
+				NetworkProfile: &armcompute.VirtualMachineScaleSetNetworkProfile{
+					NetworkInterfaceConfigurations: []*armcompute.VirtualMachineScaleSetNetworkConfiguration{
+						{
+							Name: to.Ptr("my-cluster-mp-0-netconfig"),
+							Properties: &armcompute.VirtualMachineScaleSetNetworkConfigurationProperties{
+								Primary: to.Ptr(true),
+								IPConfigurations: []*armcompute.VirtualMachineScaleSetIPConfiguration{
+									{
+										Name: to.Ptr("my-cluster-mp-0-ipconfig"),
+										Properties: &armcompute.VirtualMachineScaleSetIPConfigurationProperties{
+											Subnet: &armcompute.APIEntityReference{ID: to.Ptr(subnetID)},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// VMSSVMs returns the instances of the scale set of VMSSWithPlan: a running instance with the latest model and a
+// failed extension, and an instance being created with an outdated model missing a data disk and protected from
+// scale-in.
+func VMSSVMs() []armcompute.VirtualMachineScaleSetVM {
+	return []armcompute.VirtualMachineScaleSetVM{
+		{
+			ID:         to.Ptr(vmssID + "/virtualMachines/0"),
+			InstanceID: to.Ptr("0"),
+			Name:       to.Ptr("my-cluster-mp-0_0"),
+			Location:   to.Ptr(Location),
+			Zones:      []*string{to.Ptr("1")},
+			Plan:       ThirdPartyImagePlan(),
+			Resources: []*armcompute.VirtualMachineExtension{
+				{
+					Name: to.Ptr("CAPZ.Linux.Bootstrapping"),
+					Properties: &armcompute.VirtualMachineExtensionProperties{
+						Publisher:          to.Ptr("Microsoft.Azure.ContainerUpstream"),
+						Type:               to.Ptr("linux-bootstrapping"),
+						TypeHandlerVersion: to.Ptr("1.0"),
+					},
+				},
+			},
+			Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+				LatestModelApplied: to.Ptr(true),
+				ProvisioningState:  to.Ptr("Succeeded"),
+				OSProfile: &armcompute.OSProfile{
+					ComputerName: to.Ptr("my-cluster-mp-0000000"),
+				},
+				StorageProfile: &armcompute.StorageProfile{
+					ImageReference: ThirdPartyImageReference(),
+					DataDisks: []*armcompute.DataDisk{
+						{Name: to.Ptr("my-cluster-mp-0_0_disk2_5e0c4f1a"), Lun: to.Ptr[int32](0), DiskSizeGB: to.Ptr[int32](256)},
+						{Name: to.Ptr("my-cluster-mp-0_0_disk3_b81d2e7c"), Lun: to.Ptr[int32](1), DiskSizeGB: to.Ptr[int32](64)},
+					},
+				},
+				DiagnosticsProfile: &armcompute.DiagnosticsProfile{
+					BootDiagnostics: &armcompute.BootDiagnostics{Enabled: to.Ptr(true)},
+				},
+				InstanceView: &armcompute.VirtualMachineScaleSetVMInstanceView{
+					PlatformFaultDomain:  to.Ptr[int32](0),
+					PlatformUpdateDomain: to.Ptr[int32](0),
+					Statuses: []*armcompute.InstanceViewStatus{
+						{Code: to.Ptr("ProvisioningState/succeeded"), Level: to.Ptr(armcompute.StatusLevelTypesInfo)},
+						{Code: to.Ptr("PowerState/running"), Level: to.Ptr(armcompute.StatusLevelTypesInfo)},
+					},
+					Extensions: []*armcompute.VirtualMachineExtensionInstanceView{
+						{
+							Name: to.Ptr("CAPZ.Linux.Bootstrapping"),
+							Type: to.Ptr("Microsoft.Azure.Extensions.CustomScript"),
+							Statuses: []*armcompute.InstanceViewStatus{
+								{
+									Code:    to.Ptr("ProvisioningState/failed/1"),
+									Level:   to.Ptr(armcompute.StatusLevelTypesError),
+									Message: to.Ptr("Enable failed: failed to execute command: command terminated with exit status=1"),
+								},
+							},
+						},
+					},
+					Disks: []*armcompute.DiskInstanceView{
+						{
+							Name: to.Ptr("my-cluster-mp-0_OsDisk_1_9f6a0c2e"),
+							Statuses: []*armcompute.InstanceViewStatus{
+								{Code: to.Ptr("ProvisioningState/succeeded"), Level: to.Ptr(armcompute.StatusLevelTypesInfo)},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			ID:         to.Ptr(vmssID + "/virtualMachines/1"),
+			InstanceID: to.Ptr("1"),
+			Name:       to.Ptr("my-cluster-mp-0_1"),
+			Location:   to.Ptr(Location),
+			Zones:      []*string{to.Ptr("2")},
+			Plan:       ThirdPartyImagePlan(),
+			Properties: &armcompute.VirtualMachineScaleSetVMProperties{
+				LatestModelApplied: to.Ptr(false),
+				ProvisioningState:  to.Ptr("Creating"),
+				OSProfile: &armcompute.OSProfile{
+					ComputerName: to.Ptr("my-cluster-mp-0000001"),
+				},
+				StorageProfile: &armcompute.StorageProfile{
+					ImageReference: ThirdPartyImageReference(),
+					DataDisks: []*armcompute.DataDisk{
+						{Name: to.Ptr("my-cluster-mp-0_1_disk2_0a93c6d4"), Lun: to.Ptr[int32](0), DiskSizeGB: to.Ptr[int32](256)},
+					},
+				},
+				ProtectionPolicy: &armcompute.VirtualMachineScaleSetVMProtectionPolicy{
+					ProtectFromScaleIn: to.Ptr(true),
+				},
+			},
+		},
+	}
+}
+
+// VM returns a control plane VM read with its instance view, placed in a proximity placement group.
+func VM() armcompute.VirtualMachine {
+	return armcompute.VirtualMachine{
+		ID:       to.Ptr(vmID),
+		Name:     to.Ptr("my-cluster-control-plane-x7k2p"),
+		Type:     to.Ptr("Microsoft.Compute/virtualMachines"),
+		Location: to.Ptr(Location),
+		Tags:     ownedTags(infrav1.ControlPlane),
+		Zones:    []*string{to.Ptr("2")},
+		Properties: &armcompute.VirtualMachineProperties{
+			ProvisioningState: to.Ptr("Succeeded"),
+			HardwareProfile: &armcompute.HardwareProfile{
+				VMSize: to.Ptr(armcompute.VirtualMachineSizeTypesStandardD2SV3),
+			},
+			StorageProfile: &armcompute.StorageProfile{
+				ImageReference: MarketplaceImageReference(),
+			},
+			ProximityPlacementGroup: &armcompute.SubResource{
+				ID: to.Ptr(resourceGroupID + "/providers/Microsoft.Compute/proximityPlacementGroups/my-cluster-ppg"),
+			},
+			InstanceView: &armcompute.VirtualMachineInstanceView{
+				PlatformFaultDomain:  to.Ptr[int32](1),
+				PlatformUpdateDomain: to.Ptr[int32](0),
+				Statuses: []*armcompute.InstanceViewStatus{
+					{Code: to.Ptr("ProvisioningState/succeeded"), Level: to.Ptr(armcompute.StatusLevelTypesInfo)},
+					{Code: to.Ptr("PowerState/running"), Level: to.Ptr(armcompute.StatusLevelTypesInfo)},
+				},
+			},
+		},
+	}
+}
+
+// MultiIPConfigNIC returns the network interface of a VM with a primary IPv4 configuration, a secondary IPv4
+// configuration, an IPv6 configuration, and a configuration whose private IP isn't allocated yet.
+func MultiIPConfigNIC() armnetwork.Interface {
+	return armnetwork.Interface{
+		ID:       to.Ptr(resourceGroupID + "/providers/Microsoft.Network/networkInterfaces/my-cluster-control-plane-x7k2p-nic"),
+		Name:     to.Ptr("my-cluster-control-plane-x7k2p-nic"),
+		Location: to.Ptr(Location),
+		Tags:     ownedTags(infrav1.ControlPlane),
+		Properties: &armnetwork.InterfacePropertiesFormat{
+			ProvisioningState:           to.Ptr(armnetwork.ProvisioningStateSucceeded),
+			EnableAcceleratedNetworking: to.Ptr(true),
+			DNSSettings: &armnetwork.InterfaceDNSSettings{
+				InternalDomainNameSuffix: to.Ptr("zq1xuwmqq4bezkaox1y5mdxfbc.ax.internal.cloudapp.net"),
+				InternalFqdn:             to.Ptr("my-cluster-control-plane-x7k2p.zq1xuwmqq4bezkaox1y5mdxfbc.ax.internal.cloudapp.net"),
+			},
+			IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
+				{
+					Name: to.Ptr("pipConfig"),
+					Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+						Primary:                   to.Ptr(true),
+						PrivateIPAddress:          to.Ptr("10.0.0.4"),
+						PrivateIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodDynamic),
+						PrivateIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv4),
+						Subnet:                    &armnetwork.Subnet{ID: to.Ptr(subnetID)},
+					},
+				},
+				{
+					Name: to.Ptr("ipConfigV4-1"),
+					Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+						Primary:                   to.Ptr(false),
+						PrivateIPAddress:          to.Ptr("10.0.0.5"),
+						PrivateIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodDynamic),
+						PrivateIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv4),
+						Subnet:                    &armnetwork.Subnet{ID: to.Ptr(subnetID)},
+					},
+				},
+				{
+					Name: to.Ptr("ipConfigV6"),
+					Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+						Primary:                   to.Ptr(false),
+						PrivateIPAddress:          to.Ptr("2001:1234:5678:9abd::4"),
+						PrivateIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodDynamic),
+						PrivateIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv6),
+						Subnet:                    &armnetwork.Subnet{ID: to.Ptr(subnetID)},
+					},
+				},
+				{
+					Name: to.Ptr("ipConfigV4-2"),
+					Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+						Primary:                   to.Ptr(false),
+						PrivateIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodDynamic),
+						PrivateIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv4),
+						Subnet:                    &armnetwork.Subnet{ID: to.Ptr(subnetID)},
+					},
+				},
+			},
+		},
+	}
+}
+
+// PublicIP returns the public IP of a node, with a DNS record.
+func PublicIP() armnetwork.PublicIPAddress {
+	return armnetwork.PublicIPAddress{
+		ID:       to.Ptr(resourceGroupID + "/providers/Microsoft.Network/publicIPAddresses/pip-my-cluster-control-plane-x7k2p"),
+		Name:     to.Ptr("pip-my-cluster-control-plane-x7k2p"),
+		Location: to.Ptr(Location),
+		Tags:     ownedTags(infrav1.ControlPlane),
+		SKU: &armnetwork.PublicIPAddressSKU{
+			Name: to.Ptr(armnetwork.PublicIPAddressSKUNameStandard),
+		},
+		Properties: &armnetwork.PublicIPAddressPropertiesFormat{
+			ProvisioningState:        to.Ptr(armnetwork.ProvisioningStateSucceeded),
+			PublicIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodStatic),
+			PublicIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv4),
+			IPAddress:                to.Ptr("20.86.12.34"),
+			DNSSettings: &armnetwork.PublicIPAddressDNSSettings{
+				DomainNameLabel: to.Ptr("my-cluster-control-plane-x7k2p"),
+				Fqdn:            to.Ptr("my-cluster-control-plane-x7k2p.westeurope.cloudapp.azure.com"),
+			},
+		},
+	}
+}
+
+// DualStackSubnet returns a dual-stack subnet, whose address prefixes are only reported in AddressPrefixes.
+func DualStackSubnet() armnetwork.Subnet {
+	return armnetwork.Subnet{
+		ID:   to.Ptr(subnetID),
+		Name: to.Ptr("node-subnet"),
+		Properties: &armnetwork.SubnetPropertiesFormat{
+			ProvisioningState: to.Ptr(armnetwork.ProvisioningStateSucceeded),
+			AddressPrefixes:   []*string{to.Ptr("10.1.0.0/16"), to.Ptr("2001:1234:5678:9abd::/64")},
+		},
+	}
+}
+
+// ManagedCluster returns an AKS cluster with a system and a user agent pool, the latter being stopped.
+func ManagedCluster() armcontainerservice.ManagedCluster {
+	return armcontainerservice.ManagedCluster{
+		ID:       to.Ptr(resourceGroupID + "/providers/Microsoft.ContainerService/managedClusters/" + ClusterName),
+		Name:     to.Ptr(ClusterName),
+		Location: to.Ptr(Location),
+		Tags:     ownedTags(infrav1.CommonRole),
+		Identity: &armcontainerservice.ManagedClusterIdentity{
+			Type:        to.Ptr(armcontainerservice.ResourceIdentityTypeSystemAssigned),
+			PrincipalID: to.Ptr("7a1c9a53-4c1e-4f4e-9c6b-0d9f3e2b5a61"),
+			TenantID:    to.Ptr("72f988bf-86f1-41af-91ab-2d7cd011db47"),
+		},
+		Properties: &armcontainerservice.ManagedClusterProperties{
+			ProvisioningState: to.Ptr("Succeeded"),
+			PowerState:        &armcontainerservice.PowerState{Code: to.Ptr(armcontainerservice.CodeRunning)},
+			KubernetesVersion: to.Ptr("1.23.5"),
+			DNSPrefix:         to.Ptr(ClusterName),
+			Fqdn:              to.Ptr("my-cluster-4f2b9c1d.hcp.westeurope.azmk8s.io"),
+			IdentityProfile: map[string]*armcontainerservice.UserAssignedIdentity{
+				"kubeletidentity": {
+					ResourceID: to.Ptr("/subscriptions/" + SubscriptionID + "/resourcegroups/MC_my-cluster-rg_my-cluster_westeurope/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-cluster-agentpool"),
+					ClientID:   to.Ptr("3c0c8f5e-1b5a-4b7e-8e2e-6a9d7b4c2f10"),
+					ObjectID:   to.Ptr("9e1d2c3b-4a5f-4e6d-8c7b-1a2b3c4d5e6f"),
+				},
+			},
+			AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+				{
+					Name:                to.Ptr("pool0"),
+					Count:               to.Ptr[int32](3),
+					VMSize:              to.Ptr("Standard_D2s_v3"),
+					Mode:                to.Ptr(armcontainerservice.AgentPoolModeSystem),
+					ProvisioningState:   to.Ptr("Succeeded"),
+					PowerState:          &armcontainerservice.PowerState{Code: to.Ptr(armcontainerservice.CodeRunning)},
+					OrchestratorVersion: to.Ptr("1.23.5"),
+					NodeImageVersion:    to.Ptr("AKSUbuntu-1804gen2containerd-2022.05.10"),
+				},
+				{
+					Name:                to.Ptr("pool1"),
+					Count:               to.Ptr[int32](0),
+					VMSize:              to.Ptr("Standard_D4s_v3"),
+					Mode:                to.Ptr(armcontainerservice.AgentPoolModeUser),
+					ProvisioningState:   to.Ptr("Succeeded"),
+					PowerState:          &armcontainerservice.PowerState{Code: to.Ptr(armcontainerservice.CodeStopped)},
+					OrchestratorVersion: to.Ptr("1.22.6"),
+					NodeImageVersion:    to.Ptr("AKSUbuntu-1804gen2containerd-2022.04.27"),
+				},
+			},
+		},
+	}
+}
+
+// AgentPool returns an agent pool of an AKS cluster being upgraded.
+func AgentPool() armcontainerservice.AgentPool {
+	return armcontainerservice.AgentPool{
+		ID:   to.Ptr(resourceGroupID + "/providers/Microsoft.ContainerService/managedClusters/" + ClusterName + "/agentPools/pool0"),
+		Name: to.Ptr("pool0"),
+		Properties: &armcontainerservice.ManagedClusterAgentPoolProfileProperties{
+			Count:               to.Ptr[int32](3),
+			VMSize:              to.Ptr("Standard_D2s_v3"),
+			Mode:                to.Ptr(armcontainerservice.AgentPoolModeSystem),
+			ProvisioningState:   to.Ptr("Upgrading"),
+			PowerState:          &armcontainerservice.PowerState{Code: to.Ptr(armcontainerservice.CodeRunning)},
+			OrchestratorVersion: to.Ptr("1.23.5"),
+			NodeImageVersion:    to.Ptr("AKSUbuntu-1804gen2containerd-2022.05.10"),
+		},
+	}
+}
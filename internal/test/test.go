@@ -18,7 +18,10 @@ package test
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"k8s.io/apimachinery/pkg/util/rand"
 )
 
@@ -26,3 +29,13 @@ import (
 func RandomName(prefix string, length int) string {
 	return fmt.Sprintf("%s-%s", prefix, rand.String(length))
 }
+
+// NewResponseError returns the error a track 2 Azure client returns for a response with the given status code.
+func NewResponseError(statusCode int) error {
+	return runtime.NewResponseError(&http.Response{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Body:       http.NoBody,
+		Request:    &http.Request{Method: http.MethodGet, URL: &url.URL{}},
+	})
+}
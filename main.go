@@ -28,6 +28,7 @@ import (
 	// +kubebuilder:scaffold:imports
 	aadpodv1 "github.com/Azure/aad-pod-identity/pkg/apis/aadpodidentity/v1"
 	"github.com/spf13/pflag"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -39,13 +40,19 @@ import (
 	infrav1alpha3 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha3"
 	infrav1alpha4 "sigs.k8s.io/cluster-api-provider-azure/api/v1alpha4"
 	infrav1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	infrav1beta2 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta2"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
 	"sigs.k8s.io/cluster-api-provider-azure/controllers"
 	infrav1alpha3exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha3"
 	infrav1alpha4exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1alpha4"
 	infrav1beta1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	infrav1controllersexp "sigs.k8s.io/cluster-api-provider-azure/exp/controllers"
 	"sigs.k8s.io/cluster-api-provider-azure/feature"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/addons"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
+	"sigs.k8s.io/cluster-api-provider-azure/pkg/inflight"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/ot"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/webhook"
@@ -70,9 +77,11 @@ func init() {
 	klog.InitFlags(nil)
 
 	_ = clientgoscheme.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
 	_ = infrav1alpha3.AddToScheme(scheme)
 	_ = infrav1alpha4.AddToScheme(scheme)
 	_ = infrav1beta1.AddToScheme(scheme)
+	_ = infrav1beta2.AddToScheme(scheme)
 	_ = infrav1alpha3exp.AddToScheme(scheme)
 	_ = infrav1alpha4exp.AddToScheme(scheme)
 	_ = infrav1beta1exp.AddToScheme(scheme)
@@ -96,25 +105,47 @@ func init() {
 }
 
 var (
-	metricsAddr                        string
-	enableLeaderElection               bool
-	leaderElectionNamespace            string
-	leaderElectionLeaseDuration        time.Duration
-	leaderElectionRenewDeadline        time.Duration
-	leaderElectionRetryPeriod          time.Duration
-	watchNamespace                     string
-	watchFilterValue                   string
-	profilerAddress                    string
-	azureClusterConcurrency            int
-	azureMachineConcurrency            int
-	azureMachinePoolConcurrency        int
-	azureMachinePoolMachineConcurrency int
-	debouncingTimer                    time.Duration
-	syncPeriod                         time.Duration
-	healthAddr                         string
-	webhookPort                        int
-	reconcileTimeout                   time.Duration
-	enableTracing                      bool
+	metricsAddr                         string
+	enableLeaderElection                bool
+	leaderElectionNamespace             string
+	leaderElectionLeaseDuration         time.Duration
+	leaderElectionRenewDeadline         time.Duration
+	leaderElectionRetryPeriod           time.Duration
+	watchNamespace                      string
+	watchFilterValue                    string
+	profilerAddress                     string
+	profilerControllers                 []string
+	azureClusterConcurrency             int
+	azureMachineConcurrency             int
+	azureMachinePoolConcurrency         int
+	azureMachinePoolMachineConcurrency  int
+	azureManagedControlPlaneConcurrency int
+	maxConcurrentAzureOperations        int
+	azureAPIVersions                    map[string]string
+	debouncingTimer                     time.Duration
+	syncPeriod                          time.Duration
+	healthAddr                          string
+	webhookPort                         int
+	reconcileTimeout                    time.Duration
+	enableTracing                       bool
+	addonsManifestsPath                 string
+	eventGridWebhookURL                 string
+	eventGridBindAddress                string
+	eventGridTokenFile                  string
+	eventGridCertDir                    string
+	verifierProbeImage                  string
+	verifierEgressURL                   string
+	verifierInterval                    time.Duration
+	migrateStorageVersions              bool
+	requireClusterFeatureOptIn          bool
+	strictAzureDataConversion           bool
+)
+
+// namingPolicy and taggingPolicy customize the names and the mandatory tags of the Azure resources of clusters.
+// Downstream builds can set them from an init function in a separate file of this package.
+var (
+	namingPolicy  azure.NamingPolicy
+	taggingPolicy azure.TaggingPolicy
 )
 
 // InitFlags initializes all command-line flags.
@@ -182,12 +213,46 @@ func InitFlags(fs *pflag.FlagSet) {
 		"Bind address to expose the pprof profiler (e.g. localhost:6060)",
 	)
 
+	fs.StringSliceVar(
+		&profilerControllers,
+		"profiler-controllers",
+		nil,
+		"Controllers whose reconciles are labeled in the pprof profiles and listed at /debug/reconciles on the profiler address, e.g. AzureMachine,AzureMachinePool. If unspecified, all the controllers are profiled.",
+	)
+
 	fs.IntVar(&azureClusterConcurrency,
 		"azurecluster-concurrency",
 		10,
 		"Number of AzureClusters to process simultaneously",
 	)
 
+	fs.StringVar(
+		&addonsManifestsPath,
+		"addons-manifests-path",
+		"",
+		"Directory containing the workload cluster addon manifests, used when the ClusterAddons feature flag is enabled. Defaults to the manifests shipped with the manager",
+	)
+
+	fs.StringVar(
+		&verifierProbeImage,
+		"verifier-probe-image",
+		"mcr.microsoft.com/cbl-mariner/busybox:2.0",
+		"Image of the connectivity probe run in workload clusters, used when the ConnectivityVerification feature flag is enabled",
+	)
+
+	fs.StringVar(
+		&verifierEgressURL,
+		"verifier-egress-url",
+		"https://mcr.microsoft.com/v2/",
+		"URL the connectivity probe downloads to verify the egress of workload clusters, used when the ConnectivityVerification feature flag is enabled",
+	)
+
+	fs.DurationVar(&verifierInterval,
+		"verifier-interval",
+		30*time.Minute,
+		"How often the connectivity of workload clusters is verified again, used when the ConnectivityVerification feature flag is enabled",
+	)
+
 	fs.IntVar(&azureMachineConcurrency,
 		"azuremachine-concurrency",
 		10,
@@ -204,6 +269,41 @@ func InitFlags(fs *pflag.FlagSet) {
 		10,
 		"Number of AzureMachinePoolMachines to process simultaneously")
 
+	fs.IntVar(&azureManagedControlPlaneConcurrency,
+		"azuremanagedcontrolplane-concurrency",
+		10,
+		"Number of AzureManagedControlPlanes to process simultaneously")
+
+	fs.IntVar(&maxConcurrentAzureOperations,
+		"max-concurrent-azure-operations",
+		0,
+		"Maximum number of long-running Azure operations in flight at the same time across all controllers (0 means no limit)")
+
+	fs.StringToStringVar(&azureAPIVersions,
+		"azure-api-versions",
+		map[string]string{},
+		"API versions to use instead of those of the Azure SDK for the requests on the given resource types, e.g. Microsoft.Network/loadBalancers=2023-11-01,Microsoft.Network/virtualNetworks=2023-11-01")
+
+	fs.StringVar(&eventGridWebhookURL,
+		"event-grid-webhook-url",
+		"",
+		"The public https URL Event Grid delivers resource notifications to. Only used when the EventGridNotifications feature gate is enabled")
+
+	fs.StringVar(&eventGridBindAddress,
+		"event-grid-bind-address",
+		":9445",
+		"The address the Event Grid resource notification receiver binds to")
+
+	fs.StringVar(&eventGridTokenFile,
+		"event-grid-token-file",
+		"",
+		"The file containing the secret token Event Grid must send to the resource notification receiver, e.g. a mounted Secret. Required with --event-grid-webhook-url")
+
+	fs.StringVar(&eventGridCertDir,
+		"event-grid-cert-dir",
+		"/tmp/k8s-event-grid-server/serving-certs",
+		"The directory containing the tls.crt and tls.key files the Event Grid resource notification receiver serves HTTPS with")
+
 	fs.DurationVar(&debouncingTimer,
 		"debouncing-timer",
 		10*time.Second,
@@ -241,6 +341,27 @@ func InitFlags(fs *pflag.FlagSet) {
 		"Enable tracing to the opentelemetry-collector service in the same namespace.",
 	)
 
+	fs.BoolVar(
+		&migrateStorageVersions,
+		"migrate-storage-versions",
+		false,
+		"Rewrite the infrastructure objects stored in an older API version than the storage version of their CRD, and remove the older versions from the stored versions of the CRD.",
+	)
+
+	fs.BoolVar(
+		&requireClusterFeatureOptIn,
+		"require-cluster-feature-opt-in",
+		false,
+		"Enable the AKS, MachinePool, EdgeZone and Windows features only for the clusters opting in to them in the "+feature.ClusterFeatureGatesAnnotation+" annotation of their Cluster, rather than for all the clusters not opting out.",
+	)
+
+	fs.BoolVar(
+		&strictAzureDataConversion,
+		"strict-azure-data-conversion",
+		true,
+		"Fail the reconciliation of the scale sets whose Azure data can't be converted faithfully, e.g. a malformed image ID, rather than converting it on a best effort basis.",
+	)
+
 	feature.MutableGates.AddFlag(fs)
 }
 
@@ -257,6 +378,8 @@ func main() {
 
 	if profilerAddress != "" {
 		setupLog.Info("Profiler listening for requests", "profiler-address", profilerAddress)
+		inflight.Enable(profilerControllers)
+		http.Handle("/debug/reconciles", inflight.Handler())
 		go func() {
 			setupLog.Error(http.ListenAndServe(profilerAddress, nil), "listen and serve error")
 		}()
@@ -322,15 +445,57 @@ func main() {
 }
 
 func registerControllers(ctx context.Context, mgr manager.Manager) {
+	async.SetMaxConcurrentOperations(maxConcurrentAzureOperations)
+
+	if err := azure.SetAPIVersions(azureAPIVersions); err != nil {
+		setupLog.Error(err, "unable to set the Azure API versions")
+		os.Exit(1)
+	}
+	feature.SetClusterOptInRequired(requireClusterFeatureOptIn)
+	converters.SetStrictConversion(strictAzureDataConversion)
+	azure.SetNamingPolicy(namingPolicy)
+	azure.SetTaggingPolicy(taggingPolicy)
+
+	var eventGridReceiver *controllers.EventGridReceiver
+	if feature.Gates.Enabled(feature.EventGridNotifications) && eventGridWebhookURL != "" {
+		var err error
+		eventGridReceiver, err = controllers.NewEventGridReceiver(mgr.GetClient(), ctrl.Log.WithName("eventgrid"), eventGridBindAddress, eventGridWebhookURL,
+			eventGridTokenFile, eventGridCertDir)
+		if err != nil {
+			setupLog.Error(err, "unable to create Event Grid receiver")
+			os.Exit(1)
+		}
+		if err := mgr.Add(eventGridReceiver); err != nil {
+			setupLog.Error(err, "unable to add Event Grid receiver")
+			os.Exit(1)
+		}
+	}
+
+	if migrateStorageVersions {
+		if err := mgr.Add(&controllers.StorageVersionMigrator{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Log:       ctrl.Log.WithName("storageversionmigrator"),
+		}); err != nil {
+			setupLog.Error(err, "unable to add storage version migrator")
+			os.Exit(1)
+		}
+	}
+
 	machineCache, err := coalescing.NewRequestCache(debouncingTimer)
 	if err != nil {
 		setupLog.Error(err, "failed to build machineCache ReconcileCache")
 	}
-	if err := controllers.NewAzureMachineReconciler(mgr.GetClient(),
+
+	amr := controllers.NewAzureMachineReconciler(mgr.GetClient(),
 		mgr.GetEventRecorderFor("azuremachine-reconciler"),
 		reconcileTimeout,
 		watchFilterValue,
-	).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}, Cache: machineCache}); err != nil {
+	)
+	if eventGridReceiver != nil {
+		amr.ResourceNotifications = eventGridReceiver.MachineEvents
+	}
+	if err := amr.SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachineConcurrency}, Cache: machineCache}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureMachine")
 		os.Exit(1)
 	}
@@ -339,12 +504,17 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 	if err != nil {
 		setupLog.Error(err, "failed to build clusterCache ReconcileCache")
 	}
-	if err := controllers.NewAzureClusterReconciler(
+	acr := controllers.NewAzureClusterReconciler(
 		mgr.GetClient(),
 		mgr.GetEventRecorderFor("azurecluster-reconciler"),
 		reconcileTimeout,
 		watchFilterValue,
-	).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}, Cache: clusterCache}); err != nil {
+	)
+	if eventGridReceiver != nil {
+		acr.ResourceNotifications = eventGridReceiver.ClusterEvents
+		acr.EventGridWebhookURL = eventGridReceiver.SubscriptionURL()
+	}
+	if err := acr.SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}, Cache: clusterCache}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AzureCluster")
 		os.Exit(1)
 	}
@@ -369,6 +539,48 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 		os.Exit(1)
 	}
 
+	if err := (&controllers.AzureJSONClusterReconciler{
+		Client:           mgr.GetClient(),
+		Recorder:         mgr.GetEventRecorderFor("azurejsoncluster-reconciler"),
+		ReconcileTimeout: reconcileTimeout,
+		WatchFilterValue: watchFilterValue,
+	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AzureJSONCluster")
+		os.Exit(1)
+	}
+
+	if feature.Gates.Enabled(feature.ClusterAddons) {
+		catalog := addons.NewDefaultCatalog()
+		if addonsManifestsPath != "" {
+			catalog = addons.NewCatalog(addonsManifestsPath)
+		}
+		if err := (&controllers.AzureClusterAddonsReconciler{
+			Client:           mgr.GetClient(),
+			Recorder:         mgr.GetEventRecorderFor("azureclusteraddons-reconciler"),
+			ReconcileTimeout: reconcileTimeout,
+			WatchFilterValue: watchFilterValue,
+			Catalog:          catalog,
+		}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AzureClusterAddons")
+			os.Exit(1)
+		}
+	}
+
+	if feature.Gates.Enabled(feature.ConnectivityVerification) {
+		if err := (&controllers.AzureClusterVerifierReconciler{
+			Client:               mgr.GetClient(),
+			Recorder:             mgr.GetEventRecorderFor("azureclusterverifier-reconciler"),
+			ReconcileTimeout:     reconcileTimeout,
+			WatchFilterValue:     watchFilterValue,
+			ProbeImage:           verifierProbeImage,
+			EgressURL:            verifierEgressURL,
+			VerificationInterval: verifierInterval,
+		}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AzureClusterVerifier")
+			os.Exit(1)
+		}
+	}
+
 	if err := (&controllers.AzureIdentityReconciler{
 		Client:           mgr.GetClient(),
 		Recorder:         mgr.GetEventRecorderFor("azureidentity-reconciler"),
@@ -422,6 +634,18 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 			os.Exit(1)
 		}
 
+		if feature.Gates.Enabled(feature.MachinePoolPrescaling) {
+			if err := (&infrav1controllersexp.AzureMachinePoolPrescaleReconciler{
+				Client:           mgr.GetClient(),
+				Recorder:         mgr.GetEventRecorderFor("azuremachinepoolprescale-reconciler"),
+				ReconcileTimeout: reconcileTimeout,
+				WatchFilterValue: watchFilterValue,
+			}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureMachinePoolConcurrency}); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "AzureMachinePoolPrescale")
+				os.Exit(1)
+			}
+		}
+
 		if feature.Gates.Enabled(feature.AKS) {
 			mmpmCache, err := coalescing.NewRequestCache(debouncingTimer)
 			if err != nil {
@@ -463,7 +687,7 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 				Recorder:         mgr.GetEventRecorderFor("azuremanagedcontrolplane-reconciler"),
 				ReconcileTimeout: reconcileTimeout,
 				WatchFilterValue: watchFilterValue,
-			}).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}, Cache: mcpCache}); err != nil {
+			}).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureManagedControlPlaneConcurrency}, Cache: mcpCache}); err != nil {
 				setupLog.Error(err, "unable to create controller", "controller", "AzureManagedControlPlane")
 				os.Exit(1)
 			}
@@ -515,6 +739,13 @@ func registerWebhooks(mgr manager.Manager) {
 		os.Exit(1)
 	}
 
+	mgr.GetWebhookServer().Register("/validate-infrastructure-cluster-x-k8s-io-v1beta1-budget",
+		infrav1beta1exp.NewBudgetWebhook(mgr.GetClient()))
+	mgr.GetWebhookServer().Register("/validate-infrastructure-cluster-x-k8s-io-v1beta1-crosssubscriptionaccess",
+		controllers.NewCrossSubscriptionAccessWebhook(mgr.GetClient()))
+	mgr.GetWebhookServer().Register("/validate-infrastructure-cluster-x-k8s-io-v1beta1-identitynamespace",
+		controllers.NewIdentityNamespaceWebhook(mgr.GetClient()))
+
 	if feature.Gates.Enabled(feature.AKS) {
 		hookServer := mgr.GetWebhookServer()
 		hookServer.Register("/mutate-infrastructure-cluster-x-k8s-io-v1beta1-azuremanagedmachinepool", webhook.NewMutatingWebhook(
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package addons resolves the manifests of the workload cluster addons installed by the Azure provider.
+package addons
+
+import (
+	"embed"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+// Addon is the name of a workload cluster addon.
+type Addon string
+
+const (
+	// CloudProviderAzure is the external cloud provider for Azure (cloud-controller-manager and cloud-node-manager).
+	CloudProviderAzure Addon = "cloud-provider-azure"
+	// AzureDiskCSIDriver is the Azure Disk CSI driver.
+	AzureDiskCSIDriver Addon = "azuredisk-csi-driver"
+	// AzureFileCSIDriver is the Azure File CSI driver.
+	AzureFileCSIDriver Addon = "azurefile-csi-driver"
+
+	manifestExtension = ".yaml"
+
+	// ClusterNameVariable is replaced with the name of the cluster in the manifests.
+	ClusterNameVariable = "${CLUSTER_NAME}"
+)
+
+// manifests holds the addon manifests shipped with the manager.
+//
+//go:embed manifests
+var manifests embed.FS
+
+// Catalog resolves addon manifests from a directory laid out as <root>/<addon>/v<major>.<minor>.yaml,
+// where each file holds the manifest of the addon built for that Kubernetes minor version.
+type Catalog struct {
+	fsys fs.FS
+}
+
+// NewCatalog returns a Catalog reading manifests from the given root directory.
+func NewCatalog(root string) *Catalog {
+	return &Catalog{fsys: os.DirFS(root)}
+}
+
+// NewDefaultCatalog returns a Catalog reading the manifests shipped with the manager.
+func NewDefaultCatalog() *Catalog {
+	fsys, err := fs.Sub(manifests, "manifests")
+	if err != nil {
+		// the embedded directory always exists.
+		panic(err)
+	}
+	return &Catalog{fsys: fsys}
+}
+
+// Manifest returns the manifest of the addon built for the newest Kubernetes minor version that is not newer than
+// kubernetesVersion, along with that minor version.
+func (c *Catalog) Manifest(addon Addon, kubernetesVersion string) (string, []byte, error) {
+	k8sVersion, err := semver.ParseTolerant(kubernetesVersion)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to parse Kubernetes version %q", kubernetesVersion)
+	}
+
+	entries, err := fs.ReadDir(c.fsys, string(addon))
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to list manifests for addon %s", addon)
+	}
+
+	var best *semver.Version
+	var bestFile string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), manifestExtension) {
+			continue
+		}
+		v, err := semver.ParseTolerant(strings.TrimSuffix(entry.Name(), manifestExtension))
+		if err != nil {
+			continue
+		}
+		if v.Major != k8sVersion.Major || v.Minor > k8sVersion.Minor {
+			continue
+		}
+		if best == nil || v.GT(*best) {
+			v := v
+			best = &v
+			bestFile = entry.Name()
+		}
+	}
+	if best == nil {
+		return "", nil, errors.Errorf("no manifest for addon %s is compatible with Kubernetes version %s", addon, kubernetesVersion)
+	}
+
+	manifest, err := fs.ReadFile(c.fsys, path.Join(string(addon), bestFile))
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to read manifest for addon %s", addon)
+	}
+
+	return strings.TrimSuffix(bestFile, manifestExtension), manifest, nil
+}
+
+// Objects splits a multi-document YAML manifest into the objects it contains, once the variables of the manifest are
+// replaced with the values of the given cluster.
+func Objects(manifest []byte, clusterName string) ([]unstructured.Unstructured, error) {
+	manifest = []byte(strings.ReplaceAll(string(manifest), ClusterNameVariable, clusterName))
+	objs, err := utilyaml.ToUnstructured(manifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse addon manifest")
+	}
+	return objs, nil
+}
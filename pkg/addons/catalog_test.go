@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCatalogManifest(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, string(CloudProviderAzure))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"v1.21.yaml", "v1.23.yaml", "v1.24.yaml", "README.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name              string
+		addon             Addon
+		kubernetesVersion string
+		wantVersion       string
+		wantErr           bool
+	}{
+		{
+			name:              "exact minor version",
+			addon:             CloudProviderAzure,
+			kubernetesVersion: "v1.23.5",
+			wantVersion:       "v1.23",
+		},
+		{
+			name:              "falls back to the newest older minor version",
+			addon:             CloudProviderAzure,
+			kubernetesVersion: "v1.22.3",
+			wantVersion:       "v1.21",
+		},
+		{
+			name:              "no compatible version",
+			addon:             CloudProviderAzure,
+			kubernetesVersion: "v1.20.0",
+			wantErr:           true,
+		},
+		{
+			name:              "unknown addon",
+			addon:             AzureFileCSIDriver,
+			kubernetesVersion: "v1.23.5",
+			wantErr:           true,
+		},
+		{
+			name:              "invalid kubernetes version",
+			addon:             CloudProviderAzure,
+			kubernetesVersion: "latest",
+			wantErr:           true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			version, manifest, err := NewCatalog(root).Manifest(tc.addon, tc.kubernetesVersion)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(version).To(Equal(tc.wantVersion))
+			g.Expect(string(manifest)).To(Equal(tc.wantVersion + ".yaml"))
+		})
+	}
+}
+
+func TestObjects(t *testing.T) {
+	g := NewWithT(t)
+	objs, err := Objects([]byte(`apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: cloud-controller-manager
+  namespace: kube-system
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: kube-system
+data:
+  clusterName: ${CLUSTER_NAME}
+`), "my-cluster")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objs).To(HaveLen(2))
+	g.Expect(objs[0].GetKind()).To(Equal("ServiceAccount"))
+	g.Expect(objs[1].GetName()).To(Equal("foo"))
+	g.Expect(objs[1].Object).To(HaveKeyWithValue("data", HaveKeyWithValue("clusterName", "my-cluster")))
+}
+
+func TestDefaultCatalog(t *testing.T) {
+	for _, addon := range []Addon{CloudProviderAzure, AzureDiskCSIDriver, AzureFileCSIDriver} {
+		for _, kubernetesVersion := range []string{"v1.22.9", "v1.23.6", "v1.24.1"} {
+			addon, kubernetesVersion := addon, kubernetesVersion
+			t.Run(fmt.Sprintf("%s %s", addon, kubernetesVersion), func(t *testing.T) {
+				g := NewWithT(t)
+				_, manifest, err := NewDefaultCatalog().Manifest(addon, kubernetesVersion)
+				g.Expect(err).NotTo(HaveOccurred())
+				objs, err := Objects(manifest, "my-cluster")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(objs).NotTo(BeEmpty())
+				for _, obj := range objs {
+					g.Expect(obj.GetKind()).NotTo(BeEmpty())
+					g.Expect(obj.GetName()).NotTo(BeEmpty())
+				}
+			})
+		}
+	}
+}
+
+func TestDefaultCatalogCloudProviderVersions(t *testing.T) {
+	g := NewWithT(t)
+	for kubernetesVersion, image := range map[string]string{
+		"v1.22.9": "azure-cloud-controller-manager:v1.1.18",
+		"v1.23.6": "azure-cloud-controller-manager:v1.23.15",
+		"v1.24.1": "azure-cloud-controller-manager:v1.24.2",
+	} {
+		_, manifest, err := NewDefaultCatalog().Manifest(CloudProviderAzure, kubernetesVersion)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(string(manifest)).To(ContainSubstring(image))
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inflight
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"sync"
+	"time"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type (
+	// Operation is a long-running Azure operation a reconcile is waiting on.
+	Operation struct {
+		Type          string    `json:"type"`
+		ServiceName   string    `json:"serviceName"`
+		ResourceGroup string    `json:"resourceGroup,omitempty"`
+		Name          string    `json:"name"`
+		Since         time.Time `json:"since"`
+	}
+
+	// Reconcile is a reconcile in flight.
+	Reconcile struct {
+		Controller string      `json:"controller"`
+		Request    string      `json:"request"`
+		Started    time.Time   `json:"started"`
+		Duration   string      `json:"duration"`
+		Operations []Operation `json:"operations,omitempty"`
+	}
+
+	// Snapshot is the state of the reconciles in flight and of the Go runtime at a point in time.
+	Snapshot struct {
+		Time           time.Time   `json:"time"`
+		Goroutines     int         `json:"goroutines"`
+		HeapAllocBytes uint64      `json:"heapAllocBytes"`
+		HeapInuseBytes uint64      `json:"heapInuseBytes"`
+		NumGC          uint32      `json:"numGC"`
+		Reconciles     []Reconcile `json:"reconciles"`
+	}
+
+	// Tracker tracks the reconciles in flight of the enabled controllers and the long-running operations they wait on.
+	Tracker struct {
+		mu          sync.Mutex
+		enabled     bool
+		controllers map[string]struct{}
+		inFlight    map[*Reconcile]struct{}
+		now         func() time.Time
+	}
+
+	// reconciler is the tracking reconciler middleware.
+	reconciler struct {
+		controller string
+		upstream   reconcile.Reconciler
+		tracker    *Tracker
+	}
+
+	reconcileKey struct{}
+)
+
+// defaultTracker is the tracker of all the controllers of the manager.
+var defaultTracker = NewTracker()
+
+// NewTracker creates a new disabled Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		inFlight: make(map[*Reconcile]struct{}),
+		now:      time.Now,
+	}
+}
+
+// Enable enables the tracking of the given controllers by the default tracker, or of all the controllers if none is
+// given.
+func Enable(controllers []string) {
+	defaultTracker.Enable(controllers)
+}
+
+// NewReconciler returns a reconcile wrapper tracking the reconciles of a controller with the default tracker.
+func NewReconciler(controller string, upstream reconcile.Reconciler) reconcile.Reconciler {
+	return defaultTracker.NewReconciler(controller, upstream)
+}
+
+// WaitingOn records that the reconcile of the context is waiting on a long-running operation.
+func WaitingOn(ctx context.Context, future infrav1.Future) {
+	defaultTracker.WaitingOn(ctx, future)
+}
+
+// Handler returns the HTTP handler serving the snapshot of the default tracker as JSON.
+func Handler() http.Handler {
+	return defaultTracker.Handler()
+}
+
+// Enable enables the tracking of the given controllers, or of all the controllers if none is given.
+func (t *Tracker) Enable(controllers []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.enabled = true
+	t.controllers = nil
+	if len(controllers) > 0 {
+		t.controllers = make(map[string]struct{}, len(controllers))
+		for _, c := range controllers {
+			t.controllers[c] = struct{}{}
+		}
+	}
+}
+
+// isEnabled returns true if the reconciles of a controller are tracked.
+func (t *Tracker) isEnabled(controller string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.enabled {
+		return false
+	}
+	if t.controllers == nil {
+		return true
+	}
+	_, ok := t.controllers[controller]
+	return ok
+}
+
+// NewReconciler returns a reconcile wrapper tracking the reconciles of a controller. The reconciles of an enabled
+// controller are labeled with the controller and the request in the CPU and goroutine profiles.
+func (t *Tracker) NewReconciler(controller string, upstream reconcile.Reconciler) reconcile.Reconciler {
+	return &reconciler{
+		controller: controller,
+		upstream:   upstream,
+		tracker:    t,
+	}
+}
+
+// Reconcile tracks the request while it is reconciled by the upstream reconciler.
+func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (result reconcile.Result, err error) {
+	if !r.tracker.isEnabled(r.controller) {
+		return r.upstream.Reconcile(ctx, req)
+	}
+
+	rec := &Reconcile{
+		Controller: r.controller,
+		Request:    req.String(),
+	}
+	r.tracker.start(rec)
+	defer r.tracker.finish(rec)
+
+	pprof.Do(context.WithValue(ctx, reconcileKey{}, rec), pprof.Labels("controller", r.controller, "request", rec.Request), func(ctx context.Context) {
+		result, err = r.upstream.Reconcile(ctx, req)
+	})
+	return result, err
+}
+
+func (t *Tracker) start(rec *Reconcile) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rec.Started = t.now()
+	t.inFlight[rec] = struct{}{}
+}
+
+func (t *Tracker) finish(rec *Reconcile) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.inFlight, rec)
+}
+
+// WaitingOn records that the reconcile of the context is waiting on a long-running operation. It does nothing if the
+// reconcile isn't tracked.
+func (t *Tracker) WaitingOn(ctx context.Context, future infrav1.Future) {
+	rec, ok := ctx.Value(reconcileKey{}).(*Reconcile)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	op := Operation{
+		Type:          future.Type,
+		ServiceName:   future.ServiceName,
+		ResourceGroup: future.ResourceGroup,
+		Name:          future.Name,
+		Since:         t.now(),
+	}
+	for i, existing := range rec.Operations {
+		if existing.ServiceName == op.ServiceName && existing.ResourceGroup == op.ResourceGroup && existing.Name == op.Name {
+			op.Since = existing.Since
+			rec.Operations[i] = op
+			return
+		}
+	}
+	rec.Operations = append(rec.Operations, op)
+}
+
+// Snapshot returns the reconciles in flight, oldest first, and the state of the Go runtime.
+func (t *Tracker) Snapshot() Snapshot {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	reconciles := make([]Reconcile, 0, len(t.inFlight))
+	for rec := range t.inFlight {
+		r := *rec
+		r.Operations = append([]Operation(nil), rec.Operations...)
+		r.Duration = now.Sub(rec.Started).Round(time.Second).String()
+		reconciles = append(reconciles, r)
+	}
+	sort.Slice(reconciles, func(i, j int) bool {
+		return reconciles[i].Started.Before(reconciles[j].Started)
+	})
+
+	return Snapshot{
+		Time:           now,
+		Goroutines:     runtime.NumGoroutine(),
+		HeapAllocBytes: memStats.HeapAlloc,
+		HeapInuseBytes: memStats.HeapInuse,
+		NumGC:          memStats.NumGC,
+		Reconciles:     reconciles,
+	}
+}
+
+// Handler returns the HTTP handler serving the snapshot of the tracker as JSON.
+func (t *Tracker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(t.Snapshot()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
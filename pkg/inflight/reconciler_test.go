@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inflight
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type reconcileFunc func(context.Context, reconcile.Request) (reconcile.Result, error)
+
+func (f reconcileFunc) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return f(ctx, req)
+}
+
+func TestTracker(t *testing.T) {
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "my-machine"}}
+	future := infrav1.Future{Type: infrav1.PutFuture, ServiceName: "virtualmachine", ResourceGroup: "my-rg", Name: "my-machine"}
+
+	testcases := []struct {
+		name        string
+		controllers []string
+		enabled     bool
+		tracked     bool
+	}{
+		{
+			name:    "disabled",
+			enabled: false,
+			tracked: false,
+		},
+		{
+			name:    "all the controllers enabled",
+			enabled: true,
+			tracked: true,
+		},
+		{
+			name:        "controller enabled",
+			controllers: []string{"AzureMachine"},
+			enabled:     true,
+			tracked:     true,
+		},
+		{
+			name:        "other controllers enabled",
+			controllers: []string{"AzureCluster"},
+			enabled:     true,
+			tracked:     false,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			tracker := NewTracker()
+			now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+			tracker.now = func() time.Time { return now }
+			if tc.enabled {
+				tracker.Enable(tc.controllers)
+			}
+
+			var during Snapshot
+			r := tracker.NewReconciler("AzureMachine", reconcileFunc(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+				tracker.WaitingOn(ctx, future)
+				now = now.Add(time.Minute)
+				tracker.WaitingOn(ctx, future)
+				during = tracker.Snapshot()
+
+				label, ok := pprof.Label(ctx, "controller")
+				g.Expect(ok).To(Equal(tc.tracked))
+				if tc.tracked {
+					g.Expect(label).To(Equal("AzureMachine"))
+				}
+				return reconcile.Result{RequeueAfter: time.Second}, nil
+			}))
+
+			result, err := r.Reconcile(context.Background(), request)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result.RequeueAfter).To(Equal(time.Second))
+			g.Expect(tracker.Snapshot().Reconciles).To(BeEmpty())
+
+			if !tc.tracked {
+				g.Expect(during.Reconciles).To(BeEmpty())
+				return
+			}
+			g.Expect(during.Reconciles).To(HaveLen(1))
+			rec := during.Reconciles[0]
+			g.Expect(rec.Controller).To(Equal("AzureMachine"))
+			g.Expect(rec.Request).To(Equal("default/my-machine"))
+			g.Expect(rec.Duration).To(Equal("1m0s"))
+			g.Expect(rec.Operations).To(Equal([]Operation{{
+				Type:          infrav1.PutFuture,
+				ServiceName:   "virtualmachine",
+				ResourceGroup: "my-rg",
+				Name:          "my-machine",
+				Since:         now.Add(-time.Minute),
+			}}))
+		})
+	}
+}
+
+func TestTrackerHandler(t *testing.T) {
+	g := NewWithT(t)
+	tracker := NewTracker()
+	tracker.Enable(nil)
+
+	var body Snapshot
+	r := tracker.NewReconciler("AzureCluster", reconcileFunc(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		w := httptest.NewRecorder()
+		tracker.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/debug/reconciles", nil))
+		g.Expect(w.Header().Get("Content-Type")).To(Equal("application/json"))
+		g.Expect(json.Unmarshal(w.Body.Bytes(), &body)).To(Succeed())
+		return reconcile.Result{}, nil
+	}))
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "my-cluster"}})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(body.Goroutines).To(BeNumerically(">", 0))
+	g.Expect(body.HeapAllocBytes).To(BeNumerically(">", 0))
+	g.Expect(body.Reconciles).To(HaveLen(1))
+	g.Expect(body.Reconciles[0].Controller).To(Equal("AzureCluster"))
+}
@@ -22,14 +22,16 @@ package e2e
 import (
 	"context"
 	"fmt"
+	"os"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2020-02-01/containerservice"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v7"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	"golang.org/x/mod/semver"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	infraexpv1 "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -223,10 +225,9 @@ func GetAKSKubernetesVersion(ctx context.Context, e2eConfig *clusterctl.E2EConfi
 
 	location := e2eConfig.GetVariable(AzureLocation)
 
-	settings, err := auth.GetSettingsFromEnvironment()
-	Expect(err).NotTo(HaveOccurred())
-	subscriptionID := settings.GetSubscriptionID()
+	subscriptionID := os.Getenv(AzureSubscriptionId)
 	var maxVersion string
+	var err error
 	if e2eAKSVersion == "latest" {
 		maxVersion, err = GetLatestStableAKSKubernetesVersion(ctx, subscriptionID, location)
 		Expect(err).NotTo(HaveOccurred())
@@ -253,19 +254,17 @@ func byClusterOptions(name, namespace string) []client.ListOption {
 // If the desired version is not available, we check for any available patch version using desired version's Major.Minor semver release.
 // If no versions are available in the desired version's Major.Minor semver release, we return an error.
 func GetWorkingAKSKubernetesVersion(ctx context.Context, subscriptionID, location, version string) (string, error) {
-	settings, err := auth.GetSettingsFromEnvironment()
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
 	if err != nil {
-		return "", errors.Wrap(err, "GetSettingsFromEnvironment failed.")
+		return "", errors.Wrap(err, "Failed to get default Azure credential.")
 	}
-	authorizer, err := settings.GetAuthorizer()
+	managedClustersClient, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
 	if err != nil {
-		return "", errors.Wrap(err, "Failed to create an Authorizer.")
+		return "", errors.Wrap(err, "Failed to create a managed clusters client.")
 	}
-	containerServiceClient := containerservice.NewContainerServicesClient(subscriptionID)
-	containerServiceClient.Authorizer = authorizer
-	result, err := containerServiceClient.ListOrchestrators(ctx, location, ManagedClustersResourceType)
+	result, err := managedClustersClient.ListKubernetesVersions(ctx, location, nil)
 	if err != nil {
-		return "", errors.Wrap(err, "Failed to list Orchestrators.")
+		return "", errors.Wrap(err, "Failed to list Kubernetes versions.")
 	}
 
 	var latestStableVersionDesired bool
@@ -285,21 +284,23 @@ func GetWorkingAKSKubernetesVersion(ctx context.Context, subscriptionID, locatio
 	baseVersion := fmt.Sprintf("%s.0", semver.MajorMinor(version))
 	maxVersion := fmt.Sprintf("%s.0", semver.MajorMinor(version))
 	var foundWorkingVersion bool
-	for _, o := range *result.Orchestrators {
-		orchVersion := *o.OrchestratorVersion
-		// semver comparisons require a "v" prefix
-		if orchVersion[:1] != "v" {
-			orchVersion = fmt.Sprintf("v%s", *o.OrchestratorVersion)
-		}
-		// if the inputted version matches with an available AKS version we can return immediately
-		if orchVersion == version && !latestStableVersionDesired {
-			return version, nil
-		}
+	for _, v := range result.Values {
+		for patchVersion := range v.PatchVersions {
+			orchVersion := patchVersion
+			// semver comparisons require a "v" prefix
+			if orchVersion[:1] != "v" {
+				orchVersion = fmt.Sprintf("v%s", patchVersion)
+			}
+			// if the inputted version matches with an available AKS version we can return immediately
+			if orchVersion == version && !latestStableVersionDesired {
+				return version, nil
+			}
 
-		// or, keep track of the highest aks version for a given major.minor
-		if semver.MajorMinor(orchVersion) == semver.MajorMinor(maxVersion) && semver.Compare(orchVersion, maxVersion) >= 0 {
-			maxVersion = orchVersion
-			foundWorkingVersion = true
+			// or, keep track of the highest aks version for a given major.minor
+			if semver.MajorMinor(orchVersion) == semver.MajorMinor(maxVersion) && semver.Compare(orchVersion, maxVersion) >= 0 {
+				maxVersion = orchVersion
+				foundWorkingVersion = true
+			}
 		}
 	}
 
@@ -313,19 +314,17 @@ func GetWorkingAKSKubernetesVersion(ctx context.Context, subscriptionID, locatio
 
 // GetLatestStableAKSKubernetesVersion returns the latest stable available Kubernetes version of AKS.
 func GetLatestStableAKSKubernetesVersion(ctx context.Context, subscriptionID, location string) (string, error) {
-	settings, err := auth.GetSettingsFromEnvironment()
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
 	if err != nil {
-		return "", errors.Wrap(err, "GetSettingsFromEnvironment failed.")
+		return "", errors.Wrap(err, "Failed to get default Azure credential.")
 	}
-	authorizer, err := settings.GetAuthorizer()
+	managedClustersClient, err := armcontainerservice.NewManagedClustersClient(subscriptionID, cred, nil)
 	if err != nil {
-		return "", errors.Wrap(err, "Failed to create an Authorizer.")
+		return "", errors.Wrap(err, "Failed to create a managed clusters client.")
 	}
-	containerServiceClient := containerservice.NewContainerServicesClient(subscriptionID)
-	containerServiceClient.Authorizer = authorizer
-	result, err := containerServiceClient.ListOrchestrators(ctx, location, ManagedClustersResourceType)
+	result, err := managedClustersClient.ListKubernetesVersions(ctx, location, nil)
 	if err != nil {
-		return "", errors.Wrap(err, "Failed to list Orchestrators.")
+		return "", errors.Wrap(err, "Failed to list Kubernetes versions.")
 	}
 
 	var orchestratorversions []string
@@ -333,13 +332,15 @@ func GetLatestStableAKSKubernetesVersion(ctx context.Context, subscriptionID, lo
 	var orchVersion string
 	var maxVersion string
 
-	for _, o := range *result.Orchestrators {
-		orchVersion = *o.OrchestratorVersion
-		// semver comparisons require a "v" prefix
-		if orchVersion[:1] != "v" && o.IsPreview == nil {
-			orchVersion = fmt.Sprintf("v%s", *o.OrchestratorVersion)
+	for _, v := range result.Values {
+		for patchVersion := range v.PatchVersions {
+			orchVersion = patchVersion
+			// semver comparisons require a "v" prefix
+			if orchVersion[:1] != "v" && !pointer.BoolDeref(v.IsPreview, false) {
+				orchVersion = fmt.Sprintf("v%s", patchVersion)
+			}
+			orchestratorversions = append(orchestratorversions, orchVersion)
 		}
-		orchestratorversions = append(orchestratorversions, orchVersion)
 	}
 	semver.Sort(orchestratorversions)
 	maxVersion = orchestratorversions[len(orchestratorversions)-1]
@@ -32,15 +32,15 @@ import (
 	"time"
 
 	aadpodv1 "github.com/Azure/aad-pod-identity/pkg/apis/aadpodidentity/v1"
-	"github.com/Azure/azure-sdk-for-go/profiles/2020-09-01/monitor/mgmt/insights"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	expv1 "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	clusterv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
@@ -52,9 +52,6 @@ type (
 	AzureClusterProxy struct {
 		framework.ClusterProxy
 	}
-	// myEventData is used to be able to Marshal insights.EventData into JSON
-	// see https://github.com/Azure/azure-sdk-for-go/issues/8224#issuecomment-614777550
-	myEventData insights.EventData
 )
 
 func NewAzureClusterProxy(name string, kubeconfigPath string, options ...framework.Option) *AzureClusterProxy {
@@ -209,13 +206,10 @@ func (acp *AzureClusterProxy) collectPodLogs(ctx context.Context, namespace stri
 func (acp *AzureClusterProxy) collectActivityLogs(ctx context.Context, namespace, name, aboveMachinesPath string) {
 	timeoutctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
-	settings, err := auth.GetSettingsFromEnvironment()
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
 	Expect(err).NotTo(HaveOccurred())
-	subscriptionID := settings.GetSubscriptionID()
-	authorizer, err := settings.GetAuthorizer()
+	activityLogsClient, err := armmonitor.NewActivityLogsClient(os.Getenv(AzureSubscriptionId), cred, nil)
 	Expect(err).NotTo(HaveOccurred())
-	activityLogsClient := insights.NewActivityLogsClient(subscriptionID)
-	activityLogsClient.Authorizer = authorizer
 
 	workloadCluster, err := getAzureCluster(ctx, acp.GetClient(), namespace, name)
 	if err != nil {
@@ -228,12 +222,7 @@ func (acp *AzureClusterProxy) collectActivityLogs(ctx context.Context, namespace
 	start := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
 	end := time.Now().UTC().Format(time.RFC3339)
 
-	itr, err := activityLogsClient.ListComplete(timeoutctx, fmt.Sprintf("eventTimestamp ge '%s' and eventTimestamp le '%s' and resourceGroupName eq '%s'", start, end, groupName), "")
-	if err != nil {
-		// Failing to fetch logs should not cause the test to fail
-		Byf("Error fetching activity logs for resource group %s: %v", groupName, err)
-		return
-	}
+	pager := activityLogsClient.NewListPager(fmt.Sprintf("eventTimestamp ge '%s' and eventTimestamp le '%s' and resourceGroupName eq '%s'", start, end, groupName), nil)
 
 	logFile := path.Join(aboveMachinesPath, activitylog, groupName+".log")
 	Expect(os.MkdirAll(filepath.Dir(logFile), 0755)).To(Succeed())
@@ -248,19 +237,22 @@ func (acp *AzureClusterProxy) collectActivityLogs(ctx context.Context, namespace
 	out := bufio.NewWriter(f)
 	defer out.Flush()
 
-	for ; itr.NotDone(); err = itr.NextWithContext(timeoutctx) {
+	for pager.More() {
+		page, err := pager.NextPage(timeoutctx)
 		if err != nil {
+			// Failing to fetch logs should not cause the test to fail
 			Byf("Got error while iterating over activity logs for resource group %s: %v", groupName, err)
 			return
 		}
-		event := itr.Value()
-		if to.String(event.Category.Value) != "Policy" {
-			b, err := json.MarshalIndent(myEventData(event), "", "    ")
-			if err != nil {
-				Byf("Got error converting activity logs data to json: %v", err)
-			}
-			if _, err = out.WriteString(string(b) + "\n"); err != nil {
-				Byf("Got error while writing activity logs for resource group %s: %v", groupName, err)
+		for _, event := range page.Value {
+			if event.Category == nil || pointer.StringDeref(event.Category.Value, "") != "Policy" {
+				b, err := json.MarshalIndent(event, "", "    ")
+				if err != nil {
+					Byf("Got error converting activity logs data to json: %v", err)
+				}
+				if _, err = out.WriteString(string(b) + "\n"); err != nil {
+					Byf("Got error while writing activity logs for resource group %s: %v", groupName, err)
+				}
 			}
 		}
 	}
@@ -36,9 +36,9 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	autorest "github.com/Azure/go-autorest/autorest/azure"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/pkg/errors"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
@@ -365,28 +365,26 @@ func collectVMBootLog(ctx context.Context, am *v1beta1.AzureMachine, outputPath
 	}
 
 	resourceId := strings.TrimPrefix(*am.Spec.ProviderID, azure.ProviderIDPrefix)
-	resource, err := autorest.ParseResourceID(resourceId)
+	resource, err := arm.ParseResourceID(resourceId)
 	if err != nil {
 		return errors.Wrap(err, "failed to parse resource id")
 	}
 
-	settings, err := auth.GetSettingsFromEnvironment()
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
 	if err != nil {
-		return errors.Wrap(err, "failed to get settings from environment")
+		return errors.Wrap(err, "failed to get default azure credential")
 	}
-
-	vmClient := compute.NewVirtualMachinesClient(settings.GetSubscriptionID())
-	vmClient.Authorizer, err = settings.GetAuthorizer()
+	vmClient, err := armcompute.NewVirtualMachinesClient(resource.SubscriptionID, cred, nil)
 	if err != nil {
-		return errors.Wrap(err, "failed to get authorizer")
+		return errors.Wrap(err, "failed to create virtual machines client")
 	}
 
-	bootDiagnostics, err := vmClient.RetrieveBootDiagnosticsData(ctx, resource.ResourceGroup, resource.ResourceName, nil)
+	bootDiagnostics, err := vmClient.RetrieveBootDiagnosticsData(ctx, resource.ResourceGroupName, resource.Name, nil)
 	if err != nil {
 		return errors.Wrap(err, "failed to get boot diagnostics data")
 	}
 
-	return writeBootLog(bootDiagnostics, outputPath)
+	return writeBootLog(bootDiagnostics.RetrieveBootDiagnosticsDataResult, outputPath)
 }
 
 // collectVMSSBootLog collects boot logs of the scale set by using azure boot diagnostics.
@@ -395,33 +393,31 @@ func collectVMSSBootLog(ctx context.Context, providerID string, outputPath strin
 	v := strings.Split(resourceId, "/")
 	instanceId := v[len(v)-1]
 	resourceId = strings.TrimSuffix(resourceId, "/virtualMachines/"+instanceId)
-	resource, err := autorest.ParseResourceID(resourceId)
+	resource, err := arm.ParseResourceID(resourceId)
 	if err != nil {
 		return errors.Wrap(err, "failed to parse resource id")
 	}
 
-	Logf("Collecting boot logs for VMSS instance %s of scale set %s\n", instanceId, resource.ResourceName)
+	Logf("Collecting boot logs for VMSS instance %s of scale set %s\n", instanceId, resource.Name)
 
-	settings, err := auth.GetSettingsFromEnvironment()
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
 	if err != nil {
-		return errors.Wrap(err, "failed to get settings from environment")
+		return errors.Wrap(err, "failed to get default azure credential")
 	}
-
-	vmssClient := compute.NewVirtualMachineScaleSetVMsClient(settings.GetSubscriptionID())
-	vmssClient.Authorizer, err = settings.GetAuthorizer()
+	vmssClient, err := armcompute.NewVirtualMachineScaleSetVMsClient(resource.SubscriptionID, cred, nil)
 	if err != nil {
-		return errors.Wrap(err, "failed to get authorizer")
+		return errors.Wrap(err, "failed to create virtual machine scale set VMs client")
 	}
 
-	bootDiagnostics, err := vmssClient.RetrieveBootDiagnosticsData(ctx, resource.ResourceGroup, resource.ResourceName, instanceId, nil)
+	bootDiagnostics, err := vmssClient.RetrieveBootDiagnosticsData(ctx, resource.ResourceGroupName, resource.Name, instanceId, nil)
 	if err != nil {
 		return errors.Wrap(err, "failed to get boot diagnostics data")
 	}
 
-	return writeBootLog(bootDiagnostics, outputPath)
+	return writeBootLog(bootDiagnostics.RetrieveBootDiagnosticsDataResult, outputPath)
 }
 
-func writeBootLog(bootDiagnostics compute.RetrieveBootDiagnosticsDataResult, outputPath string) error {
+func writeBootLog(bootDiagnostics armcompute.RetrieveBootDiagnosticsDataResult, outputPath string) error {
 	var err error
 	resp, err := http.Get(*bootDiagnostics.SerialConsoleLogBlobURI)
 	if err != nil || resp.StatusCode != 200 {
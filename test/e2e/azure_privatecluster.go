@@ -24,16 +24,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
-
-	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-11-01/compute"
-	"github.com/Azure/azure-sdk-for-go/services/msi/mgmt/2018-11-30/msi"
-	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
-	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
-	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-05-01/resources"
-	azuresdk "github.com/Azure/go-autorest/autorest/azure"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
-	"github.com/Azure/go-autorest/autorest/to"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
@@ -42,6 +39,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/armtemplate"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capi_e2e "sigs.k8s.io/cluster-api/test/e2e"
 	"sigs.k8s.io/cluster-api/test/framework"
@@ -185,11 +183,10 @@ func AzurePrivateClusterSpec(ctx context.Context, inputGetter func() AzurePrivat
 	// Check that azure bastion is provisioned successfully.
 	{
 		By("verifying the Azure Bastion Host was create successfully")
-		settings, err := auth.GetSettingsFromEnvironment()
+		cred, err := azidentity.NewDefaultAzureCredential(nil)
 		Expect(err).To(BeNil())
 
-		azureBastionClient := network.NewBastionHostsClient(settings.GetSubscriptionID())
-		azureBastionClient.Authorizer, err = settings.GetAuthorizer()
+		azureBastionClient, err := armnetwork.NewBastionHostsClient(os.Getenv(AzureSubscriptionId), cred, nil)
 		Expect(err).To(BeNil())
 
 		groupName := os.Getenv(AzureResourceGroup)
@@ -202,19 +199,23 @@ func AzurePrivateClusterSpec(ctx context.Context, inputGetter func() AzurePrivat
 			Steps:    retryBackoffSteps,
 		}
 		retryFn := func() (bool, error) {
-			bastion, err := azureBastionClient.Get(ctx, groupName, azureBastionName)
+			bastion, err := azureBastionClient.Get(ctx, groupName, azureBastionName, nil)
 			if err != nil {
 				return false, err
 			}
 
-			switch bastion.ProvisioningState {
-			case network.ProvisioningStateSucceeded:
+			var provisioningState armnetwork.ProvisioningState
+			if bastion.Properties != nil && bastion.Properties.ProvisioningState != nil {
+				provisioningState = *bastion.Properties.ProvisioningState
+			}
+			switch provisioningState {
+			case armnetwork.ProvisioningStateSucceeded:
 				return true, nil
-			case network.ProvisioningStateUpdating:
+			case armnetwork.ProvisioningStateUpdating:
 				// Wait for operation to complete.
 				return false, nil
 			default:
-				return false, errors.New(fmt.Sprintf("Azure Bastion provisioning failed with state: %q", bastion.ProvisioningState))
+				return false, errors.New(fmt.Sprintf("Azure Bastion provisioning failed with state: %q", provisioningState))
 			}
 		}
 		err = wait.ExponentialBackoff(backoff, retryFn)
@@ -226,42 +227,40 @@ func AzurePrivateClusterSpec(ctx context.Context, inputGetter func() AzurePrivat
 // SetupExistingVNet creates a resource group and a VNet to be used by a workload cluster.
 func SetupExistingVNet(ctx context.Context, vnetCidr string, cpSubnetCidrs, nodeSubnetCidrs map[string]string, bastionSubnetName, bastionSubnetCidr string) func() {
 	By("creating Azure clients with the workload cluster's subscription")
-	settings, err := auth.GetSettingsFromEnvironment()
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	Expect(err).NotTo(HaveOccurred())
+	subscriptionID := os.Getenv(AzureSubscriptionId)
+	groupClient, err := armresources.NewResourceGroupsClient(subscriptionID, cred, nil)
+	Expect(err).NotTo(HaveOccurred())
+	vnetClient, err := armnetwork.NewVirtualNetworksClient(subscriptionID, cred, nil)
+	Expect(err).NotTo(HaveOccurred())
+	nsgClient, err := armnetwork.NewSecurityGroupsClient(subscriptionID, cred, nil)
 	Expect(err).NotTo(HaveOccurred())
-	subscriptionID := settings.GetSubscriptionID()
-	authorizer, err := settings.GetAuthorizer()
+	routetableClient, err := armnetwork.NewRouteTablesClient(subscriptionID, cred, nil)
 	Expect(err).NotTo(HaveOccurred())
-	groupClient := resources.NewGroupsClient(subscriptionID)
-	groupClient.Authorizer = authorizer
-	vnetClient := network.NewVirtualNetworksClient(subscriptionID)
-	vnetClient.Authorizer = authorizer
-	nsgClient := network.NewSecurityGroupsClient(subscriptionID)
-	nsgClient.Authorizer = authorizer
-	routetableClient := network.NewRouteTablesClient(subscriptionID)
-	routetableClient.Authorizer = authorizer
 
 	By("creating a resource group")
 	groupName := os.Getenv(AzureResourceGroup)
-	_, err = groupClient.CreateOrUpdate(ctx, groupName, resources.Group{
+	_, err = groupClient.CreateOrUpdate(ctx, groupName, armresources.ResourceGroup{
 		Location: pointer.StringPtr(os.Getenv(AzureLocation)),
 		Tags: map[string]*string{
 			"jobName":           pointer.StringPtr(os.Getenv(JobName)),
 			"creationTimestamp": pointer.StringPtr(os.Getenv(Timestamp)),
 		},
-	})
+	}, nil)
 	Expect(err).To(BeNil())
 
 	By("creating a network security group")
 	nsgName := "control-plane-nsg"
-	securityRules := []network.SecurityRule{
+	securityRules := []*armnetwork.SecurityRule{
 		{
 			Name: pointer.StringPtr("allow_ssh"),
-			SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+			Properties: &armnetwork.SecurityRulePropertiesFormat{
 				Description:              pointer.StringPtr("Allow SSH"),
 				Priority:                 pointer.Int32Ptr(2200),
-				Protocol:                 network.SecurityRuleProtocolTCP,
-				Access:                   network.SecurityRuleAccessAllow,
-				Direction:                network.SecurityRuleDirectionInbound,
+				Protocol:                 to.Ptr(armnetwork.SecurityRuleProtocolTCP),
+				Access:                   to.Ptr(armnetwork.SecurityRuleAccessAllow),
+				Direction:                to.Ptr(armnetwork.SecurityRuleDirectionInbound),
 				SourceAddressPrefix:      pointer.StringPtr("*"),
 				SourcePortRange:          pointer.StringPtr("*"),
 				DestinationAddressPrefix: pointer.StringPtr("*"),
@@ -270,60 +269,60 @@ func SetupExistingVNet(ctx context.Context, vnetCidr string, cpSubnetCidrs, node
 		},
 		{
 			Name: pointer.StringPtr("allow_apiserver"),
-			SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+			Properties: &armnetwork.SecurityRulePropertiesFormat{
 				Description:              pointer.StringPtr("Allow API Server"),
 				SourcePortRange:          pointer.StringPtr("*"),
 				DestinationPortRange:     pointer.StringPtr("6443"),
 				SourceAddressPrefix:      pointer.StringPtr("*"),
 				DestinationAddressPrefix: pointer.StringPtr("*"),
-				Protocol:                 network.SecurityRuleProtocolTCP,
-				Access:                   network.SecurityRuleAccessAllow,
-				Direction:                network.SecurityRuleDirectionInbound,
+				Protocol:                 to.Ptr(armnetwork.SecurityRuleProtocolTCP),
+				Access:                   to.Ptr(armnetwork.SecurityRuleAccessAllow),
+				Direction:                to.Ptr(armnetwork.SecurityRuleDirectionInbound),
 				Priority:                 pointer.Int32Ptr(2201),
 			},
 		},
 	}
-	nsgFuture, err := nsgClient.CreateOrUpdate(ctx, groupName, nsgName, network.SecurityGroup{
+	nsgPoller, err := nsgClient.BeginCreateOrUpdate(ctx, groupName, nsgName, armnetwork.SecurityGroup{
 		Location: pointer.StringPtr(os.Getenv(AzureLocation)),
-		SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
-			SecurityRules: &securityRules,
+		Properties: &armnetwork.SecurityGroupPropertiesFormat{
+			SecurityRules: securityRules,
 		},
-	})
+	}, nil)
 	Expect(err).To(BeNil())
-	err = nsgFuture.WaitForCompletionRef(ctx, nsgClient.Client)
+	_, err = nsgPoller.PollUntilDone(ctx, nil)
 	Expect(err).To(BeNil())
 
 	By("creating a node security group")
 	nsgNodeName := "node-nsg"
-	securityRulesNode := []network.SecurityRule{}
-	nsgNodeFuture, err := nsgClient.CreateOrUpdate(ctx, groupName, nsgNodeName, network.SecurityGroup{
+	securityRulesNode := []*armnetwork.SecurityRule{}
+	nsgNodePoller, err := nsgClient.BeginCreateOrUpdate(ctx, groupName, nsgNodeName, armnetwork.SecurityGroup{
 		Location: pointer.StringPtr(os.Getenv(AzureLocation)),
-		SecurityGroupPropertiesFormat: &network.SecurityGroupPropertiesFormat{
-			SecurityRules: &securityRulesNode,
+		Properties: &armnetwork.SecurityGroupPropertiesFormat{
+			SecurityRules: securityRulesNode,
 		},
-	})
+	}, nil)
 	Expect(err).To(BeNil())
-	err = nsgNodeFuture.WaitForCompletionRef(ctx, nsgClient.Client)
+	_, err = nsgNodePoller.PollUntilDone(ctx, nil)
 	Expect(err).To(BeNil())
 
 	By("creating a node routetable")
 	routeTableName := "node-routetable"
-	routeTable := network.RouteTable{
-		Location:                   pointer.StringPtr(os.Getenv(AzureLocation)),
-		RouteTablePropertiesFormat: &network.RouteTablePropertiesFormat{},
+	routeTable := armnetwork.RouteTable{
+		Location:   pointer.StringPtr(os.Getenv(AzureLocation)),
+		Properties: &armnetwork.RouteTablePropertiesFormat{},
 	}
-	routetableFuture, err := routetableClient.CreateOrUpdate(ctx, groupName, routeTableName, routeTable)
+	routetablePoller, err := routetableClient.BeginCreateOrUpdate(ctx, groupName, routeTableName, routeTable, nil)
 	Expect(err).To(BeNil())
-	err = routetableFuture.WaitForCompletionRef(ctx, routetableClient.Client)
+	_, err = routetablePoller.PollUntilDone(ctx, nil)
 	Expect(err).To(BeNil())
 
 	By("creating a virtual network")
-	var subnets []network.Subnet
+	var subnets []*armnetwork.Subnet
 	for name, cidr := range cpSubnetCidrs {
-		subnets = append(subnets, network.Subnet{
-			SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+		subnets = append(subnets, &armnetwork.Subnet{
+			Properties: &armnetwork.SubnetPropertiesFormat{
 				AddressPrefix: pointer.StringPtr(cidr),
-				NetworkSecurityGroup: &network.SecurityGroup{
+				NetworkSecurityGroup: &armnetwork.SecurityGroup{
 					ID: pointer.StringPtr(fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/networkSecurityGroups/%s", subscriptionID, groupName, nsgName)),
 				},
 			},
@@ -331,13 +330,13 @@ func SetupExistingVNet(ctx context.Context, vnetCidr string, cpSubnetCidrs, node
 		})
 	}
 	for name, cidr := range nodeSubnetCidrs {
-		subnets = append(subnets, network.Subnet{
-			SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+		subnets = append(subnets, &armnetwork.Subnet{
+			Properties: &armnetwork.SubnetPropertiesFormat{
 				AddressPrefix: pointer.StringPtr(cidr),
-				NetworkSecurityGroup: &network.SecurityGroup{
+				NetworkSecurityGroup: &armnetwork.SecurityGroup{
 					ID: pointer.StringPtr(fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/networkSecurityGroups/%s", subscriptionID, groupName, nsgNodeName)),
 				},
-				RouteTable: &network.RouteTable{
+				RouteTable: &armnetwork.RouteTable{
 					ID: pointer.StringPtr(fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/routeTables/%s", subscriptionID, groupName, routeTableName)),
 				},
 			},
@@ -346,68 +345,76 @@ func SetupExistingVNet(ctx context.Context, vnetCidr string, cpSubnetCidrs, node
 	}
 
 	// Create the AzureBastion subnet.
-	subnets = append(subnets, network.Subnet{
-		SubnetPropertiesFormat: &network.SubnetPropertiesFormat{
+	subnets = append(subnets, &armnetwork.Subnet{
+		Properties: &armnetwork.SubnetPropertiesFormat{
 			AddressPrefix: pointer.StringPtr(bastionSubnetCidr),
 		},
 		Name: pointer.StringPtr(bastionSubnetName),
 	})
 
-	vnetFuture, err := vnetClient.CreateOrUpdate(ctx, groupName, os.Getenv(AzureCustomVNetName), network.VirtualNetwork{
+	vnetPoller, err := vnetClient.BeginCreateOrUpdate(ctx, groupName, os.Getenv(AzureCustomVNetName), armnetwork.VirtualNetwork{
 		Location: pointer.StringPtr(os.Getenv(AzureLocation)),
-		VirtualNetworkPropertiesFormat: &network.VirtualNetworkPropertiesFormat{
-			AddressSpace: &network.AddressSpace{
-				AddressPrefixes: &[]string{vnetCidr},
+		Properties: &armnetwork.VirtualNetworkPropertiesFormat{
+			AddressSpace: &armnetwork.AddressSpace{
+				AddressPrefixes: []*string{pointer.StringPtr(vnetCidr)},
 			},
-			Subnets: &subnets,
+			Subnets: subnets,
 		},
-	})
+	}, nil)
 	if err != nil {
 		fmt.Print(err.Error())
 	}
 	Expect(err).To(BeNil())
-	err = vnetFuture.WaitForCompletionRef(ctx, vnetClient.Client)
+	_, err = vnetPoller.PollUntilDone(ctx, nil)
 	Expect(err).To(BeNil())
 
 	return func() {
 		Logf("deleting an existing virtual network %q", os.Getenv(AzureCustomVNetName))
-		vFuture, err := vnetClient.Delete(ctx, groupName, os.Getenv(AzureCustomVNetName))
+		vPoller, err := vnetClient.BeginDelete(ctx, groupName, os.Getenv(AzureCustomVNetName), nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = vPoller.PollUntilDone(ctx, nil)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(vFuture.WaitForCompletionRef(ctx, vnetClient.Client)).To(Succeed())
 
 		Logf("deleting an existing route table %q", routeTableName)
-		rtFuture, err := routetableClient.Delete(ctx, groupName, routeTableName)
+		rtPoller, err := routetableClient.BeginDelete(ctx, groupName, routeTableName, nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = rtPoller.PollUntilDone(ctx, nil)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(rtFuture.WaitForCompletionRef(ctx, routetableClient.Client)).To(Succeed())
 
 		Logf("deleting an existing network security group %q", nsgNodeName)
-		nsgFuture, err := nsgClient.Delete(ctx, groupName, nsgNodeName)
+		nsgPoller, err := nsgClient.BeginDelete(ctx, groupName, nsgNodeName, nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = nsgPoller.PollUntilDone(ctx, nil)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(nsgFuture.WaitForCompletionRef(ctx, nsgClient.Client)).To(Succeed())
 
 		Logf("deleting an existing network security group %q", nsgName)
-		nsgFuture, err = nsgClient.Delete(ctx, groupName, nsgName)
+		nsgPoller, err = nsgClient.BeginDelete(ctx, groupName, nsgName, nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = nsgPoller.PollUntilDone(ctx, nil)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(nsgFuture.WaitForCompletionRef(ctx, nsgClient.Client)).To(Succeed())
 
 		Logf("verifying the existing resource group %q is empty", groupName)
-		resClient := resources.NewClient(subscriptionID)
-		resClient.Authorizer = authorizer
-		Eventually(func() ([]resources.GenericResourceExpanded, error) {
-			page, err := resClient.ListByResourceGroup(ctx, groupName, "", "provisioningState", to.Int32Ptr(10))
+		resClient, err := armresources.NewClient(subscriptionID, cred, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() ([]*armresources.GenericResourceExpanded, error) {
+			pager := resClient.NewListByResourceGroupPager(groupName, &armresources.ClientListByResourceGroupOptions{
+				Expand: to.Ptr("provisioningState"),
+				Top:    to.Ptr[int32](10),
+			})
+			page, err := pager.NextPage(ctx)
 			if err != nil {
 				return nil, err
 			}
 
 			// for each resource do a GET directly for that resource to avoid hitting Azure list cache
-			var foundResources []resources.GenericResourceExpanded
-			for _, genericResource := range page.Values() {
+			var foundResources []*armresources.GenericResourceExpanded
+			for _, genericResource := range page.Value {
 				apiversion, err := getAPIVersion(*genericResource.ID)
 				if err != nil {
 					LogWarningf("failed to get API version for %q with %+v", *genericResource.ID, err)
 				}
 
-				_, err = resClient.GetByID(ctx, *genericResource.ID, apiversion)
+				_, err = resClient.GetByID(ctx, *genericResource.ID, apiversion, nil)
 				if err != nil && azure.ResourceNotFound(err) {
 					// the resources is returned in the list, but it's actually 404
 					continue
@@ -427,52 +434,51 @@ func SetupExistingVNet(ctx context.Context, vnetCidr string, cpSubnetCidrs, node
 		}, deleteOperationTimeout, retryableOperationTimeout).Should(BeEmpty(), "Expect the manually created resource group is empty after removing the manually created resources.")
 
 		Logf("deleting the existing resource group %q", groupName)
-		grpFuture, err := groupClient.Delete(ctx, groupName)
+		grpPoller, err := groupClient.BeginDelete(ctx, groupName, nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = grpPoller.PollUntilDone(ctx, nil)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(grpFuture.WaitForCompletionRef(ctx, nsgClient.Client)).To(Succeed())
 	}
 }
 
 func getAPIVersion(resourceID string) (string, error) {
-	parsed, err := azuresdk.ParseResourceID(resourceID)
+	parsed, err := arm.ParseResourceID(resourceID)
 	if err != nil {
 		return "", errors.Wrap(err, fmt.Sprintf("unable to parse resource ID %q", resourceID))
 	}
 
-	switch parsed.Provider {
+	// Use the top-level resource type so that child resources share the API version of their parent.
+	resourceType := parsed.ResourceType.Types[0]
+	switch parsed.ResourceType.Namespace {
 	case "Microsoft.Network":
-		if parsed.ResourceType == "privateDnsZones" {
-			return getAPIVersionFromUserAgent(privatedns.UserAgent()), nil
+		if resourceType == "privateDnsZones" {
+			return armtemplate.PrivateDNSAPIVersion, nil
 		}
-		return getAPIVersionFromUserAgent(network.UserAgent()), nil
+		return armtemplate.NetworkAPIVersion, nil
 	case "Microsoft.Compute":
-		return getAPIVersionFromUserAgent(compute.UserAgent()), nil
+		if resourceType == "disks" {
+			return armtemplate.DisksAPIVersion, nil
+		}
+		return armtemplate.ComputeAPIVersion, nil
 	default:
-		return "", fmt.Errorf("failed to find an API version for resource provider %q", parsed.Provider)
+		return "", fmt.Errorf("failed to find an API version for resource provider %q", parsed.ResourceType.Namespace)
 	}
 }
 
-func getAPIVersionFromUserAgent(userAgent string) string {
-	splits := strings.Split(userAgent, "/")
-	return splits[len(splits)-1]
-}
-
 // getClientIDforMSI fetches the client ID of a user assigned identity.
 func getClientIDforMSI(resourceID string) string {
-	settings, err := auth.GetSettingsFromEnvironment()
-	Expect(err).NotTo(HaveOccurred())
-	subscriptionID := settings.GetSubscriptionID()
-	authorizer, err := settings.GetAuthorizer()
+	parsed, err := arm.ParseResourceID(resourceID)
 	Expect(err).NotTo(HaveOccurred())
 
-	msiClient := msi.NewUserAssignedIdentitiesClient(subscriptionID)
-	msiClient.Authorizer = authorizer
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	Expect(err).NotTo(HaveOccurred())
 
-	parsed, err := azuresdk.ParseResourceID(resourceID)
+	msiClient, err := armmsi.NewUserAssignedIdentitiesClient(parsed.SubscriptionID, cred, nil)
 	Expect(err).NotTo(HaveOccurred())
 
-	id, err := msiClient.Get(context.TODO(), parsed.ResourceGroup, parsed.ResourceName)
+	id, err := msiClient.Get(context.TODO(), parsed.ResourceGroupName, parsed.Name, nil)
 	Expect(err).NotTo(HaveOccurred())
+	Expect(id.Properties).NotTo(BeNil())
 
-	return id.ClientID.String()
+	return pointer.StringDeref(id.Properties.ClientID, "")
 }
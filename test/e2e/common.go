@@ -28,8 +28,8 @@ import (
 	"path"
 	"path/filepath"
 
-	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2020-10-01/resources"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -71,7 +71,6 @@ const (
 	AKSKubernetesVersion           = "AKS_KUBERNETES_VERSION"
 	SecurityScanFailThreshold      = "SECURITY_SCAN_FAIL_THRESHOLD"
 	SecurityScanContainer          = "SECURITY_SCAN_CONTAINER"
-	ManagedClustersResourceType    = "managedClusters"
 	capiImagePublisher             = "cncf-upstream"
 	capiOfferName                  = "capi"
 	capiWindowsOfferName           = "capi-windows"
@@ -181,14 +180,11 @@ func dumpSpecResourcesAndCleanup(ctx context.Context, input cleanupInput) {
 // ExpectResourceGroupToBe404 performs a GET request to Azure to determine if the cluster resource group still exists.
 // If it does still exist, it means the cluster was not deleted and is leaking Azure resources.
 func ExpectResourceGroupToBe404(ctx context.Context) {
-	settings, err := auth.GetSettingsFromEnvironment()
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
 	Expect(err).NotTo(HaveOccurred())
-	subscriptionID := settings.GetSubscriptionID()
-	authorizer, err := settings.GetAuthorizer()
+	groupsClient, err := armresources.NewResourceGroupsClient(os.Getenv(AzureSubscriptionId), cred, nil)
 	Expect(err).NotTo(HaveOccurred())
-	groupsClient := resources.NewGroupsClient(subscriptionID)
-	groupsClient.Authorizer = authorizer
-	_, err = groupsClient.Get(ctx, os.Getenv(AzureResourceGroup))
+	_, err = groupsClient.Get(ctx, os.Getenv(AzureResourceGroup), nil)
 	Expect(azure.ResourceNotFound(err)).To(BeTrue(), "The resource group in Azure still exists. After deleting the cluster all of the Azure resources should also be deleted.")
 }
 
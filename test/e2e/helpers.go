@@ -38,8 +38,8 @@ import (
 
 	capi_e2e "sigs.k8s.io/cluster-api/test/e2e"
 
-	"github.com/Azure/azure-sdk-for-go/profiles/2020-09-01/compute/mgmt/compute"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/blang/semver"
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/config"
@@ -745,14 +745,11 @@ func resolveKubernetesVersion(config *clusterctl.E2EConfig, versions semver.Vers
 }
 
 // newImagesClient returns a new VM images client using environmental settings for auth.
-func newImagesClient() compute.VirtualMachineImagesClient {
-	settings, err := auth.GetSettingsFromEnvironment()
+func newImagesClient() *armcompute.VirtualMachineImagesClient {
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
 	Expect(err).NotTo(HaveOccurred())
-	subscriptionID := settings.GetSubscriptionID()
-	authorizer, err := settings.GetAuthorizer()
+	imagesClient, err := armcompute.NewVirtualMachineImagesClient(os.Getenv(AzureSubscriptionId), cred, nil)
 	Expect(err).NotTo(HaveOccurred())
-	imagesClient := compute.NewVirtualMachineImagesClient(subscriptionID)
-	imagesClient.Authorizer = authorizer
 
 	return imagesClient
 }
@@ -765,19 +762,19 @@ func getVersionsInOffer(ctx context.Context, location, publisher, offer string)
 	capiVersion := regexp.MustCompile(`^(\d)(\d{1,2})\.(\d{1,2})\.\d{8}$`)
 	oldCapiSku := regexp.MustCompile(`^k8s-(0|[1-9][0-9]*)dot(0|[1-9][0-9]*)dot(0|[1-9][0-9]*)-[a-z]*.*$`)
 	imagesClient := newImagesClient()
-	skus, err := imagesClient.ListSkus(ctx, location, publisher, offer)
+	skus, err := imagesClient.ListSKUs(ctx, location, publisher, offer, nil)
 	Expect(err).NotTo(HaveOccurred())
 
-	if skus.Value != nil {
-		versions = make(map[string]semver.Version, len(*skus.Value))
-		for _, sku := range *skus.Value {
-			res, err := imagesClient.List(ctx, location, publisher, offer, *sku.Name, "", nil, "")
+	if skus.VirtualMachineImageResourceArray != nil {
+		versions = make(map[string]semver.Version, len(skus.VirtualMachineImageResourceArray))
+		for _, sku := range skus.VirtualMachineImageResourceArray {
+			res, err := imagesClient.List(ctx, location, publisher, offer, *sku.Name, nil)
 			Expect(err).NotTo(HaveOccurred())
 			// Don't use SKUs without existing images. See https://github.com/Azure/azure-cli/issues/20115.
-			if res.Value != nil && len(*res.Value) > 0 {
+			if len(res.VirtualMachineImageResourceArray) > 0 {
 				// New SKUs don't contain the Kubernetes version and are named like "ubuntu-2004-gen1".
 				if match := capiSku.FindStringSubmatch(*sku.Name); len(match) > 0 {
-					for _, vmImage := range *res.Value {
+					for _, vmImage := range res.VirtualMachineImageResourceArray {
 						// Versions are named like "121.13.20220601", for Kubernetes v1.21.13 published on June 1, 2022.
 						match = capiVersion.FindStringSubmatch(*vmImage.Name)
 						stringVer := fmt.Sprintf("%s.%s.%s", match[1], match[2], match[3])
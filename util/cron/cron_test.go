@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Spec    string
+		WantErr bool
+	}{
+		{Name: "every minute", Spec: "* * * * *"},
+		{Name: "weekday mornings", Spec: "0 7 * * 1-5"},
+		{Name: "lists and steps", Spec: "*/15 0,12 1-15/2 * 0,7"},
+		{Name: "missing field", Spec: "0 7 * *", WantErr: true},
+		{Name: "out of range", Spec: "0 24 * * *", WantErr: true},
+		{Name: "inverted range", Spec: "0 7 * * 5-1", WantErr: true},
+		{Name: "invalid step", Spec: "*/0 * * * *", WantErr: true},
+		{Name: "not a number", Spec: "0 7 * * MON", WantErr: true},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			_, err := Parse(c.Spec)
+			if c.WantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestSchedule_Prev(t *testing.T) {
+	// Wednesday
+	now := time.Date(2022, 6, 15, 10, 42, 30, 0, time.UTC)
+
+	cases := []struct {
+		Name     string
+		Spec     string
+		Expected time.Time
+	}{
+		{Name: "every minute", Spec: "* * * * *", Expected: time.Date(2022, 6, 15, 10, 42, 0, 0, time.UTC)},
+		{Name: "earlier today", Spec: "0 7 * * 1-5", Expected: time.Date(2022, 6, 15, 7, 0, 0, 0, time.UTC)},
+		{Name: "yesterday", Spec: "0 20 * * 1-5", Expected: time.Date(2022, 6, 14, 20, 0, 0, 0, time.UTC)},
+		{Name: "last weekend", Spec: "30 9 * * 0,6", Expected: time.Date(2022, 6, 12, 9, 30, 0, 0, time.UTC)},
+		{Name: "sunday as 7", Spec: "30 9 * * 7", Expected: time.Date(2022, 6, 12, 9, 30, 0, 0, time.UTC)},
+		{Name: "steps", Spec: "*/20 */5 * * *", Expected: time.Date(2022, 6, 15, 10, 40, 0, 0, time.UTC)},
+		{Name: "last month", Spec: "0 0 1 5 *", Expected: time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "day of month or day of week", Spec: "0 0 13 * 1", Expected: time.Date(2022, 6, 13, 0, 0, 0, 0, time.UTC)},
+		{Name: "last year", Spec: "0 0 31 12 *", Expected: time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			schedule, err := Parse(c.Spec)
+			g.Expect(err).NotTo(HaveOccurred())
+			prev, ok := schedule.Prev(now)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(prev).To(Equal(c.Expected))
+		})
+	}
+
+	g := NewWithT(t)
+	schedule, err := Parse("0 0 30 2 *")
+	g.Expect(err).NotTo(HaveOccurred())
+	_, ok := schedule.Prev(now)
+	g.Expect(ok).To(BeFalse())
+}
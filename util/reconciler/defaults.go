@@ -29,6 +29,10 @@ const (
 	DefaultAzureServiceReconcileTimeout = 12 * time.Second
 	// DefaultAzureCallTimeout is the default timeout for an Azure request after which an Azure operation is considered long running.
 	DefaultAzureCallTimeout = 2 * time.Second
+	// DefaultAzureListTimeout is the default timeout for listing all the pages of an Azure list operation.
+	DefaultAzureListTimeout = 10 * time.Second
+	// DefaultAzureListMaxPages is the default maximum number of pages of an Azure list operation.
+	DefaultAzureListMaxPages = 100
 	// DefaultReconcilerRequeue is the default value for the reconcile retry.
 	DefaultReconcilerRequeue = 15 * time.Second
 )
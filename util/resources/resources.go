@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resources records the Azure resources CAPZ creates in the status of an object.
+package resources
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Setter interface defines methods that an object should implement in order to
+// use the resources package for recording Azure resources.
+type Setter interface {
+	client.Object
+
+	// GetResourceStatuses returns the list of Azure resources recorded for an object.
+	GetResourceStatuses() infrav1.ResourceStatuses
+
+	// SetResourceStatuses sets the list of Azure resources recorded for an object.
+	SetResourceStatuses(infrav1.ResourceStatuses)
+}
+
+// Set records the Azure resource with the given ID.
+//
+// NOTE: The last operation is Create the first time a resource is recorded, and Update afterwards.
+func Set(to Setter, id, resourceType, service string) {
+	if to == nil || id == "" {
+		return
+	}
+	if resourceType == "" {
+		resourceType = TypeFromID(id)
+	}
+
+	status := infrav1.ResourceStatus{
+		ID:                id,
+		Type:              resourceType,
+		ServiceName:       service,
+		LastOperation:     infrav1.ResourceOperationCreate,
+		LastOperationTime: &metav1.Time{Time: metav1.Now().UTC()},
+	}
+
+	// Check if the resource is already recorded, and update it if it is.
+	resources := to.GetResourceStatuses()
+	exists := false
+	for i, r := range resources {
+		if strings.EqualFold(r.ID, id) {
+			exists = true
+			status.ID = r.ID
+			status.LastOperation = infrav1.ResourceOperationUpdate
+			resources[i] = status
+			break
+		}
+	}
+
+	// If the resource is not recorded, add it.
+	if !exists {
+		resources = append(resources, status)
+	}
+
+	to.SetResourceStatuses(resources)
+}
+
+// Delete removes the Azure resource with the given name managed by the given service.
+func Delete(to Setter, name, service string) {
+	if to == nil || name == "" || service == "" {
+		return
+	}
+
+	resources := to.GetResourceStatuses()
+	for i, r := range resources {
+		if r.ServiceName == service && strings.EqualFold(NameFromID(r.ID), name) {
+			resources = append(resources[:i], resources[i+1:]...)
+			break
+		}
+	}
+
+	to.SetResourceStatuses(resources)
+}
+
+// NameFromID returns the name of the resource with the given ARM ID.
+func NameFromID(id string) string {
+	resourceID, err := azure.ParseResourceID(id)
+	if err != nil {
+		return ""
+	}
+	return resourceID.Name
+}
+
+// TypeFromID returns the ARM resource type of the resource with the given ARM ID,
+// such as Microsoft.Network/virtualNetworks/subnets.
+func TypeFromID(id string) string {
+	resourceID, err := azure.ParseResourceID(id)
+	if err != nil {
+		return ""
+	}
+	return resourceID.ResourceType.String()
+}
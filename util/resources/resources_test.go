@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+const (
+	vnetID   = "/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.Network/virtualNetworks/test-vnet"
+	subnetID = "/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.Network/virtualNetworks/test-vnet/subnets/test-subnet"
+)
+
+func TestSet(t *testing.T) {
+	g := NewWithT(t)
+
+	to := setterWithResources(nil)
+	Set(to, vnetID, "", "virtualnetworks")
+	Set(to, subnetID, "Microsoft.Network/virtualNetworks/subnets", "subnets")
+
+	resources := to.GetResourceStatuses()
+	g.Expect(resources).To(HaveLen(2))
+	g.Expect(resources[0].ID).To(Equal(vnetID))
+	g.Expect(resources[0].Type).To(Equal("Microsoft.Network/virtualNetworks"))
+	g.Expect(resources[0].ServiceName).To(Equal("virtualnetworks"))
+	g.Expect(resources[0].LastOperation).To(Equal(infrav1.ResourceOperationCreate))
+	g.Expect(resources[0].LastOperationTime).NotTo(BeNil())
+	g.Expect(resources[1].ID).To(Equal(subnetID))
+	g.Expect(resources[1].LastOperation).To(Equal(infrav1.ResourceOperationCreate))
+
+	// Recording a resource again updates it instead of duplicating it.
+	Set(to, vnetID, "Microsoft.Network/virtualNetworks", "virtualnetworks")
+
+	resources = to.GetResourceStatuses()
+	g.Expect(resources).To(HaveLen(2))
+	g.Expect(resources[0].ID).To(Equal(vnetID))
+	g.Expect(resources[0].LastOperation).To(Equal(infrav1.ResourceOperationUpdate))
+	g.Expect(resources[1].LastOperation).To(Equal(infrav1.ResourceOperationCreate))
+}
+
+func TestDelete(t *testing.T) {
+	vnet := infrav1.ResourceStatus{ID: vnetID, ServiceName: "virtualnetworks"}
+	subnet := infrav1.ResourceStatus{ID: subnetID, ServiceName: "subnets"}
+
+	tests := []struct {
+		name     string
+		to       Setter
+		resource string
+		service  string
+		want     infrav1.ResourceStatuses
+	}{
+		{
+			name:     "Delete removes a resource",
+			to:       setterWithResources(infrav1.ResourceStatuses{vnet, subnet}),
+			resource: "test-subnet",
+			service:  "subnets",
+			want:     infrav1.ResourceStatuses{vnet},
+		},
+		{
+			name:     "Delete does nothing if the resource is managed by another service",
+			to:       setterWithResources(infrav1.ResourceStatuses{vnet, subnet}),
+			resource: "test-vnet",
+			service:  "subnets",
+			want:     infrav1.ResourceStatuses{vnet, subnet},
+		},
+		{
+			name:     "Delete does nothing if the resource does not exist",
+			to:       setterWithResources(infrav1.ResourceStatuses{vnet}),
+			resource: "test-subnet",
+			service:  "subnets",
+			want:     infrav1.ResourceStatuses{vnet},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			Delete(tt.to, tt.resource, tt.service)
+
+			g.Expect(tt.to.GetResourceStatuses()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestTypeFromID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{id: vnetID, want: "Microsoft.Network/virtualNetworks"},
+		{id: subnetID, want: "Microsoft.Network/virtualNetworks/subnets"},
+		{
+			id:   "/subscriptions/123/resourceGroups/test-rg/providers/Microsoft.Compute/virtualMachines/test-vm/providers/Microsoft.Authorization/roleAssignments/456",
+			want: "Microsoft.Authorization/roleAssignments",
+		},
+		{id: "/subscriptions/123/resourceGroups/test-rg", want: "Microsoft.Resources/resourceGroups"},
+		{id: "not-an-id", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(TypeFromID(tt.id)).To(Equal(tt.want))
+		})
+	}
+}
+
+func setterWithResources(resources infrav1.ResourceStatuses) Setter {
+	obj := &infrav1.AzureCluster{}
+	obj.SetResourceStatuses(resources)
+	return obj
+}
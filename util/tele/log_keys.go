@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tele
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// The keys of the structured log fields shared by the controllers and the Azure services, so that log pipelines can
+// index and alert on the same fields whatever the service logging.
+const (
+	// LogKeyClusterName is the name of the Cluster API cluster being reconciled.
+	LogKeyClusterName = "clusterName"
+	// LogKeyService is the name of the Azure service, e.g. "virtualmachine".
+	LogKeyService = "service"
+	// LogKeyResource is the name of the Azure resource.
+	LogKeyResource = "resource"
+	// LogKeyResourceGroup is the resource group of the Azure resource.
+	LogKeyResourceGroup = "resourceGroup"
+	// LogKeyResourceID is the resource ID of the Azure resource.
+	LogKeyResourceID = "resourceID"
+	// LogKeyOperation is the HTTP method of the operation on the Azure resource, e.g. "PUT" or "DELETE".
+	LogKeyOperation = "operation"
+	// LogKeyAttempt is the number of times a long-running operation has been checked, starting at 1.
+	LogKeyAttempt = "attempt"
+)
+
+// WithLogValues returns a copy of the context whose logger has the given key/value pairs, so that the loggers returned
+// by StartSpanWithLogger for this context, e.g. by the Azure services, log them too.
+func WithLogValues(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	return log.IntoContext(ctx, log.FromContext(ctx).WithValues(keysAndValues...))
+}
@@ -125,6 +125,9 @@ func (h *validatingHandler) Handle(ctx context.Context, req admission.Request) a
 		}
 	}
 
+	if warner, ok := h.validator.(Warner); ok {
+		return admission.Allowed("").WithWarnings(warnings(h.decoder, warner, req)...)
+	}
 	return admission.Allowed("")
 }
 
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Warner defines functions returning admission warnings for an operation, e.g. for deprecated fields or risky
+// configurations. Unlike validation errors, warnings don't reject the operation, they are shown to the user,
+// e.g. by kubectl.
+type Warner interface {
+	runtime.Object
+	WarningsOnCreate() []string
+	WarningsOnUpdate(old runtime.Object) []string
+}
+
+// RegisterValidatingWebhookWithWarnings registers the validating webhook of the provided type with support for
+// admission warnings. It must be called before the webhook of the type is built with ctrl.NewWebhookManagedBy,
+// which then skips registering its own validating webhook for the type.
+func RegisterValidatingWebhookWithWarnings(mgr ctrl.Manager, validator interface {
+	admission.Validator
+	Warner
+}) error {
+	gvk, err := apiutil.GVKForObject(validator, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	path := "/validate-" + strings.ReplaceAll(gvk.Group, ".", "-") + "-" + gvk.Version + "-" + strings.ToLower(gvk.Kind)
+	mgr.GetWebhookServer().Register(path, NewValidatingWebhookWithWarnings(validator))
+	return nil
+}
+
+// NewValidatingWebhookWithWarnings creates a new Webhook for validating the provided type, which returns the
+// admission warnings of the type along with the allowed operations.
+func NewValidatingWebhookWithWarnings(validator interface {
+	admission.Validator
+	Warner
+}) *admission.Webhook {
+	return &admission.Webhook{
+		Handler: &warningHandler{
+			Handler: admission.ValidatingWebhookFor(validator).Handler,
+			warner:  validator,
+		},
+	}
+}
+
+type warningHandler struct {
+	admission.Handler
+	warner  Warner
+	decoder *admission.Decoder
+}
+
+var _ admission.DecoderInjector = &warningHandler{}
+
+// InjectDecoder injects the decoder into a warningHandler and the handler it wraps.
+func (h *warningHandler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	_, err := admission.InjectDecoderInto(d, h.Handler)
+	return err
+}
+
+// Handle handles admission requests, adding the warnings of the object to the allowed ones.
+func (h *warningHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	resp := h.Handler.Handle(ctx, req)
+	if !resp.Allowed {
+		return resp
+	}
+	return resp.WithWarnings(warnings(h.decoder, h.warner, req)...)
+}
+
+// warnings returns the admission warnings of the object of a create or update request.
+func warnings(decoder *admission.Decoder, warner Warner, req admission.Request) []string {
+	obj := warner.DeepCopyObject().(Warner)
+	switch req.Operation {
+	case admissionv1.Create:
+		if err := decoder.DecodeRaw(req.Object, obj); err != nil {
+			return nil
+		}
+		return obj.WarningsOnCreate()
+	case admissionv1.Update:
+		oldObj := obj.DeepCopyObject()
+		if err := decoder.DecodeRaw(req.Object, obj); err != nil {
+			return nil
+		}
+		if err := decoder.DecodeRaw(req.OldObject, oldObj); err != nil {
+			return nil
+		}
+		return obj.WarningsOnUpdate(oldObj)
+	default:
+		return nil
+	}
+}
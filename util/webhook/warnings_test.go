@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestValidatingWebhookWithWarnings(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+
+	withoutIdentity := &infrav1.AzureCluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: infrav1.GroupVersion.String(), Kind: "AzureCluster"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+		Spec: infrav1.AzureClusterSpec{
+			ResourceGroup: "test-rg",
+			AzureClusterClassSpec: infrav1.AzureClusterClassSpec{
+				Location: "westus2",
+			},
+		},
+	}
+	// The mutating webhook defaults the object before it is validated.
+	withoutIdentity.Default()
+	withIdentity := withoutIdentity.DeepCopy()
+	withIdentity.Spec.IdentityRef = &corev1.ObjectReference{Kind: "AzureClusterIdentity", Name: "test-identity"}
+	withIdentity.Spec.SubscriptionID = "00000000-0000-0000-0000-000000000000"
+	invalid := withoutIdentity.DeepCopy()
+	invalid.Name = "invalid_name"
+
+	tests := []struct {
+		name         string
+		obj          *infrav1.AzureCluster
+		allowed      bool
+		wantWarnings []string
+	}{
+		{
+			name:    "allowed without warnings",
+			obj:     withIdentity,
+			allowed: true,
+		},
+		{
+			name:    "allowed with warnings",
+			obj:     withoutIdentity,
+			allowed: true,
+			wantWarnings: []string{
+				"spec.identityRef is not set: the cluster is reconciled with the credentials of the controller manager, which is deprecated and will be removed in a future release, reference an AzureClusterIdentity instead",
+			},
+		},
+		{
+			name:    "denied without warnings",
+			obj:     invalid,
+			allowed: false,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			wh := webhook.NewValidatingWebhookWithWarnings(&infrav1.AzureCluster{})
+			g.Expect(wh.InjectScheme(scheme)).To(Succeed())
+
+			raw, err := json.Marshal(tc.obj)
+			g.Expect(err).NotTo(HaveOccurred())
+			resp := wh.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+
+			g.Expect(resp.Allowed).To(Equal(tc.allowed))
+			g.Expect(resp.Warnings).To(Equal(tc.wantWarnings))
+		})
+	}
+}
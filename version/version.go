@@ -19,10 +19,12 @@ package version
 import (
 	"fmt"
 	"runtime"
-
-	azuresdkversion "github.com/Azure/azure-sdk-for-go/version"
+	"runtime/debug"
 )
 
+// azureSDKModule is the path of the core module of the Azure SDK for Go.
+const azureSDKModule = "github.com/Azure/azure-sdk-for-go/sdk/azcore"
+
 var (
 	gitMajor     string // major version, always numeric
 	gitMinor     string // minor version, numeric possibly followed by "+"
@@ -58,8 +60,22 @@ func Get() Info {
 		GoVersion:       runtime.Version(),
 		Compiler:        runtime.Compiler,
 		Platform:        fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
-		AzureSdkVersion: azuresdkversion.Number,
+		AzureSdkVersion: azureSDKVersion(),
+	}
+}
+
+// azureSDKVersion returns the version of the core module of the Azure SDK for Go the binary is built with, if known.
+func azureSDKVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == azureSDKModule {
+			return dep.Version
+		}
 	}
+	return ""
 }
 
 // String returns version info in a human-friendly format.